@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/bundle"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/plugin"
+	"github.com/mabhi256/jdiag/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOut     string
+	bundleScrub   bool
+	bundlePlugins string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [gc-log]",
+	Short: "Package a GC log, its analysis and charts into one zip for a support ticket",
+	Long: `Bundle packs a GC log with what's needed to read it into one zip, to attach
+to a support ticket or vendor escalation:
+
+  gc.log            The log, decompressed
+  analysis.json     Summary, pauses, trends and issues found, as JSON
+  events.csv        One row per collection
+  environment.json  JVM version, collector, heap, time span, and the log's size
+                    and checksum
+  charts/*.svg      Each tab of the 'jdiag gc' dashboard as an image
+  README.txt        What's in the bundle
+
+--scrub replaces hostnames, file paths, IP and email addresses, the Java
+command and system property values in the log with placeholders, and drops the
+log's name. Timings and sizes are kept, so the scrubbed log analyzes the same.
+It's a best effort: look the log over before sending it anywhere sensitive.
+
+Analyzer plugins given with --plugins run as for 'jdiag gc analyze', so their
+issues are in the bundled analysis too.`,
+	Example: `  jdiag bundle gc.log                     # jdiag-bundle-<time>.zip here
+  jdiag bundle gc.log --scrub -o case-1234.zip
+  jdiag bundle gc.log.1.gz --scrub`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".log", ".log.gz"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(args[0]); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", args[0])
+		}
+		if strings.HasSuffix(args[0], ".jfr") {
+			return fmt.Errorf("bundle packages a GC log; convert the recording first with 'jdiag convert %s --to gclog -o gc.log'", args[0])
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+		events, analysis, _, err := parseGCFile(filename, false)
+		if err != nil {
+			return err
+		}
+		issues := gc.GetRecommendations(analysis)
+		if bundlePlugins != "" {
+			input := plugin.NewGCInput(filename, events, analysis, issues)
+			for _, err := range plugin.RunAll(bundlePlugins, input, issues, plugin.DefaultTimeout) {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+
+		out := bundleOut
+		if out == "" {
+			out = fmt.Sprintf("jdiag-bundle-%s.zip", time.Now().Format("20060102-150405"))
+		}
+		result, err := bundle.Write(out, filename, events, analysis, issues, bundle.Options{Scrub: bundleScrub, Version: version})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Wrote %s\n", result.Path)
+		for _, name := range result.Files {
+			fmt.Println(utils.MutedStyle.Render("   " + name))
+		}
+		if bundleScrub {
+			fmt.Printf("🧹 Scrubbed %s from the log\n", result.Scrubbed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCmd.Flags().StringVarP(&bundleOut, "out", "o", "", "Zip to write (default: jdiag-bundle-<timestamp>.zip)")
+	bundleCmd.Flags().BoolVar(&bundleScrub, "scrub", false, "Replace hostnames, paths, addresses and system properties in the log")
+	bundleCmd.Flags().StringVar(&bundlePlugins, "plugins", "", "Run the analyzer plugins in this directory, e.g. ~/.jdiag/plugins, and add their issues to the analysis")
+}
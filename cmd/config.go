@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// targetAnnotation marks commands whose optional PID|HOST:PORT argument can come from the config's "target" key
+const targetAnnotation = "jdiag.config.target"
+
+var (
+	configPath    string
+	configProfile string
+
+	// configTarget is the "target" setting of the running command, used when no argument is given
+	configTarget string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config [COMMAND...]",
+	Short: "Show the config file, its profiles and the settings a command would get",
+	Long: `Show the settings jdiag reads from ~/.jdiag.yaml (or --config).
+
+The file sets flag defaults per command, keyed by the command path, and named
+profiles that override them. Flags given on the command line always win.
+
+  defaults:
+    global:                      # Every command with these flags
+      notify: slack://hooks.slack.com/services/T0/B0/XXX
+    watch:
+      interval: 2000
+    gc analyze:                  # Or nested: gc: { analyze: { ... } }
+      output: cli-more
+  profiles:
+    prod-payments:
+      watch:
+        target: payments-01:9010 # Used when no PID|HOST:PORT is given
+        interval: 500
+  keymap:
+    gc:                          # Rebind GC TUI actions; press ? in it for the list
+      down: [j, down, ctrl+n]
+      top: [g g, home]           # Two keys pressed one after the other
+      cursor-prev: comma         # Or space, for keys YAML can't take as is
+
+Examples:
+  jdiag config                             # File location and profiles
+  jdiag config watch --profile prod-payments  # What jdiag watch would use`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("⚙️  Config: %s\n", cfg.Path)
+		names := cfg.ProfileNames()
+		if len(names) == 0 {
+			fmt.Println("   Profiles: none")
+		} else {
+			fmt.Printf("   Profiles: %s\n", strings.Join(names, ", "))
+		}
+		if len(args) == 0 {
+			return nil
+		}
+
+		target, _, err := cmd.Root().Find(args)
+		if err != nil || target == cmd.Root() {
+			return fmt.Errorf("unknown command '%s'", strings.Join(args, " "))
+		}
+		command := commandKey(target)
+		global, specific, err := cfg.Settings(command, configProfile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n📋 jdiag %s", command)
+		if configProfile != "" {
+			fmt.Printf(" (profile %s)", configProfile)
+		}
+		fmt.Println()
+		settings := mergedSettings(target, global, specific)
+		if len(settings) == 0 {
+			fmt.Println("   No settings")
+		}
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "target" {
+				fmt.Printf("   target: %s\n", settings[key])
+			} else {
+				fmt.Printf("   --%s=%s\n", key, settings[key])
+			}
+		}
+		return nil
+	},
+}
+
+func loadConfig() (*config.Config, error) {
+	if configPath != "" {
+		return config.Load(configPath, true)
+	}
+	path := configFilePath()
+	if path == "" {
+		return &config.Config{Defaults: config.Sections{}, Profiles: map[string]config.Sections{}, Keymap: config.Sections{}}, nil
+	}
+	return config.Load(path, false)
+}
+
+// configFilePath is the file loadConfig reads, or "" when there's none to read
+func configFilePath() string {
+	if configPath != "" {
+		return configPath
+	}
+	return config.DefaultPath()
+}
+
+func addRecentTarget(target string) error {
+	path := config.RecentPath(configFilePath())
+	if path == "" {
+		return nil
+	}
+	return config.AddRecentTarget(path, target)
+}
+
+// completeTargets offers the HOST:PORT targets used recently, then the ones set in the config file
+func completeTargets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	seen := map[string]bool{}
+
+	recent, _ := config.RecentTargets(config.RecentPath(configFilePath()))
+	for _, target := range recent {
+		seen[target] = true
+		completions = append(completions, target+"\trecent")
+	}
+
+	if cfg, err := loadConfig(); err == nil {
+		configured := cfg.Targets(commandKey(cmd))
+		targets := make([]string, 0, len(configured))
+		for target := range configured {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			// PIDs change between runs, so only endpoints are worth offering
+			if _, _, err := parseHostPort(target); err != nil || seen[target] {
+				continue
+			}
+			completions = append(completions, target+"\t"+configured[target])
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// applyConfig sets the flags the user didn't pass from the config file and profile
+func applyConfig(cmd *cobra.Command) error {
+	if cmd == configCmd {
+		return nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	command := commandKey(cmd)
+	global, specific, err := cfg.Settings(command, configProfile)
+	if err != nil {
+		return err
+	}
+
+	for key := range specific {
+		if key == "target" {
+			if cmd.Annotations[targetAnnotation] == "" {
+				return fmt.Errorf("%s: jdiag %s doesn't take a default target", cfg.Path, command)
+			}
+			continue
+		}
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil {
+			return fmt.Errorf("%s: jdiag %s has no --%s flag", cfg.Path, command, key)
+		}
+		if isConfigFlag(flag) {
+			return fmt.Errorf("%s: --%s can't be set from the config file", cfg.Path, key)
+		}
+	}
+
+	for key, value := range mergedSettings(cmd, global, specific) {
+		if key == "target" {
+			configTarget = value
+			continue
+		}
+		flag := cmd.Flags().Lookup(key)
+		if flag.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(key, value); err != nil {
+			return fmt.Errorf("%s: invalid value '%s' for jdiag %s --%s: %w", cfg.Path, value, command, key, err)
+		}
+	}
+	return nil
+}
+
+// mergedSettings keeps the global values the command has a flag for, overridden by its own section
+func mergedSettings(cmd *cobra.Command, global, specific map[string]string) map[string]string {
+	settings := map[string]string{}
+	for key, value := range global {
+		if flag := cmd.Flags().Lookup(key); flag != nil && !isConfigFlag(flag) {
+			settings[key] = value
+		}
+	}
+	for key, value := range specific {
+		settings[key] = value
+	}
+	return settings
+}
+
+func isConfigFlag(flag *pflag.Flag) bool {
+	return flag.Name == "config" || flag.Name == "profile"
+}
+
+// commandKey is the command path after "jdiag", e.g. "gc analyze"
+func commandKey(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default: ~/.jdiag.yaml)")
+	rootCmd.PersistentFlags().StringVar(&configProfile, "profile", "", "Named profile from the config file to apply")
+}
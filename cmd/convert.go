@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/convert"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/watch"
+	"github.com/mabhi256/jdiag/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertTo        string
+	convertOut       string
+	convertNamespace string
+	convertLabels    map[string]string
+)
+
+// Formats a GC log or recording converts to; a watch export only converts to remote-write
+var convertGCFormats = []string{"csv", "ndjson", "gclog"}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "Convert GC logs, JFR recordings and watch sessions to other formats",
+	Long: `Convert JVM telemetry between formats.
+
+  GC log or JFR recording --to csv       One row per collection
+  GC log or JFR recording --to ndjson    One JSON object per line and collection
+  JFR recording           --to gclog     A unified logging GC log (-Xlog:gc*), for
+                                          tools that only read logs
+  Watch session           --to remote-write
+                                          Prometheus remote-write requests, one
+                                          snappy-compressed protobuf file per chunk
+
+A watch session is the JSON saved with 'e' in 'jdiag watch'. Text formats go
+to stdout unless -o names a file; remote-write needs -o, the directory the
+chunk files go to.
+
+Remote-write metrics are named jdiag_<series>_<value> and labelled with
+job="jdiag" and the watched target as instance. --namespace replaces the
+jdiag prefix and --label adds static labels to every series, or replaces
+job and instance, so dashboards can aggregate a fleet's sessions. Both can
+be set per profile in ~/.jdiag.yaml:
+
+  convert:
+    label: [service=payments, env=prod]`,
+	Example: `  jdiag convert gc.log --to csv -o gc.csv
+  jdiag convert recording.jfr --to gclog -o gc.log
+  jdiag convert gc.log.gz --to ndjson | jq 'select(.durationMs > 200)'
+  jdiag convert jdiag_watch_20250101_120000.json --to remote-write -o chunks/
+  jdiag convert session.json --to remote-write -o chunks/ --label service=payments --label env=prod`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".log", ".log.gz", ".jfr", ".json"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(args[0]); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", args[0])
+		}
+		if !slices.Contains(convertGCFormats, convertTo) && convertTo != "remote-write" {
+			return fmt.Errorf("invalid format: %s. Valid options: %s or remote-write", convertTo, strings.Join(convertGCFormats, ", "))
+		}
+		if convertTo == "remote-write" && convertOut == "" {
+			return fmt.Errorf("--to remote-write needs -o, the directory to write the chunks to")
+		}
+		return remoteWriteOptions().Validate()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+		if strings.HasSuffix(filename, ".json") {
+			return convertWatchExport(filename)
+		}
+		if convertTo == "remote-write" {
+			return fmt.Errorf("remote-write converts a saved watch session (.json), not %s", filename)
+		}
+		if convertTo == "gclog" && !strings.HasSuffix(filename, ".jfr") {
+			return fmt.Errorf("--to gclog converts a JFR recording; %s is already a log", filename)
+		}
+
+		events, analysis, _, err := parseGCFile(filename, false)
+		if err != nil {
+			return err
+		}
+
+		out := io.Writer(os.Stdout)
+		if convertOut != "" {
+			file, err := os.Create(convertOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", convertOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		switch convertTo {
+		case "csv":
+			err = convert.WriteEventsCSV(out, gc.NewReportEvents(events))
+		case "ndjson":
+			err = convert.WriteEventsNDJSON(out, gc.NewReportEvents(events))
+		case "gclog":
+			var skipped int
+			skipped, err = gc.WriteLog(out, events, analysis)
+			if skipped > 0 {
+				fmt.Fprintf(os.Stderr, "⚠️  %d collections have no GC log line (ZGC or Shenandoah cycles) and were left out\n", skipped)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", convertTo, err)
+		}
+		if convertOut != "" {
+			fmt.Fprintf(os.Stderr, "✅ Wrote %d collections to %s\n", len(events), convertOut)
+		}
+		return nil
+	},
+}
+
+func convertWatchExport(filename string) error {
+	if convertTo != "remote-write" {
+		return fmt.Errorf("a saved watch session converts to remote-write, not %s", convertTo)
+	}
+	export, err := watch.LoadExport(filename)
+	if err != nil {
+		return err
+	}
+
+	paths, err := convert.WriteRemoteWrite(convertOut, export, remoteWriteOptions())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %d remote-write chunks to %s\n", len(paths), convertOut)
+	fmt.Println(utils.MutedStyle.Render("   Send each with: curl --data-binary @<chunk> -H 'Content-Encoding: snappy' " +
+		"-H 'Content-Type: application/x-protobuf' -H 'X-Prometheus-Remote-Write-Version: 0.1.0' <prometheus>/api/v1/write"))
+	return nil
+}
+
+func remoteWriteOptions() convert.RemoteWriteOptions {
+	return convert.RemoteWriteOptions{Namespace: convertNamespace, Labels: convertLabels}
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Format to convert to: csv, ndjson, gclog or remote-write")
+	convertCmd.Flags().StringVarP(&convertOut, "out", "o", "", "Output file, or directory for remote-write (default: stdout)")
+	convertCmd.Flags().StringVar(&convertNamespace, "namespace", convert.DefaultNamespace, "Remote-write metric name prefix")
+	convertCmd.Flags().StringToStringVar(&convertLabels, "label", nil, "Static remote-write label NAME=VALUE, e.g. service=payments (repeatable)")
+	convertCmd.MarkFlagRequired("to")
+	convertCmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append(slices.Clone(convertGCFormats), "remote-write"), cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/daemon"
+	"github.com/mabhi256/jdiag/internal/history"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonDirs      []string
+	daemonPattern   string
+	daemonPoll      time.Duration
+	daemonEvery     string
+	daemonThreshold int
+	daemonNotify    string
+	daemonHistory   string
+	daemonOnce      bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Analyze rotated GC logs as they appear and post a digest when health degrades",
+	Long: `Daemon watches log directories for GC logs that have been rotated away, such
+as gc.log.0 or gc.log.3.gz. The gc.log still being written and a JDK 8
+gc.log.N.current are skipped. Each log is analyzed once and its health score,
+pauses and issues are stored in the history (~/.jdiag/history.jsonl).
+
+Every day, or every week with --every week, it compares each directory's
+average score over the period just ended with the period before. Directories
+whose score dropped by --threshold points or more get a digest posted to
+--notify, with the new issues and the worst log. Each directory is one
+service, so give every JVM's logs their own.
+
+Directories are usually set in ~/.jdiag.yaml:
+
+  defaults:
+    daemon:
+      dir: /var/log/payments,/var/log/search
+      every: week
+      notify: slack://hooks.slack.com/services/T0/B0/XXX
+
+Run it under systemd or similar; Ctrl-C or SIGTERM stops it. --once makes a
+single pass and exits, for cron.`,
+	Example: `  jdiag daemon --dir /var/log/app --notify slack://hooks.slack.com/services/T0/B0/XXX
+  jdiag daemon --dir /var/log/a --dir /var/log/b --every day --threshold 10
+  jdiag daemon --dir /var/log/app --once          # From cron
+  jq 'select(.kind == "analysis")' ~/.jdiag/history.jsonl`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(daemonDirs) == 0 {
+			return fmt.Errorf("no log directories to watch: give them with --dir or in the config file")
+		}
+		for i, dir := range daemonDirs {
+			info, err := os.Stat(dir)
+			if err != nil || !info.IsDir() {
+				return fmt.Errorf("not a directory: %s", dir)
+			}
+			if daemonDirs[i], err = filepath.Abs(dir); err != nil {
+				return err
+			}
+		}
+		if _, err := filepath.Match(daemonPattern, ""); err != nil {
+			return fmt.Errorf("invalid --pattern %s: %w", daemonPattern, err)
+		}
+		if !slices.Contains(daemon.Periods, daemonEvery) {
+			return fmt.Errorf("invalid --every: %s. Valid options: %s", daemonEvery, strings.Join(daemon.Periods, ", "))
+		}
+		if daemonPoll <= 0 {
+			return fmt.Errorf("invalid --poll %s: must be above 0", daemonPoll)
+		}
+		if daemonHistory == "" {
+			return fmt.Errorf("no history file: the home directory is unknown, so give one with --history")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var notifier *notify.Notifier
+		if daemonNotify != "" {
+			var err error
+			if notifier, err = notify.NewNotifier(daemonNotify); err != nil {
+				return err
+			}
+		}
+
+		db, err := history.Open(daemonHistory)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if !daemonOnce {
+			fmt.Printf("🛰️  Watching %s for rotated logs (%s) every %s\n", strings.Join(daemonDirs, ", "), daemonPattern, daemonPoll)
+			fmt.Printf("   History: %s  |  Digest: every %s", db.Path(), daemonEvery)
+			if notifier != nil {
+				fmt.Printf(" to %s when the score drops %d+ points", notifier, daemonThreshold)
+			}
+			fmt.Println()
+		}
+
+		d := daemon.New(&daemon.Config{
+			Dirs:      daemonDirs,
+			Pattern:   daemonPattern,
+			Poll:      daemonPoll,
+			Period:    daemonEvery,
+			Threshold: daemonThreshold,
+			History:   db,
+			Notifier:  notifier,
+			Out:       os.Stdout,
+		})
+		return d.Run(ctx, daemonOnce)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringSliceVar(&daemonDirs, "dir", nil, "Directory of a JVM's GC logs (repeat or comma-separate for several)")
+	daemonCmd.Flags().StringVar(&daemonPattern, "pattern", daemon.DefaultPattern, "Glob for rotated logs in each directory")
+	daemonCmd.Flags().DurationVar(&daemonPoll, "poll", time.Minute, "How often to look for newly rotated logs")
+	daemonCmd.Flags().StringVar(&daemonEvery, "every", daemon.PeriodWeek, "Digest period: "+strings.Join(daemon.Periods, " or "))
+	daemonCmd.Flags().IntVar(&daemonThreshold, "threshold", 5, "Score points a directory must drop by to post a digest")
+	daemonCmd.Flags().StringVar(&daemonNotify, "notify", "", "Post digests to a webhook (slack://<webhook> or teams://<webhook>)")
+	daemonCmd.Flags().StringVar(&daemonHistory, "history", history.DefaultPath(), "History file the analyses are stored in")
+	daemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "Make one pass over the directories and exit")
+
+	daemonCmd.MarkFlagDirname("dir")
+	daemonCmd.RegisterFlagCompletionFunc("every", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return daemon.Periods, cobra.ShellCompDirectiveNoFileComp
+	})
+}
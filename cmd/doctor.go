@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorSamples  int
+	doctorInterval int
+	doctorSave     string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor PID",
+	Short: "One-shot triage of a running JVM for the first minutes of an incident",
+	Long: `One-shot triage of a running JVM for the first minutes of an incident.
+
+Captures, in about 15 seconds:
+- A burst of JMX samples (heap, GC time, CPU, threads, file descriptors)
+- A thread dump (jcmd Thread.print -l)
+- The JVM's flags (jcmd VM.flags -all) and heap layout (jcmd GC.heap_info)
+- The process memory map (/proc/<pid>/smaps)
+
+then runs the thread, flags and native memory analyzers on them and prints the
+findings most urgent first, each with what to do next.`,
+	Example: `  jdiag doctor 1234                     # Triage PID 1234
+  jdiag doctor 1234 --save incident-42  # Also keep the thread dump, flags and heap info
+  jdiag doctor 1234 -n 30 -i 500        # Longer, denser burst`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeJavaProcesses,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pid, err := strconv.Atoi(args[0])
+		if err != nil || pid <= 0 {
+			return fmt.Errorf("invalid PID '%s'", args[0])
+		}
+		if doctorSamples < 2 {
+			return fmt.Errorf("--samples must be at least 2 to measure rates, got %d", doctorSamples)
+		}
+		if doctorInterval < 250 {
+			return fmt.Errorf("--interval must be at least 250ms, got %d", doctorInterval)
+		}
+
+		interval := time.Duration(doctorInterval) * time.Millisecond
+		fmt.Printf("🩺 Examining PID %d: %d samples, %s apart, plus a thread dump, flags and memory map...\n",
+			pid, doctorSamples, interval)
+
+		triage := doctor.Capture(pid, doctorSamples, interval)
+		if len(triage.Samples) == 0 && triage.Threads == nil && triage.Flags == nil {
+			for _, skipped := range triage.Skipped {
+				fmt.Printf("   ⏭️  %s\n", skipped)
+			}
+			return fmt.Errorf("unable to collect anything from PID %d (is it a running JVM owned by this user?)", pid)
+		}
+		triage.Analyze()
+		triage.PrintSummary()
+
+		if doctorSave != "" {
+			files, err := triage.Save(doctorSave)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\n💾 Saved %d files to %s\n", len(files), doctorSave)
+			if triage.Threads != nil {
+				fmt.Printf("   jdiag thread %s -o tui   # Explore the thread dump\n", filepath.Join(doctorSave, "threads.txt"))
+			}
+			if triage.Flags != nil {
+				fmt.Printf("   jdiag flags %s           # Full flags audit\n", filepath.Join(doctorSave, "flags.txt"))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().IntVarP(&doctorSamples, "samples", "n", doctor.DefaultSamples, "Number of JMX samples to take")
+	doctorCmd.Flags().IntVarP(&doctorInterval, "interval", "i", int(doctor.DefaultInterval.Milliseconds()), "Time between samples in ms")
+	doctorCmd.Flags().StringVar(&doctorSave, "save", "", "Directory to save the captured thread dump, flags and heap info in")
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagsJDKVersion int
+	flagsShowAll    bool
+)
+
+var flagsCmd = &cobra.Command{
+	Use:   "flags [FILE|PID|HOST:PORT|TARGET]",
+	Short: "Audit a JVM's flags for deprecated, removed and conflicting options",
+	Long: `Audit a JVM's flags for deprecated, removed and conflicting options.
+
+The report includes:
+- Flags removed or ignored in the JDK version (the JVM may refuse to start)
+- Deprecated flags and their replacements
+- Conflicting combinations, e.g. two collectors or -Xms above -Xmx
+- Experimental and diagnostic flags missing their unlock option
+- Non-default flags with what they do
+
+Input can be:
+- java -XX:+PrintFlagsFinal -version output (or jcmd <pid> VM.flags -all)
+- jcmd <pid> VM.command_line or VM.flags output, or a plain java command line
+- A JFR recording (.jfr), using the arguments it recorded
+- A running JVM's PID, JMX HOST:PORT or saved target, using its input arguments`,
+	Example: `  java -XX:+PrintFlagsFinal -version > flags.txt && jdiag flags flags.txt
+  jdiag flags 1234                      # Audit a running JVM
+  jdiag flags args.txt --jdk 21         # Check a command line against JDK 21
+  jdiag flags flags.txt --all           # Include flags set by ergonomics`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		set, err := loadFlags(args[0])
+		if err != nil {
+			return err
+		}
+
+		if flagsJDKVersion > 0 {
+			set.JDKVersion, set.JDKGuessed = flagsJDKVersion, false
+		}
+		flags.Audit(set, flagsShowAll).PrintReport()
+		return nil
+	},
+}
+
+func loadFlags(arg string) (*flags.FlagSet, error) {
+	if _, err := os.Stat(arg); err == nil {
+		if strings.EqualFold(filepath.Ext(arg), ".jfr") {
+			recording, err := jfr.ParseFile(arg)
+			if err != nil {
+				return nil, err
+			}
+			if recording.JVM.Arguments == "" {
+				return nil, fmt.Errorf("recording has no jdk.JVMInformation event with JVM arguments")
+			}
+			return flags.FromArguments(flags.SourceJFR, arg, flags.SplitArguments(recording.JVM.Arguments), recording.JVM.Version), nil
+		}
+		return flags.ParseFile(arg)
+	}
+
+	config, err := jmxConfig(arg)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a file: %w", arg, err)
+	}
+
+	runtime, err := jmx.QueryRuntime(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read JVM arguments from %s: %w", config, err)
+	}
+	version := runtime.SpecVersion
+	if version == "" {
+		version = runtime.JavaVersion
+	}
+	return flags.FromArguments(flags.SourceJMX, config.String(), runtime.InputArguments, version), nil
+}
+
+func init() {
+	rootCmd.AddCommand(flagsCmd)
+
+	flagsCmd.Flags().IntVar(&flagsJDKVersion, "jdk", 0, "JDK feature release to check against, e.g. 17 (default: detected)")
+	flagsCmd.Flags().BoolVarP(&flagsShowAll, "all", "a", false, "Also list flags set by JVM ergonomics")
+}
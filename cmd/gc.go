@@ -1,22 +1,51 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/mabhi256/jdiag/internal/gc"
 	"github.com/mabhi256/jdiag/internal/gc/html"
 	"github.com/mabhi256/jdiag/internal/gc/tui"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/internal/latency"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/internal/plugin"
 	"github.com/mabhi256/jdiag/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
-	output string
+	output     string
+	gcNotify   string
+	gcNotifier *notify.Notifier
+	gcTemplate string
+	gcTmpl     *template.Template
+	gcWindow   string
+	gcSpan     time.Duration
+	gcStrict   bool
+	gcNoCache  bool
+	gcPlugins  string
+
+	latencyAtStart bool
+	latencySpike   time.Duration
+
+	generatePattern     string
+	generateDuration    time.Duration
+	generateHeap        string
+	generateAllocRate   float64
+	generateLeakRate    float64
+	generateEvacFailure float64
+	generateSeed        uint64
+	generateOut         string
 )
 
 var gcCmd = &cobra.Command{
@@ -29,11 +58,14 @@ func isHtmlFile() bool {
 }
 
 var gcAnalyzeCmd = &cobra.Command{
-	Use: "analyze [gc-log-file]",
-	Short: `Analyze a Java GC log file.
+	Use:     "analyze [gc-log-file] [candidate-gc-log-file]",
+	Aliases: []string{"report"},
+	Short:   "Analyze a Java GC log file",
+	Long: `Analyze a Java GC log file.
 
 This command parses GC log files and provides detailed analysis
 including pause times, throughput metrics, heap utilization, and tuning recommendations.
+JFR recordings (.jfr) are analyzed from their garbage collection events.
 
 Output Formats:
   cli       Basic summary with key metrics (default)
@@ -42,14 +74,32 @@ Output Formats:
   html      Generate HTML report and open in browser
   file.html Save HTML report to specific file
 
-Examples:
-  jdiag gc analyze app.log					# Basic analysis with summary output
+Given a second log with -o tui, the two are shown side by side: the first as
+the baseline and the second as the candidate, with the change in each metric.
+
+With --plugins, the executables in that directory (conventionally
+~/.jdiag/plugins) are run after the analysis: each reads the report and events
+as JSON on stdin and writes {"issues": [...]} to stdout, in the report's issue
+format. Their issues are added to the built-in ones in every output format.
+None run unless asked for.
+
+--template renders a Go text/template instead, with .File, .Report (the JSON
+report fields) and .Events in scope plus the helpers join, upper, lower,
+bytes, ms, pct and csv.`,
+	Example: `  jdiag gc analyze app.log					# Basic analysis with summary output
   jdiag gc analyze app.log -o cli-more		# Detailed command-line output with recommendations
   jdiag gc analyze app.log -o tui			# Interactive terminal interface
+  jdiag gc analyze app.log -o tui --window 1h	# Open the trends on the last hour of the log
+  jdiag gc analyze before.log after.log -o tui	# Compare a baseline and a candidate log
   jdiag gc analyze app.log -o html			# Generate HTML report
-  jdiag gc analyze app.log -o report.html	# Save HTML report to specific file`,
-	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".log"}, true),
+  jdiag gc analyze app.log -o report.html	# Save HTML report to specific file
+  jdiag gc analyze recording.jfr			# Analyze the collections in a JFR recording
+  jdiag gc analyze gc.log.1.gz			# Rotated logs compressed with gzip
+  jdiag gc analyze app.log --strict		# Fail on malformed lines instead of skipping them
+  jdiag gc analyze app.log --notify slack://hooks.slack.com/services/T0/B0/XXX	# Post a summary to Slack
+  jdiag gc report app.log --template wiki.tmpl	# Render a custom format`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".log", ".log.gz", ".jfr"}, true),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		validFormats := []string{"cli", "cli-more", "tui", "html"}
 
@@ -57,32 +107,84 @@ Examples:
 			return fmt.Errorf("invalid output format: %s. Valid options: %v or *.html", output, validFormats)
 		}
 
-		// Check if file exists
-		logFile := args[0]
-		if _, err := os.Stat(logFile); os.IsNotExist(err) {
-			return fmt.Errorf("file does not exist: %s", logFile)
+		for _, logFile := range args {
+			if _, err := os.Stat(logFile); os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", logFile)
+			}
+		}
+		if len(args) == 2 && (output != "tui" || gcTemplate != "" || gcNotify != "") {
+			return fmt.Errorf("comparing two logs is only supported with -o tui")
+		}
+
+		if gcNotify != "" {
+			var err error
+			if gcNotifier, err = notify.NewNotifier(gcNotify); err != nil {
+				return err
+			}
+		}
+
+		if gcWindow != "" {
+			var err error
+			if gcSpan, err = tui.ParseTrendWindow(gcWindow); err != nil {
+				return err
+			}
+		}
+
+		if gcTemplate != "" {
+			var err error
+			if gcTmpl, err = gc.ParseTemplateFile(gcTemplate); err != nil {
+				return err
+			}
 		}
 
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		parser := gc.NewParser()
-		events, analysis, err := parser.ParseFile(args[0])
-		gc.AnalyzeGCLogs(events, analysis)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 {
+			return compareGCLogs(args[0], args[1])
+		}
+
+		events, analysis, recording, err := parseGCFile(args[0], output == "tui")
 		if err != nil {
-			fmt.Printf("Error parsing GC log: %v\n", err)
-			return
+			return err
 		}
 		recommendations := gc.GetRecommendations(analysis)
+		if gcPlugins != "" {
+			input := plugin.NewGCInput(args[0], events, analysis, recommendations)
+			for _, err := range plugin.RunAll(gcPlugins, input, recommendations, plugin.DefaultTimeout) {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+
+		if gcNotifier != nil {
+			sendSummary(gcNotifier, notify.NewGCSummary(args[0], events, analysis, recommendations))
+		}
 
 		switch {
+		case gcTmpl != nil:
+			data := gc.NewTemplateData(args[0], events, analysis, recommendations)
+			if err := data.Render(gcTmpl, os.Stdout); err != nil {
+				return fmt.Errorf("failed to render template: %w", err)
+			}
 		case output == "cli":
+			analysis.PrintParseWarnings(3)
 			analysis.PrintSummary()
 		case output == "cli-more":
+			analysis.PrintParseWarnings(10)
 			analysis.PrintDetailed()
 			recommendations.Print()
+			if recording != nil {
+				recording.AllocationProfile().PrintHotspots(5)
+			}
 		case output == "tui":
-			tui.StartTUI(events, analysis, recommendations)
+			bookmarks, err := gc.LoadBookmarks(gc.BookmarksPath(args[0]))
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+			if err := applyGCKeymap(); err != nil {
+				return err
+			}
+			tui.StartTUI(events, analysis, recommendations, bookmarks, gcSpan)
 		case output == "html" || isHtmlFile():
 			// Generate HTML report and return absolute path of the output
 			var absPath string
@@ -93,8 +195,7 @@ Examples:
 				absPath, err = html.GenerateHTMLReport(events, analysis, recommendations, "")
 			}
 			if err != nil {
-				fmt.Printf("Error generating HTML report: %v\n", err)
-				return
+				return fmt.Errorf("failed to generate HTML report: %w", err)
 			}
 
 			fmt.Printf("HTML report generated: %s\n", makeClickableLink(absPath))
@@ -106,22 +207,258 @@ Examples:
 		default:
 			analysis.PrintSummary()
 		}
+		return nil
+	},
+}
+
+var gcLatencyCmd = &cobra.Command{
+	Use:   "latency [gc-log-file] [latency-csv]",
+	Short: "Correlate request latency spikes with GC pauses",
+	Long: `Measure how much request latency is spent in GC pauses.
+
+The latency file is a CSV of timestamp,latency lines exported from an access log
+or load test. Timestamps are ISO 8601, access log style or epoch seconds or
+milliseconds; latencies are milliseconds or durations such as 250ms. Each request
+is checked against the pauses that overlap it, and the latency percentiles are
+recomputed with the pause time removed to show how much of the tail GC causes.
+
+With a JFR recording every safepoint counts as a pause, not just GC.`,
+	Example: `  jdiag gc latency gc.log latency.csv
+  jdiag gc latency recording.jfr latency.csv --spike 500ms
+  jdiag gc latency gc.log requests.csv --at-start`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return utils.CompleteFilesByExtension([]string{".log", ".log.gz", ".jfr"}, true)(cmd, args, toComplete)
+		}
+		return utils.CompleteFilesByExtension([]string{".csv"}, false)(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gcFile, latencyFile := args[0], args[1]
+
+		for _, file := range args {
+			if _, err := os.Stat(file); os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", file)
+			}
+		}
+
+		events, analysis, recording, err := parseGCFile(gcFile, false)
+		if err != nil {
+			return err
+		}
+
+		requests, err := latency.ParseFile(latencyFile, latencyAtStart)
+		if err != nil {
+			return err
+		}
+
+		source, pauses := "GC pauses", latency.PausesFromGC(events)
+		from, to := analysis.StartTime, analysis.EndTime
+		if recording != nil {
+			from, to = recording.StartTime, recording.EndTime
+			if len(recording.Safepoints) > 0 {
+				source, pauses = "safepoints", latency.PausesFromSafepoints(recording.Safepoints)
+			}
+		} else if len(pauses) > 0 {
+			from = pauses[0].Start
+		}
+		if to.IsZero() {
+			return fmt.Errorf("%s has no wall-clock timestamps to match requests against (log with -Xlog:gc*:file=gc.log:time,uptime)", gcFile)
+		}
+
+		correlation, err := latency.Correlate(requests, pauses, source, from, to, latencySpike)
+		if err != nil {
+			return err
+		}
+		correlation.PrintSummary()
+		return nil
 	},
 }
 
+var gcGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a synthetic G1 GC log",
+	Long: `Generate a realistic JDK 21 G1 log from a simulated heap, for tests, demos
+and checking the analysis against a log whose contents are known.
+
+Patterns:
+  healthy  Steady allocation over a flat live set
+  leak     The live set grows until the heap runs out and full GCs take over
+  bursty   Allocation spikes every 5 minutes that promote early, allocate
+           humongous objects and fail evacuation
+
+What went into the log (collections, evacuation failures, the live set) is
+printed when it is done. The same --seed and flags give the same collections.`,
+	Example: `  jdiag gc generate --pattern leak --duration 2h --out leak.log
+  jdiag gc generate --pattern bursty --heap 4g --alloc-rate 200 > bursty.log
+  jdiag gc generate --pattern healthy --duration 30m --seed 7 --out demo.log`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := gc.DefaultGeneratorConfig(generatePattern)
+		if err != nil {
+			return err
+		}
+		config.Duration = generateDuration
+		config.Seed = generateSeed
+		if config.HeapMax, err = utils.ParseMemorySize(generateHeap); err != nil {
+			return fmt.Errorf("invalid heap size: %w", err)
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("alloc-rate") {
+			config.AllocRate = generateAllocRate
+		}
+		if flags.Changed("leak-rate") {
+			config.LeakRate = generateLeakRate
+		}
+		if flags.Changed("evac-failure-rate") {
+			config.EvacFailureRate = generateEvacFailure
+		}
+
+		out, status := os.Stdout, os.Stderr
+		if generateOut != "" {
+			file, err := os.Create(generateOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", generateOut, err)
+			}
+			defer file.Close()
+			out, status = file, os.Stdout
+		}
+
+		truth, err := gc.GenerateLog(out, config)
+		if err != nil {
+			return err
+		}
+		if generateOut != "" {
+			fmt.Fprintf(status, "✅ Wrote %d collections over %s to %s\n", truth.Collections, utils.FormatDuration(config.Duration), generateOut)
+		}
+		fmt.Fprintf(status, "📋 %s\n", truth.Summary())
+		return nil
+	},
+}
+
+// applyGCKeymap rebinds the GC TUI keys from the keymap.gc section of the config
+func applyGCKeymap() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if err := tui.ApplyKeymap(cfg.Keymap["gc"]); err != nil {
+		return fmt.Errorf("invalid config %s: %w", cfg.Path, err)
+	}
+	return nil
+}
+
+// parseGCFile parses and analyzes a GC log or JFR recording behind a progress bar,
+// or behind a loading screen when a TUI opens next. Logs seen before load from the
+// cache unless --no-cache. Ctrl-C stops the parse early and keeps what was read;
+// with --strict, a log with skipped lines fails
+func parseGCFile(filename string, loadingScreen bool) ([]*gc.GCEvent, *gc.GCAnalysis, *jfr.Recording, error) {
+	var events []*gc.GCEvent
+	var analysis *gc.GCAnalysis
+	var recording *jfr.Recording
+	parse := func(ctx context.Context, progress utils.ProgressFunc) error {
+		parser := gc.NewParser()
+		parser.SetContext(ctx)
+		parser.SetProgress(progress)
+
+		var cache *gc.Cache
+		if !gcNoCache {
+			cache = gc.NewCache(gc.DefaultCacheDir())
+		}
+
+		var err error
+		events, analysis, recording, err = jfr.ParseGCEvents(filename, parser, cache)
+		return err
+	}
+
+	run := utils.RunWithProgressBar
+	if loadingScreen {
+		run = utils.RunWithLoadingScreen
+	}
+	if err := run("Parsing "+filepath.Base(filename), "events", parse); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	if analysis.Interrupted {
+		fmt.Fprintf(os.Stderr, "⚠️  Interrupted after %s lines of %s; results cover only the part read so far\n",
+			utils.FormatCount(int64(analysis.LinesRead)), filename)
+	}
+	if gcStrict && analysis.SkippedLines > 0 {
+		analysis.PrintParseWarnings(10)
+		return nil, nil, nil, fmt.Errorf("%s has %d lines the parser skipped (--strict)", filename, analysis.SkippedLines)
+	}
+	return events, analysis, recording, nil
+}
+
+// compareGCLogs opens the side-by-side TUI for a baseline and a candidate log
+func compareGCLogs(baselineFile, candidateFile string) error {
+	var sides []tui.CompareSide
+	for _, file := range []string{baselineFile, candidateFile} {
+		events, analysis, _, err := parseGCFile(file, true)
+		if err != nil {
+			return err
+		}
+		sides = append(sides, tui.CompareSide{Name: filepath.Base(file), Events: events, Analysis: analysis})
+	}
+
+	if err := applyGCKeymap(); err != nil {
+		return err
+	}
+	if err := tui.StartCompareTUI(sides[0], sides[1]); err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	return nil
+}
+
 // TODO: add compare command
 
 func init() {
 	rootCmd.AddCommand(gcCmd)
 
 	gcCmd.AddCommand(gcAnalyzeCmd)
+	gcCmd.AddCommand(gcLatencyCmd)
+	gcCmd.AddCommand(gcGenerateCmd)
+
+	gcCmd.PersistentFlags().BoolVar(&gcNoCache, "no-cache", false, "Parse the log again instead of loading the analysis cached in ~/.jdiag/cache")
 
 	gcAnalyzeCmd.Flags().StringVarP(&output, "output", "o", "cli", "Output format")
+	gcAnalyzeCmd.Flags().StringVar(&gcTemplate, "template", "", "Render the analysis with a Go text/template file instead of --output")
+	gcAnalyzeCmd.Flags().StringVar(&gcWindow, "window", "", "Open the TUI trends on a span of wall time: 15m, 1h, ... or all (default: every event)")
+	gcAnalyzeCmd.Flags().BoolVar(&gcStrict, "strict", false, "Fail when the parser skips malformed lines or unparsable timestamps")
+	gcAnalyzeCmd.Flags().StringVar(&gcPlugins, "plugins", "", "Run the analyzer plugins in this directory, e.g. ~/.jdiag/plugins, and add their issues to the report")
+	gcAnalyzeCmd.Flags().StringVar(&gcNotify, "notify", "", "Post a health summary to a webhook (slack://<webhook> or teams://<webhook>)")
 
 	// When user types: jdiag gc analyze file.log -o <TAB>
 	gcAnalyzeCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"cli", "cli-more", "tui", "html"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	gcAnalyzeCmd.RegisterFlagCompletionFunc("template", utils.CompleteFilesByExtension([]string{".tmpl", ".tpl"}, false))
+
+	gcLatencyCmd.Flags().BoolVar(&latencyAtStart, "at-start", false, "Timestamps mark when requests started (default: when they completed)")
+	gcLatencyCmd.Flags().BoolVar(&gcStrict, "strict", false, "Fail when the parser skips malformed lines or unparsable timestamps")
+	gcLatencyCmd.Flags().DurationVar(&latencySpike, "spike", 0, "Latency at or above which a request is a spike (default: the p99 latency)")
+
+	gcGenerateCmd.Flags().StringVar(&generatePattern, "pattern", gc.PatternHealthy, "Application behaviour to simulate (healthy, leak, bursty)")
+	gcGenerateCmd.Flags().DurationVar(&generateDuration, "duration", time.Hour, "JVM uptime the log covers")
+	gcGenerateCmd.Flags().StringVar(&generateHeap, "heap", "1g", "Maximum heap size (-Xmx)")
+	gcGenerateCmd.Flags().Float64Var(&generateAllocRate, "alloc-rate", 0, "Allocation rate in MB/s (default: 40, 25 between bursts)")
+	gcGenerateCmd.Flags().Float64Var(&generateLeakRate, "leak-rate", 0, "MB/min the live set grows by (default: fills the heap by the end with --pattern leak)")
+	gcGenerateCmd.Flags().Float64Var(&generateEvacFailure, "evac-failure-rate", 0, "Share of young collections that fail evacuation (default: 0.03 with --pattern bursty)")
+	gcGenerateCmd.Flags().Uint64Var(&generateSeed, "seed", 1, "Random seed")
+	gcGenerateCmd.Flags().StringVar(&generateOut, "out", "", "Output file (default: stdout)")
+	gcGenerateCmd.RegisterFlagCompletionFunc("pattern", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return gc.GeneratorPatterns, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// sendSummary posts a summary; a failed notification is reported but doesn't fail the command
+func sendSummary(notifier *notify.Notifier, summary *notify.Summary) {
+	if err := notifier.Send(summary); err != nil {
+		fmt.Printf("⚠️  Notification failed: %v\n", err)
+		return
+	}
+	fmt.Printf("📣 Posted summary to %s\n", notifier)
 }
 
 func makeClickableLink(filePath string) string {
@@ -4,24 +4,77 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/export"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
 	"github.com/mabhi256/jdiag/utils"
 	"github.com/spf13/cobra"
 )
 
+var (
+	heapWorkers int
+	heapOutput  string
+	heapDebug   string
+	heapTopN    int
+
+	heapTopPackageDepth int
+
+	heapMaxMemory     string
+	heapStructureOnly bool
+	heapReindex       bool
+
+	heapExportFormat      string
+	heapExportOut         string
+	heapExportMaxObjects  int
+	heapExportMinRetained uint64
+
+	heapGraphFormat     string
+	heapGraphOut        string
+	heapGraphObject     string
+	heapGraphSuspect    int
+	heapGraphDepth      int
+	heapGraphMaxClasses int
+)
+
 var heapCmd = &cobra.Command{
-	Use: "heap [hprof-file]",
-	Short: `Analyze heap dumps (.hprof files only)
+	Use:   "heap [hprof-file]",
+	Short: "Analyze heap dumps (.hprof or gzip-compressed .hprof.gz files)",
+	Long: `Analyze heap dumps (.hprof or gzip-compressed .hprof.gz files).
+
 The tool automatically validates the file and provides comprehensive analysis including:
 - Memory usage overview
 - Memory usage by class
 - Memory leak detection  
 - Object reference analysis
 - Dominator tree visualization
-- String deduplication analysis`,
+- String deduplication analysis
+
+Output Formats:
+  cli  - Analysis summary printed to the terminal (default)
+  tui  - Interactive explorer with histogram, dominator tree, leak suspects and object inspector
+
+--structure-only skips the contents of primitive arrays while parsing, so a dump
+holding personal data can be analyzed for leaks without any String's text being
+read: sizes, references and leak suspects are unchanged, text is never shown.
+
+A full analysis writes a sidecar index next to the dump (<dump>.idx). While
+the dump is unchanged, the cli output is then reported from the index in
+seconds, and other heap commands reuse its dominator tree; --reindex ignores it.`,
 	Args:              cobra.ExactArgs(1),
-	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof"}, true),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		validFormats := []string{"cli", "tui"}
+		if !slices.Contains(validFormats, heapOutput) {
+			return fmt.Errorf("invalid output format: %s. Valid options: %v", heapOutput, validFormats)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filename := args[0]
 
@@ -30,14 +83,415 @@ The tool automatically validates the file and provides comprehensive analysis in
 		}
 
 		// Check file extension (warning only)
-		if ext := filepath.Ext(filename); ext != ".hprof" {
+		if ext := filepath.Ext(strings.TrimSuffix(filename, ".gz")); ext != ".hprof" {
 			fmt.Printf("Warning: File extension '%s' is not '.hprof', but proceeding anyway...\n", ext)
 		}
 
-		return heap.RunHeapAnalysis(filename)
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+		config.Output = heapOutput
+
+		return heap.RunHeapAnalysis(filename, config)
+	},
+}
+
+var heapTopCmd = &cobra.Command{
+	Use:   "top [hprof-file]",
+	Short: "List the objects retaining the most memory and a heap ownership treemap by package",
+	Long: `List the biggest objects in a heap dump by retained size.
+
+Objects are the top-level dominators: each one is the only thing keeping its
+retained memory alive, so the list never counts the same bytes twice. Every
+object shows the shortest reference chain from a GC root, and the report ends
+with an ASCII treemap of heap ownership by package. --package-depth rolls
+subpackages up into subsystems, so com.foo.cache and com.foo.web both count
+as com.foo.* at depth 2.`,
+	Example: `  jdiag heap top dump.hprof
+  jdiag heap top dump.hprof -n 50
+  jdiag heap top dump.hprof --package-depth 2`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapTop(filename, config, heapTopN, heapTopPackageDepth)
+	},
+}
+
+var heapInfoCmd = &cobra.Command{
+	Use:   "info [hprof-file]",
+	Short: "Summarize a heap dump in seconds, without parsing the heap",
+	Long: `Describe a heap dump before committing to a full analysis.
+
+Only the header, the record headers and the string and class tables are read:
+dump size, timestamp, identifier size and class count come from those. Object
+and GC root counts are scaled from a sample of the heap records, and are
+exact when the sample covers all of them.`,
+	Example:           `  jdiag heap info dump.hprof`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapInfo(filename, config)
+	},
+}
+
+var heapRefsCmd = &cobra.Command{
+	Use:   "refs [hprof-file]",
+	Short: "Show how much heap is held only by soft, weak, final or phantom references",
+	Long: `Classify the reachable heap by the strongest reference that keeps each object alive.
+
+Softly and weakly reachable memory is freed by the GC under memory pressure,
+so it doesn't count towards an OutOfMemoryError. The report also groups memory
+by Reference subclass to point at soft caches and weak maps.`,
+	Example:           `  jdiag heap refs dump.hprof`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapReferences(filename, config)
 	},
 }
 
+var heapResourcesCmd = &cobra.Command{
+	Use:   "resources [hprof-file]",
+	Short: "Count file descriptors, sockets, channels and JDBC objects by open or closed state",
+	Long: `Take a census of the objects that hold operating system or database resources.
+
+FileDescriptor, file streams, sockets, socket impls, NIO channels and JDBC
+driver connections and statements are counted per class, with whether each
+is open or closed where its fields tell. Set it against the file descriptor
+count from monitoring to find who holds them. Open resources that are no
+longer reachable are held open until a Cleaner or finalizer runs, a sign of
+code that doesn't close what it opens.`,
+	Example:           `  jdiag heap resources dump.hprof`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapResources(filename, config)
+	},
+}
+
+var heapThreadLocalsCmd = &cobra.Command{
+	Use:   "threadlocals [hprof-file]",
+	Short: "Find ThreadLocal values that outlive their ThreadLocal or pin an application's class loader",
+	Long: `Read every thread's ThreadLocalMap and report the values that leak.
+
+A value is reported when its ThreadLocal has already been collected (a stale
+entry), or when it or its ThreadLocal comes from a class loader other than the
+JVM's own, such as a web app's. In a pooled container thread such a value
+keeps the loader and every class it loaded alive after the app is undeployed;
+a Tomcat loader that has already been stopped is called out.`,
+	Example:           `  jdiag heap threadlocals dump.hprof`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapThreadLocals(filename, config)
+	},
+}
+
+var heapCachesCmd = &cobra.Command{
+	Use:   "caches [hprof-file] [later-hprof-file]",
+	Short: "Find Guava, Caffeine and map-based caches, their bounds, hit rates and growth",
+	Long: `Find the caches in a heap dump and how much each one retains.
+
+Guava and Caffeine caches are read for their size, maximum, expiry settings
+and, when built with recordStats(), their hit rate. Large HashMaps,
+LinkedHashMaps and ConcurrentHashMaps are reported when they are access-ordered
+or their values carry timestamp or expiry fields: caches built by hand, which
+never evict anything. Each unbounded or ineffective cache gets a suggestion.
+
+Given a second dump of the same process, the first is the baseline and the
+second is reported, with how much each cache grew in between.`,
+	Example: `  jdiag heap caches dump.hprof
+  jdiag heap caches before.hprof after.hprof	# Growth between two dumps`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, filename := range args {
+			if _, err := os.Stat(filename); os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", filename)
+			}
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		filename, baseline := args[0], ""
+		if len(args) == 2 {
+			filename, baseline = args[1], args[0]
+		}
+		return heap.RunHeapCaches(filename, baseline, config)
+	},
+}
+
+var heapExportCmd = &cobra.Command{
+	Use:   "export [hprof-file]",
+	Short: "Export the class histogram, dominator tree and object summaries as JSON or Parquet",
+	Long: `Export heap analysis results in a structured form for data-analysis pipelines or dashboards.
+
+Every exported object carries its immediate dominator (0 = held directly by GC
+roots), so the dominator tree can be rebuilt from the object table alone.
+
+Output Formats:
+  json     - Single document with dump info, summary, histogram, objects and leak suspects
+  parquet  - Directory with histogram.parquet, objects.parquet and leak_suspects.parquet`,
+	Example: `  jdiag heap export dump.hprof
+  jdiag heap export dump.hprof --format parquet --out /data/heap
+  jdiag heap export dump.hprof --max-objects 10000`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(export.Formats, heapExportFormat) {
+			return fmt.Errorf("invalid export format: %s. Valid options: %v", heapExportFormat, export.Formats)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapExport(filename, config, &heap.ExportConfig{
+			Format:      heapExportFormat,
+			OutPath:     heapExportOut,
+			MaxObjects:  heapExportMaxObjects,
+			MinRetained: heapExportMinRetained,
+		})
+	},
+}
+
+var heapGraphCmd = &cobra.Command{
+	Use:   "graph [hprof-file]",
+	Short: "Write the retained subtree of an object or leak suspect as a GraphViz or Mermaid graph",
+	Long: `Write what an object keeps alive as a DOT or Mermaid graph for incident writeups.
+
+The retained subtree is aggregated by class and limited in depth, so a map
+holding thousands of sessions becomes one "Session ×N" node. The shortest
+reference chain from a GC root to the object is drawn dashed above it.
+
+Without --object or --suspect the first leak suspect is graphed, or the
+biggest object when there are no suspects.
+
+Output Formats:
+  dot      - GraphViz (render with: dot -Tsvg graph.dot -o graph.svg)
+  mermaid  - Mermaid flowchart for Markdown documents`,
+	Example: `  jdiag heap graph dump.hprof
+  jdiag heap graph dump.hprof --suspect 2 --format mermaid
+  jdiag heap graph dump.hprof --object 0x7f1c2a40 --depth 6 --out session.dot`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(export.GraphFormats, heapGraphFormat) {
+			return fmt.Errorf("invalid graph format: %s. Valid options: %v", heapGraphFormat, export.GraphFormats)
+		}
+		if heapGraphObject != "" && heapGraphSuspect > 0 {
+			return fmt.Errorf("--object and --suspect cannot be used together")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		var objectID uint64
+		if heapGraphObject != "" {
+			id, err := strconv.ParseUint(heapGraphObject, 0, 64)
+			if err != nil {
+				return fmt.Errorf("invalid object ID %q: %w", heapGraphObject, err)
+			}
+			objectID = id
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapGraph(filename, config, &heap.GraphConfig{
+			Format:   heapGraphFormat,
+			OutPath:  heapGraphOut,
+			ObjectID: model.ID(objectID),
+			Suspect:  heapGraphSuspect,
+			Options: analyzer.SubgraphOptions{
+				MaxDepth:   heapGraphDepth,
+				MaxClasses: heapGraphMaxClasses,
+				MinShare:   analyzer.DefaultSubgraphMinShare,
+			},
+		})
+	},
+}
+
+var heapCorrelateCmd = &cobra.Command{
+	Use:   "correlate [hprof-file] [gc-log-file]",
+	Short: "Check a GC log's heap growth against the leak suspects in a heap dump",
+	Long: `Combine a GC log and a heap dump into one leak report.
+
+The GC log gives the growth rate of the heap after collections; the dump shows
+what the live heap is made of. Each leak suspect is measured against the growth
+observed over the log, and against how long the logged rate would need to
+accumulate it. When the dump has allocation traces, the code that allocated
+each suspect's retained objects is listed too.
+
+Take the dump from the same JVM run as the log, after the growth has happened.`,
+	Example: `  jdiag heap correlate dump.hprof gc.log`,
+	Args:    cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return utils.CompleteFilesByExtension([]string{".hprof", ".hprof.gz"}, true)(cmd, args, toComplete)
+		}
+		return utils.CompleteFilesByExtension([]string{".log"}, true)(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, gcLogFile := args[0], args[1]
+
+		for _, file := range args {
+			if _, err := os.Stat(file); os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", file)
+			}
+		}
+
+		config, err := newHeapConfig()
+		if err != nil {
+			return err
+		}
+
+		return heap.RunHeapCorrelate(filename, gcLogFile, config)
+	},
+}
+
+// newHeapConfig builds the analysis config from the flags shared by all heap commands
+func newHeapConfig() (*heap.Config, error) {
+	debugLevel, err := parser.ParseDebugLevel(heapDebug)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxMemory utils.MemorySize
+	if heapMaxMemory != "" {
+		if maxMemory, err = utils.ParseMemorySize(heapMaxMemory); err != nil {
+			return nil, fmt.Errorf("invalid --max-memory: %w", err)
+		}
+	}
+
+	return &heap.Config{
+		Workers:       heapWorkers,
+		Debug:         debugLevel,
+		MaxMemory:     maxMemory,
+		StructureOnly: heapStructureOnly,
+		Reindex:       heapReindex,
+	}, nil
+}
+
 func init() {
+	heapCmd.PersistentFlags().IntVarP(&heapWorkers, "workers", "w", 0, "Goroutines for parsing heap dump segments (default: number of CPUs, 1 = sequential)")
+	heapCmd.PersistentFlags().StringVar(&heapDebug, "debug", "off", "Write a parse transcript to <dump>.debug (off, summary, records, trace)")
+	heapCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "summary"
+	heapCmd.PersistentFlags().StringVar(&heapMaxMemory, "max-memory", "", "Memory budget for the analysis, e.g. 4g; past it, reference edges and dominator arrays spill to temporary files (default: no limit)")
+	heapCmd.PersistentFlags().BoolVar(&heapStructureOnly, "structure-only", false, "Skip primitive array contents (char[], byte[], ...) so no String's text is ever read, for dumps holding personal data")
+	heapCmd.PersistentFlags().BoolVar(&heapReindex, "reindex", false, "Ignore the <dump>.idx sidecar index and analyze the dump from scratch, rewriting the index")
+	heapCmd.Flags().StringVarP(&heapOutput, "output", "o", "cli", "Output format")
 	rootCmd.AddCommand(heapCmd)
+
+	heapTopCmd.Flags().IntVarP(&heapTopN, "limit", "n", 20, "Number of objects to list")
+	heapTopCmd.Flags().IntVar(&heapTopPackageDepth, "package-depth", 0, "Roll packages up to their first N names, e.g. 2 counts com.foo.cache as com.foo.* (0 = every package)")
+	heapCmd.AddCommand(heapTopCmd)
+	heapCmd.AddCommand(heapInfoCmd)
+	heapCmd.AddCommand(heapRefsCmd)
+	heapCmd.AddCommand(heapResourcesCmd)
+	heapCmd.AddCommand(heapThreadLocalsCmd)
+	heapCmd.AddCommand(heapCachesCmd)
+
+	heapExportCmd.Flags().StringVarP(&heapExportFormat, "format", "f", export.FormatJSON, "Export format (json, parquet)")
+	heapExportCmd.Flags().StringVar(&heapExportOut, "out", "", "Output file for JSON or directory for Parquet (default: next to the dump)")
+	heapExportCmd.Flags().IntVar(&heapExportMaxObjects, "max-objects", 0, "Export only the N objects with the largest retained size (0 = all reachable objects)")
+	heapExportCmd.Flags().Uint64Var(&heapExportMinRetained, "min-retained", 0, "Skip objects retaining fewer than this many bytes")
+	heapCmd.AddCommand(heapExportCmd)
+
+	heapGraphCmd.Flags().StringVarP(&heapGraphFormat, "format", "f", export.FormatDOT, "Graph format (dot, mermaid)")
+	heapGraphCmd.Flags().StringVar(&heapGraphOut, "out", "", "Output file (default: next to the dump)")
+	heapGraphCmd.Flags().StringVar(&heapGraphObject, "object", "", "Object ID to graph, e.g. 0x7f1c2a40")
+	heapGraphCmd.Flags().IntVar(&heapGraphSuspect, "suspect", 0, "Leak suspect number to graph (1 = biggest)")
+	heapGraphCmd.Flags().IntVarP(&heapGraphDepth, "depth", "d", analyzer.DefaultSubgraphDepth, "Dominator tree levels to include below the object")
+	heapGraphCmd.Flags().IntVar(&heapGraphMaxClasses, "max-classes", analyzer.DefaultSubgraphMaxClasses, "Class groups shown per node before merging the rest")
+	heapCmd.AddCommand(heapGraphCmd)
+	heapCmd.AddCommand(heapCorrelateCmd)
+
+	heapCmd.RegisterFlagCompletionFunc("debug", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return parser.DebugLevels, cobra.ShellCompDirectiveNoFileComp
+	})
+	heapCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"cli", "tui"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	heapExportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return export.Formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	heapGraphCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return export.GraphFormats, cobra.ShellCompDirectiveNoFileComp
+	})
 }
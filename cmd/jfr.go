@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/utils"
+	"github.com/spf13/cobra"
+)
+
+var jfrAllocationsLimit int
+
+var jfrCmd = &cobra.Command{
+	Use:   "jfr [recording-file]",
+	Short: "Analyze a JDK Flight Recorder recording (.jfr)",
+	Long: `Analyze a JDK Flight Recorder recording (.jfr).
+
+The summary includes:
+- Recording and JVM information
+- Event counts by type
+- Garbage collections by collector
+- Safepoints and the VM operations behind them
+- CPU load and hot methods from execution samples
+- Allocation samples (see 'jdiag jfr allocations')`,
+	Example: `  jdiag jfr recording.jfr                 # Recording summary
+  jdiag gc analyze recording.jfr -o tui   # GC analysis of the recording's collections`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".jfr"}, false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		recording, err := jfr.ParseFile(filename)
+		if err != nil {
+			return err
+		}
+		recording.PrintSummary()
+		return nil
+	},
+}
+
+var jfrAllocationsCmd = &cobra.Command{
+	Use:   "allocations [recording-file]",
+	Short: "List the top allocators in a recording by class, site, thread and stack trace",
+	Long: `Aggregate a recording's allocation samples into a top allocators report.
+
+Samples are weighted by the bytes they stand for, so shares estimate real
+allocation volume. Each allocation is attributed to its first frame outside
+the JDK, the code to change when reducing GC pressure. Recordings without
+jdk.ObjectAllocationSample (JDK 15 and older) use TLAB allocation events.`,
+	Example: `  jdiag jfr allocations recording.jfr
+  jdiag jfr allocations recording.jfr -n 20`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".jfr"}, false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		recording, err := jfr.ParseFile(filename)
+		if err != nil {
+			return err
+		}
+		recording.AllocationProfile().PrintReport(filename, jfrAllocationsLimit)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(jfrCmd)
+
+	jfrCmd.AddCommand(jfrAllocationsCmd)
+
+	jfrAllocationsCmd.Flags().IntVarP(&jfrAllocationsLimit, "limit", "n", jfr.MaxReportedItems, "Number of entries to list per section")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/native"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nativeJMXTarget string
+	nativeLimit     int
+)
+
+var nativeCmd = &cobra.Command{
+	Use:   "native [PID|SMAPS-FILE]",
+	Short: "Break down a JVM's native memory from its memory mappings (smaps or pmap -x)",
+	Long: `Break down a JVM's native memory from its memory mappings (smaps or pmap -x).
+
+The report includes:
+- Resident memory by category: Java heap, class space, code cache, thread stacks,
+  glibc malloc arenas, other anonymous memory and mapped files
+- The largest resident mappings
+- For a live JVM, JMX-reported committed memory against what is resident, to
+  show how much native memory the JVM doesn't account for
+- Findings such as glibc arena growth, many threads or swapping
+
+Input can be:
+- A running JVM's PID (reads /proc/<pid>/smaps and connects over JMX)
+- A saved /proc/<pid>/smaps, /proc/<pid>/maps or pmap -x <pid> output`,
+	Example: `  jdiag native 1234                          # Live process with JMX reconciliation
+  jdiag native smaps.txt                     # Saved smaps, no reconciliation
+  jdiag native smaps.txt --jmx 1234          # Saved smaps, reconciled with a live JVM
+  jdiag native 1234 --jmx localhost:9999     # Reconcile through a remote JMX port`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arg := args[0]
+
+		var analysis *native.Analysis
+		var err error
+		jmxTarget := nativeJMXTarget
+		if _, statErr := os.Stat(arg); statErr == nil {
+			analysis, err = native.ParseFile(arg)
+		} else if pid, convErr := strconv.Atoi(arg); convErr == nil && pid > 0 {
+			analysis, err = native.ParseProcess(pid)
+			if jmxTarget == "" {
+				jmxTarget = arg
+			}
+		} else {
+			return fmt.Errorf("invalid argument '%s': must be a PID or an smaps file", arg)
+		}
+		if err != nil {
+			return err
+		}
+
+		var jvm *native.JVMMemory
+		if jmxTarget != "" {
+			jvm, err = queryJVMMemory(jmxTarget)
+			if err != nil {
+				fmt.Printf("⚠️  Skipping JMX reconciliation: %v\n", err)
+			}
+		}
+
+		analysis.Analyze(jvm)
+		analysis.PrintReport(nativeLimit)
+		return nil
+	},
+}
+
+func queryJVMMemory(target string) (*native.JVMMemory, error) {
+	config, err := jmxConfig(target)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := jmx.QuerySnapshot(config)
+	if err != nil {
+		return nil, err
+	}
+	return native.NewJVMMemory(config.String(), snapshot), nil
+}
+
+func init() {
+	rootCmd.AddCommand(nativeCmd)
+
+	nativeCmd.Flags().StringVar(&nativeJMXTarget, "jmx", "", "JVM to reconcile with, as PID, host:port or saved target (default: the PID analyzed)")
+	nativeCmd.Flags().IntVarP(&nativeLimit, "limit", "n", native.MaxReportedMappings, "Number of largest mappings to list")
+}
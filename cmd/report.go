@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportInputs  report.Inputs
+	reportOutput  string
+	reportWorkers int
+	reportNoCache bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [artifact...]",
+	Short: "Combine whatever an incident left behind into one report",
+	Long: `Combine whatever an incident left behind into one report.
+
+Takes any of:
+- A GC log (.log, .log.gz)
+- A JDK Flight Recorder recording (.jfr)
+- A heap dump (.hprof, .hprof.gz)
+- A watch session saved with 'jdiag watch --summary <file>.json'
+
+runs the analyzer for each, then cross-references them: the heap growth in
+the GC log against the dump's leak suspects, GC pressure against the code
+JFR says allocates, leak suspect classes that are still being allocated,
+watch alerts against the pauses that preceded them, and GCLocker stalls
+against the threads the session caught in native code. Files are told apart
+by their extension, or given with --gc, --jfr, --heap and --session.
+
+Output Formats:
+  cli        Findings most urgent first (default)
+  file.json  Save the report as JSON`,
+	Example: `  jdiag report gc.log dump.hprof                  # Is the logged heap growth a leak in the dump?
+  jdiag report gc.log recording.jfr watch.json    # Everything but a dump
+  jdiag report --gc app.out --heap dump.bin       # Files with other extensions
+  jdiag report gc.log recording.jfr -o incident-42.json`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"log", "gz", "jfr", "hprof", "json"}, cobra.ShellCompDirectiveFilterFileExt
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if reportOutput != "cli" && !strings.HasSuffix(reportOutput, ".json") {
+			return fmt.Errorf("invalid output format: %s. Valid options: cli or *.json", reportOutput)
+		}
+
+		for _, arg := range args {
+			if err := reportInputs.Add(arg); err != nil {
+				return err
+			}
+		}
+		if reportInputs.Empty() {
+			return fmt.Errorf("give at least one artifact: a GC log, JFR recording, heap dump or saved watch session")
+		}
+
+		for _, file := range []string{reportInputs.GCLog, reportInputs.JFR, reportInputs.Heap, reportInputs.Session} {
+			if _, err := os.Stat(file); file != "" && os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", file)
+			}
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var cache *gc.Cache
+		if !reportNoCache {
+			cache = gc.NewCache(gc.DefaultCacheDir())
+		}
+
+		incident, err := report.Collect(reportInputs, &heap.Config{Workers: reportWorkers, Output: "cli"}, cache)
+		if err != nil {
+			return err
+		}
+
+		if reportOutput == "cli" {
+			incident.PrintSummary()
+			return nil
+		}
+
+		file, err := os.Create(reportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer file.Close()
+		if err := incident.WriteJSON(file); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		fmt.Printf("📄 Report with %d findings saved to %s\n", len(incident.Findings), reportOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "cli", "Output format (cli or *.json)")
+	reportCmd.Flags().StringVar(&reportInputs.GCLog, "gc", "", "GC log, whatever its extension")
+	reportCmd.Flags().StringVar(&reportInputs.JFR, "jfr", "", "JFR recording, whatever its extension")
+	reportCmd.Flags().StringVar(&reportInputs.Heap, "heap", "", "Heap dump, whatever its extension")
+	reportCmd.Flags().StringVar(&reportInputs.Session, "session", "", "Watch session saved with --summary <file>.json")
+	reportCmd.Flags().BoolVar(&reportNoCache, "no-cache", false, "Parse the GC log again instead of loading the analysis cached in ~/.jdiag/cache")
+	reportCmd.Flags().IntVarP(&reportWorkers, "workers", "w", 0, "Goroutines for parsing heap dump segments (default: number of CPUs, 1 = sequential)")
+
+	reportCmd.MarkFlagFilename("gc")
+	reportCmd.MarkFlagFilename("jfr", "jfr")
+	reportCmd.MarkFlagFilename("heap", "hprof", "gz")
+	reportCmd.MarkFlagFilename("session", "json")
+}
@@ -8,34 +8,56 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/mabhi256/jdiag/utils"
 	"github.com/spf13/cobra"
 )
 
+var (
+	numberLocale    string
+	numberPrecision int
+	colorTheme      string
+	asciiOutput     bool
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "jdiag",
 	Short: "Java diagnostics for GC logs and dumps",
 	Long:  `jdiag helps analyze Java application performance through GC logs, heap dumps, and thread dumps.`,
 
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Skip setup during completion or special commands
 		if cmd.Name() == "completion" || cmd.Name() == "help" || cmd.Name() == "__complete" {
-			return
+			return nil
 		}
 
 		// Don't run setup during completion context
 		if isCompletionContext() {
-			return
+			return nil
+		}
+
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+
+		if err := applyNumberFormat(); err != nil {
+			return err
+		}
+
+		if err := utils.ApplyTheme(colorTheme); err != nil {
+			return err
 		}
+		utils.SetASCII(asciiOutput)
 
 		// Allow users to disable auto-setup
 		if os.Getenv("JDIAG_NO_AUTO_SETUP") != "" {
-			return
+			return nil
 		}
 
 		if !completionsInstalled() {
 			fmt.Println("🔧 Setting up completions...")
 			setupCompletions()
 		}
+		return nil
 	},
 }
 
@@ -49,6 +71,33 @@ func GetRootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// applyNumberFormat sets how numbers, sizes and durations are printed; exports stay locale independent
+func applyNumberFormat() error {
+	locale := utils.LocaleFromEnv()
+	if numberLocale != "" {
+		var err error
+		if locale, err = utils.LookupLocale(numberLocale); err != nil {
+			return err
+		}
+	}
+	if numberPrecision < 0 {
+		return fmt.Errorf("invalid precision %d: must be 0 or more", numberPrecision)
+	}
+
+	utils.SetNumberFormat(utils.NumberFormat{Locale: locale, Precision: numberPrecision})
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&numberLocale, "locale", "", "Number format locale, e.g. en, de or fr_FR (default: from LC_ALL, LC_NUMERIC or LANG)")
+	rootCmd.PersistentFlags().IntVar(&numberPrecision, "precision", 1, "Decimal places for printed metrics")
+	rootCmd.PersistentFlags().StringVar(&colorTheme, "theme", "auto", "Color theme: auto, "+strings.Join(utils.ThemeNames(), ", ")+" (auto honors NO_COLOR and TERM=dumb)")
+	rootCmd.PersistentFlags().BoolVar(&asciiOutput, "ascii", false, "Draw charts, gauges and terminal UIs with ASCII only, for consoles that garble Unicode and emoji")
+	rootCmd.RegisterFlagCompletionFunc("theme", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{"auto"}, utils.ThemeNames()...), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
 func setupCompletions() {
 	shell := detectShell()
 	executable, _ := os.Executable()
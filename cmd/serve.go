@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/server"
+	"github.com/mabhi256/jdiag/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen    string
+	serveDataDir   string
+	serveMaxUpload string
+	serveToken     string
+	serveJobs      int
+	serveWorkers   int
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run jdiag as an HTTP service with a JSON API, so a team can share one instance",
+	Long: `Run jdiag as an HTTP service with a JSON API, so a team can share one instance.
+
+The root URL serves a live dashboard mirroring jdiag watch: heap, GC, threads and CPU
+charts for any PID or host:port, streamed over the /api/watch WebSocket.
+
+Endpoints:
+  GET    /api/health                   Liveness check
+  GET    /api/processes                Java processes on the server host (jps)
+  POST   /api/gc/analyze               Upload a GC log or .jfr recording to analyze
+  POST   /api/heap/analyze             Upload a .hprof or .hprof.gz heap dump (?max_objects=N)
+  GET    /api/reports                  Uploaded files and their analysis status
+  GET    /api/reports/{id}             Analysis report as JSON
+  GET    /api/reports/{id}/events      GC events of a GC analysis
+  DELETE /api/reports/{id}             Remove a report and its uploaded file
+  GET    /api/watch?target=PID         WebSocket stream of live JMX metrics (also host:port, &interval=ms)
+
+Uploads are a multipart "file" field or a raw body with ?name=<file name>.
+Analyses run in the background: poll the report until its status is "done".
+
+By default only this host can connect, and without --token only requests addressed to
+localhost or a loopback IP are answered. Listening on any other address requires --token;
+clients then send "Authorization: Bearer <token>", and the dashboard is opened as
+http://<host>/#token=<token>. POST and DELETE requests from another site's pages are
+refused either way.`,
+	Example: `  jdiag serve                                      # Listen on 127.0.0.1:8080, dashboard at http://127.0.0.1:8080/
+  jdiag serve --listen :9000 --token s3cret        # Accept other hosts, requiring "Authorization: Bearer s3cret"
+  curl -F file=@gc.log 127.0.0.1:8080/api/gc/analyze
+  curl --data-binary @heap.hprof '127.0.0.1:8080/api/heap/analyze?name=heap.hprof'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maxUpload, err := utils.ParseMemorySize(serveMaxUpload)
+		if err != nil {
+			return fmt.Errorf("invalid --max-upload: %w", err)
+		}
+
+		srv, err := server.NewServer(&server.Config{
+			Listen:    serveListen,
+			DataDir:   serveDataDir,
+			MaxUpload: maxUpload,
+			Token:     serveToken,
+			Jobs:      serveJobs,
+			Heap:      &heap.Config{Workers: serveWorkers},
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("🌐 jdiag serving on %s\n", serveListen)
+		fmt.Printf("   Dashboard:  http://%s/\n", dashboardHost(serveListen))
+		fmt.Printf("   Data dir:   %s\n", srv.DataDir())
+		fmt.Printf("   Max upload: %s  |  Parallel analyses: %d\n", maxUpload, serveJobs)
+		if serveToken != "" {
+			fmt.Println("   🔒 Bearer token required on /api requests")
+		}
+
+		if err := srv.Run(ctx); err != nil {
+			return err
+		}
+		fmt.Println("👋 Server stopped")
+		return nil
+	},
+}
+
+// dashboardHost turns a listen address such as ":8080" into one a browser can open
+func dashboardHost(listen string) string {
+	if strings.HasPrefix(listen, ":") {
+		return "localhost" + listen
+	}
+	return listen
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveListen, "listen", "l", server.DefaultListen, "Address to listen on; addresses other than loopback require --token")
+	serveCmd.Flags().StringVar(&serveDataDir, "data-dir", "", "Directory for uploaded files (default: $TMPDIR/jdiag-serve)")
+	serveCmd.Flags().StringVar(&serveMaxUpload, "max-upload", server.DefaultMaxUpload.String(), "Largest accepted upload, e.g. 512M, 8G")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token clients must send (default: no authentication, loopback only)")
+	serveCmd.Flags().IntVar(&serveJobs, "jobs", server.DefaultJobs, "Analyses to run at the same time")
+	serveCmd.Flags().IntVarP(&serveWorkers, "workers", "w", 0, "Goroutines for parsing each heap dump (default: number of CPUs)")
+}
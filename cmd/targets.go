@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/mabhi256/jdiag/internal/config"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	targetHost    string
+	targetPort    int
+	targetSSL     bool
+	targetUser    string
+	targetKeyring bool
+)
+
+var targetsCmd = &cobra.Command{
+	Use:   "targets",
+	Short: "Save JMX endpoints under a nickname, for jdiag watch, flags and native",
+	Long: `Save JMX endpoints under a nickname, so 'jdiag watch prod-api' connects
+to the host, port, SSL and credentials saved for it. Targets are kept next to
+the config file (~/.jdiag.targets by default).
+
+Passwords are never written to the file: with --keyring the password is read
+once and kept in the OS keyring (security on macOS, secret-tool on Linux);
+otherwise it's read from JDIAG_JMX_PASSWORD on every connection.
+
+Examples:
+  jdiag targets add prod-api --host 10.0.0.5 --port 9010 --ssl
+  jdiag targets add prod-api --host 10.0.0.5 --port 9010 --user monitor --keyring
+  jdiag targets list
+  jdiag targets remove prod-api
+  jdiag watch prod-api`,
+}
+
+var targetsAddCmd = &cobra.Command{
+	Use:   "add NAME --host HOST --port PORT",
+	Short: "Save a JMX endpoint, replacing one saved under the same name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := config.Target{
+			Name:    args[0],
+			Host:    targetHost,
+			Port:    targetPort,
+			SSL:     targetSSL,
+			User:    targetUser,
+			Keyring: targetKeyring,
+		}
+		if err := target.Validate(); err != nil {
+			return err
+		}
+
+		path, targets, err := loadTargets()
+		if err != nil {
+			return err
+		}
+		if target.Keyring {
+			password, err := readPassword(fmt.Sprintf("Password for %s@%s: ", target.User, target.Address()))
+			if err != nil {
+				return err
+			}
+			if err := config.StorePassword(target.Name, password); err != nil {
+				return err
+			}
+		} else if previous, exists := targets[target.Name]; exists && previous.Keyring {
+			_ = config.DeletePassword(target.Name)
+		}
+
+		targets[target.Name] = target
+		if err := config.SaveTargets(path, targets); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Saved %s (%s) to %s\n", target.Name, target.Address(), path)
+		return nil
+	},
+}
+
+var targetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved JMX endpoints",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, targets, err := loadTargets()
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			fmt.Printf("No saved targets in %s; add one with 'jdiag targets add'\n", path)
+			return nil
+		}
+
+		names := make([]string, 0, len(targets))
+		for name := range targets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			target := targets[name]
+			var options []string
+			if target.SSL {
+				options = append(options, "ssl")
+			}
+			switch {
+			case target.Keyring:
+				options = append(options, "user "+target.User+", password in keyring")
+			case target.User != "":
+				options = append(options, "user "+target.User+", password from JDIAG_JMX_PASSWORD")
+			}
+			line := fmt.Sprintf("%-20s %s", name, target.Address())
+			if len(options) > 0 {
+				line += "  (" + strings.Join(options, "; ") + ")"
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+var targetsRemoveCmd = &cobra.Command{
+	Use:               "remove NAME",
+	Aliases:           []string{"rm"},
+	Short:             "Remove a saved JMX endpoint and its keyring password",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedTargets,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, targets, err := loadTargets()
+		if err != nil {
+			return err
+		}
+		target, exists := targets[args[0]]
+		if !exists {
+			return fmt.Errorf("no target named '%s' in %s", args[0], path)
+		}
+
+		if target.Keyring {
+			if err := config.DeletePassword(target.Name); err != nil {
+				fmt.Printf("⚠️  Keyring password not removed: %v\n", err)
+			}
+		}
+		delete(targets, target.Name)
+		if err := config.SaveTargets(path, targets); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  Removed %s\n", target.Name)
+		return nil
+	},
+}
+
+func loadTargets() (string, map[string]config.Target, error) {
+	path := config.TargetsPath(configFilePath())
+	if path == "" {
+		return "", nil, fmt.Errorf("no home directory to keep targets in; give --config")
+	}
+	targets, err := config.LoadTargets(path)
+	return path, targets, err
+}
+
+// readPassword prompts for a password without echo, or reads a line when stdin isn't a terminal
+func readPassword(prompt string) (string, error) {
+	if term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Fprint(os.Stderr, prompt)
+		password, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+/*
+ * jmxConfig resolves a PID, HOST:PORT or saved target name to what to
+ * connect to. A saved target's password comes from the keyring or
+ * JDIAG_JMX_PASSWORD; a HOST:PORT given directly uses JDIAG_JMX_USER and
+ * JDIAG_JMX_PASSWORD when they're set.
+ */
+func jmxConfig(arg string) (*jmx.Config, error) {
+	if pid, err := strconv.Atoi(arg); err == nil && pid > 0 {
+		return &jmx.Config{PID: pid}, nil
+	}
+	if host, port, err := parseHostPort(arg); err == nil {
+		return &jmx.Config{Host: host, Port: port, User: os.Getenv("JDIAG_JMX_USER"), Password: os.Getenv("JDIAG_JMX_PASSWORD")}, nil
+	}
+
+	_, targets, err := loadTargets()
+	if err != nil {
+		return nil, err
+	}
+	target, exists := targets[arg]
+	if !exists {
+		return nil, fmt.Errorf("invalid target '%s': must be PID, host:port or a name saved with 'jdiag targets add'", arg)
+	}
+
+	jmxConfig := &jmx.Config{Name: target.Name, Host: target.Host, Port: target.Port, SSL: target.SSL, User: target.User}
+	switch {
+	case target.Keyring:
+		if jmxConfig.Password, err = config.LookupPassword(target.Name); err != nil {
+			return nil, err
+		}
+	case target.User != "":
+		jmxConfig.Password = os.Getenv("JDIAG_JMX_PASSWORD")
+	}
+	return jmxConfig, nil
+}
+
+// completeSavedTargets offers the saved target names with their endpoints
+func completeSavedTargets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	_, targets, err := loadTargets()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for name, target := range targets {
+		completions = append(completions, name+"\t"+target.Address())
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(targetsCmd)
+
+	targetsCmd.AddCommand(targetsAddCmd)
+	targetsCmd.AddCommand(targetsListCmd)
+	targetsCmd.AddCommand(targetsRemoveCmd)
+
+	targetsAddCmd.Flags().StringVar(&targetHost, "host", "", "JMX host")
+	targetsAddCmd.Flags().IntVar(&targetPort, "port", 0, "JMX port")
+	targetsAddCmd.Flags().BoolVar(&targetSSL, "ssl", false, "The RMI registry uses SSL (com.sun.management.jmxremote.registry.ssl)")
+	targetsAddCmd.Flags().StringVar(&targetUser, "user", "", "JMX username")
+	targetsAddCmd.Flags().BoolVar(&targetKeyring, "keyring", false, "Prompt for the password and keep it in the OS keyring")
+	targetsAddCmd.MarkFlagRequired("host")
+	targetsAddCmd.MarkFlagRequired("port")
+}
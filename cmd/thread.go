@@ -2,29 +2,134 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"slices"
 
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/internal/thread/tui"
+	"github.com/mabhi256/jdiag/utils"
 	"github.com/spf13/cobra"
 )
 
+var threadOutput string
+
 var threadCmd = &cobra.Command{
-	Use:   "thread",
-	Short: "Analyze thread dumps",
+	Use:     "thread [dump-file]",
+	Aliases: []string{"threads"},
+	Short:   "Analyze thread dumps (jstack or jcmd Thread.print output)",
+	Long: `Analyze thread dumps (jstack or jcmd Thread.print output).
+
+The analysis includes:
+- Thread state distribution
+- Lock dependency graph and contended locks
+- Deadlock detection (monitors and java.util.concurrent locks)
+- Groups of threads with identical stacks
+
+Output Formats:
+  cli  - Analysis summary printed to the terminal (default)
+  tui  - Interactive explorer with thread list and stack drill-down`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".txt", ".tdump", ".log"}, true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		validFormats := []string{"cli", "tui"}
+		if !slices.Contains(validFormats, threadOutput) {
+			return fmt.Errorf("invalid output format: %s. Valid options: %v", threadOutput, validFormats)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename := args[0]
+
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			return fmt.Errorf("file does not exist: %s", filename)
+		}
+
+		dumps, err := thread.ParseFileDumps(filename)
+		if err != nil {
+			return err
+		}
+		analysis := thread.Analyze(dumps[0])
+
+		if threadOutput == "tui" {
+			return tui.StartTUI(analysis)
+		}
+		if len(dumps) > 1 {
+			fmt.Printf("ℹ️  %s holds %d dumps; analyzing the first. Use 'jdiag thread compare %s' to compare them.\n\n",
+				filename, len(dumps), filename)
+		}
+		analysis.PrintSummary()
+		return nil
+	},
 }
 
 var threadValidateCmd = &cobra.Command{
-	Use:   "validate [hprof-file]",
-	Short: "Validate thread dump file",
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:               "validate [dump-file]",
+	Short:             "Validate thread dump file",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".txt", ".tdump", ".log"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Validating thread dump: %s\n", args[0])
-		// TODO: implement
+
+		dump, err := thread.ParseFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Valid thread dump: %d threads", len(dump.Threads))
+		if !dump.Timestamp.IsZero() {
+			fmt.Printf(", taken %s", dump.Timestamp.Format("2006-01-02 15:04:05"))
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var threadCompareCmd = &cobra.Command{
+	Use:   "compare [dump-file...]",
+	Short: "Compare 3-5 thread dumps taken a few seconds apart",
+	Long: `Compare 3-5 thread dumps taken a few seconds apart.
+
+Dumps can be separate files or several dumps appended to one file. Threads are
+matched across dumps and classified as:
+  stuck        - same stack in every dump while running, blocked or waiting for an owned lock
+  progressing  - stack changed between dumps
+  idle         - same stack, but only waiting for work
+CPU used between dumps is shown for JDK 11+ dumps, which print cpu= and elapsed=.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: utils.CompleteFilesByExtension([]string{".txt", ".tdump", ".log"}, true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var dumps []*thread.ThreadDump
+		for _, filename := range args {
+			if _, err := os.Stat(filename); os.IsNotExist(err) {
+				return fmt.Errorf("file does not exist: %s", filename)
+			}
+
+			fileDumps, err := thread.ParseFileDumps(filename)
+			if err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+			dumps = append(dumps, fileDumps...)
+		}
+
+		comparison, err := thread.CompareDumps(dumps)
+		if err != nil {
+			return fmt.Errorf("%w (pass several files or one file holding several dumps)", err)
+		}
+		comparison.PrintSummary()
+		return nil
 	},
 }
 
-// TODO: add summary, analyze, compare, export, watch commands
+// TODO: add export, watch commands
 
 func init() {
+	threadCmd.Flags().StringVarP(&threadOutput, "output", "o", "cli", "Output format")
 	rootCmd.AddCommand(threadCmd)
 
 	threadCmd.AddCommand(threadValidateCmd)
+	threadCmd.AddCommand(threadCompareCmd)
+
+	threadCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"cli", "tui"}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mabhi256/jdiag/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topPID        int
+	topHost       string
+	topInterval   int
+	topIterations int
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top [PID|HOST:PORT|TARGET]",
+	Short: "Print a refreshing one-screen summary of a running JVM",
+	Long: `Top prints a compact summary of a running JVM and redraws it every interval:
+heap used against its max, collections per second and their average pause,
+time spent in GC, process and system CPU, threads and loaded classes.
+
+It's plain text rather than the 'jdiag watch' dashboard, for slow SSH sessions
+and quick checks. Press Ctrl-C to stop; the last summary stays on screen. When
+the output isn't a terminal, each summary is appended instead, so it can be
+logged to a file.`,
+	Example: `  jdiag top 1234                  # Refresh every 2s until Ctrl-C
+  jdiag top --pid <TAB>           # Running JVMs with their main class
+  jdiag top prod-api -i 5000      # A saved target, every 5s
+  jdiag top --host db:9010 -n 1   # One summary, then exit
+  jdiag top 1234 -n 30 > top.log  # A minute of summaries to a file`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{targetAnnotation: "true"},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions, _ := completeJavaProcesses(cmd, args, toComplete)
+		saved, _ := completeSavedTargets(cmd, args, toComplete)
+		hosts, _ := completeTargets(cmd, args, toComplete)
+		return append(append(completions, saved...), hosts...), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && (topPID != 0 || topHost != "") {
+			return fmt.Errorf("give the target as an argument or with --pid/--host, not both")
+		}
+		if topInterval <= 0 {
+			return fmt.Errorf("invalid interval %d: must be above 0 ms", topInterval)
+		}
+
+		arg := configTarget
+		switch {
+		case len(args) > 0:
+			arg = args[0]
+		case topPID != 0:
+			arg = strconv.Itoa(topPID)
+		case topHost != "":
+			arg = topHost
+		}
+		if arg == "" {
+			return fmt.Errorf("no JVM to watch: give a PID, HOST:PORT or saved target (see 'jdiag watch' to pick one)")
+		}
+
+		config, err := jmxConfig(arg)
+		if err != nil {
+			return err
+		}
+		config.Interval = topInterval
+		if config.Host != "" && config.Name == "" {
+			// Only feeds --host completion, so a read-only home directory isn't an error
+			_ = addRecentTarget(fmt.Sprintf("%s:%d", config.Host, config.Port))
+		}
+
+		return watch.RunTop(config, topIterations, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+
+	topCmd.Flags().IntVar(&topPID, "pid", 0, "Process ID of the JVM to monitor")
+	topCmd.Flags().StringVar(&topHost, "host", "", "JMX endpoint to monitor as HOST:PORT")
+	topCmd.Flags().IntVarP(&topInterval, "interval", "i", 2000, "Update interval in ms")
+	topCmd.Flags().IntVarP(&topIterations, "iterations", "n", 0, "Stop after this many summaries (default: until Ctrl-C)")
+	topCmd.MarkFlagsMutuallyExclusive("pid", "host")
+
+	topCmd.RegisterFlagCompletionFunc("pid", completeJavaProcesses)
+	topCmd.RegisterFlagCompletionFunc("host", completeTargets)
+}
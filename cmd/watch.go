@@ -2,92 +2,233 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/notify"
 	"github.com/mabhi256/jdiag/internal/watch"
 	"github.com/spf13/cobra"
 )
 
 var (
-	interval int
-	debug    bool
+	interval    int
+	debug       bool
+	watchNotify string
+	watchPID    int
+	watchHost   string
+
+	watchAllocationRate float64
+	watchPromotionRate  float64
+	watchSummary        string
+	watchReplay         string
+	watchDuration       time.Duration
+	watchReport         string
 )
 
 var watchCmd = &cobra.Command{
-	Use: "watch [PID|HOST:PORT]",
-	Short: `Watch provides real-time monitoring of Java application performance metrics including:
-- Heap memory usage (young/old generation)
-- GC events and frequency  
+	Use:   "watch [PID|HOST:PORT|TARGET]",
+	Short: "Monitor a running JVM's heap, GC, threads and CPU in real time",
+	Long: `Watch provides real-time monitoring of Java application performance metrics including:
+- Heap memory usage (young/old generation), and when the heap runs out at its current trend
+- The old generation after each collection that covers it, fitted for a leak
+- GC events and frequency, with p50/p95/p99 pauses and allocation rates
 - Thread count and CPU usage
 - Class loading statistics
 
 The tool automatically discovers running Java processes and can enable JMX monitoring
 for processes that don't have it enabled.
 
-Examples:
-  jdiag watch           				# Interactive process selection
+Press e to save the GC events and snapshot history seen so far as JSON, or E as CSV.
+
+With --duration there's no TUI: it watches for that long, prints the session
+summary and, with --report, writes an HTML report of the whole run, for load
+tests run from CI.`,
+	Example: `  jdiag watch           				# Interactive process selection
   jdiag watch <TAB>                     # Tab completion with PID and MainClass
   jdiag watch 1234                      # Monitor process ID 1234
   jdiag watch localhost:9999            # Monitor JMX on localhost:9999
-  jdiag watch remote.com:8080           # Monitor remote JMX`,
-	Args: cobra.MaximumNArgs(1),
+  jdiag watch remote.com:8080           # Monitor remote JMX
+  jdiag watch prod-api                  # A target saved with 'jdiag targets add'
+  jdiag watch --pid <TAB>               # Running JVMs with their main class
+  jdiag watch --host <TAB>              # Recent and configured HOST:PORT targets
+  jdiag watch 1234 --summary watch.txt    # Keep the summary printed on exit
+  jdiag watch 1234 --summary watch.json   # Save it for 'jdiag report'
+  jdiag watch 1234 --alloc-rate-warn 200   # Mark allocation above 200 MB/s on the GC tab
+  jdiag watch --pid 1234 --duration 10m --report out.html  # Headless, then an HTML report
+  jdiag watch prod-api --duration 1h --report run.json     # The same run as 'e' saves it
+  jdiag watch 1234 --debug              # Also write jmx_capture_<time>.jsonl
+  jdiag watch --replay-jmx jmx_capture_20250101_120000.jsonl  # Replay a capture instead of a JVM
+  jdiag watch 1234 --notify teams://example.webhook.office.com/webhookb2/...  # Post alerts and their resolution to Teams`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{targetAnnotation: "true"},
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		// Already provided (single) argument, don't offer completions
 		if len(args) != 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 
-		// Provide Java process completion
-		processes, err := watch.DiscoverJavaProcesses()
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-
-		var completions []string
-		for _, proc := range processes {
-			option := fmt.Sprintf("%d\t%s", proc.PID, proc.MainClass)
-			completions = append(completions, option)
-		}
-
-		return completions, cobra.ShellCompDirectiveNoFileComp
+		completions, _ := completeJavaProcesses(cmd, args, toComplete)
+		saved, _ := completeSavedTargets(cmd, args, toComplete)
+		hosts, _ := completeTargets(cmd, args, toComplete)
+		return append(append(completions, saved...), hosts...), cobra.ShellCompDirectiveNoFileComp
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config := &jmx.Config{
 			Interval: interval,
 		}
 
-		if len(args) > 0 {
-			arg := args[0]
+		if len(args) > 0 && (watchPID != 0 || watchHost != "") {
+			return fmt.Errorf("give the target as an argument or with --pid/--host, not both")
+		}
+
+		if watchReplay != "" && (len(args) > 0 || watchPID != 0 || watchHost != "") {
+			return fmt.Errorf("--replay-jmx replays a capture in place of a target")
+		}
 
-			// Check if PID
-			if pid, err := strconv.Atoi(arg); err == nil && pid > 0 {
-				config.PID = pid
-			} else if host, port, err := parseHostPort(arg); err == nil {
-				// Parse as host:port
-				config.Host = host
-				config.Port = port
-			} else {
-				return fmt.Errorf("invalid argument '%s': must be PID or host:port", arg)
+		if watchDuration < 0 {
+			return fmt.Errorf("invalid duration %s: must be above 0", watchDuration)
+		}
+		if watchReport != "" && watchDuration == 0 {
+			return fmt.Errorf("--report is written at the end of a headless run; add --duration")
+		}
+
+		arg := configTarget
+		switch {
+		case watchReplay != "":
+			arg = "" // A configured target doesn't apply to a replay
+		case len(args) > 0:
+			arg = args[0]
+		case watchPID != 0:
+			arg = strconv.Itoa(watchPID)
+		case watchHost != "":
+			arg = watchHost
+		}
+
+		if arg != "" {
+			// A PID, host:port or saved target
+			target, err := jmxConfig(arg)
+			if err != nil {
+				return err
+			}
+			target.Interval = config.Interval
+			config = target
+		}
+
+		if config.Host != "" && config.Name == "" {
+			// Only feeds --host completion, so a read-only home directory isn't an error
+			_ = addRecentTarget(fmt.Sprintf("%s:%d", config.Host, config.Port))
+		}
+
+		var notifier *notify.Notifier
+		if watchNotify != "" {
+			var err error
+			if notifier, err = notify.NewNotifier(watchNotify); err != nil {
+				return err
 			}
 		}
 
 		config.Debug = debug
-		err := watch.StartTUI(config)
+		config.ReplayFile = watchReplay
+		thresholds := watch.RateThresholds{Allocation: watchAllocationRate, Promotion: watchPromotionRate}
+		if watchDuration > 0 {
+			return watchHeadless(config, notifier, thresholds)
+		}
+
+		summary, err := watch.StartTUI(config, notifier, thresholds)
 		if err != nil {
 			return fmt.Errorf("unable to start TUI: %w", err)
 		}
+		if summary.Empty() {
+			return nil
+		}
 
-		return nil
+		fmt.Println()
+		return writeWatchSummary(summary)
 	},
 }
 
+// watchHeadless runs --duration: no TUI, the summary at the end and --report if asked for
+func watchHeadless(config *jmx.Config, notifier *notify.Notifier, thresholds watch.RateThresholds) error {
+	if config.PID == 0 && config.Host == "" && config.ReplayFile == "" {
+		return fmt.Errorf("--duration needs a target: give a PID, HOST:PORT or saved target")
+	}
+
+	summary, export, err := watch.RunHeadless(config, watchDuration, notifier, thresholds)
+	if err != nil {
+		return err
+	}
+	if err := writeWatchSummary(summary); err != nil {
+		return err
+	}
+
+	if watchReport != "" {
+		if err := watch.SaveReport(watchReport, summary, export); err != nil {
+			return err
+		}
+		fmt.Printf("\n📄 Report saved to %s\n", watchReport)
+	}
+	return nil
+}
+
+// writeWatchSummary prints the session summary, and saves it too with --summary
+func writeWatchSummary(summary *watch.SessionSummary) error {
+	summary.Write(os.Stdout)
+	if watchSummary != "" {
+		var text strings.Builder
+		if strings.HasSuffix(watchSummary, ".json") {
+			// Only fails on values JSON can't hold, which the summary has none of
+			_ = summary.WriteJSON(&text)
+		} else {
+			summary.Write(&text)
+		}
+		if err := os.WriteFile(watchSummary, []byte(text.String()), 0o644); err != nil {
+			return fmt.Errorf("failed to write session summary: %w", err)
+		}
+		fmt.Printf("\n📄 Summary saved to %s\n", watchSummary)
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(watchCmd)
 
 	watchCmd.Flags().IntVarP(&interval, "interval", "i", 1000, "Update interval im ms")
 	watchCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug mode")
+	watchCmd.Flags().StringVar(&watchNotify, "notify", "", "Post alerts, escalations and resolutions to a webhook (slack://<webhook> or teams://<webhook>)")
+	watchCmd.Flags().IntVar(&watchPID, "pid", 0, "Process ID of the JVM to monitor")
+	watchCmd.Flags().StringVar(&watchHost, "host", "", "JMX endpoint to monitor as HOST:PORT")
+	watchCmd.Flags().Float64Var(&watchAllocationRate, "alloc-rate-warn", watch.DefaultAllocationRateWarning, "Allocation rate in MB/s the GC tab warns above")
+	watchCmd.Flags().Float64Var(&watchPromotionRate, "promotion-rate-warn", watch.DefaultPromotionRateWarning, "Promotion rate in MB/s the GC tab warns above")
+	watchCmd.Flags().StringVar(&watchSummary, "summary", "", "Also write the session summary printed on exit to this file (JSON if it ends in .json)")
+	watchCmd.Flags().StringVar(&watchReplay, "replay-jmx", "", "Replay a JMX capture written by --debug instead of connecting to a JVM")
+	watchCmd.Flags().DurationVar(&watchDuration, "duration", 0, "Watch without the TUI for this long, e.g. 10m, then print the summary")
+	watchCmd.Flags().StringVar(&watchReport, "report", "", "With --duration, write an HTML report of the run to this file (the export as JSON if it ends in .json)")
+	watchCmd.MarkFlagsMutuallyExclusive("pid", "host")
+
+	watchCmd.RegisterFlagCompletionFunc("pid", completeJavaProcesses)
+	watchCmd.RegisterFlagCompletionFunc("host", completeTargets)
+	watchCmd.RegisterFlagCompletionFunc("replay-jmx", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"jsonl"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+}
+
+// completeJavaProcesses offers running JVMs as PID with the main class as the description
+func completeJavaProcesses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	processes, err := watch.DiscoverJavaProcesses()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, proc := range processes {
+		option := fmt.Sprintf("%d\t%s", proc.PID, proc.MainClass)
+		completions = append(completions, option)
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
 func parseHostPort(arg string) (string, int, error) {
@@ -0,0 +1,139 @@
+package bench
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"testing"
+)
+
+var (
+	update = flag.Bool("update", false, "Record the measured allocations in baseline.json instead of comparing; needs -reason")
+	reason = flag.String("reason", "", "Why allocations changed, stored with every entry -update changes")
+)
+
+const (
+	baselinePath   = "baseline.json"
+	allocTolerance = 0.05 // Growth in allocs/op over the baseline that fails, as a fraction
+	allocRuns      = 2    // Runs averaged per benchmark, after a warm-up run
+)
+
+// Baseline is the allocations of every benchmark, keyed by case name such as gc/parse/cms_gc
+type Baseline struct {
+	Note       string                   `json:"note,omitempty"` // Where the numbers without a reason of their own come from
+	Benchmarks map[string]BaselineEntry `json:"benchmarks"`
+}
+
+type BaselineEntry struct {
+	AllocsPerOp int64  `json:"allocsPerOp"`
+	Reason      string `json:"reason,omitempty"` // Why the number last changed; the note covers the recording without one
+}
+
+func TestAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs every benchmark")
+	}
+	if *update && *reason == "" {
+		t.Fatal("-update needs -reason saying why allocations changed")
+	}
+
+	baseline, err := loadBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := Baseline{Note: baseline.Note, Benchmarks: maps.Clone(baseline.Benchmarks)}
+
+	dir := t.TempDir()
+	for _, c := range benchCases() {
+		t.Run(c.name, func(t *testing.T) {
+			allocs, err := measureAllocs(c, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			previous, ok := baseline.Benchmarks[c.name]
+			growth := change(allocs, previous.AllocsPerOp)
+			if *update {
+				if !ok || growth > allocTolerance || growth < -allocTolerance {
+					updated.Benchmarks[c.name] = BaselineEntry{AllocsPerOp: allocs, Reason: *reason}
+				}
+				return
+			}
+
+			switch {
+			case !ok:
+				t.Errorf("%d allocs/op, not in %s; record it with -update -reason", allocs, baselinePath)
+			case growth > allocTolerance:
+				t.Errorf("%d allocs/op, %+.1f%% over the baseline's %d; if the change is intended, record it with -update -reason",
+					allocs, growth*100, previous.AllocsPerOp)
+			case growth < -allocTolerance:
+				t.Logf("%d allocs/op, %+.1f%% under the baseline's %d; record the improvement with -update -reason",
+					allocs, growth*100, previous.AllocsPerOp)
+			}
+		})
+	}
+
+	if *update {
+		if err := saveBaseline(baselinePath, updated); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// measureAllocs is the average allocations of one run of the case
+func measureAllocs(c benchCase, dir string) (int64, error) {
+	var opErr error
+	allocs := func() float64 {
+		defer silenceStdout()()
+		op, err := c.setup(dir)
+		if err != nil {
+			opErr = err
+			return 0
+		}
+		return testing.AllocsPerRun(allocRuns, func() {
+			if err := op(); err != nil && opErr == nil {
+				opErr = err
+			}
+		})
+	}()
+	return int64(allocs), opErr
+}
+
+// change is how far current is above previous, as a fraction of previous
+func change(current, previous int64) float64 {
+	if previous == 0 {
+		if current == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(current-previous) / float64(previous)
+}
+
+func loadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Baseline{Benchmarks: map[string]BaselineEntry{}}, nil
+	}
+	if err != nil {
+		return Baseline{}, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return Baseline{}, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if baseline.Benchmarks == nil {
+		baseline.Benchmarks = map[string]BaselineEntry{}
+	}
+	return baseline, nil
+}
+
+func saveBaseline(path string, baseline Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,206 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
+)
+
+// gcLogs are the checked-in sample logs the GC benchmarks parse, one per collector
+var gcLogs = []string{
+	"../../gc_log_sample/unified/g1_gc_v2.log",
+	"../../gc_log_sample/unified/cms_gc.log",
+	"../../gc_log_sample/unified/parallel_gc.log",
+}
+
+const (
+	dumpEntries     = 20000 // Cache entries in the synthetic heap dump, about 100k objects
+	parallelWorkers = 4     // Fixed rather than the CPU count, so allocations compare across machines
+)
+
+// benchCase is one operation to time; setup runs once, outside the timing
+type benchCase struct {
+	name  string
+	setup func(dir string) (func() error, error)
+}
+
+func benchCases() []benchCase {
+	var cases []benchCase
+	for _, log := range gcLogs {
+		name := strings.TrimSuffix(filepath.Base(log), ".log")
+		cases = append(cases,
+			benchCase{"gc/parse/" + name, gcParseCase(fixedLog(log))},
+			benchCase{"gc/analyze/" + name, gcAnalyzeCase(fixedLog(log))})
+	}
+	return append(cases,
+		benchCase{"gc/parse/synthetic-g1", gcParseCase(syntheticGCLog)},
+		benchCase{"gc/analyze/synthetic-g1", gcAnalyzeCase(syntheticGCLog)},
+		benchCase{"heap/parse/sequential", heapParseCase(1)},
+		benchCase{"heap/parse/parallel", heapParseCase(parallelWorkers)},
+		benchCase{"heap/analyze", heapAnalyzeCase},
+	)
+}
+
+func BenchmarkGC(b *testing.B) {
+	runBenchmarks(b, "gc/")
+}
+
+func BenchmarkHeap(b *testing.B) {
+	runBenchmarks(b, "heap/")
+}
+
+// runBenchmarks runs the cases under prefix as sub-benchmarks, e.g. BenchmarkGC/parse/cms_gc
+func runBenchmarks(b *testing.B, prefix string) {
+	dir := b.TempDir()
+	for _, c := range benchCases() {
+		name, ok := strings.CutPrefix(c.name, prefix)
+		if !ok {
+			continue
+		}
+		b.Run(name, func(b *testing.B) {
+			defer silenceStdout()()
+			op, err := c.setup(dir)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if err := op(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// silenceStdout discards the progress the parsers and analyzers print, which would
+// break up the benchmark result lines; the returned func restores stdout
+func silenceStdout() func() {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return func() {}
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	return func() {
+		os.Stdout = stdout
+		devNull.Close()
+	}
+}
+
+// fixedLog is a GC log source that is already on disk
+func fixedLog(path string) func(string) (string, error) {
+	return func(string) (string, error) { return path, nil }
+}
+
+func gcParseCase(source func(dir string) (string, error)) func(string) (func() error, error) {
+	return func(dir string) (func() error, error) {
+		log, err := source(dir)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			_, _, err := gc.NewParser().ParseFile(log)
+			return err
+		}, nil
+	}
+}
+
+// gcAnalyzeCase times the analysis and recommendations. The analysis fills in the
+// events, so each run works on a copy of the parsed log; copying is cheap next to it.
+func gcAnalyzeCase(source func(dir string) (string, error)) func(string) (func() error, error) {
+	return func(dir string) (func() error, error) {
+		log, err := source(dir)
+		if err != nil {
+			return nil, err
+		}
+		events, analysis, err := gc.NewParser().ParseFile(log)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() error {
+			copied := make([]*gc.GCEvent, len(events))
+			for i, event := range events {
+				clone := *event
+				copied[i] = &clone
+			}
+			fresh := *analysis
+
+			gc.AnalyzeGCLogs(copied, &fresh)
+			gc.GetRecommendations(&fresh)
+			return nil
+		}, nil
+	}
+}
+
+func heapParseCase(workers int) func(string) (func() error, error) {
+	return func(dir string) (func() error, error) {
+		dump, err := syntheticDump(dir)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			p, err := parseDump(dump, workers)
+			if err != nil {
+				return err
+			}
+			return p.Close()
+		}, nil
+	}
+}
+
+func heapAnalyzeCase(dir string) (func() error, error) {
+	dump, err := syntheticDump(dir)
+	if err != nil {
+		return nil, err
+	}
+	p, err := parseDump(dump, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		heapAnalyzer := analyzer.NewAnalyzer(p.GetStringRegistry(), p.GetClassRegistry(), p.GetClassDumpRegistry(),
+			p.GetObjectRegistry(), p.GetArrayRegistry(), p.GetGCRootRegistry(), p.GetHeader().IdentifierSize)
+		defer heapAnalyzer.Close()
+		return heapAnalyzer.PerformAnalysis()
+	}, nil
+}
+
+// syntheticDump writes the benchmark heap dump into dir on first use
+func syntheticDump(dir string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("synthetic-%d.hprof", dumpEntries))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := writeSyntheticDump(path, dumpEntries); err != nil {
+		return "", fmt.Errorf("failed to write synthetic heap dump: %w", err)
+	}
+	return path, nil
+}
+
+func parseDump(path string, workers int) (*parser.Parser, error) {
+	p, err := parser.NewParser(path)
+	if err != nil {
+		return nil, err
+	}
+	p.SetWorkers(workers)
+	if err := p.ParseHprof(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	if p.IsTruncated() {
+		p.Close()
+		return nil, fmt.Errorf("synthetic heap dump parsed as truncated")
+	}
+	return p, nil
+}
@@ -0,0 +1,14 @@
+// Package bench holds the parser and analyzer benchmarks and an allocation gate
+// against a checked-in baseline:
+//
+//	go test ./internal/bench -run '^$' -bench . -count 10 > new.txt   # Time them (make bench)
+//	benchstat old.txt new.txt                                          # Compare two runs
+//	go test ./internal/bench -run TestAllocations                      # Fail on allocs/op past baseline.json
+//	go test ./internal/bench -run TestAllocations -update -reason '…'  # Record a change (make bench-baseline)
+//
+// ns/op depends on the machine, so time is compared with benchstat between
+// runs on the same one. allocs/op doesn't and is gated everywhere: a change
+// that allocates more updates baseline.json, with the reason next to the
+// numbers it changed. Numbers without one are from the recording its note
+// describes.
+package bench
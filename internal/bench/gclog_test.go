@@ -0,0 +1,111 @@
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * The checked-in sample logs hold a few dozen collections each, too few to
+ * show how the parser scales. The long log replays a sample over and over,
+ * renumbering the collections and shifting the timestamps of each replay
+ * past the one before, so it reads as a single run of one JVM.
+ */
+
+const (
+	gcLogTemplate  = "../../gc_log_sample/working/g1gc_premature_promotion.log"
+	gcLogMinEvents = 1000 // Collections the long log has at least
+)
+
+var (
+	gcLogDecorations = regexp.MustCompile(`^\[([^\]]+)\]\[(\d+\.\d+)s\]`)
+	gcLogID          = regexp.MustCompile(`GC\((\d+)\)`)
+)
+
+const gcLogTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// syntheticGCLog writes the long log into dir on first use
+func syntheticGCLog(dir string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("synthetic-g1-%d.log", gcLogMinEvents))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := writeSyntheticGCLog(path, gcLogTemplate, gcLogMinEvents); err != nil {
+		return "", fmt.Errorf("failed to write synthetic GC log: %w", err)
+	}
+	return path, nil
+}
+
+func writeSyntheticGCLog(path, template string, minEvents int) error {
+	data, err := os.ReadFile(template)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	// The JVM start-up lines come once, the collections after them repeat
+	first := len(lines)
+	events, span := 0, 0.0
+	for i, line := range lines {
+		if match := gcLogID.FindStringSubmatch(line); match != nil {
+			first = min(first, i)
+			id, _ := strconv.Atoi(match[1])
+			events = max(events, id+1)
+		}
+		if match := gcLogDecorations.FindStringSubmatch(line); match != nil {
+			uptime, _ := strconv.ParseFloat(match[2], 64)
+			span = max(span, uptime)
+		}
+	}
+	if events == 0 {
+		return fmt.Errorf("no collections in %s", template)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	out := bufio.NewWriter(file)
+
+	for _, line := range lines[:first] {
+		fmt.Fprintln(out, line)
+	}
+	for replay := 0; replay*events < minEvents; replay++ {
+		shift := span * float64(replay)
+		for _, line := range lines[first:] {
+			fmt.Fprintln(out, replayLine(line, replay*events, shift))
+		}
+	}
+	return out.Flush()
+}
+
+// replayLine renumbers a log line's collection by offset and moves its timestamps shift seconds on
+func replayLine(line string, offset int, shift float64) string {
+	if offset == 0 {
+		return line
+	}
+
+	line = gcLogID.ReplaceAllStringFunc(line, func(id string) string {
+		n, _ := strconv.Atoi(id[3 : len(id)-1])
+		return fmt.Sprintf("GC(%d)", n+offset)
+	})
+
+	match := gcLogDecorations.FindStringSubmatch(line)
+	if match == nil {
+		return line
+	}
+	uptime, _ := strconv.ParseFloat(match[2], 64)
+	decorations := fmt.Sprintf("[%s][%.3fs]", match[1], uptime+shift)
+	if wallClock, err := time.Parse(gcLogTimeLayout, match[1]); err == nil {
+		shifted := wallClock.Add(time.Duration(shift * float64(time.Second)))
+		decorations = fmt.Sprintf("[%s][%.3fs]", shifted.Format(gcLogTimeLayout), uptime+shift)
+	}
+	return decorations + line[len(match[0]):]
+}
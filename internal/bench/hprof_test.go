@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"math/rand/v2"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/testdump"
+)
+
+/*
+ * Synthetic heap dump for the HPROF benchmarks. Checking in a real dump
+ * would add megabytes to the repo, so one is written on the fly instead,
+ * always the same for a given size.
+ *
+ * The heap is a cache reachable from a JNI global root: an Object[] of
+ * HashMap$Node chains, each node keying a String to an Order, and every
+ * Order holding its customer String and an Object[] of items. Strings keep
+ * their characters in byte[] values, so the dump has the instance, object
+ * array and primitive array mix a real service heap has, and the analyzers
+ * get shared, chained and dominated objects to work through.
+ */
+
+const dumpIDSize = 8
+
+// writeSyntheticDump writes a dump with about entries cache entries to path
+func writeSyntheticDump(path string, entries int) error {
+	return testdump.WriteFile(path, dumpIDSize, func(w *testdump.Writer) {
+		object := w.Class("java.lang.Object", nil)
+		byteArray := w.Class("[B", object)
+		objectArray := w.Class("[Ljava.lang.Object;", object)
+		str := w.Class("java.lang.String", object,
+			testdump.Field{Name: "value", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "hash", Type: model.HPROF_INT},
+			testdump.Field{Name: "coder", Type: model.HPROF_BYTE})
+		node := w.Class("java.util.HashMap$Node", object,
+			testdump.Field{Name: "hash", Type: model.HPROF_INT}, testdump.Field{Name: "key", Type: model.HPROF_NORMAL_OBJECT},
+			testdump.Field{Name: "value", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "next", Type: model.HPROF_NORMAL_OBJECT})
+		order := w.Class("com.example.Order", object,
+			testdump.Field{Name: "id", Type: model.HPROF_LONG}, testdump.Field{Name: "customer", Type: model.HPROF_NORMAL_OBJECT},
+			testdump.Field{Name: "items", Type: model.HPROF_NORMAL_OBJECT})
+		cache := w.Class("com.example.OrderCache", object,
+			testdump.Field{Name: "table", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "size", Type: model.HPROF_INT})
+		for _, class := range []*testdump.Class{object, byteArray, objectArray, str, node, order, cache} {
+			w.Root(model.HPROF_GC_ROOT_STICKY_CLASS, class.ID)
+		}
+
+		rng := rand.New(rand.NewPCG(1, 2))
+		newString := func(length int) model.ID {
+			chars := make([]byte, length)
+			for i := range chars {
+				chars[i] = 'a' + byte(rng.IntN(26))
+			}
+			return w.StringWithHash(string(chars), int32(rng.Uint32()))
+		}
+
+		// A few customers share most orders, as in a real cache
+		customers := make([]model.ID, max(entries/50, 1))
+		for i := range customers {
+			customers[i] = newString(8 + rng.IntN(24))
+		}
+
+		buckets := make([]model.ID, max(entries/4, 1))
+		for i := range entries {
+			items := make([]model.ID, rng.IntN(6))
+			for j := range items {
+				items[j] = newString(4 + rng.IntN(60))
+			}
+			itemsID := w.ObjectArray(objectArray, items...)
+			orderID := w.Instance(order, int64(i), customers[rng.IntN(len(customers))], itemsID)
+
+			key := newString(12)
+			bucket := rng.IntN(len(buckets))
+			buckets[bucket] = w.Instance(node, int32(i), key, orderID, buckets[bucket])
+		}
+
+		table := w.ObjectArray(objectArray, buckets...)
+		w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, w.Instance(cache, table, int32(entries)))
+	})
+}
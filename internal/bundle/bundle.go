@@ -0,0 +1,310 @@
+package bundle
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/convert"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/gc/tui"
+	"github.com/mabhi256/jdiag/utils"
+	"github.com/muesli/termenv"
+)
+
+/*
+ * Bundles
+ *
+ * `jdiag bundle` packs what a support engineer asks for first into one zip
+ * to attach to a ticket:
+ *
+ * 	gc.log			the log, decompressed, and scrubbed with --scrub
+ * 	analysis.json		the analysis and issues found, as JSON
+ * 	events.csv		one row per collection, as 'jdiag convert' writes
+ * 	environment.json	where the log and the bundle came from
+ * 	charts/<tab>.svg	each tab of the GC dashboard as an image
+ * 	README.txt		what's in the bundle
+ *
+ * Scrubbing covers the log and the name it had; the analysis and charts
+ * are numbers and GC causes, which say nothing about the machine.
+ */
+
+const (
+	chartWidth  = 160
+	chartHeight = 48
+)
+
+// Environment is environment.json: where the log came from and what it held
+type Environment struct {
+	Generated time.Time `json:"generated"`
+	Jdiag     string    `json:"jdiag"`
+	Platform  string    `json:"platform"` // Where the bundle was made, not the JVM's
+
+	Source       string `json:"source"`
+	SourceBytes  int64  `json:"sourceBytes"`
+	SourceSHA256 string `json:"sourceSha256"` // Of the file as given, before decompressing or scrubbing
+
+	JVMVersion     string    `json:"jvmVersion,omitempty"`
+	Collector      string    `json:"collector,omitempty"`
+	HeapMax        int64     `json:"heapMax,omitempty"`
+	HeapRegionSize int64     `json:"heapRegionSize,omitempty"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	Events         int       `json:"events"`
+	SkippedLines   int       `json:"skippedLines"`
+
+	Scrubbed bool          `json:"scrubbed"`
+	Scrub    gc.ScrubStats `json:"scrub,omitempty"`
+}
+
+// Options control what Write puts in the bundle
+type Options struct {
+	Scrub   bool   // Replace hostnames, paths, addresses and system properties in the log
+	Version string // jdiag's version, for environment.json
+}
+
+// Result lists what Write put in the bundle
+type Result struct {
+	Path     string
+	Files    []string
+	Scrubbed gc.ScrubStats // nil without Options.Scrub
+}
+
+// Write packs the log at logFile and its parsed events and analysis into a zip at path
+func Write(path, logFile string, events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues,
+	opts Options) (*Result, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	result, err := write(file, logFile, events, analysis, issues, opts)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write bundle: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(path) // A partial zip only gets attached by mistake
+		return nil, err
+	}
+	result.Path = path
+	return result, nil
+}
+
+func write(w io.Writer, logFile string, events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues,
+	opts Options) (*Result, error) {
+	archive := zip.NewWriter(w)
+	result := &Result{}
+	now := time.Now()
+
+	add := func(name string, fill func(io.Writer) error) error {
+		entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if err := fill(entry); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		result.Files = append(result.Files, name)
+		return nil
+	}
+
+	env, err := newEnvironment(logFile, analysis, len(events), opts, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = add("gc.log", func(entry io.Writer) error {
+		stats, err := copyLog(entry, logFile, opts.Scrub)
+		env.Scrub = stats
+		result.Scrubbed = stats
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = add("analysis.json", func(entry io.Writer) error {
+		return writeJSON(entry, gc.NewReport(analysis, issues))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = add("events.csv", func(entry io.Writer) error {
+		return convert.WriteEventsCSV(entry, gc.NewReportEvents(events))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = add("environment.json", func(entry io.Writer) error {
+		return writeJSON(entry, env)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tab := range renderCharts(events, analysis, issues) {
+		err = add("charts/"+tab.Name+".svg", func(entry io.Writer) error {
+			_, err := io.WriteString(entry, utils.RenderSVG(tab.View))
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = add("README.txt", func(entry io.Writer) error {
+		_, err := io.WriteString(entry, readme(env, result.Files))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return result, nil
+}
+
+func newEnvironment(logFile string, analysis *gc.GCAnalysis, events int, opts Options, now time.Time) (*Environment, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	// The name a log was given often holds the host or service it came from
+	source := filepath.Base(logFile)
+	if opts.Scrub {
+		source = "gc" + logExtension(logFile)
+	}
+
+	return &Environment{
+		Generated:      now,
+		Jdiag:          opts.Version,
+		Platform:       runtime.GOOS + "/" + runtime.GOARCH,
+		Source:         source,
+		SourceBytes:    size,
+		SourceSHA256:   hex.EncodeToString(hash.Sum(nil)),
+		JVMVersion:     analysis.JVMVersion,
+		Collector:      analysis.Collector,
+		HeapMax:        analysis.HeapMax.Bytes(),
+		HeapRegionSize: analysis.HeapRegionSize.Bytes(),
+		StartTime:      analysis.StartTime,
+		EndTime:        analysis.EndTime,
+		Events:         events,
+		SkippedLines:   analysis.SkippedLines,
+		Scrubbed:       opts.Scrub,
+	}, nil
+}
+
+func logExtension(logFile string) string {
+	if strings.HasSuffix(logFile, ".gz") {
+		return filepath.Ext(strings.TrimSuffix(logFile, ".gz")) + ".gz"
+	}
+	return filepath.Ext(logFile)
+}
+
+// copyLog writes the log decompressed, so the bundle's zip compression isn't wasted on gzip
+func copyLog(w io.Writer, logFile string, scrub bool) (gc.ScrubStats, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(logFile, ".gz") {
+		gzReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip stream: %w", err)
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
+
+	if scrub {
+		return gc.Scrub(reader, w)
+	}
+	_, err = io.Copy(w, reader)
+	return nil, err
+}
+
+/*
+ * renderCharts renders the dashboard's tabs in color whatever stdout is, as
+ * the images are looked at later, elsewhere. A monochrome theme (NO_COLOR,
+ * --theme mono) is still respected.
+ */
+func renderCharts(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues) []tui.TabView {
+	if !utils.CurrentTheme().Monochrome {
+		profile := lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		defer lipgloss.SetColorProfile(profile)
+	}
+	return tui.RenderTabs(events, analysis, issues, chartWidth, chartHeight)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func readme(env *Environment, files []string) string {
+	descriptions := map[string]string{
+		"gc.log":           "The GC log",
+		"analysis.json":    "Summary, pauses, trends and issues found, as JSON",
+		"events.csv":       "One row per collection",
+		"environment.json": "Where the log and this bundle came from",
+		"README.txt":       "This file",
+	}
+	if env.Scrubbed {
+		descriptions["gc.log"] = "The GC log, with hostnames, paths, addresses and system properties replaced"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "GC diagnostics bundle made by jdiag %s on %s\n\n", env.Jdiag, env.Generated.Format("2006-01-02 15:04:05 MST"))
+	if env.JVMVersion != "" {
+		fmt.Fprintf(&sb, "JVM:     %s\n", env.JVMVersion)
+	}
+	if env.Collector != "" {
+		fmt.Fprintf(&sb, "GC:      %s\n", env.Collector)
+	}
+	fmt.Fprintf(&sb, "Events:  %s", utils.FormatCount(int64(env.Events)))
+	if !env.StartTime.IsZero() {
+		fmt.Fprintf(&sb, " from %s to %s", env.StartTime.Format(time.RFC3339), env.EndTime.Format(time.RFC3339))
+	}
+	sb.WriteString("\n")
+	if env.Scrubbed {
+		fmt.Fprintf(&sb, "Scrubbed: %s\n", env.Scrub)
+	}
+
+	sb.WriteString("\nContents:\n")
+	for _, name := range append(files, "README.txt") {
+		description, ok := descriptions[name]
+		if !ok && strings.HasPrefix(name, "charts/") {
+			description = "The " + strings.TrimSuffix(strings.TrimPrefix(name, "charts/"), ".svg") + " tab of 'jdiag gc', as an image"
+		}
+		fmt.Fprintf(&sb, "  %-22s %s\n", name, description)
+	}
+	sb.WriteString("\nOpen the log again with: jdiag gc gc.log\n")
+	return sb.String()
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	FileName = ".jdiag.yaml"
+
+	// GlobalSection applies to every command that has the flag
+	GlobalSection = "global"
+)
+
+/*
+ * ~/.jdiag.yaml holds flag values per command, keyed by the command path
+ * after "jdiag", either as "gc analyze:" or nested as "gc:" / "analyze:".
+ * Profiles layer over the defaults:
+ *
+ *   defaults:
+ *     global:
+ *       notify: slack://hooks.slack.com/services/...
+ *     watch:
+ *       interval: 2000
+ *     gc analyze:
+ *       output: cli-more
+ *   profiles:
+ *     prod-payments:
+ *       watch:
+ *         target: payments-01:9010
+ *         interval: 500
+ *   keymap:
+ *     gc:
+ *       down: [j, down, ctrl+n]
+ *
+ * Flags given on the command line always win. The keymap rebinds TUI
+ * actions, keyed by the TUI and then the action name.
+ */
+type Config struct {
+	Path     string
+	Defaults Sections
+	Profiles map[string]Sections
+	Keymap   Sections
+}
+
+// Sections maps a command path ("watch", "gc analyze", or GlobalSection) to flag values
+type Sections map[string]map[string]string
+
+// DefaultPath is ~/.jdiag.yaml, or "" when the home directory is unknown
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, FileName)
+}
+
+// Load reads a config file; a missing file gives an empty config unless required
+func Load(path string, required bool) (*Config, error) {
+	config := &Config{Path: path, Defaults: Sections{}, Profiles: map[string]Sections{}, Keymap: Sections{}}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) && !required {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open config: %w", err)
+	}
+	defer file.Close()
+
+	document, err := parseYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	for key, value := range document {
+		switch key {
+		case "defaults":
+			if config.Defaults, err = parseSections(value, "defaults"); err != nil {
+				return nil, fmt.Errorf("invalid config %s: %w", path, err)
+			}
+		case "profiles":
+			profiles, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid config %s: 'profiles' must be a mapping of profile names", path)
+			}
+			for name, profile := range profiles {
+				sections, err := parseSections(profile, "profiles."+name)
+				if err != nil {
+					return nil, fmt.Errorf("invalid config %s: %w", path, err)
+				}
+				config.Profiles[name] = sections
+			}
+		case "keymap":
+			if config.Keymap, err = parseSections(value, "keymap"); err != nil {
+				return nil, fmt.Errorf("invalid config %s: %w", path, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid config %s: unknown top-level key '%s' (expected defaults, profiles or keymap)", path, key)
+		}
+	}
+	return config, nil
+}
+
+// parseSections flattens nested command mappings into "gc analyze"-style paths
+func parseSections(value any, where string) (Sections, error) {
+	mapping, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be a mapping of commands", where)
+	}
+
+	sections := Sections{}
+	var flatten func(mapping map[string]any, path string) error
+	flatten = func(mapping map[string]any, path string) error {
+		for key, value := range mapping {
+			switch value := value.(type) {
+			case map[string]any:
+				if err := flatten(value, strings.TrimSpace(path+" "+key)); err != nil {
+					return err
+				}
+			case string:
+				if path == "" {
+					return fmt.Errorf("'%s.%s' must be under a command, e.g. %s: { %s: ... }", where, key, GlobalSection, key)
+				}
+				if sections[path] == nil {
+					sections[path] = map[string]string{}
+				}
+				sections[path][key] = value
+			}
+		}
+		return nil
+	}
+	if err := flatten(mapping, ""); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// Settings merges the values for a command: global then command defaults, then the same from the profile
+func (c *Config) Settings(command, profile string) (global, specific map[string]string, err error) {
+	global = map[string]string{}
+	specific = map[string]string{}
+
+	layers := []Sections{c.Defaults}
+	if profile != "" {
+		sections, ok := c.Profiles[profile]
+		if !ok {
+			return nil, nil, fmt.Errorf("profile '%s' not found in %s (available: %s)", profile, c.displayPath(),
+				strings.Join(c.ProfileNames(), ", "))
+		}
+		layers = append(layers, sections)
+	}
+
+	for _, sections := range layers {
+		for key, value := range sections[GlobalSection] {
+			global[key] = value
+		}
+		for key, value := range sections[command] {
+			specific[key] = value
+		}
+	}
+	return global, specific, nil
+}
+
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) displayPath() string {
+	if c.Path == "" {
+		return "config"
+	}
+	return c.Path
+}
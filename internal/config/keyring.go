@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringService is the service saved target passwords are filed under
+const keyringService = "jdiag"
+
+/*
+ * The OS keyring is reached through its command-line tool rather than a
+ * library: security on macOS and secret-tool (libsecret) on Linux. Windows
+ * has no tool that reads a stored password back, so keyring targets aren't
+ * supported there; JDIAG_JMX_PASSWORD works everywhere.
+ *
+ * The password never goes in argv, where any local user could read it with
+ * ps. secret-tool reads it from stdin. security only takes it as an
+ * argument, so it's run in interactive mode (security -i) instead, which
+ * reads whole commands from stdin: the argument is quoted into the command
+ * line written there. A command failing there doesn't fail security, so the
+ * password is read back to check it was saved.
+ */
+
+// StorePassword saves the target's password in the OS keyring, replacing any saved before
+func StorePassword(target, password string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		command, err := securityCommand("add-generic-password", "-U", "-s", keyringService, "-a", target, "-w", password)
+		if err != nil {
+			return err
+		}
+		if err := runKeyring(strings.NewReader(command), "security", "-i"); err != nil {
+			return err
+		}
+		if saved, err := LookupPassword(target); err != nil || saved != password {
+			return fmt.Errorf("security didn't save the password for target '%s'", target)
+		}
+		return nil
+	case "linux":
+		return runKeyring(strings.NewReader(password), "secret-tool", "store", "--label", "jdiag target "+target,
+			"service", keyringService, "target", target)
+	}
+	return fmt.Errorf("no OS keyring support on %s; set JDIAG_JMX_PASSWORD instead", runtime.GOOS)
+}
+
+// LookupPassword reads the target's password from the OS keyring
+func LookupPassword(target string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", target, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "target", target)
+	default:
+		return "", fmt.Errorf("no OS keyring support on %s; set JDIAG_JMX_PASSWORD instead", runtime.GOOS)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no password for target '%s' in the keyring: %w", target, err)
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+// DeletePassword removes the target's password from the OS keyring
+func DeletePassword(target string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return runKeyring(nil, "security", "delete-generic-password", "-s", keyringService, "-a", target)
+	case "linux":
+		return runKeyring(nil, "secret-tool", "clear", "service", keyringService, "target", target)
+	}
+	return nil
+}
+
+// securityCommand is one line for security -i, each argument double-quoted
+func securityCommand(args ...string) (string, error) {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, "\r\n") {
+			return "", fmt.Errorf("keyring values can't contain line breaks")
+		}
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+	}
+	return strings.Join(quoted, " ") + "\n", nil
+}
+
+func runKeyring(stdin *strings.Reader, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
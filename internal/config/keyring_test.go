@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestSecurityCommand(t *testing.T) {
+	tests := []struct {
+		args    []string
+		command string
+		wantErr bool
+	}{
+		{args: []string{"add-generic-password", "-w", "plain"}, command: `"add-generic-password" "-w" "plain"` + "\n"},
+		{args: []string{"-w", `pa ss"wo\rd`}, command: `"-w" "pa ss\"wo\\rd"` + "\n"},
+		{args: []string{"-w", "two\nlines"}, wantErr: true},
+		{args: []string{"-w", "carriage\rreturn"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		command, err := securityCommand(test.args...)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: got %q, want an error", test.args, command)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.args, err)
+		} else if command != test.command {
+			t.Errorf("%q: got %q, want %q", test.args, command, test.command)
+		}
+	}
+}
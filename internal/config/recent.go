@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const maxRecentTargets = 10
+
+// RecentPath keeps the targets jdiag connected to next to the config file, e.g. ~/.jdiag.recent
+func RecentPath(configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".recent"
+}
+
+// RecentTargets lists HOST:PORT targets, most recent first; a missing file has none
+func RecentTargets(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recent targets: %w", err)
+	}
+	defer file.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if target := strings.TrimSpace(scanner.Text()); target != "" && !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recent targets: %w", err)
+	}
+	return targets, nil
+}
+
+// AddRecentTarget moves the target to the top of the list, keeping the last maxRecentTargets
+func AddRecentTarget(path, target string) error {
+	targets, err := RecentTargets(path)
+	if err != nil {
+		return err
+	}
+
+	targets = slices.DeleteFunc(targets, func(t string) bool { return t == target })
+	targets = append([]string{target}, targets...)
+	if len(targets) > maxRecentTargets {
+		targets = targets[:maxRecentTargets]
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(targets, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("unable to save recent targets: %w", err)
+	}
+	return nil
+}
+
+// Targets maps each "target" set for a command to where it's set, e.g. "profile prod-payments"
+func (c *Config) Targets(command string) map[string]string {
+	targets := map[string]string{}
+	if target := c.Defaults[command]["target"]; target != "" {
+		targets[target] = "defaults"
+	}
+
+	for _, name := range c.ProfileNames() {
+		if target := c.Profiles[name][command]["target"]; target != "" {
+			if _, exists := targets[target]; !exists {
+				targets[target] = "profile " + name
+			}
+		}
+	}
+	return targets
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+ * Targets are JMX endpoints saved under a nickname with 'jdiag targets add',
+ * kept next to the config file (e.g. ~/.jdiag.targets) in the same YAML subset:
+ *
+ *   prod-api:
+ *     host: 10.0.0.5
+ *     port: 9010
+ *     ssl: true
+ *     user: monitor
+ *     keyring: true
+ *
+ * Passwords never go in the file: a keyring target's is in the OS keyring
+ * under its nickname, any other's comes from JDIAG_JMX_PASSWORD.
+ */
+
+// Target is a saved JMX endpoint
+type Target struct {
+	Name    string
+	Host    string
+	Port    int
+	SSL     bool   // The RMI registry and connector use SSL
+	User    string // JMX username, "" without authentication
+	Keyring bool   // The password is in the OS keyring under Name
+}
+
+var targetNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9._-]*$`)
+
+// Address is the target's HOST:PORT
+func (t Target) Address() string {
+	return fmt.Sprintf("%s:%d", t.Host, t.Port)
+}
+
+// Validate checks the target can be saved and read back
+func (t Target) Validate() error {
+	if !targetNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("invalid target name '%s': start with a letter, then letters, digits, '.', '_' or '-'", t.Name)
+	}
+	if t.Host == "" || strings.ContainsAny(t.Host, " \t#:'\"[]") {
+		return fmt.Errorf("invalid host '%s'", t.Host)
+	}
+	if t.Port <= 0 || t.Port > 65535 {
+		return fmt.Errorf("invalid port %d", t.Port)
+	}
+	if strings.ContainsAny(t.User, " \t#:'\"[]") { // Would read back as quoted or a list
+		return fmt.Errorf("invalid user '%s'", t.User)
+	}
+	if t.Keyring && t.User == "" {
+		return fmt.Errorf("a keyring password needs a user")
+	}
+	return nil
+}
+
+// TargetsPath keeps the saved targets next to the config file, e.g. ~/.jdiag.targets
+func TargetsPath(configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".targets"
+}
+
+// LoadTargets reads the saved targets by name; a missing file has none
+func LoadTargets(path string) (map[string]Target, error) {
+	targets := map[string]Target{}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return targets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open targets: %w", err)
+	}
+	defer file.Close()
+
+	document, err := parseYAML(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid targets %s: %w", path, err)
+	}
+	for name, value := range document {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid targets %s: '%s' must be a mapping", path, name)
+		}
+		target := Target{Name: name}
+		for key, value := range fields {
+			text, _ := value.(string)
+			switch key {
+			case "host":
+				target.Host = text
+			case "port":
+				target.Port, err = strconv.Atoi(text)
+			case "ssl":
+				target.SSL, err = strconv.ParseBool(text)
+			case "user":
+				target.User = text
+			case "keyring":
+				target.Keyring, err = strconv.ParseBool(text)
+			default:
+				return nil, fmt.Errorf("invalid targets %s: unknown key '%s.%s'", path, name, key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid targets %s: invalid '%s.%s': %w", path, name, key, err)
+			}
+		}
+		if err := target.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid targets %s: %w", path, err)
+		}
+		targets[name] = target
+	}
+	return targets, nil
+}
+
+// SaveTargets writes the targets sorted by name, readable only by the user
+func SaveTargets(path string, targets map[string]Target) error {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var text strings.Builder
+	for _, name := range names {
+		target := targets[name]
+		fmt.Fprintf(&text, "%s:\n  host: %s\n  port: %d\n", name, target.Host, target.Port)
+		if target.SSL {
+			text.WriteString("  ssl: true\n")
+		}
+		if target.User != "" {
+			fmt.Fprintf(&text, "  user: %s\n", target.User)
+		}
+		if target.Keyring {
+			text.WriteString("  keyring: true\n")
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(text.String()), 0o600); err != nil {
+		return fmt.Errorf("unable to save targets: %w", err)
+	}
+	return nil
+}
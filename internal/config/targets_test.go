@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTargetsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".jdiag.targets")
+	targets := map[string]Target{
+		"prod-api":  {Name: "prod-api", Host: "10.0.0.5", Port: 9010, SSL: true, User: "monitor", Keyring: true},
+		"local":     {Name: "local", Host: "localhost", Port: 9999},
+		"batch.eu1": {Name: "batch.eu1", Host: "batch-1.eu.internal", Port: 1099, User: "ops-user"},
+	}
+	if err := SaveTargets(path, targets); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadTargets(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, targets) {
+		t.Errorf("LoadTargets() = %+v, want %+v", got, targets)
+	}
+}
+
+func TestLoadTargetsRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // In the error
+	}{
+		{"not a mapping", "prod: 10.0.0.5\n", "'prod' must be a mapping"},
+		{"unknown key", "prod:\n  host: a\n  port: 1\n  password: x\n", "unknown key 'prod.password'"},
+		{"port not a number", "prod:\n  host: a\n  port: ninety\n", "invalid 'prod.port'"},
+		{"ssl not a bool", "prod:\n  host: a\n  port: 1\n  ssl: maybe\n", "invalid 'prod.ssl'"},
+		{"no host", "prod:\n  port: 1\n", "invalid host"},
+		{"port out of range", "prod:\n  host: a\n  port: 70000\n", "invalid port"},
+		{"bad name", "1prod:\n  host: a\n  port: 1\n", "invalid target name"},
+		{"keyring without user", "prod:\n  host: a\n  port: 1\n  keyring: true\n", "needs a user"},
+		{"not YAML", "prod:\n\thost: a\n", "tabs are not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".jdiag.targets")
+			if err := os.WriteFile(path, []byte(tt.input), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadTargets(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadTargets() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetValidateRejectsUnreadableValues(t *testing.T) {
+	for _, target := range []Target{
+		{Name: "a", Host: "host # x", Port: 1},
+		{Name: "a", Host: "[::1]", Port: 1},
+		{Name: "a", Host: "h", Port: 1, User: "'quoted'"},
+		{Name: "a", Host: "h", Port: 1, User: "[list]"},
+	} {
+		if err := target.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error: it wouldn't read back the same", target)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*
+ * A small YAML subset, enough for a settings file without a YAML dependency:
+ *
+ *   key: value            scalars, optionally 'single' or "double" quoted
+ *   key:                  nested mappings, by indentation (spaces only)
+ *     child: value
+ *   key: [a, b]           lists, inline or as "- item" lines, become
+ *   key:                  comma-separated values, which slice flags accept
+ *     - a
+ *   # comment             full-line and trailing comments
+ *
+ * Anchors, multi-line strings and flow mappings are not supported.
+ */
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// parseYAML returns nested map[string]any whose leaves are strings
+func parseYAML(reader io.Reader) (map[string]any, error) {
+	var lines []yamlLine
+	scanner := bufio.NewScanner(reader)
+	for number := 1; scanner.Scan(); number++ {
+		raw := scanner.Text()
+		text := strings.TrimRight(stripComment(raw), " \t")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", number)
+		}
+		lines = append(lines, yamlLine{number: number, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	result, next, err := parseMapping(lines, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[next].number)
+	}
+	return result, nil
+}
+
+// parseMapping reads the keys at exactly indent, starting at lines[start]
+func parseMapping(lines []yamlLine, start, indent int) (map[string]any, int, error) {
+	result := make(map[string]any)
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, i, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+
+		key, value, found := strings.Cut(line.text, ":")
+		if !found || strings.HasPrefix(line.text, "- ") {
+			return nil, i, fmt.Errorf("line %d: expected 'key: value'", line.number)
+		}
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if _, exists := result[key]; exists {
+			return nil, i, fmt.Errorf("line %d: duplicate key '%s'", line.number, key)
+		}
+		i++
+
+		switch {
+		case value != "":
+			result[key] = parseScalar(value)
+
+		case i < len(lines) && lines[i].indent > indent && strings.HasPrefix(lines[i].text, "- "):
+			var items []string
+			childIndent := lines[i].indent
+			for i < len(lines) && lines[i].indent == childIndent && strings.HasPrefix(lines[i].text, "- ") {
+				items = append(items, unquote(strings.TrimSpace(lines[i].text[2:])))
+				i++
+			}
+			result[key] = strings.Join(items, ",")
+
+		case i < len(lines) && lines[i].indent > indent:
+			child, next, err := parseMapping(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, next, err
+			}
+			result[key] = child
+			i = next
+
+		default:
+			result[key] = "" // "key:" with nothing under it
+		}
+	}
+	return result, i, nil
+}
+
+func parseScalar(value string) string {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var items []string
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, unquote(item))
+			}
+		}
+		return strings.Join(items, ",")
+	}
+	return unquote(value)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// stripComment drops a # comment that starts a line or follows whitespace, outside quotes
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]any
+	}{
+		{
+			name:  "scalars",
+			input: "interval: 500\nnotify: slack://hooks.slack.com/x\n",
+			want:  map[string]any{"interval": "500", "notify": "slack://hooks.slack.com/x"},
+		},
+		{
+			name:  "quoted",
+			input: "single: 'a b'\ndouble: \"c: d\"\n'quoted key': x\n",
+			want:  map[string]any{"single": "a b", "double": "c: d", "quoted key": "x"},
+		},
+		{
+			name:  "nested mappings",
+			input: "defaults:\n  gc:\n    analyze:\n      output: cli-more\n  watch:\n    interval: 2000\n",
+			want: map[string]any{"defaults": map[string]any{
+				"gc":    map[string]any{"analyze": map[string]any{"output": "cli-more"}},
+				"watch": map[string]any{"interval": "2000"},
+			}},
+		},
+		{
+			name:  "inline list",
+			input: "down: [j, 'down', \"ctrl+n\", ]\n",
+			want:  map[string]any{"down": "j,down,ctrl+n"},
+		},
+		{
+			name:  "block list",
+			input: "keymap:\n  down:\n    - j\n    - 'ctrl+n'\n  up: k\n",
+			want:  map[string]any{"keymap": map[string]any{"down": "j,ctrl+n", "up": "k"}},
+		},
+		{
+			name:  "comments",
+			input: "# config\nkey: value # trailing\nurl: http://x/#anchor\nquoted: 'a # b'\n\n   # indented comment\n",
+			want:  map[string]any{"key": "value", "url": "http://x/#anchor", "quoted": "a # b"},
+		},
+		{
+			name:  "empty value",
+			input: "global:\nwatch:\n  interval: 1\n",
+			want:  map[string]any{"global": "", "watch": map[string]any{"interval": "1"}},
+		},
+		{
+			name:  "dedent to a parent",
+			input: "a:\n  b:\n    c: 1\n  d: 2\ne: 3\n",
+			want:  map[string]any{"a": map[string]any{"b": map[string]any{"c": "1"}, "d": "2"}, "e": "3"},
+		},
+		{
+			name:  "empty document",
+			input: "\n# nothing\n",
+			want:  map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("parseYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // In the error
+	}{
+		{"tab indentation", "a:\n\tb: 1\n", "line 2: tabs are not allowed"},
+		{"tab after spaces", "a:\n  \tb: 1\n", "line 2: tabs are not allowed"},
+		{"indented first key", "  a: 1\n", "line 1: unexpected indentation"},
+		{"deeper sibling", "a: 1\n  b: 2\n", "line 2: unexpected indentation"},
+		{"dedent between levels", "a:\n    b: 1\n  c: 2\n", "line 3: unexpected indentation"},
+		{"no colon", "a: 1\njust text\n", "line 2: expected 'key: value'"},
+		{"list item as key", "- a: 1\n", "line 1: expected 'key: value'"},
+		{"list without key", "a: 1\n- b\n", "line 2: expected 'key: value'"},
+		{"list continued deeper", "a:\n  - x\n    y: 1\n", "line 3: unexpected indentation"},
+		{"mixed list and mapping", "a:\n  - x\n  b: 1\n", "line 3: unexpected indentation"},
+		{"duplicate key", "a: 1\nb: 2\na: 3\n", "line 3: duplicate key 'a'"},
+		{"duplicate nested key", "a:\n  b: 1\n  b: 2\n", "line 3: duplicate key 'b'"},
+		{"line too long", "a: " + strings.Repeat("x", 70*1024) + "\n", "failed to read config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatalf("parseYAML() = %#v, want an error containing %q", got, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseYAML() error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package convert
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+)
+
+// WriteEventsCSV writes one row per collection, in the columns of the report's events
+func WriteEventsCSV(w io.Writer, events []gc.ReportEvent) error {
+	writer := csv.NewWriter(w)
+	header := []string{"id", "time", "type", "subtype", "cause", "heap_before_bytes", "heap_after_bytes", "heap_total_bytes", "duration_ms"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, event := range events {
+		writer.Write([]string{
+			strconv.Itoa(event.ID),
+			event.Timestamp.Format(time.RFC3339Nano),
+			event.Type,
+			event.Subtype,
+			event.Cause,
+			strconv.FormatInt(event.HeapBefore, 10),
+			strconv.FormatInt(event.HeapAfter, 10),
+			strconv.FormatInt(event.HeapTotal, 10),
+			strconv.FormatFloat(event.DurationMs, 'f', 3, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteEventsNDJSON writes one JSON object per line and collection, for log shippers and jq
+func WriteEventsNDJSON(w io.Writer, events []gc.ReportEvent) error {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,227 @@
+package convert
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/mabhi256/jdiag/internal/watch"
+)
+
+/*
+ * Prometheus remote write
+ *
+ * A saved watch session becomes the request bodies a remote-write receiver
+ * (Prometheus with --web.enable-remote-write-receiver, Mimir, Thanos,
+ * VictoriaMetrics, ...) accepts, one file per request: a WriteRequest
+ * protobuf, snappy block compressed. Each holds at most ChunkSamples
+ * samples, and is sent with
+ *
+ * 	curl --data-binary @chunk-00001.pb.snappy -H 'Content-Encoding: snappy' \
+ * 		-H 'Content-Type: application/x-protobuf' \
+ * 		-H 'X-Prometheus-Remote-Write-Version: 0.1.0' http://prometheus:9090/api/v1/write
+ *
+ * Every history value is a gauge named jdiag_<series>_<value>, e.g.
+ * jdiag_heap_used_mb, and every GC event adds a pause and a collected
+ * sample labelled with its generation. All carry job="jdiag" and the
+ * watched target as instance. RemoteWriteOptions swaps the jdiag prefix for
+ * another namespace and adds static labels (service, env, ...) to every
+ * series, so a fleet's sessions aggregate on one dashboard; a static job or
+ * instance replaces the default one. The protobuf is written by hand: the
+ * three messages involved don't warrant a code generator.
+ *
+ * 	WriteRequest	1: repeated TimeSeries
+ * 	TimeSeries		1: repeated Label, 2: repeated Sample
+ * 	Label			1: name, 2: value
+ * 	Sample			1: double value, 2: int64 timestamp in ms
+ */
+
+// ChunkSamples is the most samples one request file holds, Prometheus's default max_samples_per_send
+const ChunkSamples = 2000
+
+// DefaultNamespace prefixes every metric name unless RemoteWriteOptions gives another
+const DefaultNamespace = "jdiag"
+
+var (
+	metricNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// RemoteWriteOptions names the metrics and labels every series
+type RemoteWriteOptions struct {
+	Namespace string            // Metric name prefix, DefaultNamespace when empty
+	Labels    map[string]string // Static labels added to every series
+}
+
+// Validate checks the namespace and labels are valid Prometheus names
+func (o RemoteWriteOptions) Validate() error {
+	if o.Namespace != "" && !labelNamePattern.MatchString(o.Namespace) {
+		return fmt.Errorf("invalid namespace '%s': use letters, digits and '_', not starting with a digit", o.Namespace)
+	}
+	for name := range o.Labels {
+		if !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid label name '%s': use letters, digits and '_', not starting with a digit or '__'", name)
+		}
+		if name == "generation" {
+			return fmt.Errorf("label 'generation' is set on GC events and can't be static")
+		}
+	}
+	return nil
+}
+
+type label struct{ name, value string }
+
+type sample struct {
+	value float64
+	at    time.Time
+}
+
+type series struct {
+	key     string
+	labels  []label // Sorted by name, as receivers expect
+	samples []sample
+}
+
+// WriteRemoteWrite writes a watch session as remote-write request files in dir and returns their paths
+func WriteRemoteWrite(dir string, export *watch.WatchExport, options RemoteWriteOptions) ([]string, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create output directory: %w", err)
+	}
+
+	var request []byte
+	var paths []string
+	samples := 0
+	flush := func() error {
+		if samples == 0 {
+			return nil
+		}
+		path := filepath.Join(dir, fmt.Sprintf("chunk-%05d.pb.snappy", len(paths)+1))
+		if err := os.WriteFile(path, snappy.Encode(nil, request), 0o644); err != nil {
+			return fmt.Errorf("unable to write chunk: %w", err)
+		}
+		paths = append(paths, path)
+		request, samples = request[:0], 0
+		return nil
+	}
+
+	for _, s := range exportSeries(export, options) {
+		for start := 0; start < len(s.samples); {
+			end := min(len(s.samples), start+ChunkSamples-samples)
+			request = appendMessage(request, 1, appendTimeSeries(nil, s.labels, s.samples[start:end]))
+			samples += end - start
+			start = end
+			if samples == ChunkSamples {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// exportSeries turns the history and GC events into time series, each sorted by time
+func exportSeries(export *watch.WatchExport, options RemoteWriteOptions) []*series {
+	namespace := cmp.Or(options.Namespace, DefaultNamespace)
+	static := map[string]string{"instance": export.Target, "job": "jdiag"}
+	maps.Copy(static, options.Labels)
+
+	byName := make(map[string]*series)
+	add := func(name string, value float64, at time.Time, extra ...label) {
+		labels := []label{{"__name__", namespace + "_" + name}}
+		for labelName, labelValue := range static {
+			labels = append(labels, label{labelName, labelValue})
+		}
+		labels = append(labels, extra...)
+		sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+		var key strings.Builder
+		for _, l := range labels {
+			key.WriteString(l.name + "\x00" + l.value + "\x00")
+		}
+		s, ok := byName[key.String()]
+		if !ok {
+			s = &series{key: key.String(), labels: labels}
+			byName[key.String()] = s
+		}
+		s.samples = append(s.samples, sample{value, at})
+	}
+
+	for name, points := range export.History {
+		for _, point := range points {
+			for field, value := range point.Values {
+				add(metricName(name, field), value, point.Time)
+			}
+		}
+	}
+	for _, event := range export.GCEvents {
+		generation := label{"generation", event.Generation}
+		add("gc_pause_seconds", event.Duration.Seconds(), event.Time, generation)
+		add("gc_collected_bytes", float64(event.Collected), event.Time, generation)
+	}
+
+	all := make([]*series, 0, len(byName))
+	for _, s := range byName {
+		sort.Slice(s.samples, func(i, j int) bool { return s.samples[i].at.Before(s.samples[j].at) })
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].key < all[j].key })
+	return all
+}
+
+// metricName is <series>_<field> in snake case, e.g. heapFloors and floor_mb give heap_floors_floor_mb
+func metricName(series, field string) string {
+	var name strings.Builder
+	for i, r := range series {
+		if unicode.IsUpper(r) && i > 0 {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToLower(r))
+	}
+	name.WriteString("_" + field)
+	return metricNameInvalid.ReplaceAllString(name.String(), "_")
+}
+
+func appendTimeSeries(b []byte, labels []label, samples []sample) []byte {
+	for _, l := range labels {
+		var encoded []byte
+		encoded = appendString(encoded, 1, l.name)
+		encoded = appendString(encoded, 2, l.value)
+		b = appendMessage(b, 1, encoded)
+	}
+	for _, s := range samples {
+		var encoded []byte
+		encoded = binary.AppendUvarint(encoded, 1<<3|1) // Field 1, fixed64
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(s.value))
+		encoded = binary.AppendUvarint(encoded, 2<<3|0) // Field 2, varint
+		encoded = binary.AppendUvarint(encoded, uint64(s.at.UnixMilli()))
+		b = appendMessage(b, 2, encoded)
+	}
+	return b
+}
+
+// appendMessage appends a length-delimited field: a nested message, string or bytes
+func appendMessage(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	return appendMessage(b, field, []byte(s))
+}
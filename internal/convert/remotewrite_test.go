@@ -0,0 +1,103 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/watch"
+)
+
+func TestExportSeriesLabels(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	export := &watch.WatchExport{
+		Target:   "payments-01:9010",
+		History:  map[string][]watch.ExportedPoint{"heap": {{Time: at, Values: map[string]float64{"used_mb": 512}}}},
+		GCEvents: []watch.ExportedGCEvent{{Time: at, Generation: "young", Duration: 20 * time.Millisecond}},
+	}
+
+	tests := []struct {
+		name    string
+		options RemoteWriteOptions
+		want    map[string]string // Labels of the heap series
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{"__name__": "jdiag_heap_used_mb", "instance": "payments-01:9010", "job": "jdiag"},
+		},
+		{
+			name:    "static labels",
+			options: RemoteWriteOptions{Labels: map[string]string{"service": "payments", "env": "prod"}},
+			want: map[string]string{"__name__": "jdiag_heap_used_mb", "instance": "payments-01:9010", "job": "jdiag",
+				"service": "payments", "env": "prod"},
+		},
+		{
+			name:    "namespace and instance override",
+			options: RemoteWriteOptions{Namespace: "payments", Labels: map[string]string{"instance": "pod-7"}},
+			want:    map[string]string{"__name__": "payments_heap_used_mb", "instance": "pod-7", "job": "jdiag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := exportSeries(export, tt.options)
+			if len(all) != 3 {
+				t.Fatalf("got %d series, want heap, pause and collected", len(all))
+			}
+			for _, s := range all {
+				for i := 1; i < len(s.labels); i++ {
+					if s.labels[i-1].name >= s.labels[i].name {
+						t.Errorf("labels not sorted by name: %v", s.labels)
+					}
+				}
+				got := map[string]string{}
+				for _, l := range s.labels {
+					got[l.name] = l.value
+				}
+				for name, value := range tt.options.Labels {
+					if got[name] != value {
+						t.Errorf("%s: label %s = %q, want %q", got["__name__"], name, got[name], value)
+					}
+				}
+				if got["__name__"] != tt.want["__name__"] {
+					if got["generation"] != "young" {
+						t.Errorf("%s: GC event series has no generation label", got["__name__"])
+					}
+					continue
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("heap labels = %v, want %v", got, tt.want)
+				}
+				for name, value := range tt.want {
+					if got[name] != value {
+						t.Errorf("heap label %s = %q, want %q", name, got[name], value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRemoteWriteOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options RemoteWriteOptions
+		valid   bool
+	}{
+		{"empty", RemoteWriteOptions{}, true},
+		{"labels", RemoteWriteOptions{Namespace: "fleet_jvm", Labels: map[string]string{"service": "api", "env": "prod"}}, true},
+		{"namespace with dash", RemoteWriteOptions{Namespace: "my-app"}, false},
+		{"namespace starting with digit", RemoteWriteOptions{Namespace: "1app"}, false},
+		{"reserved label", RemoteWriteOptions{Labels: map[string]string{"__name__": "x"}}, false},
+		{"label with dot", RemoteWriteOptions{Labels: map[string]string{"k8s.pod": "x"}}, false},
+		{"generation label", RemoteWriteOptions{Labels: map[string]string{"generation": "x"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/history"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * Daemon
+ *
+ * `jdiag daemon` watches log directories for GC logs that have been
+ * rotated away (gc.log.0, gc.log.3.gz), but not the gc.log still being
+ * written or a JDK 8 gc.log.2.current. It analyzes each one once, keyed by
+ * content, and stores the result in the history DB. A file modified in the
+ * last settleTime is left for the next poll, since logrotate may still be
+ * compressing it.
+ *
+ * At each period boundary (midnight, or Monday midnight for weekly) it
+ * compares each directory's average health score over the period just
+ * ended with the one before. A digest is posted for every directory that
+ * dropped by Threshold points or more, counting each log towards the period
+ * it ended in. Digests are recorded too, so a restart doesn't post the same
+ * one twice.
+ */
+
+const (
+	PeriodDay  = "day"
+	PeriodWeek = "week"
+
+	DefaultPattern = "*.log.*"
+
+	settleTime = 30 * time.Second
+)
+
+var Periods = []string{PeriodDay, PeriodWeek}
+
+// Config is what the daemon watches and where it reports
+type Config struct {
+	Dirs      []string // Absolute, as they're the history's groups
+	Pattern   string   // Glob for rotated logs in each directory
+	Poll      time.Duration
+	Period    string // PeriodDay or PeriodWeek
+	Threshold int    // Score points a directory must drop by to be posted
+	History   *history.DB
+	Notifier  *notify.Notifier // nil only records and prints
+	Out       io.Writer        // Where activity is logged
+}
+
+type fileState struct {
+	size     int64
+	modified time.Time
+}
+
+// Daemon polls the configured directories and posts digests
+type Daemon struct {
+	config     *Config
+	seen       map[string]fileState // Files looked at, so unchanged ones aren't hashed every poll
+	lastDigest time.Time
+}
+
+func New(config *Config) *Daemon {
+	return &Daemon{
+		config:     config,
+		seen:       make(map[string]fileState),
+		lastDigest: config.History.LastDigest(),
+	}
+}
+
+// Run scans and digests every poll until ctx is done; with once it makes a single pass
+func (d *Daemon) Run(ctx context.Context, once bool) error {
+	for {
+		d.Scan(ctx, time.Now())
+		if ctx.Err() != nil {
+			return nil
+		}
+		d.Digest(time.Now())
+		if once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(d.config.Poll):
+		}
+	}
+}
+
+func (d *Daemon) logf(format string, args ...any) {
+	fmt.Fprintf(d.config.Out, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// Scan analyzes the rotated logs that aren't in the history yet
+func (d *Daemon) Scan(ctx context.Context, now time.Time) {
+	for _, dir := range d.config.Dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, d.config.Pattern))
+		if err != nil {
+			d.logf("⚠️  %s: %v", dir, err)
+			continue
+		}
+
+		for _, path := range matches {
+			if ctx.Err() != nil {
+				return
+			}
+			if strings.HasSuffix(path, ".current") {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || now.Sub(info.ModTime()) < settleTime {
+				continue
+			}
+			state := fileState{size: info.Size(), modified: info.ModTime()}
+			if d.seen[path] == state {
+				continue
+			}
+			d.seen[path] = state
+
+			key, err := gc.CacheKey(path)
+			if err != nil {
+				d.logf("⚠️  %s: %v", path, err)
+				continue
+			}
+			if d.config.History.Analyzed(key) {
+				continue
+			}
+			d.analyze(ctx, dir, path, key, now)
+		}
+	}
+}
+
+func (d *Daemon) analyze(ctx context.Context, dir, path, key string, now time.Time) {
+	parser := gc.NewParser()
+	parser.SetContext(ctx)
+	var cache *gc.Cache // The history keeps what's needed; rotated logs aren't reopened
+	events, analysis, _, err := cache.ParseAndAnalyze(path, parser)
+	if err != nil {
+		d.logf("⚠️  %s: %v", path, err)
+		return
+	}
+	if analysis.Interrupted {
+		return
+	}
+
+	issues := gc.GetRecommendations(analysis)
+	report := gc.NewReport(analysis, issues)
+	record := history.Record{
+		Kind:       history.KindAnalysis,
+		Time:       now,
+		Group:      dir,
+		File:       filepath.Base(path),
+		SHA256:     key,
+		LogStart:   analysis.StartTime,
+		LogEnd:     analysis.EndTime,
+		Events:     len(events),
+		Score:      notify.GCScore(issues),
+		Throughput: report.Throughput,
+		MaxPauseMs: report.Pauses.MaxMs,
+		P99PauseMs: report.Pauses.P99Ms,
+		FullGCs:    report.Events.Full,
+		Critical:   issueTypes(issues.Critical),
+		Warning:    issueTypes(issues.Warning),
+	}
+	if err := d.config.History.Append(record); err != nil {
+		d.logf("❌ %v", err)
+		return
+	}
+
+	if len(events) == 0 {
+		d.logf("⚪ %s: no GC events, left out of the scores", path)
+		return
+	}
+	d.logf("📊 %s: %d/100 (%s), %s events, max pause %s", path, record.Score, notify.ScoreVerdict(record.Score),
+		utils.FormatCount(int64(len(events))), utils.FormatMillis(record.MaxPauseMs))
+}
+
+func issueTypes(issues []gc.PerformanceIssue) []string {
+	var types []string
+	for _, issue := range issues {
+		types = append(types, issue.Type)
+	}
+	return types
+}
@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/history"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// PeriodStart is the start of the day, or of the week from Monday, that now falls in
+func PeriodStart(now time.Time, period string) time.Time {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if period == PeriodWeek {
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	return start
+}
+
+// previousPeriod steps back a day or week by the calendar, so a DST change doesn't shift the boundary
+func previousPeriod(start time.Time, period string) time.Time {
+	if period == PeriodWeek {
+		return start.AddDate(0, 0, -7)
+	}
+	return start.AddDate(0, 0, -1)
+}
+
+// Digest compares the period that just ended with the one before, once per period
+func (d *Daemon) Digest(now time.Time) {
+	end := PeriodStart(now, d.config.Period)
+	if !d.lastDigest.Before(end) {
+		return
+	}
+	d.lastDigest = now
+
+	start := previousPeriod(end, d.config.Period)
+	before := previousPeriod(start, d.config.Period)
+	for _, dir := range d.config.Dirs {
+		current := scored(d.config.History.Records(history.KindAnalysis, dir, start, end))
+		previous := scored(d.config.History.Records(history.KindAnalysis, dir, before, start))
+		if len(current) == 0 {
+			continue
+		}
+
+		record := history.Record{
+			Kind:         history.KindDigest,
+			Time:         now,
+			Group:        dir,
+			Period:       d.config.Period,
+			Score:        averageScore(current),
+			Logs:         len(current),
+			PreviousLogs: len(previous),
+		}
+		if len(previous) > 0 {
+			record.PreviousScore = averageScore(previous)
+			record.Degraded = record.PreviousScore-record.Score >= d.config.Threshold
+		}
+		if err := d.config.History.Append(record); err != nil {
+			d.logf("❌ %v", err)
+		}
+
+		switch {
+		case len(previous) == 0:
+			d.logf("🗓️  %s: %d/100 over %d logs last %s, nothing to compare with", dir, record.Score, record.Logs, d.config.Period)
+		case !record.Degraded:
+			d.logf("🗓️  %s: %d/100 over %d logs last %s, was %d/100", dir, record.Score, record.Logs, d.config.Period,
+				record.PreviousScore)
+		default:
+			d.logf("📉 %s: %d/100 over %d logs last %s, down from %d/100", dir, record.Score, record.Logs,
+				d.config.Period, record.PreviousScore)
+			if d.config.Notifier != nil {
+				if err := d.config.Notifier.Send(NewDigestSummary(record, current, previous)); err != nil {
+					d.logf("⚠️  Notification failed: %v", err)
+				} else {
+					d.logf("📣 Posted digest to %s", d.config.Notifier)
+				}
+			}
+		}
+	}
+}
+
+// scored drops the logs with no collections, whose score says nothing
+func scored(records []history.Record) []history.Record {
+	return slices.DeleteFunc(records, func(r history.Record) bool { return r.Events == 0 })
+}
+
+func averageScore(records []history.Record) int {
+	total := 0
+	for _, record := range records {
+		total += record.Score
+	}
+	return (total + len(records)/2) / len(records)
+}
+
+// NewDigestSummary condenses a digest: the two periods' scores, what got worse and the issues that are new
+func NewDigestSummary(digest history.Record, current, previous []history.Record) *notify.Summary {
+	period := digest.Period
+	summary := &notify.Summary{
+		Title:   fmt.Sprintf("📉 GC health down %s over %s", period, period),
+		Source:  digest.Group,
+		Score:   digest.Score,
+		Verdict: notify.ScoreVerdict(digest.Score),
+		Metrics: []notify.Metric{
+			{Name: "Last " + period, Value: fmt.Sprintf("%d/100 over %d logs", digest.Score, digest.Logs)},
+			{Name: "The " + period + " before", Value: fmt.Sprintf("%d/100 over %d logs", digest.PreviousScore, digest.PreviousLogs)},
+			{Name: "Max pause", Value: fmt.Sprintf("%s (was %s)", utils.FormatMillis(maxPause(current)),
+				utils.FormatMillis(maxPause(previous)))},
+			{Name: "Full GCs", Value: fmt.Sprintf("%d (was %d)", fullGCs(current), fullGCs(previous))},
+		},
+	}
+
+	worst := slices.MinFunc(current, func(a, b history.Record) int { return a.Score - b.Score })
+	summary.Metrics = append(summary.Metrics, notify.Metric{Name: "Worst log",
+		Value: fmt.Sprintf("%s (%d/100)", worst.File, worst.Score)})
+
+	// Issues raised this period that weren't the period before, counted by the logs that raised them
+	for _, severity := range []string{"critical", "warning"} {
+		seen := issueCounts(previous, severity)
+		counts := issueCounts(current, severity)
+		for _, issue := range issueOrder(current, severity) {
+			if seen[issue] == 0 {
+				summary.Issues = append(summary.Issues, notify.Issue{Severity: severity, Title: issue,
+					Description: fmt.Sprintf("New, in %d of %d logs", counts[issue], len(current))})
+			}
+		}
+	}
+
+	var scores []float64
+	for _, record := range append(slices.Clone(previous), current...) {
+		scores = append(scores, float64(record.Score))
+	}
+	summary.Charts = []notify.Chart{{Title: "Score per log, both " + period + "s", Values: scores, Unit: "/100"}}
+	return summary
+}
+
+func maxPause(records []history.Record) float64 {
+	pause := 0.0
+	for _, record := range records {
+		pause = max(pause, record.MaxPauseMs)
+	}
+	return pause
+}
+
+func fullGCs(records []history.Record) int {
+	total := 0
+	for _, record := range records {
+		total += record.FullGCs
+	}
+	return total
+}
+
+func severityIssues(record history.Record, severity string) []string {
+	if severity == "critical" {
+		return record.Critical
+	}
+	return record.Warning
+}
+
+// issueCounts counts the logs that raised each issue
+func issueCounts(records []history.Record, severity string) map[string]int {
+	counts := make(map[string]int)
+	for _, record := range records {
+		for _, issue := range slices.Compact(slices.Sorted(slices.Values(severityIssues(record, severity)))) {
+			counts[issue]++
+		}
+	}
+	return counts
+}
+
+// issueOrder lists the issues in the order they were first raised
+func issueOrder(records []history.Record, severity string) []string {
+	var order []string
+	for _, record := range records {
+		for _, issue := range severityIssues(record, severity) {
+			if !slices.Contains(order, issue) {
+				order = append(order, issue)
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/history"
+)
+
+// writeBackdatedLog generates a log of pattern whose JVM started at start, dated as if rotated when it ended
+func writeBackdatedLog(t *testing.T, path, pattern string, start time.Time) {
+	t.Helper()
+	config, err := gc.DefaultGeneratorConfig(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Start = start
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gc.GenerateLog(file, config); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	end := start.Add(config.Duration)
+	if err := os.Chtimes(path, end, end); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDigestBucketsByLogEnd analyzes a backlog of rotated logs in one scan, as on
+// first start, and checks each counts towards the day it ended rather than today
+func TestDigestBucketsByLogEnd(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local)
+	day := func(offset int, hour int) time.Time {
+		return time.Date(2026, 3, 11+offset, hour, 0, 0, 0, time.Local)
+	}
+
+	writeBackdatedLog(t, filepath.Join(dir, "gc.log.0"), gc.PatternHealthy, day(-20, 8)) // Weeks before either period
+	writeBackdatedLog(t, filepath.Join(dir, "gc.log.1"), gc.PatternHealthy, day(-2, 8))  // The day before
+	writeBackdatedLog(t, filepath.Join(dir, "gc.log.2"), gc.PatternLeak, day(-1, 8))     // The day just ended
+	writeBackdatedLog(t, filepath.Join(dir, "gc.log.3"), gc.PatternLeak, day(-1, 14))
+
+	db, err := history.Open(filepath.Join(t.TempDir(), history.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := New(&Config{
+		Dirs:      []string{dir},
+		Pattern:   DefaultPattern,
+		Period:    PeriodDay,
+		Threshold: 1,
+		History:   db,
+		Out:       io.Discard,
+	})
+	d.Scan(context.Background(), now)
+	if analyzed := db.Records(history.KindAnalysis, dir, time.Time{}, now.AddDate(1, 0, 0)); len(analyzed) != 4 {
+		t.Fatalf("%d logs analyzed, want 4", len(analyzed))
+	}
+	d.Digest(now)
+
+	digests := db.Records(history.KindDigest, dir, time.Time{}, now.AddDate(1, 0, 0))
+	if len(digests) != 1 {
+		t.Fatalf("%d digests, want 1", len(digests))
+	}
+	digest := digests[0]
+	if digest.Logs != 2 || digest.PreviousLogs != 1 {
+		t.Errorf("digest covers %d logs against %d the day before, want 2 against 1", digest.Logs, digest.PreviousLogs)
+	}
+	if !digest.Degraded {
+		t.Errorf("digest not degraded: %d/100, was %d/100", digest.Score, digest.PreviousScore)
+	}
+	if today := db.Records(history.KindAnalysis, dir, day(0, 0), now.AddDate(1, 0, 0)); len(today) != 0 {
+		t.Errorf("%d logs counted towards today, the day they were analyzed", len(today))
+	}
+}
@@ -0,0 +1,260 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	heapCriticalUsage = 0.90 // Floor of the burst, so it's what survives collection
+	heapWarningUsage  = 0.75
+	gcCriticalShare   = 0.20 // Share of the burst spent in GC
+	gcWarningShare    = 0.10
+	cpuCriticalLoad   = 0.90 // Process CPU load, across all processors
+	cpuWarningLoad    = 0.70
+	blockedWarning    = 0.20 // Share of Java threads BLOCKED on a monitor
+	contendedWaiters  = 5    // Waiters on one lock worth calling out
+	fdCriticalUsage   = 0.90
+	fdWarningUsage    = 0.75
+	metaspaceWarning  = 0.90
+	threadGrowth      = 10 // Live threads gained over the burst worth calling out
+)
+
+var severityRank = map[string]int{"critical": 0, "warning": 1, "info": 2}
+
+// Analyze runs every check the captured data allows and orders the findings most urgent first
+func (t *Triage) Analyze() {
+	t.Findings = nil
+	t.checkThreads()
+	if len(t.Samples) > 0 {
+		t.checkHeap()
+		t.checkGC()
+		t.checkCPU()
+		t.checkSystem()
+	}
+	t.checkNative()
+	t.checkFlags()
+
+	sort.SliceStable(t.Findings, func(i, j int) bool {
+		return severityRank[t.Findings[i].Severity] < severityRank[t.Findings[j].Severity]
+	})
+}
+
+// burst is the time the JMX samples span
+func (t *Triage) burst() string {
+	return utils.FormatDuration(t.Last().Timestamp.Sub(t.First().Timestamp))
+}
+
+func (t *Triage) add(severity, area, title, detail, action string) {
+	t.Findings = append(t.Findings, Finding{Severity: severity, Area: area, Title: title, Detail: detail, Action: action})
+}
+
+func (t *Triage) checkThreads() {
+	if t.Threads != nil {
+		analysis := t.Threads
+		for _, deadlock := range analysis.Deadlocks {
+			names := make([]string, len(deadlock.Threads))
+			for i, thread := range deadlock.Threads {
+				names[i] = fmt.Sprintf("%q", thread.Name)
+			}
+			t.add("critical", AreaThreads, fmt.Sprintf("Deadlock between %d threads", len(deadlock.Threads)),
+				strings.Join(names, " → "),
+				"These threads will never progress; restart the JVM and fix the lock ordering from the saved dump")
+		}
+
+		if blocked := analysis.StateCounts[thread.StateBlocked]; analysis.JavaThreads > 0 {
+			if share := float64(blocked) / float64(analysis.JavaThreads); share >= blockedWarning {
+				t.add("warning", AreaThreads, fmt.Sprintf("%d of %d Java threads BLOCKED", blocked, analysis.JavaThreads),
+					fmt.Sprintf("%s of the application is waiting on monitors", utils.Precision(0).Percent(share*100)),
+					"Find the lock they wait for in the thread dump; a slow call made while holding it is the usual cause")
+			}
+		}
+
+		if len(analysis.Contended) > 0 {
+			lock := analysis.Contended[0]
+			if len(lock.Waiters) >= contendedWaiters {
+				owner := "no thread in the dump"
+				if lock.Owner != nil {
+					owner = fmt.Sprintf("%q", lock.Owner.Name)
+					if frame := thread.ApplicationFrame(lock.Owner.Frames); frame != nil {
+						owner += " at " + frame.Method
+					}
+				}
+				t.add("warning", AreaThreads, fmt.Sprintf("%d threads waiting for %s", len(lock.Waiters), lock.Lock.ClassName),
+					"Held by "+owner,
+					"Check what the owner is doing; run 'jdiag thread compare' on a few dumps to see if it is stuck")
+			}
+		}
+	} else if last := t.Last(); last != nil && len(last.Threading.DeadlockedThreads) > 0 {
+		t.add("critical", AreaThreads, fmt.Sprintf("%d threads deadlocked", len(last.Threading.DeadlockedThreads)),
+			"Reported by the ThreadMXBean; no thread dump to show the cycle",
+			"Take a thread dump with jcmd <pid> Thread.print -l, then restart the JVM")
+	}
+
+	if first, last := t.First(), t.Last(); first != nil && first != last {
+		started := last.Threading.TotalStartedCount - first.Threading.TotalStartedCount
+		if growth := last.Threading.Count - first.Threading.Count; growth >= threadGrowth {
+			t.add("warning", AreaThreads, fmt.Sprintf("Thread count grew by %d in %s", growth, t.burst()),
+				fmt.Sprintf("%d threads started, %d live now (peak %d)", started, last.Threading.Count, last.Threading.PeakCount),
+				"A pool without a bound or threads that never exit; group the dump by name pattern to find which")
+		}
+	}
+}
+
+func (t *Triage) checkHeap() {
+	last := t.Last()
+	heapMax := last.Memory.Heap.Max
+	if heapMax <= 0 {
+		return
+	}
+
+	// The lowest usage in the burst is closest to the live set: collections happened in between
+	floor, peak := last.Memory.Heap.Used, last.Memory.Heap.Used
+	for _, sample := range t.Samples {
+		floor = min(floor, sample.Memory.Heap.Used)
+		peak = max(peak, sample.Memory.Heap.Used)
+	}
+	usage := float64(floor) / float64(heapMax)
+	detail := fmt.Sprintf("At least %s of %s used throughout the burst (peak %s)", utils.MemorySize(floor),
+		utils.MemorySize(heapMax), utils.MemorySize(peak))
+
+	switch {
+	case usage >= heapCriticalUsage:
+		t.add("critical", AreaHeap, fmt.Sprintf("Heap %s full and not coming down", utils.Precision(0).Percent(usage*100)), detail,
+			"An OutOfMemoryError is close: take a heap dump (jcmd <pid> GC.heap_dump) and run 'jdiag heap' on it")
+	case usage >= heapWarningUsage:
+		t.add("warning", AreaHeap, fmt.Sprintf("Heap %s full", utils.Precision(0).Percent(usage*100)), detail,
+			"Watch whether it keeps rising with 'jdiag watch'; a steady climb after each GC is a leak")
+	}
+
+	if metaspace := last.Memory.Metaspace.Usage; metaspace.Max > 0 {
+		if share := float64(metaspace.Used) / float64(metaspace.Max); share >= metaspaceWarning {
+			t.add("warning", AreaHeap, fmt.Sprintf("Metaspace %s of MaxMetaspaceSize", utils.Precision(0).Percent(share*100)),
+				fmt.Sprintf("%s of %s, %d classes loaded", utils.MemorySize(metaspace.Used), utils.MemorySize(metaspace.Max),
+					last.ClassLoading.LoadedClassCount),
+				"Class loader leak or too low a limit; compare loaded classes over time before raising it")
+		}
+	}
+}
+
+func (t *Triage) checkGC() {
+	first, last := t.First(), t.Last()
+	elapsed := last.Timestamp.Sub(first.Timestamp)
+	if elapsed <= 0 {
+		return
+	}
+
+	gcTime := gcTimeMs(last) - gcTimeMs(first)
+	share := float64(gcTime) / float64(elapsed.Milliseconds())
+	oldCollections := last.GC.OldGCCount - first.GC.OldGCCount
+	detail := fmt.Sprintf("%dms of %s in GC; %d young, %d old collections", gcTime, t.burst(),
+		last.GC.YoungGCCount-first.GC.YoungGCCount, oldCollections)
+
+	switch {
+	case share >= gcCriticalShare:
+		t.add("critical", AreaGC, fmt.Sprintf("%s of time spent in GC", utils.Precision(0).Percent(share*100)), detail,
+			"The JVM is thrashing: the heap is too small for the live set or something is leaking")
+	case share >= gcWarningShare:
+		t.add("warning", AreaGC, fmt.Sprintf("%s of time spent in GC", utils.Precision(0).Percent(share*100)), detail,
+			"Enable GC logging (-Xlog:gc*:file=gc.log) and run 'jdiag gc analyze' for tuning advice")
+	}
+
+	if oldCollections > 0 && share < gcWarningShare {
+		t.add("warning", AreaGC, fmt.Sprintf("%d old generation collections in %s", oldCollections, t.burst()),
+			fmt.Sprintf("Last took %dms", last.GC.LastOldGC.Duration),
+			"Full or mixed collections this often mean promotion outpaces the old generation")
+	}
+}
+
+func gcTimeMs(snapshot *jmx.MBeanSnapshot) int64 {
+	return snapshot.GC.YoungGCTime + snapshot.GC.OldGCTime
+}
+
+func (t *Triage) checkCPU() {
+	var total float64
+	count := 0
+	for _, sample := range t.Samples {
+		if sample.OS.ProcessCpuLoad >= 0 {
+			total += sample.OS.ProcessCpuLoad
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+
+	load := total / float64(count)
+	last := t.Last()
+	detail := fmt.Sprintf("Average over the burst, system at %s, %d processors", utils.Precision(0).Percent(last.OS.SystemCpuLoad*100),
+		last.OS.AvailableProcessors)
+	switch {
+	case load >= cpuCriticalLoad:
+		t.add("critical", AreaCPU, fmt.Sprintf("JVM using %s CPU", utils.Precision(0).Percent(load*100)), detail,
+			"Find the busy threads: compare two thread dumps with 'jdiag thread compare' for cpu= deltas")
+	case load >= cpuWarningLoad:
+		t.add("warning", AreaCPU, fmt.Sprintf("JVM using %s CPU", utils.Precision(0).Percent(load*100)), detail,
+			"If GC time is low, the application is busy: compare thread dumps with 'jdiag thread compare' for cpu= deltas")
+	}
+}
+
+func (t *Triage) checkSystem() {
+	last := t.Last()
+	os := last.OS
+
+	if os.MaxFileDescriptorCount > 0 {
+		share := float64(os.OpenFileDescriptorCount) / float64(os.MaxFileDescriptorCount)
+		detail := fmt.Sprintf("%d of %d open", os.OpenFileDescriptorCount, os.MaxFileDescriptorCount)
+		switch {
+		case share >= fdCriticalUsage:
+			t.add("critical", AreaSystem, fmt.Sprintf("File descriptors %s used", utils.Precision(0).Percent(share*100)), detail,
+				"New connections and files will fail soon; look for leaked sockets (ls -l /proc/<pid>/fd)")
+		case share >= fdWarningUsage:
+			t.add("warning", AreaSystem, fmt.Sprintf("File descriptors %s used", utils.Precision(0).Percent(share*100)), detail,
+				"Raise the limit (ulimit -n) or find what keeps them open")
+		}
+	}
+
+	if os.TotalSwapSpace > 0 && os.FreeSwapSpace < os.TotalSwapSpace && os.FreePhysicalMemory*20 < os.TotalPhysicalMemory {
+		t.add("warning", AreaSystem, "Host is low on memory and swapping",
+			fmt.Sprintf("%s free of %s, %s swap in use", utils.MemorySize(os.FreePhysicalMemory),
+				utils.MemorySize(os.TotalPhysicalMemory), utils.MemorySize(os.TotalSwapSpace-os.FreeSwapSpace)),
+			"A swapped-out heap makes every GC pause long; reduce what runs on the host or the heap size")
+	}
+
+	if os.AvailableProcessors > 0 && os.SystemLoadAverage > float64(os.AvailableProcessors)*2 {
+		t.add("warning", AreaSystem, fmt.Sprintf("Load average %s on %d processors", utils.FormatFloat(os.SystemLoadAverage),
+			os.AvailableProcessors), "More runnable threads than the host can schedule",
+			"Check for other busy processes or too many GC and application threads for the host")
+	}
+}
+
+func (t *Triage) checkNative() {
+	if t.Native == nil {
+		return
+	}
+	for _, finding := range t.Native.Findings {
+		t.add(finding.Severity, AreaNative, finding.Description, "", finding.Recommendation)
+	}
+}
+
+func (t *Triage) checkFlags() {
+	if t.Flags == nil {
+		return
+	}
+	report := t.Flags
+	for _, findings := range [][]flags.Finding{report.Removed, report.Conflicts, report.Locked} {
+		for _, finding := range findings {
+			t.add(finding.Severity, AreaFlags, finding.Description, "", finding.Recommendation)
+		}
+	}
+	if deprecated := len(report.Deprecated); deprecated > 0 {
+		t.add("info", AreaFlags, fmt.Sprintf("%d deprecated flags", deprecated), "",
+			"Not urgent; run 'jdiag flags <pid>' for the replacements before the next JDK upgrade")
+	}
+}
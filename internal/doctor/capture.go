@@ -0,0 +1,182 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/native"
+	"github.com/mabhi256/jdiag/internal/thread"
+)
+
+const (
+	DefaultSamples  = 10
+	DefaultInterval = time.Second
+
+	jcmdTimeout = 30 * time.Second
+	// The JMX bridge starts a JVM of its own, which takes a few seconds before the first sample
+	connectTimeout = 30 * time.Second
+)
+
+/*
+ * Capture collects everything the triage needs from a local JVM:
+ *
+ *   - a burst of JMX samples, to see rates (GC time, CPU) and not just levels
+ *   - a thread dump (jcmd Thread.print -l), taken while the burst runs
+ *   - the final flag values (jcmd VM.flags -all), or the JMX input arguments
+ *   - the heap layout (jcmd GC.heap_info), kept as an artifact
+ *   - /proc/<pid>/smaps, reconciled with the last JMX sample
+ *
+ * A step that fails is recorded in Skipped so the rest still runs: in an
+ * incident a partial answer now beats a complete one later.
+ */
+func Capture(pid, samples int, interval time.Duration) *Triage {
+	triage := &Triage{PID: pid, Started: time.Now()}
+
+	var wg sync.WaitGroup
+	var skipped []string
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		skipped = triage.captureJcmd(pid)
+	}()
+
+	samplesErr := triage.sampleJMX(pid, samples, interval)
+	wg.Wait()
+	if samplesErr != nil {
+		triage.Skipped = append(triage.Skipped, fmt.Sprintf("JMX samples: %v", samplesErr))
+	}
+	triage.Skipped = append(triage.Skipped, skipped...)
+
+	if triage.Flags == nil {
+		if last := triage.Last(); last != nil && len(last.Runtime.InputArguments) > 0 {
+			version := last.Runtime.SpecVersion
+			if version == "" {
+				version = last.Runtime.JavaVersion
+			}
+			set := flags.FromArguments(flags.SourceJMX, fmt.Sprintf("PID %d", pid), last.Runtime.InputArguments, version)
+			triage.Flags = flags.Audit(set, false)
+		}
+	}
+
+	if analysis, err := native.ParseProcess(pid); err != nil {
+		triage.Skipped = append(triage.Skipped, fmt.Sprintf("Native memory: %v", err))
+	} else {
+		var jvm *native.JVMMemory
+		if last := triage.Last(); last != nil {
+			jvm = native.NewJVMMemory(fmt.Sprintf("PID %d", pid), last)
+		}
+		analysis.Analyze(jvm)
+		triage.Native = analysis
+	}
+
+	triage.Duration = time.Since(triage.Started)
+	return triage
+}
+
+// sampleJMX keeps every new connected snapshot until it has samples of them
+func (t *Triage) sampleJMX(pid, samples int, interval time.Duration) error {
+	collector := jmx.NewJMXCollector(&jmx.Config{PID: pid, Interval: int(interval.Milliseconds())})
+	if err := collector.Start(); err != nil {
+		return err
+	}
+	defer collector.Stop()
+
+	deadline := time.Now().Add(connectTimeout + time.Duration(samples)*interval)
+	var last time.Time
+	var lastErr error
+	for len(t.Samples) < samples && time.Now().Before(deadline) {
+		time.Sleep(interval / 4)
+
+		metrics := collector.GetMetrics()
+		if metrics.Timestamp.Equal(last) {
+			continue
+		}
+		last = metrics.Timestamp
+		if !metrics.Connected {
+			lastErr = metrics.Error
+			continue
+		}
+		t.Samples = append(t.Samples, metrics)
+	}
+
+	if len(t.Samples) == 0 {
+		if lastErr != nil {
+			return lastErr
+		}
+		return fmt.Errorf("no sample within %s", connectTimeout)
+	}
+	return nil
+}
+
+// captureJcmd takes the thread dump, flags and heap layout; it doesn't touch Samples, which sampleJMX fills meanwhile
+func (t *Triage) captureJcmd(pid int) []string {
+	var skipped []string
+
+	if output, err := runJcmd(pid, "Thread.print", "-l"); err != nil {
+		skipped = append(skipped, fmt.Sprintf("Thread dump: %v", err))
+	} else if dump, err := thread.Parse(bytes.NewReader(output)); err != nil {
+		skipped = append(skipped, fmt.Sprintf("Thread dump: %v", err))
+	} else {
+		t.Artifacts = append(t.Artifacts, Artifact{Name: "threads.txt", Data: output})
+		t.Threads = thread.Analyze(dump)
+	}
+
+	if output, err := runJcmd(pid, "VM.flags", "-all"); err != nil {
+		skipped = append(skipped, fmt.Sprintf("VM flags: %v", err))
+	} else if set, err := flags.Parse(bytes.NewReader(output), fmt.Sprintf("PID %d", pid)); err != nil {
+		skipped = append(skipped, fmt.Sprintf("VM flags: %v", err))
+	} else {
+		t.Artifacts = append(t.Artifacts, Artifact{Name: "flags.txt", Data: output})
+		t.Flags = flags.Audit(set, false)
+	}
+
+	if output, err := runJcmd(pid, "GC.heap_info"); err != nil {
+		skipped = append(skipped, fmt.Sprintf("Heap info: %v", err))
+	} else {
+		t.Artifacts = append(t.Artifacts, Artifact{Name: "heap_info.txt", Data: output})
+	}
+
+	return skipped
+}
+
+func runJcmd(pid int, command ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jcmdTimeout)
+	defer cancel()
+
+	args := append([]string{strconv.Itoa(pid)}, command...)
+	output, err := exec.CommandContext(ctx, "jcmd", args...).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("jcmd %s timed out after %s", strings.Join(command, " "), jcmdTimeout)
+		}
+		return nil, fmt.Errorf("jcmd %s failed: %w", strings.Join(command, " "), err)
+	}
+	return output, nil
+}
+
+// Save writes the captured artifacts into dir, returning the files written
+func (t *Triage) Save(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, artifact := range t.Artifacts {
+		path := filepath.Join(dir, artifact.Name)
+		if err := os.WriteFile(path, artifact.Data, 0o644); err != nil {
+			return files, fmt.Errorf("unable to save %s: %w", artifact.Name, err)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
@@ -0,0 +1,128 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+func (t *Triage) PrintSummary() {
+	fmt.Println()
+	fmt.Println("🩺 JDIAG DOCTOR")
+	fmt.Println(strings.Repeat("─", 80))
+	t.printTarget()
+	t.printTriage()
+	if len(t.Samples) > 0 {
+		t.printVitals()
+	}
+}
+
+func (t *Triage) printTarget() {
+	target := fmt.Sprintf("PID %d", t.PID)
+	last := t.Last()
+	if last != nil {
+		if command := strings.Fields(last.Runtime.SystemProperties["sun.java.command"]); len(command) > 0 {
+			target += " (" + command[0] + ")"
+		}
+	}
+	fmt.Printf("   Target:    %s\n", target)
+	if last != nil {
+		fmt.Printf("   JVM:       %s %s  |  Up %s\n", last.Runtime.VmName, last.Runtime.VmVersion,
+			utils.FormatDuration(last.Runtime.Uptime))
+	}
+
+	var captured []string
+	if len(t.Samples) > 0 {
+		captured = append(captured, fmt.Sprintf("%d JMX samples over %s", len(t.Samples), t.burst()))
+	}
+	if t.Threads != nil {
+		captured = append(captured, fmt.Sprintf("thread dump (%d threads)", len(t.Threads.Dump.Threads)))
+	}
+	if t.Flags != nil {
+		captured = append(captured, fmt.Sprintf("flags (%s)", t.Flags.Flags.Source))
+	}
+	if t.Native != nil {
+		captured = append(captured, "memory map")
+	}
+	if len(captured) == 0 {
+		captured = append(captured, "nothing")
+	}
+	fmt.Printf("   Captured:  %s in %s\n", strings.Join(captured, ", "), utils.FormatDuration(t.Duration))
+
+	for _, skipped := range t.Skipped {
+		fmt.Println(utils.MutedStyle.Render("   ⏭️  Skipped " + skipped))
+	}
+}
+
+func (t *Triage) printTriage() {
+	fmt.Println()
+	if len(t.Findings) == 0 {
+		fmt.Println(utils.GoodStyle.Render("✅ Nothing stands out in the captured data"))
+		return
+	}
+
+	fmt.Printf("🚦 TRIAGE (%d critical, %d warning, %d info)\n", t.Count("critical"), t.Count("warning"), t.Count("info"))
+	fmt.Println(strings.Repeat("─", 80))
+	for i, finding := range t.Findings {
+		title := fmt.Sprintf("%2d. %s [%s] %s", i+1, utils.GetSeverityIcon(finding.Severity), finding.Area, finding.Title)
+		fmt.Println(utils.GetSeverityStyle(finding.Severity).Render(title))
+		if finding.Detail != "" {
+			fmt.Printf("       %s\n", finding.Detail)
+		}
+		fmt.Printf("       → %s\n", finding.Action)
+	}
+}
+
+func (t *Triage) printVitals() {
+	first, last := t.First(), t.Last()
+
+	fmt.Println()
+	fmt.Println("📊 VITALS")
+	fmt.Println(strings.Repeat("─", 80))
+
+	heap := last.Memory.Heap
+	heapLine := fmt.Sprintf("%s used, %s committed", utils.MemorySize(heap.Used), utils.MemorySize(heap.Committed))
+	if heap.Max > 0 {
+		heapLine = fmt.Sprintf("%s of %s (%s), %s committed", utils.MemorySize(heap.Used), utils.MemorySize(heap.Max),
+			utils.Precision(0).Percent(float64(heap.Used)/float64(heap.Max)*100), utils.MemorySize(heap.Committed))
+	}
+	fmt.Printf("   Heap:      %s\n", heapLine)
+
+	fmt.Printf("   GC:        %d young (%dms), %d old (%dms) during the burst  |  %d young, %d old since start\n",
+		last.GC.YoungGCCount-first.GC.YoungGCCount, last.GC.YoungGCTime-first.GC.YoungGCTime,
+		last.GC.OldGCCount-first.GC.OldGCCount, last.GC.OldGCTime-first.GC.OldGCTime,
+		last.GC.YoungGCCount, last.GC.OldGCCount)
+
+	fmt.Printf("   CPU:       process %s, system %s, %d processors, load %s\n",
+		utils.Precision(0).Percent(max(last.OS.ProcessCpuLoad, 0)*100), utils.Precision(0).Percent(max(last.OS.SystemCpuLoad, 0)*100), last.OS.AvailableProcessors,
+		utils.Precision(2).Float(last.OS.SystemLoadAverage))
+
+	threads := fmt.Sprintf("%d live, %d daemon, peak %d", last.Threading.Count, last.Threading.DaemonCount,
+		last.Threading.PeakCount)
+	if t.Threads != nil {
+		var states []string
+		for _, state := range thread.States {
+			if count := t.Threads.StateCounts[state]; count > 0 {
+				states = append(states, fmt.Sprintf("%d %s", count, state))
+			}
+		}
+		threads += "  |  " + strings.Join(states, ", ")
+	}
+	fmt.Printf("   Threads:   %s\n", threads)
+
+	fmt.Printf("   Classes:   %d loaded, metaspace %s\n", last.ClassLoading.LoadedClassCount,
+		utils.MemorySize(last.Memory.Metaspace.Usage.Used))
+	if last.OS.MaxFileDescriptorCount > 0 {
+		fmt.Printf("   Files:     %d of %d descriptors open\n", last.OS.OpenFileDescriptorCount,
+			last.OS.MaxFileDescriptorCount)
+	}
+	if t.Native != nil {
+		fmt.Printf("   Resident:  %s", t.Native.TotalRss)
+		if t.Native.JVM != nil {
+			fmt.Printf(" (%s not accounted for by the JVM)", t.Native.Unexplained)
+		}
+		fmt.Println()
+	}
+}
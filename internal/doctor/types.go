@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/native"
+	"github.com/mabhi256/jdiag/internal/thread"
+)
+
+// Areas a finding is about, in the order they are checked
+const (
+	AreaThreads = "Threads"
+	AreaHeap    = "Heap"
+	AreaGC      = "GC"
+	AreaCPU     = "CPU"
+	AreaSystem  = "System"
+	AreaNative  = "Native memory"
+	AreaFlags   = "Flags"
+)
+
+// Finding is one triage conclusion; Findings is ordered most urgent first
+type Finding struct {
+	Severity string // "critical", "warning", "info"
+	Area     string
+	Title    string
+	Detail   string
+	Action   string
+}
+
+// Artifact is raw output captured from the JVM, kept so it can be saved for later analysis
+type Artifact struct {
+	Name string // File name when saved, e.g. "threads.txt"
+	Data []byte
+}
+
+type Triage struct {
+	PID      int
+	Started  time.Time
+	Duration time.Duration
+
+	Samples []*jmx.MBeanSnapshot // Connected JMX samples, oldest first
+	Threads *thread.ThreadAnalysis
+	Flags   *flags.Report
+	Native  *native.Analysis
+
+	Artifacts []Artifact
+	Skipped   []string // Steps that couldn't run, with the reason
+
+	Findings []Finding
+}
+
+func (t *Triage) First() *jmx.MBeanSnapshot {
+	if len(t.Samples) == 0 {
+		return nil
+	}
+	return t.Samples[0]
+}
+
+func (t *Triage) Last() *jmx.MBeanSnapshot {
+	if len(t.Samples) == 0 {
+		return nil
+	}
+	return t.Samples[len(t.Samples)-1]
+}
+
+// Count returns how many findings have the severity
+func (t *Triage) Count(severity string) int {
+	count := 0
+	for _, finding := range t.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,304 @@
+package flags
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Collectors selectable with a Use...GC flag; the JVM refuses to start with more than one
+var collectorFlags = []string{
+	"UseSerialGC", "UseParallelGC", "UseConcMarkSweepGC", "UseG1GC", "UseZGC", "UseShenandoahGC", "UseEpsilonGC",
+}
+
+const compressedOopsLimit = 32 * utils.GB
+
+type Finding struct {
+	Severity       string   // "critical", "warning", "info"
+	Flags          []string // Flag names involved
+	Description    string
+	Recommendation string
+}
+
+type Report struct {
+	Flags *FlagSet
+
+	Removed    []Finding // Expired or obsoleted for the JDK version
+	Deprecated []Finding
+	Conflicts  []Finding
+	Locked     []Finding // Flags that need an Unlock...VMOptions flag that isn't set
+	NonDefault []*Flag   // Explicitly set flags, plus ergonomic ones when requested
+}
+
+// Audit checks a JVM's flags for its JDK version; includeErgonomic lists flags the JVM chose itself too
+func Audit(set *FlagSet, includeErgonomic bool) *Report {
+	report := &Report{Flags: set}
+
+	for _, name := range set.Order {
+		flag := set.Flags[name]
+		if !set.explicit(name) {
+			if includeErgonomic && flag.Origin != OriginDefault {
+				report.NonDefault = append(report.NonDefault, flag)
+			}
+			continue
+		}
+		report.NonDefault = append(report.NonDefault, flag)
+
+		if lifecycle, found := lifecycles[name]; found {
+			report.checkLifecycle(flag, lifecycle)
+		}
+		if locked, found := lockedFlags[name]; found && flag.Value != "false" {
+			report.checkUnlocked(flag, locked)
+		}
+	}
+
+	report.checkConflicts()
+	return report
+}
+
+// explicit reports whether a flag was set by the user rather than by default or ergonomics
+func (s *FlagSet) explicit(name string) bool {
+	flag := s.Flags[name]
+	return flag != nil && flag.Origin != OriginDefault && flag.Origin != OriginErgonomic
+}
+
+func (r *Report) checkLifecycle(flag *Flag, lifecycle Lifecycle) {
+	version := r.Flags.JDKVersion
+	finding := Finding{Flags: []string{flag.Name}, Recommendation: "Replace with " + lifecycle.Replacement}
+	if strings.HasPrefix(lifecycle.Replacement, "remove") {
+		finding.Recommendation = "R" + lifecycle.Replacement[1:]
+	}
+
+	if version == 0 {
+		finding.Severity = "info"
+		finding.Description = fmt.Sprintf("%s: %s (JDK version unknown)", flag, lifecycle.describe())
+		r.Deprecated = append(r.Deprecated, finding)
+		return
+	}
+
+	switch lifecycle.Stage(version) {
+	case "expired":
+		finding.Severity = "critical"
+		finding.Description = fmt.Sprintf("%s was removed in JDK %d: the JVM refuses to start with it", flag, lifecycle.Expired)
+		r.Removed = append(r.Removed, finding)
+	case "obsoleted":
+		finding.Severity = "warning"
+		finding.Description = fmt.Sprintf("%s is obsolete since JDK %d and ignored", flag, lifecycle.Obsoleted)
+		r.Removed = append(r.Removed, finding)
+	case "deprecated":
+		finding.Severity = "warning"
+		finding.Description = fmt.Sprintf("%s is deprecated since JDK %d", flag, lifecycle.Deprecated)
+		if lifecycle.Obsoleted > 0 {
+			finding.Description += fmt.Sprintf(" and stops working in JDK %d", lifecycle.Obsoleted)
+		} else if lifecycle.Expired > 0 {
+			finding.Description += fmt.Sprintf(" and prevents startup from JDK %d", lifecycle.Expired)
+		}
+		r.Deprecated = append(r.Deprecated, finding)
+	}
+}
+
+func (l Lifecycle) describe() string {
+	var stages []string
+	if l.Deprecated > 0 {
+		stages = append(stages, fmt.Sprintf("deprecated in JDK %d", l.Deprecated))
+	}
+	if l.Obsoleted > 0 {
+		stages = append(stages, fmt.Sprintf("ignored from JDK %d", l.Obsoleted))
+	}
+	if l.Expired > 0 {
+		stages = append(stages, fmt.Sprintf("removed in JDK %d", l.Expired))
+	}
+	return strings.Join(stages, ", ")
+}
+
+func (r *Report) checkUnlocked(flag *Flag, locked lockedFlag) {
+	version := r.Flags.JDKVersion
+	if locked.until > 0 && version > locked.until || r.Flags.Enabled(locked.unlock) {
+		return
+	}
+	if r.Flags.Source == SourcePrintFlagsFinal {
+		return // The JVM started, so the flag was unlocked
+	}
+
+	kind := "experimental"
+	if locked.unlock == unlockDiagnostic {
+		kind = "diagnostic"
+	}
+	severity := "critical"
+	description := fmt.Sprintf("%s is %s: the JVM refuses to start without -XX:+%s", flag, kind, locked.unlock)
+	if locked.until > 0 && version == 0 {
+		severity = "warning"
+		description = fmt.Sprintf("%s is %s up to JDK %d and needs -XX:+%s there", flag, kind, locked.until, locked.unlock)
+	}
+	r.Locked = append(r.Locked, Finding{
+		Severity:       severity,
+		Flags:          []string{flag.Name, locked.unlock},
+		Description:    description,
+		Recommendation: fmt.Sprintf("Add -XX:+%s before %s", locked.unlock, flag),
+	})
+}
+
+func (r *Report) conflict(severity, description, recommendation string, flags ...string) {
+	r.Conflicts = append(r.Conflicts, Finding{
+		Severity:       severity,
+		Flags:          flags,
+		Description:    description,
+		Recommendation: recommendation,
+	})
+}
+
+func (r *Report) checkConflicts() {
+	set := r.Flags
+
+	var collectors []string
+	for _, name := range collectorFlags {
+		if set.explicit(name) && set.Enabled(name) {
+			collectors = append(collectors, name)
+		}
+	}
+	if len(collectors) > 1 {
+		r.conflict("critical", fmt.Sprintf("Multiple collectors selected (%s): the JVM refuses to start",
+			strings.Join(collectors, ", ")), "Keep exactly one -XX:+Use...GC flag", collectors...)
+	}
+
+	maxHeap, hasMaxHeap := set.size("MaxHeapSize")
+	initialHeap, hasInitialHeap := set.size("InitialHeapSize")
+	if hasMaxHeap && hasInitialHeap && set.explicit("InitialHeapSize") && initialHeap > maxHeap {
+		r.conflict("critical", fmt.Sprintf("Initial heap %s is larger than maximum heap %s: the JVM refuses to start",
+			initialHeap, maxHeap), "Set -Xms at or below -Xmx", "InitialHeapSize", "MaxHeapSize")
+	}
+
+	if set.usesG1() {
+		for _, name := range []string{"NewSize", "MaxNewSize", "NewRatio"} {
+			if set.explicit(name) {
+				r.conflict("warning", fmt.Sprintf("%s fixes the young generation under G1, which then can't resize it to meet the pause goal",
+					set.Get(name)), "Remove it and tune -XX:MaxGCPauseMillis instead", name, "UseG1GC")
+				break
+			}
+		}
+	}
+
+	if regionSize, found := set.size("G1HeapRegionSize"); found && set.explicit("G1HeapRegionSize") {
+		maxRegion := 32 * utils.MB
+		if set.JDKVersion >= 18 {
+			maxRegion = 512 * utils.MB
+		}
+		if bits.OnesCount64(uint64(regionSize)) != 1 || regionSize < utils.MB || regionSize > maxRegion {
+			r.conflict("warning", fmt.Sprintf("G1HeapRegionSize %s isn't a power of two between 1M and %s; the JVM adjusts it",
+				regionSize, maxRegion), "Use a power of two, or leave it to ergonomics", "G1HeapRegionSize")
+		}
+	}
+
+	if set.Enabled("DisableExplicitGC") && set.explicit("ExplicitGCInvokesConcurrent") && set.Enabled("ExplicitGCInvokesConcurrent") {
+		r.conflict("info", "ExplicitGCInvokesConcurrent has no effect: DisableExplicitGC ignores System.gc() entirely",
+			"Keep one: ExplicitGCInvokesConcurrent still lets direct buffer cleanup trigger a cycle",
+			"DisableExplicitGC", "ExplicitGCInvokesConcurrent")
+	}
+
+	if set.explicit("HeapDumpPath") && !set.Enabled("HeapDumpOnOutOfMemoryError") {
+		r.conflict("warning", "HeapDumpPath is set but HeapDumpOnOutOfMemoryError is off, so no dump is ever written",
+			"Add -XX:+HeapDumpOnOutOfMemoryError", "HeapDumpPath", "HeapDumpOnOutOfMemoryError")
+	}
+
+	if set.explicit("MaxHeapSize") {
+		for _, name := range []string{"MaxRAMPercentage", "MaxRAMFraction"} {
+			if set.explicit(name) {
+				r.conflict("info", fmt.Sprintf("%s is ignored because -Xmx sets the maximum heap", name),
+					"Keep -Xmx for a fixed heap, or the percentage to follow container limits", name, "MaxHeapSize")
+			}
+		}
+	}
+
+	maxMetaspace, hasMaxMetaspace := set.size("MaxMetaspaceSize")
+	metaspace, hasMetaspace := set.size("MetaspaceSize")
+	if hasMaxMetaspace && hasMetaspace && set.explicit("MaxMetaspaceSize") && metaspace > maxMetaspace {
+		r.conflict("warning", fmt.Sprintf("MetaspaceSize %s is above MaxMetaspaceSize %s; the JVM lowers it", metaspace, maxMetaspace),
+			"Set -XX:MetaspaceSize at or below -XX:MaxMetaspaceSize", "MetaspaceSize", "MaxMetaspaceSize")
+	}
+
+	concurrent, hasConcurrent := set.number("ConcGCThreads")
+	parallel, hasParallel := set.number("ParallelGCThreads")
+	if hasConcurrent && hasParallel && set.explicit("ConcGCThreads") && concurrent > parallel {
+		r.conflict("warning", fmt.Sprintf("ConcGCThreads %d is above ParallelGCThreads %d", concurrent, parallel),
+			"Keep ConcGCThreads at about a quarter of ParallelGCThreads", "ConcGCThreads", "ParallelGCThreads")
+	}
+
+	if set.Enabled("UseCompressedOops") && set.explicit("UseCompressedOops") && hasMaxHeap && maxHeap >= compressedOopsLimit {
+		r.conflict("warning", fmt.Sprintf("UseCompressedOops is requested but the %s heap is too large for it; the JVM disables it",
+			maxHeap), "Stay below 32G to keep compressed references, or well above it to make up for larger objects",
+			"UseCompressedOops", "MaxHeapSize")
+	}
+
+	if verify := set.Get("Xverify"); verify != nil && verify.Value == "none" {
+		severity := "warning"
+		if set.JDKVersion > 0 && set.JDKVersion < 13 {
+			severity = "info"
+		}
+		r.conflict(severity, "-Xverify:none disables bytecode verification; it is deprecated from JDK 13",
+			"Remove it; use CDS (-XX:SharedArchiveFile) to speed up startup instead", "Xverify")
+	}
+
+	if set.explicit("Xloggc") && set.explicit("Xlog") {
+		r.conflict("info", "Both -Xloggc and -Xlog configure GC logging", "Keep only -Xlog", "Xloggc", "Xlog")
+	}
+}
+
+// usesG1 reports whether G1 is the collector: selected, or the JDK 9+ default when none is chosen
+func (s *FlagSet) usesG1() bool {
+	if s.Enabled("UseG1GC") {
+		return true
+	}
+	if s.Source == SourcePrintFlagsFinal || s.JDKVersion > 0 && s.JDKVersion < 9 {
+		return false
+	}
+	for _, name := range collectorFlags {
+		if s.Enabled(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *FlagSet) size(name string) (utils.MemorySize, bool) {
+	flag := s.Flags[name]
+	if flag == nil {
+		return 0, false
+	}
+	size, err := utils.ParseMemorySize(flag.Value)
+	return size, err == nil
+}
+
+func (s *FlagSet) number(name string) (int64, bool) {
+	size, found := s.size(name)
+	return size.Bytes(), found
+}
+
+// Count returns the number of findings of a severity
+func (r *Report) Count(severity string) int {
+	count := 0
+	for _, findings := range [][]Finding{r.Removed, r.Deprecated, r.Conflicts, r.Locked} {
+		for _, finding := range findings {
+			if finding.Severity == severity {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// String formats a flag as it would be given on the command line
+func (f *Flag) String() string {
+	if f.Arg != "" {
+		return f.Arg
+	}
+	switch f.Value {
+	case "true":
+		return "-XX:+" + f.Name
+	case "false":
+		return "-XX:-" + f.Name
+	default:
+		return fmt.Sprintf("-XX:%s=%s", f.Name, f.Value)
+	}
+}
@@ -0,0 +1,231 @@
+package flags
+
+/*
+ * Lifecycle of a HotSpot flag, following JEP 245's stages:
+ *
+ *   deprecated  still works, prints a warning
+ *   obsoleted   accepted but ignored, prints a warning
+ *   expired     "Unrecognized VM option": the JVM refuses to start
+ *
+ * A zero release means the flag hasn't (yet) reached that stage.
+ */
+type Lifecycle struct {
+	Deprecated  int
+	Obsoleted   int
+	Expired     int
+	Replacement string
+}
+
+// Stage returns the lifecycle stage a flag is in on a JDK release, or "" when it is current
+func (l Lifecycle) Stage(version int) string {
+	switch {
+	case l.Expired > 0 && version >= l.Expired:
+		return "expired"
+	case l.Obsoleted > 0 && version >= l.Obsoleted:
+		return "obsoleted"
+	case l.Deprecated > 0 && version >= l.Deprecated:
+		return "deprecated"
+	default:
+		return ""
+	}
+}
+
+const unifiedLogging = "-Xlog (unified logging, JDK 9+)"
+
+var lifecycles = map[string]Lifecycle{
+	// Collectors
+	"UseConcMarkSweepGC":             {Deprecated: 9, Obsoleted: 14, Replacement: "-XX:+UseG1GC, or -XX:+UseZGC for low pauses"},
+	"CMSInitiatingOccupancyFraction": {Obsoleted: 14, Replacement: "-XX:InitiatingHeapOccupancyPercent (G1)"},
+	"UseCMSInitiatingOccupancyOnly":  {Obsoleted: 14, Replacement: "remove; G1 adapts its marking threshold"},
+	"CMSClassUnloadingEnabled":       {Obsoleted: 14, Replacement: "remove; G1 unloads classes during marking"},
+	"CMSParallelRemarkEnabled":       {Obsoleted: 14, Replacement: "remove"},
+	"CMSScavengeBeforeRemark":        {Obsoleted: 14, Replacement: "remove"},
+	"CMSIncrementalMode":             {Deprecated: 8, Obsoleted: 9, Replacement: "remove"},
+	"UseParNewGC":                    {Deprecated: 9, Obsoleted: 10, Replacement: "-XX:+UseG1GC"},
+	"UseParallelOldGC":               {Deprecated: 14, Obsoleted: 15, Replacement: "-XX:+UseParallelGC, which includes the parallel old collector"},
+	"UseAdaptiveGCBoundary":          {Obsoleted: 15, Replacement: "remove"},
+	"ZGenerational":                  {Deprecated: 23, Obsoleted: 24, Replacement: "remove; ZGC is always generational from JDK 23"},
+	"G1ConcRefinementGreenZone":      {Obsoleted: 20, Replacement: "remove; refinement is self-tuning"},
+	"G1ConcRefinementYellowZone":     {Obsoleted: 20, Replacement: "remove; refinement is self-tuning"},
+	"G1ConcRefinementRedZone":        {Obsoleted: 20, Replacement: "remove; refinement is self-tuning"},
+	"G1RSetUpdatingPauseTimePercent": {Obsoleted: 20, Replacement: "remove"},
+	"Xincgc":                         {Deprecated: 8, Expired: 9, Replacement: "-XX:+UseG1GC"},
+
+	// Memory sizing
+	"PermSize":                    {Obsoleted: 8, Expired: 17, Replacement: "-XX:MetaspaceSize"},
+	"MaxPermSize":                 {Obsoleted: 8, Expired: 17, Replacement: "-XX:MaxMetaspaceSize"},
+	"MaxRAMFraction":              {Deprecated: 10, Replacement: "-XX:MaxRAMPercentage"},
+	"MinRAMFraction":              {Deprecated: 10, Replacement: "-XX:MinRAMPercentage"},
+	"InitialRAMFraction":          {Deprecated: 10, Replacement: "-XX:InitialRAMPercentage"},
+	"UseCGroupMemoryLimitForHeap": {Deprecated: 10, Expired: 11, Replacement: "-XX:+UseContainerSupport (default on)"},
+	"UseContainerCpuShares":       {Deprecated: 19, Obsoleted: 20, Replacement: "remove; CPU shares no longer limit the processor count"},
+	"PreferContainerQuotaForCPUCount": {Deprecated: 19, Obsoleted: 20,
+		Replacement: "-XX:ActiveProcessorCount to override the detected count"},
+
+	// Locking and runtime
+	"UseBiasedLocking":          {Deprecated: 15, Obsoleted: 18, Replacement: "remove; biased locking was removed (JEP 374)"},
+	"BiasedLockingStartupDelay": {Deprecated: 15, Obsoleted: 18, Replacement: "remove"},
+	"UseMembar":                 {Deprecated: 10, Obsoleted: 12, Replacement: "remove"},
+	"AggressiveOpts":            {Deprecated: 11, Obsoleted: 12, Replacement: "remove; its optimizations are defaults or gone"},
+	"UseFastAccessorMethods":    {Obsoleted: 9, Replacement: "remove"},
+	"UseSplitVerifier":          {Obsoleted: 8, Replacement: "remove"},
+	"UnlockCommercialFeatures":  {Obsoleted: 11, Replacement: "remove; JFR is free from JDK 11"},
+	"FlightRecorder":            {Deprecated: 13, Replacement: "-XX:StartFlightRecording"},
+	"AllowRedefinitionToAddDeleteMethods": {Deprecated: 13,
+		Replacement: "remove; agents must not add or delete methods"},
+	"GCLockerRetryAllocationCount": {Obsoleted: 24, Replacement: "remove; allocations no longer give up waiting for GCLocker"},
+
+	// Logging replaced by unified logging
+	"PrintGC":                            {Deprecated: 9, Replacement: "-Xlog:gc"},
+	"PrintGCDetails":                     {Deprecated: 9, Replacement: "-Xlog:gc*"},
+	"PrintGCDateStamps":                  {Expired: 9, Replacement: "-Xlog:gc*:file=gc.log:time,uptime"},
+	"PrintGCTimeStamps":                  {Expired: 9, Replacement: "-Xlog:gc*:file=gc.log:uptime"},
+	"PrintGCCause":                       {Expired: 9, Replacement: unifiedLogging},
+	"PrintGCApplicationStoppedTime":      {Expired: 9, Replacement: "-Xlog:safepoint"},
+	"PrintGCApplicationConcurrentTime":   {Expired: 9, Replacement: "-Xlog:safepoint"},
+	"PrintTenuringDistribution":          {Expired: 9, Replacement: "-Xlog:gc+age=trace"},
+	"PrintHeapAtGC":                      {Expired: 9, Replacement: "-Xlog:gc+heap=debug"},
+	"PrintAdaptiveSizePolicy":            {Expired: 9, Replacement: "-Xlog:gc+ergo*=debug"},
+	"PrintReferenceGC":                   {Expired: 9, Replacement: "-Xlog:gc+ref=debug"},
+	"PrintPromotionFailure":              {Expired: 9, Replacement: "-Xlog:gc+promotion=debug"},
+	"UseGCLogFileRotation":               {Expired: 9, Replacement: "-Xlog:gc*:file=gc.log::filecount=5,filesize=20m"},
+	"NumberOfGCLogFiles":                 {Expired: 9, Replacement: "-Xlog:...::filecount=N"},
+	"GCLogFileSize":                      {Expired: 9, Replacement: "-Xlog:...::filesize=N"},
+	"PrintClassHistogramBeforeFullGC":    {Expired: 9, Replacement: "-Xlog:classhisto*=trace"},
+	"PrintClassHistogramAfterFullGC":     {Expired: 9, Replacement: "-Xlog:classhisto*=trace"},
+	"TraceClassLoading":                  {Deprecated: 9, Replacement: "-Xlog:class+load=info"},
+	"TraceClassUnloading":                {Deprecated: 9, Replacement: "-Xlog:class+unload=info"},
+	"PrintSafepointStatistics":           {Obsoleted: 11, Replacement: "-Xlog:safepoint+stats=debug"},
+	"PrintSafepointStatisticsCount":      {Obsoleted: 11, Replacement: "-Xlog:safepoint+stats=debug"},
+	"Xloggc":                             {Deprecated: 9, Replacement: "-Xlog:gc:file=gc.log"},
+	"PrintStringDeduplicationStatistics": {Expired: 9, Replacement: "-Xlog:stringdedup*=debug"},
+}
+
+// Lookup returns the lifecycle of a flag the catalog tracks
+func Lookup(name string) (Lifecycle, bool) {
+	lifecycle, found := lifecycles[name]
+	return lifecycle, found
+}
+
+// Release that added flags newer than JDK 9; older releases reject them as unrecognized
+var introduced = map[string]int{
+	"UseZGC":                  11,
+	"UseEpsilonGC":            11,
+	"UseShenandoahGC":         12,
+	"G1PeriodicGCInterval":    12,
+	"SoftMaxHeapSize":         13,
+	"ZGenerational":           21,
+	"UseCompactObjectHeaders": 24,
+}
+
+// Introduced returns the JDK release that added a flag, or 0 for flags JDK 9 already has
+func Introduced(name string) int {
+	return introduced[name]
+}
+
+// Flags that the JVM rejects unless unlocked, by the option that unlocks them and the last release needing it
+type lockedFlag struct {
+	unlock string
+	until  int // Last JDK release that requires the unlock; 0 means every release
+}
+
+const (
+	unlockDiagnostic   = "UnlockDiagnosticVMOptions"
+	unlockExperimental = "UnlockExperimentalVMOptions"
+)
+
+var lockedFlags = map[string]lockedFlag{
+	"UseZGC":                          {unlockExperimental, 14},
+	"UseShenandoahGC":                 {unlockExperimental, 14},
+	"UseEpsilonGC":                    {unlockExperimental, 0},
+	"G1NewSizePercent":                {unlockExperimental, 0},
+	"G1MaxNewSizePercent":             {unlockExperimental, 0},
+	"G1MixedGCLiveThresholdPercent":   {unlockExperimental, 0},
+	"G1OldCSetRegionThresholdPercent": {unlockExperimental, 0},
+	"G1AdaptiveIHOPNumInitialSamples": {unlockExperimental, 0},
+	"EnableJVMCI":                     {unlockExperimental, 0},
+	"UseJVMCICompiler":                {unlockExperimental, 0},
+	"PrintInlining":                   {unlockDiagnostic, 0},
+	"PrintAssembly":                   {unlockDiagnostic, 0},
+	"LogCompilation":                  {unlockDiagnostic, 0},
+	"DebugNonSafepoints":              {unlockDiagnostic, 0},
+	"GCLockerRetryAllocationCount":    {unlockDiagnostic, 0},
+}
+
+// Unlock returns the option a flag needs before the JVM accepts it, and the last release
+// that needs it (0: every release); option is "" for flags that need none
+func Unlock(name string) (option string, until int) {
+	locked, found := lockedFlags[name]
+	if !found {
+		return "", 0
+	}
+	return locked.unlock, locked.until
+}
+
+// What common flags do, shown with their value in the non-default list
+var explanations = map[string]string{
+	"MaxHeapSize":                    "Maximum Java heap (-Xmx)",
+	"InitialHeapSize":                "Heap committed at startup (-Xms); equal to -Xmx avoids resizing",
+	"NewSize":                        "Initial young generation size (-Xmn)",
+	"MaxNewSize":                     "Maximum young generation size (-Xmn)",
+	"NewRatio":                       "Old/young generation size ratio",
+	"SurvivorRatio":                  "Eden/survivor space size ratio",
+	"MaxTenuringThreshold":           "Young collections an object survives before promotion",
+	"ThreadStackSize":                "Stack size per Java thread (-Xss)",
+	"MetaspaceSize":                  "Metaspace usage that triggers the first metadata GC",
+	"MaxMetaspaceSize":               "Upper bound on class metadata; unlimited by default",
+	"CompressedClassSpaceSize":       "Reserved space for compressed class pointers",
+	"ReservedCodeCacheSize":          "Maximum size of the JIT code cache",
+	"MaxDirectMemorySize":            "Limit on direct ByteBuffer memory; defaults to -Xmx",
+	"MaxRAMPercentage":               "Maximum heap as a percentage of available (container) memory",
+	"InitialRAMPercentage":           "Initial heap as a percentage of available memory",
+	"MinRAMPercentage":               "Maximum heap percentage on small-memory machines",
+	"UseContainerSupport":            "Size heap and threads from cgroup limits",
+	"ActiveProcessorCount":           "Overrides the detected CPU count used for thread pools",
+	"UseG1GC":                        "Garbage-First collector, the default on server-class machines",
+	"UseParallelGC":                  "Throughput collector with parallel stop-the-world phases",
+	"UseSerialGC":                    "Single-threaded collector, for small heaps and single CPUs",
+	"UseZGC":                         "Low-latency concurrent collector with sub-millisecond pauses",
+	"UseShenandoahGC":                "Low-latency concurrent compacting collector",
+	"UseEpsilonGC":                   "No-op collector; the JVM exits when the heap fills",
+	"MaxGCPauseMillis":               "Pause time goal the collector sizes generations for",
+	"GCTimeRatio":                    "Target ratio of application time to GC time",
+	"InitiatingHeapOccupancyPercent": "Old generation occupancy that starts concurrent marking (G1)",
+	"G1UseAdaptiveIHOP":              "Lets G1 move the marking threshold from its allocation and marking predictions",
+	"G1HeapRegionSize":               "Size of G1 regions; objects over half a region are humongous",
+	"G1ReservePercent":               "Heap kept free to avoid evacuation failures (G1)",
+	"G1NewSizePercent":               "Minimum young generation as a percentage of heap (G1)",
+	"G1MaxNewSizePercent":            "Maximum young generation as a percentage of heap (G1)",
+	"ParallelGCThreads":              "Threads used in stop-the-world GC phases",
+	"ConcGCThreads":                  "Threads used in concurrent GC phases",
+	"UseStringDeduplication":         "Deduplicates String backing arrays during GC",
+	"DisableExplicitGC":              "Makes System.gc() a no-op",
+	"ExplicitGCInvokesConcurrent":    "Turns System.gc() into a concurrent cycle instead of a Full GC",
+	"HeapDumpOnOutOfMemoryError":     "Writes a heap dump when an OutOfMemoryError is thrown",
+	"HeapDumpPath":                   "Where OOM heap dumps are written",
+	"ExitOnOutOfMemoryError":         "Exits the JVM on the first OutOfMemoryError",
+	"CrashOnOutOfMemoryError":        "Crashes with a core dump and hs_err file on OutOfMemoryError",
+	"OnOutOfMemoryError":             "Command run on OutOfMemoryError",
+	"ErrorFile":                      "Where hs_err crash logs are written",
+	"AlwaysPreTouch":                 "Touches every heap page at startup; slower start, no page faults later",
+	"UseLargePages":                  "Backs the heap with large pages",
+	"UseTransparentHugePages":        "Uses transparent huge pages through madvise",
+	"UseNUMA":                        "NUMA-aware heap allocation",
+	"UseCompressedOops":              "32-bit object references; only possible below a 32 GB heap",
+	"UseCompressedClassPointers":     "32-bit class pointers in object headers",
+	"TieredCompilation":              "C1 then C2 compilation; disabling slows warm-up",
+	"TieredStopAtLevel":              "Highest compilation tier; 1 uses C1 only",
+	"CICompilerCount":                "Number of JIT compiler threads",
+	"OmitStackTraceInFastThrow":      "Drops stack traces of hot implicit exceptions",
+	"StartFlightRecording":           "Starts a JFR recording at launch",
+	"UnlockDiagnosticVMOptions":      "Allows diagnostic flags",
+	"UnlockExperimentalVMOptions":    "Allows experimental flags",
+	"Xlog":                           "Unified logging configuration",
+	"PrintGCDetails":                 "Detailed GC logging",
+	"Xloggc":                         "GC log file",
+}
+
+// Explain returns what a flag does, or "" for flags not in the catalog
+func Explain(name string) string {
+	return explanations[name]
+}
@@ -0,0 +1,106 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const valueWidth = 40
+
+func (r *Report) PrintReport() {
+	set := r.Flags
+
+	fmt.Println()
+	fmt.Println("🚩 JVM FLAGS AUDIT")
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("   Source:  %s (%s)\n", set.Origin, set.Source)
+	switch {
+	case set.JDKVersion == 0:
+		fmt.Println("   JDK:     unknown (use --jdk to check deprecations for a release)")
+	case set.JDKGuessed:
+		fmt.Printf("   JDK:     %d or later (inferred from the flags present; use --jdk to set it)\n", set.JDKVersion)
+	default:
+		fmt.Printf("   JDK:     %d\n", set.JDKVersion)
+	}
+	fmt.Printf("   Flags:   %d, %d set explicitly\n", len(set.Flags), r.explicitCount())
+	if len(set.Unknown) > 0 {
+		fmt.Printf("   Skipped: %s\n", strings.Join(set.Unknown, " "))
+	}
+
+	printFindings("❌ REMOVED & OBSOLETE", r.Removed)
+	printFindings("⚠️  DEPRECATED", r.Deprecated)
+	printFindings("🔀 CONFLICTS", r.Conflicts)
+	printFindings("🔒 NOT UNLOCKED", r.Locked)
+
+	if len(r.NonDefault) > 0 {
+		fmt.Println()
+		fmt.Println("📋 NON-DEFAULT FLAGS")
+		fmt.Println(strings.Repeat("─", 80))
+		fmt.Printf("%-32s  %-*s  %-12s  %s\n", "Flag", valueWidth/2, "Value", "Origin", "Meaning")
+		for _, flag := range r.NonDefault {
+			explanation := Explain(flag.Name)
+			if explanation == "" {
+				explanation = utils.MutedStyle.Render("-")
+			}
+			fmt.Printf("%-32s  %-*s  %-12s  %s\n", utils.TruncateString(flag.Name, 32), valueWidth/2,
+				utils.TruncateString(flag.displayValue(), valueWidth/2), flag.Origin, explanation)
+		}
+	}
+
+	fmt.Println()
+	critical, warning, info := r.Count("critical"), r.Count("warning"), r.Count("info")
+	switch {
+	case critical > 0:
+		fmt.Println(utils.CriticalStyle.Render(fmt.Sprintf("🔴 %d critical, %d warnings, %d notes: the JVM may not start with these flags",
+			critical, warning, info)))
+	case warning > 0:
+		fmt.Println(utils.WarningStyle.Render(fmt.Sprintf("🟡 %d warnings, %d notes: clean up before the next JDK upgrade",
+			warning, info)))
+	case info > 0:
+		fmt.Println(utils.InfoStyle.Render(fmt.Sprintf("💡 %d notes, no problems found", info)))
+	default:
+		fmt.Println(utils.GoodStyle.Render("✅ No deprecated, removed or conflicting flags"))
+	}
+}
+
+func printFindings(title string, findings []Finding) {
+	if len(findings) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("─", 80))
+	for _, finding := range findings {
+		fmt.Println(utils.GetSeverityStyle(finding.Severity).Render("• " + finding.Description))
+		fmt.Printf("  → %s\n", finding.Recommendation)
+	}
+}
+
+func (r *Report) explicitCount() int {
+	count := 0
+	for name := range r.Flags.Flags {
+		if r.Flags.explicit(name) {
+			count++
+		}
+	}
+	return count
+}
+
+// displayValue shows sizes in human units, whether given as "2g" or in bytes
+func (f *Flag) displayValue() string {
+	if !isSizeFlag(f.Name) {
+		return f.Value
+	}
+	size, err := utils.ParseMemorySize(f.Value)
+	if err != nil || size < utils.KB {
+		return f.Value
+	}
+	return size.String()
+}
+
+func isSizeFlag(name string) bool {
+	return strings.HasSuffix(name, "Size") || strings.HasSuffix(name, "Space")
+}
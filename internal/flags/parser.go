@@ -0,0 +1,320 @@
+package flags
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// JDK 9+:  bool UseG1GC                                  = true                                      {product} {ergonomic}
+	// JDK 8:   bool UseG1GC                                  := true                                {product}
+	flagsFinalPattern = regexp.MustCompile(`^\s*(\S+)\s+(\w+)\s+(:?=)\s*(.*?)\s*\{([^}]*)\}(?:\s*\{([^}]*)\})?\s*$`)
+
+	// "21.0.8+9-Ubuntu", "17", "1.8.0_312", "OpenJDK 64-Bit Server VM (21.0.8+9) ..."
+	versionPattern = regexp.MustCompile(`(?:^|[^\d.])(1\.(\d+)|\d+)(?:[.+_-]|$)`)
+)
+
+// X options that set a flag, normalized so rules only deal with flag names
+var xFlags = []struct {
+	prefix string
+	flags  []string
+}{
+	{"-Xmx", []string{"MaxHeapSize"}},
+	{"-Xms", []string{"InitialHeapSize"}},
+	{"-Xmn", []string{"NewSize", "MaxNewSize"}},
+	{"-Xss", []string{"ThreadStackSize"}},
+}
+
+// Options followed by a separate value argument
+var optionsWithValue = map[string]bool{
+	"-cp": true, "-classpath": true, "--class-path": true, "-p": true, "--module-path": true,
+	"--add-opens": true, "--add-exports": true, "--add-modules": true, "--add-reads": true,
+	"--patch-module": true, "--upgrade-module-path": true, "--limit-modules": true,
+}
+
+/*
+ * ParseFile reads a JVM's flags from any of:
+ *
+ *   java -XX:+PrintFlagsFinal -version     (also jcmd <pid> VM.flags -all)
+ *   jcmd <pid> VM.command_line             (the jvm_args line)
+ *   jcmd <pid> VM.flags                    (one line of -XX options)
+ *   a plain java command line or one argument per line
+ *
+ * PrintFlagsFinal gives every flag with its origin; command lines give only
+ * the options set, all with origin "command line".
+ */
+func ParseFile(filename string) (*FlagSet, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open flags file: %w", err)
+	}
+	defer file.Close()
+
+	return Parse(file, filename)
+}
+
+func Parse(reader io.Reader, origin string) (*FlagSet, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read flags: %w", err)
+	}
+
+	flagsFinal := 0
+	for _, line := range lines {
+		if flagsFinalPattern.MatchString(line) {
+			flagsFinal++
+		}
+	}
+	if flagsFinal > 0 {
+		return parseFlagsFinal(lines, origin)
+	}
+
+	set := NewFlagSet(SourceCommandLine, origin)
+	var args []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "jvm_args:"); ok {
+			// jcmd VM.command_line: only the JVM arguments matter
+			args = SplitArguments(rest)
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") || isPIDLine(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "java_command:") || strings.HasPrefix(line, "java_class_path") ||
+			strings.HasPrefix(line, "Launcher Type:") || strings.HasPrefix(line, "VM Arguments:") {
+			continue
+		}
+		args = append(args, SplitArguments(line)...)
+	}
+	ParseArguments(set, args)
+
+	if len(set.Flags) == 0 {
+		return nil, fmt.Errorf("no JVM flags found (expected -XX:+PrintFlagsFinal output or JVM arguments)")
+	}
+	return set, nil
+}
+
+func parseFlagsFinal(lines []string, origin string) (*FlagSet, error) {
+	set := NewFlagSet(SourcePrintFlagsFinal, origin)
+	jdk8 := false
+
+	for _, line := range lines {
+		matches := flagsFinalPattern.FindStringSubmatch(line)
+		if matches == nil {
+			if version := ParseJDKVersion(line); strings.Contains(line, "version") && version > 0 {
+				// -version output printed after the flags
+				set.JDKVersion = version
+			}
+			continue
+		}
+
+		flag := &Flag{
+			Type:   matches[1],
+			Name:   matches[2],
+			Value:  matches[4],
+			Kind:   normalizeKind(matches[5]),
+			Origin: strings.TrimSpace(matches[6]),
+		}
+		if flag.Origin == "" {
+			// JDK 8 marks non-default values with := and doesn't say why
+			jdk8 = true
+			flag.Origin = OriginDefault
+			if matches[3] == ":=" {
+				flag.Origin = OriginOther
+			}
+		}
+		set.add(flag)
+	}
+
+	if set.JDKVersion == 0 {
+		if jdk8 {
+			set.JDKVersion = 8
+		} else {
+			set.JDKVersion, set.JDKGuessed = guessVersion(set), true
+		}
+	}
+	return set, nil
+}
+
+// ParseArguments adds the JVM options in args to set; parsing stops at the main class or -jar
+func ParseArguments(set *FlagSet, args []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case optionsWithValue[arg]:
+			i++
+		case arg == "-jar" || !strings.HasPrefix(arg, "-"):
+			return // Main class or jar; the rest are application arguments
+		case strings.HasPrefix(arg, "-XX:"):
+			flag := parseXXOption(arg)
+			if flag == nil {
+				set.Unknown = append(set.Unknown, arg)
+				continue
+			}
+			set.add(flag)
+		case strings.HasPrefix(arg, "-X"):
+			parseXOption(set, arg)
+		}
+	}
+}
+
+// FromArguments builds a FlagSet from JVM arguments as JMX or JFR report them, with the JVM's version string
+func FromArguments(source, origin string, args []string, version string) *FlagSet {
+	set := NewFlagSet(source, origin)
+	set.JDKVersion = ParseJDKVersion(version)
+	ParseArguments(set, args)
+	return set
+}
+
+func parseXXOption(arg string) *Flag {
+	option := strings.TrimPrefix(arg, "-XX:")
+	flag := &Flag{Origin: OriginCommandLine, Arg: arg}
+
+	switch {
+	case strings.HasPrefix(option, "+"):
+		flag.Name, flag.Value, flag.Type = option[1:], "true", "bool"
+	case strings.HasPrefix(option, "-"):
+		flag.Name, flag.Value, flag.Type = option[1:], "false", "bool"
+	default:
+		name, value, found := strings.Cut(option, "=")
+		if !found {
+			return nil
+		}
+		flag.Name, flag.Value = name, value
+	}
+
+	if flag.Name == "" {
+		return nil
+	}
+	return flag
+}
+
+func parseXOption(set *FlagSet, arg string) {
+	for _, x := range xFlags {
+		if value, ok := strings.CutPrefix(arg, x.prefix); ok && value != "" {
+			for _, name := range x.flags {
+				set.add(&Flag{Name: name, Value: value, Origin: OriginCommandLine, Arg: arg})
+			}
+			return
+		}
+	}
+
+	// Other X options keep their name, e.g. "-Xloggc" or "-Xlog", so rules can refer to them
+	name, value, _ := strings.Cut(arg[1:], ":")
+	if existing := set.Get(name); existing != nil && name == "Xlog" {
+		// -Xlog may be repeated; keep every configuration
+		value = existing.Value + " " + value
+	}
+	set.add(&Flag{Name: name, Value: value, Origin: OriginCommandLine, Kind: "X option", Arg: arg})
+}
+
+// SplitArguments splits a command line on whitespace, honouring single and double quotes
+func SplitArguments(line string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, char := range line {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case char == '"' || char == '\'':
+			quote = char
+			inArg = true
+		case char == ' ' || char == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(char)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// isPIDLine matches the "12345:" line jcmd prints before its output
+func isPIDLine(line string) bool {
+	pid, found := strings.CutSuffix(line, ":")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(pid)
+	return err == nil
+}
+
+func normalizeKind(kind string) string {
+	for _, special := range []string{"diagnostic", "experimental", "manageable"} {
+		if strings.Contains(kind, special) {
+			return special
+		}
+	}
+	return "product"
+}
+
+// ParseJDKVersion extracts the feature release from a version string: "1.8.0_312" is 8, "21.0.8+9" is 21
+func ParseJDKVersion(version string) int {
+	if _, inner, found := strings.Cut(version, "("); found {
+		// "OpenJDK 64-Bit Server VM (21.0.8+9) for linux-amd64 ..." carries the version in parentheses
+		version, _, _ = strings.Cut(inner, ")")
+	}
+	matches := versionPattern.FindStringSubmatch(version)
+	if matches == nil {
+		return 0
+	}
+	number := matches[1]
+	if matches[2] != "" {
+		number = matches[2]
+	}
+	feature, err := strconv.Atoi(number)
+	if err != nil || feature < 6 || feature > 99 {
+		return 0
+	}
+	return feature
+}
+
+// Flags that exist only from a release on, newest first, to bound an unknown version from PrintFlagsFinal
+var versionMarkers = []struct {
+	flag    string
+	version int
+}{
+	{"UseCompactObjectHeaders", 24},
+	{"ZGenerational", 21},
+	{"UseContainerCpuShares", 11}, // Removed in 19, so only a lower bound
+	{"UseContainerSupport", 10},
+}
+
+// guessVersion bounds a JDK 9+ version by the flags present; biased locking is gone from 18
+func guessVersion(set *FlagSet) int {
+	for _, marker := range versionMarkers {
+		if set.Get(marker.flag) != nil {
+			if marker.version < 18 && set.Get("UseBiasedLocking") == nil && set.Get("UseZGC") != nil {
+				return 18
+			}
+			return marker.version
+		}
+	}
+	return 9
+}
@@ -0,0 +1,84 @@
+package flags
+
+// Where a flag's value came from, as PrintFlagsFinal reports it
+const (
+	OriginDefault     = "default"
+	OriginCommandLine = "command line"
+	OriginErgonomic   = "ergonomic"
+	OriginConfigFile  = "config file"
+	OriginEnvironment = "environment"
+	OriginManagement  = "management"
+	OriginAttach      = "attach"
+	OriginOther       = "other"
+)
+
+// Flag is one -XX option, or a -X option normalized to the flag it sets
+type Flag struct {
+	Name   string
+	Type   string // e.g. "bool", "size_t", "uintx"; empty when read from a command line
+	Value  string // "true"/"false" for booleans
+	Origin string
+	Kind   string // e.g. "product", "diagnostic", "experimental"; empty when unknown
+	Arg    string // Argument as given on the command line, e.g. "-Xmx2g"
+}
+
+// Source formats a FlagSet can be read from
+const (
+	SourcePrintFlagsFinal = "PrintFlagsFinal"
+	SourceCommandLine     = "command line"
+	SourceJMX             = "JMX"
+	SourceJFR             = "JFR recording"
+)
+
+// FlagSet is the configuration of one JVM
+type FlagSet struct {
+	Source     string
+	Origin     string // File name or JMX target
+	JDKVersion int    // Feature release, e.g. 8, 17, 21; 0 when unknown
+	JDKGuessed bool   // Version inferred from which flags exist; it is a lower bound
+
+	Flags map[string]*Flag
+	Order []string // Flag names in input order
+
+	Unknown []string // Arguments that aren't JVM options, e.g. an unparseable -XX
+}
+
+func NewFlagSet(source, origin string) *FlagSet {
+	return &FlagSet{Source: source, Origin: origin, Flags: make(map[string]*Flag)}
+}
+
+func (s *FlagSet) add(flag *Flag) {
+	if _, exists := s.Flags[flag.Name]; !exists {
+		s.Order = append(s.Order, flag.Name)
+	}
+	// Later arguments override earlier ones, as in the JVM
+	s.Flags[flag.Name] = flag
+}
+
+// Get returns a flag, or nil when it isn't in the set
+func (s *FlagSet) Get(name string) *Flag {
+	return s.Flags[name]
+}
+
+// Enabled reports whether a boolean flag is set to true
+func (s *FlagSet) Enabled(name string) bool {
+	flag := s.Flags[name]
+	return flag != nil && flag.Value == "true"
+}
+
+// Set reports whether a flag was chosen explicitly rather than left at its default
+func (s *FlagSet) Set(name string) bool {
+	flag := s.Flags[name]
+	return flag != nil && flag.Origin != OriginDefault
+}
+
+// NonDefault returns the flags not at their default, in input order
+func (s *FlagSet) NonDefault() []*Flag {
+	var flags []*Flag
+	for _, name := range s.Order {
+		if flag := s.Flags[name]; flag.Origin != OriginDefault {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
@@ -45,6 +45,53 @@ const (
 	TerminationTarget   = 5 * time.Millisecond
 	RefProcessingTarget = 15 * time.Millisecond
 
+	// Worker balance: a pause straggles when waiting on its slowest worker
+	// takes this share of it, and that has to happen in this share of pauses
+	StragglerPauseShare  = 0.25
+	StragglerRateWarning = 0.2
+	MinEventsForBalance  = 10
+
+	// GC frequency: collections closer than BackToBackGap leave the
+	// application almost no time to run
+	BackToBackGap         = time.Second
+	BackToBackRateWarning = 0.1
+	MinGapsForFrequency   = 10
+	SpiralMinCollections  = 5
+	SpiralGapTolerance    = 1.2 // A gap may grow this much over the previous one and still continue the spiral
+	SpiralShrinkFactor    = 4.0 // The first gap of a spiral is at least this many times its last
+
+	// Pause target compliance: one GC type is behind the misses when it
+	// accounts for this share of them
+	CulpritMissShare = 0.8
+	MinTargetMisses  = 5
+
+	// IHOP: marking should finish with IHOPMarginGoal of the heap free of old
+	// data; a suggested threshold leaves room for IHOPSafetyFactor times the
+	// largest old growth seen during marking
+	IHOPMarginGoal   = 0.15
+	IHOPIdleMargin   = 0.5 // Old data under half the heap when marking ends: marking starts early
+	IHOPSafetyFactor = 1.25
+	G1ReserveShare   = 0.10 // -XX:G1ReservePercent default, kept free for evacuation
+	MinIHOPCycles    = 3
+	MinIHOPPercent   = 10
+	MaxIHOPPercent   = 80
+
+	// Full GC phases: one dominates with FullPhaseDominantShare of the phase
+	// time; a marking sub-phase gets its own advice at FullSubphaseShare of marking
+	FullPhaseDominantShare = 0.5
+	FullSubphaseShare      = 0.3
+
+	// GCLocker: collections it started are worth tuning for at this share of
+	// all collections, or once it stalled this many allocations
+	GCLockerShareWarning = 0.05
+	MinGCLockerEvents    = 3
+
+	// Humongous sizes: a region size is suggested once it makes this share
+	// of the humongous objects normal and the heap keeps MinHeapRegions
+	HumongousNormalGoal = 0.8
+	MinHeapRegions      = 512
+	MinHumongousObjects = 5
+
 	// Leak detection
 	LeakGrowthCritical = 5.0
 	LeakGrowthWarning  = 1.0
@@ -59,6 +106,14 @@ const (
 	SurvivorOverflowWarning  = 0.2
 	SurvivorOverflowCritical = 0.3
 
+	// Survivor sizing: the suggested survivor space overflows in at most
+	// SurvivorOverflowGoal of young collections; one SurvivorFullShare full
+	// has overflowed
+	SurvivorOverflowGoal       = 0.05
+	SurvivorFullShare          = 0.95
+	DefaultTargetSurvivorRatio = 50 // % of survivor space the tenuring threshold aims to fill
+	MinSurvivorSamples         = 10
+
 	// Humongous thresholds
 	HumongousPercentCritical = 80.0
 	HumongousPercentWarning  = 50.0
@@ -204,10 +259,12 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 
 		// ===== ANALYZE INDIVIDUAL EVENT =====
 
-		// Memory pressure analysis
-		if event.HeapTotal > 0 {
-			event.HeapUtilizationBefore = float64(event.HeapBefore) / float64(event.HeapTotal)
-			event.HeapUtilizationAfter = float64(event.HeapAfter) / float64(event.HeapTotal)
+		// Memory pressure analysis; archive regions hold CDS objects the GC can
+		// neither collect nor use for allocation, so they count as neither
+		analysis.ArchiveRegions = max(analysis.ArchiveRegions, event.ArchiveRegionsBefore, event.ArchiveRegionsAfter)
+		if usable := event.HeapTotal - event.ArchiveMemoryAfter; usable > 0 {
+			event.HeapUtilizationBefore = float64(event.HeapBefore-event.ArchiveMemoryBefore) / float64(usable)
+			event.HeapUtilizationAfter = float64(event.HeapAfter-event.ArchiveMemoryAfter) / float64(usable)
 
 			totalHeapUtil += event.HeapUtilizationAfter
 			heapUtilCount++
@@ -216,8 +273,8 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 		}
 
 		// Region utilization
-		if event.HeapTotalRegions > 0 && event.HeapUsedRegionsAfter > 0 {
-			event.RegionUtilization = float64(event.HeapUsedRegionsAfter) / float64(event.HeapTotalRegions)
+		if usable := event.HeapTotalRegions - event.ArchiveRegionsAfter; usable > 0 && event.HeapUsedRegionsAfter > event.ArchiveRegionsAfter {
+			event.RegionUtilization = float64(event.HeapUsedRegionsAfter-event.ArchiveRegionsAfter) / float64(usable)
 			totalRegionUtil += event.RegionUtilization
 			regionUtilCount++
 		}
@@ -328,6 +385,7 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 
 		// Calculate pause target misses and long pauses
 		calculatePauseAnalysis(events, analysis)
+		analysis.PauseTargets = calculatePauseTargets(events, analysis.EstimatedPauseTarget)
 	}
 
 	// Collection efficiency
@@ -358,6 +416,9 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 	analysis.PhaseStats = calculatePhaseStats(totalObjectCopy, totalRootScan, totalTermination, totalRefProcessing,
 		objectCopyCount, rootScanCount, terminationCount, refProcessingCount)
 
+	analysis.WorkerBalance = calculateWorkerBalance(events)
+	analysis.Frequency = calculateFrequency(events)
+
 	// Allocation rate analysis
 	analysis.AllocationRate = calculateAllocationRate(allocationEvents, analysis.TotalRuntime)
 	analysis.AllocationBurstCount = calculateAllocationBursts(allocationEvents, analysis.AllocationRate)
@@ -370,9 +431,11 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 	analysis.MaxOldGrowthRatio = analysis.PromotionStats.MaxOldGrowthRatio
 	analysis.SurvivorOverflowRate = analysis.PromotionStats.SurvivorOverflowRate
 	analysis.ConsecutiveGrowthSpikes = max(consecutiveGrowthSpikes, currentSpikeCount)
+	analysis.Survivor = calculateSurvivorModel(events, analysis)
 
 	// Humongous analysis
 	analysis.HumongousStats = calculateHumongousStats(humongousEvents)
+	analysis.HumongousSizes = calculateHumongousHistogram(events, analysis)
 
 	// Memory leak analysis
 	if len(memoryTrendPoints) >= MinEventsForTrend && analysis.TotalRuntime >= MinTimeForTrend {
@@ -382,6 +445,9 @@ func AnalyzeGCLogs(events []*GCEvent, analysis *GCAnalysis) {
 	// Concurrent marking analysis
 	analysis.ConcurrentMarkingKeepup = assessConcurrentMarkingKeepup(analysis.YoungGCCount, analysis.MixedGCCount)
 	analysis.ConcurrentCycleDuration = estimateConcurrentCycleDuration(events)
+	analysis.IHOP = calculateIHOP(events)
+	analysis.FullGCPhases = calculateFullGCPhases(events)
+	analysis.GCLocker = calculateGCLocker(events, analysis.GCLocker)
 
 	// Variance and advanced metrics
 	analysis.PauseTimeVariance = utils.CalculateDurationVariance(durations, analysis.AvgPause)
@@ -400,6 +466,10 @@ func CategorizeGCType(gcType string) string {
 		return "Mixed"
 	case strings.Contains(eventType, "full"):
 		return "Full"
+	case strings.Contains(eventType, "remark"):
+		return GCTypeRemark
+	case strings.Contains(eventType, "cleanup"):
+		return GCTypeCleanup
 	case strings.Contains(eventType, "concurrent"),
 		strings.Contains(eventType, "abort"):
 		return "Concurrent Mark"
@@ -826,6 +896,8 @@ func (analysis *GCAnalysis) setIssueFlags() {
 	analysis.HasCriticalPromotion = analysis.MaxOldGrowthRatio > OldRegionGrowthCritical || analysis.AvgPromotionRate > PromotionRateCritical
 	analysis.HasCriticalHumongousLeak = analysis.HumongousStats.IsLeak && analysis.HumongousStats.HeapPercentage > HumongousPercentCritical
 	analysis.HasCriticalConcurrentMarkAbort = analysis.ConcurrentMarkAbortCount >= 2
+	analysis.HasCriticalDeathSpiral = analysis.Frequency.Spiral.Collections > 0
+	analysis.HasCriticalGCLocker = analysis.GCLocker.RetryFailures > 0
 
 	// Warning issues
 	analysis.HasWarningMemoryLeak = analysis.MemoryTrend.LeakSeverity == "warning"
@@ -835,10 +907,19 @@ func (analysis *GCAnalysis) setIssueFlags() {
 	analysis.HasWarningPromotion = (analysis.MaxOldGrowthRatio > OldRegionGrowthWarning || analysis.AvgPromotionRate > PromotionRateWarning) && !analysis.HasCriticalPromotion
 	analysis.HasWarningHumongousUsage = analysis.HumongousStats.HeapPercentage > HumongousPercentWarning && !analysis.HasCriticalHumongousLeak
 	analysis.HasWarningConcurrentMark = !analysis.ConcurrentMarkingKeepup
+	analysis.HasWarningLateMarking = analysis.IHOP.StartsLate()
+	analysis.HasWarningGCLocker = analysis.GCLocker.Significant() && !analysis.HasCriticalGCLocker
 	analysis.HasWarningAllocationRate = analysis.AllocationRate > AllocRateHigh
 	analysis.HasWarningCollectionEff = analysis.MixedGCCount == 0 && analysis.YoungGCCount > 50
+	analysis.HasWarningWorkerBalance = analysis.WorkerBalance.Events >= MinEventsForBalance &&
+		analysis.WorkerBalance.StragglerRate >= StragglerRateWarning
+	analysis.HasWarningBackToBack = analysis.Frequency.Gaps >= MinGapsForFrequency &&
+		analysis.Frequency.BackToBackRate >= BackToBackRateWarning && !analysis.HasCriticalDeathSpiral
 
 	// Info issues
 	analysis.HasInfoAllocationPattern = analysis.AllocationRate > AllocRateModerate && !analysis.HasWarningAllocationRate
 	analysis.HasInfoPhaseOptimization = analysis.PhaseStats.HasPhaseIssues
+	analysis.HasInfoEarlyMarking = analysis.IHOP.StartsEarly()
+	analysis.HasInfoHumongousSizing = analysis.HumongousSizes.Significant() && !analysis.HasCriticalHumongousLeak &&
+		!analysis.HasWarningHumongousUsage
 }
@@ -0,0 +1,90 @@
+package gc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Bookmark marks a GC event by its GC ID, optionally with a note such as "deploy v2.3 here"
+type Bookmark struct {
+	ID        int       `json:"id"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Note      string    `json:"note,omitempty"`
+}
+
+// Bookmarks are saved next to the log they belong to, so later sessions see them
+type Bookmarks struct {
+	path  string
+	Items []Bookmark `json:"bookmarks"`
+}
+
+// BookmarksPath is the file holding the bookmarks of a log, e.g. gc.log.bookmarks.json
+func BookmarksPath(logFile string) string {
+	return logFile + ".bookmarks.json"
+}
+
+// LoadBookmarks reads the bookmarks at path; a missing file has none
+func LoadBookmarks(path string) (*Bookmarks, error) {
+	bookmarks := &Bookmarks{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return bookmarks, nil
+	}
+	if err != nil {
+		return bookmarks, fmt.Errorf("unable to read bookmarks: %w", err)
+	}
+	if err := json.Unmarshal(data, bookmarks); err != nil {
+		return bookmarks, fmt.Errorf("invalid bookmarks file %s: %w", path, err)
+	}
+	return bookmarks, nil
+}
+
+// Save writes the bookmarks back to their file, removing it when none are left
+func (b *Bookmarks) Save() error {
+	if b.path == "" {
+		return nil
+	}
+	if len(b.Items) == 0 {
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove bookmarks: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to save bookmarks: %w", err)
+	}
+	return nil
+}
+
+// Get returns the bookmark of an event
+func (b *Bookmarks) Get(event *GCEvent) (Bookmark, bool) {
+	if b == nil {
+		return Bookmark{}, false
+	}
+	index := slices.IndexFunc(b.Items, func(item Bookmark) bool { return item.ID == event.ID })
+	if index < 0 {
+		return Bookmark{}, false
+	}
+	return b.Items[index], true
+}
+
+// Set bookmarks an event with a note, replacing any note it had
+func (b *Bookmarks) Set(event *GCEvent, note string) {
+	b.Remove(event)
+	b.Items = append(b.Items, Bookmark{ID: event.ID, Timestamp: event.Timestamp, Note: note})
+	slices.SortFunc(b.Items, func(x, y Bookmark) int { return x.ID - y.ID })
+}
+
+// Remove drops the bookmark of an event, if any
+func (b *Bookmarks) Remove(event *GCEvent) {
+	b.Items = slices.DeleteFunc(b.Items, func(item Bookmark) bool { return item.ID == event.ID })
+}
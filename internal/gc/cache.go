@@ -0,0 +1,192 @@
+package gc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+ * Parsing a multi-gigabyte log takes minutes, so analyzed logs are kept
+ * under ~/.jdiag/cache keyed by the SHA-256 of the file's content:
+ * reopening a log in the TUI, or rendering it in a second format, loads the
+ * cached events and analysis instead. Hashing reads the file once, far
+ * faster than parsing it. The key is the content, not the path, so a log
+ * that's renamed or rotated still hits and one that's appended to misses.
+ *
+ * Entries are gob encoded and written through a temporary file, so a crash
+ * never leaves half an entry behind. An entry that doesn't decode, or was
+ * written for another cacheVersion, is a miss and gets replaced; bump
+ * cacheVersion whenever the parser or analysis changes what they produce.
+ */
+
+const (
+	cacheVersion  = 8
+	CacheMaxBytes = 2 << 30 // Oldest entries are removed once the cache grows past this
+)
+
+// Cache holds analyzed logs; a nil Cache parses every time
+type Cache struct {
+	dir      string
+	maxBytes int64
+}
+
+type cacheEntry struct {
+	Version  int
+	Events   []*GCEvent
+	Analysis *GCAnalysis
+}
+
+// DefaultCacheDir is ~/.jdiag/cache, or "" when the home directory is unknown
+func DefaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".jdiag", "cache")
+}
+
+// NewCache keeps entries in dir; an empty dir disables caching
+func NewCache(dir string) *Cache {
+	if dir == "" {
+		return nil
+	}
+	return &Cache{dir: dir, maxBytes: CacheMaxBytes}
+}
+
+// CacheKey is the SHA-256 of the file's content, compressed or not
+func CacheKey(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash file: %v", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+/*
+ * ParseAndAnalyze returns the analyzed events of a log, from the cache when
+ * it has them and otherwise by parsing with parser. hit reports a cache hit.
+ * A parse stopped early isn't cached, and neither failing to hash the log nor
+ * failing to save the entry stops the analysis: the cache only saves time.
+ */
+func (c *Cache) ParseAndAnalyze(filename string, parser *Parser) (events []*GCEvent, analysis *GCAnalysis, hit bool, err error) {
+	var key string
+	if c != nil {
+		key, _ = CacheKey(filename)
+	}
+	if key != "" {
+		if events, analysis, ok := c.Load(key); ok {
+			return events, analysis, true, nil
+		}
+	}
+
+	events, analysis, err = parser.ParseFile(filename)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	AnalyzeGCLogs(events, analysis)
+
+	if key != "" && !analysis.Interrupted && len(events) > 0 {
+		_ = c.Store(key, events, analysis)
+	}
+	return events, analysis, false, nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".gob")
+}
+
+// Load reads the entry for key; any failure is a miss
+func (c *Cache) Load(key string) ([]*GCEvent, *GCAnalysis, bool) {
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, nil, false
+	}
+	defer file.Close()
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&entry); err != nil ||
+		entry.Version != cacheVersion || entry.Analysis == nil {
+		return nil, nil, false
+	}
+
+	// Marks the entry recently used, so pruning removes the ones nobody opens
+	now := time.Now()
+	_ = os.Chtimes(c.path(key), now, now)
+	return entry.Events, entry.Analysis, true
+}
+
+// Store saves the analyzed events under key, then prunes the cache back to its size limit
+func (c *Cache) Store(key string, events []*GCEvent, analysis *GCAnalysis) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create cache entry: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	writer := bufio.NewWriter(temp)
+	err = gob.NewEncoder(writer).Encode(cacheEntry{Version: cacheVersion, Events: events, Analysis: analysis})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("unable to save cache entry: %w", err)
+	}
+	c.prune()
+	return nil
+}
+
+// prune removes the least recently used entries until the cache fits in maxBytes
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".gob") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, info)
+			total += info.Size()
+		}
+	}
+
+	slices.SortFunc(files, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, file := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if os.Remove(filepath.Join(c.dir, file.Name())) == nil {
+			total -= file.Size()
+		}
+	}
+}
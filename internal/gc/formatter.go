@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/mabhi256/jdiag/utils"
 )
 
 func (analysis *GCAnalysis) PrintSummary() {
@@ -22,10 +24,10 @@ func (analysis *GCAnalysis) PrintSummary() {
 	throughputIcon, throughputStatus := getThroughputStatusWithIcon(analysis.Throughput)
 	gcTimeMs := analysis.TotalGCTime.Milliseconds()
 
-	fmt.Printf("%s Application Throughput: %.1f%% (%s)\n",
-		throughputIcon, analysis.Throughput, throughputStatus)
-	fmt.Printf("   GC Overhead: %dms of %v total runtime (%.1f%%)\n\n",
-		gcTimeMs, analysis.TotalRuntime, 100.0-analysis.Throughput)
+	fmt.Printf("%s Application Throughput: %s (%s)\n",
+		throughputIcon, utils.FormatPercent(analysis.Throughput), throughputStatus)
+	fmt.Printf("   GC Overhead: %dms of %v total runtime (%s)\n\n",
+		gcTimeMs, analysis.TotalRuntime, utils.FormatPercent(100.0-analysis.Throughput))
 
 	// Pause Time Analysis
 	fmt.Println("⏱️  RESPONSE TIME IMPACT")
@@ -38,10 +40,10 @@ func (analysis *GCAnalysis) PrintSummary() {
 
 	pauseIcon, pauseAssessment := getPauseAssessmentWithIcon(analysis.MaxPause)
 
-	fmt.Printf("%s Maximum Pause: %.1fms (%s)\n", pauseIcon, maxPauseMs, pauseAssessment)
-	fmt.Printf("   Average Pause: %.1fms\n", avgPauseMs)
-	fmt.Printf("   95th Percentile: %.1fms\n", p95PauseMs)
-	fmt.Printf("   99th Percentile: %.1fms\n\n", p99PauseMs)
+	fmt.Printf("%s Maximum Pause: %s (%s)\n", pauseIcon, utils.FormatMillis(maxPauseMs), pauseAssessment)
+	fmt.Printf("   Average Pause: %s\n", utils.FormatMillis(avgPauseMs))
+	fmt.Printf("   95th Percentile: %s\n", utils.FormatMillis(p95PauseMs))
+	fmt.Printf("   99th Percentile: %s\n\n", utils.FormatMillis(p99PauseMs))
 
 	// Collection Breakdown
 	fmt.Println("🔄 COLLECTION BREAKDOWN")
@@ -49,8 +51,8 @@ func (analysis *GCAnalysis) PrintSummary() {
 
 	if analysis.YoungGCCount > 0 {
 		avgYoungMs := float64(analysis.TotalGCTime.Nanoseconds()) / float64(analysis.YoungGCCount) / 1e6
-		fmt.Printf("✅ Young Generation: %d collections (%.1fms avg)\n",
-			analysis.YoungGCCount, avgYoungMs)
+		fmt.Printf("✅ Young Generation: %d collections (%s avg)\n",
+			analysis.YoungGCCount, utils.FormatMillis(avgYoungMs))
 	}
 
 	if analysis.MixedGCCount > 0 {
@@ -66,16 +68,18 @@ func (analysis *GCAnalysis) PrintSummary() {
 	}
 
 	if analysis.AllocationRate > 0 {
-		fmt.Printf("📊 Allocation Rate: %.1f MB/sec\n", analysis.AllocationRate)
+		fmt.Printf("📊 Allocation Rate: %s/sec\n", utils.FormatMB(analysis.AllocationRate))
 	}
 
 	// G1GC-specific metrics if available
 	if analysis.YoungCollectionEfficiency > 0 {
-		fmt.Printf("⚡ Young Collection Efficiency: %.1f%%\n", analysis.YoungCollectionEfficiency*100)
+		fmt.Printf("⚡ Young Collection Efficiency: %s\n", utils.FormatPercent(analysis.YoungCollectionEfficiency*100))
 	}
 	if analysis.MixedCollectionEfficiency > 0 {
-		fmt.Printf("🔄 Mixed Collection Efficiency: %.1f%%\n", analysis.MixedCollectionEfficiency*100)
+		fmt.Printf("🔄 Mixed Collection Efficiency: %s\n", utils.FormatPercent(analysis.MixedCollectionEfficiency*100))
 	}
+
+	analysis.printLoggingRecommendations(false)
 }
 
 func (analysis *GCAnalysis) PrintDetailed() {
@@ -92,6 +96,9 @@ func (analysis *GCAnalysis) PrintDetailed() {
 	fmt.Printf("JVM Version:        %s\n", analysis.JVMVersion)
 	fmt.Printf("Maximum Heap Size:  %s\n", analysis.HeapMax)
 	fmt.Printf("Heap Region Size:   %s\n", analysis.HeapRegionSize)
+	if cds := analysis.cdsSummary(); cds != "" {
+		fmt.Printf("Class Data Sharing: %s\n", cds)
+	}
 	fmt.Println()
 
 	// Performance Metrics
@@ -99,11 +106,11 @@ func (analysis *GCAnalysis) PrintDetailed() {
 	fmt.Println(strings.Repeat("─", 50))
 	fmt.Printf("Application Runtime:    %v\n", analysis.TotalRuntime.Round(time.Millisecond))
 	fmt.Printf("Total GC Time:          %v\n", analysis.TotalGCTime.Round(time.Millisecond))
-	fmt.Printf("GC Overhead:            %.2f%%\n", 100.0-analysis.Throughput)
-	fmt.Printf("Application Throughput: %.2f%%\n", analysis.Throughput)
+	fmt.Printf("GC Overhead:            %s\n", utils.Precision(2).Percent(100.0-analysis.Throughput))
+	fmt.Printf("Application Throughput: %s\n", utils.Precision(2).Percent(analysis.Throughput))
 
 	if analysis.AllocationRate > 0 {
-		fmt.Printf("Allocation Rate:        %.2f MB/sec", analysis.AllocationRate)
+		fmt.Printf("Allocation Rate:        %s/sec", utils.Precision(2).MB(analysis.AllocationRate))
 		if analysis.AllocationRate > 100 {
 			fmt.Printf(" ⚠️  [High - consider optimization]")
 		} else if analysis.AllocationRate > 50 {
@@ -118,22 +125,22 @@ func (analysis *GCAnalysis) PrintDetailed() {
 		fmt.Println("🔧 G1GC COLLECTION EFFICIENCY")
 		fmt.Println(strings.Repeat("─", 50))
 		if analysis.YoungCollectionEfficiency > 0 {
-			fmt.Printf("Young Generation:       %.1f%% efficiency\n", analysis.YoungCollectionEfficiency*100)
+			fmt.Printf("Young Generation:       %s efficiency\n", utils.FormatPercent(analysis.YoungCollectionEfficiency*100))
 		}
 		if analysis.MixedCollectionEfficiency > 0 {
-			fmt.Printf("Mixed Collections:      %.1f%% efficiency\n", analysis.MixedCollectionEfficiency*100)
+			fmt.Printf("Mixed Collections:      %s efficiency\n", utils.FormatPercent(analysis.MixedCollectionEfficiency*100))
 		}
 		if analysis.MixedToYoungRatio > 0 {
-			fmt.Printf("Mixed/Young Ratio:      %.1f%% mixed collections\n", analysis.MixedToYoungRatio*100)
+			fmt.Printf("Mixed/Young Ratio:      %s mixed collections\n", utils.FormatPercent(analysis.MixedToYoungRatio*100))
 		}
 		if analysis.PauseTimeVariance > 0 {
-			fmt.Printf("Pause Time Variance:    %.1f%% coefficient of variation\n", analysis.PauseTimeVariance*100)
+			fmt.Printf("Pause Time Variance:    %s coefficient of variation\n", utils.FormatPercent(analysis.PauseTimeVariance*100))
 		}
 		if analysis.PauseTargetMissRate > 0 {
-			fmt.Printf("Pause Target Miss Rate: %.1f%% collections exceed target\n", analysis.PauseTargetMissRate*100)
+			fmt.Printf("Pause Target Miss Rate: %s collections exceed target\n", utils.FormatPercent(analysis.PauseTargetMissRate*100))
 		}
 		if analysis.EvacuationFailureRate > 0 {
-			fmt.Printf("Evacuation Failures:    %.1f%% of collections\n", analysis.EvacuationFailureRate*100)
+			fmt.Printf("Evacuation Failures:    %s of collections\n", utils.FormatPercent(analysis.EvacuationFailureRate*100))
 		}
 	}
 
@@ -142,7 +149,7 @@ func (analysis *GCAnalysis) PrintDetailed() {
 	// Detailed Pause Analysis
 	fmt.Println("⏱️  PAUSE TIME ANALYSIS")
 	fmt.Println(strings.Repeat("─", 50))
-	fmt.Printf("Total Collections:     %d\n", analysis.TotalEvents)
+	fmt.Printf("Total Collections:     %s\n", utils.FormatCount(int64(analysis.TotalEvents)))
 
 	if analysis.TotalEvents > 0 {
 		avgFreq := duration.Seconds() / float64(analysis.TotalEvents)
@@ -150,8 +157,8 @@ func (analysis *GCAnalysis) PrintDetailed() {
 		maxPause := float64(analysis.MaxPause.Nanoseconds()) / 1e6
 		avgPause := float64(analysis.AvgPause.Nanoseconds()) / 1e6
 
-		fmt.Printf("Collection Frequency:  Every %.1f seconds\n", avgFreq)
-		fmt.Printf("Min/Max/Avg Pause:     %.2fms/%.2fms/%.2fms\n", minPause, maxPause, avgPause)
+		fmt.Printf("Collection Frequency:  Every %s seconds\n", utils.FormatFloat(avgFreq))
+		fmt.Printf("Min/Max/Avg Pause:     %s/%s/%s\n", utils.Precision(2).Millis(minPause), utils.Precision(2).Millis(maxPause), utils.Precision(2).Millis(avgPause))
 
 		maxPauseMs := float64(analysis.MaxPause.Nanoseconds()) / 1e6
 		if maxPauseMs > 500 {
@@ -161,11 +168,35 @@ func (analysis *GCAnalysis) PrintDetailed() {
 		}
 		fmt.Println()
 
-		fmt.Printf("95th Percentile:       %.2fms\n", float64(analysis.P95Pause.Nanoseconds())/1e6)
-		fmt.Printf("99th Percentile:       %.2fms\n", float64(analysis.P99Pause.Nanoseconds())/1e6)
+		fmt.Printf("95th Percentile:       %s\n", utils.Precision(2).Millis(float64(analysis.P95Pause.Nanoseconds())/1e6))
+		fmt.Printf("99th Percentile:       %s\n", utils.Precision(2).Millis(float64(analysis.P99Pause.Nanoseconds())/1e6))
 	}
+	analysis.printPauseTargets()
 	fmt.Println()
 
+	// Time between collections
+	if frequency := analysis.Frequency; len(frequency.ByType) > 0 {
+		fmt.Println("⏲️  GC FREQUENCY")
+		fmt.Println(strings.Repeat("─", 50))
+		for _, stats := range frequency.ByType {
+			fmt.Printf("%-22s  every %s (p50 %s, p95 %s, min %s)\n", stats.Type+":",
+				utils.FormatDuration(stats.Avg), utils.FormatDuration(stats.P50),
+				utils.FormatDuration(stats.P95), utils.FormatDuration(stats.Min))
+		}
+		if frequency.Gaps > 0 {
+			fmt.Printf("Shortest Gap:           %s\n", utils.FormatDuration(frequency.MinGap))
+			fmt.Printf("Back-to-Back:           %s of collections (< %s apart, up to %d in a row)\n",
+				utils.FormatPercent(frequency.BackToBackRate*100), utils.FormatDuration(BackToBackGap),
+				frequency.LongestBackToBackRun)
+		}
+		if spiral := frequency.Spiral; spiral.Collections > 0 {
+			fmt.Printf("Death Spiral:           %s → %s over %d collections from %s 🔴\n",
+				utils.FormatDuration(spiral.FirstGap), utils.FormatDuration(spiral.LastGap),
+				spiral.Collections, spiral.Start.Format("15:04:05"))
+		}
+		fmt.Println()
+	}
+
 	// Collection Type Analysis
 	fmt.Println("🔄 COLLECTION TYPE BREAKDOWN")
 	fmt.Println(strings.Repeat("─", 50))
@@ -174,22 +205,22 @@ func (analysis *GCAnalysis) PrintDetailed() {
 	if totalEvents > 0 {
 		if analysis.YoungGCCount > 0 {
 			youngPct := float64(analysis.YoungGCCount) / float64(totalEvents) * 100
-			fmt.Printf("✅ Young Generation:      %3d collections (%.1f%%)\n",
-				analysis.YoungGCCount, youngPct)
+			fmt.Printf("✅ Young Generation:      %3d collections (%s)\n",
+				analysis.YoungGCCount, utils.FormatPercent(youngPct))
 			fmt.Printf("                          Efficient cleanup of short-lived objects\n")
 		}
 
 		if analysis.MixedGCCount > 0 {
 			mixedPct := float64(analysis.MixedGCCount) / float64(totalEvents) * 100
-			fmt.Printf("🟡 Mixed Collections:     %3d collections (%.1f%%)\n",
-				analysis.MixedGCCount, mixedPct)
+			fmt.Printf("🟡 Mixed Collections:     %3d collections (%s)\n",
+				analysis.MixedGCCount, utils.FormatPercent(mixedPct))
 			fmt.Printf("                          Maintenance of older generation objects\n")
 		}
 
 		if analysis.FullGCCount > 0 {
 			fullPct := float64(analysis.FullGCCount) / float64(totalEvents) * 100
-			fmt.Printf("🔴 Full GC Events:        %3d collections (%.1f%%)",
-				analysis.FullGCCount, fullPct)
+			fmt.Printf("🔴 Full GC Events:        %3d collections (%s)",
+				analysis.FullGCCount, utils.FormatPercent(fullPct))
 
 			if fullPct > 10 {
 				fmt.Printf(" [Critical - Frequent memory pressure]")
@@ -206,11 +237,32 @@ func (analysis *GCAnalysis) PrintDetailed() {
 	}
 	fmt.Println()
 
+	analysis.printFullGCPhases()
+	analysis.printGCLocker()
+	analysis.printHumongousSizes()
+	analysis.printSurvivorModel()
+	analysis.printIHOP()
+
+	// Worker balance (if gc+phases=debug logged per-worker timings)
+	if len(analysis.WorkerBalance.Phases) > 0 {
+		balance := analysis.WorkerBalance
+		fmt.Println("👷 WORKER BALANCE")
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Printf("Straggling Pauses:      %s (%d of %d waited %s+ on one worker)\n",
+			utils.FormatPercent(balance.StragglerRate*100), balance.StragglerEvents, balance.Events,
+			utils.FormatPercent(StragglerPauseShare*100))
+		for _, phase := range balance.Phases[:min(4, len(balance.Phases))] {
+			fmt.Printf("%-22s  slowest %sx avg, %v waited\n", phase.Phase+":",
+				utils.Precision(2).Float(phase.AvgImbalance), phase.StragglerTime.Round(time.Microsecond))
+		}
+		fmt.Println()
+	}
+
 	// G1GC Region Analysis (if available)
 	if analysis.AvgRegionUtilization > 0 {
 		fmt.Println("🏗️  G1GC REGION ANALYSIS")
 		fmt.Println(strings.Repeat("─", 50))
-		fmt.Printf("Average Region Utilization: %.1f%%", analysis.AvgRegionUtilization*100)
+		fmt.Printf("Average Region Utilization: %s", utils.FormatPercent(analysis.AvgRegionUtilization*100))
 		if analysis.AvgRegionUtilization > 0.85 {
 			fmt.Printf(" ⚠️  [High - may cause evacuation pressure]")
 		} else if analysis.AvgRegionUtilization > 0.7 {
@@ -225,9 +277,242 @@ func (analysis *GCAnalysis) PrintDetailed() {
 		}
 		fmt.Println()
 	}
+
+	analysis.printLoggingRecommendations(true)
+}
+
+// printPauseTargets breaks the pause target misses down by GC type and the causes that miss most
+func (analysis *GCAnalysis) printPauseTargets() {
+	targets := analysis.PauseTargets
+	if targets.Misses == 0 {
+		return
+	}
+
+	fmt.Printf("Target Misses (> %s):\n", utils.FormatDuration(analysis.EstimatedPauseTarget))
+	for _, compliance := range targets.ByType {
+		line := fmt.Sprintf("  %-20s %7s  (%d of %d, max %s)", compliance.Name+":",
+			utils.FormatPercent(compliance.MissRate*100), compliance.Misses, compliance.Pauses, utils.FormatDuration(compliance.Max))
+		if compliance.Name == targets.Culprit {
+			line += fmt.Sprintf(" ⚠️  %s of all misses", utils.FormatPercent(targets.CulpritShare*100))
+		}
+		fmt.Println(line)
+	}
+	for _, compliance := range targets.ByCause[:min(3, len(targets.ByCause))] {
+		if compliance.Misses == 0 {
+			break
+		}
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("  %-20s %7s  (%d of %d)", compliance.Name+":",
+			utils.FormatPercent(compliance.MissRate*100), compliance.Misses, compliance.Pauses)))
+	}
+}
+
+// printFullGCPhases shows where Full GC time went, phase by phase
+func (analysis *GCAnalysis) printFullGCPhases() {
+	phases := analysis.FullGCPhases
+	if phases.Events == 0 {
+		return
+	}
+
+	fmt.Println("🧱 FULL GC PHASES")
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("Full GCs Timed:         %d, %s in phases\n", phases.Events, utils.FormatDuration(phases.Total))
+	for _, stats := range phases.Phases {
+		line := fmt.Sprintf("%-22s  %7s  (avg %s, max %s)", stats.Phase+":", utils.FormatPercent(stats.Share*100),
+			utils.FormatDuration(stats.Avg), utils.FormatDuration(stats.Max))
+		if stats.Phase == phases.Dominant {
+			line += " ⚠️"
+		}
+		fmt.Println(line)
+	}
+	if phases.SubphasesLogged {
+		fmt.Printf("Within Marking:         class unloading %s, reference processing %s\n",
+			utils.FormatPercent(phases.ClassUnloadingShare*100), utils.FormatPercent(phases.RefProcessingShare*100))
+	}
+	fmt.Println()
+}
+
+// printGCLocker shows the collections JNI critical regions held back and, with gc+jni=debug, the threads involved
+func (analysis *GCAnalysis) printGCLocker() {
+	locker := analysis.GCLocker
+	if locker.Collections == 0 && !locker.Logged {
+		return
+	}
+
+	fmt.Println("🔒 GCLOCKER (JNI CRITICAL REGIONS)")
+	fmt.Println(strings.Repeat("─", 50))
+	collections := fmt.Sprintf("GCLocker Collections:   %d (%s of collections", locker.Collections, utils.FormatPercent(locker.Share*100))
+	if locker.FullGCs > 0 {
+		collections += fmt.Sprintf(", %d Full", locker.FullGCs)
+	}
+	collections += ")"
+	if analysis.HasWarningGCLocker {
+		collections += " ⚠️"
+	}
+	fmt.Println(collections)
+	if locker.BackToBack > 0 {
+		fmt.Printf("Right After Another:    %d (< %s apart)\n", locker.BackToBack, utils.FormatDuration(BackToBackGap))
+	}
+	if locker.Logged {
+		fmt.Printf("Allocation Stalls:      %d, %d threads held at a region's entry\n", locker.Stalls, locker.BlockedEntries)
+		if locker.RetryFailures > 0 {
+			fmt.Printf("Gave Up (OOM):          %d 🔴\n", locker.RetryFailures)
+		}
+		if len(locker.Holders) > 0 {
+			fmt.Printf("Region Holders:         %s\n", threadList(locker.Holders))
+		}
+		if len(locker.Stalled) > 0 {
+			fmt.Printf("Stalled Threads:        %s\n", threadList(locker.Stalled))
+		}
+	}
+	fmt.Println()
+}
+
+// printHumongousSizes shows the humongous object sizes seen, the region space they waste and larger region sizes
+func (analysis *GCAnalysis) printHumongousSizes() {
+	histogram := analysis.HumongousSizes
+	if histogram.Objects == 0 {
+		return
+	}
+
+	fmt.Println("🐘 HUMONGOUS OBJECT SIZES")
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("Objects:                %d totalling %s (%s)\n", histogram.Objects, histogram.Total, histogram.Source)
+	if histogram.RegionSize > 0 {
+		fmt.Printf("Unused in Regions:      %s with %s regions\n", histogram.Wasted, histogram.RegionSize)
+	}
+	for _, bucket := range histogram.Buckets {
+		line := fmt.Sprintf("  %-20s %6d", fmt.Sprintf("%s-%s:", bucket.Min, bucket.Max), bucket.Count)
+		if bucket.Regions > 0 {
+			line += fmt.Sprintf("  %d regions, %s unused", bucket.Regions, bucket.Wasted)
+		}
+		fmt.Println(utils.MutedStyle.Render(line))
+	}
+	for _, option := range histogram.Options {
+		line := fmt.Sprintf("%-22s  %s normal, %s unused", "At "+option.Size.String()+":",
+			utils.FormatPercent(option.NormalShare*100), option.Wasted)
+		if option.HeapRegions > 0 {
+			line += fmt.Sprintf(", %d regions", option.HeapRegions)
+		}
+		if option.Size == histogram.Suggested {
+			line += " ✅"
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
+
+// printSurvivorModel shows survivor demand per young collection and the survivor size that would hold it
+func (analysis *GCAnalysis) printSurvivorModel() {
+	model := analysis.Survivor
+	if model.Samples == 0 {
+		return
+	}
+
+	fmt.Println("🛟 SURVIVOR SIZING")
+	fmt.Println(strings.Repeat("─", 50))
+	space := model.Capacity.String()
+	if model.CurrentRatio > 0 {
+		space += fmt.Sprintf(" (SurvivorRatio about %d)", model.CurrentRatio)
+	}
+	fmt.Printf("Survivor Space:         %s\n", space)
+	source := "survivor occupancy"
+	if model.FromAgeTable {
+		source = "age table"
+	}
+	fmt.Printf("Demand per Young GC:    p50 %s, p95 %s, max %s (%s, %d collections)\n",
+		model.DemandP50, model.DemandP95, model.DemandMax, source, model.Samples)
+	overflow := fmt.Sprintf("Overflowed:             %s of young collections", utils.FormatPercent(model.OverflowRate*100))
+	if model.Undersized() {
+		overflow += " ⚠️"
+	}
+	fmt.Println(overflow)
+	if model.Undersized() {
+		suggested := model.SuggestedSize.String()
+		if model.SuggestedRatio > 0 {
+			suggested += fmt.Sprintf(" (-XX:SurvivorRatio=%d)", model.SuggestedRatio)
+		}
+		fmt.Printf("Suggested Size:         %s, %s expected overflow\n", suggested,
+			utils.FormatPercent(model.ExpectedOverflow*100))
+	}
+	if model.MaxThreshold > 0 {
+		fmt.Printf("Tenuring Threshold:     median %d of %d, lowered in %s of collections\n",
+			model.MedianThreshold, model.MaxThreshold, utils.FormatPercent(model.LoweredThresholdRate*100))
+	}
+	fmt.Println()
+}
+
+// printIHOP shows how close each marking cycle came to losing its race against old gen allocation
+func (analysis *GCAnalysis) printIHOP() {
+	ihop := analysis.IHOP
+	if ihop.Cycles == 0 {
+		return
+	}
+
+	fmt.Println("🏁 CONCURRENT MARKING (IHOP)")
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("Marking Starts At:      %d%% heap occupancy (median of %d cycles)\n", ihop.CurrentPercent(), ihop.Cycles)
+	fmt.Printf("Old Growth in Marking:  up to %s of the heap\n", utils.FormatPercent(ihop.MaxGrowth*100))
+	margin := fmt.Sprintf("Free at Remark:         %s of the heap at the least", utils.FormatPercent(ihop.MinMargin*100))
+	if ihop.StartsLate() {
+		margin += " ⚠️"
+	}
+	fmt.Println(margin)
+	if ihop.MinRaceMargin > 0 {
+		fmt.Printf("Worst Race Margin:      %sx\n", utils.FormatFloat(ihop.MinRaceMargin))
+	}
+	if ihop.LostRaces > 0 {
+		fmt.Printf("Lost Races:             %d (Full GC or evacuation failure before Remark) 🔴\n", ihop.LostRaces)
+	}
+	if ihop.Logged {
+		kind := "Fixed"
+		if ihop.Adaptive {
+			kind = fmt.Sprintf("Adaptive (predicting in %s of updates)", utils.FormatPercent(ihop.PredictionActiveRate*100))
+		}
+		fmt.Printf("IHOP Threshold:         %s of target occupancy, %s\n", utils.FormatPercent(ihop.ThresholdPercent), kind)
+		fmt.Printf("Old Allocation Rate:    %s/s over %s of marking\n",
+			utils.MemorySize(ihop.OldAllocationRate), utils.FormatDuration(ihop.MarkingLength))
+	}
+	if ihop.StartsLate() || ihop.StartsEarly() {
+		fmt.Printf("Suggested IHOP:         %d%%\n", ihop.SuggestedPercent)
+	}
+	fmt.Println()
+}
+
+// printLoggingRecommendations lists what the log left out and the -Xlog setting
+// for next time; detailed adds what the analysis had to do without
+func (analysis *GCAnalysis) printLoggingRecommendations(detailed bool) {
+	if len(analysis.LoggingGaps) == 0 {
+		return
+	}
+
+	fmt.Println("\n📝 LOGGING CONFIGURATION RECOMMENDATIONS")
+	fmt.Println(strings.Repeat("─", 50))
+	for _, gap := range analysis.LoggingGaps {
+		fmt.Printf("• %-32s → %s\n", gap.Missing, gap.Setting)
+		if detailed {
+			fmt.Println(utils.MutedStyle.Render("  " + gap.Impact))
+		}
+	}
+	fmt.Printf("\nNext time run the JVM with:\n  %s\n", RecommendedXlog)
 }
 
 // Clean helper functions for professional output
+// PrintParseWarnings lists the lines the parser skipped, up to limit of them
+func (analysis *GCAnalysis) PrintParseWarnings(limit int) {
+	if analysis.SkippedLines == 0 {
+		return
+	}
+
+	fmt.Printf("⚠️  %d lines skipped while parsing\n", analysis.SkippedLines)
+	for _, warning := range analysis.ParseWarnings[:min(limit, len(analysis.ParseWarnings))] {
+		fmt.Printf("   %s\n", warning)
+	}
+	if analysis.SkippedLines > limit {
+		fmt.Printf("   ... and %d more\n", analysis.SkippedLines-limit)
+	}
+	fmt.Println()
+}
+
 func getThroughputStatusWithIcon(throughput float64) (string, string) {
 	if throughput >= 99 {
 		return "✅", "Excellent"
@@ -265,7 +550,11 @@ func (issues *GCIssues) Print() {
 		return
 	}
 
-	fmt.Println("\n🚀 PERFORMANCE RECOMMENDATIONS")
+	fmt.Print("\n🚀 PERFORMANCE RECOMMENDATIONS")
+	if issues.JDKVersion > 0 {
+		fmt.Printf(" (flags for JDK %d)", issues.JDKVersion)
+	}
+	fmt.Println()
 	fmt.Println(strings.Repeat("─", 50))
 
 	// Critical issues
@@ -313,3 +602,23 @@ func printFormattedRecommendations(recommendations []string) {
 		fmt.Printf("   • %s\n", trimmed)
 	}
 }
+
+// cdsSummary describes CDS use for the configuration report; empty when the log doesn't mention it
+func (analysis *GCAnalysis) cdsSummary() string {
+	if !analysis.CDSLogged {
+		return ""
+	}
+	if !analysis.CDSMapped {
+		return "off (archive not mapped; CDS or AppCDS would cut start-up time and footprint)"
+	}
+
+	// The GC log can't tell the JDK's default archive from an AppCDS one
+	summary := "on (default archive or AppCDS)"
+	if analysis.CDSArchiveSize > 0 {
+		summary += ", " + analysis.CDSArchiveSize.String() + " mapped"
+	}
+	if analysis.ArchiveRegions > 0 {
+		summary += fmt.Sprintf(", %d archive heap regions left out of utilization", analysis.ArchiveRegions)
+	}
+	return summary
+}
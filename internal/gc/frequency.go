@@ -0,0 +1,166 @@
+package gc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+ * A pause's timestamp is logged when it ends, so the time the application ran
+ * between two collections is the gap from one ending to the next starting.
+ *
+ * Only Young, Mixed and Full collections are allocation driven. Remark and
+ * Cleanup follow a concurrent start pause by design, so they would look like
+ * back-to-back collections without saying anything about allocation. For
+ * the same reason a young pause that escalates to a Full GC under the same GC
+ * ID (promotion failure, to-space exhaustion) counts once, as the Full GC:
+ * the two are one collection, and the gap between them is zero.
+ *
+ * Logs decorated with uptime only have no timestamps, so they have no
+ * frequency to analyze.
+ */
+
+// isCollection reports whether the event is an allocation-driven collection
+func isCollection(event *GCEvent) bool {
+	switch CategorizeGCType(event.Type) {
+	case "Young", "Mixed", "Full":
+		return true
+	}
+	return false
+}
+
+// pauseGap is the time the application ran between prev ending and event starting
+func pauseGap(prev, event *GCEvent) time.Duration {
+	return max(event.Timestamp.Sub(prev.Timestamp)-event.Duration, 0)
+}
+
+func calculateFrequency(events []*GCEvent) FrequencyAnalysis {
+	var frequency FrequencyAnalysis
+
+	// Interarrival time per GC type
+	lastByType := make(map[string]time.Time)
+	intervalsByType := make(map[string][]time.Duration)
+	for _, event := range events {
+		if event.Timestamp.IsZero() {
+			continue
+		}
+		gcType := CategorizeGCType(event.Type)
+		if last, seen := lastByType[gcType]; seen {
+			intervalsByType[gcType] = append(intervalsByType[gcType], event.Timestamp.Sub(last))
+		}
+		lastByType[gcType] = event.Timestamp
+	}
+	for gcType, intervals := range intervalsByType {
+		frequency.ByType = append(frequency.ByType, newIntervalStats(gcType, intervals))
+	}
+	slices.SortFunc(frequency.ByType, func(a, b IntervalStats) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Type, b.Type))
+	})
+
+	// Gaps between consecutive collections, for back-to-back runs and spirals
+	var collections []*GCEvent
+	for _, event := range events {
+		if !isCollection(event) || event.Timestamp.IsZero() {
+			continue
+		}
+		if n := len(collections); n > 0 && escalates(collections[n-1], event) {
+			collections[n-1] = event
+			continue
+		}
+		collections = append(collections, event)
+	}
+	if len(collections) < 2 {
+		return frequency
+	}
+
+	gaps := make([]time.Duration, len(collections)-1)
+	run := 0
+	frequency.MinGap = pauseGap(collections[0], collections[1])
+	for i := 1; i < len(collections); i++ {
+		gap := pauseGap(collections[i-1], collections[i])
+		gaps[i-1] = gap
+		frequency.MinGap = min(frequency.MinGap, gap)
+
+		if gap < BackToBackGap {
+			frequency.BackToBackCount++
+			run++
+			frequency.LongestBackToBackRun = max(frequency.LongestBackToBackRun, run)
+		} else {
+			run = 0
+		}
+	}
+	frequency.Gaps = len(gaps)
+	frequency.BackToBackRate = float64(frequency.BackToBackCount) / float64(len(gaps))
+	frequency.Spiral = findDeathSpiral(collections, gaps)
+
+	return frequency
+}
+
+// escalates reports whether event is the Full GC prev's young pause turned into
+func escalates(prev, event *GCEvent) bool {
+	return prev.ID == event.ID && CategorizeGCType(event.Type) == "Full" && CategorizeGCType(prev.Type) != "Full"
+}
+
+func newIntervalStats(gcType string, intervals []time.Duration) IntervalStats {
+	sorted := slices.Clone(intervals)
+	slices.Sort(sorted)
+
+	var sum time.Duration
+	for _, interval := range sorted {
+		sum += interval
+	}
+	return IntervalStats{
+		Type:  gcType,
+		Count: len(sorted),
+		Min:   sorted[0],
+		Avg:   sum / time.Duration(len(sorted)),
+		P50:   calculatePercentile(sorted, 50),
+		P95:   calculatePercentile(sorted, 95),
+	}
+}
+
+/*
+ * findDeathSpiral looks for the longest run of collections whose gaps keep
+ * shrinking until the application barely runs between them: the heap fills
+ * faster than each collection frees it, which usually ends in back-to-back
+ * Full GCs and an OutOfMemoryError. Gaps may wobble by SpiralGapTolerance
+ * from one collection to the next, but over the run they have to shrink by
+ * SpiralShrinkFactor and end below BackToBackGap.
+ */
+func findDeathSpiral(collections []*GCEvent, gaps []time.Duration) DeathSpiral {
+	var spiral DeathSpiral
+	start := 0
+	for i := 1; i <= len(gaps); i++ {
+		if i < len(gaps) && float64(gaps[i]) <= float64(gaps[i-1])*SpiralGapTolerance {
+			continue
+		}
+
+		// The run is gaps[start:i]
+		first, last := gaps[start], gaps[i-1]
+		pauses := i - start + 1
+		if pauses >= SpiralMinCollections && pauses > spiral.Collections &&
+			last < BackToBackGap && first > 0 && float64(first) >= float64(last)*SpiralShrinkFactor {
+			spiral = DeathSpiral{
+				Start:       collections[start].Timestamp,
+				Collections: pauses,
+				FirstGap:    first,
+				LastGap:     last,
+				FullGCs:     countFullGCs(collections[start : i+1]),
+			}
+		}
+		start = i
+	}
+	return spiral
+}
+
+func countFullGCs(collections []*GCEvent) int {
+	count := 0
+	for _, event := range collections {
+		if CategorizeGCType(event.Type) == "Full" {
+			count++
+		}
+	}
+	return count
+}
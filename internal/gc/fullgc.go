@@ -0,0 +1,90 @@
+package gc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Full GC phases, named for what they do rather than what each collector logs them as
+const (
+	FullPhaseMark        = "Mark"
+	FullPhasePrepare     = "Compute Addresses"
+	FullPhaseAdjust      = "Adjust Pointers"
+	FullPhaseCompact     = "Move Objects"
+	FullPhasePostCompact = "Post Compact"
+)
+
+/*
+ * Every collector's Full GC is a mark-compact in four steps: mark what's
+ * live, work out where each live object will move, rewrite the references
+ * to point there, then move the objects. Which step takes the time says
+ * what to change when the Full GCs themselves can't be avoided:
+ *
+ *   - marking follows the live set, and within it class unloading and
+ *     reference processing follow class churn and reference counts
+ *   - computing addresses and adjusting pointers follow the number of live
+ *     objects and references between them
+ *   - moving objects follows how much live data has to move
+ *
+ * Marking's sub-phases are only logged at gc+phases=debug; their shares are
+ * of the marking time of the Full GCs that logged them.
+ */
+
+func calculateFullGCPhases(events []*GCEvent) FullGCPhaseAnalysis {
+	var phases FullGCPhaseAnalysis
+	byPhase := make(map[string]*FullPhaseStats)
+	var subphaseMarkTime, classUnloading, refProcessing time.Duration
+
+	for _, event := range events {
+		if CategorizeGCType(event.Type) != GCTypeFull || len(event.FullPhases) == 0 {
+			continue
+		}
+		phases.Events++
+
+		var eventMark time.Duration
+		for _, phase := range event.FullPhases {
+			stats, exists := byPhase[phase.Name]
+			if !exists {
+				stats = &FullPhaseStats{Phase: phase.Name}
+				byPhase[phase.Name] = stats
+			}
+			stats.Count++
+			stats.Total += phase.Duration
+			stats.Max = max(stats.Max, phase.Duration)
+			phases.Total += phase.Duration
+			if phase.Name == FullPhaseMark {
+				eventMark += phase.Duration
+			}
+		}
+
+		if event.ClassUnloadingTime > 0 || event.MarkRefProcessingTime > 0 {
+			phases.SubphasesLogged = true
+			subphaseMarkTime += eventMark
+			classUnloading += event.ClassUnloadingTime
+			refProcessing += event.MarkRefProcessingTime
+		}
+	}
+	if phases.Events == 0 || phases.Total == 0 {
+		return phases
+	}
+
+	for _, stats := range byPhase {
+		stats.Avg = stats.Total / time.Duration(stats.Count)
+		stats.Share = float64(stats.Total) / float64(phases.Total)
+		phases.Phases = append(phases.Phases, *stats)
+	}
+	slices.SortFunc(phases.Phases, func(a, b FullPhaseStats) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), strings.Compare(a.Phase, b.Phase))
+	})
+
+	if top := phases.Phases[0]; top.Share >= FullPhaseDominantShare {
+		phases.Dominant, phases.DominantShare = top.Phase, top.Share
+	}
+	if subphaseMarkTime > 0 {
+		phases.ClassUnloadingShare = min(float64(classUnloading)/float64(subphaseMarkTime), 1)
+		phases.RefProcessingShare = min(float64(refProcessing)/float64(subphaseMarkTime), 1)
+	}
+	return phases
+}
@@ -0,0 +1,62 @@
+package gc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+/*
+ * A thread inside a JNI critical region (GetPrimitiveArrayCritical,
+ * GetStringCritical) holds a raw pointer into the heap, so no collection can
+ * move objects until it leaves. An allocation that needs a collection
+ * meanwhile stalls, and the last thread out of a critical region starts the
+ * deferred collection itself: "GCLocker Initiated GC". Native code that
+ * holds a region across slow work turns every such collection into a stall
+ * for the allocating threads, and an allocation that waits too often gives
+ * up with an OutOfMemoryError while the heap has room.
+ *
+ * The collections are found by their cause; the stalls and the threads
+ * involved only when gc+jni=debug was logged. A GCLocker collection soon
+ * after the previous one collects little: it ran because a stall asked for
+ * it, not because the young generation filled up again.
+ */
+
+// calculateGCLocker counts the GCLocker collections; the parser already counted what gc+jni logged
+func calculateGCLocker(events []*GCEvent, locker GCLockerAnalysis) GCLockerAnalysis {
+	locker.Collections, locker.FullGCs, locker.BackToBack = 0, 0, 0
+	collections := 0
+	var prev *GCEvent
+	for _, event := range events {
+		if !isCollection(event) {
+			continue
+		}
+		collections++
+		if strings.Contains(event.Cause, "GCLocker") {
+			locker.Collections++
+			if CategorizeGCType(event.Type) == GCTypeFull {
+				locker.FullGCs++
+			}
+			if prev != nil && !prev.Timestamp.IsZero() && !event.Timestamp.IsZero() && pauseGap(prev, event) < BackToBackGap {
+				locker.BackToBack++
+			}
+		}
+		prev = event
+	}
+	if collections > 0 {
+		locker.Share = float64(locker.Collections) / float64(collections)
+	}
+
+	byCount := func(a, b ThreadCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name))
+	}
+	slices.SortFunc(locker.Holders, byCount)
+	slices.SortFunc(locker.Stalled, byCount)
+	return locker
+}
+
+// Significant is whether GCLocker held collections back often enough to tune for
+func (locker GCLockerAnalysis) Significant() bool {
+	return locker.RetryFailures > 0 || locker.Stalls >= MinGCLockerEvents ||
+		(locker.Collections >= MinGCLockerEvents && locker.Share >= GCLockerShareWarning)
+}
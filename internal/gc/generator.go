@@ -0,0 +1,533 @@
+package gc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * Synthetic G1 logs. A simulated JDK 21 heap is driven by an allocation
+ * rate: eden fills, a young collection copies the survivors and promotes
+ * the tenured ones, and old occupancy past the IHOP starts a concurrent
+ * cycle followed by mixed collections. The patterns shape what the
+ * application does to that heap, and every collection is written the way
+ * -Xlog:gc*:file=gc.log:time,uptime would write it, so the output reads
+ * like a real log and what went into it is known exactly.
+ */
+
+const (
+	PatternHealthy = "healthy" // Steady allocation, a flat live set
+	PatternLeak    = "leak"    // The live set grows until the heap is exhausted
+	PatternBursty  = "bursty"  // Allocation spikes that promote early and fail evacuation
+)
+
+var GeneratorPatterns = []string{PatternHealthy, PatternLeak, PatternBursty}
+
+const (
+	genIHOP          = 0.45 // Old occupancy that starts a concurrent cycle
+	genYoungShare    = 0.25 // Share of the heap G1 sizes the young generation to
+	genMinEdenShare  = 0.02 // Smallest eden G1 falls back to when the heap is full
+	genReserveShare  = 0.05 // Free heap G1 keeps back for to-space
+	genFullShare     = 0.92 // Occupancy after a collection that forces a full GC
+	genStartLive     = 0.15 // Live share of the heap the application starts with
+	genMaxLiveShare  = 0.95 // A leak stops here; past it the JVM would throw OutOfMemoryError
+	genWorkers       = 8
+	genBurstInterval = 5 * time.Minute
+	genBurstLength   = 45 * time.Second
+)
+
+// GeneratorConfig describes the log to generate; DefaultGeneratorConfig fills it in per pattern
+type GeneratorConfig struct {
+	Pattern         string
+	Duration        time.Duration    // Uptime the log covers
+	HeapMax         utils.MemorySize // -Xmx
+	AllocRate       float64          // MB/s allocated, before the pattern shapes it
+	LeakRate        float64          // MB/min added to the live set for good
+	EvacFailureRate float64          // Share of young collections that fail evacuation regardless of free space
+	Seed            uint64           // The same seed and config give the same collections
+	Start           time.Time        // Wall clock time the JVM started
+}
+
+// GroundTruth is what went into a generated log, to check the analyzers against
+type GroundTruth struct {
+	Collections        int           `json:"collections"` // Pauses: young, mixed, full, remark and cleanup
+	Young              int           `json:"young"`
+	Mixed              int           `json:"mixed"`
+	Full               int           `json:"full"`
+	ConcurrentCycles   int           `json:"concurrentCycles"`
+	EvacuationFailures int           `json:"evacuationFailures"`
+	AllocatedBytes     int64         `json:"allocatedBytes"`
+	LiveStartBytes     int64         `json:"liveStartBytes"`
+	LiveEndBytes       int64         `json:"liveEndBytes"`
+	MaxPause           time.Duration `json:"maxPauseNs"`
+}
+
+func DefaultGeneratorConfig(pattern string) (GeneratorConfig, error) {
+	config := GeneratorConfig{
+		Pattern:   pattern,
+		Duration:  time.Hour,
+		HeapMax:   utils.GB,
+		AllocRate: 40,
+		Seed:      1,
+		Start:     time.Now().Truncate(time.Millisecond),
+	}
+
+	switch pattern {
+	case PatternHealthy:
+	case PatternLeak:
+		config.LeakRate = -1 // Sized to the duration by Validate
+	case PatternBursty:
+		config.AllocRate = 25
+		config.EvacFailureRate = 0.03
+	default:
+		return config, fmt.Errorf("unknown pattern %q. Valid options: %v", pattern, GeneratorPatterns)
+	}
+	return config, nil
+}
+
+func (c *GeneratorConfig) Validate() error {
+	switch {
+	case !slices.Contains(GeneratorPatterns, c.Pattern):
+		return fmt.Errorf("unknown pattern %q. Valid options: %v", c.Pattern, GeneratorPatterns)
+	case c.Duration < time.Minute:
+		return fmt.Errorf("duration must be at least 1m, got %s", c.Duration)
+	case c.HeapMax < 64*utils.MB:
+		return fmt.Errorf("heap must be at least 64M, got %s", c.HeapMax)
+	case c.AllocRate <= 0:
+		return fmt.Errorf("allocation rate must be positive, got %g MB/s", c.AllocRate)
+	case c.EvacFailureRate < 0 || c.EvacFailureRate > 1:
+		return fmt.Errorf("evacuation failure rate must be between 0 and 1, got %g", c.EvacFailureRate)
+	}
+
+	// A default leak fills the heap 85% of the way through, so the last stretch thrashes in full GCs
+	if c.LeakRate < 0 {
+		c.LeakRate = c.HeapMax.MB() * (genMaxLiveShare - genStartLive) / (c.Duration.Minutes() * 0.85)
+	}
+	return nil
+}
+
+// logGenerator is the simulated heap; sizes are bytes, kept as floats between collections
+type logGenerator struct {
+	config GeneratorConfig
+	out    *bufio.Writer
+	rng    *rand.Rand
+	truth  GroundTruth
+
+	uptime      time.Duration
+	gcID        int
+	invocations int
+	fullCount   int
+
+	region      float64
+	heap        float64
+	edenTarget  float64
+	survivorMax float64
+
+	eden      float64
+	survivors float64
+	humongous float64
+	oldLive   float64
+	oldDead   float64
+	metaspace float64
+
+	mixedLeft int
+}
+
+// GenerateLog writes a synthetic G1 log to w and returns what it holds
+func GenerateLog(w io.Writer, config GeneratorConfig) (*GroundTruth, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &logGenerator{
+		config: config,
+		out:    bufio.NewWriter(w),
+		rng:    rand.New(rand.NewPCG(config.Seed, config.Seed)),
+		heap:   float64(config.HeapMax),
+	}
+	g.region = float64(utils.MB)
+	for g.region*2048 < g.heap && g.region < float64(32*utils.MB) {
+		g.region *= 2
+	}
+	g.edenTarget = g.roundRegions(g.heap * genYoungShare)
+	g.survivorMax = g.roundRegions(g.edenTarget / 8)
+	g.oldLive = g.heap * genStartLive
+	g.metaspace = float64(24 * utils.MB)
+	g.truth.LiveStartBytes = int64(g.oldLive)
+
+	g.writeHeader()
+	for g.uptime < config.Duration {
+		g.allocate()
+		if g.uptime >= config.Duration {
+			break
+		}
+		g.collect()
+	}
+
+	g.truth.LiveEndBytes = int64(g.oldLive)
+	if err := g.out.Flush(); err != nil {
+		return nil, err
+	}
+	return &g.truth, nil
+}
+
+func (g *logGenerator) writeHeader() {
+	g.uptime = 3 * time.Millisecond
+	heapBytes := int64(g.heap)
+	heapMB := int64(g.config.HeapMax.MB())
+
+	init := []string{
+		"[gc,init] CardTable entry size: 512",
+		fmt.Sprintf("[gc,heap] Minimum heap %d  Initial heap %d  Maximum heap %d", heapBytes, heapBytes, heapBytes),
+		"[gc     ] Using G1",
+		"[gc,init] Version: 21.0.8+9-LTS (release)",
+		"[gc,init] CPUs: 12 total, 12 available",
+		"[gc,init] Memory: 31872M",
+		"[gc,init] Large Page Support: Disabled",
+		"[gc,init] NUMA Support: Disabled",
+		"[gc,init] Compressed Oops: Enabled (Zero based)",
+		fmt.Sprintf("[gc,init] Heap Region Size: %dM", int64(g.region)/int64(utils.MB)),
+		fmt.Sprintf("[gc,init] Heap Min Capacity: %dM", heapMB),
+		fmt.Sprintf("[gc,init] Heap Initial Capacity: %dM", heapMB),
+		fmt.Sprintf("[gc,init] Heap Max Capacity: %dM", heapMB),
+		"[gc,init] Pre-touch: Disabled",
+		"[gc,init] Parallel Workers: 10",
+		"[gc,init] Concurrent Workers: 3",
+		"[gc,init] Concurrent Refinement Workers: 10",
+		"[gc,init] Periodic GC: Disabled",
+	}
+	for _, line := range init {
+		fmt.Fprintf(g.out, "%s%s\n", g.decorations(g.uptime), line)
+	}
+}
+
+// allocate runs the application until eden is full
+func (g *logGenerator) allocate() {
+	rate := g.allocRate() * float64(utils.MB)
+	g.eden = g.edenSize()
+	interval := time.Duration(g.eden / rate * jitter(g.rng, 0.1) * float64(time.Second))
+
+	g.uptime += interval
+	g.truth.AllocatedBytes += int64(g.eden)
+	g.oldLive = min(g.oldLive+g.config.LeakRate*float64(utils.MB)*interval.Minutes(), g.heap*genMaxLiveShare)
+	g.metaspace += float64(interval.Seconds()) * 2 * float64(utils.KB)
+
+	// Large buffers allocated in a burst land in humongous regions until the next collection
+	if g.inBurst() {
+		g.humongous = g.region * float64(g.rng.IntN(12))
+	}
+}
+
+// allocRate is the application's MB/s at the current uptime, shaped by the pattern
+func (g *logGenerator) allocRate() float64 {
+	rate := g.config.AllocRate * (1 + 0.1*math.Sin(2*math.Pi*g.uptime.Hours()*2))
+	if g.inBurst() {
+		rate *= 5
+	}
+	return rate
+}
+
+func (g *logGenerator) inBurst() bool {
+	return g.config.Pattern == PatternBursty && g.uptime%genBurstInterval >= genBurstInterval-genBurstLength
+}
+
+// edenSize is the eden G1 gives the next cycle, shrunk when the old generation crowds it
+func (g *logGenerator) edenSize() float64 {
+	free := g.heap - g.old() - g.humongous - g.survivors - g.heap*genReserveShare
+	return g.roundRegions(max(min(g.edenTarget, free), g.heap*genMinEdenShare))
+}
+
+func (g *logGenerator) old() float64 {
+	return g.oldLive + g.oldDead
+}
+
+func (g *logGenerator) used() float64 {
+	return g.eden + g.survivors + g.old() + g.humongous
+}
+
+func (g *logGenerator) roundRegions(bytes float64) float64 {
+	return math.Max(math.Round(bytes/g.region), 1) * g.region
+}
+
+func (g *logGenerator) regions(bytes float64) int {
+	return int(math.Ceil(bytes / g.region))
+}
+
+// collect runs the collection eden filling up triggers, and whatever it leads to
+func (g *logGenerator) collect() {
+	subtype := "Normal"
+	switch {
+	case g.mixedLeft > 0:
+		subtype = "Mixed"
+	case g.old()+g.humongous > g.heap*genIHOP:
+		subtype = "Concurrent Start"
+	}
+	g.young(subtype)
+
+	switch {
+	case g.old()+g.humongous+g.survivors > g.heap*genFullShare:
+		g.full()
+	case subtype == "Concurrent Start":
+		g.concurrentCycle()
+	}
+}
+
+func (g *logGenerator) young(subtype string) {
+	g.truth.Collections++
+	id := g.nextID()
+	start := g.uptime
+	before := g.used()
+	edenBefore, survivorsBefore, oldBefore, humongousBefore := g.eden, g.survivors, g.old(), g.humongous
+
+	survival := 0.03
+	if g.inBurst() {
+		survival = 0.12
+	}
+	toSurvivors := g.eden*survival*jitter(g.rng, 0.3) + g.survivors*0.4
+	promoted := g.survivors * 0.3
+	if toSurvivors > g.survivorMax {
+		promoted += toSurvivors - g.survivorMax
+		toSurvivors = g.survivorMax
+	}
+	copied := toSurvivors + promoted
+
+	// Evacuation fails when to-space runs out, or as often as configured
+	free := g.heap - before
+	failed := copied > free || g.rng.Float64() < g.config.EvacFailureRate
+	pause := 1.5 + copied/float64(utils.MB)*0.6 + g.rng.Float64()
+
+	var reclaimed float64
+	if subtype == "Mixed" {
+		g.truth.Mixed++
+		g.mixedLeft--
+		reclaimed = g.oldDead * 0.35
+		pause += reclaimed / float64(utils.MB) * 0.05
+	} else {
+		g.truth.Young++
+	}
+	if failed {
+		// Regions that failed to evacuate stay in place as old regions, live and dead objects alike
+		g.truth.EvacuationFailures++
+		promoted += g.eden * 0.3
+		pause *= 3
+	}
+
+	g.oldDead += promoted - reclaimed
+	g.survivors = g.roundRegions(toSurvivors)
+	g.eden = 0
+	g.humongous = 0 // Eager reclaim frees the dead humongous buffers
+	g.invocations++
+
+	end := start + msDuration(pause)
+	g.line(start, "gc,start", "GC(%d) Pause Young (%s) (G1 Evacuation Pause)", id, subtype)
+	g.heapSummary(start, id, "before", g.invocations-1, before, edenBefore+survivorsBefore, survivorsBefore)
+	g.line(start, "gc,task", "GC(%d) Using %d workers of 10 for evacuation", id, genWorkers)
+
+	pre, merge := 0.1+g.rng.Float64()*0.4, 0.1+g.rng.Float64()*0.3
+	post := pause * 0.08
+	evacuate := max(pause-pre-merge-post-0.2, 0.1)
+	for _, phase := range []struct {
+		name string
+		ms   float64
+	}{{"Pre Evacuate Collection Set", pre}, {"Merge Heap Roots", merge},
+		{"Evacuate Collection Set", evacuate}, {"Post Evacuate Collection Set", post},
+		{"Other", max(pause-pre-merge-evacuate-post, 0.1)}} {
+		g.line(end, "gc,phases", "GC(%d)   %s: %.1fms", id, phase.name, phase.ms)
+	}
+
+	g.regionLines(end, id, edenBefore, survivorsBefore, oldBefore, humongousBefore)
+	g.heapSummary(end, id, "after", g.invocations, g.used(), g.survivors, g.survivors)
+
+	cause := "(G1 Evacuation Pause)"
+	if failed {
+		cause += " (Evacuation Failure)"
+	}
+	g.line(end, "gc", "GC(%d) Pause Young (%s) %s %s->%s(%s) %.3fms", id, subtype, cause,
+		megabytes(before), megabytes(g.used()), megabytes(g.heap), pause)
+	g.cpuLine(end, id, pause)
+
+	g.uptime = end
+	g.truth.MaxPause = max(g.truth.MaxPause, msDuration(pause))
+}
+
+// concurrentCycle marks the old generation after a concurrent start pause and lines up the mixed collections
+func (g *logGenerator) concurrentCycle() {
+	g.truth.ConcurrentCycles++
+	id := g.nextID()
+	start := g.uptime
+	oldMB := g.old() / float64(utils.MB)
+
+	scan := 0.5 + g.rng.Float64()*2
+	mark := 5 + oldMB*0.15*jitter(g.rng, 0.2)
+	rebuild := 2 + oldMB*0.05*jitter(g.rng, 0.2)
+	remark := 1 + oldMB*0.01*jitter(g.rng, 0.2)
+	cleanup := 0.1 + g.rng.Float64()*0.3
+
+	t := start
+	g.line(t, "gc", "GC(%d) Concurrent Mark Cycle", id)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Scan Root Regions", id)
+	t += msDuration(scan)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Scan Root Regions %.3fms", id, scan)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Mark", id)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Mark From Roots", id)
+	g.line(t, "gc,task", "GC(%d) Using 3 workers of 3 for marking", id)
+	t += msDuration(mark)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Mark From Roots %.3fms", id, mark)
+
+	// Remark frees the old regions marking found with nothing live in them
+	before := g.used()
+	g.oldDead *= 0.8
+	g.line(t, "gc,start", "GC(%d) Pause Remark", id)
+	t += msDuration(remark)
+	g.line(t, "gc", "GC(%d) Pause Remark %s->%s(%s) %.3fms", id, megabytes(before), megabytes(g.used()), megabytes(g.heap), remark)
+	g.cpuLine(t, id, remark)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Mark %.3fms", id, scan+mark+remark)
+
+	g.line(t, "gc,marking", "GC(%d) Concurrent Rebuild Remembered Sets and Scrub Regions", id)
+	t += msDuration(rebuild)
+	g.line(t, "gc,marking", "GC(%d) Concurrent Rebuild Remembered Sets and Scrub Regions %.3fms", id, rebuild)
+	g.line(t, "gc,start", "GC(%d) Pause Cleanup", id)
+	t += msDuration(cleanup)
+	g.line(t, "gc", "GC(%d) Pause Cleanup %s->%s(%s) %.3fms", id, megabytes(g.used()), megabytes(g.used()), megabytes(g.heap), cleanup)
+	g.cpuLine(t, id, cleanup)
+	g.line(t, "gc", "GC(%d) Concurrent Mark Cycle %.3fms", id, float64(t-start)/float64(time.Millisecond))
+
+	g.truth.Collections += 2
+	g.truth.MaxPause = max(g.truth.MaxPause, msDuration(remark))
+	g.uptime = t
+	g.mixedLeft = 4
+}
+
+// full compacts the whole heap down to the live set, as G1 does once a young collection can't make room
+func (g *logGenerator) full() {
+	g.truth.Collections++
+	g.truth.Full++
+	id := g.nextID()
+	start := g.uptime
+	before := g.used()
+	edenBefore, survivorsBefore, oldBefore, humongousBefore := g.eden, g.survivors, g.old(), g.humongous
+
+	liveMB := g.oldLive / float64(utils.MB)
+	phases := []float64{liveMB * 0.4, liveMB * 0.1, liveMB * 0.2, liveMB * 0.3}
+	pause := 10.0
+	for i := range phases {
+		phases[i] *= jitter(g.rng, 0.2)
+		pause += phases[i]
+	}
+
+	g.line(start, "gc,start", "GC(%d) Pause Full (G1 Compaction Pause)", id)
+	g.heapSummary(start, id, "before", g.invocations, before, survivorsBefore, survivorsBefore)
+	g.line(start, "gc,task", "GC(%d) Using %d workers of 10 for full compaction", id, genWorkers)
+
+	t := start
+	for i, name := range []string{"Mark live objects", "Prepare for compaction", "Adjust pointers", "Compact heap"} {
+		g.line(t, "gc,phases,start", "GC(%d) Phase %d: %s", id, i+1, name)
+		t += msDuration(phases[i])
+		g.line(t, "gc,phases", "GC(%d) Phase %d: %s %.3fms", id, i+1, name, phases[i])
+	}
+
+	g.oldDead = 0
+	g.oldLive += g.survivors
+	g.survivors, g.eden, g.humongous = 0, 0, 0
+	g.oldLive = min(g.oldLive, g.heap*genMaxLiveShare)
+	g.invocations++
+	g.fullCount++
+
+	end := start + msDuration(pause)
+	g.regionLines(end, id, edenBefore, survivorsBefore, oldBefore, humongousBefore)
+	g.heapSummary(end, id, "after", g.invocations, g.used(), 0, 0)
+	g.line(end, "gc", "GC(%d) Pause Full (G1 Compaction Pause) %s->%s(%s) %.3fms", id,
+		megabytes(before), megabytes(g.used()), megabytes(g.heap), pause)
+	g.cpuLine(end, id, pause)
+
+	g.uptime = end
+	g.truth.MaxPause = max(g.truth.MaxPause, msDuration(pause))
+}
+
+func (g *logGenerator) nextID() int {
+	id := g.gcID
+	g.gcID++
+	return id
+}
+
+// heapSummary writes the "Heap before/after GC" block
+func (g *logGenerator) heapSummary(at time.Duration, id int, when string, invocations int, used, young, survivors float64) {
+	end := max(int64(0x100000000), int64(g.heap))
+	start := end - int64(g.heap)
+	regionK := int64(g.region) / int64(utils.KB)
+	metaK := int64(g.metaspace) / int64(utils.KB)
+	classK := metaK / 10
+
+	g.line(at, "gc,heap", "GC(%d) Heap %s GC invocations=%d (full %d):", id, when, invocations, g.fullCount)
+	g.line(at, "gc,heap", "GC(%d)  garbage-first heap   total %dK, used %dK [0x%016x, 0x%016x)", id,
+		int64(g.heap)/int64(utils.KB), int64(used)/int64(utils.KB), start, end)
+	g.line(at, "gc,heap", "GC(%d)   region size %dK, %d young (%dK), %d survivors (%dK)", id, regionK,
+		g.regions(young), int64(g.regions(young))*regionK, g.regions(survivors), int64(g.regions(survivors))*regionK)
+	g.line(at, "gc,heap", "GC(%d)  Metaspace       used %dK, committed %dK, reserved %dK", id, metaK, metaK+metaK/8, 1114112)
+	g.line(at, "gc,heap", "GC(%d)   class space    used %dK, committed %dK, reserved %dK", id, classK, classK+classK/8, 1048576)
+}
+
+func (g *logGenerator) regionLines(at time.Duration, id int, eden, survivors, old, humongous float64) {
+	metaK := int64(g.metaspace) / int64(utils.KB)
+	committedK := metaK + metaK/8
+	classK := metaK / 10
+
+	g.line(at, "gc,heap", "GC(%d) Eden regions: %d->0(%d)", id, g.regions(eden), g.regions(g.edenSize()))
+	g.line(at, "gc,heap", "GC(%d) Survivor regions: %d->%d(%d)", id, g.regions(survivors), g.regions(g.survivors), g.regions(g.survivorMax))
+	g.line(at, "gc,heap", "GC(%d) Old regions: %d->%d", id, g.regions(old), g.regions(g.old()))
+	g.line(at, "gc,heap", "GC(%d) Humongous regions: %d->%d", id, g.regions(humongous), g.regions(g.humongous))
+	g.line(at, "gc,metaspace", "GC(%d) Metaspace: %dK(%dK)->%dK(%dK) NonClass: %dK(%dK)->%dK(%dK) Class: %dK(%dK)->%dK(%dK)", id,
+		metaK, committedK, metaK, committedK, metaK-classK, committedK-classK, metaK-classK, committedK-classK,
+		classK, classK+classK/8, classK, classK+classK/8)
+}
+
+func (g *logGenerator) cpuLine(at time.Duration, id int, pauseMs float64) {
+	real := pauseMs / 1000
+	user := real * genWorkers * 0.8 * jitter(g.rng, 0.2)
+	sys := real * 0.5 * jitter(g.rng, 0.5)
+	g.line(at, "gc,cpu", "GC(%d) User=%.2fs Sys=%.2fs Real=%.2fs", id, user, sys, real)
+}
+
+// line writes a log line with time and uptime decorations; tags are padded the way the JVM pads them
+func (g *logGenerator) line(at time.Duration, tags, format string, args ...any) {
+	fmt.Fprintf(g.out, "%s[%-12s] %s\n", g.decorations(at), tags, fmt.Sprintf(format, args...))
+}
+
+func (g *logGenerator) decorations(at time.Duration) string {
+	return fmt.Sprintf("[%s][%.3fs]", g.config.Start.Add(at).Format(TimestampLayout), at.Seconds())
+}
+
+// megabytes formats a size the way the JVM summarises heap occupancy, e.g. 65M
+func megabytes(bytes float64) string {
+	return fmt.Sprintf("%dM", int64(math.Round(bytes/float64(utils.MB))))
+}
+
+func msDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// jitter is a random factor within spread of 1, e.g. 0.9 to 1.1 for a spread of 0.1
+func jitter(rng *rand.Rand, spread float64) float64 {
+	return 1 + (rng.Float64()*2-1)*spread
+}
+
+// Summary describes the ground truth in one line, for printing after a log is generated
+func (t *GroundTruth) Summary() string {
+	parts := []string{
+		fmt.Sprintf("%d young", t.Young),
+		fmt.Sprintf("%d mixed", t.Mixed),
+		fmt.Sprintf("%d full", t.Full),
+		fmt.Sprintf("%d concurrent cycles", t.ConcurrentCycles),
+		fmt.Sprintf("%d evacuation failures", t.EvacuationFailures),
+	}
+	return fmt.Sprintf("%s; live set %s → %s, %s allocated, max pause %s", strings.Join(parts, ", "),
+		utils.MemorySize(t.LiveStartBytes), utils.MemorySize(t.LiveEndBytes), utils.MemorySize(t.AllocatedBytes), utils.FormatDuration(t.MaxPause))
+}
@@ -0,0 +1,137 @@
+package gc
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * G1 allocates an object over half a region as humongous: straight into
+ * old gen, in a run of contiguous regions of its own. The tail of its last
+ * region stays empty until the object dies, so a 1.1M array with 1M
+ * regions takes 2M, and one just over half a region wastes almost half.
+ *
+ * Most applications allocate their large objects in a few sizes (buffers,
+ * batch arrays, caches), so the sizes gc+humongous=debug or a JFR
+ * recording's outside-TLAB allocations show are bucketed by power of two,
+ * and each larger region size the JVM accepts is tried on them. The
+ * suggested region size is the smallest that makes HumongousNormalGoal of
+ * the objects normal while the heap keeps MinHeapRegions regions, since
+ * fewer, larger regions make the young generation and collection sets
+ * coarser.
+ */
+
+const (
+	HumongousSourceLog = "gc+humongous"
+	HumongousSourceJFR = "JFR"
+)
+
+// calculateHumongousHistogram buckets the logged sizes and tries larger region sizes on them
+func calculateHumongousHistogram(events []*GCEvent, analysis *GCAnalysis) HumongousHistogram {
+	histogram := HumongousHistogram{Source: analysis.HumongousSizes.Source, Sizes: analysis.HumongousSizes.Sizes}
+	if len(histogram.Sizes) == 0 {
+		return histogram
+	}
+
+	regionSize, heap := analysis.HeapRegionSize, analysis.HeapMax
+	for _, event := range events {
+		if regionSize == 0 {
+			regionSize = event.RegionSize
+		}
+		if analysis.HeapMax == 0 {
+			heap = max(heap, event.HeapTotal)
+		}
+	}
+	histogram.RegionSize = regionSize
+
+	sizes := slices.Sorted(maps.Keys(histogram.Sizes))
+	for _, size := range sizes {
+		count := histogram.Sizes[size]
+		histogram.Objects += count
+		histogram.Total += size * utils.MemorySize(count)
+
+		bucketMax := utils.MemorySize(1)
+		for bucketMax < size {
+			bucketMax *= 2
+		}
+		if n := len(histogram.Buckets); n == 0 || histogram.Buckets[n-1].Max != bucketMax {
+			histogram.Buckets = append(histogram.Buckets, HumongousBucket{Min: bucketMax / 2, Max: bucketMax})
+		}
+		bucket := &histogram.Buckets[len(histogram.Buckets)-1]
+		bucket.Count += count
+		if regionSize > 0 {
+			regions, wasted := humongousFootprint(size, regionSize)
+			bucket.Regions += regions * count
+			bucket.Wasted += wasted * utils.MemorySize(count)
+			histogram.Wasted += wasted * utils.MemorySize(count)
+		}
+	}
+	if regionSize == 0 {
+		return histogram
+	}
+
+	maxRegion := 32 * utils.MB
+	if flags.ParseJDKVersion(analysis.JVMVersion) >= 18 {
+		maxRegion = 512 * utils.MB
+	}
+	for candidate := regionSize * 2; candidate <= maxRegion; candidate *= 2 {
+		option := RegionSizeOption{Size: candidate}
+		if heap > 0 {
+			option.HeapRegions = int(heap / candidate)
+		}
+		for _, size := range sizes {
+			count := histogram.Sizes[size]
+			if size <= candidate/2 {
+				option.Normal += count
+				continue
+			}
+			_, wasted := humongousFootprint(size, candidate)
+			option.Wasted += wasted * utils.MemorySize(count)
+		}
+		option.NormalShare = float64(option.Normal) / float64(histogram.Objects)
+		histogram.Options = append(histogram.Options, option)
+
+		if histogram.Suggested == 0 && histogram.Objects >= MinHumongousObjects &&
+			option.NormalShare >= HumongousNormalGoal && (heap == 0 || option.HeapRegions >= MinHeapRegions) {
+			histogram.Suggested = candidate
+		}
+		if option.Normal == histogram.Objects {
+			break // Larger regions only make the heap coarser
+		}
+	}
+	return histogram
+}
+
+// humongousFootprint is how many regions an object of size takes, and what's left unused in the last one
+func humongousFootprint(size, regionSize utils.MemorySize) (int, utils.MemorySize) {
+	regions := (size + regionSize - 1) / regionSize
+	return int(regions), regions*regionSize - size
+}
+
+// Significant is whether enough humongous objects were seen to size regions for them
+func (h HumongousHistogram) Significant() bool {
+	return h.Objects >= MinHumongousObjects && h.RegionSize > 0
+}
+
+// Option is what region size would make of the objects, if it's one of the options
+func (h HumongousHistogram) Option(size utils.MemorySize) (RegionSizeOption, bool) {
+	index := slices.IndexFunc(h.Options, func(o RegionSizeOption) bool { return o.Size == size })
+	if index < 0 {
+		return RegionSizeOption{}, false
+	}
+	return h.Options[index], true
+}
+
+// Commonest is the bucket holding the most objects, the larger one on a tie
+func (h HumongousHistogram) Commonest() HumongousBucket {
+	if len(h.Buckets) == 0 {
+		return HumongousBucket{}
+	}
+	return slices.MaxFunc(h.Buckets, func(a, b HumongousBucket) int {
+		return cmp.Or(cmp.Compare(a.Count, b.Count), cmp.Compare(a.Max, b.Max))
+	})
+}
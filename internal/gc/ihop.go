@@ -0,0 +1,160 @@
+package gc
+
+import (
+	"math"
+	"slices"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * G1 starts concurrent marking once old occupancy passes the initiating heap
+ * occupancy (IHOP), and marking then races the application: old data keeps
+ * growing until marking ends and mixed collections can reclaim it. Started
+ * too late, the heap fills first and a Full GC or evacuation failure ends
+ * the race; started too early, marking runs more often than it needs to.
+ *
+ * Each cycle is measured from the pause that started it to its Remark, by
+ * the old occupancy (old plus humongous) the pauses in between left. The
+ * race margin is the heap free at the start, less G1ReservePercent, over how
+ * much old data grew while marking: below 1 the heap would have run out.
+ * Cycles are followed in log order, so logs without wall-clock time work too.
+ */
+
+// ihopCycle is one marking cycle's race against allocation
+type ihopCycle struct {
+	start, peak float64 // Old occupancy when marking started and its highest before Remark, as heap shares
+	lost        bool    // A Full GC or evacuation failure came before Remark
+}
+
+func calculateIHOP(events []*GCEvent) IHOPAnalysis {
+	var ihop IHOPAnalysis
+	var cycles []ihopCycle
+	predicted, updates := 0, 0
+	for i, event := range events {
+		if event.IHOPThreshold > 0 {
+			ihop.Logged = true
+			ihop.Adaptive = ihop.Adaptive || event.IHOPAdaptive
+			ihop.ThresholdPercent = event.IHOPThresholdPercent
+			ihop.OldAllocationRate = event.IHOPOldAllocationRate
+			ihop.MarkingLength = event.IHOPMarkingLength
+			updates++
+			if event.IHOPPredictionActive {
+				predicted++
+			}
+		}
+		if CategorizeGCType(event.Type) == "Concurrent Mark" {
+			if cycle, ok := followCycle(events, i); ok {
+				cycles = append(cycles, cycle)
+			}
+		}
+	}
+	if updates > 0 {
+		ihop.PredictionActiveRate = float64(predicted) / float64(updates)
+	}
+	if len(cycles) == 0 {
+		return ihop
+	}
+
+	ihop.Cycles = len(cycles)
+	ihop.MinMargin = 1
+	ihop.MinRaceMargin = math.Inf(1)
+	var starts []float64
+	for _, cycle := range cycles {
+		starts = append(starts, cycle.start)
+		growth := cycle.peak - cycle.start
+		ihop.MaxGrowth = max(ihop.MaxGrowth, growth)
+		ihop.MinMargin = min(ihop.MinMargin, 1-cycle.peak)
+
+		race := math.Inf(1)
+		if cycle.lost {
+			ihop.LostRaces++
+			race = 0
+		} else if growth > 0 {
+			race = max(1-G1ReserveShare-cycle.start, 0) / growth
+		}
+		ihop.MinRaceMargin = min(ihop.MinRaceMargin, race)
+	}
+	if math.IsInf(ihop.MinRaceMargin, 1) {
+		ihop.MinRaceMargin = 0 // Old data never grew during marking, so there was no race
+	}
+	slices.Sort(starts)
+	ihop.StartOccupancy = starts[len(starts)/2]
+
+	// Start marking where the worst growth, with room to spare, still leaves the margin free
+	suggested := int(math.Floor((1 - IHOPMarginGoal - ihop.MaxGrowth*IHOPSafetyFactor) * 100))
+	ihop.SuggestedPercent = min(max(suggested, MinIHOPPercent), MaxIHOPPercent)
+	return ihop
+}
+
+// followCycle measures the marking cycle at events[i] up to its Remark; ok is false when the log ends first
+func followCycle(events []*GCEvent, i int) (ihopCycle, bool) {
+	cycle := events[i]
+	var initiating *GCEvent
+	for j := i - 1; j >= 0 && initiating == nil; j-- {
+		if CategorizeGCType(events[j].Type) != "Concurrent Mark" {
+			initiating = events[j]
+		}
+	}
+	if initiating == nil || initiating.HeapTotal == 0 {
+		return ihopCycle{}, false
+	}
+
+	heap := float64(initiating.HeapTotal)
+	measured := ihopCycle{start: float64(oldOccupancy(initiating)) / heap, lost: cycle.ConcurrentMarkAborted}
+	measured.peak = measured.start
+	if measured.lost {
+		return measured, true
+	}
+	for _, event := range events[i+1:] {
+		switch CategorizeGCType(event.Type) {
+		case "Concurrent Mark":
+			return measured, false
+		case GCTypeRemark:
+			if event.ID == cycle.ID {
+				return measured, true
+			}
+		case GCTypeFull:
+			measured.lost = true
+			return measured, true
+		}
+		if event.HasEvacuationFailure {
+			measured.lost = true
+			return measured, true
+		}
+		if event.HeapTotal > 0 {
+			measured.peak = max(measured.peak, float64(oldOccupancy(event))/heap)
+		}
+	}
+	return measured, false
+}
+
+// oldOccupancy is the old and humongous data a pause left, or the whole heap when regions weren't logged
+func oldOccupancy(event *GCEvent) utils.MemorySize {
+	if event.OldMemoryAfter > 0 {
+		return event.OldMemoryAfter + event.HumongousMemoryAfter
+	}
+	return event.HeapAfter
+}
+
+/*
+ * StartsLate is whether marking lost races, or finished with less than
+ * IHOPMarginGoal of the heap free, and starting it earlier would have helped.
+ * When old data alone already fills the heap as marking starts, no threshold
+ * does: the live set outgrew the heap.
+ */
+func (ihop IHOPAnalysis) StartsLate() bool {
+	return ihop.Cycles > 0 && (ihop.LostRaces > 0 || ihop.MinMargin < IHOPMarginGoal) &&
+		ihop.StartOccupancy < 1-IHOPMarginGoal && ihop.SuggestedPercent < ihop.CurrentPercent()
+}
+
+// StartsEarly is whether old data grew during marking yet left IHOPIdleMargin of the heap free through several cycles
+func (ihop IHOPAnalysis) StartsEarly() bool {
+	return ihop.Cycles >= MinIHOPCycles && ihop.LostRaces == 0 && ihop.MaxGrowth > 0 &&
+		ihop.MinMargin >= IHOPIdleMargin && ihop.SuggestedPercent > ihop.CurrentPercent()
+}
+
+// CurrentPercent is the effective IHOP the log shows, as InitiatingHeapOccupancyPercent
+func (ihop IHOPAnalysis) CurrentPercent() int {
+	return int(math.Round(ihop.StartOccupancy * 100))
+}
@@ -0,0 +1,139 @@
+package gc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+)
+
+// -XX:+UseZGC, -XX:MaxGCPauseMillis=200
+var xxFlagPattern = regexp.MustCompile(`-XX:[+-]?(\w+)(=[^\s,;)]*)?`)
+
+/*
+ * adaptToJDK fits the flag advice to the JDK release that wrote the log:
+ *
+ *   - a flag the release has retired is swapped for its replacement, or the
+ *     suggestion dropped when there's nothing to replace it with
+ *   - a flag the release doesn't have yet drops the suggestion
+ *   - an experimental flag gets the option that unlocks it
+ *
+ * Suggestions whose flags not every release has are tagged with the releases
+ * they work on. With the version unknown (0) nothing is swapped or dropped
+ * and the tags are left to tell.
+ */
+func (issues *GCIssues) adaptToJDK(version int) {
+	issues.JDKVersion = version
+	for _, group := range [][]PerformanceIssue{issues.Critical, issues.Warning, issues.Info} {
+		for i := range group {
+			var adapted []string
+			for _, recommendation := range group[i].Recommendation {
+				if advice, ok := adaptAdvice(recommendation, version); ok {
+					adapted = append(adapted, advice)
+				}
+			}
+			group[i].Recommendation = adapted
+		}
+	}
+}
+
+// adaptAdvice rewrites the flags in one suggestion for version; ok is false when it doesn't apply there
+func adaptAdvice(advice string, version int) (string, bool) {
+	var since, until, experimentalUntil int // Releases all of the suggestion's flags work on
+	dropped, swapped := false, false
+	unlocked := make(map[string]bool)
+
+	rewritten := xxFlagPattern.ReplaceAllStringFunc(advice, func(flag string) string {
+		name := xxFlagPattern.FindStringSubmatch(flag)[1]
+
+		if introduced := flags.Introduced(name); introduced > 0 {
+			since = max(since, introduced)
+			dropped = dropped || (version > 0 && version < introduced)
+		}
+
+		if lifecycle, found := flags.Lookup(name); found {
+			if version > 0 && lifecycle.Stage(version) != "" {
+				if !strings.HasPrefix(lifecycle.Replacement, "-") {
+					dropped = true
+					return flag
+				}
+				// "-XX:+UseG1GC, or -XX:+UseZGC for low pauses" swaps in its first option
+				replacement, _, _ := strings.Cut(lifecycle.Replacement, ",")
+				swapped = true
+				return strings.Fields(replacement)[0]
+			}
+			if retired := retiredIn(lifecycle); retired > 0 && (until == 0 || retired-1 < until) {
+				until = retired - 1
+			}
+		}
+
+		option, needsUntil := flags.Unlock(name)
+		if option == "" {
+			return flag
+		}
+		unlock := "-XX:+" + option
+		switch {
+		case needsUntil > 0 && version == 0:
+			experimentalUntil = needsUntil
+		case needsUntil > 0 && version > needsUntil, unlocked[unlock], strings.Contains(advice, unlock):
+		default:
+			unlocked[unlock] = true
+			return unlock + " " + flag
+		}
+		return flag
+	})
+	if dropped {
+		return "", false
+	}
+	if swapped {
+		rewritten = dropCoveredXlog(rewritten)
+	}
+
+	if tag := releaseTag(since, until, experimentalUntil); tag != "" {
+		rewritten += " [" + tag + "]"
+	}
+	return rewritten, true
+}
+
+// dropCoveredXlog removes an -Xlog option another one in the advice extends, as
+// swapping -XX:+PrintGCDetails -XX:+PrintGCTimeStamps leaves -Xlog:gc* -Xlog:gc*:file=gc.log:uptime
+func dropCoveredXlog(advice string) string {
+	words := strings.Fields(advice)
+	kept := words[:0]
+	for i, word := range words {
+		covered := false
+		for j, other := range words {
+			covered = covered || (i != j && strings.HasPrefix(word, "-Xlog:") && len(other) > len(word) && strings.HasPrefix(other, word))
+		}
+		if !covered {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// retiredIn is the first release that ignores or rejects a flag, or 0 while every release takes it
+func retiredIn(lifecycle flags.Lifecycle) int {
+	if lifecycle.Obsoleted > 0 {
+		return lifecycle.Obsoleted
+	}
+	return lifecycle.Expired
+}
+
+// releaseTag names the JDK releases a suggestion works on, e.g. "JDK 11+" or "JDK 9-13"; "" for all of them
+func releaseTag(since, until, experimentalUntil int) string {
+	var tag string
+	switch {
+	case since > 0 && until > 0:
+		tag = fmt.Sprintf("JDK %d-%d", since, until)
+	case since > 0:
+		tag = fmt.Sprintf("JDK %d+", since)
+	case until > 0:
+		tag = fmt.Sprintf("up to JDK %d", until)
+	}
+	if experimentalUntil > 0 {
+		tag += fmt.Sprintf(", experimental before JDK %d", experimentalUntil+1)
+	}
+	return strings.TrimPrefix(tag, ", ")
+}
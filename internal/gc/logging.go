@@ -0,0 +1,95 @@
+package gc
+
+import "strings"
+
+// RecommendedXlog logs everything the analysis uses, rotated so a long-running JVM keeps its history
+const RecommendedXlog = "-Xlog:gc*,gc+phases=debug:file=gc.log:time,uptime,level,tags:filecount=5,filesize=20m"
+
+// LoggingGap is detail the log leaves out and the -Xlog setting that adds it
+type LoggingGap struct {
+	Missing string // What the log lacks
+	Setting string // -Xlog selector or decorator that logs it
+	Impact  string // What the analysis does without
+}
+
+/*
+ * checkLogging finds the details the analysis relies on that the log has no
+ * lines for, which means the JVM wasn't logging them. It goes by the tags the
+ * lines carry rather than what was parsed from them, so a line in a format
+ * the parser doesn't know isn't mistaken for a logging gap; only per-worker
+ * phase timings, which share gc,phases with the info level summary, are
+ * checked by what was parsed, and humongous object sizes are only asked for
+ * when the log shows humongous regions. Region, phase and worker details are G1
+ * output; other collectors don't log them under any setting, so they're
+ * only asked of G1 logs.
+ */
+func checkLogging(context *ParseContext) []LoggingGap {
+	if len(context.Events) == 0 {
+		return nil
+	}
+
+	var timestamps, phases, g1, humongous bool
+	for _, event := range context.Events {
+		timestamps = timestamps || !event.Timestamp.IsZero()
+		humongous = humongous || event.HumongousRegionsBefore > 0
+		phases = phases || len(event.WorkerPhases) > 0 || event.ObjectCopyTime > 0
+		g1 = g1 || event.Type == GCTypeMixed || strings.HasPrefix(event.Cause, "G1")
+	}
+	g1 = g1 || context.Analysis.HeapRegionSize > 0
+	logged := func(tags string) bool {
+		for seen := range context.tags {
+			if seen == tags || strings.HasPrefix(seen, tags+",") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var gaps []LoggingGap
+	add := func(present, g1Only bool, gap LoggingGap) {
+		if !present && (g1 || !g1Only) {
+			gaps = append(gaps, gap)
+		}
+	}
+	add(timestamps, false, LoggingGap{
+		Missing: "Wall-clock timestamps",
+		Setting: "time decorator (:time,uptime,level,tags)",
+		Impact:  "No runtime, throughput, allocation rate or GC frequency; no time axis to match against requests or alerts",
+	})
+	add(context.startup, false, LoggingGap{
+		Missing: "JVM startup lines",
+		Setting: "the file logged from JVM start; raise filecount so rotation keeps it",
+		Impact:  "Heap size, region size and CPU count unknown, so utilization and thread advice are guesses",
+	})
+	add(logged("gc,heap"), true, LoggingGap{
+		Missing: "Region counts per collection",
+		Setting: "gc+heap (in gc*)",
+		Impact:  "No eden, survivor, old or humongous breakdown: promotion, region and humongous analysis skipped",
+	})
+	add(phases, true, LoggingGap{
+		Missing: "Pause phase and worker timings",
+		Setting: "gc+phases=debug",
+		Impact:  "Slow pauses can't be put down to object copy, root scanning or straggling workers",
+	})
+	add(logged("gc,task"), true, LoggingGap{
+		Missing: "GC worker counts",
+		Setting: "gc+task (in gc*)",
+		Impact:  "ParallelGCThreads advice can't see how many workers pauses used",
+	})
+	add(logged("gc,cpu"), false, LoggingGap{
+		Missing: "CPU time per collection",
+		Setting: "gc+cpu (in gc*)",
+		Impact:  "CPU starvation and container throttling during pauses go undetected",
+	})
+	add(!humongous || logged("gc,humongous"), true, LoggingGap{
+		Missing: "Humongous object sizes",
+		Setting: "gc+humongous=debug",
+		Impact:  "Humongous regions are counted but not their objects' sizes, so no G1HeapRegionSize that would make them normal is suggested",
+	})
+	add(logged("gc,metaspace"), false, LoggingGap{
+		Missing: "Metaspace usage",
+		Setting: "gc+metaspace (in gc*)",
+		Impact:  "Class metadata growth, a common non-heap leak, isn't tracked",
+	})
+	return gaps
+}
@@ -0,0 +1,109 @@
+package gc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * WriteLog writes events as a JDK 9+ unified logging GC log (-Xlog:gc*),
+ * with time and uptime decorations: the lines this parser, and the tools
+ * that only read logs, take a collection from.
+ *
+ * 	gc			the pause summary, heap before->after(committed) and duration
+ * 	gc,cpu		user, sys and real time, when known
+ * 	gc,heap		eden, survivor and old regions, given a region size
+ * 	gc,metaspace	metaspace before->after
+ *
+ * It's a pseudo log: phases, workers and the heap blocks a real log has are
+ * missing, sizes are rounded to kilobytes, and uptime counts from the first
+ * event since a recording's events don't say when the JVM started. Events
+ * of a type a log has no line for (ZGC and Shenandoah cycles, say) are
+ * skipped and counted.
+ */
+func WriteLog(w io.Writer, events []*GCEvent, analysis *GCAnalysis) (skipped int, err error) {
+	out := bufio.NewWriter(w)
+	var start time.Time
+	if len(events) > 0 {
+		start = events[0].Timestamp
+	}
+	line := func(at time.Time, tags, format string, args ...any) {
+		fmt.Fprintf(out, "[%s][%.3fs][%-12s] %s\n", at.Format(TimestampLayout), at.Sub(start).Seconds(),
+			tags, fmt.Sprintf(format, args...))
+	}
+
+	if analysis.Collector != "" {
+		line(start, "gc", "Using %s", analysis.Collector)
+	}
+	if analysis.JVMVersion != "" {
+		line(start, "gc,init", "Version: %s", analysis.JVMVersion)
+	}
+	if analysis.HeapRegionSize > 0 {
+		line(start, "gc,init", "Heap Region Size: %dM", int64(analysis.HeapRegionSize/utils.MB))
+	}
+	if analysis.HeapMax > 0 {
+		line(start, "gc,init", "Heap Max Capacity: %dM", int64(analysis.HeapMax/utils.MB))
+	}
+
+	for _, event := range events {
+		var pause string
+		switch event.Type {
+		case GCTypeYoung, GCTypeMixed:
+			subtype := event.Subtype
+			if subtype == "" {
+				subtype = "Normal"
+			}
+			if event.Type == GCTypeMixed {
+				subtype = "Mixed"
+			}
+			pause = fmt.Sprintf("Pause Young (%s) (%s)", subtype, event.Cause)
+			if event.ToSpaceExhausted {
+				pause += " (Evacuation Failure)"
+			}
+		case GCTypeFull:
+			pause = fmt.Sprintf("Pause Full (%s)", event.Cause)
+		case GCTypeRemark, GCTypeCleanup:
+			pause = "Pause " + event.Type
+		case GCTypeConcurrent:
+			line(event.Timestamp, "gc", "GC(%d) Concurrent Mark Cycle", event.ID)
+			line(event.Timestamp.Add(event.ConcurrentDuration), "gc", "GC(%d) Concurrent Mark Cycle %.3fms",
+				event.ID, milliseconds(event.ConcurrentDuration))
+			continue
+		default:
+			skipped++
+			continue
+		}
+
+		if regionSize := event.RegionSize; regionSize > 0 {
+			regions := func(size utils.MemorySize) int64 { return int64((size + regionSize - 1) / regionSize) }
+			line(event.Timestamp, "gc,heap", "GC(%d) Eden regions: %d->%d", event.ID,
+				regions(event.EdenMemoryBefore), regions(event.EdenMemoryAfter))
+			line(event.Timestamp, "gc,heap", "GC(%d) Survivor regions: %d->%d", event.ID,
+				regions(event.SurvivorMemoryBefore), regions(event.SurvivorMemoryAfter))
+			line(event.Timestamp, "gc,heap", "GC(%d) Old regions: %d->%d", event.ID,
+				regions(event.OldMemoryBefore), regions(event.OldMemoryAfter))
+		}
+		if event.MetaspaceUsedBefore > 0 {
+			line(event.Timestamp, "gc,metaspace", "GC(%d) Metaspace: %s(%s)->%s(%s)", event.ID,
+				kilobytes(event.MetaspaceUsedBefore), kilobytes(event.MetaspaceCommittedBefore),
+				kilobytes(event.MetaspaceUsedAfter), kilobytes(event.MetaspaceCommittedAfter))
+		}
+		line(event.Timestamp, "gc", "GC(%d) %s %s->%s(%s) %.3fms", event.ID, pause,
+			kilobytes(event.HeapBefore), kilobytes(event.HeapAfter), kilobytes(event.HeapTotal), milliseconds(event.Duration))
+		if event.RealTime > 0 {
+			line(event.Timestamp, "gc,cpu", "GC(%d) User=%.2fs Sys=%.2fs Real=%.2fs", event.ID,
+				event.UserTime.Seconds(), event.SystemTime.Seconds(), event.RealTime.Seconds())
+		}
+	}
+
+	return skipped, out.Flush()
+}
+
+// kilobytes formats a size as the JVM does when it's too small for megabytes to be exact, e.g. 65536K
+func kilobytes(size utils.MemorySize) string {
+	return fmt.Sprintf("%dK", int64(size/utils.KB))
+}
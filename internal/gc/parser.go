@@ -2,12 +2,16 @@ package gc
 
 import (
 	"bufio"
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/mabhi256/jdiag/utils"
 )
@@ -20,6 +24,8 @@ const (
 	GCTypeMixed      = "Mixed"
 	GCTypeFull       = "Full"
 	GCTypeConcurrent = "Concurrent Mark Cycle"
+	GCTypeRemark     = "Remark"  // G1 stop-the-world pause that finishes concurrent marking
+	GCTypeCleanup    = "Cleanup" // G1 stop-the-world pause at the end of a concurrent cycle
 
 	// Parsing states
 	StateNormal = iota
@@ -29,19 +35,36 @@ const (
 var (
 	// [2025-07-27T06:54:55.176-0400]
 	timestampPattern = regexp.MustCompile(`\[(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}[+-]\d{4})\]`)
-	// gcIDPattern      = regexp.MustCompile(`GC\((\d+)\)`)
+	// A leading decoration that starts like a timestamp, to catch ones that don't parse
+	timestampLikePattern = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2}T[^\]]*)\]`)
+	// [0.855s] or [info ], the decorations every unified logging line starts with
+	decorationPattern = regexp.MustCompile(`^\[[^\]]+\]`)
+	// GC(0) Pause Young ... that no parser took, e.g. cut off mid-line
+	pauseLinePattern = regexp.MustCompile(`\[gc\s*\]\s*GC\(\d+\)\s+Pause`)
+	// GC(12), the collection a detail line belongs to
+	gcIDPattern = regexp.MustCompile(`GC\((\d+)\)`)
 
 	// ==== Configuration patterns (only used initially) ====
 
+	// [gc] Using G1
+	collectorPattern = regexp.MustCompile(`\[gc\s*\]\s+Using ([A-Z][\w ]+)$`)
+
 	// Version: 21.0.8+9-Ubuntu-0ubuntu124.04.1 (release)
 	versionPattern = regexp.MustCompile(`\[gc,init\]\s+Version:\s+([^\s(]+)`)
 
+	// CPUs: 12 total, 12 available
+	cpusPattern = regexp.MustCompile(`\[gc,init\]\s+CPUs:\s+\d+ total,\s+(\d+) available`)
+
 	// Heap region size: 1M
 	heapRegionPattern = regexp.MustCompile(`\[gc,init\]\s+Heap Region Size:\s+(\d+[KMGT])`)
 
 	// Maximum heap size: 256M
 	heapMaxPattern = regexp.MustCompile(`\[gc,init\]\s+Heap Max Capacity:\s+(\d+[KMGT])`)
 
+	// CDS archive(s) mapped at: [0x00007f423b000000-0x00007f423bca0000-0x00007f423bca0000), size 13238272, SharedBaseAddress: ...
+	// CDS archive(s) not mapped
+	cdsArchivePattern = regexp.MustCompile(`CDS archive\(s\) (mapped|not mapped)(?:.*?, size (\d+))?`)
+
 	// ==== Main GC event patterns ====
 
 	// before->after pattern for memory measurements
@@ -64,12 +87,6 @@ var (
 	concurrentCycleEndPattern = regexp.MustCompile(`GC\((\d+)\)\s+Concurrent (?:Mark )?Cycle\s+([\d.]+)ms$`)
 	concurrentAbortPattern    = regexp.MustCompile(`GC\((\d+)\)\s+Concurrent Mark Abort`)
 
-	// Pause Remark 211M->211M(256M) 21.685ms
-	pauseRemarkPattern = regexp.MustCompile(`GC\((\d+)\)\s+Pause Remark\s+` + beforeAfter + `\s+([\d.]+)ms`)
-
-	// Pause Cleanup 223M->213M(256M) 0.271ms
-	pauseCleanupPattern = regexp.MustCompile(`GC\((\d+)\)\s+Pause Cleanup\s+` + beforeAfter + `\s+([\d.]+)ms`)
-
 	// ==== Region and memory patterns ====
 
 	// Eden regions: 50->0(50)
@@ -88,6 +105,52 @@ var (
 	// Metaspace: 138K(320K)->138K(320K) NonClass: 130K(192K)->130K(192K) Class: 8K(128K)->8K(128K)
 	metaspaceBeforeAfterPattern = regexp.MustCompile(`(Metaspace|NonClass|Class):\s+(\d+)K\((\d+)K\)->(\d+)K\((\d+)K\)`)
 
+	// PSYoungGen: 28949K(153088K)->2515K(153088K) Eden: 28949K(131584K)->0K(131584K) From: 0K(21504K)->2515K(21504K)
+	// DefNew: 3371K(10944K)->0K(11008K) Eden: 3371K(5504K)->0K(5568K) From: 0K(5440K)->0K(5440K)
+	youngGenPattern = regexp.MustCompile(`Eden:\s+(\d+)K\((\d+)K\)->(\d+)K\((\d+)K\)\s+From:\s+(\d+)K\((\d+)K\)->(\d+)K\((\d+)K\)`)
+
+	// ParOldGen: 1036K(349696K)->1044K(349696K)
+	// Tenured: 1154K(16384K)->2099K(16384K)
+	oldGenPattern = regexp.MustCompile(`(?:ParOldGen|PSOldGen|Tenured):\s+(\d+)K\((\d+)K\)->(\d+)K\((\d+)K\)`)
+
+	// ==== Tenuring patterns (gc+age=trace) ====
+
+	// Desired survivor size 7340032 bytes, new threshold 1 (max threshold 15)
+	desiredSurvivorPattern = regexp.MustCompile(`Desired survivor size (\d+) bytes, new threshold (\d+) \(max threshold (\d+)\)`)
+
+	// Age table with threshold 1 (max threshold 15)
+	ageTableHeaderPattern = regexp.MustCompile(`Age table with threshold \d+`)
+
+	// - age   1:    1678440 bytes,    1678440 total
+	ageTablePattern = regexp.MustCompile(`- age\s+\d+:\s+\d+ bytes,\s+(\d+) total`)
+
+	// ==== Adaptive IHOP patterns (gc+ihop=debug) ====
+
+	// Basic information (value update), threshold: 188743680B (45.00), target occupancy: 419430400B, current occupancy: 98566144B,
+	// recent allocation size: 0B, recent allocation duration: 819.19ms, recent old gen allocation rate: 0.00B/s, recent marking phase length: 0.00ms
+	ihopBasicPattern = regexp.MustCompile(`Basic information \(value update\), threshold: (\d+)B \(([\d.]+)\), target occupancy: (\d+)B.*recent old gen allocation rate: ([\d.]+)B/s, recent marking phase length: ([\d.]+)ms`)
+
+	// Adaptive IHOP information (value update), threshold: 188743680B (45.00), internal target occupancy: 377487360B, occupancy: 98566144B,
+	// additional buffer size: 50331648B, predicted old gen allocation rate: 1234.00B/s, predicted marking phase length: 12.30ms, prediction active: false
+	ihopAdaptivePattern = regexp.MustCompile(`Adaptive IHOP information \(value update\), threshold: (\d+)B \(([\d.]+)\).*predicted old gen allocation rate: ([\d.]+)B/s, predicted marking phase length: ([\d.]+)ms, prediction active: (true|false)`)
+
+	// [gc,phases] GC(4) Phase 1: Mark live objects 3.467ms (G1, Serial)
+	// [gc,phases] GC(1) Marking Phase 5.323ms (Parallel)
+	// [gc,phases] GC(0) Class Unloading 0.261ms (gc+phases=debug, within marking)
+	fullPhasePattern = regexp.MustCompile(`GC\((\d+)\)\s+(Phase \d: [A-Za-z ]+?|Marking Phase|Summary Phase|Adjust Roots|Adjust Pointers|Compaction Phase|Post Compact|Class Unloading|Reference Processing)\s+([\d.]+)ms$`)
+
+	// [gc,jni] Allocation failed. Thread stalled by JNI critical section. Thread "worker-1" 1 locked.
+	// [gc,jni] Performing GC after exiting critical section. Thread "zip-writer" 0 locked.
+	gcLockerPattern = regexp.MustCompile(`(Allocation failed\. Thread stalled by JNI critical section|Thread blocked to enter critical region|Performing GC after exiting critical section)\. Thread "(.*)" \d+ locked`)
+
+	// [gc,alloc] worker-1:  Retried waiting for GCLocker too often allocating 256 words
+	gcLockerRetryPattern = regexp.MustCompile(`\]\s+([^\]]+?):\s+Retried waiting for GCLocker too often allocating \d+ words`)
+
+	// [gc,humongous] GC(3) Humongous region 112 (object size 1048592 @ 0x00000000f1000000) remset 0 code roots 0 marked 0 reclaim candidate 1 type array 1
+	// [gc,humongous] GC(3) Reclaimed humongous region 112 (object size 1048592 @ 0x00000000f1000000)
+	// [gc,humongous] GC(3) Live humongous region 7 object size 2097168 start 0x00000000c0700000  with remset 1 code roots 0 is marked 0 reclaim candidate 0 type array 1 (JDK 11)
+	humongousObjectPattern = regexp.MustCompile(`(Reclaimed |Dead |Live )?[Hh]umongous region \d+ \(?object size (\d+) (?:@|start) (0x[0-9a-fA-F]+)`)
+
 	// ==== Worker timing patterns ====
 	counter           = `(\d+)`
 	workerSummaryReal = `Min:\s*([\d.]+),\s*Avg:\s*([\d.]+),\s*Max:\s*([\d.]+),\s*Diff:\s*([\d.]+),\s*Sum:\s*([\d.]+),\s*Workers:\s*(\d+)`
@@ -108,14 +171,20 @@ var (
 	postEvacuatePhaseRegex = regexp.MustCompile(`(Code Roots Fixup|Preserve CM Refs|Reference Processing|Clear Card Table|Evacuation Failure|Reference Enqueuing|Merge Per-Thread State|Code Roots Purge|Redirty Cards|Clear Claimed Marks|Free Collection Set|Humongous Reclaim|Expand Heap After Collection):\s+([\d.]+)ms`)
 )
 
-type ParseError struct {
-	Line    string
+const (
+	MaxParseWarnings      = 100 // Skipped lines kept as samples; the rest are only counted
+	parseWarningSampleLen = 120
+)
+
+// ParseWarning is a line the parser skipped, or only partly understood, and why
+type ParseWarning struct {
 	LineNum int
-	Err     error
+	Reason  string
+	Sample  string // The start of the line
 }
 
-func (e ParseError) Error() string {
-	return fmt.Sprintf("parse error at line %d: %v", e.LineNum, e.Err)
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("line %d: %s: %s", w.LineNum, w.Reason, w.Sample)
 }
 
 type LineParser interface {
@@ -131,6 +200,60 @@ type ParseContext struct {
 	// CreatedEvents map[int]*GCEvent
 	State      int
 	LineNumber int
+
+	lastWarned int             // Line of the last warning, so a line counts once
+	tags       map[string]bool // Tag sets the log has lines for, e.g. "gc,heap"
+	startup    bool            // The log has the lines the JVM writes at startup
+
+	humongous map[string]utils.MemorySize // Live humongous objects by address, so each counts once
+}
+
+// warn records a problem with the current line in the analysis
+func (c *ParseContext) warn(reason, line string) {
+	if c.lastWarned == c.LineNumber {
+		return
+	}
+	c.lastWarned = c.LineNumber
+	c.Analysis.SkippedLines++
+
+	if len(c.Analysis.ParseWarnings) < MaxParseWarnings {
+		// Corrupt lines can hold anything, so the sample keeps to printable text
+		sample := []rune(strings.Map(func(r rune) rune {
+			if unicode.IsPrint(r) {
+				return r
+			}
+			return '?'
+		}, line))
+		if len(sample) > parseWarningSampleLen {
+			sample = append(sample[:parseWarningSampleLen], '…')
+		}
+		c.Analysis.ParseWarnings = append(c.Analysis.ParseWarnings, ParseWarning{LineNum: c.LineNumber, Reason: reason, Sample: string(sample)})
+	}
+}
+
+// eventFor finds the event a detail line (regions, metaspace, phases) belongs
+// to. G1 logs these before the pause's summary line, so a GC ID without an
+// event yet starts one that the summary line fills in. Lines without a GC ID
+// go to the latest event; nil when there is none.
+func (c *ParseContext) eventFor(line string) *GCEvent {
+	matches := gcIDPattern.FindStringSubmatch(line)
+	if matches == nil {
+		if len(c.Events) == 0 {
+			return nil
+		}
+		return c.Events[len(c.Events)-1]
+	}
+
+	gcID, _ := strconv.Atoi(matches[1])
+	if event, exists := c.ActiveEvents[gcID]; exists {
+		return event
+	}
+	event := &GCEvent{
+		ID:         gcID,
+		RegionSize: c.Analysis.HeapRegionSize,
+	}
+	c.ActiveEvents[gcID] = event
+	return event
 }
 
 func NewParseContext() *ParseContext {
@@ -139,6 +262,8 @@ func NewParseContext() *ParseContext {
 		Analysis:     &GCAnalysis{},
 		ActiveEvents: make(map[int]*GCEvent),
 		Concurrent:   make(map[int]*GCEvent),
+		tags:         make(map[string]bool),
+		humongous:    make(map[string]utils.MemorySize),
 		// CreatedEvents: make(map[int]*GCEvent),
 		State: StateNormal,
 	}
@@ -148,8 +273,29 @@ func extractTimestamp(line string, context *ParseContext) {
 	if matches := timestampPattern.FindStringSubmatch(line); len(matches) >= 2 {
 		if timestamp, err := time.Parse(TimestampLayout, matches[1]); err == nil {
 			context.Analysis.EndTime = timestamp
+			return
 		}
 	}
+	if matches := timestampLikePattern.FindStringSubmatch(line); len(matches) >= 2 {
+		context.warn(fmt.Sprintf("unparsable timestamp %q", matches[1]), line)
+	}
+}
+
+// recordTags notes the line's tag set, e.g. gc,heap from [gc,heap     ], and whether it's a startup line
+func recordTags(line string, context *ParseContext) {
+	start := strings.Index(line, "[gc")
+	if start < 0 {
+		return
+	}
+	end := strings.IndexByte(line[start:], ']')
+	if end < 0 {
+		return
+	}
+	tags := strings.TrimSpace(line[start+1 : start+end])
+	context.tags[tags] = true
+	if tags == "gc,init" || (tags == "gc" && strings.Contains(line, "] Using ")) {
+		context.startup = true
+	}
 }
 
 // Handles JVM configuration (only processes config once)
@@ -162,6 +308,14 @@ func NewConfigurationParser() *ConfigurationParser {
 }
 
 func (cp *ConfigurationParser) CanParse(line string, context *ParseContext) bool {
+	// JDK 17+ logs CDS under gc,metaspace after the heap configuration
+	if strings.Contains(line, "CDS archive(s)") {
+		return true
+	}
+	// The collector is logged under plain gc
+	if strings.Contains(line, "] Using ") && collectorPattern.MatchString(line) {
+		return true
+	}
 	if cp.configComplete || context.State == StateConfigComplete {
 		return false
 	}
@@ -169,11 +323,30 @@ func (cp *ConfigurationParser) CanParse(line string, context *ParseContext) bool
 }
 
 func (cp *ConfigurationParser) Parse(line string, context *ParseContext) error {
+	if matches := cdsArchivePattern.FindStringSubmatch(line); len(matches) > 2 {
+		context.Analysis.CDSLogged = true
+		context.Analysis.CDSMapped = matches[1] == "mapped"
+		if size, err := strconv.ParseInt(matches[2], 10, 64); err == nil {
+			context.Analysis.CDSArchiveSize = utils.MemorySize(size)
+		}
+		return nil
+	}
+
+	if matches := collectorPattern.FindStringSubmatch(line); len(matches) > 1 {
+		context.Analysis.Collector = matches[1]
+		return nil
+	}
+
 	if matches := versionPattern.FindStringSubmatch(line); len(matches) > 1 {
 		context.Analysis.JVMVersion = matches[1]
 		return nil
 	}
 
+	if matches := cpusPattern.FindStringSubmatch(line); len(matches) > 1 {
+		context.Analysis.CPUs, _ = strconv.Atoi(matches[1])
+		return nil
+	}
+
 	if matches := heapRegionPattern.FindStringSubmatch(line); len(matches) > 1 {
 		size, err := utils.ParseMemorySize(matches[1])
 		if err != nil {
@@ -220,28 +393,24 @@ func (gp *GCEventParser) Parse(line string, context *ParseContext) error {
 		return fmt.Errorf("invalid GC ID: %v", err)
 	}
 
-	if _, exists := context.Concurrent[gcID]; exists {
-		return nil
-	}
-
+	// Pause Remark and Pause Cleanup share the GC ID of their concurrent cycle
+	// and become events of their own, so every stop-the-world pause is counted
 	event := gp.getOrCreateEvent(gcID, context)
 	return gp.populateEvent(event, matches)
 }
 
+// getOrCreateEvent takes over the event the pause's detail lines started, if any
 func (gp *GCEventParser) getOrCreateEvent(gcID int, context *ParseContext) *GCEvent {
-	// // First check if event already exists anywhere
-	// if event, exists := context.CreatedEvents[gcID]; exists {
-	// 	return event // Return existing event (could be concurrent or active)
-	// }
-
-	event := &GCEvent{
-		ID:         gcID,
-		Timestamp:  context.Analysis.EndTime,
-		RegionSize: context.Analysis.HeapRegionSize,
+	event, exists := context.ActiveEvents[gcID]
+	if !exists || event.Type != "" {
+		event = &GCEvent{
+			ID:         gcID,
+			RegionSize: context.Analysis.HeapRegionSize,
+		}
+		context.ActiveEvents[gcID] = event
 	}
 
-	context.ActiveEvents[gcID] = event
-	// context.CreatedEvents[gcID] = event
+	event.Timestamp = context.Analysis.EndTime
 	context.Events = append(context.Events, event)
 	return event
 }
@@ -292,9 +461,7 @@ func NewConcurrentCycleParser() *ConcurrentCycleParser {
 func (ccp *ConcurrentCycleParser) CanParse(line string, context *ParseContext) bool {
 	return concurrentCycleStartPattern.MatchString(line) ||
 		concurrentCycleEndPattern.MatchString(line) ||
-		concurrentAbortPattern.MatchString(line) ||
-		pauseRemarkPattern.MatchString(line) ||
-		pauseCleanupPattern.MatchString(line)
+		concurrentAbortPattern.MatchString(line)
 }
 
 func (ccp *ConcurrentCycleParser) Parse(line string, context *ParseContext) error {
@@ -313,16 +480,6 @@ func (ccp *ConcurrentCycleParser) Parse(line string, context *ParseContext) erro
 		return ccp.handleCycleEnd(matches, context)
 	}
 
-	// // Handle pause remark - update existing concurrent cycle
-	// if matches := pauseRemarkPattern.FindStringSubmatch(line); len(matches) >= 6 {
-	// 	return ccp.handlePauseRemark(matches, context)
-	// }
-
-	// // Handle pause cleanup - update existing concurrent cycle
-	// if matches := pauseCleanupPattern.FindStringSubmatch(line); len(matches) >= 6 {
-	// 	return ccp.handlePauseCleanup(matches, context)
-	// }
-
 	return nil
 }
 
@@ -394,48 +551,6 @@ func (ccp *ConcurrentCycleParser) handleConcurrentAbort(matches []string, contex
 	return nil
 }
 
-// func (ccp *ConcurrentCycleParser) handlePauseRemark(matches []string, context *ParseContext) error {
-// 	gcID, err := strconv.Atoi(matches[1])
-// 	if err != nil {
-// 		return fmt.Errorf("invalid GC ID: %v", err)
-// 	}
-
-// 	duration, err := strconv.ParseFloat(matches[5], 64)
-// 	if err != nil {
-// 		return fmt.Errorf("invalid duration: %v", err)
-// 	}
-
-// 	// Update existing concurrent cycle instead of creating new event
-// 	if event, exists := context.Concurrent[gcID]; exists {
-// 		// Add remark duration to total concurrent duration
-// 		remarkDuration := time.Duration(duration * float64(time.Millisecond))
-// 		event.ConcurrentDuration += remarkDuration
-// 	}
-
-// 	return nil
-// }
-
-// func (ccp *ConcurrentCycleParser) handlePauseCleanup(matches []string, context *ParseContext) error {
-// 	gcID, err := strconv.Atoi(matches[1])
-// 	if err != nil {
-// 		return fmt.Errorf("invalid GC ID: %v", err)
-// 	}
-
-// 	duration, err := strconv.ParseFloat(matches[5], 64)
-// 	if err != nil {
-// 		return fmt.Errorf("invalid duration: %v", err)
-// 	}
-
-// 	// Update existing concurrent cycle instead of creating new event
-// 	if event, exists := context.Concurrent[gcID]; exists {
-// 		// Add cleanup duration to total concurrent duration
-// 		cleanupDuration := time.Duration(duration * float64(time.Millisecond))
-// 		event.ConcurrentDuration += cleanupDuration
-// 	}
-
-// 	return nil
-// }
-
 // RegionDetailsParser handles region and memory information
 type RegionDetailsParser struct{}
 
@@ -447,40 +562,92 @@ func (rdp *RegionDetailsParser) CanParse(line string, context *ParseContext) boo
 	return regionSummaryPattern.MatchString(line) ||
 		heapSummaryPattern.MatchString(line) ||
 		metaspacePattern.MatchString(line) ||
-		metaspaceBeforeAfterPattern.MatchString(line)
+		metaspaceBeforeAfterPattern.MatchString(line) ||
+		youngGenPattern.MatchString(line) ||
+		oldGenPattern.MatchString(line) ||
+		desiredSurvivorPattern.MatchString(line) ||
+		ageTableHeaderPattern.MatchString(line) ||
+		ageTablePattern.MatchString(line)
 }
 
 func (rdp *RegionDetailsParser) Parse(line string, context *ParseContext) error {
+	event := context.eventFor(line)
+	if event == nil {
+		return nil
+	}
+
 	// Parse region summary transitions
 	if matches := regionSummaryPattern.FindStringSubmatch(line); len(matches) >= 4 {
-		return rdp.parseRegionSummary(matches, context)
+		return rdp.parseRegionSummary(matches, event)
 	}
 
 	// Parse heap summary
 	if matches := heapSummaryPattern.FindStringSubmatch(line); len(matches) >= 3 {
-		return rdp.parseHeapSummary(matches, context)
+		return rdp.parseHeapSummary(matches, event)
 	}
 
 	// Parse metaspace information
 	if matches := metaspacePattern.FindStringSubmatch(line); len(matches) >= 6 {
-		return rdp.parseMetaspaceInfo(matches, context)
+		return rdp.parseMetaspaceInfo(matches, event)
 	}
 
 	// Parse metaspace before/after format
 	if matches := metaspaceBeforeAfterPattern.FindStringSubmatch(line); len(matches) >= 6 {
-		return rdp.parseMetaspaceBeforeAfter(matches, context)
+		return rdp.parseMetaspaceBeforeAfter(matches, event)
+	}
+
+	// Parse Parallel and Serial young generation spaces
+	if matches := youngGenPattern.FindStringSubmatch(line); len(matches) >= 9 {
+		return rdp.parseYoungGen(matches, event)
+	}
+
+	if matches := oldGenPattern.FindStringSubmatch(line); len(matches) >= 5 {
+		event.OldMemoryBefore, _ = utils.ParseMemorySize(matches[1] + "K")
+		event.OldMemoryAfter, _ = utils.ParseMemorySize(matches[3] + "K")
+		return nil
+	}
+
+	// Parse tenuring threshold and age table
+	if matches := desiredSurvivorPattern.FindStringSubmatch(line); len(matches) >= 4 {
+		desired, _ := strconv.ParseInt(matches[1], 10, 64)
+		event.DesiredSurvivorSize = utils.MemorySize(desired)
+		event.TenuringThreshold, _ = strconv.Atoi(matches[2])
+		event.MaxTenuringThreshold, _ = strconv.Atoi(matches[3])
+		return nil
+	}
+
+	// An empty table, with nothing surviving, is only its header
+	if ageTableHeaderPattern.MatchString(line) {
+		event.AgeTableLogged = true
+		return nil
+	}
+
+	// The total is cumulative, so the last age's is the table's
+	if matches := ageTablePattern.FindStringSubmatch(line); len(matches) >= 2 {
+		total, _ := strconv.ParseInt(matches[1], 10, 64)
+		event.AgeTableTotal = utils.MemorySize(total)
+		return nil
 	}
 
 	return nil
 }
 
-func (rdp *RegionDetailsParser) parseRegionSummary(matches []string, context *ParseContext) error {
-	// Find the most recent event to attach region info to
-	if len(context.Events) == 0 {
-		return nil
+func (rdp *RegionDetailsParser) parseYoungGen(matches []string, event *GCEvent) error {
+	kilobytes := func(i int) utils.MemorySize {
+		size, _ := utils.ParseMemorySize(matches[i] + "K")
+		return size
 	}
 
-	event := context.Events[len(context.Events)-1]
+	event.EdenMemoryBefore = kilobytes(1)
+	event.EdenMemoryAfter = kilobytes(3)
+	event.EdenCapacity = kilobytes(4)
+	event.SurvivorMemoryBefore = kilobytes(5)
+	event.SurvivorMemoryAfter = kilobytes(7)
+	event.SurvivorCapacity = kilobytes(8)
+	return nil
+}
+
+func (rdp *RegionDetailsParser) parseRegionSummary(matches []string, event *GCEvent) error {
 	regionType := matches[1]
 	regionsBefore, _ := strconv.Atoi(matches[2])
 	regionsAfter, _ := strconv.Atoi(matches[3])
@@ -520,17 +687,19 @@ func (rdp *RegionDetailsParser) parseRegionSummary(matches []string, context *Pa
 			event.HumongousMemoryBefore = utils.MemorySize(regionsBefore) * event.RegionSize
 			event.HumongousMemoryAfter = utils.MemorySize(regionsAfter) * event.RegionSize
 		}
+	case "Archive":
+		event.ArchiveRegionsBefore = regionsBefore
+		event.ArchiveRegionsAfter = regionsAfter
+		if event.RegionSize > 0 {
+			event.ArchiveMemoryBefore = utils.MemorySize(regionsBefore) * event.RegionSize
+			event.ArchiveMemoryAfter = utils.MemorySize(regionsAfter) * event.RegionSize
+		}
 	}
 
 	return nil
 }
 
-func (rdp *RegionDetailsParser) parseHeapSummary(matches []string, context *ParseContext) error {
-	if len(context.Events) == 0 {
-		return nil
-	}
-
-	event := context.Events[len(context.Events)-1]
+func (rdp *RegionDetailsParser) parseHeapSummary(matches []string, event *GCEvent) error {
 	totalMemory, _ := utils.ParseMemorySize(matches[1] + "K")
 	usedMemory, _ := utils.ParseMemorySize(matches[2] + "K")
 
@@ -545,12 +714,7 @@ func (rdp *RegionDetailsParser) parseHeapSummary(matches []string, context *Pars
 	return nil
 }
 
-func (rdp *RegionDetailsParser) parseMetaspaceInfo(matches []string, context *ParseContext) error {
-	if len(context.Events) == 0 {
-		return nil
-	}
-
-	event := context.Events[len(context.Events)-1]
+func (rdp *RegionDetailsParser) parseMetaspaceInfo(matches []string, event *GCEvent) error {
 	spaceType := matches[1]
 	used, _ := utils.ParseMemorySize(matches[2] + "K")
 	capacity, _ := utils.ParseMemorySize(matches[3] + "K")
@@ -572,12 +736,7 @@ func (rdp *RegionDetailsParser) parseMetaspaceInfo(matches []string, context *Pa
 	return nil
 }
 
-func (rdp *RegionDetailsParser) parseMetaspaceBeforeAfter(matches []string, context *ParseContext) error {
-	if len(context.Events) == 0 {
-		return nil
-	}
-
-	event := context.Events[len(context.Events)-1]
+func (rdp *RegionDetailsParser) parseMetaspaceBeforeAfter(matches []string, event *GCEvent) error {
 	spaceType := matches[1]
 	usedBefore, _ := utils.ParseMemorySize(matches[2] + "K")
 	committedBefore, _ := utils.ParseMemorySize(matches[3] + "K")
@@ -616,12 +775,11 @@ func (wtp *WorkerTimingParser) CanParse(line string, context *ParseContext) bool
 }
 
 func (wtp *WorkerTimingParser) Parse(line string, context *ParseContext) error {
-	if len(context.Events) == 0 {
+	event := context.eventFor(line)
+	if event == nil {
 		return nil
 	}
 
-	event := context.Events[len(context.Events)-1]
-
 	// Parse worker usage: "Using 8 workers of 8 for evacuation"
 	if matches := workerUsageRegex.FindStringSubmatch(line); len(matches) >= 4 {
 		workersUsed, _ := strconv.Atoi(matches[1])
@@ -646,12 +804,22 @@ func (wtp *WorkerTimingParser) Parse(line string, context *ParseContext) error {
 
 func (wtp *WorkerTimingParser) parseEvacuationPhase(matches []string, event *GCEvent) error {
 	phaseName := matches[1]
+	minTime, _ := strconv.ParseFloat(matches[2], 64)
 	avgTime, _ := strconv.ParseFloat(matches[3], 64)
+	maxTime, _ := strconv.ParseFloat(matches[4], 64)
 	workers, _ := strconv.Atoi(matches[7])
 
 	event.WorkersUsed = workers
 	duration := time.Duration(avgTime * float64(time.Millisecond))
 
+	event.WorkerPhases = append(event.WorkerPhases, WorkerPhase{
+		Name:    phaseName,
+		Min:     time.Duration(minTime * float64(time.Millisecond)),
+		Avg:     duration,
+		Max:     time.Duration(maxTime * float64(time.Millisecond)),
+		Workers: workers,
+	})
+
 	switch phaseName {
 	case "Ext Root Scanning":
 		event.ExtRootScanTime = duration
@@ -688,6 +856,191 @@ func (wtp *WorkerTimingParser) parsePostEvacuationPhase(matches []string, event
 	return nil
 }
 
+// IHOPParser handles the initiating heap occupancy threshold G1 logs after each pause
+type IHOPParser struct{}
+
+func NewIHOPParser() *IHOPParser {
+	return &IHOPParser{}
+}
+
+func (ip *IHOPParser) CanParse(line string, context *ParseContext) bool {
+	return strings.Contains(line, "information (value update)") &&
+		(ihopBasicPattern.MatchString(line) || ihopAdaptivePattern.MatchString(line))
+}
+
+func (ip *IHOPParser) Parse(line string, context *ParseContext) error {
+	event := context.eventFor(line)
+	if event == nil {
+		return nil
+	}
+
+	if matches := ihopBasicPattern.FindStringSubmatch(line); len(matches) >= 6 {
+		threshold, _ := strconv.ParseInt(matches[1], 10, 64)
+		target, _ := strconv.ParseInt(matches[3], 10, 64)
+		event.IHOPThreshold = utils.MemorySize(threshold)
+		event.IHOPThresholdPercent, _ = strconv.ParseFloat(matches[2], 64)
+		event.IHOPTargetOccupancy = utils.MemorySize(target)
+		event.IHOPOldAllocationRate, _ = strconv.ParseFloat(matches[4], 64)
+		event.IHOPMarkingLength = ip.parseMillis(matches[5])
+		return nil
+	}
+
+	// The adaptive threshold replaces the basic one; its rate and length are the predictions it's set from
+	if matches := ihopAdaptivePattern.FindStringSubmatch(line); len(matches) >= 6 {
+		threshold, _ := strconv.ParseInt(matches[1], 10, 64)
+		event.IHOPThreshold = utils.MemorySize(threshold)
+		event.IHOPThresholdPercent, _ = strconv.ParseFloat(matches[2], 64)
+		event.IHOPOldAllocationRate, _ = strconv.ParseFloat(matches[3], 64)
+		event.IHOPMarkingLength = ip.parseMillis(matches[4])
+		event.IHOPAdaptive = true
+		event.IHOPPredictionActive = matches[5] == "true"
+	}
+	return nil
+}
+
+func (ip *IHOPParser) parseMillis(value string) time.Duration {
+	millis, _ := strconv.ParseFloat(value, 64)
+	return time.Duration(millis * float64(time.Millisecond))
+}
+
+// FullPhaseParser handles the phases of a Full GC's mark-compact, logged before its summary line
+type FullPhaseParser struct{}
+
+func NewFullPhaseParser() *FullPhaseParser {
+	return &FullPhaseParser{}
+}
+
+func (fpp *FullPhaseParser) CanParse(line string, context *ParseContext) bool {
+	return fullPhasePattern.MatchString(line)
+}
+
+func (fpp *FullPhaseParser) Parse(line string, context *ParseContext) error {
+	matches := fullPhasePattern.FindStringSubmatch(line)
+	if len(matches) < 4 {
+		return nil
+	}
+	event := context.eventFor(line)
+	if event == nil {
+		return nil
+	}
+
+	millis, _ := strconv.ParseFloat(matches[3], 64)
+	duration := time.Duration(millis * float64(time.Millisecond))
+
+	// Each collector names the same four steps its own way, and G1 renamed two of them in JDK 17
+	switch matches[2] {
+	case "Phase 1: Mark live objects", "Marking Phase":
+		event.FullPhases = append(event.FullPhases, FullPhase{Name: FullPhaseMark, Duration: duration})
+	case "Phase 2: Compute new object addresses", "Phase 2: Prepare for compaction", "Phase 2: Prepare compaction", "Summary Phase":
+		event.FullPhases = append(event.FullPhases, FullPhase{Name: FullPhasePrepare, Duration: duration})
+	case "Phase 3: Adjust pointers", "Adjust Roots", "Adjust Pointers":
+		event.FullPhases = append(event.FullPhases, FullPhase{Name: FullPhaseAdjust, Duration: duration})
+	case "Phase 4: Move objects", "Phase 4: Compact heap", "Compaction Phase":
+		event.FullPhases = append(event.FullPhases, FullPhase{Name: FullPhaseCompact, Duration: duration})
+	case "Phase 5: Reset Metadata", "Post Compact":
+		event.FullPhases = append(event.FullPhases, FullPhase{Name: FullPhasePostCompact, Duration: duration})
+	case "Class Unloading", "Phase 1: Class Unloading and Cleanup":
+		event.ClassUnloadingTime = duration
+	case "Reference Processing", "Phase 1: Reference Processing":
+		event.MarkRefProcessingTime = duration
+	}
+	return nil
+}
+
+// GCLockerParser handles the threads gc+jni=debug names entering, leaving and stalling on
+// JNI critical regions, and the allocations that gave up waiting for them
+type GCLockerParser struct{}
+
+func NewGCLockerParser() *GCLockerParser {
+	return &GCLockerParser{}
+}
+
+func (glp *GCLockerParser) CanParse(line string, context *ParseContext) bool {
+	return (strings.Contains(line, "critical") && gcLockerPattern.MatchString(line)) ||
+		(strings.Contains(line, "GCLocker") && gcLockerRetryPattern.MatchString(line))
+}
+
+func (glp *GCLockerParser) Parse(line string, context *ParseContext) error {
+	locker := &context.Analysis.GCLocker
+	locker.Logged = true
+
+	if matches := gcLockerRetryPattern.FindStringSubmatch(line); len(matches) >= 2 {
+		locker.RetryFailures++
+		locker.Stalled = countThread(locker.Stalled, matches[1])
+		return nil
+	}
+
+	matches := gcLockerPattern.FindStringSubmatch(line)
+	if len(matches) < 3 {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(matches[1], "Allocation failed"):
+		locker.Stalls++
+		locker.Stalled = countThread(locker.Stalled, matches[2])
+	case strings.HasPrefix(matches[1], "Thread blocked"):
+		locker.BlockedEntries++
+	default:
+		// The last thread out of a critical region runs the collection the others waited for
+		locker.Holders = countThread(locker.Holders, matches[2])
+	}
+	return nil
+}
+
+// countThread adds one to name's count, adding the thread when it's new
+func countThread(threads []ThreadCount, name string) []ThreadCount {
+	for i := range threads {
+		if threads[i].Name == name {
+			threads[i].Count++
+			return threads
+		}
+	}
+	return append(threads, ThreadCount{Name: name, Count: 1})
+}
+
+// HumongousObjectParser handles the humongous objects gc+humongous=debug lists at every
+// young collection. An object stays listed until it's reclaimed, so one is counted when its
+// address first shows up, or shows up again with another size.
+type HumongousObjectParser struct{}
+
+func NewHumongousObjectParser() *HumongousObjectParser {
+	return &HumongousObjectParser{}
+}
+
+func (hop *HumongousObjectParser) CanParse(line string, context *ParseContext) bool {
+	return strings.Contains(line, "umongous region") && humongousObjectPattern.MatchString(line)
+}
+
+func (hop *HumongousObjectParser) Parse(line string, context *ParseContext) error {
+	matches := humongousObjectPattern.FindStringSubmatch(line)
+	if len(matches) < 4 {
+		return nil
+	}
+	size, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		context.warn("invalid humongous object size", line)
+		return nil
+	}
+	address := matches[3]
+
+	if kind := matches[1]; kind == "Reclaimed " || kind == "Dead " {
+		delete(context.humongous, address)
+		return nil
+	}
+	if context.humongous[address] == utils.MemorySize(size) {
+		return nil
+	}
+	context.humongous[address] = utils.MemorySize(size)
+
+	histogram := &context.Analysis.HumongousSizes
+	if histogram.Sizes == nil {
+		histogram.Sizes = make(map[utils.MemorySize]int)
+	}
+	histogram.Source = HumongousSourceLog
+	histogram.Sizes[utils.MemorySize(size)]++
+	return nil
+}
+
 // CPUTimingParser handles GC CPU timing information
 type CPUTimingParser struct{}
 
@@ -767,10 +1120,10 @@ func (ctp *CPUTimingParser) calculateDerivedValues(event *GCEvent) {
 	// Calculate old memory approximations
 	if event.HeapBefore > 0 && event.HeapAfter > 0 {
 		if event.OldMemoryBefore == 0 {
-			event.OldMemoryBefore = max(event.HeapBefore-event.YoungMemoryBefore-event.HumongousMemoryBefore, 0)
+			event.OldMemoryBefore = max(event.HeapBefore-event.YoungMemoryBefore-event.HumongousMemoryBefore-event.ArchiveMemoryBefore, 0)
 		}
 		if event.OldMemoryAfter == 0 {
-			event.OldMemoryAfter = max(event.HeapAfter-event.YoungMemoryAfter-event.HumongousMemoryAfter, 0)
+			event.OldMemoryAfter = max(event.HeapAfter-event.YoungMemoryAfter-event.HumongousMemoryAfter-event.ArchiveMemoryAfter, 0)
 		}
 	}
 }
@@ -801,6 +1154,11 @@ func (gtp *GCTypeParser) Parse(typeString string) GCTypeInfo {
 	// Apply parsing rules
 	info = gtp.extractCauseAndSubtype(info, parentheticals)
 
+	// G1 logs a mixed collection as "Pause Young (Mixed)"; it's still a mixed collection
+	if info.Type == GCTypeYoung && info.Subtype == GCTypeMixed {
+		info.Type = GCTypeMixed
+	}
+
 	return info
 }
 
@@ -851,6 +1209,9 @@ func containsAny(s string, patterns []string) bool {
 
 type Parser struct {
 	parsers []LineParser
+
+	ctx      context.Context    // Cancelling it stops the parse early, keeping what was read
+	progress utils.ProgressFunc // nil unless SetProgress was called
 }
 
 func NewParser() *Parser {
@@ -860,14 +1221,33 @@ func NewParser() *Parser {
 		NewGCEventParser(),
 		NewRegionDetailsParser(),
 		NewWorkerTimingParser(),
+		NewIHOPParser(),
+		NewFullPhaseParser(),
+		NewGCLockerParser(),
+		NewHumongousObjectParser(),
 		NewCPUTimingParser(),
 	}
 
 	return &Parser{
 		parsers: parsers,
+		ctx:     context.Background(),
 	}
 }
 
+// progressLines is how many lines ParseFile reads between progress reports and cancellation checks
+const progressLines = 1000
+
+// SetContext makes ParseFile stop early once ctx is cancelled; the events
+// read so far are returned and the analysis is marked Interrupted
+func (p *Parser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// SetProgress reports bytes read and events found while ParseFile runs
+func (p *Parser) SetProgress(progress utils.ProgressFunc) {
+	p.progress = progress
+}
+
 // ParseFile parses a GC log file using the configured parsers
 func (p *Parser) ParseFile(filename string) ([]*GCEvent, *GCAnalysis, error) {
 	file, err := os.Open(filename)
@@ -876,21 +1256,42 @@ func (p *Parser) ParseFile(filename string) ([]*GCEvent, *GCAnalysis, error) {
 	}
 	defer file.Close()
 
+	var reader io.Reader = file
+	if strings.HasSuffix(filename, ".gz") {
+		// Rotated logs compressed by logrotate, e.g. gc.log.1.gz
+		gzReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid gzip stream: %v", err)
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
+
+	// The size of a compressed log says little about how much is left to read
+	var total int64
+	if info, err := file.Stat(); err == nil && !strings.HasSuffix(filename, ".gz") {
+		total = info.Size()
+	}
+
 	context := NewParseContext()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(reader)
 	lineNum := 0
+	var bytesRead int64
 
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 		context.LineNumber = lineNum
+		bytesRead += int64(len(line)) + 1
 
-		if err := p.parseLine(line, context); err != nil {
-			return nil, nil, ParseError{
-				Line:    line,
-				LineNum: lineNum,
-				Err:     err,
+		p.parseLine(line, context)
+
+		if lineNum%progressLines == 0 {
+			p.reportProgress(bytesRead, total, context)
+			if p.ctx.Err() != nil {
+				context.Analysis.Interrupted = true
+				break
 			}
 		}
 	}
@@ -898,21 +1299,48 @@ func (p *Parser) ParseFile(filename string) ([]*GCEvent, *GCAnalysis, error) {
 	if err := scanner.Err(); err != nil {
 		return nil, nil, fmt.Errorf("scanner error: %v", err)
 	}
+	context.Analysis.LinesRead = lineNum
+	context.Analysis.LoggingGaps = checkLogging(context)
+	p.reportProgress(bytesRead, total, context)
 
 	return context.Events, context.Analysis, nil
 }
 
-func (p *Parser) parseLine(line string, context *ParseContext) error {
+func (p *Parser) reportProgress(bytesRead, total int64, context *ParseContext) {
+	if p.progress != nil {
+		p.progress(utils.Progress{BytesRead: bytesRead, TotalBytes: total, Items: len(context.Events)})
+	}
+}
+
+// parseLine runs the line through every parser; what none of them can use becomes a parse warning
+func (p *Parser) parseLine(line string, context *ParseContext) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
 	// Extract timestamp first - every line potentially has one
 	extractTimestamp(line, context)
+	recordTags(line, context)
 
 	// Run all other parsers
+	parsed := false
 	for _, parser := range p.parsers {
 		if parser.CanParse(line, context) {
+			parsed = true
 			if err := parser.Parse(line, context); err != nil {
-				return err
+				context.warn(err.Error(), line)
+				return
 			}
 		}
 	}
-	return nil
+
+	// Most unified logging lines carry nothing the analysis uses, so only lines
+	// that aren't unified logging at all, or pauses no parser took, are reported
+	switch {
+	case parsed:
+	case !decorationPattern.MatchString(line):
+		context.warn("not a unified logging line (-Xlog:gc*)", line)
+	case pauseLinePattern.MatchString(line):
+		context.warn("unrecognized pause", line)
+	}
 }
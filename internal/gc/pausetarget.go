@@ -0,0 +1,73 @@
+package gc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+ * One miss rate over every pause hides which collections miss the target,
+ * and the fix depends on it: Young pauses shorten with a smaller young
+ * generation, Mixed ones with fewer old regions per collection, Remark with
+ * faster reference processing, and Full GCs with no pause target setting at
+ * all. Concurrent cycles don't stop the application, so they're left out.
+ */
+
+func calculatePauseTargets(events []*GCEvent, target time.Duration) PauseTargetAnalysis {
+	var targets PauseTargetAnalysis
+	byType := make(map[string]*PauseCompliance)
+	byCause := make(map[string]*PauseCompliance)
+
+	count := func(groups map[string]*PauseCompliance, name string, event *GCEvent) {
+		group, exists := groups[name]
+		if !exists {
+			group = &PauseCompliance{Name: name}
+			groups[name] = group
+		}
+		group.Pauses++
+		group.Max = max(group.Max, event.Duration)
+		if event.Duration > target {
+			group.Misses++
+		}
+	}
+
+	for _, event := range events {
+		gcType := CategorizeGCType(event.Type)
+		if gcType == "Concurrent Mark" {
+			continue
+		}
+		count(byType, gcType, event)
+		if event.Cause != "" {
+			count(byCause, event.Cause, event)
+		}
+		if event.Duration > target {
+			targets.Misses++
+		}
+	}
+
+	targets.ByType = sortedCompliance(byType)
+	targets.ByCause = sortedCompliance(byCause)
+
+	if targets.Misses >= MinTargetMisses {
+		worst := targets.ByType[0]
+		share := float64(worst.Misses) / float64(targets.Misses)
+		if share >= CulpritMissShare && len(targets.ByType) > 1 {
+			targets.Culprit, targets.CulpritShare = worst.Name, share
+		}
+	}
+	return targets
+}
+
+func sortedCompliance(groups map[string]*PauseCompliance) []PauseCompliance {
+	sorted := make([]PauseCompliance, 0, len(groups))
+	for _, group := range groups {
+		group.MissRate = float64(group.Misses) / float64(group.Pauses)
+		sorted = append(sorted, *group)
+	}
+	slices.SortFunc(sorted, func(a, b PauseCompliance) int {
+		return cmp.Or(cmp.Compare(b.Misses, a.Misses), cmp.Compare(b.Pauses, a.Pauses), strings.Compare(a.Name, b.Name))
+	})
+	return sorted
+}
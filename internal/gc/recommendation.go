@@ -2,6 +2,11 @@ package gc
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/flags"
+	"github.com/mabhi256/jdiag/utils"
 )
 
 func GetRecommendations(analysis *GCAnalysis) *GCIssues {
@@ -36,6 +41,14 @@ func GetRecommendations(analysis *GCAnalysis) *GCIssues {
 		issues = append(issues, getMarkAbortRec(analysis))
 	}
 
+	if analysis.HasCriticalDeathSpiral {
+		issues = append(issues, getDeathSpiralRec(analysis))
+	}
+
+	if analysis.HasCriticalGCLocker {
+		issues = append(issues, getGCLockerRec(analysis))
+	}
+
 	// Full GC is always critical
 	if analysis.FullGCCount > 1 {
 		issues = append(issues, getFullGCRec(analysis))
@@ -78,6 +91,22 @@ func GetRecommendations(analysis *GCAnalysis) *GCIssues {
 		issues = append(issues, getCollectionEfficiencyRec(analysis))
 	}
 
+	if analysis.HasWarningWorkerBalance {
+		issues = append(issues, getWorkerBalanceRec(analysis))
+	}
+
+	if analysis.HasWarningBackToBack {
+		issues = append(issues, getBackToBackRec(analysis))
+	}
+
+	if analysis.HasWarningLateMarking {
+		issues = append(issues, getLateMarkingRec(analysis))
+	}
+
+	if analysis.HasWarningGCLocker {
+		issues = append(issues, getGCLockerRec(analysis))
+	}
+
 	// ===== INFO ISSUES =====
 	if analysis.HasInfoAllocationPattern {
 		issues = append(issues, getAllocationPatternRec(analysis))
@@ -87,7 +116,17 @@ func GetRecommendations(analysis *GCAnalysis) *GCIssues {
 		issues = append(issues, getPhaseOptimizationRec(analysis))
 	}
 
-	return groupRecsBySeverity(issues)
+	if analysis.HasInfoEarlyMarking {
+		issues = append(issues, getEarlyMarkingRec(analysis))
+	}
+
+	if analysis.HasInfoHumongousSizing {
+		issues = append(issues, getHumongousSizingRec(analysis))
+	}
+
+	grouped := groupRecsBySeverity(issues)
+	grouped.adaptToJDK(flags.ParseJDKVersion(analysis.JVMVersion))
+	return grouped
 }
 
 // ===== CRITICAL RECOMMENDATION GENERATORS =====
@@ -97,11 +136,11 @@ func getCriticalMemoryLeakRec(analysis *GCAnalysis) PerformanceIssue {
 	var recommendations []string
 
 	if analysis.FullGCCount >= 3 {
-		description = fmt.Sprintf("SEVERE MEMORY LEAK: %d Full GCs + %.2f MB/hour growth",
-			analysis.FullGCCount, analysis.MemoryTrend.GrowthRateMBPerHour)
+		description = fmt.Sprintf("SEVERE MEMORY LEAK: %d Full GCs + %s/hour growth",
+			analysis.FullGCCount, utils.Precision(2).MB(analysis.MemoryTrend.GrowthRateMBPerHour))
 	} else {
-		description = fmt.Sprintf("CRITICAL MEMORY LEAK: %.2f MB/hour growth rate",
-			analysis.MemoryTrend.GrowthRateMBPerHour)
+		description = fmt.Sprintf("CRITICAL MEMORY LEAK: %s/hour growth rate",
+			utils.Precision(2).MB(analysis.MemoryTrend.GrowthRateMBPerHour))
 	}
 
 	recommendations = []string{
@@ -118,8 +157,8 @@ func getCriticalMemoryLeakRec(analysis *GCAnalysis) PerformanceIssue {
 
 	if analysis.HumongousStats.IsLeak {
 		recommendations = append(recommendations,
-			fmt.Sprintf("Also investigate humongous objects: %d regions (%.1f%% of heap)",
-				analysis.HumongousStats.MaxRegions, analysis.HumongousStats.HeapPercentage))
+			fmt.Sprintf("Also investigate humongous objects: %d regions (%s of heap)",
+				analysis.HumongousStats.MaxRegions, utils.FormatPercent(analysis.HumongousStats.HeapPercentage)))
 	}
 
 	return PerformanceIssue{
@@ -134,8 +173,8 @@ func getCriticalEvacFailureRec(analysis *GCAnalysis) PerformanceIssue {
 	failureRate := analysis.EvacuationFailureRate * 100
 
 	recommendations := []string{
-		fmt.Sprintf("EVACUATION FAILURES: %d events (%.1f%% failure rate)",
-			analysis.EvacuationFailureCount, failureRate),
+		fmt.Sprintf("EVACUATION FAILURES: %d events (%s failure rate)",
+			analysis.EvacuationFailureCount, utils.FormatPercent(failureRate)),
 		"Evacuation failure means G1GC couldn't move objects to other regions",
 		"This causes severe performance degradation and triggers Full GC",
 		"IMMEDIATE ACTION: Increase heap size by 100-200%: -Xmx<size * 2>",
@@ -148,26 +187,26 @@ func getCriticalEvacFailureRec(analysis *GCAnalysis) PerformanceIssue {
 
 	if analysis.AvgHeapUtil > 0.9 {
 		recommendations = append(recommendations,
-			fmt.Sprintf("Critical: Heap utilization %.1f%% - increase heap immediately",
-				analysis.AvgHeapUtil*100))
+			fmt.Sprintf("Critical: Heap utilization %s - increase heap immediately",
+				utils.FormatPercent(analysis.AvgHeapUtil*100)))
 	}
 
 	return PerformanceIssue{
 		Type:           "Critical Evacuation Failures",
 		Severity:       "critical",
-		Description:    fmt.Sprintf("%d evacuation failures (%.1f%% rate)", analysis.EvacuationFailureCount, failureRate),
+		Description:    fmt.Sprintf("%d evacuation failures (%s rate)", analysis.EvacuationFailureCount, utils.FormatPercent(failureRate)),
 		Recommendation: recommendations,
 	}
 }
 
 func getCriticalThroughputRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("Application throughput %.1f%% is critically low (target: >%.0f%%)",
-			analysis.Throughput, ThroughputGood),
+		fmt.Sprintf("Application throughput %s is critically low (target: >%s)",
+			utils.FormatPercent(analysis.Throughput), utils.Precision(0).Percent(ThroughputGood)),
 		"Primary action: Increase heap size to reduce GC frequency",
-		fmt.Sprintf("Recommended heap size: %.0fGB (for allocation rate: %.1f MB/s)",
-			calculateRecommendedHeapSize(analysis.AllocationRate), analysis.AllocationRate),
-		"Consider G1GC tuning: -XX:G1HeapOccupancyPercent=35",
+		fmt.Sprintf("Recommended heap size: %sGB (for allocation rate: %s/s)",
+			utils.Precision(0).Float(calculateRecommendedHeapSize(analysis.AllocationRate)), utils.FormatMB(analysis.AllocationRate)),
+		"Consider G1GC tuning: -XX:InitiatingHeapOccupancyPercent=35",
 		"Monitor GC logs for evacuation failures and long pauses",
 		"Profile application for allocation hotspots",
 	}
@@ -180,7 +219,7 @@ func getCriticalThroughputRec(analysis *GCAnalysis) PerformanceIssue {
 	return PerformanceIssue{
 		Type:           "Critical Throughput Issues",
 		Severity:       "critical",
-		Description:    fmt.Sprintf("Throughput %.1f%% critically low", analysis.Throughput),
+		Description:    fmt.Sprintf("Throughput %s critically low", utils.FormatPercent(analysis.Throughput)),
 		Recommendation: recommendations,
 	}
 }
@@ -190,13 +229,21 @@ func getCriticalPauseTimeRec(analysis *GCAnalysis) PerformanceIssue {
 		fmt.Sprintf("Maximum pause %v exceeds critical threshold (%v)",
 			analysis.MaxPause, PauseCritical),
 		"Pause times are unacceptable for most applications",
-		fmt.Sprintf("Set pause target: -XX:MaxGCPauseMillis=%d",
-			int(PauseAcceptable.Milliseconds())),
-		"Reduce young generation: -XX:G1MaxNewSizePercent=20",
+	}
+	if advice := pauseTargetAdvice(analysis); len(advice) > 0 {
+		recommendations = append(recommendations, advice...)
+	} else {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Set pause target: -XX:MaxGCPauseMillis=%d",
+				int(PauseAcceptable.Milliseconds())),
+			"Reduce young generation: -XX:G1MaxNewSizePercent=20",
+		)
+	}
+	recommendations = append(recommendations,
 		"Increase heap size to reduce memory pressure",
 		"Consider low-latency collectors: ZGC (-XX:+UseZGC) or Shenandoah",
 		"Profile allocation patterns to reduce GC pressure",
-	}
+	)
 
 	if analysis.EvacuationFailureCount > 0 {
 		recommendations = append(recommendations,
@@ -213,28 +260,35 @@ func getCriticalPauseTimeRec(analysis *GCAnalysis) PerformanceIssue {
 
 func getCriticalPromotionRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("PREMATURE PROMOTION: Old gen growing %.1fx per young GC",
-			analysis.AvgOldGrowthRatio),
-		fmt.Sprintf("%.1f regions promoted per young collection", analysis.AvgPromotionRate),
+		fmt.Sprintf("PREMATURE PROMOTION: Old gen growing %sx per young GC",
+			utils.FormatFloat(analysis.AvgOldGrowthRatio)),
+		fmt.Sprintf("%s regions promoted per young collection", utils.FormatFloat(analysis.AvgPromotionRate)),
 		"Objects not dying in young generation as expected",
 		"Increase young generation: -XX:G1NewSizePercent=40 -XX:G1MaxNewSizePercent=60",
-		"Increase survivor space: -XX:SurvivorRatio=6",
+	}
+
+	if survivor := survivorAdvice(analysis); survivor != nil {
+		recommendations = append(recommendations, survivor...)
+	} else {
+		recommendations = append(recommendations, "Increase survivor space: -XX:SurvivorRatio=6")
+	}
+	recommendations = append(recommendations,
 		"Keep objects young longer: -XX:MaxTenuringThreshold=15",
-		"Start concurrent marking earlier: -XX:G1HeapOccupancyPercent=25",
+		"Start concurrent marking earlier: -XX:InitiatingHeapOccupancyPercent=25",
 		"Profile object lifecycle: async-profiler -e alloc -d 60 <pid>",
 		"Check for short-lived large objects or collections",
-	}
+	)
 
-	if analysis.SurvivorOverflowRate > SurvivorOverflowCritical {
+	if analysis.SurvivorOverflowRate > SurvivorOverflowCritical && analysis.Survivor.Samples == 0 {
 		recommendations = append(recommendations,
-			fmt.Sprintf("CRITICAL: %.1f%% survivor overflow - increase survivor space immediately",
-				analysis.SurvivorOverflowRate*100))
+			fmt.Sprintf("CRITICAL: %s survivor overflow - increase survivor space immediately",
+				utils.FormatPercent(analysis.SurvivorOverflowRate*100)))
 	}
 
 	return PerformanceIssue{
 		Type:           "Critical Premature Promotion",
 		Severity:       "critical",
-		Description:    fmt.Sprintf("Old gen growing %.1fx per young GC", analysis.AvgOldGrowthRatio),
+		Description:    fmt.Sprintf("Old gen growing %sx per young GC", utils.FormatFloat(analysis.AvgOldGrowthRatio)),
 		Recommendation: recommendations,
 	}
 }
@@ -243,8 +297,8 @@ func getCriticalHumongousRec(analysis *GCAnalysis) PerformanceIssue {
 	stats := analysis.HumongousStats
 
 	recommendations := []string{
-		fmt.Sprintf("HUMONGOUS OBJECT LEAK: %d regions (%.1f%% of heap)",
-			stats.MaxRegions, stats.HeapPercentage),
+		fmt.Sprintf("HUMONGOUS OBJECT LEAK: %d regions (%s of heap)",
+			stats.MaxRegions, utils.FormatPercent(stats.HeapPercentage)),
 		"Humongous objects are not being garbage collected",
 		"This indicates a MEMORY LEAK in large object allocation",
 		"Take heap dump: jcmd <pid> VM.dump_heap humongous-leak.hprof",
@@ -265,11 +319,12 @@ func getCriticalHumongousRec(analysis *GCAnalysis) PerformanceIssue {
 			fmt.Sprintf("Growing leak pattern: %d growth vs %d cleanup events",
 				stats.GrowingCount, stats.DecreasingCount))
 	}
+	recommendations = append(recommendations, humongousSizeAdvice(analysis)...)
 
 	return PerformanceIssue{
 		Type:           "Humongous Object Leak",
 		Severity:       "critical",
-		Description:    fmt.Sprintf("Humongous objects consuming %.1f%% of heap", stats.HeapPercentage),
+		Description:    fmt.Sprintf("Humongous objects consuming %s of heap", utils.FormatPercent(stats.HeapPercentage)),
 		Recommendation: recommendations,
 	}
 }
@@ -280,10 +335,10 @@ func getMarkAbortRec(analysis *GCAnalysis) PerformanceIssue {
 			analysis.ConcurrentMarkAbortCount),
 		"Concurrent marking cannot keep up with allocation rate - forces Full GC",
 		"IMMEDIATE ACTION: Double heap size: -Xmx<current * 2>",
-		"Start marking earlier: -XX:G1HeapOccupancyPercent=15 (down from 45%)",
+		"Start marking earlier: -XX:InitiatingHeapOccupancyPercent=15 (down from 45%)",
 		"Increase concurrent threads: -XX:ConcGCThreads=8",
-		fmt.Sprintf("Profile allocation hotspots: allocation rate %.1f MB/s needs optimization",
-			analysis.AllocationRate),
+		fmt.Sprintf("Profile allocation hotspots: allocation rate %s/s needs optimization",
+			utils.FormatMB(analysis.AllocationRate)),
 		"Take heap dump to analyze object lifecycle patterns",
 		"Increase young generation: -XX:G1NewSizePercent=40 -XX:G1MaxNewSizePercent=60",
 		"Consider ZGC for large heaps: -XX:+UseZGC (avoids concurrent marking)",
@@ -302,6 +357,97 @@ func getMarkAbortRec(analysis *GCAnalysis) PerformanceIssue {
 	}
 }
 
+func getDeathSpiralRec(analysis *GCAnalysis) PerformanceIssue {
+	spiral := analysis.Frequency.Spiral
+	recommendations := []string{
+		fmt.Sprintf("GC DEATH SPIRAL: time between collections shrank from %s to %s over %d collections from %s",
+			utils.FormatDuration(spiral.FirstGap), utils.FormatDuration(spiral.LastGap), spiral.Collections,
+			spiral.Start.Format("15:04:05")),
+		"Each collection frees less than the application allocates before the next one",
+		"Live data is approaching the heap size - this usually ends in OutOfMemoryError",
+		"IMMEDIATE ACTION: Increase heap size: -Xmx<current * 1.5-2>",
+		"Take a heap dump near the end of the spiral to find what keeps growing",
+		"Fail fast instead of thrashing: -XX:+ExitOnOutOfMemoryError -XX:+HeapDumpOnOutOfMemoryError",
+		"Bound GC overhead with -XX:GCTimeRatio or container health checks on GC time",
+	}
+
+	if spiral.FullGCs > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Note: %d of the spiral's collections were Full GCs", spiral.FullGCs))
+	}
+
+	return PerformanceIssue{
+		Type:     "GC Death Spiral",
+		Severity: "critical",
+		Description: fmt.Sprintf("Time between collections fell from %s to %s over %d collections",
+			utils.FormatDuration(spiral.FirstGap), utils.FormatDuration(spiral.LastGap), spiral.Collections),
+		Recommendation: recommendations,
+	}
+}
+
+/*
+ * getGCLockerRec explains the collections JNI critical regions held back. It
+ * is critical once an allocation gave up waiting, since that threw an
+ * OutOfMemoryError with the heap far from full, and a warning otherwise.
+ */
+func getGCLockerRec(analysis *GCAnalysis) PerformanceIssue {
+	locker := analysis.GCLocker
+	severity := "warning"
+	description := fmt.Sprintf("%d collections (%s) waited for JNI critical regions to exit",
+		locker.Collections, utils.FormatPercent(locker.Share*100))
+	if locker.RetryFailures > 0 {
+		severity = "critical"
+		description = fmt.Sprintf("%d allocations gave up waiting for JNI critical regions and threw OutOfMemoryError", locker.RetryFailures)
+	}
+
+	recommendations := []string{
+		"Native code inside GetPrimitiveArrayCritical or GetStringCritical blocks every collection until it exits; allocating threads stall meanwhile",
+	}
+	if locker.Stalls > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Allocation stalls: %d for %d GCLocker collections, in %s",
+			locker.Stalls, locker.Collections, threadList(locker.Stalled)))
+	}
+	if locker.BackToBack > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d GCLocker collections ran under %s after the one before, collecting little beyond what the stall needed",
+			locker.BackToBack, utils.FormatDuration(BackToBackGap)))
+	}
+	if len(locker.Holders) > 0 {
+		recommendations = append(recommendations,
+			"Critical regions the collections waited on were left by "+threadList(locker.Holders),
+			"Their native code holds the array across slow work (I/O, locks, long loops): copy it with Get<Type>ArrayRegion instead, or release it sooner")
+	} else {
+		recommendations = append(recommendations, "Name the threads holding and stalling on critical regions with -Xlog:gc+jni=debug")
+	}
+	recommendations = append(recommendations,
+		"Usual holders: java.util.zip (Deflater, Inflater, CRC32), lz4-java, snappy-java, zstd-jni, netty-tcnative and Conscrypt TLS, RocksDB",
+		"To see which of them runs, save a watch session ('jdiag watch --summary session.json') and run 'jdiag report' with it and this log")
+	if locker.RetryFailures > 0 {
+		recommendations = append(recommendations, "Let allocations wait longer before giving up: -XX:GCLockerRetryAllocationCount=100")
+	}
+	if version := flags.ParseJDKVersion(analysis.JVMVersion); (analysis.Collector == "" || analysis.Collector == "G1") && version < 22 {
+		recommendations = append(recommendations, "JDK 22+ G1 pins the regions critical arrays sit in instead of blocking collections (JEP 423)")
+	}
+
+	return PerformanceIssue{
+		Type:           "GCLocker Stalls",
+		Severity:       severity,
+		Description:    description,
+		Recommendation: recommendations,
+	}
+}
+
+// threadList names the first threads with their counts, e.g. "zip-writer (12), io-1 (3)"
+func threadList(threads []ThreadCount) string {
+	var names []string
+	for _, thread := range threads[:min(3, len(threads))] {
+		names = append(names, fmt.Sprintf("%s (%d)", thread.Name, thread.Count))
+	}
+	if more := len(threads) - 3; more > 0 {
+		names = append(names, fmt.Sprintf("%d more", more))
+	}
+	return strings.Join(names, ", ")
+}
+
 func getFullGCRec(analysis *GCAnalysis) PerformanceIssue {
 	var severity string
 	var recommendations []string
@@ -311,7 +457,7 @@ func getFullGCRec(analysis *GCAnalysis) PerformanceIssue {
 		recommendations = []string{
 			"Single Full GC detected - monitor for recurrence",
 			"Check for heap sizing: increase -Xmx by 50-100% if possible",
-			"Enable detailed logging: -XX:+PrintGCDetails -XX:+PrintGCTimeStamps",
+			"Enable detailed logging: " + RecommendedXlog,
 			"Monitor for one-time events (class loading, permgen, etc.)",
 			"Take heap dump if Full GC recurs",
 		}
@@ -326,6 +472,7 @@ func getFullGCRec(analysis *GCAnalysis) PerformanceIssue {
 			"Profile allocation patterns and object lifecycle",
 		}
 	}
+	recommendations = append(recommendations, fullGCPhaseAdvice(analysis)...)
 
 	return PerformanceIssue{
 		Type:           "Full GC Events",
@@ -339,11 +486,11 @@ func getFullGCRec(analysis *GCAnalysis) PerformanceIssue {
 
 func getWarningMemoryLeakRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("Suspicious memory growth: %.2f MB/hour", analysis.MemoryTrend.GrowthRateMBPerHour),
-		fmt.Sprintf("Trend confidence: %.1f%% over %v",
-			analysis.MemoryTrend.TrendConfidence*100, analysis.MemoryTrend.SamplePeriod),
+		fmt.Sprintf("Suspicious memory growth: %s/hour", utils.Precision(2).MB(analysis.MemoryTrend.GrowthRateMBPerHour)),
+		fmt.Sprintf("Trend confidence: %s over %v",
+			utils.FormatPercent(analysis.MemoryTrend.TrendConfidence*100), analysis.MemoryTrend.SamplePeriod),
 		"Take baseline heap dump for comparison",
-		"Enable memory tracking: -XX:+PrintGCDetails -XX:+PrintGCApplicationStoppedTime",
+		"Enable memory tracking: -Xlog:gc*,safepoint:file=gc.log:time,uptime",
 		"Profile with async-profiler for allocation hotspots",
 		"Review recent code changes for memory usage patterns",
 		"Monitor heap utilization trends",
@@ -352,7 +499,7 @@ func getWarningMemoryLeakRec(analysis *GCAnalysis) PerformanceIssue {
 	return PerformanceIssue{
 		Type:           "Suspected Memory Leak",
 		Severity:       "warning",
-		Description:    fmt.Sprintf("Memory growing %.2f MB/hour", analysis.MemoryTrend.GrowthRateMBPerHour),
+		Description:    fmt.Sprintf("Memory growing %s/hour", utils.Precision(2).MB(analysis.MemoryTrend.GrowthRateMBPerHour)),
 		Recommendation: recommendations,
 	}
 }
@@ -361,8 +508,8 @@ func getWarningEvacFailureRec(analysis *GCAnalysis) PerformanceIssue {
 	failureRate := analysis.EvacuationFailureRate * 100
 
 	recommendations := []string{
-		fmt.Sprintf("Evacuation failures detected: %d events (%.1f%% rate)",
-			analysis.EvacuationFailureCount, failureRate),
+		fmt.Sprintf("Evacuation failures detected: %d events (%s rate)",
+			analysis.EvacuationFailureCount, utils.FormatPercent(failureRate)),
 		"Monitor heap utilization - maintain <80% to prevent failures",
 		"Consider increasing heap size by 50%",
 		"Increase evacuation reserve: -XX:G1ReservePercent=15",
@@ -380,8 +527,8 @@ func getWarningEvacFailureRec(analysis *GCAnalysis) PerformanceIssue {
 
 func getWarningThroughputRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("Throughput %.1f%% below optimal (target: >%.0f%%)",
-			analysis.Throughput, ThroughputGood),
+		fmt.Sprintf("Throughput %s below optimal (target: >%s)",
+			utils.FormatPercent(analysis.Throughput), utils.Precision(0).Percent(ThroughputGood)),
 		"Fine-tune pause target: reduce -XX:MaxGCPauseMillis if currently >200ms",
 		"Optimize young generation: -XX:G1MaxNewSizePercent=40",
 		"Consider heap size increase for better performance",
@@ -391,7 +538,7 @@ func getWarningThroughputRec(analysis *GCAnalysis) PerformanceIssue {
 	return PerformanceIssue{
 		Type:           "Suboptimal Throughput",
 		Severity:       "warning",
-		Description:    fmt.Sprintf("Throughput %.1f%% has room for improvement", analysis.Throughput),
+		Description:    fmt.Sprintf("Throughput %s has room for improvement", utils.FormatPercent(analysis.Throughput)),
 		Recommendation: recommendations,
 	}
 }
@@ -399,12 +546,18 @@ func getWarningThroughputRec(analysis *GCAnalysis) PerformanceIssue {
 func getWarningPauseTimeRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
 		fmt.Sprintf("P99 pause %v exceeds target %v", analysis.P99Pause, analysis.EstimatedPauseTarget),
-		fmt.Sprintf("%.1f%% of collections miss pause target", analysis.PauseTargetMissRate*100),
-		"Pause time consistency needs improvement",
-		fmt.Sprintf("Adjust pause target: -XX:MaxGCPauseMillis=%d",
-			int(float64(analysis.EstimatedPauseTarget.Milliseconds())*1.2)),
-		"Optimize concurrent marking: -XX:G1HeapOccupancyPercent=30",
-		"Consider mixed collection tuning: -XX:G1MixedGCCountTarget=12",
+		fmt.Sprintf("%s of collections miss pause target", utils.FormatPercent(analysis.PauseTargetMissRate*100)),
+	}
+	if advice := pauseTargetAdvice(analysis); len(advice) > 0 {
+		recommendations = append(recommendations, advice...)
+	} else {
+		recommendations = append(recommendations,
+			"Pause time consistency needs improvement",
+			fmt.Sprintf("Adjust pause target: -XX:MaxGCPauseMillis=%d",
+				int(float64(analysis.EstimatedPauseTarget.Milliseconds())*1.2)),
+			"Optimize concurrent marking: -XX:InitiatingHeapOccupancyPercent=30",
+			"Consider mixed collection tuning: -XX:G1MixedGCCountTarget=12",
+		)
 	}
 
 	return PerformanceIssue{
@@ -417,20 +570,24 @@ func getWarningPauseTimeRec(analysis *GCAnalysis) PerformanceIssue {
 
 func getWarningPromotionRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("High promotion rate: %.1f regions per young GC", analysis.AvgPromotionRate),
-		fmt.Sprintf("Old generation growing %.1fx on average", analysis.AvgOldGrowthRatio),
+		fmt.Sprintf("High promotion rate: %s regions per young GC", utils.FormatFloat(analysis.AvgPromotionRate)),
+		fmt.Sprintf("Old generation growing %sx on average", utils.FormatFloat(analysis.AvgOldGrowthRatio)),
 		"Objects not dying in young generation as expected",
-		fmt.Sprintf("Young generation efficiency: %.1f%% (target: >80%%)",
-			analysis.YoungCollectionEfficiency*100),
+		fmt.Sprintf("Young generation efficiency: %s (target: >80%%)",
+			utils.FormatPercent(analysis.YoungCollectionEfficiency*100)),
 		"Increase young generation size to give objects more time to die",
 		"Monitor allocation patterns for optimization opportunities",
-		"Consider survivor space tuning if efficiency is low",
+	}
+	if survivor := survivorAdvice(analysis); survivor != nil {
+		recommendations = append(recommendations, survivor...)
+	} else {
+		recommendations = append(recommendations, "Consider survivor space tuning if efficiency is low")
 	}
 
 	return PerformanceIssue{
 		Type:           "Premature Promotion Warning",
 		Severity:       "warning",
-		Description:    fmt.Sprintf("High promotion: %.1f regions per young GC", analysis.AvgPromotionRate),
+		Description:    fmt.Sprintf("High promotion: %s regions per young GC", utils.FormatFloat(analysis.AvgPromotionRate)),
 		Recommendation: recommendations,
 	}
 }
@@ -439,31 +596,37 @@ func getWarningHumongousRec(analysis *GCAnalysis) PerformanceIssue {
 	stats := analysis.HumongousStats
 
 	recommendations := []string{
-		fmt.Sprintf("Significant humongous object usage: %d regions (%.1f%% of heap)",
-			stats.MaxRegions, stats.HeapPercentage),
+		fmt.Sprintf("Significant humongous object usage: %d regions (%s of heap)",
+			stats.MaxRegions, utils.FormatPercent(stats.HeapPercentage)),
 		"Large objects consuming significant heap space",
 		"Monitor for memory leak patterns",
-		"Consider object size optimization or heap size increase",
-		"Review large object allocation patterns",
+	}
+	if sizes := humongousSizeAdvice(analysis); sizes != nil {
+		recommendations = append(recommendations, sizes...)
+	} else {
+		recommendations = append(recommendations,
+			"Consider object size optimization or heap size increase",
+			"Review large object allocation patterns",
+			"See the sizes behind them with -Xlog:gc+humongous=debug")
 	}
 
 	return PerformanceIssue{
 		Type:           "High Humongous Object Usage",
 		Severity:       "warning",
-		Description:    fmt.Sprintf("Humongous objects: %.1f%% of heap", stats.HeapPercentage),
+		Description:    fmt.Sprintf("Humongous objects: %s of heap", utils.FormatPercent(stats.HeapPercentage)),
 		Recommendation: recommendations,
 	}
 }
 
 func getConcurrentMarkingRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("Concurrent marking falling behind allocation rate (%.1f MB/s)",
-			analysis.AllocationRate),
-		"Start marking earlier: -XX:G1HeapOccupancyPercent=25",
+		fmt.Sprintf("Concurrent marking falling behind allocation rate (%s/s)",
+			utils.FormatMB(analysis.AllocationRate)),
+		"Start marking earlier: -XX:InitiatingHeapOccupancyPercent=25",
 		fmt.Sprintf("Increase concurrent threads: -XX:ConcGCThreads=%d",
 			calculateOptimalConcThreads(analysis.AllocationRate)),
 		"Increase heap size to provide more marking time",
-		"Enable marking diagnostics: -XX:+TraceConcurrentGCollection",
+		"Enable marking diagnostics: -Xlog:gc+marking=debug",
 	}
 
 	return PerformanceIssue{
@@ -481,8 +644,8 @@ func getAllocationRateRec(analysis *GCAnalysis) PerformanceIssue {
 	if analysis.AllocationRate > AllocRateCritical {
 		severity = "critical"
 		recommendations = []string{
-			fmt.Sprintf("Very high allocation rate: %.1f MB/s requires specialized tuning",
-				analysis.AllocationRate),
+			fmt.Sprintf("Very high allocation rate: %s/s requires specialized tuning",
+				utils.FormatMB(analysis.AllocationRate)),
 			"Use large heap regions: -XX:G1HeapRegionSize=32m",
 			"Increase young generation: -XX:G1NewSizePercent=30 -XX:G1MaxNewSizePercent=70",
 			"Profile allocation hotspots with async-profiler",
@@ -491,7 +654,7 @@ func getAllocationRateRec(analysis *GCAnalysis) PerformanceIssue {
 	} else {
 		severity = "warning"
 		recommendations = []string{
-			fmt.Sprintf("High allocation rate: %.1f MB/s needs monitoring", analysis.AllocationRate),
+			fmt.Sprintf("High allocation rate: %s/s needs monitoring", utils.FormatMB(analysis.AllocationRate)),
 			getRegionSizeRecommendation(analysis.AllocationRate),
 			"Optimize young generation sizing for allocation pattern",
 			"Review object lifecycle and temporary object creation",
@@ -507,7 +670,7 @@ func getAllocationRateRec(analysis *GCAnalysis) PerformanceIssue {
 	return PerformanceIssue{
 		Type:           "High Allocation Rate",
 		Severity:       severity,
-		Description:    fmt.Sprintf("Allocation rate %.1f MB/s", analysis.AllocationRate),
+		Description:    fmt.Sprintf("Allocation rate %s/s", utils.FormatMB(analysis.AllocationRate)),
 		Recommendation: recommendations,
 	}
 }
@@ -516,7 +679,7 @@ func getCollectionEfficiencyRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
 		fmt.Sprintf("No mixed collections in %d young collections", analysis.YoungGCCount),
 		"G1GC is not performing mixed collections - old generation not being cleaned",
-		"Lower marking threshold: -XX:G1HeapOccupancyPercent=35",
+		"Lower marking threshold: -XX:InitiatingHeapOccupancyPercent=35",
 		"Adjust mixed collection targeting: -XX:G1MixedGCLiveThresholdPercent=75",
 		"Verify concurrent marking completes successfully",
 	}
@@ -529,11 +692,366 @@ func getCollectionEfficiencyRec(analysis *GCAnalysis) PerformanceIssue {
 	}
 }
 
+func getBackToBackRec(analysis *GCAnalysis) PerformanceIssue {
+	frequency := analysis.Frequency
+	recommendations := []string{
+		fmt.Sprintf("%d collections started within %s of the previous one ending (shortest gap %s)",
+			frequency.BackToBackCount, utils.FormatDuration(BackToBackGap), utils.FormatDuration(frequency.MinGap)),
+		fmt.Sprintf("Up to %d back-to-back collections in a row", frequency.LongestBackToBackRun),
+		"Young generation fills too fast: -XX:G1NewSizePercent=30 or a larger -Xmx",
+		"Reduce allocation in hot paths: profile with async-profiler -e alloc",
+	}
+
+	if analysis.AllocationRate > AllocRateHigh {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Allocation rate %s/s explains the frequency", utils.FormatMB(analysis.AllocationRate)))
+	}
+
+	return PerformanceIssue{
+		Type:     "Back-to-Back Collections",
+		Severity: "warning",
+		Description: fmt.Sprintf("%s of collections started less than %s after the previous one",
+			utils.FormatPercent(frequency.BackToBackRate*100), utils.FormatDuration(BackToBackGap)),
+		Recommendation: recommendations,
+	}
+}
+
+/*
+ * getLateMarkingRec moves the marking threshold down by what the worst race
+ * needed. Adaptive IHOP sets its own threshold, so the advice goes to what it
+ * works from: the initial threshold while it still has too few samples to
+ * predict, and the reserve it keeps once it does.
+ */
+func getLateMarkingRec(analysis *GCAnalysis) PerformanceIssue {
+	ihop := analysis.IHOP
+	description := fmt.Sprintf("Marking finished with only %s of the heap free of old data",
+		utils.FormatPercent(ihop.MinMargin*100))
+	if ihop.LostRaces > 0 {
+		description = fmt.Sprintf("%d of %d marking cycles lost the race to allocation", ihop.LostRaces, ihop.Cycles)
+	}
+
+	recommendations := []string{
+		fmt.Sprintf("Marking started at %d%% heap occupancy; old data grew by up to %s of the heap before Remark",
+			ihop.CurrentPercent(), utils.FormatPercent(ihop.MaxGrowth*100)),
+	}
+	if ihop.LostRaces > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Cycles that hit a Full GC or evacuation failure before marking finished: %d", ihop.LostRaces))
+	} else if ihop.MinRaceMargin > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Worst race margin %sx: the free heap at marking start over the old growth during marking",
+				utils.FormatFloat(ihop.MinRaceMargin)))
+	}
+
+	switch {
+	case ihop.Adaptive && ihop.PredictionActiveRate < 0.5:
+		recommendations = append(recommendations,
+			fmt.Sprintf("Adaptive IHOP used its initial threshold for %s of updates, too few samples to predict",
+				utils.FormatPercent((1-ihop.PredictionActiveRate)*100)),
+			fmt.Sprintf("Start it lower: -XX:InitiatingHeapOccupancyPercent=%d", ihop.SuggestedPercent),
+			"Let it predict after one cycle: -XX:G1AdaptiveIHOPNumInitialSamples=1")
+	case ihop.Adaptive:
+		recommendations = append(recommendations,
+			fmt.Sprintf("Adaptive IHOP predicted %s/s of old allocation over %s of marking and still started late",
+				utils.MemorySize(ihop.OldAllocationRate), utils.FormatDuration(ihop.MarkingLength)),
+			"Widen the reserve it plans around: -XX:G1ReservePercent=20",
+			fmt.Sprintf("Or fix the threshold: -XX:-G1UseAdaptiveIHOP -XX:InitiatingHeapOccupancyPercent=%d", ihop.SuggestedPercent))
+	default:
+		recommendations = append(recommendations,
+			fmt.Sprintf("Start marking earlier: -XX:InitiatingHeapOccupancyPercent=%d", ihop.SuggestedPercent),
+			"Adaptive IHOP starts from this value and moves it; -XX:-G1UseAdaptiveIHOP keeps it fixed",
+			"See the adaptive threshold and its predictions with -Xlog:gc+ihop=debug")
+	}
+
+	return PerformanceIssue{
+		Type:           "Late Concurrent Marking",
+		Severity:       "warning",
+		Description:    description,
+		Recommendation: recommendations,
+	}
+}
+
+// getEarlyMarkingRec raises a marking threshold that leaves half the heap unused
+func getEarlyMarkingRec(analysis *GCAnalysis) PerformanceIssue {
+	ihop := analysis.IHOP
+	recommendations := []string{
+		fmt.Sprintf("Marking started at %d%% heap occupancy and old data grew by at most %s of the heap before Remark",
+			ihop.CurrentPercent(), utils.FormatPercent(ihop.MaxGrowth*100)),
+		fmt.Sprintf("Fewer marking cycles, each with more to reclaim: -XX:InitiatingHeapOccupancyPercent=%d", ihop.SuggestedPercent),
+	}
+	if ihop.Adaptive && ihop.PredictionActiveRate < 0.5 {
+		recommendations = append(recommendations,
+			"Adaptive IHOP hasn't taken over from the initial threshold yet: -XX:G1AdaptiveIHOPNumInitialSamples=1")
+	}
+
+	return PerformanceIssue{
+		Type:     "Early Concurrent Marking",
+		Severity: "info",
+		Description: fmt.Sprintf("Marking finished with at least %s of the heap free across %d cycles",
+			utils.FormatPercent(ihop.MinMargin*100), ihop.Cycles),
+		Recommendation: recommendations,
+	}
+}
+
+func getWorkerBalanceRec(analysis *GCAnalysis) PerformanceIssue {
+	balance := analysis.WorkerBalance
+	description := fmt.Sprintf("%s of pauses wait on a single straggling GC worker", utils.FormatPercent(balance.StragglerRate*100))
+
+	var recommendations []string
+	if len(balance.Phases) > 0 {
+		worst := balance.Phases[0]
+		description += fmt.Sprintf(" (worst: %s, slowest worker %sx the average)", worst.Phase, utils.FormatFloat(worst.AvgImbalance))
+		recommendations = append(recommendations,
+			fmt.Sprintf("%s: pauses waited %v in total on the slowest worker", worst.Phase, worst.StragglerTime.Round(time.Millisecond)))
+		recommendations = append(recommendations, workerPhaseHint(worst.Phase))
+	}
+
+	// Extra workers only add waiting when one of them holds the work that can't be split
+	switch threads := balance.MaxWorkers; {
+	case analysis.CPUs > 0 && threads > analysis.CPUs:
+		recommendations = append(recommendations,
+			fmt.Sprintf("%d GC workers share %d CPUs, so some wait for a CPU: -XX:ParallelGCThreads=%d", threads, analysis.CPUs, analysis.CPUs))
+	case threads > 2:
+		recommendations = append(recommendations,
+			fmt.Sprintf("Fewer workers idle less while one straggles: try -XX:ParallelGCThreads=%d (now %d)", max(2, threads*3/4), threads))
+	}
+	recommendations = append(recommendations,
+		"In containers, check CPU throttling (nr_throttled in cpu.stat): a descheduled worker looks like a straggler")
+
+	return PerformanceIssue{
+		Type:           "Worker Imbalance",
+		Severity:       "warning",
+		Description:    description,
+		Recommendation: recommendations,
+	}
+}
+
+/*
+ * pauseTargetAdvice tunes for the GC type behind the pause target misses, when
+ * one is: the settings that shorten one type of pause do nothing for the
+ * others, and lowering MaxGCPauseMillis for Mixed or Full misses only makes
+ * G1 shrink young pauses that already meet it. nil when the misses are spread
+ * out, leaving the general pause advice.
+ */
+func pauseTargetAdvice(analysis *GCAnalysis) []string {
+	targets := analysis.PauseTargets
+	if targets.Culprit == "" {
+		return nil
+	}
+
+	var byType []string
+	for _, compliance := range targets.ByType {
+		byType = append(byType, fmt.Sprintf("%s %s (%d of %d)", compliance.Name,
+			utils.FormatPercent(compliance.MissRate*100), compliance.Misses, compliance.Pauses))
+	}
+	advice := []string{
+		"Misses by type: " + strings.Join(byType, ", "),
+		fmt.Sprintf("%s pauses cause %s of the misses; tune them rather than the pause target", targets.Culprit,
+			utils.FormatPercent(targets.CulpritShare*100)),
+	}
+	if len(targets.ByCause) > 0 {
+		if cause := targets.ByCause[0]; float64(cause.Misses) >= CulpritMissShare*float64(targets.Misses) {
+			advice = append(advice, fmt.Sprintf("%d of the %d misses are %s pauses", cause.Misses, targets.Misses, cause.Name))
+		}
+	}
+
+	switch targets.Culprit {
+	case "Young":
+		advice = append(advice,
+			"Copy less per young pause with a smaller young generation: -XX:G1MaxNewSizePercent=30",
+			"Or lower -XX:MaxGCPauseMillis, which G1 meets by sizing the young generation down")
+	case "Mixed":
+		advice = append(advice,
+			"Spread old regions over more mixed collections: -XX:G1MixedGCCountTarget=16",
+			"Cap old regions per mixed collection: -XX:G1OldCSetRegionThresholdPercent=5",
+			"Leave the costliest regions uncollected: -XX:G1HeapWastePercent=10",
+			"Young pauses meet the target, so lowering -XX:MaxGCPauseMillis would only shrink them")
+	case GCTypeRemark:
+		advice = append(advice,
+			"Remark time goes to reference processing and class unloading: -XX:+ParallelRefProcEnabled",
+			"See which with -Xlog:gc+ref=debug,gc+phases=debug; many soft, weak or finalizable references point at caches or finalizers")
+	case "Full":
+		advice = append(advice,
+			"No pause target setting shortens a Full GC; the fix is to stop them (see Full GC Events)")
+	default:
+		advice = append(advice, fmt.Sprintf("Check what makes %s pauses long with -Xlog:gc+phases=debug", targets.Culprit))
+	}
+	return advice
+}
+
+/*
+ * fullGCPhaseAdvice makes the Full GCs that can't be avoided cheaper, by
+ * the phase that takes their time: class churn and references when marking
+ * dominates, the object graph when addresses and pointers do, and the live
+ * data moved when compaction does. nil when the Full GCs logged no phases.
+ */
+func fullGCPhaseAdvice(analysis *GCAnalysis) []string {
+	phases := analysis.FullGCPhases
+	if phases.Events == 0 {
+		return nil
+	}
+
+	var byPhase []string
+	for _, stats := range phases.Phases {
+		byPhase = append(byPhase, fmt.Sprintf("%s %s", stats.Phase, utils.FormatPercent(stats.Share*100)))
+	}
+	advice := []string{fmt.Sprintf("Full GC time by phase: %s (%d Full GCs)", strings.Join(byPhase, ", "), phases.Events)}
+	if analysis.Collector == "Serial" {
+		advice = append(advice, "Serial Full GCs mark and compact on one thread; -XX:+UseParallelGC or -XX:+UseG1GC use every core")
+	}
+
+	switch phases.Dominant {
+	case FullPhaseMark:
+		if phases.ClassUnloadingShare >= FullSubphaseShare {
+			advice = append(advice,
+				fmt.Sprintf("Class unloading takes %s of marking: classes are generated and dropped continuously (proxies, scripts, redeploys)",
+					utils.FormatPercent(phases.ClassUnloadingShare*100)),
+				"See which with -Xlog:class+load=info,class+unload=info and cache what generates them")
+			if analysis.GCCauseDurations["Metadata GC Threshold"] > 0 {
+				advice = append(advice, "Metaspace filling up triggers collections: raise -XX:MetaspaceSize so class churn doesn't")
+			}
+		}
+		if phases.RefProcessingShare >= FullSubphaseShare {
+			advice = append(advice,
+				fmt.Sprintf("Reference processing takes %s of marking: -XX:+ParallelRefProcEnabled", utils.FormatPercent(phases.RefProcessingShare*100)),
+				"Full GCs clear every soft reference; see how many with -Xlog:gc+ref=debug and bound SoftReference caches")
+		}
+		advice = append(advice, "Marking traces every live object, so it takes as long as the live set is large: shrink long-lived caches and collections")
+		if !phases.SubphasesLogged {
+			advice = append(advice, "Split marking into class unloading and reference processing with -Xlog:gc+phases=debug")
+		}
+	case FullPhasePrepare, FullPhaseAdjust:
+		advice = append(advice,
+			"Planning moves and rewriting references take as long as there are live objects and references between them",
+			"Many small linked objects (maps, nodes, boxed values) cost most: flatten them into arrays or primitive collections")
+	case FullPhaseCompact:
+		advice = append(advice, "Moving objects takes as long as the live data that moves; a larger heap doesn't shorten it")
+		if analysis.Collector == "Serial" || analysis.Collector == "Parallel" {
+			advice = append(advice, "Leave mostly live space in place rather than compacting it: -XX:MarkSweepDeadRatio=10")
+		}
+	}
+	return advice
+}
+
+/*
+ * survivorAdvice sizes the survivor space from the demand the young
+ * collections showed, in place of a stock SurvivorRatio. nil when too few
+ * young collections logged their survivors to model, leaving the general
+ * advice.
+ */
+func survivorAdvice(analysis *GCAnalysis) []string {
+	model := analysis.Survivor
+	if model.Samples == 0 {
+		return nil
+	}
+
+	source := "survivor occupancy"
+	if model.FromAgeTable {
+		source = "age table"
+	}
+	advice := []string{
+		fmt.Sprintf("Survivor demand per young GC: median %s, P95 %s, max %s against %s of survivor space (%s, %d collections)",
+			model.DemandP50, model.DemandP95, model.DemandMax, model.Capacity, source, model.Samples),
+	}
+
+	if model.Undersized() {
+		size := fmt.Sprintf("Size survivor space for the P%.0f demand, %s", (1-SurvivorOverflowGoal)*100, model.SuggestedSize)
+		if model.SuggestedRatio > 0 {
+			size += fmt.Sprintf(": -XX:SurvivorRatio=%d (now about %d)", model.SuggestedRatio, model.CurrentRatio)
+			if analysis.Collector == "Parallel" {
+				size += " -XX:-UseAdaptiveSizePolicy"
+			}
+		}
+		advice = append(advice, size, fmt.Sprintf("Expected survivor overflow: %s of young GCs, down from %s",
+			utils.FormatPercent(model.ExpectedOverflow*100), utils.FormatPercent(model.OverflowRate*100)))
+		if model.SuggestedRatio > 0 {
+			advice = append(advice, "The young generation keeps its size, so eden shrinks by what survivors gain; young GCs come a little more often")
+		}
+	} else {
+		advice = append(advice, fmt.Sprintf("Survivor space holds the demand: %s of young GCs overflowed; leave -XX:SurvivorRatio as it is",
+			utils.FormatPercent(model.OverflowRate*100)))
+	}
+
+	if model.MaxThreshold > 0 && model.LoweredThresholdRate > SurvivorOverflowWarning {
+		advice = append(advice,
+			fmt.Sprintf("Tenuring threshold fell to %d of %d in %s of young GCs: survivors passed %d%% of the space, so objects were promoted early",
+				model.MedianThreshold, model.MaxThreshold, utils.FormatPercent(model.LoweredThresholdRate*100), DefaultTargetSurvivorRatio),
+			"Let survivors fill more of the space before tenuring early: -XX:TargetSurvivorRatio=80")
+	}
+	return advice
+}
+
+/*
+ * humongousSizeAdvice maps the humongous object sizes seen to the region
+ * space they waste, and to the region size that would make most of them
+ * normal objects. nil when too few sizes were logged, leaving the general
+ * advice.
+ */
+func humongousSizeAdvice(analysis *GCAnalysis) []string {
+	histogram := analysis.HumongousSizes
+	if !histogram.Significant() {
+		return nil
+	}
+
+	commonest := histogram.Commonest()
+	advice := []string{
+		fmt.Sprintf("Humongous objects (%s): %d totalling %s, most often %s-%s (%d of them)",
+			histogram.Source, histogram.Objects, histogram.Total, commonest.Min, commonest.Max, commonest.Count),
+		fmt.Sprintf("With %s regions they leave %s unused past their ends; objects just over half a region waste nearly half of it",
+			histogram.RegionSize, histogram.Wasted),
+	}
+
+	if option, ok := histogram.Option(histogram.Suggested); ok {
+		regions := ""
+		if option.HeapRegions > 0 {
+			regions = fmt.Sprintf(", leaving the heap %d regions", option.HeapRegions)
+		}
+		advice = append(advice, fmt.Sprintf("Make %s of them normal objects allocated in eden: -XX:G1HeapRegionSize=%s%s",
+			utils.FormatPercent(option.NormalShare*100), jvmSize(option.Size), regions))
+		if option.Normal < histogram.Objects {
+			advice = append(advice, fmt.Sprintf("The %d still humongous would leave %s unused", histogram.Objects-option.Normal, option.Wasted))
+		}
+	} else {
+		advice = append(advice, fmt.Sprintf("No region size the heap can take makes %s of them normal; keep large allocations at or under %s, half a region, e.g. by chunking buffers",
+			utils.FormatPercent(HumongousNormalGoal*100), histogram.RegionSize/2))
+	}
+	return advice
+}
+
+// workerPhaseHint names the usual reason one worker ends up with most of a phase
+func workerPhaseHint(phase string) string {
+	switch phase {
+	case "Ext Root Scanning", "Code Root Scanning":
+		return "Roots are scanned one thread stack at a time: look for threads with very deep stacks or many JNI handles"
+	case "Object Copy":
+		return "One worker copies a large object array or long linked structure alone: look for huge arrays or linked lists"
+	case "Update RS", "Scan RS":
+		return "Remembered set work is concentrated in a few regions: look for a hot old-to-young reference pattern"
+	default:
+		return "Enable -Xlog:gc+phases=trace to see per-worker times for the phase"
+	}
+}
+
 // ===== INFO RECOMMENDATION GENERATORS =====
 
+func getHumongousSizingRec(analysis *GCAnalysis) PerformanceIssue {
+	histogram := analysis.HumongousSizes
+	description := fmt.Sprintf("%d humongous objects leave %s of their regions unused", histogram.Objects, histogram.Wasted)
+	if histogram.Suggested > 0 {
+		description += fmt.Sprintf("; %s regions would make most of them normal", histogram.Suggested)
+	}
+
+	return PerformanceIssue{
+		Type:           "Humongous Allocation Sizes",
+		Severity:       "info",
+		Description:    description,
+		Recommendation: humongousSizeAdvice(analysis),
+	}
+}
+
 func getAllocationPatternRec(analysis *GCAnalysis) PerformanceIssue {
 	recommendations := []string{
-		fmt.Sprintf("Moderate allocation rate: %.1f MB/s is manageable", analysis.AllocationRate),
+		fmt.Sprintf("Moderate allocation rate: %s/s is manageable", utils.FormatMB(analysis.AllocationRate)),
 		"Current allocation rate is within normal range",
 		"Monitor for allocation bursts or patterns",
 		"Consider profiling if allocation rate increases",
@@ -542,7 +1060,7 @@ func getAllocationPatternRec(analysis *GCAnalysis) PerformanceIssue {
 	return PerformanceIssue{
 		Type:           "Allocation Pattern Analysis",
 		Severity:       "info",
-		Description:    fmt.Sprintf("Allocation rate %.1f MB/s", analysis.AllocationRate),
+		Description:    fmt.Sprintf("Allocation rate %s/s", utils.FormatMB(analysis.AllocationRate)),
 		Recommendation: recommendations,
 	}
 }
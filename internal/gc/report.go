@@ -0,0 +1,342 @@
+package gc
+
+import (
+	"math"
+	"time"
+)
+
+// Report is the structured form of a GC analysis, for JSON consumers
+type Report struct {
+	JVMVersion     string    `json:"jvmVersion"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	RuntimeMs      float64   `json:"runtimeMs"`
+	Status         string    `json:"status"` // "critical", "warning" or "healthy": the most severe issue found
+	HeapMax        int64     `json:"heapMax"`
+	HeapRegionSize int64     `json:"heapRegionSize"`
+	CDSArchiveSize int64     `json:"cdsArchiveSize,omitempty"` // Class data sharing archive mapped at start-up
+	SkippedLines   int       `json:"skippedLines"`             // Log lines the parser couldn't use
+
+	Events      ReportEventCounts `json:"events"`
+	Throughput  float64           `json:"throughput"` // Percentage of time not spent in GC pauses
+	TotalGCMs   float64           `json:"totalGcMs"`
+	AllocRateMB float64           `json:"allocationRateMBps"`
+	Pauses      ReportPauses      `json:"pauses"`
+	MemoryTrend ReportMemoryTrend `json:"memoryTrend"`
+	Survivor    *ReportSurvivor   `json:"survivor,omitempty"`     // nil when too few young collections logged their survivors
+	IHOP        *ReportIHOP       `json:"ihop,omitempty"`         // nil without a marking cycle followed to its Remark
+	FullGC      *ReportFullGC     `json:"fullGcPhases,omitempty"` // nil when no Full GC logged its phases
+	GCLocker    *ReportGCLocker   `json:"gcLocker,omitempty"`     // nil without GCLocker collections or gc+jni lines
+
+	EvacuationFailures int                `json:"evacuationFailures"`
+	TimeByTypeMs       map[string]float64 `json:"timeByTypeMs"`
+	CountByType        map[string]int     `json:"countByType"`
+	TimeByCauseMs      map[string]float64 `json:"timeByCauseMs"`
+
+	Issues []ReportIssue `json:"issues"`
+}
+
+type ReportEventCounts struct {
+	Total int `json:"total"`
+	Young int `json:"young"`
+	Mixed int `json:"mixed"`
+	Full  int `json:"full"`
+}
+
+type ReportPauses struct {
+	AvgMs          float64 `json:"avgMs"`
+	MinMs          float64 `json:"minMs"`
+	MaxMs          float64 `json:"maxMs"`
+	P95Ms          float64 `json:"p95Ms"`
+	P99Ms          float64 `json:"p99Ms"`
+	TargetMs       float64 `json:"targetMs"`
+	TargetMissRate float64 `json:"targetMissRate"`
+	LongPauses     int     `json:"longPauses"`
+
+	TargetMissByType  []ReportTargetMisses `json:"targetMissByType,omitempty"`
+	TargetMissByCause []ReportTargetMisses `json:"targetMissByCause,omitempty"`
+	TargetMissCulprit string               `json:"targetMissCulprit,omitempty"`
+}
+
+// ReportTargetMisses is how often one GC type's or cause's pauses missed the target
+type ReportTargetMisses struct {
+	Name     string  `json:"name"`
+	Pauses   int     `json:"pauses"`
+	Misses   int     `json:"misses"`
+	MissRate float64 `json:"missRate"`
+	MaxMs    float64 `json:"maxMs"`
+}
+
+type ReportMemoryTrend struct {
+	GrowthMBPerHour float64  `json:"growthMBPerHour"`
+	Confidence      float64  `json:"confidence"`
+	LeakSeverity    string   `json:"leakSeverity"`
+	ProjectedFullMs float64  `json:"projectedFullHeapMs,omitempty"`
+	Indicators      []string `json:"indicators,omitempty"`
+}
+
+// ReportSurvivor is survivor demand per young collection and the size that holds it; sizes are in bytes
+type ReportSurvivor struct {
+	Samples          int     `json:"samples"`
+	Capacity         int64   `json:"capacity"`
+	DemandP50        int64   `json:"demandP50"`
+	DemandP95        int64   `json:"demandP95"`
+	DemandMax        int64   `json:"demandMax"`
+	OverflowRate     float64 `json:"overflowRate"`
+	SuggestedSize    int64   `json:"suggestedSize"`
+	ExpectedOverflow float64 `json:"expectedOverflow"`
+	CurrentRatio     int     `json:"currentSurvivorRatio,omitempty"`
+	SuggestedRatio   int     `json:"suggestedSurvivorRatio,omitempty"`
+}
+
+// ReportIHOP is how close marking cycles came to losing the race against allocation; shares are of the heap
+type ReportIHOP struct {
+	Cycles           int     `json:"cycles"`
+	LostRaces        int     `json:"lostRaces"`
+	StartPercent     int     `json:"startPercent"`
+	MaxGrowth        float64 `json:"maxGrowth"`
+	MinMargin        float64 `json:"minMargin"`
+	MinRaceMargin    float64 `json:"minRaceMargin"`
+	SuggestedPercent int     `json:"suggestedPercent,omitempty"` // Only when marking starts too late or too early
+}
+
+// ReportFullGC is where Full GC time went; shares are of the time all phases took
+type ReportFullGC struct {
+	Events              int               `json:"events"`
+	TotalMs             float64           `json:"totalMs"`
+	Phases              []ReportFullPhase `json:"phases"`
+	Dominant            string            `json:"dominant,omitempty"`
+	ClassUnloadingShare float64           `json:"classUnloadingShare,omitempty"` // Of marking, with gc+phases=debug
+	RefProcessingShare  float64           `json:"refProcessingShare,omitempty"`
+}
+
+type ReportFullPhase struct {
+	Phase   string  `json:"phase"`
+	Count   int     `json:"count"`
+	TotalMs float64 `json:"totalMs"`
+	AvgMs   float64 `json:"avgMs"`
+	MaxMs   float64 `json:"maxMs"`
+	Share   float64 `json:"share"`
+}
+
+// ReportGCLocker is the collections JNI critical regions held back; threads come from gc+jni=debug
+type ReportGCLocker struct {
+	Collections    int                 `json:"collections"`
+	Share          float64             `json:"share"`
+	FullGCs        int                 `json:"fullGcs"`
+	BackToBack     int                 `json:"backToBack"`
+	Stalls         int                 `json:"stalls"`
+	BlockedEntries int                 `json:"blockedEntries"`
+	RetryFailures  int                 `json:"retryFailures"`
+	Holders        []ReportThreadCount `json:"holders,omitempty"`
+	Stalled        []ReportThreadCount `json:"stalled,omitempty"`
+}
+
+type ReportThreadCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type ReportIssue struct {
+	Type            string   `json:"type"`
+	Severity        string   `json:"severity"`
+	Description     string   `json:"description"`
+	Recommendations []string `json:"recommendations"`
+}
+
+// ReportEvent is one collection; heap sizes are in bytes
+type ReportEvent struct {
+	ID         int       `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Type       string    `json:"type"`
+	Subtype    string    `json:"subtype,omitempty"`
+	Cause      string    `json:"cause,omitempty"`
+	HeapBefore int64     `json:"heapBefore"`
+	HeapAfter  int64     `json:"heapAfter"`
+	HeapTotal  int64     `json:"heapTotal"`
+	DurationMs float64   `json:"durationMs"`
+}
+
+// NewReport flattens an analysis and its recommendations; JSON has no NaN, so undefined ratios become 0
+func NewReport(analysis *GCAnalysis, issues *GCIssues) *Report {
+	report := &Report{
+		JVMVersion:     analysis.JVMVersion,
+		StartTime:      analysis.StartTime,
+		EndTime:        analysis.EndTime,
+		RuntimeMs:      milliseconds(analysis.TotalRuntime),
+		Status:         issues.Status(),
+		HeapMax:        analysis.HeapMax.Bytes(),
+		HeapRegionSize: analysis.HeapRegionSize.Bytes(),
+		CDSArchiveSize: analysis.CDSArchiveSize.Bytes(),
+		SkippedLines:   analysis.SkippedLines,
+		Events: ReportEventCounts{
+			Total: analysis.TotalEvents,
+			Young: analysis.YoungGCCount,
+			Mixed: analysis.MixedGCCount,
+			Full:  analysis.FullGCCount,
+		},
+		Throughput:  finite(analysis.Throughput),
+		TotalGCMs:   milliseconds(analysis.TotalGCTime),
+		AllocRateMB: finite(analysis.AllocationRate),
+		Pauses: ReportPauses{
+			AvgMs:          milliseconds(analysis.AvgPause),
+			MinMs:          milliseconds(analysis.MinPause),
+			MaxMs:          milliseconds(analysis.MaxPause),
+			P95Ms:          milliseconds(analysis.P95Pause),
+			P99Ms:          milliseconds(analysis.P99Pause),
+			TargetMs:       milliseconds(analysis.EstimatedPauseTarget),
+			TargetMissRate: finite(analysis.PauseTargetMissRate),
+			LongPauses:     analysis.LongPauseCount,
+
+			TargetMissByType:  reportTargetMisses(analysis.PauseTargets.ByType),
+			TargetMissByCause: reportTargetMisses(analysis.PauseTargets.ByCause),
+			TargetMissCulprit: analysis.PauseTargets.Culprit,
+		},
+		MemoryTrend: ReportMemoryTrend{
+			GrowthMBPerHour: finite(analysis.MemoryTrend.GrowthRateMBPerHour),
+			Confidence:      finite(analysis.MemoryTrend.TrendConfidence),
+			LeakSeverity:    analysis.MemoryTrend.LeakSeverity,
+			ProjectedFullMs: milliseconds(analysis.MemoryTrend.ProjectedFullHeapTime),
+			Indicators:      analysis.MemoryLeakIndicators,
+		},
+		EvacuationFailures: analysis.EvacuationFailureCount,
+		TimeByTypeMs:       durationsMs(analysis.GCTypeDurations),
+		CountByType:        analysis.GCTypeEventCounts,
+		TimeByCauseMs:      durationsMs(analysis.GCCauseDurations),
+		Issues:             []ReportIssue{},
+	}
+	if model := analysis.Survivor; model.Samples > 0 {
+		report.Survivor = &ReportSurvivor{
+			Samples:          model.Samples,
+			Capacity:         model.Capacity.Bytes(),
+			DemandP50:        model.DemandP50.Bytes(),
+			DemandP95:        model.DemandP95.Bytes(),
+			DemandMax:        model.DemandMax.Bytes(),
+			OverflowRate:     finite(model.OverflowRate),
+			SuggestedSize:    model.SuggestedSize.Bytes(),
+			ExpectedOverflow: finite(model.ExpectedOverflow),
+			CurrentRatio:     model.CurrentRatio,
+			SuggestedRatio:   model.SuggestedRatio,
+		}
+	}
+	if ihop := analysis.IHOP; ihop.Cycles > 0 {
+		report.IHOP = &ReportIHOP{
+			Cycles:        ihop.Cycles,
+			LostRaces:     ihop.LostRaces,
+			StartPercent:  ihop.CurrentPercent(),
+			MaxGrowth:     finite(ihop.MaxGrowth),
+			MinMargin:     finite(ihop.MinMargin),
+			MinRaceMargin: finite(ihop.MinRaceMargin),
+		}
+		if ihop.StartsLate() || ihop.StartsEarly() {
+			report.IHOP.SuggestedPercent = ihop.SuggestedPercent
+		}
+	}
+
+	if phases := analysis.FullGCPhases; phases.Events > 0 {
+		report.FullGC = &ReportFullGC{
+			Events:              phases.Events,
+			TotalMs:             milliseconds(phases.Total),
+			Dominant:            phases.Dominant,
+			ClassUnloadingShare: finite(phases.ClassUnloadingShare),
+			RefProcessingShare:  finite(phases.RefProcessingShare),
+		}
+		for _, stats := range phases.Phases {
+			report.FullGC.Phases = append(report.FullGC.Phases, ReportFullPhase{
+				Phase:   stats.Phase,
+				Count:   stats.Count,
+				TotalMs: milliseconds(stats.Total),
+				AvgMs:   milliseconds(stats.Avg),
+				MaxMs:   milliseconds(stats.Max),
+				Share:   finite(stats.Share),
+			})
+		}
+	}
+
+	if locker := analysis.GCLocker; locker.Collections > 0 || locker.Logged {
+		report.GCLocker = &ReportGCLocker{
+			Collections:    locker.Collections,
+			Share:          finite(locker.Share),
+			FullGCs:        locker.FullGCs,
+			BackToBack:     locker.BackToBack,
+			Stalls:         locker.Stalls,
+			BlockedEntries: locker.BlockedEntries,
+			RetryFailures:  locker.RetryFailures,
+			Holders:        reportThreadCounts(locker.Holders),
+			Stalled:        reportThreadCounts(locker.Stalled),
+		}
+	}
+
+	if issues != nil {
+		for _, group := range [][]PerformanceIssue{issues.Critical, issues.Warning, issues.Info} {
+			for _, issue := range group {
+				report.Issues = append(report.Issues, ReportIssue{
+					Type:            issue.Type,
+					Severity:        issue.Severity,
+					Description:     issue.Description,
+					Recommendations: issue.Recommendation,
+				})
+			}
+		}
+	}
+	return report
+}
+
+func NewReportEvents(events []*GCEvent) []ReportEvent {
+	rows := make([]ReportEvent, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, ReportEvent{
+			ID:         event.ID,
+			Timestamp:  event.Timestamp,
+			Type:       event.Type,
+			Subtype:    event.Subtype,
+			Cause:      event.Cause,
+			HeapBefore: event.HeapBefore.Bytes(),
+			HeapAfter:  event.HeapAfter.Bytes(),
+			HeapTotal:  event.HeapTotal.Bytes(),
+			DurationMs: milliseconds(event.Duration),
+		})
+	}
+	return rows
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func durationsMs(durations map[string]time.Duration) map[string]float64 {
+	result := make(map[string]float64, len(durations))
+	for key, d := range durations {
+		result[key] = milliseconds(d)
+	}
+	return result
+}
+
+func reportTargetMisses(compliances []PauseCompliance) []ReportTargetMisses {
+	var result []ReportTargetMisses
+	for _, compliance := range compliances {
+		result = append(result, ReportTargetMisses{
+			Name:     compliance.Name,
+			Pauses:   compliance.Pauses,
+			Misses:   compliance.Misses,
+			MissRate: finite(compliance.MissRate),
+			MaxMs:    milliseconds(compliance.Max),
+		})
+	}
+	return result
+}
+
+func reportThreadCounts(threads []ThreadCount) []ReportThreadCount {
+	var result []ReportThreadCount
+	for _, thread := range threads {
+		result = append(result, ReportThreadCount{Name: thread.Name, Count: thread.Count})
+	}
+	return result
+}
+
+func finite(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return value
+}
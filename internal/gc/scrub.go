@@ -0,0 +1,163 @@
+package gc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*
+ * Scrubbing
+ *
+ * A GC log is mostly numbers, but a few lines say who and where: the
+ * command line a JDK 8 log starts with and the arguments lines of a unified
+ * one carry system properties and file paths, the hostname decoration
+ * (-Xlog:...:hostname) names the machine, and paths, addresses and emails
+ * turn up in causes and warnings. Scrub replaces those with placeholders
+ * and leaves everything else, timestamps and sizes included, as it was, so
+ * the scrubbed log parses to the same events.
+ *
+ * 	-Dkey=value				-Dkey=<redacted>
+ * 	Java Command: ...			Java Command: <redacted>
+ * 	/home/alice/app/gc.log		<path>/gc.log
+ * 	C:\Users\alice\gc.log		<path>\gc.log
+ * 	10.1.2.3				<ip>
+ * 	alice@example.com			<email>
+ * 	[web-01.example.com]		[<host>]	(a decoration before the level)
+ *
+ * It's a best effort over what GC logs are known to hold, not a guarantee:
+ * a secret in a thread name passes through.
+ */
+
+// Kinds of value Scrub replaces
+const (
+	ScrubProperty = "system properties"
+	ScrubCommand  = "command lines"
+	ScrubPath     = "paths"
+	ScrubAddress  = "IP addresses"
+	ScrubEmail    = "email addresses"
+	ScrubHost     = "hostnames"
+)
+
+var (
+	scrubPropertyPattern = regexp.MustCompile(`(-D[\w.\-]+=)("[^"]*"|\S+)`)
+	scrubCommandPattern  = regexp.MustCompile(`((?:Java Command|sun\.java\.command)\s*[:=]\s*)\S.*$`)
+	scrubUnixPathPattern = regexp.MustCompile(`(^|[\s=:'"(,])((?:/[\w.\-~@+]+)+)/([\w.\-~@+]+)`)
+	scrubWinPathPattern  = regexp.MustCompile(`\b[A-Za-z]:\\(?:[\w.\-~@+ ]+\\)*([\w.\-~@+]+)`)
+	scrubAddressPattern  = regexp.MustCompile(`\b(?:25[0-5]|2[0-4]\d|1?\d?\d)(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){3}\b`)
+	scrubEmailPattern    = regexp.MustCompile(`\b[\w.%+\-]+@[\w\-]+(?:\.[\w\-]+)+\b`)
+
+	// A decoration that can only be a time, uptime, pid or tid
+	scrubTimeDecoration = regexp.MustCompile(`^\s*[\d\-T:.+]+(s|ms|ns)?\s*$`)
+	scrubLevels         = []string{"trace", "debug", "info", "warning", "error"}
+)
+
+// ScrubStats counts the values Scrub replaced by kind
+type ScrubStats map[string]int
+
+// Total is how many values were replaced
+func (s ScrubStats) Total() int {
+	total := 0
+	for _, count := range s {
+		total += count
+	}
+	return total
+}
+
+// String lists the counts, e.g. "3 paths, 1 hostnames", or "nothing" when none were replaced
+func (s ScrubStats) String() string {
+	if s.Total() == 0 {
+		return "nothing"
+	}
+	kinds := make([]string, 0, len(s))
+	for kind := range s {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var parts []string
+	for _, kind := range kinds {
+		if s[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", s[kind], kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Scrub copies a GC log from r to w with identifying values replaced
+func Scrub(r io.Reader, w io.Writer) (ScrubStats, error) {
+	stats := make(ScrubStats)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // Command lines can be long
+	out := bufio.NewWriter(w)
+	for scanner.Scan() {
+		out.WriteString(ScrubLine(scanner.Text(), stats))
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read log: %w", err)
+	}
+	return stats, out.Flush()
+}
+
+// ScrubLine scrubs one line, counting what it replaced in stats
+func ScrubLine(line string, stats ScrubStats) string {
+	line = scrubHostDecoration(line, stats)
+
+	replace := func(pattern *regexp.Regexp, kind, replacement string) {
+		line = pattern.ReplaceAllStringFunc(line, func(match string) string {
+			stats[kind]++
+			return pattern.ReplaceAllString(match, replacement)
+		})
+	}
+	replace(scrubCommandPattern, ScrubCommand, "${1}<redacted>")
+	replace(scrubPropertyPattern, ScrubProperty, "${1}<redacted>")
+	replace(scrubEmailPattern, ScrubEmail, "<email>")
+	replace(scrubUnixPathPattern, ScrubPath, "${1}<path>/${3}")
+	replace(scrubWinPathPattern, ScrubPath, `<path>\${1}`)
+	replace(scrubAddressPattern, ScrubAddress, "<ip>")
+	return line
+}
+
+/*
+ * scrubHostDecoration replaces the hostname among a unified logging line's
+ * leading [...] decorations. Decorations come in a fixed order, with the
+ * hostname after the times and before the pid, tid and level, so it's the
+ * one before the level that isn't a time or a number. Without a level
+ * decoration it can't be told from the tags, and the line is left alone.
+ */
+func scrubHostDecoration(line string, stats ScrubStats) string {
+	if !strings.HasPrefix(line, "[") {
+		return line
+	}
+
+	host := -1
+	rest := line
+	offset := 0
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return line
+		}
+		decoration := rest[1:end]
+		trimmed := strings.TrimSpace(decoration)
+		for _, level := range scrubLevels {
+			if trimmed == level {
+				if host < 0 {
+					return line
+				}
+				stats[ScrubHost]++
+				hostEnd := host + strings.IndexByte(line[host:], ']')
+				return line[:host+1] + "<host>" + line[hostEnd:]
+			}
+		}
+		if host < 0 && !scrubTimeDecoration.MatchString(decoration) {
+			host = offset
+		}
+		offset += end + 1
+		rest = rest[end+1:]
+	}
+	return line
+}
@@ -0,0 +1,180 @@
+package gc
+
+import (
+	"math"
+	"slices"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * A young collection copies what's still live in eden and survivor space
+ * into the other survivor space; what doesn't fit overflows into the old
+ * generation whatever its age, and the old generation then fills with
+ * objects that would have died young. Each young collection is a sample of
+ * survivor demand, measured by the age table's total when gc+age=trace is
+ * logged and by survivor occupancy after the collection otherwise.
+ *
+ * A collection that overflowed was cut off at the survivor capacity, so its
+ * demand is that capacity plus what it promoted. Objects old enough to be
+ * tenured count as overflow too, which errs toward a larger survivor space.
+ *
+ * The suggested size holds the demand of all but SurvivorOverflowGoal of the
+ * collections, and the SurvivorRatio that gives it keeps the young generation
+ * its current size: eden shrinks by what the survivor spaces gain.
+ */
+
+func calculateSurvivorModel(events []*GCEvent, analysis *GCAnalysis) SurvivorModel {
+	var model SurvivorModel
+	for _, event := range events {
+		model.FromAgeTable = model.FromAgeTable || event.AgeTableLogged
+	}
+
+	var demands, capacities, youngSizes []utils.MemorySize
+	var thresholds []int
+	var regionSize utils.MemorySize
+	overflows, lowered := 0, 0
+	for _, event := range events {
+		if gcType := CategorizeGCType(event.Type); gcType != GCTypeYoung && gcType != GCTypeMixed {
+			continue
+		}
+		if event.MaxTenuringThreshold > 0 {
+			thresholds = append(thresholds, event.TenuringThreshold)
+			model.MaxThreshold = max(model.MaxThreshold, event.MaxTenuringThreshold)
+			if event.TenuringThreshold < event.MaxTenuringThreshold {
+				lowered++
+			}
+		}
+
+		capacity, youngSize, eventRegionSize := survivorSpace(event, analysis.HeapRegionSize)
+		regionSize = max(regionSize, eventRegionSize)
+		if capacity == 0 || (model.FromAgeTable && !event.AgeTableLogged) {
+			continue
+		}
+		used := event.SurvivorMemoryAfter
+		if model.FromAgeTable {
+			used = event.AgeTableTotal
+		}
+
+		demand := used
+		if survivorOverflowed(event, used, capacity) {
+			overflows++
+			demand = max(used, capacity) + max(event.OldMemoryAfter-event.OldMemoryBefore, 0)
+		}
+		demands = append(demands, demand)
+		capacities = append(capacities, capacity)
+		if youngSize > 0 {
+			youngSizes = append(youngSizes, youngSize)
+		}
+	}
+
+	if len(thresholds) > 0 {
+		slices.Sort(thresholds)
+		model.MedianThreshold = thresholds[len(thresholds)/2]
+		model.LoweredThresholdRate = float64(lowered) / float64(len(thresholds))
+	}
+	if len(demands) < MinSurvivorSamples {
+		return model
+	}
+
+	slices.Sort(demands)
+	model.Samples = len(demands)
+	model.DemandP50 = utils.MemorySize(utils.CalculatePercentile(demands, 50))
+	model.DemandP95 = utils.MemorySize(utils.CalculatePercentile(demands, 95))
+	model.DemandMax = demands[len(demands)-1]
+	model.Capacity = median(capacities)
+	model.OverflowRate = float64(overflows) / float64(len(demands))
+
+	// A G1 survivor space is whole regions
+	suggested := utils.MemorySize(utils.CalculatePercentile(demands, (1-SurvivorOverflowGoal)*100))
+	if regionSize > 0 {
+		suggested = utils.MemorySize(math.Ceil(float64(suggested)/float64(regionSize))) * regionSize
+	}
+	model.SuggestedSize = max(suggested, 1)
+
+	over := 0
+	for _, demand := range demands {
+		if demand > model.SuggestedSize {
+			over++
+		}
+	}
+	model.ExpectedOverflow = float64(over) / float64(len(demands))
+
+	if len(youngSizes) > 0 {
+		young := median(youngSizes)
+		model.CurrentRatio = survivorRatio(young, model.Capacity, regionSize > 0)
+		if model.SuggestedSize > model.Capacity {
+			model.SuggestedRatio = survivorRatio(young, model.SuggestedSize, regionSize > 0)
+		}
+	}
+	return model
+}
+
+// Undersized is whether survivors overflow more often than a resize to SuggestedSize would let them
+func (model SurvivorModel) Undersized() bool {
+	return model.OverflowRate > SurvivorOverflowGoal && model.SuggestedSize > model.Capacity
+}
+
+/*
+ * survivorSpace is the survivor space a collection could fill, and the young
+ * generation it sits in when the log shows it:
+ *
+ *   - G1: the survivor region target, in a young generation of the eden
+ *     target plus the survivors. A log without the region size still gives
+ *     it: the desired survivor size is TargetSurvivorRatio of the target
+ *   - Parallel and Serial: one survivor space, in eden plus two of them
+ *   - otherwise the desired survivor size the age line gives, at the default
+ *     TargetSurvivorRatio; the young generation is unknown
+ */
+func survivorSpace(event *GCEvent, regionSize utils.MemorySize) (capacity, young, g1RegionSize utils.MemorySize) {
+	if event.RegionSize > 0 {
+		regionSize = event.RegionSize
+	}
+	if regionSize == 0 && event.SurvivorRegionsTarget > 0 {
+		regionSize = event.DesiredSurvivorSize * 100 / DefaultTargetSurvivorRatio / utils.MemorySize(event.SurvivorRegionsTarget)
+	}
+
+	switch {
+	case event.SurvivorRegionsTarget > 0 && regionSize > 0:
+		capacity = utils.MemorySize(event.SurvivorRegionsTarget) * regionSize
+		young = utils.MemorySize(event.EdenRegionsTarget+event.SurvivorRegionsAfter) * regionSize
+		g1RegionSize = regionSize
+	case event.SurvivorCapacity > 0:
+		capacity = event.SurvivorCapacity
+		young = event.EdenCapacity + 2*event.SurvivorCapacity
+	case event.DesiredSurvivorSize > 0:
+		capacity = event.DesiredSurvivorSize * 100 / DefaultTargetSurvivorRatio
+	}
+	return capacity, young, g1RegionSize
+}
+
+// survivorOverflowed is whether the collection's survivors filled the space; G1 counts regions
+func survivorOverflowed(event *GCEvent, used, capacity utils.MemorySize) bool {
+	if event.SurvivorRegionsTarget > 0 {
+		return event.HasSurvivorOverflow
+	}
+	return float64(used) >= SurvivorFullShare*float64(capacity)
+}
+
+/*
+ * survivorRatio is the -XX:SurvivorRatio that gives a survivor space of
+ * survivor in a young generation of young. G1 caps survivors at the young
+ * length over the ratio; the other collectors split the young generation
+ * into eden and two survivor spaces, with eden ratio times one of them.
+ */
+func survivorRatio(young, survivor utils.MemorySize, g1 bool) int {
+	if survivor <= 0 || young <= 0 {
+		return 0
+	}
+	ratio := int(young / survivor)
+	if !g1 {
+		ratio -= 2
+	}
+	return max(ratio, 1)
+}
+
+func median(sizes []utils.MemorySize) utils.MemorySize {
+	sorted := slices.Clone(sizes)
+	slices.Sort(sorted)
+	return sorted[len(sorted)/2]
+}
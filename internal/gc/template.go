@@ -0,0 +1,63 @@
+package gc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// TemplateData is what a --template sees as "."; fields follow the JSON report
+type TemplateData struct {
+	File   string
+	Report *Report
+	Events []ReportEvent
+}
+
+func NewTemplateData(file string, events []*GCEvent, analysis *GCAnalysis, issues *GCIssues) *TemplateData {
+	return &TemplateData{
+		File:   file,
+		Report: NewReport(analysis, issues),
+		Events: NewReportEvents(events),
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"bytes": utils.FormatBytes,
+	"ms": func(ms float64) string {
+		return utils.FormatDuration(time.Duration(ms * float64(time.Millisecond)))
+	},
+	"pct": utils.FormatPercent,
+	"csv": func(value string) string {
+		if strings.ContainsAny(value, ",\"\n") {
+			return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+		}
+		return value
+	},
+}
+
+// ParseTemplateFile loads a text/template so syntax errors surface before any analysis runs
+func ParseTemplateFile(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (data *TemplateData) Render(tmpl *template.Template, w io.Writer) error {
+	return tmpl.Execute(w, data)
+}
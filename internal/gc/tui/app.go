@@ -2,13 +2,16 @@ package tui
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/mabhi256/jdiag/internal/gc"
 	"github.com/mabhi256/jdiag/utils"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const PageSize = 10 // Number of lines to scroll per page
@@ -19,12 +22,14 @@ func initialModel(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIs
 	selectedIssue[CriticalIssues] = 0
 	selectedIssue[WarningIssues] = 0
 	selectedIssue[InfoIssues] = 0
+	selectedIssue[TuningPlanIssues] = 0
 
 	return &Model{
 		currentTab:      DashboardTab,
 		events:          events,
 		analysis:        analysis,
 		issues:          issues,
+		plan:            gc.NewTuningPlan(analysis, issues),
 		scrollPositions: make(map[TabType]int),
 		metricsSubTab:   GeneralMetrics,
 		issuesState: &IssuesState{
@@ -39,10 +44,8 @@ func initialModel(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIs
 			searchTerm:    "",
 			showDetails:   true,
 		},
-		trendsState: &TrendsState{
-			trendSubTab: HeapAfterTrend,
-			timeWindow:  100,
-		},
+		trendsState: newTrendsState(len(events)),
+		cache:       newRenderCache(),
 	}
 }
 
@@ -56,57 +59,131 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case regionFrameMsg:
+		return m, m.stepRegionPlayback(msg)
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
+		m.statusMessage = ""
+		if m.noteInput != nil {
+			return m.handleNoteKeys(msg)
+		}
 
-		case "tab":
-			// Cycle through tabs: Dashboard -> Metrics -> Issues -> Dashboard
-			switch m.currentTab {
-			case DashboardTab:
-				m.currentTab = MetricsTab
-			case MetricsTab:
-				m.currentTab = IssuesTab
-			case IssuesTab:
-				m.currentTab = EventsTab
-			case EventsTab:
-				m.currentTab = TrendsTab
-			case TrendsTab:
-				m.currentTab = DashboardTab
-			}
+		action := keymap.Resolve(msg, m.activeScopes(), &m.pendingKey)
+		if m.showHelp && action != ActionQuit {
+			// Any key closes the help overlay
+			m.showHelp = false
+			m.pendingKey = ""
+			return m, nil
+		}
 
-		case "1":
+		switch action {
+		case ActionQuit:
+			return m, tea.Quit
+		case ActionHelp:
+			m.showHelp = true
+
+		case ActionExportText:
+			m.exportView(utils.SnapshotText)
+		case ActionExportANSI:
+			m.exportView(utils.SnapshotANSI)
+		case ActionExportSVG:
+			m.exportView(utils.SnapshotSVG)
+
+		case ActionBookmark:
+			m.toggleBookmark()
+		case ActionNote:
+			m.startNote()
+
+		case ActionNextTab:
+			// Cycle through tabs: Dashboard -> Metrics -> Issues -> Events -> Trends -> Dashboard
+			utils.CycleEnumPtr(&m.currentTab, 1, TrendsTab)
+		case ActionPrevTab:
+			utils.CycleEnumPtr(&m.currentTab, -1, TrendsTab)
+
+		case ActionTab1:
 			m.currentTab = DashboardTab
-		case "2":
+		case ActionTab2:
 			m.currentTab = MetricsTab
-		case "3":
+		case ActionTab3:
 			m.currentTab = IssuesTab
-		case "4":
+		case ActionTab4:
 			m.currentTab = EventsTab
-		case "5":
+		case ActionTab5:
 			m.currentTab = TrendsTab
 
-		case "left", "h":
+		case ActionLeft:
 			return m.handleHorizontalNavigation(-1)
-		case "right", "l":
+		case ActionRight:
 			return m.handleHorizontalNavigation(1)
 
+		case "":
+			return m, nil
+
 		default:
 			// Forward to tab-specific handlers for up/down and other keys
-			return m.handleTabSpecificKeys(msg)
+			return m.handleTabSpecificKeys(action)
 		}
 	}
 
 	return m, nil
 }
 
+// activeScopes are the keymap scopes of the current view, next to the general ones
+func (m *Model) activeScopes() []string {
+	switch m.currentTab {
+	case IssuesTab:
+		return []string{ScopeIssues}
+	case EventsTab:
+		if m.eventsState.detailView {
+			return []string{ScopeEventDetail, ScopeBookmarks}
+		}
+		return []string{ScopeEvents, ScopeBookmarks}
+	case TrendsTab:
+		return []string{ScopeTrends, ScopeBookmarks}
+	}
+	return nil
+}
+
+// verticalStep is how far an up/down style action moves a list or a scroll position
+func (m *Model) verticalStep(action Action) (int, bool) {
+	switch action {
+	case ActionUp:
+		return -1, true
+	case ActionDown:
+		return 1, true
+	case ActionPageUp:
+		return -PageSize, true
+	case ActionPageDown:
+		return PageSize, true
+	case ActionTop:
+		return math.MinInt / 2, true
+	case ActionBottom:
+		return math.MaxInt / 2, true
+	}
+	return 0, false
+}
+
+// moveSelection moves a list selection by step, staying on the list
+func moveSelection(selected *int, step, count int) {
+	*selected = max(min(*selected+step, count-1), 0)
+}
+
+// scrolled is a scroll position moved by step; the renderer bounds it at the bottom
+func scrolled(position, step int) int {
+	return max(position+step, 0)
+}
+
 func (m *Model) handleHorizontalNavigation(direction int) (tea.Model, tea.Cmd) {
 	switch m.currentTab {
 	case MetricsTab:
 		utils.CycleEnumPtr(&m.metricsSubTab, direction, ConcurrentMetrics)
 	case IssuesTab:
-		utils.CycleEnumPtr(&m.issuesState.selectedSubTab, direction, InfoIssues)
+		utils.CycleEnumPtr(&m.issuesState.selectedSubTab, direction, TuningPlanIssues)
+	case EventsTab:
+		if m.eventsState.detailView {
+			m.stepEventDetail(direction)
+		}
+		return m, nil
 	case TrendsTab:
 		utils.CycleEnumPtr(&m.trendsState.trendSubTab, direction, FrequencyTrend)
 	default:
@@ -118,113 +195,159 @@ func (m *Model) handleHorizontalNavigation(direction int) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) handleTabSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m *Model) handleTabSpecificKeys(action Action) (tea.Model, tea.Cmd) {
 	switch m.currentTab {
-	case DashboardTab:
-		return m.handleDashboardKeys(msg)
-	case MetricsTab:
-		return m.handleMetricsKeys(msg)
+	case DashboardTab, MetricsTab:
+		if step, ok := m.verticalStep(action); ok {
+			// Bounded in rendering
+			m.scrollPositions[m.currentTab] = scrolled(m.scrollPositions[m.currentTab], step)
+		}
 	case IssuesTab:
-		return m.handleIssuesKeys(msg)
+		return m.handleIssuesKeys(action)
 	case EventsTab:
-		return m.handleEventsKeys(msg)
+		return m.handleEventsKeys(action)
 	case TrendsTab:
-		return m.handleTrendsKeys(msg)
+		return m.handleTrendsKeys(action)
 	}
 
 	return m, nil
 }
 
-func (m *Model) handleDashboardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.scrollPositions[DashboardTab] > 0 {
-			m.scrollPositions[DashboardTab]--
+func (m *Model) handleIssuesKeys(action Action) (tea.Model, tea.Cmd) {
+	state := m.issuesState
+	currentSubTab := state.selectedSubTab
+
+	if step, ok := m.verticalStep(action); ok {
+		selected := state.selectedIssueMap[currentSubTab]
+		moveSelection(&selected, step, m.issuesListLength())
+		state.selectedIssueMap[currentSubTab] = selected
+		return m, nil
+	}
+
+	switch action {
+	case ActionExpand:
+		key := IssueKey{
+			SubTab: currentSubTab,
+			ID:     state.selectedIssueMap[currentSubTab],
 		}
-	case "down", "j":
-		m.scrollPositions[DashboardTab]++
+		state.expandedIssues[key] = !state.expandedIssues[key]
+	case ActionCopyFlags:
+		m.copyFlagLine()
 	}
 	return m, nil
 }
 
-func (m *Model) handleMetricsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.scrollPositions[MetricsTab] > 0 {
-			m.scrollPositions[MetricsTab]--
-		}
-	case "down", "j":
-		// Will be bounded in rendering
-		m.scrollPositions[MetricsTab]++
+// issuesListLength is the number of rows in the current issues sub-tab
+func (m *Model) issuesListLength() int {
+	if m.issuesState.selectedSubTab == TuningPlanIssues {
+		return len(m.plan.Changes)
 	}
-	return m, nil
+	return len(m.GetSubTabIssues())
 }
 
-func (m *Model) handleIssuesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	state := m.issuesState
-	selectedIssueMap := state.selectedIssueMap
-	expandedIssues := state.expandedIssues
-	currentSubTab := state.selectedSubTab
+func (m *Model) handleEventsKeys(action Action) (tea.Model, tea.Cmd) {
+	filteredEvents := m.eventRows()
 
-	subTabIssues := m.GetSubTabIssues()
-	selectedIssue := selectedIssueMap[currentSubTab]
+	if m.eventsState.detailView {
+		return m.handleEventDetailKeys(action)
+	}
 
-	switch msg.String() {
-	case "up", "k":
-		if selectedIssue > 0 {
-			selectedIssueMap[currentSubTab] = selectedIssue - 1
-		}
-	case "down", "j":
-		if selectedIssue < len(subTabIssues)-1 {
-			selectedIssueMap[currentSubTab] = selectedIssue + 1
-		}
-	case "enter", " ":
-		key := IssueKey{
-			SubTab: currentSubTab,
-			ID:     selectedIssue,
+	if step, ok := m.verticalStep(action); ok {
+		moveSelection(&m.eventsState.selectedEvent, step, len(filteredEvents))
+		return m, nil
+	}
+
+	switch action {
+	case ActionFilter:
+		utils.CycleEnumPtr(&m.eventsState.eventFilter, 1, ConcurrentAbort)
+	case ActionSort:
+		utils.CycleEnumPtr(&m.eventsState.sortBy, 1, TypeSortEvent)
+	case ActionDetails:
+		if len(filteredEvents) > 0 {
+			m.eventsState.detailView = true
+			m.eventsState.detailScroll = 0
 		}
-		expandedIssues[key] = !expandedIssues[key]
 	}
 	return m, nil
 }
 
-func (m *Model) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	filteredEvents := m.getFilteredEvents()
+func (m *Model) handleEventDetailKeys(action Action) (tea.Model, tea.Cmd) {
+	if step, ok := m.verticalStep(action); ok {
+		// Bounded in rendering
+		m.eventsState.detailScroll = scrolled(m.eventsState.detailScroll, step)
+		return m, nil
+	}
 
-	switch msg.String() {
-	case "up", "k":
-		if m.eventsState.selectedEvent > 0 {
-			m.eventsState.selectedEvent--
-		}
-	case "down", "j":
-		if m.eventsState.selectedEvent < len(filteredEvents)-1 {
-			m.eventsState.selectedEvent++
-		}
-	case "f":
-		utils.CycleEnumPtr(&m.eventsState.eventFilter, 1, ConcurrentAbort)
-	case "s":
-		utils.CycleEnumPtr(&m.eventsState.sortBy, 1, TypeSortEvent)
+	switch action {
+	case ActionBack:
+		m.eventsState.detailView = false
+	case ActionNextEvent:
+		m.stepEventDetail(1)
+	case ActionPrevEvent:
+		m.stepEventDetail(-1)
 	}
 	return m, nil
 }
 
-func (m *Model) handleTrendsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.scrollPositions[TrendsTab] > 0 {
-			m.scrollPositions[TrendsTab]--
-		}
-	case "down", "j":
-		m.scrollPositions[TrendsTab]++
-	case "+":
-		// Increase time window
-		if m.trendsState.timeWindow < 1000 {
-			m.trendsState.timeWindow += 50
-		}
-	case "-":
-		// Decrease time window
-		if m.trendsState.timeWindow > 50 {
-			m.trendsState.timeWindow -= 50
+// stepEventDetail moves to the next or previous event in the table's filter and sort order
+func (m *Model) stepEventDetail(direction int) {
+	next := m.eventsState.selectedEvent + direction
+	if next >= 0 && next < len(m.eventRows()) {
+		m.eventsState.selectedEvent = next
+		m.eventsState.detailScroll = 0
+	}
+}
+
+func (m *Model) handleTrendsKeys(action Action) (tea.Model, tea.Cmd) {
+	switch action {
+	case ActionUp, ActionDown, ActionPageUp, ActionPageDown:
+		step, _ := m.verticalStep(action)
+		m.scrollPositions[TrendsTab] = scrolled(m.scrollPositions[TrendsTab], step)
+		return m, nil
+	}
+
+	if len(m.events) == 0 {
+		return m, nil
+	}
+
+	switch action {
+	case ActionZoomIn:
+		m.zoomTimeline(1 / ZoomFactor)
+	case ActionZoomOut:
+		m.zoomTimeline(ZoomFactor)
+	case ActionPanLeft:
+		m.panTimeline(-1)
+	case ActionPanRight:
+		m.panTimeline(1)
+	case ActionCursorPrev:
+		m.moveTimelineCursor(-1)
+	case ActionCursorNext:
+		m.moveTimelineCursor(1)
+	case ActionJumpPrev:
+		m.moveTimelineCursor(-CursorJump)
+	case ActionJumpNext:
+		m.moveTimelineCursor(CursorJump)
+	case ActionTop:
+		m.moveTimelineCursor(-m.trendsState.cursor)
+	case ActionBottom:
+		m.moveTimelineCursor(len(m.events) - 1 - m.trendsState.cursor)
+	case ActionBrush:
+		m.toggleBrush()
+	case ActionZoomBrush:
+		m.zoomToBrush()
+	case ActionClearBrush:
+		m.trendsState.brushStart = -1
+	case ActionResetView:
+		m.resetTimeline()
+	case ActionWindow:
+		m.cycleTimeWindow()
+	case ActionNextMark:
+		m.jumpToBookmark(1)
+	case ActionPrevMark:
+		m.jumpToBookmark(-1)
+	case ActionPlay:
+		if m.trendsState.trendSubTab == RegionMapTrend {
+			return m, m.toggleRegionPlayback()
 		}
 	}
 	return m, nil
@@ -261,6 +384,10 @@ func (m *Model) getFilteredEvents() []*gc.GCEvent {
 }
 
 func (m *Model) View() string {
+	return utils.ToASCII(m.render())
+}
+
+func (m *Model) render() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
@@ -268,27 +395,19 @@ func (m *Model) View() string {
 	var content string
 
 	// Calculate available height for content (header + content + shortcuts)
-	headerHeight := 2 // tab line + border
+	headerHeight := 3 // tab line + pulse strip + border
 	shortcutsHeight := 1
 	contentHeight := m.height - headerHeight - shortcutsHeight
 
-	// Render current Tab
-	switch m.currentTab {
-	case DashboardTab:
-		content = m.RenderDashboard()
-	case MetricsTab:
-		content = m.RenderMetrics()
-	case IssuesTab:
-		content = m.RenderIssues()
-	case EventsTab:
-		content = m.RenderEvents()
-	case TrendsTab:
-		content = m.RenderTrends()
+	content = m.renderTab()
+	if m.showHelp {
+		content = m.renderHelp(contentHeight)
 	}
 
 	// Create a style that ensures content takes up exactly the available height
 	contentStyle := lipgloss.NewStyle().
 		Height(contentHeight).
+		MaxHeight(contentHeight).
 		Width(m.width)
 	content = contentStyle.Render(content)
 
@@ -303,6 +422,55 @@ func (m *Model) View() string {
 	)
 }
 
+func (m *Model) renderTab() string {
+	switch m.currentTab {
+	case DashboardTab:
+		return m.RenderDashboard()
+	case MetricsTab:
+		return m.RenderMetrics()
+	case IssuesTab:
+		return m.RenderIssues()
+	case EventsTab:
+		return m.RenderEvents()
+	case TrendsTab:
+		return m.RenderTrends()
+	}
+	return ""
+}
+
+// exportView writes the tab bar and current tab to the working directory
+func (m *Model) exportView(format utils.SnapshotFormat) {
+	view := utils.ToASCII(lipgloss.JoinVertical(lipgloss.Left, m.renderHeader(), m.renderTab()))
+	path, err := utils.WriteSnapshot(".", "gc-"+m.currentTab.String(), view, format)
+	if err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+	m.statusMessage = "✅ Saved " + path
+}
+
+// TabView is one tab rendered as exportView saves it
+type TabView struct {
+	Name string // "summary", "metrics", ...
+	View string
+}
+
+// RenderTabs renders every tab at the given size without opening the TUI, for exporting them all at once
+func RenderTabs(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues, width, height int) []TabView {
+	m := initialModel(events, analysis, issues)
+	m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+
+	var views []TabView
+	for tab := DashboardTab; tab <= TrendsTab; tab++ {
+		m.currentTab = tab
+		views = append(views, TabView{
+			Name: tab.String(),
+			View: utils.ToASCII(lipgloss.JoinVertical(lipgloss.Left, m.renderHeader(), m.renderTab())),
+		})
+	}
+	return views
+}
+
 func (m *Model) renderHeader() string {
 	// Enhanced tab navigation with better visual indicators
 	tabs := []string{}
@@ -320,6 +488,10 @@ func (m *Model) renderHeader() string {
 		}
 
 		tabText := fmt.Sprintf("%s%s %s[%d]", indicator, tabIcons[i], name, i+1)
+		if utils.LayoutFor(m.width) == utils.LayoutNarrow && TabType(i) != m.currentTab {
+			// Only the current tab keeps its name on narrow terminals
+			tabText = fmt.Sprintf("%s%s[%d]", indicator, tabIcons[i], i+1)
+		}
 		tabs = append(tabs, style.Render(tabText))
 	}
 
@@ -329,6 +501,7 @@ func (m *Model) renderHeader() string {
 
 	headerContent := []string{
 		tabLine,
+		m.renderPulse(),
 	}
 
 	headerContent = append(headerContent, border)
@@ -336,37 +509,29 @@ func (m *Model) renderHeader() string {
 	return lipgloss.JoinVertical(lipgloss.Left, headerContent...)
 }
 
-func GetShortcuts(currentTab TabType) string {
-	base := "q:quit • tab:cycle • 1-5:tabs"
-
-	var tabSpecific string
-	switch currentTab {
-	case DashboardTab:
-		tabSpecific = "↑↓:scroll"
-	case MetricsTab:
-		tabSpecific = "↑↓:scroll • ←/→:metrics"
-	case IssuesTab:
-		tabSpecific = "↑↓:nav • ←/→:filter • space/enter:expand"
-	case EventsTab:
-		tabSpecific = "↑↓:nav • f:filter • s:sort"
-	case TrendsTab:
-		tabSpecific = "←/→:view"
+func (m *Model) renderFooter() string {
+	shortcuts := keymap.Shortcuts(m.activeScopes())
+	if m.statusMessage != "" {
+		shortcuts = m.statusMessage
 	}
-
-	if tabSpecific != "" {
-		return base + " • " + tabSpecific
+	if m.noteInput != nil {
+		shortcuts = m.renderNoteInput()
 	}
-	return base
-}
 
-func (m *Model) renderFooter() string {
-	shortcuts := GetShortcuts(m.currentTab)
+	// One line only; the content height leaves no room for a wrapped footer
+	shortcuts = ansi.Truncate(shortcuts, max(m.width-2, 0), "…")
 
 	return utils.HelpBarStyle.Width(m.width).Render(shortcuts)
 }
 
-func StartTUI(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues) error {
+// StartTUI opens the GC log explorer; a window above 0 opens the trends on that span of wall time
+func StartTUI(events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues, bookmarks *gc.Bookmarks, window time.Duration) error {
 	model := initialModel(events, analysis, issues)
+	model.bookmarks = bookmarks
+	model.trendWindow = window
+	if window > 0 && len(events) > 0 {
+		model.setTimeWindow(window)
+	}
 
 	program := tea.NewProgram(
 		model,
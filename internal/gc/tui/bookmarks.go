@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// MaxNoteLength keeps notes short enough to show next to an event row
+const MaxNoteLength = 60
+
+// bookmarkTarget is the event m and a act on: the selected row in events, the cursor in trends
+func (m *Model) bookmarkTarget() *gc.GCEvent {
+	switch m.currentTab {
+	case EventsTab:
+		events := m.eventRows()
+		if len(events) == 0 {
+			return nil
+		}
+		return events[min(m.eventsState.selectedEvent, len(events)-1)]
+	case TrendsTab:
+		if len(m.events) == 0 {
+			return nil
+		}
+		return m.events[m.trendsState.cursor]
+	}
+	return nil
+}
+
+func (m *Model) toggleBookmark() {
+	event := m.bookmarkTarget()
+	if event == nil || m.bookmarks == nil {
+		return
+	}
+
+	if _, ok := m.bookmarks.Get(event); ok {
+		m.bookmarks.Remove(event)
+		m.saveBookmarks(fmt.Sprintf("Removed bookmark on GC(%d)", event.ID))
+	} else {
+		m.bookmarks.Set(event, "")
+		m.saveBookmarks(fmt.Sprintf("◆ Bookmarked GC(%d) • %s:add a note", event.ID, keymap.Label(ActionNote)))
+	}
+}
+
+// startNote opens the footer editor on the target event's note
+func (m *Model) startNote() {
+	event := m.bookmarkTarget()
+	if event == nil || m.bookmarks == nil {
+		return
+	}
+	bookmark, _ := m.bookmarks.Get(event)
+	m.noteInput = &NoteInput{event: event, text: bookmark.Note}
+}
+
+func (m *Model) handleNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	input := m.noteInput
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.noteInput = nil
+	case tea.KeyEnter:
+		m.bookmarks.Set(input.event, strings.TrimSpace(input.text))
+		m.noteInput = nil
+		m.saveBookmarks(fmt.Sprintf("◆ Saved note on GC(%d)", input.event.ID))
+	case tea.KeyBackspace:
+		if runes := []rune(input.text); len(runes) > 0 {
+			input.text = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace, tea.KeyRunes:
+		if len([]rune(input.text)) < MaxNoteLength {
+			input.text += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) saveBookmarks(message string) {
+	if err := m.bookmarks.Save(); err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+	m.statusMessage = message
+}
+
+// jumpToBookmark moves the trends cursor to the next or previous bookmarked event
+func (m *Model) jumpToBookmark(direction int) {
+	indices := m.bookmarkIndices()
+	if direction < 0 {
+		slices.Reverse(indices)
+	}
+	for _, index := range indices {
+		if (index-m.trendsState.cursor)*direction > 0 {
+			m.moveTimelineCursor(index - m.trendsState.cursor)
+			return
+		}
+	}
+	m.statusMessage = "No more bookmarks this way"
+}
+
+// bookmarkPrefix marks bookmarked rows in the events table
+func (m *Model) bookmarkPrefix(event *gc.GCEvent) string {
+	if _, ok := m.bookmarks.Get(event); ok {
+		return "◆ "
+	}
+	return "  "
+}
+
+// bookmarkNote is the note of a bookmarked event, styled for the end of a row
+func (m *Model) bookmarkNote(event *gc.GCEvent) string {
+	if bookmark, ok := m.bookmarks.Get(event); ok && bookmark.Note != "" {
+		return "  " + bookmark.Note
+	}
+	return ""
+}
+
+// renderBookmarksInView lists the bookmarks inside the trends window
+func (m *Model) renderBookmarksInView() string {
+	var parts []string
+	for _, index := range m.bookmarkIndices() {
+		if index < m.trendsState.viewStart || index >= m.trendsState.viewEnd {
+			continue
+		}
+		event := m.events[index]
+		bookmark, _ := m.bookmarks.Get(event)
+		part := fmt.Sprintf("GC(%d)", event.ID)
+		if bookmark.Note != "" {
+			part += " " + bookmark.Note
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return utils.CriticalStyle.Render("◆") + " " + utils.MutedStyle.Render(strings.Join(parts, " • "))
+}
+
+func (m *Model) renderNoteInput() string {
+	return fmt.Sprintf("Note for GC(%d): %s█  %s", m.noteInput.event.ID, m.noteInput.text,
+		utils.MutedStyle.Render("enter:save • esc:cancel"))
+}
+
+func (m *Model) eventBookmarkLines(event *gc.GCEvent) []string {
+	bookmark, ok := m.bookmarks.Get(event)
+	if !ok {
+		return nil
+	}
+	if bookmark.Note == "" {
+		return []string{fmt.Sprintf("Bookmarked, press %s to add a note", keymap.Label(ActionNote))}
+	}
+	return []string{"Note: " + bookmark.Note}
+}
@@ -0,0 +1,374 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	// CompareNoise is the change, in percent or percentage points, below which two values count as equal
+	CompareNoise = 2.0
+
+	CompareChromeHeight   = 20 // Header, footer, titles, axes, legend and summaries around the two charts
+	MinCompareChartHeight = 4
+)
+
+type compareMetric struct {
+	name   string
+	better int // 1 when higher is better, -1 when lower is better, 0 when neither
+	points bool
+	value  func(*gc.GCAnalysis) float64
+	format func(float64) string
+}
+
+func durationMetric(name string, f func(*gc.GCAnalysis) time.Duration) compareMetric {
+	return compareMetric{
+		name:   name,
+		better: -1,
+		value:  func(a *gc.GCAnalysis) float64 { return float64(f(a)) },
+		format: func(v float64) string { return utils.FormatDuration(time.Duration(v)) },
+	}
+}
+
+func countMetric(name string, better int, f func(*gc.GCAnalysis) int) compareMetric {
+	return compareMetric{
+		name:   name,
+		better: better,
+		value:  func(a *gc.GCAnalysis) float64 { return float64(f(a)) },
+		format: func(v float64) string { return utils.FormatCount(int64(v)) },
+	}
+}
+
+var compareMetrics = []compareMetric{
+	{
+		name: "Throughput", better: 1, points: true,
+		value:  func(a *gc.GCAnalysis) float64 { return a.Throughput },
+		format: utils.FormatPercent,
+	},
+	durationMetric("Total GC Time", func(a *gc.GCAnalysis) time.Duration { return a.TotalGCTime }),
+	durationMetric("Avg Pause", func(a *gc.GCAnalysis) time.Duration { return a.AvgPause }),
+	durationMetric("P95 Pause", func(a *gc.GCAnalysis) time.Duration { return a.P95Pause }),
+	durationMetric("P99 Pause", func(a *gc.GCAnalysis) time.Duration { return a.P99Pause }),
+	durationMetric("Max Pause", func(a *gc.GCAnalysis) time.Duration { return a.MaxPause }),
+	countMetric("Long Pauses", -1, func(a *gc.GCAnalysis) int { return a.LongPauseCount }),
+	{
+		name: "Pause Target Misses", better: -1, points: true,
+		value:  func(a *gc.GCAnalysis) float64 { return a.PauseTargetMissRate * 100 },
+		format: utils.FormatPercent,
+	},
+	countMetric("Events", 0, func(a *gc.GCAnalysis) int { return a.TotalEvents }),
+	countMetric("Young GCs", 0, func(a *gc.GCAnalysis) int { return a.YoungGCCount }),
+	countMetric("Mixed GCs", 0, func(a *gc.GCAnalysis) int { return a.MixedGCCount }),
+	countMetric("Full GCs", -1, func(a *gc.GCAnalysis) int { return a.FullGCCount }),
+	countMetric("Evacuation Failures", -1, func(a *gc.GCAnalysis) int { return a.EvacuationFailureCount }),
+	{
+		name: "Allocation Rate", better: 0,
+		value:  func(a *gc.GCAnalysis) float64 { return a.AllocationRate },
+		format: func(v float64) string { return utils.FormatMB(v) + "/s" },
+	},
+	{
+		name: "Avg Heap Utilization", better: -1, points: true,
+		value:  func(a *gc.GCAnalysis) float64 { return a.AvgHeapUtil * 100 },
+		format: utils.FormatPercent,
+	},
+	{
+		name: "Runtime", better: 0,
+		value:  func(a *gc.GCAnalysis) float64 { return float64(a.TotalRuntime) },
+		format: func(v float64) string { return utils.FormatDuration(time.Duration(v)) },
+	},
+}
+
+func newCompareModel(baseline, candidate CompareSide) *CompareModel {
+	return &CompareModel{baseline: baseline, candidate: candidate}
+}
+
+func (m *CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		m.statusMessage = ""
+		switch action := keymap.Resolve(msg, nil, &m.pendingKey); action {
+		case ActionQuit:
+			return m, tea.Quit
+		case ActionNextTab, ActionRight:
+			utils.CycleEnumPtr(&m.view, 1, CompareReclaimedView)
+			m.scroll = 0
+		case ActionPrevTab, ActionLeft:
+			utils.CycleEnumPtr(&m.view, -1, CompareReclaimedView)
+			m.scroll = 0
+		case ActionTab1, ActionTab2, ActionTab3, ActionTab4, ActionTab5:
+			m.view = CompareView(action[len(action)-1] - '1')
+			m.scroll = 0
+		case ActionUp:
+			m.scroll = max(m.scroll-1, 0)
+		case ActionDown:
+			m.scroll++
+		case ActionPageUp:
+			m.scroll = max(m.scroll-PageSize, 0)
+		case ActionPageDown:
+			m.scroll += PageSize
+		case ActionTop:
+			m.scroll = 0
+		case ActionBottom:
+			// Bounded in rendering
+			m.scroll = math.MaxInt / 2
+		case ActionExportText:
+			m.exportView(utils.SnapshotText)
+		case ActionExportANSI:
+			m.exportView(utils.SnapshotANSI)
+		case ActionExportSVG:
+			m.exportView(utils.SnapshotSVG)
+		}
+	}
+	return m, nil
+}
+
+func (m *CompareModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	header := m.renderHeader()
+	footer := m.renderFooter()
+	contentHeight := max(m.height-lipgloss.Height(header)-lipgloss.Height(footer), 1)
+
+	lines := strings.Split(m.renderView(), "\n")
+	m.scroll = min(m.scroll, max(len(lines)-contentHeight, 0))
+	lines = lines[m.scroll:min(m.scroll+contentHeight, len(lines))]
+	content := lipgloss.NewStyle().Height(contentHeight).Width(m.width).Render(strings.Join(lines, "\n"))
+
+	return utils.ToASCII(lipgloss.JoinVertical(lipgloss.Left, header, content, footer))
+}
+
+func (m *CompareModel) renderHeader() string {
+	var tabs []string
+	for view := CompareStatsView; view <= CompareReclaimedView; view++ {
+		style := utils.TabInactiveStyle
+		if view == m.view {
+			style = utils.TabActiveStyle
+		}
+		tabs = append(tabs, style.Render(fmt.Sprintf("%s[%d]", view, view+1)))
+	}
+
+	sides := fmt.Sprintf("%s %s (%d events)  vs  %s %s (%d events)",
+		utils.MutedStyle.Render("Baseline:"), m.baseline.Name, len(m.baseline.Events),
+		utils.MutedStyle.Render("Candidate:"), m.candidate.Name, len(m.candidate.Events))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		strings.Join(tabs, " "),
+		strings.Repeat("─", m.width),
+		sides,
+		"")
+}
+
+func (m *CompareModel) renderFooter() string {
+	shortcuts := fmt.Sprintf("%s:quit • %s %s:view • 1-5:views • %s %s:scroll • %s:export",
+		keymap.Label(ActionQuit), keymap.Label(ActionNextTab), keymap.Label(ActionRight),
+		keymap.Label(ActionUp), keymap.Label(ActionDown), keymap.Label(ActionExportText))
+	if m.statusMessage != "" {
+		shortcuts = m.statusMessage
+	}
+	return utils.HelpBarStyle.Width(m.width).Render(shortcuts)
+}
+
+func (m *CompareModel) renderView() string {
+	switch m.view {
+	case ComparePauseView:
+		return m.renderSeries("GC Pause Duration", "ms", func(e *gc.GCEvent) float64 {
+			return float64(e.Duration.Nanoseconds()) / 1e6
+		})
+	case CompareHeapAfterView:
+		return m.renderSeries("Heap After GC", "MB", func(e *gc.GCEvent) float64 {
+			return e.HeapAfter.MB()
+		})
+	case CompareHeapBeforeView:
+		return m.renderSeries("Heap Before GC", "MB", func(e *gc.GCEvent) float64 {
+			return e.HeapBefore.MB()
+		})
+	case CompareReclaimedView:
+		return m.renderSeries("Memory Reclaimed after GC", "MB", func(e *gc.GCEvent) float64 {
+			return (e.HeapBefore - e.HeapAfter).MB()
+		})
+	default:
+		return m.renderStats()
+	}
+}
+
+func (m *CompareModel) renderStats() string {
+	row := func(name, baseline, candidate, change string) string {
+		return fmt.Sprintf("%-22s %16s %16s   %s", name, baseline, candidate, change)
+	}
+
+	lines := []string{
+		utils.TitleStyle.Render("Baseline vs Candidate"),
+		"",
+		utils.MutedStyle.Render(row("Metric", "Baseline", "Candidate", "Change")),
+	}
+	for _, metric := range compareMetrics {
+		base, cand := metric.value(m.baseline.Analysis), metric.value(m.candidate.Analysis)
+		lines = append(lines, row(metric.name, metric.format(base), metric.format(cand),
+			compareDelta(base, cand, metric.better, metric.points)))
+	}
+
+	lines = append(lines, "", utils.MutedStyle.Render(fmt.Sprintf(
+		"Changes under %s are shown as ≈. Percentages compare in points (pp).", utils.FormatPercent(CompareNoise))))
+	return strings.Join(lines, "\n")
+}
+
+// compareDelta shows the change from baseline to candidate, green when it improves and red when it regresses
+func compareDelta(base, cand float64, better int, points bool) string {
+	var change float64
+	var text string
+	switch {
+	case points:
+		change = cand - base
+		text = utils.FormatFloat(math.Abs(change)) + "pp"
+	case base == cand:
+		return utils.MutedStyle.Render("=")
+	case base == 0:
+		change, text = math.Inf(1), "new"
+	default:
+		change = (cand - base) / math.Abs(base) * 100
+		text = utils.FormatPercent(math.Abs(change))
+	}
+
+	switch {
+	case math.Abs(change) < CompareNoise:
+		return utils.MutedStyle.Render("≈ " + text)
+	case change > 0:
+		text = "▲ " + text
+	default:
+		text = "▼ " + text
+	}
+
+	switch {
+	case better == 0:
+		return utils.InfoStyle.Render(text)
+	case (change > 0) == (better > 0):
+		return utils.GoodStyle.Render(text)
+	default:
+		return utils.CriticalStyle.Render(text)
+	}
+}
+
+// renderSeries stacks the baseline and candidate charts on one shared scale
+func (m *CompareModel) renderSeries(title, unit string, f func(*gc.GCEvent) float64) string {
+	type series struct {
+		values     []float64
+		timestamps []time.Time
+		gcTypes    []string
+	}
+	extract := func(events []*gc.GCEvent) series {
+		var s series
+		for _, event := range events {
+			if strings.Contains(event.Type, "Concurrent") {
+				continue
+			}
+			s.values = append(s.values, f(event))
+			s.timestamps = append(s.timestamps, event.Timestamp)
+			s.gcTypes = append(s.gcTypes, event.Type)
+		}
+		return s
+	}
+	baseline, candidate := extract(m.baseline.Events), extract(m.candidate.Events)
+	if len(baseline.values) == 0 || len(candidate.values) == 0 {
+		return utils.TitleStyle.Render(title) + "\n\nNo data available"
+	}
+
+	all := slices.Concat(baseline.values, candidate.values)
+	styles := CreateChartStyles()
+	config := utils.ChartConfig{
+		Width:  max(MinChartWidth, m.width-ChartMarginWidth),
+		Height: max((m.height-CompareChromeHeight)/2, MinCompareChartHeight),
+		Styles: styles,
+		MinY:   slices.Min(all),
+		MaxY:   slices.Max(all),
+	}
+
+	baselineChart := utils.CreatePlot(gcDataPoints(baseline.values, baseline.timestamps, baseline.gcTypes, styles), unit, config)
+	config.Legend = CreateGCLegend(styles)
+	candidateChart := utils.CreatePlot(gcDataPoints(candidate.values, candidate.timestamps, candidate.gcTypes, styles), unit, config)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		utils.TitleStyle.Render(title+" — Baseline: "+m.baseline.Name),
+		baselineChart,
+		renderSeriesSummary(baseline.values, nil, unit),
+		"",
+		utils.TitleStyle.Render(title+" — Candidate: "+m.candidate.Name),
+		candidateChart,
+		renderSeriesSummary(candidate.values, baseline.values, unit))
+}
+
+// renderSeriesSummary lists avg/P95/max, with the change from the baseline when given
+func renderSeriesSummary(values, baseline []float64, unit string) string {
+	stats := func(values []float64) []float64 {
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+		sum := 0.0
+		for _, v := range sorted {
+			sum += v
+		}
+		return []float64{sum / float64(len(sorted)), utils.CalculatePercentile(sorted, 95), sorted[len(sorted)-1]}
+	}
+	format := func(v float64) string {
+		if unit == "ms" {
+			return utils.FormatMillis(v)
+		}
+		return utils.FormatMB(v)
+	}
+
+	current := stats(values)
+	var previous []float64
+	if baseline != nil {
+		previous = stats(baseline)
+	}
+
+	var parts []string
+	for i, label := range []string{"avg", "P95", "max"} {
+		part := utils.MutedStyle.Render(label) + " " + format(current[i])
+		if previous != nil {
+			part += " (" + compareDelta(previous[i], current[i], -1, false) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// exportView writes the current comparison view to the working directory
+func (m *CompareModel) exportView(format utils.SnapshotFormat) {
+	view := utils.ToASCII(lipgloss.JoinVertical(lipgloss.Left, m.renderHeader(), m.renderView()))
+	name := "gc-compare-" + strings.ReplaceAll(strings.ToLower(m.view.String()), " ", "-")
+	path, err := utils.WriteSnapshot(".", name, view, format)
+	if err != nil {
+		m.statusMessage = "❌ " + err.Error()
+		return
+	}
+	m.statusMessage = "✅ Saved " + path
+}
+
+// StartCompareTUI opens the side-by-side view of a baseline and a candidate log
+func StartCompareTUI(baseline, candidate CompareSide) error {
+	program := tea.NewProgram(
+		newCompareModel(baseline, candidate),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	_, err := program.Run()
+	return err
+}
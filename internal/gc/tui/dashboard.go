@@ -24,9 +24,7 @@ func (m *Model) RenderDashboard() string {
 			jvmInfo += fmt.Sprintf("  Runtime: %s", utils.FormatDuration(runtime))
 		}
 
-		headerLine := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Render(jvmInfo)
+		headerLine := utils.MutedStyle.Render(jvmInfo)
 		headerContent = append(headerContent, headerLine)
 	} else {
 		// Add empty line to maintain consistent height when JVM info is not available
@@ -36,20 +34,24 @@ func (m *Model) RenderDashboard() string {
 	// Add spacing after JVM info section
 	headerContent = append(headerContent, "")
 
-	// Calculate layout - split into two columns
-	leftWidth := m.width/2 - 2
-	rightWidth := m.width - leftWidth - 6
-
-	leftColumn := renderDashboardLeft(m.analysis, leftWidth)
-	rightColumn := renderDashboardRight(m.analysis, m.issues, rightWidth)
+	// Two columns side by side, stacked on narrow terminals; wide ones add a pause phase panel
+	layout := utils.LayoutFor(m.width)
+	columnWidth := m.width/2 - 2
+	switch layout {
+	case utils.LayoutNarrow:
+		columnWidth = m.width - 2
+	case utils.LayoutWide:
+		columnWidth = m.width/3 - 4
+	}
 
-	// Join columns horizontally
-	columnsContent := lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		leftColumn,
-		"             ", // spacing
-		rightColumn,
-	)
+	columns := []string{
+		renderDashboardLeft(m.analysis, columnWidth),
+		renderDashboardRight(m.analysis, m.issues, columnWidth),
+	}
+	if layout == utils.LayoutWide {
+		columns = append(columns, m.renderDashboardPhases(columnWidth))
+	}
+	columnsContent := utils.Columns(m.width, 6, columns...)
 
 	headerSection := strings.Join(headerContent, "\n")
 	content := lipgloss.JoinVertical(
@@ -58,9 +60,63 @@ func (m *Model) RenderDashboard() string {
 		columnsContent,
 	)
 
+	// Stacked columns can outgrow the screen
+	lines := strings.Split(content, "\n")
+	availableHeight := m.height - 5
+	if len(lines) > availableHeight {
+		scrollY := min(m.scrollPositions[DashboardTab], len(lines)-availableHeight)
+		m.scrollPositions[DashboardTab] = scrollY
+		content = strings.Join(lines[scrollY:scrollY+availableHeight], "\n")
+	}
+
 	return content
 }
 
+// renderDashboardPhases is the wide-layout panel showing where pause time goes
+func (m *Model) renderDashboardPhases(width int) string {
+	return cachedWindow(m, windowKey{view: "dashboard phases", width: width}, func() string {
+		return m.buildDashboardPhases(width)
+	})
+}
+
+func (m *Model) buildDashboardPhases(width int) string {
+	title := utils.TitleStyle.Render("Pause Phases")
+
+	var pauses []*gc.GCEvent
+	for _, index := range m.pauseIndices(0, len(m.events)) {
+		if event := m.events[index]; event.Duration > 0 {
+			pauses = append(pauses, event)
+		}
+	}
+
+	var lines []string
+	for _, group := range []struct {
+		label  string
+		pauses []*gc.GCEvent
+	}{
+		{"All pauses", pauses},
+		{"Slowest 5%", slowestPauses(pauses, 0.05)},
+	} {
+		shares, ok := phaseShares(group.pauses)
+		if !ok {
+			continue
+		}
+		lines = append(lines, "", utils.MutedStyle.Render(fmt.Sprintf("%s (%d)", group.label, len(group.pauses))))
+
+		barWidth := max(width-28, 5)
+		for i, segment := range phaseSegments {
+			cells := int(shares[i]*float64(barWidth) + 0.5)
+			bar := segment.Style.Render(strings.Repeat(segment.Glyph, cells)) + strings.Repeat(" ", barWidth-cells)
+			lines = append(lines, fmt.Sprintf("%-15s %s %s", segment.Label, bar, utils.FormatPercent(shares[i]*100)))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "", utils.MutedStyle.Render("No phase timings in this log"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, strings.Join(lines, "\n"))
+}
+
 func renderDashboardLeft(analysis *gc.GCAnalysis, width int) string {
 	sections := []string{
 		renderPerformanceOverview(analysis),
@@ -95,7 +151,7 @@ func renderPerformanceOverview(analysis *gc.GCAnalysis) string {
 		}
 		throughputRow := fmt.Sprintf("%-15s %s %s",
 			"Throughput",
-			fmt.Sprintf("%.1f%%", analysis.Throughput),
+			utils.FormatPercent(analysis.Throughput),
 			status)
 		rows = append(rows, throughputRow)
 	}
@@ -110,7 +166,7 @@ func renderPerformanceOverview(analysis *gc.GCAnalysis) string {
 		}
 		p99Row := fmt.Sprintf("%-15s %s %s",
 			"P99 Pause",
-			fmt.Sprintf("%.1fms", p99Ms),
+			utils.FormatMillis(p99Ms),
 			status)
 		rows = append(rows, p99Row)
 	}
@@ -119,13 +175,13 @@ func renderPerformanceOverview(analysis *gc.GCAnalysis) string {
 	avgMs := float64(analysis.AvgPause.Nanoseconds()) / 1000000
 	avgRow := fmt.Sprintf("%-15s %-12s",
 		"Avg Pause",
-		fmt.Sprintf("%.1fms", avgMs))
+		utils.FormatMillis(avgMs))
 	rows = append(rows, avgRow)
 
 	// Allocation Rate - only show status if high
 	allocRow := fmt.Sprintf("%-15s %s",
 		"Allocation",
-		fmt.Sprintf("%.0f MB/s", analysis.AllocationRate))
+		fmt.Sprintf("%s/s", utils.Precision(0).MB(analysis.AllocationRate)))
 	if analysis.AllocationRate > 100 {
 		status := "⚠️"
 		if analysis.AllocationRate > 500 {
@@ -270,8 +326,8 @@ func renderMemoryPressure(analysis *gc.GCAnalysis, width int) string {
 		heapStatus = "⚠️"
 	}
 
-	heapLine := fmt.Sprintf("Heap     %s %.0f%% %s",
-		heapBar, analysis.AvgHeapUtil*100, heapStatus)
+	heapLine := fmt.Sprintf("Heap     %s %s %s",
+		heapBar, utils.Precision(0).Percent(analysis.AvgHeapUtil*100), heapStatus)
 	lines = append(lines, heapLine)
 
 	// Region Utilization (if available)
@@ -292,19 +348,19 @@ func renderMemoryPressure(analysis *gc.GCAnalysis, width int) string {
 			regionStatus = "⚠️"
 		}
 
-		regionLine := fmt.Sprintf("Regions  %s %.0f%% %s",
-			regionBar, analysis.AvgRegionUtilization*100, regionStatus)
+		regionLine := fmt.Sprintf("Regions  %s %s %s",
+			regionBar, utils.Precision(0).Percent(analysis.AvgRegionUtilization*100), regionStatus)
 		lines = append(lines, regionLine)
 	}
 
 	// Allocation Rate indicator
-	allocLine := fmt.Sprintf("Alloc Rate: %.0f MB/s", analysis.AllocationRate)
+	allocLine := fmt.Sprintf("Alloc Rate: %s/s", utils.Precision(0).MB(analysis.AllocationRate))
 	lines = append(lines, "")
 	lines = append(lines, allocLine)
 
 	// Evacuation Failures
 	if analysis.EvacuationFailureRate > 0 {
-		evacLine := fmt.Sprintf("Evac Failures: %.1f%%", analysis.EvacuationFailureRate*100)
+		evacLine := fmt.Sprintf("Evac Failures: %s", utils.FormatPercent(analysis.EvacuationFailureRate*100))
 		if analysis.EvacuationFailureRate > 0.01 {
 			evacLine = utils.CriticalStyle.Render(evacLine)
 		} else {
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// phaseTiming is one G1 evacuation phase; a zero target means G1 has no guideline for it
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+	target   time.Duration
+}
+
+// RenderEventDetail shows every parsed field of the selected event, scrolled like the metrics tab
+func (m *Model) RenderEventDetail(events []*gc.GCEvent) string {
+	index := min(m.eventsState.selectedEvent, len(events)-1)
+	event := events[index]
+
+	position := utils.MutedStyle.Render(fmt.Sprintf("Event %d/%d", index+1, len(events)))
+	header := lipgloss.JoinHorizontal(lipgloss.Top,
+		utils.TitleStyle.Render(eventTitle(event)), "  ", position)
+
+	sections := []string{
+		renderSection("◆ Bookmark", m.eventBookmarkLines(event)),
+		renderSection("📋 Overview", eventOverviewLines(event, m.analysis)),
+		renderSection("💾 Heap", eventHeapLines(event)),
+		renderSection("🧱 Regions", eventRegionLines(event)),
+		renderSection("⏱️ Phases", eventPhaseLines(event)),
+		renderSection("⚙️ CPU", eventCPULines(event)),
+		renderSection("📚 Metaspace", eventMetaspaceLines(event)),
+		renderSection("❗ Issues", m.eventIssueLines(event)),
+	}
+
+	var nonEmpty []string
+	for _, section := range sections {
+		if section != "" {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+	content := strings.Join(nonEmpty, "\n\n")
+
+	contentLines := strings.Split(content, "\n")
+	availableHeight := m.height - 7 // Header, event title and footer
+
+	if len(contentLines) > availableHeight {
+		maxScroll := len(contentLines) - availableHeight
+		m.eventsState.detailScroll = max(min(m.eventsState.detailScroll, maxScroll), 0)
+
+		start := m.eventsState.detailScroll
+		content = strings.Join(contentLines[start:start+availableHeight], "\n")
+	} else {
+		m.eventsState.detailScroll = 0
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", content)
+}
+
+func eventTitle(event *gc.GCEvent) string {
+	title := fmt.Sprintf("GC(%d) %s", event.ID, event.Type)
+	if event.Subtype != "" && event.Subtype != "Normal" {
+		title += " " + event.Subtype
+	}
+	if event.Cause != "" {
+		title += fmt.Sprintf(" (%s)", event.Cause)
+	}
+	return title
+}
+
+func eventOverviewLines(event *gc.GCEvent, analysis *gc.GCAnalysis) []string {
+	lines := []string{fmt.Sprintf("• Cause: %s", valueOrDash(event.Cause))}
+	if !event.Timestamp.IsZero() {
+		lines = append(lines, fmt.Sprintf("• Timestamp: %s", event.Timestamp.Format("2006-01-02 15:04:05.000")))
+	}
+
+	if event.ConcurrentDuration > 0 {
+		lines = append(lines, fmt.Sprintf("• Concurrent Duration: %s", utils.FormatDuration(event.ConcurrentDuration)))
+		if event.ConcurrentPhase != "" {
+			lines = append(lines, fmt.Sprintf("• Phase: %s", event.ConcurrentPhase))
+		}
+		if event.ConcurrentCycleId > 0 {
+			lines = append(lines, fmt.Sprintf("• Cycle: %d", event.ConcurrentCycleId))
+		}
+		return lines
+	}
+
+	pause := fmt.Sprintf("• Pause: %s", utils.FormatDuration(event.Duration))
+	if analysis.EstimatedPauseTarget > 0 {
+		pause += fmt.Sprintf(" (target %s) %s", utils.FormatDuration(analysis.EstimatedPauseTarget),
+			targetStatus(event.Duration, analysis.EstimatedPauseTarget))
+	}
+	lines = append(lines, pause)
+
+	if event.WorkersAvailable > 0 {
+		utilization := float64(event.WorkersUsed) / float64(event.WorkersAvailable) * 100
+		lines = append(lines, fmt.Sprintf("• Workers: %d of %d (%s)",
+			event.WorkersUsed, event.WorkersAvailable, utils.Precision(0).Percent(utilization)))
+	}
+	if event.ToSpaceExhausted {
+		lines = append(lines, "• To-space: "+utils.CriticalStyle.Render("exhausted"))
+	}
+	return lines
+}
+
+func eventHeapLines(event *gc.GCEvent) []string {
+	if event.HeapTotal == 0 {
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("• Used: %s → %s of %s", event.HeapBefore, event.HeapAfter, event.HeapTotal),
+	}
+	if event.HeapBefore > event.HeapAfter {
+		lines = append(lines, fmt.Sprintf("• Reclaimed: %s (%s efficiency)",
+			event.HeapBefore-event.HeapAfter, utils.FormatPercent(event.CollectionEfficiency*100)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("• Utilization: %s → %s",
+			utils.FormatPercent(event.HeapUtilizationBefore*100), utils.FormatPercent(event.HeapUtilizationAfter*100)))
+
+	if event.AllocationRateToEvent > 0 {
+		lines = append(lines, fmt.Sprintf("• Allocation Rate: %s/s since previous GC", utils.FormatMB(event.AllocationRateToEvent)))
+	}
+	if event.RegionSize > 0 {
+		lines = append(lines, fmt.Sprintf("• Region Size: %s", event.RegionSize))
+	}
+	return lines
+}
+
+func eventRegionLines(event *gc.GCEvent) []string {
+	if event.EdenRegionsBefore == 0 && event.SurvivorRegionsBefore == 0 && event.OldRegionsBefore == 0 &&
+		event.HumongousRegionsBefore == 0 {
+		return nil
+	}
+
+	lines := []string{
+		regionTransition("Eden", event.EdenRegionsBefore, event.EdenRegionsAfter, event.EdenRegionsTarget),
+		regionTransition("Survivor", event.SurvivorRegionsBefore, event.SurvivorRegionsAfter, event.SurvivorRegionsTarget),
+		regionTransition("Old", event.OldRegionsBefore, event.OldRegionsAfter, 0),
+		regionTransition("Humongous", event.HumongousRegionsBefore, event.HumongousRegionsAfter, 0),
+	}
+	if event.ArchiveRegionsBefore > 0 || event.ArchiveRegionsAfter > 0 {
+		lines = append(lines, regionTransition("Archive", event.ArchiveRegionsBefore, event.ArchiveRegionsAfter, 0))
+	}
+	if event.PromotionRate > 0 {
+		lines = append(lines, fmt.Sprintf("• Promoted: %s regions", utils.FormatFloat(event.PromotionRate)))
+	}
+	if event.HeapTotalRegions > 0 {
+		lines = append(lines, fmt.Sprintf("• Used Regions: %d → %d of %d",
+			event.HeapUsedRegionsBefore, event.HeapUsedRegionsAfter, event.HeapTotalRegions))
+	}
+	return lines
+}
+
+func regionTransition(name string, before, after, target int) string {
+	line := fmt.Sprintf("• %s: %d → %d", name, before, after)
+	if target > 0 {
+		line += fmt.Sprintf(" (target %d)", target)
+	}
+	return line
+}
+
+func eventPhaseLines(event *gc.GCEvent) []string {
+	phases := []phaseTiming{
+		{"Pre Evacuate", event.PreEvacuateTime, 0},
+		{"Ext Root Scan", event.ExtRootScanTime, gc.RootScanTarget},
+		{"Update RS", event.UpdateRSTime, 0},
+		{"Scan RS", event.ScanRSTime, 0},
+		{"Code Root Scan", event.CodeRootScanTime, 0},
+		{"Object Copy", event.ObjectCopyTime, gc.ObjectCopyTarget},
+		{"Termination", event.TerminationTime, gc.TerminationTarget},
+		{"Worker Other", event.WorkerOtherTime, 0},
+		{"Reference Processing", event.ReferenceProcessingTime, gc.RefProcessingTarget},
+		{"Evacuation Failure", event.EvacuationFailureTime, 0},
+		{"Post Evacuate", event.PostEvacuateTime, 0},
+	}
+
+	var lines []string
+	for _, phase := range phases {
+		if phase.duration == 0 {
+			continue
+		}
+		line := fmt.Sprintf("• %s: %s", phase.name, utils.FormatDuration(phase.duration))
+		if phase.target > 0 {
+			line += fmt.Sprintf(" (target %s) %s", utils.FormatDuration(phase.target), targetStatus(phase.duration, phase.target))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func eventCPULines(event *gc.GCEvent) []string {
+	if event.RealTime == 0 {
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("• User: %s", utils.FormatDuration(event.UserTime)),
+		fmt.Sprintf("• Sys: %s", utils.FormatDuration(event.SystemTime)),
+		fmt.Sprintf("• Real: %s", utils.FormatDuration(event.RealTime)),
+	}
+
+	// User+Sys over Real approximates how many threads did the work in parallel
+	parallelism := float64(event.UserTime+event.SystemTime) / float64(event.RealTime)
+	lines = append(lines, fmt.Sprintf("• Parallelism: %sx", utils.FormatFloat(parallelism)))
+	if event.SystemTime > event.UserTime {
+		lines = append(lines, utils.WarningStyle.Render("⚠️ Sys exceeds User, check for swapping or THP stalls"))
+	}
+	return lines
+}
+
+func eventMetaspaceLines(event *gc.GCEvent) []string {
+	if event.MetaspaceUsedBefore == 0 && event.MetaspaceUsedAfter == 0 {
+		return nil
+	}
+
+	lines := []string{memoryTransition("Used", event.MetaspaceUsedBefore, event.MetaspaceUsedAfter)}
+	if event.MetaspaceCommittedAfter > 0 {
+		lines = append(lines, memoryTransition("Committed", event.MetaspaceCommittedBefore, event.MetaspaceCommittedAfter))
+	}
+	if event.MetaspaceReserved > 0 {
+		lines = append(lines, fmt.Sprintf("• Reserved: %s", event.MetaspaceReserved))
+	}
+	if event.ClassSpaceUsedBefore > 0 || event.ClassSpaceUsedAfter > 0 {
+		lines = append(lines, memoryTransition("Class Space", event.ClassSpaceUsedBefore, event.ClassSpaceUsedAfter))
+	}
+	return lines
+}
+
+// memoryTransition omits the before value when the log only reports the state after the collection
+func memoryTransition(name string, before, after utils.MemorySize) string {
+	if before == 0 {
+		return fmt.Sprintf("• %s: %s", name, after)
+	}
+	return fmt.Sprintf("• %s: %s → %s", name, before, after)
+}
+
+func (m *Model) eventIssueLines(event *gc.GCEvent) []string {
+	issues := m.analyzeEventIssues(event)
+
+	var lines []string
+	for _, issue := range issues.critical {
+		lines = append(lines, utils.CriticalStyle.Render("🔴 "+issue))
+	}
+	for _, issue := range issues.warning {
+		lines = append(lines, utils.WarningStyle.Render("⚠️ "+issue))
+	}
+	return lines
+}
+
+func targetStatus(duration, target time.Duration) string {
+	switch {
+	case duration > target*2:
+		return utils.CriticalStyle.Render("🔴")
+	case duration > target:
+		return utils.WarningStyle.Render("⚠️")
+	default:
+		return utils.GoodStyle.Render("✓")
+	}
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
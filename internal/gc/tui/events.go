@@ -16,14 +16,17 @@ func (m *Model) RenderEvents() string {
 		return renderNoEvents()
 	}
 
-	filteredEvents := m.getFilteredEvents()
-	sortedEvents := m.getSortedEvents(filteredEvents)
+	sortedEvents := m.eventRows()
+
+	if m.eventsState.detailView && len(sortedEvents) > 0 {
+		return m.RenderEventDetail(sortedEvents)
+	}
 
 	// Calculate layout
 	headerHeight := 5  // filter line + table header + separator
 	detailsHeight := 7 // Fixed details panel height (4 lines + border)
 
-	availableTableHeight := m.height - headerHeight - detailsHeight - 3 // margins
+	availableTableHeight := m.height - headerHeight - detailsHeight - 4 // margins and the pulse strip
 
 	header := m.renderEventsHeader(sortedEvents)
 	// if len(sortedEvents) == 0 {
@@ -95,30 +98,18 @@ func (m *Model) renderEventsTable(events []*gc.GCEvent, maxHeight int) string {
 
 	separator := strings.Repeat("─", m.width)
 
-	var lines []string
-
-	for i, event := range events {
-		isSelected := i == m.eventsState.selectedEvent
-		line := m.renderEventRow(event, isSelected)
-		lines = append(lines, line)
+	// Only the rows on screen are rendered, centered on the selected event
+	maxHeight = max(maxHeight, 1)
+	startIdx, endIdx := 0, len(events)
+	if len(events) > maxHeight {
+		startIdx = max(m.eventsState.selectedEvent-maxHeight/2, 0)
+		endIdx = min(startIdx+maxHeight, len(events))
+		startIdx = max(endIdx-maxHeight, 0)
 	}
 
-	// Apply scrolling to keep selected event visible
-	startIdx := 0
-	if len(lines) > maxHeight {
-		// Center the selected event in the view
-		selectedIdx := m.eventsState.selectedEvent
-		halfHeight := maxHeight / 2
-
-		startIdx = max(selectedIdx-halfHeight, 0)
-
-		endIdx := startIdx + maxHeight
-		if endIdx > len(lines) {
-			endIdx = len(lines)
-			startIdx = max(endIdx-maxHeight, 0)
-		}
-
-		lines = lines[startIdx:endIdx]
+	lines := make([]string, 0, endIdx-startIdx)
+	for i := startIdx; i < endIdx; i++ {
+		lines = append(lines, m.renderEventRow(events[i], i == m.eventsState.selectedEvent))
 	}
 
 	table := strings.Join(lines, "\n")
@@ -169,14 +160,11 @@ func (m *Model) renderEventRow(event *gc.GCEvent, isSelected bool) string {
 		timeStr,
 		typeStr,
 		durationFieldWidth, durationStr,
-		heapStr)
+		heapStr) + m.bookmarkNote(event)
 
 	// Apply selection highlighting
 	if isSelected {
-		return lipgloss.NewStyle().
-			Background(utils.InfoColor).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Render("▶ " + row)
+		return utils.SelectedStyle.Render("▶ " + row)
 	}
 
 	// Analyze issues for row-level styling
@@ -188,7 +176,7 @@ func (m *Model) renderEventRow(event *gc.GCEvent, isSelected bool) string {
 		style = utils.WarningStyle
 	}
 
-	return style.Render("  " + row)
+	return style.Render(m.bookmarkPrefix(event) + row)
 }
 
 func (m *Model) analyzeEventIssues(event *gc.GCEvent) eventIssues {
@@ -324,8 +312,8 @@ func (m *Model) renderEventDetails(event *gc.GCEvent) string {
 
 	timingLine := ""
 	if event.ConcurrentDuration == 0 {
-		timingLine = fmt.Sprintf("Duration: %s  User: %.2fms  Sys: %.2fms  Real: %.2fms",
-			utils.FormatDuration(event.Duration), userMs, sysMs, realMs)
+		timingLine = fmt.Sprintf("Duration: %s  User: %s  Sys: %s  Real: %s",
+			utils.FormatDuration(event.Duration), utils.Precision(2).Millis(userMs), utils.Precision(2).Millis(sysMs), utils.Precision(2).Millis(realMs))
 	}
 
 	// Region information
@@ -341,8 +329,8 @@ func (m *Model) renderEventDetails(event *gc.GCEvent) string {
 	workerLine := ""
 	if event.WorkersUsed > 0 && event.WorkersAvailable > 0 {
 		utilization := float64(event.WorkersUsed) / float64(event.WorkersAvailable) * 100
-		workerLine = fmt.Sprintf("Workers: %d/%d (%.0f%% utilization)",
-			event.WorkersUsed, event.WorkersAvailable, utilization)
+		workerLine = fmt.Sprintf("Workers: %d/%d (%s utilization)",
+			event.WorkersUsed, event.WorkersAvailable, utils.Precision(0).Percent(utilization))
 	}
 
 	// Analyze issues for this event
@@ -398,6 +386,17 @@ func (m *Model) renderEventDetails(event *gc.GCEvent) string {
 	return detailsStyle.Render(content)
 }
 
+// eventRows is the events table in filter and sort order. It is only rebuilt when
+// the filter or sort changes, so scrolling a huge log doesn't re-sort it every frame.
+func (m *Model) eventRows() []*gc.GCEvent {
+	key := rowsKey{filter: m.eventsState.eventFilter, sortBy: m.eventsState.sortBy}
+	if m.cache.rows == nil || m.cache.rowsKey != key {
+		m.cache.rows = m.getSortedEvents(m.getFilteredEvents())
+		m.cache.rowsKey = key
+	}
+	return m.cache.rows
+}
+
 func (m *Model) getSortedEvents(events []*gc.GCEvent) []*gc.GCEvent {
 	// Make a copy to avoid modifying the original
 	sorted := make([]*gc.GCEvent, len(events))
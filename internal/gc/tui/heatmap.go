@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"math"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const HeatmapRows = 12
+
+// pauseHeatmap is the window's pause counts, kept until the window changes
+type pauseHeatmap struct {
+	counts       [][]int
+	labels       []string
+	columnTimes  []time.Time
+	eventIndices []int
+	columnOf     func(int) int
+}
+
+// renderPauseHeatmap counts pauses per time bucket (columns) and pause bucket (rows),
+// so two clusters of pause times or a recurring time of day stand out
+func (m *Model) renderPauseHeatmap() string {
+	title := utils.TitleStyle.Render("Pause Time Heatmap")
+
+	columns := max(m.calculateChartWidth()-utils.YAxisLabelWidth, 1)
+	key := windowKey{view: "heatmap", start: m.trendsState.viewStart, end: m.trendsState.viewEnd, width: columns}
+	data := cachedWindow(m, key, func() *pauseHeatmap {
+		var pauses []*gc.GCEvent
+		var eventIndices []int
+		for _, index := range m.windowPauses() {
+			if event := m.events[index]; event.Duration > 0 {
+				pauses = append(pauses, event)
+				eventIndices = append(eventIndices, index)
+			}
+		}
+		if len(pauses) < 2 {
+			return nil
+		}
+
+		shortest, longest := pauses[0].Duration, pauses[0].Duration
+		for _, event := range pauses {
+			shortest = min(shortest, event.Duration)
+			longest = max(longest, event.Duration)
+		}
+		bounds := pauseBucketBounds(shortest, longest, HeatmapRows)
+
+		first, last := pauses[0].Timestamp, pauses[len(pauses)-1].Timestamp
+		span := last.Sub(first)
+
+		counts := make([][]int, len(bounds))
+		for row := range counts {
+			counts[row] = make([]int, columns)
+		}
+		columnOf := func(i int) int {
+			// Without wall-clock time the columns fall back to event order
+			position := float64(i) / float64(len(pauses)-1)
+			if span > 0 {
+				position = float64(pauses[i].Timestamp.Sub(first)) / float64(span)
+			}
+			return min(int(position*float64(columns-1)+0.5), columns-1)
+		}
+		for i, event := range pauses {
+			counts[pauseBucket(event.Duration, bounds)][columnOf(i)]++
+		}
+
+		labels := make([]string, len(bounds))
+		for i, bound := range bounds {
+			labels[i] = "≤" + utils.FormatDuration(bound)
+		}
+
+		var columnTimes []time.Time
+		if span > 0 {
+			columnTimes = make([]time.Time, columns)
+			for column := range columnTimes {
+				columnTimes[column] = first.Add(span * time.Duration(column) / time.Duration(max(columns-1, 1)))
+			}
+		}
+		return &pauseHeatmap{counts: counts, labels: labels, columnTimes: columnTimes,
+			eventIndices: eventIndices, columnOf: columnOf}
+	})
+	if data == nil {
+		return title + "\n\nNot enough pauses in the window"
+	}
+
+	markers := m.chartMarkers(data.eventIndices)
+	markers.Remap(data.columnOf)
+
+	heatmap := utils.CreateHeatmap(data.counts, utils.HeatmapConfig{
+		RowLabels:   data.labels,
+		ColumnTimes: data.columnTimes,
+		Markers:     markers,
+		Styles:      CreateChartStyles(),
+	})
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", heatmap)
+}
+
+// pauseBucketBounds returns upper bounds from longest (top row) to shortest. The buckets
+// are logarithmic when pauses span an order of magnitude, where a linear scale would
+// squeeze every short pause into the bottom row.
+func pauseBucketBounds(shortest, longest time.Duration, rows int) []time.Duration {
+	if longest <= shortest {
+		return []time.Duration{longest}
+	}
+
+	logarithmic := shortest > 0 && longest >= shortest*10
+	bounds := make([]time.Duration, rows)
+	for i := range rows {
+		fraction := float64(rows-i) / float64(rows)
+		if logarithmic {
+			ratio := float64(longest) / float64(shortest)
+			bounds[i] = time.Duration(float64(shortest) * math.Pow(ratio, fraction))
+		} else {
+			bounds[i] = shortest + time.Duration(float64(longest-shortest)*fraction)
+		}
+	}
+	bounds[0] = longest
+	return bounds
+}
+
+// pauseBucket is the lowest row whose bound still covers the pause
+func pauseBucket(pause time.Duration, bounds []time.Duration) int {
+	for row := len(bounds) - 1; row >= 0; row-- {
+		if pause <= bounds[row] {
+			return row
+		}
+	}
+	return 0
+}
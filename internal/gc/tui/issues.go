@@ -18,15 +18,21 @@ func (m *Model) RenderIssues() string {
 	subTab := m.issuesState.selectedSubTab
 	subTabIssues := m.GetSubTabIssues()
 
-	header := renderIssuesHeader(m.issues, subTab)
+	header := renderIssuesHeader(m.issues, m.plan, subTab)
 	content := m.renderIssuesList(subTabIssues)
+	if subTab == TuningPlanIssues {
+		content = m.renderTuningPlan()
+	}
 
 	// Apply scrolling logic (same as before)
 	contentLines := strings.Split(content, "\n")
-	availableHeight := m.height - 4
+	availableHeight := m.height - 5
 
 	if len(contentLines) > availableHeight {
 		selectedStartLine := m.calculateSelectedStartLine(subTabIssues)
+		if subTab == TuningPlanIssues {
+			selectedStartLine = m.GetSelectedIssue() + 1 // Below the column titles
+		}
 
 		scrollY := 0
 		if selectedStartLine >= availableHeight {
@@ -52,7 +58,7 @@ func (m *Model) RenderIssues() string {
 	)
 }
 
-func renderIssuesHeader(issues *gc.GCIssues, subTab IssuesSubTab) string {
+func renderIssuesHeader(issues *gc.GCIssues, plan *gc.TuningPlan, subTab IssuesSubTab) string {
 	criticalCount := len(issues.Critical)
 	warningCount := len(issues.Warning)
 	infoCount := len(issues.Info)
@@ -76,6 +82,12 @@ func renderIssuesHeader(issues *gc.GCIssues, subTab IssuesSubTab) string {
 	}
 	counts = append(counts, infoStyle.Render(fmt.Sprintf("ℹ️  Info: %d", infoCount)))
 
+	planStyle := utils.TabInactiveStyle
+	if subTab == TuningPlanIssues {
+		planStyle = utils.TabActiveStyle
+	}
+	counts = append(counts, planStyle.Render(fmt.Sprintf("🛠  Tuning Plan: %d flags", len(plan.Changes))))
+
 	header := strings.Join(counts, "  ")
 
 	return header
@@ -130,10 +142,7 @@ func renderIssueItem(issue gc.PerformanceIssue, isSelected, isExpanded bool, wid
 	// Main issue line
 	titleLine := fmt.Sprintf("%s %s %s", selector, icon, issue.Type)
 	if isSelected {
-		titleLine = lipgloss.NewStyle().
-			Background(utils.InfoColor).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Render(titleLine)
+		titleLine = utils.SelectedStyle.Render(titleLine)
 	} else {
 		titleLine = style.Render(titleLine)
 	}
@@ -0,0 +1,368 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Action is what a key does; its name is the key users rebind under keymap.gc in ~/.jdiag.yaml
+type Action string
+
+const (
+	ActionQuit       Action = "quit"
+	ActionHelp       Action = "help"
+	ActionNextTab    Action = "next-tab"
+	ActionPrevTab    Action = "prev-tab"
+	ActionTab1       Action = "tab-1"
+	ActionTab2       Action = "tab-2"
+	ActionTab3       Action = "tab-3"
+	ActionTab4       Action = "tab-4"
+	ActionTab5       Action = "tab-5"
+	ActionExportText Action = "export-text"
+	ActionExportANSI Action = "export-ansi"
+	ActionExportSVG  Action = "export-svg"
+
+	ActionUp       Action = "up"
+	ActionDown     Action = "down"
+	ActionLeft     Action = "left"
+	ActionRight    Action = "right"
+	ActionTop      Action = "top"
+	ActionBottom   Action = "bottom"
+	ActionPageUp   Action = "page-up"
+	ActionPageDown Action = "page-down"
+
+	ActionExpand     Action = "expand"
+	ActionCopyFlags  Action = "copy-flags"
+	ActionFilter     Action = "filter"
+	ActionSort       Action = "sort"
+	ActionDetails    Action = "details"
+	ActionBack       Action = "back"
+	ActionNextEvent  Action = "next-event"
+	ActionPrevEvent  Action = "prev-event"
+	ActionBookmark   Action = "bookmark"
+	ActionNote       Action = "note"
+	ActionZoomIn     Action = "zoom-in"
+	ActionZoomOut    Action = "zoom-out"
+	ActionPanLeft    Action = "pan-left"
+	ActionPanRight   Action = "pan-right"
+	ActionCursorPrev Action = "cursor-prev"
+	ActionCursorNext Action = "cursor-next"
+	ActionJumpPrev   Action = "jump-prev"
+	ActionJumpNext   Action = "jump-next"
+	ActionBrush      Action = "brush"
+	ActionZoomBrush  Action = "zoom-brush"
+	ActionClearBrush Action = "clear-brush"
+	ActionResetView  Action = "reset-view"
+	ActionWindow     Action = "window"
+	ActionNextMark   Action = "next-bookmark"
+	ActionPrevMark   Action = "prev-bookmark"
+	ActionPlay       Action = "play"
+)
+
+// Scopes group bindings in the help overlay; a key only has to be unique among the scopes active together
+const (
+	ScopeGeneral     = "General"
+	ScopeNavigation  = "Navigation"
+	ScopeIssues      = "Issues"
+	ScopeEvents      = "Events"
+	ScopeEventDetail = "Event detail"
+	ScopeTrends      = "Trends"
+	ScopeBookmarks   = "Bookmarks"
+)
+
+// scopeSets are the scopes each view activates on top of the general ones, see Model.activeScopes
+var scopeSets = [][]string{
+	nil,
+	{ScopeIssues},
+	{ScopeEvents, ScopeBookmarks},
+	{ScopeEventDetail, ScopeBookmarks},
+	{ScopeTrends, ScopeBookmarks},
+}
+
+// Binding ties an action to its keys. A key of two space-separated keys, such as "g g", is a sequence.
+type Binding struct {
+	Action Action
+	Scope  string
+	Keys   []string
+	Help   string
+	Footer bool // Also listed in the footer
+}
+
+// Keymap is the binding table; the footer and the help overlay are generated from it
+type Keymap []Binding
+
+// DefaultKeymap returns the built-in bindings, with vim-style keys next to the arrows
+func DefaultKeymap() Keymap {
+	return Keymap{
+		{ActionQuit, ScopeGeneral, []string{"q", "ctrl+c"}, "quit", true},
+		{ActionHelp, ScopeGeneral, []string{"?"}, "keys", true},
+		{ActionNextTab, ScopeGeneral, []string{"tab"}, "next tab", true},
+		{ActionPrevTab, ScopeGeneral, []string{"shift+tab"}, "previous tab", false},
+		{ActionTab1, ScopeGeneral, []string{"1"}, "summary", false},
+		{ActionTab2, ScopeGeneral, []string{"2"}, "metrics", false},
+		{ActionTab3, ScopeGeneral, []string{"3"}, "issues", false},
+		{ActionTab4, ScopeGeneral, []string{"4"}, "events", false},
+		{ActionTab5, ScopeGeneral, []string{"5"}, "trends", false},
+		{ActionExportText, ScopeGeneral, []string{"x"}, "export text", true},
+		{ActionExportANSI, ScopeGeneral, []string{"ctrl+x"}, "export with colors", false},
+		{ActionExportSVG, ScopeGeneral, []string{"X"}, "export as SVG", false},
+
+		{ActionUp, ScopeNavigation, []string{"up", "k"}, "up", false},
+		{ActionDown, ScopeNavigation, []string{"down", "j"}, "down", false},
+		{ActionLeft, ScopeNavigation, []string{"left", "h"}, "previous view", false},
+		{ActionRight, ScopeNavigation, []string{"right", "l"}, "next view", false},
+		{ActionTop, ScopeNavigation, []string{"g g", "home"}, "first", false},
+		{ActionBottom, ScopeNavigation, []string{"G", "end"}, "last", false},
+		{ActionPageUp, ScopeNavigation, []string{"pgup", "ctrl+u"}, "page up", false},
+		{ActionPageDown, ScopeNavigation, []string{"pgdown", "ctrl+d"}, "page down", false},
+
+		{ActionExpand, ScopeIssues, []string{"enter", " "}, "expand", true},
+		{ActionCopyFlags, ScopeIssues, []string{"c", "y"}, "copy tuning flags", true},
+
+		{ActionFilter, ScopeEvents, []string{"f"}, "filter", true},
+		{ActionSort, ScopeEvents, []string{"s"}, "sort", true},
+		{ActionDetails, ScopeEvents, []string{"enter", " "}, "details", true},
+
+		{ActionBack, ScopeEventDetail, []string{"esc", "enter", "backspace"}, "back", true},
+		{ActionNextEvent, ScopeEventDetail, []string{"n"}, "next event", true},
+		{ActionPrevEvent, ScopeEventDetail, []string{"p"}, "previous event", true},
+
+		{ActionZoomIn, ScopeTrends, []string{"+", "="}, "zoom in", true},
+		{ActionZoomOut, ScopeTrends, []string{"-"}, "zoom out", true},
+		{ActionPanLeft, ScopeTrends, []string{"["}, "pan left", false},
+		{ActionPanRight, ScopeTrends, []string{"]"}, "pan right", false},
+		{ActionCursorPrev, ScopeTrends, []string{","}, "cursor left", false},
+		{ActionCursorNext, ScopeTrends, []string{"."}, "cursor right", false},
+		{ActionJumpPrev, ScopeTrends, []string{"<"}, "cursor far left", false},
+		{ActionJumpNext, ScopeTrends, []string{">"}, "cursor far right", false},
+		{ActionBrush, ScopeTrends, []string{"b"}, "brush", true},
+		{ActionZoomBrush, ScopeTrends, []string{"enter"}, "zoom to brush", false},
+		{ActionClearBrush, ScopeTrends, []string{"esc"}, "clear brush", false},
+		{ActionResetView, ScopeTrends, []string{"0"}, "reset zoom", true},
+		{ActionWindow, ScopeTrends, []string{"w"}, "time window", true},
+		{ActionNextMark, ScopeTrends, []string{"'"}, "next bookmark", false},
+		{ActionPrevMark, ScopeTrends, []string{"\""}, "previous bookmark", false},
+		{ActionPlay, ScopeTrends, []string{"p"}, "play region map", false},
+
+		{ActionBookmark, ScopeBookmarks, []string{"m"}, "bookmark", true},
+		{ActionNote, ScopeBookmarks, []string{"a"}, "note", true},
+	}
+}
+
+// keymap is shared by the GC and compare TUIs, like the watch TUI's keys
+var keymap = DefaultKeymap()
+
+// keyNames are spellings for keys the config can't write directly
+var keyNames = map[string]string{"space": " ", "comma": ","}
+
+// ApplyKeymap rebinds actions from the keymap.gc config section, e.g. down: [j, down, ctrl+n]
+func ApplyKeymap(overrides map[string]string) error {
+	updated := slices.Clone(keymap)
+	for action, value := range overrides {
+		index := slices.IndexFunc(updated, func(b Binding) bool { return string(b.Action) == action })
+		if index < 0 {
+			return fmt.Errorf("unknown keymap action '%s' (available: %s)", action, strings.Join(updated.actionNames(), ", "))
+		}
+
+		var keys []string
+		for _, key := range strings.Split(value, ",") {
+			key = strings.Join(strings.Fields(key), " ")
+			if name, ok := keyNames[key]; ok {
+				key = name
+			}
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("keymap action '%s' has no keys (write comma or space for those keys)", action)
+		}
+		updated[index].Keys = keys
+	}
+
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	keymap = updated
+	return nil
+}
+
+// validate rejects a key bound to two actions that can be active at the same time
+func (k Keymap) validate() error {
+	for _, scopes := range scopeSets {
+		owners := map[string]Action{}
+		for _, binding := range k.active(scopes) {
+			for _, key := range binding.Keys {
+				if other, ok := owners[key]; ok {
+					return fmt.Errorf("keymap: '%s' is bound to both %s and %s", keyLabel(key), other, binding.Action)
+				}
+				owners[key] = binding.Action
+			}
+		}
+		// A key that starts a sequence can't also act on its own
+		for key, action := range owners {
+			first, _, isSequence := strings.Cut(key, " ")
+			if other, ok := owners[first]; ok && isSequence {
+				return fmt.Errorf("keymap: '%s' of %s starts the sequence '%s' of %s", keyLabel(first), other, keyLabel(key), action)
+			}
+		}
+	}
+	return nil
+}
+
+func (k Keymap) actionNames() []string {
+	names := make([]string, len(k))
+	for i, binding := range k {
+		names[i] = string(binding.Action)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// active lists the general bindings, which every view has, followed by those of the given scopes
+func (k Keymap) active(scopes []string) []Binding {
+	var bindings []Binding
+	for _, scope := range append([]string{ScopeGeneral, ScopeNavigation}, scopes...) {
+		for _, binding := range k {
+			if binding.Scope == scope {
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+	return bindings
+}
+
+// Resolve turns a key press into an action. The first key of a sequence is held in
+// pending and resolves to "" until the sequence completes or is broken.
+func (k Keymap) Resolve(msg tea.KeyMsg, scopes []string, pending *string) Action {
+	key := msg.String()
+	bindings := k.active(scopes)
+
+	if *pending != "" {
+		sequence := *pending + " " + key
+		*pending = ""
+		if action := matchKey(bindings, sequence); action != "" {
+			return action
+		}
+	}
+
+	if action := matchKey(bindings, key); action != "" {
+		return action
+	}
+	for _, binding := range bindings {
+		for _, bound := range binding.Keys {
+			if key != " " && strings.HasPrefix(bound, key+" ") {
+				*pending = key
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+func matchKey(bindings []Binding, key string) Action {
+	for _, binding := range bindings {
+		if slices.Contains(binding.Keys, key) {
+			return binding.Action
+		}
+	}
+	return ""
+}
+
+// Shortcuts is the one-line footer for the given scopes
+func (k Keymap) Shortcuts(scopes []string) string {
+	var parts []string
+	for _, binding := range k.active(scopes) {
+		if binding.Footer {
+			parts = append(parts, keyLabel(binding.Keys[0])+":"+binding.Help)
+		}
+	}
+	return strings.Join(parts, " • ")
+}
+
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space",
+}
+
+// keyLabel is how a key is shown in the footer and help, e.g. "↑" or "gg"
+func keyLabel(key string) string {
+	if label, ok := keyLabels[key]; ok {
+		return label
+	}
+	return strings.ReplaceAll(key, " ", "")
+}
+
+// Label is the first key of an action, e.g. "?" for help
+func (k Keymap) Label(action Action) string {
+	for _, binding := range k {
+		if binding.Action == action {
+			return keyLabel(binding.Keys[0])
+		}
+	}
+	return ""
+}
+
+// KeysLabel joins the labels of a binding's keys, e.g. "↑/k"
+func (b Binding) KeysLabel() string {
+	labels := make([]string, len(b.Keys))
+	for i, key := range b.Keys {
+		labels[i] = keyLabel(key)
+	}
+	return strings.Join(labels, "/")
+}
+
+// renderHelp lays out the bindings of the current view in columns that fit the given height
+func (m *Model) renderHelp(height int) string {
+	scopes := append([]string{ScopeGeneral, ScopeNavigation}, m.activeScopes()...)
+	bindings := keymap.active(m.activeScopes())
+
+	keysWidth := 0
+	for _, binding := range bindings {
+		keysWidth = max(keysWidth, lipgloss.Width(binding.KeysLabel()))
+	}
+
+	var blocks []string
+	for _, scope := range scopes {
+		lines := []string{utils.InfoStyle.Bold(true).Render(scope)}
+		for _, binding := range bindings {
+			if binding.Scope == scope {
+				lines = append(lines, fmt.Sprintf("%-*s  %s", keysWidth, binding.KeysLabel(), utils.MutedStyle.Render(binding.Help)))
+			}
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	// Fill columns top to bottom, leaving room for the border and title
+	available := max(height-5, 1)
+	var columns []string
+	var column []string
+	used := 0
+	for _, block := range blocks {
+		blockHeight := lipgloss.Height(block)
+		if used > 0 && used+1+blockHeight > available {
+			columns = append(columns, strings.Join(column, "\n\n"))
+			column, used = nil, 0
+		}
+		if used > 0 {
+			used++
+		}
+		column = append(column, block)
+		used += blockHeight
+	}
+	columns = append(columns, strings.Join(column, "\n\n"))
+
+	for i := range columns[:len(columns)-1] {
+		columns[i] = lipgloss.NewStyle().PaddingRight(4).Render(columns[i])
+	}
+
+	title := utils.TitleStyle.Render("Keys") + utils.MutedStyle.Render("rebind under keymap.gc in ~/.jdiag.yaml • any key closes")
+	box := utils.BoxStyle.Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left,
+		title, "", lipgloss.JoinHorizontal(lipgloss.Top, columns...)))
+	return lipgloss.Place(m.width, height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -15,7 +15,7 @@ func (m *Model) RenderMetrics() string {
 
 	// Apply scrolling if needed
 	contentLines := strings.Split(content, "\n")
-	availableHeight := m.height - 4 // Account for tabs
+	availableHeight := m.height - 5 // Account for tabs and the pulse strip
 
 	scrollY := m.scrollPositions[MetricsTab]
 	if len(contentLines) > availableHeight {
@@ -26,6 +26,7 @@ func (m *Model) RenderMetrics() string {
 		if scrollY < 0 {
 			scrollY = 0
 		}
+		m.scrollPositions[MetricsTab] = scrollY
 
 		start := scrollY
 		end := min(start+availableHeight, len(contentLines))
@@ -120,7 +121,7 @@ func renderGeneralMetrics(analysis *gc.GCAnalysis) string {
 
 	// Performance Section
 	throughputStatus := getStatusIndicator(analysis.Throughput, gc.ThroughputPoor, gc.ThroughputCritical)
-	throughputStr := fmt.Sprintf("• Throughput: %.1f%%", analysis.Throughput)
+	throughputStr := fmt.Sprintf("• Throughput: %s", utils.FormatPercent(analysis.Throughput))
 	if throughputStatus != "" {
 		throughputStr += " " + throughputStatus
 	}
@@ -134,25 +135,25 @@ func renderGeneralMetrics(analysis *gc.GCAnalysis) string {
 
 	// Collection Breakdown
 	collection := []string{
-		fmt.Sprintf("• Young GCs: %d (%.1f%%)", analysis.YoungGCCount, float64(analysis.YoungGCCount)/total*100),
-		fmt.Sprintf("• Mixed GCs: %d (%.1f%%)", analysis.MixedGCCount, float64(analysis.MixedGCCount)/total*100),
+		fmt.Sprintf("• Young GCs: %d (%s)", analysis.YoungGCCount, utils.FormatPercent(float64(analysis.YoungGCCount)/total*100)),
+		fmt.Sprintf("• Mixed GCs: %d (%s)", analysis.MixedGCCount, utils.FormatPercent(float64(analysis.MixedGCCount)/total*100)),
 	}
 	if analysis.FullGCCount > 0 {
-		collection = append(collection, fmt.Sprintf("• Full GCs: %d (%.1f%%) %s", analysis.FullGCCount, float64(analysis.FullGCCount)/total*100, utils.CriticalStyle.Render("🔴 Critical")))
+		collection = append(collection, fmt.Sprintf("• Full GCs: %d (%s) %s", analysis.FullGCCount, utils.FormatPercent(float64(analysis.FullGCCount)/total*100), utils.CriticalStyle.Render("🔴 Critical")))
 	} else {
-		collection = append(collection, fmt.Sprintf("• Full GCs: %d (%.1f%%)", analysis.FullGCCount, float64(analysis.FullGCCount)/total*100))
+		collection = append(collection, fmt.Sprintf("• Full GCs: %d (%s)", analysis.FullGCCount, utils.FormatPercent(float64(analysis.FullGCCount)/total*100)))
 	}
 
 	// Allocation Statistics
 	allocRateStatus := getStatusIndicator(analysis.AllocationRate, gc.AllocRateHigh, gc.AllocRateCritical)
-	allocRateStr := fmt.Sprintf("• Allocation Rate: %.1f MB/s", analysis.AllocationRate)
+	allocRateStr := fmt.Sprintf("• Allocation Rate: %s/s", utils.FormatMB(analysis.AllocationRate))
 	if allocRateStatus != "" {
 		allocRateStr += " " + allocRateStatus
 	}
 
 	alloc := []string{
 		allocRateStr,
-		fmt.Sprintf("• Avg Heap Util: %.1f%%", analysis.AvgHeapUtil*100),
+		fmt.Sprintf("• Avg Heap Util: %s", utils.FormatPercent(analysis.AvgHeapUtil*100)),
 	}
 	if analysis.AllocationBurstCount > 0 {
 		alloc = append(alloc, fmt.Sprintf("• Allocation Bursts: %d", analysis.AllocationBurstCount))
@@ -203,7 +204,7 @@ func renderTimingMetrics(analysis *gc.GCAnalysis) string {
 			status = utils.WarningStyle.Render("⚠️ Elevated")
 		}
 
-		targetMissStr := fmt.Sprintf("• Target Miss Rate: %.1f%%", analysis.PauseTargetMissRate*100)
+		targetMissStr := fmt.Sprintf("• Target Miss Rate: %s", utils.FormatPercent(analysis.PauseTargetMissRate*100))
 		if status != "" {
 			targetMissStr += " " + status
 		}
@@ -222,7 +223,7 @@ func renderTimingMetrics(analysis *gc.GCAnalysis) string {
 			status = utils.WarningStyle.Render("⚠️ High Variance")
 		}
 
-		varianceStr := fmt.Sprintf("• Pause Variance: %.3f", analysis.PauseTimeVariance)
+		varianceStr := fmt.Sprintf("• Pause Variance: %s", utils.Precision(3).Float(analysis.PauseTimeVariance))
 		if status != "" {
 			varianceStr += " " + status
 		}
@@ -237,7 +238,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 	// Memory Utilization
 	heapUtilStatus := getStatusIndicator(analysis.AvgHeapUtil*100, gc.HeapUtilWarning*100, gc.HeapUtilCritical*100)
-	heapUtilStr := fmt.Sprintf("• Avg Heap Util: %.1f%%", analysis.AvgHeapUtil*100)
+	heapUtilStr := fmt.Sprintf("• Avg Heap Util: %s", utils.FormatPercent(analysis.AvgHeapUtil*100))
 	if heapUtilStatus != "" {
 		heapUtilStr += " " + heapUtilStatus
 	}
@@ -246,7 +247,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 	if analysis.AvgRegionUtilization > 0 {
 		regionUtilStatus := getStatusIndicator(analysis.AvgRegionUtilization*100, gc.RegionUtilWarning*100, gc.RegionUtilCritical*100)
-		regionUtilStr := fmt.Sprintf("• Avg Region Util: %.1f%%", analysis.AvgRegionUtilization*100)
+		regionUtilStr := fmt.Sprintf("• Avg Region Util: %s", utils.FormatPercent(analysis.AvgRegionUtilization*100))
 		if regionUtilStatus != "" {
 			regionUtilStr += " " + regionUtilStatus
 		}
@@ -256,7 +257,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 	// Allocation Patterns
 	allocRateStatus := getStatusIndicator(analysis.AllocationRate, gc.AllocRateHigh, gc.AllocRateCritical)
-	allocRateStr := fmt.Sprintf("• Allocation Rate: %.1f MB/s", analysis.AllocationRate)
+	allocRateStr := fmt.Sprintf("• Allocation Rate: %s/s", utils.FormatMB(analysis.AllocationRate))
 	if allocRateStatus != "" {
 		allocRateStr += " " + allocRateStatus
 	}
@@ -273,7 +274,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 		if analysis.AvgPromotionRate > 0 {
 			avgPromStatus := getStatusIndicator(analysis.AvgPromotionRate, gc.PromotionRateWarning, gc.PromotionRateCritical)
-			avgPromStr := fmt.Sprintf("• Avg Promotion: %.1f regions/GC", analysis.AvgPromotionRate)
+			avgPromStr := fmt.Sprintf("• Avg Promotion: %s regions/GC", utils.FormatFloat(analysis.AvgPromotionRate))
 			if avgPromStatus != "" {
 				avgPromStr += " " + avgPromStatus
 			}
@@ -282,7 +283,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 		if analysis.MaxPromotionRate > 0 {
 			maxPromStatus := getStatusIndicator(analysis.MaxPromotionRate, gc.PromotionRateWarning, gc.PromotionRateCritical)
-			maxPromStr := fmt.Sprintf("• Max Promotion: %.1f regions/GC", analysis.MaxPromotionRate)
+			maxPromStr := fmt.Sprintf("• Max Promotion: %s regions/GC", utils.FormatFloat(analysis.MaxPromotionRate))
 			if maxPromStatus != "" {
 				maxPromStr += " " + maxPromStatus
 			}
@@ -291,7 +292,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 		if analysis.SurvivorOverflowRate > 0 {
 			survivorStatus := getStatusIndicator(analysis.SurvivorOverflowRate*100, gc.SurvivorOverflowWarning*100, gc.SurvivorOverflowCritical*100)
-			survivorStr := fmt.Sprintf("• Survivor Overflow: %.1f%%", analysis.SurvivorOverflowRate*100)
+			survivorStr := fmt.Sprintf("• Survivor Overflow: %s", utils.FormatPercent(analysis.SurvivorOverflowRate*100))
 			if survivorStatus != "" {
 				survivorStr += " " + survivorStatus
 			}
@@ -300,7 +301,7 @@ func renderMemoryMetrics(analysis *gc.GCAnalysis) string {
 
 		if analysis.PromotionEfficiency > 0 {
 			efficiencyStatus := getStatusIndicator(analysis.PromotionEfficiency*100, gc.PromotionEfficiencyWarning*100, gc.PromotionEfficiencyCritical*100)
-			efficiencyStr := fmt.Sprintf("• Promotion Efficiency: %.1f%%", analysis.PromotionEfficiency*100)
+			efficiencyStr := fmt.Sprintf("• Promotion Efficiency: %s", utils.FormatPercent(analysis.PromotionEfficiency*100))
 			if efficiencyStatus != "" {
 				efficiencyStr += " " + efficiencyStatus
 			}
@@ -320,7 +321,7 @@ func renderG1GCAnalysis(analysis *gc.GCAnalysis) string {
 	var eff []string
 	if analysis.YoungCollectionEfficiency > 0 {
 		youngEffStatus := getStatusIndicator(analysis.YoungCollectionEfficiency*100, gc.YoungCollectionEffWarning*100, gc.YoungCollectionEff*100/2)
-		youngEffStr := fmt.Sprintf("• Young GC Efficiency: %.1f%%", analysis.YoungCollectionEfficiency*100)
+		youngEffStr := fmt.Sprintf("• Young GC Efficiency: %s", utils.FormatPercent(analysis.YoungCollectionEfficiency*100))
 		if youngEffStatus != "" {
 			youngEffStr += " " + youngEffStatus
 		}
@@ -329,7 +330,7 @@ func renderG1GCAnalysis(analysis *gc.GCAnalysis) string {
 
 	if analysis.MixedCollectionEfficiency > 0 {
 		mixedEffStatus := getStatusIndicator(analysis.MixedCollectionEfficiency*100, gc.MixedCollectionEffWarning*100, gc.MixedCollectionEff*100/2)
-		mixedEffStr := fmt.Sprintf("• Mixed GC Efficiency: %.1f%%", analysis.MixedCollectionEfficiency*100)
+		mixedEffStr := fmt.Sprintf("• Mixed GC Efficiency: %s", utils.FormatPercent(analysis.MixedCollectionEfficiency*100))
 		if mixedEffStatus != "" {
 			mixedEffStr += " " + mixedEffStatus
 		}
@@ -337,7 +338,7 @@ func renderG1GCAnalysis(analysis *gc.GCAnalysis) string {
 	}
 
 	if analysis.MixedToYoungRatio > 0 {
-		eff = append(eff, fmt.Sprintf("• Mixed to Young Ratio: %.2f", analysis.MixedToYoungRatio))
+		eff = append(eff, fmt.Sprintf("• Mixed to Young Ratio: %s", utils.Precision(2).Float(analysis.MixedToYoungRatio)))
 	}
 	sections = append(sections, renderSection("Collection Efficiency", eff))
 
@@ -347,7 +348,7 @@ func renderG1GCAnalysis(analysis *gc.GCAnalysis) string {
 
 		if analysis.AvgRegionUtilization > 0 {
 			regionUtilStatus := getStatusIndicator(analysis.AvgRegionUtilization*100, gc.RegionUtilWarning*100, gc.RegionUtilCritical*100)
-			regionUtilStr := fmt.Sprintf("• Avg Region Util: %.1f%%", analysis.AvgRegionUtilization*100)
+			regionUtilStr := fmt.Sprintf("• Avg Region Util: %s", utils.FormatPercent(analysis.AvgRegionUtilization*100))
 			if regionUtilStatus != "" {
 				regionUtilStr += " " + regionUtilStatus
 			}
@@ -363,7 +364,7 @@ func renderG1GCAnalysis(analysis *gc.GCAnalysis) string {
 	// Evacuation Statistics
 	if analysis.EvacuationFailureRate > 0 {
 		evacFailStatus := getStatusIndicator(analysis.EvacuationFailureRate*100, gc.EvacFailureRateWarning, gc.EvacFailureRateCritical)
-		evacFailStr := fmt.Sprintf("• Evacuation Failures: %.2f%%", analysis.EvacuationFailureRate*100)
+		evacFailStr := fmt.Sprintf("• Evacuation Failures: %s", utils.Precision(2).Percent(analysis.EvacuationFailureRate*100))
 		if evacFailStatus != "" {
 			evacFailStr += " " + evacFailStatus
 		}
@@ -398,7 +399,7 @@ func renderConcurrentMetrics(analysis *gc.GCAnalysis) string {
 	}
 
 	if analysis.ConcurrentCycleFrequency > 0 {
-		lines = append(lines, fmt.Sprintf("• Cycle Frequency: %.2f/hour", analysis.ConcurrentCycleFrequency))
+		lines = append(lines, fmt.Sprintf("• Cycle Frequency: %s/hour", utils.Precision(2).Float(analysis.ConcurrentCycleFrequency)))
 	}
 
 	if analysis.ConcurrentCycleFailures > 0 {
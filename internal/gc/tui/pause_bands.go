@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// MinPausesPerBand keeps percentiles meaningful; with fewer pauses P99 is just the max
+const MinPausesPerBand = 5
+
+// pauseBands is the window's pauses grouped into bands, kept until the window changes
+type pauseBands struct {
+	bands        []utils.PercentileBand
+	eventIndices []int
+	bandOf       func(int) int
+}
+
+// renderPauseBands draws P50/P95/P99 per time bucket, so a growing tail shows even when the median holds
+func (m *Model) renderPauseBands() string {
+	title := utils.TitleStyle.Render("GC Pause Duration (P50/P95/P99 per bucket)")
+	pauseMillis := func(event *gc.GCEvent) float64 {
+		return float64(event.Duration.Nanoseconds()) / 1e6
+	}
+
+	eventIndices := m.windowPauses()
+	if len(eventIndices) == 0 {
+		return title + "\n\nNo data available"
+	}
+
+	width := m.calculateChartWidth() - utils.YAxisLabelWidth
+	key := windowKey{view: "bands", start: m.trendsState.viewStart, end: m.trendsState.viewEnd, width: width}
+	data := cachedWindow(m, key, func() pauseBands {
+		pauses := make([]*gc.GCEvent, len(eventIndices))
+		for i, index := range eventIndices {
+			pauses[i] = m.events[index]
+		}
+
+		bandCount := min(max(len(pauses)/MinPausesPerBand, 1), width)
+		bandOf := pauseBandAssigner(pauses, bandCount)
+
+		grouped := make([][]float64, bandCount)
+		bands := make([]utils.PercentileBand, bandCount)
+		for i, event := range pauses {
+			band := bandOf(i)
+			grouped[band] = append(grouped[band], pauseMillis(event))
+			if bands[band].Count == 0 {
+				bands[band].Start = event.Timestamp
+			}
+			bands[band].Count++
+		}
+		for i, group := range grouped {
+			if len(group) == 0 {
+				continue
+			}
+			slices.Sort(group)
+			bands[i].P50 = utils.CalculatePercentile(group, 50)
+			bands[i].P95 = utils.CalculatePercentile(group, 95)
+			bands[i].P99 = utils.CalculatePercentile(group, 99)
+		}
+		fillBandStarts(bands, pauses)
+		return pauseBands{bands: bands, eventIndices: eventIndices, bandOf: bandOf}
+	})
+
+	markers := m.chartMarkers(data.eventIndices)
+	markers.Remap(data.bandOf)
+
+	chart := utils.CreateBandChart(data.bands, "ms", utils.ChartConfig{
+		Width:   m.calculateChartWidth(),
+		Height:  ChartHeight,
+		Styles:  CreateChartStyles(),
+		Markers: markers,
+	})
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		chart,
+		"",
+		m.renderRangeStats(pauseMillis, "ms"))
+}
+
+// pauseBandAssigner buckets pauses by wall-clock time, or by event order when the log has no timestamps
+func pauseBandAssigner(pauses []*gc.GCEvent, bandCount int) func(int) int {
+	first, last := pauses[0].Timestamp, pauses[len(pauses)-1].Timestamp
+	span := last.Sub(first)
+
+	return func(i int) int {
+		if span <= 0 {
+			return min(i*bandCount/len(pauses), bandCount-1)
+		}
+		offset := pauses[i].Timestamp.Sub(first)
+		return min(int(float64(offset)/float64(span)*float64(bandCount)), bandCount-1)
+	}
+}
+
+// fillBandStarts gives empty bands the start time they would have had, for the time axis
+func fillBandStarts(bands []utils.PercentileBand, pauses []*gc.GCEvent) {
+	first, last := pauses[0].Timestamp, pauses[len(pauses)-1].Timestamp
+	step := last.Sub(first) / time.Duration(len(bands))
+	for i := range bands {
+		if bands[i].Count == 0 {
+			bands[i].Start = first.Add(step * time.Duration(i))
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+var phaseSegments = []utils.StackSegment{
+	{Label: "Root Scan", Glyph: "█", Style: LipglossRenderer{utils.InfoStyle}},
+	{Label: "Object Copy", Glyph: "▓", Style: LipglossRenderer{utils.GoodStyle}},
+	{Label: "Ref Processing", Glyph: "▒", Style: LipglossRenderer{utils.WarningStyle}},
+	{Label: "Termination", Glyph: "░", Style: LipglossRenderer{utils.CriticalStyle}},
+	{Label: "Other", Glyph: "·", Style: LipglossRenderer{utils.MutedStyle}},
+}
+
+// pausePhases splits a pause in ms into phaseSegments order. G1 reports per-worker
+// averages, so when they add up to more than the pause they are scaled down to fit.
+func pausePhases(event *gc.GCEvent) []float64 {
+	rootScan := event.ExtRootScanTime + event.UpdateRSTime + event.ScanRSTime + event.CodeRootScanTime
+	phases := []time.Duration{rootScan, event.ObjectCopyTime, event.ReferenceProcessingTime, event.TerminationTime}
+
+	var measured time.Duration
+	for _, phase := range phases {
+		measured += phase
+	}
+
+	scale := 1.0
+	if measured > event.Duration && measured > 0 {
+		scale = float64(event.Duration) / float64(measured)
+	}
+
+	values := make([]float64, 0, len(phaseSegments))
+	for _, phase := range phases {
+		values = append(values, float64(phase)*scale/1e6)
+	}
+	other := max(event.Duration-measured, 0)
+	return append(values, float64(other)/1e6)
+}
+
+// phaseBreakdown is the window's bars and phase shares, kept until the window changes
+type phaseBreakdown struct {
+	bars         []utils.StackedBar
+	eventIndices []int
+	barOf        func(int) int
+	shares       []string
+	message      string // Shown instead of the chart when there is nothing to draw
+}
+
+// renderPhaseBreakdown stacks each pause by phase; with more pauses than columns the longest in each column is kept
+func (m *Model) renderPhaseBreakdown(chartWidth int) string {
+	title := utils.TitleStyle.Render("Pause Phase Breakdown")
+
+	width := chartWidth - utils.YAxisLabelWidth
+	key := windowKey{view: "phases", start: m.trendsState.viewStart, end: m.trendsState.viewEnd, width: width}
+	data := cachedWindow(m, key, func() *phaseBreakdown {
+		var pauses []*gc.GCEvent
+		var eventIndices []int
+		hasPhases := false
+		for _, index := range m.windowPauses() {
+			event := m.events[index]
+			if event.Duration == 0 {
+				continue
+			}
+			pauses = append(pauses, event)
+			eventIndices = append(eventIndices, index)
+			hasPhases = hasPhases || event.ObjectCopyTime > 0 || event.ExtRootScanTime > 0
+		}
+		if len(pauses) == 0 {
+			return &phaseBreakdown{message: "No data available"}
+		}
+		if !hasPhases {
+			return &phaseBreakdown{message: utils.MutedStyle.Render("No phase timings in this log. Enable them with -Xlog:gc+phases=debug")}
+		}
+
+		barCount := min(len(pauses), width)
+		barOf := func(i int) int { return i * barCount / len(pauses) }
+
+		bars := make([]utils.StackedBar, barCount)
+		longest := make([]*gc.GCEvent, barCount)
+		for i, event := range pauses {
+			bar := barOf(i)
+			if longest[bar] == nil || event.Duration > longest[bar].Duration {
+				longest[bar] = event
+			}
+		}
+		for i, event := range longest {
+			bars[i] = utils.StackedBar{Timestamp: event.Timestamp, Values: pausePhases(event)}
+		}
+
+		return &phaseBreakdown{bars: bars, eventIndices: eventIndices, barOf: barOf, shares: []string{
+			renderPhaseShares("All pauses", pauses),
+			renderPhaseShares("Slowest 5%", slowestPauses(pauses, 0.05)),
+		}}
+	})
+	if data.message != "" {
+		return title + "\n\n" + data.message
+	}
+
+	markers := m.chartMarkers(data.eventIndices)
+	markers.Remap(data.barOf)
+
+	chart := utils.CreateStackedBarChart(data.bars, phaseSegments, "ms", utils.ChartConfig{
+		Width:   chartWidth,
+		Height:  ChartHeight,
+		Styles:  CreateChartStyles(),
+		Markers: markers,
+	})
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		append([]string{title, "", chart, ""}, data.shares...)...)
+}
+
+// slowestPauses picks the longest fraction of the pauses, in log order. Sorting plain
+// durations for the cut-off keeps this quick on logs with hundreds of thousands of pauses.
+func slowestPauses(pauses []*gc.GCEvent, fraction float64) []*gc.GCEvent {
+	count := max(int(float64(len(pauses))*fraction), 1)
+	durations := make([]time.Duration, len(pauses))
+	for i, event := range pauses {
+		durations[i] = event.Duration
+	}
+	slices.Sort(durations)
+	cutoff := durations[len(durations)-count]
+
+	slowest := make([]*gc.GCEvent, 0, count)
+	ties := count
+	for _, event := range pauses {
+		if event.Duration > cutoff {
+			slowest = append(slowest, event)
+			ties--
+		}
+	}
+	for _, event := range pauses {
+		if event.Duration == cutoff && ties > 0 {
+			slowest = append(slowest, event)
+			ties--
+		}
+	}
+	return slowest
+}
+
+// phaseShares is the fraction of the pause time of a group of events spent in each phase
+func phaseShares(pauses []*gc.GCEvent) ([]float64, bool) {
+	shares := make([]float64, len(phaseSegments))
+	sum := 0.0
+	for _, event := range pauses {
+		for i, value := range pausePhases(event) {
+			shares[i] += value
+			sum += value
+		}
+	}
+	if sum == 0 {
+		return nil, false
+	}
+	for i := range shares {
+		shares[i] /= sum
+	}
+	return shares, true
+}
+
+// renderPhaseShares shows how the pause time of a group of events splits across phases
+func renderPhaseShares(label string, pauses []*gc.GCEvent) string {
+	shares, ok := phaseShares(pauses)
+	if !ok {
+		return ""
+	}
+
+	var parts []string
+	for i, segment := range phaseSegments {
+		share := utils.FormatPercent(shares[i] * 100)
+		parts = append(parts, segment.Style.Render(segment.Glyph)+" "+segment.Label+" "+share)
+	}
+	return utils.TitleStyle.Render(fmt.Sprintf("%s (%d):", label, len(pauses))) + " " + strings.Join(parts, " • ")
+}
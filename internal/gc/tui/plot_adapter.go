@@ -57,9 +57,18 @@ func CreateChartStyles() utils.ChartStyles {
 	}
 }
 
-// CreatePlotFromGCData creates a plot specifically for GC data with proper styling and legend
-func CreatePlotFromGCData(values []float64, timestamps []time.Time, gcTypes []string, unit string, width, height int) string {
+// CreatePlotFromGCData creates a plot specifically for GC data with proper styling and legend;
+// config gives the size, markers and time range
+func CreatePlotFromGCData(values []float64, timestamps []time.Time, gcTypes []string, unit string, config utils.ChartConfig) string {
 	styles := CreateChartStyles()
+	config.Styles = styles
+	config.Legend = CreateGCLegend(styles)
+
+	return utils.CreatePlot(gcDataPoints(values, timestamps, gcTypes, styles), unit, config)
+}
+
+// gcDataPoints pairs values with their timestamps and an icon for the GC type
+func gcDataPoints(values []float64, timestamps []time.Time, gcTypes []string, styles utils.ChartStyles) []utils.DataPoint {
 	mapper := GCIconMapper{Styles: styles}
 
 	// Convert to DataPoints with styled icons
@@ -80,15 +89,7 @@ func CreatePlotFromGCData(values []float64, timestamps []time.Time, gcTypes []st
 			Icon:      mapper.GetStyledIcon(gcType),
 		}
 	}
-
-	config := utils.ChartConfig{
-		Width:  width,
-		Height: height,
-		Styles: styles,
-		Legend: CreateGCLegend(styles),
-	}
-
-	return utils.CreatePlot(dataPoints, unit, config)
+	return dataPoints
 }
 
 // CreateSimplePlot creates a basic plot with default styling (backward compatibility)
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MinPulseWidth = 6  // Narrowest sparkline the header keeps
+	MaxPulseWidth = 24 // Widest one, so the strip stays a glance
+)
+
+// pulseMetric is one sparkline of the header strip, summarizing the whole log
+type pulseMetric struct {
+	label   string
+	values  []float64 // In log order
+	current string
+	style   lipgloss.Style
+}
+
+// renderPulse is the header strip every tab shows: heap occupancy, pauses and allocation
+// rate over the whole log, and the health badge, so a regression stays in sight
+func (m *Model) renderPulse() string {
+	return cachedWindow(m, windowKey{view: "pulse", width: m.width}, func() string {
+		return m.buildPulse()
+	})
+}
+
+func (m *Model) buildPulse() string {
+	metrics := m.pulseMetrics()
+
+	// The badge and any skipped lines lead, so narrow terminals cut a sparkline rather than them
+	parts := []string{renderHealthBadge(m.issues)}
+	if skipped := m.analysis.SkippedLines; skipped > 0 {
+		parts = append(parts, utils.WarningStyle.Render(fmt.Sprintf("⚠️ %d lines skipped", skipped)))
+	}
+
+	// Split what the labels, values and badge leave between the sparklines
+	fixed := 0
+	for _, part := range parts {
+		fixed += lipgloss.Width(part) + 3
+	}
+	for _, metric := range metrics {
+		fixed += len(metric.label) + lipgloss.Width(metric.current) + 6
+	}
+	sparkWidth := min(max((m.width-fixed)/max(len(metrics), 1), MinPulseWidth), MaxPulseWidth)
+
+	for _, metric := range metrics {
+		spark := utils.CreateSparkline(bucketMax(metric.values, sparkWidth), sparkWidth)
+		parts = append(parts, utils.MutedStyle.Render(metric.label+" ")+utils.InfoStyle.Render(spark)+" "+metric.style.Render(metric.current))
+	}
+
+	return ansi.Truncate(strings.Join(parts, utils.MutedStyle.Render(" │ ")), m.width, "…")
+}
+
+func (m *Model) pulseMetrics() []pulseMetric {
+	var occupancy, pauses, allocation []float64
+	for _, event := range m.events {
+		if event.HeapTotal > 0 {
+			occupancy = append(occupancy, float64(event.HeapAfter)/float64(event.HeapTotal)*100)
+		}
+		if event.AllocationRateToEvent > 0 {
+			allocation = append(allocation, event.AllocationRateToEvent)
+		}
+	}
+	for _, index := range m.pauseIndices(0, len(m.events)) {
+		if event := m.events[index]; event.Duration > 0 {
+			pauses = append(pauses, float64(event.Duration.Microseconds())/1000)
+		}
+	}
+
+	var metrics []pulseMetric
+	if len(occupancy) > 0 {
+		current := occupancy[len(occupancy)-1]
+		metrics = append(metrics, pulseMetric{"Heap", occupancy, utils.FormatPercent(current),
+			levelStyle(current/100, gc.HeapUtilWarning, gc.HeapUtilCritical)})
+	}
+	if len(pauses) > 0 {
+		p99 := utils.CalculatePercentile(slices.Sorted(slices.Values(pauses)), 99)
+		metrics = append(metrics, pulseMetric{"Pause", pauses, "p99 " + utils.FormatMillis(p99),
+			levelStyle(p99, float64(gc.PausePoor.Milliseconds()), float64(gc.PauseCritical.Milliseconds()))})
+	}
+	if len(allocation) > 0 {
+		rate := m.analysis.AllocationRate
+		metrics = append(metrics, pulseMetric{"Alloc", allocation, utils.FormatFloat(rate) + " MB/s",
+			levelStyle(rate, gc.AllocRateHigh, gc.AllocRateCritical)})
+	}
+	return metrics
+}
+
+// renderHealthBadge shows the health score the notifications report, colored by its verdict
+func renderHealthBadge(issues *gc.GCIssues) string {
+	score := notify.GCScore(issues)
+	verdict := notify.ScoreVerdict(score)
+	style, icon := utils.GoodStyle, "✅"
+	switch verdict {
+	case "Action needed":
+		style, icon = utils.CriticalStyle, "🔴"
+	case "Needs watching":
+		style, icon = utils.WarningStyle, "⚠️"
+	}
+	return style.Render(fmt.Sprintf("%s %s %d", icon, verdict, score))
+}
+
+func levelStyle(value, warning, critical float64) lipgloss.Style {
+	switch {
+	case value >= critical:
+		return utils.CriticalStyle
+	case value >= warning:
+		return utils.WarningStyle
+	}
+	return utils.GoodStyle
+}
+
+// bucketMax shrinks values to at most n points, keeping the highest of each stretch so spikes survive
+func bucketMax(values []float64, n int) []float64 {
+	if len(values) <= n {
+		return values
+	}
+	buckets := make([]float64, n)
+	for i := range buckets {
+		buckets[i] = slices.Max(values[i*len(values)/n : (i+1)*len(values)/n])
+	}
+	return buckets
+}
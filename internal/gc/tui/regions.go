@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * Region map
+ *
+ * G1 logs how many regions of each kind the heap had before and after every
+ * pause, not where they sit, so the map lays them out by kind: archive, old,
+ * humongous, survivor and eden regions fill the grid in that order and the
+ * rest is free. A heap with more regions than the grid has cells gives each
+ * cell several regions, but a kind that has any regions always keeps a cell.
+ *
+ * The map follows the trends cursor, so scrubbing with , and . steps through
+ * the collections; p plays them forward one per frame. Under the map a
+ * stacked chart shows what each collection left behind across the window,
+ * where old and humongous regions crowding out free ones stand out.
+ */
+
+const (
+	RegionMapRows     = 8  // Most rows a map takes; big heaps give each cell several regions
+	RegionMapMaxWidth = 64 // Widest a map gets, so it stays a grid rather than a strip
+	RegionChartHeight = 8
+
+	RegionFrameInterval = 150 * time.Millisecond // Time between collections while playing
+)
+
+// regionSegments are the kinds of region in layout order, free last
+var regionSegments = []utils.StackSegment{
+	{Label: "Archive", Glyph: "□", Style: LipglossRenderer{utils.MutedStyle}},
+	{Label: "Old", Glyph: "█", Style: LipglossRenderer{utils.WarningStyle}},
+	{Label: "Humongous", Glyph: "▓", Style: LipglossRenderer{utils.CriticalStyle}},
+	{Label: "Survivor", Glyph: "▒", Style: LipglossRenderer{utils.InfoStyle}},
+	{Label: "Eden", Glyph: "░", Style: LipglossRenderer{utils.GoodStyle}},
+	{Label: "Free", Glyph: "·", Style: LipglossRenderer{utils.MutedStyle}},
+}
+
+// regionFrameMsg advances playback; frames of a stopped or restarted playback are dropped
+type regionFrameMsg struct {
+	playback int
+}
+
+// hasRegions reports whether the event logged its region counts
+func hasRegions(event *gc.GCEvent) bool {
+	return event.EdenRegionsBefore > 0 || event.SurvivorRegionsBefore > 0 || event.OldRegionsBefore > 0 ||
+		event.HumongousRegionsBefore > 0
+}
+
+// regionCounts splits the heap before and after the event into regionSegments order
+func regionCounts(event *gc.GCEvent) (before, after []int) {
+	before = []int{event.ArchiveRegionsBefore, event.OldRegionsBefore, event.HumongousRegionsBefore,
+		event.SurvivorRegionsBefore, event.EdenRegionsBefore, 0}
+	after = []int{event.ArchiveRegionsAfter, event.OldRegionsAfter, event.HumongousRegionsAfter,
+		event.SurvivorRegionsAfter, event.EdenRegionsAfter, 0}
+
+	// The heap summary gives the region count, or else the heap size does. Without
+	// either the heap is taken to be what was in use before the collection.
+	total := event.HeapTotalRegions
+	if total == 0 && event.RegionSize > 0 {
+		total = int(event.HeapTotal / event.RegionSize)
+	}
+	usedBefore, usedAfter := 0, 0
+	for i := range before {
+		usedBefore += before[i]
+		usedAfter += after[i]
+	}
+	total = max(total, usedBefore, usedAfter)
+	before[len(before)-1] = total - usedBefore
+	after[len(after)-1] = total - usedAfter
+	return before, after
+}
+
+// regionEvents are the indices of the events with region counts, in log order
+func (m *Model) regionEvents() []int {
+	if m.cache.regions == nil {
+		m.cache.regions = make([]int, 0, len(m.events))
+		for i, event := range m.events {
+			if hasRegions(event) {
+				m.cache.regions = append(m.cache.regions, i)
+			}
+		}
+	}
+	return m.cache.regions
+}
+
+// regionEventAt is the last event with region counts at or before index, or the first one after it
+func (m *Model) regionEventAt(index int) (int, bool) {
+	regions := m.regionEvents()
+	if len(regions) == 0 {
+		return 0, false
+	}
+	i := sort.SearchInts(regions, index+1)
+	if i == 0 {
+		return regions[0], true
+	}
+	return regions[i-1], true
+}
+
+func (m *Model) renderRegionMap() string {
+	title := utils.TitleStyle.Render("G1 Region Map")
+
+	index, ok := m.regionEventAt(m.trendsState.cursor)
+	if !ok {
+		return title + "\n\n" + utils.MutedStyle.Render("No region counts in this log. G1 logs them with -Xlog:gc*")
+	}
+	event := m.events[index]
+	before, after := regionCounts(event)
+
+	mapWidth := m.calculateChartWidth()
+	if utils.LayoutFor(m.width) != utils.LayoutNarrow {
+		mapWidth = (mapWidth - 4) / 2
+	}
+	mapWidth = max(min(mapWidth, RegionMapMaxWidth), MinChartWidth)
+	maps := utils.Columns(m.width, 4,
+		utils.InfoStyle.Render("Before")+"\n"+renderRegionGrid(before, mapWidth),
+		utils.InfoStyle.Render("After")+"\n"+renderRegionGrid(after, mapWidth))
+
+	kind := event.Type
+	if event.Subtype != "" {
+		kind += " (" + event.Subtype + ")"
+	}
+	heading := fmt.Sprintf("GC(%d) %s at %s", event.ID, kind, event.Timestamp.Format("15:04:05"))
+	total := 0
+	for _, count := range before {
+		total += count
+	}
+	heading += utils.MutedStyle.Render(fmt.Sprintf("  %s regions", utils.FormatCount(int64(total))))
+	if event.RegionSize > 0 {
+		heading += utils.MutedStyle.Render(" of " + event.RegionSize.String())
+	}
+	if m.trendsState.playback > 0 {
+		heading += "  " + utils.GoodStyle.Render("▶ playing")
+	}
+
+	var counts []string
+	for i, segment := range regionSegments {
+		if before[i] == 0 && after[i] == 0 {
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%s %s %d → %d", segment.Style.Render(segment.Glyph), segment.Label, before[i], after[i]))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		heading,
+		strings.Join(counts, " • "),
+		"",
+		maps,
+		utils.MutedStyle.Render("Laid out by kind from the counts G1 logs; where each region sits isn't logged"),
+		"",
+		m.renderRegionHistory())
+}
+
+// renderRegionGrid draws the regions as cells, row by row in regionSegments order
+func renderRegionGrid(counts []int, width int) string {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return utils.MutedStyle.Render("No regions")
+	}
+	perCell := (total + width*RegionMapRows - 1) / (width * RegionMapRows)
+
+	cells := make([]int, len(counts))
+	used := 0
+	for i, count := range counts[:len(counts)-1] {
+		if count > 0 {
+			cells[i] = max((count+perCell/2)/perCell, 1)
+		}
+		used += cells[i]
+	}
+	cells[len(cells)-1] = max((total+perCell-1)/perCell-used, 0)
+
+	var sb strings.Builder
+	column := 0
+	for i, count := range cells {
+		segment := regionSegments[i]
+		for range count {
+			if column == width {
+				sb.WriteString("\n")
+				column = 0
+			}
+			sb.WriteString(segment.Style.Render(segment.Glyph))
+			column++
+		}
+	}
+
+	grid := sb.String()
+	if perCell > 1 {
+		grid += "\n" + utils.MutedStyle.Render(fmt.Sprintf("1 cell ≈ %d regions", perCell))
+	}
+	return grid
+}
+
+// regionHistory is the window's region counts after each collection, kept until the window changes
+type regionHistory struct {
+	bars         []utils.StackedBar
+	eventIndices []int
+	barOf        func(int) int
+}
+
+// renderRegionHistory stacks what each collection in the window left behind; with more collections
+// than columns the last in each column is kept
+func (m *Model) renderRegionHistory() string {
+	chartWidth := m.calculateChartWidth()
+	width := chartWidth - utils.YAxisLabelWidth
+	key := windowKey{view: "regions", start: m.trendsState.viewStart, end: m.trendsState.viewEnd, width: width}
+	data := cachedWindow(m, key, func() *regionHistory {
+		regions := m.regionEvents()
+		eventIndices := regions[sort.SearchInts(regions, m.trendsState.viewStart):sort.SearchInts(regions, m.trendsState.viewEnd)]
+		if len(eventIndices) == 0 {
+			return nil
+		}
+
+		barCount := min(len(eventIndices), width)
+		barOf := func(i int) int { return i * barCount / len(eventIndices) }
+
+		bars := make([]utils.StackedBar, barCount)
+		for i, index := range eventIndices {
+			event := m.events[index]
+			_, after := regionCounts(event)
+			values := make([]float64, len(regionSegments))
+			for j, count := range after {
+				values[j] = float64(count)
+			}
+			bars[barOf(i)] = utils.StackedBar{Timestamp: event.Timestamp, Values: values}
+		}
+		return &regionHistory{bars: bars, eventIndices: eventIndices, barOf: barOf}
+	})
+	if data == nil {
+		return utils.MutedStyle.Render("No collections with region counts in the window")
+	}
+
+	markers := m.chartMarkers(data.eventIndices)
+	markers.Remap(data.barOf)
+
+	chart := utils.CreateStackedBarChart(data.bars, regionSegments, "", utils.ChartConfig{
+		Width:   chartWidth,
+		Height:  RegionChartHeight,
+		Styles:  CreateChartStyles(),
+		Markers: markers,
+	})
+	return lipgloss.JoinVertical(lipgloss.Left, utils.TitleStyle.Render("Regions After Each GC"), "", chart)
+}
+
+// toggleRegionPlayback starts stepping the cursor through the collections, or stops it
+func (m *Model) toggleRegionPlayback() tea.Cmd {
+	state := m.trendsState
+	if state.playback > 0 {
+		state.playback = 0
+		return nil
+	}
+
+	// Playing from the end starts over at the start of the window
+	if regions := m.regionEvents(); len(regions) > 0 && state.cursor >= regions[len(regions)-1] {
+		m.moveTimelineCursor(state.viewStart - state.cursor)
+	}
+	state.frames++
+	state.playback = state.frames
+	return m.nextRegionFrame()
+}
+
+func (m *Model) nextRegionFrame() tea.Cmd {
+	playback := m.trendsState.playback
+	return tea.Tick(RegionFrameInterval, func(time.Time) tea.Msg { return regionFrameMsg{playback: playback} })
+}
+
+// stepRegionPlayback moves the cursor to the next collection with region counts, stopping
+// at the last one or once the region map is no longer shown
+func (m *Model) stepRegionPlayback(msg regionFrameMsg) tea.Cmd {
+	state := m.trendsState
+	if msg.playback != state.playback || state.playback == 0 {
+		return nil
+	}
+	if m.currentTab != TrendsTab || state.trendSubTab != RegionMapTrend {
+		state.playback = 0
+		return nil
+	}
+
+	regions := m.regionEvents()
+	next := sort.SearchInts(regions, state.cursor+1)
+	if next == len(regions) {
+		state.playback = 0
+		return nil
+	}
+	m.moveTimelineCursor(regions[next] - state.cursor)
+	return m.nextRegionFrame()
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+)
+
+// Huge logs have hundreds of thousands of events, far more than a chart has columns.
+// Trend series are downsampled once into levels that each halve the one below, and a
+// chart reads the coarsest level that still gives it a couple of points per column.
+// Aggregates over a window are kept until the window changes, so moving the cursor
+// or brush only redraws.
+
+const (
+	PointsPerColumn = 4  // Downsampled points a chart gets per column, two low and high pairs
+	MinSeriesLevel  = 64 // Points below which a series isn't downsampled further
+	MaxWindowCache  = 32 // Window aggregates kept before the cache starts over
+)
+
+// renderCache keeps what the views derive from every event, so frames don't rescan them
+type renderCache struct {
+	pauses     []int // Indices of the stop-the-world events, in log order
+	regions    []int // Indices of the events with region counts, in log order
+	series     map[TrendSubTab]*trendSeries
+	windows    map[windowKey]any
+	eventIndex map[int]int // GC ID to event index, for bookmarks
+
+	rows    []*gc.GCEvent // Events table in filter and sort order
+	rowsKey rowsKey
+}
+
+type windowKey struct {
+	view       string
+	trend      TrendSubTab
+	start, end int
+	width      int
+}
+
+type rowsKey struct {
+	filter EventFilter
+	sortBy EventSortBy
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{
+		series:  make(map[TrendSubTab]*trendSeries),
+		windows: make(map[windowKey]any),
+	}
+}
+
+// cachedWindow returns the aggregate stored under key, building it on first use
+func cachedWindow[T any](m *Model, key windowKey, build func() T) T {
+	if value, ok := m.cache.windows[key]; ok {
+		return value.(T)
+	}
+	if len(m.cache.windows) >= MaxWindowCache {
+		clear(m.cache.windows)
+	}
+	value := build()
+	m.cache.windows[key] = value
+	return value
+}
+
+// pauseIndices are the stop-the-world events between start and end, as event indices
+func (m *Model) pauseIndices(start, end int) []int {
+	if m.cache.pauses == nil {
+		m.cache.pauses = make([]int, 0, len(m.events))
+		for i, event := range m.events {
+			if !strings.Contains(event.Type, "Concurrent") {
+				m.cache.pauses = append(m.cache.pauses, i)
+			}
+		}
+	}
+	pauses := m.cache.pauses
+	return pauses[sort.SearchInts(pauses, start):sort.SearchInts(pauses, end)]
+}
+
+// windowPauses are the stop-the-world events in the trends window
+func (m *Model) windowPauses() []int {
+	return m.pauseIndices(m.trendsState.viewStart, m.trendsState.viewEnd)
+}
+
+// bookmarkIndices are the event indices of the bookmarked events, in log order
+func (m *Model) bookmarkIndices() []int {
+	if m.bookmarks == nil || len(m.bookmarks.Items) == 0 {
+		return nil
+	}
+	if m.cache.eventIndex == nil {
+		m.cache.eventIndex = make(map[int]int, len(m.events))
+		for i, event := range m.events {
+			if _, ok := m.cache.eventIndex[event.ID]; !ok {
+				m.cache.eventIndex[event.ID] = i
+			}
+		}
+	}
+
+	var indices []int
+	for _, bookmark := range m.bookmarks.Items {
+		if index, ok := m.cache.eventIndex[bookmark.ID]; ok {
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// trendSeries is one metric over the pauses, downsampled level by level. Each level
+// keeps the lowest and highest value of every four points of the level below, in
+// order, so spikes and dips survive zooming out.
+type trendSeries struct {
+	indices [][]int     // Per level, ascending event indices
+	values  [][]float64 // Per level, the metric at each index
+}
+
+// series returns the downsampled series of a trend, building it on first use
+func (m *Model) series(trend TrendSubTab, f func(*gc.GCEvent) float64) *trendSeries {
+	if series, ok := m.cache.series[trend]; ok {
+		return series
+	}
+
+	indices := m.pauseIndices(0, len(m.events))
+	values := make([]float64, len(indices))
+	for i, index := range indices {
+		values[i] = f(m.events[index])
+	}
+
+	series := &trendSeries{indices: [][]int{indices}, values: [][]float64{values}}
+	for len(indices) > MinSeriesLevel {
+		indices, values = downsample(indices, values)
+		series.indices = append(series.indices, indices)
+		series.values = append(series.values, values)
+	}
+	m.cache.series[trend] = series
+	return series
+}
+
+func downsample(indices []int, values []float64) ([]int, []float64) {
+	nextIndices := make([]int, 0, len(indices)/2+2)
+	nextValues := make([]float64, 0, len(indices)/2+2)
+
+	for start := 0; start < len(indices); start += 4 {
+		end := min(start+4, len(indices))
+		low, high := start, start
+		for i := start + 1; i < end; i++ {
+			if values[i] < values[low] {
+				low = i
+			}
+			if values[i] > values[high] {
+				high = i
+			}
+		}
+
+		first, second := min(low, high), max(low, high)
+		nextIndices = append(nextIndices, indices[first])
+		nextValues = append(nextValues, values[first])
+		if second != first {
+			nextIndices = append(nextIndices, indices[second])
+			nextValues = append(nextValues, values[second])
+		}
+	}
+	return nextIndices, nextValues
+}
+
+// window returns the points between the event indices start and end from the finest
+// level with at most maxPoints of them, and the level it read
+func (s *trendSeries) window(start, end, maxPoints int) ([]int, []float64, int) {
+	for level := range s.indices {
+		indices := s.indices[level]
+		from, to := sort.SearchInts(indices, start), sort.SearchInts(indices, end)
+		if to-from <= maxPoints || level == len(s.indices)-1 {
+			return indices[from:to], s.values[level][from:to], level
+		}
+	}
+	return nil, nil, 0
+}
@@ -0,0 +1,371 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MinZoomEvents = 10  // Narrowest window zooming in can reach
+	ZoomFactor    = 2.0 // Each +/- halves or doubles the window
+	CursorJump    = 10  // Events moved by < and >
+
+	MinZoomSpan = time.Minute // Narrowest wall-time window zooming in can reach
+	IdleGapRuns = 4           // Times the usual spacing between points a gap must span to read as idle
+
+	WindowAll time.Duration = math.MaxInt64 // Wall-time window over the whole log
+)
+
+// TrendWindows are the wall-time windows the window key cycles through
+var TrendWindows = []time.Duration{15 * time.Minute, time.Hour, 6 * time.Hour, WindowAll}
+
+// The trends window is [viewStart, viewEnd) over all events. The cursor is the
+// zoom anchor, and a brush runs from brushStart to the cursor.
+//
+// With a span the window is the events in the span of wall time up to its last
+// event, so quiet stretches and GC storms get the same width on the chart. Without
+// one it is a count of events, as zooming to a brush leaves it.
+
+// ParseTrendWindow reads a --window value: a duration such as 15m or 1h, or all
+func ParseTrendWindow(value string) (time.Duration, error) {
+	if value == "all" {
+		return WindowAll, nil
+	}
+	span, err := time.ParseDuration(value)
+	if err != nil || span <= 0 {
+		return 0, fmt.Errorf("invalid window '%s': use a duration such as 15m or 1h, or all", value)
+	}
+	return span, nil
+}
+
+func newTrendsState(eventCount int) *TrendsState {
+	return &TrendsState{
+		trendSubTab: HeapAfterTrend,
+		viewStart:   0,
+		viewEnd:     eventCount,
+		cursor:      max(eventCount-1, 0),
+		brushStart:  -1,
+	}
+}
+
+func (m *Model) visibleEvents() []*gc.GCEvent {
+	return m.events[m.trendsState.viewStart:m.trendsState.viewEnd]
+}
+
+// zoomTimeline scales the window around the cursor, keeping it at the same relative position
+func (m *Model) zoomTimeline(factor float64) {
+	state := m.trendsState
+	if state.span > 0 {
+		m.zoomTimeWindow(factor)
+		return
+	}
+
+	total := len(m.events)
+	oldWidth := state.viewEnd - state.viewStart
+	newWidth := min(max(int(float64(oldWidth)*factor+0.5), min(MinZoomEvents, total)), total)
+	if newWidth == oldWidth {
+		return
+	}
+
+	offset := (state.cursor - state.viewStart) * newWidth / max(oldWidth, 1)
+	m.setTimelineView(state.cursor-offset, newWidth)
+}
+
+// panTimeline shifts the window by a quarter of its width, dragging the cursor along
+func (m *Model) panTimeline(direction int) {
+	state := m.trendsState
+	if state.span > 0 {
+		end := m.events[state.viewEnd-1]
+		m.setTimeView(end.Timestamp.Add(m.windowSpan() / 4 * time.Duration(direction)))
+		if direction > 0 && m.events[state.viewEnd-1] == end && state.viewEnd < len(m.events) {
+			m.setTimeViewEnd(state.viewEnd) // Nothing within a quarter; skip ahead to the next event
+		}
+		m.clampTimelineCursor()
+		return
+	}
+
+	width := state.viewEnd - state.viewStart
+	step := max(width/4, 1) * direction
+
+	oldStart := state.viewStart
+	m.setTimelineView(state.viewStart+step, width)
+	state.cursor = min(max(state.cursor+state.viewStart-oldStart, state.viewStart), state.viewEnd-1)
+}
+
+func (m *Model) moveTimelineCursor(delta int) {
+	state := m.trendsState
+	state.cursor = min(max(state.cursor+delta, 0), len(m.events)-1)
+
+	if state.span > 0 {
+		if state.cursor < state.viewStart {
+			m.setTimeView(m.events[state.cursor].Timestamp.Add(m.windowSpan()))
+		} else if state.cursor >= state.viewEnd {
+			m.setTimeViewEnd(state.cursor)
+		}
+		return
+	}
+
+	width := state.viewEnd - state.viewStart
+	if state.cursor < state.viewStart {
+		m.setTimelineView(state.cursor, width)
+	} else if state.cursor >= state.viewEnd {
+		m.setTimelineView(state.cursor-width+1, width)
+	}
+}
+
+func (m *Model) setTimelineView(start, width int) {
+	state := m.trendsState
+	start = min(max(start, 0), len(m.events)-width)
+	state.viewStart = start
+	state.viewEnd = start + width
+}
+
+// cycleTimeWindow moves to the next wall-time window larger than the current one,
+// wrapping around, and keeps the cursor in view
+func (m *Model) cycleTimeWindow() {
+	next := TrendWindows[0]
+	if current := m.trendsState.span; current > 0 && current < WindowAll {
+		if index := slices.IndexFunc(TrendWindows, func(span time.Duration) bool { return span > current }); index >= 0 {
+			next = TrendWindows[index]
+		}
+	}
+	m.setTimeWindow(next)
+}
+
+// setTimeWindow windows the trends by span of wall time centered on the cursor
+func (m *Model) setTimeWindow(span time.Duration) {
+	state := m.trendsState
+	state.span = span
+	m.setTimeView(m.events[state.cursor].Timestamp.Add(m.windowSpan() / 2))
+	m.clampTimelineCursor()
+}
+
+// zoomTimeWindow scales the wall-time window around the cursor's timestamp
+func (m *Model) zoomTimeWindow(factor float64) {
+	state := m.trendsState
+	logSpan := m.logSpan()
+	span := time.Duration(float64(m.windowSpan()) * factor)
+	span = min(max(span, MinZoomSpan), logSpan)
+	if span == m.windowSpan() {
+		return
+	}
+
+	cursor := m.events[state.cursor].Timestamp
+	end := m.events[state.viewEnd-1].Timestamp
+	state.span = span
+	if span >= logSpan {
+		state.span = WindowAll
+	}
+	m.setTimeView(cursor.Add(time.Duration(float64(end.Sub(cursor)) * factor)))
+	m.clampTimelineCursor()
+}
+
+// setTimeView ends the wall-time window at the last event at or before end, keeping
+// the window within the log
+func (m *Model) setTimeView(end time.Time) {
+	state := m.trendsState
+	first, last := m.events[0].Timestamp, m.events[len(m.events)-1].Timestamp
+	span := m.windowSpan()
+	if span >= last.Sub(first) {
+		state.viewStart, state.viewEnd = 0, len(m.events)
+		return
+	}
+
+	if end.After(last) {
+		end = last
+	} else if firstEnd := first.Add(span); end.Before(firstEnd) {
+		end = firstEnd
+	}
+	m.setTimeViewEnd(sort.Search(len(m.events), func(i int) bool { return m.events[i].Timestamp.After(end) }) - 1)
+}
+
+// setTimeViewEnd windows the span of wall time up to the event at index end
+func (m *Model) setTimeViewEnd(end int) {
+	state := m.trendsState
+	start := m.events[end].Timestamp.Add(-m.windowSpan())
+	state.viewStart = sort.Search(end, func(i int) bool { return !m.events[i].Timestamp.Before(start) })
+	state.viewEnd = end + 1
+}
+
+func (m *Model) clampTimelineCursor() {
+	state := m.trendsState
+	state.cursor = min(max(state.cursor, state.viewStart), state.viewEnd-1)
+}
+
+// windowSpan is the wall time the window covers, at most the whole log
+func (m *Model) windowSpan() time.Duration {
+	return min(m.trendsState.span, m.logSpan())
+}
+
+func (m *Model) logSpan() time.Duration {
+	return m.events[len(m.events)-1].Timestamp.Sub(m.events[0].Timestamp)
+}
+
+// timeRange is the wall time a windowed chart spans, and the gap between plotted
+// points past which the line breaks for an idle period; zero outside time windows
+func (m *Model) timeRange(timestamps []time.Time, columns int) (from, to time.Time, gap time.Duration) {
+	state := m.trendsState
+	if state.span == 0 || len(timestamps) < 2 {
+		return
+	}
+	to = m.events[state.viewEnd-1].Timestamp
+	from = to.Add(-m.windowSpan())
+
+	intervals := make([]time.Duration, len(timestamps)-1)
+	for i := range intervals {
+		intervals[i] = timestamps[i+1].Sub(timestamps[i])
+	}
+	slices.Sort(intervals)
+	gap = max(intervals[len(intervals)/2]*IdleGapRuns, to.Sub(from)/time.Duration(max(columns, 1))*2)
+	return from, to, gap
+}
+
+// windowLabel names a wall-time window the way --window takes it
+func windowLabel(span time.Duration) string {
+	switch {
+	case span == WindowAll:
+		return "all"
+	case span%time.Hour == 0:
+		return fmt.Sprintf("%dh", span/time.Hour)
+	case span%time.Minute == 0:
+		return fmt.Sprintf("%dm", span/time.Minute)
+	}
+	return span.String()
+}
+
+// toggleBrush anchors a selection at the cursor, or drops the current one
+func (m *Model) toggleBrush() {
+	if m.trendsState.brushStart >= 0 {
+		m.trendsState.brushStart = -1
+		return
+	}
+	m.trendsState.brushStart = m.trendsState.cursor
+}
+
+// zoomToBrush narrows the window to the brushed events
+func (m *Model) zoomToBrush() {
+	from, to, ok := m.brushRange()
+	if !ok {
+		return
+	}
+	width := max(to-from+1, min(MinZoomEvents, len(m.events)))
+	m.trendsState.span = 0
+	m.setTimelineView(from, width)
+	m.trendsState.brushStart = -1
+}
+
+func (m *Model) resetTimeline() {
+	*m.trendsState = *newTrendsState(len(m.events))
+	if m.trendWindow > 0 {
+		m.setTimeWindow(m.trendWindow)
+	}
+}
+
+// brushRange is the inclusive range of brushed events
+func (m *Model) brushRange() (from, to int, ok bool) {
+	state := m.trendsState
+	if state.brushStart < 0 {
+		return 0, 0, false
+	}
+	return min(state.brushStart, state.cursor), max(state.brushStart, state.cursor), true
+}
+
+// chartMarkers maps the cursor, brush and bookmarks onto the plotted points, given the
+// ascending event index of each point. Events between points land on the next one.
+func (m *Model) chartMarkers(eventIndices []int) *utils.PlotMarkers {
+	markers := &utils.PlotMarkers{Cursor: -1, SelectFrom: -1, SelectTo: -1}
+	if len(eventIndices) == 0 {
+		return markers
+	}
+	point := func(index int) int { return sort.SearchInts(eventIndices, index) }
+
+	if cursor := point(m.trendsState.cursor); cursor < len(eventIndices) {
+		markers.Cursor = cursor
+	}
+	if from, to, ok := m.brushRange(); ok {
+		if first, end := point(from), point(to+1); first < end {
+			markers.SelectFrom, markers.SelectTo = first, end-1
+		}
+	}
+	for _, index := range m.bookmarkIndices() {
+		if index >= m.trendsState.viewStart && index < m.trendsState.viewEnd {
+			if bookmark := point(index); bookmark < len(eventIndices) {
+				markers.Bookmarks = append(markers.Bookmarks, bookmark)
+			}
+		}
+	}
+	return markers
+}
+
+func (m *Model) renderTimelineInfo() string {
+	state := m.trendsState
+	total := len(m.events)
+	first, last := m.events[state.viewStart], m.events[state.viewEnd-1]
+
+	info := fmt.Sprintf("Events %s–%s of %s (%s – %s)",
+		utils.FormatCount(int64(state.viewStart+1)), utils.FormatCount(int64(state.viewEnd)), utils.FormatCount(int64(total)),
+		first.Timestamp.Format("15:04:05"), last.Timestamp.Format("15:04:05"))
+	if state.span > 0 {
+		info += " • window " + windowLabel(state.span)
+	} else if width := state.viewEnd - state.viewStart; width < total {
+		info += fmt.Sprintf(" • zoom %sx", utils.FormatFloat(float64(total)/float64(width)))
+	}
+
+	cursor := m.events[state.cursor]
+	info += fmt.Sprintf(" • cursor GC(%d) %s", cursor.ID, cursor.Timestamp.Format("15:04:05"))
+	if _, ok := m.bookmarks.Get(cursor); ok {
+		info += " ◆"
+	}
+	if from, to, ok := m.brushRange(); ok {
+		info += fmt.Sprintf(" • brush %d events", to-from+1)
+	}
+	return utils.MutedStyle.Render(info)
+}
+
+// renderRangeStats summarizes a trend's metric over the brushed pauses, or the whole
+// window without a brush. It reads every pause rather than the downsampled points,
+// and is cached per range.
+func (m *Model) renderRangeStats(f func(*gc.GCEvent) float64, unit string) string {
+	label := "Window"
+	from, to := m.trendsState.viewStart, m.trendsState.viewEnd-1
+	if brushFrom, brushTo, ok := m.brushRange(); ok {
+		label = "Brush"
+		from, to = brushFrom, brushTo
+	}
+
+	key := windowKey{view: "stats " + label, trend: m.trendsState.trendSubTab, start: from, end: to}
+	return cachedWindow(m, key, func() string {
+		indices := m.pauseIndices(from, to+1)
+		if len(indices) == 0 {
+			return utils.MutedStyle.Render(label + ": no points in range")
+		}
+
+		sorted := make([]float64, len(indices))
+		for i, index := range indices {
+			sorted[i] = f(m.events[index])
+		}
+		slices.Sort(sorted)
+		format := func(value float64) string {
+			if unit == "ms" {
+				return utils.FormatMillis(value)
+			}
+			return utils.FormatFloat(value) + " " + unit
+		}
+
+		parts := []string{
+			fmt.Sprintf("%s (%d points)", label, len(sorted)),
+			"avg " + format(utils.CalculateMean(sorted)),
+			"P95 " + format(utils.CalculatePercentile(sorted, 95)),
+			"P99 " + format(utils.CalculatePercentile(sorted, 99)),
+			"max " + format(sorted[len(sorted)-1]),
+		}
+		return utils.TitleStyle.Render(parts[0]) + "  " + strings.Join(parts[1:], " • ")
+	})
+}
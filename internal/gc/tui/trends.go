@@ -21,13 +21,16 @@ const (
 
 // Tab names for cleaner code
 var trendNames = map[TrendSubTab]string{
-	HeapAfterTrend:     "HeapAfter",
-	HeapBeforeTrend:    "HeapBefore",
-	MemReclaimedTrend:  "MemReclaimed",
-	GCDurationTrend:    "GCDuration",
-	PauseDurationTrend: "PauseDuration",
-	PromotionTrend:     "Promotion",
-	FrequencyTrend:     "Collection Freq",
+	HeapAfterTrend:      "HeapAfter",
+	HeapBeforeTrend:     "HeapBefore",
+	MemReclaimedTrend:   "MemReclaimed",
+	GCDurationTrend:     "GCDuration",
+	PauseDurationTrend:  "PauseDuration",
+	PauseHeatmapTrend:   "Heatmap",
+	PhaseBreakdownTrend: "Phases",
+	PromotionTrend:      "Promotion",
+	RegionMapTrend:      "Regions",
+	FrequencyTrend:      "Collection Freq",
 }
 
 func (m *Model) RenderTrends() string {
@@ -35,7 +38,7 @@ func (m *Model) RenderTrends() string {
 		return utils.MutedStyle.Render("No GC events available for trend analysis.")
 	}
 
-	events := m.getRecentEvents()
+	events := m.visibleEvents()
 	if len(events) < 2 {
 		return utils.MutedStyle.Render("Insufficient data for trend analysis.\n\nAt least 2 GC events are required.")
 	}
@@ -58,12 +61,45 @@ func (m *Model) renderTrendsHeader() string {
 	}
 
 	tabLine := strings.Join(tabs, "  ")
-	infoLine := utils.MutedStyle.Render(fmt.Sprintf("Showing last %d events", m.trendsState.timeWindow))
+	if lipgloss.Width(tabLine) > m.width {
+		// Too narrow for every name; show the current one and where it sits
+		current := m.trendsState.trendSubTab
+		tabLine = fmt.Sprintf("◀ %s ▶ %s", utils.TabActiveStyle.Render(trendNames[current]),
+			utils.MutedStyle.Render(fmt.Sprintf("%d/%d", current+1, FrequencyTrend+1)))
+	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, tabLine, infoLine)
+	header := lipgloss.JoinVertical(lipgloss.Left, tabLine, m.renderTimelineInfo())
+	if bookmarks := m.renderBookmarksInView(); bookmarks != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, bookmarks)
+	}
+	return header
 }
 
+// renderTrendsContent is the current trend; wide terminals show the pause phases next to pause trends
 func (m *Model) renderTrendsContent(events []*gc.GCEvent) string {
+	chart := m.renderTrendChart(events)
+	if !m.hasSidePanel() {
+		return chart
+	}
+
+	sideWidth := m.width - m.calculateChartWidth() - ChartMarginWidth
+	side := m.renderPhaseBreakdown(max(MinChartWidth, sideWidth-ChartMarginWidth))
+	return utils.Columns(m.width, 4, chart, lipgloss.NewStyle().Width(sideWidth).Render(side))
+}
+
+// hasSidePanel reports whether the current trend gets the phase chart beside it
+func (m *Model) hasSidePanel() bool {
+	if utils.LayoutFor(m.width) != utils.LayoutWide {
+		return false
+	}
+	switch m.trendsState.trendSubTab {
+	case GCDurationTrend, PauseDurationTrend, PauseHeatmapTrend:
+		return true
+	}
+	return false
+}
+
+func (m *Model) renderTrendChart(events []*gc.GCEvent) string {
 	switch m.trendsState.trendSubTab {
 	case HeapAfterTrend:
 		return m.renderHeapTrends(events, "Heap After GC", "MB",
@@ -88,16 +124,19 @@ func (m *Model) renderTrendsContent(events []*gc.GCEvent) string {
 				return float64(gcDuration.Nanoseconds()) / 1e6 // convert to ms
 			})
 	case PauseDurationTrend:
-		return m.renderHeapTrends(events, "GC Pause Duration", "ms",
-			func(e *gc.GCEvent) float64 {
-				return float64(e.Duration.Nanoseconds()) / 1e6
-			})
+		return m.renderPauseBands()
+	case PauseHeatmapTrend:
+		return m.renderPauseHeatmap()
+	case PhaseBreakdownTrend:
+		return m.renderPhaseBreakdown(m.calculateChartWidth())
 	case PromotionTrend:
 		result := m.renderHeapTrends(events, "Young -> Old Promotions", "MB",
 			func(e *gc.GCEvent) float64 {
 				return e.RegionSize.Mul(float64(GetPromotedRegions(e))).MB()
 			})
 		return result + "\n" + utils.MutedStyle.Render("Cannot calculate reliably for Mixed and Full GC")
+	case RegionMapTrend:
+		return m.renderRegionMap()
 	case FrequencyTrend:
 		return m.renderFrequencyTrends(events)
 	default:
@@ -116,31 +155,36 @@ func (m *Model) renderHeapTrends(
 
 	title := utils.TitleStyle.Render(header)
 
-	// Extract data from events
-	values := make([]float64, 0)
-	timestamps := make([]time.Time, 0)
-	gcTypes := make([]string, 0)
+	// Big windows read a downsampled level, a few points per column
+	chartWidth := m.calculateChartWidth()
+	eventIndices, values, level := m.series(m.trendsState.trendSubTab, f).window(
+		m.trendsState.viewStart, m.trendsState.viewEnd, chartWidth*PointsPerColumn)
 
-	for _, event := range events {
-		if strings.Contains(event.Type, "Concurrent") {
-			continue
-		}
-		values = append(values, f(event))
-		timestamps = append(timestamps, event.Timestamp)
-		gcTypes = append(gcTypes, event.Type)
+	if len(values) == 0 {
+		return title + "\n\nNo data available"
+	}
+	if level > 0 {
+		title += utils.MutedStyle.Render(fmt.Sprintf("  (low and high of every %d pauses)", 1<<(level+1)))
 	}
 
-	if len(values) == 0 {
-		return utils.TitleStyle.Render(title) + "\n\nNo data available"
+	timestamps := make([]time.Time, len(eventIndices))
+	gcTypes := make([]string, len(eventIndices))
+	for i, index := range eventIndices {
+		timestamps[i] = m.events[index].Timestamp
+		gcTypes[i] = m.events[index].Type
 	}
 
-	chart := CreatePlotFromGCData(values, timestamps, gcTypes, unit, m.calculateChartWidth(), ChartHeight)
+	config := utils.ChartConfig{Width: chartWidth, Height: ChartHeight, Markers: m.chartMarkers(eventIndices)}
+	config.From, config.To, config.Gap = m.timeRange(timestamps, chartWidth)
+	chart := CreatePlotFromGCData(values, timestamps, gcTypes, unit, config)
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		utils.TitleStyle.Render(title),
+		title,
+		"",
+		chart,
 		"",
-		chart)
+		m.renderRangeStats(f, unit))
 }
 
 func (m *Model) renderFrequencyTrends(events []*gc.GCEvent) string {
@@ -166,6 +210,8 @@ func (m *Model) renderFrequencyTrends(events []*gc.GCEvent) string {
 		"Young":           utils.GoodStyle,
 		"Mixed":           utils.InfoStyle,
 		"Full":            utils.CriticalStyle,
+		"Remark":          utils.WarningLightStyle,
+		"Cleanup":         utils.WarningLightStyle,
 		"Concurrent Mark": utils.WarningStyle,
 		"Other":           utils.MutedStyle,
 	}
@@ -190,8 +236,8 @@ func (m *Model) renderFrequencyTrends(events []*gc.GCEvent) string {
 	config := utils.DefaultBarConfig(m.calculateChartWidth() - FreqChartPad)
 	config.ValueFormat = "%.1fms"
 
-	chartTitle := fmt.Sprintf("Time Distribution (last %d events, %.1fms total):",
-		len(events), float64(totalDuration.Nanoseconds())/1e6)
+	chartTitle := fmt.Sprintf("Time Distribution (last %d events, %s total):",
+		len(events), utils.FormatMillis(float64(totalDuration.Nanoseconds())/1e6))
 	sections := []string{utils.CreateHorizontalBarChart(chartTitle, bars, config)}
 
 	// Add frequency analysis
@@ -202,7 +248,7 @@ func (m *Model) renderFrequencyTrends(events []*gc.GCEvent) string {
 			gcPerHour := float64(time.Hour) / float64(avgInterval)
 			sections = append(sections, "",
 				fmt.Sprintf("Average Interval: %s", utils.FormatDuration(avgInterval)),
-				fmt.Sprintf("GC Events/Hour: %.1f", gcPerHour))
+				fmt.Sprintf("GC Events/Hour: %s", utils.FormatFloat(gcPerHour)))
 		}
 	}
 
@@ -273,18 +319,15 @@ func (m *Model) renderGCCausesChart(events []*gc.GCEvent) string {
 	barChart := utils.CreateHorizontalBarChart("GC Causes (Total Time)", bars, config)
 	totalMs := float64(totalDuration.Nanoseconds()) / 1e6
 
-	return fmt.Sprintf("%s\n\nTotal GC Time: %.1f ms", barChart, totalMs)
+	return fmt.Sprintf("%s\n\nTotal GC Time: %s ms", barChart, utils.FormatFloat(totalMs))
 }
 
 func (m *Model) calculateChartWidth() int {
-	return max(MinChartWidth, m.width-ChartMarginWidth)
-}
-
-func (m *Model) getRecentEvents() []*gc.GCEvent {
-	if len(m.events) <= m.trendsState.timeWindow {
-		return m.events
+	width := m.width
+	if m.hasSidePanel() {
+		width = m.width * 3 / 5
 	}
-	return m.events[len(m.events)-m.trendsState.timeWindow:]
+	return max(MinChartWidth, width-ChartMarginWidth)
 }
 
 func GetPromotedRegions(e *gc.GCEvent) int {
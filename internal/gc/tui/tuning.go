@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// renderTuningPlan shows the consolidated flags as a current-vs-proposed diff and the line to paste
+func (m *Model) renderTuningPlan() string {
+	if len(m.plan.Changes) == 0 {
+		return utils.GoodStyle.Render("✅ No flag changes proposed.") + "\n\n" +
+			utils.MutedStyle.Render("The issues found need code or heap size changes rather than new flags.")
+	}
+
+	flagWidth, currentWidth, proposedWidth := len("Flag"), len("Current"), len("Proposed")
+	for _, change := range m.plan.Changes {
+		flagWidth = max(flagWidth, len(change.Flag))
+		currentWidth = max(currentWidth, lipgloss.Width(currentValue(change)))
+		proposedWidth = max(proposedWidth, lipgloss.Width(proposedValue(change)))
+	}
+
+	lines := []string{
+		utils.MutedStyle.Render(fmt.Sprintf("  %-*s  %-*s  %s", flagWidth, "Flag", currentWidth+2, "Current", "Proposed")),
+	}
+	for i, change := range m.plan.Changes {
+		selected := i == m.GetSelectedIssue()
+		selector := " "
+		if selected {
+			selector = "▶"
+		}
+
+		flag := fmt.Sprintf("%s %-*s", selector, flagWidth, change.Flag)
+		if selected {
+			flag = utils.SelectedStyle.Render(flag)
+		}
+		current := utils.CriticalLightStyle.Render(fmt.Sprintf("- %-*s", currentWidth, currentValue(change)))
+		proposed := utils.GoodStyle.Render(fmt.Sprintf("+ %-*s", proposedWidth, proposedValue(change)))
+		lines = append(lines, fmt.Sprintf("%s  %s  %s  %s", flag, current, proposed,
+			utils.GetSeverityStyle(change.Severity).Render(utils.GetSeverityIcon(change.Severity))))
+
+		if m.IsIssueExpanded(i) {
+			for _, line := range utils.WrapText(change.Reason, m.width-8) {
+				lines = append(lines, utils.TextStyle.Render("     "+line))
+			}
+			lines = append(lines, utils.MutedStyle.Render("     From: "+strings.Join(change.Issues, ", ")))
+		}
+	}
+
+	lines = append(lines, "", utils.InfoStyle.Render(fmt.Sprintf("Flag line (%s:copy)", keymap.Label(ActionCopyFlags))))
+	for _, line := range utils.WrapText(m.plan.FlagLine(), m.width-4) {
+		lines = append(lines, "  "+line)
+	}
+	lines = append(lines, "", utils.MutedStyle.Render("Try the changes one at a time under load; the most severe issue wins where issues disagree."))
+
+	return strings.Join(lines, "\n")
+}
+
+// currentValue is the value the JVM runs with, noting when it is only the default
+func currentValue(change gc.FlagChange) string {
+	value := change.Current
+	switch value {
+	case "":
+		return "unset"
+	case "+":
+		value = "on"
+	case "-":
+		value = "off"
+	}
+	if change.Default {
+		value += " (default)"
+	}
+	return value
+}
+
+func proposedValue(change gc.FlagChange) string {
+	switch change.Proposed {
+	case "+":
+		return "on"
+	case "-":
+		return "off"
+	}
+	return change.Proposed
+}
+
+// copyFlagLine puts the tuning plan's flags on the clipboard
+func (m *Model) copyFlagLine() {
+	if len(m.plan.Changes) == 0 {
+		m.statusMessage = "No flag changes to copy"
+		return
+	}
+	utils.CopyToClipboard(m.plan.FlagLine())
+	m.statusMessage = fmt.Sprintf("📋 Copied %d flags to the clipboard", len(m.plan.Changes))
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/mabhi256/jdiag/internal/gc"
 )
 
@@ -9,6 +11,7 @@ type Model struct {
 	events   []*gc.GCEvent
 	analysis *gc.GCAnalysis
 	issues   *gc.GCIssues
+	plan     *gc.TuningPlan
 
 	// UI State
 	currentTab TabType
@@ -20,6 +23,21 @@ type Model struct {
 	issuesState     *IssuesState
 	eventsState     *EventsState
 	trendsState     *TrendsState
+	trendWindow     time.Duration // Window the trends view opens and resets to, see ParseTrendWindow
+	cache           *renderCache
+
+	bookmarks *gc.Bookmarks
+	noteInput *NoteInput // Open while a bookmark note is being typed
+
+	statusMessage string // Shown in the footer until the next key press
+	pendingKey    string // First key of a sequence such as gg
+	showHelp      bool   // Key help overlay over the current tab
+}
+
+// NoteInput is the footer editor for a bookmark's note
+type NoteInput struct {
+	event *gc.GCEvent
+	text  string
 }
 
 type TabType int
@@ -32,6 +50,18 @@ const (
 	TrendsTab
 )
 
+var tabTypeName = map[TabType]string{
+	DashboardTab: "summary",
+	MetricsTab:   "metrics",
+	IssuesTab:    "issues",
+	EventsTab:    "events",
+	TrendsTab:    "trends",
+}
+
+func (t TabType) String() string {
+	return tabTypeName[t]
+}
+
 type IssuesState struct {
 	selectedSubTab   IssuesSubTab
 	expandedIssues   map[IssueKey]bool
@@ -44,12 +74,14 @@ const (
 	CriticalIssues IssuesSubTab = iota
 	WarningIssues
 	InfoIssues
+	TuningPlanIssues // Flags consolidated from all issues, see gc.TuningPlan
 )
 
 var issueTypeName = map[IssuesSubTab]string{
-	CriticalIssues: "critical",
-	WarningIssues:  "warning",
-	InfoIssues:     "info",
+	CriticalIssues:   "critical",
+	WarningIssues:    "warning",
+	InfoIssues:       "info",
+	TuningPlanIssues: "tuning plan",
 }
 
 func (ss IssuesSubTab) String() string {
@@ -77,6 +109,8 @@ type EventsState struct {
 	sortBy        EventSortBy
 	searchTerm    string
 	showDetails   bool
+	detailView    bool // Full-screen drill-down of the selected event
+	detailScroll  int
 }
 
 type EventFilter int
@@ -99,7 +133,13 @@ const (
 
 type TrendsState struct {
 	trendSubTab TrendSubTab
-	timeWindow  int // number of recent events to show
+	viewStart   int           // First event in the zoomed window
+	viewEnd     int           // One past the last event in the window
+	span        time.Duration // Wall time the window covers, ending at its last event; 0 windows by event count
+	cursor      int           // Selected event, anchors zoom and the brush
+	brushStart  int           // Other end of the brush selection, -1 when there is none
+	playback    int           // Region map playback running, 0 when stopped
+	frames      int           // Playbacks started, so a stopped one's frames are dropped
 }
 
 type TrendSubTab int
@@ -110,7 +150,10 @@ const (
 	MemReclaimedTrend
 	GCDurationTrend
 	PauseDurationTrend
+	PauseHeatmapTrend
+	PhaseBreakdownTrend
 	PromotionTrend
+	RegionMapTrend
 	FrequencyTrend
 )
 
@@ -142,3 +185,46 @@ func (m *Model) IsIssueExpanded(id int) bool {
 	}
 	return m.issuesState.expandedIssues[issueKey]
 }
+
+// CompareSide is one of the two logs in a comparison
+type CompareSide struct {
+	Name     string
+	Events   []*gc.GCEvent
+	Analysis *gc.GCAnalysis
+}
+
+type CompareView int
+
+const (
+	CompareStatsView CompareView = iota
+	ComparePauseView
+	CompareHeapAfterView
+	CompareHeapBeforeView
+	CompareReclaimedView
+)
+
+var compareViewNames = map[CompareView]string{
+	CompareStatsView:      "Stats",
+	ComparePauseView:      "Pause Duration",
+	CompareHeapAfterView:  "Heap After",
+	CompareHeapBeforeView: "Heap Before",
+	CompareReclaimedView:  "Reclaimed",
+}
+
+func (v CompareView) String() string {
+	return compareViewNames[v]
+}
+
+// CompareModel shows a baseline log and a candidate log side by side
+type CompareModel struct {
+	baseline  CompareSide
+	candidate CompareSide
+
+	view   CompareView
+	scroll int
+	width  int
+	height int
+
+	statusMessage string
+	pendingKey    string
+}
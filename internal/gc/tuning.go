@@ -0,0 +1,160 @@
+package gc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// FlagChange is one JVM flag of a tuning plan, with the issue that asked for it
+type FlagChange struct {
+	Flag     string // As written on the command line, e.g. "-XX:G1ReservePercent"
+	Current  string // Value in the log, the JVM default, or "" when unknown
+	Default  bool   // Current is the JVM default rather than read from the log
+	Proposed string // Value to set; "+" or "-" for boolean flags
+	Severity string // Of the first issue proposing it
+	Reason   string // Recommendation text it came from
+	Issues   []string
+}
+
+// Option renders the change as a command-line option, e.g. -XX:G1ReservePercent=20
+func (c FlagChange) Option() string {
+	switch {
+	case c.Proposed == "+" || c.Proposed == "-":
+		return strings.Replace(c.Flag, "-XX:", "-XX:"+c.Proposed, 1)
+	case strings.HasPrefix(c.Flag, "-XX:"):
+		return c.Flag + "=" + c.Proposed
+	default:
+		return c.Flag + c.Proposed
+	}
+}
+
+// TuningPlan consolidates the flags suggested across all issues into one set of changes
+type TuningPlan struct {
+	Changes []FlagChange
+}
+
+var (
+	// -XX:+Flag, -XX:Flag=value or -Xmx<size>, as they appear in recommendation text
+	flagPattern = regexp.MustCompile(`-XX:([+-]?)(\w+)(?:=(\S+))?|-(Xmx|Xms|Xmn)(\S+)`)
+
+	// G1 defaults of JDK 17, used when the log doesn't show a flag's value
+	flagDefaults = map[string]string{
+		"MaxGCPauseMillis":               "200",
+		"G1ReservePercent":               "10",
+		"G1NewSizePercent":               "5",
+		"G1MaxNewSizePercent":            "60",
+		"G1MixedGCCountTarget":           "8",
+		"G1MixedGCLiveThresholdPercent":  "85",
+		"G1HeapOccupancyPercent":         "45",
+		"InitiatingHeapOccupancyPercent": "45",
+		"SurvivorRatio":                  "8",
+		"MaxTenuringThreshold":           "15",
+		"HeapDumpOnOutOfMemoryError":     "-",
+	}
+
+	// experimentalFlags need -XX:+UnlockExperimentalVMOptions ahead of them
+	experimentalFlags = []string{"G1NewSizePercent", "G1MaxNewSizePercent", "G1MixedGCLiveThresholdPercent"}
+)
+
+// NewTuningPlan collects the flags from the recommendations, most severe issue first.
+// When issues disagree on a value the most severe one wins. Placeholders such as
+// -Xmx<size * 2>, logging flags and collector switches are left out, and so are
+// values the JVM already runs with.
+func NewTuningPlan(analysis *GCAnalysis, issues *GCIssues) *TuningPlan {
+	plan := &TuningPlan{}
+
+	for _, group := range [][]PerformanceIssue{issues.Critical, issues.Warning, issues.Info} {
+		for _, issue := range group {
+			for _, recommendation := range issue.Recommendation {
+				for _, match := range flagPattern.FindAllStringSubmatch(recommendation, -1) {
+					change, ok := parseFlagChange(match, analysis)
+					if !ok {
+						continue
+					}
+					change.Severity = issue.Severity
+					change.Reason = recommendation
+					plan.add(change, issue.Type)
+				}
+			}
+		}
+	}
+	return plan
+}
+
+func parseFlagChange(match []string, analysis *GCAnalysis) (FlagChange, bool) {
+	if match[4] != "" {
+		// -Xmx and friends; only concrete sizes
+		if strings.ContainsAny(match[5], "<>*") {
+			return FlagChange{}, false
+		}
+		change := FlagChange{Flag: "-" + match[4], Proposed: match[5]}
+		if match[4] == "Xmx" && analysis.HeapMax > 0 {
+			change.Current = jvmSize(analysis.HeapMax)
+		}
+		return change, true
+	}
+
+	sign, name, value := match[1], match[2], strings.TrimRight(match[3], ".,;)")
+	if strings.HasPrefix(name, "Print") || strings.HasPrefix(name, "Trace") ||
+		(strings.HasPrefix(name, "Use") && strings.HasSuffix(name, "GC")) ||
+		strings.ContainsAny(value, "<>/") || (sign == "" && value == "") {
+		return FlagChange{}, false
+	}
+
+	current, known := flagDefaults[name]
+	change := FlagChange{Flag: "-XX:" + name, Proposed: value, Current: current, Default: known}
+	if sign != "" {
+		change.Proposed = sign
+	}
+	if name == "G1HeapRegionSize" && analysis.HeapRegionSize > 0 {
+		change.Current = jvmSize(analysis.HeapRegionSize)
+		change.Default = false
+	}
+	if strings.EqualFold(change.Current, change.Proposed) {
+		return FlagChange{}, false
+	}
+	return change, true
+}
+
+// jvmSize writes a size the way -Xmx and size flags take it, e.g. 32m
+func jvmSize(size utils.MemorySize) string {
+	switch {
+	case size%utils.GB == 0:
+		return fmt.Sprintf("%dg", size/utils.GB)
+	case size%utils.MB == 0:
+		return fmt.Sprintf("%dm", size/utils.MB)
+	case size%utils.KB == 0:
+		return fmt.Sprintf("%dk", size/utils.KB)
+	}
+	return fmt.Sprintf("%d", size)
+}
+
+func (p *TuningPlan) add(change FlagChange, issue string) {
+	index := slices.IndexFunc(p.Changes, func(c FlagChange) bool { return c.Flag == change.Flag })
+	if index < 0 {
+		change.Issues = []string{issue}
+		p.Changes = append(p.Changes, change)
+		return
+	}
+	if !slices.Contains(p.Changes[index].Issues, issue) {
+		p.Changes[index].Issues = append(p.Changes[index].Issues, issue)
+	}
+}
+
+// FlagLine is every proposed change as one line to paste into JAVA_OPTS
+func (p *TuningPlan) FlagLine() string {
+	var options []string
+	experimental := false
+	for _, change := range p.Changes {
+		experimental = experimental || slices.Contains(experimentalFlags, strings.TrimPrefix(change.Flag, "-XX:"))
+		options = append(options, change.Option())
+	}
+	if experimental {
+		options = append([]string{"-XX:+UnlockExperimentalVMOptions"}, options...)
+	}
+	return strings.Join(options, " ")
+}
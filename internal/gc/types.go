@@ -54,6 +54,20 @@ type GCEvent struct {
 	SurvivorMemoryBefore  utils.MemorySize
 	SurvivorMemoryAfter   utils.MemorySize
 
+	// [gc,heap] GC(0) PSYoungGen: 28949K(153088K)->2515K(153088K) Eden: 28949K(131584K)->0K(131584K) From: 0K(21504K)->2515K(21504K)
+	// Eden and one survivor space after the collection (Parallel, Serial)
+	EdenCapacity     utils.MemorySize
+	SurvivorCapacity utils.MemorySize
+
+	// [gc,age] GC(0) Desired survivor size 7340032 bytes, new threshold 1 (max threshold 15)
+	DesiredSurvivorSize  utils.MemorySize
+	TenuringThreshold    int
+	MaxTenuringThreshold int
+
+	// [gc,age] GC(0) - age   1:    1678440 bytes,    1678440 total
+	AgeTableTotal  utils.MemorySize // Bytes the collection copied to survivor space, all ages
+	AgeTableLogged bool
+
 	// [gc,heap] GC(0) Old regions: 2->42
 	OldRegionsBefore int
 	OldRegionsAfter  int
@@ -72,6 +86,13 @@ type GCEvent struct {
 	HumongousMemoryBefore  utils.MemorySize
 	HumongousMemoryAfter   utils.MemorySize
 
+	// [gc,heap] GC(0) Archive regions: 2->2
+	// Pinned regions holding the CDS archived heap objects (JDK 11 to 17); never collected
+	ArchiveRegionsBefore int
+	ArchiveRegionsAfter  int
+	ArchiveMemoryBefore  utils.MemorySize
+	ArchiveMemoryAfter   utils.MemorySize
+
 	// [gc,heap] GC(0)  garbage-first heap   total 262144K, used 66610K
 	HeapTotalRegions      int
 	HeapUsedRegionsBefore int
@@ -81,9 +102,32 @@ type GCEvent struct {
 	WorkersUsed      int
 	WorkersAvailable int
 
+	// [gc,phases] GC(0) Object Copy (ms): Min: 1.8, Avg: 3.4, Max: 4.3, Diff: 2.5, Sum: 27.0, Workers: 8
+	WorkerPhases []WorkerPhase
+
+	// [gc,phases] GC(4) Phase 1: Mark live objects 3.467ms
+	// Full GC phases in log order, under the names FullPhaseMark and the rest
+	FullPhases []FullPhase
+
+	// [gc,phases] GC(0) Class Unloading 0.261ms
+	// Marking sub-phases (gc+phases=debug); a Remark logs them too
+	ClassUnloadingTime    time.Duration
+	MarkRefProcessingTime time.Duration
+
 	// G1GC-specific flags
 	ToSpaceExhausted bool
 
+	// [gc,ihop] GC(3) Adaptive IHOP information (value update), threshold: 188743680B (45.00), ...
+	// Old occupancy that starts the next marking cycle, and the old gen allocation rate and
+	// marking length it allows for (the predictions, for adaptive IHOP)
+	IHOPThreshold         utils.MemorySize
+	IHOPThresholdPercent  float64 // Of the target occupancy
+	IHOPTargetOccupancy   utils.MemorySize
+	IHOPOldAllocationRate float64 // Bytes/s
+	IHOPMarkingLength     time.Duration
+	IHOPAdaptive          bool
+	IHOPPredictionActive  bool // false while adaptive IHOP still uses the initial threshold
+
 	// [gc,metaspace] GC(0) Metaspace: 138K(320K)->138K(320K) NonClass: 130K(192K)->130K(192K) Class: 8K(128K)->8K(128K)
 	// Metaspace: used(committed)->used(committed)
 	// Metaspace used 138K, committed 320K, reserved 1114112K
@@ -122,9 +166,9 @@ type GCEvent struct {
 
 	// Computed metrics for this event
 	CollectionEfficiency  float64 // Amount collected / heap before
-	HeapUtilizationBefore float64 // Heap used / heap total (before GC)
-	HeapUtilizationAfter  float64 // Heap used / heap total (after GC)
-	RegionUtilization     float64 // Used regions / total regions
+	HeapUtilizationBefore float64 // Heap used / heap total (before GC), archive regions left out
+	HeapUtilizationAfter  float64 // Heap used / heap total (after GC), archive regions left out
+	RegionUtilization     float64 // Used regions / total regions, archive regions left out
 	PromotionRate         float64 // Regions promoted to old gen
 	AllocationRateToEvent float64 // MB/s since last event
 	PauseTargetExceeded   bool    // Duration > estimated pause target
@@ -139,16 +183,36 @@ type GCEvent struct {
 type GCAnalysis struct {
 	// ===== BASIC INFO ====
 	JVMVersion     string
+	Collector      string // G1, Parallel, Serial, ...; "" when the startup lines weren't logged
+	CPUs           int    // Available to the JVM, from gc,init; 0 when not logged
 	HeapRegionSize utils.MemorySize
 	HeapMax        utils.MemorySize
-	TotalEvents    int
-	YoungGCCount   int
-	MixedGCCount   int
-	FullGCCount    int
+
+	// Class data sharing: CDSMapped unless the JVM logged the archive wasn't
+	// mapped; CDSArchiveSize is 0 when the log doesn't say
+	CDSLogged      bool
+	CDSMapped      bool
+	CDSArchiveSize utils.MemorySize
+	ArchiveRegions int // Most archive heap regions any collection reported
+
+	TotalEvents  int
+	YoungGCCount int
+	MixedGCCount int
+	FullGCCount  int
+
+	// Lines the parser skipped; ParseWarnings keeps the first MaxParseWarnings of them
+	SkippedLines  int
+	ParseWarnings []ParseWarning
+
+	// Parsing was stopped early (Ctrl-C); the analysis covers only the first LinesRead lines
+	Interrupted bool
+	LinesRead   int
+
+	// Details the analysis uses that the JVM wasn't logging, with the -Xlog settings that add them
+	LoggingGaps []LoggingGap
 
 	StartTime    time.Time
 	EndTime      time.Time
-	Status       string
 	TotalRuntime time.Duration
 	TotalGCTime  time.Duration
 
@@ -203,7 +267,7 @@ type GCAnalysis struct {
 	// ===== TIME DISTRIBUTION ANALYSIS =====
 
 	// GC Type time distributions
-	GCTypeDurations   map[string]time.Duration // "Young", "Mixed", "Full", "Concurrent Mark", "Other"
+	GCTypeDurations   map[string]time.Duration // "Young", "Mixed", "Full", "Remark", "Cleanup", "Concurrent Mark", "Other"
 	GCTypeEventCounts map[string]int           // Event counts by type
 
 	// GC Cause time distributions
@@ -225,6 +289,30 @@ type GCAnalysis struct {
 	// Phase timing analysis
 	PhaseStats PhaseAnalysis
 
+	// How long pauses waited on their slowest worker, per phase
+	WorkerBalance WorkerBalanceAnalysis
+
+	// Time between collections, back-to-back runs and death spirals
+	Frequency FrequencyAnalysis
+
+	// Pause target misses by GC type and cause
+	PauseTargets PauseTargetAnalysis
+
+	// Survivor demand per young collection and the survivor size that holds it
+	Survivor SurvivorModel
+
+	// How close marking cycles came to losing the race against old gen allocation
+	IHOP IHOPAnalysis
+
+	// Where Full GC time goes: marking, compaction, or the steps between
+	FullGCPhases FullGCPhaseAnalysis
+
+	// Collections held back by JNI critical regions, and the threads involved
+	GCLocker GCLockerAnalysis
+
+	// Humongous objects by size, from gc+humongous=debug or a JFR recording's allocations outside TLABs
+	HumongousSizes HumongousHistogram
+
 	// ===== ISSUE FLAGS FOR RECOMMENDATIONS =====
 
 	// Critical issues
@@ -235,6 +323,8 @@ type GCAnalysis struct {
 	HasCriticalPromotion           bool
 	HasCriticalHumongousLeak       bool
 	HasCriticalConcurrentMarkAbort bool
+	HasCriticalDeathSpiral         bool
+	HasCriticalGCLocker            bool
 
 	// Warning issues
 	HasWarningMemoryLeak     bool
@@ -246,10 +336,16 @@ type GCAnalysis struct {
 	HasWarningConcurrentMark bool
 	HasWarningAllocationRate bool
 	HasWarningCollectionEff  bool
+	HasWarningWorkerBalance  bool
+	HasWarningBackToBack     bool
+	HasWarningLateMarking    bool
+	HasWarningGCLocker       bool
 
 	// Info issues
 	HasInfoAllocationPattern bool
 	HasInfoPhaseOptimization bool
+	HasInfoEarlyMarking      bool
+	HasInfoHumongousSizing   bool
 }
 
 type HumongousObjectStats struct {
@@ -275,6 +371,128 @@ type PromotionAnalysis struct {
 	PrematurePromotionRate float64
 }
 
+type SurvivorModel struct {
+	Samples      int  // Young collections measured; 0 when too few to model
+	FromAgeTable bool // Demand is the age table total (gc+age=trace) rather than survivor occupancy
+
+	Capacity     utils.MemorySize // Median survivor space
+	DemandP50    utils.MemorySize
+	DemandP95    utils.MemorySize
+	DemandMax    utils.MemorySize
+	OverflowRate float64 // Collections whose survivors didn't fit
+
+	SuggestedSize    utils.MemorySize // Holds the demand of all but SurvivorOverflowGoal of the collections
+	ExpectedOverflow float64          // Collections that would still overflow SuggestedSize
+	CurrentRatio     int              // SurvivorRatio the log's space sizes imply; 0 when the young generation isn't logged
+	SuggestedRatio   int              // SurvivorRatio giving SuggestedSize
+
+	// Tenuring threshold (gc+age=debug), lowered when survivors pass TargetSurvivorRatio
+	MaxThreshold         int
+	MedianThreshold      int
+	LoweredThresholdRate float64
+}
+
+type IHOPAnalysis struct {
+	Cycles         int     // Marking cycles followed to their Remark
+	LostRaces      int     // Cycles a Full GC or evacuation failure interrupted
+	StartOccupancy float64 // Median heap share of old data when marking started: the effective IHOP
+	MaxGrowth      float64 // Largest heap share old data grew by during marking
+	MinMargin      float64 // Smallest heap share left free of old data when marking finished
+	MinRaceMargin  float64 // Free heap at marking start over the old growth during marking, worst cycle; below 1 marking loses
+
+	SuggestedPercent int // InitiatingHeapOccupancyPercent that keeps IHOPMarginGoal free after the worst growth
+
+	// From gc+ihop=debug: the latest threshold and the allocation it allows for
+	Logged               bool
+	Adaptive             bool
+	PredictionActiveRate float64 // Updates that used the adaptive prediction rather than the initial threshold
+	ThresholdPercent     float64 // Of the target occupancy
+	OldAllocationRate    float64 // Bytes/s
+	MarkingLength        time.Duration
+}
+
+// FullPhase is one step of a Full GC's mark-compact
+type FullPhase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// FullPhaseStats is one Full GC phase over every Full GC that logged it
+type FullPhaseStats struct {
+	Phase string
+	Count int
+	Total time.Duration
+	Avg   time.Duration
+	Max   time.Duration
+	Share float64 // Of the time all phases took
+}
+
+type FullGCPhaseAnalysis struct {
+	Phases []FullPhaseStats // Most time first
+	Events int              // Full GCs with phase timings
+	Total  time.Duration    // Their phase time
+
+	// The phase with FullPhaseDominantShare or more of the time; "" when it's spread out
+	Dominant      string
+	DominantShare float64
+
+	// Shares of the marking phase, when gc+phases=debug logged them
+	SubphasesLogged     bool
+	ClassUnloadingShare float64
+	RefProcessingShare  float64
+}
+
+// ThreadCount is a thread the log names, and how many times
+type ThreadCount struct {
+	Name  string
+	Count int
+}
+
+type GCLockerAnalysis struct {
+	Collections int     // Started by GCLocker once the last JNI critical region exited
+	Share       float64 // Of Young, Mixed and Full collections
+	FullGCs     int
+	BackToBack  int // Started under BackToBackGap after the collection before them
+
+	// From gc+jni=debug, and the gc+alloc warnings of allocations that gave up
+	Logged         bool
+	Stalls         int           // Allocations that waited for a critical region to exit
+	BlockedEntries int           // Threads held at a critical region's entry while a collection was pending
+	RetryFailures  int           // Allocations that stopped waiting and threw OutOfMemoryError
+	Holders        []ThreadCount // Threads whose exit from a critical region let the pending collection run, most first
+	Stalled        []ThreadCount // Threads whose allocations stalled or gave up, most first
+}
+
+// HumongousBucket counts the humongous objects sized in (Min, Max]
+type HumongousBucket struct {
+	Min, Max utils.MemorySize
+	Count    int
+	Regions  int              // Regions the objects took
+	Wasted   utils.MemorySize // Left unused in their last regions
+}
+
+// RegionSizeOption is what a larger G1HeapRegionSize would make of the same objects
+type RegionSizeOption struct {
+	Size        utils.MemorySize
+	Normal      int     // Objects at or under half a region, allocated in eden like any other
+	NormalShare float64 // Of all humongous objects
+	Wasted      utils.MemorySize
+	HeapRegions int // Regions the heap would have; 0 when the heap size is unknown
+}
+
+type HumongousHistogram struct {
+	Source string                   // "gc+humongous" or "JFR"
+	Sizes  map[utils.MemorySize]int // Objects by exact size, as logged
+
+	Objects    int
+	Total      utils.MemorySize
+	Wasted     utils.MemorySize // Past the objects' ends in their last regions
+	RegionSize utils.MemorySize
+	Buckets    []HumongousBucket  // Power-of-two sizes, smallest first
+	Options    []RegionSizeOption // Larger region sizes the JVM accepts, up to the first that makes every object normal
+	Suggested  utils.MemorySize   // Region size that makes most of the objects normal; 0 when none does
+}
+
 type PhaseAnalysis struct {
 	AvgObjectCopyTime    time.Duration
 	AvgRootScanTime      time.Duration
@@ -289,6 +507,79 @@ type PhaseAnalysis struct {
 	HasPhaseIssues bool
 }
 
+// WorkerPhase is the per-worker summary of one parallel phase of a pause
+type WorkerPhase struct {
+	Name    string
+	Min     time.Duration
+	Avg     time.Duration
+	Max     time.Duration
+	Workers int
+}
+
+// PhaseBalance is how evenly the workers shared one phase over every pause that logged it
+type PhaseBalance struct {
+	Phase         string
+	Events        int
+	AvgImbalance  float64       // Mean of the slowest worker's time over the average worker's
+	StragglerTime time.Duration // Total time pauses spent waiting on the slowest worker
+}
+
+type WorkerBalanceAnalysis struct {
+	Phases          []PhaseBalance // Most straggler time first
+	Events          int            // Pauses with per-worker phase timings
+	StragglerEvents int            // Pauses where waiting on one worker took StragglerPauseShare or more
+	MaxWorkers      int
+	StragglerRate   float64
+}
+
+// IntervalStats is the time between consecutive events of one GC type
+type IntervalStats struct {
+	Type  string
+	Count int // Intervals, one fewer than the events
+	Min   time.Duration
+	Avg   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+}
+
+// DeathSpiral is a run of collections whose gaps shrank toward zero
+type DeathSpiral struct {
+	Start       time.Time
+	Collections int // 0 when there was no spiral
+	FirstGap    time.Duration
+	LastGap     time.Duration
+	FullGCs     int
+}
+
+type FrequencyAnalysis struct {
+	ByType               []IntervalStats // Most intervals first
+	Gaps                 int             // Gaps between consecutive Young, Mixed and Full collections
+	MinGap               time.Duration   // Shortest time the application ran between two collections
+	BackToBackCount      int             // Collections starting less than BackToBackGap after the previous one ended
+	BackToBackRate       float64
+	LongestBackToBackRun int
+	Spiral               DeathSpiral
+}
+
+// PauseCompliance is how often one GC type's, or one cause's, pauses ran over the target
+type PauseCompliance struct {
+	Name     string
+	Pauses   int
+	Misses   int
+	MissRate float64
+	Max      time.Duration
+}
+
+type PauseTargetAnalysis struct {
+	ByType  []PauseCompliance // Most misses first
+	ByCause []PauseCompliance // Most misses first
+	Misses  int
+
+	// The GC type behind CulpritMissShare or more of the misses; "" when they're spread out
+	Culprit      string
+	CulpritShare float64
+}
+
 type MemoryTrend struct {
 	GrowthRateMBPerHour   float64
 	GrowthRatePercent     float64
@@ -311,4 +602,20 @@ type GCIssues struct {
 	Critical []PerformanceIssue
 	Warning  []PerformanceIssue
 	Info     []PerformanceIssue
+
+	JDKVersion int // Release the flag advice was fitted to; 0 when the log doesn't say
+}
+
+// Status is the verdict of the most severe issue: "critical", "warning", or "healthy" when only advice remains
+func (issues *GCIssues) Status() string {
+	switch {
+	case issues == nil:
+		return "healthy"
+	case len(issues.Critical) > 0:
+		return "critical"
+	case len(issues.Warning) > 0:
+		return "warning"
+	default:
+		return "healthy"
+	}
 }
@@ -0,0 +1,97 @@
+package gc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+ * A parallel phase ends when its last worker does, so a pause waits for its
+ * slowest worker. With gc+phases=debug, G1 logs each phase's Min/Avg/Max
+ * across workers; Max - Avg is the time the pause spent waiting on the
+ * straggler rather than sharing the work.
+ *
+ * Termination is where the other workers wait for the straggler, and
+ * GC Worker Total/Other sum up the rest, so they show that a pause straggled
+ * but not which phase caused it.
+ */
+
+// workerTotalPhase spans a worker's whole pause, so it gives the pause's straggler time
+const workerTotalPhase = "GC Worker Total"
+
+var workerWaitPhases = []string{"Termination", workerTotalPhase, "GC Worker Other"}
+
+// StragglerTime is how much longer the slowest worker took than the average one
+func (p WorkerPhase) StragglerTime() time.Duration {
+	return max(p.Max-p.Avg, 0)
+}
+
+// Imbalance is the slowest worker's time over the average worker's; 1 is perfectly even
+func (p WorkerPhase) Imbalance() float64 {
+	if p.Avg <= 0 {
+		return 1
+	}
+	return float64(p.Max) / float64(p.Avg)
+}
+
+// stragglerTime is how long the pause waited on its slowest worker
+func (e *GCEvent) stragglerTime() time.Duration {
+	var longest time.Duration
+	for _, phase := range e.WorkerPhases {
+		if phase.Name == workerTotalPhase {
+			return phase.StragglerTime()
+		}
+		if !slices.Contains(workerWaitPhases, phase.Name) {
+			longest = max(longest, phase.StragglerTime())
+		}
+	}
+	return longest
+}
+
+func calculateWorkerBalance(events []*GCEvent) WorkerBalanceAnalysis {
+	var balance WorkerBalanceAnalysis
+	byPhase := make(map[string]*PhaseBalance)
+	imbalanceSums := make(map[string]float64)
+
+	for _, event := range events {
+		if len(event.WorkerPhases) == 0 {
+			continue
+		}
+		balance.Events++
+
+		if event.Duration > 0 && float64(event.stragglerTime()) >= StragglerPauseShare*float64(event.Duration) {
+			balance.StragglerEvents++
+		}
+
+		for _, phase := range event.WorkerPhases {
+			balance.MaxWorkers = max(balance.MaxWorkers, phase.Workers)
+			if slices.Contains(workerWaitPhases, phase.Name) || phase.Workers < 2 {
+				continue
+			}
+
+			stats, exists := byPhase[phase.Name]
+			if !exists {
+				stats = &PhaseBalance{Phase: phase.Name}
+				byPhase[phase.Name] = stats
+			}
+			stats.Events++
+			stats.StragglerTime += phase.StragglerTime()
+			imbalanceSums[phase.Name] += phase.Imbalance()
+		}
+	}
+
+	for name, stats := range byPhase {
+		stats.AvgImbalance = imbalanceSums[name] / float64(stats.Events)
+		balance.Phases = append(balance.Phases, *stats)
+	}
+	slices.SortFunc(balance.Phases, func(a, b PhaseBalance) int {
+		return cmp.Or(cmp.Compare(b.StragglerTime, a.StragglerTime), strings.Compare(a.Phase, b.Phase))
+	})
+
+	if balance.Events > 0 {
+		balance.StragglerRate = float64(balance.StragglerEvents) / float64(balance.Events)
+	}
+	return balance
+}
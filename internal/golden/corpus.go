@@ -0,0 +1,156 @@
+// Package golden checks the GC analysis of every sample log against its
+// golden file, so a parser or threshold change can't silently change a verdict:
+//
+//	go test ./internal/golden                     # Compare (make golden)
+//	go test ./internal/golden -update             # Rewrite the golden files after an intended change (make golden-update)
+//	go test ./internal/golden -run TestGolden/leak # Only the logs whose name matches
+//
+// The corpus is the logs under gc_log_sample plus one log per gc generate
+// pattern, generated with a fixed seed and start time. Golden files keep only
+// the verdicts and the numbers behind them, never log lines, so the corpus can
+// hold anonymized production logs without the golden files leaking anything.
+// A generated log's verdict is also checked against what it was generated
+// from, so a golden file can't pin down a wrong count.
+package golden
+
+import (
+	"bytes"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+)
+
+const (
+	corpusDir = "../../gc_log_sample"
+	goldenDir = "../../gc_log_sample/golden"
+)
+
+// generatedStart is when every generated log's JVM starts, so the logs come out the same on each run
+var generatedStart = time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+// corpusLog is one log of the corpus; analyze parses it and boils the analysis down to its verdict
+type corpusLog struct {
+	name    string
+	analyze func() (*Verdict, error)
+}
+
+// Verdict is what a golden file pins down about a log
+type Verdict struct {
+	JVMVersion         string               `json:"jvmVersion,omitempty"`
+	Status             string               `json:"status"`
+	SkippedLines       int                  `json:"skippedLines"`
+	Events             gc.ReportEventCounts `json:"events"`
+	EvacuationFailures int                  `json:"evacuationFailures"`
+	LeakSeverity       string               `json:"leakSeverity"`
+	Throughput         float64              `json:"throughput"`
+	MaxPauseMs         float64              `json:"maxPauseMs"`
+	P99PauseMs         float64              `json:"p99PauseMs"`
+	Issues             []Issue              `json:"issues"`
+	GroundTruth        *gc.GroundTruth      `json:"groundTruth,omitempty"` // What a generated log was made from
+}
+
+type Issue struct {
+	Type        string `json:"type"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// corpus is every sample log, named after its path, plus a generated log per pattern
+func corpus() ([]corpusLog, error) {
+	var logs []corpusLog
+	err := filepath.WalkDir(corpusDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".log") {
+			return err
+		}
+		rel, err := filepath.Rel(corpusDir, path)
+		if err != nil {
+			return err
+		}
+		name := strings.ReplaceAll(strings.TrimSuffix(rel, ".log"), string(filepath.Separator), "_")
+		logs = append(logs, corpusLog{name, func() (*Verdict, error) { return analyzeFile(path) }})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range gc.GeneratorPatterns {
+		logs = append(logs, corpusLog{"generated_" + pattern, func() (*Verdict, error) { return analyzeGenerated(pattern) }})
+	}
+	return logs, nil
+}
+
+func analyzeFile(path string) (*Verdict, error) {
+	events, analysis, err := gc.NewParser().ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newVerdict(events, analysis), nil
+}
+
+// analyzeGenerated generates an hour of the pattern in memory and analyzes it
+func analyzeGenerated(pattern string) (*Verdict, error) {
+	config, err := gc.DefaultGeneratorConfig(pattern)
+	if err != nil {
+		return nil, err
+	}
+	config.Start = generatedStart
+
+	var log bytes.Buffer
+	truth, err := gc.GenerateLog(&log, config)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "jdiag-golden-*.log")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(log.Bytes())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verdict, err := analyzeFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+	verdict.GroundTruth = truth
+	return verdict, nil
+}
+
+func newVerdict(events []*gc.GCEvent, analysis *gc.GCAnalysis) *Verdict {
+	gc.AnalyzeGCLogs(events, analysis)
+	report := gc.NewReport(analysis, gc.GetRecommendations(analysis))
+
+	verdict := &Verdict{
+		JVMVersion:         report.JVMVersion,
+		Status:             report.Status,
+		SkippedLines:       report.SkippedLines,
+		Events:             report.Events,
+		EvacuationFailures: report.EvacuationFailures,
+		LeakSeverity:       report.MemoryTrend.LeakSeverity,
+		Throughput:         round(report.Throughput),
+		MaxPauseMs:         round(report.Pauses.MaxMs),
+		P99PauseMs:         round(report.Pauses.P99Ms),
+		Issues:             []Issue{},
+	}
+	for _, issue := range report.Issues {
+		verdict.Issues = append(verdict.Issues, Issue{Type: issue.Type, Severity: issue.Severity, Description: issue.Description})
+	}
+	return verdict
+}
+
+// round keeps two decimals, so float noise in the last digits doesn't count as a change
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,136 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files instead of comparing")
+
+func TestGolden(t *testing.T) {
+	logs, err := corpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, log := range logs {
+		t.Run(log.name, func(t *testing.T) {
+			verdict, err := log.analyze()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkStatus(t, verdict)
+			if verdict.GroundTruth != nil {
+				checkGroundTruth(t, verdict)
+			}
+
+			actual, err := json.MarshalIndent(verdict, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual = append(actual, '\n')
+
+			path := filepath.Join(goldenDir, log.name+".json")
+			if *update {
+				if err := os.WriteFile(path, actual, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(path)
+			switch {
+			case os.IsNotExist(err):
+				t.Fatalf("no golden file %s; record one with -update", path)
+			case err != nil:
+				t.Fatal(err)
+			case !bytes.Equal(expected, actual):
+				t.Errorf("verdict changed; if the change is intended, run with -update\n%s", diff(string(expected), string(actual)))
+			}
+		})
+	}
+}
+
+// checkStatus checks that the status is the severity of the worst issue, so it can't drift from the issues pinned next to it
+func checkStatus(t *testing.T, verdict *Verdict) {
+	t.Helper()
+	expect := "healthy"
+	for _, issue := range verdict.Issues {
+		switch {
+		case issue.Severity == "critical":
+			expect = "critical"
+		case issue.Severity == "warning" && expect == "healthy":
+			expect = "warning"
+		}
+	}
+	if verdict.Status != expect {
+		t.Errorf("status %q, but the worst issue makes it %q", verdict.Status, expect)
+	}
+}
+
+// checkGroundTruth compares the analysis of a generated log with what the log was made from
+func checkGroundTruth(t *testing.T, verdict *Verdict) {
+	t.Helper()
+	truth := verdict.GroundTruth
+
+	counts := []struct {
+		name           string
+		actual, expect int
+	}{
+		{"young collections", verdict.Events.Young, truth.Young},
+		{"mixed collections", verdict.Events.Mixed, truth.Mixed},
+		{"full collections", verdict.Events.Full, truth.Full},
+		{"events", verdict.Events.Total, truth.Collections + truth.ConcurrentCycles},
+		{"evacuation failures", verdict.EvacuationFailures, truth.EvacuationFailures},
+	}
+	for _, count := range counts {
+		if count.actual != count.expect {
+			t.Errorf("%s: got %d, generated %d", count.name, count.actual, count.expect)
+		}
+	}
+
+	if expect := math.Round(float64(truth.MaxPause.Microseconds())/10) / 100; verdict.MaxPauseMs != expect {
+		t.Errorf("max pause: got %.2fms, generated %.2fms", verdict.MaxPauseMs, expect)
+	}
+
+	leaking := truth.LiveEndBytes > truth.LiveStartBytes
+	if reported := verdict.LeakSeverity != "" && verdict.LeakSeverity != "none"; reported != leaking {
+		t.Errorf("leak severity %q, but the live set went from %d to %d bytes", verdict.LeakSeverity, truth.LiveStartBytes, truth.LiveEndBytes)
+	}
+	if leaking && verdict.Status == "healthy" {
+		t.Errorf("status %q for a log whose live set grew from %d to %d bytes", verdict.Status, truth.LiveStartBytes, truth.LiveEndBytes)
+	}
+}
+
+// diff shows the golden lines the analysis no longer produces and the ones it produces instead
+func diff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	// Lines common to the start and end of both are context, the rest changed
+	start := 0
+	for start < len(expectedLines) && start < len(actualLines) && expectedLines[start] == actualLines[start] {
+		start++
+	}
+	endExpected, endActual := len(expectedLines), len(actualLines)
+	for endExpected > start && endActual > start && expectedLines[endExpected-1] == actualLines[endActual-1] {
+		endExpected--
+		endActual--
+	}
+
+	var b strings.Builder
+	for _, line := range expectedLines[start:endExpected] {
+		fmt.Fprintf(&b, "  - %s\n", line)
+	}
+	for _, line := range actualLines[start:endActual] {
+		fmt.Fprintf(&b, "  + %s\n", line)
+	}
+	return b.String()
+}
@@ -1,38 +1,72 @@
 package heap
 
 import (
+	"cmp"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mabhi256/jdiag/internal/heap/analyzer"
 	"github.com/mabhi256/jdiag/internal/heap/parser"
+	"github.com/mabhi256/jdiag/internal/heap/tui"
+	"github.com/mabhi256/jdiag/utils"
 )
 
+// Config holds options for heap dump analysis
+type Config struct {
+	Workers   int               // Goroutines used to parse heap dump segments (<=1 is sequential)
+	Output    string            // "cli" or "tui"
+	Debug     parser.DebugLevel // Detail written to the .debug log next to the dump
+	MaxMemory utils.MemorySize  // Memory budget for the analysis; past it, large structures spill to disk (0 = no limit)
+
+	StructureOnly bool // Parse primitive arrays without their contents, so no String's text is read
+	Reindex       bool // Ignore the sidecar index and analyze the dump from scratch
+
+	OnWarning func(error) // Problems that don't stop a headless analysis, such as an index that couldn't be written; nil drops them
+}
+
+// Classes and objects kept in the index overview, and shown from it
+const indexedTopN = 20
+
 // RunHeapAnalysis performs the complete heap analysis using the refactored analyzer
-func RunHeapAnalysis(filename string) error {
-	parser, err := parser.NewParser(filename)
+func RunHeapAnalysis(filename string, config *Config) error {
+	// An analyzed dump is reported from its index without parsing it again; a debug log needs the parse
+	if config.Output != "tui" && !config.Reindex && config.Debug == parser.DebugOff {
+		if index, err := parser.LoadIndex(filename); err == nil && index.Overview != nil {
+			printIndexOverview(index)
+			printAnalysisOverview(index.Overview)
+			fmt.Println()
+			fmt.Println(utils.MutedStyle.Render("Reported from the heap index; --reindex runs the full analysis again."))
+			return nil
+		}
+	}
+
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
 	if err != nil {
-		return fmt.Errorf("failed to create parser: %w", err)
+		return err
 	}
 	defer parser.Close()
+	defer heapAnalyzer.Close()
 
-	if err := parser.ParseHprof(); err != nil {
-		return fmt.Errorf("failed to parse hprof file: %w", err)
+	if config.Output == "tui" {
+		offset, reason := parser.GetTruncation()
+		return tui.StartTUI(heapAnalyzer, tui.DumpInfo{
+			Filename:        filename,
+			Header:          parser.GetHeader(),
+			Truncated:       parser.IsTruncated(),
+			TruncatedAt:     offset,
+			TruncatedReason: reason,
+		})
 	}
 
-	// Create analyzer using the same interface - now with improved internal structure
-	heapAnalyzer := analyzer.NewAnalyzer(
-		parser.GetStringRegistry(),
-		parser.GetClassDumpRegistry(),
-		parser.GetObjectRegistry(),
-		parser.GetArrayRegistry(),
-		parser.GetGCRootRegistry(),
-		parser.GetHeader().IdentifierSize,
-	)
-
-	// Perform analysis - the external interface remains the same
-	if err := heapAnalyzer.PerformAnalysis(); err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
-	}
+	printAnalysisOverview(newIndexedOverview(heapAnalyzer))
+	printSuspectAllocationSites(heapAnalyzer.GetLeakSuspects())
 
 	// Demonstrate analyzer capabilities with improved error handling
 	demonstrateAnalyzerCapabilities(heapAnalyzer)
@@ -40,43 +74,304 @@ func RunHeapAnalysis(filename string) error {
 	return nil
 }
 
-// RunHeapAnalysisWithContext demonstrates using the new context-based approach for advanced scenarios
-func RunHeapAnalysisWithContext(filename string) error {
-	parser, err := parser.NewParser(filename)
+// AnalyzeHeapDump parses a dump and runs the full analysis. The caller must close the parser and the analyzer.
+func AnalyzeHeapDump(filename string, config *Config) (*parser.Parser, *analyzer.Analyzer, error) {
+	// The Go runtime collects harder as it nears the budget, rather than growing past it
+	if config.MaxMemory > 0 {
+		debug.SetMemoryLimit(config.MaxMemory.Bytes())
+	}
+
+	// A valid sidecar index gives an instant overview while the full parse runs
+	var cachedIndex *parser.HeapIndex
+	if !config.Reindex {
+		cachedIndex, _ = parser.LoadIndex(filename)
+	}
+	if cachedIndex != nil {
+		printIndexOverview(cachedIndex)
+	} else {
+		fmt.Printf("🔨 Building heap index: %s\n", parser.IndexPath(filename))
+	}
+
+	debugEnabled := config.Debug != parser.DebugOff
+	debugPath := parser.DebugPath(filename)
+
+	parser, err := newHeapParser(filename, config)
 	if err != nil {
-		return fmt.Errorf("failed to create parser: %w", err)
+		return nil, nil, err
+	}
+	if config.StructureOnly {
+		fmt.Println("🔒 Structure only: primitive array contents are skipped, so no String's text is read or shown")
+	}
+	if debugEnabled {
+		fmt.Printf("🐛 Writing %s debug log to %s\n", config.Debug, debugPath)
 	}
-	defer parser.Close()
 
+	// Ctrl-C stops the parse early and keeps the records read so far, like a truncated dump
+	run := utils.RunWithProgressBar
+	if config.Output == "tui" {
+		run = utils.RunWithLoadingScreen
+	}
+
+	start := time.Now()
+	err = run("Parsing "+filepath.Base(filename), "records", func(ctx context.Context, progress utils.ProgressFunc) error {
+		parser.SetContext(ctx)
+		parser.SetProgress(progress)
+		return parser.ParseHprof()
+	})
+	if err != nil {
+		parser.Close()
+		return nil, nil, fmt.Errorf("failed to parse hprof file: %w", err)
+	}
+	fmt.Printf("⏱️  Parsed in %s\n\n", time.Since(start).Round(time.Millisecond))
+
+	if parser.IsInterrupted() {
+		offset, _ := parser.GetTruncation()
+		fmt.Printf("⚠️  Parsing interrupted at offset %d\n", offset)
+		fmt.Println("   Results below cover only the part of the dump read so far")
+		fmt.Println()
+	} else if parser.IsTruncated() {
+		offset, reason := parser.GetTruncation()
+		fmt.Printf("⚠️  Heap dump is truncated at offset %d (%s)\n", offset, reason)
+		fmt.Println("   Results below cover only the readable portion of the dump")
+		fmt.Println()
+	}
+
+	heapAnalyzer, err := analyzeParsed(parser, cachedIndex, config, os.Stdout)
+	if err != nil {
+		parser.Close()
+		return nil, nil, err
+	}
+	if err := saveIndex(filename, parser, cachedIndex, heapAnalyzer); err != nil {
+		fmt.Printf("⚠️  Failed to write heap index: %v\n", err)
+	}
+	return parser, heapAnalyzer, nil
+}
+
+/*
+ * AnalyzeHeapDump belongs to a terminal: it draws a progress bar, stops on
+ * Ctrl-C and sets the process-wide memory limit. A server runs several
+ * analyses at once for the lifetime of the process, so it uses
+ * AnalyzeHeapDumpContext instead, which only parses and analyzes, printing
+ * nothing; what the terminal would show as a warning goes to
+ * Config.OnWarning. The per-analysis MaxMemory budget still applies to the
+ * analyzer's spilling.
+ */
+
+// AnalyzeHeapDumpContext is AnalyzeHeapDump without output, signal handling or a memory limit.
+// Cancelling ctx abandons the parse with ctx's error. The caller must close the parser and the analyzer.
+func AnalyzeHeapDumpContext(ctx context.Context, filename string, config *Config) (*parser.Parser, *analyzer.Analyzer, error) {
+	var cachedIndex *parser.HeapIndex
+	if !config.Reindex {
+		cachedIndex, _ = parser.LoadIndex(filename)
+	}
+
+	parser, err := newHeapParser(filename, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	parser.SetContext(ctx)
 	if err := parser.ParseHprof(); err != nil {
-		return fmt.Errorf("failed to parse hprof file: %w", err)
+		parser.Close()
+		return nil, nil, fmt.Errorf("failed to parse hprof file: %w", err)
+	}
+	if parser.IsInterrupted() {
+		parser.Close()
+		return nil, nil, fmt.Errorf("parsing interrupted: %w", context.Cause(ctx))
 	}
 
-	// Create analysis context first (useful for testing and advanced configuration)
-	ctx := analyzer.NewAnalysisContext(
-		parser.GetStringRegistry(),
-		parser.GetClassDumpRegistry(),
-		parser.GetObjectRegistry(),
-		parser.GetArrayRegistry(),
-		parser.GetGCRootRegistry(),
-		parser.GetHeader().IdentifierSize,
-	)
+	heapAnalyzer, err := analyzeParsed(parser, cachedIndex, config, io.Discard)
+	if err != nil {
+		parser.Close()
+		return nil, nil, err
+	}
+	if err := saveIndex(filename, parser, cachedIndex, heapAnalyzer); err != nil && config.OnWarning != nil {
+		config.OnWarning(fmt.Errorf("failed to write heap index: %w", err))
+	}
+	return parser, heapAnalyzer, nil
+}
 
-	// Create analyzer with context (alternative approach)
-	heapAnalyzer, err := analyzer.NewAnalyzerWithContext(ctx)
+// newHeapParser opens a dump with the parsing options of config
+func newHeapParser(filename string, config *Config) (*parser.Parser, error) {
+	p, err := parser.NewParser(filename)
 	if err != nil {
-		return fmt.Errorf("failed to create analyzer: %w", err)
+		return nil, fmt.Errorf("failed to create parser: %w", err)
+	}
+
+	if config.Workers > 0 {
+		p.SetWorkers(config.Workers)
+	}
+	if config.StructureOnly {
+		p.SetStructureOnly(true)
+	}
+	if config.Debug != parser.DebugOff {
+		if err := p.SetDebugLevel(config.Debug); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// analyzeParsed runs the full analysis on a parsed dump, reporting its phases to out and
+// reusing cachedIndex's dominators when it has any
+func analyzeParsed(p *parser.Parser, cachedIndex *parser.HeapIndex, config *Config, out io.Writer) (*analyzer.Analyzer, error) {
+	heapAnalyzer := analyzer.NewAnalyzer(
+		p.GetStringRegistry(),
+		p.GetClassRegistry(),
+		p.GetClassDumpRegistry(),
+		p.GetObjectRegistry(),
+		p.GetArrayRegistry(),
+		p.GetGCRootRegistry(),
+		p.GetHeader().IdentifierSize,
+	)
+	heapAnalyzer.SetOutput(out)
+	heapAnalyzer.SetMemoryBudget(config.MaxMemory.Bytes())
+	heapAnalyzer.SetStackRegistry(p.GetStackRegistry())
+
+	// Dominators are the slowest part of the analysis; reuse them when the dump is unchanged
+	if cachedIndex != nil && !p.IsTruncated() {
+		heapAnalyzer.SetCachedDominators(cachedIndex.Dominators)
 	}
 
-	// Perform analysis
 	if err := heapAnalyzer.PerformAnalysis(); err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		heapAnalyzer.Close()
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
+	return heapAnalyzer, nil
+}
 
-	// Print detailed report using new capabilities
-	heapAnalyzer.PrintDetailedReport()
+/*
+ * The index is only written here, once the analysis is done, so it always
+ * holds the dominators and the overview together. A partial dump would
+ * describe only what was read, and an index that was already complete when
+ * the analysis started is left as it is.
+ */
 
-	return nil
+// saveIndex writes the parser's index with the analysis results for the next run
+func saveIndex(filename string, p *parser.Parser, cachedIndex *parser.HeapIndex, heapAnalyzer *analyzer.Analyzer) error {
+	index := p.GetIndex()
+	tree := heapAnalyzer.GetDominatorTree()
+	if index == nil || tree == nil || p.IsTruncated() || p.IsInterrupted() {
+		return nil
+	}
+	if cachedIndex != nil && cachedIndex.Overview != nil {
+		return nil
+	}
+
+	index.Dominators = tree.Idoms()
+	index.Overview = newIndexedOverview(heapAnalyzer)
+	return index.Save(filename)
+}
+
+// newIndexedOverview summarizes an analysis: totals, and the top classes and dominators by retained size
+func newIndexedOverview(heapAnalyzer *analyzer.Analyzer) *parser.IndexedOverview {
+	ctx := heapAnalyzer.GetContext()
+	overview := &parser.IndexedOverview{GCRoots: len(ctx.RootReg.GetAllRoots())}
+
+	if histogram := heapAnalyzer.GetHistogram(); histogram != nil {
+		overview.Objects = histogram.TotalObjects
+		overview.ShallowSize = histogram.TotalShallowSize
+
+		entries := slices.Clone(histogram.Entries)
+		slices.SortStableFunc(entries, func(a, b *analyzer.ClassHistogramEntry) int {
+			return cmp.Or(cmp.Compare(b.RetainedSize, a.RetainedSize), strings.Compare(a.ClassName, b.ClassName))
+		})
+		for _, entry := range entries[:min(len(entries), indexedTopN)] {
+			overview.Classes = append(overview.Classes, parser.IndexedClassUsage{
+				ClassName:    entry.ClassName,
+				Instances:    entry.InstanceCount,
+				ShallowSize:  entry.ShallowSize,
+				RetainedSize: entry.RetainedSize,
+			})
+		}
+	}
+
+	if tree := heapAnalyzer.GetDominatorTree(); tree != nil {
+		overview.ReachableObjects = tree.ReachableCount()
+		overview.ReachableSize = tree.TotalRetainedSize()
+
+		objects := tree.Children(analyzer.SuperRootID)
+		for _, objectID := range objects[:min(len(objects), indexedTopN)] {
+			object, _ := ctx.DescribeObject(objectID)
+			overview.Dominators = append(overview.Dominators, parser.IndexedObject{
+				ObjectID:     objectID,
+				Name:         object.DisplayName(),
+				ShallowSize:  object.ShallowSize,
+				RetainedSize: tree.RetainedSize(objectID),
+			})
+		}
+	}
+	return overview
+}
+
+// printAnalysisOverview prints the totals, top classes and top dominators of an analysis
+func printAnalysisOverview(overview *parser.IndexedOverview) {
+	fmt.Println()
+	fmt.Println("📊 HEAP OVERVIEW")
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("Objects:        %s (%s shallow)\n",
+		utils.FormatCount(int64(overview.Objects)), utils.MemorySize(overview.ShallowSize).String())
+	fmt.Printf("Reachable heap: %s (%s objects)\n",
+		utils.MemorySize(overview.ReachableSize).String(), utils.FormatCount(int64(overview.ReachableObjects)))
+	fmt.Printf("GC roots:       %s\n", utils.FormatCount(int64(overview.GCRoots)))
+
+	fmt.Println()
+	fmt.Printf("📦 TOP %d CLASSES BY RETAINED SIZE\n", len(overview.Classes))
+	fmt.Printf("%4s  %10s  %10s  %10s  %s\n", "#", "Retained", "Shallow", "Instances", "Class")
+	fmt.Println(strings.Repeat("─", 80))
+	for i, class := range overview.Classes {
+		fmt.Printf("%4d  %10s  %10s  %10s  %s\n", i+1, utils.MemorySize(class.RetainedSize).String(),
+			utils.MemorySize(class.ShallowSize).String(), utils.FormatCount(int64(class.Instances)), class.ClassName)
+	}
+
+	fmt.Println()
+	fmt.Printf("🏆 TOP %d DOMINATORS BY RETAINED SIZE\n", len(overview.Dominators))
+	fmt.Printf("%4s  %10s  %6s  %10s  %s\n", "#", "Retained", "%", "Shallow", "Object")
+	fmt.Println(strings.Repeat("─", 80))
+	for i, object := range overview.Dominators {
+		var percentage float64
+		if overview.ReachableSize > 0 {
+			percentage = float64(object.RetainedSize) / float64(overview.ReachableSize) * 100
+		}
+		fmt.Printf("%4d  %10s  %6s  %10s  %s\n", i+1, utils.MemorySize(object.RetainedSize).String(),
+			utils.FormatPercent(percentage), utils.MemorySize(object.ShallowSize).String(), object.Name)
+	}
+}
+
+// printSuspectAllocationSites lists the code that allocated each leak suspect's class
+func printSuspectAllocationSites(suspects []*analyzer.LeakSuspect) {
+	if len(suspects) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("🧬 LEAK SUSPECT ALLOCATION SITES")
+	fmt.Println(strings.Repeat("─", 80))
+
+	printed := false
+	for i, suspect := range suspects {
+		if len(suspect.AllocationSites) == 0 {
+			continue
+		}
+		printed = true
+
+		fmt.Printf("Suspect %d: %s (%s, %s of the reachable heap)\n", i+1, suspect.ClassName,
+			utils.MemorySize(suspect.RetainedSize).String(), utils.FormatPercent(suspect.Percentage))
+		fmt.Printf("  %10s  %10s  %s\n", "Retained", "Instances", "Allocated at")
+		for _, site := range suspect.AllocationSites {
+			fmt.Printf("  %10s  %10s  %s\n", utils.MemorySize(site.RetainedSize).String(),
+				utils.FormatCount(int64(site.Objects)), site.Frame)
+			for _, caller := range site.Callers {
+				fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("  %10s  %10s    ← %s", "", "", caller)))
+			}
+		}
+		fmt.Println()
+	}
+
+	if !printed {
+		fmt.Println(utils.MutedStyle.Render("The dump has no allocation traces, so suspects can't be tied to allocating code."))
+		fmt.Println(utils.MutedStyle.Render("Dumps written with allocation tracking on (e.g. -agentlib:hprof=heap=dump,depth=8) have them."))
+	}
 }
 
 // demonstrateAnalyzerCapabilities showcases the refactored analyzer's enhanced capabilities
@@ -114,7 +409,7 @@ func demonstrateAnalyzerCapabilities(analyzer *analyzer.Analyzer) {
 
 		// Show example of reference navigation for first few objects
 		count := 0
-		for objID, refs := range refMap.ForwardRefs {
+		for objID, refs := range refMap.AllReferences() {
 			if count >= 3 {
 				break
 			}
@@ -169,8 +464,8 @@ func demonstrateAnalyzerCapabilities(analyzer *analyzer.Analyzer) {
 
 		// Show registry statistics
 		if ctx.StringReg != nil {
-			allStrings := ctx.StringReg.GetAllStrings()
-			fmt.Printf("   String registry: %d entries\n", len(allStrings))
+			fmt.Printf("   String registry: %d entries (%d loaded)\n",
+				ctx.StringReg.Count(), ctx.StringReg.MaterializedCount())
 		}
 
 		if ctx.InstanceReg != nil {
@@ -193,3 +488,18 @@ func demonstrateAnalyzerCapabilities(analyzer *analyzer.Analyzer) {
 
 	fmt.Println("\n✨ Refactored analyzer provides improved maintainability and testability!")
 }
+
+// printIndexOverview prints the summary available from a previously written index
+func printIndexOverview(index *parser.HeapIndex) {
+	fmt.Println("⚡ Loaded heap index")
+	fmt.Printf("   Dump timestamp: %s\n", index.DumpTimestamp.Format(time.RFC3339))
+	fmt.Printf("   Records: %d\n", len(index.Records))
+	fmt.Printf("   Classes: %d\n", len(index.Classes))
+	if index.Overview != nil {
+		fmt.Printf("   Objects: %d\n", index.Overview.Objects)
+	}
+	if len(index.Dominators) > 0 {
+		fmt.Printf("   Dominators: %d (cached)\n", len(index.Dominators))
+	}
+	fmt.Println()
+}
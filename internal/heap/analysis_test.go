@@ -0,0 +1,240 @@
+package heap
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mabhi256/jdiag/internal/heap/export"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
+	"github.com/mabhi256/jdiag/internal/heap/testdump"
+)
+
+/*
+ * The fixture is an order cache big enough to span several heap dump
+ * segments, so the parallel parser splits it between workers: a
+ * com.example.OrderCache held by a JNI global, whose Object[] table holds one
+ * HashMap$Node per order. Each node keys a String to an Order, and each Order
+ * holds one of a few shared customer Strings and an Object[] of two item
+ * Strings. All of it hangs off the cache, so the cache retains everything but
+ * the classes.
+ */
+
+const (
+	fixtureOrders    = 8000
+	fixtureCustomers = 10
+)
+
+// fixtureInstances is the histogram the fixture dump must produce
+var fixtureInstances = map[string]int64{
+	"com.example.OrderCache": 1,
+	"com.example.Order":      fixtureOrders,
+	"java.util.HashMap$Node": fixtureOrders,
+	"java.lang.String":       3*fixtureOrders + fixtureCustomers, // Key and two items per order, and the customers
+	"byte[]":                 3*fixtureOrders + fixtureCustomers,
+	"java.lang.Object[]":     fixtureOrders + 1, // Items per order, and the table
+}
+
+/*
+ * Shallow sizes with 8-byte identifiers (16-byte header, 8-byte alignment):
+ * OrderCache 32, Node 48, Order 40, String 32, the byte[]s of up to 12
+ * characters 32, items Object[2] 40 and the table 16 + 4 + 8n.
+ */
+const fixtureCacheRetained = 32 + (16+4+8*fixtureOrders+7)&^7 +
+	fixtureOrders*(48+40+40+3*(32+32)) + fixtureCustomers*(32+32)
+
+func writeFixtureDump(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "orders.hprof")
+	err := testdump.WriteFile(path, 8, func(w *testdump.Writer) {
+		object := w.Class("java.lang.Object", nil)
+		byteArray := w.Class("[B", object)
+		objectArray := w.Class("[Ljava.lang.Object;", object)
+		str := w.Class("java.lang.String", object,
+			testdump.Field{Name: "value", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "hash", Type: model.HPROF_INT},
+			testdump.Field{Name: "coder", Type: model.HPROF_BYTE})
+		node := w.Class("java.util.HashMap$Node", object,
+			testdump.Field{Name: "hash", Type: model.HPROF_INT}, testdump.Field{Name: "key", Type: model.HPROF_NORMAL_OBJECT},
+			testdump.Field{Name: "value", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "next", Type: model.HPROF_NORMAL_OBJECT})
+		order := w.Class("com.example.Order", object,
+			testdump.Field{Name: "id", Type: model.HPROF_LONG}, testdump.Field{Name: "customer", Type: model.HPROF_NORMAL_OBJECT},
+			testdump.Field{Name: "items", Type: model.HPROF_NORMAL_OBJECT})
+		cache := w.Class("com.example.OrderCache", object,
+			testdump.Field{Name: "table", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "size", Type: model.HPROF_INT})
+		for _, class := range []*testdump.Class{object, byteArray, objectArray, str, node, order, cache} {
+			w.Root(model.HPROF_GC_ROOT_STICKY_CLASS, class.ID)
+		}
+
+		customers := make([]model.ID, fixtureCustomers)
+		for i := range customers {
+			customers[i] = w.String("customer-" + string(rune('a'+i)))
+		}
+		table := make([]model.ID, fixtureOrders)
+		for i := range table {
+			items := w.ObjectArray(objectArray, w.String("item-a"), w.String("item-b"))
+			value := w.Instance(order, int64(i), customers[i%fixtureCustomers], items)
+			table[i] = w.Instance(node, int32(i), w.String("order-key"), value, model.ID(0))
+		}
+		w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, w.Instance(cache, w.ObjectArray(objectArray, table...), int32(fixtureOrders)))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// exportFixture analyzes a fresh fixture dump with workers, and again once its index holds the dominators
+func exportFixture(t *testing.T, workers int) (fresh, cached *export.HeapExport) {
+	t.Helper()
+	path := writeFixtureDump(t, t.TempDir())
+	config := &Config{Workers: workers}
+
+	for _, result := range []**export.HeapExport{&fresh, &cached} {
+		var err error
+		if *result, err = BuildExportContext(context.Background(), path, config, export.Options{}); err != nil {
+			t.Fatal(err)
+		}
+		(*result).Dump.File = ""
+	}
+	return fresh, cached
+}
+
+func TestAnalyzeFixture(t *testing.T) {
+	result, _ := exportFixture(t, 1)
+
+	if result.Dump.Truncated {
+		t.Fatal("fixture dump parsed as truncated")
+	}
+	instances := make(map[string]int64)
+	for _, row := range result.Histogram {
+		instances[row.ClassName] = row.Instances
+	}
+	for class, expected := range fixtureInstances {
+		if instances[class] != expected {
+			t.Errorf("%s: %d instances, want %d", class, instances[class], expected)
+		}
+	}
+
+	var cacheRetained uint64
+	for _, row := range result.Objects {
+		if row.ClassName == "com.example.OrderCache" {
+			cacheRetained = row.RetainedSize
+			if row.DominatorID != 0 {
+				t.Errorf("the cache is dominated by 0x%x, want the GC roots", row.DominatorID)
+			}
+		}
+	}
+	if cacheRetained != fixtureCacheRetained {
+		t.Errorf("the cache retains %d bytes, want %d", cacheRetained, fixtureCacheRetained)
+	}
+
+	if len(result.LeakSuspects) == 0 || result.LeakSuspects[0].ClassName != "com.example.OrderCache" {
+		t.Errorf("leak suspects %+v, want the cache first", result.LeakSuspects)
+	}
+}
+
+// TestParallelMatchesSequential checks that splitting the parse between workers,
+// or reusing the dominators cached in the index, doesn't change the analysis
+func TestParallelMatchesSequential(t *testing.T) {
+	sequential, sequentialCached := exportFixture(t, 1)
+	parallel, parallelCached := exportFixture(t, 4)
+
+	for name, result := range map[string]*export.HeapExport{
+		"parallel":                  parallel,
+		"sequential with the index": sequentialCached,
+		"parallel with the index":   parallelCached,
+	} {
+		if !reflect.DeepEqual(result, sequential) {
+			t.Errorf("%s analysis differs from the sequential one:\nsummary %+v\nwant    %+v", name, result.Summary, sequential.Summary)
+		}
+	}
+}
+
+// TestIndexOverview checks that an analysis leaves an index its report can be served from,
+// and that rewriting the dump in place invalidates it even at the same size and mtime
+func TestIndexOverview(t *testing.T) {
+	path := writeFixtureDump(t, t.TempDir())
+	if _, err := BuildExportContext(context.Background(), path, &Config{}, export.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := parser.LoadIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overview := index.Overview
+	if overview == nil {
+		t.Fatal("index has no overview")
+	}
+	if len(overview.Dominators) == 0 || overview.Dominators[0].RetainedSize != fixtureCacheRetained {
+		t.Errorf("top dominators %+v, want the cache first retaining %d bytes", overview.Dominators, fixtureCacheRetained)
+	}
+	for _, class := range overview.Classes {
+		if expected, ok := fixtureInstances[class.ClassName]; ok && int64(class.Instances) != expected {
+			t.Errorf("%s: %d instances in the index, want %d", class.ClassName, class.Instances, expected)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.LoadIndex(path); err == nil {
+		t.Error("index still loads after the dump's content changed")
+	}
+}
+
+// TestHeadlessAnalysis checks that a headless analysis prints nothing and hands an index it
+// couldn't write to OnWarning instead
+func TestHeadlessAnalysis(t *testing.T) {
+	path := writeFixtureDump(t, t.TempDir())
+	// A directory where the index's temporary file goes makes writing it fail
+	if err := os.Mkdir(parser.IndexPath(path)+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = write
+	printed := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(read)
+		printed <- data
+	}()
+
+	var warnings []error
+	config := &Config{OnWarning: func(err error) { warnings = append(warnings, err) }}
+	p, heapAnalyzer, err := AnalyzeHeapDumpContext(context.Background(), path, config)
+	os.Stdout = stdout
+	write.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	heapAnalyzer.Close()
+
+	if output := <-printed; len(output) > 0 {
+		t.Errorf("headless analysis printed %d bytes:\n%s", len(output), output)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings %v, want the index that couldn't be written", warnings)
+	}
+}
@@ -0,0 +1,256 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/registry"
+)
+
+const (
+	MaxClassAllocationSites = 5 // Sites listed per leak suspect class
+	allocationCallerFrames  = 3 // Callers kept below a class allocation site's frame
+)
+
+// AllocationSite is a stack frame that allocated part of a retained set, or instances of a class
+type AllocationSite struct {
+	Frame       string // e.g. com.example.Cache.put(Cache.java:42)
+	Objects     int
+	ShallowSize uint64
+
+	// Set by ClassAllocationSites only
+	RetainedSize uint64   // Retained by the site's instances, not counting ones nested under another instance of the class
+	Callers      []string // Frames below Frame in the trace that allocated the most there, innermost first
+}
+
+/*
+ * AllocationSitesWithin groups the objects retained by matching objects by the
+ * top stack frame of their allocation trace.
+ *
+ * Every HPROF object carries an allocation trace serial, but it only points at
+ * real frames when allocation tracking was on while the dump was written (the
+ * -agentlib:hprof agent or a JVMTI profiler). HotSpot's own dumps point every
+ * object at an empty trace. Objects without frames are skipped, and nil is
+ * returned when none of the retained objects have one.
+ *
+ * An object belongs to the retained set when it, or one of its dominators,
+ * matches inScope. Sites are ordered by shallow size and cut to limit (0 keeps all).
+ */
+func AllocationSitesWithin(ctx *AnalysisContext, stacks *registry.StackRegistry, tree *DominatorTree,
+	inScope func(objectID model.ID) bool, limit int) []*AllocationSite {
+
+	if stacks == nil || stacks.CountTraces() == 0 {
+		return nil
+	}
+
+	sites := make(map[string]*AllocationSite)
+	frames := make(map[model.SerialNum]string) // Trace serial -> formatted top frame ("" = no frames)
+	var scopeRoot model.ID
+
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		if scopeRoot == 0 {
+			if !inScope(objectID) {
+				return
+			}
+			scopeRoot = objectID
+		}
+
+		serial := objectTraceSerial(ctx, objectID)
+		frame, seen := frames[serial]
+		if !seen {
+			frame = topFrame(ctx, stacks, serial)
+			frames[serial] = frame
+		}
+		if frame == "" {
+			return
+		}
+
+		site, exists := sites[frame]
+		if !exists {
+			site = &AllocationSite{Frame: frame}
+			sites[frame] = site
+		}
+		site.Objects++
+		site.ShallowSize += tree.ShallowSize(objectID)
+	}, func(objectID model.ID) {
+		if objectID == scopeRoot {
+			scopeRoot = 0
+		}
+	})
+
+	if len(sites) == 0 {
+		return nil
+	}
+
+	result := make([]*AllocationSite, 0, len(sites))
+	for _, site := range sites {
+		result = append(result, site)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ShallowSize != result[j].ShallowSize {
+			return result[i].ShallowSize > result[j].ShallowSize
+		}
+		return result[i].Frame < result[j].Frame
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+/*
+ * ClassAllocationSites groups the reachable instances of a class by the top
+ * frame of their allocation trace, answering which code allocated a leaking
+ * class rather than only which class leaks. Generic frames (a collection's
+ * node factory, a String constructor) say little on their own, so each site
+ * also keeps the callers from the trace that allocated the most bytes there.
+ *
+ * A site's retained size counts the instances no other instance of the class
+ * dominates, so a chain of nodes isn't counted once per link. Sites are
+ * ordered by retained size and cut to limit (0 keeps all); nil when the dump
+ * has no allocation traces for the class.
+ */
+func ClassAllocationSites(ctx *AnalysisContext, stacks *registry.StackRegistry, tree *DominatorTree,
+	className string, limit int) []*AllocationSite {
+
+	if stacks == nil || stacks.CountTraces() == 0 {
+		return nil
+	}
+
+	type siteTrace struct {
+		site    *AllocationSite
+		callers []string
+	}
+	sites := make(map[string]*AllocationSite)
+	traces := make(map[model.SerialNum]*siteTrace) // nil = no frames
+	traceBytes := make(map[model.SerialNum]uint64)
+	heaviest := make(map[*AllocationSite]model.SerialNum)
+	var outermost model.ID
+
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		if object, _ := ctx.DescribeObject(objectID); object.ClassName != className {
+			return
+		}
+		isOutermost := outermost == 0
+		if isOutermost {
+			outermost = objectID
+		}
+
+		serial := objectTraceSerial(ctx, objectID)
+		trace, seen := traces[serial]
+		if !seen {
+			if frames := traceFrames(ctx, stacks, serial, allocationCallerFrames+1); len(frames) > 0 {
+				site, exists := sites[frames[0]]
+				if !exists {
+					site = &AllocationSite{Frame: frames[0]}
+					sites[frames[0]] = site
+				}
+				trace = &siteTrace{site: site, callers: frames[1:]}
+			}
+			traces[serial] = trace
+		}
+		if trace == nil {
+			return
+		}
+
+		site := trace.site
+		shallow := tree.ShallowSize(objectID)
+		site.Objects++
+		site.ShallowSize += shallow
+		if isOutermost {
+			site.RetainedSize += retained
+		}
+
+		traceBytes[serial] += shallow
+		if best, ok := heaviest[site]; !ok || traceBytes[serial] > traceBytes[best] {
+			heaviest[site] = serial
+			site.Callers = trace.callers
+		}
+	}, func(objectID model.ID) {
+		if objectID == outermost {
+			outermost = 0
+		}
+	})
+
+	if len(sites) == 0 {
+		return nil
+	}
+
+	result := make([]*AllocationSite, 0, len(sites))
+	for _, site := range sites {
+		result = append(result, site)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].RetainedSize != result[j].RetainedSize {
+			return result[i].RetainedSize > result[j].RetainedSize
+		}
+		return result[i].Frame < result[j].Frame
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// objectTraceSerial returns the allocation trace serial of an instance or array
+func objectTraceSerial(ctx *AnalysisContext, objectID model.ID) model.SerialNum {
+	if instance, ok := ctx.InstanceReg.GetInstance(objectID); ok {
+		return instance.StackTraceSerialNumber
+	}
+	if array, ok := ctx.ArrayReg.GetObjectArray(objectID); ok {
+		return array.StackTraceSerialNumber
+	}
+	if array, ok := ctx.ArrayReg.GetPrimitiveArray(objectID); ok {
+		return array.StackTraceSerialNumber
+	}
+	return 0
+}
+
+// topFrame formats the innermost frame of a trace, or "" if the trace has none
+func topFrame(ctx *AnalysisContext, stacks *registry.StackRegistry, serial model.SerialNum) string {
+	if frames := traceFrames(ctx, stacks, serial, 1); len(frames) > 0 {
+		return frames[0]
+	}
+	return ""
+}
+
+// traceFrames formats up to depth frames of a trace, innermost first
+func traceFrames(ctx *AnalysisContext, stacks *registry.StackRegistry, serial model.SerialNum, depth int) []string {
+	trace, ok := stacks.GetTrace(serial)
+	if !ok {
+		return nil
+	}
+
+	var frames []string
+	for _, frameID := range trace.StackFrameIDs[:min(depth, len(trace.StackFrameIDs))] {
+		frame, ok := stacks.GetFrame(frameID)
+		if !ok {
+			break
+		}
+		frames = append(frames, formatFrame(ctx, frame))
+	}
+	return frames
+}
+
+func formatFrame(ctx *AnalysisContext, frame *model.FrameBody) string {
+	className := "?"
+	if classInfo, ok := ctx.ClassReg.Get(frame.ClassSerialNumber); ok {
+		className = JavaClassName(classInfo.ClassName)
+	}
+	method := ctx.StringReg.GetOrUnresolved(frame.MethodNameID)
+	source, _ := ctx.StringReg.Get(frame.SourceFileNameID)
+
+	switch {
+	case frame.LineNumber > 0 && source != "":
+		return fmt.Sprintf("%s.%s(%s:%d)", className, method, source, frame.LineNumber)
+	case frame.LineNumber == -3:
+		return fmt.Sprintf("%s.%s(Native Method)", className, method)
+	case source != "":
+		return fmt.Sprintf("%s.%s(%s)", className, method, source)
+	default:
+		return fmt.Sprintf("%s.%s(Unknown Source)", className, method)
+	}
+}
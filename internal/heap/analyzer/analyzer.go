@@ -2,10 +2,13 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
+	"runtime"
 	"time"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 	"github.com/mabhi256/jdiag/internal/heap/registry"
+	"github.com/mabhi256/jdiag/utils"
 )
 
 // Analyzer coordinates the analysis phases using a context-based approach
@@ -22,6 +25,19 @@ type Analyzer struct {
 	ValidationResult *ValidationResult
 	ReferenceMap     *ReferenceMap
 	ObjectGraph      *ObjectGraph
+	DominatorTree    *DominatorTree
+	Histogram        *ClassHistogram
+	LeakSuspects     []*LeakSuspect
+	ReferenceStats   *ReferenceStats
+
+	// Immediate dominators from a previous run (heap index), used instead of recomputing
+	cachedIdoms map[model.ID]model.ID
+
+	// Bytes the analysis aims to stay under; 0 is unlimited
+	memoryBudget int64
+
+	// Allocation traces and frames, for the leak suspects' allocation sites
+	stacks *registry.StackRegistry
 
 	// Analysis metadata
 	startTime    time.Time
@@ -29,12 +45,13 @@ type Analyzer struct {
 }
 
 // NewAnalyzer creates a new heap analyzer with simplified constructor
-func NewAnalyzer(stringReg *registry.StringRegistry, classDumpReg *registry.ClassDumpRegistry,
+func NewAnalyzer(stringReg *registry.StringRegistry, classReg *registry.ClassRegistry,
+	classDumpReg *registry.ClassDumpRegistry,
 	instanceReg *registry.InstanceRegistry, arrayReg *registry.ArrayRegistry,
 	rootReg *registry.GCRootRegistry, identifierSize uint32) *Analyzer {
 
 	// Create analysis context
-	ctx := NewAnalysisContext(stringReg, classDumpReg, instanceReg, arrayReg, rootReg, identifierSize)
+	ctx := NewAnalysisContext(stringReg, classReg, classDumpReg, instanceReg, arrayReg, rootReg, identifierSize)
 
 	analyzer := &Analyzer{
 		ctx: ctx,
@@ -43,7 +60,7 @@ func NewAnalyzer(stringReg *registry.StringRegistry, classDumpReg *registry.Clas
 	// Initialize components with error handling
 	if err := analyzer.initializeComponents(); err != nil {
 		// Log error but continue - components will be nil and methods will handle gracefully
-		fmt.Printf("Warning: Failed to initialize analysis components: %v\n", err)
+		fmt.Fprintf(ctx.Out, "Warning: Failed to initialize analysis components: %v\n", err)
 	}
 
 	return analyzer
@@ -99,26 +116,38 @@ func (a *Analyzer) PerformAnalysis() error {
 	}
 
 	a.startTime = time.Now()
-	fmt.Println("🚀 Starting Phase 11: Data Validation & Cross-Reference Resolution")
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out, "🚀 Starting Phase 11: Data Validation & Cross-Reference Resolution")
+	fmt.Fprintln(a.ctx.Out)
 
 	// Step 11.1: Reference Validation
 	if err := a.performReferenceValidation(); err != nil {
 		return fmt.Errorf("reference validation failed: %w", err)
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
 
 	// Step 11.2: Cross-Reference Resolution
 	if err := a.performCrossReferenceResolution(); err != nil {
 		return fmt.Errorf("cross-reference resolution failed: %w", err)
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
 
 	// Step 11.3: Object Graph Construction
 	if err := a.performObjectGraphConstruction(); err != nil {
 		return fmt.Errorf("object graph construction failed: %w", err)
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
+
+	// Step 12: Dominator tree, retained sizes and leak suspects
+	if err := a.performRetainedSizeAnalysis(); err != nil {
+		return fmt.Errorf("retained size analysis failed: %w", err)
+	}
+	fmt.Fprintln(a.ctx.Out)
+
+	// Step 13: Soft/weak/final/phantom reachability
+	if err := a.performReferenceAnalysis(); err != nil {
+		return fmt.Errorf("reference analysis failed: %w", err)
+	}
+	fmt.Fprintln(a.ctx.Out)
 
 	// Finalize analysis
 	a.finalizeAnalysis()
@@ -147,6 +176,8 @@ func (a *Analyzer) performCrossReferenceResolution() error {
 		return fmt.Errorf("resolver not initialized")
 	}
 
+	a.planSpill()
+
 	refMap, err := a.resolver.BuildReferenceMap()
 	if err != nil {
 		return err
@@ -156,6 +187,32 @@ func (a *Analyzer) performCrossReferenceResolution() error {
 	return nil
 }
 
+// planSpill spills the reference edges and dominator arrays to disk when they won't fit in
+// what's left of the memory budget after parsing
+func (a *Analyzer) planSpill() {
+	if a.memoryBudget <= 0 || a.ctx.spill != nil {
+		return
+	}
+
+	objects := len(a.ctx.InstanceReg.GetAllInstances()) + len(a.ctx.ArrayReg.GetAllObjectArrays()) +
+		len(a.ctx.ArrayReg.GetAllPrimitiveArrays()) + len(a.ctx.ClassDumpReg.GetAllClassDumps())
+	needed := int64(objects) * inMemoryBytesPerObject
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	left := a.memoryBudget - int64(memStats.HeapAlloc)
+
+	if needed <= left {
+		fmt.Fprintf(a.ctx.Out, "  Reference graph needs ~%s, %s of the %s memory budget is left\n",
+			utils.MemorySize(needed), utils.MemorySize(max(left, 0)), utils.MemorySize(a.memoryBudget))
+		return
+	}
+
+	fmt.Fprintf(a.ctx.Out, "💾 Reference graph needs ~%s but only %s of the %s memory budget is left; spilling to disk\n",
+		utils.MemorySize(needed), utils.MemorySize(max(left, 0)), utils.MemorySize(a.memoryBudget))
+	a.ctx.spill = newSpillArena("", a.ctx.Out)
+}
+
 // performObjectGraphConstruction executes Step 11.3: Object Graph Construction
 func (a *Analyzer) performObjectGraphConstruction() error {
 	if a.graphBuilder == nil {
@@ -171,6 +228,104 @@ func (a *Analyzer) performObjectGraphConstruction() error {
 	return nil
 }
 
+// performRetainedSizeAnalysis executes Step 12: dominator tree, class histogram and leak suspects
+func (a *Analyzer) performRetainedSizeAnalysis() error {
+	fmt.Fprintln(a.ctx.Out, "🌳 Phase 12: Computing dominator tree & retained sizes...")
+
+	var tree *DominatorTree
+	if len(a.cachedIdoms) > 0 {
+		cached, err := NewDominatorTreeFromIdoms(a.ctx, a.cachedIdoms)
+		if err != nil {
+			fmt.Fprintf(a.ctx.Out, "  Cached dominators unusable (%v), recomputing\n", err)
+		} else {
+			fmt.Fprintln(a.ctx.Out, "  Using cached dominators from heap index")
+			tree = cached
+		}
+	}
+
+	if tree == nil {
+		computed, err := BuildDominatorTree(a.ctx, a.ObjectGraph)
+		if err != nil {
+			return err
+		}
+		tree = computed
+	}
+	a.DominatorTree = tree
+
+	a.Histogram = BuildClassHistogram(a.ctx, tree)
+	a.LeakSuspects = FindLeakSuspects(a.ctx, tree, a.Histogram)
+
+	suspectsWithSites := 0
+	for _, suspect := range a.LeakSuspects {
+		suspect.AllocationSites = ClassAllocationSites(a.ctx, a.stacks, tree, suspect.ClassName, MaxClassAllocationSites)
+		if len(suspect.AllocationSites) > 0 {
+			suspectsWithSites++
+		}
+	}
+
+	fmt.Fprintf(a.ctx.Out, "  Reachable objects: %d\n", tree.ReachableCount())
+	fmt.Fprintf(a.ctx.Out, "  Reachable heap: %s\n", utils.MemorySize(tree.TotalRetainedSize()))
+	fmt.Fprintf(a.ctx.Out, "  Classes in histogram: %d\n", len(a.Histogram.Entries))
+	fmt.Fprintf(a.ctx.Out, "  Leak suspects: %d (%d with allocation sites)\n", len(a.LeakSuspects), suspectsWithSites)
+	if a.ctx.spill != nil {
+		fmt.Fprintf(a.ctx.Out, "  Spilled to disk: %s\n", utils.MemorySize(a.ctx.spill.size()))
+	}
+	fmt.Fprintf(a.ctx.Out, "    ✅ Retained size analysis complete\n")
+
+	return nil
+}
+
+// performReferenceAnalysis executes Step 13: classify the heap by reference strength
+func (a *Analyzer) performReferenceAnalysis() error {
+	fmt.Fprintln(a.ctx.Out, "🔗 Phase 13: Classifying soft/weak/final/phantom references...")
+
+	stats, err := AnalyzeReferences(a.ctx, a.ObjectGraph)
+	if err != nil {
+		return err
+	}
+	a.ReferenceStats = stats
+
+	fmt.Fprintf(a.ctx.Out, "  Strongly reachable: %s (%d objects)\n",
+		utils.MemorySize(stats.StronglyReachableSize), stats.StronglyReachableCount)
+	for _, kind := range ReferenceKinds {
+		kindStats := stats.Kinds[kind]
+		fmt.Fprintf(a.ctx.Out, "  %s references: %d, only %s-reachable: %s (%d objects)\n",
+			kind, kindStats.Count, kind, utils.MemorySize(kindStats.ReachableSize), kindStats.ReachableCount)
+	}
+	fmt.Fprintf(a.ctx.Out, "    ✅ Reference analysis complete\n")
+
+	return nil
+}
+
+// SetMemoryBudget caps the memory the analysis aims to use; past it, the reference edges and
+// dominator arrays are kept in temporary files. 0 removes the cap.
+func (a *Analyzer) SetMemoryBudget(bytes int64) {
+	a.memoryBudget = bytes
+}
+
+// Close releases what the analysis spilled to disk; the analyzer can't be used afterwards
+func (a *Analyzer) Close() error {
+	if a.ctx.spill == nil {
+		return nil
+	}
+	return a.ctx.spill.close()
+}
+
+// SetOutput sets where the phases report progress, stdout by default; io.Discard silences them
+func (a *Analyzer) SetOutput(out io.Writer) {
+	a.ctx.Out = out
+}
+
+// SetStackRegistry provides the dump's allocation traces, so leak suspects can name the code that allocated them
+func (a *Analyzer) SetStackRegistry(stacks *registry.StackRegistry) {
+	a.stacks = stacks
+}
+
+// SetCachedDominators provides immediate dominators from a previous run to skip recomputation
+func (a *Analyzer) SetCachedDominators(idoms map[model.ID]model.ID) {
+	a.cachedIdoms = idoms
+}
+
 // finalizeAnalysis completes the analysis and prints summary
 func (a *Analyzer) finalizeAnalysis() {
 	// Record completion time
@@ -182,20 +337,20 @@ func (a *Analyzer) finalizeAnalysis() {
 
 // printFinalSummary prints a comprehensive summary of Phase 11 analysis
 func (a *Analyzer) printFinalSummary() {
-	fmt.Println("🎯 Phase 11 Analysis Complete")
-	fmt.Printf("Analysis time: %v\n", a.analysisTime)
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out, "🎯 Phase 11 Analysis Complete")
+	fmt.Fprintf(a.ctx.Out, "Analysis time: %v\n", a.analysisTime)
+	fmt.Fprintln(a.ctx.Out)
 
 	// Print object graph summary
 	if a.ObjectGraph != nil {
-		fmt.Println(a.ObjectGraph.Summary())
+		fmt.Fprintln(a.ctx.Out, a.ObjectGraph.Summary())
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
 
 	// Show success message if validation passed
 	if a.ValidationResult != nil && a.ObjectGraph != nil {
-		fmt.Println()
-		fmt.Printf("🎉 Reference integrity: %.2f%% - validation successful!\n",
+		fmt.Fprintln(a.ctx.Out)
+		fmt.Fprintf(a.ctx.Out, "🎉 Reference integrity: %.2f%% - validation successful!\n",
 			a.ObjectGraph.getReferenceIntegrity())
 	}
 }
@@ -222,6 +377,26 @@ func (a *Analyzer) GetObjectGraph() *ObjectGraph {
 	return a.ObjectGraph
 }
 
+// GetDominatorTree returns the dominator tree with retained sizes
+func (a *Analyzer) GetDominatorTree() *DominatorTree {
+	return a.DominatorTree
+}
+
+// GetHistogram returns the class histogram
+func (a *Analyzer) GetHistogram() *ClassHistogram {
+	return a.Histogram
+}
+
+// GetLeakSuspects returns the detected leak suspects, largest first
+func (a *Analyzer) GetLeakSuspects() []*LeakSuspect {
+	return a.LeakSuspects
+}
+
+// GetReferenceStats returns the soft/weak/final/phantom reachability breakdown
+func (a *Analyzer) GetReferenceStats() *ReferenceStats {
+	return a.ReferenceStats
+}
+
 // GetContext returns the analysis context (useful for testing)
 func (a *Analyzer) GetContext() *AnalysisContext {
 	return a.ctx
@@ -286,31 +461,31 @@ func (a *Analyzer) GetAnalysisMetadata() map[string]interface{} {
 // PrintDetailedReport prints a detailed analysis report
 func (a *Analyzer) PrintDetailedReport() {
 	if !a.IsReady() {
-		fmt.Println("❌ Analysis not complete - run PerformAnalysis() first")
+		fmt.Fprintln(a.ctx.Out, "❌ Analysis not complete - run PerformAnalysis() first")
 		return
 	}
 
-	fmt.Println("📊 DETAILED PHASE 11 ANALYSIS REPORT")
+	fmt.Fprintln(a.ctx.Out, "📊 DETAILED PHASE 11 ANALYSIS REPORT")
 
 	// Analysis metadata
-	fmt.Printf("Analysis Duration: %v\n", a.analysisTime)
-	fmt.Printf("Analysis Date: %s\n", a.startTime.Format("2006-01-02 15:04:05"))
-	fmt.Println()
+	fmt.Fprintf(a.ctx.Out, "Analysis Duration: %v\n", a.analysisTime)
+	fmt.Fprintf(a.ctx.Out, "Analysis Date: %s\n", a.startTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(a.ctx.Out)
 
 	// Validation details
-	fmt.Println("🔍 REFERENCE VALIDATION")
+	fmt.Fprintln(a.ctx.Out, "🔍 REFERENCE VALIDATION")
 	if a.ValidationResult != nil {
-		fmt.Printf("Total References: %d\n", a.ValidationResult.TotalRefs)
-		fmt.Printf("Valid References: %d\n", a.ValidationResult.ValidRefs)
-		fmt.Printf("Integrity: %.2f%%\n",
+		fmt.Fprintf(a.ctx.Out, "Total References: %d\n", a.ValidationResult.TotalRefs)
+		fmt.Fprintf(a.ctx.Out, "Valid References: %d\n", a.ValidationResult.ValidRefs)
+		fmt.Fprintf(a.ctx.Out, "Integrity: %.2f%%\n",
 			float64(a.ValidationResult.ValidRefs)/float64(a.ValidationResult.TotalRefs)*100)
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
 
 	// Object graph details
-	fmt.Println("🗺️  OBJECT GRAPH")
+	fmt.Fprintln(a.ctx.Out, "🗺️  OBJECT GRAPH")
 	if a.ObjectGraph != nil {
-		fmt.Println(a.ObjectGraph.Summary())
+		fmt.Fprintln(a.ctx.Out, a.ObjectGraph.Summary())
 	}
-	fmt.Println()
+	fmt.Fprintln(a.ctx.Out)
 }
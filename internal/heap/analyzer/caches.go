@@ -0,0 +1,390 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* Cache detection
+*
+* Caches are the maps a program means to keep, and the ones most often left
+* without a bound. Three kinds are found:
+*
+* 	guava		com.google.common.cache.LocalCache: entries summed over its
+* 				segments, bounded by maxWeight and the expireAfter settings
+* 	caffeine	BoundedLocalCache (generated subclasses that carry a maximum
+* 				and expiresAfter fields) and UnboundedLocalCache
+* 	map			a HashMap, LinkedHashMap or ConcurrentHashMap of at least
+* 				MinCacheEntries that is access-ordered (the LRU idiom) or
+* 				whose values carry timestamp or expiry fields
+*
+* Guava and Caffeine keep hit and miss counters when built with
+* recordStats(), which give the cache's hit rate. A plain map has neither a
+* bound nor stats; the timestamps in its values only say that something was
+* meant to evict them.
+*
+* Between two dumps of the same process, caches are matched by kind, class
+* and the field that holds them, since object IDs change as the GC moves
+* objects.
+ */
+
+const (
+	MinCacheEntries  = 100 // Entries a plain map needs to be taken for a cache
+	cacheValueSample = 32  // Values of a plain map checked for timestamp fields
+	LowCacheHitRate  = 0.5 // Below it, most lookups miss
+	guavaUnset       = -1  // LocalCache.UNSET_INT, for maxWeight and the expiry settings
+)
+
+type CacheKind int
+
+const (
+	GuavaCache CacheKind = iota
+	CaffeineCache
+	MapCache
+)
+
+func (k CacheKind) String() string {
+	switch k {
+	case GuavaCache:
+		return "guava"
+	case CaffeineCache:
+		return "caffeine"
+	case MapCache:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+// Fragments of field names that mark a value as stamped with its age or expiry
+var cacheTimestampFields = []string{"expir", "timestamp", "ttl", "lastaccess", "lastused", "lastmodified",
+	"created", "inserted", "loadtime", "writetime", "accesstime", "deadline"}
+
+// Cache is one cache-like structure in the dump
+type Cache struct {
+	ObjectID     model.ID
+	ClassName    string
+	Kind         CacheKind
+	Holder       string // Field that holds it, e.g. com.example.UserService.sessions; "" when not a plain field
+	Entries      int64
+	RetainedSize uint64
+
+	MaximumSize       int64 // Entry or weight bound; -1 when unbounded
+	ExpireAfterWrite  int64 // Nanoseconds; 0 when not set
+	ExpireAfterAccess int64
+	LRU               bool     // An access-ordered LinkedHashMap
+	EvictingSubclass  bool     // A LinkedHashMap subclass, presumably overriding removeEldestEntry
+	TimestampFields   []string // Fields of a plain map's values that look like timestamps
+
+	Hits, Misses int64 // From recordStats(); both 0 when stats are off
+
+	Baseline *Cache // The same cache in the baseline dump, set by CompareCaches
+}
+
+// Bounded reports whether the cache evicts on size or age
+func (c *Cache) Bounded() bool {
+	return c.MaximumSize >= 0 || c.ExpireAfterWrite > 0 || c.ExpireAfterAccess > 0 || c.EvictingSubclass
+}
+
+// HitRate is hits over lookups; ok is false without recorded stats
+func (c *Cache) HitRate() (rate float64, ok bool) {
+	lookups := c.Hits + c.Misses
+	if lookups == 0 {
+		return 0, false
+	}
+	return float64(c.Hits) / float64(lookups), true
+}
+
+// Suggestion names a bounding strategy for the cache, or "" when it looks bounded and effective
+func (c *Cache) Suggestion() string {
+	switch {
+	case c.Kind == MapCache && c.LRU && !c.EvictingSubclass:
+		return "access-ordered LinkedHashMap never evicts on its own: override removeEldestEntry, or use Caffeine with maximumSize"
+	case c.Kind == MapCache && !c.EvictingSubclass && len(c.TimestampFields) > 0:
+		return "values carry " + strings.Join(c.TimestampFields, ", ") + " but a map never expires them: use Caffeine with maximumSize and expireAfterWrite"
+	case c.Kind != MapCache && c.MaximumSize < 0 && c.ExpireAfterWrite == 0 && c.ExpireAfterAccess == 0:
+		return "unbounded: set maximumSize (or maximumWeight) and expireAfterWrite"
+	case c.Kind != MapCache && c.MaximumSize < 0:
+		return "expiry alone doesn't cap a burst of new keys: add maximumSize"
+	}
+	if rate, ok := c.HitRate(); ok && rate < LowCacheHitRate {
+		return "most lookups miss: entries are evicted before they're reused, or keys rarely repeat; check key cardinality before raising the bound"
+	}
+	return ""
+}
+
+// FindCaches finds the Guava, Caffeine and map-based caches in the dump, biggest first
+func FindCaches(ctx *AnalysisContext, tree *DominatorTree) []*Cache {
+	extractor := NewFieldExtractor(ctx)
+	kindOf := make(map[model.ID]string) // Class ID -> the cache or map base class it extends, "" for none
+	var caches []*Cache
+	frameworkMaps := make(map[model.ID]bool) // Caffeine's backing maps, not reported twice
+
+	baseClasses := []string{
+		"com.google.common.cache.LocalCache",
+		"com.github.benmanes.caffeine.cache.BoundedLocalCache",
+		"com.github.benmanes.caffeine.cache.UnboundedLocalCache",
+		"java.util.LinkedHashMap",
+		"java.util.HashMap",
+		"java.util.concurrent.ConcurrentHashMap",
+	}
+
+	var maps []model.ID
+	for objectID, instance := range ctx.InstanceReg.GetAllInstances() {
+		base, ok := kindOf[instance.ClassObjectID]
+		if !ok {
+			for _, className := range baseClasses {
+				if ctx.extendsClass(instance.ClassObjectID, className) {
+					base = className
+					break
+				}
+			}
+			kindOf[instance.ClassObjectID] = base
+		}
+		if base == "" || (tree != nil && !tree.Contains(objectID)) {
+			continue
+		}
+
+		cache := &Cache{ObjectID: objectID, ClassName: ctx.ClassName(instance.ClassObjectID), MaximumSize: -1}
+		switch base {
+		case "com.google.common.cache.LocalCache":
+			cache.Kind = GuavaCache
+			readGuavaCache(extractor, ctx, cache)
+		case "com.github.benmanes.caffeine.cache.BoundedLocalCache", "com.github.benmanes.caffeine.cache.UnboundedLocalCache":
+			cache.Kind = CaffeineCache
+			data := extractor.ReadReference(objectID, "data")
+			frameworkMaps[data] = true
+			cache.Entries = mapSize(extractor, ctx, data)
+			readCaffeineCache(extractor, cache)
+		default:
+			maps = append(maps, objectID)
+			continue
+		}
+		caches = append(caches, cache)
+	}
+
+	for _, objectID := range maps {
+		if frameworkMaps[objectID] {
+			continue
+		}
+		entries := mapSize(extractor, ctx, objectID)
+		instance, _ := ctx.InstanceReg.GetInstance(objectID)
+		cache := &Cache{ObjectID: objectID, ClassName: ctx.ClassName(instance.ClassObjectID), Kind: MapCache,
+			MaximumSize: -1, Entries: entries}
+
+		if kindOf[instance.ClassObjectID] == "java.util.LinkedHashMap" {
+			if accessOrder, ok := extractor.ReadField(objectID, "accessOrder"); ok {
+				cache.LRU, _ = accessOrder.Value.(bool)
+			}
+			cache.EvictingSubclass = cache.ClassName != "java.util.LinkedHashMap"
+		}
+		if entries < MinCacheEntries {
+			continue
+		}
+		cache.TimestampFields = timestampFieldsOfValues(extractor, ctx, objectID)
+		if !cache.LRU && len(cache.TimestampFields) == 0 {
+			continue
+		}
+		caches = append(caches, cache)
+	}
+
+	for _, cache := range caches {
+		if tree == nil {
+			continue
+		}
+		cache.RetainedSize = tree.RetainedSize(cache.ObjectID)
+		cache.Holder = cacheHolder(ctx, tree, extractor, cache.ObjectID)
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		if caches[i].RetainedSize != caches[j].RetainedSize {
+			return caches[i].RetainedSize > caches[j].RetainedSize
+		}
+		return caches[i].ObjectID < caches[j].ObjectID
+	})
+	return caches
+}
+
+/*
+ * cacheHolder names the field that holds a cache. Guava and Caffeine hand out
+ * a wrapper (LocalManualCache and the like) around the cache found here, so
+ * the field holding the wrapper is the one named.
+ */
+func cacheHolder(ctx *AnalysisContext, tree *DominatorTree, extractor *FieldExtractor, objectID model.ID) string {
+	for range 2 {
+		holder, ok := tree.ImmediateDominator(objectID)
+		if !ok {
+			return ""
+		}
+		field := extractor.FieldReferencing(holder, objectID)
+		if field == "" {
+			return ""
+		}
+		holderObject, _ := ctx.DescribeObject(holder)
+		if !strings.HasPrefix(holderObject.ClassName, "com.google.common.cache.") &&
+			!strings.HasPrefix(holderObject.ClassName, "com.github.benmanes.caffeine.cache.") {
+			return holderObject.ClassName + "." + field
+		}
+		objectID = holder
+	}
+	return ""
+}
+
+/*
+ * CompareCaches sets each cache's Baseline to the same cache in an earlier
+ * dump: the one of the same kind, class and holder, pairing the biggest
+ * with the biggest when there are several.
+ */
+func CompareCaches(caches, baseline []*Cache) {
+	key := func(c *Cache) string {
+		return strings.Join([]string{c.Kind.String(), c.ClassName, c.Holder}, "\x00")
+	}
+	earlier := make(map[string][]*Cache)
+	for _, cache := range baseline { // Already biggest first
+		earlier[key(cache)] = append(earlier[key(cache)], cache)
+	}
+	for _, cache := range caches {
+		if matches := earlier[key(cache)]; len(matches) > 0 {
+			cache.Baseline = matches[0]
+			earlier[key(cache)] = matches[1:]
+		}
+	}
+}
+
+// readGuavaCache sums the segments' counts and reads the bounds and stats
+func readGuavaCache(extractor *FieldExtractor, ctx *AnalysisContext, cache *Cache) {
+	if maxWeight, ok := extractor.ReadInt(cache.ObjectID, "maxWeight"); ok && maxWeight != guavaUnset {
+		cache.MaximumSize = maxWeight
+	}
+	cache.ExpireAfterWrite = positiveField(extractor, cache.ObjectID, "expireAfterWriteNanos")
+	cache.ExpireAfterAccess = positiveField(extractor, cache.ObjectID, "expireAfterAccessNanos")
+
+	segments, ok := ctx.ArrayReg.GetObjectArray(extractor.ReadReference(cache.ObjectID, "segments"))
+	if !ok {
+		return
+	}
+	for _, segment := range segments.Elements {
+		count, _ := extractor.ReadInt(segment, "count")
+		cache.Entries += count
+
+		stats := extractor.ReadReference(segment, "statsCounter")
+		cache.Hits += readCounter(extractor, ctx, extractor.ReadReference(stats, "hitCount"))
+		cache.Misses += readCounter(extractor, ctx, extractor.ReadReference(stats, "missCount"))
+	}
+}
+
+// readCaffeineCache reads the bounds the generated subclass carries and the stats
+func readCaffeineCache(extractor *FieldExtractor, cache *Cache) {
+	if maximum, ok := extractor.ReadInt(cache.ObjectID, "maximum"); ok {
+		cache.MaximumSize = maximum
+	}
+	cache.ExpireAfterWrite = positiveField(extractor, cache.ObjectID, "expiresAfterWriteNanos")
+	cache.ExpireAfterAccess = positiveField(extractor, cache.ObjectID, "expiresAfterAccessNanos")
+
+	stats := extractor.ReadReference(cache.ObjectID, "statsCounter")
+	if delegate := extractor.ReadReference(stats, "delegate"); delegate != 0 { // GuardedStatsCounter
+		stats = delegate
+	}
+	cache.Hits = readCounter(extractor, extractor.ctx, extractor.ReadReference(stats, "hitCount"))
+	cache.Misses = readCounter(extractor, extractor.ctx, extractor.ReadReference(stats, "missCount"))
+}
+
+func positiveField(extractor *FieldExtractor, objectID model.ID, name string) int64 {
+	if value, ok := extractor.ReadInt(objectID, name); ok && value > 0 {
+		return value
+	}
+	return 0
+}
+
+// readCounter sums a LongAdder (base plus its cells), or reads an AtomicLong's value
+func readCounter(extractor *FieldExtractor, ctx *AnalysisContext, counterID model.ID) int64 {
+	if counterID == 0 {
+		return 0
+	}
+	if value, ok := extractor.ReadInt(counterID, "value"); ok {
+		return value
+	}
+	sum, _ := extractor.ReadInt(counterID, "base")
+	if cells, ok := ctx.ArrayReg.GetObjectArray(extractor.ReadReference(counterID, "cells")); ok {
+		for _, cell := range cells.Elements {
+			value, _ := extractor.ReadInt(cell, "value")
+			sum += value
+		}
+	}
+	return sum
+}
+
+// mapSize reads a HashMap's size, or a ConcurrentHashMap's baseCount plus its counter cells
+func mapSize(extractor *FieldExtractor, ctx *AnalysisContext, mapID model.ID) int64 {
+	if size, ok := extractor.ReadInt(mapID, "size"); ok {
+		return size
+	}
+	size, _ := extractor.ReadInt(mapID, "baseCount")
+	if cells, ok := ctx.ArrayReg.GetObjectArray(extractor.ReadReference(mapID, "counterCells")); ok {
+		for _, cell := range cells.Elements {
+			value, _ := extractor.ReadInt(cell, "value")
+			size += value
+		}
+	}
+	return size
+}
+
+// timestampFieldsOfValues samples a map's values for fields named like timestamps or expiry times
+func timestampFieldsOfValues(extractor *FieldExtractor, ctx *AnalysisContext, mapID model.ID) []string {
+	table, ok := ctx.ArrayReg.GetObjectArray(extractor.ReadReference(mapID, "table"))
+	if !ok {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	sampled := 0
+	for _, node := range table.Elements {
+		if sampled >= cacheValueSample {
+			break
+		}
+		if node == 0 {
+			continue
+		}
+		value := extractor.ReadReference(node, "value") // HashMap.Node
+		if value == 0 {
+			value = extractor.ReadReference(node, "val") // ConcurrentHashMap.Node
+		}
+		fields, err := extractor.InstanceFields(value)
+		if err != nil {
+			continue
+		}
+		sampled++
+		for _, field := range fields {
+			if field.IsReference && !isTimeClass(ctx, field.Value) {
+				continue
+			}
+			name := strings.ToLower(field.Name)
+			for _, fragment := range cacheTimestampFields {
+				if strings.Contains(name, fragment) {
+					found[field.Name] = true
+					break
+				}
+			}
+		}
+	}
+
+	var names []string
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isTimeClass reports whether a reference field points at a java.time or java.util.Date value
+func isTimeClass(ctx *AnalysisContext, value any) bool {
+	id, ok := value.(model.ID)
+	if !ok || id == 0 {
+		return false
+	}
+	object, _ := ctx.DescribeObject(id)
+	return strings.HasPrefix(object.ClassName, "java.time.") || object.ClassName == "java.util.Date"
+}
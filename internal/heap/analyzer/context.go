@@ -2,6 +2,8 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/mabhi256/jdiag/internal/heap/registry"
 )
@@ -10,6 +12,7 @@ import (
 type AnalysisContext struct {
 	// Core registries from parsing phases
 	StringReg    *registry.StringRegistry
+	ClassReg     *registry.ClassRegistry
 	ClassDumpReg *registry.ClassDumpRegistry
 	InstanceReg  *registry.InstanceRegistry
 	ArrayReg     *registry.ArrayRegistry
@@ -17,6 +20,12 @@ type AnalysisContext struct {
 
 	// Configuration
 	Config *AnalysisConfig
+
+	// Disk-backed storage for the reference edges and dominator arrays, when over the memory budget
+	spill *spillArena
+
+	// Where the phases report progress; stdout unless the analysis runs headless
+	Out io.Writer
 }
 
 // AnalysisConfig holds configuration parameters for the analysis
@@ -25,12 +34,14 @@ type AnalysisConfig struct {
 }
 
 // NewAnalysisContext creates a new analysis context with the provided registries and configuration
-func NewAnalysisContext(stringReg *registry.StringRegistry, classDumpReg *registry.ClassDumpRegistry,
+func NewAnalysisContext(stringReg *registry.StringRegistry, classReg *registry.ClassRegistry,
+	classDumpReg *registry.ClassDumpRegistry,
 	instanceReg *registry.InstanceRegistry, arrayReg *registry.ArrayRegistry,
 	rootReg *registry.GCRootRegistry, identifierSize uint32) *AnalysisContext {
 
 	return &AnalysisContext{
 		StringReg:    stringReg,
+		ClassReg:     classReg,
 		ClassDumpReg: classDumpReg,
 		InstanceReg:  instanceReg,
 		ArrayReg:     arrayReg,
@@ -38,6 +49,7 @@ func NewAnalysisContext(stringReg *registry.StringRegistry, classDumpReg *regist
 		Config: &AnalysisConfig{
 			IdentifierSize: identifierSize,
 		},
+		Out: os.Stdout,
 	}
 }
 
@@ -46,6 +58,9 @@ func (ctx *AnalysisContext) Validate() error {
 	if ctx.StringReg == nil {
 		return fmt.Errorf("string registry is required")
 	}
+	if ctx.ClassReg == nil {
+		return fmt.Errorf("class registry is required")
+	}
 	if ctx.ClassDumpReg == nil {
 		return fmt.Errorf("class dump registry is required")
 	}
@@ -71,6 +86,7 @@ func (ctx *AnalysisContext) Validate() error {
 func (ctx *AnalysisContext) Clone() *AnalysisContext {
 	return &AnalysisContext{
 		StringReg:    ctx.StringReg,
+		ClassReg:     ctx.ClassReg,
 		ClassDumpReg: ctx.ClassDumpReg,
 		InstanceReg:  ctx.InstanceReg,
 		ArrayReg:     ctx.ArrayReg,
@@ -78,5 +94,6 @@ func (ctx *AnalysisContext) Clone() *AnalysisContext {
 		Config: &AnalysisConfig{
 			IdentifierSize: ctx.Config.IdentifierSize,
 		},
+		spill: ctx.spill,
 	}
 }
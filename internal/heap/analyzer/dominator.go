@@ -0,0 +1,478 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* Dominator tree and retained sizes
+*
+* Object X dominates Y if every path from the GC roots to Y goes through X.
+* The retained size of X is the memory freed if X were collected: its own
+* shallow size plus that of every object it dominates.
+*
+* The tree is computed with Lengauer-Tarjan over the reference graph. All GC
+* roots hang off a synthetic super-root (ID 0), matching how the resolver
+* records root references. Objects not reachable from any root are garbage
+* waiting to be collected and are left out of the tree.
+*
+* If a dump has no GC roots at all (e.g. it was truncated before the root
+* sub-records), objects without referrers are used as roots instead so the
+* readable portion can still be analyzed.
+*
+* The per-node arrays come from the analysis's spill arena, so they live on
+* disk when the analysis is over its memory budget; that's why children and
+* the Lengauer-Tarjan buckets are flat arrays rather than a slice per node.
+ */
+
+// SuperRootID is the synthetic node every GC root is attached to
+const SuperRootID model.ID = 0
+
+type DominatorTree struct {
+	ids      []model.ID         // Node index (DFS order) -> object ID; index 0 is the super-root
+	index    map[model.ID]int32 // Object ID -> node index
+	idom     []int32            // Immediate dominator per node (-1 for the super-root)
+	shallow  []uint64
+	retained []uint64
+
+	// Nodes dominated by node i are childList[childStart[i]:childStart[i+1]],
+	// sorted by retained size (largest first)
+	childStart []int32
+	childList  []int32
+}
+
+// BuildDominatorTree computes immediate dominators and retained sizes for all reachable objects
+func BuildDominatorTree(ctx *AnalysisContext, graph *ObjectGraph) (*DominatorTree, error) {
+	if graph == nil || graph.References == nil {
+		return nil, fmt.Errorf("object graph is required")
+	}
+
+	tree := &DominatorTree{
+		index: make(map[model.ID]int32),
+	}
+
+	parent := tree.numberNodes(ctx, graph)
+	tree.computeIdoms(ctx, graph, parent)
+	releaseSlice(ctx.spill, parent)
+	tree.computeRetainedSizes(ctx)
+	tree.buildChildren(ctx)
+
+	return tree, nil
+}
+
+// NewDominatorTreeFromIdoms rebuilds a tree from cached immediate dominators (e.g. the heap index)
+func NewDominatorTreeFromIdoms(ctx *AnalysisContext, idoms map[model.ID]model.ID) (*DominatorTree, error) {
+	tree := &DominatorTree{
+		ids:   []model.ID{SuperRootID},
+		index: map[model.ID]int32{SuperRootID: 0},
+	}
+
+	for objectID := range idoms {
+		tree.index[objectID] = int32(len(tree.ids))
+		tree.ids = append(tree.ids, objectID)
+	}
+
+	tree.idom = make([]int32, len(tree.ids))
+	tree.idom[0] = -1
+	for i := 1; i < len(tree.ids); i++ {
+		dom, ok := tree.index[idoms[tree.ids[i]]]
+		if !ok {
+			return nil, fmt.Errorf("cached dominator 0x%x of 0x%x is not in the tree",
+				uint64(idoms[tree.ids[i]]), uint64(tree.ids[i]))
+		}
+		tree.idom[i] = dom
+	}
+
+	// Retained sizes need children before parents; order nodes by depth
+	tree.reorderByDepth()
+	tree.computeRetainedSizes(ctx)
+	tree.buildChildren(ctx)
+
+	return tree, nil
+}
+
+// validSuccessor reports whether a reference is followed: to an existing object, not the super-root
+func validSuccessor(graph *ObjectGraph, target model.ID) bool {
+	return target != SuperRootID && graph.ObjectExists[target]
+}
+
+// graphRoots returns the GC roots, or objects without referrers when the dump has no roots
+func graphRoots(graph *ObjectGraph, roots []model.ID) []model.ID {
+	if len(roots) > 0 {
+		return roots
+	}
+
+	var pseudoRoots []model.ID
+	for objectID := range graph.ObjectExists {
+		if !graph.References.HasReferrers(objectID) {
+			pseudoRoots = append(pseudoRoots, objectID)
+		}
+	}
+	sort.Slice(pseudoRoots, func(i, j int) bool { return pseudoRoots[i] < pseudoRoots[j] })
+	return pseudoRoots
+}
+
+// numberNodes assigns DFS order from the super-root and returns each node's DFS parent
+func (dt *DominatorTree) numberNodes(ctx *AnalysisContext, graph *ObjectGraph) []int32 {
+	type frame struct {
+		node int32
+		next int
+	}
+
+	var roots []model.ID
+	for _, target := range graph.References.GetReferences(SuperRootID) {
+		if validSuccessor(graph, target) {
+			roots = append(roots, target)
+		}
+	}
+	roots = graphRoots(graph, roots)
+
+	// Every reachable object, plus the super-root
+	capacity := len(graph.ObjectExists) + 1
+	dt.ids = spillSlice[model.ID](ctx.spill, 1, capacity)
+	dt.ids[0] = SuperRootID
+	dt.index[SuperRootID] = 0
+	parent := spillSlice[int32](ctx.spill, 1, capacity)
+	parent[0] = -1
+
+	stack := []frame{{node: 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		succ := roots
+		if top.node != 0 {
+			succ = graph.References.GetReferences(dt.ids[top.node])
+		}
+
+		if top.next >= len(succ) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		target := succ[top.next]
+		top.next++
+
+		if !validSuccessor(graph, target) {
+			continue
+		}
+		if _, seen := dt.index[target]; seen {
+			continue
+		}
+
+		node := int32(len(dt.ids))
+		dt.index[target] = node
+		dt.ids = append(dt.ids, target)
+		parent = append(parent, top.node)
+		stack = append(stack, frame{node: node})
+	}
+
+	return parent
+}
+
+// computeIdoms runs Lengauer-Tarjan (simple version with path compression)
+func (dt *DominatorTree) computeIdoms(ctx *AnalysisContext, graph *ObjectGraph, parent []int32) {
+	n := len(dt.ids)
+	semi := spillSlice[int32](ctx.spill, n, n)
+	label := spillSlice[int32](ctx.spill, n, n)
+	ancestor := spillSlice[int32](ctx.spill, n, n)
+	idom := spillSlice[int32](ctx.spill, n, n)
+
+	// Each bucket is a linked list: its first node, then each node's next
+	bucketHead := spillSlice[int32](ctx.spill, n, n)
+	bucketNext := spillSlice[int32](ctx.spill, n, n)
+	defer func() {
+		for _, temp := range [][]int32{semi, label, ancestor, bucketHead, bucketNext} {
+			releaseSlice(ctx.spill, temp)
+		}
+	}()
+
+	for i := range n {
+		semi[i] = int32(i)
+		label[i] = int32(i)
+		ancestor[i] = -1
+		bucketHead[i] = -1
+	}
+
+	var path []int32
+	eval := func(v int32) int32 {
+		if ancestor[v] == -1 {
+			return v
+		}
+
+		// Iterative path compression
+		path = path[:0]
+		for x := v; ancestor[ancestor[x]] != -1; x = ancestor[x] {
+			path = append(path, x)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			x := path[i]
+			a := ancestor[x]
+			if semi[label[a]] < semi[label[x]] {
+				label[x] = label[a]
+			}
+			ancestor[x] = ancestor[a]
+		}
+
+		return label[v]
+	}
+
+	for w := int32(n - 1); w >= 1; w-- {
+		for _, pred := range dt.predecessors(graph, dt.ids[w]) {
+			v, ok := dt.index[pred]
+			if !ok {
+				continue // Unreachable referrer
+			}
+			if u := eval(v); semi[u] < semi[w] {
+				semi[w] = semi[u]
+			}
+		}
+
+		bucketNext[w] = bucketHead[semi[w]]
+		bucketHead[semi[w]] = w
+		p := parent[w]
+		ancestor[w] = p
+
+		for v := bucketHead[p]; v != -1; v = bucketNext[v] {
+			if u := eval(v); semi[u] < semi[v] {
+				idom[v] = u
+			} else {
+				idom[v] = p
+			}
+		}
+		bucketHead[p] = -1
+	}
+
+	for w := 1; w < n; w++ {
+		if idom[w] != semi[w] {
+			idom[w] = idom[idom[w]]
+		}
+	}
+	if n > 0 {
+		idom[0] = -1
+	}
+
+	dt.idom = idom
+}
+
+// predecessors returns referrers of an object, including the super-root for GC roots
+func (dt *DominatorTree) predecessors(graph *ObjectGraph, objectID model.ID) []model.ID {
+	referrers := graph.References.GetReferrers(objectID)
+	if len(referrers) == 0 {
+		// Pseudo-root: only reachable from the super-root
+		return []model.ID{SuperRootID}
+	}
+	return referrers
+}
+
+// reorderByDepth renumbers nodes breadth-first so every dominator precedes the nodes it dominates
+func (dt *DominatorTree) reorderByDepth() {
+	n := len(dt.ids)
+	kids := make([][]int32, n)
+	for i := 1; i < n; i++ {
+		kids[dt.idom[i]] = append(kids[dt.idom[i]], int32(i))
+	}
+
+	order := make([]int32, 0, n)
+	order = append(order, 0)
+	for head := 0; head < len(order); head++ {
+		order = append(order, kids[order[head]]...)
+	}
+
+	newIndex := make([]int32, n)
+	for i := range newIndex {
+		newIndex[i] = -1
+	}
+	for newPos, oldPos := range order {
+		newIndex[oldPos] = int32(newPos)
+	}
+
+	// Nodes not connected to the super-root (corrupt cache) are dropped
+	ids := make([]model.ID, len(order))
+	idom := make([]int32, len(order))
+	index := make(map[model.ID]int32, len(order))
+	for newPos, oldPos := range order {
+		ids[newPos] = dt.ids[oldPos]
+		index[dt.ids[oldPos]] = int32(newPos)
+		if dt.idom[oldPos] < 0 {
+			idom[newPos] = -1
+		} else {
+			idom[newPos] = newIndex[dt.idom[oldPos]]
+		}
+	}
+
+	dt.ids = ids
+	dt.idom = idom
+	dt.index = index
+}
+
+// computeRetainedSizes accumulates shallow sizes up the tree (nodes are ordered parents-first)
+func (dt *DominatorTree) computeRetainedSizes(ctx *AnalysisContext) {
+	n := len(dt.ids)
+	dt.shallow = spillSlice[uint64](ctx.spill, n, n)
+	dt.retained = spillSlice[uint64](ctx.spill, n, n)
+
+	for i := 1; i < n; i++ {
+		dt.shallow[i] = ctx.ShallowSize(dt.ids[i])
+		dt.retained[i] = dt.shallow[i]
+	}
+
+	for i := n - 1; i >= 1; i-- {
+		dt.retained[dt.idom[i]] += dt.retained[i]
+	}
+}
+
+// buildChildren groups nodes under their immediate dominator: count each dominator's
+// nodes, then place them using each dominator's start as its cursor
+func (dt *DominatorTree) buildChildren(ctx *AnalysisContext) {
+	n := len(dt.ids)
+	dt.childStart = spillSlice[int32](ctx.spill, n+1, n+1)
+	dt.childList = spillSlice[int32](ctx.spill, max(n-1, 0), max(n-1, 0))
+
+	for i := 1; i < n; i++ {
+		dt.childStart[dt.idom[i]+1]++
+	}
+	for i := range n {
+		dt.childStart[i+1] += dt.childStart[i]
+	}
+	for i := 1; i < n; i++ {
+		dom := dt.idom[i]
+		dt.childList[dt.childStart[dom]] = int32(i)
+		dt.childStart[dom]++
+	}
+	// Placing left each start at the next dominator's
+	copy(dt.childStart[1:], dt.childStart[:n])
+	dt.childStart[0] = 0
+
+	for i := range n {
+		kids := dt.children(int32(i))
+		sort.Slice(kids, func(a, b int) bool {
+			return dt.retained[kids[a]] > dt.retained[kids[b]]
+		})
+	}
+}
+
+// children returns the nodes a node immediately dominates
+func (dt *DominatorTree) children(node int32) []int32 {
+	return dt.childList[dt.childStart[node]:dt.childStart[node+1]]
+}
+
+// Contains reports whether an object is reachable from the GC roots
+func (dt *DominatorTree) Contains(objectID model.ID) bool {
+	_, ok := dt.index[objectID]
+	return ok && objectID != SuperRootID
+}
+
+// ReachableCount returns the number of objects reachable from the GC roots
+func (dt *DominatorTree) ReachableCount() int {
+	return len(dt.ids) - 1
+}
+
+// TotalRetainedSize returns the size of the reachable heap
+func (dt *DominatorTree) TotalRetainedSize() uint64 {
+	if len(dt.retained) == 0 {
+		return 0
+	}
+	return dt.retained[0]
+}
+
+// RetainedSize returns the retained size of an object (0 if unreachable)
+func (dt *DominatorTree) RetainedSize(objectID model.ID) uint64 {
+	if i, ok := dt.index[objectID]; ok {
+		return dt.retained[i]
+	}
+	return 0
+}
+
+// ShallowSize returns the shallow size of a reachable object
+func (dt *DominatorTree) ShallowSize(objectID model.ID) uint64 {
+	if i, ok := dt.index[objectID]; ok {
+		return dt.shallow[i]
+	}
+	return 0
+}
+
+// ImmediateDominator returns the object that immediately dominates objectID
+// (SuperRootID for objects only held by GC roots)
+func (dt *DominatorTree) ImmediateDominator(objectID model.ID) (model.ID, bool) {
+	i, ok := dt.index[objectID]
+	if !ok || dt.idom[i] < 0 {
+		return 0, false
+	}
+	return dt.ids[dt.idom[i]], true
+}
+
+// Children returns the objects directly dominated by objectID, largest retained size first.
+// Use SuperRootID to get the top-level dominators.
+func (dt *DominatorTree) Children(objectID model.ID) []model.ID {
+	i, ok := dt.index[objectID]
+	if !ok {
+		return nil
+	}
+
+	kids := make([]model.ID, len(dt.children(i)))
+	for k, child := range dt.children(i) {
+		kids[k] = dt.ids[child]
+	}
+	return kids
+}
+
+// DominatorPath returns the chain of dominators from the top-level dominator down to objectID
+func (dt *DominatorTree) DominatorPath(objectID model.ID) []model.ID {
+	i, ok := dt.index[objectID]
+	if !ok {
+		return nil
+	}
+
+	var path []model.ID
+	for x := i; x > 0; x = dt.idom[x] {
+		path = append(path, dt.ids[x])
+	}
+
+	// Reverse so the path reads root -> object
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path
+}
+
+// Idoms exports the immediate dominators for caching in the heap index
+func (dt *DominatorTree) Idoms() map[model.ID]model.ID {
+	idoms := make(map[model.ID]model.ID, len(dt.ids))
+	for i := 1; i < len(dt.ids); i++ {
+		idoms[dt.ids[i]] = dt.ids[dt.idom[i]]
+	}
+	return idoms
+}
+
+// Walk visits every reachable object in dominator-tree pre-order; leave is called
+// after all of an object's dominated objects have been visited
+func (dt *DominatorTree) Walk(enter func(objectID model.ID, retained uint64), leave func(objectID model.ID)) {
+	type frame struct {
+		node int32
+		next int
+	}
+
+	stack := []frame{{node: 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		kids := dt.children(top.node)
+
+		if top.next >= len(kids) {
+			if top.node != 0 && leave != nil {
+				leave(dt.ids[top.node])
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		child := kids[top.next]
+		top.next++
+
+		if enter != nil {
+			enter(dt.ids[child], dt.retained[child])
+		}
+		stack = append(stack, frame{node: child})
+	}
+}
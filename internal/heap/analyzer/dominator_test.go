@@ -0,0 +1,213 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
+	"github.com/mabhi256/jdiag/internal/heap/testdump"
+)
+
+/*
+ * Each case builds a small graph from three classes and names the objects
+ * it wants checked. With 8-byte identifiers an object header is 16 bytes, so:
+ *
+ * 	Leaf		no fields				16 bytes
+ * 	Node		next					24 bytes
+ * 	Pair		left, right				32 bytes
+ * 	Object[n]	16 + 4 + 8n, aligned to 8
+ *
+ * Every class is a sticky class root, so the class objects hang off the
+ * super root on their own and never count towards an instance's retained size.
+ */
+
+const testIDSize = 8
+
+type testClasses struct {
+	leaf, node, pair, objectArray *testdump.Class
+}
+
+func TestDominators(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(w *testdump.Writer, c testClasses) map[string]model.ID
+
+		retained map[string]uint64
+		idom     map[string]string   // "" is the super root: held only by GC roots
+		paths    map[string][]string // Shortest path from a GC root, root first; nil for none
+	}{
+		{
+			name: "chain",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				leaf := w.Instance(c.node, model.ID(0))
+				middle := w.Instance(c.node, leaf)
+				head := w.Instance(c.node, middle)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, head)
+				return map[string]model.ID{"head": head, "middle": middle, "leaf": leaf}
+			},
+			retained: map[string]uint64{"head": 72, "middle": 48, "leaf": 24},
+			idom:     map[string]string{"head": "", "middle": "head", "leaf": "middle"},
+			paths:    map[string][]string{"leaf": {"head", "middle", "leaf"}},
+		},
+		{
+			name: "diamond",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				shared := w.Instance(c.leaf)
+				left := w.Instance(c.node, shared)
+				right := w.Instance(c.node, shared)
+				top := w.Instance(c.pair, left, right)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, top)
+				return map[string]model.ID{"top": top, "left": left, "right": right, "shared": shared}
+			},
+			retained: map[string]uint64{"top": 96, "left": 24, "right": 24, "shared": 16},
+			idom:     map[string]string{"top": "", "left": "top", "right": "top", "shared": "top"},
+			paths:    map[string][]string{"left": {"top", "left"}},
+		},
+		{
+			name: "shared between roots",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				shared := w.Instance(c.leaf)
+				first := w.Instance(c.node, shared)
+				second := w.Instance(c.node, shared)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, first)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, second)
+				return map[string]model.ID{"first": first, "second": second, "shared": shared}
+			},
+			retained: map[string]uint64{"first": 24, "second": 24, "shared": 16},
+			idom:     map[string]string{"first": "", "second": "", "shared": ""},
+			paths:    map[string][]string{"first": {"first"}},
+		},
+		{
+			name: "cycle",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				a, b := w.NewID(), w.NewID()
+				w.InstanceAt(a, c.node, b)
+				w.InstanceAt(b, c.node, a)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, a)
+				return map[string]model.ID{"a": a, "b": b}
+			},
+			retained: map[string]uint64{"a": 48, "b": 24},
+			idom:     map[string]string{"a": "", "b": "a"},
+			paths:    map[string][]string{"b": {"a", "b"}},
+		},
+		{
+			name: "array elements",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				x, y := w.Instance(c.leaf), w.Instance(c.leaf)
+				array := w.ObjectArray(c.objectArray, x, model.ID(0), y)
+				holder := w.Instance(c.node, array)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, holder)
+				return map[string]model.ID{"holder": holder, "array": array, "x": x, "y": y}
+			},
+			retained: map[string]uint64{"holder": 104, "array": 80, "x": 16, "y": 16},
+			idom:     map[string]string{"holder": "", "array": "holder", "x": "array", "y": "array"},
+			paths:    map[string][]string{"y": {"holder", "array", "y"}},
+		},
+		{
+			name: "unreachable",
+			build: func(w *testdump.Writer, c testClasses) map[string]model.ID {
+				garbage := w.Instance(c.leaf)
+				orphan := w.Instance(c.node, garbage)
+				live := w.Instance(c.leaf)
+				w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, live)
+				return map[string]model.ID{"orphan": orphan, "garbage": garbage, "live": live}
+			},
+			retained: map[string]uint64{"orphan": 0, "garbage": 0, "live": 16},
+			idom:     map[string]string{"live": ""},
+			paths:    map[string][]string{"orphan": nil, "garbage": nil, "live": {"live"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var objects map[string]model.ID
+			analyzer := analyzeTestDump(t, func(w *testdump.Writer, c testClasses) {
+				objects = test.build(w, c)
+			})
+			tree := analyzer.GetDominatorTree()
+			names := make(map[model.ID]string, len(objects))
+			for name, id := range objects {
+				names[id] = name
+			}
+
+			for name, expected := range test.retained {
+				if retained := tree.RetainedSize(objects[name]); retained != expected {
+					t.Errorf("retained size of %s: got %d, want %d", name, retained, expected)
+				}
+			}
+
+			for name, expected := range test.idom {
+				idom, ok := tree.ImmediateDominator(objects[name])
+				if !ok {
+					t.Errorf("%s has no immediate dominator", name)
+					continue
+				}
+				if got := nameOf(names, idom); got != expected {
+					t.Errorf("immediate dominator of %s: got %q, want %q", name, got, expected)
+				}
+			}
+
+			for name, expected := range test.paths {
+				var path []string
+				for _, id := range analyzer.ShortestPathToRoot(objects[name]) {
+					path = append(path, nameOf(names, id))
+				}
+				if !slices.Equal(path, expected) {
+					t.Errorf("path to %s: got %v, want %v", name, path, expected)
+				}
+			}
+		})
+	}
+}
+
+// nameOf names an object of the test graph, "" for the super root
+func nameOf(names map[model.ID]string, id model.ID) string {
+	if id == SuperRootID {
+		return ""
+	}
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return "?"
+}
+
+// analyzeTestDump writes the dump build makes, parses it and runs the full analysis
+func analyzeTestDump(t *testing.T, build func(w *testdump.Writer, c testClasses)) *Analyzer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.hprof")
+	err := testdump.WriteFile(path, testIDSize, func(w *testdump.Writer) {
+		object := w.Class("java.lang.Object", nil)
+		c := testClasses{
+			leaf:        w.Class("Leaf", object),
+			node:        w.Class("Node", object, testdump.Field{Name: "next", Type: model.HPROF_NORMAL_OBJECT}),
+			pair:        w.Class("Pair", object, testdump.Field{Name: "left", Type: model.HPROF_NORMAL_OBJECT}, testdump.Field{Name: "right", Type: model.HPROF_NORMAL_OBJECT}),
+			objectArray: w.Class("[Ljava.lang.Object;", object),
+		}
+		for _, class := range []*testdump.Class{object, c.leaf, c.node, c.pair, c.objectArray} {
+			w.Root(model.HPROF_GC_ROOT_STICKY_CLASS, class.ID)
+		}
+		build(w, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := parser.NewParser(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	if err := p.ParseHprof(); err != nil {
+		t.Fatal(err)
+	}
+
+	analyzer := NewAnalyzer(p.GetStringRegistry(), p.GetClassRegistry(), p.GetClassDumpRegistry(),
+		p.GetObjectRegistry(), p.GetArrayRegistry(), p.GetGCRootRegistry(), p.GetHeader().IdentifierSize)
+	t.Cleanup(func() { analyzer.Close() })
+	if err := analyzer.PerformAnalysis(); err != nil {
+		t.Fatal(err)
+	}
+	return analyzer
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"sync"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 )
@@ -15,6 +16,10 @@ type FieldExtractor struct {
 
 	// Initialization state
 	initialized bool
+
+	// Resolved field layouts by class object ID
+	layoutMu sync.RWMutex
+	layouts  map[model.ID][]FieldInfo
 }
 
 // NewFieldExtractor creates a new field extractor using the analysis context
@@ -22,6 +27,7 @@ func NewFieldExtractor(ctx *AnalysisContext) *FieldExtractor {
 	extractor := &FieldExtractor{
 		ctx:         ctx,
 		initialized: ctx != nil,
+		layouts:     make(map[model.ID][]FieldInfo),
 	}
 
 	return extractor
@@ -39,17 +45,19 @@ func (fe *FieldExtractor) Initialize(ctx *AnalysisContext) error {
 
 	fe.ctx = ctx
 	fe.initialized = true
+	fe.layouts = make(map[model.ID][]FieldInfo)
 
 	return nil
 }
 
 // FieldInfo represents a field with its metadata and layout information
 type FieldInfo struct {
-	Name        string
-	Type        model.HProfTagFieldType
-	IsReference bool
-	Size        int
-	Offset      int
+	Name           string
+	Type           model.HProfTagFieldType
+	IsReference    bool
+	Size           int
+	Offset         int
+	DeclaringClass model.ID // Class (or superclass) that declares the field
 }
 
 // ExtractInstanceFieldReferences extracts object references from instance field data
@@ -98,8 +106,58 @@ func (fe *FieldExtractor) ExtractInstanceFieldValues(instance *model.GCInstanceD
 	return fieldValues, nil
 }
 
-// getAllInstanceFields gets all instance fields including inherited ones using proper inheritance order
+// FieldValue is a decoded instance field in declaration order
+type FieldValue struct {
+	FieldInfo
+	Value interface{} // model.ID for references
+}
+
+// ExtractInstanceFields decodes all field values in layout order, keeping
+// same-named fields from superclasses distinct (unlike ExtractInstanceFieldValues)
+func (fe *FieldExtractor) ExtractInstanceFields(instance *model.GCInstanceDump,
+	classDump *model.GCClassDump) ([]FieldValue, error) {
+
+	if !fe.initialized {
+		return nil, fmt.Errorf("field extractor not initialized")
+	}
+
+	allFields, err := fe.getAllInstanceFields(classDump)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance fields: %w", err)
+	}
+
+	var values []FieldValue
+	for _, field := range allFields {
+		if field.Offset+field.Size > len(instance.InstanceData) {
+			break
+		}
+
+		value, err := fe.parseFieldValue(instance.InstanceData[field.Offset:field.Offset+field.Size], field.Type)
+		if err != nil {
+			value = fmt.Sprintf("parse_error: %v", err)
+		}
+		values = append(values, FieldValue{FieldInfo: field, Value: value})
+	}
+
+	return values, nil
+}
+
+/*
+ * getAllInstanceFields resolves the layout of an instance's field data.
+ *
+ * HotSpot writes INSTANCE_DUMP field values class by class starting with the
+ * instance's own class and walking up the superclass chain, so a subclass's
+ * fields come before the ones it inherits. Layouts are cached per class since
+ * every instance of a class shares one.
+ */
 func (fe *FieldExtractor) getAllInstanceFields(classDump *model.GCClassDump) ([]FieldInfo, error) {
+	fe.layoutMu.RLock()
+	cached, ok := fe.layouts[classDump.ClassObjectID]
+	fe.layoutMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
 	var allFields []FieldInfo
 
 	// Build inheritance chain from current class to root
@@ -108,16 +166,18 @@ func (fe *FieldExtractor) getAllInstanceFields(classDump *model.GCClassDump) ([]
 		return nil, fmt.Errorf("failed to build inheritance chain: %w", err)
 	}
 
-	// Add fields in correct order: ROOT CLASS FIRST, then subclasses
-	// Reverse the inheritance chain so root class comes first
-	for i := len(inheritanceChain) - 1; i >= 0; i-- {
-		classFields := fe.getClassInstanceFields(inheritanceChain[i])
-		allFields = append(allFields, classFields...)
+	// Own fields first, then each superclass in turn
+	for _, class := range inheritanceChain {
+		allFields = append(allFields, fe.getClassInstanceFields(class)...)
 	}
 
 	// Calculate field offsets based on field order
 	fe.calculateFieldOffsets(allFields)
 
+	fe.layoutMu.Lock()
+	fe.layouts[classDump.ClassObjectID] = allFields
+	fe.layoutMu.Unlock()
+
 	return allFields, nil
 }
 
@@ -155,10 +215,11 @@ func (fe *FieldExtractor) getClassInstanceFields(classDump *model.GCClassDump) [
 		fieldType := field.Type
 
 		fieldInfo := FieldInfo{
-			Name:        fieldName,
-			Type:        fieldType,
-			IsReference: fe.isReferenceType(fieldType),
-			Size:        fieldType.Size(fe.ctx.Config.IdentifierSize),
+			Name:           fieldName,
+			Type:           fieldType,
+			IsReference:    fe.isReferenceType(fieldType),
+			Size:           fieldType.Size(fe.ctx.Config.IdentifierSize),
+			DeclaringClass: classDump.ClassObjectID,
 		}
 
 		fields = append(fields, fieldInfo)
@@ -217,6 +278,11 @@ func (fe *FieldExtractor) parseAllFieldValues(instanceData []byte, fields []Fiel
 			break
 		}
 
+		// Fields hidden by a subclass field of the same name come later; keep the subclass one
+		if _, exists := fieldValues[field.Name]; exists {
+			continue
+		}
+
 		fieldData := instanceData[field.Offset : field.Offset+field.Size]
 		value, err := fe.parseFieldValue(fieldData, field.Type)
 		if err != nil {
@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+const (
+	fieldStringPreview = 120 // Characters of a String shown inline by FormatField
+
+	// java.lang.String.coder values (JDK 9+ compact strings)
+	stringCoderLatin1 = 0
+	stringCoderUTF16  = 1
+)
+
+// InstanceFields decodes every field of an instance, own fields first
+func (fe *FieldExtractor) InstanceFields(objectID model.ID) ([]FieldValue, error) {
+	if !fe.initialized {
+		return nil, fmt.Errorf("field extractor not initialized")
+	}
+
+	instance, ok := fe.ctx.InstanceReg.GetInstance(objectID)
+	if !ok {
+		return nil, fmt.Errorf("object 0x%x is not an instance", uint64(objectID))
+	}
+
+	classDump, ok := fe.ctx.ClassDumpReg.GetClassDump(instance.ClassObjectID)
+	if !ok {
+		return nil, fmt.Errorf("class dump 0x%x not found", uint64(instance.ClassObjectID))
+	}
+
+	return fe.ExtractInstanceFields(instance, classDump)
+}
+
+// ReadField decodes a single field by name. When a subclass hides a superclass
+// field of the same name, the subclass field is returned.
+func (fe *FieldExtractor) ReadField(objectID model.ID, name string) (FieldValue, bool) {
+	fields, err := fe.InstanceFields(objectID)
+	if err != nil {
+		return FieldValue{}, false
+	}
+
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FieldValue{}, false
+}
+
+// ReadReference returns the object a reference field points to (0 for null or missing fields)
+func (fe *FieldExtractor) ReadReference(objectID model.ID, name string) model.ID {
+	field, ok := fe.ReadField(objectID, name)
+	if !ok || !field.IsReference {
+		return 0
+	}
+
+	id, _ := field.Value.(model.ID)
+	return id
+}
+
+// ReadInt returns an int-like field (byte, short, char, int, long) as int64
+func (fe *FieldExtractor) ReadInt(objectID model.ID, name string) (int64, bool) {
+	field, ok := fe.ReadField(objectID, name)
+	if !ok {
+		return 0, false
+	}
+
+	switch value := field.Value.(type) {
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	}
+	return 0, false
+}
+
+/*
+ * ReadString decodes a java.lang.String instance.
+ *
+ * JDK 8 strings keep their text in a char[] `value`. JDK 9+ compact strings
+ * use a byte[] `value` plus a `coder` byte: 0 is Latin-1, 1 is UTF-16 in the
+ * JVM's native byte order (little-endian on every platform HotSpot dumps are
+ * normally taken on). A structure-only parse kept no array elements, so
+ * nothing is decoded.
+ */
+func (fe *FieldExtractor) ReadString(objectID model.ID) (string, bool) {
+	instance, ok := fe.ctx.InstanceReg.GetInstance(objectID)
+	if !ok || fe.ctx.ClassName(instance.ClassObjectID) != "java.lang.String" {
+		return "", false
+	}
+
+	valueID := fe.ReadReference(objectID, "value")
+	if valueID == 0 {
+		return "", false
+	}
+
+	array, ok := fe.ctx.ArrayReg.GetPrimitiveArray(valueID)
+	if !ok || fe.ctx.ArrayReg.ContentsSkipped() {
+		return "", false
+	}
+
+	switch array.Type {
+	case model.HPROF_CHAR:
+		return fe.ctx.ArrayReg.GetCharArray(valueID)
+
+	case model.HPROF_BYTE:
+		coder, _ := fe.ReadInt(objectID, "coder")
+		if coder == stringCoderUTF16 {
+			return decodeUTF16LE(array.Elements), true
+		}
+		return fe.ctx.ArrayReg.GetByteArray(valueID)
+	}
+
+	return "", false
+}
+
+// FormatField renders a decoded field for display: primitives as-is, chars
+// quoted, references as the target object with String contents inlined
+func (fe *FieldExtractor) FormatField(field FieldValue) string {
+	switch v := field.Value.(type) {
+	case model.ID:
+		if v == 0 {
+			return "null"
+		}
+		object, _ := fe.ctx.DescribeObject(v)
+		if text, ok := fe.ReadString(v); ok {
+			return fmt.Sprintf("%s %s", object.DisplayName(), quotePreview(text))
+		}
+		return object.DisplayName()
+
+	case int32:
+		if field.Type == model.HPROF_CHAR {
+			return strconv.QuoteRune(rune(v))
+		}
+		return strconv.FormatInt(int64(v), 10)
+
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// FieldReferencing names the field or array slot of holder that points at target,
+// e.g. "table" or "[3]". It returns "" if holder doesn't reference target directly.
+func (fe *FieldExtractor) FieldReferencing(holder, target model.ID) string {
+	if fields, err := fe.InstanceFields(holder); err == nil {
+		for _, field := range fields {
+			if id, ok := field.Value.(model.ID); ok && field.IsReference && id == target {
+				return field.Name
+			}
+		}
+		return ""
+	}
+
+	if array, ok := fe.ctx.ArrayReg.GetObjectArray(holder); ok {
+		for i, id := range array.Elements {
+			if id == target {
+				return fmt.Sprintf("[%d]", i)
+			}
+		}
+	}
+	return ""
+}
+
+func decodeUTF16LE(data []byte) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = uint16(data[i*2]) | uint16(data[i*2+1])<<8
+	}
+	return string(utf16.Decode(units))
+}
+
+// quotePreview quotes a string, shortening it to fieldStringPreview characters
+func quotePreview(text string) string {
+	runes := []rune(text)
+	if len(runes) > fieldStringPreview {
+		return strconv.Quote(string(runes[:fieldStringPreview])) + "…"
+	}
+	return strconv.Quote(text)
+}
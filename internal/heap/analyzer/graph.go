@@ -50,7 +50,7 @@ func (gb *GraphBuilder) BuildObjectGraph(validation *ValidationResult, refMap *R
 		return nil, fmt.Errorf("reference map is required")
 	}
 
-	fmt.Println("📊 Phase 11.3: Building navigable object graph...")
+	fmt.Fprintln(gb.ctx.Out, "📊 Phase 11.3: Building navigable object graph...")
 
 	// Create and initialize the graph
 	graph := NewObjectGraph()
@@ -80,7 +80,7 @@ func (gb *GraphBuilder) buildGraphStages(graph *ObjectGraph) error {
 	}
 
 	for _, stage := range buildStages {
-		fmt.Printf("  %s...\n", stage.name)
+		fmt.Fprintf(gb.ctx.Out, "  %s...\n", stage.name)
 		if err := stage.fn(graph); err != nil {
 			return fmt.Errorf("failed during %s: %w", stage.name, err)
 		}
@@ -159,7 +159,7 @@ func (gb *GraphBuilder) calculateStatistics(graph *ObjectGraph) error {
 	stats.TotalArrays = len(graph.ArrayExists)
 
 	// Count references
-	for _, refs := range graph.References.ForwardRefs {
+	for _, refs := range graph.References.AllReferences() {
 		stats.TotalRefs += len(refs)
 	}
 
@@ -209,11 +209,16 @@ func (gb *GraphBuilder) checkReferenceConsistency(graph *ObjectGraph) error {
 	// Verify forward/backward reference symmetry
 	inconsistencies := 0
 
-	for sourceID, targets := range graph.References.ForwardRefs {
+	for sourceID, targets := range graph.References.AllReferences() {
 		for _, targetID := range targets {
+			// References to missing objects are reported by the existence check
+			if graph.References.IsSpilled() && !graph.ObjectExists[targetID] {
+				continue
+			}
+
 			// Check if backward reference exists
 			found := false
-			for _, backRef := range graph.References.BackwardRefs[targetID] {
+			for _, backRef := range graph.References.GetReferrers(targetID) {
 				if backRef == sourceID {
 					found = true
 					break
@@ -222,7 +227,7 @@ func (gb *GraphBuilder) checkReferenceConsistency(graph *ObjectGraph) error {
 			if !found {
 				inconsistencies++
 				if inconsistencies <= 5 { // Limit error reporting
-					fmt.Printf("    Warning: Missing backward reference %x <- %x\n",
+					fmt.Fprintf(gb.ctx.Out, "    Warning: Missing backward reference %x <- %x\n",
 						uint64(targetID), uint64(sourceID))
 				}
 			}
@@ -230,7 +235,7 @@ func (gb *GraphBuilder) checkReferenceConsistency(graph *ObjectGraph) error {
 	}
 
 	if inconsistencies > 0 {
-		fmt.Printf("    Found %d reference inconsistencies\n", inconsistencies)
+		fmt.Fprintf(gb.ctx.Out, "    Found %d reference inconsistencies\n", inconsistencies)
 	}
 
 	return nil
@@ -240,12 +245,12 @@ func (gb *GraphBuilder) checkReferenceConsistency(graph *ObjectGraph) error {
 func (gb *GraphBuilder) checkObjectExistenceConsistency(graph *ObjectGraph) error {
 	missingObjects := 0
 
-	for sourceID, targets := range graph.References.ForwardRefs {
+	for sourceID, targets := range graph.References.AllReferences() {
 		for _, targetID := range targets {
 			if targetID != 0 && !graph.ObjectExists[targetID] {
 				missingObjects++
 				if missingObjects <= 5 { // Limit error reporting
-					fmt.Printf("    Warning: Reference to non-existent object: %x -> %x\n",
+					fmt.Fprintf(gb.ctx.Out, "    Warning: Reference to non-existent object: %x -> %x\n",
 						uint64(sourceID), uint64(targetID))
 				}
 			}
@@ -253,7 +258,7 @@ func (gb *GraphBuilder) checkObjectExistenceConsistency(graph *ObjectGraph) erro
 	}
 
 	if missingObjects > 0 {
-		fmt.Printf("    Found %d references to missing objects\n", missingObjects)
+		fmt.Fprintf(gb.ctx.Out, "    Found %d references to missing objects\n", missingObjects)
 	}
 
 	return nil
@@ -283,7 +288,7 @@ func (gb *GraphBuilder) checkStatisticalConsistency(graph *ObjectGraph) error {
 	totalInstances := graph.TotalObjects - totalCounted
 
 	if totalInstances < 0 {
-		fmt.Printf("    Warning: Inconsistent object counts - instances: %d, classes: %d, arrays: %d\n",
+		fmt.Fprintf(gb.ctx.Out, "    Warning: Inconsistent object counts - instances: %d, classes: %d, arrays: %d\n",
 			totalInstances, graph.TotalClasses, graph.TotalArrays)
 	}
 
@@ -292,24 +297,24 @@ func (gb *GraphBuilder) checkStatisticalConsistency(graph *ObjectGraph) error {
 
 // printGraphSummary prints a comprehensive summary of the constructed graph
 func (gb *GraphBuilder) printGraphSummary(graph *ObjectGraph) {
-	fmt.Printf("  Object graph construction completed:\n")
-	fmt.Printf("    Total objects: %d\n", graph.TotalObjects)
-	fmt.Printf("    Classes: %d\n", graph.TotalClasses)
-	fmt.Printf("    Arrays: %d\n", graph.TotalArrays)
-	fmt.Printf("    Instances: %d\n", graph.TotalObjects-graph.TotalClasses-graph.TotalArrays)
-	fmt.Printf("    Total references: %d\n", graph.TotalRefs)
+	fmt.Fprintf(gb.ctx.Out, "  Object graph construction completed:\n")
+	fmt.Fprintf(gb.ctx.Out, "    Total objects: %d\n", graph.TotalObjects)
+	fmt.Fprintf(gb.ctx.Out, "    Classes: %d\n", graph.TotalClasses)
+	fmt.Fprintf(gb.ctx.Out, "    Arrays: %d\n", graph.TotalArrays)
+	fmt.Fprintf(gb.ctx.Out, "    Instances: %d\n", graph.TotalObjects-graph.TotalClasses-graph.TotalArrays)
+	fmt.Fprintf(gb.ctx.Out, "    Total references: %d\n", graph.TotalRefs)
 
 	// Calculate and display reference density
 	if graph.TotalObjects > 0 {
 		density := float64(graph.TotalRefs) / float64(graph.TotalObjects)
-		fmt.Printf("    Reference density: %.2f refs/object\n", density)
+		fmt.Fprintf(gb.ctx.Out, "    Reference density: %.2f refs/object\n", density)
 	}
 
 	// Display validation summary if available
 	if graph.Validation != nil && graph.Validation.TotalRefs > 0 {
 		integrity := float64(graph.Validation.ValidRefs) / float64(graph.Validation.TotalRefs) * 100
-		fmt.Printf("    Reference integrity: %.2f%%\n", integrity)
+		fmt.Fprintf(gb.ctx.Out, "    Reference integrity: %.2f%%\n", integrity)
 	}
 
-	fmt.Printf("    ✅ Object graph ready for analysis\n")
+	fmt.Fprintf(gb.ctx.Out, "    ✅ Object graph ready for analysis\n")
 }
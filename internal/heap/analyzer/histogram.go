@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// ClassHistogramEntry aggregates all objects of one class
+type ClassHistogramEntry struct {
+	ClassName     string
+	ClassID       model.ID // 0 for primitive arrays and java.lang.Class
+	InstanceCount int
+	ShallowSize   uint64
+	// Memory freed if every instance of the class were collected. Instances
+	// dominated by another instance of the same class are only counted once.
+	RetainedSize uint64
+}
+
+type HistogramSortBy int
+
+const (
+	SortByRetained HistogramSortBy = iota
+	SortByShallow
+	SortByCount
+	SortByName
+)
+
+func (s HistogramSortBy) String() string {
+	switch s {
+	case SortByRetained:
+		return "Retained"
+	case SortByShallow:
+		return "Shallow"
+	case SortByCount:
+		return "Count"
+	case SortByName:
+		return "Name"
+	default:
+		return "Unknown"
+	}
+}
+
+type ClassHistogram struct {
+	Entries          []*ClassHistogramEntry
+	TotalObjects     int
+	TotalShallowSize uint64
+}
+
+// BuildClassHistogram groups every object in the dump by class.
+// Retained sizes are only filled in when a dominator tree is available.
+func BuildClassHistogram(ctx *AnalysisContext, tree *DominatorTree) *ClassHistogram {
+	entries := make(map[string]*ClassHistogramEntry)
+	classOf := make(map[model.ID]string)
+
+	add := func(object HeapObject) {
+		entry, ok := entries[object.ClassName]
+		if !ok {
+			// A class object's ClassID is itself; java.lang.Class has no ID of its own to use
+			classID := object.ClassID
+			if object.Kind == ClassObject {
+				classID = 0
+			}
+			entry = &ClassHistogramEntry{ClassName: object.ClassName, ClassID: classID}
+			entries[object.ClassName] = entry
+		}
+		entry.InstanceCount++
+		entry.ShallowSize += object.ShallowSize
+		classOf[object.ID] = object.ClassName
+	}
+
+	for objectID := range ctx.InstanceReg.GetAllInstances() {
+		object, _ := ctx.DescribeObject(objectID)
+		add(object)
+	}
+	for objectID := range ctx.ArrayReg.GetAllObjectArrays() {
+		object, _ := ctx.DescribeObject(objectID)
+		add(object)
+	}
+	for objectID := range ctx.ArrayReg.GetAllPrimitiveArrays() {
+		object, _ := ctx.DescribeObject(objectID)
+		add(object)
+	}
+	for objectID := range ctx.ClassDumpReg.GetAllClassDumps() {
+		object, _ := ctx.DescribeObject(objectID)
+		add(object)
+	}
+
+	if tree != nil {
+		// Count an object's retained size only if no dominator above it has the same class
+		active := make(map[string]int)
+		tree.Walk(func(objectID model.ID, retained uint64) {
+			className := classOf[objectID]
+			if active[className] == 0 {
+				if entry, ok := entries[className]; ok {
+					entry.RetainedSize += retained
+				}
+			}
+			active[className]++
+		}, func(objectID model.ID) {
+			active[classOf[objectID]]--
+		})
+	}
+
+	histogram := &ClassHistogram{}
+	for _, entry := range entries {
+		histogram.Entries = append(histogram.Entries, entry)
+		histogram.TotalObjects += entry.InstanceCount
+		histogram.TotalShallowSize += entry.ShallowSize
+	}
+	histogram.Sort(SortByRetained)
+
+	return histogram
+}
+
+// Sort orders the histogram entries in place (largest first for sizes and counts)
+func (h *ClassHistogram) Sort(by HistogramSortBy) {
+	sort.SliceStable(h.Entries, func(i, j int) bool {
+		a, b := h.Entries[i], h.Entries[j]
+		switch by {
+		case SortByShallow:
+			if a.ShallowSize != b.ShallowSize {
+				return a.ShallowSize > b.ShallowSize
+			}
+		case SortByCount:
+			if a.InstanceCount != b.InstanceCount {
+				return a.InstanceCount > b.InstanceCount
+			}
+		case SortByName:
+			return a.ClassName < b.ClassName
+		default:
+			if a.RetainedSize != b.RetainedSize {
+				return a.RetainedSize > b.RetainedSize
+			}
+		}
+		return a.ClassName < b.ClassName
+	})
+}
+
+// Filter returns entries whose class name contains the search term (case-insensitive)
+func (h *ClassHistogram) Filter(term string) []*ClassHistogramEntry {
+	if term == "" {
+		return h.Entries
+	}
+
+	term = strings.ToLower(term)
+	var filtered []*ClassHistogramEntry
+	for _, entry := range h.Entries {
+		if strings.Contains(strings.ToLower(entry.ClassName), term) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Get returns the entry for a class name
+func (h *ClassHistogram) Get(className string) (*ClassHistogramEntry, bool) {
+	for _, entry := range h.Entries {
+		if entry.ClassName == className {
+			return entry, true
+		}
+	}
+	return nil, false
+}
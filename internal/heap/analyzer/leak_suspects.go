@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Leak suspect thresholds (fractions of the reachable heap)
+const (
+	LeakSuspectThreshold   = 0.10 // Single object or class retaining at least this much is suspicious
+	AccumulationPointRatio = 0.80 // Keep descending while one child holds this much of its parent
+	MaxLeakSuspects        = 10
+)
+
+type LeakSuspectKind int
+
+const (
+	SingleObjectSuspect LeakSuspectKind = iota
+	ClassGroupSuspect
+)
+
+func (k LeakSuspectKind) String() string {
+	switch k {
+	case SingleObjectSuspect:
+		return "object"
+	case ClassGroupSuspect:
+		return "class"
+	default:
+		return "unknown"
+	}
+}
+
+type LeakSuspect struct {
+	Kind          LeakSuspectKind
+	ObjectID      model.ID // Suspect object, or the biggest instance for class suspects
+	ClassName     string
+	InstanceCount int // Number of instances for class suspects
+	RetainedSize  uint64
+	Percentage    float64 // Share of the reachable heap
+
+	// Where memory actually piles up below the suspect (often a collection's backing array)
+	AccumulationPoint     model.ID
+	AccumulationClassName string
+	AccumulationRetained  uint64
+	AccumulationField     string // Field of the dominating object that holds the accumulation point
+
+	DominatorPath []model.ID // Top-level dominator down to the suspect
+	Description   string
+
+	// Where instances of ClassName were allocated; nil when the dump has no allocation traces
+	AllocationSites []*AllocationSite
+}
+
+// FindLeakSuspects reports objects and classes that retain a large share of the reachable heap
+func FindLeakSuspects(ctx *AnalysisContext, tree *DominatorTree, histogram *ClassHistogram) []*LeakSuspect {
+	total := tree.TotalRetainedSize()
+	if total == 0 {
+		return nil
+	}
+
+	threshold := uint64(float64(total) * LeakSuspectThreshold)
+	var suspects []*LeakSuspect
+	suspectClasses := make(map[string]bool)
+
+	// Single big objects: walk down from the top-level dominators
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		if retained < threshold {
+			return
+		}
+
+		// Skip objects whose dominator is already a suspect on its own
+		if dom, ok := tree.ImmediateDominator(objectID); ok && dom != SuperRootID &&
+			tree.RetainedSize(dom) >= threshold {
+			return
+		}
+
+		object, _ := ctx.DescribeObject(objectID)
+		suspect := &LeakSuspect{
+			Kind:          SingleObjectSuspect,
+			ObjectID:      objectID,
+			ClassName:     object.ClassName,
+			InstanceCount: 1,
+			RetainedSize:  retained,
+			Percentage:    float64(retained) / float64(total) * 100,
+			DominatorPath: tree.DominatorPath(objectID),
+		}
+		findAccumulationPoint(ctx, tree, suspect)
+		suspect.Description = describeObjectSuspect(suspect)
+
+		suspects = append(suspects, suspect)
+		suspectClasses[object.ClassName] = true
+	}, nil)
+
+	// Many small objects of the same class that together retain a lot
+	if histogram != nil {
+		for _, entry := range histogram.Entries {
+			if entry.RetainedSize < threshold || entry.InstanceCount < 2 || suspectClasses[entry.ClassName] {
+				continue
+			}
+
+			suspect := &LeakSuspect{
+				Kind:          ClassGroupSuspect,
+				ClassName:     entry.ClassName,
+				InstanceCount: entry.InstanceCount,
+				RetainedSize:  entry.RetainedSize,
+				Percentage:    float64(entry.RetainedSize) / float64(total) * 100,
+			}
+			suspect.ObjectID = BiggestInstanceOf(ctx, tree, entry.ClassName)
+			if suspect.ObjectID != 0 {
+				suspect.DominatorPath = tree.DominatorPath(suspect.ObjectID)
+			}
+			suspect.Description = describeClassSuspect(suspect)
+
+			suspects = append(suspects, suspect)
+		}
+	}
+
+	sort.SliceStable(suspects, func(i, j int) bool {
+		return suspects[i].RetainedSize > suspects[j].RetainedSize
+	})
+	if len(suspects) > MaxLeakSuspects {
+		suspects = suspects[:MaxLeakSuspects]
+	}
+
+	return suspects
+}
+
+// findAccumulationPoint descends from the suspect while a single child retains most of the memory
+func findAccumulationPoint(ctx *AnalysisContext, tree *DominatorTree, suspect *LeakSuspect) {
+	current := suspect.ObjectID
+	for {
+		children := tree.Children(current)
+		if len(children) == 0 {
+			break
+		}
+
+		biggest := children[0]
+		if float64(tree.RetainedSize(biggest)) < float64(tree.RetainedSize(current))*AccumulationPointRatio {
+			break
+		}
+		current = biggest
+	}
+
+	object, _ := ctx.DescribeObject(current)
+	suspect.AccumulationPoint = current
+	suspect.AccumulationClassName = object.ClassName
+	suspect.AccumulationRetained = tree.RetainedSize(current)
+
+	// Name the field that holds it, e.g. HashMap.table
+	if holder, ok := tree.ImmediateDominator(current); ok && current != suspect.ObjectID {
+		if field := NewFieldExtractor(ctx).FieldReferencing(holder, current); field != "" {
+			holderObject, _ := ctx.DescribeObject(holder)
+			suspect.AccumulationField = fmt.Sprintf("%s.%s", holderObject.ClassName, field)
+		}
+	}
+}
+
+// BiggestInstanceOf returns the instance of a class with the largest retained size
+func BiggestInstanceOf(ctx *AnalysisContext, tree *DominatorTree, className string) model.ID {
+	var best model.ID
+	var bestRetained uint64
+
+	// Ties go to the lowest ID, as the walk visits equal siblings in no fixed order
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		if retained < bestRetained || retained == bestRetained && best != 0 && objectID > best {
+			return
+		}
+		if object, _ := ctx.DescribeObject(objectID); object.ClassName == className {
+			best = objectID
+			bestRetained = retained
+		}
+	}, nil)
+
+	return best
+}
+
+func describeObjectSuspect(suspect *LeakSuspect) string {
+	description := fmt.Sprintf("One instance of %s retains %s (%s of the reachable heap).",
+		suspect.ClassName, utils.MemorySize(suspect.RetainedSize).String(), utils.FormatPercent(suspect.Percentage))
+
+	if suspect.AccumulationPoint != suspect.ObjectID {
+		description += fmt.Sprintf(" Memory accumulates in %s @ 0x%x (%s)",
+			suspect.AccumulationClassName, uint64(suspect.AccumulationPoint),
+			utils.MemorySize(suspect.AccumulationRetained).String())
+		if suspect.AccumulationField != "" {
+			description += fmt.Sprintf(", held by %s", suspect.AccumulationField)
+		}
+		description += "."
+	}
+
+	return description
+}
+
+func describeClassSuspect(suspect *LeakSuspect) string {
+	return fmt.Sprintf("%d instances of %s together retain %s (%s of the reachable heap).",
+		suspect.InstanceCount, suspect.ClassName,
+		utils.MemorySize(suspect.RetainedSize).String(), utils.FormatPercent(suspect.Percentage))
+}
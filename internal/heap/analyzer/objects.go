@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// ObjectKind identifies which kind of heap record an object ID refers to
+type ObjectKind int
+
+const (
+	UnknownObject ObjectKind = iota
+	InstanceObject
+	ObjectArrayObject
+	PrimitiveArrayObject
+	ClassObject
+)
+
+func (k ObjectKind) String() string {
+	switch k {
+	case InstanceObject:
+		return "instance"
+	case ObjectArrayObject:
+		return "object array"
+	case PrimitiveArrayObject:
+		return "primitive array"
+	case ClassObject:
+		return "class"
+	default:
+		return "unknown"
+	}
+}
+
+// HeapObject is a resolved view of a single object in the dump
+type HeapObject struct {
+	ID          model.ID
+	Kind        ObjectKind
+	ClassID     model.ID // Class object ID (0 for primitive arrays)
+	ClassName   string   // Java-style class name, e.g. java.util.HashMap$Node[]
+	ShallowSize uint64
+	Length      uint32 // Element count for arrays
+}
+
+// DescribeObject resolves an object's kind, class and shallow size
+func (ctx *AnalysisContext) DescribeObject(objectID model.ID) (HeapObject, bool) {
+	if instance, ok := ctx.InstanceReg.GetInstance(objectID); ok {
+		return HeapObject{
+			ID:          objectID,
+			Kind:        InstanceObject,
+			ClassID:     instance.ClassObjectID,
+			ClassName:   ctx.ClassName(instance.ClassObjectID),
+			ShallowSize: ctx.instanceShallowSize(instance),
+		}, true
+	}
+
+	if array, ok := ctx.ArrayReg.GetObjectArray(objectID); ok {
+		return HeapObject{
+			ID:          objectID,
+			Kind:        ObjectArrayObject,
+			ClassID:     array.ClassID,
+			ClassName:   ctx.ClassName(array.ClassID),
+			ShallowSize: ctx.arrayShallowSize(array.Size, int(ctx.Config.IdentifierSize)),
+			Length:      array.Size,
+		}, true
+	}
+
+	if array, ok := ctx.ArrayReg.GetPrimitiveArray(objectID); ok {
+		return HeapObject{
+			ID:          objectID,
+			Kind:        PrimitiveArrayObject,
+			ClassName:   array.Type.String() + "[]",
+			ShallowSize: ctx.arrayShallowSize(array.Size, array.Type.Size(ctx.Config.IdentifierSize)),
+			Length:      array.Size,
+		}, true
+	}
+
+	if classDump, ok := ctx.ClassDumpReg.GetClassDump(objectID); ok {
+		return HeapObject{
+			ID:          objectID,
+			Kind:        ClassObject,
+			ClassID:     objectID,
+			ClassName:   "java.lang.Class",
+			ShallowSize: ctx.classShallowSize(classDump),
+		}, true
+	}
+
+	return HeapObject{ID: objectID, Kind: UnknownObject, ClassName: "<unknown>"}, false
+}
+
+// ClassName returns the Java-style name for a class object ID
+func (ctx *AnalysisContext) ClassName(classID model.ID) string {
+	if classID == 0 {
+		return "<unknown>"
+	}
+
+	if ctx.ClassReg != nil {
+		if classInfo, ok := ctx.ClassReg.GetByObjectID(classID); ok {
+			return JavaClassName(classInfo.ClassName)
+		}
+	}
+
+	return "<unresolved class>"
+}
+
+// ShallowSize returns the estimated in-memory size of a single object
+func (ctx *AnalysisContext) ShallowSize(objectID model.ID) uint64 {
+	object, _ := ctx.DescribeObject(objectID)
+	return object.ShallowSize
+}
+
+// DisplayName formats an object as "ClassName @ 0x..." for reports
+func (o HeapObject) DisplayName() string {
+	return fmt.Sprintf("%s @ 0x%x", o.ClassName, uint64(o.ID))
+}
+
+// Package returns the package portion of the class name, or "<default>"
+func (o HeapObject) Package() string {
+	return PackageName(o.ClassName)
+}
+
+/*
+* Object size estimates follow HotSpot's layout:
+*
+* 	object header		mark word + class pointer (2 * identifier size)
+* 	array header		object header + u4 length
+* 	alignment			every object is padded to 8 bytes
+*
+* Compressed class pointers can't be detected from the dump, so sizes may be
+* a few bytes larger than what the JVM actually uses.
+ */
+func (ctx *AnalysisContext) objectHeaderSize() uint64 {
+	return 2 * uint64(ctx.Config.IdentifierSize)
+}
+
+func (ctx *AnalysisContext) instanceShallowSize(instance *model.GCInstanceDump) uint64 {
+	return alignObjectSize(ctx.objectHeaderSize() + uint64(instance.Size))
+}
+
+func (ctx *AnalysisContext) arrayShallowSize(length uint32, elementSize int) uint64 {
+	return alignObjectSize(ctx.objectHeaderSize() + 4 + uint64(length)*uint64(elementSize))
+}
+
+func (ctx *AnalysisContext) classShallowSize(classDump *model.GCClassDump) uint64 {
+	size := ctx.objectHeaderSize()
+	for _, field := range classDump.StaticFields {
+		size += uint64(field.Type.Size(ctx.Config.IdentifierSize))
+	}
+	return alignObjectSize(size)
+}
+
+func alignObjectSize(size uint64) uint64 {
+	return (size + 7) &^ 7
+}
+
+// JavaClassName converts a JVM internal name to source form:
+// "java/lang/String" -> "java.lang.String", "[Ljava/lang/Object;" -> "java.lang.Object[]"
+func JavaClassName(internalName string) string {
+	dims := 0
+	for dims < len(internalName) && internalName[dims] == '[' {
+		dims++
+	}
+
+	name := internalName[dims:]
+	if dims > 0 {
+		switch {
+		case strings.HasPrefix(name, "L") && strings.HasSuffix(name, ";"):
+			name = name[1 : len(name)-1]
+		case len(name) == 1:
+			name = primitiveDescriptorName(name[0])
+		}
+	}
+
+	return strings.ReplaceAll(name, "/", ".") + strings.Repeat("[]", dims)
+}
+
+func primitiveDescriptorName(descriptor byte) string {
+	switch descriptor {
+	case 'Z':
+		return "boolean"
+	case 'C':
+		return "char"
+	case 'F':
+		return "float"
+	case 'D':
+		return "double"
+	case 'B':
+		return "byte"
+	case 'S':
+		return "short"
+	case 'I':
+		return "int"
+	case 'J':
+		return "long"
+	default:
+		return string(descriptor)
+	}
+}
+
+// PackageName returns the package of a Java class name, ignoring array suffixes
+func PackageName(className string) string {
+	name := strings.TrimRight(className, "[]")
+	if idx := strings.LastIndex(name, "."); idx > 0 {
+		return name[:idx]
+	}
+	return "<default>"
+}
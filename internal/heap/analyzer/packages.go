@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// Packages whose objects are usually implementation detail of whoever holds them
+var jdkPackagePrefixes = []string{"java.", "javax.", "jdk.", "sun.", "com.sun."}
+
+// PackageRetention is the heap owned by objects of one Java package
+type PackageRetention struct {
+	Package     string
+	ObjectCount int
+
+	// Memory freed if every object of the package were collected. Packages
+	// nest (a com.example cache retains java.util nodes), so these overlap.
+	RetainedSize uint64
+
+	// Shallow sizes of the package's own objects plus JDK objects it dominates.
+	// Owned sizes partition the reachable heap and add up to 100%.
+	OwnedSize  uint64
+	Percentage float64 // OwnedSize as a share of the reachable heap
+}
+
+// PackageRollup is what a breakdown at depth counts a package under: its first depth
+// names as "com.foo.*", or the package itself when depth is 0 or it has fewer names
+func PackageRollup(pkg string, depth int) string {
+	if depth <= 0 || pkg == "<default>" {
+		return pkg
+	}
+	names := strings.SplitN(pkg, ".", depth+1)
+	if len(names) < depth {
+		return pkg
+	}
+	return strings.Join(names[:depth], ".") + ".*"
+}
+
+/*
+ * BuildPackageBreakdown attributes the reachable heap to Java packages. With
+ * a depth above 0, subpackages are rolled up into their first depth names
+ * (depth 2 counts com.foo.cache and com.foo.web as com.foo.*), so heap can be
+ * put down to subsystems rather than every package in them. Whether an object
+ * is from the JDK is decided on its own package, before the rollup.
+ */
+func BuildPackageBreakdown(ctx *AnalysisContext, tree *DominatorTree, depth int) []*PackageRetention {
+	total := tree.TotalRetainedSize()
+	packages := make(map[string]*PackageRetention)
+	packageOf := make(map[model.ID]string)
+	active := make(map[string]int)
+	var owners []string
+
+	get := func(pkg string) *PackageRetention {
+		entry, ok := packages[pkg]
+		if !ok {
+			entry = &PackageRetention{Package: pkg}
+			packages[pkg] = entry
+		}
+		return entry
+	}
+
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		object, _ := ctx.DescribeObject(objectID)
+		jdk := isJDKPackage(object.Package())
+		pkg := PackageRollup(object.Package(), depth)
+		packageOf[objectID] = pkg
+
+		entry := get(pkg)
+		entry.ObjectCount++
+		if active[pkg] == 0 {
+			entry.RetainedSize += retained
+		}
+		active[pkg]++
+
+		// JDK objects and primitive arrays belong to the nearest dominator outside the JDK, if any
+		owner := pkg
+		if (jdk || object.Kind == PrimitiveArrayObject) && len(owners) > 0 {
+			owner = owners[len(owners)-1]
+		}
+		owners = append(owners, owner)
+		get(owner).OwnedSize += tree.ShallowSize(objectID)
+	}, func(objectID model.ID) {
+		active[packageOf[objectID]]--
+		owners = owners[:len(owners)-1]
+	})
+
+	breakdown := make([]*PackageRetention, 0, len(packages))
+	for _, entry := range packages {
+		if total > 0 {
+			entry.Percentage = float64(entry.OwnedSize) / float64(total) * 100
+		}
+		breakdown = append(breakdown, entry)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].OwnedSize != breakdown[j].OwnedSize {
+			return breakdown[i].OwnedSize > breakdown[j].OwnedSize
+		}
+		return breakdown[i].Package < breakdown[j].Package
+	})
+
+	return breakdown
+}
+
+func isJDKPackage(pkg string) bool {
+	for _, prefix := range jdkPackagePrefixes {
+		if strings.HasPrefix(pkg, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// MaxRootPathSearch bounds the number of objects visited when looking for a GC root path
+const MaxRootPathSearch = 1_000_000
+
+// ShortestPathToRoot finds the shortest reference chain from any GC root to objectID.
+// The path reads root -> object and is nil if no root is found within MaxRootPathSearch objects.
+func (a *Analyzer) ShortestPathToRoot(objectID model.ID) []model.ID {
+	if a.ReferenceMap == nil || a.ctx.RootReg == nil {
+		return nil
+	}
+
+	// Breadth-first search backwards over referrers; next[x] is the object x refers to on the way to objectID
+	next := map[model.ID]model.ID{objectID: objectID}
+	queue := []model.ID{objectID}
+
+	for len(queue) > 0 && len(next) <= MaxRootPathSearch {
+		current := queue[0]
+		queue = queue[1:]
+
+		if a.ctx.RootReg.IsRootObject(current) {
+			path := []model.ID{current}
+			for current != objectID {
+				current = next[current]
+				path = append(path, current)
+			}
+			return path
+		}
+
+		for _, referrer := range a.ReferenceMap.GetReferrers(current) {
+			if _, seen := next[referrer]; seen {
+				continue
+			}
+			next[referrer] = current
+			queue = append(queue, referrer)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* Soft, weak, final and phantom reachability
+*
+* java.lang.ref.Reference objects hold their referent in the "referent" field.
+* The GC treats that edge specially, so an object reachable only through it is
+* collected earlier than a strongly reachable one:
+*
+* 	soft		cleared when the heap is under memory pressure
+* 	weak		cleared at the next GC that finds it
+* 	final		kept until its finalizer has run (java.lang.ref.Finalizer)
+* 	phantom		already finalized, waiting to be enqueued
+*
+* Reachability is computed in stages: a strong pass from the GC roots that
+* never follows referent edges, then one pass per reference kind seeded with
+* the referents found so far. Each object is counted once, at the strongest
+* level it can be reached, matching the JVM's definition.
+ */
+
+type ReferenceKind int
+
+const (
+	StrongReference ReferenceKind = iota
+	SoftReference
+	WeakReference
+	FinalReference
+	PhantomReference
+)
+
+// ReferenceKinds lists the non-strong kinds from strongest to weakest
+var ReferenceKinds = []ReferenceKind{SoftReference, WeakReference, FinalReference, PhantomReference}
+
+func (k ReferenceKind) String() string {
+	switch k {
+	case StrongReference:
+		return "strong"
+	case SoftReference:
+		return "soft"
+	case WeakReference:
+		return "weak"
+	case FinalReference:
+		return "final"
+	case PhantomReference:
+		return "phantom"
+	default:
+		return "unknown"
+	}
+}
+
+var referenceBaseClasses = map[string]ReferenceKind{
+	"java.lang.ref.SoftReference":    SoftReference,
+	"java.lang.ref.WeakReference":    WeakReference,
+	"java.lang.ref.FinalReference":   FinalReference,
+	"java.lang.ref.PhantomReference": PhantomReference,
+}
+
+// ReferenceKindStats summarizes one kind of java.lang.ref.Reference
+type ReferenceKindStats struct {
+	Kind          ReferenceKind
+	Count         int            // Reference objects of this kind
+	LiveReferents int            // References whose referent hasn't been cleared
+	Referents     map[string]int // Referent class name -> count
+
+	// Objects reachable only through references of this kind (or weaker ones),
+	// i.e. what the GC is allowed to reclaim at this level
+	ReachableCount int
+	ReachableSize  uint64
+}
+
+// ReferenceOwner groups the memory held by one Reference subclass, e.g. a cache's soft entry type
+type ReferenceOwner struct {
+	ClassName      string
+	Kind           ReferenceKind
+	Count          int
+	ReachableCount int
+	ReachableSize  uint64
+}
+
+type ReferenceStats struct {
+	Kinds map[ReferenceKind]*ReferenceKindStats
+
+	StronglyReachableCount int
+	StronglyReachableSize  uint64
+
+	Owners []*ReferenceOwner // Largest ReachableSize first
+}
+
+type referenceObject struct {
+	kind     ReferenceKind
+	referent model.ID
+}
+
+type reachabilitySeed struct {
+	id    model.ID
+	owner *ReferenceOwner
+}
+
+// AnalyzeReferences classifies the heap by the strongest kind of reference that keeps each object alive
+func AnalyzeReferences(ctx *AnalysisContext, graph *ObjectGraph) (*ReferenceStats, error) {
+	if graph == nil || graph.References == nil {
+		return nil, fmt.Errorf("object graph is required")
+	}
+
+	stats := &ReferenceStats{Kinds: make(map[ReferenceKind]*ReferenceKindStats)}
+	for _, kind := range ReferenceKinds {
+		stats.Kinds[kind] = &ReferenceKindStats{Kind: kind, Referents: make(map[string]int)}
+	}
+
+	references, owners := findReferenceObjects(ctx, stats)
+
+	levels := make(map[model.ID]ReferenceKind)
+	pending := make(map[ReferenceKind][]reachabilitySeed)
+
+	visit := func(stage ReferenceKind, seeds []reachabilitySeed) {
+		var queue []reachabilitySeed
+		push := func(seed reachabilitySeed) {
+			if _, seen := levels[seed.id]; seen || !graph.ObjectExists[seed.id] {
+				return
+			}
+			levels[seed.id] = stage
+			queue = append(queue, seed)
+		}
+		for _, seed := range seeds {
+			push(seed)
+		}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			size := ctx.ShallowSize(current.id)
+			if stage == StrongReference {
+				stats.StronglyReachableCount++
+				stats.StronglyReachableSize += size
+			} else {
+				stats.Kinds[stage].ReachableCount++
+				stats.Kinds[stage].ReachableSize += size
+				if current.owner != nil {
+					current.owner.ReachableCount++
+					current.owner.ReachableSize += size
+				}
+			}
+
+			ref, isReference := references[current.id]
+			for _, target := range graph.References.GetReferences(current.id) {
+				if target == SuperRootID {
+					continue
+				}
+				if isReference && target == ref.referent {
+					// Referents are only as reachable as their weakest link
+					seed := reachabilitySeed{id: target, owner: current.owner}
+					if ref.kind > stage {
+						seed.owner = owners[current.id]
+						pending[ref.kind] = append(pending[ref.kind], seed)
+					} else {
+						push(seed)
+					}
+					continue
+				}
+				push(reachabilitySeed{id: target, owner: current.owner})
+			}
+		}
+	}
+
+	var roots []reachabilitySeed
+	for _, objectID := range graphRoots(graph, graph.References.GetReferences(SuperRootID)) {
+		roots = append(roots, reachabilitySeed{id: objectID})
+	}
+	visit(StrongReference, roots)
+	for _, kind := range ReferenceKinds {
+		visit(kind, pending[kind])
+	}
+
+	ownerSet := make(map[*ReferenceOwner]bool)
+	for _, owner := range owners {
+		if !ownerSet[owner] {
+			ownerSet[owner] = true
+			stats.Owners = append(stats.Owners, owner)
+		}
+	}
+	sort.Slice(stats.Owners, func(i, j int) bool {
+		a, b := stats.Owners[i], stats.Owners[j]
+		if a.ReachableSize != b.ReachableSize {
+			return a.ReachableSize > b.ReachableSize
+		}
+		return a.ClassName < b.ClassName
+	})
+
+	return stats, nil
+}
+
+// findReferenceObjects decodes every Reference instance and groups them by class
+func findReferenceObjects(ctx *AnalysisContext, stats *ReferenceStats) (map[model.ID]referenceObject, map[model.ID]*ReferenceOwner) {
+	extractor := NewFieldExtractor(ctx)
+	kindOfClass := make(map[model.ID]ReferenceKind)
+	ownersByClass := make(map[string]*ReferenceOwner)
+
+	references := make(map[model.ID]referenceObject)
+	owners := make(map[model.ID]*ReferenceOwner)
+
+	for objectID, instance := range ctx.InstanceReg.GetAllInstances() {
+		kind, ok := kindOfClass[instance.ClassObjectID]
+		if !ok {
+			kind = ctx.referenceKindOf(instance.ClassObjectID)
+			kindOfClass[instance.ClassObjectID] = kind
+		}
+		if kind == StrongReference {
+			continue
+		}
+
+		className := ctx.ClassName(instance.ClassObjectID)
+		owner, ok := ownersByClass[className]
+		if !ok {
+			owner = &ReferenceOwner{ClassName: className, Kind: kind}
+			ownersByClass[className] = owner
+		}
+		owner.Count++
+		owners[objectID] = owner
+
+		kindStats := stats.Kinds[kind]
+		kindStats.Count++
+
+		referent := referentOf(extractor, ctx, instance)
+		references[objectID] = referenceObject{kind: kind, referent: referent}
+		if referent != 0 {
+			kindStats.LiveReferents++
+			referentObject, _ := ctx.DescribeObject(referent)
+			kindStats.Referents[referentObject.ClassName]++
+		}
+	}
+
+	return references, owners
+}
+
+// referenceKindOf walks the superclass chain looking for one of the java.lang.ref base classes
+func (ctx *AnalysisContext) referenceKindOf(classID model.ID) ReferenceKind {
+	for classID != 0 {
+		if kind, ok := referenceBaseClasses[ctx.ClassName(classID)]; ok {
+			return kind
+		}
+
+		classDump, ok := ctx.ClassDumpReg.GetClassDump(classID)
+		if !ok {
+			break
+		}
+		classID = classDump.SuperClassObjectID
+	}
+	return StrongReference
+}
+
+func referentOf(extractor *FieldExtractor, ctx *AnalysisContext, instance *model.GCInstanceDump) model.ID {
+	classDump, ok := ctx.ClassDumpReg.GetClassDump(instance.ClassObjectID)
+	if !ok {
+		return 0
+	}
+
+	fields, err := extractor.ExtractInstanceFields(instance, classDump)
+	if err != nil {
+		return 0
+	}
+
+	for _, field := range fields {
+		if field.Name == "referent" {
+			if id, ok := field.Value.(model.ID); ok {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// TopReferents returns the most common referent classes for a kind
+func (s *ReferenceKindStats) TopReferents(n int) []string {
+	classes := make([]string, 0, len(s.Referents))
+	for className := range s.Referents {
+		classes = append(classes, className)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if s.Referents[classes[i]] != s.Referents[classes[j]] {
+			return s.Referents[classes[i]] > s.Referents[classes[j]]
+		}
+		return classes[i] < classes[j]
+	})
+
+	return classes[:min(n, len(classes))]
+}
+
+// ReclaimableSize is the memory the GC frees under memory pressure before throwing
+// OutOfMemoryError: softly and weakly reachable objects. Final and phantom
+// reachable objects also go away, but only after reference processing.
+func (s *ReferenceStats) ReclaimableSize() uint64 {
+	return s.Kinds[SoftReference].ReachableSize + s.Kinds[WeakReference].ReachableSize
+}
@@ -46,14 +46,24 @@ func (r *ResolverFinal) BuildReferenceMap() (*ReferenceMap, error) {
 		return nil, fmt.Errorf("resolver not initialized - call Initialize() first")
 	}
 
-	fmt.Println("🔗 Phase 11.2: Building cross-reference maps (VisualVM-compatible)...")
+	fmt.Fprintln(r.ctx.Out, "🔗 Phase 11.2: Building cross-reference maps (VisualVM-compatible)...")
 
 	refMap := NewReferenceMap()
+	if r.ctx.spill != nil {
+		spilled, err := newSpilledReferenceMap(r.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to spill references: %w", err)
+		}
+		refMap = spilled
+	}
 
 	// Build references using a structured approach
 	if err := r.buildAllReferences(refMap); err != nil {
 		return nil, fmt.Errorf("failed to build references: %w", err)
 	}
+	if err := refMap.finish(); err != nil {
+		return nil, fmt.Errorf("failed to lay out spilled references: %w", err)
+	}
 
 	// Print summary
 	r.printReferenceSummary(refMap)
@@ -75,7 +85,7 @@ func (r *ResolverFinal) buildAllReferences(refMap *ReferenceMap) error {
 	}
 
 	for _, builder := range referenceBuilders {
-		fmt.Printf("  Building %s...\n", builder.name)
+		fmt.Fprintf(r.ctx.Out, "  Building %s...\n", builder.name)
 		if err := builder.fn(refMap); err != nil {
 			return fmt.Errorf("failed to build %s: %w", builder.name, err)
 		}
@@ -215,26 +225,26 @@ func (r *ResolverFinal) extractObjectIDFromStaticField(staticField *model.Static
 func (r *ResolverFinal) printReferenceSummary(refMap *ReferenceMap) {
 	stats := r.calculateReferenceStatistics(refMap)
 
-	fmt.Printf("  Cross-reference mapping completed:\n")
-	fmt.Printf("    Objects with outgoing references: %d\n", stats.ObjectsWithReferences)
-	fmt.Printf("    Total forward references: %d\n", stats.TotalForwardRefs)
-	fmt.Printf("    Total backward references: %d\n", stats.TotalBackwardRefs)
+	fmt.Fprintf(r.ctx.Out, "  Cross-reference mapping completed:\n")
+	fmt.Fprintf(r.ctx.Out, "    Objects with outgoing references: %d\n", stats.ObjectsWithReferences)
+	fmt.Fprintf(r.ctx.Out, "    Total forward references: %d\n", stats.TotalForwardRefs)
+	fmt.Fprintf(r.ctx.Out, "    Total backward references: %d\n", stats.TotalBackwardRefs)
 
 	if stats.ObjectsWithReferences > 0 {
-		fmt.Printf("    Average references per object: %.2f\n", stats.AvgReferencesPerObject)
+		fmt.Fprintf(r.ctx.Out, "    Average references per object: %.2f\n", stats.AvgReferencesPerObject)
 	}
 
 	if stats.MaxOutgoing > 0 {
-		fmt.Printf("    Most outgoing refs: Object 0x%x (%d references)\n",
+		fmt.Fprintf(r.ctx.Out, "    Most outgoing refs: Object 0x%x (%d references)\n",
 			uint64(stats.MaxOutgoingID), stats.MaxOutgoing)
 	}
 
 	if stats.MaxIncoming > 0 {
-		fmt.Printf("    Most incoming refs: Object 0x%x (%d referrers)\n",
+		fmt.Fprintf(r.ctx.Out, "    Most incoming refs: Object 0x%x (%d referrers)\n",
 			uint64(stats.MaxIncomingID), stats.MaxIncoming)
 	}
 
-	fmt.Printf("    ✅ Reference mapping complete\n")
+	fmt.Fprintf(r.ctx.Out, "    ✅ Reference mapping complete\n")
 }
 
 // ReferenceStatistics holds statistics about the reference mapping
@@ -251,17 +261,24 @@ type ReferenceStatistics struct {
 
 // calculateReferenceStatistics computes comprehensive statistics about the reference map
 func (r *ResolverFinal) calculateReferenceStatistics(refMap *ReferenceMap) *ReferenceStatistics {
-	stats := &ReferenceStatistics{
-		ObjectsWithReferences: len(refMap.ForwardRefs),
-	}
+	stats := &ReferenceStatistics{}
 
-	// Calculate totals
-	for _, refs := range refMap.ForwardRefs {
+	// Calculate totals and find maximums
+	for objID, refs := range refMap.AllReferences() {
+		stats.ObjectsWithReferences++
 		stats.TotalForwardRefs += len(refs)
+		if len(refs) > stats.MaxOutgoing {
+			stats.MaxOutgoing = len(refs)
+			stats.MaxOutgoingID = objID
+		}
 	}
 
-	for _, refs := range refMap.BackwardRefs {
+	for objID, refs := range refMap.AllReferrers() {
 		stats.TotalBackwardRefs += len(refs)
+		if len(refs) > stats.MaxIncoming {
+			stats.MaxIncoming = len(refs)
+			stats.MaxIncomingID = objID
+		}
 	}
 
 	// Calculate average
@@ -269,20 +286,5 @@ func (r *ResolverFinal) calculateReferenceStatistics(refMap *ReferenceMap) *Refe
 		stats.AvgReferencesPerObject = float64(stats.TotalForwardRefs) / float64(stats.ObjectsWithReferences)
 	}
 
-	// Find maximums
-	for objID, count := range refMap.OutgoingCount {
-		if count > stats.MaxOutgoing {
-			stats.MaxOutgoing = count
-			stats.MaxOutgoingID = objID
-		}
-	}
-
-	for objID, count := range refMap.IncomingCount {
-		if count > stats.MaxIncoming {
-			stats.MaxIncoming = count
-			stats.MaxIncomingID = objID
-		}
-	}
-
 	return stats
 }
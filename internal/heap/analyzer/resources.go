@@ -0,0 +1,277 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* Resource census
+*
+* Monitoring counts a process's open file descriptors; the heap shows which
+* objects they belong to. Instances of the JDK's file, socket and channel
+* classes, and of JDBC driver connections and statements, are counted with
+* whether they are still open when the JDK's (or the driver's) own fields
+* say so:
+*
+* 	closed, isClosed	boolean, on streams, channels, sockets, most drivers
+* 	open				boolean, on JDK 8 channels
+* 	state				int, on sun.nio.ch.NioSocketImpl (JDK 13+)
+* 	fd					int on a FileDescriptor, -1 once closed; else the
+* 						FileDescriptor it points at
+* 	impl				SocketImpl of a java.net.Socket
+*
+* One open socket shows up several times: as a Socket, its SocketImpl and
+* its FileDescriptor. An open resource that is no longer reachable is
+* garbage still holding its handle until a Cleaner or finalizer runs; many
+* of them point at code that forgets to close what it opens.
+ */
+
+type ResourceKind int
+
+const (
+	FileDescriptorResource ResourceKind = iota
+	FileStreamResource
+	SocketResource
+	SocketImplResource
+	ChannelResource
+	ConnectionResource
+	StatementResource
+)
+
+// ResourceKinds lists every kind in report order
+var ResourceKinds = []ResourceKind{FileDescriptorResource, FileStreamResource, SocketResource, SocketImplResource,
+	ChannelResource, ConnectionResource, StatementResource}
+
+func (k ResourceKind) String() string {
+	switch k {
+	case FileDescriptorResource:
+		return "file descriptor"
+	case FileStreamResource:
+		return "file stream"
+	case SocketResource:
+		return "socket"
+	case SocketImplResource:
+		return "socket impl"
+	case ChannelResource:
+		return "channel"
+	case ConnectionResource:
+		return "JDBC connection"
+	case StatementResource:
+		return "JDBC statement"
+	default:
+		return "unknown"
+	}
+}
+
+var resourceBaseClasses = map[string]ResourceKind{
+	"java.io.FileDescriptor":                             FileDescriptorResource,
+	"java.io.FileInputStream":                            FileStreamResource,
+	"java.io.FileOutputStream":                           FileStreamResource,
+	"java.io.RandomAccessFile":                           FileStreamResource,
+	"java.net.Socket":                                    SocketResource,
+	"java.net.ServerSocket":                              SocketResource,
+	"java.net.DatagramSocket":                            SocketResource,
+	"java.net.SocketImpl":                                SocketImplResource,
+	"java.nio.channels.spi.AbstractInterruptibleChannel": ChannelResource,
+	"com.zaxxer.hikari.pool.ProxyConnection":             ConnectionResource,
+	"com.zaxxer.hikari.pool.ProxyStatement":              StatementResource,
+}
+
+// NioSocketImpl's state from ST_CLOSING on
+const nioSocketClosing = 4
+
+// ResourceCount tallies resources by whether they are open
+type ResourceCount struct {
+	Total   int
+	Open    int
+	Closed  int
+	Unknown int // No field said either way
+
+	UnreachableOpen int // Open, but garbage waiting to be collected
+}
+
+// ResourceClass is the census of one resource class
+type ResourceClass struct {
+	ClassName string
+	Kind      ResourceKind
+	ResourceCount
+}
+
+type ResourceCensus struct {
+	Kinds   map[ResourceKind]*ResourceCount
+	Classes []*ResourceClass // Most open first
+}
+
+type resourceState int
+
+const (
+	resourceUnknown resourceState = iota
+	resourceOpen
+	resourceClosed
+)
+
+// CensusResources counts the file, socket, channel and JDBC objects in the dump by state
+func CensusResources(ctx *AnalysisContext, tree *DominatorTree) *ResourceCensus {
+	census := &ResourceCensus{Kinds: make(map[ResourceKind]*ResourceCount)}
+	for _, kind := range ResourceKinds {
+		census.Kinds[kind] = &ResourceCount{}
+	}
+
+	extractor := NewFieldExtractor(ctx)
+	type classKind struct {
+		kind       ResourceKind
+		isResource bool
+	}
+	kindOfClass := make(map[model.ID]classKind)
+	classes := make(map[model.ID]*ResourceClass)
+
+	for objectID, instance := range ctx.InstanceReg.GetAllInstances() {
+		resource, ok := kindOfClass[instance.ClassObjectID]
+		if !ok {
+			resource.kind, resource.isResource = ctx.resourceKindOf(instance.ClassObjectID)
+			kindOfClass[instance.ClassObjectID] = resource
+		}
+		if !resource.isResource {
+			continue
+		}
+
+		class, ok := classes[instance.ClassObjectID]
+		if !ok {
+			class = &ResourceClass{ClassName: ctx.ClassName(instance.ClassObjectID), Kind: resource.kind}
+			classes[instance.ClassObjectID] = class
+		}
+
+		state := resourceStateOf(extractor, ctx, objectID, 0)
+		reachable := tree == nil || tree.Contains(objectID)
+		for _, count := range []*ResourceCount{&class.ResourceCount, census.Kinds[resource.kind]} {
+			count.add(state, reachable)
+		}
+	}
+
+	for _, class := range classes {
+		census.Classes = append(census.Classes, class)
+	}
+	sort.Slice(census.Classes, func(i, j int) bool {
+		a, b := census.Classes[i], census.Classes[j]
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.ClassName < b.ClassName
+	})
+
+	return census
+}
+
+func (rc *ResourceCount) add(state resourceState, reachable bool) {
+	rc.Total++
+	switch state {
+	case resourceOpen:
+		rc.Open++
+		if !reachable {
+			rc.UnreachableOpen++
+		}
+	case resourceClosed:
+		rc.Closed++
+	default:
+		rc.Unknown++
+	}
+}
+
+/*
+ * resourceKindOf walks the superclass chain for one of the resource base
+ * classes. JDBC drivers share no base class, and the interfaces they
+ * implement aren't in the dump, so failing that a class in a jdbc or sql
+ * package named ...Connection or ...Statement is taken for one.
+ */
+func (ctx *AnalysisContext) resourceKindOf(classID model.ID) (ResourceKind, bool) {
+	var names []string
+	for classID != 0 {
+		name := ctx.ClassName(classID)
+		if kind, ok := resourceBaseClasses[name]; ok {
+			return kind, true
+		}
+		names = append(names, name)
+
+		classDump, ok := ctx.ClassDumpReg.GetClassDump(classID)
+		if !ok {
+			break
+		}
+		classID = classDump.SuperClassObjectID
+	}
+
+	for _, name := range names {
+		pkg := strings.ToLower(PackageName(name))
+		if strings.HasPrefix(pkg, "java.") || !(strings.Contains(pkg, "jdbc") || strings.Contains(pkg, "sql")) {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, "Connection"):
+			return ConnectionResource, true
+		case strings.HasSuffix(name, "Statement"):
+			return StatementResource, true
+		}
+	}
+	return 0, false
+}
+
+// resourceStateOf decodes whether a resource is open, following fd and impl fields a couple of levels down
+func resourceStateOf(extractor *FieldExtractor, ctx *AnalysisContext, objectID model.ID, depth int) resourceState {
+	fields, err := extractor.InstanceFields(objectID)
+	if err != nil {
+		return resourceUnknown
+	}
+	byName := make(map[string]FieldValue, len(fields))
+	for _, field := range fields {
+		if _, seen := byName[field.Name]; !seen { // A subclass's field hides its superclass's
+			byName[field.Name] = field
+		}
+	}
+
+	// A FileDescriptor's own descriptor: -1 once closed or never opened, unless a Windows handle is set
+	if fd, ok := byName["fd"].Value.(int32); ok {
+		if handle, ok := byName["handle"].Value.(int64); ok && fd == -1 {
+			return stateOf(handle != -1)
+		}
+		return stateOf(fd != -1)
+	}
+
+	for _, name := range []string{"closed", "isClosed"} {
+		if closed, ok := byName[name].Value.(bool); ok {
+			return stateOf(!closed)
+		}
+	}
+	if open, ok := byName["open"].Value.(bool); ok {
+		return stateOf(open)
+	}
+
+	instance, _ := ctx.InstanceReg.GetInstance(objectID)
+	if ctx.ClassName(instance.ClassObjectID) == "sun.nio.ch.NioSocketImpl" {
+		if state, ok := byName["state"].Value.(int32); ok {
+			return stateOf(state < nioSocketClosing)
+		}
+	}
+
+	if depth < 2 {
+		for _, name := range []string{"fd", "impl"} {
+			if target, ok := byName[name].Value.(model.ID); ok && target != 0 {
+				if state := resourceStateOf(extractor, ctx, target, depth+1); state != resourceUnknown {
+					return state
+				}
+			}
+		}
+	}
+	return resourceUnknown
+}
+
+func stateOf(open bool) resourceState {
+	if open {
+		return resourceOpen
+	}
+	return resourceClosed
+}
@@ -0,0 +1,302 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"unsafe"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* Spilling to disk
+*
+* The reference edges and the dominator tree's per-object arrays grow with
+* the number of objects, and on a dump bigger than the RAM at hand they are
+* what takes the analyzer itself out of memory. Given a memory budget
+* (--max-memory), the analysis estimates whether they fit in what's left of
+* it once the dump is parsed; when they don't, they are kept in temporary
+* files mapped into memory instead. Those pages are file-backed, so under
+* pressure the kernel writes them out and reads them back as they're used
+* instead of the process being killed.
+*
+* Spilled edges are appended to a file as the resolver finds them and only
+* then laid out, in one array per direction indexed by object, so they are
+* never held in maps. Lookups binary search the sorted object IDs.
+*
+* Each file is unlinked as soon as it's mapped: the space comes back when
+* the analyzer is closed, or when the process exits however it exits.
+* Platforms without mmap keep everything on the Go heap.
+ */
+
+// inMemoryBytesPerObject is roughly what the reference maps (both directions,
+// about two references per object) and the dominator arrays cost per object
+const inMemoryBytesPerObject = 300
+
+// spillArena hands out slices backed by temporary files; a nil arena allocates on the Go heap
+type spillArena struct {
+	dir    string             // Where the files go; "" is the system temporary directory
+	mapped map[uintptr][]byte // Mappings by address, so a slice can be released early
+	warned bool
+	out    io.Writer // Where the warning about falling back to memory goes
+	used   int64
+}
+
+func newSpillArena(dir string, out io.Writer) *spillArena {
+	return &spillArena{dir: dir, mapped: make(map[uintptr][]byte), out: out}
+}
+
+// spillSlice returns a zeroed slice of length n that can grow to capacity without reallocating
+func spillSlice[T int32 | uint64 | model.ID](arena *spillArena, n, capacity int) []T {
+	if arena == nil || capacity == 0 {
+		return make([]T, n, capacity)
+	}
+
+	var zero T
+	data, err := arena.allocate(capacity * int(unsafe.Sizeof(zero)))
+	if err != nil {
+		if !arena.warned {
+			fmt.Fprintf(arena.out, "  ⚠️  Unable to spill to disk (%v), keeping the analysis in memory\n", err)
+			arena.warned = true
+		}
+		return make([]T, n, capacity)
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&data[0])), capacity)[:n]
+}
+
+// releaseSlice unmaps a slice from spillSlice once it's no longer needed; heap slices are left to the GC
+func releaseSlice[T int32 | uint64 | model.ID](arena *spillArena, s []T) {
+	if arena == nil || cap(s) == 0 {
+		return
+	}
+	address := uintptr(unsafe.Pointer(unsafe.SliceData(s)))
+	if data, ok := arena.mapped[address]; ok {
+		delete(arena.mapped, address)
+		arena.used -= int64(len(data))
+		unmapSpillFile(data)
+	}
+}
+
+// allocate maps a new zero-filled temporary file of size bytes
+func (sa *spillArena) allocate(size int) ([]byte, error) {
+	file, err := os.CreateTemp(sa.dir, "jdiag-spill-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create spill file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	// A sparse file: disk is only used for the pages that get written out
+	if err := file.Truncate(int64(size)); err != nil {
+		return nil, fmt.Errorf("unable to size spill file: %w", err)
+	}
+	data, err := mapSpillFile(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	sa.mapped[uintptr(unsafe.Pointer(&data[0]))] = data
+	sa.used += int64(size)
+	return data, nil
+}
+
+// size is the size of everything currently spilled
+func (sa *spillArena) size() int64 {
+	return sa.used
+}
+
+// close unmaps every spilled slice; none of them can be used afterwards
+func (sa *spillArena) close() error {
+	var firstErr error
+	for address, data := range sa.mapped {
+		if err := unmapSpillFile(data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(sa.mapped, address)
+	}
+	sa.used = 0
+	return firstErr
+}
+
+// spilledReferences is the disk-backed form of a ReferenceMap
+type spilledReferences struct {
+	arena *spillArena
+	nodes []model.ID // Sorted IDs of every object, plus SuperRootID; an object's position is its node
+
+	// (source, target) pairs in the order they were added, until finish lays them out
+	edges  *os.File
+	writer *bufio.Writer
+	err    error
+
+	// The references of node i are forward[forwardStart[i]:forwardStart[i+1]], and likewise backward
+	forwardStart  []uint64
+	forward       []model.ID
+	backwardStart []uint64
+	backward      []model.ID
+}
+
+const spilledEdgeSize = 16
+
+// newSpilledReferenceMap creates a reference map whose edges are kept on disk
+func newSpilledReferenceMap(ctx *AnalysisContext) (*ReferenceMap, error) {
+	instances := ctx.InstanceReg.GetAllInstances()
+	objectArrays := ctx.ArrayReg.GetAllObjectArrays()
+	primitiveArrays := ctx.ArrayReg.GetAllPrimitiveArrays()
+	classes := ctx.ClassDumpReg.GetAllClassDumps()
+
+	nodes := spillSlice[model.ID](ctx.spill, 0, 1+len(instances)+len(objectArrays)+len(primitiveArrays)+len(classes))
+	nodes = append(nodes, SuperRootID)
+	for objectID := range instances {
+		nodes = append(nodes, objectID)
+	}
+	for objectID := range objectArrays {
+		nodes = append(nodes, objectID)
+	}
+	for objectID := range primitiveArrays {
+		nodes = append(nodes, objectID)
+	}
+	for objectID := range classes {
+		nodes = append(nodes, objectID)
+	}
+	slices.Sort(nodes)
+	nodes = slices.Compact(nodes)
+
+	edges, err := os.CreateTemp(ctx.spill.dir, "jdiag-edges-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create edge file: %w", err)
+	}
+
+	return &ReferenceMap{spilled: &spilledReferences{
+		arena:  ctx.spill,
+		nodes:  nodes,
+		edges:  edges,
+		writer: bufio.NewWriterSize(edges, 1<<20),
+	}}, nil
+}
+
+// node returns the position of an object in nodes
+func (sr *spilledReferences) node(objectID model.ID) (int, bool) {
+	return slices.BinarySearch(sr.nodes, objectID)
+}
+
+func (sr *spilledReferences) add(source, target model.ID) {
+	if sr.err != nil {
+		return
+	}
+	var edge [spilledEdgeSize]byte
+	binary.LittleEndian.PutUint64(edge[:8], uint64(source))
+	binary.LittleEndian.PutUint64(edge[8:], uint64(target))
+	_, sr.err = sr.writer.Write(edge[:])
+}
+
+// readEdges calls fn for every added edge, in the order they were added
+func (sr *spilledReferences) readEdges(fn func(source, target model.ID)) error {
+	if _, err := sr.edges.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(sr.edges, 1<<20)
+	var edge [spilledEdgeSize]byte
+	for {
+		if _, err := io.ReadFull(reader, edge[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fn(model.ID(binary.LittleEndian.Uint64(edge[:8])), model.ID(binary.LittleEndian.Uint64(edge[8:])))
+	}
+}
+
+/*
+ * finish lays the edges out by node: one pass counts each node's references
+ * and referrers, the next places them. Forward lists are kept for every
+ * source in nodes, whatever the target; backward lists only for targets in
+ * nodes, as references to objects missing from the dump have no node to hang
+ * off. Both keep the order the references were added in.
+ */
+func (sr *spilledReferences) finish() error {
+	defer func() {
+		sr.edges.Close()
+		os.Remove(sr.edges.Name())
+		sr.edges, sr.writer = nil, nil
+	}()
+
+	if sr.err != nil {
+		return fmt.Errorf("unable to write edge file: %w", sr.err)
+	}
+	if err := sr.writer.Flush(); err != nil {
+		return fmt.Errorf("unable to write edge file: %w", err)
+	}
+
+	n := len(sr.nodes)
+	sr.forwardStart = spillSlice[uint64](sr.arena, n+1, n+1)
+	sr.backwardStart = spillSlice[uint64](sr.arena, n+1, n+1)
+	err := sr.readEdges(func(source, target model.ID) {
+		if s, ok := sr.node(source); ok {
+			sr.forwardStart[s+1]++
+		}
+		if t, ok := sr.node(target); ok {
+			sr.backwardStart[t+1]++
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read edge file: %w", err)
+	}
+	for i := range n {
+		sr.forwardStart[i+1] += sr.forwardStart[i]
+		sr.backwardStart[i+1] += sr.backwardStart[i]
+	}
+
+	// Each node's start is its cursor while placing, which leaves it at the next node's start
+	sr.forward = spillSlice[model.ID](sr.arena, int(sr.forwardStart[n]), int(sr.forwardStart[n]))
+	sr.backward = spillSlice[model.ID](sr.arena, int(sr.backwardStart[n]), int(sr.backwardStart[n]))
+	err = sr.readEdges(func(source, target model.ID) {
+		if s, ok := sr.node(source); ok {
+			sr.forward[sr.forwardStart[s]] = target
+			sr.forwardStart[s]++
+		}
+		if t, ok := sr.node(target); ok {
+			sr.backward[sr.backwardStart[t]] = source
+			sr.backwardStart[t]++
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read edge file: %w", err)
+	}
+	copy(sr.forwardStart[1:], sr.forwardStart[:n])
+	copy(sr.backwardStart[1:], sr.backwardStart[:n])
+	sr.forwardStart[0], sr.backwardStart[0] = 0, 0
+
+	return nil
+}
+
+func (sr *spilledReferences) references(objectID model.ID) []model.ID {
+	i, ok := sr.node(objectID)
+	if !ok || sr.forwardStart == nil {
+		return nil
+	}
+	return sr.forward[sr.forwardStart[i]:sr.forwardStart[i+1]]
+}
+
+func (sr *spilledReferences) referrers(objectID model.ID) []model.ID {
+	i, ok := sr.node(objectID)
+	if !ok || sr.backwardStart == nil {
+		return nil
+	}
+	return sr.backward[sr.backwardStart[i]:sr.backwardStart[i+1]]
+}
+
+// all yields every node with a non-empty list
+func (sr *spilledReferences) all(list func(model.ID) []model.ID) iter.Seq2[model.ID, []model.ID] {
+	return func(yield func(model.ID, []model.ID) bool) {
+		for _, objectID := range sr.nodes {
+			if refs := list(objectID); len(refs) > 0 && !yield(objectID, refs) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package analyzer
+
+import (
+	"fmt"
+	"os"
+)
+
+// mapSpillFile is not supported on this platform; spilled slices stay on the Go heap
+func mapSpillFile(file *os.File, size int) ([]byte, error) {
+	return nil, fmt.Errorf("mmap not supported on this platform")
+}
+
+func unmapSpillFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapSpillFile maps a spill file read-write; writes go back to the file, not to swap
+func mapSpillFile(file *os.File, size int) ([]byte, error) {
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap failed: %w", err)
+	}
+	return data, nil
+}
+
+func unmapSpillFile(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// Defaults for retained subgraph exports
+const (
+	DefaultSubgraphDepth      = 4
+	DefaultSubgraphMaxClasses = 8
+	DefaultSubgraphMinShare   = 0.01
+)
+
+// SubgraphOptions bounds the size of a retained subgraph
+type SubgraphOptions struct {
+	MaxDepth   int     // Dominator tree levels below the root object
+	MaxClasses int     // Class groups kept per node; the rest are merged
+	MinShare   float64 // Groups retaining less than this fraction of the root are merged
+}
+
+// SubgraphNode is a group of objects of one class at the same place in the retained subtree
+type SubgraphNode struct {
+	ID           int
+	ClassName    string
+	Objects      int
+	ShallowSize  uint64
+	RetainedSize uint64
+	Depth        int
+	Sample       model.ID // Largest object in the group (0 for merged groups)
+	Merged       bool     // Stands for several small class groups
+}
+
+// SubgraphEdge means every object in To is dominated by an object in From
+type SubgraphEdge struct {
+	From, To int
+	Objects  int
+}
+
+// RetainedSubgraph is the class-aggregated retained subtree of one object,
+// plus the reference chain that keeps the object alive
+type RetainedSubgraph struct {
+	Root         model.ID
+	RootSize     uint64
+	Nodes        []*SubgraphNode // Nodes[0] is the root object
+	Edges        []SubgraphEdge
+	RootPath     []HeapObject // GC root -> object, excluding the object itself
+	RootPathType string       // GC root kind of RootPath[0]
+}
+
+/*
+ * Build the class-aggregated retained subtree of objectID.
+ *
+ * The dominator tree is walked level by level. At each level the dominated
+ * children of every object in a group are bucketed by class, so a HashMap
+ * holding 50k Session objects becomes a single "Session ×50000" node instead
+ * of 50k nodes. Buckets below MinShare of the root, or beyond MaxClasses per
+ * group, are merged into one "other classes" node that is not expanded.
+ */
+func (a *Analyzer) BuildRetainedSubgraph(objectID model.ID, options SubgraphOptions) (*RetainedSubgraph, error) {
+	tree := a.DominatorTree
+	if tree == nil {
+		return nil, fmt.Errorf("heap analysis has no dominator tree")
+	}
+	if objectID == SuperRootID || !tree.Contains(objectID) {
+		return nil, fmt.Errorf("object 0x%x is not reachable from any GC root", uint64(objectID))
+	}
+
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = DefaultSubgraphDepth
+	}
+	if options.MaxClasses <= 0 {
+		options.MaxClasses = DefaultSubgraphMaxClasses
+	}
+
+	root, _ := a.ctx.DescribeObject(objectID)
+	graph := &RetainedSubgraph{
+		Root:     objectID,
+		RootSize: tree.RetainedSize(objectID),
+	}
+	graph.Nodes = append(graph.Nodes, &SubgraphNode{
+		ClassName:    root.ClassName,
+		Objects:      1,
+		ShallowSize:  root.ShallowSize,
+		RetainedSize: graph.RootSize,
+		Sample:       objectID,
+	})
+
+	minRetained := uint64(float64(graph.RootSize) * options.MinShare)
+
+	type group struct {
+		node    int
+		objects []model.ID
+	}
+	level := []group{{node: 0, objects: []model.ID{objectID}}}
+
+	for depth := 1; depth <= options.MaxDepth && len(level) > 0; depth++ {
+		var next []group
+
+		for _, parent := range level {
+			buckets := a.bucketChildrenByClass(parent.objects)
+
+			var merged *SubgraphNode
+			for i, bucket := range buckets {
+				if i >= options.MaxClasses || bucket.node.RetainedSize < minRetained {
+					if merged == nil {
+						// Buckets are sorted, so everything from here on is merged
+						name := fmt.Sprintf("%d other classes", len(buckets)-i)
+						if len(buckets)-i == 1 {
+							name = "1 other class"
+						}
+						merged = &SubgraphNode{
+							ClassName: name,
+							Depth:     depth,
+							Merged:    true,
+						}
+					}
+					merged.Objects += bucket.node.Objects
+					merged.ShallowSize += bucket.node.ShallowSize
+					merged.RetainedSize += bucket.node.RetainedSize
+					continue
+				}
+
+				bucket.node.Depth = depth
+				id := graph.addNode(bucket.node)
+				graph.Edges = append(graph.Edges, SubgraphEdge{From: parent.node, To: id, Objects: bucket.node.Objects})
+				next = append(next, group{node: id, objects: bucket.objects})
+			}
+
+			if merged != nil {
+				id := graph.addNode(merged)
+				graph.Edges = append(graph.Edges, SubgraphEdge{From: parent.node, To: id, Objects: merged.Objects})
+			}
+		}
+
+		level = next
+	}
+
+	a.addRootPath(graph)
+	return graph, nil
+}
+
+type classBucket struct {
+	node    *SubgraphNode
+	objects []model.ID
+}
+
+// bucketChildrenByClass groups the objects dominated by parents by class, largest retained first
+func (a *Analyzer) bucketChildrenByClass(parents []model.ID) []*classBucket {
+	byClass := make(map[string]*classBucket)
+	sampleRetained := make(map[string]uint64)
+
+	for _, parentID := range parents {
+		for _, childID := range a.DominatorTree.Children(parentID) {
+			object, _ := a.ctx.DescribeObject(childID)
+			retained := a.DominatorTree.RetainedSize(childID)
+
+			bucket, exists := byClass[object.ClassName]
+			if !exists {
+				bucket = &classBucket{node: &SubgraphNode{ClassName: object.ClassName}}
+				byClass[object.ClassName] = bucket
+			}
+			bucket.node.Objects++
+			bucket.node.ShallowSize += object.ShallowSize
+			bucket.node.RetainedSize += retained
+			bucket.objects = append(bucket.objects, childID)
+
+			if retained >= sampleRetained[object.ClassName] {
+				sampleRetained[object.ClassName] = retained
+				bucket.node.Sample = childID
+			}
+		}
+	}
+
+	buckets := make([]*classBucket, 0, len(byClass))
+	for _, bucket := range byClass {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].node.RetainedSize != buckets[j].node.RetainedSize {
+			return buckets[i].node.RetainedSize > buckets[j].node.RetainedSize
+		}
+		return buckets[i].node.ClassName < buckets[j].node.ClassName
+	})
+	return buckets
+}
+
+// addRootPath records the shortest reference chain from a GC root to the subgraph root
+func (a *Analyzer) addRootPath(graph *RetainedSubgraph) {
+	path := a.ShortestPathToRoot(graph.Root)
+	if len(path) == 0 {
+		return
+	}
+
+	if rootType, ok := a.ctx.RootReg.GetRootType(path[0]); ok {
+		graph.RootPathType = rootType.String()
+	}
+	for _, id := range path[:len(path)-1] {
+		object, _ := a.ctx.DescribeObject(id)
+		graph.RootPath = append(graph.RootPath, object)
+	}
+}
+
+func (g *RetainedSubgraph) addNode(node *SubgraphNode) int {
+	node.ID = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+	return node.ID
+}
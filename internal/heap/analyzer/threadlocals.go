@@ -0,0 +1,295 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+* ThreadLocal leaks
+*
+* Every Thread keeps its thread-local values in a ThreadLocalMap (the
+* threadLocals and inheritableThreadLocals fields): a table of entries that
+* hold their ThreadLocal weakly and their value strongly. A value set and
+* never removed lives as long as its thread, and in a pool that is as long
+* as the process. Two patterns are reported:
+*
+* 	stale		the ThreadLocal has been collected but its value is still
+* 				held, and is only freed if the map happens to expunge it
+* 	loader		the value, or the ThreadLocal, is of a class from a class
+* 				loader other than the JVM's own, typically a web app's. A
+* 				pooled container thread then keeps the whole loader, and
+* 				every class it loaded, alive after the app is undeployed
+*
+* Tomcat records a loader's lifecycle, so a value whose loader has already
+* been stopped is reported as outliving it.
+ */
+
+// Loaders that live as long as the JVM; values from them can't pin an application
+var systemClassLoaders = map[string]bool{
+	"jdk.internal.loader.ClassLoaders$AppClassLoader":      true,
+	"jdk.internal.loader.ClassLoaders$PlatformClassLoader": true,
+	"sun.misc.Launcher$AppClassLoader":                     true,
+	"sun.misc.Launcher$ExtClassLoader":                     true,
+}
+
+// Tomcat LifecycleState names from which a web app loader is done
+var stoppedLoaderStates = map[string]bool{"STOPPING": true, "STOPPED": true, "DESTROYING": true, "DESTROYED": true}
+
+// Thread name fragments of the executors and containers that keep threads for the process's lifetime
+var pooledThreadNames = []string{"-exec-", "pool-", "-thread-", "worker", "forkjoinpool", "executor", "qtp"}
+
+// ThreadLocalEntry is one value held in a thread's ThreadLocalMap
+type ThreadLocalEntry struct {
+	ThreadID    model.ID
+	ThreadName  string
+	Pooled      bool // The thread's name looks like an executor's or container's
+	Inheritable bool // From inheritableThreadLocals
+
+	EntryID      model.ID
+	KeyClass     string // The ThreadLocal's class; "" once it has been collected
+	ValueID      model.ID
+	ValueClass   string
+	RetainedSize uint64
+
+	Stale         bool   // The ThreadLocal was collected, the value wasn't
+	LoaderClass   string // Class loader of the value or ThreadLocal, when not the JVM's own
+	LoaderStopped bool   // That loader's application has been stopped
+}
+
+// ThreadLocalLeak groups the leaking entries of one kind across threads
+type ThreadLocalLeak struct {
+	KeyClass      string
+	ValueClass    string
+	LoaderClass   string
+	Stale         bool
+	LoaderStopped bool
+
+	Entries       int
+	Threads       int
+	PooledThreads int
+	RetainedSize  uint64
+	Largest       *ThreadLocalEntry
+}
+
+type ThreadLocalReport struct {
+	Threads      int // Threads with a non-empty ThreadLocalMap
+	Entries      int
+	RetainedSize uint64 // By every entry's value
+	Leaks        []*ThreadLocalLeak
+}
+
+// Describe names the pattern a leak follows
+func (l *ThreadLocalLeak) Describe() string {
+	switch {
+	case l.LoaderStopped:
+		return "outlives its stopped class loader " + l.LoaderClass
+	case l.LoaderClass != "":
+		return "pins class loader " + l.LoaderClass
+	default:
+		return "ThreadLocal collected, value still held"
+	}
+}
+
+// FindThreadLocalLeaks reads every thread's ThreadLocalMaps and groups the entries that leak
+func FindThreadLocalLeaks(ctx *AnalysisContext, tree *DominatorTree) *ThreadLocalReport {
+	report := &ThreadLocalReport{}
+	extractor := NewFieldExtractor(ctx)
+	isThread := make(map[model.ID]bool)
+	loaders := make(map[model.ID]string) // Class ID -> loader class, "" for the JVM's own
+	stopped := make(map[model.ID]bool)   // Loader ID -> stopped
+	leaks := make(map[string]*ThreadLocalLeak)
+	threadsOf := make(map[*ThreadLocalLeak]map[model.ID]bool)
+
+	for threadID, instance := range ctx.InstanceReg.GetAllInstances() {
+		thread, ok := isThread[instance.ClassObjectID]
+		if !ok {
+			thread = ctx.extendsClass(instance.ClassObjectID, "java.lang.Thread")
+			isThread[instance.ClassObjectID] = thread
+		}
+		if !thread {
+			continue
+		}
+
+		var entries []*ThreadLocalEntry
+		for _, field := range []string{"threadLocals", "inheritableThreadLocals"} {
+			table := extractor.ReadReference(extractor.ReadReference(threadID, field), "table")
+			array, ok := ctx.ArrayReg.GetObjectArray(table)
+			if !ok {
+				continue
+			}
+			for _, entryID := range array.Elements {
+				valueID := extractor.ReadReference(entryID, "value")
+				if valueID == 0 {
+					continue
+				}
+				entry := &ThreadLocalEntry{
+					ThreadID:    threadID,
+					Inheritable: field == "inheritableThreadLocals",
+					EntryID:     entryID,
+					ValueID:     valueID,
+					Stale:       true,
+				}
+				value, _ := ctx.DescribeObject(valueID)
+				entry.ValueClass = value.ClassName
+				if tree != nil {
+					entry.RetainedSize = tree.RetainedSize(valueID)
+				}
+
+				classes := []model.ID{classOfObject(ctx, valueID)}
+				if keyID := extractor.ReadReference(entryID, "referent"); keyID != 0 {
+					key, _ := ctx.DescribeObject(keyID)
+					entry.KeyClass, entry.Stale = key.ClassName, false
+					classes = append(classes, classOfObject(ctx, keyID))
+				}
+				for _, classID := range classes {
+					loaderID, loaderClass := ctx.applicationLoaderOf(classID, loaders)
+					if loaderClass == "" {
+						continue
+					}
+					entry.LoaderClass = loaderClass
+					if _, ok := stopped[loaderID]; !ok {
+						stopped[loaderID] = loaderStopped(extractor, loaderID)
+					}
+					entry.LoaderStopped = stopped[loaderID]
+					break
+				}
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		report.Threads++
+		name := threadName(extractor, threadID)
+		pooled := isPooledThreadName(name)
+		for _, entry := range entries {
+			entry.ThreadName, entry.Pooled = name, pooled
+			report.Entries++
+			report.RetainedSize += entry.RetainedSize
+			if !entry.Stale && entry.LoaderClass == "" {
+				continue
+			}
+
+			key := strings.Join([]string{entry.KeyClass, entry.ValueClass, entry.LoaderClass}, "\x00")
+			leak, ok := leaks[key]
+			if !ok {
+				leak = &ThreadLocalLeak{KeyClass: entry.KeyClass, ValueClass: entry.ValueClass, LoaderClass: entry.LoaderClass}
+				leaks[key] = leak
+				threadsOf[leak] = make(map[model.ID]bool)
+			}
+			leak.Stale = leak.Stale || entry.Stale
+			leak.LoaderStopped = leak.LoaderStopped || entry.LoaderStopped
+			leak.Entries++
+			leak.RetainedSize += entry.RetainedSize
+			if !threadsOf[leak][threadID] {
+				threadsOf[leak][threadID] = true
+				leak.Threads++
+				if pooled {
+					leak.PooledThreads++
+				}
+			}
+			if leak.Largest == nil || entry.RetainedSize > leak.Largest.RetainedSize {
+				leak.Largest = entry
+			}
+		}
+	}
+
+	for _, leak := range leaks {
+		report.Leaks = append(report.Leaks, leak)
+	}
+	sort.Slice(report.Leaks, func(i, j int) bool {
+		a, b := report.Leaks[i], report.Leaks[j]
+		if a.LoaderStopped != b.LoaderStopped {
+			return a.LoaderStopped
+		}
+		if a.RetainedSize != b.RetainedSize {
+			return a.RetainedSize > b.RetainedSize
+		}
+		return a.ValueClass < b.ValueClass
+	})
+
+	return report
+}
+
+// extendsClass reports whether a class is className or one of its subclasses
+func (ctx *AnalysisContext) extendsClass(classID model.ID, className string) bool {
+	for classID != 0 {
+		if ctx.ClassName(classID) == className {
+			return true
+		}
+		classDump, ok := ctx.ClassDumpReg.GetClassDump(classID)
+		if !ok {
+			return false
+		}
+		classID = classDump.SuperClassObjectID
+	}
+	return false
+}
+
+// applicationLoaderOf returns the loader that defined a class when it isn't the bootstrap or a system loader
+func (ctx *AnalysisContext) applicationLoaderOf(classID model.ID, cache map[model.ID]string) (model.ID, string) {
+	classDump, ok := ctx.ClassDumpReg.GetClassDump(classID)
+	if !ok || classDump.ClassLoaderObjectID == 0 {
+		return 0, ""
+	}
+	loaderClass, ok := cache[classID]
+	if !ok {
+		loader, _ := ctx.DescribeObject(classDump.ClassLoaderObjectID)
+		if !systemClassLoaders[loader.ClassName] {
+			loaderClass = loader.ClassName
+		}
+		cache[classID] = loaderClass
+	}
+	return classDump.ClassLoaderObjectID, loaderClass
+}
+
+// classOfObject is an instance's or array's class; 0 for anything else
+func classOfObject(ctx *AnalysisContext, objectID model.ID) model.ID {
+	if instance, ok := ctx.InstanceReg.GetInstance(objectID); ok {
+		return instance.ClassObjectID
+	}
+	if array, ok := ctx.ArrayReg.GetObjectArray(objectID); ok {
+		return array.ClassID
+	}
+	return 0
+}
+
+// loaderStopped reads a Tomcat web app loader's lifecycle: its state enum, or the started flag of older versions
+func loaderStopped(extractor *FieldExtractor, loaderID model.ID) bool {
+	if state := extractor.ReadReference(loaderID, "state"); state != 0 {
+		name, _ := extractor.ReadString(extractor.ReadReference(state, "name"))
+		return stoppedLoaderStates[name]
+	}
+	if started, ok := extractor.ReadField(loaderID, "started"); ok {
+		if value, ok := started.Value.(bool); ok {
+			return !value
+		}
+	}
+	return false
+}
+
+// threadName decodes Thread.name: a String, or a char[] before JDK 8
+func threadName(extractor *FieldExtractor, threadID model.ID) string {
+	nameID := extractor.ReadReference(threadID, "name")
+	if name, ok := extractor.ReadString(nameID); ok {
+		return name
+	}
+	if name, ok := extractor.ctx.ArrayReg.GetCharArray(nameID); ok {
+		return name
+	}
+	return "<unnamed>"
+}
+
+func isPooledThreadName(name string) bool {
+	name = strings.ToLower(name)
+	for _, fragment := range pooledThreadNames {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
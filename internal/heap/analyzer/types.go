@@ -2,12 +2,15 @@ package analyzer
 
 import (
 	"fmt"
+	"iter"
+	"maps"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 )
 
 // ReferenceMap tracks bidirectional object references for graph traversal
-// This matches VisualVM's approach but uses in-memory maps instead of file offsets
+// This matches VisualVM's approach but uses in-memory maps instead of file offsets,
+// unless the analysis is over its memory budget and the references are spilled to disk
 type ReferenceMap struct {
 	// Forward references: ID -> list of objects it references
 	ForwardRefs map[model.ID][]model.ID
@@ -15,64 +18,106 @@ type ReferenceMap struct {
 	// Backward references: ID -> list of objects that reference it
 	BackwardRefs map[model.ID][]model.ID
 
-	// Reference counts for quick lookups
-	OutgoingCount map[model.ID]int
-	IncomingCount map[model.ID]int
+	// Disk-backed references instead of the maps, when spilled
+	spilled *spilledReferences
 }
 
 // NewReferenceMap creates a new bidirectional reference map
 func NewReferenceMap() *ReferenceMap {
 	return &ReferenceMap{
-		ForwardRefs:   make(map[model.ID][]model.ID),
-		BackwardRefs:  make(map[model.ID][]model.ID),
-		OutgoingCount: make(map[model.ID]int),
-		IncomingCount: make(map[model.ID]int),
+		ForwardRefs:  make(map[model.ID][]model.ID),
+		BackwardRefs: make(map[model.ID][]model.ID),
 	}
 }
 
 // AddReference adds a reference from source to target object
 func (rm *ReferenceMap) AddReference(source, target model.ID) {
+	if rm.spilled != nil {
+		rm.spilled.add(source, target)
+		return
+	}
+
 	// Add forward reference
 	rm.ForwardRefs[source] = append(rm.ForwardRefs[source], target)
-	rm.OutgoingCount[source]++
 
 	// Add backward reference
 	rm.BackwardRefs[target] = append(rm.BackwardRefs[target], source)
-	rm.IncomingCount[target]++
+}
+
+// finish makes spilled references readable once every reference has been added
+func (rm *ReferenceMap) finish() error {
+	if rm.spilled == nil {
+		return nil
+	}
+	return rm.spilled.finish()
+}
+
+// IsSpilled reports whether the references are kept on disk instead of in memory
+func (rm *ReferenceMap) IsSpilled() bool {
+	return rm.spilled != nil
 }
 
 // GetReferences returns all objects that the given object references
 func (rm *ReferenceMap) GetReferences(objectID model.ID) []model.ID {
+	if rm.spilled != nil {
+		return rm.spilled.references(objectID)
+	}
 	return rm.ForwardRefs[objectID]
 }
 
 // GetReferrers returns all objects that reference the given object
 func (rm *ReferenceMap) GetReferrers(objectID model.ID) []model.ID {
+	if rm.spilled != nil {
+		return rm.spilled.referrers(objectID)
+	}
 	return rm.BackwardRefs[objectID]
 }
 
+// AllReferences yields every object that references others, with the objects it references
+func (rm *ReferenceMap) AllReferences() iter.Seq2[model.ID, []model.ID] {
+	if rm.spilled != nil {
+		return rm.spilled.all(rm.spilled.references)
+	}
+	return maps.All(rm.ForwardRefs)
+}
+
+// AllReferrers yields every referenced object, with the objects that reference it
+func (rm *ReferenceMap) AllReferrers() iter.Seq2[model.ID, []model.ID] {
+	if rm.spilled != nil {
+		return rm.spilled.all(rm.spilled.referrers)
+	}
+	return maps.All(rm.BackwardRefs)
+}
+
 // HasReferences checks if an object has any outgoing references
 func (rm *ReferenceMap) HasReferences(objectID model.ID) bool {
-	return rm.OutgoingCount[objectID] > 0
+	return len(rm.GetReferences(objectID)) > 0
 }
 
 // HasReferrers checks if an object has any incoming references
 func (rm *ReferenceMap) HasReferrers(objectID model.ID) bool {
-	return rm.IncomingCount[objectID] > 0
+	return len(rm.GetReferrers(objectID)) > 0
 }
 
 // GetStatistics returns comprehensive statistics about the reference map
 func (rm *ReferenceMap) GetStatistics() map[string]interface{} {
 	stats := make(map[string]interface{})
 
+	objectsWithReferences := 0
 	totalForwardRefs := 0
-	for _, refs := range rm.ForwardRefs {
+	for _, refs := range rm.AllReferences() {
+		objectsWithReferences++
 		totalForwardRefs += len(refs)
 	}
 
-	stats["objects_with_references"] = len(rm.ForwardRefs)
+	referencedObjects := 0
+	for range rm.AllReferrers() {
+		referencedObjects++
+	}
+
+	stats["objects_with_references"] = objectsWithReferences
 	stats["total_forward_references"] = totalForwardRefs
-	stats["total_backward_references"] = len(rm.BackwardRefs)
+	stats["total_backward_references"] = referencedObjects
 
 	return stats
 }
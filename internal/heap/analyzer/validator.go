@@ -50,7 +50,7 @@ func (v *ValidatorFinal) ValidateReferences() (*ValidationResult, error) {
 		Valid: true,
 	}
 
-	fmt.Println("🔍 Phase 11.1: Validating object references...")
+	fmt.Fprintln(v.ctx.Out, "🔍 Phase 11.1: Validating object references...")
 
 	// Build existence maps for validation
 	objectExists, stringExists := v.buildExistenceMaps()
@@ -94,14 +94,14 @@ func (v *ValidatorFinal) validateAllReferences(result *ValidationResult, objectE
 	}
 
 	for _, step := range validationSteps {
-		fmt.Printf("  Validating %s...\n", step.name)
+		fmt.Fprintf(v.ctx.Out, "  Validating %s...\n", step.name)
 		if err := step.fn(result, objectExists); err != nil {
 			return fmt.Errorf("failed to validate %s: %w", step.name, err)
 		}
 	}
 
 	// Validate class references (uses both object and string existence maps)
-	fmt.Println("  Validating class references...")
+	fmt.Fprintln(v.ctx.Out, "  Validating class references...")
 	if err := v.validateClassReferences(result, objectExists, stringExists); err != nil {
 		return fmt.Errorf("failed to validate class references: %w", err)
 	}
@@ -140,7 +140,8 @@ func (v *ValidatorFinal) buildObjectExistenceMap() map[model.ID]bool {
 func (v *ValidatorFinal) buildStringExistenceMap() map[model.ID]bool {
 	stringExists := make(map[model.ID]bool)
 
-	for stringID := range v.ctx.StringReg.GetAllStrings() {
+	// Only the IDs are needed; reading the text would defeat lazy string loading
+	for _, stringID := range v.ctx.StringReg.GetIDs() {
 		stringExists[stringID] = true
 	}
 
@@ -309,22 +310,22 @@ func (v *ValidatorFinal) extractObjectIDFromStaticField(staticField *model.Stati
 
 // printValidationSummary prints a summary of validation results
 func (v *ValidatorFinal) printValidationSummary(result *ValidationResult) {
-	fmt.Printf("  Validation completed:\n")
-	fmt.Printf("    Total references checked: %d\n", result.TotalRefs)
-	fmt.Printf("    Valid references: %d\n", result.ValidRefs)
+	fmt.Fprintf(v.ctx.Out, "  Validation completed:\n")
+	fmt.Fprintf(v.ctx.Out, "    Total references checked: %d\n", result.TotalRefs)
+	fmt.Fprintf(v.ctx.Out, "    Valid references: %d\n", result.ValidRefs)
 
 	if result.TotalRefs > 0 {
 		integrity := float64(result.ValidRefs) / float64(result.TotalRefs) * 100
-		fmt.Printf("    Reference integrity: %.2f%%\n", integrity)
+		fmt.Fprintf(v.ctx.Out, "    Reference integrity: %.2f%%\n", integrity)
 	}
 
 	if len(result.MissingObjects) > 0 {
-		fmt.Printf("    Missing objects: %d\n", len(result.MissingObjects))
+		fmt.Fprintf(v.ctx.Out, "    Missing objects: %d\n", len(result.MissingObjects))
 	}
 
 	status := "✅"
 	if !result.Valid {
 		status = "⚠️"
 	}
-	fmt.Printf("    %s Validation complete\n", status)
+	fmt.Fprintf(v.ctx.Out, "    %s Validation complete\n", status)
 }
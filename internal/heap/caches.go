@@ -0,0 +1,173 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const maxCaches = 20
+
+// RunHeapCaches prints the cache-like structures in a dump; given a baseline dump, with their growth since
+func RunHeapCaches(filename, baselineFile string, config *Config) error {
+	var baseline []*analyzer.Cache
+	if baselineFile != "" {
+		var err error
+		if baseline, err = findDumpCaches(baselineFile, config); err != nil {
+			return fmt.Errorf("baseline %s: %w", baselineFile, err)
+		}
+	}
+
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	tree := heapAnalyzer.GetDominatorTree()
+	caches := analyzer.FindCaches(heapAnalyzer.GetContext(), tree)
+	if baselineFile != "" {
+		analyzer.CompareCaches(caches, baseline)
+	}
+	printCaches(caches, tree.TotalRetainedSize(), baselineFile != "")
+	return nil
+}
+
+// findDumpCaches analyzes a dump only for its caches, closing it before the next one is parsed
+func findDumpCaches(filename string, config *Config) ([]*analyzer.Cache, error) {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return nil, err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	return analyzer.FindCaches(heapAnalyzer.GetContext(), heapAnalyzer.GetDominatorTree()), nil
+}
+
+func printCaches(caches []*analyzer.Cache, reachable uint64, compared bool) {
+	fmt.Println()
+	fmt.Println("🗄️  CACHES")
+	if len(caches) == 0 {
+		fmt.Println("No Guava or Caffeine caches, and no large maps that look like caches")
+		return
+	}
+
+	var total uint64
+	for _, cache := range caches {
+		total += cache.RetainedSize
+	}
+	summary := fmt.Sprintf("%s caches retain %s", utils.FormatCount(int64(len(caches))), utils.MemorySize(total).String())
+	if reachable > 0 {
+		summary += fmt.Sprintf(" (%s of the reachable heap)", utils.FormatPercent(float64(total)/float64(reachable)*100))
+	}
+	fmt.Println(summary)
+	fmt.Println()
+
+	header := fmt.Sprintf("%10s  %10s  %-24s  %8s  %-8s  %s", "Retained", "Entries", "Bound", "Hit rate", "Kind", "Cache")
+	if compared {
+		header = fmt.Sprintf("%10s  %10s  %18s  %-24s  %8s  %-8s  %s", "Retained", "Entries", "Growth", "Bound", "Hit rate", "Kind", "Cache")
+	}
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("─", 106))
+
+	unbounded := 0
+	for _, cache := range caches[:min(len(caches), maxCaches)] {
+		name := cache.Holder
+		if name == "" {
+			name = cache.ClassName
+		}
+		hitRate := "-"
+		if rate, ok := cache.HitRate(); ok {
+			hitRate = utils.FormatPercent(rate * 100)
+		}
+
+		var line string
+		if compared {
+			line = fmt.Sprintf("%10s  %10s  %18s  %-24s  %8s  %-8s  %s", utils.MemorySize(cache.RetainedSize).String(),
+				utils.FormatCount(cache.Entries), cacheGrowth(cache), cacheBound(cache), hitRate, cache.Kind, name)
+		} else {
+			line = fmt.Sprintf("%10s  %10s  %-24s  %8s  %-8s  %s", utils.MemorySize(cache.RetainedSize).String(),
+				utils.FormatCount(cache.Entries), cacheBound(cache), hitRate, cache.Kind, name)
+		}
+
+		suggestion := cache.Suggestion()
+		if suggestion == "" {
+			fmt.Println(line)
+			continue
+		}
+		if !cache.Bounded() {
+			unbounded++
+		}
+		fmt.Println(utils.WarningStyle.Render(line))
+		fmt.Println(utils.MutedStyle.Render("            " + suggestion))
+	}
+	if len(caches) > maxCaches {
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("... and %d more caches", len(caches)-maxCaches)))
+	}
+
+	fmt.Println()
+	if unbounded > 0 {
+		verb := "caches have"
+		if unbounded == 1 {
+			verb = "cache has"
+		}
+		fmt.Println(utils.InfoStyle.Render(fmt.Sprintf(
+			"💡 %s %s no bound: growing with every distinct key until the heap runs out", utils.FormatCount(int64(unbounded)), verb)))
+	}
+	if !compared {
+		fmt.Println(utils.MutedStyle.Render("Pass an earlier dump of the same process first to see how much each cache grew"))
+	}
+}
+
+// cacheBound describes how a cache evicts: its maximum and expiry, or the LRU idiom
+func cacheBound(cache *analyzer.Cache) string {
+	var bounds []string
+	if cache.MaximumSize >= 0 {
+		bounds = append(bounds, "max "+utils.FormatCount(cache.MaximumSize))
+	}
+	if cache.ExpireAfterWrite > 0 {
+		bounds = append(bounds, "write "+utils.FormatDuration(time.Duration(cache.ExpireAfterWrite)))
+	}
+	if cache.ExpireAfterAccess > 0 {
+		bounds = append(bounds, "access "+utils.FormatDuration(time.Duration(cache.ExpireAfterAccess)))
+	}
+	switch {
+	case len(bounds) > 0:
+		return strings.Join(bounds, ", ")
+	case cache.EvictingSubclass:
+		return "removeEldestEntry"
+	case cache.LRU:
+		return "LRU, no bound"
+	default:
+		return "none"
+	}
+}
+
+// cacheGrowth is the change in retained size and entries since the baseline dump
+func cacheGrowth(cache *analyzer.Cache) string {
+	if cache.Baseline == nil {
+		return "new"
+	}
+	retained := int64(cache.RetainedSize) - int64(cache.Baseline.RetainedSize)
+	entries := cache.Entries - cache.Baseline.Entries
+	return signedBytes(retained) + ", " + signedCount(entries)
+}
+
+func signedBytes(n int64) string {
+	if n < 0 {
+		return "-" + utils.FormatBytes(-n)
+	}
+	return "+" + utils.FormatBytes(n)
+}
+
+func signedCount(n int64) string {
+	if n < 0 {
+		return "-" + utils.FormatCount(-n)
+	}
+	return "+" + utils.FormatCount(n)
+}
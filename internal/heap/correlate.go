@@ -0,0 +1,315 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/registry"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	growthExplainedRatio = 0.5 // Share of the GC log's growth the suspects must retain to explain it
+	correlatedSites      = 5   // Allocation sites listed per suspect
+	minShortLogEvents    = 5   // Collections needed to fit a trend to a log the GC analyzer considers too short
+)
+
+// LeakCorrelation compares the heap growth seen in a GC log with what the dump retains
+type LeakCorrelation struct {
+	Trend          gc.MemoryTrend
+	ShortLog       bool             // Trend fitted here because the log is below the GC analyzer's minimum
+	ObservedGrowth uint64           // Heap growth over the trend window (rate × sample period)
+	LastHeapAfter  utils.MemorySize // Heap occupancy after the last collection in the log
+	ReachableHeap  uint64
+
+	Suspects  []*CorrelatedSuspect
+	Explained uint64 // Growth accounted for by the suspects, without double counting
+	HasSites  bool   // At least one suspect has allocation sites
+
+	Severity string // "critical", "warning", "info" or "good"
+	Verdict  string
+	Notes    []string
+}
+
+// CorrelatedSuspect is a leak suspect measured against the GC log's growth rate
+type CorrelatedSuspect struct {
+	Suspect     *analyzer.LeakSuspect
+	GrowthShare float64       // Retained size / observed growth
+	GrowthTime  time.Duration // Time the logged growth rate needs to accumulate the retained size
+	Sites       []*analyzer.AllocationSite
+}
+
+// RunHeapCorrelate checks a GC log's leak trend against the leak suspects in a heap dump
+func RunHeapCorrelate(filename, gcLogFile string, config *Config) error {
+	fmt.Printf("📜 Parsing GC log: %s\n", gcLogFile)
+	events, gcAnalysis, err := gc.NewParser().ParseFile(gcLogFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse GC log: %w", err)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no GC events found in %s", gcLogFile)
+	}
+	gc.AnalyzeGCLogs(events, gcAnalysis)
+
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	correlation := CorrelateLeak(events, gcAnalysis, heapAnalyzer, parser.GetStackRegistry())
+	printLeakCorrelation(correlation)
+	return nil
+}
+
+/*
+ * CorrelateLeak measures each leak suspect against the heap growth rate from a GC log.
+ *
+ * The GC log says how fast the live set grows (heap after GC, by linear
+ * regression); the dump says what the live set is made of. A suspect that
+ * retains at least half of the growth observed over the log is a credible
+ * explanation for it, and retained size / growth rate estimates how long the
+ * suspect has been accumulating.
+ *
+ * HPROF has no allocation timestamps, so "recent" memory can only be tied to
+ * code through allocation traces. When the dump has them, each suspect lists the
+ * frames that allocated its retained objects.
+ */
+func CorrelateLeak(events []*gc.GCEvent, gcAnalysis *gc.GCAnalysis, heapAnalyzer *analyzer.Analyzer,
+	stacks *registry.StackRegistry) *LeakCorrelation {
+
+	ctx := heapAnalyzer.GetContext()
+	tree := heapAnalyzer.GetDominatorTree()
+
+	correlation := &LeakCorrelation{Trend: gcAnalysis.MemoryTrend}
+	if correlation.Trend.EventCount == 0 {
+		correlation.Trend, correlation.ShortLog = shortLogTrend(events)
+	}
+	if tree != nil {
+		correlation.ReachableHeap = tree.TotalRetainedSize()
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].HeapAfter > 0 {
+			correlation.LastHeapAfter = events[i].HeapAfter
+			break
+		}
+	}
+
+	trend := correlation.Trend
+	if trend.GrowthRateMBPerHour > 0 {
+		growth := trend.GrowthRateMBPerHour * trend.SamplePeriod.Hours() * float64(utils.MB)
+		correlation.ObservedGrowth = uint64(growth)
+	}
+
+	var objectSuspectsSize, largestClassSuspect uint64
+	for _, suspect := range heapAnalyzer.GetLeakSuspects() {
+		correlated := &CorrelatedSuspect{Suspect: suspect}
+		if correlation.ObservedGrowth > 0 {
+			correlated.GrowthShare = float64(suspect.RetainedSize) / float64(correlation.ObservedGrowth)
+			hours := utils.MemorySize(suspect.RetainedSize).MB() / trend.GrowthRateMBPerHour
+			correlated.GrowthTime = time.Duration(hours * float64(time.Hour))
+		}
+		if tree != nil {
+			correlated.Sites = analyzer.AllocationSitesWithin(ctx, stacks, tree, suspectScope(ctx, suspect), correlatedSites)
+			correlation.HasSites = correlation.HasSites || len(correlated.Sites) > 0
+		}
+		correlation.Suspects = append(correlation.Suspects, correlated)
+
+		// Object suspects are disjoint top-level dominators; class suspects may overlap them
+		if suspect.Kind == analyzer.SingleObjectSuspect {
+			objectSuspectsSize += suspect.RetainedSize
+		} else {
+			largestClassSuspect = max(largestClassSuspect, suspect.RetainedSize)
+		}
+	}
+	correlation.Explained = max(objectSuspectsSize, largestClassSuspect)
+
+	assessCorrelation(correlation)
+	return correlation
+}
+
+// shortLogTrend fits heap-after-GC over time for logs too short for the GC analyzer's trend
+func shortLogTrend(events []*gc.GCEvent) (gc.MemoryTrend, bool) {
+	var hours, heapAfter []float64
+	var first, last time.Time
+	for _, event := range events {
+		if event.HeapAfter <= 0 {
+			continue
+		}
+		if first.IsZero() {
+			first = event.Timestamp
+		}
+		last = event.Timestamp
+		hours = append(hours, event.Timestamp.Sub(first).Hours())
+		heapAfter = append(heapAfter, event.HeapAfter.MB())
+	}
+
+	if len(hours) < minShortLogEvents || !last.After(first) {
+		return gc.MemoryTrend{}, false
+	}
+
+	slope, correlation := utils.LinearRegression(hours, heapAfter)
+	return gc.MemoryTrend{
+		GrowthRateMBPerHour: slope,
+		TrendConfidence:     correlation * correlation,
+		SamplePeriod:        last.Sub(first),
+		EventCount:          len(hours),
+	}, true
+}
+
+// suspectScope matches the objects whose retained sets make up a suspect
+func suspectScope(ctx *analyzer.AnalysisContext, suspect *analyzer.LeakSuspect) func(model.ID) bool {
+	if suspect.Kind == analyzer.SingleObjectSuspect {
+		return func(objectID model.ID) bool { return objectID == suspect.ObjectID }
+	}
+	return func(objectID model.ID) bool {
+		object, _ := ctx.DescribeObject(objectID)
+		return object.ClassName == suspect.ClassName
+	}
+}
+
+func assessCorrelation(c *LeakCorrelation) {
+	trend := c.Trend
+
+	switch {
+	case trend.EventCount == 0:
+		c.Severity = "info"
+		c.Verdict = fmt.Sprintf("The GC log is too short to measure heap growth (needs at least %d collections).",
+			minShortLogEvents)
+
+	case trend.GrowthRateMBPerHour <= 0 || trend.TrendConfidence <= gc.LeakConfidenceThreshold:
+		c.Severity = "good"
+		c.Verdict = fmt.Sprintf("The GC log shows no sustained heap growth (%s/h, %s confidence). "+
+			"Suspects in the dump are more likely caches or working set than a leak.",
+			utils.Precision(2).MB(trend.GrowthRateMBPerHour), utils.Precision(0).Percent(trend.TrendConfidence*100))
+
+	case len(c.Suspects) == 0:
+		c.Severity = "warning"
+		c.Verdict = fmt.Sprintf("The heap grows by %s/h but no single object or class in the dump stands out. "+
+			"The growth may be spread across many small owners, or outside the Java heap.",
+			utils.Precision(2).MB(trend.GrowthRateMBPerHour))
+
+	case c.Suspects[0].GrowthShare >= growthExplainedRatio:
+		top := c.Suspects[0].Suspect
+		c.Severity = "critical"
+		c.Verdict = fmt.Sprintf("Leak confirmed: %s retains %s, %s the %s the heap grew by over the GC log.",
+			top.ClassName, utils.MemorySize(top.RetainedSize).String(),
+			describeGrowthShare(c.Suspects[0].GrowthShare), utils.MemorySize(c.ObservedGrowth).String())
+
+	case float64(c.Explained) >= float64(c.ObservedGrowth)*growthExplainedRatio:
+		c.Severity = "warning"
+		c.Verdict = fmt.Sprintf("Likely leak: no single suspect explains the growth, but together they retain %s of the %s growth.",
+			utils.MemorySize(c.Explained).String(), utils.MemorySize(c.ObservedGrowth).String())
+
+	default:
+		c.Severity = "warning"
+		c.Verdict = fmt.Sprintf("Growth not explained by the dump: the suspects retain %s of the %s growth. "+
+			"The dump may predate most of the growth, or the memory may be off-heap or in metaspace.",
+			utils.MemorySize(c.Explained).String(), utils.MemorySize(c.ObservedGrowth).String())
+	}
+
+	// A dump far smaller than the log's live set probably isn't from the same run
+	if c.LastHeapAfter > 0 && c.ReachableHeap > 0 && float64(c.ReachableHeap) < float64(c.LastHeapAfter)*0.5 {
+		c.Notes = append(c.Notes, fmt.Sprintf("The dump's reachable heap (%s) is less than half the heap after the last GC in the log (%s); check that both come from the same JVM run.",
+			utils.MemorySize(c.ReachableHeap).String(), c.LastHeapAfter.String()))
+	}
+	if c.ShortLog {
+		c.Notes = append(c.Notes, fmt.Sprintf("The GC log covers only %s; the growth rate is extrapolated from a short run.",
+			utils.FormatDuration(trend.SamplePeriod)))
+	}
+	if len(c.Suspects) > 0 && !c.HasSites {
+		c.Notes = append(c.Notes, "The dump has no allocation traces, so suspects can't be tied to allocating code.")
+	}
+}
+
+func describeGrowthShare(share float64) string {
+	if share > 1 {
+		return "more than" // The leak predates the start of the log
+	}
+	return fmt.Sprintf("%s of", utils.Precision(0).Percent(share*100))
+}
+
+func printLeakCorrelation(c *LeakCorrelation) {
+	trend := c.Trend
+
+	fmt.Println()
+	fmt.Println("🔗 GC LOG × HEAP DUMP LEAK CORRELATION")
+	fmt.Println(strings.Repeat("─", 80))
+
+	fmt.Println("📈 GC log")
+	if trend.EventCount > 0 {
+		fmt.Printf("   Growth rate:      %s/h (%s confidence, %d collections over %s)\n",
+			utils.Precision(2).MB(trend.GrowthRateMBPerHour), utils.Precision(0).Percent(trend.TrendConfidence*100), trend.EventCount, utils.FormatDuration(trend.SamplePeriod))
+		if c.ObservedGrowth > 0 {
+			fmt.Printf("   Observed growth:  %s\n", utils.MemorySize(c.ObservedGrowth).String())
+		}
+		if trend.ProjectedFullHeapTime > 0 {
+			fmt.Printf("   Heap full in:     %s at this rate\n", utils.FormatDuration(trend.ProjectedFullHeapTime))
+		}
+	} else {
+		fmt.Println("   Growth rate:      not enough collections for a trend")
+	}
+	if c.LastHeapAfter > 0 {
+		fmt.Printf("   Heap after GC:    %s (last collection)\n", c.LastHeapAfter.String())
+	}
+
+	fmt.Println()
+	fmt.Println("🧊 Heap dump")
+	fmt.Printf("   Reachable heap:   %s\n", utils.MemorySize(c.ReachableHeap).String())
+	fmt.Printf("   Leak suspects:    %d\n", len(c.Suspects))
+
+	if len(c.Suspects) > 0 {
+		fmt.Println()
+		fmt.Printf("%4s  %10s  %12s  %12s  %s\n", "#", "Retained", "Of growth", "Growth time", "Suspect")
+		fmt.Println(strings.Repeat("─", 80))
+
+		for i, correlated := range c.Suspects {
+			suspect := correlated.Suspect
+
+			share, elapsed := "-", "-"
+			if c.ObservedGrowth > 0 {
+				share = utils.Precision(0).Percent(correlated.GrowthShare * 100)
+				elapsed = utils.FormatDuration(correlated.GrowthTime)
+			}
+
+			name := suspect.ClassName
+			if suspect.Kind == analyzer.ClassGroupSuspect {
+				name = fmt.Sprintf("%d × %s", suspect.InstanceCount, suspect.ClassName)
+			}
+
+			line := fmt.Sprintf("%4d  %10s  %12s  %12s  %s", i+1,
+				utils.MemorySize(suspect.RetainedSize).String(), share, elapsed, name)
+			fmt.Println(utils.GetSeverityStyle(correlatedSeverity(correlated.GrowthShare)).Render(line))
+
+			if suspect.AccumulationField != "" {
+				fmt.Println(utils.MutedStyle.Render("      Held by " + suspect.AccumulationField))
+			}
+			for _, site := range correlated.Sites {
+				fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("      %10s  %6d objects  allocated at %s",
+					utils.MemorySize(site.ShallowSize).String(), site.Objects, site.Frame)))
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%s %s\n", utils.GetSeverityIcon(c.Severity), utils.GetSeverityStyle(c.Severity).Render(c.Verdict))
+	for _, note := range c.Notes {
+		fmt.Println(utils.MutedStyle.Render("   • " + note))
+	}
+}
+
+func correlatedSeverity(share float64) string {
+	switch {
+	case share >= growthExplainedRatio:
+		return "critical"
+	case share > 0:
+		return "warning"
+	default:
+		return "info"
+	}
+}
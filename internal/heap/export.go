@@ -0,0 +1,114 @@
+package heap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/export"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// ExportConfig selects the export format and how many objects to include
+type ExportConfig struct {
+	Format      string // "json" or "parquet"
+	OutPath     string // JSON file or Parquet directory (default derived from the dump name)
+	MaxObjects  int    // 0 exports every reachable object
+	MinRetained uint64
+}
+
+// RunHeapExport writes the class histogram, dominator tree and object summaries in a structured format
+func RunHeapExport(filename string, config *Config, exportConfig *ExportConfig) error {
+	result, err := BuildExport(filename, config, export.Options{
+		MaxObjects:  exportConfig.MaxObjects,
+		MinRetained: exportConfig.MinRetained,
+	})
+	if err != nil {
+		return err
+	}
+
+	outPath := exportConfig.OutPath
+	if outPath == "" {
+		outPath = defaultExportPath(filename, exportConfig.Format)
+	}
+
+	switch exportConfig.Format {
+	case export.FormatJSON:
+		if err := export.WriteJSONFile(outPath, result); err != nil {
+			return err
+		}
+		fmt.Printf("📤 Exported heap analysis to %s\n", outPath)
+
+	case export.FormatParquet:
+		files, err := export.WriteParquet(outPath, result)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("📤 Exported heap analysis to %s/\n", outPath)
+		for _, file := range files {
+			fmt.Printf("   %s\n", file)
+		}
+
+	default:
+		return fmt.Errorf("unsupported export format: %s", exportConfig.Format)
+	}
+
+	fmt.Printf("   %d classes, %d objects (%s reachable), %d leak suspects\n",
+		len(result.Histogram), len(result.Objects),
+		utils.MemorySize(result.Summary.ReachableSize).String(), len(result.LeakSuspects))
+
+	return nil
+}
+
+// BuildExport analyzes a heap dump into its structured export form
+func BuildExport(filename string, config *Config, options export.Options) (*export.HeapExport, error) {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return nil, err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	return buildExport(filename, parser, heapAnalyzer, options)
+}
+
+// BuildExportContext is BuildExport on AnalyzeHeapDumpContext, for callers without a terminal
+func BuildExportContext(ctx context.Context, filename string, config *Config, options export.Options) (*export.HeapExport, error) {
+	parser, heapAnalyzer, err := AnalyzeHeapDumpContext(ctx, filename, config)
+	if err != nil {
+		return nil, err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	return buildExport(filename, parser, heapAnalyzer, options)
+}
+
+func buildExport(filename string, parser *parser.Parser, heapAnalyzer *analyzer.Analyzer, options export.Options) (*export.HeapExport, error) {
+	dump := export.DumpInfo{
+		File:      filename,
+		Truncated: parser.IsTruncated(),
+	}
+	if header := parser.GetHeader(); header != nil {
+		dump.Format = header.Format
+		dump.IdentifierSize = header.IdentifierSize
+		dump.Timestamp = header.Timestamp
+	}
+
+	result, err := export.Build(heapAnalyzer, dump, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build export: %w", err)
+	}
+	return result, nil
+}
+
+// defaultExportPath names the output after the dump: dump.json or dump-export/ for Parquet
+func defaultExportPath(filename, format string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(filename, ".gz"), ".hprof")
+	if format == export.FormatParquet {
+		return base + "-export"
+	}
+	return base + "." + format
+}
@@ -0,0 +1,229 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+
+	maxRootPathNodes = 6 // Longer GC root chains keep both ends and elide the middle
+)
+
+var GraphFormats = []string{FormatDOT, FormatMermaid}
+
+// Fill colors by share of the subgraph root's retained size
+const (
+	criticalFill = "#f8d7da"
+	warningFill  = "#fff3cd"
+	normalFill   = "#e8f4fd"
+	mergedFill   = "#eeeeee"
+	pathFill     = "#ffffff"
+)
+
+// graphNode is a renderer-neutral node: subgraph groups and root path objects
+type graphNode struct {
+	id     string
+	label  []string
+	fill   string
+	dashed bool
+}
+
+type graphEdge struct {
+	from, to string
+	label    string
+	dashed   bool
+}
+
+// WriteGraph renders a retained subgraph as GraphViz DOT or Mermaid
+func WriteGraph(w io.Writer, graph *analyzer.RetainedSubgraph, format string) error {
+	nodes, edges := layoutGraph(graph)
+
+	switch format {
+	case FormatDOT:
+		return writeDOT(w, nodes, edges)
+	case FormatMermaid:
+		return writeMermaid(w, nodes, edges)
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+}
+
+func layoutGraph(graph *analyzer.RetainedSubgraph) ([]graphNode, []graphEdge) {
+	var nodes []graphNode
+	var edges []graphEdge
+
+	// GC root chain leading to the subgraph root, drawn dashed above it
+	if graph.RootPathType != "" {
+		nodes = append(nodes, graphNode{id: "root", label: []string{"GC root", graph.RootPathType}, fill: pathFill, dashed: true})
+
+		previous := "root"
+		path := graph.RootPath
+		elided := 0
+		if len(path) > maxRootPathNodes {
+			elided = len(path) - maxRootPathNodes
+			path = append(path[:maxRootPathNodes/2:maxRootPathNodes/2], path[len(path)-maxRootPathNodes/2:]...)
+		}
+
+		for i, object := range path {
+			id := fmt.Sprintf("p%d", i)
+			nodes = append(nodes, graphNode{id: id, label: []string{object.DisplayName()}, fill: pathFill, dashed: true})
+			label := ""
+			if elided > 0 && i == maxRootPathNodes/2 {
+				label = fmt.Sprintf("%d more hops", elided)
+			}
+			edges = append(edges, graphEdge{from: previous, to: id, label: label, dashed: true})
+			previous = id
+		}
+		edges = append(edges, graphEdge{from: previous, to: "n0", label: "references", dashed: true})
+	}
+
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, graphNode{
+			id:    fmt.Sprintf("n%d", node.ID),
+			label: subgraphLabel(graph, node),
+			fill:  subgraphFill(graph, node),
+		})
+	}
+	for _, edge := range graph.Edges {
+		edges = append(edges, graphEdge{
+			from:  fmt.Sprintf("n%d", edge.From),
+			to:    fmt.Sprintf("n%d", edge.To),
+			label: fmt.Sprintf("retains ×%d", edge.Objects),
+		})
+	}
+
+	return nodes, edges
+}
+
+func subgraphLabel(graph *analyzer.RetainedSubgraph, node *analyzer.SubgraphNode) []string {
+	name := node.ClassName
+	switch {
+	case node.ID == 0:
+		name = fmt.Sprintf("%s@0x%x", node.ClassName, uint64(graph.Root))
+	case !node.Merged && node.Objects > 1:
+		name = fmt.Sprintf("%s ×%d", node.ClassName, node.Objects)
+	}
+
+	share := 0.0
+	if graph.RootSize > 0 {
+		share = float64(node.RetainedSize) / float64(graph.RootSize) * 100
+	}
+
+	return []string{
+		name,
+		fmt.Sprintf("retained %s (%.1f%%)", utils.MemorySize(node.RetainedSize).String(), share),
+		fmt.Sprintf("shallow %s", utils.MemorySize(node.ShallowSize).String()),
+	}
+}
+
+func subgraphFill(graph *analyzer.RetainedSubgraph, node *analyzer.SubgraphNode) string {
+	if node.Merged {
+		return mergedFill
+	}
+	if node.ID == 0 || graph.RootSize == 0 {
+		return criticalFill
+	}
+
+	share := float64(node.RetainedSize) / float64(graph.RootSize)
+	switch {
+	case share >= 0.30:
+		return criticalFill
+	case share >= analyzer.LeakSuspectThreshold:
+		return warningFill
+	default:
+		return normalFill
+	}
+}
+
+func writeDOT(w io.Writer, nodes []graphNode, edges []graphEdge) error {
+	var b strings.Builder
+
+	b.WriteString("digraph retained {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n\n")
+
+	for _, node := range nodes {
+		style := ""
+		if node.dashed {
+			style = `, style="rounded,dashed"`
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\", fillcolor=\"%s\"%s];\n",
+			node.id, dotEscape(strings.Join(node.label, "\n")), node.fill, style)
+	}
+	b.WriteString("\n")
+
+	for _, edge := range edges {
+		var attrs []string
+		if edge.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=\"%s\"", dotEscape(edge.label)))
+		}
+		if edge.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "  %s -> %s [%s];\n", edge.from, edge.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", edge.from, edge.to)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMermaid(w io.Writer, nodes []graphNode, edges []graphEdge) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart TD\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.id, mermaidEscape(strings.Join(node.label, "\n")))
+	}
+	b.WriteString("\n")
+
+	for _, edge := range edges {
+		arrow := "-->"
+		if edge.dashed {
+			arrow = "-.->"
+		}
+
+		if edge.label != "" {
+			fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", edge.from, arrow, mermaidEscape(edge.label), edge.to)
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", edge.from, arrow, edge.to)
+		}
+	}
+	b.WriteString("\n")
+
+	for _, node := range nodes {
+		style := fmt.Sprintf("fill:%s,stroke:#555", node.fill)
+		if node.dashed {
+			style += ",stroke-dasharray:4 3"
+		}
+		fmt.Fprintf(&b, "  style %s %s\n", node.id, style)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	return strings.ReplaceAll(text, "\n", `\n`)
+}
+
+// mermaidEscape uses entity codes since Mermaid labels can't contain raw quotes
+func mermaidEscape(text string) string {
+	text = strings.ReplaceAll(text, `"`, "#quot;")
+	return strings.ReplaceAll(text, "\n", "<br/>")
+}
@@ -0,0 +1,192 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+// HeapExport is the structured form of a heap dump analysis
+type HeapExport struct {
+	Dump         DumpInfo         `json:"dump"`
+	Summary      Summary          `json:"summary"`
+	Histogram    []ClassRow       `json:"histogram"`
+	Objects      []ObjectRow      `json:"objects"`
+	LeakSuspects []LeakSuspectRow `json:"leakSuspects"`
+}
+
+type DumpInfo struct {
+	File           string    `json:"file"`
+	Format         string    `json:"format"`
+	IdentifierSize uint32    `json:"identifierSize"`
+	Timestamp      time.Time `json:"timestamp"`
+	Truncated      bool      `json:"truncated"`
+}
+
+type Summary struct {
+	Instances        int    `json:"instances"`
+	ObjectArrays     int    `json:"objectArrays"`
+	PrimitiveArrays  int    `json:"primitiveArrays"`
+	Classes          int    `json:"classes"`
+	GCRoots          int    `json:"gcRoots"`
+	TotalShallowSize uint64 `json:"totalShallowSize"`
+	ReachableObjects int    `json:"reachableObjects"`
+	ReachableSize    uint64 `json:"reachableSize"`
+}
+
+// ClassRow is one class histogram entry
+type ClassRow struct {
+	ClassName    string `json:"className" parquet:"class_name"`
+	ClassID      uint64 `json:"classId" parquet:"class_id"`
+	Instances    int64  `json:"instances" parquet:"instances"`
+	ShallowSize  uint64 `json:"shallowSize" parquet:"shallow_size"`
+	RetainedSize uint64 `json:"retainedSize" parquet:"retained_size"`
+}
+
+// ObjectRow summarizes one reachable object. DominatorID encodes the dominator
+// tree as parent pointers; 0 means the object is held directly by GC roots.
+type ObjectRow struct {
+	ID           uint64 `json:"id" parquet:"id"`
+	Kind         string `json:"kind" parquet:"kind,dict"`
+	ClassName    string `json:"className" parquet:"class_name,dict"`
+	Length       int64  `json:"length,omitempty" parquet:"length"`
+	ShallowSize  uint64 `json:"shallowSize" parquet:"shallow_size"`
+	RetainedSize uint64 `json:"retainedSize" parquet:"retained_size"`
+	DominatorID  uint64 `json:"dominatorId" parquet:"dominator_id"`
+	Depth        int32  `json:"depth" parquet:"depth"` // Distance from the GC roots in the dominator tree
+}
+
+type LeakSuspectRow struct {
+	Kind                  string  `json:"kind" parquet:"kind"`
+	ObjectID              uint64  `json:"objectId" parquet:"object_id"`
+	ClassName             string  `json:"className" parquet:"class_name"`
+	InstanceCount         int64   `json:"instanceCount" parquet:"instance_count"`
+	RetainedSize          uint64  `json:"retainedSize" parquet:"retained_size"`
+	Percentage            float64 `json:"percentage" parquet:"percentage"`
+	AccumulationPointID   uint64  `json:"accumulationPointId" parquet:"accumulation_point_id"`
+	AccumulationClassName string  `json:"accumulationClassName" parquet:"accumulation_class_name"`
+	Description           string  `json:"description" parquet:"description"`
+	TopAllocationSite     string  `json:"topAllocationSite,omitempty" parquet:"top_allocation_site"` // Frame that allocated the most of the class, when the dump has traces
+}
+
+// Options limit how much is exported
+type Options struct {
+	MaxObjects  int    // Largest objects by retained size to include (0 = all reachable objects)
+	MinRetained uint64 // Skip objects retaining less than this many bytes
+}
+
+// Build converts analysis results to export rows
+func Build(heapAnalyzer *analyzer.Analyzer, dump DumpInfo, options Options) (*HeapExport, error) {
+	ctx := heapAnalyzer.GetContext()
+	tree := heapAnalyzer.GetDominatorTree()
+	histogram := heapAnalyzer.GetHistogram()
+	if tree == nil || histogram == nil {
+		return nil, fmt.Errorf("heap analysis has no dominator tree")
+	}
+
+	result := &HeapExport{
+		Dump: dump,
+		Summary: Summary{
+			Instances:        ctx.InstanceReg.GetCount(),
+			ObjectArrays:     ctx.ArrayReg.GetObjectArrayCount(),
+			PrimitiveArrays:  ctx.ArrayReg.GetPrimitiveArrayCount(),
+			Classes:          ctx.ClassDumpReg.GetCount(),
+			GCRoots:          ctx.RootReg.GetTotalRoots(),
+			TotalShallowSize: histogram.TotalShallowSize,
+			ReachableObjects: tree.ReachableCount(),
+			ReachableSize:    tree.TotalRetainedSize(),
+		},
+		Histogram:    buildClassRows(histogram),
+		Objects:      buildObjectRows(ctx, tree, options),
+		LeakSuspects: buildLeakSuspectRows(heapAnalyzer.GetLeakSuspects()),
+	}
+
+	return result, nil
+}
+
+func buildClassRows(histogram *analyzer.ClassHistogram) []ClassRow {
+	rows := make([]ClassRow, 0, len(histogram.Entries))
+	for _, entry := range histogram.Entries {
+		rows = append(rows, ClassRow{
+			ClassName:    entry.ClassName,
+			ClassID:      uint64(entry.ClassID),
+			Instances:    int64(entry.InstanceCount),
+			ShallowSize:  entry.ShallowSize,
+			RetainedSize: entry.RetainedSize,
+		})
+	}
+
+	// Ties go by name, so the same dump always exports the same rows
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].RetainedSize != rows[j].RetainedSize {
+			return rows[i].RetainedSize > rows[j].RetainedSize
+		}
+		return rows[i].ClassName < rows[j].ClassName
+	})
+	return rows
+}
+
+func buildObjectRows(ctx *analyzer.AnalysisContext, tree *analyzer.DominatorTree, options Options) []ObjectRow {
+	rows := make([]ObjectRow, 0, tree.ReachableCount())
+	var depth int32
+
+	tree.Walk(func(objectID model.ID, retained uint64) {
+		depth++
+		if retained < options.MinRetained {
+			return
+		}
+
+		object, _ := ctx.DescribeObject(objectID)
+		dominator, _ := tree.ImmediateDominator(objectID)
+		rows = append(rows, ObjectRow{
+			ID:           uint64(objectID),
+			Kind:         object.Kind.String(),
+			ClassName:    object.ClassName,
+			Length:       int64(object.Length),
+			ShallowSize:  object.ShallowSize,
+			RetainedSize: retained,
+			DominatorID:  uint64(dominator),
+			Depth:        depth,
+		})
+	}, func(objectID model.ID) {
+		depth--
+	})
+
+	// The walk visits equal siblings in no fixed order, so ties go by ID
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].RetainedSize != rows[j].RetainedSize {
+			return rows[i].RetainedSize > rows[j].RetainedSize
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	if options.MaxObjects > 0 && len(rows) > options.MaxObjects {
+		rows = rows[:options.MaxObjects]
+	}
+
+	return rows
+}
+
+func buildLeakSuspectRows(suspects []*analyzer.LeakSuspect) []LeakSuspectRow {
+	rows := make([]LeakSuspectRow, 0, len(suspects))
+	for _, suspect := range suspects {
+		row := LeakSuspectRow{
+			Kind:                  suspect.Kind.String(),
+			ObjectID:              uint64(suspect.ObjectID),
+			ClassName:             suspect.ClassName,
+			InstanceCount:         int64(suspect.InstanceCount),
+			RetainedSize:          suspect.RetainedSize,
+			Percentage:            suspect.Percentage,
+			AccumulationPointID:   uint64(suspect.AccumulationPoint),
+			AccumulationClassName: suspect.AccumulationClassName,
+			Description:           suspect.Description,
+		}
+		if len(suspect.AllocationSites) > 0 {
+			row.TopAllocationSite = suspect.AllocationSites[0].Frame
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatParquet = "parquet"
+)
+
+var Formats = []string{FormatJSON, FormatParquet}
+
+// WriteJSON writes the whole export as a single JSON document
+func WriteJSON(w io.Writer, result *HeapExport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteJSONFile writes the export to path
+func WriteJSONFile(path string, result *HeapExport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := WriteJSON(file, result); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteParquet writes one Parquet file per table into dir and returns the paths written.
+// Parquet is columnar, so the dump info and summary are only part of the JSON export.
+func WriteParquet(dir string, result *HeapExport) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var written []string
+	write := func(name string, fn func(path string) error) error {
+		path := filepath.Join(dir, name)
+		if err := fn(path); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	if err := write("histogram.parquet", func(path string) error {
+		return parquet.WriteFile(path, result.Histogram)
+	}); err != nil {
+		return written, err
+	}
+
+	if err := write("objects.parquet", func(path string) error {
+		return parquet.WriteFile(path, result.Objects)
+	}); err != nil {
+		return written, err
+	}
+
+	if err := write("leak_suspects.parquet", func(path string) error {
+		return parquet.WriteFile(path, result.LeakSuspects)
+	}); err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
@@ -0,0 +1,97 @@
+package heap
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/export"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// GraphConfig selects the object to graph and how the subgraph is rendered
+type GraphConfig struct {
+	Format   string   // "dot" or "mermaid"
+	OutPath  string   // Defaults to <dump>-<object>.<ext>
+	ObjectID model.ID // Object to graph; 0 picks a leak suspect
+	Suspect  int      // 1-based leak suspect number, used when ObjectID is 0
+	Options  analyzer.SubgraphOptions
+}
+
+// RunHeapGraph writes the class-aggregated retained subtree of an object as a DOT or Mermaid graph
+func RunHeapGraph(filename string, config *Config, graphConfig *GraphConfig) error {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	objectID, err := selectGraphObject(heapAnalyzer, graphConfig)
+	if err != nil {
+		return err
+	}
+
+	graph, err := heapAnalyzer.BuildRetainedSubgraph(objectID, graphConfig.Options)
+	if err != nil {
+		return fmt.Errorf("failed to build retained subgraph: %w", err)
+	}
+
+	outPath := graphConfig.OutPath
+	if outPath == "" {
+		ext := map[string]string{export.FormatDOT: "dot", export.FormatMermaid: "mmd"}[graphConfig.Format]
+		base := strings.TrimSuffix(strings.TrimSuffix(filename, ".gz"), ".hprof")
+		outPath = fmt.Sprintf("%s-0x%x.%s", base, uint64(objectID), ext)
+	}
+
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outPath, err)
+	}
+	if err := export.WriteGraph(file, graph, graphConfig.Format); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outPath, err)
+	}
+
+	root, _ := heapAnalyzer.GetContext().DescribeObject(objectID)
+	fmt.Printf("🕸️  Wrote retained subgraph of %s to %s\n", root.DisplayName(), outPath)
+	fmt.Printf("   %s retained, %d class groups, %d-hop GC root path\n",
+		utils.MemorySize(graph.RootSize).String(), len(graph.Nodes), len(graph.RootPath))
+	if graphConfig.Format == export.FormatDOT {
+		fmt.Printf("   Render with: dot -Tsvg %s -o graph.svg\n", outPath)
+	}
+
+	return nil
+}
+
+// selectGraphObject resolves --object/--suspect, falling back to the first leak
+// suspect and then the biggest top-level dominator
+func selectGraphObject(heapAnalyzer *analyzer.Analyzer, graphConfig *GraphConfig) (model.ID, error) {
+	if graphConfig.ObjectID != 0 {
+		return graphConfig.ObjectID, nil
+	}
+
+	suspects := heapAnalyzer.GetLeakSuspects()
+	if graphConfig.Suspect > 0 {
+		if graphConfig.Suspect > len(suspects) {
+			return 0, fmt.Errorf("leak suspect %d not found (%d suspects)", graphConfig.Suspect, len(suspects))
+		}
+		return suspects[graphConfig.Suspect-1].ObjectID, nil
+	}
+
+	if len(suspects) > 0 {
+		return suspects[0].ObjectID, nil
+	}
+
+	if tree := heapAnalyzer.GetDominatorTree(); tree != nil {
+		if top := tree.Children(analyzer.SuperRootID); len(top) > 0 {
+			return top[0], nil
+		}
+	}
+	return 0, fmt.Errorf("no reachable objects to graph")
+}
@@ -0,0 +1,89 @@
+package heap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/internal/heap/parser"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// RunHeapInfo describes a dump from its records, without the full parse and analysis
+func RunHeapInfo(filename string, config *Config) error {
+	hprofParser, err := parser.NewParser(filename)
+	if err != nil {
+		return err
+	}
+	defer hprofParser.Close()
+
+	if err := hprofParser.SetDebugLevel(config.Debug); err != nil {
+		return err
+	}
+
+	summary, err := hprofParser.Summarize()
+	if err != nil {
+		return fmt.Errorf("failed to read heap dump: %w", err)
+	}
+
+	printDumpSummary(filename, summary)
+	return nil
+}
+
+func printDumpSummary(filename string, summary *parser.DumpSummary) {
+	// Estimated counts are marked, so they aren't taken for what a full parse finds
+	count := func(n int64) string {
+		if summary.Exact() {
+			return utils.FormatCount(n)
+		}
+		return "~" + utils.FormatCount(n)
+	}
+
+	fmt.Println()
+	fmt.Println("📄 HEAP DUMP INFO")
+	fmt.Println(utils.FormatKeyValue("File", filename, 16))
+
+	size := utils.MemorySize(summary.FileSize).String()
+	if summary.Compressed {
+		size += " (gzip-compressed)"
+	}
+	fmt.Println(utils.FormatKeyValue("Size", size, 16))
+	fmt.Println(utils.FormatKeyValue("Format", fmt.Sprintf("%s, %d-byte identifiers", summary.Format, summary.IdentifierSize), 16))
+
+	taken := "unknown"
+	if summary.Timestamp.UnixMilli() > 0 {
+		taken = summary.Timestamp.Format("2006-01-02 15:04:05 MST")
+	}
+	fmt.Println(utils.FormatKeyValue("Taken", taken, 16))
+
+	fmt.Println()
+	fmt.Println(utils.FormatKeyValue("Records", fmt.Sprintf("%s (%s strings, %s stack traces)",
+		utils.FormatCount(int64(summary.Records)), utils.FormatCount(int64(summary.Strings)),
+		utils.FormatCount(int64(summary.RecordCounts[model.HPROF_TRACE]))), 16))
+	fmt.Println(utils.FormatKeyValue("Classes", utils.FormatCount(int64(summary.Classes)), 16))
+	segments := "segments"
+	if summary.Segments == 1 {
+		segments = "segment"
+	}
+	fmt.Println(utils.FormatKeyValue("Heap records", fmt.Sprintf("%s in %s %s",
+		utils.MemorySize(summary.HeapBytes).String(), utils.FormatCount(int64(summary.Segments)), segments), 16))
+	fmt.Println(utils.FormatKeyValue("Objects", fmt.Sprintf("%s (%s instances, %s object arrays, %s primitive arrays)",
+		count(summary.Objects()), count(summary.SubRecords[model.HPROF_GC_INSTANCE_DUMP]),
+		count(summary.SubRecords[model.HPROF_GC_OBJ_ARRAY_DUMP]), count(summary.SubRecords[model.HPROF_GC_PRIM_ARRAY_DUMP])), 16))
+	// Roots are written together, not spread over the heap, so a sample says nothing about them
+	if summary.Exact() {
+		fmt.Println(utils.FormatKeyValue("GC roots", utils.FormatCount(summary.GCRoots()), 16))
+	}
+
+	fmt.Println()
+	if !summary.Exact() && summary.HeapBytes > 0 {
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("Object counts are scaled from a %s sample (%s of the heap records)",
+			utils.MemorySize(summary.SampledBytes).String(),
+			utils.FormatPercent(float64(summary.SampledBytes)/float64(summary.HeapBytes)*100))))
+	}
+	if summary.Truncated {
+		fmt.Println(utils.WarningStyle.Render("⚠️  Dump is truncated: " + summary.TruncatedReason))
+	}
+	fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("Read in %s; run 'jdiag heap %s' for the full analysis",
+		summary.Elapsed.Round(time.Millisecond), filename)))
+}
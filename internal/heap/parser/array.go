@@ -102,6 +102,11 @@ func parseObjectArrayDump(reader *BinaryReader, arrayReg *registry.ArrayRegistry
 *
 * This is crucial for String resolution since String objects reference
 * char[] or byte[] arrays containing the actual character data.
+*
+* When the registry's contents are skipped (structure-only), the elements are
+* stepped over and Elements stays nil: the array keeps its ID, type and
+* length, so sizes and references are unchanged, but no String can be
+* decoded from it.
  */
 func parsePrimitiveArrayDump(reader *BinaryReader, arrayReg *registry.ArrayRegistry) error {
 	array := &model.GCPrimitiveArrayDump{}
@@ -136,12 +141,18 @@ func parsePrimitiveArrayDump(reader *BinaryReader, arrayReg *registry.ArrayRegis
 		return fmt.Errorf("unknown primitive array element type: 0x%02x", elementTypeRaw)
 	}
 
-	// Read array elements as raw bytes
+	// Read array elements as raw bytes, or step over them without keeping a copy
 	totalSize := int(array.Size) * elementSize
-	array.Elements = make([]byte, totalSize)
-	err = reader.ReadBytes(array.Elements)
-	if err != nil {
-		return fmt.Errorf("failed to read array elements: %w", err)
+	if arrayReg.ContentsSkipped() {
+		if err := reader.Skip(totalSize); err != nil {
+			return fmt.Errorf("failed to skip array elements: %w", err)
+		}
+	} else {
+		array.Elements = make([]byte, totalSize)
+		err = reader.ReadBytes(array.Elements)
+		if err != nil {
+			return fmt.Errorf("failed to read array elements: %w", err)
+		}
 	}
 
 	// Add to registry
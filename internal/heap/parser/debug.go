@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DebugLevel controls how much of the parse is written to the .debug log
+type DebugLevel int
+
+const (
+	DebugOff     DebugLevel = iota
+	DebugSummary            // Header and end-of-parse totals
+	DebugRecords            // Plus the decoded contents of every record and segment
+	DebugTrace              // Plus record headers with byte offsets and lengths
+)
+
+var DebugLevels = []string{"off", "summary", "records", "trace"}
+
+const debugBufferSize = 1 << 20
+
+func (l DebugLevel) String() string {
+	if l >= DebugOff && int(l) < len(DebugLevels) {
+		return DebugLevels[l]
+	}
+	return "unknown"
+}
+
+// ParseDebugLevel converts a --debug flag value to a DebugLevel
+func ParseDebugLevel(value string) (DebugLevel, error) {
+	for i, name := range DebugLevels {
+		if strings.EqualFold(value, name) {
+			return DebugLevel(i), nil
+		}
+	}
+	return DebugOff, fmt.Errorf("invalid debug level: %s. Valid options: %v", value, DebugLevels)
+}
+
+// DebugPath returns the debug log path for a dump: dump.hprof(.gz) -> dump.debug
+func DebugPath(filename string) string {
+	base := strings.TrimSuffix(filename, ".gz")
+	return strings.TrimSuffix(base, filepath.Ext(base)) + ".debug"
+}
+
+// debugSink buffers debug output; a nil sink discards everything.
+// Writes are serialized because segment workers log concurrently.
+type debugSink struct {
+	mu     sync.Mutex
+	level  DebugLevel
+	file   *os.File
+	writer *bufio.Writer
+}
+
+func newDebugSink(path string, level DebugLevel) (*debugSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create debug file: %w", err)
+	}
+
+	return &debugSink{
+		level:  level,
+		file:   file,
+		writer: bufio.NewWriterSize(file, debugBufferSize),
+	}, nil
+}
+
+func (s *debugSink) enabled(level DebugLevel) bool {
+	return s != nil && level <= s.level
+}
+
+func (s *debugSink) printf(level DebugLevel, format string, args ...interface{}) {
+	if !s.enabled(level) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.writer, format, args...)
+}
+
+func (s *debugSink) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.writer.Flush()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+/*
+*	Gzip-compressed heap dumps (jcmd <pid> GC.heap_dump -gz=<level>, JDK 15+)
+*
+*	The JDK writes the dump as a sequence of independently compressed gzip
+*	members. compress/gzip reads multi-member streams transparently, so the
+*	dump is decompressed on the fly instead of being inflated to disk first.
+*	Compressed dumps can't be memory-mapped and are always parsed sequentially.
+*	Offsets recorded in the sidecar index refer to the decompressed stream.
+ */
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzipFile checks the file's magic bytes and rewinds it
+func isGzipFile(file *os.File) (bool, error) {
+	magic := make([]byte, len(gzipMagic))
+	n, err := io.ReadFull(file, magic)
+	if _, seekErr := file.Seek(0, io.SeekStart); seekErr != nil {
+		return false, fmt.Errorf("unable to rewind file: %w", seekErr)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to read file magic: %w", err)
+	}
+
+	return n == len(gzipMagic) && magic[0] == gzipMagic[0] && magic[1] == gzipMagic[1], nil
+}
+
+// openGzip returns a streaming decompressor for a gzipped dump
+func openGzip(file *os.File) (*gzip.Reader, error) {
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip stream: %w", err)
+	}
+	return gzReader, nil
+}
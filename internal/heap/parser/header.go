@@ -2,15 +2,19 @@ package parser
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 )
 
+var supportedFormats = []string{"JAVA PROFILE 1.0.1", "JAVA PROFILE 1.0.2"}
+
 /*
 *	ParseHeader parses the HPROF file header
 *
-*	"JAVA PROFILE 1.0.2\0"		Null-terminated string
+*	"JAVA PROFILE 1.0.2\0"		Null-terminated string ("JAVA PROFILE 1.0.1" for
+*								older JVMs, which never emit HEAP_DUMP_SEGMENT)
 *	u4                    		Size of IDs (usually pointer size)
 *	u4                    		High word of timestamp
 *	u4                    		Low word of timestamp (ms since 1/1/70)
@@ -22,7 +26,7 @@ func ParseHeader(reader *BinaryReader) (*model.HprofHeader, error) {
 		return nil, fmt.Errorf("unable to read format: %w", err)
 	}
 
-	if hprofFormat != "JAVA PROFILE 1.0.2" {
+	if !slices.Contains(supportedFormats, hprofFormat) {
 		return nil, fmt.Errorf("invalid format: %s", hprofFormat)
 	}
 
@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+*	Sidecar index written next to the .hprof once a complete dump is analyzed.
+*
+*	<dump>.hprof.idx contains:
+*	- Source file size, modification time and a fingerprint of its content
+*	- Top-level record table (type, offset, length)
+*	- Class table (serial number, class object ID, name)
+*	- Dominator results, reused by later analyses of the same dump
+*	- The analysis overview: totals, top classes and top dominators, enough
+*	  to report on the dump again without parsing it
+*
+*	Size and mtime catch most changes for free, but a dump rewritten in place
+*	within the same second at the same size (a re-run of jmap, a copy with
+*	preserved timestamps) would pass them. The fingerprint hashes the first
+*	and last indexFingerprintSpan bytes: the header holds the dump timestamp
+*	and the tail the last heap segments, so a different dump differs there.
+*	Any mismatch, or a change to IndexVersion, makes the index stale.
+ */
+
+// IndexVersion must be bumped whenever the HeapIndex layout changes
+const IndexVersion = 2
+
+// Bytes hashed from each end of the dump for the content fingerprint
+const indexFingerprintSpan = 64 << 10
+
+const IndexExtension = ".idx"
+
+type IndexedRecord struct {
+	Type   model.HProfTagRecord
+	Offset int64
+	Length uint32
+}
+
+type IndexedClass struct {
+	SerialNumber model.SerialNum
+	ObjectID     model.ID
+	Name         string
+}
+
+// IndexedOverview is what a full analysis reports about the dump, kept so it can be shown without one
+type IndexedOverview struct {
+	Objects          int
+	ShallowSize      uint64
+	GCRoots          int
+	ReachableObjects int
+	ReachableSize    uint64
+
+	Classes    []IndexedClassUsage // Largest retained size first
+	Dominators []IndexedObject     // Top-level dominators, largest retained size first
+}
+
+type IndexedClassUsage struct {
+	ClassName    string
+	Instances    int
+	ShallowSize  uint64
+	RetainedSize uint64
+}
+
+type IndexedObject struct {
+	ObjectID     model.ID
+	Name         string
+	ShallowSize  uint64
+	RetainedSize uint64
+}
+
+type HeapIndex struct {
+	Version           int
+	SourceSize        int64
+	SourceModTime     time.Time
+	SourceFingerprint []byte
+	IdentifierSize    uint32
+	DumpTimestamp     time.Time
+
+	Records []IndexedRecord
+	Classes []IndexedClass
+
+	// Immediate dominator per object; empty until dominator analysis has run
+	Dominators map[model.ID]model.ID
+
+	// Nil until the dump has been analyzed
+	Overview *IndexedOverview
+}
+
+func NewHeapIndex() *HeapIndex {
+	return &HeapIndex{
+		Version:    IndexVersion,
+		Dominators: make(map[model.ID]model.ID),
+	}
+}
+
+// IndexPath returns the sidecar index location for a dump
+func IndexPath(hprofFile string) string {
+	return hprofFile + IndexExtension
+}
+
+// AddRecord tracks a top-level record position
+func (idx *HeapIndex) AddRecord(recordType model.HProfTagRecord, offset int64, length uint32) {
+	if idx == nil {
+		return
+	}
+	idx.Records = append(idx.Records, IndexedRecord{Type: recordType, Offset: offset, Length: length})
+}
+
+// AddClass tracks a LOAD_CLASS entry
+func (idx *HeapIndex) AddClass(serial model.SerialNum, objectID model.ID, name string) {
+	if idx == nil {
+		return
+	}
+	idx.Classes = append(idx.Classes, IndexedClass{SerialNumber: serial, ObjectID: objectID, Name: name})
+}
+
+// IsValidFor reports whether the index still describes the given dump
+func (idx *HeapIndex) IsValidFor(file *os.File) (bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("unable to stat heap dump: %w", err)
+	}
+	if idx.Version != IndexVersion || idx.SourceSize != info.Size() || !idx.SourceModTime.Equal(info.ModTime()) {
+		return false, nil
+	}
+
+	fingerprint, err := fingerprintDump(file, info.Size())
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(idx.SourceFingerprint, fingerprint), nil
+}
+
+// fingerprintDump hashes the first and last indexFingerprintSpan bytes of a dump of the given size
+func fingerprintDump(file *os.File, size int64) ([]byte, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, 0, min(size, indexFingerprintSpan))); err != nil {
+		return nil, fmt.Errorf("failed to read heap dump: %w", err)
+	}
+	if tail := max(size-indexFingerprintSpan, indexFingerprintSpan); tail < size {
+		if _, err := io.Copy(hash, io.NewSectionReader(file, tail, size-tail)); err != nil {
+			return nil, fmt.Errorf("failed to read heap dump: %w", err)
+		}
+	}
+	return hash.Sum(nil), nil
+}
+
+// Save writes the index next to the dump
+func (idx *HeapIndex) Save(hprofFile string) error {
+	source, err := os.Open(hprofFile)
+	if err != nil {
+		return fmt.Errorf("unable to open heap dump: %w", err)
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat heap dump: %w", err)
+	}
+	idx.SourceSize = info.Size()
+	idx.SourceModTime = info.ModTime()
+	if idx.SourceFingerprint, err = fingerprintDump(source, info.Size()); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written index behind
+	tmpPath := IndexPath(hprofFile) + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("unable to create index file: %w", err)
+	}
+
+	if err := gob.NewEncoder(file).Encode(idx); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close index file: %w", err)
+	}
+
+	return os.Rename(tmpPath, IndexPath(hprofFile))
+}
+
+// LoadIndex reads the sidecar index for a dump, returning an error if it
+// is missing, unreadable, or stale
+func LoadIndex(hprofFile string) (*HeapIndex, error) {
+	source, err := os.Open(hprofFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open heap dump: %w", err)
+	}
+	defer source.Close()
+
+	file, err := os.Open(IndexPath(hprofFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open index: %w", err)
+	}
+	defer file.Close()
+
+	idx := &HeapIndex{}
+	if err := gob.NewDecoder(file).Decode(idx); err != nil {
+		return nil, fmt.Errorf("failed to decode index: %w", err)
+	}
+
+	valid, err := idx.IsValidFor(source)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("index is stale for %s", hprofFile)
+	}
+
+	return idx, nil
+}
+
+// GetRecordCounts returns the number of records per type
+func (idx *HeapIndex) GetRecordCounts() map[model.HProfTagRecord]int {
+	counts := make(map[model.HProfTagRecord]int)
+	for _, record := range idx.Records {
+		counts[record.Type]++
+	}
+	return counts
+}
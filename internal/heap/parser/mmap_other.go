@@ -0,0 +1,18 @@
+//go:build !unix
+
+package parser
+
+import (
+	"fmt"
+	"os"
+)
+
+// mapFile is not supported on this platform; the parser falls back to
+// sequential buffered reads
+func mapFile(file *os.File) ([]byte, error) {
+	return nil, fmt.Errorf("mmap not supported on this platform")
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
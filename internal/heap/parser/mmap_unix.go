@@ -0,0 +1,37 @@
+//go:build unix
+
+package parser
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the whole file read-only into memory. Pages are file-backed,
+// so the kernel can evict them under pressure and RSS stays bounded.
+func mapFile(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat file: %w", err)
+	}
+
+	size := info.Size()
+	if size == 0 {
+		return nil, fmt.Errorf("cannot map empty file")
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("file too large to map: %d bytes", size)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap failed: %w", err)
+	}
+
+	return data, nil
+}
+
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
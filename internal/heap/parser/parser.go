@@ -1,14 +1,18 @@
 package parser
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 	"github.com/mabhi256/jdiag/internal/heap/registry"
+	"github.com/mabhi256/jdiag/utils"
 )
 
 /*
@@ -18,9 +22,14 @@ import (
 
 // Parser represents the main HPROF file parser
 type Parser struct {
-	file       *os.File
-	reader     *BinaryReader
-	outputFile *os.File // For debugging output
+	file     *os.File
+	data     []byte       // Memory-mapped dump, nil when mmap is unavailable
+	gzReader *gzip.Reader // Set when the dump is gzip-compressed
+	reader   *BinaryReader
+	debug    *debugSink // nil unless SetDebugLevel enabled the .debug log
+
+	// Strings are read from the dump on demand unless it is compressed
+	lazyStrings bool
 
 	header    *model.HprofHeader
 	stringReg *registry.StringRegistry
@@ -32,11 +41,30 @@ type Parser struct {
 	objectReg    *registry.InstanceRegistry
 	arrayReg     *registry.ArrayRegistry
 
+	// Sidecar index built while parsing
+	filename string
+	index    *HeapIndex
+
+	// Parallel segment parsing (only used when the dump is memory-mapped)
+	workers  int
+	segments *segmentPool
+
 	// Statistics
 	recordCount          int
 	recordCountMap       map[model.HProfTagRecord]int
 	heapDumpSegmentCount int
 	heapDumpEnded        bool
+
+	// Truncation (e.g. the JVM was killed mid-dump)
+	truncated       bool
+	truncatedAt     int64
+	truncatedReason string
+
+	// Progress and cancellation; an interrupted parse is kept like a truncated dump
+	ctx         context.Context
+	progress    utils.ProgressFunc
+	totalBytes  int64 // 0 for compressed dumps, whose uncompressed size isn't known
+	interrupted bool
 }
 
 // NewParser creates a new HPROF parser
@@ -46,56 +74,142 @@ func NewParser(filename string) (*Parser, error) {
 		return nil, fmt.Errorf("unable to open file: %w", err)
 	}
 
-	// Create debug output file (remove extension, add .debug)
-	ext := filepath.Ext(filename)
-	baseFilename := strings.TrimSuffix(filename, ext)
-	debugFilename := baseFilename + ".debug"
-
-	outputFile, err := os.Create(debugFilename)
+	compressed, err := isGzipFile(file)
 	if err != nil {
 		file.Close()
-		return nil, fmt.Errorf("unable to create debug file: %w", err)
+		return nil, err
+	}
+
+	// Prefer mmap; fall back to buffered sequential reads if it fails
+	var reader *BinaryReader
+	var data []byte
+	var gzReader *gzip.Reader
+	if compressed {
+		gzReader, err = openGzip(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		reader = NewBinaryReader(gzReader)
+	} else if data, err = mapFile(file); err == nil {
+		reader = NewBinaryReader(bytes.NewReader(data))
+	} else {
+		data = nil
+		reader = NewBinaryReader(file)
 	}
 
 	parser := &Parser{
-		file:       file,
-		reader:     NewBinaryReader(file),
-		outputFile: outputFile,
-		stringReg:  registry.NewStringRegistry(),
-		classReg:   registry.NewClassRegistry(),
-		stackReg:   registry.NewStackRegistry(),
+		file:      file,
+		data:      data,
+		gzReader:  gzReader,
+		reader:    reader,
+		stringReg: registry.NewStringRegistry(),
+
+		lazyStrings: !compressed,
+		classReg:    registry.NewClassRegistry(),
+		stackReg:    registry.NewStackRegistry(),
 		// threadReg:      registry.NewThreadRegistry(),
 		rootReg:        registry.NewGCRootRegistry(),
 		classDumpReg:   registry.NewClassDumpRegistry(),
 		objectReg:      registry.NewInstanceRegistry(),
 		arrayReg:       registry.NewArrayRegistry(),
+		filename:       filename,
+		index:          NewHeapIndex(),
+		workers:        DefaultWorkers(),
 		recordCountMap: make(map[model.HProfTagRecord]int),
+		ctx:            context.Background(),
+	}
+
+	if info, err := file.Stat(); err == nil && !compressed {
+		parser.totalBytes = info.Size()
+	}
+
+	if data != nil {
+		parser.stringReg.SetSource(bytes.NewReader(data))
+	} else if !compressed {
+		parser.stringReg.SetSource(file)
 	}
 
 	return parser, nil
 }
 
+// SetWorkers sets the number of goroutines used to parse heap dump segments.
+// A value of 1 or less parses segments sequentially.
+func (p *Parser) SetWorkers(workers int) {
+	p.workers = workers
+}
+
+// SetStructureOnly parses primitive arrays without their elements, so a dump
+// holding personal data can be analyzed without any String's text being read.
+func (p *Parser) SetStructureOnly(structureOnly bool) {
+	p.arrayReg.SetContentsSkipped(structureOnly)
+}
+
+// SetContext makes ParseHprof stop early once ctx is cancelled. The records
+// read so far are kept and the dump is reported as truncated where it stopped.
+func (p *Parser) SetContext(ctx context.Context) {
+	p.ctx = ctx
+}
+
+// SetProgress reports bytes read and records found while ParseHprof runs
+func (p *Parser) SetProgress(progress utils.ProgressFunc) {
+	p.progress = progress
+}
+
+// SetDebugLevel writes a transcript of the parse to DebugPath(filename).
+// DebugOff (the default) writes nothing.
+func (p *Parser) SetDebugLevel(level DebugLevel) error {
+	if err := p.debug.Close(); err != nil {
+		return fmt.Errorf("failed to close debug file: %w", err)
+	}
+	p.debug = nil
+
+	if level == DebugOff {
+		return nil
+	}
+
+	sink, err := newDebugSink(DebugPath(p.filename), level)
+	if err != nil {
+		return err
+	}
+	p.debug = sink
+	return nil
+}
+
 // Close closes the parser and its files
 func (p *Parser) Close() error {
 	var err error
+
+	// Lazy strings can't be read once the dump is unmapped
+	p.stringReg.SetSource(nil)
+	if p.data != nil {
+		unmapFile(p.data)
+		p.data = nil
+	}
+	if p.gzReader != nil {
+		p.gzReader.Close()
+		p.gzReader = nil
+	}
 	if p.file != nil {
 		err = p.file.Close()
+		p.file = nil
 	}
-	if p.outputFile != nil {
-		p.outputFile.Close()
+	if debugErr := p.debug.Close(); err == nil {
+		err = debugErr
 	}
+	p.debug = nil
 	return err
 }
 
-// debugf writes debug information to our output file
-func (p *Parser) debugf(format string, args ...interface{}) {
-	fmt.Fprintf(p.outputFile, format, args...)
+// debugf writes to the debug log when it is enabled at level or above
+func (p *Parser) debugf(level DebugLevel, format string, args ...interface{}) {
+	p.debug.printf(level, format, args...)
 }
 
 // parseHeader parses the HPROF file header
 func (p *Parser) parseHeader() error {
-	p.debugf("--- Parsing Header ---\n")
-	p.debugf("Byte offset: %d\n", p.reader.BytesRead())
+	p.debugf(DebugSummary, "--- Parsing Header ---\n")
+	p.debugf(DebugTrace, "Byte offset: %d\n", p.reader.BytesRead())
 
 	header, err := ParseHeader(p.reader)
 	if err != nil {
@@ -103,13 +217,15 @@ func (p *Parser) parseHeader() error {
 	}
 
 	p.header = header
+	p.index.IdentifierSize = header.IdentifierSize
+	p.index.DumpTimestamp = header.Timestamp
 
-	p.debugf("Format: %s\n", header.Format)
-	p.debugf("Identifier size: %d bytes\n", header.IdentifierSize)
-	p.debugf("Raw timestamp: %d\n", header.Timestamp.UnixMilli())
-	p.debugf("Timestamp: %s\n", header.Timestamp.Format("2006-01-02 15:04:05 UTC"))
-	p.debugf("Header bytes read: %d\n", p.reader.BytesRead())
-	p.debugf("Header parsed successfully!\n\n")
+	p.debugf(DebugSummary, "Format: %s\n", header.Format)
+	p.debugf(DebugSummary, "Identifier size: %d bytes\n", header.IdentifierSize)
+	p.debugf(DebugSummary, "Raw timestamp: %d\n", header.Timestamp.UnixMilli())
+	p.debugf(DebugSummary, "Timestamp: %s\n", header.Timestamp.Format("2006-01-02 15:04:05 UTC"))
+	p.debugf(DebugTrace, "Header bytes read: %d\n", p.reader.BytesRead())
+	p.debugf(DebugSummary, "Header parsed successfully!\n\n")
 
 	return nil
 }
@@ -141,31 +257,31 @@ func (p *Parser) parseRecord(record *model.HprofRecord) error {
 	case model.HPROF_ALLOC_SITES:
 		// HPROF_ALLOC_SITES: Generated only by deprecated -agentlib:hprof agent
 		// Modern heap dumps (jcmd, -XX:+HeapDumpOnOutOfMemoryError) don't include this
-		p.debugf("  Skipping deprecated ALLOC_SITES record (legacy HPROF agent)\n")
+		p.debugf(DebugRecords, "  Skipping deprecated ALLOC_SITES record (legacy HPROF agent)\n")
 		return p.skipRecordData(record.Length)
 
 	case model.HPROF_HEAP_SUMMARY:
 		// HPROF_HEAP_SUMMARY: Legacy summary record, rarely used in modern dumps
 		// Heap information is typically derived from heap dump segments
-		p.debugf("  Skipping legacy HEAP_SUMMARY record (rarely used)\n")
+		p.debugf(DebugRecords, "  Skipping legacy HEAP_SUMMARY record (rarely used)\n")
 		return p.skipRecordData(record.Length)
 
 	case model.HPROF_START_THREAD, model.HPROF_END_THREAD:
 		// HPROF_START_THREAD/END_THREAD: Rarely generated by modern JVMs
 		// Thread information is typically captured in heap dump segments instead
-		p.debugf("  Skipping rarely-used thread lifecycle record (%s)\n", record.Type)
+		p.debugf(DebugRecords, "  Skipping rarely-used thread lifecycle record (%s)\n", record.Type)
 		return p.skipRecordData(record.Length)
 
 	case model.HPROF_CPU_SAMPLES:
 		// HPROF_CPU_SAMPLES: Generated only by deprecated -agentlib:hprof agent
 		// Modern profiling tools use different mechanisms for CPU sampling
-		p.debugf("  Skipping deprecated CPU_SAMPLES record (legacy HPROF agent)\n")
+		p.debugf(DebugRecords, "  Skipping deprecated CPU_SAMPLES record (legacy HPROF agent)\n")
 		return p.skipRecordData(record.Length)
 
 	case model.HPROF_CONTROL_SETTINGS:
 		// HPROF_CONTROL_SETTINGS: Generated only by deprecated -agentlib:hprof agent
 		// Modern heap dumps don't include profiler configuration settings
-		p.debugf("  Skipping deprecated CONTROL_SETTINGS record (legacy HPROF agent)\n")
+		p.debugf(DebugRecords, "  Skipping deprecated CONTROL_SETTINGS record (legacy HPROF agent)\n")
 		return p.skipRecordData(record.Length)
 
 	default:
@@ -176,13 +292,17 @@ func (p *Parser) parseRecord(record *model.HprofRecord) error {
 
 // parseUTF8Record parses a HPROF_UTF8 record
 func (p *Parser) parseUTF8Record(length uint32) error {
-	utf8Body, err := ParseUTF8(p.reader, length, p.stringReg)
+	utf8Body, err := ParseUTF8(p.reader, length, p.stringReg, p.lazyStrings)
 	if err != nil {
 		return fmt.Errorf("failed to parse UTF8 record: %w", err)
 	}
 
-	p.debugf("  String ID: 0x%x\n", uint64(utf8Body.StringID))
-	p.debugf("  Text: \"%s\" (%d chars)\n", utf8Body.Text, len(utf8Body.Text))
+	p.debugf(DebugRecords, "  String ID: 0x%x\n", uint64(utf8Body.StringID))
+	if p.lazyStrings {
+		p.debugf(DebugRecords, "  Text: %d bytes, loaded on demand\n", length-p.header.IdentifierSize)
+	} else {
+		p.debugf(DebugRecords, "  Text: \"%s\" (%d chars)\n", utf8Body.Text, len(utf8Body.Text))
+	}
 
 	return nil
 }
@@ -195,10 +315,12 @@ func (p *Parser) parseLoadClassRecord() error {
 	}
 
 	className := p.stringReg.GetOrUnresolved(loadClassBody.ClassNameID)
-	p.debugf("  Class Serial: %d\n", loadClassBody.ClassSerialNumber)
-	p.debugf("  Object ID: 0x%x\n", uint64(loadClassBody.ObjectID))
-	p.debugf("  Stack Trace Serial: %d\n", loadClassBody.StackTraceSerialNumber)
-	p.debugf("  Class Name: \"%s\"\n", className)
+	p.index.AddClass(loadClassBody.ClassSerialNumber, loadClassBody.ObjectID, className)
+
+	p.debugf(DebugRecords, "  Class Serial: %d\n", loadClassBody.ClassSerialNumber)
+	p.debugf(DebugRecords, "  Object ID: 0x%x\n", uint64(loadClassBody.ObjectID))
+	p.debugf(DebugRecords, "  Stack Trace Serial: %d\n", loadClassBody.StackTraceSerialNumber)
+	p.debugf(DebugRecords, "  Class Name: \"%s\"\n", className)
 
 	return nil
 }
@@ -210,7 +332,7 @@ func (p *Parser) parseUnloadClassRecord() error {
 		return fmt.Errorf("failed to parse UNLOAD_CLASS record: %w", err)
 	}
 
-	p.debugf("  Class Serial: %d (unloaded)\n", unloadClassBody.ClassSerialNumber)
+	p.debugf(DebugRecords, "  Class Serial: %d (unloaded)\n", unloadClassBody.ClassSerialNumber)
 
 	return nil
 }
@@ -221,12 +343,16 @@ func (p *Parser) parseFrameRecord() error {
 		return fmt.Errorf("failed to parse FRAME record: %w", err)
 	}
 
-	p.debugf("  Frame ID: 0x%x\n", uint64(frameBody.StackFrameID))
-	p.debugf("  Method: %s\n", p.stringReg.GetOrUnresolved(frameBody.MethodNameID))
-	p.debugf("  Signature: %s\n", p.stringReg.GetOrUnresolved(frameBody.MethodSignatureID))
-	p.debugf("  Source: %s\n", p.stringReg.GetOrUnresolved(frameBody.SourceFileNameID))
-	p.debugf("  Class Serial: %d\n", frameBody.ClassSerialNumber)
-	p.debugf("  Line: %d\n", frameBody.LineNumber)
+	if !p.debug.enabled(DebugRecords) {
+		return nil
+	}
+
+	p.debugf(DebugRecords, "  Frame ID: 0x%x\n", uint64(frameBody.StackFrameID))
+	p.debugf(DebugRecords, "  Method: %s\n", p.stringReg.GetOrUnresolved(frameBody.MethodNameID))
+	p.debugf(DebugRecords, "  Signature: %s\n", p.stringReg.GetOrUnresolved(frameBody.MethodSignatureID))
+	p.debugf(DebugRecords, "  Source: %s\n", p.stringReg.GetOrUnresolved(frameBody.SourceFileNameID))
+	p.debugf(DebugRecords, "  Class Serial: %d\n", frameBody.ClassSerialNumber)
+	p.debugf(DebugRecords, "  Line: %d\n", frameBody.LineNumber)
 
 	return nil
 }
@@ -237,17 +363,21 @@ func (p *Parser) parseTraceRecord() error {
 		return fmt.Errorf("failed to parse TRACE record: %w", err)
 	}
 
-	p.debugf("  Trace Serial: %d\n", traceBody.StackTraceSerialNumber)
-	p.debugf("  Thread Serial: %d\n", traceBody.ThreadSerialNumber)
-	p.debugf("  Frame Count: %d\n", traceBody.NumFrames)
-	p.debugf("  Frame IDs: ")
+	if !p.debug.enabled(DebugRecords) {
+		return nil
+	}
+
+	p.debugf(DebugRecords, "  Trace Serial: %d\n", traceBody.StackTraceSerialNumber)
+	p.debugf(DebugRecords, "  Thread Serial: %d\n", traceBody.ThreadSerialNumber)
+	p.debugf(DebugRecords, "  Frame Count: %d\n", traceBody.NumFrames)
+	p.debugf(DebugRecords, "  Frame IDs: ")
 	for i, frameID := range traceBody.StackFrameIDs {
 		if i > 0 {
-			p.debugf(", ")
+			p.debugf(DebugRecords, ", ")
 		}
-		p.debugf("0x%x", uint64(frameID))
+		p.debugf(DebugRecords, "0x%x", uint64(frameID))
 	}
-	p.debugf("\n")
+	p.debugf(DebugRecords, "\n")
 
 	return nil
 }
@@ -255,19 +385,33 @@ func (p *Parser) parseTraceRecord() error {
 func (p *Parser) parseHeapDumpSegmentRecord(length uint32) error {
 	p.heapDumpSegmentCount++
 
+	if p.segments != nil {
+		offset := p.reader.BytesRead()
+
+		// A truncated final segment is still handed to a worker so the
+		// sub-records before the cut are kept
+		available := int64(len(p.data)) - offset
+		if int64(length) > available {
+			p.segments.submit(segmentJob{number: p.heapDumpSegmentCount, offset: offset,
+				length: uint32(available), truncated: true})
+			return fmt.Errorf("HEAP_DUMP_SEGMENT needs %d bytes, only %d available: %w",
+				length, available, io.ErrUnexpectedEOF)
+		}
+
+		if err := p.reader.Skip(int(length)); err != nil {
+			return fmt.Errorf("failed to skip HEAP_DUMP_SEGMENT record: %w", err)
+		}
+		p.segments.submit(segmentJob{number: p.heapDumpSegmentCount, offset: offset, length: length})
+		return nil
+	}
+
 	subRecordCount, subRecordCountMap, err := ParseHeapDumpSegment(p.reader, length,
 		p.rootReg, p.classDumpReg, p.objectReg, p.stringReg, p.arrayReg)
 	if err != nil {
 		return fmt.Errorf("failed to parse HEAP_DUMP_SEGMENT record: %w", err)
 	}
 
-	p.debugf("  Segment #%d: %d bytes, %d sub-records\n",
-		p.heapDumpSegmentCount, length, subRecordCount)
-
-	p.debugf("  Sub-record breakdown:\n")
-	for subRecordType, count := range subRecordCountMap {
-		p.debugf("    %s: %d\n", subRecordType, count)
-	}
+	p.debugSegment(p.heapDumpSegmentCount, length, subRecordCount, subRecordCountMap)
 
 	return nil
 }
@@ -279,7 +423,7 @@ func (p *Parser) parseHeapDumpEndRecord(length uint32) error {
 	}
 
 	p.heapDumpEnded = true
-	p.debugf("  Heap dump sequence completed\n")
+	p.debugf(DebugRecords, "  Heap dump sequence completed\n")
 
 	return nil
 }
@@ -290,20 +434,36 @@ func (p *Parser) skipRecordData(length uint32) error {
 	if err != nil {
 		return fmt.Errorf("failed to skip %d bytes: %w", length, err)
 	}
-	p.debugf("  Skipped %d bytes of record data\n", length)
+	p.debugf(DebugTrace, "  Skipped %d bytes of record data\n", length)
 	return nil
 }
 
 // parseRecords parses all records in the file
 func (p *Parser) parseRecords() error {
-	p.debugf("--- Parsing Records ---\n")
+	p.debugf(DebugRecords, "--- Parsing Records ---\n")
 
 	for {
 		cursor := p.reader.BytesRead()
 
+		if p.recordCount%progressRecords == 0 {
+			p.reportProgress(cursor)
+			if p.ctx.Err() != nil {
+				p.interrupted = true
+				p.markTruncated(cursor, "interrupted")
+				break
+			}
+		}
+
 		record, err := p.reader.ReadRecordHeader()
 		if err == io.EOF {
-			p.debugf("Reached EOF. Parsed %d records.\n", p.recordCount)
+			p.debugf(DebugSummary, "Reached EOF. Parsed %d records.\n", p.recordCount)
+			if p.heapDumpSegmentCount > 0 && !p.heapDumpEnded {
+				p.markTruncated(cursor, "HEAP_DUMP_END record missing")
+			}
+			break
+		}
+		if isTruncation(err) {
+			p.markTruncated(cursor, "incomplete record header")
 			break
 		}
 		if err != nil {
@@ -312,15 +472,23 @@ func (p *Parser) parseRecords() error {
 
 		p.recordCount++
 		p.recordCountMap[record.Type]++
+		p.index.AddRecord(record.Type, cursor, record.Length)
 
-		p.debugf("Record #%d at offset %d:\n", p.recordCount, cursor)
-		p.debugf("  Type: %s (0x%02x)\n", record.Type, record.Type)
-		p.debugf("  Time offset: %d ms\n", record.TimeOffset)
-		p.debugf("  Length: %d bytes\n", record.Length)
+		if p.debug.enabled(DebugTrace) {
+			p.debugf(DebugTrace, "Record #%d at offset %d:\n", p.recordCount, cursor)
+			p.debugf(DebugTrace, "  Type: %s (0x%02x)\n", record.Type, record.Type)
+			p.debugf(DebugTrace, "  Time offset: %d ms\n", record.TimeOffset)
+			p.debugf(DebugTrace, "  Length: %d bytes\n", record.Length)
+		} else {
+			p.debugf(DebugRecords, "Record #%d: %s\n", p.recordCount, record.Type)
+		}
 
 		newCursorExpected := cursor + 9 + int64(record.Length)
 
-		p.parseRecord(record)
+		if err := p.parseRecord(record); isTruncation(err) {
+			p.markTruncated(cursor, fmt.Sprintf("incomplete %s record: %v", record.Type, err))
+			break
+		}
 
 		if p.reader.BytesRead() != newCursorExpected {
 			return fmt.Errorf(
@@ -328,55 +496,86 @@ func (p *Parser) parseRecords() error {
 				record.Type, newCursorExpected, p.reader.BytesRead())
 		}
 
-		p.debugf("  Processed successfully, now at offset %d\n\n", p.reader.BytesRead())
+		p.debugf(DebugTrace, "  Processed successfully, now at offset %d\n\n", p.reader.BytesRead())
 	}
 
 	return nil
 }
 
+// progressRecords is how many records parseRecords reads between progress reports and cancellation checks
+const progressRecords = 1000
+
+func (p *Parser) reportProgress(cursor int64) {
+	if p.progress != nil {
+		p.progress(utils.Progress{BytesRead: cursor, TotalBytes: p.totalBytes, Items: p.recordCount})
+	}
+}
+
+// isTruncation reports whether an error was caused by running out of input
+func isTruncation(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// markTruncated records where a truncated dump stopped being readable
+func (p *Parser) markTruncated(offset int64, reason string) {
+	p.truncated = true
+	p.truncatedAt = offset
+	p.truncatedReason = reason
+	p.debugf(DebugSummary, "⚠️ Dump truncated at offset %d: %s\n", offset, reason)
+	p.debugf(DebugSummary, "Keeping %d records parsed before the truncation point\n\n", p.recordCount)
+}
+
 // printSummary prints a summary of parsing results
 func (p *Parser) printSummary() {
-	p.debugf("--- Record Summary ---\n")
-	p.debugf("Total records: %d\n", p.recordCount)
-	p.debugf("Record type breakdown:\n")
+	if !p.debug.enabled(DebugSummary) {
+		return
+	}
+
+	p.debugf(DebugSummary, "--- Record Summary ---\n")
+	p.debugf(DebugSummary, "Total records: %d\n", p.recordCount)
+	p.debugf(DebugSummary, "Record type breakdown:\n")
 	for recordType, count := range p.recordCountMap {
-		p.debugf("  %s: %d\n", recordType, count)
+		p.debugf(DebugSummary, "  %s: %d\n", recordType, count)
+	}
+	p.debugf(DebugSummary, "Total bytes processed: %d\n", p.reader.BytesRead())
+	if p.truncated {
+		p.debugf(DebugSummary, "Truncated at offset: %d (%s)\n", p.truncatedAt, p.truncatedReason)
 	}
-	p.debugf("Total bytes processed: %d\n", p.reader.BytesRead())
 
 	// Show some example strings
-	p.debugf("\nSample strings from table:\n")
+	p.debugf(DebugSummary, "\nSample strings from table:\n")
 	stringSampleCount := 0
 	maxStringSamples := 10
-	for id, text := range p.stringReg.GetAll() {
+	for _, id := range p.stringReg.GetIDs() {
 		if stringSampleCount >= maxStringSamples {
 			break
 		}
+		text := p.stringReg.GetOrUnresolved(id)
 		if len(text) > 50 {
-			p.debugf("  0x%x: \"%.50s...\" (%d chars)\n", uint64(id), text, len(text))
+			p.debugf(DebugSummary, "  0x%x: \"%.50s...\" (%d chars)\n", uint64(id), text, len(text))
 		} else {
-			p.debugf("  0x%x: \"%s\"\n", uint64(id), text)
+			p.debugf(DebugSummary, "  0x%x: \"%s\"\n", uint64(id), text)
 		}
 		stringSampleCount++
 	}
 
-	p.debugf("Total strings in table: %d\n", p.stringReg.Count())
+	p.debugf(DebugSummary, "Total strings in table: %d (%d loaded)\n", p.stringReg.Count(), p.stringReg.MaterializedCount())
 
-	p.debugf("\nSample loaded classes:\n")
+	p.debugf(DebugSummary, "\nSample loaded classes:\n")
 	loadedClasses := p.classReg.GetLoadedClasses()
 	maxClassSamples := 15
 	for i, classInfo := range loadedClasses {
 		if i >= maxClassSamples {
-			p.debugf("  ... and %d more classes\n", len(loadedClasses)-maxClassSamples)
+			p.debugf(DebugSummary, "  ... and %d more classes\n", len(loadedClasses)-maxClassSamples)
 			break
 		}
-		p.debugf("  %d. %s (id: 0x%x)\n",
+		p.debugf(DebugSummary, "  %d. %s (id: 0x%x)\n",
 			classInfo.LoadClassBody.ClassSerialNumber,
 			classInfo.ClassName,
 			uint64(classInfo.LoadClassBody.ObjectID))
 	}
 
-	p.debugf("\nSample frames from registry:\n")
+	p.debugf(DebugSummary, "\nSample frames from registry:\n")
 	frameSampleCount := 0
 	maxFrameSamples := 5
 	for frameID, frame := range p.stackReg.GetAllFrames() {
@@ -387,12 +586,12 @@ func (p *Parser) printSummary() {
 		methodName := p.stringReg.GetOrUnresolved(frame.MethodNameID)
 		sourceFile := p.stringReg.GetOrUnresolved(frame.SourceFileNameID)
 
-		p.debugf("  Frame 0x%x: %s (%s:%d)\n",
+		p.debugf(DebugSummary, "  Frame 0x%x: %s (%s:%d)\n",
 			uint64(frameID), methodName, sourceFile, frame.LineNumber)
 		frameSampleCount++
 	}
 
-	p.debugf("\nSample traces from registry:\n")
+	p.debugf(DebugSummary, "\nSample traces from registry:\n")
 	traceSampleCount := 0
 	maxTraceSamples := 5
 	for frameID, frame := range p.stackReg.GetAllFrames() {
@@ -403,40 +602,40 @@ func (p *Parser) printSummary() {
 		methodName := p.stringReg.GetOrUnresolved(frame.MethodNameID)
 		sourceFile := p.stringReg.GetOrUnresolved(frame.SourceFileNameID)
 
-		p.debugf("  Frame 0x%x: %s (%s:%d)\n",
+		p.debugf(DebugSummary, "  Frame 0x%x: %s (%s:%d)\n",
 			uint64(frameID), methodName, sourceFile, frame.LineNumber)
 		traceSampleCount++
 	}
 
-	p.debugf("\n--- GC Root Summary ---\n")
-	p.debugf("Total GC roots: %d\n", p.rootReg.GetTotalRoots())
+	p.debugf(DebugSummary, "\n--- GC Root Summary ---\n")
+	p.debugf(DebugSummary, "Total GC roots: %d\n", p.rootReg.GetTotalRoots())
 
 	rootTypeCounts := p.rootReg.GetRootTypeCounts()
-	p.debugf("GC root type breakdown:\n")
+	p.debugf(DebugSummary, "GC root type breakdown:\n")
 	for rootType, count := range rootTypeCounts {
-		p.debugf("  %s: %d\n", rootType, count)
+		p.debugf(DebugSummary, "  %s: %d\n", rootType, count)
 	}
 
 	// Show thread object information
 	threadObjects := p.rootReg.GetThreadObjectRoots()
 	if len(threadObjects) > 0 {
-		p.debugf("\nThread objects identified:\n")
+		p.debugf(DebugSummary, "\nThread objects identified:\n")
 		maxThreadSamples := 10
 		for i, threadObj := range threadObjects {
 			if i >= maxThreadSamples {
-				p.debugf("  ... and %d more threads\n", len(threadObjects)-maxThreadSamples)
+				p.debugf(DebugSummary, "  ... and %d more threads\n", len(threadObjects)-maxThreadSamples)
 				break
 			}
 
 			// Try to get the stack trace for this thread
 			if trace, exists := p.stackReg.GetTrace(threadObj.StackTraceSerialNumber); exists {
-				p.debugf("  Thread %d: Object ID 0x%x, Stack trace %d (%d frames)\n",
+				p.debugf(DebugSummary, "  Thread %d: Object ID 0x%x, Stack trace %d (%d frames)\n",
 					threadObj.ThreadSerialNumber,
 					uint64(threadObj.ThreadObjectID),
 					threadObj.StackTraceSerialNumber,
 					trace.NumFrames)
 			} else {
-				p.debugf("  Thread %d: Object ID 0x%x, Stack trace %d (not found)\n",
+				p.debugf(DebugSummary, "  Thread %d: Object ID 0x%x, Stack trace %d (not found)\n",
 					threadObj.ThreadSerialNumber,
 					uint64(threadObj.ThreadObjectID),
 					threadObj.StackTraceSerialNumber)
@@ -447,7 +646,7 @@ func (p *Parser) printSummary() {
 	// Show thread stack root analysis
 	threadSerials := p.rootReg.GetAllThreadSerials()
 	if len(threadSerials) > 0 {
-		p.debugf("\nThread stack memory analysis:\n")
+		p.debugf(DebugSummary, "\nThread stack memory analysis:\n")
 		maxThreadStackSamples := 5
 		for i, threadSerial := range threadSerials {
 			if i >= maxThreadStackSamples {
@@ -455,26 +654,26 @@ func (p *Parser) printSummary() {
 			}
 
 			stackRoots := p.rootReg.GetThreadStackRoots(threadSerial)
-			p.debugf("  Thread %d: %d stack root objects\n", threadSerial, len(stackRoots))
+			p.debugf(DebugSummary, "  Thread %d: %d stack root objects\n", threadSerial, len(stackRoots))
 		}
 	}
 
-	p.debugf("\n--- Class Dump Summary ---\n")
-	p.debugf("Total class dumps: %d\n", p.classDumpReg.GetCount())
+	p.debugf(DebugSummary, "\n--- Class Dump Summary ---\n")
+	p.debugf(DebugSummary, "Total class dumps: %d\n", p.classDumpReg.GetCount())
 
 	// Show sample class dumps
 	allClassDumps := p.classDumpReg.GetAllClassDumps()
 	if len(allClassDumps) > 0 {
-		p.debugf("\nSample class dumps:\n")
+		p.debugf(DebugSummary, "\nSample class dumps:\n")
 		maxClassDumpSamples := 10
 		count := 0
 		for classID, classDump := range allClassDumps {
 			if count >= maxClassDumpSamples {
-				p.debugf("  ... and %d more classes\n", len(allClassDumps)-maxClassDumpSamples)
+				p.debugf(DebugSummary, "  ... and %d more classes\n", len(allClassDumps)-maxClassDumpSamples)
 				break
 			}
 
-			p.debugf("  Class 0x%x: Instance size %d bytes, %d static fields, %d instance fields\n",
+			p.debugf(DebugSummary, "  Class 0x%x: Instance size %d bytes, %d static fields, %d instance fields\n",
 				uint64(classID),
 				classDump.InstanceSize,
 				len(classDump.StaticFields),
@@ -484,25 +683,25 @@ func (p *Parser) printSummary() {
 	}
 
 	// Phase 9: Object Instance Summary
-	p.debugf("\n--- Object Instance Summary ---\n")
-	p.debugf("Total object instances: %d\n", p.objectReg.GetCount())
-	p.debugf("Total instance memory: %d bytes (%.2f MB)\n",
+	p.debugf(DebugSummary, "\n--- Object Instance Summary ---\n")
+	p.debugf(DebugSummary, "Total object instances: %d\n", p.objectReg.GetCount())
+	p.debugf(DebugSummary, "Total instance memory: %d bytes (%.2f MB)\n",
 		p.objectReg.GetTotalSize(),
 		float64(p.objectReg.GetTotalSize())/(1024*1024))
 
 	// Show instance counts by class
 	classCounts := p.objectReg.GetInstanceClassCounts()
 	if len(classCounts) > 0 {
-		p.debugf("\nTop classes by instance count:\n")
+		p.debugf(DebugSummary, "\nTop classes by instance count:\n")
 		// Simple display of first few classes
 		count := 0
 		maxClassInstanceSamples := 10
 		for classID, instanceCount := range classCounts {
 			if count >= maxClassInstanceSamples {
-				p.debugf("  ... and %d more classes\n", len(classCounts)-maxClassInstanceSamples)
+				p.debugf(DebugSummary, "  ... and %d more classes\n", len(classCounts)-maxClassInstanceSamples)
 				break
 			}
-			p.debugf("  Class 0x%x: %d instances\n", uint64(classID), instanceCount)
+			p.debugf(DebugSummary, "  Class 0x%x: %d instances\n", uint64(classID), instanceCount)
 			count++
 		}
 	}
@@ -510,13 +709,13 @@ func (p *Parser) printSummary() {
 	// // Show Thread instances (Phase 9.4)
 	// threadInstanceCount := p.objectReg.GetThreadCount()
 	// if threadInstanceCount > 0 {
-	// 	p.debugf("\nThread Object Instances: %d\n", threadInstanceCount)
+	// 	p.debugf(DebugSummary, "\nThread Object Instances: %d\n", threadInstanceCount)
 	// 	threadInstances := p.objectReg.GetAllThreadInstances()
 	// 	maxThreadInstanceSamples := 5
 	// 	count := 0
 	// 	for objectID, threadData := range threadInstances {
 	// 		if count >= maxThreadInstanceSamples {
-	// 			p.debugf("  ... and %d more thread instances\n", len(threadInstances)-maxThreadInstanceSamples)
+	// 			p.debugf(DebugSummary, "  ... and %d more thread instances\n", len(threadInstances)-maxThreadInstanceSamples)
 	// 			break
 	// 		}
 
@@ -524,7 +723,7 @@ func (p *Parser) printSummary() {
 	// 		if name == "" {
 	// 			name = "<unknown>"
 	// 		}
-	// 		p.debugf("  Thread 0x%x (TID: %d): Name=\"%s\", Priority=%d, Daemon=%t\n",
+	// 		p.debugf(DebugSummary, "  Thread 0x%x (TID: %d): Name=\"%s\", Priority=%d, Daemon=%t\n",
 	// 			uint64(objectID), threadData.ThreadID, name, threadData.Priority, threadData.Daemon)
 	// 		count++
 	// 	}
@@ -533,28 +732,28 @@ func (p *Parser) printSummary() {
 	// Show object statistics
 	stats := p.objectReg.Statistics()
 	if avgSize, ok := stats["average_size"].(float64); ok {
-		p.debugf("\nObject Statistics:\n")
-		p.debugf("  Average object size: %.2f bytes\n", avgSize)
+		p.debugf(DebugSummary, "\nObject Statistics:\n")
+		p.debugf(DebugSummary, "  Average object size: %.2f bytes\n", avgSize)
 		if uniqueClasses, ok := stats["unique_classes"].(int); ok {
-			p.debugf("  Unique classes with instances: %d\n", uniqueClasses)
+			p.debugf(DebugSummary, "  Unique classes with instances: %d\n", uniqueClasses)
 		}
 	}
 
-	p.debugf("\n--- Array Summary ---\n")
-	p.debugf("Total arrays: %d\n", p.arrayReg.GetCount())
-	p.debugf("Object arrays: %d\n", p.arrayReg.GetObjectArrayCount())
-	p.debugf("Primitive arrays: %d\n", p.arrayReg.GetPrimitiveArrayCount())
-	p.debugf("Total array elements: %d\n", p.arrayReg.GetTotalElements())
-	p.debugf("Total array memory: %d bytes (%.2f MB)\n",
+	p.debugf(DebugSummary, "\n--- Array Summary ---\n")
+	p.debugf(DebugSummary, "Total arrays: %d\n", p.arrayReg.GetCount())
+	p.debugf(DebugSummary, "Object arrays: %d\n", p.arrayReg.GetObjectArrayCount())
+	p.debugf(DebugSummary, "Primitive arrays: %d\n", p.arrayReg.GetPrimitiveArrayCount())
+	p.debugf(DebugSummary, "Total array elements: %d\n", p.arrayReg.GetTotalElements())
+	p.debugf(DebugSummary, "Total array memory: %d bytes (%.2f MB)\n",
 		p.arrayReg.GetTotalSize(),
 		float64(p.arrayReg.GetTotalSize())/(1024*1024))
 
 	// Show largest arrays
 	largestArrays := p.arrayReg.GetLargestArrays(5)
 	if len(largestArrays) > 0 {
-		p.debugf("\nLargest arrays:\n")
+		p.debugf(DebugSummary, "\nLargest arrays:\n")
 		for i, array := range largestArrays {
-			p.debugf("  %d. Array 0x%x: %d elements (%s - %s)\n",
+			p.debugf(DebugSummary, "  %d. Array 0x%x: %d elements (%s - %s)\n",
 				i+1, uint64(array.ObjectID), array.Size, array.Type, array.ElementType)
 		}
 	}
@@ -562,10 +761,10 @@ func (p *Parser) printSummary() {
 	// Show array statistics
 	arrayStats := p.arrayReg.Statistics()
 	if avgObjSize, ok := arrayStats["avg_object_array_size"].(float64); ok {
-		p.debugf("\nArray Statistics:\n")
-		p.debugf("  Average object array size: %.2f elements\n", avgObjSize)
+		p.debugf(DebugSummary, "\nArray Statistics:\n")
+		p.debugf(DebugSummary, "  Average object array size: %.2f elements\n", avgObjSize)
 		if avgPrimSize, ok := arrayStats["avg_primitive_array_size"].(float64); ok {
-			p.debugf("  Average primitive array size: %.2f elements\n", avgPrimSize)
+			p.debugf(DebugSummary, "  Average primitive array size: %.2f elements\n", avgPrimSize)
 		}
 	}
 }
@@ -577,25 +776,69 @@ func (p *Parser) printSummary() {
 // [Record 2]
 // ...
 // [Record N]
+//
+// The dump stays open afterwards so strings can be loaded on demand; call Close when done.
 func (p *Parser) ParseHprof() error {
-	defer p.Close()
-
-	p.debugf("🔍 Starting HPROF analysis of: %s\n", p.file.Name())
+	p.debugf(DebugSummary, "🔍 Starting HPROF analysis of: %s\n", p.file.Name())
+	if p.gzReader != nil {
+		p.debugf(DebugSummary, "Gzip-compressed dump, decompressing on the fly\n")
+	}
 
 	if err := p.parseHeader(); err != nil {
 		return err
 	}
 
-	if err := p.parseRecords(); err != nil {
+	start := time.Now()
+	if p.data != nil && p.workers > 1 {
+		p.segments = newSegmentPool(p, p.workers)
+		p.debugf(DebugSummary, "Parsing heap dump segments with %d workers (mmap)\n\n", p.workers)
+	}
+
+	err := p.parseRecords()
+	if p.segments != nil {
+		// Always drain the pool so no worker outlives the mapping
+		if poolErr := p.segments.wait(); err == nil {
+			err = poolErr
+		}
+		p.segments = nil
+	}
+	if err != nil {
 		return err
 	}
+	p.debugf(DebugSummary, "Records parsed in %s\n\n", time.Since(start))
+	p.reportProgress(p.reader.BytesRead())
 
 	p.printSummary()
-	p.debugf("--- PARSING COMPLETE ---\n")
+	p.debugf(DebugSummary, "--- PARSING COMPLETE ---\n")
 
 	return nil
 }
 
+// IsTruncated reports whether the dump ended before it was complete
+func (p *Parser) IsTruncated() bool {
+	return p.truncated
+}
+
+// IsInterrupted reports whether parsing was stopped early through SetContext
+func (p *Parser) IsInterrupted() bool {
+	return p.interrupted
+}
+
+// GetTruncation returns the offset of the last readable record and why parsing stopped
+func (p *Parser) GetTruncation() (int64, string) {
+	return p.truncatedAt, p.truncatedReason
+}
+
+// IsCompressed reports whether the dump is gzip-compressed
+func (p *Parser) IsCompressed() bool {
+	return p.gzReader != nil
+}
+
+// GetIndex returns the sidecar index built during parsing
+func (p *Parser) GetIndex() *HeapIndex {
+	return p.index
+}
+
 // GetHeader returns the parsed header
 func (p *Parser) GetHeader() *model.HprofHeader {
 	return p.header
@@ -606,6 +849,11 @@ func (p *Parser) GetStringRegistry() *registry.StringRegistry {
 	return p.stringReg
 }
 
+// GetClassRegistry returns the class registry
+func (p *Parser) GetClassRegistry() *registry.ClassRegistry {
+	return p.classReg
+}
+
 // GetClassDumpRegistry returns the class dump registry
 func (p *Parser) GetClassDumpRegistry() *registry.ClassDumpRegistry {
 	return p.classDumpReg
@@ -625,3 +873,8 @@ func (p *Parser) GetGCRootRegistry() *registry.GCRootRegistry {
 func (p *Parser) GetArrayRegistry() *registry.ArrayRegistry {
 	return p.arrayReg
 }
+
+// GetStackRegistry returns the stack frame and trace registry
+func (p *Parser) GetStackRegistry() *registry.StackRegistry {
+	return p.stackReg
+}
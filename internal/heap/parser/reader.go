@@ -2,6 +2,7 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -22,6 +23,16 @@ func NewBinaryReader(reader io.Reader) *BinaryReader {
 	}
 }
 
+// NewBinaryReaderAt reads a slice of an in-memory dump, reporting offsets
+// relative to the start of the whole file
+func NewBinaryReaderAt(data []byte, offset int64, header *model.HprofHeader) *BinaryReader {
+	return &BinaryReader{
+		reader:    bufio.NewReader(bytes.NewReader(data)),
+		bytesRead: offset,
+		header:    header,
+	}
+}
+
 func (br *BinaryReader) BytesRead() int64 {
 	return br.bytesRead
 }
@@ -133,7 +144,8 @@ func (br *BinaryReader) ReadBytes(buf []byte) error {
 
 // Skip skips n bytes in the stream
 func (br *BinaryReader) Skip(n int) error {
-	_, err := br.ReadNBytes(n) // Just discard the data
+	discarded, err := br.reader.Discard(n)
+	br.bytesRead += int64(discarded)
 	if err != nil {
 		return fmt.Errorf("failed to skip %d bytes: %w", n, err)
 	}
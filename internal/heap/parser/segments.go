@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+*	Parallel HEAP_DUMP_SEGMENT parsing over a memory-mapped dump.
+*
+*	Segments are self-contained: every sub-record is addressed by object ID
+*	and the registries are safe for concurrent use, so segments can be parsed
+*	in any order. The main loop only reads record headers and hands each
+*	segment's byte range to a worker.
+*
+*	The job queue holds at most one pending segment per worker, which keeps
+*	the number of segments being decoded (and their allocations) bounded
+*	regardless of dump size.
+ */
+
+type segmentJob struct {
+	number    int
+	offset    int64
+	length    uint32
+	truncated bool // Final segment cut short; running out of data is expected
+}
+
+type segmentPool struct {
+	parser *Parser
+	jobs   chan segmentJob
+	wg     sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// DefaultWorkers is the number of segment workers used when none is configured
+func DefaultWorkers() int {
+	return runtime.NumCPU()
+}
+
+func newSegmentPool(p *Parser, workers int) *segmentPool {
+	pool := &segmentPool{
+		parser: p,
+		jobs:   make(chan segmentJob, workers),
+	}
+
+	for range workers {
+		pool.wg.Add(1)
+		go pool.work()
+	}
+
+	return pool
+}
+
+func (sp *segmentPool) work() {
+	defer sp.wg.Done()
+
+	for job := range sp.jobs {
+		if err := sp.parseSegment(job); err != nil && !(job.truncated && isTruncation(err)) {
+			sp.mu.Lock()
+			if sp.err == nil {
+				sp.err = err
+			}
+			sp.mu.Unlock()
+		}
+	}
+}
+
+func (sp *segmentPool) parseSegment(job segmentJob) error {
+	p := sp.parser
+	end := job.offset + int64(job.length)
+	if end > int64(len(p.data)) {
+		return fmt.Errorf("segment #%d extends past end of file (%d > %d)", job.number, end, len(p.data))
+	}
+
+	reader := NewBinaryReaderAt(p.data[job.offset:end], job.offset, p.header)
+	subRecordCount, subRecordCountMap, err := ParseHeapDumpSegment(reader, job.length,
+		p.rootReg, p.classDumpReg, p.objectReg, p.stringReg, p.arrayReg)
+	if err != nil {
+		return fmt.Errorf("failed to parse HEAP_DUMP_SEGMENT #%d: %w", job.number, err)
+	}
+
+	p.debugSegment(job.number, job.length, subRecordCount, subRecordCountMap)
+	return nil
+}
+
+// submit queues a segment, blocking while all workers are busy
+func (sp *segmentPool) submit(job segmentJob) {
+	sp.jobs <- job
+}
+
+// wait blocks until every queued segment has been parsed
+func (sp *segmentPool) wait() error {
+	close(sp.jobs)
+	sp.wg.Wait()
+	return sp.err
+}
+
+// debugSegment writes the per-segment summary as a single block so output
+// from concurrent workers doesn't interleave
+func (p *Parser) debugSegment(number int, length uint32, subRecordCount int,
+	subRecordCountMap map[model.HProfTagSubRecord]int) {
+	if !p.debug.enabled(DebugRecords) {
+		return
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "  Segment #%d: %d bytes, %d sub-records\n", number, length, subRecordCount)
+	sb.WriteString("  Sub-record breakdown:\n")
+	for subRecordType, count := range subRecordCountMap {
+		fmt.Fprintf(&sb, "    %s: %d\n", subRecordType, count)
+	}
+
+	p.debugf(DebugRecords, "%s", sb.String())
+}
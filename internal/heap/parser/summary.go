@@ -0,0 +1,285 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+/*
+*	Summarize reads a dump for `jdiag heap info` without parsing its heap.
+*
+*	Only the header, the top-level record headers and the UTF8 and LOAD_CLASS
+*	records are read; heap dump segments are skipped, apart from a sample of
+*	about SummarySampleBytes spread evenly over them. Sub-records in the
+*	sample are counted by tag and skipped by their size, never decoded, and
+*	the counts are scaled up by the bytes the sample covered. When every
+*	segment fits in the sample the counts are exact.
+*
+*	Compressed dumps have to be inflated to be skipped and their size isn't
+*	known up front, so their sample is the start of the heap instead.
+ */
+
+// SummarySampleBytes is how much of the heap Summarize counts sub-records in
+const SummarySampleBytes = 64 << 20
+
+// DumpSummary describes a dump from its records alone
+type DumpSummary struct {
+	Format         string
+	IdentifierSize uint32
+	Timestamp      time.Time
+	FileSize       int64 // On disk, so compressed for a gzipped dump
+	Compressed     bool
+
+	Records      int
+	RecordCounts map[model.HProfTagRecord]int
+	Strings      int
+	Classes      int // LOAD_CLASS records
+	Segments     int
+	HeapBytes    int64 // Bytes of heap dump segments
+	SampledBytes int64 // Of HeapBytes, the ones whose sub-records were counted
+
+	// Sub-records by tag, scaled from the sample
+	SubRecords map[model.HProfTagSubRecord]int64
+
+	Truncated       bool
+	TruncatedReason string
+	Elapsed         time.Duration
+}
+
+// Exact reports whether the sample covered the whole heap, so the counts are exact
+func (s *DumpSummary) Exact() bool {
+	return s.SampledBytes == s.HeapBytes
+}
+
+// Objects is the number of instances and arrays
+func (s *DumpSummary) Objects() int64 {
+	return s.SubRecords[model.HPROF_GC_INSTANCE_DUMP] + s.SubRecords[model.HPROF_GC_OBJ_ARRAY_DUMP] +
+		s.SubRecords[model.HPROF_GC_PRIM_ARRAY_DUMP]
+}
+
+// GCRoots is the number of GC root sub-records of every kind; only meaningful when Exact
+func (s *DumpSummary) GCRoots() int64 {
+	var roots int64
+	for tag, count := range s.SubRecords {
+		switch tag {
+		case model.HPROF_GC_CLASS_DUMP, model.HPROF_GC_INSTANCE_DUMP, model.HPROF_GC_OBJ_ARRAY_DUMP, model.HPROF_GC_PRIM_ARRAY_DUMP:
+		default:
+			roots += count
+		}
+	}
+	return roots
+}
+
+// Summarize reads the dump's records without parsing the heap; use it instead of ParseHprof
+func (p *Parser) Summarize() (*DumpSummary, error) {
+	start := time.Now()
+	if err := p.parseHeader(); err != nil {
+		return nil, err
+	}
+
+	summary := &DumpSummary{
+		Format:         p.header.Format,
+		IdentifierSize: p.header.IdentifierSize,
+		Timestamp:      p.header.Timestamp,
+		Compressed:     p.gzReader != nil,
+		RecordCounts:   make(map[model.HProfTagRecord]int),
+		SubRecords:     make(map[model.HProfTagSubRecord]int64),
+	}
+	if info, err := p.file.Stat(); err == nil {
+		summary.FileSize = info.Size()
+	}
+	sampled := make(map[model.HProfTagSubRecord]int64)
+
+	for {
+		cursor := p.reader.BytesRead()
+		record, err := p.reader.ReadRecordHeader()
+		if err == io.EOF {
+			if summary.Segments > 0 && summary.RecordCounts[model.HPROF_HEAP_DUMP_END] == 0 {
+				p.markTruncated(cursor, "HEAP_DUMP_END record missing")
+			}
+			break
+		}
+		if isTruncation(err) {
+			p.markTruncated(cursor, "incomplete record header")
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record header at offset %d: %w", cursor, err)
+		}
+
+		summary.Records++
+		summary.RecordCounts[record.Type]++
+
+		switch record.Type {
+		case model.HPROF_UTF8:
+			err = p.parseUTF8Record(record.Length)
+			summary.Strings++
+		case model.HPROF_LOAD_CLASS:
+			err = p.parseLoadClassRecord()
+			summary.Classes++
+		case model.HPROF_HEAP_DUMP, model.HPROF_HEAP_DUMP_SEGMENT:
+			summary.Segments++
+			summary.HeapBytes += int64(record.Length)
+			err = p.sampleSegment(summary, sampled, record.Length)
+		default:
+			err = p.skipRecordData(record.Length)
+		}
+		if isTruncation(err) {
+			p.markTruncated(cursor, fmt.Sprintf("incomplete %s record: %v", record.Type, err))
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Scale the sample up to the whole heap
+	for tag, count := range sampled {
+		if summary.Exact() {
+			summary.SubRecords[tag] = count
+		} else if summary.SampledBytes > 0 {
+			summary.SubRecords[tag] = int64(float64(count) * float64(summary.HeapBytes) / float64(summary.SampledBytes))
+		}
+	}
+
+	summary.Truncated, summary.TruncatedReason = p.truncated, p.truncatedReason
+	summary.Elapsed = time.Since(start)
+	return summary, nil
+}
+
+/*
+ * sampleSegment counts the sub-records at the start of a segment, as many as
+ * the sample has room for given how far through the dump the segment ends,
+ * and skips the rest of it. A sub-record is counted whole even when it goes
+ * past that point.
+ */
+func (p *Parser) sampleSegment(summary *DumpSummary, counts map[model.HProfTagSubRecord]int64, length uint32) error {
+	end := p.reader.BytesRead() + int64(length)
+
+	budget := int64(SummarySampleBytes)
+	if p.totalBytes > 0 {
+		budget = int64(float64(SummarySampleBytes) * float64(end) / float64(p.totalBytes))
+	}
+	allowance := min(budget-summary.SampledBytes, int64(length))
+
+	start := p.reader.BytesRead()
+	for p.reader.BytesRead()-start < allowance {
+		tag, err := p.reader.ReadU1()
+		if err != nil {
+			return err
+		}
+		if err := p.skipSubRecord(model.HProfTagSubRecord(tag)); err != nil {
+			return err
+		}
+		counts[model.HProfTagSubRecord(tag)]++
+	}
+	if p.reader.BytesRead() > end {
+		return fmt.Errorf("sub-record crossed the end of the segment at offset %d", end)
+	}
+	summary.SampledBytes += p.reader.BytesRead() - start
+
+	return p.skipRecordData(uint32(end - p.reader.BytesRead()))
+}
+
+// skipSubRecord skips a sub-record's body by the sizes in it, without decoding the values
+func (p *Parser) skipSubRecord(tag model.HProfTagSubRecord) error {
+	idSize := int(p.header.IdentifierSize)
+
+	switch tag {
+	case model.HPROF_GC_ROOT_UNKNOWN, model.HPROF_GC_ROOT_STICKY_CLASS, model.HPROF_GC_ROOT_MONITOR_USED:
+		return p.reader.Skip(idSize)
+	case model.HPROF_GC_ROOT_JNI_GLOBAL:
+		return p.reader.Skip(2 * idSize)
+	case model.HPROF_GC_ROOT_NATIVE_STACK, model.HPROF_GC_ROOT_THREAD_BLOCK:
+		return p.reader.Skip(idSize + 4)
+	case model.HPROF_GC_ROOT_JNI_LOCAL, model.HPROF_GC_ROOT_JAVA_FRAME, model.HPROF_GC_ROOT_THREAD_OBJ:
+		return p.reader.Skip(idSize + 8)
+
+	case model.HPROF_GC_INSTANCE_DUMP:
+		if err := p.reader.Skip(2*idSize + 4); err != nil {
+			return err
+		}
+		length, err := p.reader.ReadU4()
+		if err != nil {
+			return err
+		}
+		return p.reader.Skip(int(length))
+
+	case model.HPROF_GC_OBJ_ARRAY_DUMP:
+		if err := p.reader.Skip(idSize + 4); err != nil {
+			return err
+		}
+		count, err := p.reader.ReadU4()
+		if err != nil {
+			return err
+		}
+		return p.reader.Skip(idSize + int(count)*idSize)
+
+	case model.HPROF_GC_PRIM_ARRAY_DUMP:
+		if err := p.reader.Skip(idSize + 4); err != nil {
+			return err
+		}
+		count, err := p.reader.ReadU4()
+		if err != nil {
+			return err
+		}
+		elementType, err := p.reader.ReadU1()
+		if err != nil {
+			return err
+		}
+		size := model.HProfTagFieldType(elementType).Size(p.header.IdentifierSize)
+		if size == 0 {
+			return fmt.Errorf("unknown array element type 0x%x at offset %d", elementType, p.reader.BytesRead())
+		}
+		return p.reader.Skip(int(count) * size)
+
+	case model.HPROF_GC_CLASS_DUMP:
+		return p.skipClassDump()
+	}
+	return fmt.Errorf("unknown sub-record 0x%x at offset %d", byte(tag), p.reader.BytesRead()-1)
+}
+
+// skipClassDump skips a CLASS_DUMP, whose constant pool, static and instance fields come one by one
+func (p *Parser) skipClassDump() error {
+	idSize := int(p.header.IdentifierSize)
+
+	// Class, stack trace, super, loader, signers, protection domain, two reserved, instance size
+	if err := p.reader.Skip(7*idSize + 4 + 4); err != nil {
+		return err
+	}
+
+	// Constant pool (u2 index, type, value), statics (name, type, value), instance fields (name, type)
+	entries := []struct {
+		prefix    int
+		withValue bool
+	}{{2, true}, {idSize, true}, {idSize, false}}
+	for _, entry := range entries {
+		count, err := p.reader.ReadU2()
+		if err != nil {
+			return err
+		}
+		for range count {
+			if err := p.reader.Skip(entry.prefix); err != nil {
+				return err
+			}
+			fieldType, err := p.reader.ReadU1()
+			if err != nil {
+				return err
+			}
+			if !entry.withValue {
+				continue
+			}
+			size := model.HProfTagFieldType(fieldType).Size(p.header.IdentifierSize)
+			if size == 0 {
+				return fmt.Errorf("unknown field type 0x%x at offset %d", fieldType, p.reader.BytesRead())
+			}
+			if err := p.reader.Skip(size); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
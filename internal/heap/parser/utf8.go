@@ -12,9 +12,12 @@ import (
 *
 *	id   		ID for this string
 *	[u1]*		UTF-8 characters (no null terminator)
+*
+*	When lazy is set only the text's offset is recorded; the registry reads
+*	it from the dump the first time the string is looked up.
  */
 func ParseUTF8(reader *BinaryReader, length uint32,
-	stringReg *registry.StringRegistry,
+	stringReg *registry.StringRegistry, lazy bool,
 ) (*model.UTF8Body, error) {
 	stringID, err := reader.ReadID()
 	if err != nil {
@@ -27,6 +30,15 @@ func ParseUTF8(reader *BinaryReader, length uint32,
 		return nil, fmt.Errorf("invalid string length: %d", stringLength)
 	}
 
+	if lazy {
+		offset := reader.BytesRead()
+		if err := reader.Skip(stringLength); err != nil {
+			return nil, fmt.Errorf("failed to skip string data: %w", err)
+		}
+		stringReg.AddLocation(stringID, offset, uint32(stringLength))
+		return &model.UTF8Body{StringID: stringID}, nil
+	}
+
 	// Read the string bytes (UTF-8 encoded, no null terminator)
 	text, err := reader.ReadUtf8String(stringLength)
 	if err != nil {
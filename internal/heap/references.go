@@ -0,0 +1,114 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const maxReferenceOwners = 15
+
+// RunHeapReferences prints how much of the heap is held only by soft, weak, final or phantom references
+func RunHeapReferences(filename string, config *Config) error {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	stats := heapAnalyzer.GetReferenceStats()
+	if stats == nil {
+		return fmt.Errorf("reference analysis produced no results")
+	}
+
+	printReferenceStats(stats)
+	return nil
+}
+
+func printReferenceStats(stats *analyzer.ReferenceStats) {
+	total := stats.StronglyReachableSize
+	for _, kind := range analyzer.ReferenceKinds {
+		total += stats.Kinds[kind].ReachableSize
+	}
+
+	percent := func(size uint64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(size) / float64(total) * 100
+	}
+
+	fmt.Println()
+	fmt.Println("🔗 REFERENCE REACHABILITY")
+	fmt.Printf("Reachable heap: %s\n\n", utils.MemorySize(total).String())
+
+	fmt.Printf("%-10s  %10s  %10s  %12s  %6s  %s\n", "Kind", "References", "Referents", "Only held", "%", "Objects")
+	fmt.Println(strings.Repeat("─", 70))
+	fmt.Printf("%-10s  %10s  %10s  %12s  %6s  %d\n", "strong", "-", "-",
+		utils.MemorySize(stats.StronglyReachableSize).String(), utils.FormatPercent(percent(stats.StronglyReachableSize)),
+		stats.StronglyReachableCount)
+
+	for _, kind := range analyzer.ReferenceKinds {
+		kindStats := stats.Kinds[kind]
+		fmt.Printf("%-10s  %10d  %10d  %12s  %6s  %d\n", kind, kindStats.Count, kindStats.LiveReferents,
+			utils.MemorySize(kindStats.ReachableSize).String(), utils.FormatPercent(percent(kindStats.ReachableSize)),
+			kindStats.ReachableCount)
+	}
+
+	reclaimable := stats.ReclaimableSize()
+	fmt.Println()
+	if reclaimable > 0 {
+		fmt.Println(utils.GoodStyle.Render(fmt.Sprintf(
+			"♻️  %s (%s) can be freed under memory pressure (soft + weak)",
+			utils.MemorySize(reclaimable).String(), utils.FormatPercent(percent(reclaimable)))))
+	}
+	fmt.Println(utils.InfoStyle.Render(fmt.Sprintf(
+		"🔒 %s (%s) is strongly retained and survives any GC",
+		utils.MemorySize(stats.StronglyReachableSize).String(), utils.FormatPercent(percent(stats.StronglyReachableSize)))))
+
+	for _, kind := range analyzer.ReferenceKinds {
+		kindStats := stats.Kinds[kind]
+		if kindStats.LiveReferents == 0 {
+			continue
+		}
+
+		var referents []string
+		for _, className := range kindStats.TopReferents(5) {
+			referents = append(referents, fmt.Sprintf("%s (%d)", className, kindStats.Referents[className]))
+		}
+		fmt.Printf("\nTop %s referents: %s\n", kind, strings.Join(referents, ", "))
+	}
+
+	printReferenceOwners(stats)
+}
+
+// printReferenceOwners lists Reference subclasses by the memory only they keep alive,
+// which points at soft caches and weak maps
+func printReferenceOwners(stats *analyzer.ReferenceStats) {
+	var owners []*analyzer.ReferenceOwner
+	for _, owner := range stats.Owners {
+		if owner.ReachableSize > 0 {
+			owners = append(owners, owner)
+		}
+	}
+	if len(owners) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("🗄️  MEMORY HELD BY REFERENCE TYPE")
+	fmt.Printf("%-50s  %-8s  %10s  %12s\n", "Reference class", "Kind", "Count", "Only held")
+	fmt.Println(strings.Repeat("─", 86))
+
+	for i, owner := range owners {
+		if i == maxReferenceOwners {
+			fmt.Printf("... and %d more\n", len(owners)-i)
+			break
+		}
+		fmt.Printf("%-50s  %-8s  %10d  %12s\n", utils.TruncateString(owner.ClassName, 50), owner.Kind,
+			owner.Count, utils.MemorySize(owner.ReachableSize).String())
+	}
+}
@@ -21,6 +21,10 @@ type ArrayRegistry struct {
 	totalElements   int64
 	totalArraySize  uint64
 	mu              sync.RWMutex
+
+	// Primitive arrays were parsed without their elements (structure-only), so no
+	// char[] or byte[] can be read back as text
+	contentsSkipped bool
 }
 
 func NewArrayRegistry() *ArrayRegistry {
@@ -47,6 +51,16 @@ func (r *ArrayRegistry) AddPrimitiveArray(array *model.GCPrimitiveArrayDump) {
 	r.mu.Unlock()
 }
 
+// SetContentsSkipped marks primitive arrays as parsed without their elements; set it before parsing
+func (r *ArrayRegistry) SetContentsSkipped(skipped bool) {
+	r.contentsSkipped = skipped
+}
+
+// ContentsSkipped reports whether primitive arrays were parsed without their elements
+func (r *ArrayRegistry) ContentsSkipped() bool {
+	return r.contentsSkipped
+}
+
 func (r *ArrayRegistry) GetObjectArray(arrayID model.ID) (*model.GCObjectArrayDump, bool) {
 	return r.objectArrays.Get(arrayID)
 }
@@ -89,7 +103,7 @@ func (r *ArrayRegistry) GetTotalSize() uint64 {
 
 func (r *ArrayRegistry) GetCharArray(arrayID model.ID) (string, bool) {
 	array, exists := r.primitiveArrays.Get(arrayID)
-	if !exists || array.Type != model.HPROF_CHAR {
+	if !exists || array.Type != model.HPROF_CHAR || r.contentsSkipped {
 		return "", false
 	}
 	return r.convertCharArrayToString(array.Elements), true
@@ -97,7 +111,7 @@ func (r *ArrayRegistry) GetCharArray(arrayID model.ID) (string, bool) {
 
 func (r *ArrayRegistry) GetByteArray(arrayID model.ID) (string, bool) {
 	array, exists := r.primitiveArrays.Get(arrayID)
-	if !exists || array.Type != model.HPROF_BYTE {
+	if !exists || array.Type != model.HPROF_BYTE || r.contentsSkipped {
 		return "", false
 	}
 	return r.convertByteArrayToString(array.Elements), true
@@ -2,18 +2,163 @@ package registry
 
 import (
 	"fmt"
+	"io"
+	"maps"
+	"sync"
+	"unique"
 
 	"github.com/mabhi256/jdiag/internal/heap/model"
 )
 
+// stringLocation is where a UTF8 record's text lives in the dump file
+type stringLocation struct {
+	offset int64
+	length uint32
+}
+
+/*
+ * StringRegistry holds the HPROF string table.
+ *
+ * Large dumps carry tens of millions of UTF8 records, but analysis only ever
+ * looks at class, field and method names. When the dump supports random access
+ * the parser records each string's file offset instead of its text, and the
+ * text is read on first lookup. Materialized strings are interned, so names
+ * repeated across records share one copy.
+ *
+ * Compressed dumps can't be read at an offset, so their strings are stored
+ * eagerly (still interned).
+ */
 type StringRegistry struct {
-	*BaseRegistry[model.ID, string]
+	mu       sync.RWMutex
+	strings  map[model.ID]string         // Eagerly added or already resolved
+	located  map[model.ID]stringLocation // Not yet read from the source
+	source   io.ReaderAt                 // Dump contents for lazy lookups
+	resolved int                         // Lazily located strings read so far
 }
 
 func NewStringRegistry() *StringRegistry {
 	return &StringRegistry{
-		BaseRegistry: NewBaseRegistry[model.ID, string](),
+		strings: make(map[model.ID]string),
+		located: make(map[model.ID]stringLocation),
+	}
+}
+
+// SetSource sets where located strings are read from. Passing nil (e.g. when the
+// dump is closed) makes unresolved located strings report as missing.
+func (r *StringRegistry) SetSource(source io.ReaderAt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source = source
+}
+
+// Add stores a string's text
+func (r *StringRegistry) Add(stringID model.ID, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strings[stringID] = unique.Make(value).Value()
+	delete(r.located, stringID)
+}
+
+// AddLocation records where a string's text is in the dump without reading it
+func (r *StringRegistry) AddLocation(stringID model.ID, offset int64, length uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.located[stringID] = stringLocation{offset: offset, length: length}
+	delete(r.strings, stringID)
+}
+
+// Get returns a string's text, reading it from the dump on first use
+func (r *StringRegistry) Get(stringID model.ID) (string, bool) {
+	value, exists, read := r.lookup(stringID)
+	if exists || !read {
+		return value, exists
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, stillLocated := r.located[stringID]; stillLocated {
+		r.strings[stringID] = value
+		delete(r.located, stringID)
+		r.resolved++
+	}
+	return value, true
+}
+
+/*
+ * The source may be a memory-mapped dump, which Close unmaps right after
+ * SetSource(nil). Reading it after releasing the lock would let that happen
+ * mid-read and fault instead of failing, so the read lock is held across
+ * ReadAt: SetSource waits for reads in flight, and later ones see nil.
+ */
+
+// lookup returns a stored string, or reads a located one from the source without storing it
+func (r *StringRegistry) lookup(stringID model.ID) (value string, exists, read bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if value, exists := r.strings[stringID]; exists {
+		return value, true, false
+	}
+	location, located := r.located[stringID]
+	if !located || r.source == nil {
+		return "", false, false
 	}
+
+	buf := make([]byte, location.length)
+	if _, err := r.source.ReadAt(buf, location.offset); err != nil {
+		return "", false, false
+	}
+	return unique.Make(string(buf)).Value(), false, true
+}
+
+// GetAll returns every string, reading all located strings from the dump
+func (r *StringRegistry) GetAll() map[model.ID]string {
+	for _, id := range r.GetIDs() {
+		r.Get(id)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[model.ID]string, len(r.strings))
+	maps.Copy(result, r.strings)
+	return result
+}
+
+// GetIDs returns every string ID without reading any text
+func (r *StringRegistry) GetIDs() []model.ID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]model.ID, 0, len(r.strings)+len(r.located))
+	for id := range r.strings {
+		ids = append(ids, id)
+	}
+	for id := range r.located {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Count returns the number of strings, whether or not they have been read
+func (r *StringRegistry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.strings) + len(r.located)
+}
+
+// MaterializedCount returns how many strings are held in memory
+func (r *StringRegistry) MaterializedCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.strings)
+}
+
+// Clear removes all strings
+func (r *StringRegistry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strings = make(map[model.ID]string)
+	r.located = make(map[model.ID]stringLocation)
+	r.resolved = 0
 }
 
 func (r *StringRegistry) AddString(stringID model.ID, value string) {
@@ -30,8 +175,11 @@ func (r *StringRegistry) GetAllStrings() map[model.ID]string {
 
 // HasString checks if a string ID exists in the registry
 func (r *StringRegistry) HasString(stringID model.ID) bool {
-	_, exists := r.Get(stringID)
-	return exists
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.strings[stringID]
+	_, located := r.located[stringID]
+	return exists || located
 }
 
 // GetOrUnresolved returns the string value or a fallback for unresolved IDs
@@ -46,14 +194,16 @@ func (r *StringRegistry) GetCount() int {
 	return r.Count()
 }
 
+// Statistics reports string lengths without reading located strings from the dump
 func (r *StringRegistry) Statistics() map[string]interface{} {
-	allStrings := r.GetAllStrings()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	totalLength := 0
 	maxLength := 0
 	minLength := int(^uint(0) >> 1) // Max int
 
-	for _, str := range allStrings {
-		length := len(str)
+	record := func(length int) {
 		totalLength += length
 		if length > maxLength {
 			maxLength = length
@@ -62,8 +212,14 @@ func (r *StringRegistry) Statistics() map[string]interface{} {
 			minLength = length
 		}
 	}
+	for _, str := range r.strings {
+		record(len(str))
+	}
+	for _, location := range r.located {
+		record(int(location.length))
+	}
 
-	count := len(allStrings)
+	count := len(r.strings) + len(r.located)
 	avgLength := 0.0
 	if count > 0 {
 		avgLength = float64(totalLength) / float64(count)
@@ -75,5 +231,7 @@ func (r *StringRegistry) Statistics() map[string]interface{} {
 		"average_length": avgLength,
 		"max_length":     maxLength,
 		"min_length":     minLength,
+		"materialized":   len(r.strings),
+		"lazy_resolved":  r.resolved,
 	}
 }
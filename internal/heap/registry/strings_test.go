@@ -0,0 +1,68 @@
+package registry
+
+import (
+	"testing"
+	"time"
+)
+
+// blockingSource is a dump whose reads wait for release, recording whether one ran after close
+type blockingSource struct {
+	data    []byte
+	entered chan struct{}
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func (s *blockingSource) ReadAt(p []byte, offset int64) (int, error) {
+	close(s.entered)
+	<-s.release
+	select {
+	case <-s.closed:
+		panic("read from a closed source")
+	default:
+	}
+	return copy(p, s.data[offset:]), nil
+}
+
+// TestStringCloseWaitsForReads checks that clearing the source, as Close does before
+// unmapping the dump, waits for a lazy read that is already in flight
+func TestStringCloseWaitsForReads(t *testing.T) {
+	source := &blockingSource{
+		data:    []byte("java.lang.String"),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	r := NewStringRegistry()
+	r.AddLocation(1, 0, uint32(len(source.data)))
+	r.SetSource(source)
+
+	got := make(chan string)
+	go func() {
+		value, _ := r.Get(1)
+		got <- value
+	}()
+	<-source.entered
+
+	cleared := make(chan struct{})
+	go func() {
+		r.SetSource(nil)
+		close(source.closed) // The dump would be unmapped here
+		close(cleared)
+	}()
+
+	select {
+	case <-cleared:
+		t.Fatal("source cleared while a read was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(source.release)
+
+	if value := <-got; value != "java.lang.String" {
+		t.Errorf("got %q, want java.lang.String", value)
+	}
+	<-cleared
+	if _, ok := r.Get(1); !ok {
+		t.Error("string read before the close is gone")
+	}
+}
@@ -0,0 +1,74 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const maxResourceClasses = 20
+
+// RunHeapResources prints the census of file, socket, channel and JDBC objects in the dump
+func RunHeapResources(filename string, config *Config) error {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	census := analyzer.CensusResources(heapAnalyzer.GetContext(), heapAnalyzer.GetDominatorTree())
+	printResourceCensus(census)
+	return nil
+}
+
+func printResourceCensus(census *analyzer.ResourceCensus) {
+	fmt.Println()
+	fmt.Println("🔌 RESOURCE CENSUS")
+	if len(census.Classes) == 0 {
+		fmt.Println("No file, socket, channel or JDBC objects found")
+		return
+	}
+	fmt.Println(utils.MutedStyle.Render("One open socket counts as a socket, a socket impl and a file descriptor"))
+	fmt.Println()
+
+	fmt.Printf("%-16s  %8s  %8s  %8s  %8s  %s\n", "Kind", "Total", "Open", "Closed", "Unknown", "Open, unreachable")
+	fmt.Println(strings.Repeat("─", 75))
+	unreachableOpen := 0
+	for _, kind := range analyzer.ResourceKinds {
+		count := census.Kinds[kind]
+		if count.Total == 0 {
+			continue
+		}
+		fmt.Printf("%-16s  %8s  %8s  %8s  %8s  %s\n", kind, utils.FormatCount(int64(count.Total)),
+			utils.FormatCount(int64(count.Open)), utils.FormatCount(int64(count.Closed)),
+			utils.FormatCount(int64(count.Unknown)), utils.FormatCount(int64(count.UnreachableOpen)))
+		unreachableOpen += count.UnreachableOpen
+	}
+
+	fmt.Println()
+	fmt.Printf("%8s  %8s  %8s  %-16s  %s\n", "Open", "Closed", "Unknown", "Kind", "Class")
+	fmt.Println(strings.Repeat("─", 75))
+	for _, class := range census.Classes[:min(len(census.Classes), maxResourceClasses)] {
+		line := fmt.Sprintf("%8s  %8s  %8s  %-16s  %s", utils.FormatCount(int64(class.Open)),
+			utils.FormatCount(int64(class.Closed)), utils.FormatCount(int64(class.Unknown)), class.Kind, class.ClassName)
+		if class.UnreachableOpen > 0 {
+			line = utils.WarningStyle.Render(line)
+		}
+		fmt.Println(line)
+	}
+	if len(census.Classes) > maxResourceClasses {
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("... and %d more classes", len(census.Classes)-maxResourceClasses)))
+	}
+
+	fmt.Println()
+	if unreachableOpen > 0 {
+		fmt.Println(utils.WarningStyle.Render(fmt.Sprintf(
+			"⚠️  %s open resources are unreachable: their handles stay open until a Cleaner or finalizer runs, which points at code that doesn't close them",
+			utils.FormatCount(int64(unreachableOpen)))))
+	} else {
+		fmt.Println(utils.GoodStyle.Render("✅ Every open resource is still reachable"))
+	}
+}
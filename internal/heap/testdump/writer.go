@@ -0,0 +1,469 @@
+// Package testdump writes small HPROF heap dumps with a known object graph.
+//
+// Checking real dumps in would add gigabytes to the repo and leave every
+// expected number to be worked out by hand from an opaque graph. Building the
+// dump in code instead puts the graph next to what it should produce: which
+// object dominates which, what each retains, the path from a GC root.
+//
+//	w := testdump.NewWriter(file, 8)
+//	node := w.Class("Node", nil, testdump.Field{Name: "next", Type: model.HPROF_NORMAL_OBJECT})
+//	a, b := w.NewID(), w.NewID()
+//	w.InstanceAt(a, node, b) // a -> b -> a: a cycle
+//	w.InstanceAt(b, node, a)
+//	w.Root(model.HPROF_GC_ROOT_JNI_GLOBAL, a)
+//	err := w.Close()
+//
+// Object IDs are handed out 16 bytes apart, like addresses, so an object can
+// be referenced before it is written; that's how cycles are built.
+package testdump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+const (
+	firstID    = 0x1000
+	idSpacing  = 16
+	segmentMax = 1 << 20 // Sub-record bytes per HEAP_DUMP_SEGMENT, so parallel parsing has work to share
+)
+
+// Class is a class written to the dump
+type Class struct {
+	ID     model.ID
+	Serial model.SerialNum
+	Name   string // As the JVM writes it: java.lang.String, [B, [Ljava.lang.Object;
+	Super  *Class
+	Fields []Field // Instance fields declared by this class, in dump order
+}
+
+type Field struct {
+	Name string
+	Type model.HProfTagFieldType
+}
+
+// Frame is one frame of an allocation trace
+type Frame struct {
+	Class  string // Must have been written with Class to resolve; "?" otherwise
+	Method string
+	Source string
+	Line   int32 // >0 a line, -1 unknown, -2 compiled, -3 native
+}
+
+// Writer streams a dump: top-level records go out as they are made, heap
+// sub-records are gathered into segments
+type Writer struct {
+	out     *bufio.Writer
+	idSize  int
+	segment bytes.Buffer
+	err     error
+
+	nextID     uint64
+	nextSerial model.SerialNum
+	strings    map[string]model.ID
+	classes    map[string]*Class
+	trace      model.SerialNum // Allocation trace of the objects written next
+	loader     model.ID        // Class loader of the classes written next
+}
+
+// NewWriter writes the HPROF header for identifiers of idSize bytes (4 or 8)
+func NewWriter(out io.Writer, idSize int) *Writer {
+	w := &Writer{
+		out:     bufio.NewWriter(out),
+		idSize:  idSize,
+		nextID:  firstID,
+		strings: make(map[string]model.ID),
+		classes: make(map[string]*Class),
+	}
+	if idSize != 4 && idSize != 8 {
+		w.err = fmt.Errorf("identifier size must be 4 or 8, not %d", idSize)
+	}
+
+	w.out.WriteString("JAVA PROFILE 1.0.2\x00")
+	w.u4(w.out, uint32(idSize))
+	w.u4(w.out, 0) // Timestamp, high and low words
+	w.u4(w.out, 0)
+	return w
+}
+
+// WriteFile creates path and writes the dump build makes to it
+func WriteFile(path string, idSize int, build func(w *Writer)) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := NewWriter(file, idSize)
+	build(w)
+	err = w.Close()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Close ends the heap dump and flushes it; it returns the first error met while writing
+func (w *Writer) Close() error {
+	w.flushSegment()
+	w.record(model.HPROF_HEAP_DUMP_END, nil)
+	if err := w.out.Flush(); w.err == nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// NewID reserves an object ID, to reference an object before writing it
+func (w *Writer) NewID() model.ID {
+	w.nextID += idSpacing
+	if w.idSize == 4 && w.nextID > math.MaxUint32 {
+		w.fail(fmt.Errorf("ran out of 4-byte identifiers"))
+	}
+	return model.ID(w.nextID)
+}
+
+// AllocatedAt makes the objects written next carry an allocation trace from Trace; 0 is none
+func (w *Writer) AllocatedAt(trace model.SerialNum) {
+	w.trace = trace
+}
+
+// LoadedBy makes the classes written next be defined by the loader object; 0 is the bootstrap loader
+func (w *Writer) LoadedBy(loader model.ID) {
+	w.loader = loader
+}
+
+// UTF8 writes a string record once per text and returns its ID
+func (w *Writer) UTF8(text string) model.ID {
+	if id, ok := w.strings[text]; ok {
+		return id
+	}
+	id := w.NewID()
+	w.strings[text] = id
+
+	var body bytes.Buffer
+	w.id(&body, id)
+	body.WriteString(text)
+	w.record(model.HPROF_UTF8, body.Bytes())
+	return id
+}
+
+// Class writes a class's LOAD_CLASS record and class dump. It isn't a GC root
+// unless Root makes it one.
+func (w *Writer) Class(name string, super *Class, fields ...Field) *Class {
+	w.nextSerial++
+	class := &Class{ID: w.NewID(), Serial: w.nextSerial, Name: name, Super: super, Fields: fields}
+	w.classes[name] = class
+
+	var body bytes.Buffer
+	w.u4(&body, uint32(class.Serial))
+	w.id(&body, class.ID)
+	w.u4(&body, 0)
+	w.id(&body, w.UTF8(name))
+	w.record(model.HPROF_LOAD_CLASS, body.Bytes())
+
+	fieldNames := make([]model.ID, len(fields))
+	for i, field := range fields {
+		fieldNames[i] = w.UTF8(field.Name)
+	}
+
+	var superID model.ID
+	if super != nil {
+		superID = super.ID
+	}
+	w.subRecord(model.HPROF_GC_CLASS_DUMP)
+	w.id(&w.segment, class.ID)
+	w.u4(&w.segment, 0)
+	w.id(&w.segment, superID)
+	w.id(&w.segment, w.loader)
+	for range 4 { // Signers, protection domain, two reserved
+		w.id(&w.segment, 0)
+	}
+	w.u4(&w.segment, uint32(w.instanceSize(class)))
+	w.u2(0) // Constant pool
+	w.u2(0) // Static fields
+	w.u2(uint16(len(fields)))
+	for i, field := range fields {
+		w.id(&w.segment, fieldNames[i])
+		w.segment.WriteByte(byte(field.Type))
+	}
+	return class
+}
+
+// instanceSize is the size of an instance's field values, superclass fields included
+func (w *Writer) instanceSize(class *Class) int {
+	size := 0
+	for ; class != nil; class = class.Super {
+		for _, field := range class.Fields {
+			size += field.Type.Size(uint32(w.idSize))
+		}
+	}
+	return size
+}
+
+// Instance writes an instance of class and returns its ID
+func (w *Writer) Instance(class *Class, values ...any) model.ID {
+	id := w.NewID()
+	w.InstanceAt(id, class, values...)
+	return id
+}
+
+/*
+ * InstanceAt writes an instance under an ID from NewID. values are the field
+ * values in dump order, the class's own fields first and then each
+ * superclass's: a model.ID for object fields, and bool, uint16 (char), float32,
+ * float64, int8 or byte, int16, int32 and int64 for the primitive types.
+ */
+func (w *Writer) InstanceAt(id model.ID, class *Class, values ...any) {
+	var fields []Field
+	for c := class; c != nil; c = c.Super {
+		fields = append(fields, c.Fields...)
+	}
+	if len(values) != len(fields) {
+		w.fail(fmt.Errorf("%s has %d fields, got %d values", class.Name, len(fields), len(values)))
+		return
+	}
+
+	var data bytes.Buffer
+	for i, field := range fields {
+		if err := w.value(&data, field.Type, values[i]); err != nil {
+			w.fail(fmt.Errorf("%s.%s: %w", class.Name, field.Name, err))
+			return
+		}
+	}
+
+	w.subRecord(model.HPROF_GC_INSTANCE_DUMP)
+	w.id(&w.segment, id)
+	w.u4(&w.segment, uint32(w.trace))
+	w.id(&w.segment, class.ID)
+	w.u4(&w.segment, uint32(data.Len()))
+	w.segment.Write(data.Bytes())
+}
+
+func (w *Writer) value(out *bytes.Buffer, kind model.HProfTagFieldType, value any) error {
+	var ok bool
+	switch kind {
+	case model.HPROF_NORMAL_OBJECT, model.HPROF_ARRAY_OBJECT:
+		var id model.ID
+		if value != nil {
+			id, ok = value.(model.ID)
+		} else {
+			ok = true
+		}
+		w.id(out, id)
+	case model.HPROF_BOOLEAN:
+		var v bool
+		if v, ok = value.(bool); v {
+			out.WriteByte(1)
+		} else {
+			out.WriteByte(0)
+		}
+	case model.HPROF_BYTE:
+		switch v := value.(type) {
+		case byte:
+			out.WriteByte(v)
+			ok = true
+		case int8:
+			out.WriteByte(byte(v))
+			ok = true
+		}
+	case model.HPROF_CHAR:
+		ok = writeAs[uint16](out, value)
+	case model.HPROF_SHORT:
+		ok = writeAs[int16](out, value)
+	case model.HPROF_INT:
+		ok = writeAs[int32](out, value)
+	case model.HPROF_LONG:
+		ok = writeAs[int64](out, value)
+	case model.HPROF_FLOAT:
+		ok = writeAs[float32](out, value)
+	case model.HPROF_DOUBLE:
+		ok = writeAs[float64](out, value)
+	default:
+		return fmt.Errorf("unknown field type 0x%x", byte(kind))
+	}
+	if !ok {
+		return fmt.Errorf("%T can't be written as %s", value, kind)
+	}
+	return nil
+}
+
+// writeAs writes value big-endian when it has type T
+func writeAs[T uint16 | int16 | int32 | int64 | float32 | float64](out *bytes.Buffer, value any) bool {
+	v, ok := value.(T)
+	if ok {
+		binary.Write(out, binary.BigEndian, v)
+	}
+	return ok
+}
+
+// ObjectArray writes an array of class (e.g. [Ljava.lang.Object;) holding elements; 0 is null
+func (w *Writer) ObjectArray(class *Class, elements ...model.ID) model.ID {
+	id := w.NewID()
+	w.ObjectArrayAt(id, class, elements...)
+	return id
+}
+
+// ObjectArrayAt writes an object array under an ID from NewID
+func (w *Writer) ObjectArrayAt(id model.ID, class *Class, elements ...model.ID) {
+	w.subRecord(model.HPROF_GC_OBJ_ARRAY_DUMP)
+	w.id(&w.segment, id)
+	w.u4(&w.segment, uint32(w.trace))
+	w.u4(&w.segment, uint32(len(elements)))
+	w.id(&w.segment, class.ID)
+	for _, element := range elements {
+		w.id(&w.segment, element)
+	}
+}
+
+// PrimitiveArray writes an array of length elements of elementType; data is its
+// big-endian content, or nil for zeros
+func (w *Writer) PrimitiveArray(elementType model.HProfTagFieldType, length int, data []byte) model.ID {
+	size := length * elementType.Size(uint32(w.idSize))
+	if data == nil {
+		data = make([]byte, size)
+	}
+	if len(data) != size {
+		w.fail(fmt.Errorf("%d %s elements take %d bytes, got %d", length, elementType, size, len(data)))
+		return 0
+	}
+
+	id := w.NewID()
+	w.subRecord(model.HPROF_GC_PRIM_ARRAY_DUMP)
+	w.id(&w.segment, id)
+	w.u4(&w.segment, uint32(w.trace))
+	w.u4(&w.segment, uint32(length))
+	w.segment.WriteByte(byte(elementType))
+	w.segment.Write(data)
+	return id
+}
+
+// String writes a compact (Latin-1) java.lang.String and its byte[] value. Each
+// call makes a new instance, so writing the same text twice makes a duplicate.
+func (w *Writer) String(text string) model.ID {
+	return w.StringWithHash(text, 0)
+}
+
+// StringWithHash is String with the hash field set
+func (w *Writer) StringWithHash(text string, hash int32) model.ID {
+	str, ok := w.classes["java.lang.String"]
+	if !ok {
+		object := w.classes["java.lang.Object"]
+		if object == nil {
+			object = w.Class("java.lang.Object", nil)
+		}
+		if w.classes["[B"] == nil {
+			w.Class("[B", object)
+		}
+		str = w.Class("java.lang.String", object,
+			Field{"value", model.HPROF_NORMAL_OBJECT}, Field{"hash", model.HPROF_INT}, Field{"coder", model.HPROF_BYTE})
+	}
+
+	value := w.PrimitiveArray(model.HPROF_BYTE, len(text), []byte(text))
+	return w.Instance(str, value, hash, byte(0))
+}
+
+/*
+ * Root marks an object as a GC root of the given kind. The thread and frame
+ * a root may name are left 0, as nothing here has threads.
+ */
+func (w *Writer) Root(kind model.HProfTagSubRecord, id model.ID) {
+	w.subRecord(kind)
+	w.id(&w.segment, id)
+	switch kind {
+	case model.HPROF_GC_ROOT_JNI_GLOBAL:
+		w.id(&w.segment, 0) // JNI global ref
+	case model.HPROF_GC_ROOT_JNI_LOCAL, model.HPROF_GC_ROOT_JAVA_FRAME, model.HPROF_GC_ROOT_THREAD_OBJ:
+		w.u4(&w.segment, 0) // Thread serial
+		w.u4(&w.segment, 0) // Frame number, or stack trace serial for a thread object
+	case model.HPROF_GC_ROOT_NATIVE_STACK, model.HPROF_GC_ROOT_THREAD_BLOCK:
+		w.u4(&w.segment, 0) // Thread serial
+	}
+}
+
+// Trace writes the frames, innermost first, and a trace of them; objects written after
+// AllocatedAt(serial) carry it
+func (w *Writer) Trace(frames ...Frame) model.SerialNum {
+	frameIDs := make([]model.ID, len(frames))
+	for i, frame := range frames {
+		frameIDs[i] = w.NewID()
+
+		var classSerial model.SerialNum
+		if class, ok := w.classes[frame.Class]; ok {
+			classSerial = class.Serial
+		}
+
+		var body bytes.Buffer
+		w.id(&body, frameIDs[i])
+		w.id(&body, w.UTF8(frame.Method))
+		w.id(&body, w.UTF8("()V"))
+		w.id(&body, w.UTF8(frame.Source))
+		w.u4(&body, uint32(classSerial))
+		w.u4(&body, uint32(frame.Line))
+		w.record(model.HPROF_FRAME, body.Bytes())
+	}
+
+	w.nextSerial++
+	serial := w.nextSerial
+
+	var body bytes.Buffer
+	w.u4(&body, uint32(serial))
+	w.u4(&body, 0) // Thread serial
+	w.u4(&body, uint32(len(frames)))
+	for _, id := range frameIDs {
+		w.id(&body, id)
+	}
+	w.record(model.HPROF_TRACE, body.Bytes())
+	return serial
+}
+
+// subRecord starts a heap dump sub-record, closing the segment once it is full
+func (w *Writer) subRecord(tag model.HProfTagSubRecord) {
+	if w.segment.Len() >= segmentMax {
+		w.flushSegment()
+	}
+	w.segment.WriteByte(byte(tag))
+}
+
+func (w *Writer) flushSegment() {
+	if w.segment.Len() > 0 {
+		w.record(model.HPROF_HEAP_DUMP_SEGMENT, w.segment.Bytes())
+		w.segment.Reset()
+	}
+}
+
+func (w *Writer) record(tag model.HProfTagRecord, body []byte) {
+	w.out.WriteByte(byte(tag))
+	w.u4(w.out, 0)
+	w.u4(w.out, uint32(len(body)))
+	if _, err := w.out.Write(body); err != nil {
+		w.fail(err)
+	}
+}
+
+func (w *Writer) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *Writer) id(out io.Writer, id model.ID) {
+	if w.idSize == 4 {
+		binary.Write(out, binary.BigEndian, uint32(id))
+		return
+	}
+	binary.Write(out, binary.BigEndian, uint64(id))
+}
+
+func (w *Writer) u4(out io.Writer, value uint32) {
+	binary.Write(out, binary.BigEndian, value)
+}
+
+func (w *Writer) u2(value uint16) {
+	binary.Write(&w.segment, binary.BigEndian, value)
+}
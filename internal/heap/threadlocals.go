@@ -0,0 +1,65 @@
+package heap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const maxThreadLocalLeaks = 20
+
+// RunHeapThreadLocals prints the ThreadLocalMap entries that outlive their ThreadLocal or pin a class loader
+func RunHeapThreadLocals(filename string, config *Config) error {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	report := analyzer.FindThreadLocalLeaks(heapAnalyzer.GetContext(), heapAnalyzer.GetDominatorTree())
+	printThreadLocalReport(report)
+	return nil
+}
+
+func printThreadLocalReport(report *analyzer.ThreadLocalReport) {
+	fmt.Println()
+	fmt.Println("🧵 THREADLOCAL LEAKS")
+	fmt.Printf("%s threads hold %s thread-local values retaining %s\n\n",
+		utils.FormatCount(int64(report.Threads)), utils.FormatCount(int64(report.Entries)),
+		utils.MemorySize(report.RetainedSize).String())
+
+	if len(report.Leaks) == 0 {
+		fmt.Println(utils.GoodStyle.Render("✅ No stale entries, and no values from application class loaders"))
+		return
+	}
+
+	fmt.Printf("%10s  %8s  %14s  %s\n", "Retained", "Entries", "Threads", "Value ← ThreadLocal")
+	fmt.Println(strings.Repeat("─", 80))
+	for _, leak := range report.Leaks[:min(len(report.Leaks), maxThreadLocalLeaks)] {
+		key := leak.KeyClass
+		if key == "" {
+			key = "<collected>"
+		}
+		threads := fmt.Sprintf("%s (%s pooled)", utils.FormatCount(int64(leak.Threads)), utils.FormatCount(int64(leak.PooledThreads)))
+		line := fmt.Sprintf("%10s  %8s  %14s  %s ← %s", utils.MemorySize(leak.RetainedSize).String(),
+			utils.FormatCount(int64(leak.Entries)), threads, leak.ValueClass, key)
+		switch {
+		case leak.LoaderStopped:
+			fmt.Println(utils.CriticalStyle.Render(line))
+		case leak.PooledThreads > 0:
+			fmt.Println(utils.WarningStyle.Render(line))
+		default:
+			fmt.Println(line)
+		}
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("%38s%s; biggest in \"%s\"", "", leak.Describe(), leak.Largest.ThreadName)))
+	}
+	if len(report.Leaks) > maxThreadLocalLeaks {
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("... and %d more", len(report.Leaks)-maxThreadLocalLeaks)))
+	}
+
+	fmt.Println()
+	fmt.Println(utils.InfoStyle.Render("💡 Pooled threads never die: call ThreadLocal.remove() in a finally block once the request or task is done"))
+}
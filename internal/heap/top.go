@@ -0,0 +1,177 @@
+package heap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	treemapWidth    = 72
+	treemapHeight   = 16
+	treemapPackages = 12 // Packages drawn individually; the rest are merged into "other"
+)
+
+// RunHeapTop prints the biggest objects in the dump and a package ownership treemap,
+// with packages rolled up to packageDepth names (0 = every package on its own)
+func RunHeapTop(filename string, config *Config, limit, packageDepth int) error {
+	parser, heapAnalyzer, err := AnalyzeHeapDump(filename, config)
+	if err != nil {
+		return err
+	}
+	defer parser.Close()
+	defer heapAnalyzer.Close()
+
+	tree := heapAnalyzer.GetDominatorTree()
+	if tree == nil || tree.ReachableCount() == 0 {
+		fmt.Println("No reachable objects found")
+		return nil
+	}
+
+	printTopObjects(heapAnalyzer, tree, limit)
+	printPackageTreemap(heapAnalyzer.GetContext(), tree, packageDepth)
+
+	return nil
+}
+
+func printTopObjects(heapAnalyzer *analyzer.Analyzer, tree *analyzer.DominatorTree, limit int) {
+	ctx := heapAnalyzer.GetContext()
+	total := tree.TotalRetainedSize()
+
+	// Top-level dominators don't overlap, so together they account for the whole reachable heap
+	objects := tree.Children(analyzer.SuperRootID)
+	if limit > 0 && len(objects) > limit {
+		objects = objects[:limit]
+	}
+
+	fmt.Println()
+	fmt.Printf("🏆 TOP %d OBJECTS BY RETAINED SIZE\n", len(objects))
+	fmt.Printf("Reachable heap: %s (%s objects)\n\n",
+		utils.MemorySize(total).String(), utils.FormatCount(int64(tree.ReachableCount())))
+
+	fmt.Printf("%4s  %10s  %6s  %10s  %s\n", "#", "Retained", "%", "Shallow", "Object")
+	fmt.Println(strings.Repeat("─", 80))
+
+	for i, objectID := range objects {
+		object, _ := ctx.DescribeObject(objectID)
+		retained := tree.RetainedSize(objectID)
+		percentage := float64(retained) / float64(total) * 100
+
+		line := fmt.Sprintf("%4d  %10s  %6s  %10s  %s", i+1,
+			utils.MemorySize(retained).String(), utils.FormatPercent(percentage),
+			utils.MemorySize(object.ShallowSize).String(), object.DisplayName())
+
+		switch {
+		case percentage >= 30:
+			fmt.Println(utils.CriticalStyle.Render(line))
+		case percentage >= analyzer.LeakSuspectThreshold*100:
+			fmt.Println(utils.WarningStyle.Render(line))
+		default:
+			fmt.Println(line)
+		}
+		fmt.Println(utils.MutedStyle.Render("      " + summarizeRootPath(heapAnalyzer, objectID)))
+	}
+}
+
+// summarizeRootPath describes how an object is kept alive, e.g.
+// "Java frame → HashMap → HashMap$Node[] → … → Session (5 hops)"
+func summarizeRootPath(heapAnalyzer *analyzer.Analyzer, objectID model.ID) string {
+	ctx := heapAnalyzer.GetContext()
+
+	path := heapAnalyzer.ShortestPathToRoot(objectID)
+	if len(path) == 0 {
+		return "Path: no GC root found"
+	}
+
+	rootType, _ := ctx.RootReg.GetRootType(path[0])
+	if len(path) == 1 {
+		return fmt.Sprintf("Path: GC root (%s)", rootType)
+	}
+
+	var names []string
+	for _, id := range path {
+		object, _ := ctx.DescribeObject(id)
+		names = append(names, simpleClassName(object.ClassName))
+	}
+
+	// Keep the root side and the object side of long chains
+	if len(names) > 5 {
+		names = append(names[:2:2], append([]string{"…"}, names[len(names)-2:]...)...)
+	}
+
+	return fmt.Sprintf("Path: %s → %s (%d hops)", rootType, strings.Join(names, " → "), len(path)-1)
+}
+
+func printPackageTreemap(ctx *analyzer.AnalysisContext, tree *analyzer.DominatorTree, depth int) {
+	breakdown := analyzer.BuildPackageBreakdown(ctx, tree, depth)
+	if len(breakdown) == 0 {
+		return
+	}
+
+	styles := []lipgloss.Style{utils.CriticalStyle, utils.WarningStyle, utils.InfoStyle, utils.GoodStyle, utils.TextStyle, utils.MutedStyle}
+
+	var items []utils.TreemapItem
+	var other *analyzer.PackageRetention
+	for i, entry := range breakdown {
+		if i >= treemapPackages {
+			if other == nil {
+				other = &analyzer.PackageRetention{Package: "other"}
+			}
+			other.ObjectCount += entry.ObjectCount
+			other.OwnedSize += entry.OwnedSize
+			other.Percentage += entry.Percentage
+			continue
+		}
+		items = append(items, utils.TreemapItem{
+			Label: entry.Package,
+			Value: float64(entry.OwnedSize),
+			Style: styles[i%len(styles)],
+		})
+	}
+
+	legend := slices.Clone(breakdown[:min(len(breakdown), treemapPackages)])
+	if other != nil {
+		items = append(items, utils.TreemapItem{Label: other.Package, Value: float64(other.OwnedSize), Style: utils.MutedStyle})
+		legend = append(legend, other)
+	}
+
+	fmt.Println()
+	if depth > 0 {
+		fmt.Printf("📦 HEAP OWNERSHIP BY PACKAGE (rolled up to %d names)\n", depth)
+	} else {
+		fmt.Println("📦 HEAP OWNERSHIP BY PACKAGE")
+	}
+	fmt.Println(utils.MutedStyle.Render("JDK objects and primitive arrays are attributed to the application package that dominates them"))
+	fmt.Println()
+	fmt.Println(utils.CreateTreemap(items, treemapWidth, treemapHeight))
+	fmt.Println()
+
+	// Retained sizes overlap between packages, so "other" has none
+	fmt.Printf("    %-40s %10s  %6s  %10s  %s\n", "Package", "Owned", "%", "Retained", "Objects")
+	for i, entry := range legend {
+		retained := "-"
+		if entry != other {
+			retained = utils.MemorySize(entry.RetainedSize).String()
+		}
+		fmt.Printf(" %s  %-40s %10s  %6s  %10s  %s\n",
+			items[i].Style.Render(utils.TreemapKey(i)), utils.TruncateString(entry.Package, 40),
+			utils.MemorySize(entry.OwnedSize).String(), utils.FormatPercent(entry.Percentage), retained,
+			utils.FormatCount(int64(entry.ObjectCount)))
+	}
+}
+
+// simpleClassName drops the package: java.util.HashMap$Node[] -> HashMap$Node[]
+func simpleClassName(className string) string {
+	name := strings.TrimRight(className, "[]")
+	suffix := className[len(name):]
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name + suffix
+}
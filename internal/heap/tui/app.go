@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const PageSize = 10 // Number of lines to scroll per page
+
+func initialModel(heapAnalyzer *analyzer.Analyzer, info DumpInfo) *Model {
+	ctx := heapAnalyzer.GetContext()
+
+	m := &Model{
+		analyzer:        heapAnalyzer,
+		ctx:             ctx,
+		tree:            heapAnalyzer.GetDominatorTree(),
+		histogram:       heapAnalyzer.GetHistogram(),
+		suspects:        heapAnalyzer.GetLeakSuspects(),
+		refStats:        heapAnalyzer.GetReferenceStats(),
+		fields:          analyzer.NewFieldExtractor(ctx),
+		info:            info,
+		currentTab:      OverviewTab,
+		scrollPositions: make(map[TabType]int),
+		histogramState: &HistogramState{
+			sortBy: analyzer.SortByRetained,
+		},
+		dominatorsState: &DominatorsState{
+			expanded: make(map[model.ID]bool),
+		},
+		leaksState: &LeaksState{
+			expanded: make(map[int]bool),
+		},
+		inspectorState: &InspectorState{
+			expanded:       map[ReferenceDirection]bool{OutboundReferences: true},
+			sortByRetained: true,
+		},
+	}
+
+	// Start the inspector on the biggest object so the tab is never empty
+	if m.tree != nil {
+		if top := m.tree.Children(analyzer.SuperRootID); len(top) > 0 {
+			m.inspectorState.current = top[0]
+		}
+	}
+
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		// While typing a search term every key belongs to the search box
+		if search := m.currentSearch(); search != nil && search.active {
+			return m.handleSearchInput(search, msg)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+
+		case "tab":
+			utils.CycleEnumPtr(&m.currentTab, 1, InspectorTab)
+
+		case "1":
+			m.currentTab = OverviewTab
+		case "2":
+			m.currentTab = HistogramTab
+		case "3":
+			m.currentTab = DominatorsTab
+		case "4":
+			m.currentTab = LeakSuspectsTab
+		case "5":
+			m.currentTab = InspectorTab
+
+		default:
+			// Forward to tab-specific handlers for up/down and other keys
+			return m.handleTabSpecificKeys(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleTabSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.currentTab {
+	case OverviewTab:
+		return m.handleOverviewKeys(msg)
+	case HistogramTab:
+		return m.handleHistogramKeys(msg)
+	case DominatorsTab:
+		return m.handleDominatorsKeys(msg)
+	case LeakSuspectsTab:
+		return m.handleLeaksKeys(msg)
+	case InspectorTab:
+		return m.handleInspectorKeys(msg)
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleOverviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.scrollPositions[OverviewTab] > 0 {
+			m.scrollPositions[OverviewTab]--
+		}
+	case "down", "j":
+		// Will be bounded in rendering
+		m.scrollPositions[OverviewTab]++
+	}
+	return m, nil
+}
+
+// currentSearch returns the search box of the current tab, if it has one
+func (m *Model) currentSearch() *SearchState {
+	switch m.currentTab {
+	case HistogramTab:
+		return &m.histogramState.search
+	case DominatorsTab:
+		return &m.dominatorsState.search
+	}
+	return nil
+}
+
+func (m *Model) handleSearchInput(search *SearchState, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		search.active = false
+	case tea.KeyEsc:
+		search.active = false
+		search.term = ""
+	case tea.KeyBackspace:
+		if len(search.term) > 0 {
+			runes := []rune(search.term)
+			search.term = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		search.term += string(msg.Runes)
+	}
+
+	m.resetSelection()
+	return m, nil
+}
+
+// resetSelection moves the cursor back to the top after the list contents change
+func (m *Model) resetSelection() {
+	switch m.currentTab {
+	case HistogramTab:
+		m.histogramState.selected = 0
+	case DominatorsTab:
+		m.dominatorsState.selected = 0
+	}
+}
+
+// inspect opens an object in the inspector, remembering the current one for 'b'
+func (m *Model) inspect(objectID model.ID) {
+	state := m.inspectorState
+	if objectID == 0 {
+		return
+	}
+
+	if state.current != 0 && state.current != objectID {
+		state.history = append(state.history, state.current)
+	}
+	state.current = objectID
+	state.selected = 0
+	m.currentTab = InspectorTab
+}
+
+func (m *Model) View() string {
+	return utils.ToASCII(m.render())
+}
+
+func (m *Model) render() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var content string
+
+	// Calculate available height for content (header + content + shortcuts)
+	headerHeight := 2 // tab line + border
+	shortcutsHeight := 1
+	contentHeight := m.height - headerHeight - shortcutsHeight
+
+	// Render current Tab
+	switch m.currentTab {
+	case OverviewTab:
+		content = m.RenderOverview(contentHeight)
+	case HistogramTab:
+		content = m.RenderHistogram(contentHeight)
+	case DominatorsTab:
+		content = m.RenderDominators(contentHeight)
+	case LeakSuspectsTab:
+		content = m.RenderLeakSuspects(contentHeight)
+	case InspectorTab:
+		content = m.RenderInspector(contentHeight)
+	}
+
+	// Create a style that ensures content takes up exactly the available height
+	contentStyle := lipgloss.NewStyle().
+		Height(contentHeight).
+		Width(m.width)
+	content = contentStyle.Render(content)
+
+	// Build the full Tab
+	header := m.renderHeader()
+	shortcuts := m.renderFooter()
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		content,
+		shortcuts,
+	)
+}
+
+func (m *Model) renderHeader() string {
+	tabs := []string{}
+
+	tabIcons := []string{"📋", "📊", "🌳", "🔍", "🔬"}
+	tabNames := []string{"Overview", "Histogram", "Dominators", "Leak Suspects", "Inspector"}
+
+	for i, name := range tabNames {
+		style := utils.TabInactiveStyle
+		indicator := " "
+
+		if TabType(i) == m.currentTab {
+			style = utils.TabActiveStyle
+			indicator = "●" // Active indicator
+		}
+
+		tabText := fmt.Sprintf("%s%s %s[%d]", indicator, tabIcons[i], name, i+1)
+		tabs = append(tabs, style.Render(tabText))
+	}
+
+	tabLine := strings.Join(tabs, "")
+
+	border := strings.Repeat("─", m.width)
+
+	return lipgloss.JoinVertical(lipgloss.Left, tabLine, border)
+}
+
+func GetShortcuts(currentTab TabType) string {
+	base := "q:quit • tab:cycle • 1-5:tabs"
+
+	var tabSpecific string
+	switch currentTab {
+	case OverviewTab:
+		tabSpecific = "↑↓:scroll"
+	case HistogramTab:
+		tabSpecific = "↑↓:nav • s:sort • /:search • i:inspect biggest"
+	case DominatorsTab:
+		tabSpecific = "↑↓:nav • space/enter:expand • /:search • i:inspect"
+	case LeakSuspectsTab:
+		tabSpecific = "↑↓:nav • space/enter:expand • i:inspect • a:accumulation point"
+	case InspectorTab:
+		tabSpecific = "↑↓:nav • enter:expand/open • b:back • s:sort"
+	}
+
+	if tabSpecific != "" {
+		return base + " • " + tabSpecific
+	}
+	return base
+}
+
+func (m *Model) renderFooter() string {
+	shortcuts := GetShortcuts(m.currentTab)
+	if search := m.currentSearch(); search != nil && search.active {
+		shortcuts = "enter:apply • esc:clear • type to search"
+	}
+
+	return utils.HelpBarStyle.Width(m.width).Render(shortcuts)
+}
+
+// renderSearchLine shows the current search term, with a cursor while typing
+func renderSearchLine(search SearchState) string {
+	if search.active {
+		return utils.InfoStyle.Render(fmt.Sprintf("Search: %s█", search.term))
+	}
+	if search.term != "" {
+		return utils.MutedStyle.Render(fmt.Sprintf("Search: %s (esc to clear)", search.term))
+	}
+	return utils.MutedStyle.Render("Press / to search")
+}
+
+// visibleWindow returns the slice bounds that keep the selected row centered
+func visibleWindow(selected, total, height int) (int, int) {
+	if height <= 0 || total <= height {
+		return 0, total
+	}
+
+	start := max(selected-height/2, 0)
+	end := start + height
+	if end > total {
+		end = total
+		start = max(end-height, 0)
+	}
+	return start, end
+}
+
+// scrollContent clips content to the given height starting at a bounded scroll offset
+func (m *Model) scrollContent(tab TabType, content string, height int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) <= height {
+		m.scrollPositions[tab] = 0
+		return content
+	}
+
+	maxScroll := len(lines) - height
+	if m.scrollPositions[tab] > maxScroll {
+		m.scrollPositions[tab] = maxScroll
+	}
+
+	start := m.scrollPositions[tab]
+	return strings.Join(lines[start:start+height], "\n")
+}
+
+func renderSelectedRow(row string) string {
+	return utils.SelectedStyle.Render("▶ " + row)
+}
+
+func formatSize(size uint64) string {
+	return utils.MemorySize(size).String()
+}
+
+func StartTUI(heapAnalyzer *analyzer.Analyzer, info DumpInfo) error {
+	if heapAnalyzer.GetDominatorTree() == nil {
+		return fmt.Errorf("heap analysis has no dominator tree")
+	}
+
+	model := initialModel(heapAnalyzer, info)
+
+	program := tea.NewProgram(
+		model,
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	_, err := program.Run()
+	return err
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	MaxTreeChildren  = 100 // Children listed per expanded node
+	MaxSearchResults = 500
+)
+
+func (m *Model) handleDominatorsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.dominatorsState
+	rows := m.getDominatorRows()
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(rows)-1 {
+			state.selected++
+		}
+	case "pgup":
+		state.selected = max(state.selected-PageSize, 0)
+	case "pgdown":
+		state.selected = max(min(state.selected+PageSize, len(rows)-1), 0)
+	case "enter", " ":
+		if state.selected < len(rows) && !rows[state.selected].hasMore {
+			id := rows[state.selected].id
+			if state.search.term != "" {
+				m.inspect(id)
+			} else if len(m.tree.Children(id)) > 0 {
+				state.expanded[id] = !state.expanded[id]
+			}
+		}
+	case "i":
+		if state.selected < len(rows) && !rows[state.selected].hasMore {
+			m.inspect(rows[state.selected].id)
+		}
+	case "/":
+		state.search.active = true
+	case "esc":
+		state.search.term = ""
+		state.selected = 0
+	}
+	return m, nil
+}
+
+// getDominatorRows flattens the expanded part of the tree, or lists search matches
+func (m *Model) getDominatorRows() []dominatorRow {
+	state := m.dominatorsState
+	if state.search.term != "" {
+		return m.getDominatorSearchResults()
+	}
+
+	var rows []dominatorRow
+	var addChildren func(parent model.ID, depth int)
+	addChildren = func(parent model.ID, depth int) {
+		children := m.tree.Children(parent)
+		for i, child := range children {
+			if i == MaxTreeChildren {
+				rows = append(rows, dominatorRow{depth: depth, hasMore: true, moreLeft: len(children) - i})
+				break
+			}
+
+			rows = append(rows, dominatorRow{id: child, depth: depth})
+			if state.expanded[child] {
+				addChildren(child, depth+1)
+			}
+		}
+	}
+	addChildren(analyzer.SuperRootID, 0)
+
+	return rows
+}
+
+func (m *Model) getDominatorSearchResults() []dominatorRow {
+	state := m.dominatorsState
+	if state.resultsTerm == state.search.term && state.results != nil {
+		return state.results
+	}
+
+	term := strings.ToLower(state.search.term)
+	var matches []model.ID
+	m.tree.Walk(func(objectID model.ID, retained uint64) {
+		object, _ := m.ctx.DescribeObject(objectID)
+		if strings.Contains(strings.ToLower(object.ClassName), term) {
+			matches = append(matches, objectID)
+		}
+	}, nil)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return m.tree.RetainedSize(matches[i]) > m.tree.RetainedSize(matches[j])
+	})
+
+	results := make([]dominatorRow, 0, min(len(matches), MaxSearchResults))
+	for i, objectID := range matches {
+		if i == MaxSearchResults {
+			results = append(results, dominatorRow{hasMore: true, moreLeft: len(matches) - i})
+			break
+		}
+		results = append(results, dominatorRow{id: objectID})
+	}
+
+	state.results = results
+	state.resultsTerm = state.search.term
+	return results
+}
+
+func (m *Model) RenderDominators(height int) string {
+	state := m.dominatorsState
+	rows := m.getDominatorRows()
+
+	total := m.tree.TotalRetainedSize()
+	mode := "Tree"
+	if state.search.term != "" {
+		mode = "Search results"
+	}
+	statusLine := fmt.Sprintf("%s | %s",
+		utils.TabActiveStyle.Render(mode),
+		utils.MutedStyle.Render(fmt.Sprintf("%s reachable objects, %s retained",
+			utils.FormatCount(int64(m.tree.ReachableCount())), formatSize(total))))
+
+	nameWidth := max(30, m.width-40)
+	headerLine := fmt.Sprintf("  %-*s │ %10s │ %10s │ %6s", nameWidth, "Object", "Shallow", "Retained", "%")
+	separator := strings.Repeat("─", m.width)
+
+	if len(rows) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			statusLine,
+			renderSearchLine(state.search),
+			"",
+			utils.MutedStyle.Render("No objects to display"),
+		)
+	}
+
+	if state.selected >= len(rows) {
+		state.selected = len(rows) - 1
+	}
+
+	// status + search + blank + table header + separator
+	start, end := visibleWindow(state.selected, len(rows), height-5)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		row := rows[i]
+		indent := strings.Repeat("  ", row.depth)
+
+		if row.hasMore {
+			line := fmt.Sprintf("%s    … %d more", indent, row.moreLeft)
+			lines = append(lines, utils.MutedStyle.Render("  "+line))
+			continue
+		}
+
+		expandIcon := "   "
+		if state.search.term == "" && len(m.tree.Children(row.id)) > 0 {
+			expandIcon = "[+]"
+			if state.expanded[row.id] {
+				expandIcon = "[-]"
+			}
+		}
+
+		object, _ := m.ctx.DescribeObject(row.id)
+		retained := m.tree.RetainedSize(row.id)
+		percentage := 0.0
+		if total > 0 {
+			percentage = float64(retained) / float64(total) * 100
+		}
+
+		name := fmt.Sprintf("%s%s %s", indent, expandIcon, object.DisplayName())
+		line := fmt.Sprintf("%-*s │ %10s │ %10s │ %6s",
+			nameWidth, utils.TruncateString(name, nameWidth),
+			formatSize(object.ShallowSize), formatSize(retained), utils.FormatPercent(percentage))
+
+		if i == state.selected {
+			lines = append(lines, renderSelectedRow(line))
+			continue
+		}
+
+		style := utils.TextStyle
+		if percentage >= analyzer.LeakSuspectThreshold*100 {
+			style = utils.WarningStyle
+		}
+		lines = append(lines, style.Render("  "+line))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		statusLine,
+		renderSearchLine(state.search),
+		"",
+		utils.TitleStyle.Render(headerLine),
+		utils.MutedStyle.Render(separator),
+		strings.Join(lines, "\n"),
+	)
+}
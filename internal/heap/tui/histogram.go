@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *Model) handleHistogramKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.histogramState
+	entries := m.getFilteredHistogram()
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(entries)-1 {
+			state.selected++
+		}
+	case "pgup":
+		state.selected = max(state.selected-PageSize, 0)
+	case "pgdown":
+		state.selected = max(min(state.selected+PageSize, len(entries)-1), 0)
+	case "s":
+		utils.CycleEnumPtr(&state.sortBy, 1, analyzer.SortByName)
+		m.histogram.Sort(state.sortBy)
+		state.selected = 0
+	case "/":
+		state.search.active = true
+	case "esc":
+		state.search.term = ""
+		state.selected = 0
+	case "i", "enter":
+		if state.selected < len(entries) {
+			m.inspect(analyzer.BiggestInstanceOf(m.ctx, m.tree, entries[state.selected].ClassName))
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) getFilteredHistogram() []*analyzer.ClassHistogramEntry {
+	return m.histogram.Filter(m.histogramState.search.term)
+}
+
+// topClassesByRetained returns the n classes retaining the most memory regardless of the current sort
+func (m *Model) topClassesByRetained(n int) []*analyzer.ClassHistogramEntry {
+	entries := slices.Clone(m.histogram.Entries)
+	slices.SortStableFunc(entries, func(a, b *analyzer.ClassHistogramEntry) int {
+		switch {
+		case a.RetainedSize > b.RetainedSize:
+			return -1
+		case a.RetainedSize < b.RetainedSize:
+			return 1
+		}
+		return strings.Compare(a.ClassName, b.ClassName)
+	})
+
+	return entries[:min(n, len(entries))]
+}
+
+func (m *Model) RenderHistogram(height int) string {
+	state := m.histogramState
+	entries := m.getFilteredHistogram()
+
+	statusLine := fmt.Sprintf("%s | %s",
+		utils.TabActiveStyle.Render(fmt.Sprintf("Sort: %s", state.sortBy)),
+		utils.MutedStyle.Render(fmt.Sprintf("%d/%d classes", len(entries), len(m.histogram.Entries))))
+
+	nameWidth := max(20, m.width-52)
+	headerLine := fmt.Sprintf("  %-*s │ %10s │ %10s │ %10s │ %6s",
+		nameWidth, "Class", "Objects", "Shallow", "Retained", "%")
+	separator := strings.Repeat("─", m.width)
+
+	// status + search + blank + table header + separator
+	tableHeight := height - 5
+
+	if len(entries) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			statusLine,
+			renderSearchLine(state.search),
+			"",
+			utils.MutedStyle.Render("No classes match the search"),
+		)
+	}
+
+	if state.selected >= len(entries) {
+		state.selected = len(entries) - 1
+	}
+
+	total := m.tree.TotalRetainedSize()
+	start, end := visibleWindow(state.selected, len(entries), tableHeight)
+
+	var rows []string
+	for i := start; i < end; i++ {
+		entry := entries[i]
+
+		percentage := 0.0
+		if total > 0 {
+			percentage = float64(entry.RetainedSize) / float64(total) * 100
+		}
+
+		row := fmt.Sprintf("%-*s │ %10s │ %10s │ %10s │ %6s",
+			nameWidth, utils.TruncateString(entry.ClassName, nameWidth),
+			utils.FormatCount(int64(entry.InstanceCount)),
+			formatSize(entry.ShallowSize),
+			formatSize(entry.RetainedSize),
+			utils.FormatPercent(percentage))
+
+		if i == state.selected {
+			rows = append(rows, renderSelectedRow(row))
+			continue
+		}
+
+		style := utils.TextStyle
+		if percentage >= analyzer.LeakSuspectThreshold*100 {
+			style = utils.WarningStyle
+		}
+		rows = append(rows, style.Render("  "+row))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		statusLine,
+		renderSearchLine(state.search),
+		"",
+		utils.TitleStyle.Render(headerLine),
+		utils.MutedStyle.Render(separator),
+		strings.Join(rows, "\n"),
+	)
+}
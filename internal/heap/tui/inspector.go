@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	MaxInspectorFields = 30 // Fields listed before truncating
+	MaxStringPreview   = 80 // Characters of char[]/byte[] contents shown
+)
+
+func (m *Model) handleInspectorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.inspectorState
+	rows := m.getInspectorRows()
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(rows)-1 {
+			state.selected++
+		}
+	case "pgup":
+		state.selected = max(state.selected-PageSize, 0)
+	case "pgdown":
+		state.selected = max(min(state.selected+PageSize, len(rows)-1), 0)
+	case "enter", " ", "i":
+		if state.selected >= len(rows) {
+			break
+		}
+		row := rows[state.selected]
+		if row.isSection {
+			state.expanded[row.direction] = !state.expanded[row.direction]
+		} else if row.reference.id != 0 {
+			m.inspect(row.reference.id)
+		}
+	case "b", "backspace":
+		if n := len(state.history); n > 0 {
+			state.current = state.history[n-1]
+			state.history = state.history[:n-1]
+			state.selected = 0
+		}
+	case "s":
+		state.sortByRetained = !state.sortByRetained
+	}
+	return m, nil
+}
+
+// getInspectorRows lists the selectable rows: each reference section followed by its entries when expanded
+func (m *Model) getInspectorRows() []inspectorRow {
+	state := m.inspectorState
+	if state.current == 0 {
+		return nil
+	}
+
+	references := m.getReferences()
+
+	var rows []inspectorRow
+	for _, direction := range []ReferenceDirection{OutboundReferences, InboundReferences} {
+		rows = append(rows, inspectorRow{direction: direction, isSection: true})
+		if !state.expanded[direction] {
+			continue
+		}
+
+		for i, reference := range references[direction] {
+			if i == MaxTreeChildren {
+				more := objectReference{label: fmt.Sprintf("… %d more", len(references[direction])-i)}
+				rows = append(rows, inspectorRow{direction: direction, reference: more})
+				break
+			}
+			rows = append(rows, inspectorRow{direction: direction, reference: reference})
+		}
+	}
+
+	return rows
+}
+
+func (m *Model) getReferences() map[ReferenceDirection][]objectReference {
+	state := m.inspectorState
+	if state.references != nil && state.referencesFor == state.current && state.referencesOrder == state.sortByRetained {
+		return state.references
+	}
+
+	references := map[ReferenceDirection][]objectReference{
+		OutboundReferences: m.outboundReferences(state.current),
+		InboundReferences:  m.inboundReferences(state.current),
+	}
+
+	if state.sortByRetained {
+		for _, list := range references {
+			sort.SliceStable(list, func(i, j int) bool {
+				return m.tree.RetainedSize(list[i].id) > m.tree.RetainedSize(list[j].id)
+			})
+		}
+	}
+
+	state.references = references
+	state.referencesFor = state.current
+	state.referencesOrder = state.sortByRetained
+	return references
+}
+
+// outboundReferences labels each outgoing reference with the field or array slot holding it
+func (m *Model) outboundReferences(objectID model.ID) []objectReference {
+	var references []objectReference
+
+	if instance, ok := m.ctx.InstanceReg.GetInstance(objectID); ok {
+		if classDump, ok := m.ctx.ClassDumpReg.GetClassDump(instance.ClassObjectID); ok {
+			if fields, err := m.fields.ExtractInstanceFields(instance, classDump); err == nil {
+				for _, field := range fields {
+					if id, ok := field.Value.(model.ID); ok && field.IsReference && id != 0 {
+						references = append(references, objectReference{label: field.Name, id: id})
+					}
+				}
+				return references
+			}
+		}
+	}
+
+	if array, ok := m.ctx.ArrayReg.GetObjectArray(objectID); ok {
+		for i, id := range array.Elements {
+			if id != 0 {
+				references = append(references, objectReference{label: fmt.Sprintf("[%d]", i), id: id})
+			}
+		}
+		return references
+	}
+
+	// Class objects and anything the field layout couldn't decode
+	refMap := m.analyzer.GetReferenceMap()
+	if refMap == nil {
+		return nil
+	}
+	for _, id := range refMap.GetReferences(objectID) {
+		references = append(references, objectReference{id: id})
+	}
+	return references
+}
+
+func (m *Model) inboundReferences(objectID model.ID) []objectReference {
+	refMap := m.analyzer.GetReferenceMap()
+	if refMap == nil {
+		return nil
+	}
+
+	seen := make(map[model.ID]bool)
+	var references []objectReference
+	for _, id := range refMap.GetReferrers(objectID) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		references = append(references, objectReference{id: id})
+	}
+	return references
+}
+
+func (m *Model) RenderInspector(height int) string {
+	state := m.inspectorState
+	if state.current == 0 {
+		return utils.MutedStyle.Render("No object selected.\n\nPress i on the Histogram, Dominators or Leak Suspects tab to inspect an object.")
+	}
+
+	header := m.renderObjectHeader(state.current)
+	fields := m.renderObjectFields(state.current)
+
+	rows := m.getInspectorRows()
+	if state.selected >= len(rows) {
+		state.selected = max(len(rows)-1, 0)
+	}
+
+	used := strings.Count(header, "\n") + strings.Count(fields, "\n") + 4
+	start, end := visibleWindow(state.selected, len(rows), max(height-used, 3))
+
+	references := m.getReferences()
+	var lines []string
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderInspectorRow(rows[i], references, i == state.selected))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		fields,
+		"",
+		strings.Join(lines, "\n"),
+	)
+}
+
+func (m *Model) renderObjectHeader(objectID model.ID) string {
+	object, exists := m.ctx.DescribeObject(objectID)
+
+	lines := []string{utils.TitleStyle.Render("🔬 " + object.DisplayName())}
+	if !exists {
+		lines = append(lines, utils.WarningStyle.Render("Object is not present in the dump"))
+		return strings.Join(lines, "\n")
+	}
+
+	kind := object.Kind.String()
+	if object.Kind == analyzer.ObjectArrayObject || object.Kind == analyzer.PrimitiveArrayObject {
+		kind = fmt.Sprintf("%s, length %d", kind, object.Length)
+	}
+
+	lines = append(lines, utils.FormatKeyValue("Kind", kind, 14))
+	lines = append(lines, utils.FormatKeyValue("Shallow size", formatSize(object.ShallowSize), 14))
+
+	if m.tree.Contains(objectID) {
+		lines = append(lines, utils.FormatKeyValue("Retained size", formatSize(m.tree.RetainedSize(objectID)), 14))
+
+		dominator := "<GC roots>"
+		if dom, ok := m.tree.ImmediateDominator(objectID); ok && dom != analyzer.SuperRootID {
+			domObject, _ := m.ctx.DescribeObject(dom)
+			dominator = domObject.DisplayName()
+		}
+		lines = append(lines, utils.FormatKeyValue("Dominated by", dominator, 14))
+	} else {
+		lines = append(lines, utils.MutedStyle.Render(utils.FormatKeyValue("Retained size", "unreachable", 14)))
+	}
+
+	if rootType, ok := m.ctx.RootReg.GetRootType(objectID); ok {
+		lines = append(lines, utils.InfoStyle.Render(utils.FormatKeyValue("GC root", rootType.String(), 14)))
+	}
+
+	if len(m.inspectorState.history) > 0 {
+		lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("b: back (%d)", len(m.inspectorState.history))))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderObjectFields(objectID model.ID) string {
+	lines := []string{utils.InfoStyle.Render("Fields:")}
+
+	if instance, ok := m.ctx.InstanceReg.GetInstance(objectID); ok {
+		classDump, ok := m.ctx.ClassDumpReg.GetClassDump(instance.ClassObjectID)
+		if !ok {
+			return utils.MutedStyle.Render("Fields: class dump not found")
+		}
+
+		fields, err := m.fields.ExtractInstanceFields(instance, classDump)
+		if err != nil {
+			return utils.WarningStyle.Render(fmt.Sprintf("Fields: %v", err))
+		}
+		if len(fields) == 0 {
+			lines = append(lines, utils.MutedStyle.Render("  (none)"))
+		}
+		if text, ok := m.fields.ReadString(objectID); ok {
+			lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("  text: %q", utils.TruncateString(text, MaxStringPreview))))
+		} else if m.ctx.ArrayReg.ContentsSkipped() && m.ctx.ClassName(instance.ClassObjectID) == "java.lang.String" {
+			lines = append(lines, utils.MutedStyle.Render("  text: not read (--structure-only)"))
+		}
+
+		for i, field := range fields {
+			if i == MaxInspectorFields {
+				lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("  … %d more fields", len(fields)-i)))
+				break
+			}
+			line := fmt.Sprintf("  %-24s %-8s %s", field.Name, field.Type, m.fields.FormatField(field))
+			lines = append(lines, utils.TextStyle.Render(utils.TruncateString(line, m.width-2)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	if preview, ok := m.ctx.ArrayReg.GetCharArray(objectID); ok {
+		lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("  value: %q", utils.TruncateString(preview, MaxStringPreview))))
+		return strings.Join(lines, "\n")
+	}
+	if preview, ok := m.ctx.ArrayReg.GetByteArray(objectID); ok {
+		lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("  value: %q", utils.TruncateString(preview, MaxStringPreview))))
+		return strings.Join(lines, "\n")
+	}
+	if _, ok := m.ctx.ArrayReg.GetPrimitiveArray(objectID); ok && m.ctx.ArrayReg.ContentsSkipped() {
+		lines = append(lines, utils.MutedStyle.Render("  value: not read (--structure-only)"))
+		return strings.Join(lines, "\n")
+	}
+
+	if classDump, ok := m.ctx.ClassDumpReg.GetClassDump(objectID); ok {
+		lines[0] = utils.InfoStyle.Render("Static fields:")
+		if len(classDump.StaticFields) == 0 {
+			lines = append(lines, utils.MutedStyle.Render("  (none)"))
+		}
+		for i, field := range classDump.StaticFields {
+			if i == MaxInspectorFields {
+				lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("  … %d more fields", len(classDump.StaticFields)-i)))
+				break
+			}
+			name := m.ctx.StringReg.GetOrUnresolved(field.NameID)
+			lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("  %-24s %s", name, field.Type)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	return utils.MutedStyle.Render("Fields: (none)")
+}
+
+func (m *Model) renderInspectorRow(row inspectorRow, references map[ReferenceDirection][]objectReference, isSelected bool) string {
+	if row.isSection {
+		expandIcon := "[+]"
+		if m.inspectorState.expanded[row.direction] {
+			expandIcon = "[-]"
+		}
+
+		title := "Outbound references"
+		if row.direction == InboundReferences {
+			title = "Inbound references"
+		}
+		order := "field order"
+		if m.inspectorState.sortByRetained {
+			order = "by retained size"
+		}
+
+		line := fmt.Sprintf("%s %s (%d, %s)", expandIcon, title, len(references[row.direction]), order)
+		if isSelected {
+			return renderSelectedRow(line)
+		}
+		return utils.InfoStyle.Render("  " + line)
+	}
+
+	if row.reference.id == 0 {
+		return utils.MutedStyle.Render("      " + row.reference.label)
+	}
+
+	object, _ := m.ctx.DescribeObject(row.reference.id)
+	arrow := "→"
+	if row.direction == InboundReferences {
+		arrow = "←"
+	}
+
+	name := object.DisplayName()
+	if row.reference.label != "" {
+		name = fmt.Sprintf("%s: %s", row.reference.label, name)
+	}
+
+	nameWidth := max(30, m.width-30)
+	line := fmt.Sprintf("    %s %-*s %10s",
+		arrow, nameWidth, utils.TruncateString(name, nameWidth),
+		formatSize(m.tree.RetainedSize(row.reference.id)))
+
+	if isSelected {
+		return renderSelectedRow(line)
+	}
+	return utils.TextStyle.Render("  " + line)
+}
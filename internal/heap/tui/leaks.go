@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *Model) handleLeaksKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.leaksState
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(m.suspects)-1 {
+			state.selected++
+		}
+	case "enter", " ":
+		state.expanded[state.selected] = !state.expanded[state.selected]
+	case "i":
+		if state.selected < len(m.suspects) {
+			m.inspect(m.suspects[state.selected].ObjectID)
+		}
+	case "a":
+		if state.selected < len(m.suspects) {
+			suspect := m.suspects[state.selected]
+			if suspect.AccumulationPoint != 0 {
+				m.inspect(suspect.AccumulationPoint)
+			} else {
+				m.inspect(suspect.ObjectID)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) RenderLeakSuspects(height int) string {
+	if len(m.suspects) == 0 {
+		return utils.GoodStyle.Render(fmt.Sprintf(
+			"✅ No leak suspects found!\n\nNo single object or class retains more than %s of the reachable heap.",
+			utils.Precision(0).Percent(analyzer.LeakSuspectThreshold*100)))
+	}
+
+	header := utils.TitleStyle.Render(fmt.Sprintf("🔍 %d leak suspect(s) retaining %s+ of the reachable heap",
+		len(m.suspects), utils.Precision(0).Percent(analyzer.LeakSuspectThreshold*100)))
+
+	var lines []string
+	selectedStartLine := 0
+	for i, suspect := range m.suspects {
+		if i == m.leaksState.selected {
+			selectedStartLine = len(lines)
+		}
+		lines = append(lines, m.renderSuspectItem(i, suspect)...)
+		lines = append(lines, "") // Spacing between suspects
+	}
+
+	// Keep the selected suspect visible, same as the issues list
+	availableHeight := height - 2
+	if len(lines) > availableHeight {
+		scrollY := 0
+		if selectedStartLine >= availableHeight/2 {
+			scrollY = selectedStartLine - availableHeight/2
+		}
+		scrollY = max(min(scrollY, len(lines)-availableHeight), 0)
+		lines = lines[scrollY:min(scrollY+availableHeight, len(lines))]
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		strings.Join(lines, "\n"),
+	)
+}
+
+func (m *Model) renderSuspectItem(index int, suspect *analyzer.LeakSuspect) []string {
+	var lines []string
+
+	isSelected := index == m.leaksState.selected
+	isExpanded := m.leaksState.expanded[index]
+
+	style := utils.WarningStyle
+	icon := "⚠️ "
+	if suspect.Percentage >= 30 {
+		style = utils.CriticalStyle
+		icon = "🔴"
+	}
+
+	selector := " "
+	if isSelected {
+		selector = "▶"
+	}
+
+	expandIcon := "[+]"
+	if isExpanded {
+		expandIcon = "[-]"
+	}
+
+	titleLine := fmt.Sprintf("%s %s Suspect %d: %s (%s, %s)", selector, icon, index+1,
+		suspect.ClassName, formatSize(suspect.RetainedSize), utils.FormatPercent(suspect.Percentage))
+	if isSelected {
+		titleLine = utils.SelectedStyle.Render(titleLine)
+	} else {
+		titleLine = style.Render(titleLine)
+	}
+	lines = append(lines, titleLine)
+
+	for j, line := range utils.WrapText(suspect.Description, m.width-8) {
+		prefix := "  ├─ "
+		if j > 0 {
+			prefix = "  │  "
+		}
+		lines = append(lines, utils.MutedStyle.Render(prefix+line))
+	}
+
+	expandLine := fmt.Sprintf("  └─ %s Show Details", expandIcon)
+	if isSelected {
+		expandLine = utils.InfoStyle.Render(expandLine)
+	} else {
+		expandLine = utils.MutedStyle.Render(expandLine)
+	}
+	lines = append(lines, expandLine)
+
+	if isExpanded {
+		lines = append(lines, m.renderSuspectDetails(suspect)...)
+	}
+
+	return lines
+}
+
+func (m *Model) renderSuspectDetails(suspect *analyzer.LeakSuspect) []string {
+	lines := []string{""}
+
+	lines = append(lines, utils.InfoStyle.Render("     Details:"))
+	lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("       Kind: %s", suspect.Kind)))
+	if suspect.Kind == analyzer.ClassGroupSuspect {
+		lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("       Instances: %s", utils.FormatCount(int64(suspect.InstanceCount)))))
+	}
+	if suspect.ObjectID != 0 {
+		object, _ := m.ctx.DescribeObject(suspect.ObjectID)
+		lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("       Object: %s", object.DisplayName())))
+	}
+
+	if suspect.AccumulationPoint != 0 && suspect.AccumulationPoint != suspect.ObjectID {
+		lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("       Accumulation point: %s @ 0x%x (%s)",
+			suspect.AccumulationClassName, uint64(suspect.AccumulationPoint),
+			formatSize(suspect.AccumulationRetained))))
+		if suspect.AccumulationField != "" {
+			lines = append(lines, utils.TextStyle.Render(fmt.Sprintf("       Held by: %s", suspect.AccumulationField)))
+		}
+	}
+
+	if len(suspect.DominatorPath) > 0 {
+		lines = append(lines, "", utils.InfoStyle.Render("     Dominator path:"))
+		for depth, objectID := range suspect.DominatorPath {
+			object, _ := m.ctx.DescribeObject(objectID)
+			connector := "└─ "
+			if depth == 0 {
+				connector = ""
+			}
+			line := fmt.Sprintf("       %s%s%s (%s)", strings.Repeat("   ", max(depth-1, 0)), connector,
+				object.DisplayName(), formatSize(m.tree.RetainedSize(objectID)))
+			lines = append(lines, utils.TextStyle.Render(line))
+		}
+	}
+
+	if len(suspect.AllocationSites) > 0 {
+		lines = append(lines, "", utils.InfoStyle.Render(fmt.Sprintf("     Allocation sites of %s:", suspect.ClassName)))
+		for _, site := range suspect.AllocationSites {
+			line := fmt.Sprintf("       %s (%s, %s instances)", site.Frame,
+				formatSize(site.RetainedSize), utils.FormatCount(int64(site.Objects)))
+			lines = append(lines, utils.TextStyle.Render(utils.TruncateString(line, m.width-2)))
+			for _, caller := range site.Callers {
+				lines = append(lines, utils.MutedStyle.Render(utils.TruncateString("         ← "+caller, m.width-2)))
+			}
+		}
+	}
+
+	return lines
+}
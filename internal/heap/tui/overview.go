@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const overviewTopClasses = 10
+
+func (m *Model) RenderOverview(height int) string {
+	var sections []string
+
+	sections = append(sections, m.renderDumpInfo())
+	if m.info.Truncated {
+		sections = append(sections, utils.WarningStyle.Render(fmt.Sprintf(
+			"⚠️  Heap dump is truncated at offset %d (%s) - results cover only the readable portion",
+			m.info.TruncatedAt, m.info.TruncatedReason)))
+	}
+	sections = append(sections, m.renderHeapTotals())
+	if m.refStats != nil {
+		sections = append(sections, m.renderReferenceTotals())
+	}
+	sections = append(sections, m.renderTopClassesChart())
+
+	content := strings.Join(sections, "\n\n")
+	return m.scrollContent(OverviewTab, content, height)
+}
+
+func (m *Model) renderDumpInfo() string {
+	lines := []string{utils.TitleStyle.Render("📁 Heap Dump")}
+	lines = append(lines, utils.FormatKeyValue("File", filepath.Base(m.info.Filename), 18))
+
+	if header := m.info.Header; header != nil {
+		lines = append(lines, utils.FormatKeyValue("Format", header.Format, 18))
+		lines = append(lines, utils.FormatKeyValue("Identifier size", fmt.Sprintf("%d bytes", header.IdentifierSize), 18))
+		lines = append(lines, utils.FormatKeyValue("Dumped at", header.Timestamp.Format(time.RFC3339), 18))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderHeapTotals() string {
+	ctx := m.ctx
+	lines := []string{utils.TitleStyle.Render("📦 Objects")}
+
+	instances := ctx.InstanceReg.GetCount()
+	objectArrays := ctx.ArrayReg.GetObjectArrayCount()
+	primitiveArrays := ctx.ArrayReg.GetPrimitiveArrayCount()
+	classes := ctx.ClassDumpReg.GetCount()
+
+	lines = append(lines, utils.FormatKeyValue("Instances", utils.FormatCount(int64(instances)), 18))
+	lines = append(lines, utils.FormatKeyValue("Object arrays", utils.FormatCount(int64(objectArrays)), 18))
+	lines = append(lines, utils.FormatKeyValue("Primitive arrays", utils.FormatCount(int64(primitiveArrays)), 18))
+	lines = append(lines, utils.FormatKeyValue("Classes", utils.FormatCount(int64(classes)), 18))
+	lines = append(lines, utils.FormatKeyValue("GC roots", utils.FormatCount(int64(ctx.RootReg.GetTotalRoots())), 18))
+
+	lines = append(lines, "", utils.TitleStyle.Render("💾 Memory"))
+
+	totalShallow := m.histogram.TotalShallowSize
+	reachable := m.tree.TotalRetainedSize()
+	unreachable := uint64(0)
+	if totalShallow > reachable {
+		unreachable = totalShallow - reachable
+	}
+
+	lines = append(lines, utils.FormatKeyValue("Total heap", formatSize(totalShallow), 18))
+	lines = append(lines, utils.FormatKeyValue("Reachable", fmt.Sprintf("%s (%s objects)",
+		formatSize(reachable), utils.FormatCount(int64(m.tree.ReachableCount()))), 18))
+
+	unreachableLine := utils.FormatKeyValue("Unreachable", formatSize(unreachable), 18)
+	if totalShallow > 0 && float64(unreachable)/float64(totalShallow) > 0.25 {
+		unreachableLine = utils.WarningStyle.Render(unreachableLine + "  (garbage not yet collected)")
+	}
+	lines = append(lines, unreachableLine)
+
+	suspectsLine := utils.FormatKeyValue("Leak suspects", fmt.Sprintf("%d", len(m.suspects)), 18)
+	if len(m.suspects) > 0 {
+		suspectsLine = utils.CriticalStyle.Render(suspectsLine + "  (see tab 4)")
+	} else {
+		suspectsLine = utils.GoodStyle.Render(suspectsLine)
+	}
+	lines = append(lines, suspectsLine)
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderReferenceTotals() string {
+	stats := m.refStats
+	lines := []string{utils.TitleStyle.Render("🔗 Reference Reachability")}
+
+	lines = append(lines, utils.FormatKeyValue("Strong", formatSize(stats.StronglyReachableSize), 18))
+	for _, kind := range analyzer.ReferenceKinds {
+		kindStats := stats.Kinds[kind]
+		if kindStats.Count == 0 {
+			continue
+		}
+		value := fmt.Sprintf("%s only (%d references)", formatSize(kindStats.ReachableSize), kindStats.Count)
+		lines = append(lines, utils.FormatKeyValue(strings.ToUpper(kind.String()[:1])+kind.String()[1:], value, 18))
+	}
+
+	if reclaimable := stats.ReclaimableSize(); reclaimable > 0 {
+		lines = append(lines, utils.GoodStyle.Render(fmt.Sprintf("♻️  %s can be freed under memory pressure", formatSize(reclaimable))))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderTopClassesChart() string {
+	total := m.tree.TotalRetainedSize()
+	if total == 0 || len(m.histogram.Entries) == 0 {
+		return utils.MutedStyle.Render("No reachable objects")
+	}
+
+	// The histogram is kept in the user's sort order, so rank a copy by retained size
+	entries := m.topClassesByRetained(overviewTopClasses)
+
+	labelWidth := 40
+	config := utils.DefaultBarConfig(max(10, m.width-labelWidth-30))
+	config.LabelWidth = labelWidth
+	config.ShowValue = false
+
+	var bars []utils.BarData
+	for _, entry := range entries {
+		percentage := float64(entry.RetainedSize) / float64(total) * 100
+		style := utils.GoodStyle
+		if percentage >= 30 {
+			style = utils.CriticalStyle
+		} else if percentage >= 10 {
+			style = utils.WarningStyle
+		}
+
+		bars = append(bars, utils.BarData{
+			Label:      utils.TruncateString(entry.ClassName, labelWidth),
+			Percentage: percentage,
+			Style:      style,
+			Suffix:     fmt.Sprintf("%s retained", formatSize(entry.RetainedSize)),
+		})
+	}
+
+	return utils.CreateHorizontalBarChart(utils.TitleStyle.Render("🏆 Top Classes by Retained Size"), bars, config)
+}
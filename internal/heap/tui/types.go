@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/model"
+)
+
+type Model struct {
+	// Data
+	analyzer  *analyzer.Analyzer
+	ctx       *analyzer.AnalysisContext
+	tree      *analyzer.DominatorTree
+	histogram *analyzer.ClassHistogram
+	suspects  []*analyzer.LeakSuspect
+	refStats  *analyzer.ReferenceStats
+	fields    *analyzer.FieldExtractor
+	info      DumpInfo
+
+	// UI State
+	currentTab TabType
+	width      int
+	height     int
+
+	scrollPositions map[TabType]int
+	histogramState  *HistogramState
+	dominatorsState *DominatorsState
+	leaksState      *LeaksState
+	inspectorState  *InspectorState
+}
+
+// DumpInfo describes the heap dump file being explored
+type DumpInfo struct {
+	Filename        string
+	Header          *model.HprofHeader
+	Truncated       bool
+	TruncatedAt     int64
+	TruncatedReason string
+}
+
+type TabType int
+
+const (
+	OverviewTab TabType = iota
+	HistogramTab
+	DominatorsTab
+	LeakSuspectsTab
+	InspectorTab
+)
+
+// SearchState holds an in-progress or applied '/' search
+type SearchState struct {
+	active bool // Typing a search term
+	term   string
+}
+
+type HistogramState struct {
+	selected int
+	sortBy   analyzer.HistogramSortBy
+	search   SearchState
+}
+
+type DominatorsState struct {
+	selected int
+	expanded map[model.ID]bool
+	search   SearchState
+
+	// Matches for search.term, cached because finding them walks the whole tree
+	results     []dominatorRow
+	resultsTerm string
+}
+
+type dominatorRow struct {
+	id       model.ID
+	depth    int
+	hasMore  bool // Placeholder for children beyond MaxTreeChildren
+	moreLeft int
+}
+
+type LeaksState struct {
+	selected int
+	expanded map[int]bool
+}
+
+type InspectorState struct {
+	current        model.ID
+	history        []model.ID
+	selected       int
+	expanded       map[ReferenceDirection]bool
+	sortByRetained bool
+
+	// References of the current object, rebuilt when the object or sort changes
+	references      map[ReferenceDirection][]objectReference
+	referencesFor   model.ID
+	referencesOrder bool
+}
+
+type ReferenceDirection int
+
+const (
+	OutboundReferences ReferenceDirection = iota
+	InboundReferences
+)
+
+type inspectorRow struct {
+	direction ReferenceDirection
+	isSection bool
+	reference objectReference
+}
+
+// objectReference is one edge shown in the inspector
+type objectReference struct {
+	label string // Field name, array index or referrer class
+	id    model.ID
+}
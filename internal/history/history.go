@@ -0,0 +1,190 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+/*
+ * The history DB keeps one record per analyzed log, and one per directory at
+ * each digest, in ~/.jdiag/history.jsonl. It's JSON lines so records are only ever
+ * appended, a crash costs at most the line being written (which Open
+ * skips), and it reads with jq:
+ *
+ *   jq -r 'select(.kind == "analysis") | [.time, .file, .score] | @tsv' ~/.jdiag/history.jsonl
+ *
+ * Records are grouped by the directory the log was found in, which stands
+ * for one service: week-over-week compares a group with itself. An
+ * analysis counts towards the period its log ended in, not the one it was
+ * analyzed in, so a backlog of rotated logs found at first start, or after
+ * downtime, lands on the days it covers.
+ */
+
+const (
+	KindAnalysis = "analysis"
+	KindDigest   = "digest"
+
+	FileName = "history.jsonl"
+)
+
+// Record is one line of the history DB
+type Record struct {
+	Kind  string    `json:"kind"`
+	Time  time.Time `json:"time"`  // When the log was analyzed, or the digest made
+	Group string    `json:"group"` // Directory the log was found in
+
+	File       string    `json:"file,omitempty"`
+	SHA256     string    `json:"sha256,omitempty"` // Of the file's content, so a log is analyzed once whatever it's renamed to
+	LogStart   time.Time `json:"logStart,omitzero"`
+	LogEnd     time.Time `json:"logEnd,omitzero"`
+	Events     int       `json:"events,omitempty"`
+	Score      int       `json:"score"`
+	Throughput float64   `json:"throughput,omitempty"`
+	MaxPauseMs float64   `json:"maxPauseMs,omitempty"`
+	P99PauseMs float64   `json:"p99PauseMs,omitempty"`
+	FullGCs    int       `json:"fullGCs,omitempty"`
+	Critical   []string  `json:"critical,omitempty"` // Issue types raised
+	Warning    []string  `json:"warning,omitempty"`
+
+	// Digests only: Score is the period's average, compared with the one before
+	Period        string `json:"period,omitempty"` // "day" or "week"
+	Logs          int    `json:"logs,omitempty"`
+	PreviousScore int    `json:"previousScore,omitempty"`
+	PreviousLogs  int    `json:"previousLogs,omitempty"`
+	Degraded      bool   `json:"degraded,omitempty"`
+}
+
+// DB is the history file and the records read from it
+type DB struct {
+	path    string
+	mu      sync.RWMutex
+	records []Record
+	hashes  map[string]bool
+}
+
+// DefaultPath is ~/.jdiag/history.jsonl, or "" when the home directory is unknown
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".jdiag", FileName)
+}
+
+// Open reads the history at path; a missing file is an empty history
+func Open(path string) (*DB, error) {
+	db := &DB{path: path, hashes: make(map[string]bool)}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // Torn by a crash mid-write
+		}
+		db.add(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return db, nil
+}
+
+// Path is the file the history is kept in
+func (db *DB) Path() string {
+	return db.path
+}
+
+func (db *DB) add(record Record) {
+	db.records = append(db.records, record)
+	if record.SHA256 != "" {
+		db.hashes[record.SHA256] = true
+	}
+}
+
+// Append writes the record to the end of the history
+func (db *DB) Append(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(db.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create history directory: %w", err)
+	}
+	file, err := os.OpenFile(db.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	db.add(record)
+	return nil
+}
+
+// Analyzed reports whether a log with this content hash has a record
+func (db *DB) Analyzed(sha256 string) bool {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.hashes[sha256]
+}
+
+// PeriodTime is when the record counts for: the end of its log, or when it was made for
+// digests and logs without wall clock timestamps
+func (r Record) PeriodTime() time.Time {
+	if !r.LogEnd.IsZero() {
+		return r.LogEnd
+	}
+	return r.Time
+}
+
+// Records returns the records of a kind and group whose PeriodTime is in [from, to), oldest first; "" matches every group
+func (db *DB) Records(kind, group string, from, to time.Time) []Record {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var records []Record
+	for _, record := range db.records {
+		at := record.PeriodTime()
+		if record.Kind == kind && (group == "" || record.Group == group) && !at.Before(from) && at.Before(to) {
+			records = append(records, record)
+		}
+	}
+	slices.SortStableFunc(records, func(a, b Record) int { return a.PeriodTime().Compare(b.PeriodTime()) })
+	return records
+}
+
+// LastDigest is when the latest digest was made, or the zero time when none was
+func (db *DB) LastDigest() time.Time {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var last time.Time
+	for _, record := range db.records {
+		if record.Kind == KindDigest && record.Time.After(last) {
+			last = record.Time
+		}
+	}
+	return last
+}
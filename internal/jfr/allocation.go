@@ -0,0 +1,200 @@
+package jfr
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Frames in these packages are library code; allocations are attributed to the first frame outside them
+var libraryPackages = []string{"java.", "javax.", "jdk.", "sun.", "com.sun."}
+
+const unknownSite = "<no stack trace>"
+
+// AllocationProfile aggregates allocation samples by what was allocated and where
+type AllocationProfile struct {
+	Samples  int
+	Total    utils.MemorySize
+	Duration time.Duration
+
+	Classes []*ClassAllocation // Largest first
+	Sites   []*Allocator       // First application frame of each sample's stack; largest first
+	Stacks  []*StackAllocation // Distinct (class, stack trace) pairs; largest first
+	Threads []*Allocator       // Largest first
+}
+
+// Allocator is anything allocation weight is attributed to: a site, a thread or a class
+type Allocator struct {
+	Name    string
+	Weight  utils.MemorySize
+	Samples int
+}
+
+type ClassAllocation struct {
+	Allocator
+	Sites []*Allocator // Where this class is allocated; largest first
+}
+
+type StackAllocation struct {
+	Allocator // Name is the allocated class
+	Stack     *StackTrace
+}
+
+func (p *AllocationProfile) Share(weight utils.MemorySize) float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(weight) / float64(p.Total)
+}
+
+// Rate is the average bytes allocated per second over the recording
+func (p *AllocationProfile) Rate() utils.MemorySize {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return utils.MemorySize(float64(p.Total) / p.Duration.Seconds())
+}
+
+/*
+ * AllocationProfile aggregates the recording's allocation samples.
+ *
+ * Each jdk.ObjectAllocationSample carries a weight: the bytes allocated by
+ * its thread since the previous sample, so summing weights estimates the
+ * real allocation volume rather than counting samples. Recordings without
+ * samples (JDK 15 and older) fall back to TLAB events, weighted by the TLAB
+ * or object size.
+ *
+ * A sample's site is its first frame outside the JDK, since the frame that
+ * actually allocates is usually a collection or string builder internal.
+ */
+func (r *Recording) AllocationProfile() *AllocationProfile {
+	profile := &AllocationProfile{Samples: len(r.Allocations), Duration: r.Duration()}
+
+	classes := make(map[string]*ClassAllocation)
+	classSites := make(map[string]map[string]*Allocator)
+	sites := make(map[string]*Allocator)
+	stacks := make(map[string]*StackAllocation)
+	threads := make(map[string]*Allocator)
+
+	for _, sample := range r.Allocations {
+		profile.Total += sample.Weight
+
+		class, ok := classes[sample.Class]
+		if !ok {
+			class = &ClassAllocation{Allocator: Allocator{Name: sample.Class}}
+			classes[sample.Class] = class
+			classSites[sample.Class] = make(map[string]*Allocator)
+		}
+		class.add(sample)
+
+		site := allocationSite(sample.Stack)
+		addTo(sites, site, sample)
+		addTo(classSites[sample.Class], site, sample)
+		addTo(threads, sample.Thread, sample)
+
+		key := sample.Class + "\n" + stackKey(sample.Stack)
+		stack, ok := stacks[key]
+		if !ok {
+			stack = &StackAllocation{Allocator: Allocator{Name: sample.Class}, Stack: sample.Stack}
+			stacks[key] = stack
+		}
+		stack.add(sample)
+	}
+
+	for name, class := range classes {
+		class.Sites = sortedAllocators(classSites[name])
+		profile.Classes = append(profile.Classes, class)
+	}
+	sort.Slice(profile.Classes, func(i, j int) bool {
+		return heavier(&profile.Classes[i].Allocator, &profile.Classes[j].Allocator)
+	})
+
+	for _, stack := range stacks {
+		profile.Stacks = append(profile.Stacks, stack)
+	}
+	sort.Slice(profile.Stacks, func(i, j int) bool {
+		if profile.Stacks[i].Weight != profile.Stacks[j].Weight {
+			return profile.Stacks[i].Weight > profile.Stacks[j].Weight
+		}
+		return stackKey(profile.Stacks[i].Stack) < stackKey(profile.Stacks[j].Stack)
+	})
+
+	profile.Sites = sortedAllocators(sites)
+	profile.Threads = sortedAllocators(threads)
+	return profile
+}
+
+func (a *Allocator) add(sample *AllocationSample) {
+	a.Weight += sample.Weight
+	a.Samples++
+}
+
+func addTo(allocators map[string]*Allocator, name string, sample *AllocationSample) {
+	allocator, ok := allocators[name]
+	if !ok {
+		allocator = &Allocator{Name: name}
+		allocators[name] = allocator
+	}
+	allocator.add(sample)
+}
+
+func sortedAllocators(allocators map[string]*Allocator) []*Allocator {
+	sorted := make([]*Allocator, 0, len(allocators))
+	for _, allocator := range allocators {
+		sorted = append(sorted, allocator)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return heavier(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+func heavier(a, b *Allocator) bool {
+	if a.Weight != b.Weight {
+		return a.Weight > b.Weight
+	}
+	return a.Name < b.Name
+}
+
+// ApplicationFrame returns the innermost frame outside the JDK, or the top frame of a JDK-only stack
+func (s *StackTrace) ApplicationFrame() *StackFrame {
+	if s == nil {
+		return nil
+	}
+	for _, frame := range s.Frames {
+		if !isLibraryClass(frame.Class) {
+			return frame
+		}
+	}
+	return s.TopFrame()
+}
+
+func isLibraryClass(class string) bool {
+	for _, prefix := range libraryPackages {
+		if strings.HasPrefix(class, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func allocationSite(stack *StackTrace) string {
+	if frame := stack.ApplicationFrame(); frame != nil {
+		return frame.String()
+	}
+	return unknownSite
+}
+
+func stackKey(stack *StackTrace) string {
+	if stack == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, frame := range stack.Frames {
+		b.WriteString(frame.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,272 @@
+package jfr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const (
+	chunkHeaderSize = 68
+	chunkMagic      = "FLR\x00"
+
+	metadataTypeID   = 0
+	checkpointTypeID = 1
+
+	featureCompressedInts = 1
+	maxResolveDepth       = 8
+)
+
+// chunkHeader is the fixed 68-byte header that starts every chunk
+type chunkHeader struct {
+	major, minor     uint16
+	size             int64
+	constantPoolPos  int64
+	metadataPos      int64
+	startNanos       int64 // Wall clock, nanoseconds since the epoch
+	durationNanos    int64
+	startTicks       int64
+	ticksPerSecond   int64
+	compressedValues bool
+}
+
+// chunk is one self-contained piece of a recording: its own metadata, constant pools and events
+type chunk struct {
+	header  chunkHeader
+	data    []byte
+	classes map[int64]*class
+	pools   map[int64]map[int64]any // Type id -> constant key -> decoded value
+}
+
+// constantRef is a value stored in a constant pool, resolved after every checkpoint is read
+type constantRef struct {
+	typeID int64
+	key    int64
+}
+
+// object is a decoded event or composite value; values line up with class.fields
+type object struct {
+	class  *class
+	values []any
+}
+
+func parseChunkHeader(data []byte) (chunkHeader, error) {
+	var header chunkHeader
+	if len(data) < chunkHeaderSize {
+		return header, fmt.Errorf("chunk header truncated: %d bytes", len(data))
+	}
+	if string(data[:4]) != chunkMagic {
+		return header, fmt.Errorf("not a JFR recording: bad magic %q", data[:4])
+	}
+
+	be := binary.BigEndian
+	header.major = be.Uint16(data[4:])
+	header.minor = be.Uint16(data[6:])
+	header.size = int64(be.Uint64(data[8:]))
+	header.constantPoolPos = int64(be.Uint64(data[16:]))
+	header.metadataPos = int64(be.Uint64(data[24:]))
+	header.startNanos = int64(be.Uint64(data[32:]))
+	header.durationNanos = int64(be.Uint64(data[40:]))
+	header.startTicks = int64(be.Uint64(data[48:]))
+	header.ticksPerSecond = int64(be.Uint64(data[56:]))
+	header.compressedValues = be.Uint32(data[64:])&featureCompressedInts != 0
+
+	if header.major != 2 {
+		return header, fmt.Errorf("unsupported JFR format %d.%d (JDK 11+ and 8u262+ write 2.x)", header.major, header.minor)
+	}
+	if header.ticksPerSecond <= 0 {
+		return header, fmt.Errorf("invalid chunk header: %d ticks per second", header.ticksPerSecond)
+	}
+	return header, nil
+}
+
+// newChunk loads a chunk's metadata and constant pools so its events can be decoded
+func newChunk(header chunkHeader, data []byte) (*chunk, error) {
+	c := &chunk{header: header, data: data, pools: make(map[int64]map[int64]any)}
+
+	if header.metadataPos < chunkHeaderSize || header.metadataPos >= int64(len(data)) {
+		return nil, fmt.Errorf("metadata offset %d outside chunk of %d bytes", header.metadataPos, len(data))
+	}
+	r := c.reader()
+	r.pos = int(header.metadataPos)
+	classes, err := readMetadata(r)
+	if err != nil {
+		return nil, err
+	}
+	c.classes = classes
+
+	// Events can refer to constants from checkpoints written after them, so load every pool first
+	err = c.forEachEvent(func(r *reader, typeID int64) error {
+		if typeID == checkpointTypeID {
+			return c.readCheckpoint(r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *chunk) reader() *reader {
+	return newReader(c.data, c.header.compressedValues)
+}
+
+// forEachEvent calls fn for each event with the reader positioned after the event's type id
+func (c *chunk) forEachEvent(fn func(r *reader, typeID int64) error) error {
+	r := c.reader()
+	pos := chunkHeaderSize
+
+	for pos < len(c.data) {
+		r.pos = pos
+		size := r.int()
+		if r.err() != nil || size <= 0 || int64(pos)+size > int64(len(c.data)) {
+			return fmt.Errorf("invalid event size %d at chunk offset %d", size, pos)
+		}
+		typeID := r.long()
+
+		// Confine the callback to this event's bytes
+		event := newReader(c.data[:pos+int(size)], c.header.compressedValues)
+		event.pos = r.pos
+		if err := fn(event, typeID); err != nil {
+			return err
+		}
+		if err := event.err(); err != nil {
+			return fmt.Errorf("event type %d at chunk offset %d: %w", typeID, pos, err)
+		}
+		pos += int(size)
+	}
+	return nil
+}
+
+/*
+ * readCheckpoint loads a constant pool event:
+ *
+ *   start time, duration, delta to the previous checkpoint, checkpoint type (1 byte),
+ *   pool count, then per pool: type id, constant count, (key, value)...
+ *
+ * Every checkpoint is visited by the sequential event scan, so the delta
+ * chain is not needed.
+ */
+func (c *chunk) readCheckpoint(r *reader) error {
+	r.long() // Start time
+	r.long() // Duration
+	r.long() // Delta
+	r.u1()   // Checkpoint type
+
+	pools := r.int()
+	for i := int64(0); i < pools && r.err() == nil; i++ {
+		typeID := r.long()
+		if r.err() != nil {
+			break // Reported by the event scan, rather than as a pool for type 0
+		}
+		class, ok := c.classes[typeID]
+		if !ok {
+			return fmt.Errorf("constant pool for unknown type %d", typeID)
+		}
+
+		pool := c.pools[typeID]
+		if pool == nil {
+			pool = make(map[int64]any)
+			c.pools[typeID] = pool
+		}
+
+		count := r.int()
+		for j := int64(0); j < count && r.err() == nil; j++ {
+			key := r.long()
+			pool[key] = c.decode(r, class, 0)
+		}
+	}
+	return nil
+}
+
+// decode reads one value of a class: a primitive, a string or a composite of its fields
+func (c *chunk) decode(r *reader, class *class, depth int) any {
+	switch class.name {
+	case "boolean":
+		return r.bool()
+	case "byte":
+		return int64(int8(r.u1()))
+	case "char":
+		return r.char()
+	case "short":
+		return r.short()
+	case "int":
+		return r.int()
+	case "long":
+		return r.long()
+	case "float":
+		return r.float()
+	case "double":
+		return r.double()
+	case "java.lang.String":
+		return r.string(class.id)
+	}
+
+	if depth > maxResolveDepth*4 {
+		r.fail("values of %s nested too deeply", class.name)
+		return nil
+	}
+
+	obj := &object{class: class, values: make([]any, len(class.fields))}
+	for i, f := range class.fields {
+		if r.err() != nil {
+			break
+		}
+		obj.values[i] = c.decodeField(r, f, depth+1)
+	}
+	return obj
+}
+
+func (c *chunk) decodeField(r *reader, f *field, depth int) any {
+	if f.array {
+		n := r.length()
+		values := make([]any, n)
+		for i := range values {
+			values[i] = c.decodeSingle(r, f, depth)
+		}
+		return values
+	}
+	return c.decodeSingle(r, f, depth)
+}
+
+func (c *chunk) decodeSingle(r *reader, f *field, depth int) any {
+	if f.constantPool {
+		return constantRef{typeID: f.typeID, key: r.long()}
+	}
+	class, ok := c.classes[f.typeID]
+	if !ok {
+		r.fail("field %s has unknown type %d", f.name, f.typeID)
+		return nil
+	}
+	return c.decode(r, class, depth)
+}
+
+// resolve follows constant pool references to the stored value
+func (c *chunk) resolve(value any) any {
+	for i := 0; i < maxResolveDepth; i++ {
+		ref, ok := value.(constantRef)
+		if !ok {
+			return value
+		}
+		value = c.pools[ref.typeID][ref.key]
+	}
+	return nil
+}
+
+func (c *chunk) ticksToTime(ticks int64) time.Time {
+	elapsed := float64(ticks-c.header.startTicks) * float64(time.Second) / float64(c.header.ticksPerSecond)
+	return time.Unix(0, c.header.startNanos+int64(elapsed))
+}
+
+func (c *chunk) ticksToDuration(ticks int64) time.Duration {
+	return time.Duration(float64(ticks) * float64(time.Second) / float64(c.header.ticksPerSecond))
+}
+
+func (c *chunk) startTime() time.Time {
+	return time.Unix(0, c.header.startNanos)
+}
+
+func (c *chunk) duration() time.Duration {
+	return time.Duration(c.header.durationNanos)
+}
@@ -0,0 +1,400 @@
+package jfr
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+ * The tests build chunks by hand with compressed integers, the layout every
+ * JDK 11+ recording uses: the header, the metadata event at offset 68, then
+ * checkpoint and other events in any order.
+ */
+
+const (
+	testLongID      = 4
+	testIntID       = 5
+	testStringID    = 8
+	testGCNameID    = 20
+	testTimespanID  = 21
+	testCollectedID = 100
+
+	testStartNanos = int64(1_700_000_000_000_000_000)
+)
+
+// varint encodes a compressed integer; negative ints take their 32-bit two's complement, as the JDK writes them
+func varint(value int64) []byte {
+	return binary.AppendUvarint(nil, uint64(value))
+}
+
+// paddedVarint is the fixed 4-byte varint the JDK writes event sizes as, so they can be patched in place
+func paddedVarint(value int) []byte {
+	return []byte{byte(value) | 0x80, byte(value>>7) | 0x80, byte(value>>14) | 0x80, byte(value>>21) & 0x7f}
+}
+
+func utf8String(s string) []byte {
+	return append(append([]byte{stringUTF8}, varint(int64(len(s)))...), s...)
+}
+
+// testEvent is size, type id and the body; the size covers all three
+func testEvent(typeID int64, body ...[]byte) []byte {
+	data := varint(typeID)
+	for _, b := range body {
+		data = append(data, b...)
+	}
+	return append(paddedVarint(len(data)+4), data...)
+}
+
+// checkpoint holds one constant pool with one value
+func checkpoint(typeID, key int64, value []byte) []byte {
+	return testEvent(checkpointTypeID, varint(0), varint(0), varint(0), []byte{0},
+		varint(1), varint(typeID), varint(1), varint(key), value)
+}
+
+// node is a metadata element, attributes as name, value pairs
+type node struct {
+	name       string
+	attributes []string
+	children   []node
+}
+
+func classNode(id int64, name string, extra []string, fields ...node) node {
+	return node{"class", append([]string{"id", strconv.FormatInt(id, 10), "name", name}, extra...), fields}
+}
+
+func fieldNode(name string, typeID int64, extra []string, annotations ...node) node {
+	return node{"field", append([]string{"name", name, "class", strconv.FormatInt(typeID, 10)}, extra...), annotations}
+}
+
+func timespan(unit string) node {
+	return node{"annotation", []string{"class", strconv.Itoa(testTimespanID), "value", unit}, nil}
+}
+
+// testClasses are the types of a chunk holding jdk.GarbageCollection events
+func testClasses() []node {
+	return []node{
+		classNode(testLongID, "long", nil),
+		classNode(testIntID, "int", nil),
+		classNode(testStringID, "java.lang.String", nil),
+		classNode(testGCNameID, "jdk.types.GCName", []string{"simpleType", "true"}, fieldNode("name", testStringID, nil)),
+		classNode(testTimespanID, annotationTimespan, nil),
+		classNode(testCollectedID, "jdk.GarbageCollection", nil,
+			fieldNode("startTime", testLongID, nil),
+			fieldNode("duration", testLongID, nil, timespan(unitTicks)),
+			fieldNode("gcId", testIntID, nil),
+			fieldNode("name", testGCNameID, []string{"constantPool", "true"}),
+			fieldNode("cause", testStringID, nil),
+			fieldNode("sumOfPauses", testLongID, nil, timespan(unitNanoseconds)),
+			fieldNode("longestPause", testLongID, nil, timespan(unitNanoseconds)),
+		),
+	}
+}
+
+// metadataEvent encodes the string table and then the element tree, which refers to strings by index
+func metadataEvent(classes ...node) []byte {
+	var strings []string
+	index := map[string]int64{}
+	lookup := func(s string) []byte {
+		i, ok := index[s]
+		if !ok {
+			i = int64(len(strings))
+			index[s] = i
+			strings = append(strings, s)
+		}
+		return varint(i)
+	}
+
+	var element func(n node) []byte
+	element = func(n node) []byte {
+		data := lookup(n.name)
+		data = append(data, varint(int64(len(n.attributes)/2))...)
+		for _, attribute := range n.attributes {
+			data = append(data, lookup(attribute)...)
+		}
+		data = append(data, varint(int64(len(n.children)))...)
+		for _, child := range n.children {
+			data = append(data, element(child)...)
+		}
+		return data
+	}
+	root := element(node{"root", nil, []node{{"metadata", nil, classes}}})
+
+	var table []byte
+	for _, s := range strings {
+		table = append(table, utf8String(s)...)
+	}
+	return rawMetadata(len(strings), table, root)
+}
+
+// rawMetadata is a metadata event from an encoded string table and root element
+func rawMetadata(count int, table, root []byte) []byte {
+	return testEvent(metadataTypeID, varint(0), varint(0), varint(1), varint(int64(count)), table, root)
+}
+
+// testChunk puts a header in front of the metadata event and the rest
+func testChunk(metadata []byte, events ...[]byte) []byte {
+	data := binary.BigEndian.AppendUint32([]byte(chunkMagic), 2<<16|1) // Version 2.1
+	data = binary.BigEndian.AppendUint64(data, 0)                      // Size, set below
+	data = binary.BigEndian.AppendUint64(data, 0)                      // Constant pool offset, unused
+	data = binary.BigEndian.AppendUint64(data, chunkHeaderSize)        // Metadata offset
+	data = binary.BigEndian.AppendUint64(data, uint64(testStartNanos))
+	data = binary.BigEndian.AppendUint64(data, uint64(time.Second))
+	data = binary.BigEndian.AppendUint64(data, 0)   // Start ticks
+	data = binary.BigEndian.AppendUint64(data, 1e9) // Ticks per second
+	data = binary.BigEndian.AppendUint32(data, 1)   // Compressed integers
+	data = append(data, metadata...)
+	for _, event := range events {
+		data = append(data, event...)
+	}
+	binary.BigEndian.PutUint64(data[8:], uint64(len(data)))
+	return data
+}
+
+// collectionEvent is a jdk.GarbageCollection whose name is key 1 in the GC name pool
+func collectionEvent() []byte {
+	return testEvent(testCollectedID,
+		varint(1_000_000), varint(5_000_000), varint(7), varint(1),
+		utf8String("G1 Evacuation Pause"), varint(4_000_000), varint(3_000_000))
+}
+
+func writeRecording(t *testing.T, chunks ...[]byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recording.jfr")
+	var data []byte
+	for _, chunk := range chunks {
+		data = append(data, chunk...)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseFileMinimalChunk(t *testing.T) {
+	// The collection comes before the checkpoint holding its name, as a live JVM can write it
+	chunk := testChunk(metadataEvent(testClasses()...),
+		collectionEvent(),
+		checkpoint(testGCNameID, 1, utf8String("G1New")))
+
+	recording, err := ParseFile(writeRecording(t, chunk, chunk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recording.Chunks != 2 || recording.Incomplete {
+		t.Errorf("Chunks = %d, Incomplete = %v; want 2 complete chunks", recording.Chunks, recording.Incomplete)
+	}
+	if got := recording.EventCounts["jdk.GarbageCollection"]; got != 2 {
+		t.Errorf("EventCounts = %v, want 2 jdk.GarbageCollection", recording.EventCounts)
+	}
+	if !recording.StartTime.Equal(time.Unix(0, testStartNanos)) {
+		t.Errorf("StartTime = %v, want the header's", recording.StartTime)
+	}
+
+	if len(recording.Collections) != 1 {
+		t.Fatalf("got %d collections, want gcId 7 once", len(recording.Collections))
+	}
+	got := recording.Collections[0]
+	want := Collection{
+		ID:           7,
+		Name:         "G1New",
+		Cause:        "G1 Evacuation Pause",
+		Start:        time.Unix(0, testStartNanos+int64(time.Millisecond)),
+		Duration:     5 * time.Millisecond,
+		SumOfPauses:  4 * time.Millisecond,
+		LongestPause: 3 * time.Millisecond,
+	}
+	if got.ID != want.ID || got.Name != want.Name || got.Cause != want.Cause || !got.Start.Equal(want.Start) ||
+		got.Duration != want.Duration || got.SumOfPauses != want.SumOfPauses || got.LongestPause != want.LongestPause {
+		t.Errorf("collection = %+v, want %+v", *got, want)
+	}
+}
+
+func TestParseFileIncompleteLastChunk(t *testing.T) {
+	chunk := testChunk(metadataEvent(testClasses()...), checkpoint(testGCNameID, 1, utf8String("G1New")), collectionEvent())
+	recording, err := ParseFile(writeRecording(t, chunk, chunk[:len(chunk)-10]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recording.Chunks != 1 || !recording.Incomplete {
+		t.Errorf("Chunks = %d, Incomplete = %v; want the cut chunk skipped", recording.Chunks, recording.Incomplete)
+	}
+}
+
+func TestParseChunkHeaderRejects(t *testing.T) {
+	valid := testChunk(metadataEvent(testClasses()...))
+	with := func(offset int, value ...byte) []byte {
+		data := append([]byte(nil), valid...)
+		copy(data[offset:], value)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string // In the error
+	}{
+		{"truncated", valid[:40], "chunk header truncated: 40 bytes"},
+		{"bad magic", with(0, 'P', 'K'), "not a JFR recording"},
+		{"version 1", with(4, 0, 1), "unsupported JFR format 1.1"},
+		{"no ticks", with(56, 0, 0, 0, 0, 0, 0, 0, 0), "0 ticks per second"},
+		{"negative ticks", with(56, 0xff), "ticks per second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseChunkHeader(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseChunkHeader() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Every input below is what a truncated, corrupt or hostile file could hold.
+ * Each must fail with an error, not a panic, an allocation sized by the
+ * input or a recursion that never ends.
+ */
+func TestParseFileRejectsHostileChunks(t *testing.T) {
+	classes := testClasses()
+	withClasses := func(extra ...node) []byte {
+		return metadataEvent(append(testClasses(), extra...)...)
+	}
+	nested := varint(0) // The root, 40 levels of single children deep
+	for range 40 {
+		nested = append(nested, 0, 1, 0)
+	}
+
+	tests := []struct {
+		name  string
+		chunk []byte
+		want  string // In the error
+	}{
+		// chunk.go
+		{
+			name:  "metadata offset past the chunk",
+			chunk: func() []byte { c := testChunk(metadataEvent(classes...)); c[27] = 1; return c }(),
+			want:  "metadata offset 4294967364 outside chunk",
+		},
+		{
+			name:  "event size zero",
+			chunk: testChunk(metadataEvent(classes...), paddedVarint(0)),
+			want:  "invalid event size 0",
+		},
+		{
+			name:  "event size past the chunk",
+			chunk: testChunk(metadataEvent(classes...), paddedVarint(1<<20)),
+			want:  "invalid event size 1048576",
+		},
+		{
+			name:  "event cut short",
+			chunk: testChunk(metadataEvent(classes...), checkpoint(testGCNameID, 1, utf8String("G1New")), testEvent(testCollectedID, varint(1))),
+			want:  "event type 100 at chunk offset",
+		},
+		{
+			name:  "event of unknown type",
+			chunk: testChunk(metadataEvent(classes...), testEvent(999)),
+			want:  "event of unknown type 999",
+		},
+		{
+			name:  "constant pool for unknown type",
+			chunk: testChunk(metadataEvent(classes...), checkpoint(77, 1, nil)),
+			want:  "constant pool for unknown type 77",
+		},
+		{
+			name: "pool count past the event",
+			chunk: testChunk(metadataEvent(classes...),
+				testEvent(checkpointTypeID, varint(0), varint(0), varint(0), []byte{0}, varint(1<<30))),
+			want: "unexpected end of chunk",
+		},
+		{
+			name: "constant count past the event",
+			chunk: testChunk(metadataEvent(classes...),
+				testEvent(checkpointTypeID, varint(0), varint(0), varint(0), []byte{0}, varint(1), varint(testGCNameID), varint(1<<30))),
+			want: "unexpected end of chunk",
+		},
+		{
+			name:  "field of unknown type",
+			chunk: testChunk(withClasses(classNode(30, "Broken", nil, fieldNode("value", 55, nil))), checkpoint(30, 1, nil)),
+			want:  "field value has unknown type 55",
+		},
+		{
+			name:  "value that contains itself",
+			chunk: testChunk(withClasses(classNode(31, "Loop", nil, fieldNode("next", 31, nil))), checkpoint(31, 1, nil)),
+			want:  "values of Loop nested too deeply",
+		},
+		{
+			name: "array length past the event",
+			chunk: testChunk(withClasses(classNode(32, "Items", nil, fieldNode("items", testLongID, []string{"dimension", "1"}))),
+				checkpoint(32, 1, varint(1<<20))),
+			want: "invalid length 1048576",
+		},
+		{
+			name: "negative array length",
+			chunk: testChunk(withClasses(classNode(32, "Items", nil, fieldNode("items", testLongID, []string{"dimension", "1"}))),
+				checkpoint(32, 1, varint(0xffffffff))),
+			want: "invalid length -1",
+		},
+		{
+			name:  "unknown string encoding",
+			chunk: testChunk(metadataEvent(classes...), checkpoint(testStringID, 1, []byte{7})),
+			want:  "unknown string encoding 7",
+		},
+
+		// metadata.go
+		{
+			name:  "string table past the event",
+			chunk: testChunk(rawMetadata(1<<20, nil, nil)),
+			want:  "invalid metadata: invalid length 1048576",
+		},
+		{
+			name:  "string index out of range",
+			chunk: testChunk(rawMetadata(1, utf8String("root"), varint(5))),
+			want:  "metadata string index 5 out of range",
+		},
+		{
+			name:  "negative string index",
+			chunk: testChunk(rawMetadata(1, utf8String("root"), varint(0xffffffff))),
+			want:  "metadata string index -1 out of range",
+		},
+		{
+			name:  "attribute count past the event",
+			chunk: testChunk(rawMetadata(1, utf8String("root"), append(varint(0), varint(1<<20)...))),
+			want:  "invalid metadata: invalid length 1048576",
+		},
+		{
+			name:  "child count past the event",
+			chunk: testChunk(rawMetadata(1, utf8String("root"), append(varint(0), 0, 0x80, 0x80, 0x40))),
+			want:  "invalid metadata: invalid length",
+		},
+		{
+			name:  "elements nested too deeply",
+			chunk: testChunk(rawMetadata(1, utf8String("root"), append(nested, 0, 0))),
+			want:  "metadata nested too deeply",
+		},
+		{
+			name:  "class id not a number",
+			chunk: testChunk(metadataEvent(node{"class", []string{"id", "x", "name", "Broken"}, nil})),
+			want:  `invalid class id "x"`,
+		},
+		{
+			name:  "field type not a number",
+			chunk: testChunk(metadataEvent(classNode(33, "Broken", nil, node{"field", []string{"name", "f", "class", "y"}, nil}))),
+			want:  "invalid type of field Broken.f",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFile(writeRecording(t, tt.chunk))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseFile() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
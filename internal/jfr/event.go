@@ -0,0 +1,155 @@
+package jfr
+
+import (
+	"time"
+)
+
+// event is a decoded event or nested value with typed accessors by field name.
+// Missing fields and values of another type read as zero.
+type event struct {
+	chunk *chunk
+	obj   *object
+}
+
+func (e *event) field(name string) (*field, any) {
+	if e == nil {
+		return nil, nil
+	}
+	i, ok := e.obj.class.fieldIndex[name]
+	if !ok {
+		return nil, nil
+	}
+	return e.obj.class.fields[i], e.obj.values[i]
+}
+
+func (e *event) has(name string) bool {
+	f, _ := e.field(name)
+	return f != nil
+}
+
+func (e *event) value(name string) any {
+	if e == nil {
+		return nil
+	}
+	_, value := e.field(name)
+	return e.chunk.resolve(value)
+}
+
+// ref returns the constant pool key behind a field, for caching values shared by many events
+func (e *event) ref(name string) (constantRef, bool) {
+	_, value := e.field(name)
+	ref, ok := value.(constantRef)
+	return ref, ok
+}
+
+func (e *event) long(name string) int64 {
+	switch v := e.value(name).(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (e *event) float(name string) float64 {
+	switch v := e.value(name).(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func (e *event) bool(name string) bool {
+	v, _ := e.value(name).(bool)
+	return v
+}
+
+// string reads a string field, unwrapping single-field types such as
+// jdk.types.Symbol, jdk.types.GCName and jdk.types.FrameType
+func (e *event) string(name string) string {
+	if e == nil {
+		return ""
+	}
+	return e.chunk.stringValue(e.value(name))
+}
+
+func (c *chunk) stringValue(value any) string {
+	for i := 0; i < maxResolveDepth; i++ {
+		switch v := c.resolve(value).(type) {
+		case string:
+			return v
+		case *object:
+			if len(v.values) != 1 {
+				return ""
+			}
+			value = v.values[0]
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func (e *event) object(name string) *event {
+	if obj, ok := e.value(name).(*object); ok {
+		return &event{chunk: e.chunk, obj: obj}
+	}
+	return nil
+}
+
+func (e *event) array(name string) []*event {
+	values, _ := e.value(name).([]any)
+	events := make([]*event, 0, len(values))
+	for _, value := range values {
+		if obj, ok := e.chunk.resolve(value).(*object); ok {
+			events = append(events, &event{chunk: e.chunk, obj: obj})
+		}
+	}
+	return events
+}
+
+// time reads a Timestamp field; unannotated fields are ticks, like startTime
+func (e *event) time(name string) time.Time {
+	f, _ := e.field(name)
+	if f == nil {
+		return time.Time{}
+	}
+	value := e.long(name)
+	if f.timestamp == unitMillisecondsSinceEpoch {
+		return time.UnixMilli(value)
+	}
+	return e.chunk.ticksToTime(value)
+}
+
+// duration reads a Timespan field in whatever unit it is annotated with
+func (e *event) duration(name string) time.Duration {
+	f, _ := e.field(name)
+	if f == nil {
+		return 0
+	}
+	value := e.long(name)
+	switch f.timespan {
+	case unitNanoseconds:
+		return time.Duration(value)
+	case unitMicroseconds:
+		return time.Duration(value) * time.Microsecond
+	case unitMilliseconds:
+		return time.Duration(value) * time.Millisecond
+	case unitSeconds:
+		return time.Duration(value) * time.Second
+	default:
+		return e.chunk.ticksToDuration(value)
+	}
+}
+
+// endTime is the event's start time plus its duration
+func (e *event) endTime() time.Time {
+	return e.time("startTime").Add(e.duration("duration"))
+}
@@ -0,0 +1,371 @@
+package jfr
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MaxReportedItems = 10
+	loadBarWidth     = 30
+	shareBarWidth    = 20
+	maxStackFrames   = 8 // Frames printed per allocation stack
+
+	// Safepoints slower than this to reach stall every application thread noticeably
+	slowTimeToSafepoint = 10 * time.Millisecond
+)
+
+func (r *Recording) PrintSummary() {
+	// Header
+	fmt.Printf("🛩️  JFR Recording Analysis\n")
+	fmt.Printf("File: %s  |  Chunks: %d  |  Duration: %s", filepath.Base(r.Filename), r.Chunks,
+		utils.FormatDuration(r.Duration()))
+	if !r.StartTime.IsZero() {
+		fmt.Printf("  |  Started: %s", r.StartTime.Format(time.DateTime))
+	}
+	fmt.Println()
+	if r.JVM.Version != "" {
+		fmt.Printf("JVM: %s\n", r.JVM.Version)
+	}
+	if r.Incomplete {
+		fmt.Println(utils.WarningStyle.Render("⚠️  The last chunk was still being written and was skipped"))
+	}
+	fmt.Println(strings.Repeat("═", 65))
+
+	r.printEvents()
+	r.printCollections()
+	r.printSafepoints()
+	r.printCPU()
+	r.printAllocations()
+}
+
+func (r *Recording) printEvents() {
+	fmt.Println("\n📦 EVENTS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	total := 0
+	names := make([]string, 0, len(r.EventCounts))
+	for name, count := range r.EventCounts {
+		names = append(names, name)
+		total += count
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if r.EventCounts[names[i]] != r.EventCounts[names[j]] {
+			return r.EventCounts[names[i]] > r.EventCounts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Printf("   %s events of %d types\n", utils.FormatCount(int64(total)), len(names))
+	for i, name := range names {
+		if i >= MaxReportedItems {
+			fmt.Printf("   ... and %d more types\n", len(names)-MaxReportedItems)
+			break
+		}
+		fmt.Printf("   %8s  %s\n", utils.FormatCount(int64(r.EventCounts[name])), name)
+	}
+}
+
+func (r *Recording) printCollections() {
+	fmt.Println("\n♻️  GARBAGE COLLECTION")
+	fmt.Println(strings.Repeat("─", 35))
+
+	if len(r.Collections) == 0 {
+		fmt.Println("   No jdk.GarbageCollection events recorded")
+		return
+	}
+
+	if r.GCConfig.YoungCollector != "" {
+		fmt.Printf("   Collectors: %s / %s", r.GCConfig.YoungCollector, r.GCConfig.OldCollector)
+		if r.GCConfig.HeapMax > 0 {
+			fmt.Printf("  |  Max heap: %s", r.GCConfig.HeapMax)
+		}
+		fmt.Println()
+	}
+
+	type collectorStats struct {
+		count    int
+		total    time.Duration
+		longest  time.Duration
+		failures int
+	}
+	stats := make(map[string]*collectorStats)
+	var order []string
+	for _, c := range r.Collections {
+		s, ok := stats[c.Name]
+		if !ok {
+			s = &collectorStats{}
+			stats[c.Name] = s
+			order = append(order, c.Name)
+		}
+		s.count++
+		s.total += c.SumOfPauses
+		s.longest = max(s.longest, c.LongestPause)
+		if c.EvacuationFailed {
+			s.failures++
+		}
+	}
+
+	for _, name := range order {
+		s := stats[name]
+		fmt.Printf("   %-18s %5d collections, %s paused, longest %s", name, s.count,
+			utils.FormatDuration(s.total), utils.FormatDuration(s.longest))
+		if s.failures > 0 {
+			fmt.Print(utils.CriticalStyle.Render(fmt.Sprintf("  %d evacuation failures", s.failures)))
+		}
+		fmt.Println()
+	}
+	fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("   Run 'jdiag gc analyze %s' for the full GC analysis", filepath.Base(r.Filename))))
+}
+
+func (r *Recording) printSafepoints() {
+	if len(r.Safepoints) == 0 {
+		return
+	}
+
+	fmt.Println("\n⏸️  SAFEPOINTS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	var total, longest, longestTTSP time.Duration
+	slow := 0
+	byOperation := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, s := range r.Safepoints {
+		total += s.Duration
+		longest = max(longest, s.Duration)
+		longestTTSP = max(longestTTSP, s.TimeToSafepoint)
+		if s.TimeToSafepoint > slowTimeToSafepoint {
+			slow++
+		}
+
+		operation := s.Operation
+		if operation == "" {
+			operation = "<unknown>"
+		}
+		byOperation[operation] += s.Duration
+		counts[operation]++
+	}
+
+	fmt.Printf("   %d safepoints, %s total, longest %s\n", len(r.Safepoints),
+		utils.FormatDuration(total), utils.FormatDuration(longest))
+	ttsp := "Longest time to safepoint: " + utils.FormatDuration(longestTTSP)
+	if slow > 0 {
+		fmt.Println(utils.WarningStyle.Render(fmt.Sprintf("⚠️  %s (%d over %s)", ttsp, slow, slowTimeToSafepoint)))
+	} else {
+		fmt.Println("   " + ttsp)
+	}
+
+	operations := make([]string, 0, len(byOperation))
+	for operation := range byOperation {
+		operations = append(operations, operation)
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return byOperation[operations[i]] > byOperation[operations[j]]
+	})
+	for i, operation := range operations {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("   %-28s %5d  %s\n", operation, counts[operation], utils.FormatDuration(byOperation[operation]))
+	}
+}
+
+func (r *Recording) printCPU() {
+	if len(r.CPULoad) == 0 && len(r.CPUSamples) == 0 {
+		return
+	}
+
+	fmt.Println("\n🔥 CPU")
+	fmt.Println(strings.Repeat("─", 35))
+
+	if len(r.CPULoad) > 0 {
+		var jvm, machine, peak float64
+		for _, load := range r.CPULoad {
+			jvm += load.JVMUser + load.JVMSystem
+			machine += load.MachineTotal
+			peak = max(peak, load.JVMUser+load.JVMSystem)
+		}
+		jvm /= float64(len(r.CPULoad))
+		machine /= float64(len(r.CPULoad))
+
+		fmt.Printf("   JVM      %s %6s avg, %s peak\n",
+			utils.CreateProgressBar(jvm, loadBarWidth, utils.InfoColor), utils.FormatPercent(jvm*100), utils.FormatPercent(peak*100))
+		fmt.Printf("   Machine  %s %6s avg\n",
+			utils.CreateProgressBar(machine, loadBarWidth, utils.MutedColor), utils.FormatPercent(machine*100))
+	}
+
+	if len(r.CPUSamples) == 0 {
+		return
+	}
+
+	// Hot methods are the innermost frames of the profiler's samples
+	counts := make(map[string]int)
+	for _, sample := range r.CPUSamples {
+		if frame := sample.Stack.TopFrame(); frame != nil {
+			counts[frame.Class+"."+frame.Method]++
+		}
+	}
+	methods := make([]string, 0, len(counts))
+	for method := range counts {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if counts[methods[i]] != counts[methods[j]] {
+			return counts[methods[i]] > counts[methods[j]]
+		}
+		return methods[i] < methods[j]
+	})
+
+	fmt.Printf("\n   Hot methods (%d samples):\n", len(r.CPUSamples))
+	for i, method := range methods {
+		if i >= MaxReportedItems {
+			break
+		}
+		share := float64(counts[method]) / float64(len(r.CPUSamples))
+		fmt.Printf("   %6s  %s\n", utils.FormatPercent(share*100), utils.TruncateString(method, 80))
+	}
+}
+
+func (r *Recording) printAllocations() {
+	if len(r.Allocations) == 0 {
+		return
+	}
+
+	fmt.Println("\n💾 ALLOCATIONS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	profile := r.AllocationProfile()
+	fmt.Printf("   %d samples standing for %s allocated", profile.Samples, profile.Total)
+	if rate := profile.Rate(); rate > 0 {
+		fmt.Printf(" (%s/s)", rate)
+	}
+	fmt.Println()
+
+	for i, class := range profile.Classes {
+		if i >= 3 {
+			break
+		}
+		fmt.Printf("   %6s  %s\n", utils.FormatPercent(profile.Share(class.Weight)*100), class.Name)
+	}
+	fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("   Run 'jdiag jfr allocations %s' for the top allocators", filepath.Base(r.Filename))))
+}
+
+// PrintReport prints the top allocators: classes, application sites, threads and full stacks
+func (p *AllocationProfile) PrintReport(filename string, limit int) {
+	// Header
+	fmt.Printf("💾 Allocation Profile\n")
+	fmt.Printf("File: %s  |  Samples: %d  |  Allocated: %s", filepath.Base(filename), p.Samples, p.Total)
+	if rate := p.Rate(); rate > 0 {
+		fmt.Printf(" (%s/s over %s)", rate, utils.FormatDuration(p.Duration))
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("═", 65))
+
+	if p.Samples == 0 {
+		fmt.Println("\nNo allocation events in the recording. Record with the 'profile' settings")
+		fmt.Println("or enable jdk.ObjectAllocationSample (JDK 16+) to sample allocations.")
+		return
+	}
+
+	p.printClasses(limit)
+	p.printSites("\n🎯 TOP ALLOCATION SITES", limit)
+	p.printThreads(limit)
+	p.printStacks(limit)
+}
+
+// PrintHotspots prints the top allocation sites, for reports that only point at them
+func (p *AllocationProfile) PrintHotspots(limit int) {
+	if p.Samples == 0 {
+		return
+	}
+	p.printSites(fmt.Sprintf("\n🎯 ALLOCATION HOTSPOTS (%s/s sampled by JFR)", p.Rate()), limit)
+}
+
+func (p *AllocationProfile) printClasses(limit int) {
+	fmt.Println("\n🏷️  TOP ALLOCATED CLASSES")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, class := range p.Classes {
+		if i >= limit {
+			fmt.Printf("   ... and %d more classes\n", len(p.Classes)-limit)
+			break
+		}
+		p.printAllocator(i+1, &class.Allocator)
+		if len(class.Sites) > 0 {
+			site := class.Sites[0]
+			fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("        %s from %s", utils.Precision(0).Percent(float64(site.Weight)/float64(class.Weight)*100), site.Name)))
+		}
+	}
+}
+
+func (p *AllocationProfile) printSites(title string, limit int) {
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, site := range p.Sites {
+		if i >= limit {
+			fmt.Printf("   ... and %d more sites\n", len(p.Sites)-limit)
+			break
+		}
+		p.printAllocator(i+1, site)
+	}
+}
+
+func (p *AllocationProfile) printThreads(limit int) {
+	fmt.Println("\n🧵 TOP ALLOCATING THREADS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, thread := range p.Threads {
+		if i >= limit {
+			fmt.Printf("   ... and %d more threads\n", len(p.Threads)-limit)
+			break
+		}
+		p.printAllocator(i+1, thread)
+	}
+}
+
+func (p *AllocationProfile) printStacks(limit int) {
+	fmt.Println("\n📚 TOP ALLOCATION STACKS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, stack := range p.Stacks {
+		if i >= limit {
+			fmt.Printf("   ... and %d more stacks\n", len(p.Stacks)-limit)
+			break
+		}
+		fmt.Printf("%3d. %s  %s (%s, %d samples)\n", i+1, stack.Name, stack.Weight,
+			utils.FormatPercent(p.Share(stack.Weight)*100), stack.Samples)
+
+		if stack.Stack == nil {
+			fmt.Println(utils.MutedStyle.Render("        " + unknownSite))
+			continue
+		}
+		application := stack.Stack.ApplicationFrame()
+		for j, frame := range stack.Stack.Frames {
+			if j >= maxStackFrames {
+				fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("        ... %d more frames", len(stack.Stack.Frames)-maxStackFrames)))
+				break
+			}
+			line := "        at " + frame.String()
+			if frame == application {
+				fmt.Println(utils.InfoStyle.Render(line))
+			} else {
+				fmt.Println(utils.MutedStyle.Render(line))
+			}
+		}
+		if stack.Stack.Truncated {
+			fmt.Println(utils.MutedStyle.Render("        ... truncated by the recording's stack depth"))
+		}
+	}
+}
+
+func (p *AllocationProfile) printAllocator(rank int, a *Allocator) {
+	share := p.Share(a.Weight)
+	bar := utils.CreateProgressBar(share, shareBarWidth, utils.InfoColor)
+	fmt.Printf("%3d. %s %6s %8s  %s\n", rank, bar, utils.FormatPercent(share*100), a.Weight, utils.TruncateString(a.Name, 80))
+}
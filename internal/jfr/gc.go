@@ -0,0 +1,148 @@
+package jfr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Collector names in jdk.GarbageCollection mapped to the GC log's pause types
+var collectorTypes = map[string]string{
+	"G1New":            gc.GCTypeYoung,
+	"ParallelScavenge": gc.GCTypeYoung,
+	"DefNew":           gc.GCTypeYoung,
+	"ParNew":           gc.GCTypeYoung,
+	"G1Full":           gc.GCTypeFull,
+	"SerialOld":        gc.GCTypeFull,
+	"ParallelOld":      gc.GCTypeFull,
+	"G1Old":            gc.GCTypeConcurrent,
+}
+
+// ParseGCEvents reads and analyzes a GC log with parser, or the collections of a JFR recording; recording
+// is nil for logs. Logs come from cache when it has them; a nil cache parses every time
+func ParseGCEvents(filename string, parser *gc.Parser, cache *gc.Cache) ([]*gc.GCEvent, *gc.GCAnalysis, *Recording, error) {
+	if strings.HasSuffix(filename, ".jfr") {
+		recording, err := ParseFile(filename)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(recording.Collections) == 0 {
+			return nil, nil, nil, fmt.Errorf("no jdk.GarbageCollection events in %s", filename)
+		}
+		events, analysis := recording.GCEvents()
+		gc.AnalyzeGCLogs(events, analysis)
+		return events, analysis, recording, nil
+	}
+
+	events, analysis, _, err := cache.ParseAndAnalyze(filename, parser)
+	return events, analysis, nil, err
+}
+
+/*
+ * GCEvents converts the recording's collections into the events the GC log
+ * parser produces, so a recording runs through the same analysis,
+ * recommendations, TUI and HTML report as a log.
+ *
+ * Pauses are stamped at their end, as log lines are. A G1 concurrent cycle
+ * (G1Old) becomes a concurrent mark event stamped at its start; its Duration
+ * is the remark and cleanup pauses inside it, which a log reports as
+ * separate events. Collectors the log parser has no type for (ZGC,
+ * Shenandoah) keep their JFR name.
+ */
+func (r *Recording) GCEvents() ([]*gc.GCEvent, *gc.GCAnalysis) {
+	analysis := &gc.GCAnalysis{
+		JVMVersion:     r.JVM.shortVersion(),
+		HeapRegionSize: r.GCConfig.RegionSize,
+		HeapMax:        r.GCConfig.HeapMax,
+	}
+
+	events := make([]*gc.GCEvent, 0, len(r.Collections))
+	for _, c := range r.Collections {
+		event := &gc.GCEvent{
+			ID:         c.ID,
+			Timestamp:  c.Start.Add(c.Duration),
+			Type:       c.Name,
+			Subtype:    c.G1Type,
+			Cause:      c.Cause,
+			HeapBefore: c.HeapBefore,
+			HeapAfter:  c.HeapAfter,
+			HeapTotal:  c.HeapCommitted,
+			Duration:   c.SumOfPauses,
+			RegionSize: r.GCConfig.RegionSize,
+
+			UserTime:   c.UserTime,
+			SystemTime: c.SystemTime,
+			RealTime:   c.RealTime,
+
+			EdenMemoryBefore:     c.EdenBefore,
+			EdenMemoryAfter:      c.EdenAfter,
+			SurvivorMemoryBefore: c.SurvivorBefore,
+			SurvivorMemoryAfter:  c.SurvivorAfter,
+			OldMemoryBefore:      c.OldBefore,
+			OldMemoryAfter:       c.OldAfter,
+			YoungMemoryBefore:    c.EdenBefore + c.SurvivorBefore,
+			YoungMemoryAfter:     c.EdenAfter + c.SurvivorAfter,
+
+			MetaspaceUsedBefore:      c.MetaspaceBefore.Used,
+			MetaspaceUsedAfter:       c.MetaspaceAfter.Used,
+			MetaspaceCommittedBefore: c.MetaspaceBefore.Committed,
+			MetaspaceCommittedAfter:  c.MetaspaceAfter.Committed,
+			MetaspaceReserved:        c.MetaspaceAfter.Reserved,
+
+			ToSpaceExhausted: c.EvacuationFailed,
+		}
+
+		if eventType, ok := collectorTypes[c.Name]; ok {
+			event.Type = eventType
+		}
+		if event.Type == gc.GCTypeYoung && event.Subtype == gc.GCTypeMixed {
+			event.Type = gc.GCTypeMixed
+		}
+		if event.Type == gc.GCTypeConcurrent {
+			event.Timestamp = c.Start
+			event.Subtype = ""
+			event.ConcurrentDuration = c.Duration
+			event.ConcurrentPhase = c.Name
+			event.ConcurrentCycleId = c.ID
+		}
+
+		// Before JDK 21 G1 tracks only young usage, so old is the rest of the heap, as the log parser approximates it
+		if event.YoungMemoryBefore > 0 && event.OldMemoryBefore == 0 && event.HeapBefore > 0 {
+			event.OldMemoryBefore = max(event.HeapBefore-event.YoungMemoryBefore, 0)
+		}
+		if event.YoungMemoryAfter > 0 && event.OldMemoryAfter == 0 && event.HeapAfter > 0 {
+			event.OldMemoryAfter = max(event.HeapAfter-event.YoungMemoryAfter, 0)
+		}
+
+		events = append(events, event)
+	}
+
+	// Allocations over half a region are humongous, as gc+humongous=debug would list them
+	if r.GCConfig.RegionSize > 0 {
+		for size, count := range r.LargeAllocations {
+			if size <= r.GCConfig.RegionSize/2 {
+				continue
+			}
+			if analysis.HumongousSizes.Sizes == nil {
+				analysis.HumongousSizes.Sizes = make(map[utils.MemorySize]int)
+				analysis.HumongousSizes.Source = gc.HumongousSourceJFR
+			}
+			analysis.HumongousSizes.Sizes[size] += count
+		}
+	}
+
+	return events, analysis
+}
+
+// shortVersion extracts the version the GC log prints, e.g. "21.0.8+9-Ubuntu-0ubuntu124.04.1",
+// from "OpenJDK 64-Bit Server VM (21.0.8+9-Ubuntu-0ubuntu124.04.1) for linux-amd64 JRE ..."
+func (j JVMInfo) shortVersion() string {
+	start := strings.Index(j.Version, "(")
+	end := strings.Index(j.Version, ")")
+	if start == -1 || end < start {
+		return j.Version
+	}
+	return j.Version[start+1 : end]
+}
@@ -0,0 +1,178 @@
+package jfr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Annotations that give a long field a unit
+const (
+	annotationTimespan  = "jdk.jfr.Timespan"
+	annotationTimestamp = "jdk.jfr.Timestamp"
+)
+
+// Units named by Timespan and Timestamp annotations
+const (
+	unitTicks                  = "TICKS"
+	unitNanoseconds            = "NANOSECONDS"
+	unitMicroseconds           = "MICROSECONDS"
+	unitMilliseconds           = "MILLISECONDS"
+	unitSeconds                = "SECONDS"
+	unitMillisecondsSinceEpoch = "MILLISECONDS_SINCE_EPOCH"
+)
+
+// class is a type described by the chunk's metadata: an event, a constant pool type or a primitive
+type class struct {
+	id         int64
+	name       string
+	superType  string
+	simpleType bool // Wraps a single field, e.g. jdk.types.Symbol around a String
+	fields     []*field
+	fieldIndex map[string]int
+}
+
+type field struct {
+	name         string
+	typeID       int64
+	constantPool bool // Value is a key into the constant pool of typeID
+	array        bool
+	timespan     string // Unit if annotated with jdk.jfr.Timespan
+	timestamp    string // Unit if annotated with jdk.jfr.Timestamp
+}
+
+// element is a node of the metadata event's XML-like tree
+type element struct {
+	name       string
+	attributes map[string]string
+	children   []*element
+}
+
+/*
+ * readMetadata decodes the metadata event that describes every type in the chunk:
+ *
+ *   size, type id (0), start time, duration, metadata id
+ *   string table: count, then encoded strings
+ *   root element: name index, attribute count, (key index, value index)..., child count, children...
+ *
+ * The root holds a "metadata" element whose "class" children carry "field" and
+ * "annotation" children. Field units come from Timespan/Timestamp annotations,
+ * which refer to annotation classes by id.
+ */
+func readMetadata(r *reader) (map[int64]*class, error) {
+	r.int()  // Event size
+	r.long() // Type id (0)
+	r.long() // Start time
+	r.long() // Duration
+	r.long() // Metadata id
+
+	count := r.length()
+	strings := make([]string, count)
+	for i := range strings {
+		value, _ := r.string(0).(string)
+		strings[i] = value
+	}
+
+	lookup := func() string {
+		i := r.int()
+		if i < 0 || i >= int64(len(strings)) {
+			r.fail("metadata string index %d out of range", i)
+			return ""
+		}
+		return strings[i]
+	}
+
+	var readElement func(depth int) *element
+	readElement = func(depth int) *element {
+		if depth > 32 {
+			r.fail("metadata nested too deeply")
+			return &element{}
+		}
+		e := &element{name: lookup(), attributes: make(map[string]string)}
+		attributes := r.length()
+		for i := 0; i < attributes && r.err() == nil; i++ {
+			key := lookup()
+			e.attributes[key] = lookup()
+		}
+		children := r.length()
+		for i := 0; i < children && r.err() == nil; i++ {
+			e.children = append(e.children, readElement(depth+1))
+		}
+		return e
+	}
+
+	root := readElement(0)
+	if err := r.err(); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	return buildClasses(root)
+}
+
+func buildClasses(root *element) (map[int64]*class, error) {
+	classes := make(map[int64]*class)
+	var classElements []*element
+
+	for _, child := range root.children {
+		if child.name != "metadata" {
+			continue
+		}
+		for _, e := range child.children {
+			if e.name != "class" {
+				continue
+			}
+			id, err := strconv.ParseInt(e.attributes["id"], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid class id %q in metadata: %w", e.attributes["id"], err)
+			}
+			classes[id] = &class{
+				id:         id,
+				name:       e.attributes["name"],
+				superType:  e.attributes["superType"],
+				simpleType: e.attributes["simpleType"] == "true",
+				fieldIndex: make(map[string]int),
+			}
+			classElements = append(classElements, e)
+		}
+	}
+
+	// Fields refer to annotation classes by id, so resolve them once every class is known
+	for _, e := range classElements {
+		id, _ := strconv.ParseInt(e.attributes["id"], 10, 64)
+		c := classes[id]
+
+		for _, child := range e.children {
+			if child.name != "field" {
+				continue
+			}
+			typeID, err := strconv.ParseInt(child.attributes["class"], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type of field %s.%s: %w", c.name, child.attributes["name"], err)
+			}
+
+			f := &field{
+				name:         child.attributes["name"],
+				typeID:       typeID,
+				constantPool: child.attributes["constantPool"] == "true",
+				array:        child.attributes["dimension"] == "1",
+			}
+			for _, annotation := range child.children {
+				if annotation.name != "annotation" {
+					continue
+				}
+				annotationID, _ := strconv.ParseInt(annotation.attributes["class"], 10, 64)
+				if annotationClass, ok := classes[annotationID]; ok {
+					switch annotationClass.name {
+					case annotationTimespan:
+						f.timespan = annotation.attributes["value"]
+					case annotationTimestamp:
+						f.timestamp = annotation.attributes["value"]
+					}
+				}
+			}
+
+			c.fieldIndex[f.name] = len(c.fields)
+			c.fields = append(c.fields, f)
+		}
+	}
+
+	return classes, nil
+}
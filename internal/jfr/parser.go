@@ -0,0 +1,457 @@
+package jfr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// GC phases named by the "when" field of heap summaries
+const (
+	whenBeforeGC = "Before GC"
+	whenAfterGC  = "After GC"
+)
+
+// LargeAllocationMin is half the smallest G1 region, the least an object can be and be humongous
+const LargeAllocationMin = 512 * utils.KB
+
+/*
+ * ParseFile reads a JDK Flight Recorder file.
+ *
+ * A recording is a sequence of chunks. Each chunk is self-contained: a
+ * 68-byte header pointing at a metadata event that describes every event
+ * and value type, checkpoint events holding constant pools (class names,
+ * stack traces, thread names), and the events themselves, each laid out as
+ * size, type id and then its fields in metadata order.
+ *
+ * jdiag decodes the event types it reports on and skips the rest by size.
+ * A final chunk still being written by a live JVM is ignored and the
+ * recording marked incomplete.
+ */
+func ParseFile(filename string) (*Recording, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat recording: %w", err)
+	}
+
+	recording := &Recording{Filename: filename, EventCounts: make(map[string]int)}
+	builder := newRecordingBuilder(recording)
+
+	headerBytes := make([]byte, chunkHeaderSize)
+	for offset := int64(0); offset < info.Size(); {
+		if _, err := file.ReadAt(headerBytes, offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("unable to read chunk header at offset %d: %w", offset, err)
+		}
+		header, err := parseChunkHeader(headerBytes)
+		if err != nil {
+			if recording.Chunks == 0 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("chunk %d at offset %d: %w", recording.Chunks+1, offset, err)
+		}
+
+		if header.size < chunkHeaderSize || offset+header.size > info.Size() {
+			if recording.Chunks == 0 {
+				return nil, fmt.Errorf("recording is incomplete: chunk of %d bytes in a %d byte file (is it still being written?)",
+					header.size, info.Size())
+			}
+			recording.Incomplete = true
+			break
+		}
+
+		data := make([]byte, header.size)
+		if _, err := file.ReadAt(data, offset); err != nil {
+			return nil, fmt.Errorf("unable to read chunk at offset %d: %w", offset, err)
+		}
+
+		c, err := newChunk(header, data)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d at offset %d: %w", recording.Chunks+1, offset, err)
+		}
+		if err := builder.addChunk(c); err != nil {
+			return nil, fmt.Errorf("chunk %d at offset %d: %w", recording.Chunks+1, offset, err)
+		}
+
+		recording.Chunks++
+		offset += header.size
+	}
+
+	builder.finish()
+	return recording, nil
+}
+
+// recordingBuilder merges the events of every chunk into a Recording
+type recordingBuilder struct {
+	recording *Recording
+	handlers  map[string]func(*event)
+
+	collections     map[int]*Collection
+	safepoints      map[int64]*Safepoint
+	safepointEnds   map[int64]time.Time // Begin and End can fall in different chunks
+	tlabAllocations []*AllocationSample // Used only when the recording has no allocation samples
+
+	// Stack traces are constants, so many events share one; cached per chunk by pool key
+	stacks map[int64]*StackTrace
+}
+
+func newRecordingBuilder(recording *Recording) *recordingBuilder {
+	b := &recordingBuilder{
+		recording:     recording,
+		collections:   make(map[int]*Collection),
+		safepoints:    make(map[int64]*Safepoint),
+		safepointEnds: make(map[int64]time.Time),
+	}
+
+	b.handlers = map[string]func(*event){
+		"jdk.JVMInformation":         b.jvmInformation,
+		"jdk.GCConfiguration":        b.gcConfiguration,
+		"jdk.GCHeapConfiguration":    b.gcHeapConfiguration,
+		"jdk.UnsignedLongFlag":       b.unsignedLongFlag,
+		"jdk.GarbageCollection":      b.garbageCollection,
+		"jdk.G1GarbageCollection":    b.g1GarbageCollection,
+		"jdk.GCHeapSummary":          b.heapSummary,
+		"jdk.G1HeapSummary":          b.g1HeapSummary,
+		"jdk.MetaspaceSummary":       b.metaspaceSummary,
+		"jdk.EvacuationFailed":       b.evacuationFailed,
+		"jdk.PromotionFailed":        b.evacuationFailed,
+		"jdk.GCCPUTime":              b.gcCPUTime,
+		"jdk.ObjectAllocationSample": b.allocationSample,
+		"jdk.ObjectAllocationInNewTLAB": func(e *event) {
+			b.tlabAllocation(e, "tlabSize")
+		},
+		"jdk.ObjectAllocationOutsideTLAB": func(e *event) {
+			b.tlabAllocation(e, "allocationSize")
+			b.largeAllocation(e)
+		},
+		"jdk.SafepointBegin":                b.safepointBegin,
+		"jdk.SafepointStateSynchronization": b.safepointSynchronization,
+		"jdk.SafepointEnd":                  b.safepointEnd,
+		"jdk.ExecuteVMOperation":            b.vmOperation,
+		"jdk.ExecutionSample":               b.executionSample,
+		"jdk.CPULoad":                       b.cpuLoad,
+	}
+	return b
+}
+
+func (b *recordingBuilder) addChunk(c *chunk) error {
+	recording := b.recording
+	if recording.StartTime.IsZero() || c.startTime().Before(recording.StartTime) {
+		recording.StartTime = c.startTime()
+	}
+	if end := c.startTime().Add(c.duration()); end.After(recording.EndTime) {
+		recording.EndTime = end
+	}
+
+	b.stacks = make(map[int64]*StackTrace)
+
+	return c.forEachEvent(func(r *reader, typeID int64) error {
+		if typeID == metadataTypeID || typeID == checkpointTypeID {
+			return nil
+		}
+		class, ok := c.classes[typeID]
+		if !ok {
+			return fmt.Errorf("event of unknown type %d", typeID)
+		}
+		recording.EventCounts[class.name]++
+
+		handler, wanted := b.handlers[class.name]
+		if !wanted {
+			return nil
+		}
+		obj, _ := c.decode(r, class, 0).(*object)
+		if obj != nil && r.err() == nil {
+			handler(&event{chunk: c, obj: obj})
+		}
+		return nil
+	})
+}
+
+func (b *recordingBuilder) finish() {
+	recording := b.recording
+
+	for _, collection := range b.collections {
+		// Heap summaries without their jdk.GarbageCollection come from a GC cut off by the recording's end
+		if collection.Name != "" {
+			recording.Collections = append(recording.Collections, collection)
+		}
+	}
+	sort.Slice(recording.Collections, func(i, j int) bool {
+		return recording.Collections[i].ID < recording.Collections[j].ID
+	})
+
+	for id, safepoint := range b.safepoints {
+		if safepoint.Start.IsZero() {
+			continue
+		}
+		if end, ok := b.safepointEnds[id]; ok {
+			safepoint.Duration = end.Sub(safepoint.Start)
+		}
+		recording.Safepoints = append(recording.Safepoints, safepoint)
+	}
+	sort.Slice(recording.Safepoints, func(i, j int) bool {
+		return recording.Safepoints[i].Start.Before(recording.Safepoints[j].Start)
+	})
+
+	if len(recording.Allocations) == 0 {
+		recording.Allocations = b.tlabAllocations
+	}
+}
+
+func (b *recordingBuilder) collection(e *event) *Collection {
+	id := int(e.long("gcId"))
+	collection, ok := b.collections[id]
+	if !ok {
+		collection = &Collection{ID: id}
+		b.collections[id] = collection
+	}
+	return collection
+}
+
+func (b *recordingBuilder) jvmInformation(e *event) {
+	b.recording.JVM = JVMInfo{
+		Name:          e.string("jvmName"),
+		Version:       e.string("jvmVersion"),
+		Arguments:     e.string("jvmArguments"),
+		JavaArguments: e.string("javaArguments"),
+		StartTime:     e.time("jvmStartTime"),
+		PID:           e.long("pid"),
+	}
+}
+
+func (b *recordingBuilder) gcConfiguration(e *event) {
+	config := &b.recording.GCConfig
+	config.YoungCollector = e.string("youngCollector")
+	config.OldCollector = e.string("oldCollector")
+	config.ParallelThreads = int(e.long("parallelGCThreads"))
+	config.ConcurrentThreads = int(e.long("concurrentGCThreads"))
+	config.PauseTarget = e.duration("pauseTarget")
+}
+
+func (b *recordingBuilder) gcHeapConfiguration(e *event) {
+	config := &b.recording.GCConfig
+	config.HeapMin = utils.MemorySize(e.long("minSize"))
+	config.HeapMax = utils.MemorySize(e.long("maxSize"))
+	config.HeapInitial = utils.MemorySize(e.long("initialSize"))
+}
+
+func (b *recordingBuilder) unsignedLongFlag(e *event) {
+	if e.string("name") == "G1HeapRegionSize" {
+		b.recording.GCConfig.RegionSize = utils.MemorySize(e.long("value"))
+	}
+}
+
+func (b *recordingBuilder) garbageCollection(e *event) {
+	collection := b.collection(e)
+	collection.Name = e.string("name")
+	collection.Cause = e.string("cause")
+	collection.Start = e.time("startTime")
+	collection.Duration = e.duration("duration")
+	collection.SumOfPauses = e.duration("sumOfPauses")
+	collection.LongestPause = e.duration("longestPause")
+}
+
+func (b *recordingBuilder) g1GarbageCollection(e *event) {
+	b.collection(e).G1Type = e.string("type")
+}
+
+func (b *recordingBuilder) heapSummary(e *event) {
+	collection := b.collection(e)
+	used := utils.MemorySize(e.long("heapUsed"))
+
+	switch e.string("when") {
+	case whenBeforeGC:
+		collection.HeapBefore = used
+	case whenAfterGC:
+		collection.HeapAfter = used
+		collection.HeapCommitted = utils.MemorySize(e.object("heapSpace").long("committedSize"))
+	}
+}
+
+func (b *recordingBuilder) g1HeapSummary(e *event) {
+	collection := b.collection(e)
+	eden := utils.MemorySize(e.long("edenUsedSize"))
+	survivor := utils.MemorySize(e.long("survivorUsedSize"))
+	old := utils.MemorySize(e.long("oldGenUsedSize")) // JDK 21+
+
+	switch e.string("when") {
+	case whenBeforeGC:
+		collection.EdenBefore, collection.SurvivorBefore, collection.OldBefore = eden, survivor, old
+	case whenAfterGC:
+		collection.EdenAfter, collection.SurvivorAfter, collection.OldAfter = eden, survivor, old
+	}
+}
+
+func (b *recordingBuilder) metaspaceSummary(e *event) {
+	collection := b.collection(e)
+	metaspace := e.object("metaspace")
+	usage := MetaspaceUsage{
+		Used:      utils.MemorySize(metaspace.long("used")),
+		Committed: utils.MemorySize(metaspace.long("committed")),
+		Reserved:  utils.MemorySize(metaspace.long("reserved")),
+	}
+
+	switch e.string("when") {
+	case whenBeforeGC:
+		collection.MetaspaceBefore = usage
+	case whenAfterGC:
+		collection.MetaspaceAfter = usage
+	}
+}
+
+func (b *recordingBuilder) evacuationFailed(e *event) {
+	b.collection(e).EvacuationFailed = true
+}
+
+func (b *recordingBuilder) gcCPUTime(e *event) {
+	collection := b.collection(e)
+	collection.UserTime = e.duration("userTime")
+	collection.SystemTime = e.duration("systemTime")
+	collection.RealTime = e.duration("realTime")
+}
+
+func (b *recordingBuilder) allocationSample(e *event) {
+	b.recording.Allocations = append(b.recording.Allocations, &AllocationSample{
+		Time:   e.time("startTime"),
+		Thread: threadName(e.object("eventThread")),
+		Class:  className(e.object("objectClass")),
+		Weight: utils.MemorySize(e.long("weight")),
+		Stack:  b.stackTrace(e),
+	})
+}
+
+// tlabAllocation records TLAB events, weighted by the TLAB or object size as JMC does
+func (b *recordingBuilder) tlabAllocation(e *event, weightField string) {
+	b.tlabAllocations = append(b.tlabAllocations, &AllocationSample{
+		Time:   e.time("startTime"),
+		Thread: threadName(e.object("eventThread")),
+		Class:  className(e.object("objectClass")),
+		Weight: utils.MemorySize(e.long(weightField)),
+		Stack:  b.stackTrace(e),
+	})
+}
+
+// largeAllocation counts the objects big enough to be humongous with the smallest G1 regions
+func (b *recordingBuilder) largeAllocation(e *event) {
+	size := utils.MemorySize(e.long("allocationSize"))
+	if size < LargeAllocationMin {
+		return
+	}
+	if b.recording.LargeAllocations == nil {
+		b.recording.LargeAllocations = make(map[utils.MemorySize]int)
+	}
+	b.recording.LargeAllocations[size]++
+}
+
+func (b *recordingBuilder) safepoint(e *event) *Safepoint {
+	id := e.long("safepointId")
+	safepoint, ok := b.safepoints[id]
+	if !ok {
+		safepoint = &Safepoint{ID: id}
+		b.safepoints[id] = safepoint
+	}
+	return safepoint
+}
+
+func (b *recordingBuilder) safepointBegin(e *event) {
+	safepoint := b.safepoint(e)
+	safepoint.Start = e.time("startTime")
+	safepoint.Threads = int(e.long("totalThreadCount"))
+	safepoint.JNICritical = int(e.long("jniCriticalThreadCount"))
+
+	// Begin covers synchronization unless the recording has the dedicated event
+	if safepoint.TimeToSafepoint == 0 {
+		safepoint.TimeToSafepoint = e.duration("duration")
+	}
+	safepoint.Duration = e.duration("duration")
+}
+
+func (b *recordingBuilder) safepointSynchronization(e *event) {
+	b.safepoint(e).TimeToSafepoint = e.duration("duration")
+}
+
+func (b *recordingBuilder) safepointEnd(e *event) {
+	b.safepointEnds[e.long("safepointId")] = e.endTime()
+}
+
+func (b *recordingBuilder) vmOperation(e *event) {
+	if !e.bool("safepoint") || !e.has("safepointId") {
+		return
+	}
+	b.safepoint(e).Operation = e.string("operation")
+}
+
+func (b *recordingBuilder) executionSample(e *event) {
+	b.recording.CPUSamples = append(b.recording.CPUSamples, &ExecutionSample{
+		Time:   e.time("startTime"),
+		Thread: threadName(e.object("sampledThread")),
+		State:  e.string("state"),
+		Stack:  b.stackTrace(e),
+	})
+}
+
+func (b *recordingBuilder) cpuLoad(e *event) {
+	b.recording.CPULoad = append(b.recording.CPULoad, &CPULoad{
+		Time:         e.time("startTime"),
+		JVMUser:      e.float("jvmUser"),
+		JVMSystem:    e.float("jvmSystem"),
+		MachineTotal: e.float("machineTotal"),
+	})
+}
+
+// stackTrace decodes an event's stackTrace field, shared across events with the same trace
+func (b *recordingBuilder) stackTrace(e *event) *StackTrace {
+	ref, isRef := e.ref("stackTrace")
+	if isRef {
+		if stack, ok := b.stacks[ref.key]; ok {
+			return stack
+		}
+	}
+
+	value := e.object("stackTrace")
+	if value == nil {
+		return nil
+	}
+
+	stack := &StackTrace{Truncated: value.bool("truncated")}
+	for _, frame := range value.array("frames") {
+		method := frame.object("method")
+		stack.Frames = append(stack.Frames, &StackFrame{
+			Class:  className(method.object("type")),
+			Method: method.string("name"),
+			Line:   int(frame.long("lineNumber")),
+			Type:   frame.string("type"),
+		})
+	}
+
+	if isRef {
+		b.stacks[ref.key] = stack
+	}
+	return stack
+}
+
+func threadName(thread *event) string {
+	if name := thread.string("javaName"); name != "" {
+		return name
+	}
+	return thread.string("osName")
+}
+
+// className turns a java.lang.Class constant into a source-form name; JFR stores internal names
+func className(class *event) string {
+	name := class.string("name")
+	if name == "" {
+		return "<unknown>"
+	}
+	return analyzer.JavaClassName(name)
+}
@@ -0,0 +1,190 @@
+package jfr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf16"
+)
+
+// String encodings used by JFR
+const (
+	stringNull          = 0
+	stringEmpty         = 1
+	stringConstantPool  = 2 // Key into the java.lang.String constant pool
+	stringUTF8          = 3
+	stringCharArray     = 4 // UTF-16 code units, each a compressed int
+	stringLatin1        = 5
+	maxStringCharacters = 1 << 24
+)
+
+// reader decodes JFR's big-endian values from an in-memory chunk.
+// Errors are sticky: once a read runs past the end every later read returns
+// zero and err() reports the first failure.
+type reader struct {
+	data       []byte
+	pos        int
+	compressed bool // Integers are LEB128-style varints (every JDK 11+ recording)
+	failure    error
+}
+
+func newReader(data []byte, compressed bool) *reader {
+	return &reader{data: data, compressed: compressed}
+}
+
+func (r *reader) err() error {
+	return r.failure
+}
+
+func (r *reader) fail(format string, args ...any) {
+	if r.failure == nil {
+		r.failure = fmt.Errorf(format, args...)
+	}
+}
+
+func (r *reader) remaining() int {
+	return len(r.data) - r.pos
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.failure != nil {
+		return nil
+	}
+	if n < 0 || n > r.remaining() {
+		r.fail("unexpected end of chunk at offset %d reading %d bytes", r.pos, n)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) u1() byte {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) bool() bool {
+	return r.u1() != 0
+}
+
+func (r *reader) rawU2() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) rawU4() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) rawU8() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+/*
+ * varint reads JFR's compressed integer: 7 bits per byte, least significant
+ * group first, high bit set while more bytes follow. The ninth byte, if
+ * reached, contributes all 8 of its bits, so a long never takes more than 9.
+ */
+func (r *reader) varint() int64 {
+	var value uint64
+	for i := 0; i < 8; i++ {
+		b := r.u1()
+		value |= uint64(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int64(value)
+		}
+	}
+	return int64(value | uint64(r.u1())<<56)
+}
+
+func (r *reader) short() int64 {
+	if r.compressed {
+		return int64(int16(r.varint()))
+	}
+	return int64(int16(r.rawU2()))
+}
+
+func (r *reader) char() int64 {
+	if r.compressed {
+		return int64(uint16(r.varint()))
+	}
+	return int64(r.rawU2())
+}
+
+func (r *reader) int() int64 {
+	if r.compressed {
+		return int64(int32(r.varint()))
+	}
+	return int64(int32(r.rawU4()))
+}
+
+func (r *reader) long() int64 {
+	if r.compressed {
+		return r.varint()
+	}
+	return int64(r.rawU8())
+}
+
+func (r *reader) float() float64 {
+	return float64(math.Float32frombits(r.rawU4()))
+}
+
+func (r *reader) double() float64 {
+	return math.Float64frombits(r.rawU8())
+}
+
+// length reads an array or string length and checks it against the bytes left
+func (r *reader) length() int {
+	n := r.int()
+	if n < 0 || n > int64(r.remaining()) {
+		r.fail("invalid length %d at offset %d", n, r.pos)
+		return 0
+	}
+	return int(n)
+}
+
+// string reads an encoded string. Strings stored in the constant pool come
+// back as a constantRef to be resolved once all pools are loaded.
+func (r *reader) string(stringType int64) any {
+	switch encoding := r.u1(); encoding {
+	case stringNull, stringEmpty:
+		return ""
+	case stringConstantPool:
+		return constantRef{typeID: stringType, key: r.long()}
+	case stringUTF8:
+		return string(r.bytes(r.length()))
+	case stringLatin1:
+		raw := r.bytes(r.length())
+		runes := make([]rune, len(raw))
+		for i, b := range raw {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	case stringCharArray:
+		n := r.length()
+		if n > maxStringCharacters {
+			r.fail("string of %d characters at offset %d", n, r.pos)
+			return ""
+		}
+		units := make([]uint16, n)
+		for i := range units {
+			units[i] = uint16(r.char())
+		}
+		return string(utf16.Decode(units))
+	default:
+		r.fail("unknown string encoding %d at offset %d", encoding, r.pos-1)
+		return ""
+	}
+}
@@ -0,0 +1,129 @@
+package jfr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReaderVarint(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int64
+	}{
+		{"one byte", []byte{0x05}, 5},
+		{"two bytes", []byte{0xac, 0x02}, 300},
+		{"padded", []byte{0x85, 0x80, 0x80, 0x00}, 5},
+		{"nine bytes", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReader(tt.data, true)
+			if got := r.long(); got != tt.want || r.err() != nil {
+				t.Errorf("long() = %d, %v; want %d", got, r.err(), tt.want)
+			}
+			if r.remaining() != 0 {
+				t.Errorf("%d bytes left unread", r.remaining())
+			}
+		})
+	}
+}
+
+func TestReaderUncompressed(t *testing.T) {
+	r := newReader([]byte{0xff, 0xfe, 0x00, 0x00, 0x01, 0x00, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0}, false)
+	if got := r.short(); got != -2 {
+		t.Errorf("short() = %d, want -2", got)
+	}
+	if got := r.int(); got != 256 {
+		t.Errorf("int() = %d, want 256", got)
+	}
+	if got := r.double(); got != 1 {
+		t.Errorf("double() = %v, want 1", got)
+	}
+	if r.err() != nil || r.remaining() != 0 {
+		t.Errorf("err() = %v with %d bytes left", r.err(), r.remaining())
+	}
+}
+
+func TestReaderString(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want any
+	}{
+		{"null", []byte{stringNull}, ""},
+		{"empty", []byte{stringEmpty}, ""},
+		{"utf-8", []byte{stringUTF8, 3, 'G', '1', ' '}, "G1 "},
+		{"latin-1", []byte{stringLatin1, 2, 'n', 0xe9}, "né"},
+		{"utf-16 surrogate pair", []byte{stringCharArray, 2, 0xbd, 0xb0, 0x03, 0x80, 0xbc, 0x03}, "😀"},
+		{"constant pool", []byte{stringConstantPool, 42}, constantRef{typeID: 8, key: 42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReader(tt.data, true)
+			if got := r.string(8); got != tt.want || r.err() != nil {
+				t.Errorf("string() = %#v, %v; want %#v", got, r.err(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		read func(r *reader)
+		want string // In the error
+	}{
+		{"truncated varint", []byte{0x80, 0x80}, func(r *reader) { r.long() }, "unexpected end of chunk at offset 2"},
+		{"truncated fixed", []byte{0, 0, 0}, func(r *reader) { r.double() }, "unexpected end of chunk at offset 0 reading 8 bytes"},
+		{"negative length", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, func(r *reader) { r.length() }, "invalid length -1"},
+		{"length past the end", []byte{10, 'a'}, func(r *reader) { r.length() }, "invalid length 10"},
+		{"utf-8 past the end", []byte{stringUTF8, 5, 'a'}, func(r *reader) { r.string(0) }, "invalid length 5"},
+		{"char array past the end", []byte{stringCharArray, 3, 'a'}, func(r *reader) { r.string(0) }, "invalid length 3"},
+		{"unknown string encoding", []byte{9}, func(r *reader) { r.string(0) }, "unknown string encoding 9 at offset 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReader(tt.data, true)
+			tt.read(r)
+			if r.err() == nil || !strings.Contains(r.err().Error(), tt.want) {
+				t.Fatalf("err() = %v, want it to contain %q", r.err(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderStringTooLong(t *testing.T) {
+	// The length check passes: the buffer holds a byte per character
+	n := maxStringCharacters + 1
+	data := append([]byte{stringCharArray, byte(n) | 0x80, byte(n>>7) | 0x80, byte(n>>14) | 0x80, byte(n >> 21)}, make([]byte, n)...)
+
+	r := newReader(data, true)
+	if got := r.string(0); got != "" || r.err() == nil || !strings.Contains(r.err().Error(), "string of 16777217 characters") {
+		t.Errorf("string() = %q, %v; want the length rejected", got, r.err())
+	}
+}
+
+func TestReaderErrorsAreSticky(t *testing.T) {
+	r := newReader([]byte{stringUTF8, 9, 1, 2, 3}, true)
+	r.string(0)
+	first := r.err()
+	if first == nil {
+		t.Fatal("reading a string past the end succeeded")
+	}
+
+	if got := r.u1(); got != 0 {
+		t.Errorf("u1() after a failure = %d, want 0", got)
+	}
+	if got := r.long(); got != 0 {
+		t.Errorf("long() after a failure = %d, want 0", got)
+	}
+	r.fail("a later failure")
+	if r.err() != first {
+		t.Errorf("err() = %v, want the first failure %v", r.err(), first)
+	}
+}
@@ -0,0 +1,160 @@
+package jfr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// Recording is everything jdiag extracts from a .jfr file
+type Recording struct {
+	Filename   string
+	Chunks     int
+	Incomplete bool // The last chunk was still being written and was skipped
+	StartTime  time.Time
+	EndTime    time.Time
+
+	EventCounts map[string]int // Every event in the recording by type, e.g. "jdk.GarbageCollection"
+
+	JVM      JVMInfo
+	GCConfig GCConfig
+
+	Collections []*Collection       // In GC id order
+	Allocations []*AllocationSample // jdk.ObjectAllocationSample, or TLAB events on recordings without it
+	Safepoints  []*Safepoint
+	CPUSamples  []*ExecutionSample
+	CPULoad     []*CPULoad
+
+	// jdk.ObjectAllocationOutsideTLAB by size, from LargeAllocationMin up: the objects G1 could make humongous
+	LargeAllocations map[utils.MemorySize]int
+}
+
+type JVMInfo struct {
+	Name          string // e.g. "OpenJDK 64-Bit Server VM"
+	Version       string // Full version string as printed by the JVM
+	Arguments     string // JVM flags from the command line
+	JavaArguments string // Main class and program arguments
+	StartTime     time.Time
+	PID           int64
+}
+
+type GCConfig struct {
+	YoungCollector    string // e.g. "G1New"
+	OldCollector      string // e.g. "G1Old"
+	ParallelThreads   int
+	ConcurrentThreads int
+	PauseTarget       time.Duration
+	HeapInitial       utils.MemorySize
+	HeapMin           utils.MemorySize
+	HeapMax           utils.MemorySize
+	RegionSize        utils.MemorySize // G1HeapRegionSize flag, G1 only
+}
+
+// Collection is one jdk.GarbageCollection with the heap summaries and details recorded for its GC id
+type Collection struct {
+	ID       int
+	Name     string // Collector, e.g. "G1New", "G1Old" (concurrent cycle), "G1Full", "ParallelScavenge"
+	Cause    string // e.g. "G1 Evacuation Pause", "System.gc()"
+	G1Type   string // jdk.G1GarbageCollection type, e.g. "Normal", "Concurrent Start", "Mixed"
+	Start    time.Time
+	Duration time.Duration // Start to end; for concurrent cycles this includes the time the application ran
+
+	SumOfPauses  time.Duration
+	LongestPause time.Duration
+
+	HeapBefore    utils.MemorySize
+	HeapAfter     utils.MemorySize
+	HeapCommitted utils.MemorySize // Committed heap after the collection
+
+	EdenBefore     utils.MemorySize // G1 only
+	EdenAfter      utils.MemorySize
+	SurvivorBefore utils.MemorySize
+	SurvivorAfter  utils.MemorySize
+	OldBefore      utils.MemorySize
+	OldAfter       utils.MemorySize
+
+	MetaspaceBefore MetaspaceUsage
+	MetaspaceAfter  MetaspaceUsage
+
+	EvacuationFailed bool
+
+	// JDK 20+ jdk.GCCPUTime
+	UserTime   time.Duration
+	SystemTime time.Duration
+	RealTime   time.Duration
+}
+
+type MetaspaceUsage struct {
+	Used      utils.MemorySize
+	Committed utils.MemorySize
+	Reserved  utils.MemorySize
+}
+
+type StackFrame struct {
+	Class  string // Java source form, e.g. "java.util.HashMap"
+	Method string
+	Line   int
+	Type   string // "Interpreted", "JIT compiled", "Inlined", "Native"
+}
+
+type StackTrace struct {
+	Frames    []*StackFrame // Innermost first
+	Truncated bool          // Deeper than the recording's stackdepth setting
+}
+
+// AllocationSample is an allocation sample or TLAB allocation event
+type AllocationSample struct {
+	Time   time.Time
+	Thread string
+	Class  string           // Allocated class in Java source form
+	Weight utils.MemorySize // Bytes of allocation the sample stands for
+	Stack  *StackTrace      // Shared between samples with the same trace; nil if not recorded
+}
+
+// Safepoint pairs jdk.SafepointBegin and jdk.SafepointEnd, named by the VM operation that ran in it
+type Safepoint struct {
+	ID              int64
+	Start           time.Time
+	Duration        time.Duration // From the start of synchronization to the end of the safepoint
+	TimeToSafepoint time.Duration // Waiting for every thread to stop
+	Operation       string        // e.g. "G1CollectForAllocation", "RevokeBias"
+	Threads         int
+	JNICritical     int // Threads in JNI critical regions that delayed it
+}
+
+// ExecutionSample is a jdk.ExecutionSample taken by the method profiler
+type ExecutionSample struct {
+	Time   time.Time
+	Thread string
+	State  string // e.g. "STATE_RUNNABLE"
+	Stack  *StackTrace
+}
+
+// CPULoad is a jdk.CPULoad sample; loads are fractions of the machine's total capacity
+type CPULoad struct {
+	Time         time.Time
+	JVMUser      float64
+	JVMSystem    float64
+	MachineTotal float64
+}
+
+// TopFrame returns the innermost frame, or nil for an empty trace
+func (s *StackTrace) TopFrame() *StackFrame {
+	if s == nil || len(s.Frames) == 0 {
+		return nil
+	}
+	return s.Frames[0]
+}
+
+func (f *StackFrame) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s.%s:%d", f.Class, f.Method, f.Line)
+	}
+	return f.Class + "." + f.Method
+}
+
+// Duration is the wall time covered by the recording
+func (r *Recording) Duration() time.Duration {
+	return r.EndTime.Sub(r.StartTime)
+}
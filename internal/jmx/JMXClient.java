@@ -1,5 +1,8 @@
 import com.sun.tools.attach.*;
 import java.io.*;
+import java.lang.management.ManagementFactory;
+import java.lang.management.ThreadInfo;
+import java.lang.management.ThreadMXBean;
 import java.time.LocalDateTime;
 import java.time.format.DateTimeFormatter;
 import java.util.*;
@@ -7,6 +10,7 @@ import javax.management.*;
 import javax.management.openmbean.CompositeData;
 import javax.management.openmbean.TabularData;
 import javax.management.remote.*;
+import javax.rmi.ssl.SslRMIClientSocketFactory;
 
 public class JMXClient {
     private static PrintWriter logWriter;
@@ -40,6 +44,9 @@ public class JMXClient {
                 ObjectName objectName = new ObjectName(objectNameStr);
                 List<Map<String, Object>> result = queryMbeanPattern(mbsc, objectName, attributes);
                 printJSON(result);
+            } else if (mode.equals("threads")) {
+                List<Map<String, Object>> result = queryThreads(mbsc, objectNameStr);
+                printJSON(result);
             }
         } catch (Exception e) {
             logError("Main execution failed: " + e.getMessage());
@@ -84,7 +91,16 @@ public class JMXClient {
         }
 
         JMXServiceURL serviceURL = new JMXServiceURL(connection);
-        JMXConnector connector = JMXConnectorFactory.connect(serviceURL);
+        Map<String, Object> env = new HashMap<>();
+        String user = System.getenv("JDIAG_JMX_USER");
+        if (user != null && !user.isEmpty()) {
+            String password = System.getenv("JDIAG_JMX_PASSWORD");
+            env.put(JMXConnector.CREDENTIALS, new String[] { user, password == null ? "" : password });
+        }
+        if ("true".equals(System.getenv("JDIAG_JMX_SSL"))) {
+            env.put("com.sun.jndi.rmi.factory.socket", new SslRMIClientSocketFactory());
+        }
+        JMXConnector connector = JMXConnectorFactory.connect(serviceURL, env);
         return connector.getMBeanServerConnection();
     }
 
@@ -182,6 +198,54 @@ public class JMXClient {
         return resMap;
     }
 
+    // Per-thread details need ThreadMXBean operations rather than attributes
+    private static List<Map<String, Object>> queryThreads(
+            MBeanServerConnection mbsc,
+            String objectName) throws Exception {
+        ThreadMXBean threading = ManagementFactory.newPlatformMXBeanProxy(mbsc, objectName, ThreadMXBean.class);
+        long[] ids = threading.getAllThreadIds();
+        ThreadInfo[] infos = threading.getThreadInfo(ids, 1); // The top frame says what native code a thread is in
+
+        // CPU time and allocation are HotSpot extensions, -1 where they are off
+        long[] cpuTimes = null;
+        long[] allocatedBytes = null;
+        try {
+            com.sun.management.ThreadMXBean extended = ManagementFactory.newPlatformMXBeanProxy(
+                    mbsc, objectName, com.sun.management.ThreadMXBean.class);
+            cpuTimes = extended.getThreadCpuTime(ids);
+            allocatedBytes = extended.getThreadAllocatedBytes(ids);
+        } catch (Exception e) {
+            logError("Per-thread CPU and allocation not available: " + e.getMessage());
+        }
+
+        List<Map<String, Object>> threads = new ArrayList<>();
+        for (int i = 0; i < infos.length; i++) {
+            ThreadInfo info = infos[i];
+            if (info == null) {
+                continue; // Ended since getAllThreadIds
+            }
+
+            Map<String, Object> thread = new HashMap<>();
+            thread.put("id", info.getThreadId());
+            thread.put("name", info.getThreadName());
+            thread.put("state", info.getThreadState().name());
+            thread.put("daemon", info.isDaemon());
+            thread.put("blockedCount", info.getBlockedCount());
+            thread.put("blockedTime", info.getBlockedTime());
+            thread.put("lockName", info.getLockName());
+            thread.put("lockOwnerName", info.getLockOwnerName());
+            thread.put("cpuTime", cpuTimes != null ? cpuTimes[i] : -1L);
+            thread.put("allocatedBytes", allocatedBytes != null ? allocatedBytes[i] : -1L);
+            thread.put("inNative", info.isInNative());
+            StackTraceElement[] stack = info.getStackTrace();
+            if (stack.length > 0) {
+                thread.put("topFrame", stack[0].getClassName() + "." + stack[0].getMethodName());
+            }
+            threads.add(thread);
+        }
+        return threads;
+    }
+
     private static Object[] convertToKeyArray(Object keyObj) {
         if (keyObj instanceof Object[] objects) {
             return objects;
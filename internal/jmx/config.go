@@ -11,11 +11,21 @@ type Config struct {
 	Host string // Remote monitoring
 	Port int    // Remote monitoring
 
+	// Remote connection options, from a saved target
+	Name     string // Nickname the target was saved under
+	SSL      bool   // The RMI registry uses SSL; the connector's SSL comes with its stub
+	User     string
+	Password string
+
 	Interval int // ms
 
 	// Debug configuration
 	Debug        bool   // Enable debug mode
 	DebugLogFile string // Path to debug log file
+	CaptureFile  string // Path to the replayable JSONL capture debug mode writes
+
+	// Replay configuration
+	ReplayFile string // Answer queries from a capture instead of a JVM
 }
 
 func (c *Config) GetInterval() time.Duration {
@@ -23,10 +33,18 @@ func (c *Config) GetInterval() time.Duration {
 }
 
 func (c *Config) String() string {
+	if c.ReplayFile != "" {
+		return fmt.Sprintf("Replay of %s", c.ReplayFile)
+	}
+
 	if c.PID != 0 {
 		return fmt.Sprintf("PID %d", c.PID)
 	}
 
+	if c.Name != "" {
+		return fmt.Sprintf("%s (%s:%d)", c.Name, c.Host, c.Port)
+	}
+
 	if c.Host != "" {
 		if c.Port != 0 {
 			return fmt.Sprintf("%s:%d", c.Host, c.Port)
@@ -36,3 +54,25 @@ func (c *Config) String() string {
 
 	return "No target specified"
 }
+
+func (c *Config) newClient() (*JMXClient, error) {
+	if c.PID != 0 {
+		return NewJMXClient(c.PID, "")
+	}
+
+	// Standard JMX service URL format
+	url := fmt.Sprintf("service:jmx:rmi:///jndi/rmi://%s:%d/jmxrmi", c.Host, c.Port)
+	client, err := NewJMXClient(0, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Through the environment, so the password stays out of the process list
+	if c.User != "" {
+		client.env = append(client.env, "JDIAG_JMX_USER="+c.User, "JDIAG_JMX_PASSWORD="+c.Password)
+	}
+	if c.SSL {
+		client.env = append(client.env, "JDIAG_JMX_SSL=true")
+	}
+	return client, nil
+}
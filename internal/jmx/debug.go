@@ -9,8 +9,9 @@ import (
 
 // DebugJMXClient wraps the original JMXClient to add debug logging
 type DebugJMXClient struct {
-	originalClient *JMXClient
+	originalClient JMXClientInterface
 	debugFile      *os.File
+	captureFile    *os.File // Compact JSONL of every query, for --replay-jmx
 	enabled        bool
 }
 
@@ -53,6 +54,28 @@ func (jc *JMXPoller) initDebugLogging() error {
 		return fmt.Errorf("failed to initialize snapshot debug logging: %w", err)
 	}
 
+	// Replaying a capture with debug on shouldn't record it again
+	if jc.config.ReplayFile == "" {
+		if err := jc.initCapture(); err != nil {
+			return fmt.Errorf("failed to initialize JMX capture: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (jc *JMXPoller) initCapture() error {
+	if jc.config.CaptureFile == "" {
+		timestamp := time.Now().Format("20060102_150405")
+		jc.config.CaptureFile = fmt.Sprintf("jmx_capture_%s.jsonl", timestamp)
+	}
+
+	file, err := os.OpenFile(jc.config.CaptureFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+
+	jc.captureFile = file
 	return nil
 }
 
@@ -77,8 +100,8 @@ func (jc *JMXPoller) initSnapshotDebugLogging() error {
 }
 
 // QueryMBean implementation for DebugJMXClient
-func (dc *DebugJMXClient) QueryMBean(objectName string) (map[string]any, error) {
-	result, err := dc.originalClient.QueryMBean(objectName)
+func (dc *DebugJMXClient) QueryMBean(objectName string, attributes ...string) (map[string]any, error) {
+	result, err := dc.originalClient.QueryMBean(objectName, attributes...)
 
 	if dc.enabled && dc.debugFile != nil {
 		dc.logQueryResult(objectName, "QueryMBean", result, err)
@@ -88,8 +111,8 @@ func (dc *DebugJMXClient) QueryMBean(objectName string) (map[string]any, error)
 }
 
 // QueryMBeanPattern implementation for DebugJMXClient
-func (dc *DebugJMXClient) QueryMBeanPattern(pattern string) ([]map[string]any, error) {
-	result, err := dc.originalClient.QueryMBeanPattern(pattern)
+func (dc *DebugJMXClient) QueryMBeanPattern(pattern string, attributes ...string) ([]map[string]any, error) {
+	result, err := dc.originalClient.QueryMBeanPattern(pattern, attributes...)
 
 	if dc.enabled && dc.debugFile != nil {
 		dc.logQueryResult(pattern, "QueryMBeanPattern", result, err)
@@ -98,6 +121,17 @@ func (dc *DebugJMXClient) QueryMBeanPattern(pattern string) ([]map[string]any, e
 	return result, err
 }
 
+// QueryThreads implementation for DebugJMXClient
+func (dc *DebugJMXClient) QueryThreads(objectName string) ([]map[string]any, error) {
+	result, err := dc.originalClient.QueryThreads(objectName)
+
+	if dc.enabled && dc.debugFile != nil {
+		dc.logQueryResult(objectName, "QueryThreads", result, err)
+	}
+
+	return result, err
+}
+
 // TestConnection implementation for DebugJMXClient
 func (dc *DebugJMXClient) TestConnection() error {
 	return dc.originalClient.TestConnection()
@@ -109,6 +143,10 @@ func (dc *DebugJMXClient) Close() error {
 }
 
 func (dc *DebugJMXClient) logQueryResult(objectName, queryType string, data any, err error) {
+	if dc.captureFile != nil {
+		writeCapture(dc.captureFile, queryType, objectName, data, err)
+	}
+
 	entry := DebugLogEntry{
 		Timestamp: time.Now(),
 		MBeanName: objectName,
@@ -199,11 +237,12 @@ func (dc *DebugJMXClient) logAvailableAttributes(objectName string, data interfa
 
 // Discover all available attributes for common MBeans
 func (jc *JMXPoller) discoverAvailableAttributes() {
-	if jc.debugClient == nil {
+	if jc.debugClient == nil || jc.config.ReplayFile != "" {
 		return
 	}
 
-	client := jc.getEffectiveClient()
+	// Past the debug wrapper, so discovery stays out of the capture and replays line up with it
+	client := jc.debugClient.originalClient
 
 	// List of MBeans to discover
 	mbeans := []string{
@@ -5,11 +5,13 @@ import (
 	"strings"
 )
 
+var gcAttributes = []string{"Name", "Valid", "MemoryPoolNames", "CollectionCount", "CollectionTime", "LastGcInfo"}
+
 func (jc *JMXPoller) collectGCMetrics(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
 	// Query all GC collectors
-	gcs, err := client.QueryMBeanPattern("java.lang:type=GarbageCollector,name=*")
+	gcs, err := client.QueryMBeanPattern("java.lang:type=GarbageCollector,name=*", gcAttributes...)
 	if err != nil {
 		return fmt.Errorf("failed to query GC metrics: %w", err)
 	}
@@ -21,6 +21,7 @@ type JMXClient struct {
 	connectionURL string          // JMX service URL
 	tempDir       string          // Temporary directory for generated Java code
 	javaPath      string          // Path to Java executable
+	env           []string        // Connection options for JMXClient.java, added to the environment
 	activeCmd     *exec.Cmd       // Currently running command (if any)
 	cmdMutex      sync.Mutex      // Mutex for active command
 	ctx           context.Context // Context for cancellation
@@ -29,8 +30,9 @@ type JMXClient struct {
 
 // Interface to allow both regular and debug clients to be used interchangeably
 type JMXClientInterface interface {
-	QueryMBean(string) (map[string]any, error)
-	QueryMBeanPattern(string) ([]map[string]any, error)
+	QueryMBean(objectName string, attributes ...string) (map[string]any, error)
+	QueryMBeanPattern(pattern string, attributes ...string) ([]map[string]any, error)
+	QueryThreads(string) ([]map[string]any, error)
 	TestConnection() error
 	Close() error
 }
@@ -135,6 +137,9 @@ func (c *JMXClient) runJMXCommand(args []string) ([]byte, error) {
 	}
 
 	cmd := exec.CommandContext(c.ctx, c.javaPath, args...)
+	if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
 
 	// Track active command
 	c.cmdMutex.Lock()
@@ -185,9 +190,21 @@ func (c *JMXClient) executeJMXQueryPattern(pattern string, attributes []string)
 	return c.runJMXCommand(args)
 }
 
-// QueryMBean queries a specific MBean and returns attributes as JSON
-func (c *JMXClient) QueryMBean(objectName string) (map[string]any, error) {
-	output, err := c.executeJMXQuery(objectName, []string{})
+func (c *JMXClient) executeJMXThreadsQuery(objectName string) ([]byte, error) {
+	args := []string{"-cp", c.tempDir, "JMXClient", "threads", objectName}
+
+	if c.pid != 0 {
+		args = append(args, strconv.Itoa(c.pid))
+	} else {
+		args = append(args, c.connectionURL)
+	}
+
+	return c.runJMXCommand(args)
+}
+
+// QueryMBean queries a specific MBean and returns the attributes named, or all of them when none are
+func (c *JMXClient) QueryMBean(objectName string, attributes ...string) (map[string]any, error) {
+	output, err := c.executeJMXQuery(objectName, attributes)
 	if err != nil {
 		return nil, err
 	}
@@ -200,9 +217,25 @@ func (c *JMXClient) QueryMBean(objectName string) (map[string]any, error) {
 	return result, nil
 }
 
-// QueryMBeanPattern queries multiple MBeans matching a pattern
-func (c *JMXClient) QueryMBeanPattern(pattern string) ([]map[string]any, error) {
-	output, err := c.executeJMXQueryPattern(pattern, []string{})
+// QueryMBeanPattern queries multiple MBeans matching a pattern, for the attributes named or all of them
+func (c *JMXClient) QueryMBeanPattern(pattern string, attributes ...string) ([]map[string]any, error) {
+	output, err := c.executeJMXQueryPattern(pattern, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]any
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JMX response: %w", err)
+	}
+
+	return result, nil
+}
+
+// QueryThreads returns the state, CPU time and allocation of every live thread,
+// through the operations of the Threading MBean at objectName
+func (c *JMXClient) QueryThreads(objectName string) ([]map[string]any, error) {
+	output, err := c.executeJMXThreadsQuery(objectName)
 	if err != nil {
 		return nil, err
 	}
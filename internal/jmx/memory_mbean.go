@@ -1,16 +1,28 @@
 package jmx
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 )
 
+var (
+	memoryAttributes = []string{"HeapMemoryUsage", "NonHeapMemoryUsage", "ObjectPendingFinalizationCount", "Verbose"}
+	poolAttributes   = []string{
+		"Name", "Type", "Valid", "MemoryManagerNames", "Usage", "PeakUsage", "CollectionUsage",
+		"UsageThresholdSupported", "UsageThreshold", "UsageThresholdExceeded", "UsageThresholdCount",
+		"CollectionUsageThresholdSupported", "CollectionUsageThreshold", "CollectionUsageThresholdExceeded", "CollectionUsageThresholdCount",
+	}
+	bufferPoolAttributes = []string{"Name", "Count", "MemoryUsed", "TotalCapacity"}
+)
+
 // ===== MEMORY METRICS =====
 func (jc *JMXPoller) collectMemoryMetrics(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
-	// 1. Basic Memory - Get heap and non-heap totals
-	heapMemory, err := client.QueryMBean("java.lang:type=Memory")
+	// Basic Memory - Get heap and non-heap totals; pools are collected on their own
+	heapMemory, err := client.QueryMBean("java.lang:type=Memory", memoryAttributes...)
 	if err != nil {
 		return fmt.Errorf("failed to query heap memory: %w", err)
 	}
@@ -33,16 +45,6 @@ func (jc *JMXPoller) collectMemoryMetrics(metrics *MBeanSnapshot) error {
 		metrics.Memory.VerboseLogging = verbose
 	}
 
-	// 2. Memory Pools
-	if err := jc.collectMemoryPools(metrics); err != nil {
-		return err
-	}
-
-	// 3. Buffer Pools
-	if err := jc.collectBufferPools(metrics); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -50,11 +52,13 @@ func (jc *JMXPoller) collectMemoryMetrics(metrics *MBeanSnapshot) error {
 func (jc *JMXPoller) collectMemoryPools(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
-	pools, err := client.QueryMBeanPattern("java.lang:type=MemoryPool,name=*")
+	pools, err := client.QueryMBeanPattern("java.lang:type=MemoryPool,name=*", poolAttributes...)
 	if err != nil {
 		return fmt.Errorf("failed to query memory pools: %w", err)
 	}
 
+	metrics.Memory.CodeCache = MemoryUsage{}
+
 	for _, pool := range pools {
 		// poolType, typeOk := pool["Type"].(string)
 		poolName, nameOk := pool["Name"].(string)
@@ -105,7 +109,10 @@ func (jc *JMXPoller) collectMemoryPools(metrics *MBeanSnapshot) error {
 		}
 
 		// Create memory pool structure
+		poolType, _ := pool["Type"].(string)
 		memPool := MemoryPool{
+			Name:                poolName,
+			Heap:                poolType == "HEAP",
 			Usage:               usage,
 			PeakUsage:           peakUsage,
 			Threshold:           threshold,
@@ -115,6 +122,8 @@ func (jc *JMXPoller) collectMemoryPools(metrics *MBeanSnapshot) error {
 			Managers:            managers,
 		}
 
+		metrics.Memory.Pools = append(metrics.Memory.Pools, memPool)
+
 		// Store in appropriate location and aggregate
 		lowerPoolName := strings.ToLower(poolName)
 		switch {
@@ -129,16 +138,39 @@ func (jc *JMXPoller) collectMemoryPools(metrics *MBeanSnapshot) error {
 		case strings.Contains(lowerPoolName, "g1 old"):
 			metrics.Memory.G1OldGen = memPool
 		case strings.Contains(lowerPoolName, "code"):
+			// Segmented code heap (JDK 9+) has three pools; older JVMs one "Code Cache"
+			metrics.Memory.CodeCache.Used += usage.Used
+			metrics.Memory.CodeCache.Committed += usage.Committed
+			metrics.Memory.CodeCache.Max += usage.Max
+			metrics.Memory.CodeCache.Init += usage.Init
 		}
 	}
 
+	slices.SortStableFunc(metrics.Memory.Pools, func(a, b MemoryPool) int {
+		return cmp.Or(cmp.Compare(poolRank(a), poolRank(b)), strings.Compare(a.Name, b.Name))
+	})
+
 	return nil
 }
 
+// poolRank orders pools the way they fill: young, old, then class metadata and code
+func poolRank(pool MemoryPool) int {
+	name := strings.ToLower(pool.Name)
+	for rank, part := range []string{"eden", "survivor", "old", "tenured", "metaspace", "code", "compressed class"} {
+		if strings.Contains(name, part) {
+			return rank
+		}
+	}
+	if pool.Heap {
+		return 3 // Other heap pools, e.g. ZGC's and Shenandoah's single pool
+	}
+	return 7
+}
+
 func (jc *JMXPoller) collectBufferPools(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
-	bufferPools, err := client.QueryMBeanPattern("java.nio:type=BufferPool,name=*")
+	bufferPools, err := client.QueryMBeanPattern("java.nio:type=BufferPool,name=*", bufferPoolAttributes...)
 	if err != nil {
 		return nil // Buffer pools are optional
 	}
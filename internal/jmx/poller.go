@@ -3,7 +3,9 @@ package jmx
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sync"
 	"time"
 )
@@ -11,7 +13,8 @@ import (
 type JMXPoller struct {
 	config            *Config
 	client            *JMXClient
-	debugClient       *DebugJMXClient // Wrapper for debug logging
+	replayClient      *ReplayJMXClient // Answers in place of client with --replay-jmx
+	debugClient       *DebugJMXClient  // Wrapper for debug logging
 	metrics           *MBeanSnapshot
 	mu                sync.RWMutex
 	running           bool
@@ -19,6 +22,9 @@ type JMXPoller struct {
 	errChan           chan error
 	debugFile         *os.File // Raw JMX debug logging
 	snapshotDebugFile *os.File // Parsed snapshot debug logging
+	captureFile       *os.File // Replayable JMX capture
+
+	staticAttributes map[string]map[string]any // By MBean, fetched with the first poll that succeeds
 }
 
 func NewJMXCollector(config *Config) *JMXPoller {
@@ -48,27 +54,27 @@ func (jc *JMXPoller) Start() error {
 		return fmt.Errorf("collector already running")
 	}
 
-	// Create original JMX client
+	// Create original JMX client, or replay a capture instead
+	var original JMXClientInterface
 	var err error
-	if jc.config.PID != 0 {
-		jc.client, err = NewJMXClient(jc.config.PID, "")
+	if jc.config.ReplayFile != "" {
+		if jc.replayClient, err = NewReplayJMXClient(jc.config.ReplayFile); err != nil {
+			return err
+		}
+		original = jc.replayClient
 	} else {
-		// Standard JMX service URL format
-		host := jc.config.Host
-		port := jc.config.Port
-		url := fmt.Sprintf("service:jmx:rmi:///jndi/rmi://%s:%d/jmxrmi", host, port)
-		jc.client, err = NewJMXClient(0, url)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to create JMX client: %w", err)
+		if jc.client, err = jc.config.newClient(); err != nil {
+			return fmt.Errorf("failed to create JMX client: %w", err)
+		}
+		original = jc.client
 	}
 
 	// Create debug wrapper if debug mode is enabled
 	if jc.config.Debug && jc.debugFile != nil {
 		jc.debugClient = &DebugJMXClient{
-			originalClient: jc.client,
+			originalClient: original,
 			debugFile:      jc.debugFile,
+			captureFile:    jc.captureFile,
 			enabled:        true,
 		}
 
@@ -91,6 +97,9 @@ func (jc *JMXPoller) getEffectiveClient() JMXClientInterface {
 	if jc.debugClient != nil {
 		return jc.debugClient
 	}
+	if jc.replayClient != nil {
+		return jc.replayClient
+	}
 	return jc.client
 }
 
@@ -121,6 +130,11 @@ func (jc *JMXPoller) Stop() {
 		jc.snapshotDebugFile.Close()
 		jc.snapshotDebugFile = nil
 	}
+
+	if jc.captureFile != nil {
+		jc.captureFile.Close()
+		jc.captureFile = nil
+	}
 }
 
 // Get the current snapshot
@@ -160,6 +174,7 @@ func (jc *JMXPoller) collectMetrics() {
 		jc.collectGCMetrics,
 		jc.collectThreadingMetrics,
 		jc.collectClassLoadingMetrics,
+		jc.collectCompilationMetrics,
 		jc.collectOperatingSystemMetrics,
 		jc.collectRuntimeMetrics,
 	}
@@ -186,6 +201,49 @@ func (jc *JMXPoller) collectMetrics() {
 	}
 }
 
+/*
+ * A query with no attributes named fetches every attribute of the MBean:
+ * Runtime's alone carries all system properties and class paths, which over
+ * a remote connection makes up most of each poll. Collectors name what they
+ * read instead, and attributes that can't change while the JVM runs are
+ * fetched once, together with the first poll's, then served from memory.
+ * Reconnecting creates a new poller, so a restarted JVM is read afresh.
+ */
+
+// queryMBean fetches the dynamic attributes, adding the static ones fetched by the first query
+func (jc *JMXPoller) queryMBean(objectName string, static, dynamic []string) (map[string]any, error) {
+	cached, exists := jc.staticAttributes[objectName]
+	if exists && len(dynamic) == 0 {
+		return maps.Clone(cached), nil // No attributes would fetch them all
+	}
+
+	attributes := dynamic
+	if !exists {
+		attributes = append(slices.Clone(static), dynamic...)
+	}
+	result, err := jc.getEffectiveClient().QueryMBean(objectName, attributes...)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = make(map[string]any)
+	}
+
+	if exists {
+		maps.Copy(result, cached)
+		return result, nil
+	}
+	cached = make(map[string]any, len(static))
+	for _, name := range static {
+		cached[name] = result[name]
+	}
+	if jc.staticAttributes == nil {
+		jc.staticAttributes = make(map[string]map[string]any)
+	}
+	jc.staticAttributes[objectName] = cached
+	return result, nil
+}
+
 func (jc *JMXPoller) updateMetrics(metrics *MBeanSnapshot) {
 	jc.mu.Lock()
 	defer jc.mu.Unlock()
@@ -196,3 +254,19 @@ func (jc *JMXPoller) TestConnection() error {
 	client := jc.getEffectiveClient()
 	return client.TestConnection()
 }
+
+// QuerySnapshot collects every metric once without starting the polling loop
+func QuerySnapshot(config *Config) (*MBeanSnapshot, error) {
+	client, err := config.newClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JMX client: %w", err)
+	}
+	defer client.Close()
+
+	poller := &JMXPoller{config: config, client: client}
+	poller.collectMetrics()
+	if poller.metrics.Error != nil {
+		return nil, poller.metrics.Error
+	}
+	return poller.metrics, nil
+}
@@ -0,0 +1,162 @@
+package jmx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+ * The debug log is pretty-printed for people to read. Alongside it, debug
+ * mode writes a capture: one compact JSON line per MBean query, holding the
+ * response exactly as the JVM sent it, or its error. Replaying a capture
+ * feeds those responses back through the collectors in place of a JVM, so
+ * collection logic can be run again, and the same way every time, against
+ * the payloads a real JVM produced: missing attributes, odd pool names,
+ * counters that reset.
+ *
+ * The collectors query in the same order every cycle, so each query gets the
+ * recorded responses to the same query and name in turn. Replay is paced by
+ * the poll interval, not by the recorded times, and ends once any query runs
+ * out of responses.
+ */
+
+// CaptureEntry is one line of a capture: a query and what it returned
+type CaptureEntry struct {
+	Time  time.Time       `json:"t"`
+	Query string          `json:"q"`
+	Name  string          `json:"n"`
+	Data  json.RawMessage `json:"d,omitempty"`
+	Error string          `json:"e,omitempty"`
+}
+
+// writeCapture appends a query's result to the capture; a result JSON can't hold is recorded as its error
+func writeCapture(file *os.File, queryType, objectName string, data any, err error) {
+	entry := CaptureEntry{Time: time.Now(), Query: queryType, Name: objectName}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if raw, marshalErr := json.Marshal(data); marshalErr != nil {
+		entry.Error = fmt.Sprintf("failed to marshal response: %v", marshalErr)
+	} else {
+		entry.Data = raw
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+	file.Write(append(line, '\n'))
+}
+
+// ReplayJMXClient answers queries from a capture instead of a JVM
+type ReplayJMXClient struct {
+	file      string
+	mu        sync.Mutex
+	responses map[string][]CaptureEntry // By query and name, in recorded order
+}
+
+// NewReplayJMXClient loads a capture written by debug mode
+func NewReplayJMXClient(file string) (*ReplayJMXClient, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JMX capture: %w", err)
+	}
+	defer f.Close()
+
+	client := &ReplayJMXClient{file: file, responses: make(map[string][]CaptureEntry)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // Thread dumps make long lines
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry CaptureEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid JMX capture %s at line %d: %w", file, lineNum, err)
+		}
+		key := replayKey(entry.Query, entry.Name)
+		client.responses[key] = append(client.responses[key], entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JMX capture: %w", err)
+	}
+	if len(client.responses) == 0 {
+		return nil, fmt.Errorf("JMX capture %s has no queries", file)
+	}
+	return client, nil
+}
+
+func replayKey(queryType, objectName string) string {
+	return queryType + "\x00" + objectName
+}
+
+// next decodes the next recorded response to the query into result
+func (rc *ReplayJMXClient) next(queryType, objectName string, result any) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	key := replayKey(queryType, objectName)
+	queue, exists := rc.responses[key]
+	if !exists {
+		return fmt.Errorf("%s of %s was never recorded in %s", queryType, objectName, rc.file)
+	}
+	if len(queue) == 0 {
+		return fmt.Errorf("replay of %s ended", rc.file)
+	}
+	entry := queue[0]
+	rc.responses[key] = queue[1:]
+
+	if entry.Error != "" {
+		return fmt.Errorf("%s", entry.Error)
+	}
+	if err := json.Unmarshal(entry.Data, result); err != nil {
+		return fmt.Errorf("failed to parse JMX response: %w", err)
+	}
+	return nil
+}
+
+// QueryMBean answers with the recorded response, whichever attributes it holds
+func (rc *ReplayJMXClient) QueryMBean(objectName string, attributes ...string) (map[string]any, error) {
+	var result map[string]any
+	if err := rc.next("QueryMBean", objectName, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (rc *ReplayJMXClient) QueryMBeanPattern(pattern string, attributes ...string) ([]map[string]any, error) {
+	var result []map[string]any
+	if err := rc.next("QueryMBeanPattern", pattern, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (rc *ReplayJMXClient) QueryThreads(objectName string) ([]map[string]any, error) {
+	var result []map[string]any
+	if err := rc.next("QueryThreads", objectName, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TestConnection succeeds while the capture has responses left
+func (rc *ReplayJMXClient) TestConnection() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, queue := range rc.responses {
+		if len(queue) == 0 {
+			return fmt.Errorf("replay of %s ended", rc.file)
+		}
+	}
+	return nil
+}
+
+func (rc *ReplayJMXClient) Close() error {
+	return nil
+}
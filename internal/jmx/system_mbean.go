@@ -5,11 +5,22 @@ import (
 	"time"
 )
 
+var (
+	osStaticAttributes  = []string{"Name", "Version", "Arch", "AvailableProcessors", "TotalPhysicalMemorySize", "TotalSwapSpaceSize", "MaxFileDescriptorCount"}
+	osDynamicAttributes = []string{
+		"SystemCpuLoad", "ProcessCpuLoad", "ProcessCpuTime", "SystemLoadAverage", "FreePhysicalMemorySize",
+		"FreeSwapSpaceSize", "CommittedVirtualMemorySize", "OpenFileDescriptorCount",
+	}
+	runtimeStaticAttributes = []string{
+		"Pid", "Name", "VmName", "VmVendor", "VmVersion", "SpecName", "SpecVendor", "SpecVersion", "StartTime",
+		"InputArguments", "ClassPath", "LibraryPath", "BootClassPath", "BootClassPathSupported", "ManagementSpecVersion", "SystemProperties",
+	}
+	runtimeDynamicAttributes = []string{"Uptime"}
+)
+
 // ===== OPERATING SYSTEM METRICS =====
 func (jc *JMXPoller) collectOperatingSystemMetrics(metrics *MBeanSnapshot) error {
-	client := jc.getEffectiveClient()
-
-	osInfo, err := client.QueryMBean("java.lang:type=OperatingSystem")
+	osInfo, err := jc.queryMBean("java.lang:type=OperatingSystem", osStaticAttributes, osDynamicAttributes)
 	if err != nil {
 		return fmt.Errorf("failed to query OS metrics: %w", err)
 	}
@@ -71,10 +82,25 @@ func (jc *JMXPoller) collectOperatingSystemMetrics(metrics *MBeanSnapshot) error
 }
 
 // ===== RUNTIME METRICS =====
-func (jc *JMXPoller) collectRuntimeMetrics(metrics *MBeanSnapshot) error {
-	client := jc.getEffectiveClient()
 
-	runtime, err := client.QueryMBean("java.lang:type=Runtime")
+// QueryRuntime reads the Runtime MBean once, for commands that need JVM arguments rather than polling
+func QueryRuntime(config *Config) (*Runtime, error) {
+	client, err := config.newClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JMX client: %w", err)
+	}
+	defer client.Close()
+
+	poller := &JMXPoller{config: config, client: client}
+	var snapshot MBeanSnapshot
+	if err := poller.collectRuntimeMetrics(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot.Runtime, nil
+}
+
+func (jc *JMXPoller) collectRuntimeMetrics(metrics *MBeanSnapshot) error {
+	runtime, err := jc.queryMBean("java.lang:type=Runtime", runtimeStaticAttributes, runtimeDynamicAttributes)
 	if err != nil {
 		return fmt.Errorf("failed to query runtime metrics: %w", err)
 	}
@@ -2,11 +2,25 @@ package jmx
 
 import "fmt"
 
+var (
+	threadingStaticAttributes = []string{
+		"ThreadCpuTimeSupported", "ThreadAllocatedMemorySupported", "ThreadContentionMonitoringSupported",
+		"ObjectMonitorUsageSupported", "SynchronizerUsageSupported",
+	}
+	threadingDynamicAttributes = []string{
+		"ThreadCount", "PeakThreadCount", "DaemonThreadCount", "TotalStartedThreadCount", "AllThreadIds",
+		"CurrentThreadCpuTime", "CurrentThreadUserTime", "CurrentThreadAllocatedBytes",
+		"ThreadCpuTimeEnabled", "ThreadAllocatedMemoryEnabled", "ThreadContentionMonitoringEnabled",
+	}
+	classLoadingAttributes      = []string{"LoadedClassCount", "TotalLoadedClassCount", "UnloadedClassCount", "Verbose"}
+	compilationStaticAttributes = []string{"Name", "CompilationTimeMonitoringSupported"}
+)
+
 // ===== THREADING METRICS =====
 func (jc *JMXPoller) collectThreadingMetrics(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
-	threading, err := client.QueryMBean("java.lang:type=Threading")
+	threading, err := jc.queryMBean("java.lang:type=Threading", threadingStaticAttributes, threadingDynamicAttributes)
 	if err != nil {
 		return fmt.Errorf("failed to query thread metrics: %w", err)
 	}
@@ -73,14 +87,64 @@ func (jc *JMXPoller) collectThreadingMetrics(metrics *MBeanSnapshot) error {
 		metrics.Threading.AllThreadIds = threadIdList
 	}
 
+	// Per-thread details are optional, older or restricted JVMs only report counts
+	if threads, err := client.QueryThreads("java.lang:type=Threading"); err == nil {
+		metrics.Threading.Threads = parseThreadInfos(threads)
+	}
+
 	return nil
 }
 
+func parseThreadInfos(threads []map[string]any) []ThreadInfo {
+	infos := make([]ThreadInfo, 0, len(threads))
+	for _, thread := range threads {
+		info := ThreadInfo{CPUTime: -1, AllocatedBytes: -1, BlockedTime: -1}
+		if id, ok := thread["id"].(float64); ok {
+			info.ID = int64(id)
+		}
+		if name, ok := thread["name"].(string); ok {
+			info.Name = name
+		}
+		if state, ok := thread["state"].(string); ok {
+			info.State = state
+		}
+		if daemon, ok := thread["daemon"].(bool); ok {
+			info.Daemon = daemon
+		}
+		if cpuTime, ok := thread["cpuTime"].(float64); ok {
+			info.CPUTime = int64(cpuTime)
+		}
+		if allocated, ok := thread["allocatedBytes"].(float64); ok {
+			info.AllocatedBytes = int64(allocated)
+		}
+		if blockedCount, ok := thread["blockedCount"].(float64); ok {
+			info.BlockedCount = int64(blockedCount)
+		}
+		if blockedTime, ok := thread["blockedTime"].(float64); ok {
+			info.BlockedTime = int64(blockedTime)
+		}
+		if lockName, ok := thread["lockName"].(string); ok {
+			info.LockName = lockName
+		}
+		if lockOwner, ok := thread["lockOwnerName"].(string); ok {
+			info.LockOwnerName = lockOwner
+		}
+		if inNative, ok := thread["inNative"].(bool); ok {
+			info.InNative = inNative
+		}
+		if frame, ok := thread["topFrame"].(string); ok {
+			info.TopFrame = frame
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // ===== CLASS LOADING METRICS =====
 func (jc *JMXPoller) collectClassLoadingMetrics(metrics *MBeanSnapshot) error {
 	client := jc.getEffectiveClient()
 
-	classLoading, err := client.QueryMBean("java.lang:type=ClassLoading")
+	classLoading, err := client.QueryMBean("java.lang:type=ClassLoading", classLoadingAttributes...)
 	if err != nil {
 		return fmt.Errorf("failed to query class loading metrics: %w", err)
 	}
@@ -100,3 +164,24 @@ func (jc *JMXPoller) collectClassLoadingMetrics(metrics *MBeanSnapshot) error {
 
 	return nil
 }
+
+// ===== COMPILATION METRICS =====
+func (jc *JMXPoller) collectCompilationMetrics(metrics *MBeanSnapshot) error {
+	compilation, err := jc.queryMBean("java.lang:type=Compilation", compilationStaticAttributes, []string{"TotalCompilationTime"})
+	if err != nil {
+		return nil // Interpreter-only JVMs don't register it
+	}
+
+	if name, ok := compilation["Name"].(string); ok {
+		metrics.Compilation.Name = name
+	}
+	if supported, ok := compilation["CompilationTimeMonitoringSupported"].(bool); ok {
+		metrics.Compilation.TimeMonitoringSupported = supported
+	}
+	if total, ok := compilation["TotalCompilationTime"].(float64); ok {
+		metrics.Compilation.TotalCompilationTime = int64(total)
+	}
+	metrics.Compilation.Valid = true
+
+	return nil
+}
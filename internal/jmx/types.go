@@ -17,6 +17,8 @@ type ThresholdInfo struct {
 }
 
 type MemoryPool struct {
+	Name                string
+	Heap                bool
 	Usage               MemoryUsage
 	PeakUsage           MemoryUsage
 	Threshold           ThresholdInfo
@@ -45,6 +47,9 @@ type Memory struct {
 	G1Survivor           MemoryPool
 	G1OldGen             MemoryPool
 
+	// Every pool on its own, heap pools first
+	Pools []MemoryPool
+
 	// Buffer pools
 	DirectBuffers BufferPool
 	MappedBuffers BufferPool
@@ -125,6 +130,25 @@ type Threading struct {
 	ContentionMonitoringEnabled   bool
 	ObjectMonitorUsageSupported   bool
 	SynchronizerUsageSupported    bool
+
+	// Per-thread details; nil when the JVM couldn't report them
+	Threads []ThreadInfo
+}
+
+// ThreadInfo is one live thread, as ThreadMXBean reports it
+type ThreadInfo struct {
+	ID             int64
+	Name           string
+	State          string // RUNNABLE, BLOCKED, WAITING, TIMED_WAITING or NEW
+	Daemon         bool
+	CPUTime        int64 // ns, -1 when CPU time measurement is off
+	AllocatedBytes int64 // -1 when allocation measurement is off
+	BlockedCount   int64
+	BlockedTime    int64  // ms, -1 without contention monitoring
+	LockName       string // Lock the thread is blocked on or waiting for
+	LockOwnerName  string // Thread holding that lock
+	InNative       bool   // Running a native method
+	TopFrame       string // Class.method on top of its stack; "" when the stack is empty
 }
 
 type ClassLoading struct {
@@ -134,6 +158,13 @@ type ClassLoading struct {
 	VerboseLogging        bool
 }
 
+type Compilation struct {
+	Name                    string // JIT compiler, e.g. "HotSpot 64-Bit Tiered Compilers"
+	TotalCompilationTime    int64  // ms, summed over compiler threads
+	TimeMonitoringSupported bool
+	Valid                   bool // False when the JVM runs without a JIT
+}
+
 type OperatingSystem struct {
 	// System identification
 	Name    string
@@ -208,6 +239,7 @@ type MBeanSnapshot struct {
 	Memory       Memory
 	Threading    Threading
 	ClassLoading ClassLoading
+	Compilation  Compilation
 	OS           OperatingSystem
 	Runtime      Runtime
 }
@@ -0,0 +1,320 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	// Share of tail latency spent in pauses above which GC is the main cause
+	criticalGCShare = 0.5
+	warningGCShare  = 0.2
+
+	topPauses = 5
+)
+
+// Percentiles reported for the latency distribution
+var percentiles = []float64{50, 90, 95, 99, 99.9}
+
+// Pause is a stop-the-world pause: a GC pause from a log, or a safepoint from a recording
+type Pause struct {
+	Start    time.Time
+	Duration time.Duration
+	Kind     string // e.g. "Young (Normal)", "Full" or a VM operation such as "G1CollectForAllocation"
+}
+
+func (p *Pause) End() time.Time {
+	return p.Start.Add(p.Duration)
+}
+
+type Percentile struct {
+	Label     string // e.g. "p99"
+	Latency   time.Duration
+	WithoutGC time.Duration // Same percentile with pause time removed from every request
+}
+
+// PauseImpact is how many requests, and how much of their latency, one pause accounts for
+type PauseImpact struct {
+	Pause    *Pause
+	Requests int
+	Spikes   int
+	Delay    time.Duration // Pause time summed over the requests it stalled
+}
+
+// Correlation quantifies how much request latency overlaps stop-the-world pauses
+type Correlation struct {
+	Source     string // "GC pauses" or "safepoints"
+	WindowFrom time.Time
+	WindowTo   time.Time
+
+	Requests int // Requests inside the pause source's time range
+	Outside  int // Requests outside it, excluded
+	Pauses   int // Pauses inside the requests' time range
+
+	Percentiles []Percentile
+
+	SpikeThreshold time.Duration
+	Spikes         int
+	SpikesInPause  int           // Spikes overlapping at least one pause
+	SpikeLatency   time.Duration // Total latency of the spikes
+	SpikePauseTime time.Duration // Part of it spent in pauses
+	RequestsPaused int           // Requests of any latency overlapping a pause
+	TotalLatency   time.Duration
+	TotalPauseTime time.Duration
+
+	TopPauses []*PauseImpact
+
+	Severity string // "critical", "warning" or "good"
+	Verdict  string
+}
+
+// TailGCShare is the share of spike latency spent in pauses
+func (c *Correlation) TailGCShare() float64 {
+	if c.SpikeLatency == 0 {
+		return 0
+	}
+	return float64(c.SpikePauseTime) / float64(c.SpikeLatency)
+}
+
+// OverallGCShare is the share of all request latency spent in pauses
+func (c *Correlation) OverallGCShare() float64 {
+	if c.TotalLatency == 0 {
+		return 0
+	}
+	return float64(c.TotalPauseTime) / float64(c.TotalLatency)
+}
+
+// PausesFromGC turns GC events into pauses. Logs stamp a pause when it ends; concurrent phases don't stop the application.
+func PausesFromGC(events []*gc.GCEvent) []*Pause {
+	var pauses []*Pause
+	for _, event := range events {
+		if event.Duration <= 0 || gc.CategorizeGCType(event.Type) == "Concurrent Mark" {
+			continue
+		}
+		kind := event.Type
+		if event.Subtype != "" {
+			kind += " (" + event.Subtype + ")"
+		}
+		pauses = append(pauses, &Pause{
+			Start:    event.Timestamp.Add(-event.Duration),
+			Duration: event.Duration,
+			Kind:     kind,
+		})
+	}
+	return sortPauses(pauses)
+}
+
+// PausesFromSafepoints turns a recording's safepoints into pauses. They include GC pauses and every other VM operation.
+func PausesFromSafepoints(safepoints []*jfr.Safepoint) []*Pause {
+	var pauses []*Pause
+	for _, safepoint := range safepoints {
+		if safepoint.Duration <= 0 {
+			continue
+		}
+		kind := safepoint.Operation
+		if kind == "" {
+			kind = "Safepoint"
+		}
+		pauses = append(pauses, &Pause{Start: safepoint.Start, Duration: safepoint.Duration, Kind: kind})
+	}
+	return sortPauses(pauses)
+}
+
+func sortPauses(pauses []*Pause) []*Pause {
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].Start.Before(pauses[j].Start)
+	})
+	return pauses
+}
+
+/*
+ * Correlate measures how much of each request's latency was spent in a pause.
+ *
+ * A request overlapping a pause was stalled for the overlap: the JVM ran no
+ * Java code then, whatever the request was doing. Removing that overlap from
+ * every request gives the latency percentiles the service would have had
+ * without pauses; the difference at the tail is what GC costs.
+ *
+ * Spikes are requests at or above spikeThreshold (the p99 latency when 0).
+ * Requests outside the time range covered by the pauses (from, to) are
+ * excluded, since nothing is known about pauses there.
+ */
+func Correlate(requests []*Request, pauses []*Pause, source string, from, to time.Time, spikeThreshold time.Duration) (*Correlation, error) {
+	correlation := &Correlation{Source: source, WindowFrom: from, WindowTo: to}
+
+	var inside []*Request
+	for _, request := range requests {
+		if request.End.Before(from) || request.Start.After(to) {
+			correlation.Outside++
+			continue
+		}
+		inside = append(inside, request)
+	}
+	if len(inside) == 0 {
+		first, last := requests[0].Start, requests[len(requests)-1].End
+		return nil, fmt.Errorf("no requests between %s and %s, the range covered by the %s (requests span %s to %s; check the time zones)",
+			from.Format(time.DateTime), to.Format(time.DateTime), source, first.Format(time.DateTime), last.Format(time.DateTime))
+	}
+	correlation.Requests = len(inside)
+
+	latencies := make([]time.Duration, len(inside))
+	for i, request := range inside {
+		latencies[i] = request.Latency
+	}
+	sorted := sortedDurations(latencies)
+
+	correlation.SpikeThreshold = spikeThreshold
+	if spikeThreshold <= 0 {
+		correlation.SpikeThreshold = percentile(sorted, 99)
+	}
+
+	impacts := make(map[*Pause]*PauseImpact)
+	withoutGC := make([]time.Duration, len(inside))
+	windowStart, windowEnd := inside[0].Start, inside[0].End
+
+	for i, request := range inside {
+		windowStart = minTime(windowStart, request.Start)
+		windowEnd = maxTime(windowEnd, request.End)
+		spike := request.Latency >= correlation.SpikeThreshold
+
+		var paused time.Duration
+		for _, pause := range overlapping(pauses, request) {
+			overlap := minTime(pause.End(), request.End).Sub(maxTime(pause.Start, request.Start))
+			paused += overlap
+
+			impact, ok := impacts[pause]
+			if !ok {
+				impact = &PauseImpact{Pause: pause}
+				impacts[pause] = impact
+			}
+			impact.Requests++
+			impact.Delay += overlap
+			if spike {
+				impact.Spikes++
+			}
+		}
+		paused = min(paused, request.Latency)
+		withoutGC[i] = request.Latency - paused
+
+		correlation.TotalLatency += request.Latency
+		correlation.TotalPauseTime += paused
+		if paused > 0 {
+			correlation.RequestsPaused++
+		}
+		if spike {
+			correlation.Spikes++
+			correlation.SpikeLatency += request.Latency
+			correlation.SpikePauseTime += paused
+			if paused > 0 {
+				correlation.SpikesInPause++
+			}
+		}
+	}
+
+	for _, pause := range pauses {
+		if pause.End().After(windowStart) && pause.Start.Before(windowEnd) {
+			correlation.Pauses++
+		}
+	}
+
+	sortedWithoutGC := sortedDurations(withoutGC)
+	for _, p := range percentiles {
+		correlation.Percentiles = append(correlation.Percentiles, Percentile{
+			Label:     "p" + strings.TrimSuffix(fmt.Sprintf("%.1f", p), ".0"),
+			Latency:   percentile(sorted, p),
+			WithoutGC: percentile(sortedWithoutGC, p),
+		})
+	}
+	correlation.Percentiles = append(correlation.Percentiles, Percentile{
+		Label:     "max",
+		Latency:   sorted[len(sorted)-1],
+		WithoutGC: sortedWithoutGC[len(sortedWithoutGC)-1],
+	})
+
+	for _, impact := range impacts {
+		correlation.TopPauses = append(correlation.TopPauses, impact)
+	}
+	sort.Slice(correlation.TopPauses, func(i, j int) bool {
+		a, b := correlation.TopPauses[i], correlation.TopPauses[j]
+		if a.Delay != b.Delay {
+			return a.Delay > b.Delay
+		}
+		return a.Pause.Start.Before(b.Pause.Start)
+	})
+	if len(correlation.TopPauses) > topPauses {
+		correlation.TopPauses = correlation.TopPauses[:topPauses]
+	}
+
+	correlation.assess()
+	return correlation, nil
+}
+
+func (c *Correlation) assess() {
+	share := c.TailGCShare()
+	switch {
+	case share >= criticalGCShare:
+		c.Severity = "critical"
+		c.Verdict = fmt.Sprintf("%s cause most of the tail latency: %s of the time spent in spikes was paused", describeSource(c.Source), utils.Precision(0).Percent(share*100))
+	case share >= warningGCShare:
+		c.Severity = "warning"
+		c.Verdict = fmt.Sprintf("%s contribute noticeably to tail latency: %s of the time spent in spikes was paused", describeSource(c.Source), utils.Precision(0).Percent(share*100))
+	default:
+		c.Severity = "good"
+		c.Verdict = fmt.Sprintf("Tail latency is mostly not GC: only %s of the time spent in spikes was paused; look at downstream calls, locks and CPU", utils.Precision(0).Percent(share*100))
+	}
+}
+
+func describeSource(source string) string {
+	if source == "" {
+		return "Pauses"
+	}
+	return strings.ToUpper(source[:1]) + source[1:]
+}
+
+// overlapping returns the pauses that intersect a request; pauses are sorted and don't overlap each other
+func overlapping(pauses []*Pause, request *Request) []*Pause {
+	end := sort.Search(len(pauses), func(i int) bool {
+		return !pauses[i].Start.Before(request.End)
+	})
+	start := end
+	for start > 0 && pauses[start-1].End().After(request.Start) {
+		start--
+	}
+	return pauses[start:end]
+}
+
+func sortedDurations(durations []time.Duration) []time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentile uses the nearest-rank method on sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+package latency
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+func (c *Correlation) PrintSummary() {
+	fmt.Println()
+	fmt.Printf("⏱️  REQUEST LATENCY × %s CORRELATION\n", strings.ToUpper(c.Source))
+	fmt.Println(strings.Repeat("─", 80))
+
+	fmt.Printf("   Requests:   %d", c.Requests)
+	if c.Outside > 0 {
+		fmt.Printf(" (%d outside %s to %s excluded)", c.Outside,
+			c.WindowFrom.Format(time.DateTime), c.WindowTo.Format(time.DateTime))
+	}
+	fmt.Println()
+	fmt.Printf("   Pauses:     %d during the requests' time range\n", c.Pauses)
+	fmt.Printf("   Paused:     %d requests (%s) overlapped a pause; %s of all latency was paused\n",
+		c.RequestsPaused, utils.FormatPercent(float64(c.RequestsPaused)/float64(c.Requests)*100), utils.FormatPercent(c.OverallGCShare()*100))
+
+	fmt.Println()
+	fmt.Printf("%8s  %12s  %12s  %s\n", "", "Latency", "Without GC", "Attributable to GC")
+	fmt.Println(strings.Repeat("─", 80))
+	for _, p := range c.Percentiles {
+		saved := p.Latency - p.WithoutGC
+		share := 0.0
+		if p.Latency > 0 {
+			share = float64(saved) / float64(p.Latency)
+		}
+		line := fmt.Sprintf("%8s  %12s  %12s  %s (%s)", p.Label, utils.FormatDuration(p.Latency),
+			utils.FormatDuration(p.WithoutGC), utils.FormatDuration(saved), utils.Precision(0).Percent(share*100))
+		fmt.Println(utils.GetSeverityStyle(shareSeverity(share)).Render(line))
+	}
+
+	fmt.Println()
+	fmt.Printf("🔺 Spikes (≥ %s): %d requests, %d (%s) overlapped a pause\n", utils.FormatDuration(c.SpikeThreshold),
+		c.Spikes, c.SpikesInPause, utils.Precision(0).Percent(float64(c.SpikesInPause)/float64(max(c.Spikes, 1))*100))
+	fmt.Printf("   Time in spikes: %s, of which %s paused (%s) and %s other causes\n",
+		utils.FormatDuration(c.SpikeLatency), utils.FormatDuration(c.SpikePauseTime), utils.Precision(0).Percent(c.TailGCShare()*100),
+		utils.FormatDuration(c.SpikeLatency-c.SpikePauseTime))
+
+	if len(c.TopPauses) > 0 {
+		fmt.Println()
+		fmt.Printf("%4s  %-19s  %10s  %-28s  %8s  %6s  %s\n", "#", "Start", "Pause", "Kind", "Requests", "Spikes", "Delay")
+		fmt.Println(strings.Repeat("─", 80))
+		for i, impact := range c.TopPauses {
+			pause := impact.Pause
+			fmt.Printf("%4d  %-19s  %10s  %-28s  %8d  %6d  %s\n", i+1, pause.Start.Format("15:04:05.000"),
+				utils.FormatDuration(pause.Duration), utils.TruncateString(pause.Kind, 28), impact.Requests,
+				impact.Spikes, utils.FormatDuration(impact.Delay))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(utils.GetSeverityStyle(c.Severity).Render("💡 " + c.Verdict))
+}
+
+func shareSeverity(share float64) string {
+	switch {
+	case share >= criticalGCShare:
+		return "critical"
+	case share >= warningGCShare:
+		return "warning"
+	default:
+		return "good"
+	}
+}
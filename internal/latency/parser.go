@@ -0,0 +1,133 @@
+package latency
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Request is one line of a latency file
+type Request struct {
+	Start   time.Time
+	End     time.Time
+	Latency time.Duration
+}
+
+// Timestamp layouts accepted in latency files, tried in order; layouts without a zone are local time
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700", // GC log style
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"02/Jan/2006:15:04:05 -0700", // Access log %t
+}
+
+// Epoch values above this are milliseconds rather than seconds
+const epochMillisThreshold = 1e11
+
+/*
+ * ParseFile reads a latency CSV of "timestamp,latency" lines.
+ *
+ * Timestamps are ISO 8601 / RFC 3339, access log style, or epoch seconds or
+ * milliseconds. Latencies are milliseconds, or Go durations with a unit
+ * ("250ms", "1.2s"). A header line, blank lines, "#" comments and extra
+ * columns are ignored.
+ *
+ * Access logs usually stamp a request when it completes; atStart treats
+ * timestamps as the time the request arrived instead.
+ */
+func ParseFile(filename string, atStart bool) ([]*Request, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open latency file: %w", err)
+	}
+	defer file.Close()
+
+	return Parse(file, atStart)
+}
+
+func Parse(reader io.Reader, atStart bool) ([]*Request, error) {
+	csvReader := csv.NewReader(bufio.NewReader(reader))
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	csvReader.Comment = '#'
+
+	var requests []*Request
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency file: %w", err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected timestamp,latency", line)
+		}
+
+		timestamp, err := parseTimestamp(strings.TrimSpace(record[0]))
+		if err != nil {
+			if len(requests) == 0 && line == 1 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		latency, err := parseLatency(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		request := &Request{Start: timestamp.Add(-latency), End: timestamp, Latency: latency}
+		if atStart {
+			request.Start, request.End = timestamp, timestamp.Add(latency)
+		}
+		requests = append(requests, request)
+	}
+
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no requests found in latency file")
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Start.Before(requests[j].Start)
+	})
+	return requests, nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if epoch, err := strconv.ParseFloat(value, 64); err == nil {
+		if epoch > epochMillisThreshold {
+			return time.UnixMilli(int64(epoch)), nil
+		}
+		return time.Unix(0, int64(epoch*float64(time.Second))), nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if timestamp, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
+
+func parseLatency(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		if ms < 0 {
+			return 0, fmt.Errorf("negative latency %q", value)
+		}
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+
+	latency, err := time.ParseDuration(value)
+	if err != nil || latency < 0 {
+		return 0, fmt.Errorf("invalid latency %q (milliseconds or a duration such as 250ms)", value)
+	}
+	return latency, nil
+}
@@ -0,0 +1,341 @@
+package native
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	// glibc reserves each secondary malloc arena as a HEAP_MAX_SIZE block, aligned to its size
+	arenaSize = 64 * utils.MB
+
+	// Java thread stacks are a small guard zone followed by the usable stack
+	maxStackGuard = 64 * utils.KB
+	minStackSize  = 128 * utils.KB
+	maxStackSize  = 16 * utils.MB
+
+	// Below this an anonymous reservation is too small to be the Java heap
+	minHeapReservation = 16 * utils.MB
+
+	// Default CompressedClassSpaceSize
+	classSpaceReservation = 1 * utils.GB
+
+	// Thresholds for findings
+	arenaWarningShare       = 0.25 // Of total RSS
+	arenaWarningRss         = 256 * utils.MB
+	unexplainedWarningShare = 0.25
+	unexplainedWarningRss   = 256 * utils.MB
+	manyThreads             = 1000
+)
+
+/*
+ * Analyze classifies every mapping, then reconciles the result with the
+ * JVM's own accounting when jvm is not nil.
+ *
+ * Without Native Memory Tracking the JVM's mappings are only told apart by
+ * shape:
+ *
+ *   malloc arenas   64 MB-aligned rw-p + ---p pairs totalling exactly 64 MB
+ *   thread stacks   a small ---p guard directly below a rw-p stack
+ *   code cache      anonymous executable memory
+ *   Java heap       the largest run of adjacent anonymous mappings (or the
+ *                   run matching the JMX maximum heap)
+ *   class space     the run right after the CDS archive, or a 1 GB run
+ *
+ * What is left is malloc'd memory above the mmap threshold, metaspace,
+ * GC data structures, direct buffers and anything JNI code allocates.
+ */
+func (a *Analysis) Analyze(jvm *JVMMemory) {
+	a.JVM = jvm
+	if jvm != nil && a.Threads == 0 {
+		a.Threads = jvm.Threads
+	}
+
+	a.classify()
+
+	a.Usage = make(map[string]*Usage)
+	for _, mapping := range a.Mappings {
+		usage := a.Usage[mapping.Category]
+		if usage == nil {
+			usage = &Usage{Category: mapping.Category}
+			a.Usage[mapping.Category] = usage
+		}
+		usage.Mappings++
+		usage.Size += mapping.Size
+		usage.Rss += mapping.Rss
+		usage.PrivateDirty += mapping.PrivateDirty
+		usage.Swap += mapping.Swap
+
+		a.TotalSize += mapping.Size
+		a.TotalRss += mapping.Rss
+		a.TotalSwap += mapping.Swap
+	}
+	for i, mapping := range a.Mappings {
+		if a.startsRegion(i) {
+			a.Usage[mapping.Category].Regions++
+		}
+	}
+
+	if jvm != nil {
+		a.reconcile()
+	}
+	a.findIssues()
+}
+
+func (a *Analysis) classify() {
+	mappings := a.Mappings
+
+	for _, mapping := range mappings {
+		mapping.Category = classifyPath(mapping)
+	}
+
+	for i := 0; i < len(mappings); i++ {
+		mapping := mappings[i]
+		if mapping.Category != CategoryOtherAnon {
+			continue
+		}
+
+		var next *Mapping
+		if i+1 < len(mappings) && mappings[i+1].Category == CategoryOtherAnon && mappings[i+1].Start == mapping.End {
+			next = mappings[i+1]
+		}
+
+		switch {
+		case isArena(mapping, next):
+			mapping.Category = CategoryMallocArenas
+			if next != nil && mapping.Size < arenaSize {
+				next.Category = CategoryMallocArenas
+				i++
+			}
+		case isThreadStack(mapping, next):
+			mapping.Category = CategoryThreadStacks
+			next.Category = CategoryThreadStacks
+			i++
+		case mapping.Executable():
+			mapping.Category = CategoryCodeCache
+			if next != nil && next.Reserved() {
+				// The uncommitted rest of ReservedCodeCacheSize
+				next.Category = CategoryCodeCache
+				i++
+			}
+		}
+	}
+
+	a.classifyReservations()
+}
+
+func classifyPath(mapping *Mapping) string {
+	path := mapping.Path
+	switch {
+	case path == "":
+		return CategoryOtherAnon
+	case path == "[heap]":
+		return CategoryMallocHeap
+	case path == "[stack]":
+		return CategoryMainStack
+	case path == "[vdso]" || path == "[vvar]" || path == "[vsyscall]" || path == "[vvar_vclock]":
+		return CategoryKernel
+	case strings.HasPrefix(path, "[anon:") || strings.HasPrefix(path, "[anon_shmem:"):
+		return CategoryOtherAnon // Named anonymous memory (prctl PR_SET_VMA_ANON_NAME)
+	}
+
+	base := filepath.Base(strings.TrimSuffix(path, " (deleted)"))
+	switch {
+	case strings.HasSuffix(base, ".jsa"):
+		return CategoryCDS
+	case strings.HasSuffix(base, ".jar") || base == "modules":
+		return CategoryJars
+	case strings.HasSuffix(base, ".so") || strings.Contains(base, ".so."):
+		return CategoryLibraries
+	default:
+		return CategoryFiles
+	}
+}
+
+func isArena(mapping, next *Mapping) bool {
+	if mapping.Permissions != "rw-p" || mapping.Start%uint64(arenaSize) != 0 {
+		return false
+	}
+	if mapping.Size == arenaSize {
+		return true
+	}
+	return next != nil && next.Reserved() && mapping.Size+next.Size == arenaSize
+}
+
+func isThreadStack(guard, stack *Mapping) bool {
+	if stack == nil || !guard.Reserved() || guard.Size > maxStackGuard || stack.Permissions != "rw-p" {
+		return false
+	}
+	total := guard.Size + stack.Size
+	return total >= minStackSize && total <= maxStackSize
+}
+
+// startsRegion reports whether a mapping begins a new stack, arena or run of adjacent mappings
+func (a *Analysis) startsRegion(i int) bool {
+	mapping := a.Mappings[i]
+	switch mapping.Category {
+	case CategoryThreadStacks:
+		return mapping.Reserved() // Each stack has one guard zone below it
+	case CategoryMallocArenas:
+		return !mapping.Reserved()
+	}
+	if i == 0 {
+		return true
+	}
+	previous := a.Mappings[i-1]
+	return previous.Category != mapping.Category || previous.End != mapping.Start
+}
+
+// classifyReservations finds the heap and class space among runs of adjacent unclassified anonymous mappings
+func (a *Analysis) classifyReservations() {
+	type run struct {
+		first, last int
+		size        utils.MemorySize
+	}
+
+	var runs []run
+	for i, mapping := range a.Mappings {
+		if mapping.Category != CategoryOtherAnon {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].last == i-1 && a.Mappings[i-1].End == mapping.Start {
+			runs[n-1].last = i
+			runs[n-1].size += mapping.Size
+			continue
+		}
+		runs = append(runs, run{first: i, last: i, size: mapping.Size})
+	}
+
+	mark := func(r run, category string) {
+		for i := r.first; i <= r.last; i++ {
+			a.Mappings[i].Category = category
+		}
+	}
+
+	afterCDS := make([]bool, len(runs))
+	for i, r := range runs {
+		previous := a.Mappings[max(r.first-1, 0)]
+		afterCDS[i] = r.first > 0 && previous.Category == CategoryCDS && previous.End == a.Mappings[r.first].Start
+	}
+
+	heap := -1
+	for i, r := range runs {
+		if afterCDS[i] || r.size < minHeapReservation {
+			continue
+		}
+		if heap < 0 {
+			heap = i
+			continue
+		}
+		if a.JVM != nil && a.JVM.HeapMax > 0 {
+			// Prefer the reservation the size of the maximum heap
+			matches, heapMatches := matchesSize(r.size, a.JVM.HeapMax), matchesSize(runs[heap].size, a.JVM.HeapMax)
+			if matches != heapMatches {
+				if matches {
+					heap = i
+				}
+				continue
+			}
+		}
+		if r.size > runs[heap].size {
+			heap = i
+		}
+	}
+
+	for i, r := range runs {
+		switch {
+		case i == heap:
+			mark(r, CategoryHeap)
+		case afterCDS[i] || r.size == classSpaceReservation:
+			mark(r, CategoryClassSpace)
+		}
+	}
+}
+
+// matchesSize allows for the alignment the JVM rounds reservations up to
+func matchesSize(size, target utils.MemorySize) bool {
+	return size >= target && size <= target+target/50+32*utils.MB
+}
+
+func (a *Analysis) rss(categories ...string) utils.MemorySize {
+	var total utils.MemorySize
+	for _, category := range categories {
+		if usage := a.Usage[category]; usage != nil {
+			total += usage.Rss
+		}
+	}
+	return total
+}
+
+// reconcile compares resident anonymous memory with what the JVM accounts for
+func (a *Analysis) reconcile() {
+	jvm := a.JVM
+
+	anonymous := a.rss(CategoryHeap, CategoryClassSpace, CategoryCodeCache, CategoryThreadStacks, CategoryMainStack,
+		CategoryMallocArenas, CategoryMallocHeap, CategoryOtherAnon)
+
+	// Committed memory is an upper bound of what is resident; stacks are counted as mapped
+	accounted := jvm.HeapCommitted + jvm.NonHeapCommitted + jvm.DirectBuffers + a.rss(CategoryThreadStacks, CategoryMainStack)
+	a.Unexplained = max(anonymous-accounted, 0)
+}
+
+func (a *Analysis) addFinding(severity, description, recommendation string) {
+	a.Findings = append(a.Findings, Finding{Severity: severity, Description: description, Recommendation: recommendation})
+}
+
+func (a *Analysis) findIssues() {
+	if arenas := a.Usage[CategoryMallocArenas]; arenas != nil {
+		share := float64(arenas.Rss) / float64(max(a.TotalRss, 1))
+		severity := "info"
+		if share >= arenaWarningShare || arenas.Rss >= arenaWarningRss {
+			severity = "warning"
+		}
+		description := fmt.Sprintf("%d glibc malloc arenas hold %s resident (%s of RSS)", arenas.Regions, arenas.Rss, utils.Precision(0).Percent(share*100))
+		if a.JVM != nil && a.JVM.Processors > 0 {
+			description += fmt.Sprintf("; glibc allows up to %d on %d CPUs", 8*a.JVM.Processors, a.JVM.Processors)
+		}
+		a.addFinding(severity, description,
+			"Cap arenas with MALLOC_ARENA_MAX=2 (or 4), or preload jemalloc/tcmalloc, and compare RSS after warm-up")
+	}
+
+	if a.JVM != nil {
+		share := float64(a.Unexplained) / float64(max(a.TotalRss, 1))
+		if share >= unexplainedWarningShare || a.Unexplained >= unexplainedWarningRss {
+			a.addFinding("warning", fmt.Sprintf("%s of resident anonymous memory (%s of RSS) isn't heap, JMX non-heap, direct buffers or stacks",
+				a.Unexplained, utils.Precision(0).Percent(share*100)),
+				"Run with -XX:NativeMemoryTracking=summary and compare jcmd <pid> VM.native_memory summary against this report; "+
+					"what NMT doesn't see either is allocated by native libraries (JNI, compression, TLS)")
+		}
+
+		if heap := a.Usage[CategoryHeap]; heap != nil && a.JVM.HeapCommitted > 0 && heap.Rss > a.JVM.HeapCommitted+a.JVM.HeapCommitted/10 {
+			a.addFinding("info", fmt.Sprintf("Java heap mappings are %s resident but JMX reports %s committed; the heap may be misidentified",
+				heap.Rss, a.JVM.HeapCommitted), "Check the top mappings for the Java heap's address range")
+		}
+	}
+
+	if a.Threads >= manyThreads {
+		a.addFinding("warning", fmt.Sprintf("%d threads; their stacks alone hold %s resident", a.Threads, a.rss(CategoryThreadStacks)),
+			"Look for unbounded thread pools with jdiag thread, or lower -Xss if deep stacks aren't needed")
+	}
+
+	if a.TotalSwap > 0 {
+		a.addFinding("warning", fmt.Sprintf("%s of the process is swapped out; GC touching swapped heap pages stalls badly", a.TotalSwap),
+			"Reduce the heap or other processes' memory, or lock memory, so the JVM stays resident")
+	}
+
+	if huge := a.hugePages(); huge > 0 {
+		a.addFinding("info", fmt.Sprintf("%s is backed by transparent huge pages", huge),
+			"THP can inflate RSS in sparsely used mappings; -XX:+UseTransparentHugePages limits it to the Java heap")
+	}
+}
+
+func (a *Analysis) hugePages() utils.MemorySize {
+	var total utils.MemorySize
+	for _, mapping := range a.Mappings {
+		total += mapping.AnonHugePages
+	}
+	return total
+}
@@ -0,0 +1,120 @@
+package native
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MaxReportedMappings = 10
+	shareBarWidth       = 20
+)
+
+func (a *Analysis) PrintReport(limit int) {
+	fmt.Println()
+	fmt.Printf("🧠 NATIVE MEMORY MAP (%s)\n", a.Format)
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("   Source:    %s\n", a.Source)
+	fmt.Printf("   Mappings:  %d  |  Virtual: %s  |  Resident: %s", len(a.Mappings), a.TotalSize, a.TotalRss)
+	if a.TotalSwap > 0 {
+		fmt.Printf("  |  Swapped: %s", a.TotalSwap)
+	}
+	fmt.Println()
+	if a.Threads > 0 {
+		fmt.Printf("   Threads:   %d\n", a.Threads)
+	}
+
+	a.printCategories()
+	a.printTopMappings(limit)
+	if a.JVM != nil {
+		a.printReconciliation()
+	}
+	a.printFindings()
+}
+
+func (a *Analysis) printCategories() {
+	fmt.Println()
+	fmt.Println("📊 BY CATEGORY")
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("%-18s  %7s  %10s  %10s  %-*s  %10s\n", "Category", "Regions", "Virtual", "Resident", shareBarWidth+6,
+		"Share of RSS", "Dirty")
+	for _, category := range Categories {
+		usage := a.Usage[category]
+		if usage == nil {
+			continue
+		}
+		share := float64(usage.Rss) / float64(max(a.TotalRss, 1))
+		fmt.Printf("%-18s  %7d  %10s  %10s  %s %5s  %10s\n", category, usage.Regions, usage.Size, usage.Rss,
+			utils.CreateProgressBar(share, shareBarWidth, utils.InfoColor), utils.Precision(0).Percent(share*100), usage.PrivateDirty)
+	}
+}
+
+func (a *Analysis) printTopMappings(limit int) {
+	mappings := make([]*Mapping, len(a.Mappings))
+	copy(mappings, a.Mappings)
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return mappings[i].Rss > mappings[j].Rss
+	})
+
+	fmt.Println()
+	fmt.Println("🔝 LARGEST RESIDENT MAPPINGS")
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("%-33s  %-4s  %10s  %10s  %-18s  %s\n", "Address", "Perm", "Virtual", "Resident", "Category", "Path")
+	for i, mapping := range mappings {
+		if i >= limit || mapping.Rss == 0 {
+			break
+		}
+		fmt.Printf("%016x-%016x  %-4s  %10s  %10s  %-18s  %s\n", mapping.Start, mapping.End, mapping.Permissions,
+			mapping.Size, mapping.Rss, mapping.Category, utils.TruncateString(mapping.Path, 40))
+	}
+}
+
+func (a *Analysis) printReconciliation() {
+	jvm := a.JVM
+
+	fmt.Println()
+	fmt.Printf("⚖️  JVM ACCOUNTING (JMX, %s)\n", jvm.Target)
+	fmt.Println(strings.Repeat("─", 80))
+	fmt.Printf("%-28s  %14s  %14s\n", "Area", "JVM committed", "Resident")
+
+	row := func(area string, committed utils.MemorySize, resident string) {
+		fmt.Printf("%-28s  %14s  %14s\n", area, committed, resident)
+	}
+	row(CategoryHeap, jvm.HeapCommitted, a.rss(CategoryHeap).String())
+	row(CategoryClassSpace, jvm.ClassSpaceCommitted, a.rss(CategoryClassSpace).String())
+	row(CategoryCodeCache, jvm.CodeCacheCommitted, a.rss(CategoryCodeCache).String())
+	row("Metaspace (non-class)", jvm.MetaspaceCommitted-jvm.ClassSpaceCommitted, "in "+CategoryOtherAnon)
+	row("Direct buffers", jvm.DirectBuffers, "in "+CategoryOtherAnon)
+	fmt.Printf("%-28s  %14s  %14s\n", fmt.Sprintf("%s (%d threads)", CategoryThreadStacks, a.Threads), "-",
+		a.rss(CategoryThreadStacks))
+
+	line := fmt.Sprintf("%-28s  %14s  %14s", "Not accounted for", "-", a.Unexplained)
+	share := float64(a.Unexplained) / float64(max(a.TotalRss, 1))
+	severity := "good"
+	if share >= unexplainedWarningShare || a.Unexplained >= unexplainedWarningRss {
+		severity = "warning"
+	}
+	fmt.Println(utils.GetSeverityStyle(severity).Render(line))
+}
+
+func (a *Analysis) printFindings() {
+	fmt.Println()
+	if len(a.Findings) == 0 {
+		fmt.Println(utils.GoodStyle.Render("✅ No signs of native memory bloat"))
+	} else {
+		fmt.Println("💡 FINDINGS")
+		fmt.Println(strings.Repeat("─", 80))
+		for _, finding := range a.Findings {
+			fmt.Println(utils.GetSeverityStyle(finding.Severity).Render("• " + finding.Description))
+			fmt.Printf("  → %s\n", finding.Recommendation)
+		}
+	}
+
+	if a.JVM == nil {
+		fmt.Println()
+		fmt.Println(utils.MutedStyle.Render("Connect to the JVM (PID or --jmx) to reconcile the mappings with heap and non-heap usage"))
+	}
+}
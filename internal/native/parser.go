@@ -0,0 +1,181 @@
+package native
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+var (
+	// 7f2c4c000000-7f2c4c021000 rw-p 00000000 00:00 0                          [heap]
+	smapsHeaderPattern = regexp.MustCompile(`^([0-9a-f]+)-([0-9a-f]+)\s+([rwxsp-]{4})\s+([0-9a-f]+)\s+\S+\s+(\d+)\s*(.*)$`)
+
+	// 00007f2c4c000000     132     128     128 rw---   [ anon ]
+	pmapPattern = regexp.MustCompile(`^([0-9a-f]{8,16})\s+(\d+)\s+(\d+|-)\s+(\d+|-)\s+([rwxsR-]{5})\s+(.*)$`)
+)
+
+// ParseProcess reads the mappings of a running process from /proc
+func ParseProcess(pid int) (*Analysis, error) {
+	filename := fmt.Sprintf("/proc/%d/smaps", pid)
+	analysis, err := ParseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	analysis.Threads = processThreads(pid)
+	return analysis, nil
+}
+
+// ParseFile reads a saved /proc/<pid>/smaps or `pmap -x <pid>` output
+func ParseFile(filename string) (*Analysis, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open mappings file: %w", err)
+	}
+	defer file.Close()
+
+	mappings, format, err := Parse(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Analysis{Source: filename, Format: format, Mappings: mappings}, nil
+}
+
+func Parse(reader io.Reader) ([]*Mapping, string, error) {
+	var mappings []*Mapping
+	var current *Mapping
+	format := ""
+	counters := false
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if matches := smapsHeaderPattern.FindStringSubmatch(line); matches != nil && format != "pmap -x" {
+			format = "smaps"
+			current = &Mapping{
+				Start:       parseHex(matches[1]),
+				End:         parseHex(matches[2]),
+				Permissions: matches[3],
+				Offset:      parseHex(matches[4]),
+				Path:        strings.TrimSpace(matches[6]),
+			}
+			current.Inode, _ = strconv.ParseUint(matches[5], 10, 64)
+			// Plain /proc/<pid>/maps has no counters; its size comes from the address range
+			current.Size = utils.MemorySize(current.End - current.Start)
+			mappings = append(mappings, current)
+			continue
+		}
+
+		if matches := pmapPattern.FindStringSubmatch(line); matches != nil && format != "smaps" {
+			format = "pmap -x"
+			mappings = append(mappings, parsePmapLine(matches))
+			continue
+		}
+
+		if current != nil && parseCounter(current, line) {
+			counters = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", fmt.Errorf("unable to read mappings: %w", err)
+	}
+
+	if len(mappings) == 0 {
+		return nil, "", fmt.Errorf("no mappings found (expected /proc/<pid>/smaps or pmap -x output)")
+	}
+	if format == "smaps" && !counters {
+		format = "maps" // Address ranges only: nothing is known to be resident
+	}
+	return mappings, format, nil
+}
+
+// parseCounter reads an smaps line such as "Rss:                 128 kB"
+func parseCounter(mapping *Mapping, line string) bool {
+	key, value, found := strings.Cut(line, ":")
+	if !found {
+		return false
+	}
+	kilobytes, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+	if err != nil {
+		return false // VmFlags...
+	}
+	size := utils.MemorySize(kilobytes) * utils.KB
+
+	switch key {
+	case "Size":
+		mapping.Size = size
+	case "Rss":
+		mapping.Rss = size
+	case "Pss":
+		mapping.Pss = size
+	case "Private_Dirty":
+		mapping.PrivateDirty = size
+	case "Swap":
+		mapping.Swap = size
+	case "AnonHugePages":
+		mapping.AnonHugePages = size
+	}
+	return true
+}
+
+func parsePmapLine(matches []string) *Mapping {
+	size := parseKilobytes(matches[2])
+	mode := matches[5]
+
+	// pmap modes are "rwxs-"; convert to the maps "rwxp" layout
+	sharing := "p"
+	if mode[3] == 's' {
+		sharing = "s"
+	}
+	path := strings.TrimSpace(matches[6])
+	switch path {
+	case "[ anon ]":
+		path = ""
+	case "[ stack ]":
+		path = "[stack]"
+	}
+
+	mapping := &Mapping{
+		Start:        parseHex(matches[1]),
+		Permissions:  mode[:3] + sharing,
+		Path:         path,
+		Size:         size,
+		Rss:          parseKilobytes(matches[3]),
+		PrivateDirty: parseKilobytes(matches[4]),
+	}
+	mapping.End = mapping.Start + uint64(size)
+	mapping.Pss = mapping.Rss
+	return mapping
+}
+
+func parseHex(s string) uint64 {
+	value, _ := strconv.ParseUint(s, 16, 64)
+	return value
+}
+
+func parseKilobytes(s string) utils.MemorySize {
+	kilobytes, _ := strconv.ParseInt(s, 10, 64)
+	return utils.MemorySize(kilobytes) * utils.KB
+}
+
+// processThreads reads the thread count from /proc/<pid>/status, or 0 when unavailable
+func processThreads(pid int) int64 {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "Threads:"); found {
+			threads, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			return threads
+		}
+	}
+	return 0
+}
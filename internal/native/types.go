@@ -0,0 +1,134 @@
+package native
+
+import (
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// What a mapping holds, as far as its address, permissions and neighbours tell
+const (
+	CategoryHeap         = "Java heap"
+	CategoryClassSpace   = "Class space"
+	CategoryCodeCache    = "Code cache"
+	CategoryThreadStacks = "Thread stacks"
+	CategoryMainStack    = "Main stack"
+	CategoryMallocArenas = "malloc arenas"
+	CategoryMallocHeap   = "malloc main heap"
+	CategoryOtherAnon    = "Other anonymous"
+	CategoryCDS          = "CDS archive"
+	CategoryJars         = "JARs & modules"
+	CategoryLibraries    = "Shared libraries"
+	CategoryFiles        = "Mapped files"
+	CategoryKernel       = "Kernel"
+)
+
+// Categories in report order: JVM-managed memory first, then native, then file-backed
+var Categories = []string{
+	CategoryHeap, CategoryClassSpace, CategoryCodeCache, CategoryThreadStacks, CategoryMainStack,
+	CategoryMallocArenas, CategoryMallocHeap, CategoryOtherAnon,
+	CategoryCDS, CategoryJars, CategoryLibraries, CategoryFiles, CategoryKernel,
+}
+
+// Mapping is one line of /proc/<pid>/maps with its smaps counters
+type Mapping struct {
+	Start       uint64
+	End         uint64
+	Permissions string // e.g. "rw-p", "---p", "r-xp"
+	Offset      uint64
+	Inode       uint64
+	Path        string // File, or "[heap]", "[stack]", "[vdso]"...; empty for anonymous memory
+
+	Size          utils.MemorySize
+	Rss           utils.MemorySize
+	Pss           utils.MemorySize
+	PrivateDirty  utils.MemorySize
+	Swap          utils.MemorySize
+	AnonHugePages utils.MemorySize
+
+	Category string
+}
+
+func (m *Mapping) Anonymous() bool {
+	return m.Path == ""
+}
+
+// Reserved reports whether the mapping is address space only (PROT_NONE), e.g. uncommitted heap or guard pages
+func (m *Mapping) Reserved() bool {
+	return m.Permissions[:3] == "---"
+}
+
+func (m *Mapping) Executable() bool {
+	return m.Permissions[2] == 'x'
+}
+
+// Usage sums the mappings of one category
+type Usage struct {
+	Category     string
+	Mappings     int
+	Regions      int // Stacks or arenas; each is a group of adjacent mappings
+	Size         utils.MemorySize
+	Rss          utils.MemorySize
+	PrivateDirty utils.MemorySize
+	Swap         utils.MemorySize
+}
+
+// JVMMemory is what the JVM reports through JMX, to reconcile against the mappings
+type JVMMemory struct {
+	Target string
+
+	HeapCommitted       utils.MemorySize
+	HeapMax             utils.MemorySize
+	NonHeapCommitted    utils.MemorySize
+	MetaspaceCommitted  utils.MemorySize
+	ClassSpaceCommitted utils.MemorySize
+	CodeCacheCommitted  utils.MemorySize
+	DirectBuffers       utils.MemorySize
+	MappedBuffers       utils.MemorySize
+	Threads             int64
+	Processors          int64
+}
+
+// Finding is a conclusion about where native memory goes
+type Finding struct {
+	Severity       string // "critical", "warning", "info"
+	Description    string
+	Recommendation string
+}
+
+type Analysis struct {
+	Source  string // File name or "/proc/<pid>/smaps"
+	Format  string // "smaps" or "pmap -x"
+	Threads int64  // From /proc/<pid>/status, or JMX; 0 when unknown
+
+	Mappings []*Mapping
+	Usage    map[string]*Usage
+
+	TotalSize utils.MemorySize
+	TotalRss  utils.MemorySize
+	TotalSwap utils.MemorySize
+
+	JVM *JVMMemory // nil without a JMX connection
+
+	// Resident anonymous memory not explained by JMX-reported pools; only with JVM
+	Unexplained utils.MemorySize
+
+	Findings []Finding
+}
+
+// NewJVMMemory takes the figures to reconcile from a JMX snapshot
+func NewJVMMemory(target string, snapshot *jmx.MBeanSnapshot) *JVMMemory {
+	memory := snapshot.Memory
+	return &JVMMemory{
+		Target:              target,
+		HeapCommitted:       utils.MemorySize(memory.Heap.Committed),
+		HeapMax:             utils.MemorySize(max(memory.Heap.Max, 0)),
+		NonHeapCommitted:    utils.MemorySize(memory.NonHeap.Committed),
+		MetaspaceCommitted:  utils.MemorySize(memory.Metaspace.Usage.Committed),
+		ClassSpaceCommitted: utils.MemorySize(memory.CompressedClassSpace.Usage.Committed),
+		CodeCacheCommitted:  utils.MemorySize(memory.CodeCache.Committed),
+		DirectBuffers:       utils.MemorySize(memory.DirectBuffers.Capacity),
+		MappedBuffers:       utils.MemorySize(memory.MappedBuffers.Capacity),
+		Threads:             snapshot.Threading.Count,
+		Processors:          snapshot.OS.AvailableProcessors,
+	}
+}
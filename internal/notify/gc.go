@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * GC health score: 100 minus a penalty per issue the recommendations
+ * raise, so the score moves with what `gc analyze` reports:
+ *
+ *   critical -20, warning -7, info -1, floored at 0
+ *
+ * 90 and above is healthy, 70-89 needs watching, below 70 needs action.
+ */
+const (
+	criticalPenalty = 20
+	warningPenalty  = 7
+	infoPenalty     = 1
+)
+
+func GCScore(issues *gc.GCIssues) int {
+	score := 100 - criticalPenalty*len(issues.Critical) - warningPenalty*len(issues.Warning) -
+		infoPenalty*len(issues.Info)
+	return max(score, 0)
+}
+
+// NewGCSummary condenses a GC analysis and its recommendations
+func NewGCSummary(filename string, events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues) *Summary {
+	score := GCScore(issues)
+	summary := &Summary{
+		Title:   "🔍 GC analysis complete",
+		Source:  filepath.Base(filename),
+		Score:   score,
+		Verdict: ScoreVerdict(score),
+		Metrics: []Metric{
+			{"Events", fmt.Sprintf("%d", analysis.TotalEvents)},
+			{"Throughput", utils.FormatPercent(analysis.Throughput)},
+			{"Max pause", utils.FormatDuration(analysis.MaxPause)},
+			{"P99 pause", utils.FormatDuration(analysis.P99Pause)},
+			{"Full GCs", fmt.Sprintf("%d", analysis.FullGCCount)},
+		},
+	}
+	if analysis.HeapMax > 0 {
+		summary.Metrics = append(summary.Metrics, Metric{"Heap", analysis.HeapMax.String()})
+	}
+
+	for _, group := range []struct {
+		severity string
+		issues   []gc.PerformanceIssue
+	}{{"critical", issues.Critical}, {"warning", issues.Warning}} {
+		for _, issue := range group.issues {
+			summary.Issues = append(summary.Issues, Issue{group.severity, issue.Type, issue.Description})
+		}
+	}
+
+	var heapAfter, pauses []float64
+	for _, event := range events {
+		if event.HeapAfter > 0 {
+			heapAfter = append(heapAfter, event.HeapAfter.MB())
+		}
+		if event.Duration > 0 {
+			pauses = append(pauses, float64(event.Duration.Microseconds())/1000)
+		}
+	}
+	summary.Charts = []Chart{
+		{Title: "Heap after GC", Values: heapAfter, Unit: " MB"},
+		{Title: "Pause time", Values: pauses, Unit: " ms"},
+	}
+	return summary
+}
+
+// ScoreVerdict reads a health score as one of the three bands above
+func ScoreVerdict(score int) string {
+	switch {
+	case score >= 90:
+		return "Healthy"
+	case score >= 70:
+		return "Needs watching"
+	default:
+		return "Action needed"
+	}
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ServiceSlack = "slack"
+	ServiceTeams = "teams"
+
+	sendTimeout = 10 * time.Second
+)
+
+// Notifier posts summaries to a Slack or Microsoft Teams incoming webhook
+type Notifier struct {
+	service string
+	webhook string
+	client  *http.Client
+}
+
+/*
+ * NewNotifier accepts the webhook with its service as the scheme, e.g.
+ *
+ *   slack://hooks.slack.com/services/T000/B000/XXXX
+ *   teams://example.webhook.office.com/webhookb2/...
+ *
+ * which is posted to over https. A plain https:// webhook URL works too when
+ * the service can be told from its host.
+ */
+func NewNotifier(target string) (*Notifier, error) {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid notify target '%s': expected slack://<webhook> or teams://<webhook>", target)
+	}
+
+	service := parsed.Scheme
+	switch service {
+	case ServiceSlack, ServiceTeams:
+		parsed.Scheme = "https"
+	case "https":
+		switch host := parsed.Hostname(); {
+		case strings.HasSuffix(host, "slack.com"):
+			service = ServiceSlack
+		case strings.HasSuffix(host, "office.com"), strings.HasSuffix(host, "logic.azure.com"):
+			service = ServiceTeams
+		default:
+			return nil, fmt.Errorf("unknown webhook host '%s': use slack://<webhook> or teams://<webhook>", host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported notify scheme '%s': use slack:// or teams://", parsed.Scheme)
+	}
+
+	return &Notifier{
+		service: service,
+		webhook: parsed.String(),
+		client:  &http.Client{Timeout: sendTimeout},
+	}, nil
+}
+
+// String names the destination without the webhook secret
+func (n *Notifier) String() string {
+	host := n.webhook
+	if parsed, err := url.Parse(n.webhook); err == nil {
+		host = parsed.Host
+	}
+	if n.service == ServiceSlack {
+		return fmt.Sprintf("Slack (%s)", host)
+	}
+	return fmt.Sprintf("Teams (%s)", host)
+}
+
+func (n *Notifier) Send(summary *Summary) error {
+	payload, err := json.Marshal(map[string]string{"text": summary.Render(n.service)})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	response, err := n.client.Post(n.webhook, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		// Keep the webhook URL, which carries its secret, out of the message
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to %s: %w", n, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 200))
+		return fmt.Errorf("%s rejected the notification: %s %s", n, response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	NoScore = -1
+
+	maxIssues  = 3
+	chartWidth = 40
+)
+
+// Summary is a condensed, chat-sized health report
+type Summary struct {
+	Title   string
+	Source  string // File analyzed or JVM watched
+	Score   int    // 0-100, or NoScore
+	Verdict string
+	Metrics []Metric
+	Issues  []Issue // Most severe first; only the first few are posted
+	Charts  []Chart
+}
+
+type Metric struct {
+	Name  string
+	Value string
+}
+
+type Issue struct {
+	Severity    string // "critical", "warning", "info"
+	Title       string
+	Description string
+}
+
+// Chart is drawn as a text sparkline, labelled with its range
+type Chart struct {
+	Title  string
+	Values []float64
+	Unit   string
+}
+
+// markup holds the few formatting differences between Slack mrkdwn and Teams markdown
+type markup struct {
+	bold       func(string) string
+	newline    string
+	blankLines bool // Whether an empty line can separate sections
+}
+
+var markups = map[string]markup{
+	ServiceSlack: {
+		bold:       func(s string) string { return "*" + s + "*" },
+		newline:    "\n",
+		blankLines: true,
+	},
+	ServiceTeams: {
+		bold:    func(s string) string { return "**" + s + "**" },
+		newline: "\n\n", // Teams collapses single newlines, so every line is already a paragraph
+	},
+}
+
+func (s *Summary) Render(service string) string {
+	m := markups[service]
+	var lines []string
+
+	lines = append(lines, m.bold(s.Title))
+	if s.Source != "" {
+		lines = append(lines, "Source: `"+s.Source+"`")
+	}
+	if s.Score != NoScore {
+		lines = append(lines, fmt.Sprintf("%s Health score: %s (%s)", scoreIcon(s.Score),
+			m.bold(fmt.Sprintf("%d/100", s.Score)), s.Verdict))
+	} else if s.Verdict != "" {
+		lines = append(lines, s.Verdict)
+	}
+
+	if len(s.Metrics) > 0 {
+		metrics := make([]string, len(s.Metrics))
+		for i, metric := range s.Metrics {
+			metrics[i] = fmt.Sprintf("%s: %s", metric.Name, metric.Value)
+		}
+		lines = append(lines, "• "+strings.Join(metrics, "  • "))
+	}
+
+	if len(s.Issues) > 0 {
+		lines = append(lines, "", m.bold("Top issues"))
+		for i, issue := range s.Issues {
+			if i >= maxIssues {
+				lines = append(lines, fmt.Sprintf("…and %d more", len(s.Issues)-maxIssues))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%s %s: %s", severityIcon(issue.Severity), m.bold(issue.Title),
+				issue.Description))
+		}
+	}
+
+	for _, chart := range s.Charts {
+		if line := chart.render(); line != "" {
+			lines = append(lines, "", m.bold(chart.Title), line)
+		}
+	}
+
+	if !m.blankLines {
+		lines = slices.DeleteFunc(lines, func(line string) bool { return line == "" })
+	}
+	return strings.Join(lines, m.newline)
+}
+
+func (c Chart) render() string {
+	if len(c.Values) < 2 {
+		return ""
+	}
+	values := resample(c.Values, chartWidth)
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+	return fmt.Sprintf("`%s`  %s–%s%s", utils.CreateSparkline(values, len(values)), utils.Precision(0).Float(low), utils.Precision(0).Float(high), c.Unit)
+}
+
+// resample keeps the peak of each bucket, so short spikes survive the downsampling
+func resample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	result := make([]float64, width)
+	for i := range result {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		peak := values[start]
+		for _, v := range values[start:end] {
+			peak = max(peak, v)
+		}
+		result[i] = peak
+	}
+	return result
+}
+
+func scoreIcon(score int) string {
+	switch {
+	case score >= 90:
+		return "✅"
+	case score >= 70:
+		return "⚠️"
+	default:
+		return "🔴"
+	}
+}
+
+func severityIcon(severity string) string {
+	switch severity {
+	case "critical":
+		return "🔴"
+	case "warning":
+		return "🟡"
+	default:
+		return "ℹ️"
+	}
+}
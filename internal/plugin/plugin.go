@@ -0,0 +1,255 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+)
+
+/*
+ * Analysis plugins
+ *
+ * A plugin is any executable in the plugins directory given with --plugins
+ * (conventionally ~/.jdiag/plugins). Plugins only run when asked for, on the
+ * command line or in the config file, since they're arbitrary programs that
+ * get the whole analysis. Each one is run once per analysis with an Input as JSON on
+ * stdin, and answers with an Output as JSON on stdout: the issues it found,
+ * in the same form as the report's own. Those are merged into the
+ * analysis's issues by severity, so they show up in every output format.
+ *
+ * 	stdin	{"protocol": 1, "kind": "gc", "file": "...", "report": {...}, "events": [...]}
+ * 	stdout	{"issues": [{"type": "...", "severity": "warning", "description": "...", "recommendations": ["..."]}]}
+ *
+ * A subprocess rather than a Go plugin, so that a plugin can be written in
+ * any language and needn't be built with the exact toolchain and module
+ * versions jdiag was. A plugin that fails, times out or writes something
+ * that isn't an Output is reported and skipped; it never stops the
+ * analysis. Stderr is left to the plugin for its own diagnostics, and the
+ * last line of it is quoted when the plugin fails.
+ *
+ * Run waits for the plugin's output as well as the process, so a script
+ * that leaves a background child holding stdout open would otherwise keep
+ * it waiting long after the timeout killed the script. Once the plugin has
+ * exited or timed out, its pipes get pluginWaitDelay to close before they
+ * are closed for it. Output is capped too, at maxPluginOutput.
+ */
+
+// Protocol is the version of Input and Output; a change that breaks plugins bumps it
+const Protocol = 1
+
+// DefaultTimeout is how long one plugin may take
+const DefaultTimeout = 30 * time.Second
+
+const (
+	pluginWaitDelay = 2 * time.Second // How long the pipes may stay open once the plugin is done
+	maxPluginOutput = 1 << 20         // Bytes of stdout read before the plugin is stopped
+	maxPluginStderr = 4 << 10         // Bytes of stderr kept, from the end, to quote its last line
+)
+
+var errOutputTooLarge = fmt.Errorf("output over %d bytes", maxPluginOutput)
+
+// Input is what a plugin reads from stdin
+type Input struct {
+	Protocol int              `json:"protocol"`
+	Kind     string           `json:"kind"` // What was analyzed: "gc"
+	File     string           `json:"file"`
+	Report   *gc.Report       `json:"report"`
+	Events   []gc.ReportEvent `json:"events"`
+}
+
+// Output is what a plugin writes to stdout
+type Output struct {
+	Issues []gc.ReportIssue `json:"issues"`
+}
+
+type Plugin struct {
+	Name string // File name without its extension
+	Path string
+}
+
+// Discover lists the executables in dir by name; "" is no plugins. A leading ~/ is the home
+// directory, as in a config file the shell hasn't expanded
+func Discover(dir string) ([]Plugin, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if rest, found := strings.CutPrefix(dir, "~/"); found {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the plugins directory: %w", err)
+		}
+		dir = filepath.Join(home, rest)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read plugins directory: %w", err)
+	}
+
+	var plugins []Plugin
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !isExecutable(info) {
+			continue
+		}
+		name := entry.Name()
+		plugins = append(plugins, Plugin{
+			Name: strings.TrimSuffix(name, filepath.Ext(name)),
+			Path: filepath.Join(dir, name),
+		})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Path < plugins[j].Path })
+	return plugins, nil
+}
+
+// Windows has no execute bit, so there it's the extension that says
+func isExecutable(info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// NewGCInput is the Input for an analyzed GC log
+func NewGCInput(file string, events []*gc.GCEvent, analysis *gc.GCAnalysis, issues *gc.GCIssues) *Input {
+	return &Input{
+		Protocol: Protocol,
+		Kind:     "gc",
+		File:     file,
+		Report:   gc.NewReport(analysis, issues),
+		Events:   gc.NewReportEvents(events),
+	}
+}
+
+// Run runs the plugin on input and returns the issues it reports
+func (p Plugin) Run(ctx context.Context, input *Input) ([]gc.ReportIssue, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode plugin input: %w", err)
+	}
+
+	stdout := &cappedBuffer{limit: maxPluginOutput}
+	stderr := &cappedBuffer{limit: maxPluginStderr, keepTail: true}
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = pluginWaitDelay
+	cmd.Env = append(os.Environ(), fmt.Sprintf("JDIAG_PLUGIN_PROTOCOL=%d", Protocol))
+
+	// A plugin that exited cleanly but left a child holding stdout has still answered
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return nil, fmt.Errorf("timed out")
+		case stdout.overflowed:
+			return nil, errOutputTooLarge
+		}
+		if line := lastLine(string(stderr.data)); line != "" {
+			return nil, fmt.Errorf("%v: %s", err, line)
+		}
+		return nil, err
+	}
+
+	var output Output
+	if err := json.Unmarshal(stdout.data, &output); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	return output.Issues, nil
+}
+
+/*
+ * RunAll runs every plugin in dir on input, one after another, and merges
+ * the issues they report into issues. Each issue's type names the plugin
+ * it came from. The errors are those of the plugins that failed, for the
+ * caller to warn about.
+ */
+func RunAll(dir string, input *Input, issues *gc.GCIssues, timeout time.Duration) []error {
+	plugins, err := Discover(dir)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, plugin := range plugins {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		reported, err := plugin.Run(ctx, input)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", plugin.Name, err))
+			continue
+		}
+		for _, issue := range reported {
+			merge(issues, plugin, issue)
+		}
+	}
+	return errs
+}
+
+func merge(issues *gc.GCIssues, plugin Plugin, issue gc.ReportIssue) {
+	kind := issue.Type
+	if kind == "" {
+		kind = "Plugin finding"
+	}
+	merged := gc.PerformanceIssue{
+		Type:           fmt.Sprintf("%s (%s)", kind, plugin.Name),
+		Severity:       issue.Severity,
+		Description:    issue.Description,
+		Recommendation: issue.Recommendations,
+	}
+
+	switch issue.Severity {
+	case "critical":
+		issues.Critical = append(issues.Critical, merged)
+	case "warning":
+		issues.Warning = append(issues.Warning, merged)
+	default:
+		merged.Severity = "info"
+		issues.Info = append(issues.Info, merged)
+	}
+}
+
+// cappedBuffer keeps at most limit bytes: a plugin's stdout fails past them, and
+// stderr keeps its last ones
+type cappedBuffer struct {
+	data       []byte
+	limit      int
+	keepTail   bool
+	overflowed bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if !b.keepTail {
+		if len(b.data)+len(p) > b.limit {
+			b.overflowed = true
+			return 0, errOutputTooLarge
+		}
+		b.data = append(b.data, p...)
+		return len(p), nil
+	}
+
+	b.data = append(b.data, p...)
+	if over := len(b.data) - b.limit; over > 0 {
+		b.data = append(b.data[:0], b.data[over:]...)
+	}
+	return len(p), nil
+}
+
+func lastLine(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
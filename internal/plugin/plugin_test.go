@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePlugin writes a shell script plugin into a fresh directory
+func writePlugin(t *testing.T, script string) Plugin {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins under test are shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "check.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return Plugin{Name: "check", Path: path}
+}
+
+func TestRun(t *testing.T) {
+	const answer = `echo '{"issues": [{"type": "Slow", "severity": "warning", "description": "too slow"}]}'`
+
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+		issues  int
+		err     string
+	}{
+		{name: "answers", script: "cat > /dev/null\n" + answer, issues: 1},
+		{name: "fails", script: "echo first >&2\necho 'no config found' >&2\nexit 3", err: "no config found"},
+		{name: "invalid output", script: "echo not json", err: "invalid output"},
+		// The background sleep keeps stdout open after the script has answered and exited
+		{name: "leaves a child holding stdout", script: answer + "\nsleep 30 &", issues: 1},
+		{name: "hangs", script: "sleep 30", timeout: 200 * time.Millisecond, err: "timed out"},
+		{name: "hangs in a child", script: "sleep 30 &\nwait", timeout: 200 * time.Millisecond, err: "timed out"},
+		{name: "floods stdout", script: "yes", err: errOutputTooLarge.Error()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin := writePlugin(t, test.script)
+			timeout := test.timeout
+			if timeout == 0 {
+				timeout = DefaultTimeout
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			issues, err := plugin.Run(ctx, &Input{Protocol: Protocol, Kind: "gc"})
+			if elapsed := time.Since(start); elapsed > timeout+2*pluginWaitDelay {
+				t.Errorf("Run took %s, past the timeout and wait delay", elapsed)
+			}
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("error %v, want one containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != test.issues {
+				t.Errorf("%d issues, want %d", len(issues), test.issues)
+			}
+		})
+	}
+}
+
+func TestCappedBufferKeepsTail(t *testing.T) {
+	buffer := &cappedBuffer{limit: 8, keepTail: true}
+	for _, chunk := range []string{"first line\n", "second\n", "last"} {
+		if _, err := buffer.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := string(buffer.data); got != "ond\nlast" {
+		t.Errorf("kept %q, want the last 8 bytes", got)
+	}
+
+	head := &cappedBuffer{limit: 4}
+	if _, err := head.Write([]byte("12345")); !errors.Is(err, errOutputTooLarge) {
+		t.Errorf("error %v writing past the limit, want errOutputTooLarge", err)
+	}
+}
@@ -0,0 +1,334 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/internal/watch"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	maxActions       = 3  // Recommendations kept per GC issue
+	maxHeapSuspects  = 3  // Leak suspects reported on their own
+	criticalSuspect  = 30 // Share of the reachable heap, as the heap TUI marks suspects
+	maxAlertFindings = 5  // Distinct watch alerts reported on their own
+)
+
+var severityRank = map[string]int{"critical": 0, "warning": 1, "info": 2}
+
+// Add files a path under the input its extension says it is; anything unrecognized is taken as a GC log
+func (in *Inputs) Add(path string) error {
+	slot, kind := &in.GCLog, SourceGCLog
+	switch name := strings.TrimSuffix(path, ".gz"); {
+	case strings.HasSuffix(path, ".jfr"):
+		slot, kind = &in.JFR, SourceJFR
+	case strings.HasSuffix(name, ".hprof"):
+		slot, kind = &in.Heap, SourceHeap
+	case strings.HasSuffix(path, ".json"):
+		slot, kind = &in.Session, SourceSession
+	}
+
+	if *slot != "" {
+		return fmt.Errorf("two %s files given: %s and %s", strings.ToLower(kind), *slot, path)
+	}
+	*slot = path
+	return nil
+}
+
+// Empty reports whether no artifact was given
+func (in *Inputs) Empty() bool {
+	return in.GCLog == "" && in.JFR == "" && in.Heap == "" && in.Session == ""
+}
+
+/*
+ * Collect runs the analyzer for each artifact given, then cross-references
+ * what they found. An artifact that can't be analyzed is skipped with the
+ * reason rather than failing the report, since incidents rarely leave every
+ * artifact intact; it's an error only when nothing could be analyzed.
+ *
+ * A GC log comes from cache when it's been analyzed before; a nil cache
+ * parses it again. The heap dump goes last: it's by far the slowest to
+ * analyze, and its parse prints progress of its own.
+ */
+func Collect(inputs Inputs, heapConfig *heap.Config, cache *gc.Cache) (*Incident, error) {
+	incident := &Incident{Generated: time.Now()}
+
+	if inputs.GCLog != "" {
+		incident.skipOnError(SourceGCLog, inputs.GCLog, incident.loadGCLog(inputs.GCLog, cache))
+	}
+	if inputs.JFR != "" {
+		incident.skipOnError(SourceJFR, inputs.JFR, incident.loadRecording(inputs.JFR))
+	}
+	if inputs.Session != "" {
+		incident.skipOnError(SourceSession, inputs.Session, incident.loadSession(inputs.Session))
+	}
+	if inputs.Heap != "" {
+		closeDump, err := incident.loadHeapDump(inputs.Heap, heapConfig)
+		incident.skipOnError(SourceHeap, inputs.Heap, err)
+		if closeDump != nil {
+			defer closeDump()
+		}
+	}
+
+	if len(incident.Artifacts) == 0 {
+		return nil, fmt.Errorf("none of the artifacts could be analyzed:\n  %s", strings.Join(incident.Skipped, "\n  "))
+	}
+
+	incident.addGCFindings()
+	incident.addJFRFindings()
+	incident.addHeapFindings()
+	incident.addSessionFindings()
+	incident.crossReference()
+
+	sort.SliceStable(incident.Findings, func(i, j int) bool {
+		return severityRank[incident.Findings[i].Severity] < severityRank[incident.Findings[j].Severity]
+	})
+	return incident, nil
+}
+
+func (i *Incident) skipOnError(source, path string, err error) {
+	if err != nil {
+		i.Skipped = append(i.Skipped, fmt.Sprintf("%s %s: %v", strings.ToLower(source), filepath.Base(path), err))
+	}
+}
+
+func (i *Incident) add(severity, title, detail string, actions []string, sources ...string) {
+	i.Findings = append(i.Findings, Finding{Severity: severity, Sources: sources, Title: title, Detail: detail, Actions: actions})
+}
+
+// ===== LOADERS =====
+
+func (i *Incident) loadGCLog(path string, cache *gc.Cache) error {
+	events, analysis, _, err := cache.ParseAndAnalyze(path, gc.NewParser())
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no GC events found")
+	}
+	i.gcSource, i.gcEvents, i.gcAnalysis = SourceGCLog, events, analysis
+
+	i.Artifacts = append(i.Artifacts, Artifact{
+		Source: SourceGCLog,
+		Path:   path,
+		Summary: fmt.Sprintf("%d collections over %s, %s throughput, max pause %s",
+			analysis.TotalEvents, utils.FormatDuration(analysis.TotalRuntime),
+			utils.FormatPercent(analysis.Throughput), utils.FormatDuration(analysis.MaxPause)),
+		Start: analysis.StartTime,
+		End:   analysis.EndTime,
+	})
+	return nil
+}
+
+// loadRecording also stands in for the GC log when none was given and the recording has collections
+func (i *Incident) loadRecording(path string) error {
+	recording, err := jfr.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	i.recording = recording
+	if len(recording.Allocations) > 0 {
+		i.allocations = recording.AllocationProfile()
+	}
+
+	summary := fmt.Sprintf("%s recording, %d collections, %d allocation samples",
+		utils.FormatDuration(recording.Duration()), len(recording.Collections), len(recording.Allocations))
+	if i.gcAnalysis == nil && len(recording.Collections) > 0 {
+		events, analysis := recording.GCEvents()
+		gc.AnalyzeGCLogs(events, analysis)
+		i.gcSource, i.gcEvents, i.gcAnalysis = SourceJFR, events, analysis
+		summary += " (used for the GC analysis)"
+	}
+
+	i.Artifacts = append(i.Artifacts, Artifact{
+		Source:  SourceJFR,
+		Path:    path,
+		Summary: summary,
+		Start:   recording.StartTime,
+		End:     recording.EndTime,
+	})
+	return nil
+}
+
+func (i *Incident) loadSession(path string) error {
+	session, err := watch.LoadSessionSummary(path)
+	if err != nil {
+		return err
+	}
+	i.session = session
+
+	i.Artifacts = append(i.Artifacts, Artifact{
+		Source: SourceSession,
+		Path:   path,
+		Summary: fmt.Sprintf("%s of %s, %d snapshots, %d alerts", utils.FormatDuration(session.End.Sub(session.Start)),
+			session.Target, session.Snapshots, len(session.Alerts)),
+		Start: session.Start,
+		End:   session.End,
+	})
+	return nil
+}
+
+// loadHeapDump returns the function that closes the dump once the cross-references are done
+func (i *Incident) loadHeapDump(path string, config *heap.Config) (func(), error) {
+	parser, heapAnalyzer, err := heap.AnalyzeHeapDump(path, config)
+	if err != nil {
+		return nil, err
+	}
+	i.heap = heapAnalyzer
+	i.heapStacks = parser.GetStackRegistry()
+	i.heapCut = parser.IsTruncated()
+
+	summary := fmt.Sprintf("%d leak suspects", len(heapAnalyzer.GetLeakSuspects()))
+	if tree := heapAnalyzer.GetDominatorTree(); tree != nil {
+		summary = fmt.Sprintf("%s reachable, %s", utils.MemorySize(tree.TotalRetainedSize()), summary)
+	}
+	if i.heapCut {
+		summary += " (truncated)"
+	}
+
+	i.Artifacts = append(i.Artifacts, Artifact{Source: SourceHeap, Path: path, Summary: summary})
+	return func() {
+		heapAnalyzer.Close()
+		parser.Close()
+	}, nil
+}
+
+// ===== FINDINGS OF SINGLE ARTIFACTS =====
+
+func (i *Incident) addGCFindings() {
+	if i.gcAnalysis == nil {
+		return
+	}
+
+	issues := gc.GetRecommendations(i.gcAnalysis)
+	for _, group := range [][]gc.PerformanceIssue{issues.Critical, issues.Warning, issues.Info} {
+		for _, issue := range group {
+			actions := issue.Recommendation[:min(maxActions, len(issue.Recommendation))]
+			i.add(issue.Severity, issue.Type, issue.Description, actions, i.gcSource)
+		}
+	}
+
+	if gaps := i.gcAnalysis.LoggingGaps; len(gaps) > 0 {
+		var missing []string
+		for _, gap := range gaps {
+			missing = append(missing, gap.Missing)
+		}
+		i.add("info", "The GC log leaves out details the analysis uses", "Missing: "+strings.Join(missing, ", "),
+			[]string{"Next time run the JVM with " + gc.RecommendedXlog}, SourceGCLog)
+	}
+}
+
+func (i *Incident) addJFRFindings() {
+	if i.allocations == nil || len(i.allocations.Sites) == 0 {
+		return
+	}
+
+	profile := i.allocations
+	site := profile.Sites[0]
+	detail := fmt.Sprintf("%s of the %s sampled", utils.FormatPercent(profile.Share(site.Weight)*100), profile.Total)
+	if rate := profile.Rate(); rate > 0 {
+		detail += fmt.Sprintf(" (%s/s)", rate)
+	}
+	i.add("info", "Top allocation site: "+site.Name, detail,
+		[]string{fmt.Sprintf("jdiag jfr allocations %s   # Classes, sites, threads and stacks", i.recording.Filename)},
+		SourceJFR)
+}
+
+func (i *Incident) addHeapFindings() {
+	if i.heap == nil {
+		return
+	}
+
+	if i.heapCut {
+		i.add("info", "The heap dump is incomplete",
+			"Retained sizes and leak suspects cover only the part of the dump that could be read", nil, SourceHeap)
+	}
+
+	for n, suspect := range i.heap.GetLeakSuspects() {
+		if n >= maxHeapSuspects {
+			break
+		}
+		severity := "warning"
+		if suspect.Percentage >= criticalSuspect {
+			severity = "critical"
+		}
+
+		title := fmt.Sprintf("%s %s (%s of the heap)", suspectName(suspect),
+			utils.MemorySize(suspect.RetainedSize), utils.Precision(1).Percent(suspect.Percentage))
+		detail := suspect.Description
+		if len(suspect.AllocationSites) > 0 {
+			site := suspect.AllocationSites[0]
+			detail += fmt.Sprintf(" %s instances of %s were allocated at %s.",
+				utils.FormatCount(int64(site.Objects)), suspect.ClassName, site.Frame)
+		}
+		i.add(severity, title, detail,
+			[]string{"Follow its dominator path in 'jdiag heap <dump> -o tui' to the field that holds it"}, SourceHeap)
+	}
+}
+
+// suspectName is the suspect with the verb that fits it
+func suspectName(suspect *analyzer.LeakSuspect) string {
+	if suspect.Kind == analyzer.ClassGroupSuspect {
+		return fmt.Sprintf("%d instances of %s retain", suspect.InstanceCount, suspect.ClassName)
+	}
+	return suspect.ClassName + " retains"
+}
+
+// addSessionFindings reports each alert the session fired once, with how often it fired
+func (i *Incident) addSessionFindings() {
+	if i.session == nil {
+		return
+	}
+
+	type firing struct {
+		latest watch.PerformanceAlert
+		level  string
+		count  int
+	}
+	var titles []string
+	byTitle := make(map[string]*firing)
+	for _, alert := range i.session.Alerts {
+		if alert.Resolved {
+			continue
+		}
+		f, seen := byTitle[alert.Title]
+		if !seen {
+			f = &firing{level: alert.Level}
+			byTitle[alert.Title] = f
+			titles = append(titles, alert.Title)
+		}
+		f.latest = alert
+		f.count++
+		if severityRank[alert.Level] < severityRank[f.level] {
+			f.level = alert.Level
+		}
+	}
+	sort.SliceStable(titles, func(a, b int) bool {
+		return severityRank[byTitle[titles[a]].level] < severityRank[byTitle[titles[b]].level]
+	})
+
+	for n, title := range titles {
+		if n >= maxAlertFindings {
+			break
+		}
+		f := byTitle[title]
+		detail := f.latest.Description
+		if f.count > 1 {
+			detail = fmt.Sprintf("Fired %d times, last at %s: %s", f.count, f.latest.Timestamp.Local().Format("15:04:05"), detail)
+		}
+		i.add(f.level, title, detail, nil, SourceSession)
+	}
+
+	if len(i.session.Advisories) > 0 {
+		i.add("info", "The watch session raised advisories", strings.Join(i.session.Advisories, ", "),
+			[]string{"Run 'jdiag watch' on the JVM again to see the full advice"}, SourceSession)
+	}
+}
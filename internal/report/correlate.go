@@ -0,0 +1,283 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/internal/watch"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	alertLookback    = 30 * time.Second // A watch alert is put down to a collection that ended this long before it
+	maxAlertMatches  = 3                // Alert and collection pairs listed
+	maxSharedSites   = 2                // JFR sites listed for a class both retained and allocated
+	maxNativeMatches = 3                // Native threads listed behind GCLocker stalls
+)
+
+/*
+ * crossReference draws the conclusions no single artifact supports:
+ *
+ *   - GC log + heap dump: is the heap growth the log shows retained by the
+ *     dump's leak suspects? (the analysis behind 'jdiag heap correlate')
+ *   - GC log + JFR: which code the allocation pressure the log shows comes from
+ *   - Heap dump + JFR: leak suspect classes that are still being allocated
+ *   - Watch session + GC log: alerts that fired right after a long pause, and
+ *     the threads in native code behind GCLocker stalls
+ *
+ * The GC side is the JFR recording when it stands in for a missing log.
+ */
+func (i *Incident) crossReference() {
+	if i.gcAnalysis != nil && i.heap != nil {
+		i.correlateLeak()
+	}
+	if i.gcAnalysis != nil && i.allocations != nil {
+		i.correlateAllocationPressure()
+	}
+	if i.heap != nil && i.allocations != nil {
+		i.correlateRetainedAllocations()
+	}
+	if i.session != nil && i.gcAnalysis != nil {
+		i.correlateAlerts()
+		if i.gcAnalysis.HasCriticalGCLocker || i.gcAnalysis.HasWarningGCLocker {
+			i.correlateGCLocker()
+		}
+	}
+}
+
+// sources names the GC source and the others, without naming the recording twice
+func (i *Incident) sources(others ...string) []string {
+	sources := []string{i.gcSource}
+	for _, other := range others {
+		if !slices.Contains(sources, other) {
+			sources = append(sources, other)
+		}
+	}
+	return sources
+}
+
+func (i *Incident) correlateLeak() {
+	correlation := heap.CorrelateLeak(i.gcEvents, i.gcAnalysis, i.heap, i.heapStacks)
+
+	severity := correlation.Severity
+	if severity == "good" {
+		severity = "info"
+	}
+
+	actions := correlation.Notes
+	if len(correlation.Suspects) > 0 {
+		actions = append(slices.Clone(actions), "Run 'jdiag heap correlate <dump> <gc-log>' for each suspect's share of the growth and its allocation sites")
+	}
+	i.Findings = append(i.Findings, Finding{
+		Severity: severity,
+		Sources:  i.sources(SourceHeap),
+		Title:    "Heap growth checked against the dump's leak suspects",
+		Detail:   correlation.Verdict,
+		Actions:  actions,
+	})
+}
+
+// correlateAllocationPressure names the code behind the allocation pressure the GC analysis flagged
+func (i *Incident) correlateAllocationPressure() {
+	analysis := i.gcAnalysis
+	var pressure string
+	severity := "warning"
+	switch {
+	case analysis.HasCriticalDeathSpiral:
+		pressure, severity = "Collections spiraled toward back-to-back", "critical"
+	case analysis.HasWarningBackToBack:
+		pressure = fmt.Sprintf("%s of collections ran back-to-back", utils.FormatPercent(analysis.Frequency.BackToBackRate*100))
+	case analysis.HasWarningAllocationRate:
+		pressure = fmt.Sprintf("Allocation rate %s/s", utils.FormatMB(analysis.AllocationRate))
+	default:
+		return
+	}
+
+	profile := i.allocations
+	if len(profile.Sites) == 0 {
+		return
+	}
+	site := profile.Sites[0]
+	detail := fmt.Sprintf("%s; JFR puts %s of allocation at %s", pressure,
+		utils.FormatPercent(profile.Share(site.Weight)*100), site.Name)
+	if len(profile.Classes) > 0 {
+		class := profile.Classes[0]
+		detail += fmt.Sprintf(", and %s of it is %s", utils.FormatPercent(profile.Share(class.Weight)*100), class.Name)
+	}
+
+	i.Findings = append(i.Findings, Finding{
+		Severity: severity,
+		Sources:  i.sources(SourceJFR),
+		Title:    "GC pressure traced to " + site.Name,
+		Detail:   detail,
+		Actions: []string{
+			"Cutting allocation at this site lowers GC frequency more than any heap or collector tuning",
+			fmt.Sprintf("jdiag jfr allocations %s   # The stacks that reach it", i.recording.Filename),
+		},
+	})
+}
+
+// correlateRetainedAllocations finds leak suspect classes the recording shows still being allocated
+func (i *Incident) correlateRetainedAllocations() {
+	classes := make(map[string]*jfr.ClassAllocation)
+	for _, class := range i.allocations.Classes {
+		classes[class.Name] = class
+	}
+
+	seen := make(map[string]bool)
+	for _, suspect := range i.heap.GetLeakSuspects() {
+		for _, name := range []string{suspect.ClassName, suspect.AccumulationClassName} {
+			class, allocated := classes[name]
+			if name == "" || seen[name] || !allocated {
+				continue
+			}
+			seen[name] = true
+
+			var sites []string
+			for _, site := range class.Sites[:min(maxSharedSites, len(class.Sites))] {
+				sites = append(sites, site.Name)
+			}
+			detail := fmt.Sprintf("A leak suspect retaining %s in the dump; %s of sampled allocation in the recording",
+				utils.MemorySize(suspect.RetainedSize), utils.FormatPercent(i.allocations.Share(class.Weight)*100))
+			if len(sites) > 0 {
+				detail += ", at " + strings.Join(sites, " and ")
+			}
+
+			i.add("warning", name+" is retained and still being allocated", detail,
+				[]string{"These sites are where the retained instances most likely come from; check what keeps a reference after use"},
+				SourceHeap, SourceJFR)
+		}
+	}
+}
+
+/*
+ * correlateAlerts pairs the session's warning and critical alerts with the
+ * Full GC or long pause that ended shortly before each: JMX sees a pause
+ * only once it's over, so the alert follows the collection behind it.
+ */
+func (i *Incident) correlateAlerts() {
+	if !overlaps(i.session.Start, i.session.End, i.gcAnalysis.StartTime, i.gcAnalysis.EndTime) {
+		i.add("info", "The watch session and GC data cover different times",
+			fmt.Sprintf("Session %s to %s, GC data %s to %s",
+				i.session.Start.Local().Format(time.DateTime), i.session.End.Local().Format(time.DateTime),
+				i.gcAnalysis.StartTime.Local().Format(time.DateTime), i.gcAnalysis.EndTime.Local().Format(time.DateTime)),
+			[]string{"Give the GC log of the same JVM run to match alerts to collections"},
+			i.sources(SourceSession)...)
+		return
+	}
+
+	var matches []string
+	severity := "info"
+	for _, alert := range i.session.Alerts {
+		if alert.Level == "info" {
+			continue
+		}
+		event := i.collectionBefore(alert.Timestamp)
+		if event == nil {
+			continue
+		}
+		if severityRank[alert.Level] < severityRank[severity] {
+			severity = alert.Level
+		}
+		if len(matches) < maxAlertMatches {
+			matches = append(matches, fmt.Sprintf("%s %q fired %s after %s GC #%d (%s, %s)",
+				alert.Timestamp.Local().Format("15:04:05"), alert.Title, utils.FormatDuration(alert.Timestamp.Sub(event.Timestamp)),
+				gc.CategorizeGCType(event.Type), event.ID, event.Cause, utils.FormatDuration(event.Duration)))
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	i.add(severity, "Watch alerts followed long GC pauses", strings.Join(matches, "; "),
+		[]string{"Fix the pauses first; the alerts are their symptoms"},
+		i.sources(SourceSession)...)
+}
+
+/*
+ * correlateGCLocker names the threads the session caught running native code
+ * as the likely holders of the JNI critical regions GC waited on. Threads
+ * gc+jni logged starting the deferred collection are listed first; the rest
+ * are only candidates, as JMX can't tell a critical region from other
+ * native code.
+ */
+func (i *Incident) correlateGCLocker() {
+	if len(i.session.NativeThreads) == 0 {
+		return
+	}
+	locker := i.gcAnalysis.GCLocker
+	holders := make(map[string]bool)
+	for _, holder := range locker.Holders {
+		holders[holder.Name] = true
+	}
+	var threads []watch.NativeThread
+	for _, thread := range i.session.NativeThreads {
+		if holders[thread.Name] {
+			threads = append(threads, thread)
+		}
+	}
+	for _, thread := range i.session.NativeThreads {
+		if !holders[thread.Name] {
+			threads = append(threads, thread)
+		}
+	}
+
+	var matches, libraries []string
+	for _, thread := range threads[:min(maxNativeMatches, len(threads))] {
+		match := fmt.Sprintf("%q in %s (%d of %d snapshots)", thread.Name, thread.Frame, thread.Snapshots, i.session.Snapshots)
+		if holders[thread.Name] {
+			match += ", logged starting a GCLocker collection"
+		}
+		matches = append(matches, match)
+		if library := nativeLibrary(thread.Frame); library != "" && !slices.Contains(libraries, library) {
+			libraries = append(libraries, library)
+		}
+	}
+
+	severity := "warning"
+	if i.gcAnalysis.HasCriticalGCLocker {
+		severity = "critical"
+	}
+	actions := []string{"Check these threads' native code for JNI critical regions held across slow work, and release them sooner"}
+	if len(libraries) > 0 {
+		actions = append(actions, "Native code to investigate is in "+strings.Join(libraries, ", "))
+	}
+	i.add(severity, "GCLocker stalls traced to threads in native code",
+		fmt.Sprintf("%d GCLocker collections; the session caught %s", locker.Collections, strings.Join(matches, "; ")),
+		actions, i.sources(SourceSession)...)
+}
+
+// nativeLibrary is the package of a Class.method frame, as the closest the JVM gets to naming the library
+func nativeLibrary(frame string) string {
+	class := frame[:max(strings.LastIndex(frame, "."), 0)]
+	if index := strings.LastIndex(class, "."); index >= 0 {
+		return class[:index]
+	}
+	return class
+}
+
+// collectionBefore is the longest Full GC or poor pause that ended within alertLookback before at
+func (i *Incident) collectionBefore(at time.Time) *gc.GCEvent {
+	var found *gc.GCEvent
+	for _, event := range i.gcEvents {
+		if event.Timestamp.After(at) || at.Sub(event.Timestamp) > alertLookback {
+			continue
+		}
+		if gc.CategorizeGCType(event.Type) != gc.GCTypeFull && event.Duration <= gc.PausePoor {
+			continue
+		}
+		if found == nil || event.Duration > found.Duration {
+			found = event
+		}
+	}
+	return found
+}
+
+func overlaps(startA, endA, startB, endB time.Time) bool {
+	return !startA.IsZero() && !startB.IsZero() && !startA.After(endB) && !startB.After(endA)
+}
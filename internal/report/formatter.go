@@ -0,0 +1,76 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+func (i *Incident) PrintSummary() {
+	fmt.Println()
+	fmt.Println("📋 JDIAG INCIDENT REPORT")
+	fmt.Println(strings.Repeat("─", 80))
+	i.printArtifacts()
+	i.printFindings()
+}
+
+// WriteJSON writes the incident as one JSON document
+func (i *Incident) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(i)
+}
+
+func (i *Incident) printArtifacts() {
+	for _, artifact := range i.Artifacts {
+		fmt.Printf("   %-14s %s\n", artifact.Source+":", filepath.Base(artifact.Path))
+		span := ""
+		if !artifact.Start.IsZero() {
+			span = fmt.Sprintf("  |  %s to %s", artifact.Start.Local().Format(time.DateTime),
+				artifact.End.Local().Format("15:04:05"))
+		}
+		fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("   %14s %s%s", "", artifact.Summary, span)))
+	}
+	for _, skipped := range i.Skipped {
+		fmt.Println(utils.MutedStyle.Render("   ⏭️  Skipped " + skipped))
+	}
+}
+
+func (i *Incident) printFindings() {
+	fmt.Println()
+	if len(i.Findings) == 0 {
+		fmt.Println(utils.GoodStyle.Render("✅ Nothing stands out in the artifacts"))
+		return
+	}
+
+	crossReferenced := 0
+	for _, finding := range i.Findings {
+		if finding.CrossReferenced() {
+			crossReferenced++
+		}
+	}
+	fmt.Printf("🚦 FINDINGS (%d critical, %d warning, %d info; %d cross-referenced)\n",
+		i.Count("critical"), i.Count("warning"), i.Count("info"), crossReferenced)
+	fmt.Println(strings.Repeat("─", 80))
+
+	for n, finding := range i.Findings {
+		link := ""
+		if finding.CrossReferenced() {
+			link = "🔗 "
+		}
+		title := fmt.Sprintf("%2d. %s [%s] %s%s", n+1, utils.GetSeverityIcon(finding.Severity),
+			strings.Join(finding.Sources, " + "), link, finding.Title)
+		fmt.Println(utils.GetSeverityStyle(finding.Severity).Render(title))
+		if finding.Detail != "" {
+			fmt.Printf("       %s\n", finding.Detail)
+		}
+		for _, action := range finding.Actions {
+			fmt.Printf("       → %s\n", action)
+		}
+	}
+}
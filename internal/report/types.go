@@ -0,0 +1,80 @@
+package report
+
+import (
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap/analyzer"
+	"github.com/mabhi256/jdiag/internal/heap/registry"
+	"github.com/mabhi256/jdiag/internal/jfr"
+	"github.com/mabhi256/jdiag/internal/watch"
+)
+
+// Sources a finding can draw on
+const (
+	SourceGCLog   = "GC log"
+	SourceJFR     = "JFR"
+	SourceHeap    = "Heap dump"
+	SourceSession = "Watch session"
+)
+
+// Inputs are the artifacts an incident left behind; any of them may be empty
+type Inputs struct {
+	GCLog   string
+	JFR     string
+	Heap    string
+	Session string // Saved with 'jdiag watch --summary <file>.json'
+}
+
+// Artifact is one input that was analyzed
+type Artifact struct {
+	Source  string    `json:"source"`
+	Path    string    `json:"path"`
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start,omitzero"` // Time span the artifact covers; zero for heap dumps
+	End     time.Time `json:"end,omitzero"`
+}
+
+// Finding is one conclusion; cross-referenced findings name every source they draw on
+type Finding struct {
+	Severity string   `json:"severity"` // "critical", "warning", "info"
+	Sources  []string `json:"sources"`
+	Title    string   `json:"title"`
+	Detail   string   `json:"detail,omitempty"`
+	Actions  []string `json:"actions,omitempty"`
+}
+
+// CrossReferenced reports whether the finding needed more than one artifact
+func (f Finding) CrossReferenced() bool {
+	return len(f.Sources) > 1
+}
+
+// Incident is the unified report; Findings is ordered most urgent first
+type Incident struct {
+	Generated time.Time  `json:"generated"`
+	Artifacts []Artifact `json:"artifacts"`
+	Skipped   []string   `json:"skipped,omitempty"` // Artifacts that couldn't be analyzed, with the reason
+	Findings  []Finding  `json:"findings"`
+
+	// Analyzer results the findings and cross-references are drawn from
+	gcSource    string // SourceGCLog, or SourceJFR when the recording stands in for a log
+	gcEvents    []*gc.GCEvent
+	gcAnalysis  *gc.GCAnalysis
+	recording   *jfr.Recording
+	allocations *jfr.AllocationProfile
+	heap        *analyzer.Analyzer
+	heapStacks  *registry.StackRegistry
+	heapCut     bool // The dump is truncated or its parse was interrupted
+	session     *watch.SessionSummary
+}
+
+// Count returns how many findings have the severity
+func (i *Incident) Count(severity string) int {
+	count := 0
+	for _, finding := range i.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Embed the live dashboard at compile time; it is plain JS so the Go build needs no extra toolchain
+//
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// dashboardHandler serves the browser counterpart of `jdiag watch`, fed by /api/watch
+func dashboardHandler() http.Handler {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // The embedded directory is fixed at compile time
+	}
+	return http.FileServerFS(files)
+}
@@ -0,0 +1,389 @@
+// Live JVM dashboard: streams LiveSnapshots from /api/watch and charts them like `jdiag watch`
+'use strict'
+
+const WINDOW_MS = 5 * 60 * 1000 // Same history window as the watch TUI
+const MAX_GC_EVENTS = 20
+
+const COLORS = {
+  good: '#228b22',
+  info: '#4682b4',
+  warning: '#ff8800',
+  critical: '#cc3333',
+  muted: '#888888',
+  grid: '#e2e8f0',
+}
+
+// The API token, when the server requires one, comes from the page URL: /#token=...
+// The fragment never reaches the server, so the token stays out of its logs
+const token = new URLSearchParams(location.hash.slice(1)).get('token') || ''
+
+let socket = null
+let samples = []
+let gcEvents = []
+
+// ==== API ====
+
+function apiURL(path, params = {}) {
+  const url = new URL(path, location.href)
+  url.hash = ''
+  for (const [key, value] of Object.entries(params)) {
+    url.searchParams.set(key, value)
+  }
+  return url
+}
+
+function apiHeaders() {
+  return token ? { Authorization: `Bearer ${token}` } : {}
+}
+
+// Browsers can't set headers on a WebSocket, so the token rides along as a subprotocol
+function websocketProtocols() {
+  if (!token) {
+    return ['jdiag']
+  }
+  const bytes = new TextEncoder().encode(token)
+  const encoded = btoa(String.fromCharCode(...bytes)).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+  return ['jdiag', `jdiag.token.${encoded}`]
+}
+
+async function loadProcesses() {
+  const select = document.getElementById('process-select')
+  try {
+    const response = await fetch(apiURL('/api/processes'), { headers: apiHeaders() })
+    const processes = await response.json()
+    if (!response.ok) {
+      throw new Error(processes.error)
+    }
+    for (const process of processes) {
+      const option = document.createElement('option')
+      option.value = String(process.pid)
+      option.textContent = `${process.pid} ${process.mainClass}`
+      select.appendChild(option)
+    }
+  } catch (err) {
+    select.firstElementChild.textContent = 'No local processes (enter a target)'
+  }
+}
+
+function connect(target, interval) {
+  if (socket) {
+    socket.onclose = null
+    socket.close()
+  }
+  samples = []
+  gcEvents = []
+  setStatus('disconnected', `Connecting to ${target}…`)
+
+  const url = apiURL('/api/watch', { target, interval })
+  url.protocol = location.protocol === 'https:' ? 'wss:' : 'ws:'
+  socket = new WebSocket(url, websocketProtocols())
+  socket.onmessage = (message) => addSample(JSON.parse(message.data))
+  socket.onclose = () => setStatus('disconnected', `Disconnected from ${target}`)
+  socket.onerror = () => setStatus('error', `Connection to ${target} failed`)
+}
+
+function setStatus(kind, text) {
+  const status = document.getElementById('status')
+  status.className = `status ${kind}`
+  status.textContent = text
+}
+
+// ==== SAMPLES ====
+
+function addSample(sample) {
+  if (!sample.connected) {
+    setStatus('error', sample.error || `Not connected to ${sample.target}`)
+    return
+  }
+  setStatus('connected', `Connected to ${sample.target}`)
+
+  sample.time = new Date(sample.timestamp).getTime()
+  const previous = samples[samples.length - 1]
+  if (previous) {
+    sample.youngDeltaMs = Math.max(sample.youngGcTimeMs - previous.youngGcTimeMs, 0)
+    sample.oldDeltaMs = Math.max(sample.oldGcTimeMs - previous.oldGcTimeMs, 0)
+    trackCollections(previous, sample)
+  } else {
+    sample.youngDeltaMs = 0
+    sample.oldDeltaMs = 0
+  }
+
+  samples.push(sample)
+  while (samples.length > 0 && samples[0].time < sample.time - WINDOW_MS) {
+    samples.shift()
+  }
+  render()
+}
+
+// trackCollections infers collections from counter deltas; JMX reports totals, not individual pauses
+function trackCollections(previous, sample) {
+  const generations = [
+    ['Young', sample.youngGcCount - previous.youngGcCount, sample.youngDeltaMs],
+    ['Old', sample.oldGcCount - previous.oldGcCount, sample.oldDeltaMs],
+  ]
+  for (const [generation, count, timeMs] of generations) {
+    if (count > 0) {
+      gcEvents.unshift({ time: sample.time, generation, count, avgMs: timeMs / count })
+    }
+  }
+  gcEvents.length = Math.min(gcEvents.length, MAX_GC_EVENTS)
+}
+
+// ==== RENDERING ====
+
+function render() {
+  const latest = samples[samples.length - 1]
+
+  drawChart('heap-chart', [
+    { color: COLORS.good, value: (s) => s.heap.used },
+    { color: COLORS.info, value: (s) => s.heap.committed },
+  ], formatBytes)
+  renderPools(latest)
+
+  drawChart('gc-chart', [
+    { color: COLORS.good, value: (s) => s.youngDeltaMs },
+    { color: COLORS.warning, value: (s) => s.oldDeltaMs },
+  ], (v) => `${v.toFixed(0)}ms`)
+  renderGCSummary(latest)
+  renderGCEvents()
+
+  drawChart('threads-chart', [
+    { color: COLORS.info, value: (s) => s.threads },
+    { color: COLORS.muted, value: (s) => s.daemonThreads },
+  ], (v) => v.toFixed(0))
+  renderThreads(latest)
+
+  drawChart('cpu-chart', [
+    { color: COLORS.warning, value: (s) => s.processCpu * 100 },
+    { color: COLORS.info, value: (s) => s.systemCpu * 100 },
+  ], (v) => `${v.toFixed(0)}%`, 100)
+  renderSystem(latest)
+}
+
+// drawChart plots one line per series over the history window
+function drawChart(id, series, format, fixedMax) {
+  const canvas = document.getElementById(id)
+  if (canvas.offsetParent === null) {
+    return // Hidden tab; redrawn when shown
+  }
+
+  const ratio = window.devicePixelRatio || 1
+  const width = canvas.clientWidth
+  const height = canvas.clientHeight
+  canvas.width = width * ratio
+  canvas.height = height * ratio
+  const ctx = canvas.getContext('2d')
+  ctx.scale(ratio, ratio)
+  ctx.clearRect(0, 0, width, height)
+
+  const left = 70
+  const bottom = 20
+  const plotWidth = width - left - 10
+  const plotHeight = height - bottom - 10
+  const end = samples.length > 0 ? samples[samples.length - 1].time : Date.now()
+  const start = end - WINDOW_MS
+
+  let maxValue = fixedMax || 0
+  if (!fixedMax) {
+    for (const s of samples) {
+      for (const line of series) {
+        maxValue = Math.max(maxValue, line.value(s))
+      }
+    }
+  }
+  maxValue = maxValue * 1.1 || 1
+
+  const x = (time) => left + ((time - start) / WINDOW_MS) * plotWidth
+  const y = (value) => 10 + plotHeight - (value / maxValue) * plotHeight
+
+  // Grid and axis labels
+  ctx.strokeStyle = COLORS.grid
+  ctx.fillStyle = COLORS.muted
+  ctx.font = '11px sans-serif'
+  ctx.lineWidth = 1
+  for (let i = 0; i <= 4; i++) {
+    const value = (maxValue * i) / 4
+    ctx.beginPath()
+    ctx.moveTo(left, y(value))
+    ctx.lineTo(left + plotWidth, y(value))
+    ctx.stroke()
+    ctx.fillText(format(value), 4, y(value) + 4)
+  }
+  for (let minutes = 5; minutes >= 0; minutes--) {
+    const time = end - minutes * 60 * 1000
+    ctx.fillText(minutes === 0 ? 'now' : `-${minutes}m`, x(time) - 10, height - 4)
+  }
+
+  ctx.lineWidth = 2
+  for (const line of series) {
+    ctx.strokeStyle = line.color
+    ctx.beginPath()
+    samples.forEach((s, i) => {
+      const method = i === 0 ? 'moveTo' : 'lineTo'
+      ctx[method](x(s.time), y(line.value(s)))
+    })
+    ctx.stroke()
+  }
+}
+
+function renderPools(latest) {
+  const pools = [
+    ['Heap', latest.heap],
+    ['Eden', latest.eden],
+    ['Survivor', latest.survivor],
+    ['Old Gen', latest.old],
+    ['Metaspace', latest.metaspace],
+    ['Non-heap', latest.nonHeap],
+  ]
+  document.getElementById('memory-pools').innerHTML = pools
+    .filter(([, usage]) => usage.committed > 0 || usage.used > 0)
+    .map(([name, usage]) => {
+      const limit = usage.max > 0 ? usage.max : usage.committed
+      const share = limit > 0 ? usage.used / limit : 0
+      const limitLabel = usage.max > 0 ? formatBytes(usage.max) : `${formatBytes(usage.committed)} committed`
+      return `<div class="pool">
+        <span>${name}</span>
+        <div class="bar"><div class="fill ${severity(share, 0.75, 0.9)}" style="width:${(share * 100).toFixed(1)}%"></div></div>
+        <span class="detail">${formatBytes(usage.used)} / ${limitLabel}</span>
+      </div>`
+    })
+    .join('')
+}
+
+function renderGCSummary(latest) {
+  const first = samples[0]
+  const windowMs = latest.time - first.time
+  const windowGCTime = latest.youngGcTimeMs + latest.oldGcTimeMs - first.youngGcTimeMs - first.oldGcTimeMs
+  const windowCount = latest.youngGcCount + latest.oldGcCount - first.youngGcCount - first.oldGcCount
+  const overhead = windowMs > 0 ? windowGCTime / windowMs : 0
+
+  renderMetrics('gc-summary', [
+    ['Young GCs', latest.youngGcCount, `${formatMs(latest.youngGcTimeMs)} total`],
+    ['Old GCs', latest.oldGcCount, `${formatMs(latest.oldGcTimeMs)} total`],
+    ['Frequency', windowMs > 0 ? `${((windowCount / windowMs) * 60000).toFixed(1)}/min` : '-', 'last 5 min'],
+    ['GC overhead', `${(overhead * 100).toFixed(2)}%`, 'last 5 min', severity(overhead, 0.05, 0.1)],
+  ])
+}
+
+function renderGCEvents() {
+  const container = document.getElementById('gc-events')
+  if (gcEvents.length === 0) {
+    container.innerHTML = '<span class="muted">No collections since connecting</span>'
+    return
+  }
+  const rows = gcEvents
+    .map((event) => {
+      const pause = severity(event.avgMs, 100, 500)
+      return `<tr>
+        <td>${new Date(event.time).toLocaleTimeString()}</td>
+        <td>${event.generation === 'Young' ? '🐣' : '👵'} ${event.generation}</td>
+        <td>${event.count}</td>
+        <td class="pause ${pause}">${formatMs(event.avgMs)}</td>
+      </tr>`
+    })
+    .join('')
+  container.innerHTML = `<table><tr><th>Time</th><th>Generation</th><th>Collections</th><th>Avg pause</th></tr>${rows}</table>`
+}
+
+function renderThreads(latest) {
+  const deadlocked = latest.deadlocked || []
+  renderMetrics('threads-summary', [
+    ['Live', latest.threads, ''],
+    ['Peak', latest.peakThreads, ''],
+    ['Daemon', latest.daemonThreads, ''],
+    ['Deadlocked', deadlocked.length, deadlocked.join(', '), deadlocked.length > 0 ? 'critical' : ''],
+  ])
+}
+
+function renderSystem(latest) {
+  renderMetrics('system-summary', [
+    ['Process CPU', `${(latest.processCpu * 100).toFixed(1)}%`, '', severity(latest.processCpu, 0.7, 0.9)],
+    ['System CPU', `${(latest.systemCpu * 100).toFixed(1)}%`, '', severity(latest.systemCpu, 0.7, 0.9)],
+    ['Loaded classes', latest.loadedClasses, `${latest.unloadedClasses} unloaded`],
+    ['Uptime', formatMs(latest.uptimeMs), ''],
+  ])
+}
+
+function renderMetrics(id, metrics) {
+  document.getElementById(id).innerHTML =
+    '<div class="metrics">' +
+    metrics
+      .map(([label, value, detail, level = '']) => `<div class="metric">
+        <div class="label">${label}</div>
+        <div class="value ${level}">${value}</div>
+        <div class="muted">${escapeHTML(detail)}</div>
+      </div>`)
+      .join('') +
+    '</div>'
+}
+
+// ==== FORMATTING ====
+
+function severity(value, warning, critical) {
+  if (value >= critical) return 'critical'
+  if (value >= warning) return 'warning'
+  return 'good'
+}
+
+function formatBytes(bytes) {
+  const units = ['B', 'K', 'M', 'G', 'T']
+  let value = bytes
+  let unit = 0
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024
+    unit++
+  }
+  return `${value.toFixed(unit === 0 ? 0 : 1)}${units[unit]}`
+}
+
+function formatMs(ms) {
+  if (ms < 1000) return `${ms.toFixed(0)}ms`
+  if (ms < 60000) return `${(ms / 1000).toFixed(1)}s`
+  if (ms < 3600000) return `${(ms / 60000).toFixed(1)}m`
+  return `${(ms / 3600000).toFixed(1)}h`
+}
+
+function escapeHTML(text) {
+  const div = document.createElement('div')
+  div.textContent = String(text)
+  return div.innerHTML
+}
+
+// ==== SETUP ====
+
+document.querySelectorAll('.tab-btn').forEach((button) => {
+  button.addEventListener('click', () => {
+    document.querySelectorAll('.tab-btn, .tab-content').forEach((el) => el.classList.remove('active'))
+    button.classList.add('active')
+    document.getElementById(button.dataset.tab).classList.add('active')
+    if (samples.length > 0) {
+      render()
+    }
+  })
+})
+
+document.getElementById('process-select').addEventListener('change', (event) => {
+  document.getElementById('target-input').value = event.target.value
+})
+
+document.getElementById('connect-form').addEventListener('submit', (event) => {
+  event.preventDefault()
+  const target = document.getElementById('target-input').value.trim()
+  if (target) {
+    connect(target, document.getElementById('interval-select').value)
+    const url = apiURL('/', { target })
+    url.hash = location.hash
+    history.replaceState(null, '', url)
+  }
+})
+
+window.addEventListener('resize', () => samples.length > 0 && render())
+
+loadProcesses()
+
+// Reconnect to ?target= so dashboard links can be shared during an incident
+const initialTarget = new URLSearchParams(location.search).get('target')
+if (initialTarget) {
+  document.getElementById('target-input').value = initialTarget
+  connect(initialTarget, document.getElementById('interval-select').value)
+}
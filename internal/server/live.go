@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/jmx"
+)
+
+const (
+	defaultWatchInterval = 1000 // ms, as `jdiag watch`
+	minWatchInterval     = 250
+)
+
+// LiveSnapshot is one sample of a monitored JVM, as streamed over /api/watch; sizes are in bytes
+type LiveSnapshot struct {
+	Timestamp time.Time `json:"timestamp"`
+	Target    string    `json:"target"`
+	Connected bool      `json:"connected"`
+	Error     string    `json:"error,omitempty"`
+
+	Heap      LiveMemory `json:"heap"`
+	NonHeap   LiveMemory `json:"nonHeap"`
+	Eden      LiveMemory `json:"eden"`
+	Survivor  LiveMemory `json:"survivor"`
+	Old       LiveMemory `json:"old"`
+	Metaspace LiveMemory `json:"metaspace"`
+
+	YoungGCCount  int64 `json:"youngGcCount"`
+	YoungGCTimeMs int64 `json:"youngGcTimeMs"`
+	OldGCCount    int64 `json:"oldGcCount"`
+	OldGCTimeMs   int64 `json:"oldGcTimeMs"`
+
+	Threads       int64    `json:"threads"`
+	PeakThreads   int64    `json:"peakThreads"`
+	DaemonThreads int64    `json:"daemonThreads"`
+	Deadlocked    []string `json:"deadlocked,omitempty"`
+
+	ProcessCPU      float64 `json:"processCpu"` // 0-1
+	SystemCPU       float64 `json:"systemCpu"`
+	LoadedClasses   int64   `json:"loadedClasses"`
+	UnloadedClasses int64   `json:"unloadedClasses"`
+	UptimeMs        int64   `json:"uptimeMs"`
+}
+
+type LiveMemory struct {
+	Used      int64 `json:"used"`
+	Committed int64 `json:"committed"`
+	Max       int64 `json:"max"`
+}
+
+func NewLiveSnapshot(target string, snapshot *jmx.MBeanSnapshot) *LiveSnapshot {
+	live := &LiveSnapshot{
+		Timestamp: snapshot.Timestamp,
+		Target:    target,
+		Connected: snapshot.Connected,
+	}
+	if snapshot.Error != nil {
+		live.Error = snapshot.Error.Error()
+	}
+	if !snapshot.Connected {
+		return live
+	}
+
+	memory := snapshot.Memory
+	live.Heap = newLiveMemory(memory.Heap)
+	live.NonHeap = newLiveMemory(memory.NonHeap)
+	live.Eden = newLiveMemory(memory.G1Eden.Usage)
+	live.Survivor = newLiveMemory(memory.G1Survivor.Usage)
+	live.Old = newLiveMemory(memory.G1OldGen.Usage)
+	live.Metaspace = newLiveMemory(memory.Metaspace.Usage)
+
+	live.YoungGCCount = snapshot.GC.YoungGCCount
+	live.YoungGCTimeMs = snapshot.GC.YoungGCTime
+	live.OldGCCount = snapshot.GC.OldGCCount
+	live.OldGCTimeMs = snapshot.GC.OldGCTime
+
+	live.Threads = snapshot.Threading.Count
+	live.PeakThreads = snapshot.Threading.PeakCount
+	live.DaemonThreads = snapshot.Threading.DaemonCount
+	live.Deadlocked = snapshot.Threading.DeadlockedThreads
+
+	live.ProcessCPU = max(snapshot.OS.ProcessCpuLoad, 0)
+	live.SystemCPU = max(snapshot.OS.SystemCpuLoad, 0)
+	live.LoadedClasses = snapshot.ClassLoading.LoadedClassCount
+	live.UnloadedClasses = snapshot.ClassLoading.UnloadedClassCount
+	live.UptimeMs = snapshot.Runtime.Uptime.Milliseconds()
+	return live
+}
+
+func newLiveMemory(usage jmx.MemoryUsage) LiveMemory {
+	return LiveMemory{Used: usage.Used, Committed: usage.Committed, Max: usage.Max}
+}
+
+// handleWatch streams LiveSnapshots of ?target=PID|host:port over a WebSocket, one per ?interval= ms
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	config, err := parseTarget(r.URL.Query().Get("target"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	config.Interval = defaultWatchInterval
+	if value := r.URL.Query().Get("interval"); value != "" {
+		interval, err := strconv.Atoi(value)
+		if err != nil || interval < minWatchInterval {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid interval '%s': must be at least %d ms", value,
+				minWatchInterval))
+			return
+		}
+		config.Interval = interval
+	}
+
+	ws, err := upgradeWebsocket(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer ws.Close()
+
+	collector := jmx.NewJMXCollector(config)
+	if err := collector.Start(); err != nil {
+		message, _ := json.Marshal(&LiveSnapshot{Timestamp: time.Now(), Target: config.String(), Error: err.Error()})
+		ws.WriteText(message)
+		return
+	}
+	defer collector.Stop()
+
+	ticker := time.NewTicker(config.GetInterval())
+	defer ticker.Stop()
+
+	var last time.Time
+	for {
+		select {
+		case <-ws.Done():
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		// The collector samples on its own ticker; only send samples not sent yet
+		snapshot := collector.GetMetrics()
+		if !snapshot.Timestamp.After(last) {
+			continue
+		}
+		last = snapshot.Timestamp
+
+		message, err := json.Marshal(NewLiveSnapshot(config.String(), snapshot))
+		if err != nil {
+			return
+		}
+		if err := ws.WriteText(message); err != nil {
+			return
+		}
+	}
+}
+
+func parseTarget(target string) (*jmx.Config, error) {
+	if target == "" {
+		return nil, fmt.Errorf("missing target: use ?target=PID or ?target=host:port")
+	}
+	if pid, err := strconv.Atoi(target); err == nil && pid > 0 {
+		return &jmx.Config{PID: pid}, nil
+	}
+	host, portValue, err := net.SplitHostPort(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target '%s': must be PID or host:port", target)
+	}
+	port, err := strconv.Atoi(portValue)
+	if err != nil || port <= 0 {
+		return nil, fmt.Errorf("invalid port in target '%s'", target)
+	}
+	return &jmx.Config{Host: host, Port: port}, nil
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/heap/export"
+	"github.com/mabhi256/jdiag/internal/jfr"
+)
+
+const (
+	KindGC   = "gc"
+	KindHeap = "heap"
+
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+
+	defaultHeapObjects = 1000
+)
+
+// Report is one uploaded file and its analysis; Result is a gc.Report or export.HeapExport once done
+type Report struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	File      string    `json:"file"`
+	Size      int64     `json:"size"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Warnings  []string  `json:"warnings,omitempty"` // Problems that didn't stop the analysis
+	Created   time.Time `json:"created"`
+	ElapsedMs float64   `json:"elapsedMs,omitempty"`
+	Result    any       `json:"result,omitempty"`
+
+	path   string
+	events []gc.ReportEvent
+}
+
+type reportStore struct {
+	mu      sync.RWMutex
+	reports map[string]*Report
+}
+
+func newReportStore() *reportStore {
+	return &reportStore{reports: make(map[string]*Report)}
+}
+
+func (rs *reportStore) add(report *Report) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.reports[report.ID] = report
+}
+
+// get returns a copy, so handlers can encode it while the job updates the original
+func (rs *reportStore) get(id string) (Report, bool) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	report, ok := rs.reports[id]
+	if !ok {
+		return Report{}, false
+	}
+	return *report, true
+}
+
+func (rs *reportStore) update(id string, apply func(*Report)) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if report, ok := rs.reports[id]; ok {
+		apply(report)
+	}
+}
+
+func (rs *reportStore) remove(id string) (Report, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	report, ok := rs.reports[id]
+	if !ok {
+		return Report{}, false
+	}
+	delete(rs.reports, id)
+	return *report, true
+}
+
+// list returns the reports newest first, without their results
+func (rs *reportStore) list() []Report {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	reports := make([]Report, 0, len(rs.reports))
+	for _, report := range rs.reports {
+		summary := *report
+		summary.Result = nil
+		reports = append(reports, summary)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Created.After(reports[j].Created)
+	})
+	return reports
+}
+
+func (rs *reportStore) count() int {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return len(rs.reports)
+}
+
+// handleAnalyze accepts a multipart "file" field or a raw body named by ?name=, and queues its analysis
+func (s *Server) handleAnalyze(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options := export.Options{MaxObjects: defaultHeapObjects}
+		if value := r.URL.Query().Get("max_objects"); value != "" {
+			objects, err := strconv.Atoi(value)
+			if err != nil || objects < 0 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid max_objects '%s'", value))
+				return
+			}
+			options.MaxObjects = objects
+		}
+
+		id, err := newReportID()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxUpload.Bytes())
+		path, size, err := s.saveUpload(r, id)
+		if err != nil {
+			os.RemoveAll(filepath.Join(s.config.DataDir, id))
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		report := &Report{
+			ID:      id,
+			Kind:    kind,
+			File:    filepath.Base(path),
+			Size:    size,
+			Status:  StatusQueued,
+			Created: time.Now(),
+			path:    path,
+		}
+		s.reports.add(report)
+		go s.runAnalysis(id, kind, path, options)
+
+		w.Header().Set("Location", "/api/reports/"+id)
+		writeJSON(w, http.StatusAccepted, map[string]string{
+			"id":     id,
+			"status": StatusQueued,
+			"report": "/api/reports/" + id,
+		})
+	}
+}
+
+// saveUpload stores the upload under DataDir/<id>/, keeping its name so .jfr and .gz detection still work
+func (s *Server) saveUpload(r *http.Request, id string) (string, int64, error) {
+	var source io.Reader
+	var name string
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid multipart upload: %w", err)
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return "", 0, fmt.Errorf("multipart upload has no 'file' field")
+			}
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid multipart upload: %w", err)
+			}
+			if part.FormName() == "file" {
+				source, name = part, part.FileName()
+				break
+			}
+		}
+	} else {
+		source, name = r.Body, r.URL.Query().Get("name")
+	}
+
+	name = filepath.Base(name)
+	if name == "." || name == "/" || name == "" {
+		name = "upload"
+	}
+
+	dir := filepath.Join(s.config.DataDir, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, source)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if size == 0 {
+		return "", 0, fmt.Errorf("upload is empty")
+	}
+	return path, size, nil
+}
+
+func (s *Server) runAnalysis(id, kind, path string, options export.Options) {
+	select {
+	case s.jobs <- struct{}{}:
+		defer func() { <-s.jobs }()
+	case <-s.ctx.Done():
+		s.reports.update(id, func(report *Report) {
+			report.Status = StatusFailed
+			report.Error = "server shut down before the analysis started"
+		})
+		return
+	}
+
+	start := time.Now()
+	s.reports.update(id, func(report *Report) {
+		report.Status = StatusRunning
+	})
+
+	var result any
+	var events []gc.ReportEvent
+	var warnings []string
+	var err error
+	switch kind {
+	case KindGC:
+		result, events, err = analyzeGC(path)
+	case KindHeap:
+		config := *s.config.Heap
+		config.OnWarning = func(warning error) { warnings = append(warnings, warning.Error()) }
+		result, err = heap.BuildExportContext(s.ctx, path, &config, options)
+	}
+
+	s.reports.update(id, func(report *Report) {
+		report.ElapsedMs = float64(time.Since(start)) / float64(time.Millisecond)
+		report.Warnings = warnings
+		if err != nil {
+			report.Status = StatusFailed
+			report.Error = err.Error()
+			return
+		}
+		report.Status = StatusDone
+		report.Result = result
+		report.events = events
+	})
+}
+
+func analyzeGC(path string) (*gc.Report, []gc.ReportEvent, error) {
+	events, analysis, _, err := jfr.ParseGCEvents(path, gc.NewParser(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil, fmt.Errorf("no GC events found in %s", filepath.Base(path))
+	}
+	return gc.NewReport(analysis, gc.GetRecommendations(analysis)), gc.NewReportEvents(events), nil
+}
+
+func (s *Server) handleListReports(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.reports.list())
+}
+
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	report, ok := s.reports.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("report '%s' not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *Server) handleGetEvents(w http.ResponseWriter, r *http.Request) {
+	report, ok := s.reports.get(r.PathValue("id"))
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, fmt.Errorf("report '%s' not found", r.PathValue("id")))
+	case report.Kind != KindGC:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("report '%s' is not a GC analysis", report.ID))
+	case report.Status != StatusDone:
+		writeError(w, http.StatusConflict, fmt.Errorf("report '%s' is %s", report.ID, report.Status))
+	default:
+		writeJSON(w, http.StatusOK, report.events)
+	}
+}
+
+func (s *Server) handleDeleteReport(w http.ResponseWriter, r *http.Request) {
+	report, ok := s.reports.remove(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("report '%s' not found", r.PathValue("id")))
+		return
+	}
+	// A running analysis keeps its open file; Unix lets it finish
+	if err := os.RemoveAll(filepath.Dir(report.path)); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to remove upload: %w", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newReportID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate report id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/heap"
+	"github.com/mabhi256/jdiag/internal/watch"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	DefaultListen    = "127.0.0.1:8080"
+	DefaultMaxUpload = 4 * utils.GB
+	DefaultJobs      = 2
+
+	shutdownTimeout = 10 * time.Second
+)
+
+// Config holds the options of `jdiag serve`
+type Config struct {
+	Listen    string
+	DataDir   string           // Uploaded files, one directory per report (default: $TMPDIR/jdiag-serve)
+	MaxUpload utils.MemorySize // Largest accepted upload
+	Token     string           // Bearer token required on /api requests; empty disables auth, which only loopback addresses allow
+	Jobs      int              // Analyses that run at the same time; the rest wait queued
+	Heap      *heap.Config
+}
+
+type Server struct {
+	config  *Config
+	reports *reportStore
+	jobs    chan struct{} // Semaphore bounding concurrent analyses
+	mux     *http.ServeMux
+
+	ctx    context.Context // Cancelled when Run returns, abandoning queued and running analyses
+	cancel context.CancelFunc
+}
+
+func NewServer(config *Config) (*Server, error) {
+	if config.Listen == "" {
+		config.Listen = DefaultListen
+	}
+	if config.DataDir == "" {
+		config.DataDir = filepath.Join(os.TempDir(), "jdiag-serve")
+	}
+	if config.MaxUpload <= 0 {
+		config.MaxUpload = DefaultMaxUpload
+	}
+	if config.Jobs <= 0 {
+		config.Jobs = DefaultJobs
+	}
+	if config.Heap == nil {
+		config.Heap = &heap.Config{}
+	}
+	if config.Token == "" && !isLoopback(config.Listen) {
+		return nil, fmt.Errorf("listening on %s exposes the API beyond this host; set a token or listen on a loopback address such as %s", config.Listen, DefaultListen)
+	}
+	if err := os.MkdirAll(config.DataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	s := &Server{
+		config:  config,
+		reports: newReportStore(),
+		jobs:    make(chan struct{}, config.Jobs),
+		mux:     http.NewServeMux(),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.routes()
+	return s, nil
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/health", s.handleHealth)
+	s.mux.HandleFunc("GET /api/processes", s.handleProcesses)
+
+	s.mux.HandleFunc("POST /api/gc/analyze", s.handleAnalyze(KindGC))
+	s.mux.HandleFunc("POST /api/heap/analyze", s.handleAnalyze(KindHeap))
+
+	s.mux.HandleFunc("GET /api/reports", s.handleListReports)
+	s.mux.HandleFunc("GET /api/reports/{id}", s.handleGetReport)
+	s.mux.HandleFunc("GET /api/reports/{id}/events", s.handleGetEvents)
+	s.mux.HandleFunc("DELETE /api/reports/{id}", s.handleDeleteReport)
+
+	s.mux.HandleFunc("GET /api/watch", s.handleWatch)
+
+	s.mux.Handle("GET /", dashboardHandler())
+}
+
+func (s *Server) DataDir() string {
+	return s.config.DataDir
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.authenticate(s.mux)
+}
+
+// Run serves until ctx is cancelled, then lets in-flight requests finish
+func (s *Server) Run(ctx context.Context) error {
+	defer s.cancel()
+
+	httpServer := &http.Server{
+		Addr:              s.config.Listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
+
+/*
+ * The token travels only in headers, since query strings end up in proxy
+ * and access logs. Browsers can't set headers on a WebSocket, but they can
+ * offer subprotocols, so /api/watch also takes it as the subprotocol
+ * "jdiag.token.<base64url token>" next to "jdiag", which the handshake
+ * echoes back.
+ */
+
+const (
+	websocketProtocol      = "jdiag"
+	websocketTokenProtocol = "jdiag.token."
+)
+
+/*
+ * Without a token the loopback listener is the only protection, and a web
+ * page can get around it: DNS rebinding points the page's own name at
+ * 127.0.0.1, so its requests reach the server as same-origin ones carrying
+ * that name as Host. Tokenless servers therefore only answer requests for a
+ * loopback Host. A page can also send a simple cross-origin POST without
+ * reading the answer, which would still start an analysis, so requests that
+ * change state must not come from a foreign Origin.
+ */
+
+// authenticate checks the Host, the Origin of state-changing requests and the bearer token on API requests
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.Token == "" && !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not a loopback address; set a token to serve other hosts", r.Host))
+			return
+		}
+		if !isSafeMethod(r.Method) && !sameOrigin(r) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("cross-origin %s requests are not allowed", r.Method))
+			return
+		}
+		if s.config.Token == "" || !strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/api/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = websocketToken(r)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// websocketToken is the token offered as a Sec-WebSocket-Protocol value, or "" without one
+func websocketToken(r *http.Request) string {
+	for _, protocol := range headerTokens(r.Header, "Sec-WebSocket-Protocol") {
+		if encoded, ok := strings.CutPrefix(protocol, websocketTokenProtocol); ok {
+			token, err := base64.RawURLEncoding.DecodeString(encoded)
+			if err != nil {
+				return ""
+			}
+			return string(token)
+		}
+	}
+	return ""
+}
+
+// isLoopback reports whether a listen address only accepts connections from this host
+func isLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	return err == nil && isLoopbackName(host)
+}
+
+// isLoopbackHost reports whether a request's Host, with or without a port, names this host
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	return isLoopbackName(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+}
+
+func isLoopbackName(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isSafeMethod reports whether a request method only reads
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":  "ok",
+		"reports": s.reports.count(),
+	})
+}
+
+func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	processes, err := watch.DiscoverJavaProcesses()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if processes == nil {
+		processes = []*watch.JavaProcess{}
+	}
+	writeJSON(w, http.StatusOK, processes)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
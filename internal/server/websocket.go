@@ -0,0 +1,222 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+ * Just enough of RFC 6455 to push JSON to browsers and scripts: the server
+ * sends unfragmented text frames and only reads client frames to answer
+ * pings and notice the connection closing. Client messages are otherwise
+ * ignored.
+ */
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+
+	maxControlPayload = 125
+	writeTimeout      = 10 * time.Second
+)
+
+type websocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu     sync.Mutex // Serializes frame writes
+	closed chan struct{}
+	once   sync.Once
+}
+
+// upgradeWebsocket completes the opening handshake and takes over the connection
+func upgradeWebsocket(w http.ResponseWriter, r *http.Request) (*websocketConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, fmt.Errorf("not a websocket upgrade request")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("unsupported websocket handshake")
+	}
+	// WebSockets are exempt from the same-origin policy, so any page a user opens could otherwise connect
+	if !sameOrigin(r) {
+		return nil, fmt.Errorf("websocket origin '%s' does not match host '%s'", r.Header.Get("Origin"), r.Host)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection does not support hijacking")
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n"
+	if slices.Contains(headerTokens(r.Header, "Sec-WebSocket-Protocol"), websocketProtocol) {
+		response += "Sec-WebSocket-Protocol: " + websocketProtocol + "\r\n"
+	}
+	response += "\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write handshake: %w", err)
+	}
+
+	ws := &websocketConn{conn: conn, reader: buffered.Reader, closed: make(chan struct{})}
+	go ws.readLoop()
+	return ws, nil
+}
+
+// sameOrigin reports whether a browser's Origin names the host it connected to; clients other than browsers send none
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host)
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	return slices.ContainsFunc(headerTokens(header, name), func(value string) bool {
+		return strings.EqualFold(value, token)
+	})
+}
+
+// headerTokens splits the comma-separated values of a header
+func headerTokens(header http.Header, name string) []string {
+	var tokens []string
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			tokens = append(tokens, strings.TrimSpace(part))
+		}
+	}
+	return tokens
+}
+
+// WriteText sends one text message
+func (ws *websocketConn) WriteText(payload []byte) error {
+	return ws.writeFrame(opText, payload)
+}
+
+func (ws *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	header := []byte{0x80 | opcode} // FIN set: messages are never fragmented
+	switch length := len(payload); {
+	case length <= maxControlPayload:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	ws.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := ws.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("failed to write websocket frame: %w", err)
+	}
+	return nil
+}
+
+// Done is closed once the client disconnects or sends a close frame
+func (ws *websocketConn) Done() <-chan struct{} {
+	return ws.closed
+}
+
+func (ws *websocketConn) Close() error {
+	ws.once.Do(func() {
+		ws.writeFrame(opClose, nil)
+		close(ws.closed)
+	})
+	return ws.conn.Close()
+}
+
+func (ws *websocketConn) readLoop() {
+	defer ws.Close()
+
+	for {
+		opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opClose:
+			return
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (ws *websocketConn) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > 1<<20 {
+		return 0, nil, fmt.Errorf("websocket frame of %d bytes is too large", length)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
@@ -0,0 +1,342 @@
+package thread
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	MinGroupSize     = 2  // Threads sharing a stack before they're reported as a group
+	HotLockThreshold = 3  // Waiters on one lock before it's flagged as contended
+	MaxReportedItems = 10 // Groups and locks printed in the CLI summary
+)
+
+// Packages treated as library code when picking the frame that best describes a stack
+var libraryPackages = []string{"java.", "javax.", "jdk.", "sun.", "com.sun."}
+
+// Frames that only implement waiting; a JDK-only stack is described by the frame below them
+var waitPrefixes = []string{"jdk.internal.misc.Unsafe.", "sun.misc.Unsafe.", "java.util.concurrent.locks.",
+	"java.lang.Object.wait", "java.lang.Thread.sleep"}
+
+var threadNumberRegex = regexp.MustCompile(`\d+`)
+
+// ThreadAnalysis is everything derived from a single thread dump
+type ThreadAnalysis struct {
+	Dump *ThreadDump
+
+	StateCounts map[ThreadState]int
+	JavaThreads int
+	Daemons     int
+
+	Locks     map[string]*LockInfo // By lock address
+	Contended []*LockInfo          // Owned locks with waiters, most waiters first
+	Deadlocks []*Deadlock
+	Groups    []*StackGroup // Identical stacks shared by MinGroupSize+ threads, largest first
+}
+
+// LockInfo is a lock's owner and the threads waiting to acquire it
+type LockInfo struct {
+	Lock    *Lock
+	Owner   *Thread // nil if no thread in the dump holds it
+	Waiters []*Thread
+}
+
+// Deadlock is a cycle of threads: Threads[i] waits for Locks[i], held by Threads[i+1]
+type Deadlock struct {
+	Threads []*Thread
+	Locks   []*Lock
+}
+
+// StackGroup is a set of threads with the same state and the same stack
+type StackGroup struct {
+	Threads     []*Thread
+	State       string // Display state shared by the group, e.g. "WAITING (parking)"
+	Frames      []*StackFrame
+	NamePattern string // e.g. "pool-1-thread-*"
+	SharedLock  *Lock  // Lock every thread in the group is waiting for, if they all wait for the same one
+}
+
+// Analyze builds the state distribution, lock graph, deadlocks and stack groups of a dump
+func Analyze(dump *ThreadDump) *ThreadAnalysis {
+	analysis := &ThreadAnalysis{
+		Dump:        dump,
+		StateCounts: make(map[ThreadState]int),
+		Locks:       make(map[string]*LockInfo),
+	}
+
+	for _, thread := range dump.Threads {
+		analysis.StateCounts[thread.State]++
+		if thread.IsJava() {
+			analysis.JavaThreads++
+		}
+		if thread.Daemon {
+			analysis.Daemons++
+		}
+	}
+
+	analysis.buildLockGraph()
+	analysis.findDeadlocks()
+	analysis.groupStacks()
+
+	return analysis
+}
+
+// OwnerOf returns the thread holding a lock, or nil
+func (a *ThreadAnalysis) OwnerOf(lock *Lock) *Thread {
+	if lock == nil {
+		return nil
+	}
+	if info, ok := a.Locks[lock.Address]; ok {
+		return info.Owner
+	}
+	return nil
+}
+
+// HeldLocks returns the locks a thread currently owns
+func HeldLocks(thread *Thread) []*Lock {
+	var held []*Lock
+	seen := make(map[string]bool)
+
+	add := func(lock *Lock) {
+		// Object.wait() releases the monitor, though its frame still prints "- locked"
+		if seen[lock.Address] || (thread.WaitingOn != nil && thread.WaitingOn.Address == lock.Address) {
+			return
+		}
+		seen[lock.Address] = true
+		held = append(held, lock)
+	}
+
+	for _, frame := range thread.Frames {
+		for _, lock := range frame.Locked {
+			add(lock)
+		}
+	}
+	for _, lock := range thread.Synchronizers {
+		add(lock)
+	}
+	return held
+}
+
+func (a *ThreadAnalysis) lockInfo(lock *Lock) *LockInfo {
+	info, ok := a.Locks[lock.Address]
+	if !ok {
+		info = &LockInfo{Lock: lock}
+		a.Locks[lock.Address] = info
+	}
+	if info.Lock.ClassName == "" {
+		info.Lock.ClassName = lock.ClassName
+	}
+	return info
+}
+
+func (a *ThreadAnalysis) buildLockGraph() {
+	for _, thread := range a.Dump.Threads {
+		for _, lock := range HeldLocks(thread) {
+			a.lockInfo(lock).Owner = thread
+		}
+	}
+
+	for _, thread := range a.Dump.Threads {
+		if lock := thread.BlockedOn(); lock != nil {
+			info := a.lockInfo(lock)
+			info.Waiters = append(info.Waiters, thread)
+		}
+	}
+
+	// Threads parked on a Condition with no owner are idle, not contending
+	for _, info := range a.Locks {
+		if len(info.Waiters) > 0 && info.Owner != nil {
+			a.Contended = append(a.Contended, info)
+		}
+	}
+	sort.Slice(a.Contended, func(i, j int) bool {
+		if len(a.Contended[i].Waiters) != len(a.Contended[j].Waiters) {
+			return len(a.Contended[i].Waiters) > len(a.Contended[j].Waiters)
+		}
+		return a.Contended[i].Lock.Address < a.Contended[j].Lock.Address
+	})
+}
+
+/*
+ * findDeadlocks looks for cycles in the wait-for graph.
+ *
+ * A thread waits for at most one lock and a lock has at most one owner, so
+ * every thread has at most one outgoing edge (to the owner of the lock it is
+ * blocked on). Following those edges from each thread either ends at a thread
+ * that isn't blocked or loops back; a loop is a deadlock. This covers both
+ * synchronized monitors and java.util.concurrent locks, as parked threads
+ * count as waiting for the synchronizer's owner.
+ */
+func (a *ThreadAnalysis) findDeadlocks() {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	status := make(map[*Thread]int)
+
+	for _, start := range a.Dump.Threads {
+		if status[start] != unvisited {
+			continue
+		}
+
+		var path []*Thread
+		current := start
+		for current != nil && status[current] == unvisited {
+			status[current] = visiting
+			path = append(path, current)
+			current = a.OwnerOf(current.BlockedOn())
+		}
+
+		// Reached a thread on the current path: the path from there on is a cycle
+		if current != nil && status[current] == visiting {
+			cycleStart := 0
+			for path[cycleStart] != current {
+				cycleStart++
+			}
+
+			deadlock := &Deadlock{}
+			for _, thread := range path[cycleStart:] {
+				deadlock.Threads = append(deadlock.Threads, thread)
+				deadlock.Locks = append(deadlock.Locks, thread.BlockedOn())
+			}
+			a.Deadlocks = append(a.Deadlocks, deadlock)
+		}
+
+		for _, thread := range path {
+			status[thread] = done
+		}
+	}
+}
+
+func (a *ThreadAnalysis) groupStacks() {
+	groups := make(map[string]*StackGroup)
+	var order []string
+
+	for _, thread := range a.Dump.Threads {
+		if len(thread.Frames) == 0 {
+			continue // JVM-internal threads have no stack to compare
+		}
+
+		key := stackKey(thread)
+		group, exists := groups[key]
+		if !exists {
+			group = &StackGroup{State: thread.DisplayState(), Frames: thread.Frames}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.Threads = append(group.Threads, thread)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		if len(group.Threads) < MinGroupSize {
+			continue
+		}
+		group.NamePattern = namePattern(group.Threads)
+		group.SharedLock = sharedLock(group.Threads)
+		a.Groups = append(a.Groups, group)
+	}
+
+	sort.SliceStable(a.Groups, func(i, j int) bool {
+		return len(a.Groups[i].Threads) > len(a.Groups[j].Threads)
+	})
+}
+
+// stackKey identifies a stack by state and frames; lock addresses are left out
+// so threads parked on different instances of the same code still group
+func stackKey(thread *Thread) string {
+	var b strings.Builder
+	b.WriteString(thread.DisplayState())
+	for _, frame := range thread.Frames {
+		b.WriteByte('\n')
+		b.WriteString(frame.String())
+	}
+	return b.String()
+}
+
+// namePattern summarizes thread names by masking numbers: pool-1-thread-3 -> pool-*-thread-*
+func namePattern(threads []*Thread) string {
+	patterns := make(map[string]bool)
+	for _, thread := range threads {
+		patterns[threadNumberRegex.ReplaceAllString(thread.Name, "*")] = true
+	}
+	if len(patterns) == 1 {
+		for pattern := range patterns {
+			return pattern
+		}
+	}
+	return fmt.Sprintf("%d differently named threads", len(patterns))
+}
+
+func sharedLock(threads []*Thread) *Lock {
+	first := threads[0].BlockedOn()
+	if first == nil {
+		return nil
+	}
+	for _, thread := range threads[1:] {
+		if lock := thread.BlockedOn(); lock == nil || lock.Address != first.Address {
+			return nil
+		}
+	}
+	return first
+}
+
+// TopFrame returns the innermost frame of a stack, or nil for JVM-internal threads
+func TopFrame(frames []*StackFrame) *StackFrame {
+	if len(frames) == 0 {
+		return nil
+	}
+	return frames[0]
+}
+
+// ApplicationFrame returns the innermost frame outside the JDK. JDK-only stacks fall
+// back to the innermost frame that isn't part of parking or waiting.
+func ApplicationFrame(frames []*StackFrame) *StackFrame {
+	for _, frame := range frames {
+		if !hasPrefix(frame.Method, libraryPackages) {
+			return frame
+		}
+	}
+	for _, frame := range frames {
+		if !hasPrefix(frame.Method, waitPrefixes) {
+			return frame
+		}
+	}
+	return TopFrame(frames)
+}
+
+func hasPrefix(method string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe summarizes a group, e.g. "47 pool-*-thread-* threads WAITING (parking) on the same
+// java.util.concurrent.locks.AbstractQueuedSynchronizer$ConditionObject"
+func (g *StackGroup) Describe() string {
+	description := fmt.Sprintf("%d %s threads %s", len(g.Threads), g.NamePattern, g.State)
+	if strings.HasSuffix(g.NamePattern, "named threads") {
+		description = fmt.Sprintf("%d threads (%s) %s", len(g.Threads), g.NamePattern, g.State)
+	}
+
+	if g.SharedLock != nil {
+		description += " on the same " + lockClassName(g.SharedLock)
+	}
+	if frame := ApplicationFrame(g.Frames); frame != nil {
+		description += " in " + frame.Method
+	}
+	return description
+}
+
+func lockClassName(lock *Lock) string {
+	if lock.ClassName == "" {
+		return "lock <" + lock.Address + ">"
+	}
+	return lock.ClassName
+}
@@ -0,0 +1,274 @@
+package thread
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	RecommendedDumps = 3    // Dumps needed before "same stack" reliably means stuck
+	SpinningCPUShare = 0.5  // CPU time / wall time above which a stuck RUNNABLE thread is burning CPU
+	IdleCPUShare     = 0.01 // Below this a RUNNABLE thread in a native frame is waiting on I/O
+)
+
+// Progress is how a thread behaved across sequential dumps
+type Progress string
+
+const (
+	ProgressStuck       Progress = "stuck"       // Same stack in every dump while running, blocked or waiting for an owned lock
+	ProgressIdle        Progress = "idle"        // Same stack, but only waiting for work (pool queues, sleeps, socket accept)
+	ProgressProgressing Progress = "progressing" // Stack changed between dumps
+)
+
+// DumpComparison is the result of comparing sequential dumps of the same JVM
+type DumpComparison struct {
+	Dumps    []*ThreadDump
+	Analyses []*ThreadAnalysis // One per dump, for lock owners
+	Interval time.Duration     // First to last dump, zero if the dumps have no timestamps
+
+	Threads     []*ThreadHistory // Java threads seen in at least two dumps
+	Stuck       []*ThreadHistory // Most CPU first
+	Progressing []*ThreadHistory
+	Idle        []*ThreadHistory
+	Started     []*ThreadHistory // Missing from the first dump
+	Finished    []*ThreadHistory // Missing from the last dump
+	TopCPU      []*ThreadHistory // Threads with CPU deltas, most CPU first
+}
+
+// ThreadHistory is one thread followed across the compared dumps
+type ThreadHistory struct {
+	Name      string
+	Snapshots []*Thread // One per dump, nil where the thread doesn't appear
+	Progress  Progress
+
+	HasCPUDelta bool
+	CPUDelta    time.Duration // CPU time used between the first and last dump the thread appears in
+	WallDelta   time.Duration // Wall time over the same span
+}
+
+// CompareDumps compares sequential dumps, oldest first. Dumps that all carry a
+// timestamp are put in time order.
+func CompareDumps(dumps []*ThreadDump) (*DumpComparison, error) {
+	if len(dumps) < 2 {
+		return nil, fmt.Errorf("need at least 2 thread dumps to compare, got %d", len(dumps))
+	}
+
+	dumps = append([]*ThreadDump(nil), dumps...)
+	if allTimestamped(dumps) {
+		sort.SliceStable(dumps, func(i, j int) bool {
+			return dumps[i].Timestamp.Before(dumps[j].Timestamp)
+		})
+	}
+
+	comparison := &DumpComparison{Dumps: dumps}
+	for _, dump := range dumps {
+		comparison.Analyses = append(comparison.Analyses, Analyze(dump))
+	}
+	first, last := dumps[0], dumps[len(dumps)-1]
+	if !first.Timestamp.IsZero() && !last.Timestamp.IsZero() {
+		comparison.Interval = last.Timestamp.Sub(first.Timestamp)
+	}
+
+	for _, history := range matchThreads(dumps) {
+		seen := history.seen()
+		if history.Snapshots[0] == nil {
+			comparison.Started = append(comparison.Started, history)
+		}
+		if history.Snapshots[len(dumps)-1] == nil {
+			comparison.Finished = append(comparison.Finished, history)
+		}
+		if len(seen) < 2 || !seen[0].IsJava() {
+			continue
+		}
+
+		history.computeCPU(dumps)
+		history.Progress = history.classify(comparison.Analyses)
+		comparison.Threads = append(comparison.Threads, history)
+
+		switch history.Progress {
+		case ProgressStuck:
+			comparison.Stuck = append(comparison.Stuck, history)
+		case ProgressIdle:
+			comparison.Idle = append(comparison.Idle, history)
+		case ProgressProgressing:
+			comparison.Progressing = append(comparison.Progressing, history)
+		}
+		if history.HasCPUDelta && history.CPUDelta > 0 {
+			comparison.TopCPU = append(comparison.TopCPU, history)
+		}
+	}
+
+	byCPU := func(threads []*ThreadHistory) {
+		sort.SliceStable(threads, func(i, j int) bool {
+			return threads[i].CPUDelta > threads[j].CPUDelta
+		})
+	}
+	byCPU(comparison.Stuck)
+	byCPU(comparison.TopCPU)
+
+	return comparison, nil
+}
+
+func allTimestamped(dumps []*ThreadDump) bool {
+	for _, dump := range dumps {
+		if dump.Timestamp.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// matchThreads lines up each thread's snapshots across dumps, in order of first appearance
+func matchThreads(dumps []*ThreadDump) []*ThreadHistory {
+	histories := make(map[string]*ThreadHistory)
+	var order []*ThreadHistory
+
+	for i, dump := range dumps {
+		for _, thread := range dump.Threads {
+			key := threadKey(thread)
+			history, exists := histories[key]
+			if !exists {
+				history = &ThreadHistory{Name: thread.Name, Snapshots: make([]*Thread, len(dumps))}
+				histories[key] = history
+				order = append(order, history)
+			}
+			history.Snapshots[i] = thread
+		}
+	}
+	return order
+}
+
+// threadKey identifies a thread across dumps. Thread numbers are never reused within
+// a JVM; threads without one (JVM-internal, JDK 7) fall back to the native ID.
+func threadKey(thread *Thread) string {
+	if thread.Number >= 0 {
+		return fmt.Sprintf("#%d %s", thread.Number, thread.Name)
+	}
+	return fmt.Sprintf("nid=%s %s", thread.NID, thread.Name)
+}
+
+// seen returns the snapshots of the dumps the thread appears in
+func (h *ThreadHistory) seen() []*Thread {
+	var seen []*Thread
+	for _, snapshot := range h.Snapshots {
+		if snapshot != nil {
+			seen = append(seen, snapshot)
+		}
+	}
+	return seen
+}
+
+// Latest returns the thread as it appears in the most recent dump that has it
+func (h *ThreadHistory) Latest() *Thread {
+	for i := len(h.Snapshots) - 1; i >= 0; i-- {
+		if h.Snapshots[i] != nil {
+			return h.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// CPUShare is the fraction of one core used between dumps, or -1 if unknown
+func (h *ThreadHistory) CPUShare() float64 {
+	if !h.HasCPUDelta || h.WallDelta <= 0 {
+		return -1
+	}
+	return float64(h.CPUDelta) / float64(h.WallDelta)
+}
+
+func (h *ThreadHistory) computeCPU(dumps []*ThreadDump) {
+	firstIndex, lastIndex := -1, -1
+	for i, snapshot := range h.Snapshots {
+		if snapshot == nil {
+			continue
+		}
+		if firstIndex < 0 {
+			firstIndex = i
+		}
+		lastIndex = i
+	}
+	first, last := h.Snapshots[firstIndex], h.Snapshots[lastIndex]
+
+	if !first.HasCPUTime || !last.HasCPUTime {
+		return
+	}
+	h.HasCPUDelta = true
+	h.CPUDelta = max(last.CPUTime-first.CPUTime, 0)
+
+	// elapsed= is more precise than the dump's whole-second date line
+	h.WallDelta = last.Elapsed - first.Elapsed
+	if h.WallDelta <= 0 && !dumps[firstIndex].Timestamp.IsZero() && !dumps[lastIndex].Timestamp.IsZero() {
+		h.WallDelta = dumps[lastIndex].Timestamp.Sub(dumps[firstIndex].Timestamp)
+	}
+}
+
+/*
+ * classify decides whether a thread moved between dumps.
+ *
+ * Any change in the stack counts as progress. A thread whose stack never
+ * changed is stuck if it was running, blocked on a monitor, or parked on a
+ * lock another thread owns in that dump; threads parked on an ownerless
+ * condition (idle pool workers) or sleeping are just waiting for work. A
+ * RUNNABLE thread sitting in a native frame without using CPU is blocked in
+ * I/O such as socket accept or read, which is also treated as idle.
+ */
+func (h *ThreadHistory) classify(analyses []*ThreadAnalysis) Progress {
+	seen := h.seen()
+	key := stackKey(seen[0])
+	for _, snapshot := range seen[1:] {
+		if stackKey(snapshot) != key {
+			return ProgressProgressing
+		}
+	}
+
+	thread := seen[len(seen)-1]
+	switch thread.State {
+	case StateRunnable:
+		if share := h.CPUShare(); share >= 0 && share < IdleCPUShare && inNativeFrame(thread) {
+			return ProgressIdle
+		}
+		return ProgressStuck
+
+	case StateBlocked:
+		return ProgressStuck
+
+	case StateWaiting, StateTimedWaiting:
+		for i, snapshot := range h.Snapshots {
+			if snapshot == nil || snapshot.BlockedOn() == nil {
+				continue
+			}
+			if analyses[i].OwnerOf(snapshot.BlockedOn()) != nil {
+				return ProgressStuck
+			}
+		}
+		return ProgressIdle
+	}
+
+	return ProgressIdle
+}
+
+func inNativeFrame(thread *Thread) bool {
+	frame := TopFrame(thread.Frames)
+	return frame != nil && strings.Contains(frame.Location, "Native Method")
+}
+
+// WaitingFor describes the lock a thread waits for in the last dump it appears in and who holds it
+func (c *DumpComparison) WaitingFor(history *ThreadHistory) string {
+	for i := len(history.Snapshots) - 1; i >= 0; i-- {
+		snapshot := history.Snapshots[i]
+		if snapshot == nil {
+			continue
+		}
+		lock := snapshot.BlockedOn()
+		if lock == nil {
+			return ""
+		}
+		if owner := c.Analyses[i].OwnerOf(lock); owner != nil {
+			return fmt.Sprintf("waiting for %s held by \"%s\"", lock, owner.Name)
+		}
+		return "waiting for " + lock.String()
+	}
+	return ""
+}
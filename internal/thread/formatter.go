@@ -0,0 +1,321 @@
+package thread
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const stateBarWidth = 30
+
+func (a *ThreadAnalysis) PrintSummary() {
+	dump := a.Dump
+
+	// Header
+	fmt.Printf("🧵 Thread Dump Analysis\n")
+	fmt.Printf("File: %s  |  Threads: %d (%d Java, %d daemon)", filepath.Base(dump.Filename),
+		len(dump.Threads), a.JavaThreads, a.Daemons)
+	if !dump.Timestamp.IsZero() {
+		fmt.Printf("  |  Taken: %s", dump.Timestamp.Format(time.DateTime))
+	}
+	fmt.Println()
+	if dump.JVMVersion != "" {
+		fmt.Printf("JVM: %s\n", dump.JVMVersion)
+	}
+	fmt.Println(strings.Repeat("═", 65))
+
+	a.printStates()
+	a.printDeadlocks()
+	a.printContention()
+	a.printGroups()
+}
+
+func (a *ThreadAnalysis) printStates() {
+	fmt.Println("\n📊 THREAD STATES")
+	fmt.Println(strings.Repeat("─", 35))
+
+	total := len(a.Dump.Threads)
+	for _, state := range States {
+		count := a.StateCounts[state]
+		if count == 0 {
+			continue
+		}
+
+		share := float64(count) / float64(total)
+		bar := utils.CreateProgressBar(share, stateBarWidth, StateColor(state))
+		fmt.Printf("%s %-14s %s %4d (%s)\n", StateIcon(state), state, bar, count, utils.Precision(0).Percent(share*100))
+	}
+}
+
+func (a *ThreadAnalysis) printDeadlocks() {
+	fmt.Println("\n🔒 DEADLOCKS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	if len(a.Deadlocks) == 0 {
+		fmt.Println("✅ No deadlocks found")
+		return
+	}
+
+	for i, deadlock := range a.Deadlocks {
+		fmt.Println(utils.CriticalStyle.Render(fmt.Sprintf("🔴 Deadlock %d: %d threads", i+1, len(deadlock.Threads))))
+		for j, thread := range deadlock.Threads {
+			holder := deadlock.Threads[(j+1)%len(deadlock.Threads)]
+			fmt.Printf("   \"%s\" waits for %s\n", thread.Name, deadlock.Locks[j])
+			fmt.Printf("      held by \"%s\"", holder.Name)
+			if frame := ApplicationFrame(thread.Frames); frame != nil {
+				fmt.Printf(", blocked in %s", frame)
+			}
+			fmt.Println()
+		}
+	}
+
+	if a.Dump.ReportedDeadlocks > 0 && a.Dump.ReportedDeadlocks != len(a.Deadlocks) {
+		fmt.Printf("   ⚠️  The JVM reported %d deadlock(s) in the dump\n", a.Dump.ReportedDeadlocks)
+	}
+}
+
+func (a *ThreadAnalysis) printContention() {
+	if len(a.Contended) == 0 {
+		return
+	}
+
+	fmt.Println("\n🚦 LOCK CONTENTION")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, info := range a.Contended {
+		if i >= MaxReportedItems {
+			fmt.Printf("   ... and %d more contended locks\n", len(a.Contended)-MaxReportedItems)
+			break
+		}
+
+		owner := "no owner in dump"
+		if info.Owner != nil {
+			owner = fmt.Sprintf("held by \"%s\"", info.Owner.Name)
+		}
+
+		line := fmt.Sprintf("%3d waiting  %s, %s", len(info.Waiters), info.Lock, owner)
+		if len(info.Waiters) >= HotLockThreshold {
+			fmt.Println(utils.WarningStyle.Render("⚠️ " + line))
+		} else {
+			fmt.Println("   " + line)
+		}
+
+		if info.Owner != nil {
+			if frame := ApplicationFrame(info.Owner.Frames); frame != nil {
+				fmt.Println(utils.MutedStyle.Render(fmt.Sprintf("      owner %s in %s", info.Owner.DisplayState(), frame)))
+			}
+		}
+	}
+}
+
+func (a *ThreadAnalysis) printGroups() {
+	if len(a.Groups) == 0 {
+		return
+	}
+
+	fmt.Println("\n👥 IDENTICAL STACKS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, group := range a.Groups {
+		if i >= MaxReportedItems {
+			fmt.Printf("   ... and %d more groups\n", len(a.Groups)-MaxReportedItems)
+			break
+		}
+
+		fmt.Printf("%s %s\n", StateIcon(group.Threads[0].State), group.Describe())
+		if frame := TopFrame(group.Frames); frame != nil {
+			fmt.Println(utils.MutedStyle.Render("      at " + frame.String()))
+		}
+	}
+}
+
+// StateIcon is the traffic-light icon used for a state in reports
+func StateIcon(state ThreadState) string {
+	switch state {
+	case StateRunnable:
+		return "🟢"
+	case StateBlocked:
+		return "🔴"
+	case StateWaiting, StateTimedWaiting:
+		return "🟡"
+	default:
+		return "⚪"
+	}
+}
+
+// StateColor is the color used for a state in reports and progress bars
+func StateColor(state ThreadState) lipgloss.Color {
+	switch state {
+	case StateRunnable:
+		return utils.GoodColor
+	case StateBlocked:
+		return utils.CriticalColor
+	case StateWaiting, StateTimedWaiting:
+		return utils.WarningColor
+	default:
+		return utils.MutedColor
+	}
+}
+
+func (c *DumpComparison) PrintSummary() {
+	first, last := c.Dumps[0], c.Dumps[len(c.Dumps)-1]
+
+	// Header
+	fmt.Printf("🧵 Thread Dump Comparison\n")
+	fmt.Printf("Dumps: %d", len(c.Dumps))
+	if c.Interval > 0 {
+		fmt.Printf(" over %s", utils.FormatDuration(c.Interval))
+	}
+	fmt.Printf("  |  Threads: %d → %d\n", len(first.Threads), len(last.Threads))
+	fmt.Println(strings.Repeat("═", 65))
+
+	for i, dump := range c.Dumps {
+		taken := "no timestamp"
+		if !dump.Timestamp.IsZero() {
+			taken = dump.Timestamp.Format(time.DateTime)
+		}
+		fmt.Printf("  %d. %-20s %s  %4d threads\n", i+1, filepath.Base(dump.Filename), taken, len(dump.Threads))
+	}
+	if len(c.Dumps) < RecommendedDumps {
+		fmt.Println(utils.WarningStyle.Render(fmt.Sprintf(
+			"⚠️  Only %d dumps: take %d or more a few seconds apart to tell stuck threads from slow ones",
+			len(c.Dumps), RecommendedDumps)))
+	}
+
+	c.printProgress()
+	c.printStuck()
+	c.printCPU()
+	c.printProgressing()
+}
+
+func (c *DumpComparison) printProgress() {
+	fmt.Println("\n📊 THREAD PROGRESS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	total := len(c.Threads)
+	if total == 0 {
+		fmt.Println("No Java threads appear in more than one dump")
+		return
+	}
+
+	rows := []struct {
+		icon  string
+		label string
+		count int
+		color lipgloss.Color
+	}{
+		{"🔴", "Stuck", len(c.Stuck), utils.CriticalColor},
+		{"🟢", "Progressing", len(c.Progressing), utils.GoodColor},
+		{"💤", "Idle", len(c.Idle), utils.MutedColor},
+	}
+	for _, row := range rows {
+		share := float64(row.count) / float64(total)
+		bar := utils.CreateProgressBar(share, stateBarWidth, row.color)
+		fmt.Printf("%s %-14s %s %4d (%s)\n", row.icon, row.label, bar, row.count, utils.Precision(0).Percent(share*100))
+	}
+
+	if len(c.Started) > 0 || len(c.Finished) > 0 {
+		fmt.Printf("🆕 %d thread(s) started and 🏁 %d finished between the first and last dump\n",
+			len(c.Started), len(c.Finished))
+	}
+}
+
+func (c *DumpComparison) printStuck() {
+	if len(c.Stuck) == 0 {
+		fmt.Println("\n✅ No stuck threads: every busy or blocked thread moved between dumps")
+		return
+	}
+
+	fmt.Printf("\n🔴 STUCK THREADS (same stack in every dump)\n")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, history := range c.Stuck {
+		if i >= MaxReportedItems {
+			fmt.Printf("   ... and %d more stuck threads\n", len(c.Stuck)-MaxReportedItems)
+			break
+		}
+
+		thread := history.Latest()
+		line := fmt.Sprintf("\"%s\" %s", history.Name, thread.DisplayState())
+		if cpu := formatCPUDelta(history); cpu != "" {
+			line += "  " + cpu
+		}
+
+		if thread.State == StateRunnable && history.CPUShare() >= SpinningCPUShare {
+			fmt.Println(utils.CriticalStyle.Render("🔥 " + line + "  burning CPU in the same frame"))
+		} else {
+			fmt.Printf("%s %s\n", StateIcon(thread.State), line)
+		}
+
+		if waiting := c.WaitingFor(history); waiting != "" {
+			fmt.Printf("      %s\n", waiting)
+		}
+		if frame := ApplicationFrame(thread.Frames); frame != nil {
+			fmt.Println(utils.MutedStyle.Render("      at " + frame.String()))
+		}
+	}
+}
+
+func (c *DumpComparison) printCPU() {
+	if len(c.TopCPU) == 0 {
+		return
+	}
+
+	fmt.Println("\n🔥 CPU BETWEEN DUMPS")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, history := range c.TopCPU {
+		if i >= MaxReportedItems {
+			break
+		}
+
+		share := "   ?  "
+		if s := history.CPUShare(); s >= 0 {
+			share = fmt.Sprintf("%6s", utils.FormatPercent(s*100))
+		}
+		fmt.Printf("%s  %10s  %-12s \"%s\"\n", share, utils.FormatDuration(history.CPUDelta),
+			history.Progress, history.Name)
+	}
+}
+
+func (c *DumpComparison) printProgressing() {
+	if len(c.Progressing) == 0 {
+		return
+	}
+
+	fmt.Println("\n🟢 PROGRESSING")
+	fmt.Println(strings.Repeat("─", 35))
+
+	for i, history := range c.Progressing {
+		if i >= MaxReportedItems {
+			fmt.Printf("   ... and %d more progressing threads\n", len(c.Progressing)-MaxReportedItems)
+			break
+		}
+
+		thread := history.Latest()
+		fmt.Printf("%s \"%s\" %s", StateIcon(thread.State), history.Name, thread.DisplayState())
+		if frame := ApplicationFrame(thread.Frames); frame != nil {
+			fmt.Printf(", now in %s", frame.Method)
+		}
+		fmt.Println()
+	}
+}
+
+// formatCPUDelta shows CPU used between dumps, e.g. "cpu +9.0s (90% of a core)"
+func formatCPUDelta(history *ThreadHistory) string {
+	if !history.HasCPUDelta {
+		return ""
+	}
+	if history.CPUDelta == 0 {
+		return "no CPU used"
+	}
+	text := "cpu +" + utils.FormatDuration(history.CPUDelta)
+	if share := history.CPUShare(); share >= 0 {
+		text += fmt.Sprintf(" (%s of a core)", utils.Precision(0).Percent(share*100))
+	}
+	return text
+}
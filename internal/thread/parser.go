@@ -0,0 +1,322 @@
+package thread
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// 2024-01-15 10:30:45
+	dumpDateRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\s*$`)
+
+	// Full thread dump OpenJDK 64-Bit Server VM (17.0.2+8-86 mixed mode, sharing):
+	dumpHeaderRegex = regexp.MustCompile(`^Full thread dump (.*?):?\s*$`)
+
+	//    java.lang.Thread.State: WAITING (parking)
+	threadStateRegex = regexp.MustCompile(`^\s+java\.lang\.Thread\.State: (\w+)(?: \((.*)\))?`)
+
+	// 	- waiting to lock <0x000000071a8d1234> (a java.lang.Object)
+	lockLineRegex = regexp.MustCompile(`^\s+- (locked|waiting to lock|waiting on|parking to wait for|waiting to re-lock in wait\(\))\s+<(0x[0-9a-fA-F]+)>(?: \(a (.*)\))?`)
+
+	// 	- <0x000000071a8d5678> (a java.util.concurrent.locks.ReentrantLock$NonfairSync)
+	synchronizerRegex = regexp.MustCompile(`^\s+- <(0x[0-9a-fA-F]+)>(?: \(a (.*)\))?`)
+
+	// JNI global refs: 15, weak refs: 0   or   JNI global references: 15
+	jniRefsRegex = regexp.MustCompile(`^JNI global ref(?:erence)?s: (\d+)`)
+
+	// Found 1 deadlock.
+	deadlockCountRegex = regexp.MustCompile(`^Found (\d+) deadlocks?\.`)
+)
+
+const dumpDateLayout = "2006-01-02 15:04:05"
+
+// ParseFile reads a jstack or jcmd Thread.print thread dump. A file holding several
+// dumps returns the first one; use ParseFileDumps to get all of them.
+func ParseFile(filename string) (*ThreadDump, error) {
+	dumps, err := ParseFileDumps(filename)
+	if err != nil {
+		return nil, err
+	}
+	return dumps[0], nil
+}
+
+// ParseFileDumps reads every thread dump in a file, e.g. several jstack runs appended to one log
+func ParseFileDumps(filename string) ([]*ThreadDump, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open thread dump: %w", err)
+	}
+	defer file.Close()
+
+	dumps, err := ParseDumps(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, dump := range dumps {
+		dump.Filename = filename
+	}
+	return dumps, nil
+}
+
+// Parse reads a single thread dump; see ParseDumps for the format
+func Parse(reader io.Reader) (*ThreadDump, error) {
+	dumps, err := ParseDumps(reader)
+	if err != nil {
+		return nil, err
+	}
+	return dumps[0], nil
+}
+
+/*
+ * ParseDumps reads thread dumps in the format printed by jstack, jcmd Thread.print
+ * and kill -3:
+ *
+ *   2024-01-15 10:30:45
+ *   Full thread dump OpenJDK 64-Bit Server VM (17.0.2+8-86 mixed mode, sharing):
+ *
+ *   "pool-1-thread-3" #14 prio=5 os_prio=0 cpu=1.20ms elapsed=9.90s tid=0x... nid=0x1a2b waiting on condition  [0x...]
+ *      java.lang.Thread.State: WAITING (parking)
+ *   	at jdk.internal.misc.Unsafe.park(java.base@17.0.2/Native Method)
+ *   	- parking to wait for  <0x000000071a8d3c48> (a java.util.concurrent.locks.AbstractQueuedSynchronizer$ConditionObject)
+ *   	at java.util.concurrent.locks.LockSupport.park(java.base@17.0.2/LockSupport.java:341)
+ *
+ *      Locked ownable synchronizers:
+ *   	- None
+ *
+ * Threads are separated by blank lines. The JVM's own deadlock report at the
+ * end repeats the deadlocked stacks, so only its deadlock count is kept.
+ *
+ * A date line or "Full thread dump" header after a dump has started begins
+ * the next dump, so sequential dumps appended to one file come back separately.
+ */
+func ParseDumps(reader io.Reader) ([]*ThreadDump, error) {
+	var dumps []*ThreadDump
+	dump := &ThreadDump{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var current *Thread
+	inSynchronizers := false
+	inDeadlockReport := false
+	sawHeader := false
+
+	// Start the next dump if the current one already has content
+	nextDump := func() {
+		if len(dump.Threads) > 0 || dump.JVMVersion != "" {
+			dumps = append(dumps, dump)
+			dump = &ThreadDump{}
+		}
+		current = nil
+		inSynchronizers = false
+		inDeadlockReport = false
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if dumpDateRegex.MatchString(line) {
+			nextDump()
+			if timestamp, err := time.ParseInLocation(dumpDateLayout, strings.TrimSpace(line), time.Local); err == nil {
+				dump.Timestamp = timestamp
+			}
+			continue
+		}
+
+		if inDeadlockReport {
+			if matches := deadlockCountRegex.FindStringSubmatch(line); matches != nil {
+				dump.ReportedDeadlocks, _ = strconv.Atoi(matches[1])
+				inDeadlockReport = false
+			}
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			// Stacks and their "Locked ownable synchronizers" are separated by a blank line
+			inSynchronizers = false
+
+		case strings.HasPrefix(line, "\""):
+			thread, ok := parseThreadHeader(line)
+			if !ok {
+				current = nil
+				continue
+			}
+			dump.Threads = append(dump.Threads, thread)
+			current = thread
+			inSynchronizers = false
+
+		case strings.HasPrefix(line, "Found one Java-level deadlock") || strings.HasPrefix(line, "Found a total of"):
+			inDeadlockReport = true
+
+		case jniRefsRegex.MatchString(line):
+			dump.JNIGlobalRefs, _ = strconv.Atoi(jniRefsRegex.FindStringSubmatch(line)[1])
+			current = nil
+
+		case dumpHeaderRegex.MatchString(line):
+			// The date line usually started this dump already
+			if len(dump.Threads) > 0 || dump.JVMVersion != "" {
+				nextDump()
+			}
+			dump.JVMVersion = dumpHeaderRegex.FindStringSubmatch(line)[1]
+			sawHeader = true
+			current = nil
+
+		case current != nil:
+			parseThreadLine(current, line, &inSynchronizers)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading thread dump: %w", err)
+	}
+	dumps = append(dumps, dump)
+
+	// Drop headers with no threads, e.g. a dump cut off at the end of the file
+	var complete []*ThreadDump
+	for _, dump := range dumps {
+		if len(dump.Threads) == 0 {
+			continue
+		}
+		for _, thread := range dump.Threads {
+			if thread.State == "" {
+				thread.State = StateVM
+			}
+		}
+		complete = append(complete, dump)
+	}
+
+	if len(complete) == 0 {
+		if !sawHeader {
+			return nil, fmt.Errorf("no threads found: not a jstack or Thread.print dump")
+		}
+		return nil, fmt.Errorf("no threads found in thread dump")
+	}
+
+	return complete, nil
+}
+
+// parseThreadHeader parses the quoted name line that starts each thread
+func parseThreadHeader(line string) (*Thread, bool) {
+	end := strings.LastIndex(line, "\"")
+	if end <= 0 {
+		return nil, false
+	}
+
+	thread := &Thread{
+		Name:   line[1:end],
+		Number: -1,
+	}
+
+	fields := strings.Fields(line[end+1:])
+	if len(fields) == 0 {
+		return nil, false // "Thread-1": lines of the JVM's deadlock report
+	}
+
+	for i, field := range fields {
+		key, value, hasValue := strings.Cut(field, "=")
+
+		switch {
+		case strings.HasPrefix(field, "#"):
+			thread.Number, _ = strconv.Atoi(field[1:])
+		case field == "daemon":
+			thread.Daemon = true
+		case !hasValue:
+			continue // e.g. the "[12345]" native ID JDK 19+ prints after the thread number
+		case key == "prio":
+			thread.Priority, _ = strconv.Atoi(value)
+		case key == "os_prio":
+			thread.OSPriority, _ = strconv.Atoi(value)
+		case key == "cpu":
+			if cpu, err := time.ParseDuration(value); err == nil {
+				thread.CPUTime = cpu
+				thread.HasCPUTime = true
+			}
+		case key == "elapsed":
+			thread.Elapsed, _ = time.ParseDuration(value)
+		case key == "tid":
+			thread.TID = value
+		case key == "nid":
+			thread.NID = value
+			thread.Status = headerStatus(fields[i+1:])
+			return thread, true
+		}
+	}
+
+	return thread, true
+}
+
+// headerStatus joins the status words after nid=, dropping the trailing [0x...] stack address
+func headerStatus(fields []string) string {
+	var words []string
+	for _, field := range fields {
+		if strings.HasPrefix(field, "[") {
+			break
+		}
+		words = append(words, field)
+	}
+	return strings.Join(words, " ")
+}
+
+func parseThreadLine(thread *Thread, line string, inSynchronizers *bool) {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(trimmed, "at "):
+		*inSynchronizers = false
+		thread.Frames = append(thread.Frames, parseFrame(strings.TrimPrefix(trimmed, "at ")))
+
+	case strings.HasPrefix(trimmed, "java.lang.Thread.State:"):
+		if matches := threadStateRegex.FindStringSubmatch(line); matches != nil {
+			thread.State = ThreadState(matches[1])
+			thread.StateDetail = matches[2]
+		}
+
+	case strings.HasPrefix(trimmed, "Locked ownable synchronizers:"):
+		*inSynchronizers = true
+
+	case *inSynchronizers:
+		if matches := synchronizerRegex.FindStringSubmatch(line); matches != nil {
+			thread.Synchronizers = append(thread.Synchronizers, &Lock{Address: matches[1], ClassName: matches[2]})
+		}
+
+	default:
+		matches := lockLineRegex.FindStringSubmatch(line)
+		if matches == nil {
+			return
+		}
+
+		lock := &Lock{Address: matches[2], ClassName: matches[3]}
+		switch matches[1] {
+		case "locked":
+			if len(thread.Frames) > 0 {
+				frame := thread.Frames[len(thread.Frames)-1]
+				frame.Locked = append(frame.Locked, lock)
+			}
+		case "waiting to lock", "waiting to re-lock in wait()":
+			thread.WaitingToLock = lock
+		case "waiting on":
+			thread.WaitingOn = lock
+		case "parking to wait for":
+			thread.ParkedOn = lock
+		}
+	}
+}
+
+// parseFrame splits "java.lang.Thread.sleep(java.base@17.0.2/Native Method)"
+func parseFrame(text string) *StackFrame {
+	open := strings.Index(text, "(")
+	if open < 0 || !strings.HasSuffix(text, ")") {
+		return &StackFrame{Method: text}
+	}
+	return &StackFrame{
+		Method:   text[:open],
+		Location: text[open+1 : len(text)-1],
+	}
+}
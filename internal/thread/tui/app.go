@@ -0,0 +1,325 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func initialModel(analysis *thread.ThreadAnalysis) *Model {
+	return &Model{
+		analysis:        analysis,
+		threads:         analysis.Dump.Threads,
+		currentTab:      OverviewTab,
+		scrollPositions: make(map[TabType]int),
+		threadsState:    &ThreadsState{},
+		groupsState: &GroupsState{
+			expanded: make(map[int]bool),
+		},
+		locksState: &LocksState{
+			expanded: make(map[int]bool),
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		// While typing a search term every key belongs to the search box
+		if search := m.currentSearch(); search != nil && search.active {
+			return m.handleSearchInput(search, msg)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+
+		case "tab":
+			utils.CycleEnumPtr(&m.currentTab, 1, LocksTab)
+
+		case "1":
+			m.currentTab = OverviewTab
+		case "2":
+			m.currentTab = ThreadsTab
+		case "3":
+			m.currentTab = GroupsTab
+		case "4":
+			m.currentTab = LocksTab
+
+		default:
+			// Forward to tab-specific handlers for up/down and other keys
+			return m.handleTabSpecificKeys(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleTabSpecificKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.currentTab {
+	case OverviewTab:
+		return m.handleOverviewKeys(msg)
+	case ThreadsTab:
+		return m.handleThreadsKeys(msg)
+	case GroupsTab:
+		return m.handleGroupsKeys(msg)
+	case LocksTab:
+		return m.handleLocksKeys(msg)
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleOverviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.scrollPositions[OverviewTab] > 0 {
+			m.scrollPositions[OverviewTab]--
+		}
+	case "down", "j":
+		// Will be bounded in rendering
+		m.scrollPositions[OverviewTab]++
+	}
+	return m, nil
+}
+
+// currentSearch returns the search box of the current tab, if it has one
+func (m *Model) currentSearch() *SearchState {
+	if m.currentTab == ThreadsTab && m.threadsState.stack == nil {
+		return &m.threadsState.search
+	}
+	return nil
+}
+
+func (m *Model) handleSearchInput(search *SearchState, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		search.active = false
+	case tea.KeyEsc:
+		search.active = false
+		search.term = ""
+	case tea.KeyBackspace:
+		if len(search.term) > 0 {
+			runes := []rune(search.term)
+			search.term = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		search.term += string(msg.Runes)
+	}
+
+	m.threadsState.selected = 0
+	return m, nil
+}
+
+// showStack opens a thread's stack in the Threads tab, remembering the current one for 'b'
+func (m *Model) showStack(t *thread.Thread) {
+	state := m.threadsState
+	if t == nil {
+		return
+	}
+
+	// Following a lock owner keeps a trail for 'b'; jumping in from another tab starts a new one
+	if m.currentTab != ThreadsTab {
+		state.history = nil
+	} else if state.stack != nil && state.stack != t {
+		state.history = append(state.history, state.stack)
+	}
+	state.stack = t
+	state.stackStart = 0
+	m.currentTab = ThreadsTab
+}
+
+func (m *Model) View() string {
+	return utils.ToASCII(m.render())
+}
+
+func (m *Model) render() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var content string
+
+	// Calculate available height for content (header + content + shortcuts)
+	headerHeight := 2 // tab line + border
+	shortcutsHeight := 1
+	contentHeight := m.height - headerHeight - shortcutsHeight
+
+	// Render current Tab
+	switch m.currentTab {
+	case OverviewTab:
+		content = m.RenderOverview(contentHeight)
+	case ThreadsTab:
+		content = m.RenderThreads(contentHeight)
+	case GroupsTab:
+		content = m.RenderGroups(contentHeight)
+	case LocksTab:
+		content = m.RenderLocks(contentHeight)
+	}
+
+	// Create a style that ensures content takes up exactly the available height
+	contentStyle := lipgloss.NewStyle().
+		Height(contentHeight).
+		Width(m.width)
+	content = contentStyle.Render(content)
+
+	// Build the full Tab
+	header := m.renderHeader()
+	shortcuts := m.renderFooter()
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		content,
+		shortcuts,
+	)
+}
+
+func (m *Model) renderHeader() string {
+	tabs := []string{}
+
+	tabIcons := []string{"📋", "🧵", "👥", "🔒"}
+	tabNames := []string{"Overview", "Threads", "Stack Groups", "Locks"}
+
+	for i, name := range tabNames {
+		style := utils.TabInactiveStyle
+		indicator := " "
+
+		if TabType(i) == m.currentTab {
+			style = utils.TabActiveStyle
+			indicator = "●" // Active indicator
+		}
+
+		tabText := fmt.Sprintf("%s%s %s[%d]", indicator, tabIcons[i], name, i+1)
+		tabs = append(tabs, style.Render(tabText))
+	}
+
+	tabLine := strings.Join(tabs, "")
+
+	border := strings.Repeat("─", m.width)
+
+	return lipgloss.JoinVertical(lipgloss.Left, tabLine, border)
+}
+
+func (m *Model) shortcuts() string {
+	base := "q:quit • tab:cycle • 1-4:tabs"
+
+	var tabSpecific string
+	switch m.currentTab {
+	case OverviewTab:
+		tabSpecific = "↑↓:scroll"
+	case ThreadsTab:
+		if m.threadsState.stack != nil {
+			tabSpecific = "↑↓:scroll • o:lock owner • b/esc:back"
+		} else {
+			tabSpecific = "↑↓:nav • enter:stack • s:sort by CPU • /:search"
+		}
+	case GroupsTab:
+		tabSpecific = "↑↓:nav • space:expand • enter:stack"
+	case LocksTab:
+		tabSpecific = "↑↓:nav • space:expand • enter:owner stack"
+	}
+
+	return base + " • " + tabSpecific
+}
+
+func (m *Model) renderFooter() string {
+	shortcuts := m.shortcuts()
+	if search := m.currentSearch(); search != nil && search.active {
+		shortcuts = "enter:apply • esc:clear • type to search"
+	}
+
+	return utils.HelpBarStyle.Width(m.width).Render(shortcuts)
+}
+
+// renderSearchLine shows the current search term, with a cursor while typing
+func renderSearchLine(search SearchState) string {
+	if search.active {
+		return utils.InfoStyle.Render(fmt.Sprintf("Search: %s█", search.term))
+	}
+	if search.term != "" {
+		return utils.MutedStyle.Render(fmt.Sprintf("Search: %s (esc to clear)", search.term))
+	}
+	return utils.MutedStyle.Render("Press / to search by thread name or frame")
+}
+
+// visibleWindow returns the slice bounds that keep the selected row centered
+func visibleWindow(selected, total, height int) (int, int) {
+	if height <= 0 || total <= height {
+		return 0, total
+	}
+
+	start := max(selected-height/2, 0)
+	end := start + height
+	if end > total {
+		end = total
+		start = max(end-height, 0)
+	}
+	return start, end
+}
+
+// scrollContent clips content to the given height starting at a bounded scroll offset
+func (m *Model) scrollContent(tab TabType, content string, height int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) <= height {
+		m.scrollPositions[tab] = 0
+		return content
+	}
+
+	maxScroll := len(lines) - height
+	if m.scrollPositions[tab] > maxScroll {
+		m.scrollPositions[tab] = maxScroll
+	}
+
+	start := m.scrollPositions[tab]
+	return strings.Join(lines[start:start+height], "\n")
+}
+
+// keepSelectedVisible clips item lines so the selected item stays on screen
+func keepSelectedVisible(lines []string, selectedStartLine, height int) []string {
+	if len(lines) <= height {
+		return lines
+	}
+
+	scrollY := 0
+	if selectedStartLine >= height/2 {
+		scrollY = selectedStartLine - height/2
+	}
+	scrollY = max(min(scrollY, len(lines)-height), 0)
+	return lines[scrollY:min(scrollY+height, len(lines))]
+}
+
+func renderSelectedRow(row string) string {
+	return utils.SelectedStyle.Render("▶ " + row)
+}
+
+func stateStyle(state thread.ThreadState) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(thread.StateColor(state))
+}
+
+func StartTUI(analysis *thread.ThreadAnalysis) error {
+	model := initialModel(analysis)
+
+	program := tea.NewProgram(
+		model,
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	_, err := program.Run()
+	return err
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Thread names listed under an expanded group
+const groupThreadNames = 10
+
+func (m *Model) handleGroupsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.groupsState
+	groups := m.analysis.Groups
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(groups)-1 {
+			state.selected++
+		}
+	case " ":
+		state.expanded[state.selected] = !state.expanded[state.selected]
+	case "enter":
+		// Every thread in the group shares the stack, so show the first one
+		if state.selected < len(groups) {
+			m.showStack(groups[state.selected].Threads[0])
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) RenderGroups(height int) string {
+	groups := m.analysis.Groups
+	if len(groups) == 0 {
+		return utils.GoodStyle.Render(fmt.Sprintf(
+			"✅ No identical stacks\n\nNo %d or more threads share the same state and stack.", thread.MinGroupSize))
+	}
+
+	header := utils.TitleStyle.Render(fmt.Sprintf("👥 %d groups of threads with identical stacks", len(groups)))
+
+	var lines []string
+	selectedStartLine := 0
+	for i, group := range groups {
+		if i == m.groupsState.selected {
+			selectedStartLine = len(lines)
+		}
+		lines = append(lines, m.renderGroupItem(i, group)...)
+		lines = append(lines, "") // Spacing between groups
+	}
+
+	lines = keepSelectedVisible(lines, selectedStartLine, height-2)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		strings.Join(lines, "\n"),
+	)
+}
+
+func (m *Model) renderGroupItem(index int, group *thread.StackGroup) []string {
+	var lines []string
+
+	isSelected := index == m.groupsState.selected
+	isExpanded := m.groupsState.expanded[index]
+
+	expandIcon := "[+]"
+	if isExpanded {
+		expandIcon = "[-]"
+	}
+
+	titleLine := fmt.Sprintf("%s %d × %s  %s", thread.StateIcon(group.Threads[0].State),
+		len(group.Threads), group.NamePattern, group.State)
+	if isSelected {
+		titleLine = renderSelectedRow(titleLine)
+	} else {
+		titleLine = "  " + stateStyle(group.Threads[0].State).Render(titleLine)
+	}
+	lines = append(lines, titleLine)
+
+	for j, line := range utils.WrapText(group.Describe(), m.width-8) {
+		prefix := "  ├─ "
+		if j > 0 {
+			prefix = "  │  "
+		}
+		lines = append(lines, utils.MutedStyle.Render(prefix+line))
+	}
+
+	expandLine := fmt.Sprintf("  └─ %s Show Threads and Stack", expandIcon)
+	if isSelected {
+		expandLine = utils.InfoStyle.Render(expandLine)
+	} else {
+		expandLine = utils.MutedStyle.Render(expandLine)
+	}
+	lines = append(lines, expandLine)
+
+	if isExpanded {
+		lines = append(lines, renderGroupDetails(group)...)
+	}
+
+	return lines
+}
+
+func renderGroupDetails(group *thread.StackGroup) []string {
+	lines := []string{"     Threads:"}
+	for i, t := range group.Threads {
+		if i >= groupThreadNames {
+			lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("       ... and %d more", len(group.Threads)-groupThreadNames)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("       \"%s\"", t.Name))
+	}
+
+	lines = append(lines, "     Stack:")
+	for _, frame := range group.Frames {
+		lines = append(lines, utils.MutedStyle.Render("       at "+frame.String()))
+	}
+	return lines
+}
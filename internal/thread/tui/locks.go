@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *Model) handleLocksKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.locksState
+	locks := m.analysis.Contended
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(locks)-1 {
+			state.selected++
+		}
+	case " ":
+		state.expanded[state.selected] = !state.expanded[state.selected]
+	case "enter":
+		if state.selected < len(locks) {
+			m.showStack(locks[state.selected].Owner)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) RenderLocks(height int) string {
+	locks := m.analysis.Contended
+	if len(locks) == 0 {
+		return utils.GoodStyle.Render("✅ No lock contention\n\nNo thread is waiting for a lock held by another thread.")
+	}
+
+	header := utils.TitleStyle.Render(fmt.Sprintf("🔒 %d contended locks", len(locks)))
+
+	var lines []string
+	selectedStartLine := 0
+	for i, info := range locks {
+		if i == m.locksState.selected {
+			selectedStartLine = len(lines)
+		}
+		lines = append(lines, m.renderLockItem(i, info)...)
+		lines = append(lines, "") // Spacing between locks
+	}
+
+	lines = keepSelectedVisible(lines, selectedStartLine, height-2)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		"",
+		strings.Join(lines, "\n"),
+	)
+}
+
+func (m *Model) renderLockItem(index int, info *thread.LockInfo) []string {
+	var lines []string
+
+	isSelected := index == m.locksState.selected
+	isExpanded := m.locksState.expanded[index]
+
+	style := utils.MutedStyle
+	icon := "  "
+	if len(info.Waiters) >= thread.HotLockThreshold {
+		style = utils.WarningStyle
+		icon = "⚠️"
+	}
+
+	expandIcon := "[+]"
+	if isExpanded {
+		expandIcon = "[-]"
+	}
+
+	titleLine := fmt.Sprintf("%s %d waiting  %s", icon, len(info.Waiters), info.Lock)
+	if isSelected {
+		titleLine = renderSelectedRow(titleLine)
+	} else {
+		titleLine = "  " + style.Render(titleLine)
+	}
+	lines = append(lines, titleLine)
+
+	ownerLine := fmt.Sprintf("  ├─ held by \"%s\" %s", info.Owner.Name, info.Owner.DisplayState())
+	if frame := thread.ApplicationFrame(info.Owner.Frames); frame != nil {
+		ownerLine += " in " + frame.Method
+	}
+	lines = append(lines, utils.MutedStyle.Render(ownerLine))
+
+	expandLine := fmt.Sprintf("  └─ %s Show Waiters", expandIcon)
+	if isSelected {
+		expandLine = utils.InfoStyle.Render(expandLine)
+	} else {
+		expandLine = utils.MutedStyle.Render(expandLine)
+	}
+	lines = append(lines, expandLine)
+
+	if isExpanded {
+		for _, waiter := range info.Waiters {
+			line := fmt.Sprintf("       \"%s\" %s", waiter.Name, waiter.DisplayState())
+			if frame := thread.ApplicationFrame(waiter.Frames); frame != nil {
+				line += " in " + frame.Method
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const overviewTopGroups = 5
+
+func (m *Model) RenderOverview(height int) string {
+	var sections []string
+
+	sections = append(sections, m.renderDumpInfo())
+	sections = append(sections, m.renderStates())
+	sections = append(sections, m.renderDeadlocks())
+	if len(m.analysis.Groups) > 0 {
+		sections = append(sections, m.renderTopGroups())
+	}
+
+	content := strings.Join(sections, "\n\n")
+	return m.scrollContent(OverviewTab, content, height)
+}
+
+func (m *Model) renderDumpInfo() string {
+	dump := m.analysis.Dump
+	lines := []string{utils.TitleStyle.Render("📁 Thread Dump")}
+	lines = append(lines, utils.FormatKeyValue("File", filepath.Base(dump.Filename), 14))
+	if !dump.Timestamp.IsZero() {
+		lines = append(lines, utils.FormatKeyValue("Taken", dump.Timestamp.Format(time.DateTime), 14))
+	}
+	if dump.JVMVersion != "" {
+		lines = append(lines, utils.FormatKeyValue("JVM", dump.JVMVersion, 14))
+	}
+	lines = append(lines, utils.FormatKeyValue("Threads", fmt.Sprintf("%d (%d Java, %d daemon)",
+		len(dump.Threads), m.analysis.JavaThreads, m.analysis.Daemons), 14))
+	lines = append(lines, utils.FormatKeyValue("Contended", fmt.Sprintf("%d locks", len(m.analysis.Contended)), 14))
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderStates() string {
+	lines := []string{utils.TitleStyle.Render("📊 Thread States")}
+
+	barWidth := max(min(m.width-40, 40), 10)
+	total := len(m.threads)
+	for _, state := range thread.States {
+		count := m.analysis.StateCounts[state]
+		if count == 0 {
+			continue
+		}
+
+		share := float64(count) / float64(total)
+		style := stateStyle(state)
+		bar := utils.CreateProgressBar(share, barWidth, thread.StateColor(state))
+		lines = append(lines, fmt.Sprintf("%s %s %s %4d (%s)", thread.StateIcon(state),
+			style.Render(fmt.Sprintf("%-14s", state)), bar, count, utils.Precision(0).Percent(share*100)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderDeadlocks() string {
+	lines := []string{utils.TitleStyle.Render("🔒 Deadlocks")}
+
+	if len(m.analysis.Deadlocks) == 0 {
+		lines = append(lines, utils.GoodStyle.Render("✅ No deadlocks found"))
+		return strings.Join(lines, "\n")
+	}
+
+	for i, deadlock := range m.analysis.Deadlocks {
+		lines = append(lines, utils.CriticalStyle.Render(fmt.Sprintf("🔴 Deadlock %d: %d threads", i+1, len(deadlock.Threads))))
+		for j, t := range deadlock.Threads {
+			holder := deadlock.Threads[(j+1)%len(deadlock.Threads)]
+			lines = append(lines, fmt.Sprintf("   \"%s\" waits for %s held by \"%s\"", t.Name, deadlock.Locks[j], holder.Name))
+		}
+	}
+	lines = append(lines, utils.MutedStyle.Render("   Open the Threads tab and press enter on a thread to see its stack"))
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderTopGroups() string {
+	lines := []string{utils.TitleStyle.Render("👥 Largest Stack Groups")}
+
+	for i, group := range m.analysis.Groups {
+		if i >= overviewTopGroups {
+			lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("   ... %d more in the Stack Groups tab",
+				len(m.analysis.Groups)-overviewTopGroups)))
+			break
+		}
+		lines = append(lines, thread.StateIcon(group.Threads[0].State)+" "+group.Describe())
+	}
+
+	return strings.Join(lines, "\n")
+}
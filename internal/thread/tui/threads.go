@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/thread"
+	"github.com/mabhi256/jdiag/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *Model) handleThreadsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.threadsState.stack != nil {
+		return m.handleStackKeys(msg)
+	}
+
+	state := m.threadsState
+	visible := m.visibleThreads()
+
+	switch msg.String() {
+	case "up", "k":
+		if state.selected > 0 {
+			state.selected--
+		}
+	case "down", "j":
+		if state.selected < len(visible)-1 {
+			state.selected++
+		}
+	case "s":
+		state.sortByCPU = !state.sortByCPU
+		state.selected = 0
+	case "/":
+		state.search.active = true
+	case "esc":
+		state.search.term = ""
+		state.selected = 0
+	case "enter":
+		if state.selected < len(visible) {
+			m.showStack(visible[state.selected])
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) handleStackKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := m.threadsState
+
+	switch msg.String() {
+	case "up", "k":
+		if state.stackStart > 0 {
+			state.stackStart--
+		}
+	case "down", "j":
+		// Will be bounded in rendering
+		state.stackStart++
+	case "o":
+		// Follow the wait-for edge to the thread holding the lock
+		if owner := m.analysis.OwnerOf(state.stack.BlockedOn()); owner != nil {
+			m.showStack(owner)
+		}
+	case "b", "esc":
+		if n := len(state.history); n > 0 {
+			state.stack = state.history[n-1]
+			state.history = state.history[:n-1]
+		} else {
+			state.stack = nil
+		}
+		state.stackStart = 0
+	}
+	return m, nil
+}
+
+// visibleThreads applies the search and sort order to the dump's threads
+func (m *Model) visibleThreads() []*thread.Thread {
+	state := m.threadsState
+	term := strings.ToLower(state.search.term)
+
+	var visible []*thread.Thread
+	for _, t := range m.threads {
+		if term == "" || matchesSearch(t, term) {
+			visible = append(visible, t)
+		}
+	}
+
+	if state.sortByCPU {
+		sort.SliceStable(visible, func(i, j int) bool {
+			return visible[i].CPUTime > visible[j].CPUTime
+		})
+	}
+	return visible
+}
+
+func matchesSearch(t *thread.Thread, term string) bool {
+	if strings.Contains(strings.ToLower(t.Name), term) {
+		return true
+	}
+	for _, frame := range t.Frames {
+		if strings.Contains(strings.ToLower(frame.Method), term) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) RenderThreads(height int) string {
+	if m.threadsState.stack != nil {
+		return m.renderStack(height)
+	}
+
+	state := m.threadsState
+	visible := m.visibleThreads()
+
+	order := "dump order"
+	if state.sortByCPU {
+		order = "CPU time"
+	}
+	header := utils.TitleStyle.Render(fmt.Sprintf("🧵 %d of %d threads (by %s)", len(visible), len(m.threads), order))
+	headerRow := utils.MutedStyle.Render(fmt.Sprintf("  %-36s %-28s %10s  %s", "Name", "State", "CPU", "Frame"))
+
+	var rows []string
+	start, end := visibleWindow(state.selected, len(visible), height-5)
+	for i := start; i < end; i++ {
+		row := m.renderThreadRow(visible[i])
+		if i == state.selected {
+			rows = append(rows, renderSelectedRow(row))
+		} else {
+			rows = append(rows, "  "+row)
+		}
+	}
+	if len(visible) == 0 {
+		rows = append(rows, utils.MutedStyle.Render("  No threads match the search"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		renderSearchLine(state.search),
+		"",
+		headerRow,
+		strings.Join(rows, "\n"),
+	)
+}
+
+func (m *Model) renderThreadRow(t *thread.Thread) string {
+	cpu := "-"
+	if t.HasCPUTime {
+		cpu = utils.FormatDuration(t.CPUTime)
+	}
+
+	frame := ""
+	if top := thread.ApplicationFrame(t.Frames); top != nil {
+		frame = top.Method
+	}
+
+	nameWidth := 36
+	frameWidth := max(m.width-nameWidth-28-10-8, 10)
+	return fmt.Sprintf("%-36s %s %10s  %s", utils.TruncateString(t.Name, nameWidth),
+		stateStyle(t.State).Render(fmt.Sprintf("%-28s", utils.TruncateString(t.DisplayState(), 28))),
+		cpu, utils.TruncateString(frame, frameWidth))
+}
+
+// renderStack is the drill-down view of a single thread
+func (m *Model) renderStack(height int) string {
+	t := m.threadsState.stack
+
+	header := utils.TitleStyle.Render(fmt.Sprintf("🧵 \"%s\"", t.Name))
+
+	var info []string
+	details := []string{stateStyle(t.State).Render(t.DisplayState())}
+	if t.Number >= 0 {
+		details = append(details, fmt.Sprintf("#%d", t.Number))
+	}
+	if t.Daemon {
+		details = append(details, "daemon")
+	}
+	if t.NID != "" {
+		details = append(details, "nid="+t.NID)
+	}
+	if t.HasCPUTime {
+		details = append(details, fmt.Sprintf("cpu=%s elapsed=%s",
+			utils.FormatDuration(t.CPUTime), utils.FormatDuration(t.Elapsed)))
+	}
+	info = append(info, strings.Join(details, "  "))
+
+	if lock := t.BlockedOn(); lock != nil {
+		line := "Waiting for " + lock.String()
+		if owner := m.analysis.OwnerOf(lock); owner != nil {
+			line += fmt.Sprintf(" held by \"%s\" (o to follow)", owner.Name)
+		}
+		info = append(info, utils.WarningStyle.Render(line))
+	}
+	for _, lock := range thread.HeldLocks(t) {
+		line := "Holds " + lock.String()
+		if waiters := len(m.analysis.Locks[lock.Address].Waiters); waiters > 0 {
+			line += fmt.Sprintf(", %d thread(s) waiting", waiters)
+			info = append(info, utils.WarningStyle.Render(line))
+		} else {
+			info = append(info, utils.MutedStyle.Render(line))
+		}
+	}
+
+	var frames []string
+	for _, frame := range t.Frames {
+		frames = append(frames, "  at "+frame.String())
+		for _, lock := range frame.Locked {
+			frames = append(frames, utils.MutedStyle.Render("     - locked "+lock.String()))
+		}
+	}
+	if len(frames) == 0 {
+		frames = append(frames, utils.MutedStyle.Render("  No Java stack (JVM-internal thread)"))
+	}
+
+	// Bound the stack scroll to what fits below the thread info
+	available := max(height-len(info)-3, 1)
+	maxStart := max(len(frames)-available, 0)
+	m.threadsState.stackStart = min(m.threadsState.stackStart, maxStart)
+	start := m.threadsState.stackStart
+	frames = frames[start:min(start+available, len(frames))]
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		strings.Join(info, "\n"),
+		"",
+		strings.Join(frames, "\n"),
+	)
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"github.com/mabhi256/jdiag/internal/thread"
+)
+
+type Model struct {
+	// Data
+	analysis *thread.ThreadAnalysis
+	threads  []*thread.Thread
+
+	// UI State
+	currentTab TabType
+	width      int
+	height     int
+
+	scrollPositions map[TabType]int
+	threadsState    *ThreadsState
+	groupsState     *GroupsState
+	locksState      *LocksState
+}
+
+type TabType int
+
+const (
+	OverviewTab TabType = iota
+	ThreadsTab
+	GroupsTab
+	LocksTab
+)
+
+// SearchState holds an in-progress or applied '/' search
+type SearchState struct {
+	active bool // Typing a search term
+	term   string
+}
+
+type ThreadsState struct {
+	selected  int
+	sortByCPU bool
+	search    SearchState
+
+	// Stack drill-down; nil shows the thread list
+	stack      *thread.Thread
+	stackStart int // Scroll offset within the stack
+	history    []*thread.Thread
+}
+
+type GroupsState struct {
+	selected int
+	expanded map[int]bool
+}
+
+type LocksState struct {
+	selected int
+	expanded map[int]bool
+}
@@ -0,0 +1,103 @@
+package thread
+
+import (
+	"time"
+)
+
+// ThreadState is the java.lang.Thread.State of a thread
+type ThreadState string
+
+const (
+	StateNew          ThreadState = "NEW"
+	StateRunnable     ThreadState = "RUNNABLE"
+	StateBlocked      ThreadState = "BLOCKED"
+	StateWaiting      ThreadState = "WAITING"
+	StateTimedWaiting ThreadState = "TIMED_WAITING"
+	StateTerminated   ThreadState = "TERMINATED"
+	StateVM           ThreadState = "VM" // JVM-internal threads (GC, compiler) have no Java state
+)
+
+// States in display order
+var States = []ThreadState{StateRunnable, StateBlocked, StateWaiting, StateTimedWaiting, StateNew, StateTerminated, StateVM}
+
+// ThreadDump is one jstack / jcmd Thread.print snapshot
+type ThreadDump struct {
+	Filename   string
+	Timestamp  time.Time // Zero if the dump has no date line
+	JVMVersion string    // e.g. "OpenJDK 64-Bit Server VM (17.0.2+8-86 mixed mode, sharing)"
+	Threads    []*Thread
+
+	JNIGlobalRefs     int
+	ReportedDeadlocks int // Deadlocks the JVM itself found and printed at the end of the dump
+}
+
+type Thread struct {
+	Name       string
+	Number     int // "#12" thread number, -1 if not printed (JVM-internal threads, JDK 7)
+	Daemon     bool
+	Priority   int
+	OSPriority int
+	CPUTime    time.Duration // cpu=, JDK 11+
+	Elapsed    time.Duration // elapsed=, JDK 11+
+	HasCPUTime bool
+	TID        string // JVM thread address
+	NID        string // Native (OS) thread ID
+	Status     string // Header text after nid, e.g. "waiting on condition"
+
+	State       ThreadState
+	StateDetail string // e.g. "parking", "on object monitor", "sleeping"
+
+	Frames []*StackFrame
+
+	WaitingToLock *Lock   // Monitor the thread is blocked entering
+	ParkedOn      *Lock   // java.util.concurrent synchronizer the thread is parked on
+	WaitingOn     *Lock   // Monitor released by Object.wait(), reacquired when notified
+	Synchronizers []*Lock // Locked ownable synchronizers (ReentrantLock and friends)
+}
+
+type StackFrame struct {
+	Method   string  // e.g. java.lang.Thread.sleep
+	Location string  // e.g. Main.java:10, java.base@17.0.2/Native Method
+	Locked   []*Lock // Monitors taken by this frame
+}
+
+// Lock is a monitor or synchronizer object, identified by its address
+type Lock struct {
+	Address   string // e.g. 0x000000071a8d3c48
+	ClassName string // e.g. java.util.concurrent.locks.ReentrantLock$NonfairSync
+}
+
+// IsJava reports whether the thread is a Java thread rather than a JVM-internal one
+func (t *Thread) IsJava() bool {
+	return t.State != StateVM
+}
+
+// BlockedOn returns the lock the thread is waiting to acquire, if any
+func (t *Thread) BlockedOn() *Lock {
+	if t.WaitingToLock != nil {
+		return t.WaitingToLock
+	}
+	return t.ParkedOn
+}
+
+// DisplayState is the state with its detail, e.g. "WAITING (parking)"
+func (t *Thread) DisplayState() string {
+	if t.StateDetail == "" {
+		return string(t.State)
+	}
+	return string(t.State) + " (" + t.StateDetail + ")"
+}
+
+func (f *StackFrame) String() string {
+	if f.Location == "" {
+		return f.Method
+	}
+	return f.Method + "(" + f.Location + ")"
+}
+
+func (l *Lock) String() string {
+	if l.ClassName == "" {
+		return "<" + l.Address + ">"
+	}
+	return "<" + l.Address + "> (a " + l.ClassName + ")"
+}
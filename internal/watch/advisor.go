@@ -0,0 +1,404 @@
+package watch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	AdvisoryWindow     = 10 * time.Minute // History the trend advisories compare halves of
+	MaxAdvisoriesShown = 3                // Advisories listed above the tabs; the rest are counted
+
+	headroomWarning  = 0.15 // Heap left after the last GC
+	headroomCritical = 0.05
+	trendGrowth      = 1.5  // Recent half of the window over the earlier half
+	minTrendRate     = 1.0  // MB/s below which a promotion trend is noise
+	liveSetGrowth    = 0.10 // Rise of the heap's low point, as a share of max heap
+	metaspaceWarning = 0.90
+)
+
+// Advisory is a condition the live analysis currently sees. It stays until the
+// condition clears, keeping the time it was first seen.
+type Advisory struct {
+	Level  string // "warning" or "critical"
+	Title  string // Stable across ticks, so an advisory keeps its Since
+	Detail string
+	Since  time.Time
+}
+
+// Advisor reruns the GC checks over the accumulated history on every snapshot
+type Advisor struct {
+	since  map[string]time.Time // Title -> when it was first seen
+	active []Advisory
+}
+
+func NewAdvisor() *Advisor {
+	return &Advisor{since: make(map[string]time.Time)}
+}
+
+// Update evaluates the checks, keeps the ones that still hold and drops the rest
+func (a *Advisor) Update(mp *MetricsProcessor, state *TabState, thresholds RateThresholds, now time.Time) []Advisory {
+	checks := []func(*MetricsProcessor, *TabState, RateThresholds) *Advisory{
+		checkHeapHeadroom,
+		checkLiveSet,
+		checkExhaustion,
+		checkOldFloor,
+		checkPromotionTrend,
+		checkAllocationRate,
+		checkGCOverhead,
+		checkLongPauses,
+		checkMetaspace,
+		checkFileDescriptors,
+		checkSwapping,
+		checkClassLoading,
+		checkCompilation,
+	}
+
+	var active []Advisory
+	seen := make(map[string]time.Time)
+	for _, check := range checks {
+		advisory := check(mp, state, thresholds)
+		if advisory == nil {
+			continue
+		}
+		advisory.Since = now
+		if since, ok := a.since[advisory.Title]; ok {
+			advisory.Since = since
+		}
+		seen[advisory.Title] = advisory.Since
+		active = append(active, *advisory)
+	}
+
+	// Critical first, then the longest standing
+	slices.SortStableFunc(active, func(x, y Advisory) int {
+		if x.Level != y.Level {
+			if x.Level == "critical" {
+				return -1
+			}
+			return 1
+		}
+		return x.Since.Compare(y.Since)
+	})
+
+	a.since = seen
+	a.active = active
+	return active
+}
+
+// Active returns the advisories of the last update
+func (a *Advisor) Active() []Advisory {
+	return a.active
+}
+
+func checkHeapHeadroom(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	if state.Memory.HeapMax <= 0 {
+		return nil
+	}
+
+	// What survived the last GC, where the pools report it
+	var live int64
+	for _, pool := range state.Memory.Pools {
+		if pool.Heap {
+			live += pool.Floor
+		}
+	}
+	if live == 0 {
+		live = state.Memory.HeapUsed
+	}
+
+	headroom := 1 - float64(live)/float64(state.Memory.HeapMax)
+	level := ""
+	switch {
+	case headroom < headroomCritical:
+		level = "critical"
+	case headroom < headroomWarning:
+		level = "warning"
+	default:
+		return nil
+	}
+	return &Advisory{
+		Level: level,
+		Title: "Heap headroom low",
+		Detail: fmt.Sprintf("%s of %s free after GC, under %s", utils.Precision(0).Percent(headroom*100),
+			utils.MemorySize(state.Memory.HeapMax), utils.Precision(0).Percent(headroomWarning*100)),
+	}
+}
+
+// checkLiveSet compares the heap's low point in the two halves of the window. The low
+// point is where a GC just ran, so a steady rise is live data piling up.
+func checkLiveSet(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	earlier, recent, ok := splitWindow(mp.dataStore.GetRecentHistory(AdvisoryWindow, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.heapMemory
+	}))
+	if !ok {
+		return nil
+	}
+
+	low := func(points []utils.TimeMap) float64 {
+		lowest := points[0].GetOrDefault("used_mb", 0)
+		for _, point := range points[1:] {
+			lowest = min(lowest, point.GetOrDefault("used_mb", 0))
+		}
+		return lowest
+	}
+	before, after := low(earlier), low(recent)
+
+	limit := utils.MemorySize(state.Memory.HeapMax).MB()
+	if limit <= 0 {
+		limit = before
+	}
+	if limit <= 0 || after-before < liveSetGrowth*limit {
+		return nil
+	}
+	return &Advisory{
+		Level: "warning",
+		Title: "Live heap growing",
+		Detail: fmt.Sprintf("Post-GC low rose from %s to %s in the last %d min; watch for a leak",
+			utils.FormatMB(before), utils.FormatMB(after), int(AdvisoryWindow.Minutes())),
+	}
+}
+
+func checkExhaustion(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	estimate := state.Memory.Exhaustion
+	level := estimate.Level()
+	if level == "" {
+		return nil
+	}
+	return &Advisory{
+		Level: level,
+		Title: "Heap exhaustion projected",
+		Detail: fmt.Sprintf("~%s left at the current trend (%s confidence); post-GC heap %s of %s usable, rising %s/min",
+			utils.FormatDuration(estimate.TimeLeft.Truncate(time.Second)), estimate.Confidence,
+			utils.FormatMB(estimate.Floor), utils.FormatMB(estimate.Usable), utils.FormatMB(estimate.FloorGrowth*60)),
+	}
+}
+
+func checkOldFloor(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	trend := state.Memory.FloorTrend
+	if !trend.Fitted() || trend.LeakSeverity == "none" {
+		return nil
+	}
+	return &Advisory{
+		Level: trend.LeakSeverity,
+		Title: "Old generation floor rising",
+		Detail: fmt.Sprintf("Old gen after GC grows %.1f MB/h (R² %.2f) over %s, now %s; suspect a leak",
+			trend.GrowthMBPerHour, trend.Confidence, utils.FormatDuration(trend.Span.Truncate(time.Minute)), utils.FormatMB(trend.Latest)),
+	}
+}
+
+func checkPromotionTrend(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	earlier, recent, ok := splitWindow(mp.dataStore.GetRecentHistory(AdvisoryWindow, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.gcRates
+	}))
+	if !ok {
+		return nil
+	}
+
+	before, after := averageField(earlier, "promotion_mb_s"), averageField(recent, "promotion_mb_s")
+	if after < minTrendRate || after < before*trendGrowth {
+		return nil
+	}
+
+	level := "warning"
+	if thresholds.Promotion > 0 && after > thresholds.Promotion {
+		level = "critical"
+	}
+	return &Advisory{
+		Level: level,
+		Title: "Promotion trending up",
+		Detail: fmt.Sprintf("%s/s over the last %d min, up from %s/s", utils.FormatMB(after),
+			int(AdvisoryWindow.Minutes()/2), utils.FormatMB(before)),
+	}
+}
+
+func checkAllocationRate(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	if thresholds.Allocation <= 0 {
+		return nil
+	}
+	rates := mp.dataStore.GetRecentHistory(time.Minute, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.gcRates
+	})
+	if len(rates) == 0 {
+		return nil
+	}
+
+	rate := averageField(rates, "allocation_mb_s")
+	if rate <= thresholds.Allocation {
+		return nil
+	}
+	level := "warning"
+	if rate > gc.AllocRateCritical {
+		level = "critical"
+	}
+	return &Advisory{
+		Level:  level,
+		Title:  "Allocation rate high",
+		Detail: fmt.Sprintf("%s/s over the last minute, above %s/s", utils.FormatMB(rate), utils.FormatMB(thresholds.Allocation)),
+	}
+}
+
+func checkGCOverhead(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	overhead := mp.gcTracker.CalculateGCOverhead(alertWindow)
+	level := ""
+	switch {
+	case overhead*100 > 100-gc.ThroughputPoor:
+		level = "critical"
+	case overhead*100 > 100-gc.ThroughputGood:
+		level = "warning"
+	default:
+		return nil
+	}
+	return &Advisory{
+		Level:  level,
+		Title:  "GC overhead high",
+		Detail: fmt.Sprintf("%s of the last %d min spent in GC", utils.FormatPercent(overhead*100), int(alertWindow.Minutes())),
+	}
+}
+
+func checkLongPauses(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	pause := mp.gcTracker.GetMaxPause(alertWindow)
+	level := ""
+	switch {
+	case pause >= gc.PauseCritical:
+		level = "critical"
+	case pause >= gc.PausePoor:
+		level = "warning"
+	default:
+		return nil
+	}
+	return &Advisory{
+		Level: level,
+		Title: "Long GC pauses",
+		Detail: fmt.Sprintf("%s pause in the last %d min, %d over %s", utils.FormatDuration(pause), int(alertWindow.Minutes()),
+			mp.gcTracker.GetLongPauses(gc.PausePoor, alertWindow), utils.FormatDuration(gc.PausePoor)),
+	}
+}
+
+func checkMetaspace(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	for _, pool := range state.Memory.Pools {
+		if !strings.Contains(strings.ToLower(pool.Name), "metaspace") || pool.Max <= 0 {
+			continue
+		}
+		usage := float64(pool.Used) / float64(pool.Max)
+		if usage < metaspaceWarning {
+			return nil
+		}
+		return &Advisory{
+			Level:  "warning",
+			Title:  "Metaspace nearly full",
+			Detail: fmt.Sprintf("%s of %s used", utils.MemorySize(pool.Used), utils.MemorySize(pool.Max)),
+		}
+	}
+	return nil
+}
+
+func checkFileDescriptors(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	system := state.System
+	level := resourceLevel(system.FileDescriptorPercent, fdWarning, fdCritical)
+	if system.MaxFileDescriptors <= 0 || level == "" {
+		return nil
+	}
+	return &Advisory{
+		Level: level,
+		Title: "File descriptors running out",
+		Detail: fmt.Sprintf("%d of %d open; the JVM fails to open files and sockets at the limit",
+			system.OpenFileDescriptors, system.MaxFileDescriptors),
+	}
+}
+
+// checkSwapping warns once swap use is past its threshold or still growing, since
+// a GC touching swapped-out heap pages stalls for far longer than its usual pause
+func checkSwapping(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	system := state.System
+	if system.TotalSwap <= 0 || system.UsedSwap <= 0 {
+		return nil
+	}
+
+	history := mp.dataStore.GetRecentHistory(alertWindow, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.systemUsage
+	})
+	var growth float64
+	if len(history) > 1 {
+		growth = history[len(history)-1].GetOrDefault("swap", 0) - history[0].GetOrDefault("swap", 0)
+	}
+
+	level := resourceLevel(system.SwapPercent, swapWarning, swapCritical)
+	if level == "" && growth <= 0 {
+		return nil
+	}
+	if level == "" {
+		level = "warning"
+	}
+	detail := fmt.Sprintf("%s of swap in use", utils.MemorySize(system.UsedSwap))
+	if growth > 0 {
+		detail += fmt.Sprintf(", up %s in the last %d min", utils.FormatMB(growth*1024), int(alertWindow.Minutes()))
+	}
+	return &Advisory{Level: level, Title: "System is swapping", Detail: detail}
+}
+
+func checkClassLoading(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	return classLoadingAdvisory(state.Classes)
+}
+
+// checkCompilation leaves JIT warm-up to the Classes tab; only a storm past it is advice
+func checkCompilation(mp *MetricsProcessor, state *TabState, thresholds RateThresholds) *Advisory {
+	if advisory := compilationAdvisory(state.Classes); advisory != nil && advisory.Level != "info" {
+		return advisory
+	}
+	return nil
+}
+
+// splitWindow halves the history by time, once it covers at least half the window
+func splitWindow(points []utils.TimeMap) (earlier, recent []utils.TimeMap, ok bool) {
+	if len(points) < 4 {
+		return nil, nil, false
+	}
+	first, last := points[0].Timestamp, points[len(points)-1].Timestamp
+	if last.Sub(first) < AdvisoryWindow/2 {
+		return nil, nil, false
+	}
+
+	middle := first.Add(last.Sub(first) / 2)
+	split, _ := slices.BinarySearchFunc(points, middle, func(point utils.TimeMap, t time.Time) int {
+		return point.Timestamp.Compare(t)
+	})
+	if split == 0 || split == len(points) {
+		return nil, nil, false
+	}
+	return points[:split], points[split:], true
+}
+
+func averageField(points []utils.TimeMap, field string) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	var total float64
+	for _, point := range points {
+		total += point.GetOrDefault(field, 0)
+	}
+	return total / float64(len(points))
+}
+
+// renderAdvisories lists the active advisories, most severe first, one line each
+func renderAdvisories(advisories []Advisory, width int, now time.Time) string {
+	if len(advisories) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, advisory := range advisories[:min(len(advisories), MaxAdvisoriesShown)] {
+		title := utils.GetSeverityStyle(advisory.Level).Render(utils.GetSeverityIcon(advisory.Level) + " " + advisory.Title)
+		detail := fmt.Sprintf(" · %s (for %s)", advisory.Detail, utils.FormatDuration(now.Sub(advisory.Since).Truncate(time.Second)))
+		lines = append(lines, title+utils.MutedStyle.Render(utils.TruncateString(detail, max(width-lipgloss.Width(title), 0))))
+	}
+	if hidden := len(advisories) - MaxAdvisoriesShown; hidden > 0 {
+		lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("   +%d more", hidden)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,252 @@
+package watch
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	heapAlertThreshold  = 0.90            // Heap used / max
+	gcOverheadThreshold = 0.20            // Share of wall time, where the GC tab turns critical
+	longPauseThreshold  = 1 * time.Second // Where the GC tab calls pressure high
+	alertWindow         = 5 * time.Minute
+)
+
+/*
+ * A condition that holds for an hour must not post to the webhook every
+ * poll. Each alert follows its rule instead: it's posted when its breach
+ * begins, as a warning unless the rule escalates at once, and again only
+ * when it turns critical after lasting escalateAfter, or as a reminder once
+ * cooldown passes. Once the breach has been gone for clearAfter a
+ * resolution is posted, and the next breach starts over. Alerts computed
+ * over alertWindow already lag their cause, so they clear at once.
+ */
+
+// alertRule is how one metric's alert repeats, escalates and clears
+type alertRule struct {
+	cooldown      time.Duration // Between reminders of a breach that hasn't changed level
+	escalateAfter time.Duration // Breach that turns a warning critical; 0 is critical from the start
+	clearAfter    time.Duration // Without a breach, before it's resolved
+}
+
+var alertRules = map[string]alertRule{
+	"heap_usage":  {cooldown: 15 * time.Minute, escalateAfter: 2 * time.Minute, clearAfter: time.Minute},
+	"gc_overhead": {cooldown: 15 * time.Minute, escalateAfter: alertWindow},
+	"gc_pause":    {cooldown: 15 * time.Minute, escalateAfter: 2 * alertWindow},
+	"deadlock":    {cooldown: time.Hour},
+}
+
+// alertState is a breach that was posted and hasn't been resolved yet
+type alertState struct {
+	since     time.Time // When the breach began
+	lastSeen  time.Time // Latest snapshot still in breach
+	lastFired time.Time
+	fired     PerformanceAlert // Last one posted
+}
+
+type AlertTracker struct {
+	active map[string]*alertState // By MetricName
+}
+
+func NewAlertTracker() *AlertTracker {
+	return &AlertTracker{active: make(map[string]*alertState)}
+}
+
+// Check returns the alerts to post for the snapshot: breaches that began, escalated or are
+// due a reminder, and resolutions of those that cleared
+func (at *AlertTracker) Check(mp *MetricsProcessor, metrics *jmx.MBeanSnapshot) []PerformanceAlert {
+	if !metrics.Connected {
+		return nil
+	}
+
+	alerts := at.breaches(mp, metrics)
+	now := metrics.Timestamp
+
+	var fired []PerformanceAlert
+	breaching := make(map[string]bool)
+	for _, alert := range alerts {
+		rule := alertRules[alert.MetricName]
+		breaching[alert.MetricName] = true
+		state, exists := at.active[alert.MetricName]
+		if !exists {
+			state = &alertState{since: now}
+			at.active[alert.MetricName] = state
+		}
+		state.lastSeen = now
+
+		lasted := now.Sub(state.since)
+		if rule.escalateAfter > 0 && lasted < rule.escalateAfter {
+			alert.Level = "warning"
+		}
+		switch {
+		case !exists || alertRank[alert.Level] > alertRank[state.fired.Level]:
+		case now.Sub(state.lastFired) >= rule.cooldown:
+			alert.Description += fmt.Sprintf(" (ongoing for %s)", utils.FormatDuration(lasted.Truncate(time.Second)))
+		default:
+			continue // Already posted at this level
+		}
+		if exists && alertRank[alert.Level] > alertRank[state.fired.Level] {
+			alert.Description += fmt.Sprintf(" (escalated after %s)", utils.FormatDuration(lasted.Truncate(time.Second)))
+		}
+		state.lastFired = now
+		state.fired = alert
+		fired = append(fired, alert)
+	}
+
+	names := slices.Sorted(maps.Keys(at.active))
+	for _, name := range names {
+		state := at.active[name]
+		if breaching[name] || now.Sub(state.lastSeen) < alertRules[name].clearAfter {
+			continue
+		}
+		delete(at.active, name)
+		fired = append(fired, PerformanceAlert{
+			Level:       "info",
+			Resolved:    true,
+			Title:       state.fired.Title,
+			Description: fmt.Sprintf("Resolved after %s", utils.FormatDuration(state.lastSeen.Sub(state.since).Truncate(time.Second))),
+			Timestamp:   now,
+			Threshold:   state.fired.Threshold,
+			MetricName:  name,
+		})
+	}
+	return fired
+}
+
+// alertRank orders levels, so an escalation is told from a reminder
+var alertRank = map[string]int{"info": 0, "warning": 1, "critical": 2}
+
+// breaches is every alert the snapshot is in breach of, each at its rule's full level
+func (at *AlertTracker) breaches(mp *MetricsProcessor, metrics *jmx.MBeanSnapshot) []PerformanceAlert {
+	var alerts []PerformanceAlert
+	now := metrics.Timestamp
+
+	heap := metrics.Memory.Heap
+	if heap.Max > 0 {
+		usage := float64(heap.Used) / float64(heap.Max)
+		if usage >= heapAlertThreshold {
+			alerts = append(alerts, PerformanceAlert{
+				Level: "critical",
+				Title: "Heap nearly full",
+				Description: fmt.Sprintf("%s of %s used (%s)", utils.MemorySize(heap.Used),
+					utils.MemorySize(heap.Max), utils.Precision(0).Percent(usage*100)),
+				Timestamp:  now,
+				Value:      usage,
+				Threshold:  heapAlertThreshold,
+				MetricName: "heap_usage",
+			})
+		}
+	}
+
+	if overhead := mp.gcTracker.CalculateGCOverhead(alertWindow); overhead >= gcOverheadThreshold {
+		alerts = append(alerts, PerformanceAlert{
+			Level:       "critical",
+			Title:       "GC overhead critical",
+			Description: fmt.Sprintf("%s of the last %s spent in GC", utils.FormatPercent(overhead*100), alertWindow),
+			Timestamp:   now,
+			Value:       overhead,
+			Threshold:   gcOverheadThreshold,
+			MetricName:  "gc_overhead",
+		})
+	}
+
+	if pause := mp.gcTracker.GetMaxPause(alertWindow); pause >= longPauseThreshold {
+		alerts = append(alerts, PerformanceAlert{
+			Level:       "critical",
+			Title:       "Long GC pause",
+			Description: fmt.Sprintf("%s pause in the last %s", utils.FormatDuration(pause), alertWindow),
+			Timestamp:   now,
+			Value:       pause.Seconds(),
+			Threshold:   longPauseThreshold.Seconds(),
+			MetricName:  "gc_pause",
+		})
+	}
+
+	if deadlocked := len(metrics.Threading.DeadlockedThreads); deadlocked > 0 {
+		alerts = append(alerts, PerformanceAlert{
+			Level:       "critical",
+			Title:       "Deadlock detected",
+			Description: fmt.Sprintf("%d threads deadlocked", deadlocked),
+			Timestamp:   now,
+			Value:       float64(deadlocked),
+			MetricName:  "deadlock",
+		})
+	}
+
+	return alerts
+}
+
+// NewAlertSummary describes fired alerts with the recent heap and pause history
+func NewAlertSummary(target string, alerts []PerformanceAlert, state *TabState, mp *MetricsProcessor) *notify.Summary {
+	counts := make(map[string]int)
+	for _, alert := range alerts {
+		if alert.Resolved {
+			counts["resolved"]++
+		} else {
+			counts[alert.Level]++
+		}
+	}
+	var verdict []string
+	for _, kind := range []string{"critical", "warning", "resolved"} {
+		if counts[kind] > 0 {
+			verdict = append(verdict, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+
+	// Most severe first, so the title names the worst
+	alerts = slices.Clone(alerts)
+	slices.SortStableFunc(alerts, func(a, b PerformanceAlert) int { return alertRank[b.Level] - alertRank[a.Level] })
+	icon := "🚨"
+	switch {
+	case alerts[0].Resolved:
+		icon = "✅"
+	case alerts[0].Level == "warning":
+		icon = "⚠️"
+	}
+
+	summary := &notify.Summary{
+		Title:   fmt.Sprintf("%s %s", icon, alerts[0].Title),
+		Source:  target,
+		Score:   notify.NoScore,
+		Verdict: fmt.Sprintf("%s alert(s) from jdiag watch", strings.Join(verdict, ", ")),
+		Metrics: []notify.Metric{
+			{Name: "Heap", Value: fmt.Sprintf("%s / %s", utils.MemorySize(state.Memory.HeapUsed),
+				utils.MemorySize(state.Memory.HeapMax))},
+			{Name: "GC overhead", Value: utils.FormatPercent(mp.gcTracker.CalculateGCOverhead(alertWindow) * 100)},
+			{Name: "Threads", Value: fmt.Sprintf("%d", state.Threads.CurrentThreadCount)},
+			{Name: "CPU", Value: utils.Precision(0).Percent(max(state.System.ProcessCpuLoad, 0) * 100)},
+		},
+	}
+	for _, alert := range alerts {
+		title := alert.Title
+		if alert.Resolved {
+			title = "Resolved: " + title
+		}
+		summary.Issues = append(summary.Issues, notify.Issue{
+			Severity:    alert.Level,
+			Title:       title,
+			Description: alert.Description,
+		})
+	}
+
+	var heapUsed []float64
+	for _, point := range mp.dataStore.GetRecentDataField(alertWindow, "used_mb") {
+		heapUsed = append(heapUsed, point.Value)
+	}
+	var pauses []float64
+	for _, event := range mp.gcTracker.GetRecentEvents(50) {
+		pauses = append(pauses, float64(event.Duration.Microseconds())/1000)
+	}
+	summary.Charts = []notify.Chart{
+		{Title: "Heap used, last 5 min", Values: heapUsed, Unit: " MB"},
+		{Title: "Recent GC pauses", Values: pauses, Unit: " ms"},
+	}
+	return summary
+}
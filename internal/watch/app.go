@@ -8,11 +8,13 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/notify"
 	"github.com/mabhi256/jdiag/utils"
 )
 
-func StartTUI(config *jmx.Config) error {
-	model := initialModel(config)
+// StartTUI runs the watch TUI until the user quits and returns what the session saw
+func StartTUI(config *jmx.Config, notifier *notify.Notifier, thresholds RateThresholds) (*SessionSummary, error) {
+	model := initialModel(config, notifier, thresholds)
 
 	program := tea.NewProgram(
 		model,
@@ -22,13 +24,17 @@ func StartTUI(config *jmx.Config) error {
 
 	// Run the program
 	if _, err := program.Run(); err != nil {
-		return fmt.Errorf("TUI error: %w", err)
+		return nil, fmt.Errorf("TUI error: %w", err)
 	}
 
-	return nil
+	return model.session, nil
 }
 
 func (m *Model) View() string {
+	return utils.ToASCII(m.render())
+}
+
+func (m *Model) render() string {
 	if m.width == 0 || m.height == 0 {
 		return ""
 	}
@@ -51,6 +57,10 @@ func (m *Model) renderMonitorView() string {
 	header := m.renderHeader()
 	tabBar := m.renderTabBar()
 	helpView := m.help.View(keys)
+	view := m.viewFrame()
+	if advisories := renderAdvisories(view.advisories, m.width, view.time); advisories != "" {
+		tabBar = lipgloss.JoinVertical(lipgloss.Left, advisories, tabBar)
+	}
 
 	// Calculate available height for content area
 	headerHeight := lipgloss.Height(header)
@@ -75,19 +85,27 @@ func (m *Model) renderActiveTab() string {
 		return utils.CriticalStyle.Render("No connection to JVM")
 	}
 
+	view := m.viewFrame()
+	state := m.viewState(view)
+
 	switch m.activeTab {
 	case TabMemory:
 		heapHistory := m.GetHistoricalHeapMemory(5 * time.Minute)
-		return RenderMemoryTab(m.tabState, m.width, heapHistory)
+		return RenderMemoryTab(state, m.width, heapHistory, m.GetHistoricalOldFloors())
+	case TabPools:
+		return RenderPoolsTab(state, m.width)
 	case TabGC:
-		return RenderGCTab(m.tabState, m.metricsProcessor.gcTracker, m.width)
+		rateHistory := m.GetHistoricalGCRates(5 * time.Minute)
+		return RenderGCTab(state, view.tracker, m.width, rateHistory, m.thresholds)
 	case TabThreads:
-		classHistory := m.GetHistoricalClassCount(5 * time.Minute)
 		threadHistory := m.GetHistoricaThreadCount(5 * time.Minute)
-		return RenderThreadsTab(m.tabState, m.width, classHistory, threadHistory)
+		return RenderThreadsTab(state, m.width, threadHistory)
+	case TabClasses:
+		classHistory := m.GetHistoricalClassCount(5 * time.Minute)
+		return RenderClassesTab(state, m.width, classHistory)
 	case TabSystem:
 		systemHistory := m.GetHistoricalSystemUsage(5 * time.Minute)
-		return RenderSystemTab(m.tabState, m.config, m.width, systemHistory)
+		return RenderSystemTab(state, m.config, m.width, systemHistory)
 	default:
 		return utils.CriticalStyle.Render("Unknown tab")
 	}
@@ -126,6 +144,12 @@ func (m *Model) getStatus() string {
 		if m.errorMessage != "" {
 			status = utils.WarningStyle.Render("⚠️ Connected (Warning)")
 		}
+		if m.paused {
+			status += " • " + m.pauseStatus()
+		}
+		if m.exportMessage != "" && time.Since(m.exportTime) < exportDuration {
+			status += " • " + m.exportMessage
+		}
 	} else {
 		status = utils.CriticalStyle.Render("🔴 Disconnected")
 		if m.errorMessage != "" {
@@ -0,0 +1,211 @@
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	classChurnRate    = 100.0            // Classes unloaded per minute, while as many load, that reads as churn
+	classLeakRate     = 100.0            // Classes loaded per minute, with hardly any unloading, that reads as a leak
+	classLeakUnloaded = 0.10             // Share of the loaded classes unloaded below which loading reads as a leak
+	compilationStorm  = 0.5              // Compile time per second of wall time
+	jitWarmup         = 10 * time.Minute // Uptime during which heavy loading and compiling is expected
+)
+
+// RenderClassesTab charts class loading and JIT compilation, flagging churn and storms
+func RenderClassesTab(state *TabState, width int, classHistory []utils.TimeMap) string {
+	classes := state.Classes
+	var sections []string
+
+	if flags := classFlags(classes); len(flags) > 0 {
+		var lines []string
+		for _, flag := range flags {
+			lines = append(lines, utils.GetSeverityStyle(flag.Level).Render(utils.GetSeverityIcon(flag.Level)+" "+flag.Title)+
+				utils.MutedStyle.Render(" · "+flag.Detail))
+		}
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, lines...), "")
+	}
+
+	chartWidth := width - 20
+	sections = append(sections,
+		renderLoadedClassesChart(classes, chartWidth, classHistory),
+		renderClassActivityChart(classes, chartWidth, classHistory),
+		renderCompilationChart(classes, chartWidth, classHistory))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// classFlags are the class loading and compilation conditions worth a look
+func classFlags(classes *ClassState) []Advisory {
+	var flags []Advisory
+	if flag := classLoadingAdvisory(classes); flag != nil {
+		flags = append(flags, *flag)
+	}
+	if flag := compilationAdvisory(classes); flag != nil {
+		flags = append(flags, *flag)
+	}
+	return flags
+}
+
+// classLoadingAdvisory tells churn, generated classes that load and unload again (dynamic
+// proxies, script engines, reflection accessors), from a leak, where they only pile up
+func classLoadingAdvisory(classes *ClassState) *Advisory {
+	if classes.RateWindow < alertWindow/2 || classes.JVMUptime < jitWarmup {
+		return nil
+	}
+
+	loading, unloading := classes.ClassLoadingRate, classes.ClassUnloadingRate
+	window := utils.FormatDuration(classes.RateWindow.Truncate(time.Second))
+	switch {
+	case unloading >= classChurnRate && loading >= classChurnRate:
+		return &Advisory{
+			Level: "warning",
+			Title: "Class loading churn",
+			Detail: fmt.Sprintf("%s loaded and %s unloaded per minute over %s; look for generated proxies or script engines",
+				utils.FormatFloat(loading), utils.FormatFloat(unloading), window),
+		}
+	case loading >= classLeakRate && unloading < loading*classLeakUnloaded:
+		return &Advisory{
+			Level: "warning",
+			Title: "Classes piling up",
+			Detail: fmt.Sprintf("%s loaded per minute over %s and few unloaded; a class loader may be leaking",
+				utils.FormatFloat(loading), window),
+		}
+	}
+	return nil
+}
+
+// compilationAdvisory flags the JIT busy for much of the wall time. Early on that is
+// warm-up after a deployment; later it means code is being deoptimized and recompiled.
+func compilationAdvisory(classes *ClassState) *Advisory {
+	if !classes.CompilationSupported || classes.CompilationShare < compilationStorm {
+		return nil
+	}
+
+	share := utils.FormatPercent(classes.CompilationShare * 100)
+	if classes.JVMUptime < jitWarmup {
+		return &Advisory{
+			Level:  "info",
+			Title:  "JIT warm-up",
+			Detail: fmt.Sprintf("Compiling for %s of the last minute, %s after start", share, utils.FormatDuration(classes.JVMUptime.Truncate(time.Second))),
+		}
+	}
+	return &Advisory{
+		Level:  "warning",
+		Title:  "Compilation storm",
+		Detail: fmt.Sprintf("Compiling for %s of the last minute; check for deoptimization or a redeploy", share),
+	}
+}
+
+func renderLoadedClassesChart(classes *ClassState, width int, classHistory []utils.TimeMap) string {
+	valuesText := fmt.Sprintf("Loaded: %d | Unloaded: %d | Loaded Since Start: %d",
+		classes.LoadedClassCount,
+		classes.UnloadedClassCount,
+		classes.TotalLoadedClasses)
+
+	chart := utils.NewChart(max(width-10, 30), 8)
+	for _, point := range classHistory {
+		chart.Push(utils.TimePoint{
+			Time:  point.Timestamp,
+			Value: point.GetOrDefault("loaded_count", 0),
+		})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.GoodColor))
+	chart.DrawBrailleAll()
+
+	legend := lipgloss.NewStyle().Foreground(utils.GoodColor).Render("■ Loaded")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		utils.InfoStyle.Render("Classes"),
+		lipgloss.JoinHorizontal(lipgloss.Left, chart.View(), "", legend),
+		utils.MutedStyle.Render(valuesText),
+		"", // Empty line for spacing
+	)
+}
+
+// renderClassActivityChart charts the classes loaded and unloaded between polls, per minute
+func renderClassActivityChart(classes *ClassState, width int, classHistory []utils.TimeMap) string {
+	valuesText := fmt.Sprintf("Loading: %s/min | Unloading: %s/min",
+		utils.FormatFloat(classes.ClassLoadingRate),
+		utils.FormatFloat(classes.ClassUnloadingRate))
+	if classes.RateWindow > 0 {
+		valuesText += fmt.Sprintf(" | Over: %s", utils.FormatDuration(classes.RateWindow.Truncate(time.Second)))
+	}
+
+	chart := utils.NewChart(max(width-10, 30), 8)
+	for i := 1; i < len(classHistory); i++ {
+		previous, point := classHistory[i-1], classHistory[i]
+		minutes := point.Timestamp.Sub(previous.Timestamp).Minutes()
+		if minutes <= 0 {
+			continue
+		}
+		chart.Push(utils.TimePoint{
+			Time:  point.Timestamp,
+			Value: (point.GetOrDefault("total_loaded", 0) - previous.GetOrDefault("total_loaded", 0)) / minutes,
+		})
+		chart.PushDataSet("unloaded", utils.TimePoint{
+			Time:  point.Timestamp,
+			Value: (point.GetOrDefault("unloaded_count", 0) - previous.GetOrDefault("unloaded_count", 0)) / minutes,
+		})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.InfoColor))
+	chart.SetDataSetStyle("unloaded", lipgloss.NewStyle().Foreground(utils.WarningColor))
+	chart.DrawBrailleAll()
+
+	loadedLegend := lipgloss.NewStyle().Foreground(utils.InfoColor).Render("■ Loaded/min")
+	unloadedLegend := lipgloss.NewStyle().Foreground(utils.WarningColor).Render("■ Unloaded/min")
+	legend := lipgloss.JoinHorizontal(lipgloss.Left, loadedLegend, "  ", unloadedLegend)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		utils.InfoStyle.Render("Class Loading Activity"),
+		lipgloss.JoinHorizontal(lipgloss.Left, chart.View(), "", legend),
+		utils.MutedStyle.Render(valuesText),
+		"", // Empty line for spacing
+	)
+}
+
+// renderCompilationChart charts JIT compile time per second of wall time between polls
+func renderCompilationChart(classes *ClassState, width int, classHistory []utils.TimeMap) string {
+	title := utils.InfoStyle.Render("JIT Compilation")
+	switch {
+	case classes.Compiler == "":
+		return lipgloss.JoinVertical(lipgloss.Left, title, utils.MutedStyle.Render("This JVM runs without a JIT compiler"))
+	case !classes.CompilationSupported:
+		return lipgloss.JoinVertical(lipgloss.Left, title,
+			utils.MutedStyle.Render(fmt.Sprintf("%s doesn't report compilation time", classes.Compiler)))
+	}
+
+	valuesText := fmt.Sprintf("Compiler: %s | Total: %s | Last minute: %s busy",
+		classes.Compiler,
+		utils.FormatDuration(classes.CompilationTime),
+		utils.FormatPercent(classes.CompilationShare*100))
+
+	chart := utils.NewChart(max(width-10, 30), 8)
+	for i := 1; i < len(classHistory); i++ {
+		previous, point := classHistory[i-1], classHistory[i]
+		elapsed := point.Timestamp.Sub(previous.Timestamp).Milliseconds()
+		if elapsed <= 0 {
+			continue
+		}
+		compiled := point.GetOrDefault("compilation_ms", 0) - previous.GetOrDefault("compilation_ms", 0)
+		chart.Push(utils.TimePoint{
+			Time:  point.Timestamp,
+			Value: compiled / float64(elapsed) * 100,
+		})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.InfoColor))
+	chart.DrawBrailleAll()
+
+	legend := lipgloss.NewStyle().Foreground(utils.InfoColor).Render("■ % of wall time compiling")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		lipgloss.JoinHorizontal(lipgloss.Left, chart.View(), "", legend),
+		utils.MutedStyle.Render(valuesText),
+		"", // Empty line for spacing
+	)
+}
@@ -0,0 +1,149 @@
+package watch
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MinExhaustionSamples = 4                // Post-GC floors needed before estimating
+	ExhaustionWarning    = 30 * time.Minute // Time left the advisory warns under
+	ExhaustionCritical   = 5 * time.Minute
+
+	floorFitGood      = 0.8 // R² above which the floor's rise is a trend rather than noise
+	floorSamplesGood  = 10
+	oldGrowthAgrees   = 0.5 // Old generation growth, as a share of the floor's, that backs it
+	maxAllocationRoom = 0.5 // Share of the heap the allocation between GCs may claim
+)
+
+/*
+ * The heap runs out when what survives each GC, the post-GC floor, no longer
+ * leaves room to allocate into until the next one. The floor's rise over the
+ * advisory window, fitted by least squares, says how fast that room shrinks;
+ * the allocation rate times the interval between young GCs says how much of
+ * it the application needs, so the floor gives out before it reaches the max.
+ *
+ * Confidence follows how well the evidence hangs together: a floor that
+ * rises steadily rather than jumping, enough collections to fit, and the old
+ * generation, where a leak ends up, growing along with it. A floor that
+ * rises while old stays flat is young data caught mid-flight, not a leak.
+ */
+
+// ExhaustionEstimate projects when the heap runs out at the current trend
+type ExhaustionEstimate struct {
+	Rising         bool          // The post-GC floor is growing; TimeLeft is meaningless otherwise
+	TimeLeft       time.Duration // Until the floor reaches Usable
+	Confidence     string        // "low", "medium" or "high"
+	Floor          float64       // Latest post-GC heap, MB
+	Usable         float64       // Heap the floor can grow into before allocation runs out of room, MB
+	FloorGrowth    float64       // MB/s
+	OldGrowth      float64       // Post-GC old generation growth, MB/s
+	AllocationRate float64       // MB/s over the last minute
+	Samples        int
+}
+
+// estimateExhaustion fits the post-GC floors of the advisory window; nil until there are enough
+func (mp *MetricsProcessor) estimateExhaustion(memory *MemoryState) *ExhaustionEstimate {
+	floors := mp.dataStore.GetRecentHistory(AdvisoryWindow, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.heapFloors
+	})
+	if len(floors) < MinExhaustionSamples || memory.HeapMax <= 0 {
+		return nil
+	}
+
+	estimate := &ExhaustionEstimate{
+		Floor:   floors[len(floors)-1].GetOrDefault("floor_mb", 0),
+		Samples: len(floors),
+	}
+	var fit float64
+	estimate.FloorGrowth, fit = fitRate(floors, "floor_mb")
+	estimate.OldGrowth, _ = fitRate(floors, "old_mb")
+	estimate.AllocationRate = averageField(mp.dataStore.GetRecentHistory(time.Minute, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.gcRates
+	}), "allocation_mb_s")
+
+	heapMax := utils.MemorySize(memory.HeapMax).MB()
+	var room float64
+	if perMinute := mp.gcTracker.GetGCFrequencyByGeneration("young", alertWindow); perMinute > 0 {
+		room = estimate.AllocationRate * 60 / perMinute
+	}
+	estimate.Usable = heapMax - min(room, heapMax*maxAllocationRoom)
+
+	estimate.Rising = estimate.FloorGrowth > 0
+	if estimate.Rising {
+		left := max(estimate.Usable-estimate.Floor, 0) / estimate.FloorGrowth
+		estimate.TimeLeft = time.Duration(left * float64(time.Second))
+	}
+
+	evidence := 0
+	if fit >= floorFitGood {
+		evidence++
+	}
+	if estimate.Samples >= floorSamplesGood {
+		evidence++
+	}
+	if estimate.Rising && estimate.OldGrowth >= oldGrowthAgrees*estimate.FloorGrowth {
+		evidence++
+	}
+	switch evidence {
+	case 3:
+		estimate.Confidence = "high"
+	case 2:
+		estimate.Confidence = "medium"
+	default:
+		estimate.Confidence = "low"
+	}
+	return estimate
+}
+
+// fitRate is the least squares slope of the field per second, and the fit's R²
+func fitRate(points []utils.TimeMap, field string) (slope, r2 float64) {
+	n := float64(len(points))
+	var sumX, sumY, sumXY, sumXX, sumYY float64
+	for _, point := range points {
+		x := point.Timestamp.Sub(points[0].Timestamp).Seconds()
+		y := point.GetOrDefault(field, 0)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+		sumYY += y * y
+	}
+	varX := n*sumXX - sumX*sumX
+	varY := n*sumYY - sumY*sumY
+	if varX <= 0 {
+		return 0, 0
+	}
+	covariance := n*sumXY - sumX*sumY
+	slope = covariance / varX
+	if varY > 0 {
+		r2 = covariance * covariance / (varX * varY)
+	}
+	return slope, r2
+}
+
+// Summary is the estimate in one line, for the Memory tab
+func (e *ExhaustionEstimate) Summary() string {
+	if !e.Rising {
+		return fmt.Sprintf("Post-GC heap steady at %s; no exhaustion in sight", utils.FormatMB(e.Floor))
+	}
+	return fmt.Sprintf("Heap exhausted in ~%s at the current trend (%s confidence) · post-GC floor +%s/min, old gen +%s/min, allocating %s/s",
+		utils.FormatDuration(e.TimeLeft.Truncate(time.Second)), e.Confidence,
+		utils.FormatMB(e.FloorGrowth*60), utils.FormatMB(math.Max(e.OldGrowth, 0)*60), utils.FormatMB(e.AllocationRate))
+}
+
+// Level is how urgent the estimate is: "critical", "warning", or "" while there's time or doubt
+func (e *ExhaustionEstimate) Level() string {
+	switch {
+	case e == nil || !e.Rising || e.Confidence == "low":
+		return ""
+	case e.TimeLeft < ExhaustionCritical:
+		return "critical"
+	case e.TimeLeft < ExhaustionWarning:
+		return "warning"
+	}
+	return ""
+}
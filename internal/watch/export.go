@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * An anomaly seen while watching is usually gone before anyone thinks to
+ * record it, so the data the tabs were drawn from can be saved from the TUI:
+ * the GC events the tracker still holds, over its 5 minute window, and every
+ * snapshot's history since watching started. JSON keeps both in one file;
+ * CSV takes two, one row per GC event and one per snapshot.
+ *
+ * Files go to the working directory, named for when they were saved, so
+ * saving twice never overwrites the first.
+ */
+
+// exportDuration is how long the header says where an export went
+const exportDuration = 5 * time.Second
+
+// WatchExport is what 'e' saves as JSON
+type WatchExport struct {
+	Target   string                     `json:"target"`
+	Exported time.Time                  `json:"exported"`
+	GCEvents []ExportedGCEvent          `json:"gcEvents"`
+	History  map[string][]ExportedPoint `json:"history"` // Series by name, oldest point first
+}
+
+type ExportedGCEvent struct {
+	ID                int64         `json:"id"`
+	Time              time.Time     `json:"time"`
+	Generation        string        `json:"generation"`
+	Duration          time.Duration `json:"durationNs"`
+	Before            int64         `json:"before"`
+	After             int64         `json:"after"`
+	Collected         int64         `json:"collected"`
+	SampledEfficiency *float64      `json:"sampledEfficiency,omitempty"` // Only the last collection before each poll is sampled
+}
+
+type ExportedPoint struct {
+	Time   time.Time          `json:"time"`
+	Values map[string]float64 `json:"values"`
+}
+
+// Series copies every history series by name, for export
+func (hds *HistoricalDataStore) Series() map[string][]utils.TimeMap {
+	hds.mu.RLock()
+	defer hds.mu.RUnlock()
+
+	return map[string][]utils.TimeMap{
+		"heap":       slices.Clone(hds.heapMemory),
+		"threads":    slices.Clone(hds.threadCounts),
+		"classes":    slices.Clone(hds.classCounts),
+		"system":     slices.Clone(hds.systemUsage),
+		"gcRates":    slices.Clone(hds.gcRates),
+		"heapFloors": slices.Clone(hds.heapFloors),
+		"oldFloors":  slices.Clone(hds.oldFloors),
+	}
+}
+
+// GetEvents returns every GC event still in the tracker's window
+func (get *GCEventTracker) GetEvents() []GCEvent {
+	get.mu.RLock()
+	defer get.mu.RUnlock()
+	return slices.Clone(get.gcEvents)
+}
+
+func (m *Model) buildExport(now time.Time) *WatchExport {
+	return newWatchExport(m.config.String(), m.metricsProcessor, now)
+}
+
+// newWatchExport copies the GC events the tracker holds and the whole history
+func newWatchExport(target string, mp *MetricsProcessor, now time.Time) *WatchExport {
+	export := &WatchExport{Target: target, Exported: now, History: make(map[string][]ExportedPoint)}
+	for _, event := range mp.gcTracker.GetEvents() {
+		exported := ExportedGCEvent{
+			ID:         event.Id,
+			Time:       event.Timestamp,
+			Generation: event.Generation,
+			Duration:   event.Duration,
+			Before:     event.Before,
+			After:      event.After,
+			Collected:  event.Collected,
+		}
+		if event.Sampled {
+			exported.SampledEfficiency = &event.SampledEfficiency
+		}
+		export.GCEvents = append(export.GCEvents, exported)
+	}
+	for name, series := range mp.dataStore.Series() {
+		points := make([]ExportedPoint, 0, len(series))
+		for _, point := range series {
+			points = append(points, ExportedPoint{Time: point.Timestamp, Values: point.Values})
+		}
+		export.History[name] = points
+	}
+	return export
+}
+
+// exportJSON saves the export in one JSON file and returns its name
+func (m *Model) exportJSON(now time.Time) (string, error) {
+	name := fmt.Sprintf("jdiag_watch_%s.json", now.Format("20060102_150405"))
+	data, err := json.MarshalIndent(m.buildExport(now), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode export: %w", err)
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+	return name, nil
+}
+
+// LoadExport reads a session saved with 'e' as JSON
+func LoadExport(path string) (*WatchExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read watch export: %w", err)
+	}
+
+	var export WatchExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%s is not a saved watch session: %w", path, err)
+	}
+	if len(export.History) == 0 && len(export.GCEvents) == 0 {
+		return nil, fmt.Errorf("%s has no history or GC events", path)
+	}
+	return &export, nil
+}
+
+// exportCSV saves the GC events and the history as two CSV files and returns their names
+func (m *Model) exportCSV(now time.Time) ([]string, error) {
+	export := m.buildExport(now)
+	prefix := fmt.Sprintf("jdiag_watch_%s", now.Format("20060102_150405"))
+
+	events := [][]string{{"id", "time", "generation", "duration_ms", "before_bytes", "after_bytes", "collected_bytes", "sampled_efficiency"}}
+	for _, event := range export.GCEvents {
+		efficiency := ""
+		if event.SampledEfficiency != nil {
+			efficiency = strconv.FormatFloat(*event.SampledEfficiency, 'f', 4, 64)
+		}
+		events = append(events, []string{
+			strconv.FormatInt(event.ID, 10),
+			event.Time.Format(time.RFC3339Nano),
+			event.Generation,
+			strconv.FormatFloat(float64(event.Duration)/float64(time.Millisecond), 'f', 3, 64),
+			strconv.FormatInt(event.Before, 10),
+			strconv.FormatInt(event.After, 10),
+			strconv.FormatInt(event.Collected, 10),
+			efficiency,
+		})
+	}
+
+	names := []string{prefix + "_gc.csv", prefix + "_history.csv"}
+	if err := writeCSV(names[0], events); err != nil {
+		return nil, err
+	}
+	if err := writeCSV(names[1], historyRows(export.History)); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// historyRows joins every series into one row per snapshot, a column per series value; a
+// value a snapshot didn't record is left empty
+func historyRows(history map[string][]ExportedPoint) [][]string {
+	columnSet := make(map[string]bool)
+	byTime := make(map[time.Time]map[string]float64)
+	for name, points := range history {
+		for _, point := range points {
+			row, exists := byTime[point.Time]
+			if !exists {
+				row = make(map[string]float64)
+				byTime[point.Time] = row
+			}
+			for field, value := range point.Values {
+				column := name + "." + field
+				columnSet[column] = true
+				row[column] = value
+			}
+		}
+	}
+
+	columns := slices.Sorted(maps.Keys(columnSet))
+	times := slices.SortedFunc(maps.Keys(byTime), time.Time.Compare)
+	rows := [][]string{append([]string{"time"}, columns...)}
+	for _, at := range times {
+		row := []string{at.Format(time.RFC3339Nano)}
+		for _, column := range columns {
+			value, exists := byTime[at][column]
+			if !exists {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func writeCSV(name string, rows [][]string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// setExportStatus shows where an export went, or why it failed
+func (m *Model) setExportStatus(names []string, err error) {
+	if err != nil {
+		m.setError(err.Error())
+		return
+	}
+	m.exportMessage = fmt.Sprintf("💾 Saved %s", names[0])
+	if len(names) > 1 {
+		m.exportMessage = fmt.Sprintf("💾 Saved %s and %s", names[0], names[1])
+	}
+	m.exportTime = time.Now()
+}
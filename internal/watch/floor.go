@@ -0,0 +1,187 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+const (
+	MinFloorSamples = 5                // Old generation floors needed before fitting a trend
+	MinFloorSpan    = 10 * time.Minute // and the time they must cover, so a burst isn't taken per hour
+)
+
+/*
+ * What the old generation holds right after a collection that covered it is
+ * the live data that outlasted every young collection: it only climbs for
+ * good when something keeps a hold of what it allocates. The pool's
+ * CollectionUsage reports it after each such collection (for G1 only mixed
+ * and full ones, as young collections leave old regions alone), so each
+ * change is a point; collectors whose old pool doesn't report it fall back
+ * to the old GC's own usage after.
+ *
+ * The points of the whole session are fitted by least squares in MB per
+ * hour and judged like the offline MemoryTrend: a fit above
+ * gc.LeakConfidenceThreshold growing past gc.LeakGrowthWarning or
+ * gc.LeakGrowthCritical is a leak.
+ */
+
+// FloorTrend is the old generation's post-collection occupancy over the session
+type FloorTrend struct {
+	Latest          float64       // MB
+	Max             float64       // MB; 0 when the old generation is unbounded
+	GrowthMBPerHour float64       // Slope of the fit
+	Intercept       float64       // Fit's MB at the first point
+	Confidence      float64       // R² of the fit
+	LeakSeverity    string        // "none", "warning" or "critical", as gc.MemoryTrend's
+	ProjectedFull   time.Duration // Until the fit reaches Max; 0 when it never does
+	Span            time.Duration
+	Samples         int
+}
+
+// oldPool is the old generation's pool, or the heap's only pool for collectors without generations
+func oldPool(metrics *jmx.MBeanSnapshot) (jmx.MemoryPool, bool) {
+	var heapPools []jmx.MemoryPool
+	for _, pool := range metrics.Memory.Pools {
+		if !pool.Heap {
+			continue
+		}
+		name := strings.ToLower(pool.Name)
+		if strings.Contains(name, "old") || strings.Contains(name, "tenured") {
+			return pool, true
+		}
+		heapPools = append(heapPools, pool)
+	}
+	if len(heapPools) == 1 {
+		return heapPools[0], true
+	}
+	return jmx.MemoryPool{}, false
+}
+
+// oldFloor is the old generation's usage after a collection that covered it, when one ran between the snapshots
+func oldFloor(previous, current *jmx.MBeanSnapshot) (used, limit int64, ok bool) {
+	pool, found := oldPool(current)
+	if !found {
+		return 0, 0, false
+	}
+	if pool.CollectionUsage != (jmx.MemoryUsage{}) {
+		before, _ := oldPool(previous)
+		return pool.CollectionUsage.Used, pool.Usage.Max, pool.CollectionUsage != before.CollectionUsage
+	}
+
+	lastOld := current.GC.LastOldGC
+	if current.GC.OldGCCount > previous.GC.OldGCCount && lastOld.IsValid() {
+		return lastOld.OldAfter, pool.Usage.Max, true
+	}
+	return 0, 0, false
+}
+
+// floorTrend fits the old generation floors up to end; nil before the first one
+func (mp *MetricsProcessor) floorTrend(end time.Time) *FloorTrend {
+	floors := mp.dataStore.OldFloorsUntil(end)
+	if len(floors) == 0 {
+		return nil
+	}
+
+	last := floors[len(floors)-1]
+	trend := &FloorTrend{
+		Latest:       last.GetOrDefault("used_mb", 0),
+		Max:          last.GetOrDefault("max_mb", 0),
+		LeakSeverity: "none",
+		Span:         last.Timestamp.Sub(floors[0].Timestamp),
+		Samples:      len(floors),
+	}
+	if trend.Samples < MinFloorSamples || trend.Span < MinFloorSpan {
+		return trend
+	}
+
+	hours := make([]float64, len(floors))
+	used := make([]float64, len(floors))
+	var meanHours, meanUsed float64
+	for i, point := range floors {
+		hours[i] = point.Timestamp.Sub(floors[0].Timestamp).Hours()
+		used[i] = point.GetOrDefault("used_mb", 0)
+		meanHours += hours[i] / float64(len(floors))
+		meanUsed += used[i] / float64(len(floors))
+	}
+	slope, correlation := utils.LinearRegression(hours, used)
+	trend.GrowthMBPerHour = slope
+	trend.Intercept = meanUsed - slope*meanHours
+	trend.Confidence = correlation * correlation
+
+	if slope > 0 && trend.Max > 0 {
+		trend.ProjectedFull = time.Duration(max(trend.Max-trend.Latest, 0) / slope * float64(time.Hour))
+	}
+	if trend.Confidence > gc.LeakConfidenceThreshold {
+		switch {
+		case slope > gc.LeakGrowthCritical:
+			trend.LeakSeverity = "critical"
+		case slope > gc.LeakGrowthWarning:
+			trend.LeakSeverity = "warning"
+		}
+	}
+	return trend
+}
+
+// Fitted reports whether there were enough floors, over long enough, for the fit to mean anything
+func (t *FloorTrend) Fitted() bool {
+	return t != nil && t.Samples >= MinFloorSamples && t.Span >= MinFloorSpan
+}
+
+// Summary is the trend in one line, for the Memory tab
+func (t *FloorTrend) Summary() string {
+	if !t.Fitted() {
+		return fmt.Sprintf("Old gen after GC %s · %d collections over %s; a trend needs %d over %s",
+			utils.FormatMB(t.Latest), t.Samples, utils.FormatDuration(t.Span.Truncate(time.Second)),
+			MinFloorSamples, utils.FormatDuration(MinFloorSpan))
+	}
+	line := fmt.Sprintf("Old gen after GC %s · %+.1f MB/h (R² %.2f) over %s",
+		utils.FormatMB(t.Latest), t.GrowthMBPerHour, t.Confidence, utils.FormatDuration(t.Span.Truncate(time.Second)))
+	if t.ProjectedFull > 0 {
+		line += fmt.Sprintf(" · full in ~%s", utils.FormatDuration(t.ProjectedFull.Truncate(time.Minute)))
+	}
+	if t.LeakSeverity != "none" {
+		line += " · leak suspected"
+	}
+	return line
+}
+
+// renderFloorTrend charts the old generation floors with the fitted trend under them
+func renderFloorTrend(trend *FloorTrend, history []utils.TimeMap, width int) string {
+	if trend == nil {
+		return ""
+	}
+	summary := utils.TruncateString("  "+trend.Summary(), width)
+	if trend.LeakSeverity != "none" {
+		summary = utils.GetSeverityStyle(trend.LeakSeverity).Render(summary)
+	} else {
+		summary = utils.MutedStyle.Render(summary)
+	}
+	if len(history) < 2 {
+		return summary
+	}
+
+	chart := utils.NewChart(max(width-10, 40), 6)
+	for _, point := range history {
+		chart.Push(utils.TimePoint{Time: point.Timestamp, Value: point.GetOrDefault("used_mb", 0)})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.WarningColor))
+
+	legend := "Old Gen After GC " + lipgloss.NewStyle().Foreground(utils.WarningColor).Render("■ Floor")
+	if trend.Fitted() {
+		first, last := history[0].Timestamp, history[len(history)-1].Timestamp
+		for _, at := range []time.Time{first, last} {
+			chart.PushDataSet("trend", utils.TimePoint{Time: at, Value: trend.Intercept + trend.GrowthMBPerHour*at.Sub(first).Hours()})
+		}
+		chart.SetDataSetStyle("trend", lipgloss.NewStyle().Foreground(utils.InfoColor))
+		legend += "  " + lipgloss.NewStyle().Foreground(utils.InfoColor).Render("■ Trend")
+	}
+	chart.DrawBrailleAll()
+
+	return lipgloss.JoinVertical(lipgloss.Left, legend, "", chart.View(), summary)
+}
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// MaxFrames is how many snapshots are kept for stepping through while paused
+const MaxFrames = 300
+
+// frame is what the tabs showed after one snapshot, kept so a paused view can go
+// back to it while collection carries on
+type frame struct {
+	time       time.Time
+	state      *TabState
+	tracker    *GCEventTracker
+	advisories []Advisory
+}
+
+// recordFrame keeps the snapshot just processed, dropping the oldest past MaxFrames
+func (m *Model) recordFrame(at time.Time) {
+	m.frames = append(m.frames, frame{
+		time:       at,
+		state:      m.tabState,
+		tracker:    m.metricsProcessor.gcTracker.Freeze(at),
+		advisories: m.advisor.Active(),
+	})
+	if len(m.frames) > MaxFrames {
+		m.frames = m.frames[len(m.frames)-MaxFrames:]
+		if m.paused {
+			m.frameIndex = max(m.frameIndex-1, 0)
+		}
+	}
+}
+
+// togglePause freezes the view on the latest frame, or goes back to live updates
+func (m *Model) togglePause() {
+	if m.paused || len(m.frames) == 0 {
+		m.paused = false
+		return
+	}
+	m.paused = true
+	m.frameIndex = len(m.frames) - 1
+}
+
+// stepFrame moves the paused view through the buffered frames, pausing first if live
+func (m *Model) stepFrame(delta int) {
+	if len(m.frames) == 0 {
+		return
+	}
+	if !m.paused {
+		m.togglePause()
+	}
+	m.frameIndex = min(max(m.frameIndex+delta, 0), len(m.frames)-1)
+}
+
+// viewFrame is the frame the tabs render: the one stepped to while paused, otherwise live
+func (m *Model) viewFrame() frame {
+	if m.paused && m.frameIndex < len(m.frames) {
+		return m.frames[m.frameIndex]
+	}
+	return frame{
+		time:       time.Now(),
+		state:      m.tabState,
+		tracker:    m.metricsProcessor.gcTracker,
+		advisories: m.advisor.Active(),
+	}
+}
+
+// viewState is the frame's tab state with the live GC chart filter and percentile
+// window, so they still cycle while paused
+func (m *Model) viewState(view frame) *TabState {
+	if view.state == m.tabState {
+		return view.state
+	}
+	state := *view.state
+	gcState := *state.GC
+	gcState.gcChartFilter = m.tabState.GC.gcChartFilter
+	gcState.percentileWindow = m.tabState.GC.percentileWindow
+	state.GC = &gcState
+	return &state
+}
+
+// pauseStatus describes the paused view for the header
+func (m *Model) pauseStatus() string {
+	newer := len(m.frames) - 1 - m.frameIndex
+	status := fmt.Sprintf("⏸ Paused at %s • Frame %d/%d", m.frames[m.frameIndex].time.Format("15:04:05"),
+		m.frameIndex+1, len(m.frames))
+	if newer > 0 {
+		status += fmt.Sprintf(" • %d newer", newer)
+	}
+	return utils.WarningStyle.Render(status)
+}
@@ -2,13 +2,15 @@ package watch
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/internal/gc"
 	"github.com/mabhi256/jdiag/utils"
 )
 
-func RenderGCTab(state *TabState, tracker *GCEventTracker, width int) string {
+func RenderGCTab(state *TabState, tracker *GCEventTracker, width int, rateHistory []utils.TimeMap, thresholds RateThresholds) string {
 	var sections []string
 
 	// Analysis window for calculations
@@ -25,10 +27,25 @@ func RenderGCTab(state *TabState, tracker *GCEventTracker, width int) string {
 		sections = append(sections, "")
 	}
 
+	// Allocation and promotion rates side by side
+	if rateSection := renderRateCharts(rateHistory, thresholds, width); rateSection != "" {
+		sections = append(sections, rateSection, "")
+	}
+
 	// Middle section: Generation stats and recent GC side by side
-	middleSection := renderMiddleSection(tracker, window)
+	middleSection := renderMiddleSection(tracker, window, width)
 	sections = append(sections, middleSection)
 
+	// Pause distribution since watching started
+	if histogram := renderPauseHistogram(tracker, width); histogram != "" {
+		sections = append(sections, histogram, "")
+	}
+
+	// Pause and allocation rate percentiles over the chosen window
+	if percentiles := renderPercentiles(tracker, state.GC.percentileWindow); percentiles != "" {
+		sections = append(sections, percentiles, "")
+	}
+
 	// Bottom section: Performance analysis in organized blocks
 	performanceSection := renderPerformanceGrid(tracker, window)
 	sections = append(sections, performanceSection)
@@ -139,6 +156,72 @@ func renderGCEventsChart(tracker *GCEventTracker, width int, filter GCChartFilte
 	return lipgloss.JoinVertical(lipgloss.Left, "", chartView)
 }
 
+// renderRateCharts charts the allocation and promotion rates, stacked when the terminal is narrow
+func renderRateCharts(history []utils.TimeMap, thresholds RateThresholds, width int) string {
+	if len(history) < 2 {
+		return ""
+	}
+
+	chartWidth := (width - 6) / 2
+	if utils.LayoutFor(width) == utils.LayoutNarrow {
+		chartWidth = width - 4
+	}
+
+	allocation := renderRateChart("Allocation Rate", history, "allocation_mb_s", thresholds.Allocation, utils.GoodColor, chartWidth)
+	promotion := renderRateChart("Promotion Rate", history, "promotion_mb_s", thresholds.Promotion, utils.InfoColor, chartWidth)
+	return utils.Columns(width, 4, allocation, promotion)
+}
+
+// renderRateChart draws one rate over time. Samples above the threshold get a
+// highlighted column, and the threshold is drawn once the rate gets near it.
+func renderRateChart(title string, history []utils.TimeMap, field string, threshold float64, color lipgloss.Color, width int) string {
+	chart := utils.NewChart(max(width, 30), 8)
+
+	var current, peak float64
+	var over int
+	for _, point := range history {
+		current = point.GetOrDefault(field, 0)
+		peak = max(peak, current)
+		if threshold > 0 && current > threshold {
+			over++
+		}
+		chart.Push(utils.TimePoint{Time: point.Timestamp, Value: current})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(color))
+
+	// A flat line at a far-off threshold would squash the rate against the axis
+	if threshold > 0 && peak >= threshold/2 {
+		for _, point := range history {
+			chart.PushDataSet("threshold", utils.TimePoint{Time: point.Timestamp, Value: threshold})
+		}
+		chart.SetDataSetStyle("threshold", lipgloss.NewStyle().Foreground(utils.CriticalColor))
+	}
+
+	chart.DrawBrailleAll()
+	if over > 0 {
+		marker := lipgloss.NewStyle().Background(utils.CriticalColor)
+		for _, point := range history {
+			if point.GetOrDefault(field, 0) > threshold {
+				chart.SetColumnBackgroundStyle(point.Timestamp, marker)
+			}
+		}
+	}
+
+	header := utils.InfoStyle.Render(title) + utils.MutedStyle.Render(fmt.Sprintf("  now %s/s  peak %s/s",
+		utils.FormatMB(current), utils.FormatMB(peak)))
+
+	status := utils.MutedStyle.Render("No warning threshold")
+	switch {
+	case over > 0:
+		status = lipgloss.NewStyle().Foreground(utils.CriticalColor).Render(
+			fmt.Sprintf("⚠ Above %s/s in %d of the last %d samples", utils.FormatMB(threshold), over, len(history)))
+	case threshold > 0:
+		status = utils.MutedStyle.Render(fmt.Sprintf("Warns above %s/s", utils.FormatMB(threshold)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, status, "", chart.View())
+}
+
 // renderGCSummaryGrid creates a clean, organized summary layout
 func renderGCSummaryGrid(tracker *GCEventTracker, window time.Duration) string {
 	totalGCs := tracker.GetTotalGCCount()
@@ -156,11 +239,11 @@ func renderGCSummaryGrid(tracker *GCEventTracker, window time.Duration) string {
 	if avgPauseTime > 0 {
 		metrics = append(metrics, fmt.Sprintf("Recent Avg: %s", utils.FormatDuration(avgPauseTime)))
 	} else if overallAvg > 0 {
-		metrics = append(metrics, fmt.Sprintf("Overall Avg: %.1fms", overallAvg))
+		metrics = append(metrics, fmt.Sprintf("Overall Avg: %s", utils.FormatMillis(overallAvg)))
 	}
 
 	if frequency > 0 {
-		metrics = append(metrics, fmt.Sprintf("Frequency: %.1f/min", frequency))
+		metrics = append(metrics, fmt.Sprintf("Frequency: %s/min", utils.FormatFloat(frequency)))
 	}
 
 	// Create a clean horizontal layout with proper spacing
@@ -176,26 +259,159 @@ func renderGCSummaryGrid(tracker *GCEventTracker, window time.Duration) string {
 }
 
 // renderMiddleSection combines generation stats and most recent GC info
-func renderMiddleSection(tracker *GCEventTracker, window time.Duration) string {
+// renderPauseHistogram counts the pauses seen per log-scale bucket, so a long tail shows
+// up even when the averages and the last event look fine. Empty buckets at either end
+// are left out.
+func renderPauseHistogram(tracker *GCEventTracker, width int) string {
+	young, old := tracker.GetPauseHistogram("young"), tracker.GetPauseHistogram("old")
+
+	var total int64
+	first, last := -1, -1
+	for i := range young {
+		if count := young[i] + old[i]; count > 0 {
+			total += count
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+
+	var bars []utils.BarData
+	var seen int64
+	p50, p99 := -1, -1
+	for i := first; i <= last; i++ {
+		count := young[i] + old[i]
+		seen += count
+		if p50 < 0 && seen*2 >= total {
+			p50 = i
+		}
+		if p99 < 0 && seen*100 >= total*99 {
+			p99 = i
+		}
+
+		suffix := ""
+		if old[i] > 0 {
+			suffix = fmt.Sprintf("%d old", old[i])
+		}
+		bars = append(bars, utils.BarData{
+			Label:      pauseBucketLabel(i),
+			Value:      float64(count),
+			Percentage: float64(count) / float64(total) * 100,
+			Style:      pauseBucketStyle(i, count),
+			Suffix:     suffix,
+		})
+	}
+
+	config := utils.DefaultBarConfig(min(max(width-40, 20), 60))
+	config.LabelWidth = 10
+	config.ValueFormat = "%.0f"
+
+	title := utils.InfoStyle.Render("Pause Distribution") +
+		utils.MutedStyle.Render(fmt.Sprintf(" (%d pauses since watching, log scale)", total))
+	percentiles := fmt.Sprintf("p50 %s | p99 %s", pauseBucketLabel(p50), pauseBucketLabel(p99))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		utils.CreateHorizontalBarChart(title, bars, config),
+		utils.MutedStyle.Render(percentiles))
+}
+
+// renderPercentiles shows the tail of the pauses and allocation rates the histograms counted
+// in the window, which the averages and the maximum hide
+func renderPercentiles(tracker *GCEventTracker, window time.Duration) string {
+	_, pauses := tracker.GetPausePercentile(50, window)
+	_, rates := tracker.GetAllocationRatePercentile(50, window)
+	if pauses == 0 && rates == 0 {
+		return ""
+	}
+
+	lines := []string{utils.InfoStyle.Render("Percentiles") +
+		utils.MutedStyle.Render(fmt.Sprintf(" (last %s, w to change)", utils.FormatDuration(window)))}
+	if pauses > 0 {
+		var values []string
+		for _, p := range []float64{50, 95, 99} {
+			pause, _ := tracker.GetPausePercentile(p, window)
+			style := utils.GoodStyle
+			switch {
+			case pause >= gc.PauseCritical:
+				style = utils.CriticalStyle
+			case pause >= gc.PausePoor:
+				style = utils.WarningStyle
+			}
+			values = append(values, fmt.Sprintf("p%d %s", int(p), style.Render(utils.FormatDuration(pause))))
+		}
+		lines = append(lines, fmt.Sprintf("• Pause:      %s  %s", strings.Join(values, " | "),
+			utils.MutedStyle.Render(fmt.Sprintf("(%d pauses)", pauses))))
+	}
+	if rates > 0 {
+		var values []string
+		for _, p := range []float64{50, 95, 99} {
+			rate, _ := tracker.GetAllocationRatePercentile(p, window)
+			values = append(values, fmt.Sprintf("p%d %s/s", int(p), utils.FormatMB(rate)))
+		}
+		lines = append(lines, fmt.Sprintf("• Allocation: %s  %s", strings.Join(values, " | "),
+			utils.MutedStyle.Render(fmt.Sprintf("(%d snapshots)", rates))))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// pauseBucketLabel names a PauseBucketBounds bucket by the pauses it holds, like "20–50ms"
+func pauseBucketLabel(bucket int) string {
+	switch bucket {
+	case 0:
+		return "<" + pauseBound(PauseBucketBounds[0])
+	case len(PauseBucketBounds):
+		return "≥" + pauseBound(PauseBucketBounds[bucket-1])
+	}
+
+	lower, upper := pauseBound(PauseBucketBounds[bucket-1]), pauseBound(PauseBucketBounds[bucket])
+	if unit := strings.TrimLeft(upper, "0123456789"); strings.TrimLeft(lower, "0123456789") == unit {
+		lower = strings.TrimSuffix(lower, unit) // Say the unit once, "20–50ms"
+	}
+	return lower + "–" + upper
+}
+
+// pauseBound prints a whole bucket bound as "200ms" or "2s"
+func pauseBound(bound time.Duration) string {
+	if bound < time.Second {
+		return fmt.Sprintf("%dms", bound.Milliseconds())
+	}
+	return fmt.Sprintf("%ds", int(bound.Seconds()))
+}
+
+// pauseBucketStyle colors a bucket by how its longest pauses compare to the GC analysis thresholds
+func pauseBucketStyle(bucket int, count int64) lipgloss.Style {
+	switch {
+	case count == 0:
+		return utils.MutedStyle
+	case bucket == len(PauseBucketBounds) || PauseBucketBounds[bucket] > gc.PauseCritical:
+		return utils.CriticalStyle
+	case PauseBucketBounds[bucket] > gc.PausePoor:
+		return utils.WarningStyle
+	}
+	return utils.GoodStyle
+}
+
+func renderMiddleSection(tracker *GCEventTracker, window time.Duration, width int) string {
 	// Left side: Generation statistics
-	generationStats := renderGenerationColumns(tracker, window)
+	generationStats := renderGenerationColumns(tracker, window, width)
 
 	// Right side: Most recent GC info
 	recentGCInfo := renderMostRecentGCBox(tracker)
 
 	// Combine side by side if we have recent GC info
 	if recentGCInfo != "" {
-		return lipgloss.JoinHorizontal(lipgloss.Top,
-			generationStats,
-			"    ",
-			recentGCInfo) + "\n"
+		return utils.Columns(width, 4, generationStats, recentGCInfo) + "\n"
 	}
 
 	return generationStats + "\n"
 }
 
 // renderGenerationColumns creates side-by-side generation statistics
-func renderGenerationColumns(tracker *GCEventTracker, window time.Duration) string {
+func renderGenerationColumns(tracker *GCEventTracker, window time.Duration, width int) string {
 	youngStats := buildGenerationColumn(tracker, "young", window)
 	oldStats := buildGenerationColumn(tracker, "old", window)
 
@@ -209,7 +425,7 @@ func renderGenerationColumns(tracker *GCEventTracker, window time.Duration) stri
 			utils.InfoStyle.Render("Old Generation"),
 			oldStats))
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, youngColumn, "  ", oldColumn)
+	return utils.Columns(width, 2, youngColumn, oldColumn)
 }
 
 func buildGenerationColumn(tracker *GCEventTracker, generation string, window time.Duration) string {
@@ -232,7 +448,7 @@ func buildGenerationColumn(tracker *GCEventTracker, generation string, window ti
 	}
 
 	frequency := tracker.GetGCFrequencyByGeneration(generation, window)
-	youngEff, oldEff, _ := tracker.CalculateEfficiency(window)
+	youngEff, oldEff, _ := tracker.CalculateSampledEfficiency(window)
 
 	var efficiency float64
 	if generation == "young" {
@@ -253,15 +469,15 @@ func buildGenerationColumn(tracker *GCEventTracker, generation string, window ti
 	lines := []string{
 		fmt.Sprintf("Count: %d", count),
 		fmt.Sprintf("Total Time: %s", utils.FormatDuration(time.Duration(totalTime)*time.Millisecond)),
-		fmt.Sprintf("Avg Time: %.1fms", avgTime),
+		fmt.Sprintf("Avg Time: %s", utils.FormatMillis(avgTime)),
 	}
 
 	if frequency > 0 {
-		lines = append(lines, fmt.Sprintf("Frequency: %.1f/min", frequency))
+		lines = append(lines, fmt.Sprintf("Frequency: %s/min", utils.FormatFloat(frequency)))
 	}
 
 	if efficiency > 0 {
-		lines = append(lines, fmt.Sprintf("Efficiency: %.1f%%", efficiency))
+		lines = append(lines, fmt.Sprintf("Sampled Efficiency: %s", utils.FormatPercent(efficiency)))
 	}
 
 	if count > 0 {
@@ -303,7 +519,7 @@ func renderMostRecentGCBox(tracker *GCEventTracker) string {
 		pauseColor = utils.WarningColor
 	}
 
-	timeAgo := time.Since(timestamp)
+	timeAgo := tracker.now().Sub(timestamp)
 
 	lines := []string{
 		fmt.Sprintf("%s %s Generation",
@@ -316,7 +532,7 @@ func renderMostRecentGCBox(tracker *GCEventTracker) string {
 	}
 
 	if collected > 0 {
-		lines = append(lines, fmt.Sprintf("Freed: %.1f MB", utils.MemorySize(collected).MB()))
+		lines = append(lines, fmt.Sprintf("Freed: %s", utils.FormatMB(utils.MemorySize(collected).MB())))
 	}
 
 	content := ""
@@ -352,7 +568,7 @@ func renderOverheadColumn(tracker *GCEventTracker, window time.Duration) string
 	totalTime := float64(tracker.GetTotalGCTime())
 	var overallOverhead float64
 	if tracker.currentSnapshot != nil && !tracker.currentSnapshot.Runtime.StartTime.IsZero() {
-		uptime := time.Since(tracker.currentSnapshot.Runtime.StartTime)
+		uptime := tracker.now().Sub(tracker.currentSnapshot.Runtime.StartTime)
 		if uptime > 0 {
 			overallOverhead = totalTime / float64(uptime.Milliseconds())
 		}
@@ -386,14 +602,14 @@ func renderOverheadColumn(tracker *GCEventTracker, window time.Duration) string
 
 	lines := []string{
 		fmt.Sprintf("GC Overhead: %s",
-			lipgloss.NewStyle().Foreground(overheadColor).Render(fmt.Sprintf("%.2f%%", displayOverhead*100))),
+			lipgloss.NewStyle().Foreground(overheadColor).Render(utils.Precision(2).Percent(displayOverhead*100))),
 		fmt.Sprintf("Status: %s",
 			lipgloss.NewStyle().Foreground(overheadColor).Render(status)),
 	}
 
 	if recentOverhead > 0 && overallOverhead > 0 && recentOverhead != overallOverhead {
-		lines = append(lines, fmt.Sprintf("Recent: %.2f%%", recentOverhead*100))
-		lines = append(lines, fmt.Sprintf("Overall: %.2f%%", overallOverhead*100))
+		lines = append(lines, fmt.Sprintf("Recent: %s", utils.Precision(2).Percent(recentOverhead*100)))
+		lines = append(lines, fmt.Sprintf("Overall: %s", utils.Precision(2).Percent(overallOverhead*100)))
 	}
 
 	content := ""
@@ -410,7 +626,7 @@ func renderOverheadColumn(tracker *GCEventTracker, window time.Duration) string
 func renderMetricsColumn(tracker *GCEventTracker, window time.Duration) string {
 	maxPause := tracker.GetMaxPause(window)
 	longPauses := tracker.GetLongPauses(100*time.Millisecond, window)
-	_, _, overallEfficiency := tracker.CalculateEfficiency(window)
+	_, _, overallEfficiency := tracker.CalculateSampledEfficiency(window)
 	pressureLevel := tracker.GetGCPressureLevel(window)
 
 	var lines []string
@@ -440,8 +656,8 @@ func renderMetricsColumn(tracker *GCEventTracker, window time.Duration) string {
 			efficiencyColor = utils.CriticalColor
 		}
 		lines = append(lines,
-			fmt.Sprintf("Efficiency: %s",
-				lipgloss.NewStyle().Foreground(efficiencyColor).Render(fmt.Sprintf("%.1f%%", overallEfficiency))))
+			fmt.Sprintf("Sampled Efficiency: %s",
+				lipgloss.NewStyle().Foreground(efficiencyColor).Render(utils.FormatPercent(overallEfficiency))))
 	}
 
 	pressureColor := utils.GoodColor
@@ -504,12 +720,11 @@ func renderRecentEventsClean(events []GCEvent) string {
 		}
 
 		if event.Collected > 0 {
-			eventDetails = append(eventDetails, fmt.Sprintf("Freed: %.1f MB", utils.MemorySize(event.Collected).MB()))
+			eventDetails = append(eventDetails, fmt.Sprintf("Freed: %s", utils.FormatMB(utils.MemorySize(event.Collected).MB())))
 		}
 
-		if event.Before > 0 {
-			efficiency := float64(event.Collected) / float64(event.Before) * 100
-			eventDetails = append(eventDetails, fmt.Sprintf("Efficiency: %.1f%%", efficiency))
+		if event.Sampled {
+			eventDetails = append(eventDetails, fmt.Sprintf("Sampled Efficiency: %s", utils.FormatPercent(event.SampledEfficiency*100)))
 		}
 
 		eventLine := "• " + eventDetails[0]
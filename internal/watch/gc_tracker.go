@@ -1,25 +1,47 @@
 package watch
 
 import (
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/mabhi256/jdiag/internal/jmx"
 )
 
+// PauseBucketBounds are the upper bounds of the pause histogram buckets, 1-2-5 steps so
+// each covers about the same span on a log scale; pauses past the last go in one more bucket
+var PauseBucketBounds = []time.Duration{
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
 type GCEventTracker struct {
 	mu sync.RWMutex
 
 	gcEvents       []GCEvent
 	lastGCCounts   map[string]int64
 	lastGCTimes    map[string]int64
+	lastGCInfoIds  map[string]int64 // Collection whose usage was last measured, per generation
 	windowDuration time.Duration
 
+	// Pauses per PauseBucketBounds bucket by generation, since watching started
+	pauseBuckets map[string][]int64
+
+	// Pauses in microseconds and allocation rates in KB/s, for percentiles over PercentileWindows
+	pauseHistogram      *WindowedHistogram
+	allocationHistogram *WindowedHistogram
+
 	// JVM start time for timestamp conversion
 	jvmStartTime time.Time
 
 	// Current raw data (for calculations)
 	currentSnapshot *jmx.MBeanSnapshot
+
+	// Set on frozen copies, whose windows end there instead of now
+	frozenAt time.Time
 }
 
 func NewGCEventTracker() *GCEventTracker {
@@ -27,7 +49,12 @@ func NewGCEventTracker() *GCEventTracker {
 		gcEvents:       make([]GCEvent, 0),
 		lastGCCounts:   make(map[string]int64),
 		lastGCTimes:    make(map[string]int64),
+		lastGCInfoIds:  make(map[string]int64),
 		windowDuration: 5 * time.Minute,
+		pauseBuckets:   make(map[string][]int64),
+
+		pauseHistogram:      NewWindowedHistogram(),
+		allocationHistogram: NewWindowedHistogram(),
 	}
 }
 
@@ -65,6 +92,39 @@ func (get *GCEventTracker) ProcessGCMetrics(metrics *jmx.MBeanSnapshot) {
 	get.cleanupOldEvents()
 }
 
+// Freeze copies the tracker as it is, with its windows ending at the given time
+func (get *GCEventTracker) Freeze(at time.Time) *GCEventTracker {
+	get.mu.RLock()
+	defer get.mu.RUnlock()
+
+	buckets := make(map[string][]int64, len(get.pauseBuckets))
+	for generation, counts := range get.pauseBuckets {
+		buckets[generation] = slices.Clone(counts)
+	}
+
+	return &GCEventTracker{
+		gcEvents:            slices.Clone(get.gcEvents),
+		pauseBuckets:        buckets,
+		pauseHistogram:      get.pauseHistogram.clone(),
+		allocationHistogram: get.allocationHistogram.clone(),
+		lastGCCounts:        maps.Clone(get.lastGCCounts),
+		lastGCTimes:         maps.Clone(get.lastGCTimes),
+		lastGCInfoIds:       maps.Clone(get.lastGCInfoIds),
+		windowDuration:      get.windowDuration,
+		jvmStartTime:        get.jvmStartTime,
+		currentSnapshot:     get.currentSnapshot,
+		frozenAt:            at,
+	}
+}
+
+// now is where the windows end: the present, or the freeze time of a frozen copy
+func (get *GCEventTracker) now() time.Time {
+	if !get.frozenAt.IsZero() {
+		return get.frozenAt
+	}
+	return time.Now()
+}
+
 // processGenerationGC handles GC event processing for a specific generation
 func (get *GCEventTracker) processGenerationGC(generation string, currentCount, currentTime int64,
 	lastGCInfo jmx.LastGCInfo, fallbackUsed int64, timestamp time.Time) {
@@ -86,11 +146,17 @@ func (get *GCEventTracker) processGenerationGC(generation string, currentCount,
 	// Determine memory before/after values and actual timestamp
 	var beforeMem, afterMem, collected int64
 	var eventTimestamp time.Time
+	var efficiency float64
+	measured := false
 
 	if lastGCInfo.IsValid() && get.isRecentGC(lastGCInfo) {
 		// Use actual GC data if available and recent
 		beforeMem, afterMem, collected = get.extractMemoryFromGCInfo(lastGCInfo, generation)
 		eventTimestamp = get.convertJVMTimestamp(lastGCInfo.EndTime)
+		if get.lastGCInfoIds[generation] != lastGCInfo.Id {
+			efficiency, measured = sampledEfficiency(lastGCInfo, generation)
+			get.lastGCInfoIds[generation] = lastGCInfo.Id
+		}
 	} else {
 		// Fallback to current usage (less accurate but better than nothing)
 		beforeMem = fallbackUsed
@@ -105,9 +171,16 @@ func (get *GCEventTracker) processGenerationGC(generation string, currentCount,
 		actualDuration = time.Duration(lastGCInfo.Duration) * time.Millisecond
 	}
 
-	// Create GC events for each new collection
+	// Create GC events for each new collection; the usage reported is the last one's
+	if get.pauseBuckets[generation] == nil {
+		get.pauseBuckets[generation] = make([]int64, len(PauseBucketBounds)+1)
+	}
+	get.pauseBuckets[generation][pauseBucket(actualDuration)] += newEvents
 	for range newEvents {
-		get.gcEvents = append(get.gcEvents, GCEvent{
+		get.pauseHistogram.Record(eventTimestamp, actualDuration.Microseconds())
+	}
+	for i := range newEvents {
+		event := GCEvent{
 			Id:         lastGCInfo.Id,
 			Timestamp:  eventTimestamp,
 			Generation: generation,
@@ -115,12 +188,83 @@ func (get *GCEventTracker) processGenerationGC(generation string, currentCount,
 			Before:     beforeMem,
 			After:      afterMem,
 			Collected:  collected,
-		})
+		}
+		if i == newEvents-1 {
+			event.SampledEfficiency, event.Sampled = efficiency, measured
+		}
+		get.gcEvents = append(get.gcEvents, event)
 	}
 }
 
+/*
+ * sampledEfficiency is the share of the space a collection covered that
+ * the heap got back: young collections cover eden and survivor, old ones the
+ * whole heap. It's the heap's drop across the collection, so what a young
+ * collection promotes counts as kept, not freed.
+ *
+ * It's a sample, not a measure of every collection: the bridge only reports
+ * LastGcInfo, the most recent collection at the time of the poll, and a JMX
+ * query is a one-shot process that can't subscribe to GC notifications. When
+ * several collections run between polls, only the last one is seen.
+ */
+func sampledEfficiency(gcInfo jmx.LastGCInfo, generation string) (float64, bool) {
+	heapBefore := gcInfo.EdenBefore + gcInfo.SurvivorBefore + gcInfo.OldBefore
+	heapAfter := gcInfo.EdenAfter + gcInfo.SurvivorAfter + gcInfo.OldAfter
+	covered := heapBefore
+	if generation == "young" {
+		covered = gcInfo.EdenBefore + gcInfo.SurvivorBefore
+	}
+	if covered <= 0 {
+		return 0, false
+	}
+	return min(max(float64(heapBefore-heapAfter)/float64(covered), 0), 1), true
+}
+
+// RecordAllocationRate counts the allocation rate, in MB/s, since the previous snapshot
+func (get *GCEventTracker) RecordAllocationRate(at time.Time, rate float64) {
+	get.mu.Lock()
+	defer get.mu.Unlock()
+
+	get.allocationHistogram.Record(at, int64(rate*1024))
+}
+
+// pauseBucket is the first bucket whose bound covers the pause
+func pauseBucket(pause time.Duration) int {
+	bucket, _ := slices.BinarySearch(PauseBucketBounds, pause)
+	return bucket
+}
+
 // ===== ALL DERIVED DATA CALCULATIONS =====
 
+// GetPauseHistogram returns the pauses per PauseBucketBounds bucket of a generation
+func (get *GCEventTracker) GetPauseHistogram(generation string) []int64 {
+	get.mu.RLock()
+	defer get.mu.RUnlock()
+
+	if counts := get.pauseBuckets[generation]; counts != nil {
+		return slices.Clone(counts)
+	}
+	return make([]int64, len(PauseBucketBounds)+1)
+}
+
+// GetPausePercentile returns the pth percentile pause in the window, and how many pauses it covers
+func (get *GCEventTracker) GetPausePercentile(p float64, window time.Duration) (time.Duration, int64) {
+	get.mu.RLock()
+	defer get.mu.RUnlock()
+
+	hist := get.pauseHistogram.Window(get.now(), window)
+	return time.Duration(hist.Percentile(p)) * time.Microsecond, hist.Count()
+}
+
+// GetAllocationRatePercentile returns the pth percentile allocation rate in the window in MB/s, and how many snapshots it covers
+func (get *GCEventTracker) GetAllocationRatePercentile(p float64, window time.Duration) (float64, int64) {
+	get.mu.RLock()
+	defer get.mu.RUnlock()
+
+	hist := get.allocationHistogram.Window(get.now(), window)
+	return float64(hist.Percentile(p)) / 1024, hist.Count()
+}
+
 // GetTotalGCCount returns total GC count across all generations
 func (get *GCEventTracker) GetTotalGCCount() int64 {
 	get.mu.RLock()
@@ -352,7 +496,7 @@ func (get *GCEventTracker) GetGCFrequency(window time.Duration) float64 {
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	count := 0
 	for _, event := range get.gcEvents {
 		if event.Timestamp.After(cutoff) {
@@ -367,7 +511,7 @@ func (get *GCEventTracker) GetGCFrequencyByGeneration(generation string, window
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	count := 0
 	for _, event := range get.gcEvents {
 		if event.Generation == generation && event.Timestamp.After(cutoff) {
@@ -382,7 +526,7 @@ func (get *GCEventTracker) GetAveragePauseTime(window time.Duration) time.Durati
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	var totalDuration time.Duration
 	count := 0
 
@@ -405,7 +549,7 @@ func (get *GCEventTracker) GetTotalGCTimeWindow(window time.Duration) time.Durat
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	var totalDuration time.Duration
 
 	for _, event := range get.gcEvents {
@@ -422,7 +566,7 @@ func (get *GCEventTracker) GetLongPauses(threshold, window time.Duration) int64
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	count := int64(0)
 
 	for _, event := range get.gcEvents {
@@ -439,7 +583,7 @@ func (get *GCEventTracker) GetMaxPause(window time.Duration) time.Duration {
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
+	cutoff := get.now().Add(-window)
 	var maxPause time.Duration
 
 	for _, event := range get.gcEvents {
@@ -460,41 +604,36 @@ func (get *GCEventTracker) CalculateGCOverhead(window time.Duration) float64 {
 	return float64(totalGCTime) / float64(window)
 }
 
-// CalculateEfficiency calculates collection efficiency metrics by generation
-func (get *GCEventTracker) CalculateEfficiency(window time.Duration) (young, old, overall float64) {
+// CalculateSampledEfficiency averages the sampled collections' efficiency in the window, as percentages
+func (get *GCEventTracker) CalculateSampledEfficiency(window time.Duration) (young, old, overall float64) {
 	get.mu.RLock()
 	defer get.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
-
-	var youngCollected, youngBefore, oldCollected, oldBefore, totalCollected, totalBefore int64
+	cutoff := get.now().Add(-window)
 
+	var youngTotal, oldTotal float64
+	var youngCount, oldCount int
 	for _, event := range get.gcEvents {
-		if event.Timestamp.After(cutoff) {
-			totalCollected += event.Collected
-			totalBefore += event.Before
-
-			if event.Generation == "young" {
-				youngCollected += event.Collected
-				youngBefore += event.Before
-			} else if event.Generation == "old" {
-				oldCollected += event.Collected
-				oldBefore += event.Before
-			}
+		if !event.Sampled || !event.Timestamp.After(cutoff) {
+			continue
+		}
+		if event.Generation == "young" {
+			youngTotal += event.SampledEfficiency
+			youngCount++
+		} else if event.Generation == "old" {
+			oldTotal += event.SampledEfficiency
+			oldCount++
 		}
 	}
 
-	// Calculate efficiency percentages
-	if youngBefore > 0 {
-		young = float64(youngCollected) / float64(youngBefore) * 100
+	if youngCount > 0 {
+		young = youngTotal / float64(youngCount) * 100
 	}
-
-	if oldBefore > 0 {
-		old = float64(oldCollected) / float64(oldBefore) * 100
+	if oldCount > 0 {
+		old = oldTotal / float64(oldCount) * 100
 	}
-
-	if totalBefore > 0 {
-		overall = float64(totalCollected) / float64(totalBefore) * 100
+	if count := youngCount + oldCount; count > 0 {
+		overall = (youngTotal + oldTotal) / float64(count) * 100
 	}
 
 	return young, old, overall
@@ -0,0 +1,141 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/notify"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * Headless watch
+ *
+ * --duration watches without the TUI for a fixed time, for load tests run
+ * from CI: the same collection, alerts, advisories and notifications as the
+ * dashboard, then the session summary and an export of the whole run to
+ * report on. The GC tracker keeps every collection for the length of the run
+ * rather than its usual five minutes, so the export holds all of them.
+ *
+ * Ctrl-C ends the run early; what was collected up to then is still
+ * returned. Progress goes to stderr, redrawn on one line on a terminal and
+ * otherwise only at the start, so CI logs stay short.
+ */
+
+type headlessSession struct {
+	target     string
+	processor  *MetricsProcessor
+	alerts     *AlertTracker
+	advisor    *Advisor
+	thresholds RateThresholds
+	session    *SessionSummary
+}
+
+func newHeadlessSession(target string, duration time.Duration, thresholds RateThresholds) *headlessSession {
+	processor := NewMetricsProcessor()
+	processor.gcTracker.windowDuration = duration + time.Minute // Keep the whole run, and collections the last poll catches late
+	return &headlessSession{
+		target:     target,
+		processor:  processor,
+		alerts:     NewAlertTracker(),
+		advisor:    NewAdvisor(),
+		thresholds: thresholds,
+		session:    NewSessionSummary(target),
+	}
+}
+
+// record processes one snapshot as the TUI's tick does, and returns the alerts it fired
+func (h *headlessSession) record(metrics *jmx.MBeanSnapshot) ([]PerformanceAlert, *TabState) {
+	state := h.processor.ProcessMetrics(metrics)
+	if metrics.Connected {
+		h.advisor.Update(h.processor, state, h.thresholds, time.Now())
+		h.session.Record(metrics, h.processor.gcTracker, h.advisor.Active())
+	}
+
+	fired := h.alerts.Check(h.processor, metrics)
+	h.session.RecordAlerts(fired)
+	return fired, state
+}
+
+// RunHeadless watches the JVM for duration without the TUI and returns the session's summary and export
+func RunHeadless(config *jmx.Config, duration time.Duration, notifier *notify.Notifier,
+	thresholds RateThresholds) (*SessionSummary, *WatchExport, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	collector := jmx.NewJMXCollector(config)
+	if err := collector.Start(); err != nil {
+		return nil, nil, err
+	}
+	defer collector.Stop()
+
+	h := newHeadlessSession(config.String(), duration, thresholds)
+	fmt.Fprintf(os.Stderr, "⏳ Watching %s for %s (Ctrl-C ends early)\n", config.String(), utils.FormatDuration(duration))
+	live := term.IsTerminal(os.Stderr.Fd())
+
+	// Alerts are posted in the background so a slow webhook doesn't delay sampling
+	var sends sync.WaitGroup
+	defer sends.Wait()
+
+	// The collector samples on its own ticker; process each sample once
+	ticker := time.NewTicker(max(config.GetInterval()/4, 50*time.Millisecond))
+	defer ticker.Stop()
+
+	start := time.Now()
+	var last time.Time
+	var lastErr error
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+
+		metrics := collector.GetMetrics()
+		if !metrics.Timestamp.After(last) {
+			continue
+		}
+		last = metrics.Timestamp
+		if !metrics.Connected {
+			lastErr = metrics.Error
+		}
+
+		fired, state := h.record(metrics)
+		if len(fired) > 0 && notifier != nil {
+			summary := NewAlertSummary(h.target, fired, state, h.processor)
+			sends.Add(1)
+			go func() {
+				defer sends.Done()
+				if err := notifier.Send(summary); err != nil {
+					fmt.Fprintf(os.Stderr, "\n⚠️  Failed to post alerts: %v\n", err)
+				}
+			}()
+		}
+
+		if live {
+			fmt.Fprintf(os.Stderr, "\r\033[K⏳ %s / %s  %s snapshots  %s GCs  %d alerts",
+				utils.FormatDuration(time.Since(start).Round(time.Second)), utils.FormatDuration(duration),
+				utils.FormatCount(h.session.Snapshots), utils.FormatCount(h.session.YoungGCs+h.session.OldGCs),
+				len(h.session.Alerts))
+		}
+	}
+	if live {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+
+	if h.session.Empty() {
+		if lastErr != nil {
+			return nil, nil, fmt.Errorf("no snapshot from %s: %w", config.String(), lastErr)
+		}
+		return nil, nil, fmt.Errorf("no snapshot from %s within %s", config.String(), utils.FormatDuration(duration))
+	}
+	return h.session, newWatchExport(h.target, h.processor, time.Now()), nil
+}
@@ -0,0 +1,158 @@
+package watch
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+const (
+	histogramSubBits     = 5 // 32 linear sub-buckets per power of two, so a value is off by at most 1/32
+	histogramSubCount    = 1 << histogramSubBits
+	histogramSliceLength = 10 * time.Second // Windows are made of slices this long
+)
+
+// PercentileWindows are the windows the GC tab's percentiles cycle through; the longest is kept
+var PercentileWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// DefaultPercentileWindow is the window the GC tab's percentiles start on
+const DefaultPercentileWindow = 5 * time.Minute
+
+// nextPercentileWindow cycles through PercentileWindows
+func nextPercentileWindow(window time.Duration) time.Duration {
+	next := (slices.Index(PercentileWindows, window) + 1) % len(PercentileWindows)
+	return PercentileWindows[next]
+}
+
+/*
+ * Histogram counts values in HDR-style buckets: exact below 32, then 32
+ * linear sub-buckets per power of two. Every value lands within about 3%
+ * of its bucket's bounds whatever its size, so a microsecond pause and a
+ * ten-second one are told apart equally well, and percentiles come from the
+ * counts without keeping the values. Buckets are sparse; few ever fill.
+ */
+type Histogram struct {
+	counts map[int]int64
+	total  int64
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make(map[int]int64)}
+}
+
+// Record counts a value; negative values count as 0
+func (h *Histogram) Record(value int64) {
+	h.counts[histogramBucket(max(value, 0))]++
+	h.total++
+}
+
+// Merge adds the other histogram's counts
+func (h *Histogram) Merge(other *Histogram) {
+	for bucket, count := range other.counts {
+		h.counts[bucket] += count
+	}
+	h.total += other.total
+}
+
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Percentile is the highest value in the bucket that holds the pth percentile, 0 when empty
+func (h *Histogram) Percentile(p float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+	buckets := make([]int, 0, len(h.counts))
+	for bucket := range h.counts {
+		buckets = append(buckets, bucket)
+	}
+	slices.Sort(buckets)
+
+	rank := max(int64(math.Ceil(p/100*float64(h.total))), 1)
+	var seen int64
+	for _, bucket := range buckets {
+		seen += h.counts[bucket]
+		if seen >= rank {
+			return histogramBucketMax(bucket)
+		}
+	}
+	return histogramBucketMax(buckets[len(buckets)-1])
+}
+
+func (h *Histogram) clone() *Histogram {
+	clone := NewHistogram()
+	clone.Merge(h)
+	return clone
+}
+
+// histogramBucket finds the bucket of a value: the value itself below histogramSubCount,
+// then histogramSubCount buckets per power of two
+func histogramBucket(value int64) int {
+	if value < histogramSubCount {
+		return int(value)
+	}
+	shift := bits.Len64(uint64(value)) - histogramSubBits - 1
+	return (shift+1)*histogramSubCount + int(value>>shift) - histogramSubCount
+}
+
+// histogramBucketMax is the highest value that lands in the bucket
+func histogramBucketMax(bucket int) int64 {
+	if bucket < histogramSubCount {
+		return int64(bucket)
+	}
+	shift := bucket/histogramSubCount - 1
+	sub := int64(bucket%histogramSubCount + histogramSubCount)
+	return (sub+1)<<shift - 1
+}
+
+// WindowedHistogram keeps a histogram per histogramSliceLength, for the longest PercentileWindow
+type WindowedHistogram struct {
+	slices []histogramSlice
+}
+
+type histogramSlice struct {
+	start time.Time
+	hist  *Histogram
+}
+
+func NewWindowedHistogram() *WindowedHistogram {
+	return &WindowedHistogram{}
+}
+
+// Record counts a value in the slice its time falls in, dropping slices past the longest window
+func (w *WindowedHistogram) Record(at time.Time, value int64) {
+	if n := len(w.slices); n == 0 || !at.Before(w.slices[n-1].start.Add(histogramSliceLength)) {
+		w.slices = append(w.slices, histogramSlice{start: at.Truncate(histogramSliceLength), hist: NewHistogram()})
+	}
+	w.slices[len(w.slices)-1].hist.Record(value)
+
+	cutoff := at.Add(-PercentileWindows[len(PercentileWindows)-1] - histogramSliceLength)
+	drop := 0
+	for drop < len(w.slices) && w.slices[drop].start.Before(cutoff) {
+		drop++
+	}
+	w.slices = w.slices[drop:]
+}
+
+// Window merges the slices that overlap the window ending at end
+func (w *WindowedHistogram) Window(end time.Time, window time.Duration) *Histogram {
+	merged := NewHistogram()
+	start := end.Add(-window)
+	for _, slice := range w.slices {
+		if slice.start.Add(histogramSliceLength).After(start) && !slice.start.After(end) {
+			merged.Merge(slice.hist)
+		}
+	}
+	return merged
+}
+
+// clone copies the histogram; only the newest slice is still recorded into, so the older ones are shared
+func (w *WindowedHistogram) clone() *WindowedHistogram {
+	clone := &WindowedHistogram{slices: slices.Clone(w.slices)}
+	if n := len(clone.slices); n > 0 {
+		clone.slices[n-1].hist = clone.slices[n-1].hist.clone()
+	}
+	return clone
+}
@@ -15,6 +15,9 @@ type HistoricalDataStore struct {
 	threadCounts []utils.TimeMap
 	classCounts  []utils.TimeMap
 	systemUsage  []utils.TimeMap
+	gcRates      []utils.TimeMap
+	heapFloors   []utils.TimeMap // One point per snapshot that saw a GC
+	oldFloors    []utils.TimeMap // One point per collection that covered the old generation
 
 	windowDuration time.Duration
 }
@@ -25,6 +28,9 @@ func NewHistoricalDataStore() *HistoricalDataStore {
 		threadCounts:   make([]utils.TimeMap, 0),
 		classCounts:    make([]utils.TimeMap, 0),
 		systemUsage:    make([]utils.TimeMap, 0),
+		gcRates:        make([]utils.TimeMap, 0),
+		heapFloors:     make([]utils.TimeMap, 0),
+		oldFloors:      make([]utils.TimeMap, 0),
 		windowDuration: 5 * time.Minute,
 	}
 }
@@ -58,14 +64,18 @@ func (hds *HistoricalDataStore) AddThreadCount(timestamp time.Time, entry *jmx.T
 	hds.threadCounts = append(hds.threadCounts, *point)
 }
 
-func (hds *HistoricalDataStore) AddClassCount(timestamp time.Time, entry *jmx.ClassLoading) {
+func (hds *HistoricalDataStore) AddClassCount(timestamp time.Time, entry *jmx.ClassLoading, compilation *jmx.Compilation) {
 	hds.mu.Lock()
 	defer hds.mu.Unlock()
 
 	point := utils.NewTimeMap(timestamp)
 
+	point.Values["loaded_count"] = float64(entry.LoadedClassCount)
 	point.Values["total_loaded"] = float64(entry.TotalLoadedClassCount)
 	point.Values["unloaded_count"] = float64(entry.UnloadedClassCount)
+	if compilation.TimeMonitoringSupported {
+		point.Values["compilation_ms"] = float64(compilation.TotalCompilationTime)
+	}
 
 	hds.classCounts = append(hds.classCounts, *point)
 }
@@ -80,16 +90,84 @@ func (hds *HistoricalDataStore) AddSystemUsage(timestamp time.Time, entry *jmx.O
 	point.Values["system_cpu"] = float64(entry.SystemCpuLoad)
 	point.Values["ram"] = utils.MemorySize(entry.TotalPhysicalMemory - entry.FreePhysicalMemory).GB()
 	point.Values["swap"] = utils.MemorySize(entry.TotalSwapSpace - entry.FreeSwapSpace).GB()
+	if entry.MaxFileDescriptorCount > 0 {
+		point.Values["open_fds"] = float64(entry.OpenFileDescriptorCount)
+	}
 
 	hds.systemUsage = append(hds.systemUsage, *point)
 }
 
+// AddGCRates records the allocation and promotion rates since the previous snapshot, in MB/s
+func (hds *HistoricalDataStore) AddGCRates(timestamp time.Time, allocation, promotion float64) {
+	hds.mu.Lock()
+	defer hds.mu.Unlock()
+
+	point := utils.NewTimeMap(timestamp)
+
+	point.Values["allocation_mb_s"] = allocation
+	point.Values["promotion_mb_s"] = promotion
+
+	hds.gcRates = append(hds.gcRates, *point)
+}
+
+// AddHeapFloor records what the heap and old generation held right after the latest GC
+func (hds *HistoricalDataStore) AddHeapFloor(timestamp time.Time, heap, old int64) {
+	hds.mu.Lock()
+	defer hds.mu.Unlock()
+
+	point := utils.NewTimeMap(timestamp)
+
+	point.Values["floor_mb"] = utils.MemorySize(heap).MB()
+	point.Values["old_mb"] = utils.MemorySize(old).MB()
+
+	hds.heapFloors = append(hds.heapFloors, *point)
+}
+
+// AddOldFloor records what the old generation held after a collection that covered it
+func (hds *HistoricalDataStore) AddOldFloor(timestamp time.Time, used, limit int64) {
+	hds.mu.Lock()
+	defer hds.mu.Unlock()
+
+	point := utils.NewTimeMap(timestamp)
+
+	point.Values["used_mb"] = utils.MemorySize(used).MB()
+	if limit > 0 {
+		point.Values["max_mb"] = utils.MemorySize(limit).MB()
+	}
+
+	hds.oldFloors = append(hds.oldFloors, *point)
+}
+
+// OldFloorsUntil returns the session's old generation floors up to the given time
+func (hds *HistoricalDataStore) OldFloorsUntil(end time.Time) []utils.TimeMap {
+	hds.mu.RLock()
+	defer hds.mu.RUnlock()
+
+	var result []utils.TimeMap
+	for _, point := range hds.oldFloors {
+		if !point.Timestamp.After(end) {
+			result = append(result, point)
+		}
+	}
+	return result
+}
+
 func (hds *HistoricalDataStore) GetRecentHistory(window time.Duration, f func(*HistoricalDataStore) []utils.TimeMap) []utils.TimeMap {
+	return hds.GetHistoryAt(time.Now(), window, f)
+}
+
+// GetHistoryAt returns the points of the window that ends at the given time
+func (hds *HistoricalDataStore) GetHistoryAt(end time.Time, window time.Duration, f func(*HistoricalDataStore) []utils.TimeMap) []utils.TimeMap {
 	hds.mu.RLock()
 	defer hds.mu.RUnlock()
 
-	cutoff := time.Now().Add(-window)
-	return hds.filterMultiValueByTime(f(hds), cutoff)
+	var result []utils.TimeMap
+	for _, point := range hds.filterMultiValueByTime(f(hds), end.Add(-window)) {
+		if !point.Timestamp.After(end) {
+			result = append(result, point)
+		}
+	}
+	return result
 }
 
 func (hds *HistoricalDataStore) GetRecentDataField(window time.Duration, fieldName string) []utils.TimePoint {
@@ -1,12 +1,18 @@
 package watch
 
 import (
+	"cmp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/mabhi256/jdiag/internal/jmx"
 	"github.com/mabhi256/jdiag/utils"
 )
 
+// TopThreadCount is how many threads the top CPU and allocation lists show
+const TopThreadCount = 5
+
 type MetricsProcessor struct {
 	dataStore *HistoricalDataStore
 	gcTracker *GCEventTracker
@@ -45,31 +51,67 @@ func (mp *MetricsProcessor) updateHistoricalData(metrics *jmx.MBeanSnapshot) {
 
 	mp.dataStore.AddHeapMemory(now, &metrics.Memory.Heap)
 	mp.dataStore.AddThreadCount(now, &metrics.Threading)
-	mp.dataStore.AddClassCount(now, &metrics.ClassLoading)
+	mp.dataStore.AddClassCount(now, &metrics.ClassLoading, &metrics.Compilation)
 	mp.dataStore.AddSystemUsage(now, &metrics.OS)
 
+	if mp.lastMetrics != nil {
+		if elapsed := now.Sub(mp.lastMetrics.Timestamp).Seconds(); elapsed > 0 {
+			allocated, promoted := gcTransfers(mp.lastMetrics, metrics)
+			mp.dataStore.AddGCRates(now, utils.MemorySize(allocated).MB()/elapsed, utils.MemorySize(promoted).MB()/elapsed)
+			mp.gcTracker.RecordAllocationRate(now, utils.MemorySize(allocated).MB()/elapsed)
+		}
+		if lastGC, ok := newestGC(mp.lastMetrics, metrics); ok {
+			mp.dataStore.AddHeapFloor(now, lastGC.EdenAfter+lastGC.SurvivorAfter+lastGC.OldAfter, lastGC.OldAfter)
+		}
+		if used, limit, ok := oldFloor(mp.lastMetrics, metrics); ok {
+			mp.dataStore.AddOldFloor(now, used, limit)
+		}
+	}
+}
+
+// newestGC is the latest collection's info when one ran between the two snapshots
+func newestGC(previous, current *jmx.MBeanSnapshot) (jmx.LastGCInfo, bool) {
+	young, old := current.GC.LastYoungGC, current.GC.LastOldGC
+	ran := current.GC.YoungGCCount > previous.GC.YoungGCCount || current.GC.OldGCCount > previous.GC.OldGCCount
+	if !ran || (!young.IsValid() && !old.IsValid()) {
+		return jmx.LastGCInfo{}, false
+	}
+	if old.IsValid() && old.EndTime > young.EndTime {
+		return old, true
+	}
+	return young, young.IsValid()
 }
 
 func (m *Model) GetHistoricalHeapMemory(window time.Duration) []utils.TimeMap {
-	return m.metricsProcessor.dataStore.GetRecentHistory(window, func(hds *HistoricalDataStore) []utils.TimeMap {
+	return m.metricsProcessor.dataStore.GetHistoryAt(m.viewFrame().time, window, func(hds *HistoricalDataStore) []utils.TimeMap {
 		return hds.heapMemory
 	})
 }
 
+func (m *Model) GetHistoricalOldFloors() []utils.TimeMap {
+	return m.metricsProcessor.dataStore.OldFloorsUntil(m.viewFrame().time)
+}
+
+func (m *Model) GetHistoricalGCRates(window time.Duration) []utils.TimeMap {
+	return m.metricsProcessor.dataStore.GetHistoryAt(m.viewFrame().time, window, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.gcRates
+	})
+}
+
 func (m *Model) GetHistoricalClassCount(window time.Duration) []utils.TimeMap {
-	return m.metricsProcessor.dataStore.GetRecentHistory(window, func(hds *HistoricalDataStore) []utils.TimeMap {
+	return m.metricsProcessor.dataStore.GetHistoryAt(m.viewFrame().time, window, func(hds *HistoricalDataStore) []utils.TimeMap {
 		return hds.classCounts
 	})
 }
 
 func (m *Model) GetHistoricaThreadCount(window time.Duration) []utils.TimeMap {
-	return m.metricsProcessor.dataStore.GetRecentHistory(window, func(hds *HistoricalDataStore) []utils.TimeMap {
+	return m.metricsProcessor.dataStore.GetHistoryAt(m.viewFrame().time, window, func(hds *HistoricalDataStore) []utils.TimeMap {
 		return hds.threadCounts
 	})
 }
 
 func (m *Model) GetHistoricalSystemUsage(window time.Duration) []utils.TimeMap {
-	return m.metricsProcessor.dataStore.GetRecentHistory(window, func(hds *HistoricalDataStore) []utils.TimeMap {
+	return m.metricsProcessor.dataStore.GetHistoryAt(m.viewFrame().time, window, func(hds *HistoricalDataStore) []utils.TimeMap {
 		return hds.systemUsage
 	})
 }
@@ -127,6 +169,20 @@ func (mp *MetricsProcessor) buildTabState(metrics *jmx.MBeanSnapshot, gcOverhead
 		state.Memory.NonHeapUsagePercent = float64(metrics.Memory.NonHeap.Used) / float64(metrics.Memory.NonHeap.Committed)
 	}
 
+	for _, pool := range metrics.Memory.Pools {
+		state.Memory.Pools = append(state.Memory.Pools, PoolState{
+			Name:      pool.Name,
+			Heap:      pool.Heap,
+			Used:      pool.Usage.Used,
+			Committed: pool.Usage.Committed,
+			Max:       pool.Usage.Max,
+			Peak:      pool.PeakUsage.Used,
+			Floor:     pool.CollectionUsage.Used,
+		})
+	}
+	state.Memory.Exhaustion = mp.estimateExhaustion(state.Memory)
+	state.Memory.FloorTrend = mp.floorTrend(metrics.Timestamp)
+
 	// === GC State ===
 	state.GC.YoungGCCount = metrics.GC.YoungGCCount
 	state.GC.YoungGCTime = metrics.GC.YoungGCTime
@@ -162,9 +218,10 @@ func (mp *MetricsProcessor) buildTabState(metrics *jmx.MBeanSnapshot, gcOverhead
 	state.Threads.DaemonThreadCount = metrics.Threading.DaemonCount
 	state.Threads.TotalStartedCount = metrics.Threading.TotalStartedCount
 
-	state.Threads.LoadedClassCount = metrics.ClassLoading.LoadedClassCount
-	state.Threads.UnloadedClassCount = metrics.ClassLoading.UnloadedClassCount
-	state.Threads.TotalLoadedClasses = metrics.ClassLoading.TotalLoadedClassCount
+	mp.buildThreadDetails(metrics, state.Threads)
+
+	// === Class State ===
+	mp.buildClassState(metrics, state.Classes)
 
 	// === System State ===
 	state.System.ProcessCpuLoad = metrics.OS.ProcessCpuLoad
@@ -186,12 +243,170 @@ func (mp *MetricsProcessor) buildTabState(metrics *jmx.MBeanSnapshot, gcOverhead
 		state.System.TotalSwap = metrics.OS.TotalSwapSpace
 		state.System.FreeSwap = metrics.OS.FreeSwapSpace
 		state.System.UsedSwap = metrics.OS.TotalSwapSpace - metrics.OS.FreeSwapSpace
-		state.System.SwapPercent = float64(state.System.UsedSwap) / float64(metrics.OS.TotalSwapSpace)
+		if metrics.OS.TotalSwapSpace > 0 {
+			state.System.SwapPercent = float64(state.System.UsedSwap) / float64(metrics.OS.TotalSwapSpace)
+		}
+	}
+
+	state.System.OpenFileDescriptors = metrics.OS.OpenFileDescriptorCount
+	state.System.MaxFileDescriptors = metrics.OS.MaxFileDescriptorCount
+	if metrics.OS.MaxFileDescriptorCount > 0 {
+		state.System.FileDescriptorPercent = float64(metrics.OS.OpenFileDescriptorCount) / float64(metrics.OS.MaxFileDescriptorCount)
 	}
 
 	return state
 }
 
+// buildThreadDetails counts threads per state and ranks them by the CPU and
+// allocation they used since the previous poll
+func (mp *MetricsProcessor) buildThreadDetails(metrics *jmx.MBeanSnapshot, threads *ThreadState) {
+	if len(metrics.Threading.Threads) == 0 {
+		return
+	}
+
+	previous := make(map[int64]jmx.ThreadInfo)
+	var elapsed time.Duration
+	if mp.lastMetrics != nil {
+		elapsed = metrics.Timestamp.Sub(mp.lastMetrics.Timestamp)
+		for _, thread := range mp.lastMetrics.Threading.Threads {
+			previous[thread.ID] = thread
+		}
+	}
+
+	threads.StateCounts = make(map[string]int64)
+	var usage []ThreadUsage
+	for _, thread := range metrics.Threading.Threads {
+		threads.StateCounts[thread.State]++
+
+		if thread.State == "BLOCKED" {
+			blocked := BlockedThread{
+				Name:         thread.Name,
+				LockName:     thread.LockName,
+				LockOwner:    thread.LockOwnerName,
+				BlockedCount: thread.BlockedCount,
+			}
+			if thread.BlockedTime > 0 {
+				blocked.BlockedTime = time.Duration(thread.BlockedTime) * time.Millisecond
+			}
+			threads.BlockedThreads = append(threads.BlockedThreads, blocked)
+		}
+
+		before, ok := previous[thread.ID]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		used := ThreadUsage{Name: thread.Name, State: thread.State}
+		if thread.CPUTime >= 0 && before.CPUTime >= 0 {
+			used.CPUPercent = float64(thread.CPUTime-before.CPUTime) / float64(elapsed) * 100
+		}
+		if thread.AllocatedBytes >= 0 && before.AllocatedBytes >= 0 {
+			used.AllocationRate = float64(thread.AllocatedBytes-before.AllocatedBytes) / elapsed.Seconds()
+		}
+		usage = append(usage, used)
+	}
+
+	threads.BlockedThreadCount = threads.StateCounts["BLOCKED"]
+	threads.WaitingThreadCount = threads.StateCounts["WAITING"] + threads.StateCounts["TIMED_WAITING"]
+	threads.ThreadContention = threads.BlockedThreadCount > 0
+
+	threads.TopCPU = topThreads(usage, func(u ThreadUsage) float64 { return u.CPUPercent })
+	threads.TopAllocating = topThreads(usage, func(u ThreadUsage) float64 { return u.AllocationRate })
+}
+
+// topThreads returns up to TopThreadCount threads with the highest non-zero value
+// buildClassState fills the class counts and derives the loading and compilation
+// rates from the recorded history
+func (mp *MetricsProcessor) buildClassState(metrics *jmx.MBeanSnapshot, classes *ClassState) {
+	classes.LoadedClassCount = metrics.ClassLoading.LoadedClassCount
+	classes.TotalLoadedClasses = metrics.ClassLoading.TotalLoadedClassCount
+	classes.UnloadedClassCount = metrics.ClassLoading.UnloadedClassCount
+	classes.JVMUptime = metrics.Runtime.Uptime
+	if metrics.Compilation.Valid {
+		classes.Compiler = metrics.Compilation.Name
+		classes.CompilationSupported = metrics.Compilation.TimeMonitoringSupported
+		classes.CompilationTime = time.Duration(metrics.Compilation.TotalCompilationTime) * time.Millisecond
+	}
+
+	history := mp.dataStore.GetRecentHistory(alertWindow, func(hds *HistoricalDataStore) []utils.TimeMap {
+		return hds.classCounts
+	})
+	if len(history) > 1 {
+		first, last := history[0], history[len(history)-1]
+		classes.RateWindow = last.Timestamp.Sub(first.Timestamp)
+		if minutes := classes.RateWindow.Minutes(); minutes > 0 {
+			classes.ClassLoadingRate = (last.GetOrDefault("total_loaded", 0) - first.GetOrDefault("total_loaded", 0)) / minutes
+			classes.ClassUnloadingRate = (last.GetOrDefault("unloaded_count", 0) - first.GetOrDefault("unloaded_count", 0)) / minutes
+		}
+	}
+
+	if classes.CompilationSupported {
+		recent := mp.dataStore.GetRecentHistory(time.Minute, func(hds *HistoricalDataStore) []utils.TimeMap {
+			return hds.classCounts
+		})
+		if len(recent) > 1 {
+			first, last := recent[0], recent[len(recent)-1]
+			if elapsed := last.Timestamp.Sub(first.Timestamp).Milliseconds(); elapsed > 0 {
+				classes.CompilationShare = (last.GetOrDefault("compilation_ms", 0) - first.GetOrDefault("compilation_ms", 0)) / float64(elapsed)
+			}
+		}
+	}
+}
+
+func topThreads(usage []ThreadUsage, value func(ThreadUsage) float64) []ThreadUsage {
+	var top []ThreadUsage
+	for _, used := range usage {
+		if value(used) > 0 {
+			top = append(top, used)
+		}
+	}
+	slices.SortFunc(top, func(a, b ThreadUsage) int {
+		return cmp.Compare(value(b), value(a))
+	})
+	return top[:min(len(top), TopThreadCount)]
+}
+
+// gcTransfers estimates the bytes allocated into eden and promoted into the old generation
+// between two snapshots. Without a young GC in between, allocation is eden's growth; with
+// one, it is what eden grew to before the last GC plus what it refilled since, and each
+// earlier GC is taken to have collected as full an eden. Promotion is the old generation's
+// growth while no old GC ran, otherwise what the last young GC moved into it.
+func gcTransfers(previous, current *jmx.MBeanSnapshot) (allocated, promoted int64) {
+	edenBefore, edenNow := poolUsed(previous, "eden"), poolUsed(current, "eden")
+	oldBefore, oldNow := poolUsed(previous, "old", "tenured"), poolUsed(current, "old", "tenured")
+	youngGCs := current.GC.YoungGCCount - previous.GC.YoungGCCount
+	oldGCs := current.GC.OldGCCount - previous.GC.OldGCCount
+	lastGC := current.GC.LastYoungGC
+
+	switch {
+	case youngGCs <= 0:
+		allocated = max(edenNow-edenBefore, 0)
+	case lastGC.EdenBefore > 0:
+		allocated = max(lastGC.EdenBefore-edenBefore, 0) + (youngGCs-1)*lastGC.EdenBefore + max(edenNow-lastGC.EdenAfter, 0)
+	default:
+		allocated = edenNow // No pool usage around the GC, so only the refill is known
+	}
+
+	switch {
+	case oldGCs <= 0:
+		promoted = max(oldNow-oldBefore, 0)
+	case youngGCs > 0:
+		promoted = youngGCs * max(lastGC.OldAfter-lastGC.OldBefore, 0)
+	}
+	return allocated, promoted
+}
+
+// poolUsed sums the used bytes of the pools whose name contains any of the parts
+func poolUsed(metrics *jmx.MBeanSnapshot, parts ...string) int64 {
+	var used int64
+	for _, pool := range metrics.Memory.Pools {
+		name := strings.ToLower(pool.Name)
+		if slices.ContainsFunc(parts, func(part string) bool { return strings.Contains(name, part) }) {
+			used += pool.Usage.Used
+		}
+	}
+	return used
+}
+
 // Helper functions
 func (mp *MetricsProcessor) getYoungGenUsage(metrics *jmx.MBeanSnapshot) (used, committed, max int64) {
 	// G1 Eden space
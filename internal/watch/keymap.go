@@ -4,44 +4,58 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines the key bindings
 type KeyMap struct {
-	Up            key.Binding
-	Down          key.Binding
-	Left          key.Binding
-	Right         key.Binding
-	Tab           key.Binding
-	Quit          key.Binding
-	SelectProcess key.Binding
-	Reconnect     key.Binding
-	Enter         key.Binding
-	Escape        key.Binding
-	PageUp        key.Binding
-	PageDown      key.Binding
-	GCFilter      key.Binding
+	Up               key.Binding
+	Down             key.Binding
+	Left             key.Binding
+	Right            key.Binding
+	Tab              key.Binding
+	Quit             key.Binding
+	SelectProcess    key.Binding
+	Reconnect        key.Binding
+	Enter            key.Binding
+	Escape           key.Binding
+	PageUp           key.Binding
+	PageDown         key.Binding
+	GCFilter         key.Binding
+	PercentileWindow key.Binding
+	Pause            key.Binding
+	StepBack         key.Binding
+	StepForward      key.Binding
+	ExportJSON       key.Binding
+	ExportCSV        key.Binding
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Tab, k.Quit}
+	return []key.Binding{k.Tab, k.Pause, k.Quit}
 }
 
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Tab, k.SelectProcess, k.Reconnect, k.Quit},
+		{k.Pause, k.StepBack, k.StepForward},
+		{k.ExportJSON, k.ExportCSV},
 	}
 }
 
 var keys = KeyMap{
-	Up:            key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-	Down:          key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-	Left:          key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
-	Right:         key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")),
-	Tab:           key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch view")),
-	Quit:          key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-	SelectProcess: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "select process")),
-	Reconnect:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "reconnect")),
-	Enter:         key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
-	Escape:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
-	PageUp:        key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")),
-	PageDown:      key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", "page down")),
-	GCFilter:      key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "gc filter")),
+	Up:               key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:             key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	Left:             key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
+	Right:            key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")),
+	Tab:              key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch view")),
+	Quit:             key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+	SelectProcess:    key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "select process")),
+	Reconnect:        key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "reconnect")),
+	Enter:            key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+	Escape:           key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	PageUp:           key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")),
+	PageDown:         key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", "page down")),
+	GCFilter:         key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "gc filter")),
+	PercentileWindow: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "percentile window")),
+	Pause:            key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "pause/resume")),
+	StepBack:         key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous snapshot")),
+	StepForward:      key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next snapshot")),
+	ExportJSON:       key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export JSON")),
+	ExportCSV:        key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export CSV")),
 }
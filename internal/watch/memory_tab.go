@@ -8,16 +8,26 @@ import (
 	"github.com/mabhi256/jdiag/utils"
 )
 
-func RenderMemoryTab(state *TabState, width int, heapHistory []utils.TimeMap) string {
+func RenderMemoryTab(state *TabState, width int, heapHistory, floorHistory []utils.TimeMap) string {
 	var sections []string
 
 	// ntcharts timeseries graph
 	graphSection := renderHeapGraph(heapHistory, width)
 	sections = append(sections, graphSection)
 	sections = append(sections, "")
+	if exhaustion := renderExhaustion(state.Memory.Exhaustion, width); exhaustion != "" {
+		sections = append(sections, exhaustion, "")
+	}
+	if floor := renderFloorTrend(state.Memory.FloorTrend, floorHistory, width); floor != "" {
+		sections = append(sections, floor, "")
+	}
 
 	// Calculate width for each column (accounting for separator and padding)
 	columnWidth := (width - 3) / 2 // -3 for " | " separator
+	if utils.LayoutFor(width) == utils.LayoutNarrow {
+		// The grid stacks into one column
+		columnWidth = width - 4
+	}
 
 	// Get most recent GC info
 	recentGCInfo, isYoungGen := getMostRecentGCInfo(state)
@@ -80,9 +90,9 @@ func RenderMemoryTab(state *TabState, width int, heapHistory []utils.TimeMap) st
 		state.Memory.NonHeapUsagePercent,
 		columnWidth)
 
-	topRow := lipgloss.JoinHorizontal(lipgloss.Top, "  ", heapSection, "     ", youngSection)
+	topRow := lipgloss.JoinHorizontal(lipgloss.Top, "  ", utils.Columns(width-2, 5, heapSection, youngSection))
 	// Create bottom row: non-heap | old
-	bottomRow := lipgloss.JoinHorizontal(lipgloss.Top, "  ", nonHeapSection, "    ", oldSection)
+	bottomRow := lipgloss.JoinHorizontal(lipgloss.Top, "  ", utils.Columns(width-2, 4, nonHeapSection, oldSection))
 
 	// Add the grid rows to sections
 	sections = append(sections, topRow)
@@ -116,11 +126,23 @@ func getMostRecentGCInfo(state *TabState) (string, bool) {
 	freedStr := utils.MemorySize(recentEvent.Collected).MB()
 
 	// Create the formatted string
-	gcInfo := fmt.Sprintf("%s GC-%v, freed %0.2fM, %s ago", emoji, recentEvent.Id, freedStr, utils.FormatDuration(timeAgo))
+	gcInfo := fmt.Sprintf("%s GC-%v, freed %sM, %s ago", emoji, recentEvent.Id, utils.Precision(2).Float(freedStr), utils.FormatDuration(timeAgo))
 
 	return gcInfo, isYoungGen
 }
 
+// renderExhaustion is the time-to-OOM line, colored once the estimate is urgent and confident
+func renderExhaustion(estimate *ExhaustionEstimate, width int) string {
+	if estimate == nil {
+		return ""
+	}
+	line := utils.TruncateString("  "+estimate.Summary(), width)
+	if level := estimate.Level(); level != "" {
+		return utils.GetSeverityStyle(level).Render(line)
+	}
+	return utils.MutedStyle.Render(line)
+}
+
 func renderHeapGraph(history []utils.TimeMap, width int) string {
 	if len(history) < 2 {
 		return ""
@@ -181,7 +203,7 @@ func renderMemorySection(title string, used, committed, max int64, percentage fl
 		barWidth = 20
 	}
 	progressBar := utils.CreateProgressBar(percentage, barWidth, color)
-	percentStr := fmt.Sprintf("%.1f%%", percentage*100)
+	percentStr := utils.FormatPercent(percentage * 100)
 
 	// Build the section
 	titleStyled := utils.InfoStyle.Render(title)
@@ -217,7 +239,7 @@ func renderMemorySectionWithGC(title string, gcInfo string, used, committed, max
 		barWidth = 20
 	}
 	progressBar := utils.CreateProgressBar(percentage, barWidth, color)
-	percentStr := fmt.Sprintf("%.1f%%", percentage*100)
+	percentStr := utils.FormatPercent(percentage * 100)
 
 	// Build the section with GC info
 	titleStyled := utils.InfoStyle.Render(title)
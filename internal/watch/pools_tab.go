@@ -0,0 +1,113 @@
+package watch
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// RenderPoolsTab shows every memory pool as a gauge, heap pools beside non-heap ones
+func RenderPoolsTab(state *TabState, width int) string {
+	pools := state.Memory.Pools
+	if len(pools) == 0 {
+		return utils.MutedStyle.Render("No memory pool data yet")
+	}
+
+	columnWidth := (width - 6) / 2
+	if utils.LayoutFor(width) == utils.LayoutNarrow {
+		columnWidth = width - 4
+	}
+
+	var heap, nonHeap []string
+	for _, pool := range pools {
+		if pool.Heap {
+			heap = append(heap, renderPoolGauge(pool, columnWidth))
+		} else {
+			nonHeap = append(nonHeap, renderPoolGauge(pool, columnWidth))
+		}
+	}
+
+	heapColumn := lipgloss.JoinVertical(lipgloss.Left, append([]string{utils.TitleStyle.Render("Heap Pools"), ""}, heap...)...)
+	nonHeapColumn := lipgloss.JoinVertical(lipgloss.Left, append([]string{utils.TitleStyle.Render("Non-Heap Pools"), ""}, nonHeap...)...)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		renderPoolLegend(),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, "  ", utils.Columns(width-2, 4, heapColumn, nonHeapColumn)))
+}
+
+func renderPoolLegend() string {
+	return utils.MutedStyle.Render("▓ live after last GC  █ allocated since  ") +
+		lipgloss.NewStyle().Foreground(utils.WarningColor).Render("┃") +
+		utils.MutedStyle.Render(" peak  • of max, or of committed for unbounded pools")
+}
+
+// renderPoolGauge fills the bar to the used size, shading the part that was still
+// live after the last GC, and marks the peak
+func renderPoolGauge(pool PoolState, width int) string {
+	limit := pool.Max
+	if limit <= 0 {
+		limit = pool.Committed
+	}
+	percentage := 0.0
+	if limit > 0 {
+		percentage = float64(pool.Used) / float64(limit)
+	}
+
+	var color lipgloss.Color
+	switch {
+	case percentage > 0.9:
+		color = utils.CriticalColor
+	case percentage > 0.7:
+		color = utils.WarningColor
+	default:
+		color = utils.GoodColor
+	}
+
+	barWidth := max(width-10, 20)
+	cell := func(size int64) int {
+		if limit <= 0 {
+			return 0
+		}
+		return min(max(int(math.Round(float64(size)/float64(limit)*float64(barWidth))), 0), barWidth)
+	}
+	used := cell(pool.Used)
+	floor := min(cell(pool.Floor), used)
+	peak := -1
+	if pool.Peak > pool.Used {
+		peak = max(cell(pool.Peak)-1, used)
+	}
+
+	style := lipgloss.NewStyle().Foreground(color)
+	var bar strings.Builder
+	bar.WriteString(style.Render(strings.Repeat("▓", floor) + strings.Repeat("█", used-floor)))
+	for i := used; i < barWidth; i++ {
+		if i == peak {
+			bar.WriteString(lipgloss.NewStyle().Foreground(utils.WarningColor).Render("┃"))
+		} else {
+			bar.WriteString(utils.MutedStyle.Render("░"))
+		}
+	}
+
+	title := utils.InfoStyle.Render(pool.Name)
+	if pool.Floor > 0 {
+		title += utils.MutedStyle.Render(fmt.Sprintf(" (post-GC floor %s)", utils.MemorySize(pool.Floor)))
+	}
+
+	maxText := "unbounded"
+	if pool.Max > 0 {
+		maxText = utils.MemorySize(pool.Max).String()
+	}
+	detail := fmt.Sprintf("Used: %s | Peak: %s | Committed: %s | Max: %s",
+		utils.MemorySize(pool.Used), utils.MemorySize(pool.Peak), utils.MemorySize(pool.Committed), maxText)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		bar.String()+" "+utils.FormatPercent(percentage*100),
+		utils.MutedStyle.Render(detail),
+		"", // Empty line for spacing
+	)
+}
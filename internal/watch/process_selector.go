@@ -67,6 +67,7 @@ func (m *Model) selectProcess(process *JavaProcess) (tea.Model, tea.Cmd) {
 	m.config.PID = process.PID
 	m.config.Host = ""
 	m.config.Port = 0
+	m.config.ReplayFile = ""
 	m.selectedProcess = process
 	m.processMode = false
 	m.clearError()
@@ -74,6 +75,9 @@ func (m *Model) selectProcess(process *JavaProcess) (tea.Model, tea.Cmd) {
 	// Reset start time for new monitoring session
 	m.startTime = time.Now()
 	m.updateCount = 0
+	m.frames = nil
+	m.paused = false
+	m.session = NewSessionSummary(fmt.Sprintf("%s (PID %d)", process.MainClass, process.PID))
 
 	// Update system state with process info
 	m.tabState.System.ProcessName = process.MainClass
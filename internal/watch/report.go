@@ -0,0 +1,367 @@
+package watch
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * Session report
+ *
+ * One HTML file for a watch session, for attaching to a CI run or a load
+ * test ticket: the summary as cards, the history as charts, pause
+ * percentiles, the longest pauses and the alerts. It's drawn from a summary
+ * and an export, so it needs nothing but them, and it's self-contained: the
+ * charts are SVG rendered here, with no scripts or network requests.
+ */
+
+//go:embed templates/report.html
+var reportTemplate string
+
+// Chart geometry, in SVG user units; the SVG scales to the page width
+const (
+	chartWidth  = 760
+	chartHeight = 180
+	chartLeft   = 56 // Room for the y-axis labels
+	chartBottom = 20 // Room for the time labels
+	chartPoints = 600
+)
+
+// reportLongestPauses is how many of the longest pauses the report lists
+const reportLongestPauses = 20
+
+var chartColors = []string{"#2563eb", "#9ca3af", "#16a34a", "#d97706"}
+
+type reportData struct {
+	Summary   *SessionSummary
+	Generated time.Time
+	Span      string
+	JVM       string // Name, version and uptime; "" for a summary loaded from a file
+	Cards     []reportCard
+	Charts    []reportChart
+	Pauses    []reportCard // Percentiles
+	Longest   []reportPause
+	Alerts    []reportAlert
+}
+
+type reportCard struct {
+	Label  string
+	Value  string
+	Detail string
+	Level  string // "", "warning" or "critical"
+}
+
+type reportChart struct {
+	Title  string
+	SVG    template.HTML
+	Legend []reportLegend
+}
+
+type reportLegend struct {
+	Name  string
+	Color string
+}
+
+type reportPause struct {
+	Time       string
+	Generation string
+	Duration   string
+	Collected  string
+}
+
+type reportAlert struct {
+	Time        string
+	Level       string
+	Title       string
+	Description string
+	Resolved    bool
+}
+
+// chartSeries is one line of a chart, or dots when Scatter
+type chartSeries struct {
+	Name    string
+	Color   string // "" takes the chart's next color
+	Times   []time.Time
+	Values  []float64
+	Scatter bool
+}
+
+// WriteHTMLReport renders the session as a self-contained HTML page
+func WriteHTMLReport(w io.Writer, summary *SessionSummary, export *WatchExport) error {
+	tmpl, err := template.New("report").Parse(reportTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid report template: %w", err)
+	}
+	return tmpl.Execute(w, newReportData(summary, export))
+}
+
+func newReportData(summary *SessionSummary, export *WatchExport) *reportData {
+	data := &reportData{
+		Summary:   summary,
+		Generated: export.Exported,
+		Span:      utils.FormatDuration(summary.End.Sub(summary.Start).Truncate(time.Second)),
+	}
+	if summary.last != nil && summary.last.Runtime.VmName != "" {
+		data.JVM = fmt.Sprintf("%s %s, up %s", summary.last.Runtime.VmName, summary.last.Runtime.VmVersion,
+			utils.FormatDuration(summary.last.Runtime.Uptime.Truncate(time.Second)))
+	}
+
+	data.Cards = append(data.Cards,
+		reportCard{Label: "Collections", Value: utils.FormatCount(summary.YoungGCs + summary.OldGCs),
+			Detail: fmt.Sprintf("%s young, %s old", utils.FormatCount(summary.YoungGCs), utils.FormatCount(summary.OldGCs)),
+			Level:  levelAbove(float64(summary.OldGCs), 0, math.Inf(1))},
+		reportCard{Label: "GC overhead", Value: utils.Precision(2).Percent(summary.GCOverhead * 100),
+			Detail: utils.FormatDuration(summary.GCTime) + " in GC", Level: levelAbove(summary.GCOverhead, 0.05, 0.10)},
+		reportCard{Label: "Max pause", Value: utils.FormatDuration(summary.MaxPause),
+			Level: levelAbove(summary.MaxPause.Seconds(), 0.2, 1)})
+	peak := reportCard{Label: "Peak heap", Value: utils.MemorySize(summary.PeakHeap).String()}
+	if summary.HeapMax > 0 {
+		fraction := float64(summary.PeakHeap) / float64(summary.HeapMax)
+		peak.Detail = fmt.Sprintf("of %s (%s)", utils.MemorySize(summary.HeapMax), utils.Precision(0).Percent(fraction*100))
+		peak.Level = levelAbove(fraction, 0.7, 0.9)
+	}
+	data.Cards = append(data.Cards, peak,
+		reportCard{Label: "Alerts", Value: utils.FormatCount(int64(len(summary.Alerts))),
+			Detail: fmt.Sprintf("%s snapshots", utils.FormatCount(summary.Snapshots)), Level: alertsLevel(summary.Alerts)})
+
+	data.Charts = reportCharts(export)
+	data.Pauses, data.Longest = reportPauses(export.GCEvents)
+	for _, alert := range summary.Alerts {
+		data.Alerts = append(data.Alerts, reportAlert{
+			Time:        alert.Timestamp.Format("15:04:05"),
+			Level:       alert.Level,
+			Title:       alert.Title,
+			Description: alert.Description,
+			Resolved:    alert.Resolved,
+		})
+	}
+	return data
+}
+
+func reportCharts(export *WatchExport) []reportChart {
+	field := func(name, series, key string, scale float64) chartSeries {
+		line := chartSeries{Name: name}
+		for _, point := range export.History[series] {
+			if value, ok := point.Values[key]; ok {
+				line.Times = append(line.Times, point.Time)
+				line.Values = append(line.Values, value*scale)
+			}
+		}
+		return line
+	}
+
+	var young, old chartSeries
+	young.Name, old.Name = "Young", "Old"
+	young.Scatter, old.Scatter = true, true
+	old.Color = "#dc2626"
+	for _, event := range export.GCEvents {
+		series := &young
+		if event.Generation == "old" {
+			series = &old
+		}
+		series.Times = append(series.Times, event.Time)
+		series.Values = append(series.Values, float64(event.Duration)/float64(time.Millisecond))
+	}
+
+	var charts []reportChart
+	for _, chart := range []struct {
+		title  string
+		unit   string
+		series []chartSeries
+	}{
+		{"Heap", "MB", []chartSeries{field("Used", "heap", "used_mb", 1), field("Committed", "heap", "committed_mb", 1)}},
+		{"GC pauses", "ms", []chartSeries{young, old}},
+		{"Allocation rate", "MB/s", []chartSeries{field("Allocation", "gcRates", "allocation_mb_s", 1),
+			field("Promotion", "gcRates", "promotion_mb_s", 1)}},
+		{"CPU", "%", []chartSeries{field("Process", "system", "process_cpu", 100), field("System", "system", "system_cpu", 100)}},
+		{"Threads", "", []chartSeries{field("Live", "threads", "current_count", 1), field("Daemon", "threads", "daemon_count", 1)}},
+	} {
+		svg, legend := renderChart(chart.series, chart.unit)
+		if svg == "" {
+			continue
+		}
+		charts = append(charts, reportChart{Title: chart.title, SVG: svg, Legend: legend})
+	}
+	return charts
+}
+
+// reportPauses gives the pause percentiles and the longest pauses, longest first
+func reportPauses(events []ExportedGCEvent) ([]reportCard, []reportPause) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	durations := make([]time.Duration, len(events))
+	for i, event := range events {
+		durations[i] = event.Duration
+	}
+	slices.Sort(durations)
+	var percentiles []reportCard
+	for _, p := range []float64{50, 90, 95, 99} {
+		index := min(int(math.Ceil(p/100*float64(len(durations))))-1, len(durations)-1)
+		percentiles = append(percentiles, reportCard{Label: fmt.Sprintf("p%.0f", p),
+			Value: utils.FormatDuration(durations[max(index, 0)])})
+	}
+	percentiles = append(percentiles, reportCard{Label: "max", Value: utils.FormatDuration(durations[len(durations)-1])})
+
+	longest := slices.Clone(events)
+	slices.SortStableFunc(longest, func(a, b ExportedGCEvent) int { return cmp.Compare(b.Duration, a.Duration) })
+	var pauses []reportPause
+	for _, event := range longest[:min(len(longest), reportLongestPauses)] {
+		pauses = append(pauses, reportPause{
+			Time:       event.Time.Format("15:04:05.000"),
+			Generation: event.Generation,
+			Duration:   utils.FormatDuration(event.Duration),
+			Collected:  utils.MemorySize(event.Collected).String(),
+		})
+	}
+	return percentiles, pauses
+}
+
+/*
+ * renderChart draws the series over a shared time axis, from zero to the
+ * largest value. Lines with more than chartPoints points are thinned to
+ * the largest value of each stretch, so spikes survive. It returns "" when
+ * no series has a point.
+ */
+func renderChart(series []chartSeries, unit string) (template.HTML, []reportLegend) {
+	var start, end time.Time
+	top := 0.0
+	for _, s := range series {
+		for i, at := range s.Times {
+			if start.IsZero() || at.Before(start) {
+				start = at
+			}
+			if at.After(end) {
+				end = at
+			}
+			top = max(top, s.Values[i])
+		}
+	}
+	if start.IsZero() {
+		return "", nil
+	}
+	if top <= 0 {
+		top = 1
+	}
+	span := max(end.Sub(start), time.Second)
+
+	plotWidth := float64(chartWidth - chartLeft)
+	plotHeight := float64(chartHeight - chartBottom)
+	x := func(at time.Time) float64 { return chartLeft + float64(at.Sub(start))/float64(span)*plotWidth }
+	y := func(value float64) float64 { return plotHeight - value/top*(plotHeight-8) }
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg viewBox="0 0 %d %d" role="img" xmlns="http://www.w3.org/2000/svg">`, chartWidth, chartHeight)
+	for _, fraction := range []float64{0, 0.5, 1} {
+		value := top * fraction
+		fmt.Fprintf(&svg, `<line class="grid" x1="%d" x2="%d" y1="%.1f" y2="%.1f"/>`, chartLeft, chartWidth, y(value), y(value))
+		fmt.Fprintf(&svg, `<text class="axis" x="%d" y="%.1f" text-anchor="end">%s</text>`, chartLeft-6, y(value)+4,
+			template.HTMLEscapeString(strings.TrimSpace(utils.FormatFloat(value)+" "+unit)))
+	}
+	fmt.Fprintf(&svg, `<text class="axis" x="%d" y="%d">%s</text>`, chartLeft, chartHeight-4, start.Format("15:04:05"))
+	fmt.Fprintf(&svg, `<text class="axis" x="%d" y="%d" text-anchor="end">%s</text>`, chartWidth, chartHeight-4, end.Format("15:04:05"))
+
+	var legend []reportLegend
+	for n, s := range series {
+		if len(s.Times) == 0 {
+			continue
+		}
+		color := s.Color
+		if color == "" {
+			color = chartColors[n%len(chartColors)]
+		}
+		legend = append(legend, reportLegend{Name: s.Name, Color: color})
+
+		if s.Scatter {
+			for i, at := range s.Times {
+				fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"/>`, x(at), y(s.Values[i]), color)
+			}
+			continue
+		}
+		var points []string
+		for _, i := range thinIndexes(s.Values, chartPoints) {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(s.Times[i]), y(s.Values[i])))
+		}
+		fmt.Fprintf(&svg, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, color, strings.Join(points, " "))
+	}
+	svg.WriteString(`</svg>`)
+	return template.HTML(svg.String()), legend
+}
+
+// thinIndexes picks at most limit indexes, the largest value of each equal stretch
+func thinIndexes(values []float64, limit int) []int {
+	indexes := make([]int, 0, min(len(values), limit))
+	if len(values) <= limit {
+		for i := range values {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	for bucket := range limit {
+		from, to := bucket*len(values)/limit, (bucket+1)*len(values)/limit
+		peak := from
+		for i := from; i < to; i++ {
+			if values[i] > values[peak] {
+				peak = i
+			}
+		}
+		indexes = append(indexes, peak)
+	}
+	return indexes
+}
+
+// levelAbove is "critical" above critical, "warning" above warning and "" otherwise
+func levelAbove(value, warning, critical float64) string {
+	switch {
+	case value > critical:
+		return "critical"
+	case value > warning:
+		return "warning"
+	}
+	return ""
+}
+
+func alertsLevel(alerts []PerformanceAlert) string {
+	level := ""
+	for _, alert := range alerts {
+		switch alert.Level {
+		case "critical":
+			return "critical"
+		case "warning":
+			level = "warning"
+		}
+	}
+	return level
+}
+
+// SaveReport writes the session's HTML report to path, or its export as 'e' saves it when path ends in .json
+func SaveReport(path string, summary *SessionSummary, export *WatchExport) error {
+	var content bytes.Buffer
+	if strings.HasSuffix(path, ".json") {
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode export: %w", err)
+		}
+		content.Write(data)
+	} else if err := WriteHTMLReport(&content, summary, export); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, content.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
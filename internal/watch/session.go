@@ -0,0 +1,199 @@
+package watch
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+// SessionSummary is what one watch session saw, printed when it ends and
+// saved as JSON for 'jdiag report' to pick up
+type SessionSummary struct {
+	Target    string    `json:"target"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Snapshots int64     `json:"snapshots"`
+
+	YoungGCs   int64         `json:"youngGCs"`
+	OldGCs     int64         `json:"oldGCs"`
+	GCTime     time.Duration `json:"gcTimeNs"`
+	GCOverhead float64       `json:"gcOverhead"` // Share of the session spent in GC
+	MaxPause   time.Duration `json:"maxPauseNs"`
+
+	PeakHeap int64 `json:"peakHeap"`
+	HeapMax  int64 `json:"heapMax"`
+
+	Alerts     []PerformanceAlert `json:"alerts"`
+	Advisories []string           `json:"advisories,omitempty"` // Titles of the advisories raised, in the order first seen
+
+	// Threads caught running native code other than waiting on I/O, most often caught first
+	NativeThreads []NativeThread `json:"nativeThreads,omitempty"`
+
+	first, last *jmx.MBeanSnapshot
+}
+
+// NativeThread is a thread snapshots caught running a native method, and which.
+// These are the threads that can hold a JNI critical region and stall GC.
+type NativeThread struct {
+	Name      string `json:"name"`
+	Frame     string `json:"frame"`     // Native method on top of its stack, e.g. java.util.zip.Deflater.deflateBytesBytes
+	Snapshots int64  `json:"snapshots"` // Snapshots that caught it there
+}
+
+// MaxNativeThreads is how many native threads a session keeps
+const MaxNativeThreads = 10
+
+/*
+ * Native methods that block on I/O, signals or child processes run without
+ * touching the heap. They keep a thread in native far longer than any real
+ * work does, so they'd crowd out the threads worth investigating.
+ */
+var nativeWaitPrefixes = []string{
+	"sun.nio.ch.", "sun.nio.fs.", "java.net.", "java.io.FileInputStream.", "java.io.FileOutputStream.",
+	"java.io.RandomAccessFile.", "java.lang.ProcessHandleImpl.", "java.lang.ProcessImpl.", "jdk.internal.misc.Signal.",
+}
+
+func NewSessionSummary(target string) *SessionSummary {
+	return &SessionSummary{Target: target, Start: time.Now()}
+}
+
+// Record adds a connected snapshot and what the tabs derived from it
+func (s *SessionSummary) Record(metrics *jmx.MBeanSnapshot, tracker *GCEventTracker, advisories []Advisory) {
+	if s.first == nil {
+		s.first = metrics
+	}
+	s.last = metrics
+	s.End = metrics.Timestamp
+	s.Snapshots++
+
+	s.YoungGCs = metrics.GC.YoungGCCount - s.first.GC.YoungGCCount
+	s.OldGCs = metrics.GC.OldGCCount - s.first.GC.OldGCCount
+	s.GCTime = time.Duration(metrics.GC.YoungGCTime+metrics.GC.OldGCTime-s.first.GC.YoungGCTime-s.first.GC.OldGCTime) * time.Millisecond
+	if elapsed := metrics.Timestamp.Sub(s.first.Timestamp); elapsed > 0 {
+		s.GCOverhead = float64(s.GCTime) / float64(elapsed)
+	}
+
+	for _, event := range tracker.GetRecentEvents(10) {
+		s.MaxPause = max(s.MaxPause, event.Duration)
+	}
+	s.PeakHeap = max(s.PeakHeap, metrics.Memory.Heap.Used)
+	s.HeapMax = metrics.Memory.Heap.Max
+
+	for _, advisory := range advisories {
+		if !slices.Contains(s.Advisories, advisory.Title) {
+			s.Advisories = append(s.Advisories, advisory.Title)
+		}
+	}
+	s.recordNativeThreads(metrics.Threading.Threads)
+}
+
+// recordNativeThreads counts the runnable threads the snapshot caught in native code, keeping the most frequent
+func (s *SessionSummary) recordNativeThreads(threads []jmx.ThreadInfo) {
+	for _, thread := range threads {
+		if !thread.InNative || thread.State != "RUNNABLE" || thread.TopFrame == "" ||
+			slices.ContainsFunc(nativeWaitPrefixes, func(prefix string) bool { return strings.HasPrefix(thread.TopFrame, prefix) }) {
+			continue
+		}
+		index := slices.IndexFunc(s.NativeThreads, func(native NativeThread) bool {
+			return native.Name == thread.Name && native.Frame == thread.TopFrame
+		})
+		if index < 0 {
+			s.NativeThreads = append(s.NativeThreads, NativeThread{Name: thread.Name, Frame: thread.TopFrame})
+			index = len(s.NativeThreads) - 1
+		}
+		s.NativeThreads[index].Snapshots++
+	}
+
+	slices.SortStableFunc(s.NativeThreads, func(a, b NativeThread) int { return cmp.Compare(b.Snapshots, a.Snapshots) })
+	if len(s.NativeThreads) > MaxNativeThreads {
+		s.NativeThreads = s.NativeThreads[:MaxNativeThreads]
+	}
+}
+
+// RecordAlerts keeps the alerts fired during the session
+func (s *SessionSummary) RecordAlerts(alerts []PerformanceAlert) {
+	s.Alerts = append(s.Alerts, alerts...)
+}
+
+// Empty reports whether the session never got a snapshot from the JVM
+func (s *SessionSummary) Empty() bool {
+	return s.Snapshots == 0
+}
+
+// WriteJSON saves the summary in the form LoadSessionSummary reads back
+func (s *SessionSummary) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// LoadSessionSummary reads a summary saved with --summary <file>.json
+func LoadSessionSummary(path string) (*SessionSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read session summary: %w", err)
+	}
+
+	var summary SessionSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("%s is not a saved watch session: %w", path, err)
+	}
+	if summary.Snapshots == 0 {
+		return nil, fmt.Errorf("%s has no snapshots", path)
+	}
+	return &summary, nil
+}
+
+// Write prints the summary as plain text, for the terminal and for --summary files
+func (s *SessionSummary) Write(w io.Writer) {
+	fmt.Fprintln(w, "👀 JDIAG WATCH SESSION")
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+	fmt.Fprintf(w, "   Target:    %s\n", s.Target)
+	fmt.Fprintf(w, "   Session:   %s to %s (%s, %d snapshots)\n", s.Start.Format("2006-01-02 15:04:05"),
+		s.End.Format("15:04:05"), utils.FormatDuration(s.End.Sub(s.Start).Truncate(time.Second)), s.Snapshots)
+	if s.last != nil && s.last.Runtime.VmName != "" {
+		fmt.Fprintf(w, "   JVM:       %s %s  |  Up %s\n", s.last.Runtime.VmName, s.last.Runtime.VmVersion,
+			utils.FormatDuration(s.last.Runtime.Uptime.Truncate(time.Second)))
+	}
+
+	fmt.Fprintf(w, "   GC:        %d young, %d old, %s in GC (%s overhead)\n", s.YoungGCs, s.OldGCs,
+		utils.FormatDuration(s.GCTime), utils.Precision(2).Percent(s.GCOverhead*100))
+	if s.MaxPause > 0 {
+		fmt.Fprintf(w, "   Max pause: %s\n", utils.FormatDuration(s.MaxPause))
+	}
+
+	heap := fmt.Sprintf("%s peak used", utils.MemorySize(s.PeakHeap))
+	if s.HeapMax > 0 {
+		heap += fmt.Sprintf(" of %s (%s)", utils.MemorySize(s.HeapMax),
+			utils.Precision(0).Percent(float64(s.PeakHeap)/float64(s.HeapMax)*100))
+	}
+	fmt.Fprintf(w, "   Heap:      %s\n", heap)
+
+	if len(s.Alerts) == 0 {
+		fmt.Fprintln(w, "   Alerts:    none fired")
+	} else {
+		fmt.Fprintf(w, "   Alerts:    %d fired\n", len(s.Alerts))
+		for _, alert := range s.Alerts {
+			icon := utils.GetSeverityIcon(alert.Level)
+			if alert.Resolved {
+				icon = utils.GetSeverityIcon("good")
+			}
+			fmt.Fprintf(w, "     %s  %s %s: %s\n", alert.Timestamp.Format("15:04:05"), icon, alert.Title, alert.Description)
+		}
+	}
+	if len(s.Advisories) > 0 {
+		fmt.Fprintf(w, "   Advised:   %s\n", strings.Join(s.Advisories, ", "))
+	}
+	if len(s.NativeThreads) > 0 {
+		native := s.NativeThreads[0]
+		fmt.Fprintf(w, "   Native:    %s in %s (%d of %d snapshots)\n", native.Name, native.Frame, native.Snapshots, s.Snapshots)
+	}
+}
@@ -9,6 +9,31 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Resource thresholds, as fractions of the limit. Load is per available CPU.
+const (
+	cpuWarning   = 0.80
+	cpuCritical  = 0.95
+	loadWarning  = 1.0
+	loadCritical = 2.0
+	fdWarning    = 0.80
+	fdCritical   = 0.95
+	ramWarning   = 0.90
+	ramCritical  = 0.97
+	swapWarning  = 0.05
+	swapCritical = 0.25
+)
+
+// resourceLevel grades a value against its thresholds as "critical", "warning" or ""
+func resourceLevel(value, warning, critical float64) string {
+	switch {
+	case value >= critical:
+		return "critical"
+	case value >= warning:
+		return "warning"
+	}
+	return ""
+}
+
 // Render renders the system tab view
 func RenderSystemTab(state *TabState, config *jmx.Config, width int, systemHistory []utils.TimeMap) string {
 	var sections []string
@@ -17,6 +42,9 @@ func RenderSystemTab(state *TabState, config *jmx.Config, width int, systemHisto
 	overviewSection := renderSystemOverview(state.System)
 	sections = append(sections, overviewSection)
 
+	// Resource gauges against their thresholds
+	sections = append(sections, renderResourceGauges(state.System, width))
+
 	// Charts section (CPU and Memory charts side by side)
 	chartsSection := renderSystemCharts(state.System, width, systemHistory)
 	sections = append(sections, chartsSection)
@@ -37,16 +65,26 @@ func renderSystemOverview(system *SystemState) string {
 	// Determine overall system health
 	cpuLoad := system.ProcessCpuLoad
 	systemLoad := system.SystemCpuLoad
+	worst := ""
+	for _, gauge := range resourceGauges(system) {
+		if gauge.level == "critical" {
+			worst = gauge.level
+			break
+		}
+		if gauge.level != "" {
+			worst = gauge.level
+		}
+	}
 
 	switch {
-	case cpuLoad > 0.95 || systemLoad > 0.95:
+	case worst == "critical":
 		statusColor = utils.CriticalColor
 		statusIcon = "🔴"
-		statusText = "Critical system load"
-	case cpuLoad > 0.80 || systemLoad > 0.85:
+		statusText = "Critical resource pressure"
+	case worst == "warning":
 		statusColor = utils.WarningColor
 		statusIcon = "🟡"
-		statusText = "High system load"
+		statusText = "High resource pressure"
 	case cpuLoad > 0.60 || systemLoad > 0.70:
 		statusColor = utils.InfoColor
 		statusIcon = "🟠"
@@ -71,6 +109,99 @@ func renderSystemOverview(system *SystemState) string {
 	return overview + "\n"
 }
 
+// resourceGauge is one OS resource as a share of its limit
+type resourceGauge struct {
+	label    string
+	fraction float64 // Bar fill, 0 to 1
+	value    string
+	level    string // "critical", "warning" or ""
+}
+
+// resourceGauges grades the resources the OS reports; file descriptors and load
+// are left out where the platform doesn't report them
+func resourceGauges(system *SystemState) []resourceGauge {
+	gauges := []resourceGauge{
+		{
+			label:    "Process CPU",
+			fraction: system.ProcessCpuLoad,
+			value:    utils.FormatPercent(system.ProcessCpuLoad * 100),
+			level:    resourceLevel(system.ProcessCpuLoad, cpuWarning, cpuCritical),
+		},
+		{
+			label:    "System CPU",
+			fraction: system.SystemCpuLoad,
+			value:    utils.FormatPercent(system.SystemCpuLoad * 100),
+			level:    resourceLevel(system.SystemCpuLoad, cpuWarning, cpuCritical),
+		},
+	}
+
+	if system.SystemLoad >= 0 && system.AvailableProcessors > 0 {
+		perCPU := system.SystemLoad / float64(system.AvailableProcessors)
+		gauges = append(gauges, resourceGauge{
+			label:    "Load average",
+			fraction: perCPU / loadCritical,
+			value: fmt.Sprintf("%s on %d CPUs (%s per CPU)", utils.Precision(2).Float(system.SystemLoad),
+				system.AvailableProcessors, utils.Precision(2).Float(perCPU)),
+			level: resourceLevel(perCPU, loadWarning, loadCritical),
+		})
+	}
+
+	if system.MaxFileDescriptors > 0 {
+		gauges = append(gauges, resourceGauge{
+			label:    "File descriptors",
+			fraction: system.FileDescriptorPercent,
+			value:    fmt.Sprintf("%d of %d open", system.OpenFileDescriptors, system.MaxFileDescriptors),
+			level:    resourceLevel(system.FileDescriptorPercent, fdWarning, fdCritical),
+		})
+	}
+
+	if system.TotalSystemMemory > 0 {
+		gauges = append(gauges, resourceGauge{
+			label:    "RAM",
+			fraction: system.SystemMemoryPercent,
+			value:    fmt.Sprintf("%s of %s", utils.MemorySize(system.UsedSystemMemory), utils.MemorySize(system.TotalSystemMemory)),
+			level:    resourceLevel(system.SystemMemoryPercent, ramWarning, ramCritical),
+		})
+	}
+
+	if system.TotalSwap > 0 {
+		gauges = append(gauges, resourceGauge{
+			label:    "Swap",
+			fraction: system.SwapPercent,
+			value:    fmt.Sprintf("%s of %s", utils.MemorySize(system.UsedSwap), utils.MemorySize(system.TotalSwap)),
+			level:    resourceLevel(system.SwapPercent, swapWarning, swapCritical),
+		})
+	}
+
+	return gauges
+}
+
+// renderResourceGauges lists each resource as a bar colored by its thresholds
+func renderResourceGauges(system *SystemState, width int) string {
+	barWidth := min(max(width/4, 10), 40)
+
+	lines := []string{utils.InfoStyle.Render("Resources")}
+	for _, gauge := range resourceGauges(system) {
+		color := utils.GoodColor
+		status := ""
+		switch gauge.level {
+		case "critical":
+			color = utils.CriticalColor
+			status = utils.CriticalStyle.Render("  " + utils.GetSeverityIcon(gauge.level) + " critical")
+		case "warning":
+			color = utils.WarningColor
+			status = utils.WarningStyle.Render("  " + utils.GetSeverityIcon(gauge.level) + " high")
+		}
+		lines = append(lines, fmt.Sprintf("%-17s%s  %s%s", gauge.label,
+			utils.CreateProgressBar(gauge.fraction, barWidth, color), utils.MutedStyle.Render(gauge.value), status))
+	}
+
+	if system.MaxFileDescriptors <= 0 {
+		lines = append(lines, utils.MutedStyle.Render("File descriptors are not reported on this platform"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...) + "\n"
+}
+
 // renderSystemCharts shows CPU and Memory charts side by side
 func renderSystemCharts(system *SystemState, width int, systemHistory []utils.TimeMap) string {
 	chartWidth := width - 20
@@ -79,18 +210,21 @@ func renderSystemCharts(system *SystemState, width int, systemHistory []utils.Ti
 	memoryChartSection := renderMemoryChart(system, chartWidth, systemHistory)
 
 	chartsRow := lipgloss.JoinVertical(lipgloss.Top, "", cpuChartSection, "", memoryChartSection)
+	if system.MaxFileDescriptors > 0 {
+		chartsRow = lipgloss.JoinVertical(lipgloss.Top, chartsRow, renderFileDescriptorChart(system, chartWidth, systemHistory))
+	}
 
 	return chartsRow + "\n"
 }
 
 // renderCPUChart renders the CPU usage chart
 func renderCPUChart(system *SystemState, width int, systemHistory []utils.TimeMap) string {
-	valuesText := fmt.Sprintf("Process: %.1f%% | System: %.1f%%",
-		system.ProcessCpuLoad*100,
-		system.SystemCpuLoad*100)
+	valuesText := fmt.Sprintf("Process: %s | System: %s",
+		utils.FormatPercent(system.ProcessCpuLoad*100),
+		utils.FormatPercent(system.SystemCpuLoad*100))
 
 	if system.SystemLoad > 0 {
-		valuesText += fmt.Sprintf(" | Load: %.2f", system.SystemLoad)
+		valuesText += fmt.Sprintf(" | Load: %s", utils.Precision(2).Float(system.SystemLoad))
 	}
 
 	var chartView string
@@ -143,11 +277,11 @@ func renderCPUChart(system *SystemState, width int, systemHistory []utils.TimeMa
 
 // renderMemoryChart renders the RAM and Swap usage chart
 func renderMemoryChart(system *SystemState, width int, systemHistory []utils.TimeMap) string {
-	valuesText := fmt.Sprintf("RAM: %s (%.1f%%) | Swap: %s (%.1f%%)",
+	valuesText := fmt.Sprintf("RAM: %s (%s) | Swap: %s (%s)",
 		utils.MemorySize(system.UsedSystemMemory),
-		system.SystemMemoryPercent*100,
+		utils.FormatPercent(system.SystemMemoryPercent*100),
 		utils.MemorySize(system.UsedSwap),
-		system.SwapPercent*100)
+		utils.FormatPercent(system.SwapPercent*100))
 
 	var chartView string
 
@@ -197,6 +331,40 @@ func renderMemoryChart(system *SystemState, width int, systemHistory []utils.Tim
 	return section
 }
 
+// renderFileDescriptorChart charts open file descriptors, with the warning level as
+// a line once the count gets near it
+func renderFileDescriptorChart(system *SystemState, width int, systemHistory []utils.TimeMap) string {
+	chart := utils.NewChart(max(width-10, 30), 8)
+
+	warning := fdWarning * float64(system.MaxFileDescriptors)
+	var peak float64
+	for _, point := range systemHistory {
+		open := point.GetOrDefault("open_fds", 0)
+		peak = max(peak, open)
+		chart.Push(utils.TimePoint{Time: point.Timestamp, Value: open})
+	}
+	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.InfoColor))
+
+	legend := lipgloss.NewStyle().Foreground(utils.InfoColor).Render("■ Open")
+	if peak >= warning/2 {
+		for _, point := range systemHistory {
+			chart.PushDataSet("warning", utils.TimePoint{Time: point.Timestamp, Value: warning})
+		}
+		chart.SetDataSetStyle("warning", lipgloss.NewStyle().Foreground(utils.WarningColor))
+		legend += "  " + lipgloss.NewStyle().Foreground(utils.WarningColor).Render(
+			fmt.Sprintf("■ %s of max", utils.Precision(0).Percent(fdWarning*100)))
+	}
+
+	chart.DrawBrailleAll()
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		utils.InfoStyle.Render("File Descriptors"),
+		lipgloss.JoinHorizontal(lipgloss.Left, chart.View(), "", legend),
+		utils.MutedStyle.Render(fmt.Sprintf("Open: %d | Max: %d | Peak: %.0f", system.OpenFileDescriptors, system.MaxFileDescriptors, peak)),
+		"", // Empty line for spacing
+	)
+}
+
 // renderJVMInfo shows JVM version and runtime information
 func renderJVMInfo(system *SystemState) string {
 	var jvmLines []string
@@ -2,16 +2,24 @@ package watch
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mabhi256/jdiag/utils"
 )
 
+// MaxBlockedWarnings caps the blocked-thread warnings listed at the top of the tab
+const MaxBlockedWarnings = 5
+
 // Render renders the threads tab view
-func RenderThreadsTab(state *TabState, width int, classHistory []utils.TimeMap, threadHistory []utils.TimeMap) string {
+func RenderThreadsTab(state *TabState, width int, threadHistory []utils.TimeMap) string {
 	var sections []string
 
-	chartsSection := renderThreadsCharts(state.Threads, width, classHistory, threadHistory)
+	if len(state.Threads.BlockedThreads) > 0 {
+		sections = append(sections, renderBlockedWarnings(state.Threads))
+	}
+
+	chartsSection := renderThreadsCharts(state.Threads, width, threadHistory)
 	sections = append(sections, chartsSection)
 
 	// Thread performance metrics
@@ -20,23 +28,30 @@ func RenderThreadsTab(state *TabState, width int, classHistory []utils.TimeMap,
 		sections = append(sections, performanceSection)
 	}
 
-	// Thread state breakdown (if available)
-	if state.Threads.BlockedThreadCount > 0 || state.Threads.WaitingThreadCount > 0 {
-		stateSection := renderThreadStates(state.Threads)
-		sections = append(sections, stateSection)
+	// Per-thread details, when the JVM reports them
+	if len(state.Threads.StateCounts) > 0 {
+		sections = append(sections, renderThreadStates(state.Threads, width))
+		sections = append(sections, utils.Columns(width, 4,
+			renderTopThreads("Top CPU", state.Threads.TopCPU, func(u ThreadUsage) string {
+				return utils.FormatPercent(u.CPUPercent)
+			}),
+			renderTopThreads("Top Allocating", state.Threads.TopAllocating, func(u ThreadUsage) string {
+				return utils.MemorySize(u.AllocationRate).String() + "/s"
+			})))
+	} else if state.Threads.CurrentThreadCount > 0 {
+		sections = append(sections, utils.MutedStyle.Render("Per-thread details are not available from this JVM"))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
-// Show thread and class charts side by side
-func renderThreadsCharts(threads *ThreadState, width int, classHistory []utils.TimeMap, threadHistory []utils.TimeMap) string {
+// Show the thread count chart
+func renderThreadsCharts(threads *ThreadState, width int, threadHistory []utils.TimeMap) string {
 	chartWidth := width - 20
 
 	threadsChartSection := renderThreadChart(threads, chartWidth, threadHistory)
-	classChartSection := renderClassChart(threads, chartWidth, classHistory)
 
-	chartsRow := lipgloss.JoinVertical(lipgloss.Top, "", threadsChartSection, "", classChartSection)
+	chartsRow := lipgloss.JoinVertical(lipgloss.Top, "", threadsChartSection)
 
 	return chartsRow + "\n"
 }
@@ -94,59 +109,6 @@ func renderThreadChart(threads *ThreadState, width int, threadHistory []utils.Ti
 	return section
 }
 
-// renderClassChart renders the class loading chart
-func renderClassChart(threads *ThreadState, width int, classHistory []utils.TimeMap) string {
-	valuesText := fmt.Sprintf("Loaded: %d | Unloaded: %d | Currently Loaded: %d",
-		threads.LoadedClassCount,
-		threads.UnloadedClassCount,
-		threads.TotalLoadedClasses)
-
-	var chartView string
-
-	graphWidth := max(width-10, 30)
-	graphHeight := 8
-
-	chart := utils.NewChart(graphWidth, graphHeight)
-
-	for _, point := range classHistory {
-		totalLoaded := point.GetOrDefault("total_loaded", 0)
-		chart.Push(utils.TimePoint{
-			Time:  point.Timestamp,
-			Value: float64(totalLoaded),
-		})
-	}
-
-	// Set current threads style (green)
-	chart.SetStyle(lipgloss.NewStyle().Foreground(utils.GoodColor))
-
-	for _, point := range classHistory {
-		unloadedCount := point.GetOrDefault("unloaded_count", 0)
-		chart.PushDataSet("unloaded", utils.TimePoint{
-			Time:  point.Timestamp,
-			Value: float64(unloadedCount),
-		})
-	}
-	chart.SetDataSetStyle("unloaded", lipgloss.NewStyle().Foreground(utils.InfoColor))
-
-	chart.DrawBrailleAll()
-
-	// Create legend
-	loadedLegend := lipgloss.NewStyle().Foreground(utils.GoodColor).Render("■ Total Loaded")
-	unloadedLegend := lipgloss.NewStyle().Foreground(utils.InfoColor).Render("■ Unloaded")
-	legend := lipgloss.JoinHorizontal(lipgloss.Left, loadedLegend, "  ", unloadedLegend)
-
-	chartView = lipgloss.JoinHorizontal(lipgloss.Left, chart.View(), "", legend)
-
-	section := lipgloss.JoinVertical(lipgloss.Left,
-		utils.InfoStyle.Render("Classes"),
-		chartView,
-		utils.MutedStyle.Render(valuesText),
-		"", // Empty line for spacing
-	)
-
-	return section
-}
-
 // renderThreadPerformance shows thread performance metrics
 func renderThreadPerformance(threads *ThreadState) string {
 	var performanceLines []string
@@ -161,7 +123,7 @@ func renderThreadPerformance(threads *ThreadState) string {
 		}
 
 		creationLine := lipgloss.NewStyle().Foreground(creationColor).Render(
-			fmt.Sprintf("Thread Creation Rate: %.1f/min", threads.ThreadCreationRate))
+			fmt.Sprintf("Thread Creation Rate: %s/min", utils.FormatFloat(threads.ThreadCreationRate)))
 		performanceLines = append(performanceLines, "• "+creationLine)
 	}
 
@@ -195,46 +157,97 @@ func renderThreadPerformance(threads *ThreadState) string {
 	return section
 }
 
-func renderThreadStates(threads *ThreadState) string {
-	runningThreads := threads.CurrentThreadCount - threads.BlockedThreadCount - threads.WaitingThreadCount
-
-	var stateLines []string
+// threadStates are the Thread.State values in the order the breakdown bar shows them
+var threadStates = []struct {
+	name  string
+	label string
+	color lipgloss.Color
+}{
+	{"RUNNABLE", "Runnable", utils.GoodColor},
+	{"BLOCKED", "Blocked", utils.CriticalColor},
+	{"WAITING", "Waiting", utils.InfoColor},
+	{"TIMED_WAITING", "Timed Waiting", utils.WarningColor},
+	{"NEW", "New", utils.MutedColor},
+}
 
-	if runningThreads > 0 {
-		stateLines = append(stateLines,
-			fmt.Sprintf("Running: %d", runningThreads))
+// renderThreadStates draws one bar split by thread state, with the counts below
+func renderThreadStates(threads *ThreadState, width int) string {
+	var total int64
+	for _, count := range threads.StateCounts {
+		total += count
+	}
+	if total == 0 {
+		return ""
 	}
 
-	if threads.BlockedThreadCount > 0 {
-		blockedColor := utils.WarningColor
-		if threads.BlockedThreadCount > threads.CurrentThreadCount/4 { // More than 25% blocked
-			blockedColor = utils.CriticalColor
+	barWidth := max(width-4, 10)
+	var bar strings.Builder
+	var legend []string
+	used := 0
+	for _, state := range threadStates {
+		count := threads.StateCounts[state.name]
+		if count == 0 {
+			continue
 		}
+		cells := max(int(float64(count)/float64(total)*float64(barWidth)+0.5), 1)
+		cells = min(cells, barWidth-used)
+		used += cells
 
-		blockedLine := lipgloss.NewStyle().Foreground(blockedColor).Render(
-			fmt.Sprintf("Blocked: %d", threads.BlockedThreadCount))
-		stateLines = append(stateLines, blockedLine)
+		style := lipgloss.NewStyle().Foreground(state.color)
+		bar.WriteString(style.Render(strings.Repeat("█", cells)))
+		legend = append(legend, style.Render("■")+fmt.Sprintf(" %s %d", state.label, count))
 	}
 
-	if threads.WaitingThreadCount > 0 {
-		stateLines = append(stateLines,
-			fmt.Sprintf("Waiting: %d", threads.WaitingThreadCount))
+	blockedColor := utils.GoodColor
+	if threads.BlockedThreadCount > 0 {
+		blockedColor = utils.WarningColor
 	}
-
-	if len(stateLines) == 0 {
-		return ""
+	if threads.BlockedThreadCount > threads.CurrentThreadCount/4 { // More than 25% blocked
+		blockedColor = utils.CriticalColor
 	}
+	title := utils.InfoStyle.Render("Thread States") + " " +
+		lipgloss.NewStyle().Foreground(blockedColor).Render(fmt.Sprintf("(%d blocked)", threads.BlockedThreadCount))
 
-	statesText := "• " + stateLines[0]
-	for _, line := range stateLines[1:] {
-		statesText += "\n• " + line
-	}
-
-	section := lipgloss.JoinVertical(lipgloss.Left,
-		utils.InfoStyle.Render("Thread States"),
-		statesText,
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		bar.String(),
+		strings.Join(legend, "  "),
 		"", // Empty line for spacing
 	)
+}
 
-	return section
+// renderTopThreads lists the busiest threads since the previous poll
+func renderTopThreads(title string, usage []ThreadUsage, value func(ThreadUsage) string) string {
+	lines := []string{utils.InfoStyle.Render(title)}
+	if len(usage) == 0 {
+		lines = append(lines, utils.MutedStyle.Render("No data yet, needs two polls"))
+	}
+	for _, used := range usage {
+		lines = append(lines, fmt.Sprintf("%10s  %-30s %s", value(used),
+			utils.TruncateString(used.Name, 30), utils.MutedStyle.Render(used.State)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, "")...)
+}
+
+// renderBlockedWarnings names the blocked threads and the lock holders they wait on
+func renderBlockedWarnings(threads *ThreadState) string {
+	blocked := threads.BlockedThreads
+	lines := []string{utils.CriticalStyle.Render(fmt.Sprintf("⚠️  Blocked threads: %d", len(blocked)))}
+
+	for _, thread := range blocked[:min(len(blocked), MaxBlockedWarnings)] {
+		line := fmt.Sprintf("• %s blocked on %s", thread.Name, thread.LockName)
+		if thread.LockOwner != "" {
+			line += " held by " + thread.LockOwner
+		}
+		detail := fmt.Sprintf(" (blocked %d times", thread.BlockedCount)
+		if thread.BlockedTime > 0 {
+			detail += ", " + utils.FormatDuration(thread.BlockedTime) + " total"
+		}
+		lines = append(lines, utils.WarningStyle.Render(line)+utils.MutedStyle.Render(detail+")"))
+	}
+	if len(blocked) > MaxBlockedWarnings {
+		lines = append(lines, utils.MutedStyle.Render(fmt.Sprintf("  …and %d more", len(blocked)-MaxBlockedWarnings)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, append(lines, "")...)
 }
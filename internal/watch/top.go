@@ -0,0 +1,211 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/utils"
+)
+
+/*
+ * jdiag top
+ *
+ * A one-screen summary of a JVM, redrawn every interval: heap, GC rate and
+ * pauses, CPU, threads and classes. It's for SSH sessions too slow or small
+ * for the dashboard, and for a quick look (-n 1). Each frame is a handful of
+ * lines of plain text, so a redraw costs a few hundred bytes.
+ *
+ * Rates and average pauses are over the last interval, except on the first
+ * frame, which has no earlier sample to compare with: its rates are since
+ * the JVM started. When stdout isn't a terminal, frames are appended instead
+ * of redrawn, one block per sample, which suits logging to a file.
+ */
+
+const topLabelWidth = 9
+
+// RunTop prints a summary of the JVM every interval until Ctrl-C, or until iterations frames when above 0
+func RunTop(config *jmx.Config, iterations int, out io.Writer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	collector := jmx.NewJMXCollector(config)
+	if err := collector.Start(); err != nil {
+		return err
+	}
+	defer collector.Stop()
+
+	redraw := false
+	if file, ok := out.(*os.File); ok {
+		redraw = term.IsTerminal(file.Fd())
+	}
+
+	// The collector samples on its own ticker; draw each sample once
+	ticker := time.NewTicker(max(config.GetInterval()/4, 50*time.Millisecond))
+	defer ticker.Stop()
+
+	var previous *jmx.MBeanSnapshot
+	var last time.Time
+	for frames := 0; iterations <= 0 || frames < iterations; {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		snapshot := collector.GetMetrics()
+		if !snapshot.Timestamp.After(last) {
+			continue
+		}
+		last = snapshot.Timestamp
+		frames++
+
+		frame := renderTopFrame(config.String(), snapshot, previous)
+		if redraw {
+			fmt.Fprint(out, "\033[H\033[2J") // Home and clear, rather than the alternate screen, so the last frame stays
+		} else if frames > 1 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintln(out, utils.ToASCII(frame))
+
+		if !snapshot.Connected {
+			if iterations > 0 && frames == iterations && snapshot.Error != nil {
+				return snapshot.Error
+			}
+			continue
+		}
+		previous = snapshot
+	}
+	return nil
+}
+
+func renderTopFrame(target string, snapshot, previous *jmx.MBeanSnapshot) string {
+	var lines []string
+	header := fmt.Sprintf("jdiag top - %s", target)
+	if snapshot.Connected && snapshot.Runtime.Uptime > 0 {
+		header += "  up " + utils.FormatDuration(snapshot.Runtime.Uptime)
+	}
+	lines = append(lines, utils.TitleStyle.Padding(0).Render(header)+"  "+utils.MutedStyle.Render(snapshot.Timestamp.Format("15:04:05")))
+
+	if !snapshot.Connected {
+		message := "connecting..."
+		if snapshot.Error != nil {
+			message = snapshot.Error.Error()
+		}
+		lines = append(lines, utils.WarningStyle.Render("⚠️  "+message))
+		if previous != nil {
+			lines = append(lines, utils.MutedStyle.Render("   Last sample at "+previous.Timestamp.Format("15:04:05")))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	memory := snapshot.Memory
+	heap := topMemory(memory.Heap)
+	if memory.Heap.Max > 0 {
+		fraction := float64(memory.Heap.Used) / float64(memory.Heap.Max)
+		heap = fmt.Sprintf("%s %s  %s", utils.CreateProgressBar(fraction, 20, usageColor(fraction)),
+			topMemory(memory.Heap), utils.MutedStyle.Render("committed "+utils.FormatBytes(memory.Heap.Committed)))
+	}
+	lines = append(lines, topLine("Heap", heap))
+	if memory.G1OldGen.Valid {
+		lines = append(lines, topLine("Old", topMemory(memory.G1OldGen.Usage)))
+	}
+	lines = append(lines, topLine("Non-heap", fmt.Sprintf("%s  %s", utils.FormatBytes(memory.NonHeap.Used),
+		utils.MutedStyle.Render("metaspace "+utils.FormatBytes(memory.Metaspace.Usage.Used)))))
+
+	lines = append(lines, topLine("GC", renderTopGC(snapshot, previous)))
+
+	system := snapshot.OS
+	cpu := fmt.Sprintf("process %s  system %s", utils.FormatPercent(max(system.ProcessCpuLoad, 0)*100),
+		utils.FormatPercent(max(system.SystemCpuLoad, 0)*100))
+	if system.SystemLoadAverage >= 0 && system.AvailableProcessors > 0 {
+		cpu += utils.MutedStyle.Render(fmt.Sprintf("  load %.2f on %d cores", system.SystemLoadAverage, system.AvailableProcessors))
+	}
+	lines = append(lines, topLine("CPU", cpu))
+
+	threading := snapshot.Threading
+	lines = append(lines, topLine("Threads", fmt.Sprintf("%s  %s", utils.FormatCount(threading.Count),
+		utils.MutedStyle.Render(fmt.Sprintf("peak %s  daemon %s", utils.FormatCount(threading.PeakCount),
+			utils.FormatCount(threading.DaemonCount))))))
+	if deadlocked := len(threading.DeadlockedThreads); deadlocked > 0 {
+		lines = append(lines, topLine("", utils.CriticalStyle.Render(fmt.Sprintf("🔴 %d deadlocked: %s", deadlocked,
+			strings.Join(threading.DeadlockedThreads, ", ")))))
+	}
+
+	classes := snapshot.ClassLoading
+	lines = append(lines, topLine("Classes", fmt.Sprintf("%s loaded  %s", utils.FormatCount(classes.LoadedClassCount),
+		utils.MutedStyle.Render(utils.FormatCount(classes.UnloadedClassCount)+" unloaded"))))
+
+	return strings.Join(lines, "\n")
+}
+
+// renderTopGC gives the collections per second, their average pause and the time spent in GC since previous, or since start without one
+func renderTopGC(snapshot, previous *jmx.MBeanSnapshot) string {
+	elapsed := snapshot.Runtime.Uptime
+	gc := snapshot.GC
+	youngCount, youngTime, oldCount, oldTime := gc.YoungGCCount, gc.YoungGCTime, gc.OldGCCount, gc.OldGCTime
+	window := "since start"
+	if previous != nil {
+		elapsed = snapshot.Timestamp.Sub(previous.Timestamp)
+		youngCount -= previous.GC.YoungGCCount
+		youngTime -= previous.GC.YoungGCTime
+		oldCount -= previous.GC.OldGCCount
+		oldTime -= previous.GC.OldGCTime
+		window = "last " + utils.FormatDuration(elapsed)
+	}
+	if elapsed <= 0 {
+		return utils.MutedStyle.Render("no samples yet")
+	}
+
+	generation := func(name string, count, timeMs int64) string {
+		rate := fmt.Sprintf("%s %s/s", name, utils.Precision(2).Float(float64(count)/elapsed.Seconds()))
+		if count <= 0 {
+			return rate + "  avg -"
+		}
+		return rate + "  avg " + utils.FormatMillis(float64(timeMs)/float64(count))
+	}
+
+	share := float64(youngTime+oldTime) / float64(elapsed.Milliseconds()) * 100
+	shareStyle := utils.TextStyle
+	switch {
+	case share > 10:
+		shareStyle = utils.CriticalStyle
+	case share > 5:
+		shareStyle = utils.WarningStyle
+	}
+	return fmt.Sprintf("%s  %s  %s %s", generation("young", youngCount, youngTime), generation("old", oldCount, oldTime),
+		shareStyle.Render(utils.FormatPercent(share)+" in GC"), utils.MutedStyle.Render("("+window+")"))
+}
+
+// topMemory is used / max (percent), or just used when the pool has no max
+func topMemory(usage jmx.MemoryUsage) string {
+	if usage.Max <= 0 {
+		return utils.FormatBytes(usage.Used)
+	}
+	fraction := float64(usage.Used) / float64(usage.Max)
+	return fmt.Sprintf("%s / %s (%s)", utils.FormatBytes(usage.Used), utils.FormatBytes(usage.Max),
+		utils.FormatPercent(fraction*100))
+}
+
+func usageColor(fraction float64) lipgloss.Color {
+	switch {
+	case fraction > 0.9:
+		return utils.CriticalColor
+	case fraction > 0.7:
+		return utils.WarningColor
+	default:
+		return utils.GoodColor
+	}
+}
+
+func topLine(label, value string) string {
+	return utils.InfoStyle.Render(fmt.Sprintf("%-*s", topLabelWidth, label)) + value
+}
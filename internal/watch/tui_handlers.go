@@ -54,6 +54,12 @@ func (m *Model) handleMonitoringModeUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Process JMX data in monitor mode
 		return m.handleMetricsTick()
 
+	case NotifyResultMsg:
+		if msg.Err != nil {
+			m.setError(fmt.Sprintf("Notification failed: %v", msg.Err))
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		return m.handleMonitoringModeKeys(msg)
 	}
@@ -76,6 +82,7 @@ func (m *Model) handleWindowResize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 
 func (m *Model) handleMetricsTick() (tea.Model, tea.Cmd) {
 	currentGCFilter := m.tabState.GC.gcChartFilter
+	currentPercentileWindow := m.tabState.GC.percentileWindow
 
 	metrics := m.collector.GetMetrics()
 	m.tabState = m.metricsProcessor.ProcessMetrics(metrics)
@@ -99,12 +106,37 @@ func (m *Model) handleMetricsTick() (tea.Model, tea.Cmd) {
 		m.tabState.System.ConnectionUptime = time.Since(m.startTime)
 		m.tabState.System.UpdateCount = m.updateCount
 		m.tabState.GC.gcChartFilter = currentGCFilter
+		m.tabState.GC.percentileWindow = currentPercentileWindow
+		m.advisor.Update(m.metricsProcessor, m.tabState, m.thresholds, time.Now())
+		m.recordFrame(metrics.Timestamp)
+		m.session.Record(metrics, m.metricsProcessor.gcTracker, m.advisor.Active())
+	}
+
+	if fired := m.alerts.Check(m.metricsProcessor, metrics); len(fired) > 0 {
+		m.session.RecordAlerts(fired)
+		if m.notifier != nil {
+			return m, tea.Batch(m.scheduleTick(), m.sendAlerts(fired))
+		}
 	}
 
 	// Always schedule the next tick
 	return m, m.scheduleTick()
 }
 
+// sendAlerts posts in the background so a slow webhook doesn't stall the UI
+func (m *Model) sendAlerts(alerts []PerformanceAlert) tea.Cmd {
+	target := m.config.String()
+	if m.selectedProcess != nil {
+		target = fmt.Sprintf("%s (PID %d)", m.selectedProcess.MainClass, m.selectedProcess.PID)
+	}
+	summary := NewAlertSummary(target, alerts, m.tabState, m.metricsProcessor)
+	notifier := m.notifier
+
+	return func() tea.Msg {
+		return NotifyResultMsg{Err: notifier.Send(summary)}
+	}
+}
+
 func (m *Model) handleProcessModeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Enter):
@@ -174,12 +206,40 @@ func (m *Model) handleMonitoringModeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, triggerImmediateTick()
 
+	case key.Matches(msg, keys.Pause):
+		m.togglePause()
+		return m, nil
+
+	case key.Matches(msg, keys.StepBack):
+		m.stepFrame(-1)
+		return m, nil
+
+	case key.Matches(msg, keys.StepForward):
+		m.stepFrame(1)
+		return m, nil
+
+	case key.Matches(msg, keys.ExportJSON):
+		name, err := m.exportJSON(time.Now())
+		m.setExportStatus([]string{name}, err)
+		return m, nil
+
+	case key.Matches(msg, keys.ExportCSV):
+		names, err := m.exportCSV(time.Now())
+		m.setExportStatus(names, err)
+		return m, nil
+
 	case key.Matches(msg, keys.GCFilter):
 		// Only cycle GC filter when on GC tab
 		if m.activeTab == TabGC {
 			m.tabState.GC.gcChartFilter = m.tabState.GC.gcChartFilter.Next()
 		}
 		return m, nil
+
+	case key.Matches(msg, keys.PercentileWindow):
+		if m.activeTab == TabGC {
+			m.tabState.GC.percentileWindow = nextPercentileWindow(m.tabState.GC.percentileWindow)
+		}
+		return m, nil
 	}
 
 	return m, nil
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mabhi256/jdiag/internal/jmx"
+	"github.com/mabhi256/jdiag/internal/notify"
 )
 
 type Model struct {
@@ -16,6 +17,28 @@ type Model struct {
 	metricsProcessor *MetricsProcessor
 	help             help.Model
 
+	// Alert notifications; notifier is nil without --notify
+	notifier *notify.Notifier
+	alerts   *AlertTracker
+
+	// Rates the GC tab charts warn above
+	thresholds RateThresholds
+
+	// Live advisories shown above the tabs
+	advisor *Advisor
+
+	// Snapshots kept for pausing; while paused the tabs show frames[frameIndex]
+	frames     []frame
+	paused     bool
+	frameIndex int
+
+	// What the session saw, for the summary printed on exit
+	session *SessionSummary
+
+	// Where the last export went, shown in the header for exportDuration
+	exportMessage string
+	exportTime    time.Time
+
 	// UI state
 	width  int
 	height int
@@ -43,7 +66,7 @@ type Model struct {
 	startTime   time.Time
 }
 
-func initialModel(config *jmx.Config) *Model {
+func initialModel(config *jmx.Config, notifier *notify.Notifier, thresholds RateThresholds) *Model {
 	// Create process list
 	items := []list.Item{}
 	processList := list.New(items, list.NewDefaultDelegate(), 0, 0)
@@ -56,11 +79,16 @@ func initialModel(config *jmx.Config) *Model {
 		collector:        jmx.NewJMXCollector(config),
 		metricsProcessor: NewMetricsProcessor(),
 		help:             help.New(),
+		notifier:         notifier,
+		alerts:           NewAlertTracker(),
+		thresholds:       thresholds,
+		advisor:          NewAdvisor(),
+		session:          NewSessionSummary(config.String()),
 		activeTab:        TabMemory,
 		scrollPositions:  make(map[TabType]int),
 		tabState:         NewTabState(),
 		processList:      processList,
-		processMode:      config.PID == 0 && config.Host == "", // Start in process mode if no target specified
+		processMode:      config.PID == 0 && config.Host == "" && config.ReplayFile == "", // Start in process mode if no target specified
 		connected:        false,
 		startTime:        time.Now(),
 	}
@@ -88,6 +116,11 @@ func (m *Model) clearError() {
 // Message types
 type TickMsg time.Time
 
+// NotifyResultMsg reports the outcome of posting alerts
+type NotifyResultMsg struct {
+	Err error
+}
+
 func (m *Model) scheduleTick() tea.Cmd {
 	interval := m.config.GetInterval() // Default metrics interval
 	if m.processMode {
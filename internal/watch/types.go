@@ -8,8 +8,10 @@ type TabType int
 
 const (
 	TabMemory TabType = iota
+	TabPools
 	TabGC
 	TabThreads
+	TabClasses
 	TabSystem
 )
 
@@ -17,10 +19,14 @@ func (t TabType) String() string {
 	switch t {
 	case TabMemory:
 		return "Memory"
+	case TabPools:
+		return "Pools"
 	case TabGC:
 		return "GC"
 	case TabThreads:
 		return "Threads"
+	case TabClasses:
+		return "Classes"
 	case TabSystem:
 		return "System"
 	default:
@@ -29,13 +35,27 @@ func (t TabType) String() string {
 }
 
 func GetAllTabs() []TabType {
-	return []TabType{TabMemory, TabGC, TabThreads, TabSystem}
+	return []TabType{TabMemory, TabPools, TabGC, TabThreads, TabClasses, TabSystem}
+}
+
+// Rates above which the GC tab marks the allocation and promotion charts, in MB/s.
+// The allocation one matches the high allocation rate of the GC log analysis.
+const (
+	DefaultAllocationRateWarning = 500.0
+	DefaultPromotionRateWarning  = 50.0
+)
+
+// RateThresholds are the allocation and promotion rates the GC tab warns above, in MB/s
+type RateThresholds struct {
+	Allocation float64
+	Promotion  float64
 }
 
 type TabState struct {
 	Memory  *MemoryState
 	GC      *GCState
 	Threads *ThreadState
+	Classes *ClassState
 	System  *SystemState
 }
 
@@ -45,11 +65,13 @@ func NewTabState() *TabState {
 			MemoryPressure: "low",
 		},
 		GC: &GCState{
-			GCPressureLevel: "low",
-			RecentGCEvents:  make([]GCEvent, 0),
-			gcChartFilter:   GCFilterAfter,
+			GCPressureLevel:  "low",
+			RecentGCEvents:   make([]GCEvent, 0),
+			gcChartFilter:    GCFilterAfter,
+			percentileWindow: DefaultPercentileWindow,
 		},
 		Threads: &ThreadState{},
+		Classes: &ClassState{},
 		System:  &SystemState{},
 	}
 }
@@ -63,16 +85,23 @@ type GCEvent struct {
 	Before     int64 // Memory before GC
 	After      int64 // Memory after GC
 	Collected  int64 // Amount collected
+
+	// Share of the space collected that the heap got back, from the usage the JVM
+	// reported for its last collection at the poll. Only that collection is sampled:
+	// when several ran between polls, the earlier ones have none.
+	SampledEfficiency float64
+	Sampled           bool
 }
 
 type PerformanceAlert struct {
-	Level       string // "info", "warning", "critical"
-	Title       string
-	Description string
-	Timestamp   time.Time
-	Value       float64
-	Threshold   float64
-	MetricName  string
+	Level       string    `json:"level"` // "info", "warning", "critical"
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Timestamp   time.Time `json:"timestamp"`
+	Value       float64   `json:"value"`
+	Threshold   float64   `json:"threshold"`
+	MetricName  string    `json:"metric"`
+	Resolved    bool      `json:"resolved,omitempty"` // The alert of MetricName cleared; Level is "info"
 }
 
 type MemoryState struct {
@@ -99,6 +128,21 @@ type MemoryState struct {
 	// Memory trends and alerts
 	MemoryPressure  string // "low", "moderate", "high", "critical"
 	LastMemoryAlert *PerformanceAlert
+	Exhaustion      *ExhaustionEstimate // nil until enough collections were seen
+	FloorTrend      *FloorTrend         // nil until a collection covered the old generation
+
+	Pools []PoolState
+}
+
+// PoolState is one memory pool, such as Eden or a code heap segment
+type PoolState struct {
+	Name      string
+	Heap      bool
+	Used      int64
+	Committed int64
+	Max       int64 // -1 when the pool is unbounded
+	Peak      int64
+	Floor     int64 // Used right after the last GC; 0 for pools GC doesn't report
 }
 
 // GCState contains ONLY basic structure - NO calculation methods
@@ -123,7 +167,8 @@ type GCState struct {
 	AvgGCPauseTime  time.Duration
 	LastGCEvent     *GCEvent
 
-	gcChartFilter GCChartFilter
+	gcChartFilter    GCChartFilter
+	percentileWindow time.Duration // One of PercentileWindows
 }
 
 type ThreadState struct {
@@ -132,10 +177,6 @@ type ThreadState struct {
 	DaemonThreadCount  int64
 	TotalStartedCount  int64
 
-	LoadedClassCount   int64
-	UnloadedClassCount int64
-	TotalLoadedClasses int64
-
 	// Thread performance metrics
 	ThreadCreationRate float64 // threads created per minute
 	ThreadContention   bool    // whether thread contention is detected
@@ -143,9 +184,47 @@ type ThreadState struct {
 	BlockedThreadCount int64
 	WaitingThreadCount int64
 
-	// Class loading metrics
+	// Per-thread breakdown; empty when the JVM doesn't report thread details
+	StateCounts    map[string]int64 // Threads per Thread.State
+	TopCPU         []ThreadUsage
+	TopAllocating  []ThreadUsage
+	BlockedThreads []BlockedThread
+}
+
+type ClassState struct {
+	LoadedClassCount   int64 // Currently loaded
+	TotalLoadedClasses int64 // Loaded since start
+	UnloadedClassCount int64
+
+	// Over the history window, so startup loading doesn't read as a rate
 	ClassLoadingRate   float64 // classes loaded per minute
 	ClassUnloadingRate float64 // classes unloaded per minute
+	RateWindow         time.Duration
+
+	// JIT compilation; Compiler is empty when the JVM has no JIT
+	Compiler             string
+	CompilationSupported bool
+	CompilationTime      time.Duration // Total since start
+	CompilationShare     float64       // Compile time per second of wall time, over the last minute
+
+	JVMUptime time.Duration
+}
+
+// ThreadUsage is what one thread used since the previous poll
+type ThreadUsage struct {
+	Name           string
+	State          string
+	CPUPercent     float64 // Of one core
+	AllocationRate float64 // Bytes per second
+}
+
+// BlockedThread is a thread waiting to enter a monitor
+type BlockedThread struct {
+	Name         string
+	LockName     string
+	LockOwner    string
+	BlockedCount int64
+	BlockedTime  time.Duration // Total, 0 without contention monitoring
 }
 
 type SystemState struct {
@@ -163,6 +242,11 @@ type SystemState struct {
 	UsedSwap    int64
 	SwapPercent float64
 
+	// File descriptors, only reported on Unix
+	OpenFileDescriptors   int64
+	MaxFileDescriptors    int64
+	FileDescriptorPercent float64
+
 	// JVM uptime and info
 	JVMUptime    time.Duration
 	JVMStartTime time.Time
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var asciiMode bool
+
+// asciiGlyphs replaces glyphs that carry meaning. Anything else outside ASCII,
+// mostly decorative emoji, becomes blank space.
+var asciiGlyphs = map[rune]string{
+	// Box drawing and chart axes
+	'─': "-", '━': "-", '═': "-", '┄': "-", '╌': "-",
+	'│': "|", '┃': "|", '║': "|", '┆': "|", '┤': "|", '├': "|",
+	'┌': "+", '┐': "+", '└': "+", '┘': "+", '┬': "+", '┴': "+", '┼': "+",
+	'╭': "+", '╮': "+", '╰': "+", '╯': "+", '╔': "+", '╗': "+", '╚': "+", '╝': "+",
+
+	// Bars, gauges and heat shades
+	'█': "#", '▓': "%", '▒': "=", '░': "-", '▚': "%", '■': "#", '▪': "#", '□': "o",
+	'▁': "_", '▂': ".", '▃': ",", '▄': "-", '▅': "=", '▆': "+", '▇': "*",
+
+	// Markers and arrows
+	'●': "*", '○': "o", '◆': "*", '◇': "o", '·': ".", '•': "|", '…': ".",
+	'▶': ">", '◀': "<", '▲': "^", '▼': "v", '►': ">", '◄': "<",
+	'→': ">", '←': "<", '↑': "^", '↓': "v", '↔': "-", '⇄': "-",
+	'≤': "<", '≥': ">", '≈': "~", '×': "x", '–': "-", '—': "-", '±': "+",
+
+	// Status icons
+	'✅': "ok", '✓': "v", '✔': "v", '❌': "xx", '✗': "x", '⚠': "!", 'ℹ': "i",
+	'🔴': "!!", '🟠': "! ", '🟡': "! ", '🟢': "ok", '⚫': "--",
+	'📈': "/ ", '📉': "\\ ", '➡': ">",
+}
+
+// SetASCII switches charts, gauges and terminal UIs to ASCII-only output for
+// consoles that garble Unicode
+func SetASCII(on bool) {
+	asciiMode = on
+	termCaps.SupportsUnicode = !on
+}
+
+// ASCIIMode reports whether output is restricted to ASCII
+func ASCIIMode() bool {
+	return asciiMode
+}
+
+// ToASCII rewrites rendered output in ASCII mode, keeping each glyph's cell
+// width so aligned columns and borders stay aligned. ANSI styling is kept.
+func ToASCII(s string) string {
+	if !asciiMode {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		if r < 0x80 {
+			sb.WriteRune(r)
+			continue
+		}
+
+		width := lipgloss.Width(string(r))
+		replacement, ok := asciiGlyphs[r]
+		switch {
+		case ok:
+		case r == 0x2800: // Blank braille cell
+			replacement = " "
+		case r > 0x2800 && r <= 0x28FF: // Braille line charts
+			replacement = "*"
+		}
+
+		if len(replacement) > width {
+			replacement = replacement[:width]
+		}
+		sb.WriteString(replacement)
+		sb.WriteString(strings.Repeat(" ", width-len(replacement)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PercentileBand summarizes the values in one time bucket of a band chart
+type PercentileBand struct {
+	Start time.Time
+	Count int
+	P50   float64
+	P95   float64
+	P99   float64
+}
+
+// CreateBandChart layers P50, P95 and P99 as stacked shaded areas, one band per
+// bucket spread evenly across the width. Empty buckets are left blank.
+func CreateBandChart(bands []PercentileBand, unit string, config ChartConfig) string {
+	if len(bands) == 0 {
+		return "No data"
+	}
+
+	maxVal := 0.0
+	for _, band := range bands {
+		maxVal = max(maxVal, band.P99)
+	}
+	if maxVal == 0 {
+		maxVal = 1
+	}
+
+	width := config.Width - YAxisLabelWidth
+	bandAt := func(x int) int { return min(x*len(bands)/width, len(bands)-1) }
+
+	var lines []string
+	for row := 0; row < config.Height; row++ {
+		top := maxVal * float64(config.Height-row) / float64(config.Height)
+		// A cell is filled when the value reaches into it, so small values still show on the bottom row
+		bottom := top - maxVal/float64(config.Height)
+
+		var sb strings.Builder
+		for x := range width {
+			band := bands[bandAt(x)]
+			switch {
+			case band.Count == 0:
+				sb.WriteString(" ")
+			case band.P50 > bottom:
+				sb.WriteString(config.Styles.Good.Render("█"))
+			case band.P95 > bottom:
+				sb.WriteString(config.Styles.Warning.Render("▓"))
+			case band.P99 > bottom:
+				sb.WriteString(config.Styles.Critical.Render("░"))
+			default:
+				sb.WriteString(" ")
+			}
+		}
+
+		var label string
+		if unit == "ms" && top >= 1000 {
+			label = fmt.Sprintf(" %7s", Precision(2).Float(top/1000)+"s")
+		} else {
+			label = fmt.Sprintf(" %6s%s", Precision(2).Float(top), unit)
+		}
+		lines = append(lines, config.Styles.Muted.Render(label+" ┤")+sb.String())
+	}
+
+	if config.Markers != nil {
+		// Markers index bands; point them at the middle of each band
+		column := func(i int) int { return min((2*i+1)*width/(2*len(bands)), width-1) }
+		lines = append(lines, createMarkerLine(*config.Markers, len(bands), width, column, config.Styles))
+	}
+
+	timestamps := make([]time.Time, width)
+	for x := range timestamps {
+		timestamps[x] = bands[bandAt(x)].Start
+	}
+	lines = append(lines, createTimeAxis(timestamps, width, config.Styles.Muted)...)
+
+	legend := config.Styles.Good.Render("█") + " ≤P50  " +
+		config.Styles.Warning.Render("▓") + " P50–P95  " +
+		config.Styles.Critical.Render("░") + " P95–P99"
+	lines = append(lines, "", config.Styles.Muted.Render("Legend: ")+legend)
+
+	return strings.Join(lines, "\n")
+}
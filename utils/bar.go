@@ -66,7 +66,7 @@ func CreateHorizontalBar(data BarData, config HorizontalBarConfig) string {
 		valueParts = append(valueParts, fmt.Sprintf(config.ValueFormat, data.Value))
 	}
 	if config.ShowPercent {
-		valueParts = append(valueParts, fmt.Sprintf("(%4.1f%%)", data.Percentage))
+		valueParts = append(valueParts, fmt.Sprintf("(%5s)", FormatPercent(data.Percentage)))
 	}
 	if data.Suffix != "" {
 		valueParts = append(valueParts, data.Suffix)
@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"github.com/atotto/clipboard"
+	"github.com/muesli/termenv"
+)
+
+// CopyToClipboard puts text on the system clipboard. Without a clipboard tool,
+// e.g. over SSH, it asks the terminal to copy it with an OSC 52 sequence instead.
+func CopyToClipboard(text string) {
+	if err := clipboard.WriteAll(text); err == nil {
+		return
+	}
+	termenv.Copy(text)
+}
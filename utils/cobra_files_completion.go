@@ -60,9 +60,12 @@ func isValidFileExtension(filename string, extensions []string, includeRotated b
 			return true
 		}
 
-		// Rotated files: .ext.0, .ext.1, .ext.2, etc.
+		// Rotated files: .ext.0, .ext.1, .ext.2, etc., or .log.1.gz once compressed
 		if includeRotated {
 			pattern := regexp.QuoteMeta(ext) + `\.\d+$`
+			if base, found := strings.CutSuffix(ext, ".gz"); found {
+				pattern = regexp.QuoteMeta(base) + `\.\d+\.gz$`
+			}
 			if matched, _ := regexp.MatchString(pattern, filename); matched {
 				return true
 			}
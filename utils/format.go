@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale holds the separators used when printing numbers for humans
+type Locale struct {
+	Name    string
+	Decimal string
+	Group   string // Thousands separator, empty for none
+}
+
+// Languages not listed here fall back to English separators
+var locales = map[string]Locale{
+	"c":  {Name: "C", Decimal: "."},
+	"en": {Name: "en", Decimal: ".", Group: ","},
+	"ja": {Name: "ja", Decimal: ".", Group: ","},
+	"zh": {Name: "zh", Decimal: ".", Group: ","},
+	"de": {Name: "de", Decimal: ",", Group: "."},
+	"es": {Name: "es", Decimal: ",", Group: "."},
+	"it": {Name: "it", Decimal: ",", Group: "."},
+	"nl": {Name: "nl", Decimal: ",", Group: "."},
+	"pt": {Name: "pt", Decimal: ",", Group: "."},
+	"fr": {Name: "fr", Decimal: ",", Group: " "},
+	"pl": {Name: "pl", Decimal: ",", Group: " "},
+	"ru": {Name: "ru", Decimal: ",", Group: " "},
+	"sv": {Name: "sv", Decimal: ",", Group: " "},
+}
+
+// NumberFormat renders numbers, sizes and durations for terminals and reports.
+// Machine-readable output (JSON, Parquet, CSV exports) must not go through it.
+type NumberFormat struct {
+	Locale    Locale
+	Precision int // Decimals used unless a caller asks for a specific precision
+}
+
+var numberFormat = NumberFormat{Locale: locales["en"], Precision: 1}
+
+// LookupLocale accepts a language ("de"), a POSIX locale ("de_DE.UTF-8") or a BCP 47 tag ("de-DE")
+func LookupLocale(name string) (Locale, error) {
+	tag := strings.ToLower(name)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "posix" {
+		tag = "c"
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(tag, "-", "_"), "_")
+
+	locale, ok := locales[language]
+	if !ok {
+		names := make([]string, 0, len(locales))
+		for key := range locales {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		return Locale{}, fmt.Errorf("unsupported locale %q (supported: %s)", name, strings.Join(names, ", "))
+	}
+	return locale, nil
+}
+
+// LocaleFromEnv picks the numeric locale the same way libc does, defaulting to English
+func LocaleFromEnv() Locale {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			if locale, err := LookupLocale(value); err == nil {
+				return locale
+			}
+			break
+		}
+	}
+	return locales["en"]
+}
+
+// SetNumberFormat changes the format used by the package level Format* helpers
+func SetNumberFormat(format NumberFormat) {
+	numberFormat = format
+}
+
+// CurrentNumberFormat is the format the Format* helpers use
+func CurrentNumberFormat() NumberFormat {
+	return numberFormat
+}
+
+// Precision is the current format with a fixed number of decimals, for values that need more (or less) than the default
+func Precision(decimals int) NumberFormat {
+	return numberFormat.WithPrecision(decimals)
+}
+
+func (f NumberFormat) WithPrecision(decimals int) NumberFormat {
+	f.Precision = max(decimals, 0)
+	return f
+}
+
+// Float formats a value with grouping and the locale's decimal separator
+func (f NumberFormat) Float(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	text := strconv.FormatFloat(value, 'f', f.Precision, 64)
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+
+	result := sign + f.group(whole)
+	if hasFraction {
+		result += f.Locale.Decimal + fraction
+	}
+	return result
+}
+
+func (f NumberFormat) Count(n int64) string {
+	if n < 0 {
+		return "-" + f.group(strconv.FormatInt(-n, 10))
+	}
+	return f.group(strconv.FormatInt(n, 10))
+}
+
+// Percent formats a value that is already a percentage (12.5 -> "12.5%")
+func (f NumberFormat) Percent(percent float64) string {
+	return f.Float(percent) + "%"
+}
+
+func (f NumberFormat) MB(mb float64) string {
+	return f.Float(mb) + " MB"
+}
+
+func (f NumberFormat) Millis(ms float64) string {
+	return f.Float(ms) + "ms"
+}
+
+// Bytes uses binary units with a single letter suffix, e.g. 1.50G; whole values drop the decimals
+func (f NumberFormat) Bytes(bytes int64) string {
+	if bytes <= 0 {
+		return "0B"
+	}
+
+	units := []struct {
+		size   MemorySize
+		suffix string
+	}{{PB, "P"}, {TB, "T"}, {GB, "G"}, {MB, "M"}, {KB, "K"}}
+
+	for _, unit := range units {
+		if MemorySize(bytes) >= unit.size {
+			value := float64(bytes) / float64(unit.size)
+			if value == math.Trunc(value) {
+				return f.WithPrecision(0).Float(value) + unit.suffix
+			}
+			return f.WithPrecision(2).Float(value) + unit.suffix
+		}
+	}
+	return f.Count(bytes) + "B"
+}
+
+// Duration picks the unit by magnitude, from microseconds up to hours
+func (f NumberFormat) Duration(d time.Duration) string {
+	if d < time.Millisecond {
+		return f.Float(float64(d.Nanoseconds())/1000) + "μs"
+	}
+	if d < time.Second {
+		return f.Float(float64(d.Nanoseconds())/1000000) + "ms"
+	}
+	if d < time.Minute {
+		return f.Float(d.Seconds()) + "s"
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm %.0fs", int(d.Minutes()), math.Floor(math.Mod(d.Seconds(), 60)))
+	}
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) - 60*hours
+	return fmt.Sprintf("%sh %dm", f.Count(int64(hours)), minutes)
+}
+
+func (f NumberFormat) group(digits string) string {
+	if f.Locale.Group == "" || len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(f.Locale.Group)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func FormatFloat(value float64) string     { return numberFormat.Float(value) }
+func FormatCount(n int64) string           { return numberFormat.Count(n) }
+func FormatPercent(percent float64) string { return numberFormat.Percent(percent) }
+func FormatMB(mb float64) string           { return numberFormat.MB(mb) }
+func FormatMillis(ms float64) string       { return numberFormat.Millis(ms) }
+func FormatBytes(bytes int64) string       { return numberFormat.Bytes(bytes) }
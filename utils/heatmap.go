@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HeatmapShades go from the fewest to the most points in a cell
+var HeatmapShades = []string{"░", "▒", "▓", "█"}
+
+// HeatmapConfig describes the axes of a heatmap; RowLabels run top to bottom
+type HeatmapConfig struct {
+	RowLabels   []string
+	ColumnTimes []time.Time  // Start of each column, for the time axis
+	Markers     *PlotMarkers // Optional, indexed by column
+	Styles      ChartStyles
+}
+
+// CreateHeatmap shades counts[row][column] relative to the busiest cell
+func CreateHeatmap(counts [][]int, config HeatmapConfig) string {
+	if len(counts) == 0 || len(counts[0]) == 0 {
+		return "No data"
+	}
+
+	peak := 0
+	for _, row := range counts {
+		for _, count := range row {
+			peak = max(peak, count)
+		}
+	}
+
+	styles := []Renderer{config.Styles.Good, config.Styles.Info, config.Styles.Warning, config.Styles.Critical}
+	width := len(counts[0])
+
+	var lines []string
+	for r, row := range counts {
+		label := ""
+		if r < len(config.RowLabels) {
+			label = config.RowLabels[r]
+		}
+
+		var sb strings.Builder
+		for _, count := range row {
+			if count == 0 {
+				sb.WriteString(" ")
+				continue
+			}
+			level := heatmapLevel(count, peak)
+			sb.WriteString(styles[level].Render(HeatmapShades[level]))
+		}
+		lines = append(lines, config.Styles.Muted.Render(fmt.Sprintf("%9s ┤", label))+sb.String())
+	}
+
+	if config.Markers != nil {
+		column := func(i int) int { return i }
+		lines = append(lines, createMarkerLine(*config.Markers, width, width, column, config.Styles))
+	}
+
+	if len(config.ColumnTimes) == width {
+		lines = append(lines, createTimeAxis(config.ColumnTimes, width, config.Styles.Muted)...)
+	}
+
+	var legend []string
+	for level, shade := range HeatmapShades {
+		low, high := heatmapLevelRange(level, peak)
+		if low > high {
+			continue
+		}
+		text := fmt.Sprintf("%d", low)
+		if high > low {
+			text = fmt.Sprintf("%d-%d", low, high)
+		}
+		legend = append(legend, styles[level].Render(shade)+" "+text)
+	}
+	lines = append(lines, "", config.Styles.Muted.Render("Events per cell: ")+strings.Join(legend, "  "))
+
+	return strings.Join(lines, "\n")
+}
+
+// heatmapLevel splits 1..peak into len(HeatmapShades) equal bands
+func heatmapLevel(count, peak int) int {
+	levels := len(HeatmapShades)
+	if peak <= 1 {
+		return 0
+	}
+	return min((count-1)*levels/peak, levels-1)
+}
+
+// heatmapLevelRange is the inverse of heatmapLevel, low > high when no count maps to the level
+func heatmapLevelRange(level, peak int) (low, high int) {
+	low, high = 0, -1
+	for count := 1; count <= peak; count++ {
+		if heatmapLevel(count, peak) != level {
+			continue
+		}
+		if high < 0 {
+			low = count
+		}
+		high = count
+	}
+	return low, high
+}
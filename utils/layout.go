@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Terminal widths where the TUIs change layout
+const (
+	NarrowWidth = 100 // Below this, side-by-side columns stack
+	WideWidth   = 200 // From this on, views add extra panels
+)
+
+type LayoutMode int
+
+const (
+	LayoutNarrow LayoutMode = iota
+	LayoutRegular
+	LayoutWide
+)
+
+// LayoutFor picks the layout for a terminal width
+func LayoutFor(width int) LayoutMode {
+	switch {
+	case width < NarrowWidth:
+		return LayoutNarrow
+	case width >= WideWidth:
+		return LayoutWide
+	}
+	return LayoutRegular
+}
+
+// Columns joins blocks side by side with gap columns between them, or stacks
+// them with a blank line between when the terminal is narrow or they don't fit
+func Columns(width, gap int, blocks ...string) string {
+	total := gap * max(len(blocks)-1, 0)
+	for _, block := range blocks {
+		total += lipgloss.Width(block)
+	}
+
+	if LayoutFor(width) == LayoutNarrow || total > width {
+		return strings.Join(blocks, "\n\n")
+	}
+
+	spaced := make([]string, 0, 2*len(blocks))
+	for i, block := range blocks {
+		if i > 0 {
+			spaced = append(spaced, strings.Repeat(" ", gap))
+		}
+		spaced = append(spaced, block)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, spaced...)
+}
@@ -18,33 +18,9 @@ const (
 	PB   MemorySize = 1024 * TB
 )
 
-// String returns a human-readable representation of the memory size
+// String returns a human-readable representation of the memory size in the current number format
 func (m MemorySize) String() string {
-	if m <= 0 {
-		return "0B"
-	}
-
-	formatValue := func(val float64, unit string) string {
-		if val == float64(int64(val)) {
-			return fmt.Sprintf("%.0f%s", val, unit)
-		}
-		return fmt.Sprintf("%.2f%s", val, unit)
-	}
-
-	switch {
-	case m >= PB:
-		return formatValue(float64(m)/float64(TB), "P")
-	case m >= TB:
-		return formatValue(float64(m)/float64(TB), "T")
-	case m >= GB:
-		return formatValue(float64(m)/float64(GB), "G")
-	case m >= MB:
-		return formatValue(float64(m)/float64(MB), "M")
-	case m >= KB:
-		return formatValue(float64(m)/float64(KB), "K")
-	default:
-		return fmt.Sprintf("%dB", m)
-	}
+	return numberFormat.Bytes(int64(m))
 }
 
 // Bytes returns the memory size as bytes
@@ -153,7 +129,9 @@ func (m MemorySize) Ratio(other MemorySize) float64 {
 
 // MarshalJSON implements json.Marshaler
 func (m MemorySize) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`"%s"`, m.String())), nil
+	// Locale independent so the value parses back
+	canonical := NumberFormat{Locale: locales["c"]}.Bytes(int64(m))
+	return []byte(fmt.Sprintf(`"%s"`, canonical)), nil
 }
 
 // UnmarshalJSON implements json.Unmarshaler
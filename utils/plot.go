@@ -37,10 +37,39 @@ type DataPoint struct {
 
 // ChartConfig holds configuration for chart rendering
 type ChartConfig struct {
-	Width  int
-	Height int
-	Styles ChartStyles
-	Legend string // Optional pre-formatted legend
+	Width   int
+	Height  int
+	Styles  ChartStyles
+	Legend  string       // Optional pre-formatted legend
+	Markers *PlotMarkers // Optional cursor and selection row under the chart
+
+	// Optional fixed value range, so paired charts share a scale; unused when MaxY <= MinY
+	MinY, MaxY float64
+
+	// Optional time range across the x axis. Points then sit at their timestamps rather
+	// than evenly spaced, and the line breaks over gaps between points longer than Gap.
+	From, To time.Time
+	Gap      time.Duration
+}
+
+// PlotMarkers are indices into the data points; -1 leaves a marker out
+type PlotMarkers struct {
+	Cursor     int
+	SelectFrom int
+	SelectTo   int
+	Bookmarks  []int
+}
+
+// Remap moves every marker through f, for charts that group data points into bars or bands
+func (p *PlotMarkers) Remap(f func(int) int) {
+	for _, marker := range []*int{&p.Cursor, &p.SelectFrom, &p.SelectTo} {
+		if *marker >= 0 {
+			*marker = f(*marker)
+		}
+	}
+	for i, bookmark := range p.Bookmarks {
+		p.Bookmarks[i] = f(bookmark)
+	}
 }
 
 // SimpleRenderer provides a basic renderer that just returns the text as-is
@@ -63,6 +92,9 @@ func CreatePlot(dataPoints []DataPoint, unit string, config ChartConfig) string
 	}
 
 	maxVal, minVal := slices.Max(values), slices.Min(values)
+	if config.MaxY > config.MinY {
+		minVal, maxVal = config.MinY, config.MaxY
+	}
 	if maxVal == minVal {
 		maxVal = minVal + 1 // Avoid division by zero
 	}
@@ -79,13 +111,16 @@ func CreatePlot(dataPoints []DataPoint, unit string, config ChartConfig) string
 		}
 	}
 
+	column := func(i int) int { return plotColumn(i, len(dataPoints), width) }
+	timed := config.To.After(config.From)
+	if timed {
+		column = func(i int) int { return timeColumn(dataPoints[i].Timestamp, config.From, config.To, width) }
+	}
+
 	// Calculate data point positions
 	chartPoints := make([]struct{ x, y int }, len(dataPoints))
 	for i, dp := range dataPoints {
-		x := i * (width - 1) / max(1, len(dataPoints)-1)
-		if len(dataPoints) == 1 {
-			x = width / 2
-		}
+		x := column(i)
 		// Convert value to y position (inverted since we draw from top to bottom)
 		y := int((maxVal-dp.Value)/(maxVal-minVal)*float64(config.Height-1) + 0.5)
 		if y >= config.Height {
@@ -102,6 +137,9 @@ func CreatePlot(dataPoints []DataPoint, unit string, config ChartConfig) string
 	// Draw lines between consecutive points
 	if len(chartPoints) > 1 {
 		for i := 0; i < len(chartPoints)-1; i++ {
+			if config.Gap > 0 && dataPoints[i+1].Timestamp.Sub(dataPoints[i].Timestamp) > config.Gap {
+				continue // Leave idle periods blank
+			}
 			drawLine(chartGrid, chartPoints[i].x, chartPoints[i].y, chartPoints[i+1].x, chartPoints[i+1].y, width, config.Height, config.Styles.Muted)
 		}
 	}
@@ -120,20 +158,31 @@ func CreatePlot(dataPoints []DataPoint, unit string, config ChartConfig) string
 		// Y-axis label
 		var label string
 		if unit == "ms" && threshold >= 1000 {
-			label = fmt.Sprintf(" %6.2fs", threshold/1000)
+			label = fmt.Sprintf(" %7s", Precision(2).Float(threshold/1000)+"s")
 		} else {
-			label = fmt.Sprintf(" %6.2f%s", threshold, unit)
+			label = fmt.Sprintf(" %6s%s", Precision(2).Float(threshold), unit)
 		}
 		lineStr := config.Styles.Muted.Render(label+" ┤") + strings.Join(chartGrid[row], "")
 		lines = append(lines, lineStr)
 	}
 
+	if config.Markers != nil {
+		lines = append(lines, createMarkerLine(*config.Markers, len(dataPoints), width, column, config.Styles))
+	}
+
 	// Add time axis
 	if len(dataPoints) > 0 {
 		timestamps := make([]time.Time, len(dataPoints))
 		for i, dp := range dataPoints {
 			timestamps[i] = dp.Timestamp
 		}
+		if timed {
+			// One timestamp per column, so the labels read the range rather than the points
+			timestamps = make([]time.Time, width)
+			for x := range timestamps {
+				timestamps[x] = config.From.Add(config.To.Sub(config.From) * time.Duration(x) / time.Duration(max(width-1, 1)))
+			}
+		}
 		lines = append(lines, createTimeAxis(timestamps, width, config.Styles.Muted)...)
 	}
 
@@ -207,6 +256,51 @@ func drawLine(grid [][]string, x1, y1, x2, y2, width, height int, mutedRenderer
 	}
 }
 
+// plotColumn is the x position of the i-th of count points spread across width columns
+func plotColumn(i, count, width int) int {
+	if count == 1 {
+		return width / 2
+	}
+	return i * (width - 1) / max(1, count-1)
+}
+
+// timeColumn is the x position of a timestamp between from and to across width columns
+func timeColumn(ts, from, to time.Time, width int) int {
+	offset := float64(ts.Sub(from)) / float64(to.Sub(from))
+	return min(max(int(offset*float64(width-1)+0.5), 0), width-1)
+}
+
+// createMarkerLine underlines the selected points and points at the cursor; column maps a point to its x position
+func createMarkerLine(markers PlotMarkers, count, width int, column func(int) int, styles ChartStyles) string {
+	row := make([]string, width)
+	for i := range row {
+		row[i] = " "
+	}
+
+	if markers.SelectFrom >= 0 && markers.SelectTo >= markers.SelectFrom && markers.SelectFrom < count {
+		from := column(markers.SelectFrom)
+		to := column(min(markers.SelectTo, count-1))
+		for x := from; x <= to && x < width; x++ {
+			row[x] = styles.Info.Render("━")
+		}
+	}
+	for _, bookmark := range markers.Bookmarks {
+		if bookmark >= 0 && bookmark < count {
+			if x := column(bookmark); x < width {
+				row[x] = styles.Critical.Render("◆")
+			}
+		}
+	}
+	if markers.Cursor >= 0 && markers.Cursor < count {
+		if x := column(markers.Cursor); x < width {
+			row[x] = styles.Warning.Render("▲")
+		}
+	}
+
+	// Y-axis labels are 9 columns wide plus " ┤"
+	return strings.Repeat(" ", 11) + strings.Join(row, "")
+}
+
 // createTimeAxis creates time axis labels for the chart
 func createTimeAxis(timestamps []time.Time, width int, mutedRenderer Renderer) []string {
 	axisLine := strings.Repeat(" ", 10) + "└" + strings.Repeat("─", width)
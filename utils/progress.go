@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+// Progress is how far a long parse has got
+type Progress struct {
+	BytesRead  int64
+	TotalBytes int64 // 0 when the size isn't known up front, e.g. compressed input
+	Items      int   // Events or records found so far
+}
+
+// ProgressFunc receives progress updates from a parser
+type ProgressFunc func(Progress)
+
+// Fraction is the share of the input read, or -1 when the total is unknown
+func (p Progress) Fraction() float64 {
+	if p.TotalBytes <= 0 {
+		return -1
+	}
+	return min(float64(p.BytesRead)/float64(p.TotalBytes), 1)
+}
+
+// ETA extrapolates the time left from the time spent so far; 0 when it can't tell yet
+func (p Progress) ETA(elapsed time.Duration) time.Duration {
+	fraction := p.Fraction()
+	if fraction <= 0 || fraction >= 1 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+}
+
+// progressRedraw limits how often the CLI bar and loading screen redraw
+const progressRedraw = 100 * time.Millisecond
+
+// describeProgress is the bytes, items and ETA part of a progress line
+func describeProgress(p Progress, unit string, elapsed time.Duration) string {
+	parts := []string{}
+	if p.TotalBytes > 0 {
+		parts = append(parts, FormatBytes(p.BytesRead)+" / "+FormatBytes(p.TotalBytes))
+	} else {
+		parts = append(parts, FormatBytes(p.BytesRead)+" read")
+	}
+	parts = append(parts, FormatCount(int64(p.Items))+" "+unit)
+	if eta := p.ETA(elapsed); eta > 0 {
+		parts = append(parts, "ETA "+FormatDuration(eta.Round(time.Second)))
+	} else {
+		parts = append(parts, FormatDuration(elapsed.Round(time.Second))+" elapsed")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// progressBar redraws a single line on a terminal; it draws nothing when
+// stderr is redirected, so logs and pipes never see the control characters
+type progressBar struct {
+	out     io.Writer
+	label   string
+	unit    string
+	start   time.Time
+	drawn   time.Time
+	enabled bool
+}
+
+func newProgressBar(label, unit string) *progressBar {
+	return &progressBar{
+		out:     os.Stderr,
+		label:   label,
+		unit:    unit,
+		start:   time.Now(),
+		enabled: term.IsTerminal(os.Stderr.Fd()),
+	}
+}
+
+func (b *progressBar) update(p Progress) {
+	if !b.enabled || time.Since(b.drawn) < progressRedraw {
+		return
+	}
+	b.drawn = time.Now()
+
+	line := "⏳ " + b.label
+	if fraction := p.Fraction(); fraction >= 0 {
+		line += fmt.Sprintf("  %s %s", CreateProgressBar(fraction, 24, InfoColor), Precision(0).Percent(fraction*100))
+	}
+	line += "  " + describeProgress(p, b.unit, time.Since(b.start))
+	fmt.Fprintf(b.out, "\r\033[K%s", line)
+}
+
+// done clears the bar so the report starts on a clean line
+func (b *progressBar) done() {
+	if b.enabled && !b.drawn.IsZero() {
+		fmt.Fprint(b.out, "\r\033[K")
+	}
+}
+
+/*
+ * RunWithProgressBar runs a parse with a progress bar on stderr. Ctrl-C
+ * cancels the context handed to work rather than killing the process, so the
+ * parser can stop early and the caller still reports what was read. Once
+ * work returns Ctrl-C kills the process again.
+ */
+func RunWithProgressBar(label, unit string, work func(ctx context.Context, progress ProgressFunc) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	bar := newProgressBar(label, unit)
+	err := work(ctx, bar.update)
+	bar.done()
+	return err
+}
+
+/*
+ * RunWithLoadingScreen runs a parse behind a full-screen loading screen, for
+ * commands that open a TUI once the parse is done. Ctrl-C on the loading
+ * screen cancels the context handed to work, so the TUI opens with whatever
+ * was read up to that point.
+ */
+func RunWithLoadingScreen(label, unit string, work func(ctx context.Context, progress ProgressFunc) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	model := &loadingModel{label: label, unit: unit, start: time.Now(), cancel: cancel}
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	done := make(chan struct{})
+	var workErr error
+	go func() {
+		defer close(done)
+		workErr = work(ctx, func(p Progress) { program.Send(loadingProgressMsg(p)) })
+		program.Send(loadingDoneMsg{})
+	}()
+
+	if _, err := program.Run(); err != nil {
+		cancel()
+		<-done
+		return fmt.Errorf("loading screen: %w", err)
+	}
+	<-done
+	return workErr
+}
+
+type (
+	loadingProgressMsg Progress
+	loadingDoneMsg     struct{}
+	loadingTickMsg     struct{}
+)
+
+type loadingModel struct {
+	label    string
+	unit     string
+	start    time.Time
+	progress Progress
+	stopping bool
+	cancel   context.CancelFunc
+	width    int
+	height   int
+}
+
+func loadingTick() tea.Cmd {
+	return tea.Tick(progressRedraw, func(time.Time) tea.Msg { return loadingTickMsg{} })
+}
+
+func (m *loadingModel) Init() tea.Cmd {
+	return loadingTick()
+}
+
+func (m *loadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.stopping = true
+			m.cancel()
+		}
+	case loadingProgressMsg:
+		m.progress = Progress(msg)
+	case loadingTickMsg:
+		// Keeps the elapsed time moving while the parser reports nothing
+		return m, loadingTick()
+	case loadingDoneMsg:
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *loadingModel) View() string {
+	barWidth := min(max(m.width-20, 10), 50)
+
+	lines := []string{TitleStyle.Render("⏳ " + m.label), ""}
+	if fraction := m.progress.Fraction(); fraction >= 0 {
+		lines = append(lines, CreateProgressBar(fraction, barWidth, InfoColor)+" "+Precision(0).Percent(fraction*100))
+	}
+	lines = append(lines, TextStyle.Render(describeProgress(m.progress, m.unit, time.Since(m.start))), "")
+	if m.stopping {
+		lines = append(lines, WarningStyle.Render("Stopping, keeping what was read so far..."))
+	} else {
+		lines = append(lines, MutedStyle.Render("ctrl+c: stop and open what was read so far"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
+	if m.width == 0 || m.height == 0 {
+		return content
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
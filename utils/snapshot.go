@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type SnapshotFormat int
+
+const (
+	SnapshotText SnapshotFormat = iota // Plain text, styling stripped
+	SnapshotANSI                       // Text with the terminal's color codes
+	SnapshotSVG                        // Colored image for tickets and docs
+)
+
+var snapshotExtensions = map[SnapshotFormat]string{
+	SnapshotText: ".txt",
+	SnapshotANSI: ".ans",
+	SnapshotSVG:  ".svg",
+}
+
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4
+	svgLineHeight = 18
+	svgPadding    = 10
+)
+
+// WriteSnapshot saves a rendered view to jdiag-<name>-<timestamp> in dir and returns the path
+func WriteSnapshot(dir, name, view string, format SnapshotFormat) (string, error) {
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	// Drop the padding a full-screen view adds below the content
+	for len(lines) > 0 && strings.TrimSpace(ansi.Strip(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	view = strings.Join(lines, "\n")
+
+	var content string
+	switch format {
+	case SnapshotText:
+		content = ansi.Strip(view) + "\n"
+	case SnapshotANSI:
+		content = view + "\n"
+	case SnapshotSVG:
+		content = RenderSVG(view)
+	default:
+		return "", fmt.Errorf("unknown snapshot format %d", format)
+	}
+
+	filename := fmt.Sprintf("jdiag-%s-%s%s", name, time.Now().Format("20060102-150405"), snapshotExtensions[format])
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// svgCell is the styling of one run of text in RenderSVG
+type svgCell struct {
+	fg, bg  string
+	bold    bool
+	reverse bool
+}
+
+// RenderSVG draws ANSI-styled terminal output as an SVG image, one text cell per column
+func RenderSVG(view string) string {
+	background, foreground := "#1e1e1e", "#cccccc"
+	if CurrentTheme().Name == LightTheme.Name {
+		background, foreground = "#ffffff", "#333333"
+	}
+
+	lines := strings.Split(view, "\n")
+	columns := 0
+	for _, line := range lines {
+		columns = max(columns, ansi.StringWidth(line))
+	}
+	width := float64(columns)*svgCellWidth + 2*svgPadding
+	height := len(lines)*svgLineHeight + 2*svgPadding
+
+	var rects, texts strings.Builder
+	for row, line := range lines {
+		y := svgPadding + row*svgLineHeight
+		column := 0
+		style := svgCell{}
+
+		for len(line) > 0 {
+			if strings.HasPrefix(line, "\x1b[") {
+				end := strings.IndexFunc(line[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+				if end < 0 {
+					break
+				}
+				if line[2+end] == 'm' {
+					style = applySGR(style, line[2:2+end])
+				}
+				line = line[3+end:]
+				continue
+			}
+
+			// Collect a run of text with the same style up to the next escape
+			next := strings.Index(line, "\x1b")
+			if next == 0 {
+				// Not a CSI sequence; drop the escape byte
+				line = line[1:]
+				continue
+			}
+			if next < 0 {
+				next = len(line)
+			}
+			run := line[:next]
+			line = line[next:]
+
+			fg, bg := style.fg, style.bg
+			if style.reverse {
+				fg, bg = bg, fg
+				if fg == "" {
+					fg = background
+				}
+				if bg == "" {
+					bg = foreground
+				}
+			}
+
+			if bg != "" {
+				fmt.Fprintf(&rects, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					svgPadding+float64(column)*svgCellWidth, y, float64(ansi.StringWidth(run))*svgCellWidth, svgLineHeight, bg)
+			}
+
+			// Each glyph is placed on its own cell so wide glyphs and font metrics can't shift the columns
+			for _, r := range run {
+				cells := lipgloss.Width(string(r))
+				x := svgPadding + float64(column)*svgCellWidth
+				if r != ' ' && cells > 0 {
+					attrs := ""
+					if fg != "" {
+						attrs += ` fill="` + fg + `"`
+					}
+					if style.bold {
+						attrs += ` font-weight="bold"`
+					}
+					fmt.Fprintf(&texts, `<text x="%.1f" y="%d"%s>%s</text>`+"\n",
+						x, y+svgFontSize, attrs, html.EscapeString(string(r)))
+				}
+				column += cells
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%d" viewBox="0 0 %.0f %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", background)
+	sb.WriteString(rects.String())
+	fmt.Fprintf(&sb, `<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" fill="%s" xml:space="preserve">`+"\n",
+		svgFontSize, foreground)
+	sb.WriteString(texts.String())
+	sb.WriteString("</g>\n</svg>\n")
+	return sb.String()
+}
+
+// applySGR updates the style with one Select Graphic Rendition sequence, e.g. "1;38;2;255;0;0"
+func applySGR(style svgCell, params string) svgCell {
+	if params == "" {
+		return svgCell{}
+	}
+
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, _ := strconv.Atoi(codes[i])
+		switch {
+		case code == 0:
+			style = svgCell{}
+		case code == 1:
+			style.bold = true
+		case code == 22:
+			style.bold = false
+		case code == 7:
+			style.reverse = true
+		case code == 27:
+			style.reverse = false
+		case code == 39:
+			style.fg = ""
+		case code == 49:
+			style.bg = ""
+		case code >= 30 && code <= 37:
+			style.fg = ansiPalette[code-30]
+		case code >= 90 && code <= 97:
+			style.fg = ansiPalette[code-90+8]
+		case code >= 40 && code <= 47:
+			style.bg = ansiPalette[code-40]
+		case code >= 100 && code <= 107:
+			style.bg = ansiPalette[code-100+8]
+		case code == 38 || code == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if code == 38 {
+				style.fg = color
+			} else {
+				style.bg = color
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor reads the "5;n" or "2;r;g;b" arguments of an extended color and how many it used
+func extendedColor(args []string) (string, int) {
+	if len(args) >= 2 && args[0] == "5" {
+		n, _ := strconv.Atoi(args[1])
+		return xterm256(n), 2
+	}
+	if len(args) >= 4 && args[0] == "2" {
+		r, _ := strconv.Atoi(args[1])
+		g, _ := strconv.Atoi(args[2])
+		b, _ := strconv.Atoi(args[3])
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b), 4
+	}
+	return "", len(args)
+}
+
+var ansiPalette = []string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+func xterm256(n int) string {
+	switch {
+	case n < 16:
+		return ansiPalette[max(n, 0)]
+	case n < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (min(n, 255)-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
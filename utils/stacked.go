@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StackSegment is one layer of a stacked bar chart, drawn bottom up in slice order
+type StackSegment struct {
+	Label string
+	Glyph string
+	Style Renderer
+}
+
+// StackedBar holds one value per segment
+type StackedBar struct {
+	Timestamp time.Time
+	Values    []float64
+}
+
+// CreateStackedBarChart draws one column per bar, spread across the width like CreatePlot
+func CreateStackedBarChart(bars []StackedBar, segments []StackSegment, unit string, config ChartConfig) string {
+	if len(bars) == 0 {
+		return "No data"
+	}
+
+	maxVal := 0.0
+	for _, bar := range bars {
+		total := 0.0
+		for _, value := range bar.Values {
+			total += value
+		}
+		maxVal = max(maxVal, total)
+	}
+	if maxVal == 0 {
+		maxVal = 1
+	}
+
+	width := config.Width - YAxisLabelWidth
+	columns := make([]int, width) // Bar drawn in each column, -1 for gaps
+	for x := range columns {
+		columns[x] = -1
+	}
+	for i := range bars {
+		x := plotColumn(i, len(bars), width)
+		columns[x] = i
+		// Wide charts with few bars get bars two columns wide
+		if len(bars)*3 <= width && x+1 < width {
+			columns[x+1] = i
+		}
+	}
+
+	var lines []string
+	for row := 0; row < config.Height; row++ {
+		top := maxVal * float64(config.Height-row) / float64(config.Height)
+		bottom := top - maxVal/float64(config.Height)
+
+		var sb strings.Builder
+		for _, bar := range columns {
+			if bar < 0 {
+				sb.WriteString(" ")
+				continue
+			}
+			sb.WriteString(stackCell(bars[bar].Values, segments, bottom))
+		}
+
+		var label string
+		if unit == "ms" && top >= 1000 {
+			label = fmt.Sprintf(" %7s", Precision(2).Float(top/1000)+"s")
+		} else {
+			label = fmt.Sprintf(" %6s%s", Precision(2).Float(top), unit)
+		}
+		lines = append(lines, config.Styles.Muted.Render(label+" ┤")+sb.String())
+	}
+
+	if config.Markers != nil {
+		column := func(i int) int { return plotColumn(i, len(bars), width) }
+		lines = append(lines, createMarkerLine(*config.Markers, len(bars), width, column, config.Styles))
+	}
+
+	timestamps := make([]time.Time, len(bars))
+	for i, bar := range bars {
+		timestamps[i] = bar.Timestamp
+	}
+	lines = append(lines, createTimeAxis(timestamps, width, config.Styles.Muted)...)
+
+	var legend []string
+	for _, segment := range segments {
+		legend = append(legend, segment.Style.Render(segment.Glyph)+" "+segment.Label)
+	}
+	lines = append(lines, "", config.Styles.Muted.Render("Legend: ")+strings.Join(legend, "  "))
+
+	return strings.Join(lines, "\n")
+}
+
+// stackCell picks the segment whose layer covers the cell starting at bottom
+func stackCell(values []float64, segments []StackSegment, bottom float64) string {
+	cumulative := 0.0
+	for i, value := range values {
+		cumulative += value
+		if value > 0 && cumulative > bottom && i < len(segments) {
+			return segments[i].Style.Render(segments[i].Glyph)
+		}
+	}
+	return " "
+}
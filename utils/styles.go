@@ -11,82 +11,114 @@ import (
 )
 
 var (
-	CriticalColor = lipgloss.Color("#CC3333") // Dark red
-	WarningColor  = lipgloss.Color("#FF8800") // Orange
-	GoodColor     = lipgloss.Color("#228B22") // Forest green
-	InfoColor     = lipgloss.Color("#4682B4") // Steel blue
-	TextColor     = lipgloss.Color("#CCCCCC") // Light gray
-	MutedColor    = lipgloss.Color("#888888") // Medium gray
-	BorderColor   = lipgloss.Color("#666666") // Dark gray
-
-	CriticalLightColor = lipgloss.Color("#FF6666") // Lighter red
-	WarningLightColor  = lipgloss.Color("#FFAA44") // Lighter orange
-	GoodLightColor     = lipgloss.Color("#66BB66") // Lighter green
-	InfoLightColor     = lipgloss.Color("#88AACC") // Lighter blue
+	CriticalColor lipgloss.Color
+	WarningColor  lipgloss.Color
+	GoodColor     lipgloss.Color
+	InfoColor     lipgloss.Color
+	TextColor     lipgloss.Color
+	MutedColor    lipgloss.Color
+	BorderColor   lipgloss.Color
+
+	CriticalLightColor lipgloss.Color
+	WarningLightColor  lipgloss.Color
+	GoodLightColor     lipgloss.Color
+	InfoLightColor     lipgloss.Color
+
+	TitleColor     lipgloss.Color // Titles and emphasized text
+	HighlightColor lipgloss.Color // Text on an InfoColor background
+	SurfaceColor   lipgloss.Color // Header and help bar background
 )
 
+// Styles are rebuilt from the colors whenever a theme is applied
 var (
+	CriticalStyle lipgloss.Style
+	WarningStyle  lipgloss.Style
+	GoodStyle     lipgloss.Style
+	InfoStyle     lipgloss.Style
+	MutedStyle    lipgloss.Style
+	TextStyle     lipgloss.Style
+
+	CriticalLightStyle lipgloss.Style
+	WarningLightStyle  lipgloss.Style
+	GoodLightStyle     lipgloss.Style
+	InfoLightStyle     lipgloss.Style
+
+	// SelectedStyle marks the selected row in lists and tables
+	SelectedStyle lipgloss.Style
+
+	TabActiveStyle   lipgloss.Style
+	TabInactiveStyle lipgloss.Style
+
+	BoxStyle       lipgloss.Style
+	TitleStyle     lipgloss.Style
+	HeaderStyle    lipgloss.Style
+	StatusBarStyle lipgloss.Style
+	ErrorStyle     lipgloss.Style
+	HelpBarStyle   lipgloss.Style
+)
+
+func buildStyles() {
 	CriticalStyle = lipgloss.NewStyle().Foreground(CriticalColor).Bold(true)
-	WarningStyle  = lipgloss.NewStyle().Foreground(WarningColor).Bold(true)
-	GoodStyle     = lipgloss.NewStyle().Foreground(GoodColor).Bold(true)
-	InfoStyle     = lipgloss.NewStyle().Foreground(InfoColor)
-	MutedStyle    = lipgloss.NewStyle().Foreground(MutedColor)
-	TextStyle     = lipgloss.NewStyle().Foreground(TextColor)
+	WarningStyle = lipgloss.NewStyle().Foreground(WarningColor).Bold(true)
+	GoodStyle = lipgloss.NewStyle().Foreground(GoodColor).Bold(true)
+	InfoStyle = lipgloss.NewStyle().Foreground(InfoColor)
+	MutedStyle = lipgloss.NewStyle().Foreground(MutedColor)
+	TextStyle = lipgloss.NewStyle().Foreground(TextColor)
 
 	CriticalLightStyle = lipgloss.NewStyle().Foreground(CriticalLightColor)
-	WarningLightStyle  = lipgloss.NewStyle().Foreground(WarningLightColor)
-	GoodLightStyle     = lipgloss.NewStyle().Foreground(GoodLightColor)
-	InfoLightStyle     = lipgloss.NewStyle().Foreground(InfoLightColor)
-)
+	WarningLightStyle = lipgloss.NewStyle().Foreground(WarningLightColor)
+	GoodLightStyle = lipgloss.NewStyle().Foreground(GoodLightColor)
+	InfoLightStyle = lipgloss.NewStyle().Foreground(InfoLightColor)
+
+	SelectedStyle = lipgloss.NewStyle().
+		Foreground(HighlightColor).
+		Background(InfoColor)
 
-var (
 	TabActiveStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(InfoColor).
-			Padding(0, 1).
-			Bold(true)
+		Foreground(HighlightColor).
+		Background(InfoColor).
+		Padding(0, 1).
+		Bold(true)
 
 	TabInactiveStyle = lipgloss.NewStyle().
-				Foreground(MutedColor).
-				Padding(0, 1)
-)
+		Foreground(MutedColor).
+		Padding(0, 1)
 
-var (
 	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BorderColor).
+		Padding(1, 2)
 
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(TitleColor).
+		Bold(true).
+		Padding(0, 1)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			Background(lipgloss.Color("#1a1a1a")).
-			Bold(true).
-			Padding(0, 1)
+		Foreground(TextColor).
+		Background(SurfaceColor).
+		Bold(true).
+		Padding(0, 1)
 
 	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			Background(MutedColor).
-			Padding(0, 1)
+		Foreground(TextColor).
+		Background(MutedColor).
+		Padding(0, 1)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(CriticalColor).
-			Background(lipgloss.Color("#1a1a1a")).
-			Bold(true).
-			Padding(1, 2).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(CriticalColor)
+		Foreground(CriticalColor).
+		Background(SurfaceColor).
+		Bold(true).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(CriticalColor)
 
 	HelpBarStyle = lipgloss.NewStyle().
-			Foreground(MutedColor).
-			Background(lipgloss.Color("#1a1a1a")).
-			Width(0). // Will be set dynamically
-			Padding(0, 1)
-)
+		Foreground(MutedColor).
+		Background(SurfaceColor).
+		Width(0). // Will be set dynamically
+		Padding(0, 1)
+}
 
 type TerminalCapabilities struct {
 	SupportsUnicode bool
@@ -98,6 +130,7 @@ var termCaps *TerminalCapabilities
 
 func init() {
 	termCaps = detectTerminalCapabilities()
+	useTheme(DarkTheme)
 }
 
 func detectTerminalCapabilities() *TerminalCapabilities {
@@ -112,6 +145,9 @@ func detectTerminalCapabilities() *TerminalCapabilities {
 	if strings.Contains(term, "xterm") || strings.Contains(term, "color") {
 		caps.SupportsColor = true
 	}
+	if NoColorRequested() {
+		caps.SupportsColor = false
+	}
 
 	// Test unicode support by checking if we can measure unicode characters properly
 	testStr := "█░"
@@ -148,7 +184,7 @@ func GetProgressBarConfig(width int) ProgressBarConfig {
 
 func CreateProgressBar(percentage float64, width int, color lipgloss.Color) string {
 	if width < 4 {
-		return fmt.Sprintf("%.0f%%", percentage*100)
+		return Precision(0).Percent(percentage * 100)
 	}
 
 	config := GetProgressBarConfig(width)
@@ -176,7 +212,7 @@ func CreateProgressBar(percentage float64, width int, color lipgloss.Color) stri
 
 func CreateProgressBarWithLabel(percentage float64, width int, color lipgloss.Color, label string) string {
 	if width < 10 {
-		return fmt.Sprintf("%.0f%%", percentage*100)
+		return Precision(0).Percent(percentage * 100)
 	}
 
 	// Reserve space for label
@@ -193,7 +229,7 @@ func CreateProgressBarWithLabel(percentage float64, width int, color lipgloss.Co
 
 func CreateTargetProgressBar(current, target float64, width int, better string) string {
 	if width < 15 {
-		return fmt.Sprintf("%.1f/%.1f", current, target)
+		return FormatFloat(current) + "/" + FormatFloat(target)
 	}
 
 	// Calculate performance ratio
@@ -219,7 +255,7 @@ func CreateTargetProgressBar(current, target float64, width int, better string)
 	// Clamp percentage for progress bar display
 	percentage := math.Min(performance, 1.0)
 
-	label := fmt.Sprintf("%.1f (target: %.1f) %s", current, target, status)
+	label := fmt.Sprintf("%s (target: %s) %s", FormatFloat(current), FormatFloat(target), status)
 	barWidth := width - len(label) - 1
 
 	if barWidth < 4 {
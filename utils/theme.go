@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is the palette every style is built from
+type Theme struct {
+	Name string
+
+	Critical, Warning, Good, Info lipgloss.Color
+	Text, Muted, Border           lipgloss.Color
+
+	CriticalLight, WarningLight, GoodLight, InfoLight lipgloss.Color
+
+	Title, Highlight, Surface lipgloss.Color
+
+	// Monochrome drops all colors and marks selection with reverse video
+	Monochrome bool
+}
+
+var DarkTheme = Theme{
+	Name:     "dark",
+	Critical: "#CC3333", // Dark red
+	Warning:  "#FF8800", // Orange
+	Good:     "#228B22", // Forest green
+	Info:     "#4682B4", // Steel blue
+	Text:     "#CCCCCC", // Light gray
+	Muted:    "#888888", // Medium gray
+	Border:   "#666666", // Dark gray
+
+	CriticalLight: "#FF6666",
+	WarningLight:  "#FFAA44",
+	GoodLight:     "#66BB66",
+	InfoLight:     "#88AACC",
+
+	Title:     "#FFFFFF",
+	Highlight: "#FFFFFF",
+	Surface:   "#1a1a1a",
+}
+
+var LightTheme = Theme{
+	Name:     "light",
+	Critical: "#B22222",
+	Warning:  "#B35900",
+	Good:     "#1B6E1B",
+	Info:     "#1F5F99",
+	Text:     "#333333",
+	Muted:    "#666666",
+	Border:   "#999999",
+
+	CriticalLight: "#D04040",
+	WarningLight:  "#C77700",
+	GoodLight:     "#2E8B57",
+	InfoLight:     "#3A78B5",
+
+	Title:     "#000000",
+	Highlight: "#FFFFFF",
+	Surface:   "#E8E8E8",
+}
+
+var HighContrastTheme = Theme{
+	Name:     "high-contrast",
+	Critical: "#FF0000",
+	Warning:  "#FFFF00",
+	Good:     "#00FF00",
+	Info:     "#00FFFF",
+	Text:     "#FFFFFF",
+	Muted:    "#C0C0C0",
+	Border:   "#FFFFFF",
+
+	CriticalLight: "#FF5555",
+	WarningLight:  "#FFFF55",
+	GoodLight:     "#55FF55",
+	InfoLight:     "#55FFFF",
+
+	Title:     "#FFFFFF",
+	Highlight: "#000000",
+	Surface:   "#000000",
+}
+
+var MonochromeTheme = Theme{Name: "monochrome", Monochrome: true}
+
+var themes = map[string]Theme{
+	DarkTheme.Name:         DarkTheme,
+	LightTheme.Name:        LightTheme,
+	HighContrastTheme.Name: HighContrastTheme,
+	MonochromeTheme.Name:   MonochromeTheme,
+	"mono":                 MonochromeTheme,
+}
+
+var currentTheme Theme
+
+// ThemeNames lists the themes accepted by ApplyTheme, besides "auto"
+func ThemeNames() []string {
+	var names []string
+	for name, theme := range themes {
+		if name == theme.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NoColorRequested reports whether NO_COLOR is set or the terminal is dumb
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// ApplyTheme switches every style to the named theme. An empty name or "auto"
+// picks monochrome when color is unwanted, otherwise light or dark to match the
+// terminal background.
+func ApplyTheme(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		switch {
+		case NoColorRequested():
+			name = MonochromeTheme.Name
+		case !lipgloss.HasDarkBackground():
+			name = LightTheme.Name
+		default:
+			name = DarkTheme.Name
+		}
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme '%s': use auto, %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	useTheme(theme)
+	return nil
+}
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+func useTheme(theme Theme) {
+	currentTheme = theme
+
+	CriticalColor, WarningColor, GoodColor, InfoColor = theme.Critical, theme.Warning, theme.Good, theme.Info
+	TextColor, MutedColor, BorderColor = theme.Text, theme.Muted, theme.Border
+	CriticalLightColor, WarningLightColor = theme.CriticalLight, theme.WarningLight
+	GoodLightColor, InfoLightColor = theme.GoodLight, theme.InfoLight
+	TitleColor, HighlightColor, SurfaceColor = theme.Title, theme.Highlight, theme.Surface
+
+	buildStyles()
+
+	if theme.Monochrome {
+		// Catches any color set outside the theme, e.g. by chart libraries
+		lipgloss.SetColorProfile(termenv.Ascii)
+		termCaps.SupportsColor = false
+		SelectedStyle = lipgloss.NewStyle().Reverse(true)
+		TabActiveStyle = TabActiveStyle.Reverse(true)
+	}
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TreemapKeys are the fill characters used for treemap cells, in item order
+const TreemapKeys = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// TreemapItem represents a single area in the treemap
+type TreemapItem struct {
+	Label string
+	Value float64
+	Style lipgloss.Style
+}
+
+// TreemapKey returns the fill character for the i-th item
+func TreemapKey(i int) string {
+	if i < len(TreemapKeys) {
+		return string(TreemapKeys[i])
+	}
+	return "#"
+}
+
+// CreateTreemap draws items as nested rectangles whose areas are proportional to their values.
+// Items should be sorted largest first; each area is filled with its TreemapKey.
+func CreateTreemap(items []TreemapItem, width, height int) string {
+	if width <= 0 || height <= 0 || len(items) == 0 {
+		return ""
+	}
+
+	grid := make([][]int, height)
+	for y := range grid {
+		grid[y] = make([]int, width)
+		for x := range grid[y] {
+			grid[y][x] = -1
+		}
+	}
+
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		if item.Value > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	layoutTreemap(items, indexes, grid, 0, 0, width, height)
+
+	var lines []string
+	for _, row := range grid {
+		var sb strings.Builder
+		for _, cell := range row {
+			if cell < 0 {
+				sb.WriteString(" ")
+				continue
+			}
+			sb.WriteString(items[cell].Style.Render(TreemapKey(cell)))
+		}
+		lines = append(lines, sb.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// layoutTreemap splits the rectangle between two halves of the items of roughly equal value,
+// cutting across its longer side (slice-and-dice)
+func layoutTreemap(items []TreemapItem, indexes []int, grid [][]int, x, y, w, h int) {
+	if len(indexes) == 0 || w <= 0 || h <= 0 {
+		return
+	}
+
+	if len(indexes) == 1 {
+		for row := y; row < y+h; row++ {
+			for col := x; col < x+w; col++ {
+				grid[row][col] = indexes[0]
+			}
+		}
+		return
+	}
+
+	total := 0.0
+	for _, i := range indexes {
+		total += items[i].Value
+	}
+
+	// Find the split point closest to half of the total value
+	split, running := 1, 0.0
+	for k, i := range indexes[:len(indexes)-1] {
+		running += items[i].Value
+		split = k + 1
+		if running >= total/2 {
+			break
+		}
+	}
+
+	first := 0.0
+	for _, i := range indexes[:split] {
+		first += items[i].Value
+	}
+	ratio := first / total
+
+	// Terminal cells are roughly twice as tall as they are wide
+	if w >= 2*h {
+		cut := clampCut(int(float64(w)*ratio+0.5), w)
+		layoutTreemap(items, indexes[:split], grid, x, y, cut, h)
+		layoutTreemap(items, indexes[split:], grid, x+cut, y, w-cut, h)
+	} else {
+		cut := clampCut(int(float64(h)*ratio+0.5), h)
+		layoutTreemap(items, indexes[:split], grid, x, y, w, cut)
+		layoutTreemap(items, indexes[split:], grid, x, y+cut, w, h-cut)
+	}
+}
+
+// clampCut keeps at least one cell on each side of a cut when there is room for it
+func clampCut(cut, size int) int {
+	if size < 2 {
+		return size
+	}
+	return max(1, min(cut, size-1))
+}
@@ -88,3 +88,21 @@ func CalculateDurationVariance(durations []time.Duration, avgPause time.Duration
 
 	return CalculateNormalizedVariance(nanos, avgPause.Nanoseconds())
 }
+
+// CalculatePercentile interpolates between the nearest ranks of already sorted values
+func CalculatePercentile[T Numeric](sorted []T, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	index := percentile / 100.0 * float64(len(sorted)-1)
+	lower := int(index)
+	upper := lower + 1
+
+	if upper >= len(sorted) {
+		return float64(sorted[len(sorted)-1])
+	}
+
+	weight := index - float64(lower)
+	return float64(sorted[lower])*(1-weight) + float64(sorted[upper])*weight
+}